@@ -0,0 +1,106 @@
+package api
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAnnotationNoteLength max length of an annotation note
+const maxAnnotationNoteLength = 1000
+
+// ownedTraderID trader ID of the route if it belongs to the current user (writes error response otherwise)
+func (s *Server) ownedTraderID(c *gin.Context) (string, bool) {
+	traderID := c.Param("id")
+	if err := s.store.AuthorizeTrader(c.GetString("user_id"), traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return "", false
+	}
+	return traderID, true
+}
+
+// handleListAnnotations Get trade journal annotations of a trader
+func (s *Server) handleListAnnotations(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	limit := 100
+	if limitParam := c.Query("limit"); limitParam != "" {
+		fmt.Sscanf(limitParam, "%d", &limit)
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	annotations, err := s.store.Annotation().List(traderID, time.Time{}, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get annotations: %v", err)})
+		return
+	}
+	if annotations == nil {
+		annotations = []*store.TradeAnnotation{}
+	}
+	c.JSON(http.StatusOK, gin.H{"annotations": annotations, "labels": store.AnnotationLabels})
+}
+
+// handleCreateAnnotation Annotate a closed trade of a trader
+func (s *Server) handleCreateAnnotation(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		PositionID int64  `json:"position_id" binding:"required"`
+		Label      string `json:"label" binding:"required"`
+		Note       string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: position_id and label are required"})
+		return
+	}
+	if !store.IsValidAnnotationLabel(req.Label) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid label %q, must be one of %v", req.Label, store.AnnotationLabels)})
+		return
+	}
+	if len(req.Note) > maxAnnotationNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Note cannot exceed %d characters", maxAnnotationNoteLength)})
+		return
+	}
+
+	annotation := &store.TradeAnnotation{
+		TraderID:   traderID,
+		PositionID: req.PositionID,
+		Label:      req.Label,
+		Note:       req.Note,
+	}
+	if err := s.store.Annotation().Create(annotation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to save annotation: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, annotation)
+}
+
+// handleDeleteAnnotation Delete a trade journal annotation
+func (s *Server) handleDeleteAnnotation(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("annotation_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid annotation ID"})
+		return
+	}
+	if err := s.store.Annotation().Delete(traderID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Annotation deleted"})
+}
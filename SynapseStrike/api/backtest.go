@@ -8,15 +8,18 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"SynapseStrike/backtest"
+	"SynapseStrike/logger"
 	"SynapseStrike/market"
 	"SynapseStrike/store"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (s *Server) registerBacktestRoutes(router *gin.RouterGroup) {
@@ -34,12 +37,25 @@ func (s *Server) registerBacktestRoutes(router *gin.RouterGroup) {
 	router.GET("/trace", s.handleBacktestTrace)
 	router.GET("/decisions", s.handleBacktestDecisions)
 	router.GET("/export", s.handleBacktestExport)
+	router.POST("/optimize", s.handleBacktestOptimize)
+	router.GET("/optimize", s.handleBacktestOptimizeStatus)
 }
 
 type backtestStartRequest struct {
 	Config backtest.BacktestConfig `json:"config"`
 }
 
+type backtestOptimizeRequest struct {
+	StrategyID string                  `json:"strategy_id"` // Strategy to optimize
+	Config     backtest.BacktestConfig `json:"config"`      // Symbols, window, AI and cost settings of every sweep run
+	Params     []backtest.ParamRange   `json:"params"`
+	Folds      int                     `json:"folds"`
+	TrainPct   float64                 `json:"train_pct"`
+	Objective  string                  `json:"objective"`
+	MaxRuns    int                     `json:"max_runs"`
+	Save       bool                    `json:"save"` // Save the best config as a new strategy version
+}
+
 type runIDRequest struct {
 	RunID string `json:"run_id"`
 }
@@ -72,8 +88,8 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 		return
 	}
 
-	// Load Alpaca credentials from user's brokerage config for market data (skip for Polygon)
-	if cfg.DataSource != "polygon" {
+	// Load Alpaca credentials from user's brokerage config for market data (skip for Polygon and archive)
+	if cfg.DataSource != "polygon" && cfg.DataSource != "archive" {
 		if err := s.loadAlpacaCredentialsForBacktest(cfg.UserID); err != nil {
 			fmt.Printf("⚠️ Could not load Alpaca credentials from brokerage: %v\n", err)
 		}
@@ -673,3 +689,135 @@ func extractSymbolsFromConfigJSON(configJSON string) []string {
 
 	return nil
 }
+
+// handleBacktestOptimize starts a walk-forward parameter sweep of a strategy
+func (s *Server) handleBacktestOptimize(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+
+	var req backtestOptimizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.StrategyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "strategy_id is required"})
+		return
+	}
+
+	userID := normalizeUserID(c.GetString("user_id"))
+	strategy, err := s.store.Strategy().Get(userID, req.StrategyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Strategy not found"})
+		return
+	}
+	var strategyConfig store.StrategyConfig
+	if err := json.Unmarshal([]byte(strategy.Config), &strategyConfig); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse strategy config: " + err.Error()})
+		return
+	}
+
+	id := "wfo_" + time.Now().UTC().Format("20060102_150405")
+	cfg := req.Config
+	cfg.RunID = id
+	cfg.UserID = userID
+	cfg.StrategyID = req.StrategyID
+	if err := s.hydrateBacktestAIConfig(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cfg.DataSource != "polygon" && cfg.DataSource != "archive" {
+		if err := s.loadAlpacaCredentialsForBacktest(cfg.UserID); err != nil {
+			fmt.Printf("⚠️ Could not load Alpaca credentials from brokerage: %v\n", err)
+		}
+	}
+	if len(cfg.Symbols) == 0 {
+		cfg.Symbols = extractSymbolsFromConfigJSON(strategy.Config)
+	}
+	if err := cfg.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	optimizeReq := backtest.OptimizeRequest{
+		Base:      cfg,
+		Strategy:  strategyConfig,
+		Params:    req.Params,
+		Folds:     req.Folds,
+		TrainPct:  req.TrainPct,
+		Objective: req.Objective,
+		MaxRuns:   req.MaxRuns,
+	}
+
+	var onDone func(*backtest.OptimizeReport) string
+	if req.Save {
+		onDone = func(report *backtest.OptimizeReport) string {
+			return s.saveOptimizedStrategy(strategy, report)
+		}
+	}
+
+	report, err := s.backtestManager.StartOptimization(id, optimizeReq, onDone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// handleBacktestOptimizeStatus returns progress and result of a walk-forward optimization
+func (s *Server) handleBacktestOptimizeStatus(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+
+	id := c.Query("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id is required"})
+		return
+	}
+
+	report, ok := s.backtestManager.GetOptimization(id)
+	if !ok || normalizeUserID(report.UserID) != normalizeUserID(c.GetString("user_id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "optimization not found"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// saveOptimizedStrategy stores the best config of a completed optimization as a new strategy version
+func (s *Server) saveOptimizedStrategy(base *store.Strategy, report *backtest.OptimizeReport) string {
+	if report.State != backtest.OptimizeCompleted || report.BestStrategy == nil {
+		return ""
+	}
+
+	configJSON, err := json.Marshal(report.BestStrategy)
+	if err != nil {
+		logger.Warnf("⚠️ [WFO %s] Failed to serialize optimized strategy: %v", report.ID, err)
+		return ""
+	}
+
+	params := make([]string, 0, len(report.BestParams))
+	for name, v := range report.BestParams {
+		params = append(params, fmt.Sprintf("%s=%g", name, v))
+	}
+	sort.Strings(params)
+
+	strategy := &store.Strategy{
+		ID:     uuid.New().String(),
+		UserID: base.UserID,
+		Name:   fmt.Sprintf("%s (WFO %s)", base.Name, report.FinishedAt.Format("2006-01-02 15:04")),
+		Description: fmt.Sprintf("Walk-forward optimized from %s over %d folds: %s. Out-of-sample %s %.3f, return %.2f%%, max drawdown %.2f%%.",
+			base.Name, len(report.Folds), strings.Join(params, ", "), report.Objective,
+			report.OutOfSample.Score, report.OutOfSample.TotalReturnPct, report.OutOfSample.MaxDrawdownPct),
+		Config: string(configJSON),
+	}
+	if err := s.store.Strategy().Create(strategy); err != nil {
+		logger.Warnf("⚠️ [WFO %s] Failed to save optimized strategy: %v", report.ID, err)
+		return ""
+	}
+	logger.Infof("💾 [WFO %s] Saved optimized strategy %s (%s)", report.ID, strategy.Name, strategy.ID)
+	return strategy.ID
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"SynapseStrike/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetEquityFloor Equity floor and halt state of a trader
+func (s *Server) handleGetEquityFloor(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+	c.JSON(http.StatusOK, at.GetEquityFloorStatus())
+}
+
+// handleResetEquityFloor Lift an equity floor halt so the trader resumes trading
+// The floor stays armed: if equity is still below it the next cycle halts again.
+func (s *Server) handleResetEquityFloor(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if !at.ResetEquityFloor("user " + userID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trading is not halted by the equity floor"})
+		return
+	}
+	logger.Infof("🧱 User %s reset the equity floor halt: trader=%s", userID, traderID)
+	c.JSON(http.StatusOK, at.GetEquityFloorStatus())
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"SynapseStrike/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetFillQuality Slippage of the trader's fills vs decision-time price, per exchange/symbol
+// Supports optional 'days' (fills of the last N days, default 0 = all)
+func (s *Server) handleGetFillQuality(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	var since time.Time
+	if daysParam := c.Query("days"); daysParam != "" {
+		days, err := strconv.Atoi(daysParam)
+		if err != nil || days < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a non-negative integer"})
+			return
+		}
+		if days > 0 {
+			since = time.Now().AddDate(0, 0, -days)
+		}
+	}
+
+	stats, err := at.GetFillQuality(since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get fill quality: " + err.Error()})
+		return
+	}
+	type symbolStats struct {
+		store.SlippageStats
+		LimitFillRate float64 `json:"limit_fill_rate"`
+	}
+	result := make([]symbolStats, 0, len(stats))
+	for _, st := range stats {
+		result = append(result, symbolStats{SlippageStats: st, LimitFillRate: st.LimitFillRate()})
+	}
+	c.JSON(http.StatusOK, gin.H{"stats": result})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"SynapseStrike/decision"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetGrid State and P&L of the trader's grids (grid/DCA tactic), apart from its positions
+func (s *Server) handleGetGrid(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	grids := at.GetGridReport()
+	var realized, unrealized float64
+	for _, grid := range grids {
+		realized += grid.RealizedPnL
+		unrealized += grid.UnrealizedPnL
+	}
+	if grids == nil {
+		grids = []decision.GridInfo{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"grids":          grids,
+		"realized_pnl":   realized,
+		"unrealized_pnl": unrealized,
+	})
+}
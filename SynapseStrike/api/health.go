@@ -0,0 +1,83 @@
+package api
+
+import (
+	"SynapseStrike/trader"
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dbProbeTimeout timeout of the database ping
+const dbProbeTimeout = 3 * time.Second
+
+// probeDB pings the database
+func (s *Server) probeDB(ctx context.Context) trader.ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, dbProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	result := trader.ProbeResult{Status: trader.ProbeOK}
+	if err := s.store.DB().PingContext(ctx); err != nil {
+		result.Status = trader.ProbeFail
+		result.Message = err.Error()
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// handleHealthz Liveness probe: the process serves requests and the database answers
+func (s *Server) handleHealthz(c *gin.Context) {
+	db := s.probeDB(c.Request.Context())
+	status, code := trader.ProbeOK, http.StatusOK
+	if db.Status == trader.ProbeFail {
+		status, code = trader.ProbeFail, http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status": status,
+		"time":   time.Now().Format(time.RFC3339),
+		"checks": gin.H{"database": db},
+	})
+}
+
+// handleReadyz Readiness probe: database plus exchange, AI provider and market data of every running trader
+// Returns 503 if any check failed. Trader probes run concurrently and are cached per trader (see trader/health.go).
+func (s *Server) handleReadyz(c *gin.Context) {
+	db := s.probeDB(c.Request.Context())
+
+	traders := s.traderManager.GetAllTraders()
+	results := make([]*trader.TraderHealth, 0, len(traders))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, at := range traders {
+		wg.Add(1)
+		go func(at *trader.AutoTrader) {
+			defer wg.Done()
+			health := at.ProbeHealth()
+			mu.Lock()
+			results = append(results, health)
+			mu.Unlock()
+		}(at)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].TraderID < results[j].TraderID })
+
+	ready := db.Status != trader.ProbeFail
+	for _, health := range results {
+		if !health.Healthy() {
+			ready = false
+		}
+	}
+	status, code := trader.ProbeOK, http.StatusOK
+	if !ready {
+		status, code = trader.ProbeFail, http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status":  status,
+		"time":    time.Now().Format(time.RFC3339),
+		"checks":  gin.H{"database": db},
+		"traders": results,
+	})
+}
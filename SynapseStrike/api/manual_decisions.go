@@ -0,0 +1,54 @@
+package api
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/trader"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleSubmitDecisions Manually enter or close positions through the trader's decision pipeline
+// Decisions use the AI decision format and pass the same validation and risk enforcement.
+func (s *Server) handleSubmitDecisions(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	var decisions []decision.Decision
+	if err := c.ShouldBindJSON(&decisions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: expected a JSON array of decisions"})
+		return
+	}
+	if len(decisions) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one decision is required"})
+		return
+	}
+
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	logger.Infof("✋ User %s submitted %d manual decisions: trader=%s", userID, len(decisions), traderID)
+	record, err := at.SubmitManualDecisions(decisions, "user "+userID)
+	switch {
+	case errors.Is(err, trader.ErrCycleInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil && record == nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "execution_log": record.ExecutionLog})
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"cycle_number":  record.CycleNumber,
+			"decisions":     record.Decisions,
+			"execution_log": record.ExecutionLog,
+		})
+	}
+}
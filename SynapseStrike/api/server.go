@@ -7,6 +7,7 @@ import (
 	"SynapseStrike/crypto"
 	"SynapseStrike/logger"
 	"SynapseStrike/manager"
+	"SynapseStrike/market"
 	"SynapseStrike/metrics"
 	"SynapseStrike/store"
 	"SynapseStrike/trader"
@@ -94,6 +95,10 @@ func (s *Server) setupRoutes() {
 	// Prometheus metrics endpoint (no authentication, at root level)
 	s.router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
 
+	// Liveness / readiness probes for orchestration platforms (no authentication, at root level)
+	s.router.GET("/healthz", s.handleHealthz)
+	s.router.GET("/readyz", s.handleReadyz)
+
 	// API route group
 	api := s.router.Group("/api")
 	{
@@ -125,6 +130,9 @@ func (s *Server) setupRoutes() {
 		// Market status (no authentication required)
 		api.GET("/market-status", s.handleMarketStatus)
 
+		// Screener candidate webhook (no authentication required, HMAC signed per strategy)
+		api.POST("/webhooks/candidates/:strategy_id", s.handleCandidateWebhook)
+
 		// Authentication related routes (no authentication required)
 		api.POST("/register", s.handleRegister)
 		api.POST("/login", s.handleLogin)
@@ -152,6 +160,26 @@ func (s *Server) setupRoutes() {
 			protected.POST("/traders/:id/sync-balance", s.handleSyncBalance)
 			protected.POST("/traders/:id/close-position", s.handleClosePosition)
 			protected.PUT("/traders/:id/competition", s.handleToggleCompetition)
+			protected.GET("/traders/:id/symbol-lists", s.handleGetSymbolLists)
+			protected.PUT("/traders/:id/symbol-lists", s.handleUpdateSymbolLists)
+			protected.POST("/traders/:id/decisions", s.handleSubmitDecisions)
+			protected.GET("/traders/:id/equity-floor", s.handleGetEquityFloor)
+			protected.POST("/traders/:id/equity-floor/reset", s.handleResetEquityFloor)
+			protected.POST("/traders/:id/pause", s.handlePauseTrader)
+			protected.POST("/traders/:id/resume", s.handleResumeTrader)
+			protected.GET("/traders/:id/grid", s.handleGetGrid)
+			protected.GET("/traders/:id/fill-quality", s.handleGetFillQuality)
+			protected.GET("/traders/:id/symbols", s.handleGetSymbolView)
+
+			// Trade journal annotations (closed trades)
+			protected.GET("/traders/:id/annotations", s.handleListAnnotations)
+			protected.POST("/traders/:id/annotations", s.handleCreateAnnotation)
+			protected.DELETE("/traders/:id/annotations/:annotation_id", s.handleDeleteAnnotation)
+
+			// Trade and decision export, import of externally executed trades
+			protected.GET("/traders/:id/export/trades", s.handleExportTrades)
+			protected.GET("/traders/:id/export/decisions", s.handleExportDecisions)
+			protected.POST("/traders/:id/import/trades", s.handleImportTrades)
 
 			// AI model configuration
 			protected.GET("/models", s.handleGetModelConfigs)
@@ -169,6 +197,7 @@ func (s *Server) setupRoutes() {
 			protected.GET("/strategies/default-config", s.handleGetDefaultStrategyConfig)
 			protected.POST("/strategies/preview-prompt", s.handlePreviewPrompt)
 			protected.POST("/strategies/test-run", s.handleStrategyTestRun)
+			protected.POST("/strategies/dry-run", s.handleStrategyDryRun)
 			protected.GET("/strategies/:id", s.handleGetStrategy)
 			protected.POST("/strategies", s.handleCreateStrategy)
 			protected.PUT("/strategies/:id", s.handleUpdateStrategy)
@@ -211,6 +240,10 @@ func (s *Server) setupRoutes() {
 			protected.GET("/decisions", s.handleDecisions)
 			protected.GET("/decisions/latest", s.handleLatestDecisions)
 			protected.GET("/statistics", s.handleStatistics)
+			protected.GET("/trade-explanations", s.handleTradeExplanations)
+			protected.GET("/pnl-attribution", s.handlePnLAttribution)
+			protected.GET("/stress-report", s.handleStressReport)
+			protected.GET("/trade-quality", s.handleTradeQuality)
 
 			// Backtest routes
 			backtest := protected.Group("/backtest")
@@ -384,10 +417,23 @@ func isPrivateIP(ip net.IP) bool {
 }
 
 // getTraderFromQuery Get trader from query parameter
+// Only the requesting user's traders are resolved; anonymous requests (public routes)
+// may read traders shown in the competition.
 func (s *Server) getTraderFromQuery(c *gin.Context) (*manager.TraderManager, string, error) {
-	userID := c.GetString("user_id")
+	userID := s.requestUserID(c)
 	traderID := c.Query("trader_id")
 
+	if userID == "" {
+		if traderID == "" {
+			return nil, "", fmt.Errorf("trader_id is required")
+		}
+		traderRecord, err := s.store.Trader().GetByID(traderID)
+		if err != nil || traderRecord == nil || !traderRecord.ShowInCompetition {
+			return nil, "", store.ErrTraderNotOwned
+		}
+		return s.traderManager, traderID, nil
+	}
+
 	// Ensure user's traders are loaded into memory
 	err := s.traderManager.LoadUserTradersFromStore(s.store, userID)
 	if err != nil {
@@ -396,23 +442,35 @@ func (s *Server) getTraderFromQuery(c *gin.Context) (*manager.TraderManager, str
 
 	if traderID == "" {
 		// If no trader_id specified, return first trader for this user
-		ids := s.traderManager.GetTraderIDs()
-		if len(ids) == 0 {
-			return nil, "", fmt.Errorf("No available traders")
-		}
-
-		// Get user's trader list, prioritize returning user's own traders
 		userTraders, err := s.store.Trader().List(userID)
-		if err == nil && len(userTraders) > 0 {
-			traderID = userTraders[0].ID
-		} else {
-			traderID = ids[0]
+		if err != nil || len(userTraders) == 0 {
+			return nil, "", fmt.Errorf("No available traders")
 		}
+		traderID = userTraders[0].ID
 	}
 
+	if err := s.store.AuthorizeTrader(userID, traderID); err != nil {
+		return nil, "", err
+	}
 	return s.traderManager, traderID, nil
 }
 
+// requestUserID user of the request; public routes accept an optional valid Bearer token
+func (s *Server) requestUserID(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return userID
+	}
+	tokenString, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || auth.IsTokenBlacklisted(tokenString) {
+		return ""
+	}
+	claims, err := auth.ValidateJWT(tokenString)
+	if err != nil {
+		return ""
+	}
+	return claims.UserID
+}
+
 // AI trader management related structures
 type CreateTraderRequest struct {
 	Name                 string  `json:"name" binding:"required"`
@@ -424,6 +482,9 @@ type CreateTraderRequest struct {
 	IsCrossMargin        *bool   `json:"is_cross_margin"`         // Pointer type, nil means use default value true
 	ShowInCompetition    *bool   `json:"show_in_competition"`     // Pointer type, nil means use default value true
 	TradeOnlyMarketHours *bool   `json:"trade_only_market_hours"` // Pointer type, nil means use default value true
+	ShadowMode           bool    `json:"shadow_mode"`             // Execute on virtual ledger instead of exchange
+	TradingSchedule      *store.TradingSchedule `json:"trading_schedule"` // Time-of-day / day-of-week trading windows
+	SymbolLists          *store.SymbolLists     `json:"symbol_lists"`     // Symbol allow/deny lists
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	LargeCapLeverage     int    `json:"large_cap_leverage"`
 	SmallCapLeverage     int    `json:"small_cap_leverage"`
@@ -467,7 +528,7 @@ type ExchangeConfig struct {
 // SafeExchangeConfig Safe exchange configuration structure (does not contain sensitive information)
 type SafeExchangeConfig struct {
 	ID                    string `json:"id"`            // UUID
-	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName           string `json:"account_name"`  // User-defined account name
 	Name                  string `json:"name"`          // Display name
 	Type                  string `json:"type"`          // "cex" or "dex"
@@ -477,6 +538,10 @@ type SafeExchangeConfig struct {
 	AsterUser             string `json:"asterUser"`             // Aster username (not sensitive)
 	AsterSigner           string `json:"asterSigner"`           // Aster signer (not sensitive)
 	LighterWalletAddr     string `json:"lighterWalletAddr"`     // LIGHTER wallet address (not sensitive)
+	DydxSubaccount        int    `json:"dydxSubaccount"`        // dYdX subaccount number (not sensitive)
+	QuoteCurrency         string `json:"quote_currency"`        // Collateral currency (empty = exchange default)
+	CCXTGatewayURL        string `json:"ccxt_gateway_url"`      // CCXT bridge gateway URL (not sensitive)
+	CCXTExchangeID        string `json:"ccxt_exchange_id"`      // CCXT exchange id (not sensitive)
 }
 
 type UpdateModelConfigRequest struct {
@@ -503,6 +568,11 @@ type UpdateExchangeConfigRequest struct {
 		LighterPrivateKey       string `json:"lighter_private_key"`
 		LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
 		LighterAPIKeyIndex      int    `json:"lighter_api_key_index"`
+		DydxMnemonic            string `json:"dydx_mnemonic"`
+		DydxSubaccount          int    `json:"dydx_subaccount"`
+		QuoteCurrency           string `json:"quote_currency"`
+		CCXTGatewayURL          string `json:"ccxt_gateway_url"`
+		CCXTExchangeID          string `json:"ccxt_exchange_id"`
 	} `json:"exchanges"`
 }
 
@@ -563,6 +633,16 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		tradeOnlyMarketHours = *req.TradeOnlyMarketHours
 	}
 
+	if err := req.TradingSchedule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.SymbolLists.Normalize()
+	if err := req.SymbolLists.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Set leverage default values
 	largeCapLeverage := 10 // Default value
 	smallCapLeverage := 5  // Default value
@@ -613,7 +693,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		// Use ExchangeType (e.g., "binance") instead of ID (UUID)
 		switch exchangeCfg.ExchangeType {
 		case "binance":
-			tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
+			tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID, exchangeCfg.Testnet)
 		case "hyperliquid":
 			tempTrader, createErr = trader.NewHyperliquidTrader(
 				exchangeCfg.APIKey, // private key
@@ -630,6 +710,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 			tempTrader = trader.NewBybitTrader(
 				exchangeCfg.APIKey,
 				exchangeCfg.SecretKey,
+				exchangeCfg.Testnet,
 			)
 		case "okx":
 			tempTrader = trader.NewOKXTrader(
@@ -655,6 +736,21 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 			} else {
 				createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
 			}
+		case "dydx":
+			tempTrader, createErr = trader.NewDydxTrader(
+				exchangeCfg.DydxMnemonic,
+				exchangeCfg.DydxSubaccount,
+				exchangeCfg.Testnet,
+			)
+		case "ccxt":
+			tempTrader, createErr = trader.NewCCXTBridgeTrader(
+				exchangeCfg.CCXTGatewayURL,
+				exchangeCfg.CCXTExchangeID,
+				exchangeCfg.APIKey,
+				exchangeCfg.SecretKey,
+				exchangeCfg.Passphrase,
+				trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency),
+			)
 		default:
 			logger.Infof("⚠️ Unsupported exchange type: %s, using user input for initial balance", exchangeCfg.ExchangeType)
 		}
@@ -664,6 +760,9 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		} else if tempTrader != nil {
 			// Query actual balance
 			balanceInfo, balanceErr := tempTrader.GetBalance()
+			if balanceErr == nil {
+				balanceInfo, balanceErr = trader.ConvertBalanceToUSD(balanceInfo, trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency))
+			}
 			if balanceErr != nil {
 				logger.Infof("⚠️ Failed to query exchange balance, using user input for initial balance: %v", balanceErr)
 			} else {
@@ -674,7 +773,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 				for _, key := range balanceKeys {
 					if balance, ok := balanceInfo[key].(float64); ok && balance > 0 {
 						actualBalance = balance
-						logger.Infof("✓ Queried exchange total equity (%s): %.2f USDT (user input: %.2f USDT)", key, actualBalance, req.InitialBalance)
+						logger.Infof("✓ Queried exchange total equity (%s): %.2f USD (user input: %.2f USD)", key, actualBalance, req.InitialBalance)
 						break
 					}
 				}
@@ -706,6 +805,9 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
 		TradeOnlyMarketHours: tradeOnlyMarketHours,
+		ShadowMode:           req.ShadowMode,
+		TradingSchedule:      req.TradingSchedule,
+		SymbolLists:          req.SymbolLists,
 		ScanIntervalMinutes:  scanIntervalMinutes,
 		IsRunning:            false,
 	}
@@ -750,6 +852,9 @@ type UpdateTraderRequest struct {
 	IsCrossMargin        *bool   `json:"is_cross_margin"`
 	ShowInCompetition    *bool   `json:"show_in_competition"`
 	TradeOnlyMarketHours *bool   `json:"trade_only_market_hours"` // Only trade during market hours
+	ShadowMode           *bool   `json:"shadow_mode"`             // Execute on virtual ledger instead of exchange
+	TradingSchedule      *store.TradingSchedule `json:"trading_schedule"` // nil keeps existing schedule
+	SymbolLists          *store.SymbolLists     `json:"symbol_lists"`     // nil keeps existing lists
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	LargeCapLeverage     int    `json:"large_cap_leverage"`
 	SmallCapLeverage     int    `json:"small_cap_leverage"`
@@ -806,6 +911,30 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		tradeOnlyMarketHours = *req.TradeOnlyMarketHours
 	}
 
+	shadowMode := existingTrader.ShadowMode // Keep original value
+	if req.ShadowMode != nil {
+		shadowMode = *req.ShadowMode
+	}
+
+	tradingSchedule := existingTrader.TradingSchedule // Keep original value
+	if req.TradingSchedule != nil {
+		if err := req.TradingSchedule.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tradingSchedule = req.TradingSchedule
+	}
+
+	symbolLists := existingTrader.SymbolLists // Keep original value
+	if req.SymbolLists != nil {
+		req.SymbolLists.Normalize()
+		if err := req.SymbolLists.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		symbolLists = req.SymbolLists
+	}
+
 	// Set leverage default values
 	largeCapLeverage := req.LargeCapLeverage
 	smallCapLeverage := req.SmallCapLeverage
@@ -854,6 +983,9 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
 		TradeOnlyMarketHours: tradeOnlyMarketHours,
+		ShadowMode:           shadowMode,
+		TradingSchedule:      tradingSchedule,
+		SymbolLists:          symbolLists,
 		ScanIntervalMinutes:  scanIntervalMinutes,
 		IsRunning:            existingTrader.IsRunning, // Keep original value
 	}
@@ -889,7 +1021,10 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 // handleDeleteTrader Delete trader
 func (s *Server) handleDeleteTrader(c *gin.Context) {
 	userID := c.GetString("user_id")
-	traderID := c.Param("id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
 
 	// Delete from database
 	err := s.store.Trader().Delete(userID, traderID)
@@ -910,6 +1045,14 @@ func (s *Server) handleDeleteTrader(c *gin.Context) {
 	// Remove trader from memory
 	s.traderManager.RemoveTrader(traderID)
 
+	// Drop persisted runtime state (written by Stop above)
+	if err := s.store.RuntimeState().Delete(traderID); err != nil {
+		logger.Warnf("⚠️ Failed to delete runtime state for trader %s: %v", traderID, err)
+	}
+	if err := s.store.Memory().DeleteByTrader(traderID); err != nil {
+		logger.Warnf("⚠️ Failed to delete decision memories for trader %s: %v", traderID, err)
+	}
+
 	logger.Infof("✓ Trader deleted: %s", traderID)
 	c.JSON(http.StatusOK, gin.H{"message": "Trader deleted"})
 }
@@ -1043,7 +1186,10 @@ func (s *Server) handleStopTrader(c *gin.Context) {
 
 // handleUpdateTraderPrompt Update trader custom prompt
 func (s *Server) handleUpdateTraderPrompt(c *gin.Context) {
-	traderID := c.Param("id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
 	userID := c.GetString("user_id")
 
 	var req struct {
@@ -1076,7 +1222,10 @@ func (s *Server) handleUpdateTraderPrompt(c *gin.Context) {
 
 // handleToggleCompetition Toggle trader competition visibility
 func (s *Server) handleToggleCompetition(c *gin.Context) {
-	traderID := c.Param("id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
 	userID := c.GetString("user_id")
 
 	var req struct {
@@ -1111,6 +1260,66 @@ func (s *Server) handleToggleCompetition(c *gin.Context) {
 	})
 }
 
+// handleGetSymbolLists Get trader symbol allow/deny lists
+func (s *Server) handleGetSymbolLists(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader not found"})
+		return
+	}
+	lists := fullConfig.Trader.SymbolLists
+	if lists == nil {
+		lists = &store.SymbolLists{}
+	}
+	c.JSON(http.StatusOK, lists)
+}
+
+// handleUpdateSymbolLists Replace trader symbol allow/deny lists, applied to a running trader without restart
+func (s *Server) handleUpdateSymbolLists(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req store.SymbolLists
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var lists *store.SymbolLists
+	if !req.IsEmpty() {
+		lists = &req
+	}
+	if err := s.store.Trader().UpdateSymbolLists(userID, traderID, lists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update symbol lists: %v", err)})
+		return
+	}
+
+	// Update in-memory trader if it exists
+	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
+		trader.SetSymbolLists(lists)
+	}
+
+	logger.Infof("✓ Trader %s symbol lists updated: allow=%d deny=%d", traderID, len(req.Allow), len(req.Deny))
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Symbol lists updated",
+		"symbol_lists": req,
+	})
+}
+
 // handleSyncBalance Sync exchange balance to initial_balance (Option B: Manual Sync + Option C: Smart Detection)
 func (s *Server) handleSyncBalance(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -1140,7 +1349,13 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
 	switch exchangeCfg.ExchangeType {
 	case "binance":
-		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
+		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID, exchangeCfg.Testnet)
+	case "binance-spot":
+		tempTrader = trader.NewBinanceSpotTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey,
+			trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency), exchangeCfg.Testnet)
+	case "coinbase":
+		tempTrader, createErr = trader.NewCoinbaseSpotTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey,
+			trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency))
 	case "hyperliquid":
 		tempTrader, createErr = trader.NewHyperliquidTrader(
 			exchangeCfg.APIKey,
@@ -1157,6 +1372,7 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 		tempTrader = trader.NewBybitTrader(
 			exchangeCfg.APIKey,
 			exchangeCfg.SecretKey,
+			exchangeCfg.Testnet,
 		)
 	case "okx":
 		tempTrader = trader.NewOKXTrader(
@@ -1182,6 +1398,21 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 		} else {
 			createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
 		}
+	case "dydx":
+		tempTrader, createErr = trader.NewDydxTrader(
+			exchangeCfg.DydxMnemonic,
+			exchangeCfg.DydxSubaccount,
+			exchangeCfg.Testnet,
+		)
+	case "ccxt":
+		tempTrader, createErr = trader.NewCCXTBridgeTrader(
+			exchangeCfg.CCXTGatewayURL,
+			exchangeCfg.CCXTExchangeID,
+			exchangeCfg.APIKey,
+			exchangeCfg.SecretKey,
+			exchangeCfg.Passphrase,
+			trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency),
+		)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange type"})
 		return
@@ -1195,6 +1426,9 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 
 	// Query actual balance
 	balanceInfo, balanceErr := tempTrader.GetBalance()
+	if balanceErr == nil {
+		balanceInfo, balanceErr = trader.ConvertBalanceToUSD(balanceInfo, trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency))
+	}
 	if balanceErr != nil {
 		logger.Infof("⚠️ Failed to query exchange balance: %v", balanceErr)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to query balance: %v", balanceErr)})
@@ -1291,7 +1525,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
 	switch exchangeCfg.ExchangeType {
 	case "binance":
-		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
+		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID, exchangeCfg.Testnet)
 	case "hyperliquid":
 		tempTrader, createErr = trader.NewHyperliquidTrader(
 			exchangeCfg.APIKey,
@@ -1308,6 +1542,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 		tempTrader = trader.NewBybitTrader(
 			exchangeCfg.APIKey,
 			exchangeCfg.SecretKey,
+			exchangeCfg.Testnet,
 		)
 	case "okx":
 		tempTrader = trader.NewOKXTrader(
@@ -1333,6 +1568,21 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 		} else {
 			createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
 		}
+	case "dydx":
+		tempTrader, createErr = trader.NewDydxTrader(
+			exchangeCfg.DydxMnemonic,
+			exchangeCfg.DydxSubaccount,
+			exchangeCfg.Testnet,
+		)
+	case "ccxt":
+		tempTrader, createErr = trader.NewCCXTBridgeTrader(
+			exchangeCfg.CCXTGatewayURL,
+			exchangeCfg.CCXTExchangeID,
+			exchangeCfg.APIKey,
+			exchangeCfg.SecretKey,
+			exchangeCfg.Passphrase,
+			trader.ResolveQuoteCurrency(exchangeCfg.ExchangeType, exchangeCfg.QuoteCurrency),
+		)
 	case "alpaca":
 		tempTrader = trader.NewAlpacaTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, false)
 	case "alpaca-paper":
@@ -1537,6 +1787,10 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 			AsterUser:             exchange.AsterUser,
 			AsterSigner:           exchange.AsterSigner,
 			LighterWalletAddr:     exchange.LighterWalletAddr,
+			DydxSubaccount:        exchange.DydxSubaccount,
+			QuoteCurrency:         exchange.QuoteCurrency,
+			CCXTGatewayURL:        exchange.CCXTGatewayURL,
+			CCXTExchangeID:        exchange.CCXTExchangeID,
 		}
 	}
 
@@ -1605,11 +1859,31 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 
 	// Update each exchange's configuration
 	for exchangeID, exchangeData := range req.Exchanges {
-		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey, exchangeData.LighterAPIKeyIndex)
+		if !market.IsValidQuoteCurrency(exchangeData.QuoteCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid quote currency for exchange %s: %s", exchangeID, exchangeData.QuoteCurrency)})
+			return
+		}
+		if exchangeData.CCXTGatewayURL != "" {
+			if err := trader.ValidateCCXTBridgeConfig(exchangeData.CCXTGatewayURL, exchangeData.CCXTExchangeID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Exchange %s: %v", exchangeID, err)})
+				return
+			}
+		}
+		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey, exchangeData.LighterAPIKeyIndex, exchangeData.DydxMnemonic, exchangeData.DydxSubaccount)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update exchange %s: %v", exchangeID, err)})
 			return
 		}
+		if err := s.store.Exchange().UpdateQuoteCurrency(userID, exchangeID, exchangeData.QuoteCurrency); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update quote currency of exchange %s: %v", exchangeID, err)})
+			return
+		}
+		if exchangeData.CCXTGatewayURL != "" {
+			if err := s.store.Exchange().UpdateCCXTBridge(userID, exchangeID, exchangeData.CCXTGatewayURL, exchangeData.CCXTExchangeID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update CCXT bridge of exchange %s: %v", exchangeID, err)})
+				return
+			}
+		}
 	}
 
 	// Reload all traders for this user to make new config take effect immediately
@@ -1625,7 +1899,7 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 
 // CreateExchangeRequest request structure for creating a new exchange account
 type CreateExchangeRequest struct {
-	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName             string `json:"account_name"`                     // User-defined account name
 	Enabled                 bool   `json:"enabled"`
 	APIKey                  string `json:"api_key"`
@@ -1640,6 +1914,11 @@ type CreateExchangeRequest struct {
 	LighterPrivateKey       string `json:"lighter_private_key"`
 	LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
 	LighterAPIKeyIndex      int    `json:"lighter_api_key_index"`
+	DydxMnemonic            string `json:"dydx_mnemonic"`
+	DydxSubaccount          int    `json:"dydx_subaccount"`
+	QuoteCurrency           string `json:"quote_currency"`   // Collateral currency (empty = exchange default)
+	CCXTGatewayURL          string `json:"ccxt_gateway_url"` // CCXT bridge only
+	CCXTExchangeID          string `json:"ccxt_exchange_id"` // CCXT bridge only, e.g. "krakenfutures"
 }
 
 // handleCreateExchange Create a new exchange account
@@ -1695,12 +1974,23 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 
 	// Validate exchange type
 	validTypes := map[string]bool{
-		"alpaca": true, "alpaca-paper": true, "ibkr": true, "simplefx": true, "oanda": true,
+		"alpaca": true, "alpaca-paper": true, "ibkr": true, "simplefx": true, "oanda": true, "ccxt": true,
+		"binance-spot": true, "coinbase": true,
 	}
 	if !validTypes[req.ExchangeType] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid exchange type: %s", req.ExchangeType)})
 		return
 	}
+	if !market.IsValidQuoteCurrency(req.QuoteCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid quote currency: %s", req.QuoteCurrency)})
+		return
+	}
+	if req.ExchangeType == "ccxt" {
+		if err := trader.ValidateCCXTBridgeConfig(req.CCXTGatewayURL, req.CCXTExchangeID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// Create new exchange account
 	id, err := s.store.Exchange().Create(
@@ -1708,12 +1998,23 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 		req.APIKey, req.SecretKey, req.Passphrase, req.Testnet,
 		req.HyperliquidWalletAddr, req.AsterUser, req.AsterSigner, req.AsterPrivateKey,
 		req.LighterWalletAddr, req.LighterPrivateKey, req.LighterAPIKeyPrivateKey, req.LighterAPIKeyIndex,
+		req.DydxMnemonic, req.DydxSubaccount,
 	)
 	if err != nil {
 		logger.Infof("❌ Failed to create exchange account: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create exchange account: %v", err)})
 		return
 	}
+	if req.QuoteCurrency != "" {
+		if err := s.store.Exchange().UpdateQuoteCurrency(userID, id, req.QuoteCurrency); err != nil {
+			logger.Warnf("⚠️ Failed to set quote currency of exchange %s: %v", id, err)
+		}
+	}
+	if req.ExchangeType == "ccxt" {
+		if err := s.store.Exchange().UpdateCCXTBridge(userID, id, req.CCXTGatewayURL, req.CCXTExchangeID); err != nil {
+			logger.Warnf("⚠️ Failed to set CCXT bridge of exchange %s: %v", id, err)
+		}
+	}
 
 	logger.Infof("✓ Created exchange account: type=%s, name=%s, id=%s", req.ExchangeType, req.AccountName, id)
 	c.JSON(http.StatusOK, gin.H{
@@ -1861,6 +2162,9 @@ func (s *Server) handleGetTraderConfig(c *gin.Context) {
 		"use_oi_top":              traderConfig.UseOITop,
 		"is_running":              isRunning,
 		"trade_only_market_hours": traderConfig.TradeOnlyMarketHours,
+		"shadow_mode":             traderConfig.ShadowMode,
+		"trading_schedule":        traderConfig.TradingSchedule,
+		"symbol_lists":            traderConfig.SymbolLists,
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -1934,7 +2238,7 @@ func (s *Server) handlePositions(c *gin.Context) {
 		return
 	}
 
-	trader, err := s.traderManager.GetTrader(traderID)
+	at, err := s.traderManager.GetTrader(traderID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -1942,10 +2246,10 @@ func (s *Server) handlePositions(c *gin.Context) {
 
 	// Step 1: Get positions from internal database (filtered by trader_id)
 	// This ensures each trader only sees positions THEY opened
-	dbPositions, dbErr := s.store.Position().GetOpenPositions(traderID)
+	dbPositions, dbErr := s.store.Position().ForUser(s.requestUserID(c)).GetOpenPositions(traderID)
 
 	// Step 2: Get live positions from exchange for current prices
-	livePositions, liveErr := trader.GetPositions()
+	livePositions, liveErr := at.GetExchangePositions()
 	if liveErr != nil {
 		logger.Infof("⚠️ Failed to get live positions for trader %s: %v", traderID, liveErr)
 	} else {
@@ -1955,84 +2259,75 @@ func (s *Server) handlePositions(c *gin.Context) {
 	// If we have DB positions, merge with live data for current prices
 	if dbErr == nil && len(dbPositions) > 0 {
 		// Create a map of live positions by symbol+side for quick lookup
-		livePriceMap := make(map[string]map[string]interface{})
+		livePriceMap := make(map[string]int) // symbol_SIDE -> index in livePositions
 		if liveErr == nil {
-			for _, lp := range livePositions {
-				symbol, _ := lp["symbol"].(string)
-				side, _ := lp["side"].(string)
+			for i, lp := range livePositions {
 				// Normalize side for comparison
-				sideUpper := strings.ToUpper(side)
-				key := symbol + "_" + sideUpper
-				livePriceMap[key] = lp
-				logger.Infof("📌 Added live position to map: key=%s, markPrice=%v, uPnL=%v", 
-					key, lp["markPrice"], lp["unRealizedProfit"])
+				key := lp.Symbol + "_" + strings.ToUpper(lp.Side)
+				livePriceMap[key] = i
+				logger.Infof("📌 Added live position to map: key=%s, markPrice=%v, uPnL=%v",
+					key, lp.MarkPrice, lp.UnrealizedPnL)
 			}
 		}
 
 		// Convert DB positions to response format with live prices
-		positions := make([]map[string]interface{}, 0, len(dbPositions))
+		positions := make([]trader.PositionSummary, 0, len(dbPositions))
 		for _, dbPos := range dbPositions {
-			// Build position response using snake_case expected by frontend
-			posMap := map[string]interface{}{
-				"symbol":             dbPos.Symbol,
-				"side":               strings.ToLower(dbPos.Side),
-				"quantity":           dbPos.Quantity,
-				"entry_price":        dbPos.EntryPrice,
-				"mark_price":         dbPos.EntryPrice, // Default to entry price
-				"unrealized_pnl":     0.0,
-				"unrealized_pnl_pct": 0.0,
-				"liquidation_price":  0.0,
-				"margin":             float64(dbPos.Leverage),
-				"entry_time":         dbPos.EntryTime.Format("2006-01-02 15:04"),
+			// Start from the stored position, marked at entry price until a live price is found
+			pos := trader.Position{
+				Symbol:     dbPos.Symbol,
+				Side:       strings.ToLower(dbPos.Side),
+				EntryPrice: dbPos.EntryPrice,
+				MarkPrice:  dbPos.EntryPrice,
+				Qty:        dbPos.Quantity,
 			}
 
 			// Merge with live data if available
 			key := dbPos.Symbol + "_" + strings.ToUpper(dbPos.Side)
-			logger.Infof("🔍 Trying to match DB position: key=%s (symbol=%s, side=%s)", 
+			logger.Infof("🔍 Trying to match DB position: key=%s (symbol=%s, side=%s)",
 				key, dbPos.Symbol, dbPos.Side)
-			if livePos, found := livePriceMap[key]; found {
+			if i, found := livePriceMap[key]; found {
+				livePos := livePositions[i]
 				logger.Infof("✓ Match found for %s! Updating mark_price and uPnL", key)
-				if markPrice, ok := livePos["markPrice"].(float64); ok {
-					posMap["mark_price"] = markPrice
-					logger.Infof("  → mark_price: %.4f", markPrice)
-				}
-				if uPnL, ok := livePos["unRealizedProfit"].(float64); ok {
-					posMap["unrealized_pnl"] = uPnL
-					logger.Infof("  → unrealized_pnl: %.2f", uPnL)
-				}
-				if liqPrice, ok := livePos["liquidationPrice"].(float64); ok {
-					posMap["liquidation_price"] = liqPrice
-				}
-				// Calculate PnL %
-				if mark, mOk := posMap["mark_price"].(float64); mOk && mark > 0 && dbPos.EntryPrice > 0 {
-					if strings.ToUpper(dbPos.Side) == "LONG" {
-						posMap["unrealized_pnl_pct"] = (mark - dbPos.EntryPrice) / dbPos.EntryPrice * 100
-					} else {
-						posMap["unrealized_pnl_pct"] = (dbPos.EntryPrice - mark) / dbPos.EntryPrice * 100
-					}
+				if livePos.MarkPrice > 0 {
+					pos.MarkPrice = livePos.MarkPrice
+					logger.Infof("  → mark_price: %.4f", livePos.MarkPrice)
 				}
+				pos.UnrealizedPnL = livePos.UnrealizedPnL
+				logger.Infof("  → unrealized_pnl: %.2f", livePos.UnrealizedPnL)
+				pos.LiqPrice = livePos.LiqPrice
+				pos.MarginUsed = livePos.MarginUsed
 			} else {
 				logger.Infof("❌ No match found for %s in livePriceMap, attempting fallback price fetch", key)
 				// Fallback: If not found in live positions, try to fetch current market price directly
-				marketPrice, err := trader.GetMarketPrice(dbPos.Symbol)
+				marketPrice, err := at.GetMarketPrice(dbPos.Symbol)
 				if err == nil && marketPrice > 0 {
 					logger.Infof("⚡ Fallback success! Got price for %s: %.4f", dbPos.Symbol, marketPrice)
-					posMap["mark_price"] = marketPrice
+					pos.MarkPrice = marketPrice
 					// Calculate PnL based on this fetched price
-					if strings.ToUpper(dbPos.Side) == "LONG" {
-						posMap["unrealized_pnl"] = (marketPrice - dbPos.EntryPrice) * dbPos.Quantity
-						posMap["unrealized_pnl_pct"] = (marketPrice - dbPos.EntryPrice) / dbPos.EntryPrice * 100
+					if pos.Side == "long" {
+						pos.UnrealizedPnL = (marketPrice - dbPos.EntryPrice) * dbPos.Quantity
 					} else {
-						posMap["unrealized_pnl"] = (dbPos.EntryPrice - marketPrice) * dbPos.Quantity
-						posMap["unrealized_pnl_pct"] = (dbPos.EntryPrice - marketPrice) / dbPos.EntryPrice * 100
+						pos.UnrealizedPnL = (dbPos.EntryPrice - marketPrice) * dbPos.Quantity
 					}
-					logger.Infof("  → Rescued uPnL: %.2f", posMap["unrealized_pnl"])
+					logger.Infof("  → Rescued uPnL: %.2f", pos.UnrealizedPnL)
 				} else {
 					logger.Warnf("⚠️ Fallback failed for %s: %v", dbPos.Symbol, err)
 				}
 			}
 
-			positions = append(positions, posMap)
+			summary := trader.SummarizePosition(pos, float64(dbPos.Leverage))
+			summary.EntryTime = dbPos.EntryTime.Format("2006-01-02 15:04")
+			// Dashboard shows the price move (%), not the return on margin
+			summary.UnrealizedPnLPct = 0
+			if dbPos.EntryPrice > 0 {
+				if pos.Side == "long" {
+					summary.UnrealizedPnLPct = (pos.MarkPrice - dbPos.EntryPrice) / dbPos.EntryPrice * 100
+				} else {
+					summary.UnrealizedPnLPct = (dbPos.EntryPrice - pos.MarkPrice) / dbPos.EntryPrice * 100
+				}
+			}
+			positions = append(positions, summary)
 		}
 
 		logger.Infof("📊 Returning %d positions for trader %s (filtered from DB, merged with live prices)", len(positions), traderID)
@@ -2046,7 +2341,7 @@ func (s *Server) handlePositions(c *gin.Context) {
 		logger.Infof("⚠️ Could not get DB positions for trader %s: %v, returning empty", traderID, dbErr)
 	}
 
-	c.JSON(http.StatusOK, []map[string]interface{}{})
+	c.JSON(http.StatusOK, []trader.PositionSummary{})
 }
 
 // handleDecisions Decision log list
@@ -2071,7 +2366,7 @@ func (s *Server) handleDecisions(c *gin.Context) {
 	}
 
 	// Get all historical decision records (unlimited)
-	records, err := st.Decision().GetLatestRecords(trader.GetID(), 10000)
+	records, err := st.Decision().ForUser(s.requestUserID(c)).GetLatestRecords(trader.GetID(), 10000)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to get decision log: %v", err),
@@ -2114,7 +2409,7 @@ func (s *Server) handleLatestDecisions(c *gin.Context) {
 		return
 	}
 
-	records, err := st.Decision().GetLatestRecords(trader.GetID(), limit)
+	records, err := st.Decision().ForUser(s.requestUserID(c)).GetLatestRecords(trader.GetID(), limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to get decision log: %v", err),
@@ -2152,7 +2447,7 @@ func (s *Server) handleStatistics(c *gin.Context) {
 		return
 	}
 
-	stats, err := st.Decision().GetStatistics(trader.GetID())
+	stats, err := st.Decision().ForUser(s.requestUserID(c)).GetStatistics(trader.GetID())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to get statistics: %v", err),
@@ -2163,6 +2458,126 @@ func (s *Server) handleStatistics(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// handleTradeExplanations Closed trades joined with the indicator values the AI saw at entry
+// Supports optional 'limit' parameter (default 100, max 1000)
+func (s *Server) handleTradeExplanations(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 100
+	if limitParam := c.Query("limit"); limitParam != "" {
+		fmt.Sscanf(limitParam, "%d", &limit)
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	trades, err := s.store.Position().ForUser(s.requestUserID(c)).GetTradeExplanations(traderID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get trade explanations: %v", err),
+		})
+		return
+	}
+	if trades == nil {
+		trades = []store.TradeExplanation{}
+	}
+
+	c.JSON(http.StatusOK, trades)
+}
+
+// handlePnLAttribution Realized PnL by symbol, side, close reason and entry hour of day (symbol × hour heatmap)
+// Supports optional 'days' (closed in the last N days, default 0 = all) and 'tz' (default America/New_York) parameters
+func (s *Server) handlePnLAttribution(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var since time.Time
+	if daysParam := c.Query("days"); daysParam != "" {
+		days, err := strconv.Atoi(daysParam)
+		if err != nil || days < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a non-negative integer"})
+			return
+		}
+		if days > 0 {
+			since = time.Now().AddDate(0, 0, -days)
+		}
+	}
+	tz := c.DefaultQuery("tz", "America/New_York")
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid time zone: %s", tz)})
+		return
+	}
+
+	attribution, err := s.store.Position().ForUser(s.requestUserID(c)).GetPnLAttribution(traderID, since, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get PnL attribution: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, attribution)
+}
+
+// handleStressReport Equity impact of adverse price moves on the open positions at current leverage
+// Supports optional 'moves' parameter (comma-separated move sizes in percent, default 2,5,10)
+func (s *Server) handleStressReport(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	moves, err := trader.ParseStressMoves(c.Query("moves"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := at.GetStressReport(moves)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to build stress report: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// handleTradeQuality Entry/exit quality of closed trades from their maximum favorable/adverse excursion (MFE/MAE)
+// Supports optional 'days' parameter (closed in the last N days, default 0 = all)
+func (s *Server) handleTradeQuality(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	since, ok := exportSince(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.store.Position().ForUser(s.requestUserID(c)).GetExcursionStats(traderID, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get trade quality: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
 // handleCompetition Competition overview (compare all traders)
 func (s *Server) handleCompetition(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -2204,14 +2619,15 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 	// Get equity historical data from new equity table
 	var snapshots []*store.EquitySnapshot
 	now := time.Now()
+	equity := s.store.Equity().ForUser(s.requestUserID(c))
 
 	if hours > 0 {
 		// Filter by time range
 		startTime := now.Add(-time.Duration(hours) * time.Hour)
-		snapshots, err = s.store.Equity().GetByTimeRange(traderID, startTime, now)
+		snapshots, err = equity.GetByTimeRange(traderID, startTime, now)
 	} else {
-		// Default: Every 3 minutes per cycle: 10000 records = about 20 days of data
-		snapshots, err = s.store.Equity().GetLatest(traderID, 10000)
+		// Default: raw points for the last 7 days, then 15-minute and hourly rollups (see store/equity_rollup.go)
+		snapshots, err = equity.GetLatest(traderID, 10000)
 	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -2235,6 +2651,17 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 		TotalPnLPct      float64 `json:"total_pnl_pct"`     // Total PnL percentage
 		PositionCount    int     `json:"position_count"`    // Position count
 		MarginUsedPct    float64 `json:"margin_used_pct"`   // Margin used percentage
+
+		// Buy-and-hold benchmark over the returned window (omitted where not tracked)
+		BenchmarkSymbol string  `json:"benchmark_symbol,omitempty"`
+		BenchmarkEquity float64 `json:"benchmark_equity,omitempty"`  // Initial balance held in the benchmark
+		BenchmarkPnLPct float64 `json:"benchmark_pnl_pct,omitempty"` // Benchmark return since the window's first tracked point
+		AlphaPct        float64 `json:"alpha_pct,omitempty"`         // Equity return minus benchmark return over the same span
+
+		// Rolled-up points older than 7 days (omitted for raw points): bucket size and equity range inside the bucket
+		ResolutionSec int     `json:"resolution_sec,omitempty"`
+		EquityLow     float64 `json:"equity_low,omitempty"`
+		EquityHigh    float64 `json:"equity_high,omitempty"`
 	}
 
 	// Use the balance of the first record as initial balance to calculate return rate
@@ -2244,6 +2671,7 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 	}
 
 	var history []EquityPoint
+	var benchmarkStart *store.EquitySnapshot
 	for _, snap := range snapshots {
 		// Calculate PnL percentage
 		totalPnLPct := 0.0
@@ -2251,7 +2679,7 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 			totalPnLPct = (snap.UnrealizedPnL / initialBalance) * 100
 		}
 
-		history = append(history, EquityPoint{
+		point := EquityPoint{
 			Timestamp:        snap.Timestamp.Format("2006-01-02 15:04:05"),
 			TotalEquity:      snap.TotalEquity,
 			AvailableBalance: snap.Balance,
@@ -2259,7 +2687,21 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 			TotalPnLPct:      totalPnLPct,
 			PositionCount:    snap.PositionCount,
 			MarginUsedPct:    snap.MarginUsedPct,
-		})
+			ResolutionSec:    snap.ResolutionSec,
+			EquityLow:        snap.EquityLow,
+			EquityHigh:       snap.EquityHigh,
+		}
+		// Benchmark returns start at the first point tracking the snapshot's benchmark symbol
+		if snap.BenchmarkEquity > 0 && (benchmarkStart == nil || benchmarkStart.BenchmarkSymbol != snap.BenchmarkSymbol) {
+			benchmarkStart = snap
+		}
+		if perf, ok := store.RelativePerformance(benchmarkStart, snap); ok {
+			point.BenchmarkSymbol = perf.Symbol
+			point.BenchmarkEquity = snap.BenchmarkEquity
+			point.BenchmarkPnLPct = perf.BenchmarkReturnPct
+			point.AlphaPct = perf.AlphaPct
+		}
+		history = append(history, point)
 	}
 
 	c.JSON(http.StatusOK, history)
@@ -2633,6 +3075,8 @@ func (s *Server) handleGetSupportedExchanges(c *gin.Context) {
 		{ExchangeType: "ibkr", Name: "Interactive Brokers", Type: "broker"},
 		{ExchangeType: "simplefx", Name: "SimpleFX", Type: "broker"},
 		{ExchangeType: "oanda", Name: "OANDA", Type: "forex"},
+		{ExchangeType: "binance-spot", Name: "Binance Spot", Type: "cex"},
+		{ExchangeType: "coinbase", Name: "Coinbase", Type: "cex"},
 	}
 
 	c.JSON(http.StatusOK, supportedExchanges)
@@ -2644,6 +3088,8 @@ func (s *Server) Start() error {
 	logger.Infof("🌐 API server starting at http://localhost%s", addr)
 	logger.Infof("📊 API Documentation:")
 	logger.Infof("  • GET  /api/health           - Health check")
+	logger.Infof("  • GET  /healthz              - Liveness probe (database ping)")
+	logger.Infof("  • GET  /readyz               - Readiness probe (database, exchange, AI provider, market data per trader)")
 	logger.Infof("  • GET  /api/traders          - Public AI trader leaderboard top 50 (no auth required)")
 	logger.Infof("  • GET  /api/competition      - Public competition data (no auth required)")
 	logger.Infof("  • GET  /api/top-traders      - Top 5 trader data (no auth required, for performance comparison)")
@@ -2664,6 +3110,22 @@ func (s *Server) Start() error {
 	logger.Infof("  • GET  /api/decisions?trader_id=xxx  - Specified trader's decision log")
 	logger.Infof("  • GET  /api/decisions/latest?trader_id=xxx - Specified trader's latest decisions")
 	logger.Infof("  • GET  /api/statistics?trader_id=xxx - Specified trader's statistics")
+	logger.Infof("  • GET  /api/trade-explanations?trader_id=xxx - Closed trades with their entry-time indicators")
+	logger.Infof("  • GET  /api/pnl-attribution?trader_id=xxx - PnL by symbol, side and hour of day")
+	logger.Infof("  • GET  /api/stress-report?trader_id=xxx - Equity impact of ±2/5/10%% moves on open positions")
+	logger.Infof("  • GET  /api/trade-quality?trader_id=xxx - Entry quality, MFE/MAE and stop efficiency of closed trades")
+	logger.Infof("  • POST /api/traders/:id/decisions - Manually enter/close positions (validated and executed like AI decisions)")
+	logger.Infof("  • GET  /api/traders/:id/equity-floor - Equity floor and halt state")
+	logger.Infof("  • POST /api/traders/:id/equity-floor/reset - Resume trading after an equity floor halt")
+	logger.Infof("  • POST /api/traders/:id/pause - Block new opens, keep managing positions")
+	logger.Infof("  • POST /api/traders/:id/resume - Lift a pause")
+	logger.Infof("  • GET  /api/traders/:id/grid - Grid/DCA state and grid P&L")
+	logger.Infof("  • GET  /api/traders/:id/fill-quality - Fill slippage per exchange/symbol")
+	logger.Infof("  • GET  /api/traders/:id/symbols - Last cycle's candidate symbols as the AI saw them")
+	logger.Infof("  • GET  /api/traders/:id/annotations - Trade journal annotations of closed trades")
+	logger.Infof("  • GET  /api/traders/:id/export/trades?format=csv|json - Export closed trades")
+	logger.Infof("  • GET  /api/traders/:id/export/decisions?format=csv|json - Export decision records")
+	logger.Infof("  • POST /api/traders/:id/import/trades - Import externally executed trades (CSV or JSON)")
 	logger.Infof("  • GET  /api/performance?trader_id=xxx - Specified trader's AI learning performance analysis")
 	logger.Info()
 
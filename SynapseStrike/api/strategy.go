@@ -2,12 +2,17 @@ package api
 
 import (
 	"SynapseStrike/decision"
+	"SynapseStrike/logger"
 	"SynapseStrike/market"
 	"SynapseStrike/mcp"
 	"SynapseStrike/store"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -48,6 +53,7 @@ func (s *Server) handleGetStrategies(c *gin.Context) {
 	for _, st := range strategies {
 		var config store.StrategyConfig
 		json.Unmarshal([]byte(st.Config), &config)
+		config.RedactSecrets()
 
 		result = append(result, gin.H{
 			"id":          st.ID,
@@ -84,6 +90,7 @@ func (s *Server) handleGetStrategy(c *gin.Context) {
 
 	var config store.StrategyConfig
 	json.Unmarshal([]byte(strategy.Config), &config)
+	config.RedactSecrets()
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":          strategy.ID,
@@ -115,6 +122,10 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	req.Config.RestoreSecrets(nil)
+	if !checkStrategyConfig(c, &req.Config) {
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -183,6 +194,12 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	// Clients only see the redacted config, an empty webhook secret keeps the stored one
+	existingConfig, _ := existing.ParseConfig()
+	req.Config.RestoreSecrets(existingConfig)
+	if !checkStrategyConfig(c, &req.Config) {
+		return
+	}
 
 	// Debug logging
 	fmt.Printf("🔍 Strategy update - StaticStocks received: %v", req.Config.CoinSource.StaticStocks)
@@ -223,6 +240,11 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		response["warnings"] = warnings
 	}
 
+	// Hot-reload into running traders (takes effect on their next cycle)
+	if reloaded := s.traderManager.UpdateStrategyConfig(strategyID, &req.Config); len(reloaded) > 0 {
+		response["reloaded_traders"] = reloaded
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -328,6 +350,7 @@ func (s *Server) handleGetActiveStrategy(c *gin.Context) {
 
 	var config store.StrategyConfig
 	json.Unmarshal([]byte(strategy.Config), &config)
+	config.RedactSecrets()
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":          strategy.ID,
@@ -547,7 +570,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 
 	// If requesting real AI call
 	if req.RunRealAI && req.AIModelID != "" {
-		aiResponse, aiErr := s.runRealAITest(userID, req.AIModelID, systemPrompt, userPrompt)
+		aiResponse, aiErr := s.runRealAITest(userID, req.AIModelID, systemPrompt, userPrompt, engine.GetGenerationParams())
 		if aiErr != nil {
 			c.JSON(http.StatusOK, gin.H{
 				"system_prompt":   systemPrompt,
@@ -587,7 +610,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 }
 
 // runRealAITest Execute real AI test call
-func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string) (string, error) {
+func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string, params mcp.GenerationParams) (string, error) {
 	// Get AI model configuration
 	model, err := s.store.AIModel().Get(userID, modelID)
 	if err != nil {
@@ -637,6 +660,8 @@ func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string)
 		aiClient.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
 	}
 
+	aiClient.SetGenerationParams(params)
+
 	// Call AI API
 	response, err := aiClient.CallWithMessages(systemPrompt, userPrompt)
 	if err != nil {
@@ -645,3 +670,89 @@ func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string)
 
 	return response, nil
 }
+
+// candidateWebhookMaxSkew max age of a signed webhook timestamp (replay protection)
+const candidateWebhookMaxSkew = 5 * time.Minute
+
+// verifyCandidateWebhookSignature checks signature = hex(HMAC-SHA256(secret, timestamp + "." + body))
+func verifyCandidateWebhookSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid or missing X-Webhook-Timestamp")
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > candidateWebhookMaxSkew || skew < -candidateWebhookMaxSkew {
+		return fmt.Errorf("timestamp outside allowed window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !hmac.Equal(got, expected) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// handleCandidateWebhook receives a signed candidate list from an external screener (no authentication, HMAC signed)
+// Body: {"symbols": ["BTCUSDT", {"symbol": "ETHUSDT", "score": 87.5, "note": "breakout"}]}
+func (s *Server) handleCandidateWebhook(c *gin.Context) {
+	strategyID := c.Param("strategy_id")
+
+	// Strategy may live in either strategies or tactics table
+	var configJSON string
+	if strategy, err := s.store.Strategy().GetByID(strategyID); err == nil {
+		configJSON = strategy.Config
+	} else if tactic, err := s.store.Tactic().GetByID(strategyID); err == nil {
+		configJSON = tactic.Config
+	} else {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Strategy not found"})
+		return
+	}
+
+	var config store.StrategyConfig
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse strategy configuration"})
+		return
+	}
+	source := config.CoinSource
+	if source.SourceType != "webhook" && !(source.SourceType == "mixed" && source.UseWebhook) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook candidate source is not enabled for this strategy"})
+		return
+	}
+	if source.WebhookSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook secret is not configured for this strategy"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 1<<20)
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+	if err := verifyCandidateWebhookSignature(source.WebhookSecret,
+		c.GetHeader("X-Webhook-Timestamp"), c.GetHeader("X-Webhook-Signature"), body, time.Now()); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req struct {
+		Symbols []decision.WebhookCandidate `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
+		return
+	}
+
+	count, err := decision.SetWebhookCandidates(strategyID, req.Symbols)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Infof("📥 Webhook candidates received for strategy %s: %d symbols", strategyID, count)
+	c.JSON(http.StatusOK, gin.H{"message": "Candidates updated", "count": count})
+}
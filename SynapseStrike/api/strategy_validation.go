@@ -0,0 +1,54 @@
+package api
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"SynapseStrike/trader"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkStrategyConfig migrates legacy fields and validates a submitted config
+// Writes a 400 response listing the invalid fields and returns false if the config is rejected.
+func checkStrategyConfig(c *gin.Context, config *store.StrategyConfig) bool {
+	config.Migrate()
+	if err := trader.ValidateStrategyConfig(config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Invalid strategy config: " + err.Error(),
+			"config_errors": store.AsConfigErrors(err),
+		})
+		return false
+	}
+	return true
+}
+
+// handleStrategyDryRun Validate a strategy config and check it against live data sources (no trading, no AI call)
+func (s *Server) handleStrategyDryRun(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Config   store.StrategyConfig `json:"config" binding:"required"`
+		Exchange string               `json:"exchange"` // Exchange type selecting the market data source (default: crypto data)
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
+		return
+	}
+
+	// Stock candidates need Alpaca market data
+	if err := s.loadAlpacaCredentialsForBacktest(userID); err != nil {
+		logger.Infof("⚠️ Strategy dry run: could not load Alpaca credentials: %v", err)
+	}
+
+	report := decision.DryRunConfig(&req.Config, req.Exchange, trader.ValidateStrategyConfig)
+	c.JSON(http.StatusOK, gin.H{
+		"ok":     report.OK(),
+		"report": report,
+	})
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetSymbolView Last cycle's per-symbol data as presented to the AI (price, indicators, news, filter status)
+// Lets the dashboard render the engine's view without re-fetching from external APIs.
+func (s *Server) handleGetSymbolView(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	snapshot := at.GetSymbolSnapshot()
+	if snapshot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No cycle has run yet"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
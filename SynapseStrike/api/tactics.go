@@ -48,6 +48,7 @@ func (s *Server) handleGetTactics(c *gin.Context) {
 	for _, st := range tactics {
 		var config store.TacticConfig
 		json.Unmarshal([]byte(st.Config), &config)
+		config.RedactSecrets()
 
 		result = append(result, gin.H{
 			"id":            st.ID,
@@ -85,6 +86,7 @@ func (s *Server) handleGetTactic(c *gin.Context) {
 
 	var config store.TacticConfig
 	json.Unmarshal([]byte(tactic.Config), &config)
+	config.RedactSecrets()
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":          tactic.ID,
@@ -117,6 +119,10 @@ func (s *Server) handleCreateTactic(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	req.Config.RestoreSecrets(nil)
+	if !checkStrategyConfig(c, &req.Config) {
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -192,6 +198,12 @@ func (s *Server) handleUpdateTactic(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	// Clients only see the redacted config, an empty webhook secret keeps the stored one
+	existingConfig, _ := existing.ParseConfig()
+	req.Config.RestoreSecrets(existingConfig)
+	if !checkStrategyConfig(c, &req.Config) {
+		return
+	}
 
 	// Debug logging
 	fmt.Printf("🔍 Tactic update - StaticStocks received: %v", req.Config.CoinSource.StaticStocks)
@@ -232,6 +244,11 @@ func (s *Server) handleUpdateTactic(c *gin.Context) {
 		response["warnings"] = warnings
 	}
 
+	// Hot-reload into running traders (takes effect on their next cycle)
+	if reloaded := s.traderManager.UpdateStrategyConfig(tacticID, &req.Config); len(reloaded) > 0 {
+		response["reloaded_traders"] = reloaded
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -337,6 +354,7 @@ func (s *Server) handleGetActiveTactic(c *gin.Context) {
 
 	var config store.TacticConfig
 	json.Unmarshal([]byte(tactic.Config), &config)
+	config.RedactSecrets()
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":          tactic.ID,
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"SynapseStrike/store"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxTradeImportBytes  = 10 << 20 // 10 MB
+	maxTradeImportErrors = 50       // Row errors listed in the import response
+)
+
+// exportSince start of the export window from ?days= (zero time = everything, false = bad request written)
+func exportSince(c *gin.Context) (time.Time, bool) {
+	daysParam := c.Query("days")
+	if daysParam == "" {
+		return time.Time{}, true
+	}
+	days, err := strconv.Atoi(daysParam)
+	if err != nil || days < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a non-negative integer"})
+		return time.Time{}, false
+	}
+	if days == 0 {
+		return time.Time{}, true
+	}
+	return time.Now().AddDate(0, 0, -days), true
+}
+
+// exportFormat csv (default) or json from ?format= (false = bad request written)
+func exportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return "", false
+	}
+	return format, true
+}
+
+// handleExportTrades Export closed trades of a trader as CSV or JSON
+func (s *Server) handleExportTrades(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	since, ok := exportSince(c)
+	if !ok {
+		return
+	}
+
+	positions, err := s.store.Position().ForUser(s.requestUserID(c)).GetClosedPositionsSince(traderID, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get closed trades: %v", err)})
+		return
+	}
+	trades := make([]store.ExportedTrade, 0, len(positions))
+	for _, pos := range positions {
+		trades = append(trades, store.NewExportedTrade(pos))
+	}
+
+	filename := fmt.Sprintf("%s_trades_%s.%s", traderID, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.JSON(http.StatusOK, store.TradeExport{
+			Schema:     store.TradeExportSchema,
+			TraderID:   traderID,
+			ExportedAt: time.Now().UTC(),
+			Trades:     trades,
+		})
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := store.WriteTradesCSV(c.Writer, trades); err != nil {
+		c.Error(err)
+	}
+}
+
+// handleExportDecisions Export decision records of a trader as CSV or JSON
+func (s *Server) handleExportDecisions(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	since, ok := exportSince(c)
+	if !ok {
+		return
+	}
+
+	records, err := s.store.Decision().ForUser(s.requestUserID(c)).GetRecordsSince(traderID, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get decision records: %v", err)})
+		return
+	}
+	if records == nil {
+		records = []*store.DecisionRecord{}
+	}
+
+	filename := fmt.Sprintf("%s_decisions_%s.%s", traderID, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.JSON(http.StatusOK, store.DecisionExport{
+			Schema:     store.DecisionExportSchema,
+			TraderID:   traderID,
+			ExportedAt: time.Now().UTC(),
+			Records:    records,
+		})
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := store.WriteDecisionsCSV(c.Writer, records); err != nil {
+		c.Error(err)
+	}
+}
+
+// handleImportTrades Import externally executed trades (CSV or JSON export schema) as closed positions
+func (s *Server) handleImportTrades(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get trader: %v", err)})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxTradeImportBytes)
+	var trades []store.ExportedTrade
+	if c.Query("format") == "csv" || strings.Contains(c.ContentType(), "csv") {
+		if trades, err = store.ParseTradesCSV(body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid CSV: %v", err)})
+			return
+		}
+	} else {
+		var doc store.TradeExport
+		if err := json.NewDecoder(body).Decode(&doc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid JSON: %v", err)})
+			return
+		}
+		if doc.Schema != "" && doc.Schema != store.TradeExportSchema {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported schema %q, expected %s", doc.Schema, store.TradeExportSchema)})
+			return
+		}
+		trades = doc.Trades
+	}
+	if len(trades) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No trades to import"})
+		return
+	}
+
+	imported, duplicates := 0, 0
+	errors := []string{}
+	for i := range trades {
+		trade := &trades[i]
+		if err := trade.Validate(); err != nil {
+			if len(errors) < maxTradeImportErrors {
+				errors = append(errors, fmt.Sprintf("trade %d: %v", i+1, err))
+			}
+			continue
+		}
+		created, err := s.store.Position().ImportTrade(traderID, traderRecord.ExchangeID, *trade)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to import trade %d: %v", i+1, err)})
+			return
+		}
+		if created {
+			imported++
+		} else {
+			duplicates++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported":   imported,
+		"duplicates": duplicates,
+		"invalid":    len(trades) - imported - duplicates,
+		"errors":     errors,
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"SynapseStrike/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlePauseTrader Block new opens of a running trader, positions stay managed (no process or loop stop)
+func (s *Server) handlePauseTrader(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"` // Shown in the status and to the AI (optional)
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
+			return
+		}
+	}
+
+	userID := c.GetString("user_id")
+	if !at.Pause("user "+userID, req.Reason) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trader is already paused"})
+		return
+	}
+	logger.Infof("⏸ User %s paused trader %s", userID, traderID)
+	c.JSON(http.StatusOK, at.GetPauseStatus())
+}
+
+// handleResumeTrader Lift an operator pause so the trader opens positions again
+func (s *Server) handleResumeTrader(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if !at.Resume("user " + userID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trader is not paused"})
+		return
+	}
+	logger.Infof("▶️ User %s resumed trader %s", userID, traderID)
+	c.JSON(http.StatusOK, at.GetPauseStatus())
+}
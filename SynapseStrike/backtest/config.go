@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"SynapseStrike/market"
+	"SynapseStrike/mcp"
 	"SynapseStrike/store"
 )
 
@@ -17,6 +18,28 @@ type AIConfig struct {
 	SecretKey   string  `json:"secret_key,omitempty"`
 	BaseURL     string  `json:"base_url,omitempty"`
 	Temperature float64 `json:"temperature,omitempty"`
+
+	// Sampling parameters (unset = provider client defaults)
+	TopP            float64 `json:"top_p,omitempty"`
+	MaxTokens       int     `json:"max_tokens,omitempty"`
+	ReasoningEffort string  `json:"reasoning_effort,omitempty"` // "low" | "medium" | "high"
+}
+
+// GenerationParams sampling parameters applied to the backtest AI client
+func (c AIConfig) GenerationParams() mcp.GenerationParams {
+	params := mcp.GenerationParams{
+		MaxTokens:       c.MaxTokens,
+		ReasoningEffort: c.ReasoningEffort,
+	}
+	if c.Temperature > 0 {
+		temperature := c.Temperature
+		params.Temperature = &temperature
+	}
+	if c.TopP > 0 {
+		topP := c.TopP
+		params.TopP = &topP
+	}
+	return params
 }
 
 type LeverageConfig struct {
@@ -50,8 +73,11 @@ type BacktestConfig struct {
 	StrategyID    string `json:"strategy_id,omitempty"`    // Strategy/tactic ID (loads stock source, indicators, algo config)
 	SimulatedTime int64  `json:"simulated_time,omitempty"` // Unix timestamp: pretend "now" is this time for algo evaluation
 
+	// Full strategy config to run instead of the one derived from symbols/timeframes (set by the optimizer)
+	Strategy *store.StrategyConfig `json:"strategy,omitempty"`
+
 	// Data source selection
-	DataSource     string `json:"data_source,omitempty"`      // "alpaca" (default) or "polygon" (Widesurf/Polygon-compatible)
+	DataSource     string `json:"data_source,omitempty"`      // "alpaca" (default), "polygon" (Widesurf/Polygon-compatible) or "archive" (local kline archive)
 	PolygonAPIKey  string `json:"polygon_api_key,omitempty"`  // Widesurf API key
 	PolygonBaseURL string `json:"polygon_base_url,omitempty"` // Widesurf base URL (e.g. https://api.widesurf.io)
 
@@ -156,6 +182,13 @@ func (cfg *BacktestConfig) Validate() error {
 	if cfg.AICfg.Temperature == 0 {
 		cfg.AICfg.Temperature = 0.4
 	}
+	if cfg.AICfg.TopP < 0 || cfg.AICfg.TopP > 1 || cfg.AICfg.MaxTokens < 0 {
+		return fmt.Errorf("ai top_p must be between 0 and 1 and max_tokens cannot be negative")
+	}
+	cfg.AICfg.ReasoningEffort = strings.ToLower(strings.TrimSpace(cfg.AICfg.ReasoningEffort))
+	if !mcp.IsValidReasoningEffort(cfg.AICfg.ReasoningEffort) {
+		return fmt.Errorf("unsupported ai reasoning_effort '%s'", cfg.AICfg.ReasoningEffort)
+	}
 
 	if cfg.Leverage.LargeCapLeverage <= 0 {
 		cfg.Leverage.LargeCapLeverage = 5
@@ -196,6 +229,16 @@ func validateFillPolicy(policy string) error {
 // ToStrategyConfig converts BacktestConfig to StrategyConfig for unified prompt generation.
 // This ensures backtest uses the same StrategyEngine logic as live trading.
 func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
+	if cfg.Strategy != nil {
+		strategy := *cfg.Strategy
+		strategy.CoinSource = store.CoinSourceConfig{
+			SourceType:     "static",
+			StaticStocks:   cfg.Symbols,
+			StockPoolLimit: len(cfg.Symbols),
+		}
+		return &strategy
+	}
+
 	// Determine primary and longer timeframe from the timeframes list
 	primaryTF := "5m"
 	longerTF := "4h"
@@ -208,12 +251,12 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 
 	return &store.StrategyConfig{
 		CoinSource: store.CoinSourceConfig{
-			SourceType:    "static",
-			StaticCoins:   cfg.Symbols,
-			UseCoinPool:   false,
-			CoinPoolLimit: len(cfg.Symbols),
-			UseOITop:      false,
-			OITopLimit:    0,
+			SourceType:     "static",
+			StaticStocks:   cfg.Symbols,
+			UseStockPool:   false,
+			StockPoolLimit: len(cfg.Symbols),
+			UseOITop:       false,
+			OITopLimit:     0,
 		},
 		Indicators: store.IndicatorConfig{
 			Klines: store.KlineConfig{
@@ -245,6 +288,7 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			SmallCapMaxPositionValueRatio: 1.0,
 			MaxMarginUsage:               0.9,
 			MinPositionSize:              12,
+			LargeCapMinPositionSize:      60,
 			MinRiskRewardRatio:           3.0,
 			MinConfidence:                75,
 		},
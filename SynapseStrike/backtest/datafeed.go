@@ -75,7 +75,9 @@ func (df *DataFeed) loadAll() error {
 
 			var klines []market.Kline
 			var err error
-			if df.cfg.DataSource == "polygon" && df.cfg.PolygonAPIKey != "" {
+			if df.cfg.DataSource == "archive" {
+				klines, err = loadArchivedKlines(symbol, tf, fetchStart, fetchEnd)
+			} else if df.cfg.DataSource == "polygon" && df.cfg.PolygonAPIKey != "" {
 				klines, err = market.GetKlinesRangePolygon(symbol, tf, fetchStart, fetchEnd, df.cfg.PolygonAPIKey, df.cfg.PolygonBaseURL)
 			} else {
 				klines, err = market.GetKlinesRange(symbol, tf, fetchStart, fetchEnd)
@@ -125,6 +127,22 @@ func (df *DataFeed) loadAll() error {
 	return nil
 }
 
+// loadArchivedKlines reads klines from the local kline archive
+func loadArchivedKlines(symbol, tf string, start, end time.Time) ([]market.Kline, error) {
+	archive := market.GetKlineArchive()
+	if archive == nil {
+		return nil, fmt.Errorf("kline archive not available")
+	}
+	klines, err := archive.Range(symbol, tf, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no archived klines, download them first with scripts/klinearchive")
+	}
+	return klines, nil
+}
+
 func (df *DataFeed) DecisionBarCount() int {
 	return len(df.decisionTimes)
 }
@@ -21,16 +21,19 @@ type Manager struct {
 	cancels    map[string]context.CancelFunc
 	mcpClient  mcp.AIClient
 	aiResolver AIConfigResolver
+
+	optimizers map[string]*Optimizer // Walk-forward optimizations by ID (see optimizer.go)
 }
 
 type AIConfigResolver func(*BacktestConfig) error
 
 func NewManager(defaultClient mcp.AIClient) *Manager {
 	return &Manager{
-		runners:   make(map[string]*Runner),
-		metadata:  make(map[string]*RunMetadata),
-		cancels:   make(map[string]context.CancelFunc),
-		mcpClient: defaultClient,
+		runners:    make(map[string]*Runner),
+		metadata:   make(map[string]*RunMetadata),
+		cancels:    make(map[string]context.CancelFunc),
+		mcpClient:  defaultClient,
+		optimizers: make(map[string]*Optimizer),
 	}
 }
 
@@ -491,3 +494,63 @@ func (m *Manager) RestoreRuns() error {
 func (m *Manager) RestoreRunsFromDisk() error {
 	return m.RestoreRuns()
 }
+
+// StartOptimization launches a walk-forward optimization in the background
+// onDone receives the final report and returns the ID of the strategy version it saved (empty = none).
+func (m *Manager) StartOptimization(id string, req OptimizeRequest, onDone func(*OptimizeReport) string) (*OptimizeReport, error) {
+	optimizer, err := NewOptimizer(id, req, m.runToCompletion)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if existing, ok := m.optimizers[id]; ok && existing.Report().State == OptimizeRunning {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("optimization %s is already running", id)
+	}
+	m.optimizers[id] = optimizer
+	m.mu.Unlock()
+
+	go func() {
+		report, _ := optimizer.Run(context.Background())
+		if onDone == nil {
+			return
+		}
+		if strategyID := onDone(report); strategyID != "" {
+			optimizer.mu.Lock()
+			optimizer.report.StrategyID = strategyID
+			optimizer.mu.Unlock()
+		}
+	}()
+	return optimizer.Report(), nil
+}
+
+// GetOptimization returns a snapshot of an optimization report
+func (m *Manager) GetOptimization(id string) (*OptimizeReport, bool) {
+	m.mu.RLock()
+	optimizer, ok := m.optimizers[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return optimizer.Report(), true
+}
+
+// runToCompletion runs one sweep backtest and removes it from the run list once its metrics are known
+func (m *Manager) runToCompletion(ctx context.Context, cfg BacktestConfig) (*Metrics, error) {
+	runner, err := m.Start(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := m.Delete(cfg.RunID); err != nil {
+			logger.Warnf("⚠️ [WFO] Failed to remove sweep run %s: %v", cfg.RunID, err)
+		}
+	}()
+
+	if err := m.Wait(cfg.RunID); err != nil && !errors.Is(err, errLiquidated) {
+		return nil, err
+	}
+	state := runner.snapshotState()
+	return CalculateMetrics(cfg.RunID, &runner.cfg, &state)
+}
@@ -0,0 +1,510 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+)
+
+// ============================================================================
+// Walk-Forward Parameter Optimization
+// ============================================================================
+// The backtest window is cut into consecutive folds. Every parameter
+// combination of the sweep runs on the in-sample (train) part of each fold,
+// the best one by the objective is then run on the out-of-sample (test) part.
+// Finally every distinct fold winner is scored on all test parts and the one
+// with the best mean out-of-sample objective is reported as the best config.
+
+// Optimization objectives (OptimizeRequest.Objective)
+const (
+	ObjectiveSharpe       = "sharpe"        // Sharpe ratio
+	ObjectiveReturn       = "return"        // Total return %
+	ObjectiveProfitFactor = "profit_factor" // Gross profit / gross loss
+	ObjectiveReturnDD     = "return_dd"     // Return % / max drawdown %
+)
+
+// Optimization states (OptimizeReport.State)
+const (
+	OptimizeRunning   = "running"
+	OptimizeCompleted = "completed"
+	OptimizeFailed    = "failed"
+)
+
+const (
+	defaultOptimizeFolds    = 3
+	defaultOptimizeTrainPct = 70.0
+	defaultOptimizeMaxRuns  = 200
+
+	liquidatedScore = -1e9 // Objective score of liquidated or missing runs
+)
+
+// ParamRange sweep range of one strategy parameter (Min..Max inclusive in Step increments)
+type ParamRange struct {
+	Name string  `json:"name"` // See SweepableParams
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step"` // 0 = Min and Max only
+}
+
+// OptimizeRequest walk-forward parameter sweep request
+type OptimizeRequest struct {
+	Base      BacktestConfig       `json:"base"`      // Symbols, window, AI and cost settings shared by every run
+	Strategy  store.StrategyConfig `json:"strategy"`  // Strategy the sweep starts from
+	Params    []ParamRange         `json:"params"`    // Swept parameters
+	Folds     int                  `json:"folds"`     // Walk-forward folds (default: 3)
+	TrainPct  float64              `json:"train_pct"` // In-sample share of each fold % (default: 70)
+	Objective string               `json:"objective"` // "sharpe" (default) | "return" | "profit_factor" | "return_dd"
+	MaxRuns   int                  `json:"max_runs"`  // Backtest run budget of the whole sweep (default: 200)
+}
+
+// FoldResult in-sample winner of one fold and its out-of-sample result
+type FoldResult struct {
+	Index       int                `json:"index"`
+	TrainStart  int64              `json:"train_start"`
+	TrainEnd    int64              `json:"train_end"`
+	TestStart   int64              `json:"test_start"`
+	TestEnd     int64              `json:"test_end"`
+	BestParams  map[string]float64 `json:"best_params"`
+	TrainScore  float64            `json:"train_score"`
+	TestScore   float64            `json:"test_score"`
+	TestMetrics *Metrics           `json:"test_metrics,omitempty"`
+}
+
+// OutOfSampleSummary mean out-of-sample metrics of the best parameters over all folds
+type OutOfSampleSummary struct {
+	Score          float64 `json:"score"`
+	TotalReturnPct float64 `json:"total_return_pct"`
+	SharpeRatio    float64 `json:"sharpe_ratio"`
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Worst fold
+	WinRate        float64 `json:"win_rate"`
+	Trades         int     `json:"trades"` // Sum over folds
+}
+
+// OptimizeReport progress and result of a walk-forward optimization
+type OptimizeReport struct {
+	ID           string                `json:"id"`
+	UserID       string                `json:"user_id,omitempty"`
+	State        string                `json:"state"`
+	Error        string                `json:"error,omitempty"`
+	Objective    string                `json:"objective"`
+	Combinations int                   `json:"combinations"`
+	RunsDone     int                   `json:"runs_done"`
+	RunsTotal    int                   `json:"runs_total"`
+	Folds        []FoldResult          `json:"folds"`
+	BestParams   map[string]float64    `json:"best_params,omitempty"`
+	OutOfSample  *OutOfSampleSummary   `json:"out_of_sample,omitempty"`
+	BestStrategy *store.StrategyConfig `json:"-"`                     // Base strategy with BestParams applied
+	StrategyID   string                `json:"strategy_id,omitempty"` // Saved strategy version (set by the caller)
+	StartedAt    time.Time             `json:"started_at"`
+	FinishedAt   time.Time             `json:"finished_at,omitempty"`
+}
+
+// SweepableParams strategy parameters the optimizer can sweep
+var SweepableParams = map[string]func(cfg *store.StrategyConfig, v float64){
+	"ema_fast": func(cfg *store.StrategyConfig, v float64) {
+		setEMAPeriod(cfg, 0, int(math.Round(v)))
+	},
+	"ema_slow": func(cfg *store.StrategyConfig, v float64) {
+		setEMAPeriod(cfg, 1, int(math.Round(v)))
+	},
+	"confluence_min_match": func(cfg *store.StrategyConfig, v float64) {
+		cfg.Indicators.ConfluenceMinMatch = int(math.Round(v))
+	},
+	"min_risk_reward_ratio": func(cfg *store.StrategyConfig, v float64) {
+		cfg.RiskControl.MinRiskRewardRatio = v
+	},
+	"vwap_min_deviation_atr": func(cfg *store.StrategyConfig, v float64) {
+		cfg.Indicators.VWAPMinDeviationATR = v
+	},
+	"vwap_max_deviation_atr": func(cfg *store.StrategyConfig, v float64) {
+		cfg.Indicators.VWAPMaxDeviationATR = v
+	},
+}
+
+// setEMAPeriod sets EMA period at idx, filling missing periods with the defaults [20, 50]
+func setEMAPeriod(cfg *store.StrategyConfig, idx, period int) {
+	defaults := []int{20, 50}
+	for len(cfg.Indicators.EMAPeriods) <= idx {
+		cfg.Indicators.EMAPeriods = append(cfg.Indicators.EMAPeriods, defaults[len(cfg.Indicators.EMAPeriods)])
+	}
+	cfg.Indicators.EMAPeriods[idx] = period
+}
+
+// RunBacktestFunc runs one backtest to completion and returns its metrics
+type RunBacktestFunc func(ctx context.Context, cfg BacktestConfig) (*Metrics, error)
+
+// Optimizer walk-forward parameter optimizer
+type Optimizer struct {
+	req  OptimizeRequest
+	run  RunBacktestFunc
+	grid []map[string]float64
+
+	mu     sync.Mutex // Guards report while the sweep runs
+	report *OptimizeReport
+}
+
+// NewOptimizer validates the request and prepares the parameter grid
+func NewOptimizer(id string, req OptimizeRequest, run RunBacktestFunc) (*Optimizer, error) {
+	if run == nil {
+		return nil, fmt.Errorf("backtest runner is nil")
+	}
+	if len(req.Params) == 0 {
+		return nil, fmt.Errorf("at least one parameter range is required")
+	}
+	if req.Base.EndTS <= req.Base.StartTS {
+		return nil, fmt.Errorf("end_ts must be after start_ts")
+	}
+	if req.Folds <= 0 {
+		req.Folds = defaultOptimizeFolds
+	}
+	if req.TrainPct <= 0 {
+		req.TrainPct = defaultOptimizeTrainPct
+	}
+	if req.TrainPct >= 100 {
+		return nil, fmt.Errorf("train_pct must be below 100")
+	}
+	if req.MaxRuns <= 0 {
+		req.MaxRuns = defaultOptimizeMaxRuns
+	}
+	req.Objective = strings.ToLower(strings.TrimSpace(req.Objective))
+	switch req.Objective {
+	case "":
+		req.Objective = ObjectiveSharpe
+	case ObjectiveSharpe, ObjectiveReturn, ObjectiveProfitFactor, ObjectiveReturnDD:
+	default:
+		return nil, fmt.Errorf("unsupported objective '%s'", req.Objective)
+	}
+
+	grid, err := buildParamGrid(req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Train runs per fold + one test run per fold + at most Folds winners re-tested on every fold
+	runsTotal := len(grid)*req.Folds + req.Folds*req.Folds
+	if runsTotal > req.MaxRuns {
+		return nil, fmt.Errorf("sweep needs up to %d backtest runs (%d combinations × %d folds), exceeding max_runs %d",
+			runsTotal, len(grid), req.Folds, req.MaxRuns)
+	}
+
+	return &Optimizer{
+		req:  req,
+		run:  run,
+		grid: grid,
+		report: &OptimizeReport{
+			ID:           id,
+			UserID:       req.Base.UserID,
+			State:        OptimizeRunning,
+			Objective:    req.Objective,
+			Combinations: len(grid),
+			RunsTotal:    runsTotal,
+			StartedAt:    time.Now(),
+		},
+	}, nil
+}
+
+// Report returns a snapshot of the report (safe to call while the sweep runs)
+func (o *Optimizer) Report() *OptimizeReport {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	snapshot := *o.report
+	snapshot.Folds = append([]FoldResult(nil), o.report.Folds...)
+	return &snapshot
+}
+
+// buildParamGrid expands parameter ranges into all combinations
+func buildParamGrid(ranges []ParamRange) ([]map[string]float64, error) {
+	grid := []map[string]float64{{}}
+	seen := make(map[string]bool)
+	for _, r := range ranges {
+		name := strings.ToLower(strings.TrimSpace(r.Name))
+		if _, ok := SweepableParams[name]; !ok {
+			return nil, fmt.Errorf("parameter '%s' cannot be swept", r.Name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("parameter '%s' listed twice", name)
+		}
+		seen[name] = true
+		if r.Max < r.Min || r.Step < 0 {
+			return nil, fmt.Errorf("invalid range for '%s': min %.4g, max %.4g, step %.4g", name, r.Min, r.Max, r.Step)
+		}
+
+		values := []float64{r.Min}
+		if r.Step > 0 {
+			for i := 1; ; i++ {
+				v := r.Min + float64(i)*r.Step
+				if v > r.Max+r.Step*1e-9 {
+					break
+				}
+				values = append(values, math.Round(v*1e6)/1e6)
+			}
+		} else if r.Max > r.Min {
+			values = append(values, r.Max)
+		}
+
+		next := make([]map[string]float64, 0, len(grid)*len(values))
+		for _, combo := range grid {
+			for _, v := range values {
+				c := make(map[string]float64, len(combo)+1)
+				for k, existing := range combo {
+					c[k] = existing
+				}
+				c[name] = v
+				next = append(next, c)
+			}
+		}
+		grid = next
+	}
+	return grid, nil
+}
+
+// foldWindow train/test time window of one fold
+type foldWindow struct {
+	trainStart, trainEnd, testStart, testEnd int64
+}
+
+// walkForwardWindows cuts [start, end] into consecutive folds split at trainPct
+func walkForwardWindows(start, end int64, folds int, trainPct float64) []foldWindow {
+	span := (end - start) / int64(folds)
+	windows := make([]foldWindow, 0, folds)
+	for i := 0; i < folds; i++ {
+		foldStart := start + int64(i)*span
+		foldEnd := foldStart + span
+		if i == folds-1 {
+			foldEnd = end
+		}
+		split := foldStart + int64(float64(foldEnd-foldStart)*trainPct/100)
+		windows = append(windows, foldWindow{trainStart: foldStart, trainEnd: split, testStart: split, testEnd: foldEnd})
+	}
+	return windows
+}
+
+// objectiveScore scores metrics by the objective (higher is better, liquidation is worst)
+func objectiveScore(m *Metrics, objective string) float64 {
+	if m == nil || m.Liquidated {
+		return liquidatedScore
+	}
+	switch objective {
+	case ObjectiveReturn:
+		return m.TotalReturnPct
+	case ObjectiveProfitFactor:
+		return m.ProfitFactor
+	case ObjectiveReturnDD:
+		dd := m.MaxDrawdownPct
+		if dd < 1 {
+			dd = 1 // Avoid rewarding near-zero drawdown of runs that barely traded
+		}
+		return m.TotalReturnPct / dd
+	default:
+		return m.SharpeRatio
+	}
+}
+
+// paramsKey stable identity of a parameter combination
+func paramsKey(params map[string]float64) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%g", name, params[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ApplyParams returns a copy of base with the parameter values applied
+func ApplyParams(base *store.StrategyConfig, params map[string]float64) (*store.StrategyConfig, error) {
+	data, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("copy strategy config: %w", err)
+	}
+	var cfg store.StrategyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("copy strategy config: %w", err)
+	}
+	for name, v := range params {
+		set, ok := SweepableParams[name]
+		if !ok {
+			return nil, fmt.Errorf("parameter '%s' cannot be swept", name)
+		}
+		set(&cfg, v)
+	}
+	return &cfg, nil
+}
+
+// Run executes the sweep; the report is final once Run returns
+func (o *Optimizer) Run(ctx context.Context) (*OptimizeReport, error) {
+	err := o.sweep(ctx)
+
+	o.mu.Lock()
+	o.report.FinishedAt = time.Now()
+	if err != nil {
+		o.report.State = OptimizeFailed
+		o.report.Error = err.Error()
+		logger.Warnf("⚠️ [WFO %s] Optimization failed: %v", o.report.ID, err)
+	} else {
+		o.report.State = OptimizeCompleted
+		logger.Infof("✅ [WFO %s] Best params %s, out-of-sample %s %.3f", o.report.ID,
+			paramsKey(o.report.BestParams), o.report.Objective, o.report.OutOfSample.Score)
+	}
+	o.mu.Unlock()
+	return o.Report(), err
+}
+
+// sweep runs all folds and selects the best parameters
+func (o *Optimizer) sweep(ctx context.Context) error {
+	windows := walkForwardWindows(o.req.Base.StartTS, o.req.Base.EndTS, o.req.Folds, o.req.TrainPct)
+	objective := o.req.Objective
+
+	// testMetrics[paramsKey][fold] out-of-sample metrics of fold winners
+	testMetrics := make(map[string]map[int]*Metrics)
+	winners := make(map[string]map[string]float64)
+
+	for i, w := range windows {
+		var (
+			bestParams map[string]float64
+			bestScore  = float64(liquidatedScore)
+		)
+		for _, params := range o.grid {
+			m, err := o.backtest(ctx, params, w.trainStart, w.trainEnd, fmt.Sprintf("f%d_train", i+1))
+			if err != nil {
+				return err
+			}
+			if score := objectiveScore(m, objective); bestParams == nil || score > bestScore {
+				bestParams, bestScore = params, score
+			}
+		}
+
+		m, err := o.backtest(ctx, bestParams, w.testStart, w.testEnd, fmt.Sprintf("f%d_test", i+1))
+		if err != nil {
+			return err
+		}
+		key := paramsKey(bestParams)
+		if testMetrics[key] == nil {
+			testMetrics[key] = make(map[int]*Metrics)
+		}
+		testMetrics[key][i] = m
+		winners[key] = bestParams
+
+		o.mu.Lock()
+		o.report.Folds = append(o.report.Folds, FoldResult{
+			Index:       i + 1,
+			TrainStart:  w.trainStart,
+			TrainEnd:    w.trainEnd,
+			TestStart:   w.testStart,
+			TestEnd:     w.testEnd,
+			BestParams:  bestParams,
+			TrainScore:  bestScore,
+			TestScore:   objectiveScore(m, objective),
+			TestMetrics: m,
+		})
+		o.mu.Unlock()
+		logger.Infof("📈 [WFO %s] Fold %d/%d: %s (train %.3f, test %.3f)", o.report.ID, i+1, len(windows),
+			key, bestScore, objectiveScore(m, objective))
+	}
+
+	// Score every fold winner on all test windows, best mean wins
+	keys := make([]string, 0, len(winners))
+	for key := range winners {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		best       *OutOfSampleSummary
+		bestParams map[string]float64
+	)
+	for _, key := range keys {
+		for i, w := range windows {
+			if _, ok := testMetrics[key][i]; ok {
+				continue
+			}
+			m, err := o.backtest(ctx, winners[key], w.testStart, w.testEnd, fmt.Sprintf("f%d_test", i+1))
+			if err != nil {
+				return err
+			}
+			testMetrics[key][i] = m
+		}
+		summary := summarizeOutOfSample(testMetrics[key], objective)
+		if best == nil || summary.Score > best.Score {
+			best, bestParams = summary, winners[key]
+		}
+	}
+
+	bestCfg, err := ApplyParams(&o.req.Strategy, bestParams)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.report.BestParams = bestParams
+	o.report.OutOfSample = best
+	o.report.BestStrategy = bestCfg
+	o.mu.Unlock()
+	return nil
+}
+
+// backtest runs one sweep backtest on [start, end] with params applied
+func (o *Optimizer) backtest(ctx context.Context, params map[string]float64, start, end int64, label string) (*Metrics, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	strategy, err := ApplyParams(&o.req.Strategy, params)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	runNumber := o.report.RunsDone + 1
+	o.mu.Unlock()
+
+	cfg := o.req.Base
+	cfg.RunID = fmt.Sprintf("%s_%s_%d", o.report.ID, label, runNumber)
+	cfg.StartTS = start
+	cfg.EndTS = end
+	cfg.Strategy = strategy
+	cfg.Symbols = append([]string(nil), o.req.Base.Symbols...)
+
+	m, err := o.run(ctx, cfg)
+	o.mu.Lock()
+	o.report.RunsDone++
+	o.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("backtest %s (%s): %w", cfg.RunID, paramsKey(params), err)
+	}
+	return m, nil
+}
+
+// summarizeOutOfSample averages out-of-sample metrics over folds
+func summarizeOutOfSample(byFold map[int]*Metrics, objective string) *OutOfSampleSummary {
+	summary := &OutOfSampleSummary{}
+	if len(byFold) == 0 {
+		summary.Score = liquidatedScore
+		return summary
+	}
+	n := float64(len(byFold))
+	for _, m := range byFold {
+		summary.Score += objectiveScore(m, objective) / n
+		if m == nil {
+			continue
+		}
+		summary.TotalReturnPct += m.TotalReturnPct / n
+		summary.SharpeRatio += m.SharpeRatio / n
+		summary.WinRate += m.WinRate / n
+		summary.Trades += m.Trades
+		if m.MaxDrawdownPct > summary.MaxDrawdownPct {
+			summary.MaxDrawdownPct = m.MaxDrawdownPct
+		}
+	}
+	return summary
+}
@@ -0,0 +1,100 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+
+	"SynapseStrike/store"
+)
+
+func TestBuildParamGrid(t *testing.T) {
+	grid, err := buildParamGrid([]ParamRange{
+		{Name: "ema_fast", Min: 10, Max: 20, Step: 5},
+		{Name: "min_risk_reward_ratio", Min: 2, Max: 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(grid) != 6 {
+		t.Fatalf("expected 3×2 combinations, got %d", len(grid))
+	}
+
+	if _, err := buildParamGrid([]ParamRange{{Name: "leverage", Min: 1, Max: 5, Step: 1}}); err == nil {
+		t.Error("unknown parameter should be rejected")
+	}
+	if _, err := buildParamGrid([]ParamRange{{Name: "ema_fast", Min: 20, Max: 10, Step: 1}}); err == nil {
+		t.Error("inverted range should be rejected")
+	}
+}
+
+func TestWalkForwardWindows(t *testing.T) {
+	windows := walkForwardWindows(0, 1000, 4, 75)
+	if len(windows) != 4 {
+		t.Fatalf("expected 4 folds, got %d", len(windows))
+	}
+	for i, w := range windows {
+		if w.trainEnd != w.testStart || w.trainStart >= w.trainEnd || w.testStart >= w.testEnd {
+			t.Errorf("fold %d has invalid split: %+v", i, w)
+		}
+		if i > 0 && w.trainStart != windows[i-1].testEnd {
+			t.Errorf("fold %d does not start where fold %d ends", i, i-1)
+		}
+	}
+	if windows[0].trainEnd != 187 || windows[3].testEnd != 1000 {
+		t.Errorf("unexpected fold bounds: %+v", windows)
+	}
+}
+
+func TestOptimizerRun(t *testing.T) {
+	base := store.StrategyConfig{}
+	base.Indicators.EMAPeriods = []int{20, 50}
+
+	// Synthetic objective: EMA 15 is best everywhere
+	run := func(ctx context.Context, cfg BacktestConfig) (*Metrics, error) {
+		fast := cfg.Strategy.Indicators.EMAPeriods[0]
+		diff := float64(fast - 15)
+		return &Metrics{SharpeRatio: 2 - diff*diff/25, TotalReturnPct: 10 - diff}, nil
+	}
+
+	optimizer, err := NewOptimizer("wfo_test", OptimizeRequest{
+		Base:     BacktestConfig{Symbols: []string{"AAPL"}, StartTS: 0, EndTS: 3000},
+		Strategy: base,
+		Params:   []ParamRange{{Name: "ema_fast", Min: 5, Max: 25, Step: 5}},
+	}, run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := optimizer.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.State != OptimizeCompleted {
+		t.Fatalf("expected completed, got %s", report.State)
+	}
+	if len(report.Folds) != 3 {
+		t.Fatalf("expected 3 folds, got %d", len(report.Folds))
+	}
+	if report.BestParams["ema_fast"] != 15 {
+		t.Errorf("expected best ema_fast 15, got %v", report.BestParams)
+	}
+	if report.BestStrategy.Indicators.EMAPeriods[0] != 15 || base.Indicators.EMAPeriods[0] != 20 {
+		t.Error("best strategy should be a modified copy of the base strategy")
+	}
+	// 5 combinations × 3 folds + 3 test runs (one winner, tested once per fold)
+	if report.RunsDone != 18 {
+		t.Errorf("expected 18 runs, got %d", report.RunsDone)
+	}
+}
+
+func TestNewOptimizer_RunBudget(t *testing.T) {
+	run := func(ctx context.Context, cfg BacktestConfig) (*Metrics, error) { return &Metrics{}, nil }
+	_, err := NewOptimizer("wfo_test", OptimizeRequest{
+		Base:    BacktestConfig{StartTS: 0, EndTS: 1000},
+		Params:  []ParamRange{{Name: "ema_fast", Min: 5, Max: 50, Step: 1}},
+		MaxRuns: 50,
+	}, run)
+	if err == nil {
+		t.Error("sweep exceeding max_runs should be rejected")
+	}
+}
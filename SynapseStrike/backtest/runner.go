@@ -74,6 +74,7 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 	if err != nil {
 		return nil, err
 	}
+	client.SetGenerationParams(cfg.AICfg.GenerationParams())
 
 	feed, err := NewDataFeed(cfg)
 	if err != nil {
@@ -566,10 +567,12 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 	symbol := dec.Symbol
 	usedLeverage := r.resolveLeverage(dec.Leverage, symbol)
 	actionRecord := store.DecisionAction{
-		Action:    dec.Action,
-		Symbol:    symbol,
-		Leverage:  usedLeverage,
-		Timestamp: time.UnixMilli(ts).UTC(),
+		Action:        dec.Action,
+		Symbol:        symbol,
+		Leverage:      usedLeverage,
+		Timestamp:     time.UnixMilli(ts).UTC(),
+		SchemaVersion: dec.SchemaVersion,
+		Metadata:      dec.Metadata,
 	}
 
 	basePrice := priceMap[symbol]
@@ -697,6 +700,9 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
+	case "update_stops":
+		// Backtest positions carry no SL/TP orders, the update is recorded only
+		return actionRecord, nil, "stop update not simulated", nil
 	case "hold", "wait":
 		return actionRecord, nil, fmt.Sprintf("hold position: %s", dec.Action), nil
 	default:
@@ -1324,10 +1330,12 @@ func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision
 		switch action {
 		case "close_long", "close_short":
 			return 1
-		case "open_long", "open_short":
+		case "update_stops":
 			return 2
-		case "hold", "wait":
+		case "open_long", "open_short":
 			return 3
+		case "hold", "wait":
+			return 4
 		default:
 			return 99
 		}
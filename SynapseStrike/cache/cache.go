@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"SynapseStrike/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Shared Data Cache
+// ============================================================================
+// Short-lived market data (funding rates, AI100 sell triggers, OI snapshots,
+// quant data) is cached here instead of in per-package maps. By default the
+// cache lives in process memory; with REDIS_URL set it is kept in Redis so a
+// restart starts warm and several processes (traders, backtests, debate
+// workers) share one copy. When Redis is unreachable the cache falls back to
+// memory and retries Redis after a short pause, so a Redis outage only costs
+// extra API calls.
+
+// keyPrefix namespace of all keys (several apps may share one Redis)
+const keyPrefix = "synapsestrike:"
+
+// Store key/value cache with per-entry TTL
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+var (
+	sharedMu sync.RWMutex
+	shared   Store = NewMemoryStore()
+)
+
+// Init selects the shared cache backend ("" = in-memory, otherwise redis://[[user]:password@]host:port[/db])
+// An unreachable Redis is not fatal: the store is installed with its memory fallback and the error returned for logging.
+func Init(redisURL string) error {
+	redisURL = strings.TrimSpace(redisURL)
+	if redisURL == "" {
+		setShared(NewMemoryStore())
+		return nil
+	}
+	store, err := NewRedisStore(redisURL)
+	if err != nil {
+		return err
+	}
+	setShared(store)
+	return store.Ping()
+}
+
+// Shared the shared cache backend
+func Shared() Store {
+	sharedMu.RLock()
+	defer sharedMu.RUnlock()
+	return shared
+}
+
+func setShared(store Store) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if closer, ok := shared.(io.Closer); ok {
+		closer.Close()
+	}
+	shared = store
+}
+
+// GetJSON decodes a cached value into dst, false on miss or undecodable value
+func GetJSON(key string, dst interface{}) bool {
+	data, ok := Shared().Get(keyPrefix + key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		logger.Warnf("⚠️ Cache entry %s undecodable, ignoring: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// SetJSON caches the JSON encoding of v for ttl
+func SetJSON(key string, v interface{}, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Warnf("⚠️ Cache entry %s not encodable: %v", key, err)
+		return
+	}
+	Shared().Set(keyPrefix+key, data, ttl)
+}
+
+// Delete removes a cached value
+func Delete(key string) {
+	Shared().Delete(keyPrefix + key)
+}
+
+// HashKey short digest for key parts that are long or carry credentials (e.g. request URLs with auth keys)
+func HashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ============================================================================
+// In-memory store
+// ============================================================================
+
+const memoryPruneInterval = time.Minute
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore process-local store (default backend and Redis fallback)
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastPrune time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), lastPrune: time.Now()}
+}
+
+// Get returns an unexpired value
+func (m *MemoryStore) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores a value for ttl (expired entries are pruned periodically)
+func (m *MemoryStore) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	if now.Sub(m.lastPrune) < memoryPruneInterval {
+		return
+	}
+	m.lastPrune = now
+	for k, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+// Delete removes a value
+func (m *MemoryStore) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis minimal RESP server supporting AUTH/PING/GET/SET/DEL
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	conns    []net.Conn
+	user     string // Required AUTH credentials, none if empty
+	password string
+	auths    [][]string
+}
+
+func startFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	f := &fakeRedis{listener: l, data: make(map[string]string)}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.conns = append(f.conns, conn)
+			f.mu.Unlock()
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+// stop closes the listener and drops every client connection
+func (f *fakeRedis) stop() {
+	f.listener.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	f.mu.Lock()
+	authed := f.password == ""
+	f.mu.Unlock()
+	for {
+		header, err := rd.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+		args := make([]string, n)
+		for i := range args {
+			sizeLine, _ := rd.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(sizeLine[1:]))
+			buf := make([]byte, size+2)
+			io.ReadFull(rd, buf)
+			args[i] = string(buf[:size])
+		}
+
+		f.mu.Lock()
+		command := strings.ToUpper(args[0])
+		switch {
+		case command == "AUTH":
+			f.auths = append(f.auths, args[1:])
+			authed = len(args) == 3 && args[1] == f.user && args[2] == f.password
+			if authed {
+				io.WriteString(conn, "+OK\r\n")
+			} else {
+				io.WriteString(conn, "-WRONGPASS invalid username-password pair\r\n")
+			}
+		case !authed:
+			io.WriteString(conn, "-NOAUTH Authentication required\r\n")
+		case command == "PING":
+			io.WriteString(conn, "+PONG\r\n")
+		case command == "SET":
+			f.data[args[1]] = args[2]
+			io.WriteString(conn, "+OK\r\n")
+		case command == "GET":
+			if v, ok := f.data[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case command == "DEL":
+			delete(f.data, args[1])
+			io.WriteString(conn, ":1\r\n")
+		default:
+			io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestMemoryStoreTTL(t *testing.T) {
+	m := NewMemoryStore()
+	m.Set("a", []byte("1"), time.Hour)
+	m.Set("b", []byte("2"), -time.Second)
+	if v, ok := m.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("a = %q, %v", v, ok)
+	}
+	if _, ok := m.Get("b"); ok {
+		t.Error("expired entry should miss")
+	}
+}
+
+func TestRedisStoreSharedAndFallback(t *testing.T) {
+	server := startFakeRedis(t)
+	t.Cleanup(func() { Init("") })
+	if err := Init("redis://" + server.listener.Addr().String()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	type rates struct{ Rate float64 }
+	SetJSON("funding:BTCUSDT", rates{Rate: 0.0001}, time.Minute)
+	server.mu.Lock()
+	stored := server.data[keyPrefix+"funding:BTCUSDT"]
+	server.mu.Unlock()
+	if stored != `{"Rate":0.0001}` {
+		t.Fatalf("redis value = %q", stored)
+	}
+
+	// A second process sees the value written by the first
+	other, _ := NewRedisStore("redis://" + server.listener.Addr().String())
+	defer other.Close()
+	if v, ok := other.Get(keyPrefix + "funding:BTCUSDT"); !ok || string(v) != stored {
+		t.Errorf("second store read %q, %v", v, ok)
+	}
+
+	// Redis down: served from the memory fallback
+	server.stop()
+	var got rates
+	if !GetJSON("funding:BTCUSDT", &got) || got.Rate != 0.0001 {
+		t.Errorf("fallback read %+v", got)
+	}
+	if !Shared().(*RedisStore).down() {
+		t.Error("store should back off after the connection failure")
+	}
+}
+
+func TestInitRejectsBadURL(t *testing.T) {
+	t.Cleanup(func() { Init("") })
+	if err := Init("http://localhost:6379"); err == nil {
+		t.Error("non-redis scheme should be rejected")
+	}
+}
+
+func TestRedisStoreACLAuth(t *testing.T) {
+	server := startFakeRedis(t)
+	server.mu.Lock()
+	server.user, server.password = "cache", "s3cret"
+	server.mu.Unlock()
+	addr := server.listener.Addr().String()
+
+	store, err := NewRedisStore("redis://cache:s3cret@" + addr + "/0")
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	defer store.Close()
+	if err := store.Ping(); err != nil {
+		t.Fatalf("Ping with ACL user: %v", err)
+	}
+	server.mu.Lock()
+	auths := server.auths
+	server.mu.Unlock()
+	if len(auths) == 0 || len(auths[0]) != 2 || auths[0][0] != "cache" || auths[0][1] != "s3cret" {
+		t.Errorf("AUTH arguments = %v, want [cache s3cret]", auths)
+	}
+
+	// A bare userinfo is the legacy password-only form
+	legacy, err := NewRedisStore("redis://s3cret@" + addr)
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	defer legacy.Close()
+	opts := legacy.client.Options()
+	if opts.Username != "" || opts.Password != "s3cret" {
+		t.Errorf("legacy URL user %q password %q, want password only", opts.Username, opts.Password)
+	}
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"SynapseStrike/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisDialTimeout  = 2 * time.Second
+	redisOpTimeout    = 2 * time.Second
+	redisPoolSize     = 10
+	redisRetryBackoff = 30 * time.Second // Memory-only period after a Redis failure
+)
+
+// RedisStore Redis-backed store with in-memory fallback
+type RedisStore struct {
+	client *redis.Client
+	addr   string
+
+	mu        sync.Mutex
+	downUntil time.Time // Redis skipped until then after a failure
+
+	fallback *MemoryStore
+}
+
+// NewRedisStore creates a store for redis://[[user]:password@]host:port[/db] (rediss:// for TLS)
+// Connections are pooled and opened lazily on first use.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid REDIS_URL scheme %q (expected redis:// or rediss://)", u.Scheme)
+	}
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	// redis://secret@host (no colon) has always meant a password, not an ACL user
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); !hasPassword {
+			opts.Username, opts.Password = "", u.User.Username()
+		}
+	}
+	opts.DialTimeout = redisDialTimeout
+	opts.ReadTimeout = redisOpTimeout
+	opts.WriteTimeout = redisOpTimeout
+	opts.PoolSize = redisPoolSize
+	opts.MaxRetries = -1 // A failure switches to the memory fallback instead
+	opts.Protocol = 2
+	opts.DisableIdentity = true
+	return &RedisStore{client: redis.NewClient(opts), addr: opts.Addr, fallback: NewMemoryStore()}, nil
+}
+
+// Ping checks the Redis connection
+func (r *RedisStore) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	return r.check(r.client.Ping(ctx).Err())
+}
+
+// Get reads from Redis, from the memory fallback while Redis is down
+func (r *RedisStore) Get(key string) ([]byte, bool) {
+	if r.down() {
+		return r.fallback.Get(key)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false
+	}
+	if r.check(err) != nil {
+		return r.fallback.Get(key)
+	}
+	return value, true
+}
+
+// Set writes to Redis and the memory fallback
+func (r *RedisStore) Set(key string, value []byte, ttl time.Duration) {
+	r.fallback.Set(key, value, ttl)
+	if r.down() {
+		return
+	}
+	if ttl < time.Millisecond {
+		ttl = time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	r.check(r.client.Set(ctx, key, value, ttl).Err())
+}
+
+// Delete removes from Redis and the memory fallback
+func (r *RedisStore) Delete(key string) {
+	r.fallback.Delete(key)
+	if r.down() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+	r.check(r.client.Del(ctx, key).Err())
+}
+
+// Close closes the connection pool
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
+
+// down whether Redis is in its post-failure backoff
+func (r *RedisStore) down() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Now().Before(r.downUntil)
+}
+
+// check starts the fallback backoff on connection errors; error replies keep Redis in use
+func (r *RedisStore) check(err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var replyErr redis.Error
+	switch {
+	case err == nil:
+		if !r.downUntil.IsZero() {
+			logger.Infof("✓ Redis cache %s reconnected", r.addr)
+			r.downUntil = time.Time{}
+		}
+	case errors.Is(err, redis.Nil), errors.As(err, &replyErr):
+	default:
+		if r.downUntil.IsZero() {
+			logger.Warnf("⚠️ Redis cache %s unavailable, using in-memory cache: %v", r.addr, err)
+		}
+		r.downUntil = time.Now().Add(redisRetryBackoff)
+	}
+	return err
+}
@@ -22,6 +22,38 @@ type Config struct {
 	// TransportEncryption enables browser-side encryption for API keys
 	// Requires HTTPS or localhost. Set to false for HTTP access via IP.
 	TransportEncryption bool
+
+	// Notification configuration (empty = channel disabled)
+	TelegramBotToken  string
+	TelegramChatID    string
+	DiscordWebhookURL string
+	NotifyWebhookURL  string
+	NotifyEvents      string // Comma-separated event list, empty or "all" = all events
+
+	// AI latency budget
+	// Per-request timeout and retry/backoff are read by the mcp package
+	// (AI_REQUEST_TIMEOUT_SECONDS, AI_MAX_RETRIES, AI_RETRY_WAIT_BASE_SECONDS, AI_RETRY_WAIT_MAX_SECONDS)
+	AICycleTimeoutSeconds int // Total AI time per trading cycle (0 = 80% of scan interval)
+
+	// Local kline archive (fallback for live market data, "archive" backtest data source)
+	KlineArchivePath string // SQLite archive path (empty = disabled, default data/klines.db)
+
+	// US market holidays/half days of years after the built-in table (JSON, missing file = built-in only)
+	MarketCalendarPath string // default data/market_calendar.json
+
+	// Executables strategies may run as context hook scripts (strategies name scripts, never paths)
+	ContextHooksDir string // default data/context_hooks
+
+	// Shared market data cache (funding rates, AI100 sell triggers, OI snapshots, quant data)
+	RedisURL string // redis://[[user]:password@]host:port[/db] (empty = in-memory cache per process)
+
+	// Quote currency reference rates (balances of USDC/EUR accounts are reported in USD)
+	QuoteRates              string // Static rates, e.g. "EUR=1.08,USDC=1" (override the live feed)
+	QuoteRateRefreshMinutes int    // Live rate cache duration (0 = static rates only, default 15)
+
+	// Exchange maintenance windows (cycles are deferred or run monitoring-only during venue downtime)
+	MaintenanceWindowsPath string // Static windows (JSON, missing file = none), default data/maintenance_windows.json
+	MaintenancePollMinutes int    // Bybit/OKX status page cache duration (0 = static windows only, default 10)
 }
 
 // Init initializes global configuration (from .env)
@@ -62,6 +94,55 @@ func Init() {
 		cfg.TransportEncryption = strings.ToLower(v) == "true"
 	}
 
+	// Notification channels
+	cfg.TelegramBotToken = strings.TrimSpace(os.Getenv("TELEGRAM_BOT_TOKEN"))
+	cfg.TelegramChatID = strings.TrimSpace(os.Getenv("TELEGRAM_CHAT_ID"))
+	cfg.DiscordWebhookURL = strings.TrimSpace(os.Getenv("DISCORD_WEBHOOK_URL"))
+	cfg.NotifyWebhookURL = strings.TrimSpace(os.Getenv("NOTIFY_WEBHOOK_URL"))
+	cfg.NotifyEvents = strings.TrimSpace(os.Getenv("NOTIFY_EVENTS"))
+
+	cfg.KlineArchivePath = "data/klines.db"
+	if v, ok := os.LookupEnv("KLINE_ARCHIVE_PATH"); ok {
+		cfg.KlineArchivePath = strings.TrimSpace(v)
+	}
+
+	cfg.MarketCalendarPath = "data/market_calendar.json"
+	if v := strings.TrimSpace(os.Getenv("MARKET_CALENDAR_PATH")); v != "" {
+		cfg.MarketCalendarPath = v
+	}
+
+	cfg.ContextHooksDir = "data/context_hooks"
+	if v := strings.TrimSpace(os.Getenv("CONTEXT_HOOKS_DIR")); v != "" {
+		cfg.ContextHooksDir = v
+	}
+
+	cfg.RedisURL = strings.TrimSpace(os.Getenv("REDIS_URL"))
+
+	cfg.QuoteRates = strings.TrimSpace(os.Getenv("QUOTE_RATES"))
+	cfg.QuoteRateRefreshMinutes = 15
+	if v := os.Getenv("QUOTE_RATE_REFRESH_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes >= 0 {
+			cfg.QuoteRateRefreshMinutes = minutes
+		}
+	}
+
+	cfg.MaintenanceWindowsPath = "data/maintenance_windows.json"
+	if v := strings.TrimSpace(os.Getenv("MAINTENANCE_WINDOWS_PATH")); v != "" {
+		cfg.MaintenanceWindowsPath = v
+	}
+	cfg.MaintenancePollMinutes = 10
+	if v := os.Getenv("MAINTENANCE_POLL_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes >= 0 {
+			cfg.MaintenancePollMinutes = minutes
+		}
+	}
+
+	if v := os.Getenv("AI_CYCLE_TIMEOUT_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			cfg.AICycleTimeoutSeconds = seconds
+		}
+	}
+
 	global = cfg
 }
 
@@ -25,8 +25,10 @@ const (
 
 // Environment variable names
 const (
-	EnvDataEncryptionKey = "DATA_ENCRYPTION_KEY" // AES data encryption key (Base64)
-	EnvRSAPrivateKey     = "RSA_PRIVATE_KEY"     // RSA private key (PEM format, use \n for newlines)
+	EnvDataEncryptionKey          = "DATA_ENCRYPTION_KEY"          // AES data encryption key (Base64)
+	EnvDataEncryptionKeyFile      = "DATA_ENCRYPTION_KEY_FILE"     // File holding the data key (KMS agent / secret manager mount), used when DATA_ENCRYPTION_KEY is unset
+	EnvPreviousDataEncryptionKeys = "DATA_ENCRYPTION_KEY_PREVIOUS" // Comma-separated retired data keys, still accepted for decryption during key rotation
+	EnvRSAPrivateKey              = "RSA_PRIVATE_KEY"              // RSA private key (PEM format, use \n for newlines)
 )
 
 type EncryptedPayload struct {
@@ -46,9 +48,10 @@ type AADData struct {
 }
 
 type CryptoService struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-	dataKey    []byte
+	privateKey   *rsa.PrivateKey
+	publicKey    *rsa.PublicKey
+	dataKey      []byte
+	previousKeys [][]byte // Retired data keys, tried when the current key fails to decrypt
 }
 
 // NewCryptoService creates crypto service (loads keys from environment variables)
@@ -66,12 +69,23 @@ func NewCryptoService() (*CryptoService, error) {
 	}
 
 	return &CryptoService{
-		privateKey: privateKey,
-		publicKey:  &privateKey.PublicKey,
-		dataKey:    dataKey,
+		privateKey:   privateKey,
+		publicKey:    &privateKey.PublicKey,
+		dataKey:      dataKey,
+		previousKeys: loadPreviousDataKeysFromEnv(),
 	}, nil
 }
 
+// NewStorageCryptoService creates crypto service for storage encryption only (no RSA key required)
+// Used by offline tooling such as the key rotation command.
+func NewStorageCryptoService() (*CryptoService, error) {
+	dataKey, err := loadDataKeyFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data encryption key: %w", err)
+	}
+	return &CryptoService{dataKey: dataKey, previousKeys: loadPreviousDataKeysFromEnv()}, nil
+}
+
 // loadRSAPrivateKeyFromEnv loads RSA private key from environment variable
 func loadRSAPrivateKeyFromEnv() (*rsa.PrivateKey, error) {
 	keyPEM := os.Getenv(EnvRSAPrivateKey)
@@ -85,23 +99,47 @@ func loadRSAPrivateKeyFromEnv() (*rsa.PrivateKey, error) {
 	return ParseRSAPrivateKeyFromPEM([]byte(keyPEM))
 }
 
-// loadDataKeyFromEnv loads AES data encryption key from environment variable
+// loadDataKeyFromEnv loads AES data encryption key from environment variable (or the key file)
 func loadDataKeyFromEnv() ([]byte, error) {
 	keyStr := strings.TrimSpace(os.Getenv(EnvDataEncryptionKey))
+	if keyStr == "" {
+		if path := strings.TrimSpace(os.Getenv(EnvDataEncryptionKeyFile)); path != "" {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", EnvDataEncryptionKeyFile, err)
+			}
+			keyStr = strings.TrimSpace(string(content))
+		}
+	}
 	if keyStr == "" {
 		return nil, fmt.Errorf("environment variable %s not set, please configure data encryption key in .env", EnvDataEncryptionKey)
 	}
+	return parseDataKey(keyStr), nil
+}
 
+// loadPreviousDataKeysFromEnv loads retired AES data keys (empty if none)
+func loadPreviousDataKeysFromEnv() [][]byte {
+	var keys [][]byte
+	for _, keyStr := range strings.Split(os.Getenv(EnvPreviousDataEncryptionKeys), ",") {
+		if keyStr = strings.TrimSpace(keyStr); keyStr != "" {
+			keys = append(keys, parseDataKey(keyStr))
+		}
+	}
+	return keys
+}
+
+// parseDataKey decodes a configured data key (Base64/hex), other strings are hashed into a key
+func parseDataKey(keyStr string) []byte {
 	// Try to decode
 	if key, ok := decodePossibleKey(keyStr); ok {
-		return key, nil
+		return key
 	}
 
 	// If decoding fails, use SHA256 hash as key
 	sum := sha256.Sum256([]byte(keyStr))
 	key := make([]byte, len(sum))
 	copy(key, sum[:])
-	return key, nil
+	return key
 }
 
 // ParseRSAPrivateKeyFromPEM parses RSA private key from PEM format
@@ -191,6 +229,14 @@ func (cs *CryptoService) EncryptForStorage(plaintext string, aadParts ...string)
 	if isEncryptedStorageValue(plaintext) {
 		return plaintext, nil
 	}
+	return cs.sealForStorage(plaintext, aadParts)
+}
+
+// sealForStorage encrypts with the current data key in storage format
+func (cs *CryptoService) sealForStorage(plaintext string, aadParts []string) (string, error) {
+	if !cs.HasDataKey() {
+		return "", errors.New("data encryption key not configured")
+	}
 
 	block, err := aes.NewCipher(cs.dataKey)
 	if err != nil {
@@ -243,33 +289,63 @@ func (cs *CryptoService) DecryptFromStorage(value string, aadParts ...string) (s
 		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
-	block, err := aes.NewCipher(cs.dataKey)
+	aad := composeAAD(aadParts)
+	plaintext, err := openWithKey(cs.dataKey, nonce, ciphertext, aad)
 	if err != nil {
-		return "", err
+		// Encrypted before a key rotation: try the retired keys
+		for _, key := range cs.previousKeys {
+			if previous, prevErr := openWithKey(key, nonce, ciphertext, aad); prevErr == nil {
+				return string(previous), nil
+			}
+		}
+		return "", fmt.Errorf("decryption failed: %w", err)
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	return string(plaintext), nil
+}
+
+// ReencryptForStorage re-encrypts a stored value with the current data key
+// Values encrypted with a retired key are decrypted and sealed again; plain-text values are encrypted.
+func (cs *CryptoService) ReencryptForStorage(value string, aadParts ...string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	plaintext, err := cs.DecryptFromStorage(value, aadParts...)
 	if err != nil {
 		return "", err
 	}
+	// EncryptForStorage keeps values that look encrypted, seal the plaintext directly
+	return cs.sealForStorage(plaintext, aadParts)
+}
 
-	if len(nonce) != gcm.NonceSize() {
-		return "", fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
+func openWithKey(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
 	}
 
-	aad := composeAAD(aadParts)
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("decryption failed: %w", err)
+		return nil, err
 	}
 
-	return string(plaintext), nil
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
+	}
+
+	return gcm.Open(nil, nonce, ciphertext, aad)
 }
 
 func (cs *CryptoService) IsEncryptedStorageValue(value string) bool {
 	return isEncryptedStorageValue(value)
 }
 
+// IsEncryptedStorageValue whether a value is in encrypted storage format
+// A credential still in this format after loading could not be decrypted (wrong or missing key).
+func IsEncryptedStorageValue(value string) bool {
+	return isEncryptedStorageValue(value)
+}
+
 func composeAAD(parts []string) []byte {
 	if len(parts) == 0 {
 		return nil
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// newTestDataKey generates a random AES data key
+func newTestDataKey(t *testing.T) []byte {
+	t.Helper()
+	encoded, err := GenerateDataKey()
+	if err != nil {
+		t.Fatalf("GenerateDataKey: %v", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode data key: %v", err)
+	}
+	return key
+}
+
+func TestReencryptForStorage(t *testing.T) {
+	oldKey, newKey, otherKey := newTestDataKey(t), newTestDataKey(t), newTestDataKey(t)
+	before := &CryptoService{dataKey: oldKey}
+	rotating := &CryptoService{dataKey: newKey, previousKeys: [][]byte{otherKey, oldKey}}
+	after := &CryptoService{dataKey: newKey}
+
+	sealed, err := before.EncryptForStorage("secret", "exchanges", "api_key")
+	if err != nil {
+		t.Fatalf("EncryptForStorage: %v", err)
+	}
+	if _, err := after.DecryptFromStorage(sealed, "exchanges", "api_key"); err == nil {
+		t.Fatal("value sealed with the old key should not open with the new key alone")
+	}
+
+	rotated, err := rotating.ReencryptForStorage(sealed, "exchanges", "api_key")
+	if err != nil {
+		t.Fatalf("ReencryptForStorage: %v", err)
+	}
+	if rotated == sealed || !IsEncryptedStorageValue(rotated) {
+		t.Fatalf("rotated value = %q, want a new sealed value", rotated)
+	}
+	if plaintext, err := after.DecryptFromStorage(rotated, "exchanges", "api_key"); err != nil || plaintext != "secret" {
+		t.Errorf("rotated value opens with the new key as %q (%v), want secret", plaintext, err)
+	}
+	if _, err := before.DecryptFromStorage(rotated, "exchanges", "api_key"); err == nil {
+		t.Error("rotated value should no longer open with the old key")
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string // Plaintext after rotation
+		wantErr bool
+	}{
+		{"empty", "", "", false},
+		{"plain text is encrypted", "plain-secret", "plain-secret", false},
+		{"current key is resealed", mustSeal(t, after, "current"), "current", false},
+		{"unknown key", mustSeal(t, &CryptoService{dataKey: newTestDataKey(t)}, "lost"), "", true},
+		{"malformed", storagePrefix + "not-base64", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rotating.ReencryptForStorage(tt.value, "exchanges", "api_key")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ReencryptForStorage(%q) should fail", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReencryptForStorage: %v", err)
+			}
+			if tt.value == "" {
+				if got != "" {
+					t.Errorf("empty value rotated to %q", got)
+				}
+				return
+			}
+			if plaintext, err := after.DecryptFromStorage(got, "exchanges", "api_key"); err != nil || plaintext != tt.want {
+				t.Errorf("rotated value opens as %q (%v), want %q", plaintext, err, tt.want)
+			}
+		})
+	}
+
+	// Values are bound to their column: a previous key does not bypass the AAD check
+	if _, err := rotating.ReencryptForStorage(sealed, "ai_models", "api_key"); err == nil {
+		t.Error("ReencryptForStorage should fail when the AAD does not match")
+	}
+}
+
+func mustSeal(t *testing.T, cs *CryptoService, plaintext string) string {
+	t.Helper()
+	sealed, err := cs.EncryptForStorage(plaintext, "exchanges", "api_key")
+	if err != nil {
+		t.Fatalf("EncryptForStorage: %v", err)
+	}
+	return sealed
+}
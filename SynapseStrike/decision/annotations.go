@@ -0,0 +1,94 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Trade Journal Feedback
+// ============================================================================
+// Users annotate closed trades with a verdict label and an optional note
+// ("bad_entry: chased the breakout"). With Annotations.Enabled the trader
+// loads the annotations of the lookback window into ctx.Annotations and the
+// user prompt gets a digest: how often each verdict was given, then the most
+// recent annotated trades with their notes, as coaching context for the AI.
+
+const (
+	defaultAnnotationDigestItems  = 10
+	defaultAnnotationLookbackDays = 30
+)
+
+// AnnotationLookbackDays annotation window of the config (default when unset)
+func AnnotationLookbackDays(cfg store.AnnotationFeedbackConfig) int {
+	if cfg.LookbackDays <= 0 {
+		return defaultAnnotationLookbackDays
+	}
+	return cfg.LookbackDays
+}
+
+// annotationLabelText human-readable annotation labels for the prompt
+var annotationLabelText = map[string]string{
+	store.AnnotationGoodTrade: "good trade",
+	store.AnnotationBadEntry:  "bad entry",
+	store.AnnotationPoorExit:  "correct read, poor exit",
+	store.AnnotationWrongRead: "wrong read",
+	store.AnnotationOversized: "oversized",
+	store.AnnotationOther:     "other",
+}
+
+// formatAnnotationDigest trade journal section of the user prompt (empty without annotations)
+// Annotations are newest first; all of them are counted, the first maxItems are listed.
+func formatAnnotationDigest(annotations []*store.TradeAnnotation, maxItems int) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+	if maxItems <= 0 {
+		maxItems = defaultAnnotationDigestItems
+	}
+
+	counts := make(map[string]int)
+	for _, a := range annotations {
+		counts[a.Label]++
+	}
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if counts[labels[i]] != counts[labels[j]] {
+			return counts[labels[i]] > counts[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf("%s ×%d", annotationLabel(label), counts[label])
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Trader Coaching (human review of your recent closed trades)\n")
+	sb.WriteString(fmt.Sprintf("Verdicts: %s\n", strings.Join(parts, ", ")))
+	for i, a := range annotations {
+		if i >= maxItems {
+			break
+		}
+		line := fmt.Sprintf("- %s %s (%+.2f USD, %s): %s", a.Symbol, a.Side, a.RealizedPnL, a.CloseReason, annotationLabel(a.Label))
+		if a.Note != "" {
+			line += " — " + a.Note
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("Repeat what was marked good, avoid the mistakes flagged above.\n\n")
+	return sb.String()
+}
+
+// annotationLabel prompt text of an annotation label
+func annotationLabel(label string) string {
+	if text, ok := annotationLabelText[label]; ok {
+		return text
+	}
+	return label
+}
@@ -0,0 +1,30 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+)
+
+func TestFormatAnnotationDigest(t *testing.T) {
+	if formatAnnotationDigest(nil, 10) != "" {
+		t.Error("digest without annotations should be empty")
+	}
+
+	annotations := []*store.TradeAnnotation{
+		{Symbol: "NVDA", Side: "long", RealizedPnL: 42, CloseReason: "take_profit", Label: store.AnnotationPoorExit, Note: "sold half the move"},
+		{Symbol: "TSLA", Side: "short", RealizedPnL: -18, CloseReason: "stop_loss", Label: store.AnnotationBadEntry, Note: "chased the breakdown"},
+		{Symbol: "AMD", Side: "long", RealizedPnL: -7, CloseReason: "stop_loss", Label: store.AnnotationBadEntry},
+	}
+	digest := formatAnnotationDigest(annotations, 2)
+
+	if !strings.Contains(digest, "Verdicts: bad entry ×2, correct read, poor exit ×1") {
+		t.Errorf("digest should count verdicts, most frequent first:\n%s", digest)
+	}
+	if !strings.Contains(digest, "NVDA long (+42.00 USD, take_profit): correct read, poor exit — sold half the move") {
+		t.Errorf("digest should list annotated trades with notes:\n%s", digest)
+	}
+	if strings.Contains(digest, "AMD") {
+		t.Errorf("digest should list at most 2 trades:\n%s", digest)
+	}
+}
@@ -0,0 +1,81 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ============================================================================
+// Batch Retry and Failover
+// ============================================================================
+// A failed AI call of a batch is retried up to BatchRetry.MaxRetries times
+// with exponential backoff (BaseDelayMs × 2^attempt, capped at MaxDelayMs)
+// and jitter (the wait is a random 50-100% of that delay, so traders hitting
+// the same provider do not retry in lockstep). When all retries fail and a
+// failover AI model is configured (ctx.FailoverClient, a different provider),
+// the batch is tried once more on that client before it is skipped. Retries
+// stop at the cycle deadline.
+
+const (
+	defaultBatchRetryBaseDelayMs = 1000
+	defaultBatchRetryMaxDelayMs  = 8000
+)
+
+// batchRetryDelay backoff before retry attempt (0-based) with jitter in [0, 1)
+func batchRetryDelay(attempt, baseDelayMs, maxDelayMs int, jitter float64) time.Duration {
+	if baseDelayMs <= 0 {
+		baseDelayMs = defaultBatchRetryBaseDelayMs
+	}
+	if maxDelayMs <= 0 {
+		maxDelayMs = defaultBatchRetryMaxDelayMs
+	}
+	delay := float64(baseDelayMs)
+	for i := 0; i < attempt && delay < float64(maxDelayMs); i++ {
+		delay *= 2
+	}
+	if delay > float64(maxDelayMs) {
+		delay = float64(maxDelayMs)
+	}
+	return time.Duration(delay*(0.5+jitter/2)) * time.Millisecond
+}
+
+// callBatchWithRetry runs call on client, retrying with backoff and failing over to ctx.FailoverClient
+// Returns the response and the client that produced it.
+func (e *StrategyEngine) callBatchWithRetry(ctx *Context, client mcp.AIClient, label string,
+	call func(client mcp.AIClient) (string, error)) (string, mcp.AIClient, error) {
+	cfg := e.config.BatchRetry
+
+	response, err := call(client)
+	for attempt := 0; err != nil && attempt < cfg.MaxRetries; attempt++ {
+		if errors.Is(err, errCycleDeadline) {
+			return "", client, err
+		}
+		delay := batchRetryDelay(attempt, cfg.BaseDelayMs, cfg.MaxDelayMs, rand.Float64())
+		if !ctx.Deadline.IsZero() && time.Now().Add(delay).After(ctx.Deadline) {
+			logger.Warnf("⏱️  [%s] No time left before cycle deadline to retry: %v", label, err)
+			break
+		}
+		logger.Warnf("🔁 [%s] AI call failed: %v — retry %d/%d in %.1fs", label, err, attempt+1, cfg.MaxRetries, delay.Seconds())
+		time.Sleep(delay)
+		response, err = call(client)
+	}
+	if err == nil {
+		return response, client, nil
+	}
+
+	failover := ctx.FailoverClient
+	if failover == nil || failover == client || errors.Is(err, errCycleDeadline) {
+		return "", client, err
+	}
+	logger.Warnf("🔀 [%s] AI call failed on %s: %v — failing over to %s/%s", label, client.GetProvider(), err,
+		failover.GetProvider(), failover.GetModel())
+	response, failoverErr := call(failover)
+	if failoverErr != nil {
+		return "", client, fmt.Errorf("%w (failover %s: %v)", err, failover.GetProvider(), failoverErr)
+	}
+	return response, failover, nil
+}
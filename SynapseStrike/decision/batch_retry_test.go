@@ -0,0 +1,80 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFastRetryEngine fixture engine with millisecond retry delays
+func newFastRetryEngine() *StrategyEngine {
+	engine := newFixtureEngine()
+	engine.GetConfig().BatchRetry.BaseDelayMs = 1
+	engine.GetConfig().BatchRetry.MaxDelayMs = 2
+	return engine
+}
+
+func TestBatchRetryDelay(t *testing.T) {
+	if d := batchRetryDelay(0, 1000, 8000, 0); d != 500*time.Millisecond {
+		t.Errorf("first retry without jitter = %v, want 500ms", d)
+	}
+	if d := batchRetryDelay(2, 1000, 8000, 0.999); d < 3900*time.Millisecond || d > 4000*time.Millisecond {
+		t.Errorf("third retry with full jitter = %v, want ~4s", d)
+	}
+	if d := batchRetryDelay(10, 1000, 8000, 0.999); d > 8*time.Second {
+		t.Errorf("delay %v exceeds cap", d)
+	}
+}
+
+// TestGetFullDecisionWithStrategy_BatchRetry tests that a failed batch is retried before being skipped
+func TestGetFullDecisionWithStrategy_BatchRetry(t *testing.T) {
+	client := &mcp.MockClient{Provider: mcp.ProviderMock}
+	client.AddError(errors.New("status 503"))
+	client.AddError(errors.New("status 503"))
+	client.AddResponse(waitResponse("SYM0", "hold"))
+
+	fd, err := GetFullDecisionWithStrategy(newBatchContext(1), client, newFastRetryEngine(), "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.CallCount() != 3 {
+		t.Errorf("expected 3 AI calls (2 retries), got %d", client.CallCount())
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); got != "SYM0:hold" {
+		t.Errorf("unexpected decisions: %s", got)
+	}
+}
+
+// TestGetFullDecisionWithStrategy_BatchFailover tests that a batch failing all retries is sent to the failover client
+func TestGetFullDecisionWithStrategy_BatchFailover(t *testing.T) {
+	client := &mcp.MockClient{Provider: mcp.ProviderMock}
+	client.AddError(errors.New("status 429"))
+	failover := mcp.NewMockClient(waitResponse("SYM0", "hold"))
+	failover.Provider = "failover"
+
+	ctx := newBatchContext(1)
+	ctx.FailoverClient = failover
+	fd, err := GetFullDecisionWithStrategy(ctx, client, newFastRetryEngine(), "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.CallCount() != 3 || failover.CallCount() != 1 {
+		t.Errorf("expected 3 primary calls and 1 failover call, got %d and %d", client.CallCount(), failover.CallCount())
+	}
+	if !strings.Contains(fd.CoTTrace, "failover") {
+		t.Error("expected failover to be noted in CoT trace")
+	}
+
+	// Failing failover: error mentions both clients
+	client = &mcp.MockClient{Provider: mcp.ProviderMock}
+	client.AddError(errors.New("status 429"))
+	failover = &mcp.MockClient{Provider: "failover"}
+	failover.AddError(errors.New("status 500"))
+	ctx = newBatchContext(1)
+	ctx.FailoverClient = failover
+	if _, err := GetFullDecisionWithStrategy(ctx, client, newFastRetryEngine(), "balanced"); err == nil || !strings.Contains(err.Error(), "failover") {
+		t.Errorf("expected error mentioning failover, got %v", err)
+	}
+}
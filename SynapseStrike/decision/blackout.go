@@ -0,0 +1,234 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// News Blackouts
+// ============================================================================
+// Opens are not allowed shortly before or after scheduled news:
+//   - earnings: within EarningsBlackoutHours of the symbol's release (from
+//     StockExtraData; "bmo" counts as 08:00 ET, "amc" as 16:00 ET, unknown
+//     timing covers the whole session)
+//   - macro releases (MacroBlackoutEnabled): from MacroBlackoutHoursBefore
+//     until MacroBlackoutHoursAfter a US economic calendar event matching one
+//     of MacroBlackoutEvents, for every symbol
+// The older Indicators event filters are honored too: EnableEarningsFilter
+// blacks out EarningsBlackoutDays around earnings when no hours are set, and
+// EnableFOMCFilter blacks out the whole ET day of a Fed rate decision.
+// ComputeBlackouts collects the windows that are active or start within
+// blackoutLookahead, the prompt lists them and validateDecision rejects opens
+// inside an active one. Closes are never blocked.
+
+const blackoutLookahead = 24 * time.Hour
+
+// macroEventAliases calendar names of macro event keywords that don't appear in the event name
+var macroEventAliases = map[string][]string{
+	"FOMC": {"Fed Interest Rate Decision"},
+}
+
+// Blackout time window without new opens
+type Blackout struct {
+	Symbol string    `json:"symbol,omitempty"` // Blacked out symbol ("" = all symbols)
+	Event  string    `json:"event"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Active checks whether t lies inside the window
+func (b Blackout) Active(t time.Time) bool {
+	return !t.Before(b.Start) && !t.After(b.End)
+}
+
+// AppliesTo checks whether the window blocks symbol
+func (b Blackout) AppliesTo(symbol string) bool {
+	return b.Symbol == "" || strings.EqualFold(b.Symbol, symbol)
+}
+
+// newYorkLocation exchange time zone of earnings release times
+func newYorkLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.FixedZone("ET", -5*3600)
+}
+
+// EarningsBlackout blackout window around the earnings release of symbol (nil if date can't be parsed)
+// date is "2006-01-02", timing the FMP release time ("bmo", "amc", anything else = unknown).
+func EarningsBlackout(symbol, date, timing string, hours float64) *Blackout {
+	loc := newYorkLocation()
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil || hours <= 0 {
+		return nil
+	}
+	releaseStart, releaseEnd := day.Add(8*time.Hour), day.Add(16*time.Hour)
+	switch strings.ToLower(timing) {
+	case "bmo":
+		releaseEnd = releaseStart
+	case "amc":
+		releaseStart = releaseEnd
+	}
+	window := time.Duration(hours * float64(time.Hour))
+	return &Blackout{
+		Symbol: symbol,
+		Event:  fmt.Sprintf("earnings %s %s", date, timing),
+		Start:  releaseStart.Add(-window),
+		End:    releaseEnd.Add(window),
+	}
+}
+
+// matchMacroEvent configured keyword matching a calendar event name ("" if none)
+func matchMacroEvent(name string, keywords []string) string {
+	lower := strings.ToLower(name)
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			continue
+		}
+		names := append([]string{keyword}, macroEventAliases[strings.ToUpper(keyword)]...)
+		for _, n := range names {
+			if strings.Contains(lower, strings.ToLower(n)) {
+				return keyword
+			}
+		}
+	}
+	return ""
+}
+
+// MacroBlackouts market-wide blackout windows around US calendar events matching keywords
+// Releases of the same keyword at the same time (e.g. CPI MoM and YoY) share one window.
+func MacroBlackouts(events []market.EconomicEvent, keywords []string, hoursBefore, hoursAfter float64) []Blackout {
+	before := time.Duration(hoursBefore * float64(time.Hour))
+	after := time.Duration(hoursAfter * float64(time.Hour))
+
+	seen := make(map[string]bool)
+	var windows []Blackout
+	for _, event := range events {
+		if !strings.EqualFold(event.Country, "US") {
+			continue
+		}
+		keyword := matchMacroEvent(event.Event, keywords)
+		key := keyword + "|" + event.Time.String()
+		if keyword == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		windows = append(windows, Blackout{
+			Event: fmt.Sprintf("%s (%s)", keyword, event.Event),
+			Start: event.Time.Add(-before),
+			End:   event.Time.Add(after),
+		})
+	}
+	return windows
+}
+
+// FOMCDayBlackouts market-wide blackouts of the whole ET day of each Fed rate decision
+func FOMCDayBlackouts(events []market.EconomicEvent) []Blackout {
+	loc := newYorkLocation()
+	windows := MacroBlackouts(events, []string{"FOMC"}, 0, 0)
+	for i := range windows {
+		release := windows[i].Start.In(loc)
+		windows[i].Start = time.Date(release.Year(), release.Month(), release.Day(), 0, 0, 0, 0, loc)
+		windows[i].End = windows[i].Start.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return windows
+}
+
+// earningsBlackoutHours earnings blackout of the config (RiskControl hours, else the Indicators earnings filter days)
+func earningsBlackoutHours(cfg *store.StrategyConfig) float64 {
+	if cfg.RiskControl.EarningsBlackoutHours > 0 || !cfg.Indicators.EnableEarningsFilter {
+		return cfg.RiskControl.EarningsBlackoutHours
+	}
+	days := cfg.Indicators.EarningsBlackoutDays
+	if days <= 0 {
+		days = 1
+	}
+	return float64(days) * 24
+}
+
+// ComputeBlackouts collects active and upcoming blackout windows into ctx.Blackouts
+func (e *StrategyEngine) ComputeBlackouts(ctx *Context) {
+	if ctx == nil {
+		return
+	}
+	rc := e.config.RiskControl
+	now := time.Now()
+	var windows []Blackout
+
+	if hours := earningsBlackoutHours(e.config); hours > 0 {
+		for symbol, data := range ctx.MarketDataMap {
+			if data == nil || data.StockExtraData == nil || data.StockExtraData.NextEarningsDate == "" {
+				continue
+			}
+			extra := data.StockExtraData
+			if w := EarningsBlackout(symbol, extra.NextEarningsDate, extra.EarningsTime, hours); w != nil {
+				windows = append(windows, *w)
+			}
+		}
+	}
+
+	enabled, keywords, before, after := rc.MacroBlackout()
+	if enabled || e.config.Indicators.EnableFOMCFilter {
+		events, err := market.GetEconomicCalendar()
+		if err != nil {
+			logger.Warnf("⚠️  [Blackout] Failed to fetch economic calendar: %v", err)
+		}
+		if enabled {
+			windows = append(windows, MacroBlackouts(events, keywords, before, after)...)
+		}
+		if e.config.Indicators.EnableFOMCFilter {
+			windows = append(windows, FOMCDayBlackouts(events)...)
+		}
+	}
+
+	ctx.Blackouts = nil
+	for _, w := range windows {
+		if w.End.After(now) && w.Start.Before(now.Add(blackoutLookahead)) {
+			ctx.Blackouts = append(ctx.Blackouts, w)
+		}
+	}
+	sort.Slice(ctx.Blackouts, func(i, j int) bool { return ctx.Blackouts[i].Start.Before(ctx.Blackouts[j].Start) })
+}
+
+// checkBlackout rejects opens inside an active blackout window of the symbol
+func checkBlackout(d *Decision, limits PositionLimits) error {
+	now := time.Now()
+	for _, w := range limits.Blackouts {
+		if w.AppliesTo(d.Symbol) && w.Active(now) {
+			return fmt.Errorf("%s is in a news blackout (%s) until %s UTC, no new opens",
+				d.Symbol, w.Event, w.End.UTC().Format("2006-01-02 15:04"))
+		}
+	}
+	return nil
+}
+
+// formatBlackouts news blackout section of the user prompt (empty if there are none)
+func formatBlackouts(blackouts []Blackout) string {
+	if len(blackouts) == 0 {
+		return ""
+	}
+	now := time.Now()
+	var sb strings.Builder
+	sb.WriteString("News Blackouts (computed, opens inside an active window are rejected):\n")
+	for _, w := range blackouts {
+		scope := w.Symbol
+		if scope == "" {
+			scope = "ALL symbols"
+		}
+		status := "upcoming"
+		if w.Active(now) {
+			status = "⛔ ACTIVE"
+		}
+		sb.WriteString(fmt.Sprintf("- %s %s: %s, %s → %s UTC\n", status, scope, w.Event,
+			w.Start.UTC().Format("01-02 15:04"), w.End.UTC().Format("01-02 15:04")))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEarningsBlackout(t *testing.T) {
+	w := EarningsBlackout("AAPL", "2026-01-29", "amc", 24)
+	if w == nil {
+		t.Fatal("expected earnings blackout window")
+	}
+	release := time.Date(2026, 1, 29, 21, 0, 0, 0, time.UTC) // 16:00 ET
+	if !w.Start.Equal(release.Add(-24*time.Hour)) || !w.End.Equal(release.Add(24*time.Hour)) {
+		t.Errorf("amc window = %v → %v, want 24h around %v", w.Start.UTC(), w.End.UTC(), release)
+	}
+
+	// Unknown timing covers the whole session
+	w = EarningsBlackout("AAPL", "2026-01-29", "--", 2)
+	if w.End.Sub(w.Start) != 12*time.Hour {
+		t.Errorf("unknown timing window = %v, want 8h session + 2×2h", w.End.Sub(w.Start))
+	}
+	if EarningsBlackout("AAPL", "soon", "bmo", 24) != nil {
+		t.Error("unparseable date should not produce a window")
+	}
+}
+
+func TestMacroBlackouts(t *testing.T) {
+	release := time.Date(2026, 3, 18, 18, 0, 0, 0, time.UTC)
+	cpi := time.Date(2026, 3, 11, 12, 30, 0, 0, time.UTC)
+	events := []market.EconomicEvent{
+		{Event: "Fed Interest Rate Decision", Country: "US", Time: release},
+		{Event: "CPI (YoY)", Country: "US", Time: cpi},
+		{Event: "Core CPI (MoM)", Country: "US", Time: cpi},
+		{Event: "CPI (YoY)", Country: "JP", Time: cpi},
+		{Event: "Initial Jobless Claims", Country: "US", Time: cpi},
+	}
+	windows := MacroBlackouts(events, []string{"FOMC", "CPI"}, 2, 1)
+	if len(windows) != 2 {
+		t.Fatalf("windows = %+v, want one FOMC and one CPI window", windows)
+	}
+	if !strings.HasPrefix(windows[0].Event, "FOMC") || !windows[0].Start.Equal(release.Add(-2*time.Hour)) || !windows[0].End.Equal(release.Add(time.Hour)) {
+		t.Errorf("FOMC window = %+v", windows[0])
+	}
+}
+
+func TestFOMCDayBlackouts(t *testing.T) {
+	release := time.Date(2026, 3, 18, 18, 0, 0, 0, time.UTC) // 14:00 ET
+	windows := FOMCDayBlackouts([]market.EconomicEvent{{Event: "Fed Interest Rate Decision", Country: "US", Time: release}})
+	if len(windows) != 1 {
+		t.Fatalf("windows = %+v, want one FOMC day", windows)
+	}
+	dayStart := time.Date(2026, 3, 18, 4, 0, 0, 0, time.UTC) // Midnight ET (EDT)
+	if !windows[0].Start.Equal(dayStart) || !windows[0].Active(release) || windows[0].Active(dayStart.Add(24*time.Hour)) {
+		t.Errorf("FOMC day window = %v → %v", windows[0].Start.UTC(), windows[0].End.UTC())
+	}
+}
+
+func TestCheckBlackout(t *testing.T) {
+	now := time.Now()
+	limits := PositionLimits{Blackouts: []Blackout{
+		{Symbol: "NVDA", Event: "earnings", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+		{Event: "CPI", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+	}}
+	if err := checkBlackout(&Decision{Symbol: "NVDA", Action: "open_long"}, limits); err == nil {
+		t.Error("open inside active earnings blackout should be rejected")
+	}
+	if err := checkBlackout(&Decision{Symbol: "MSFT", Action: "open_long"}, limits); err != nil {
+		t.Errorf("other symbol before the macro window should pass, got %v", err)
+	}
+}
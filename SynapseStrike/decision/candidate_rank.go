@@ -0,0 +1,157 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Candidate Ranking
+// ============================================================================
+// Before batching, candidates are ordered by an opportunity score so the most
+// promising symbols land in the earliest AI batches (the ones that still run
+// when the cycle deadline cuts later batches). Each factor is scaled to 0-1:
+//   - momentum:     |1h change| + |4h change| / 2, full score at candidateMomentumFullPct
+//   - volume surge: current / average volume, full score at 3x
+//   - OI delta:     |OI change %| from OI Top data, full score at candidateOIFullPct
+//   - news recency: latest headline age, full score within 1h, zero after 24h
+// The score is the weighted average of the factors. With MaxCandidates set,
+// low scorers beyond the limit are cut; symbols with an open position are
+// always kept so the AI can manage them.
+
+const (
+	candidateMomentumFullPct = 5.0
+	candidateVolumeFullRatio = 3.0
+	candidateOIFullPct       = 10.0
+	candidateNewsFreshFor    = time.Hour
+	candidateNewsStaleAfter  = 24 * time.Hour
+)
+
+// CandidateScore opportunity score of a candidate (factors 0-1)
+type CandidateScore struct {
+	Symbol      string  `json:"symbol"`
+	Score       float64 `json:"score"`
+	Momentum    float64 `json:"momentum"`
+	VolumeSurge float64 `json:"volume_surge"`
+	OIDelta     float64 `json:"oi_delta"`
+	NewsRecency float64 `json:"news_recency"`
+}
+
+// CandidateRanking result of ranking candidates before batching
+type CandidateRanking struct {
+	Scores map[string]*CandidateScore `json:"scores"`
+	Cut    []string                   `json:"cut,omitempty"` // Low scorers removed (over MaxCandidates)
+}
+
+// RankCandidates orders ctx.CandidateStocks by opportunity score (no-op unless ranking is enabled)
+func (e *StrategyEngine) RankCandidates(ctx *Context) {
+	cfg := e.config.CandidateRanking
+	if !cfg.Enabled || ctx == nil || len(ctx.CandidateStocks) == 0 {
+		return
+	}
+	weights := [4]float64{cfg.MomentumWeight, cfg.VolumeWeight, cfg.OIWeight, cfg.NewsWeight}
+	if weights == [4]float64{} {
+		weights = [4]float64{1, 1, 1, 1}
+	}
+
+	ranking := &CandidateRanking{Scores: make(map[string]*CandidateScore, len(ctx.CandidateStocks))}
+	now := time.Now()
+	for _, stock := range ctx.CandidateStocks {
+		ranking.Scores[stock.Symbol] = scoreCandidate(stock.Symbol, ctx, weights, now)
+	}
+
+	ranked := make([]CandidateStock, len(ctx.CandidateStocks))
+	copy(ranked, ctx.CandidateStocks)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranking.Scores[ranked[i].Symbol].Score > ranking.Scores[ranked[j].Symbol].Score
+	})
+
+	if cfg.MaxCandidates > 0 && len(ranked) > cfg.MaxCandidates {
+		held := make(map[string]bool, len(ctx.Positions))
+		for _, pos := range ctx.Positions {
+			held[pos.Symbol] = true
+		}
+		kept := ranked[:0:0]
+		for _, stock := range ranked {
+			if len(kept) < cfg.MaxCandidates || held[stock.Symbol] {
+				kept = append(kept, stock)
+			} else {
+				ranking.Cut = append(ranking.Cut, stock.Symbol)
+			}
+		}
+		ranked = kept
+	}
+
+	ctx.CandidateStocks = ranked
+	ctx.CandidateRanking = ranking
+
+	top := make([]string, 0, 5)
+	for i := 0; i < len(ranked) && i < 5; i++ {
+		top = append(top, fmt.Sprintf("%s %.2f", ranked[i].Symbol, ranking.Scores[ranked[i].Symbol].Score))
+	}
+	logger.Infof("🏅 [Ranking] %d candidates ranked, top: %s", len(ranked), strings.Join(top, ", "))
+	if len(ranking.Cut) > 0 {
+		logger.Infof("✂️  [Ranking] Cut %d low scorers over limit %d: %s", len(ranking.Cut), cfg.MaxCandidates, strings.Join(ranking.Cut, ", "))
+	}
+}
+
+// scoreCandidate computes opportunity score of symbol from context data
+// weights: momentum, volume surge, OI delta, news recency
+func scoreCandidate(symbol string, ctx *Context, weights [4]float64, now time.Time) *CandidateScore {
+	score := &CandidateScore{Symbol: symbol}
+	if data, ok := ctx.MarketDataMap[symbol]; ok && data != nil {
+		move := math.Abs(data.PriceChange1h) + math.Abs(data.PriceChange4h)/2
+		score.Momentum = math.Min(move/candidateMomentumFullPct, 1)
+
+		if extra := data.StockExtraData; extra != nil {
+			if extra.VolumeRatio > 1 {
+				score.VolumeSurge = math.Min((extra.VolumeRatio-1)/(candidateVolumeFullRatio-1), 1)
+			} else if extra.VolumeSurge {
+				score.VolumeSurge = 0.5
+			}
+			score.NewsRecency = newsRecency(extra.RecentNews, now)
+		}
+	}
+	if oi, ok := ctx.OITopDataMap[symbol]; ok && oi != nil {
+		score.OIDelta = math.Min(math.Abs(oi.OIDeltaPercent)/candidateOIFullPct, 1)
+	}
+
+	factors := [4]float64{score.Momentum, score.VolumeSurge, score.OIDelta, score.NewsRecency}
+	var total, weightSum float64
+	for i, w := range weights {
+		if w > 0 {
+			total += w * factors[i]
+			weightSum += w
+		}
+	}
+	if weightSum > 0 {
+		score.Score = total / weightSum
+	}
+	return score
+}
+
+// newsRecency 1 for news within candidateNewsFreshFor, decaying to 0 at candidateNewsStaleAfter
+func newsRecency(news []market.NewsItem, now time.Time) float64 {
+	best := 0.0
+	for _, item := range news {
+		published, err := time.Parse(time.RFC3339, item.CreatedAt)
+		if err != nil {
+			continue
+		}
+		age := now.Sub(published)
+		var recency float64
+		switch {
+		case age <= candidateNewsFreshFor:
+			recency = 1
+		case age < candidateNewsStaleAfter:
+			recency = 1 - float64(age-candidateNewsFreshFor)/float64(candidateNewsStaleAfter-candidateNewsFreshFor)
+		}
+		best = math.Max(best, recency)
+	}
+	return best
+}
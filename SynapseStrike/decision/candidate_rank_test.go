@@ -0,0 +1,50 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRankCandidates tests that candidates are ordered by score and low scorers cut, keeping held symbols
+func TestRankCandidates(t *testing.T) {
+	engine := newFixtureEngine()
+	engine.config.CandidateRanking.Enabled = true
+	engine.config.CandidateRanking.MaxCandidates = 2
+
+	ctx := newBatchContext(5)
+	ctx.MarketDataMap["SYM1"].PriceChange1h = 4 // momentum
+	ctx.MarketDataMap["SYM3"].StockExtraData = &market.StockExtraData{
+		VolumeRatio: 3,
+		RecentNews:  []market.NewsItem{{Headline: "news", CreatedAt: time.Now().Add(-30 * time.Minute).Format(time.RFC3339)}},
+	}
+	ctx.OITopDataMap["SYM4"] = &OITopData{OIDeltaPercent: -5}
+	ctx.Positions = []PositionInfo{{Symbol: "SYM0", Side: "long"}}
+
+	engine.RankCandidates(ctx)
+
+	var order []string
+	for _, stock := range ctx.CandidateStocks {
+		order = append(order, stock.Symbol)
+	}
+	// SYM3 (volume + news) > SYM1 (momentum) > SYM4 (OI); SYM0 kept as held position
+	if got := strings.Join(order, ","); got != "SYM3,SYM1,SYM0" {
+		t.Fatalf("ranked candidates = %s", got)
+	}
+	if got := strings.Join(ctx.CandidateRanking.Cut, ","); got != "SYM4,SYM2" {
+		t.Errorf("cut candidates = %s", got)
+	}
+	if score := ctx.CandidateRanking.Scores["SYM3"]; score.VolumeSurge != 1 || score.NewsRecency != 1 {
+		t.Errorf("unexpected SYM3 factors: %+v", score)
+	}
+
+	// Disabled ranking keeps list order
+	engine.config.CandidateRanking.Enabled = false
+	ctx = newBatchContext(3)
+	ctx.MarketDataMap["SYM2"].PriceChange1h = 4
+	engine.RankCandidates(ctx)
+	if ctx.CandidateStocks[0].Symbol != "SYM0" || ctx.CandidateRanking != nil {
+		t.Error("expected no ranking when disabled")
+	}
+}
@@ -0,0 +1,176 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Churn Guard (cycle-over-cycle decision comparator)
+// ============================================================================
+// The trader remembers the last decision per symbol and the last position it
+// opened on it. With ChurnGuard.Enabled:
+//   - the user prompt lists "your previous decision and outcome" for every
+//     candidate and held symbol, so the AI sees what it did last cycle
+//   - closing or reversing a position opened within the last LookbackCycles
+//     cycles is a flip; unless price moved MinPriceMovePct or RSI7 moved
+//     MinRSIChange since the open, the flip is flagged in the decision log
+//     (action "flag") or turned into hold/wait (action "suppress")
+
+const (
+	defaultChurnLookbackCycles  = 3
+	defaultChurnMinPriceMovePct = 1.0
+	defaultChurnMinRSIChange    = 15.0
+)
+
+// PreviousDecision last decision on a symbol and what happened since (filled by the trader)
+type PreviousDecision struct {
+	Symbol     string  `json:"symbol"`
+	Action     string  `json:"action"`
+	Cycle      int     `json:"cycle"`      // Cycle number of the decision
+	CyclesAgo  int     `json:"cycles_ago"` // Relative to the current cycle
+	Price      float64 `json:"price"`      // Price when decided
+	Confidence int     `json:"confidence,omitempty"`
+	Executed   bool    `json:"executed"` // Order placed successfully (opens/closes only)
+	Outcome    string  `json:"outcome,omitempty"`
+
+	// Last position opened on the symbol (OpenSide "" = none remembered)
+	OpenSide  string  `json:"open_side,omitempty"` // "long" / "short"
+	OpenCycle int     `json:"open_cycle,omitempty"`
+	OpenPrice float64 `json:"open_price,omitempty"`
+	OpenRSI   float64 `json:"open_rsi,omitempty"` // RSI7 at the open
+}
+
+// ChurnFlag direction flip on a recently opened symbol without a material change
+type ChurnFlag struct {
+	Symbol       string  `json:"symbol"`
+	Action       string  `json:"action"`    // Flip action proposed by the AI
+	OpenSide     string  `json:"open_side"` // Side opened within the lookback
+	CyclesAgo    int     `json:"cycles_ago"`
+	PriceMovePct float64 `json:"price_move_pct"` // Since the open
+	RSIChange    float64 `json:"rsi_change"`     // Since the open
+	Suppressed   bool    `json:"suppressed"`
+}
+
+// String one-line description for the decision log
+func (f ChurnFlag) String() string {
+	verb := "Flagged"
+	if f.Suppressed {
+		verb = "Suppressed"
+	}
+	return fmt.Sprintf("🔁 Churn guard: %s %s %s, %s opened %d cycles ago (price %+.2f%%, RSI %+.1f since open)",
+		verb, f.Action, f.Symbol, f.OpenSide, f.CyclesAgo, f.PriceMovePct, f.RSIChange)
+}
+
+// flipAgainst side of the position a close/reverse action works against ("" = not a flip action)
+func flipAgainst(action string) string {
+	switch action {
+	case "close_long", "open_short":
+		return "long"
+	case "close_short", "open_long":
+		return "short"
+	}
+	return ""
+}
+
+// CheckChurn flags or suppresses flips on symbols opened within the lookback (ChurnGuard.Enabled only)
+// Symbols without market data are left untouched since the change cannot be evaluated.
+func CheckChurn(decisions []Decision, ctx *Context, cfg store.ChurnGuardConfig) ([]Decision, []ChurnFlag) {
+	if !cfg.Enabled || len(ctx.PreviousDecisions) == 0 {
+		return decisions, nil
+	}
+	lookback := cfg.LookbackCycles
+	if lookback <= 0 {
+		lookback = defaultChurnLookbackCycles
+	}
+	minMove := cfg.MinPriceMovePct
+	if minMove <= 0 {
+		minMove = defaultChurnMinPriceMovePct
+	}
+	minRSI := cfg.MinRSIChange
+	if minRSI <= 0 {
+		minRSI = defaultChurnMinRSIChange
+	}
+
+	var flags []ChurnFlag
+	for i := range decisions {
+		d := &decisions[i]
+		prev := ctx.PreviousDecisions[d.Symbol]
+		if prev == nil || prev.OpenSide == "" || flipAgainst(d.Action) != prev.OpenSide {
+			continue
+		}
+		cyclesAgo := ctx.CallCount - prev.OpenCycle
+		if cyclesAgo > lookback || prev.OpenPrice <= 0 {
+			continue
+		}
+		data := ctx.MarketDataMap[d.Symbol]
+		if data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		move := (data.CurrentPrice - prev.OpenPrice) / prev.OpenPrice * 100
+		rsiChange := data.CurrentRSI7 - prev.OpenRSI
+		if math.Abs(move) >= minMove || math.Abs(rsiChange) >= minRSI {
+			continue
+		}
+
+		flag := ChurnFlag{
+			Symbol:       d.Symbol,
+			Action:       d.Action,
+			OpenSide:     prev.OpenSide,
+			CyclesAgo:    cyclesAgo,
+			PriceMovePct: move,
+			RSIChange:    rsiChange,
+			Suppressed:   cfg.Action == store.ChurnActionSuppress,
+		}
+		flags = append(flags, flag)
+		logger.Warnf("🔁 [Churn] %s", flag.String())
+		if flag.Suppressed {
+			d.Reasoning = fmt.Sprintf("[Churn guard suppressed %s: %s opened %d cycles ago, price %+.2f%% / RSI %+.1f since] %s",
+				d.Action, prev.OpenSide, cyclesAgo, move, rsiChange, d.Reasoning)
+			if strings.HasPrefix(d.Action, "close_") {
+				d.Action = "hold"
+			} else {
+				d.Action = "wait"
+			}
+		}
+	}
+	return decisions, flags
+}
+
+// formatPreviousDecisions previous decision section of the user prompt (empty without history)
+func formatPreviousDecisions(ctx *Context) string {
+	if len(ctx.PreviousDecisions) == 0 {
+		return ""
+	}
+	symbols := make([]string, 0, len(ctx.PreviousDecisions))
+	for symbol := range ctx.PreviousDecisions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var sb strings.Builder
+	sb.WriteString("## Your Previous Decisions\n")
+	for _, symbol := range symbols {
+		prev := ctx.PreviousDecisions[symbol]
+		sb.WriteString(fmt.Sprintf("- %s: %s %d cycles ago", symbol, prev.Action, prev.CyclesAgo))
+		if prev.Price > 0 {
+			sb.WriteString(fmt.Sprintf(" @ %.4f", prev.Price))
+			if data := ctx.MarketDataMap[symbol]; data != nil && data.CurrentPrice > 0 {
+				sb.WriteString(fmt.Sprintf(" → now %.4f (%+.2f%%)", data.CurrentPrice, (data.CurrentPrice-prev.Price)/prev.Price*100))
+			}
+		}
+		if prev.Confidence > 0 {
+			sb.WriteString(fmt.Sprintf(" | confidence %d", prev.Confidence))
+		}
+		if prev.Outcome != "" {
+			sb.WriteString(" | " + prev.Outcome)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Reversing a recent decision needs a material change in price or indicators, not a re-read of the same data.\n\n")
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+)
+
+func TestCheckChurn(t *testing.T) {
+	cfg := store.ChurnGuardConfig{Enabled: true, LookbackCycles: 3, MinPriceMovePct: 1, MinRSIChange: 15, Action: store.ChurnActionSuppress}
+	ctx := &Context{
+		CallCount: 10,
+		MarketDataMap: map[string]*market.Data{
+			"NVDA": {Symbol: "NVDA", CurrentPrice: 100.5, CurrentRSI7: 55},
+			"TSLA": {Symbol: "TSLA", CurrentPrice: 97, CurrentRSI7: 40},
+			"AAPL": {Symbol: "AAPL", CurrentPrice: 200, CurrentRSI7: 50},
+		},
+		PreviousDecisions: map[string]*PreviousDecision{
+			"NVDA": {Symbol: "NVDA", Action: "hold", OpenSide: "long", OpenCycle: 8, OpenPrice: 100, OpenRSI: 60},
+			"TSLA": {Symbol: "TSLA", Action: "open_long", OpenSide: "long", OpenCycle: 9, OpenPrice: 100, OpenRSI: 45},   // -3% since open
+			"AAPL": {Symbol: "AAPL", Action: "open_short", OpenSide: "short", OpenCycle: 2, OpenPrice: 200, OpenRSI: 50}, // Outside lookback
+		},
+	}
+	decisions := []Decision{
+		{Symbol: "NVDA", Action: "close_long"},
+		{Symbol: "TSLA", Action: "close_long"},
+		{Symbol: "AAPL", Action: "open_long"},
+	}
+
+	decisions, flags := CheckChurn(decisions, ctx, cfg)
+	if len(flags) != 1 || flags[0].Symbol != "NVDA" || flags[0].CyclesAgo != 2 {
+		t.Fatalf("flags = %+v, want only the NVDA flip", flags)
+	}
+	if decisions[0].Action != "hold" || !strings.Contains(decisions[0].Reasoning, "Churn guard") {
+		t.Errorf("suppressed close should become hold, got %s (%s)", decisions[0].Action, decisions[0].Reasoning)
+	}
+	if decisions[1].Action != "close_long" || decisions[2].Action != "open_long" {
+		t.Errorf("material move and old opens should pass, got %s / %s", decisions[1].Action, decisions[2].Action)
+	}
+
+	cfg.Action = store.ChurnActionFlag
+	flagged, flags := CheckChurn([]Decision{{Symbol: "NVDA", Action: "open_short"}}, ctx, cfg)
+	if len(flags) != 1 || flags[0].Suppressed || flagged[0].Action != "open_short" {
+		t.Errorf("flag mode should keep the reversal, got %s (flags %+v)", flagged[0].Action, flags)
+	}
+}
+
+func TestFormatPreviousDecisions(t *testing.T) {
+	ctx := &Context{
+		MarketDataMap: map[string]*market.Data{"NVDA": {CurrentPrice: 102}},
+		PreviousDecisions: map[string]*PreviousDecision{
+			"NVDA": {Symbol: "NVDA", Action: "open_long", CyclesAgo: 1, Price: 100, Confidence: 80, Outcome: "long position open, unrealized +2.00%"},
+		},
+	}
+	out := formatPreviousDecisions(ctx)
+	for _, want := range []string{"## Your Previous Decisions", "NVDA: open_long 1 cycles ago @ 100.0000 → now 102.0000 (+2.00%)", "confidence 80", "unrealized +2.00%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("prompt section missing %q:\n%s", want, out)
+		}
+	}
+	if formatPreviousDecisions(&Context{}) != "" {
+		t.Error("no history should produce no section")
+	}
+}
@@ -0,0 +1,132 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Strategy Config Dry Run
+// ============================================================================
+// DryRunConfig checks a strategy config before any trader runs it: legacy
+// fields are migrated, the config is validated (store.StrategyConfig.Validate)
+// and, if valid, every live data source it depends on is queried once — the
+// candidate source, klines of all configured timeframes for a few candidates,
+// and the quant data and OI ranking APIs when enabled. Nothing is traded and
+// no AI is called (POST /api/strategies/dry-run).
+
+// dryRunMaxSymbols candidates whose market data is fetched by a dry run
+const dryRunMaxSymbols = 3
+
+// ConfigCheck result of one live data source check
+type ConfigCheck struct {
+	Name   string `json:"name"` // "candidates", "klines", "quant_data", "oi_ranking"
+	Symbol string `json:"symbol,omitempty"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// DryRunReport result of a strategy config dry run
+type DryRunReport struct {
+	Valid      bool                 `json:"valid"`
+	Errors     []*store.ConfigError `json:"errors,omitempty"`
+	Migrations []string             `json:"migrations,omitempty"` // Legacy fields rewritten before validation
+	Checks     []ConfigCheck        `json:"checks"`
+}
+
+// OK whether the config is valid and every live check passed
+func (r *DryRunReport) OK() bool {
+	if !r.Valid {
+		return false
+	}
+	for _, check := range r.Checks {
+		if !check.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// DryRunConfig validates a strategy config and checks it against live data sources
+// validate runs on the migrated config (the trader's full validation); exchange selects the market data source.
+func DryRunConfig(cfg *store.StrategyConfig, exchange string, validate func(*store.StrategyConfig) error) *DryRunReport {
+	report := &DryRunReport{Migrations: cfg.Migrate(), Checks: []ConfigCheck{}}
+	if err := validate(cfg); err != nil {
+		report.Errors = store.AsConfigErrors(err)
+		return report
+	}
+	report.Valid = true
+
+	engine := NewStrategyEngine(cfg)
+	candidates, err := engine.GetCandidateStocks()
+	if err != nil {
+		report.Checks = append(report.Checks, ConfigCheck{Name: "candidates", Detail: err.Error()})
+		return report
+	}
+	if len(candidates) == 0 {
+		report.Checks = append(report.Checks, ConfigCheck{Name: "candidates", Detail: "source returned no candidates"})
+		return report
+	}
+	report.Checks = append(report.Checks, ConfigCheck{Name: "candidates", OK: true,
+		Detail: fmt.Sprintf("%d candidates from %s", len(candidates), cfg.CoinSource.SourceType)})
+
+	timeframes, primaryTimeframe, klineCount := klineTimeframes(cfg)
+	symbols := make([]string, 0, dryRunMaxSymbols)
+	for _, candidate := range candidates {
+		if len(symbols) == dryRunMaxSymbols {
+			break
+		}
+		symbols = append(symbols, candidate.Symbol)
+		report.Checks = append(report.Checks, dryRunKlines(exchange, candidate.Symbol, timeframes, primaryTimeframe, klineCount))
+	}
+
+	if cfg.Indicators.EnableQuantData && cfg.Indicators.QuantDataAPIURL != "" {
+		data, errs := engine.FetchQuantDataBatchWithErrors(symbols)
+		check := ConfigCheck{Name: "quant_data", OK: len(errs) == 0, Detail: fmt.Sprintf("%d/%d symbols", len(data), len(symbols))}
+		for symbol, err := range errs {
+			check.Detail += fmt.Sprintf("; %s: %v", symbol, err)
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	if cfg.Indicators.EnableOIRanking {
+		check := ConfigCheck{Name: "oi_ranking", OK: engine.FetchOIRankingData() != nil, Detail: "ranking fetched"}
+		if !check.OK {
+			check.Detail = "source unavailable (see server log)"
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	return report
+}
+
+// dryRunKlines fetches market data of one symbol for all configured timeframes
+func dryRunKlines(exchange, symbol string, timeframes []string, primaryTimeframe string, klineCount int) ConfigCheck {
+	check := ConfigCheck{Name: "klines", Symbol: symbol}
+	var data *market.Data
+	var err error
+	if market.UsesAlpacaData(exchange, symbol) {
+		data, err = market.GetStockDataWithTimeframes(symbol, timeframes, primaryTimeframe, klineCount)
+	} else {
+		data, err = market.GetWithTimeframes(symbol, timeframes, primaryTimeframe, klineCount)
+	}
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+
+	var gaps []string
+	for _, gap := range data.Gaps {
+		if gap.Feed == market.FeedKlines {
+			gaps = append(gaps, gap.Reason)
+		}
+	}
+	if len(gaps) > 0 {
+		check.Detail = "missing " + strings.Join(gaps, "; ")
+		return check
+	}
+	check.OK = true
+	check.Detail = fmt.Sprintf("%s, primary %s", strings.Join(timeframes, "/"), primaryTimeframe)
+	return check
+}
@@ -0,0 +1,253 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Multi-Timeframe Confluence
+// ============================================================================
+// Each timeframe gets a trend direction from three votes:
+//   - EMA20 slope over the last few bars
+//   - MACD sign
+//   - RSI regime (>55 bullish, <45 bearish)
+// A timeframe is bullish/bearish when at least 2 of 3 votes agree and none
+// oppose. The per-symbol score is the share of timeframes agreeing with the
+// dominant direction.
+
+const (
+	TrendBullish = "bullish"
+	TrendBearish = "bearish"
+	TrendNeutral = "neutral"
+
+	confluenceEMASlopeBars = 3    // Bars used to measure EMA20 slope
+	confluenceRSIBull      = 55.0 // RSI above this votes bullish
+	confluenceRSIBear      = 45.0 // RSI below this votes bearish
+	defaultConfluenceMatch = 2    // Default ConfluenceMinMatch (matches prompt wording)
+)
+
+// TimeframeTrend trend direction of a single timeframe
+type TimeframeTrend struct {
+	Timeframe string  `json:"timeframe"`
+	Direction string  `json:"direction"` // bullish/bearish/neutral
+	EMASlope  float64 `json:"ema_slope"` // EMA20 change (%) over last bars
+	MACD      float64 `json:"macd"`
+	RSI       float64 `json:"rsi"`
+}
+
+// ConfluenceScore multi-timeframe alignment of a symbol
+type ConfluenceScore struct {
+	Symbol     string           `json:"symbol"`
+	Direction  string           `json:"direction"` // Dominant direction across timeframes
+	Score      float64          `json:"score"`     // Share of timeframes agreeing with Direction (0-1)
+	Bullish    int              `json:"bullish"`
+	Bearish    int              `json:"bearish"`
+	Neutral    int              `json:"neutral"`
+	Timeframes []TimeframeTrend `json:"timeframes"`
+}
+
+// Total number of evaluated timeframes
+func (s *ConfluenceScore) Total() int {
+	return len(s.Timeframes)
+}
+
+// Matches number of timeframes aligned with trade side ("long"/"short")
+func (s *ConfluenceScore) Matches(side string) int {
+	switch side {
+	case "long":
+		return s.Bullish
+	case "short":
+		return s.Bearish
+	}
+	return 0
+}
+
+// Summary one-line description for prompts and logs
+func (s *ConfluenceScore) Summary() string {
+	parts := make([]string, 0, len(s.Timeframes))
+	for _, tf := range s.Timeframes {
+		arrow := "→"
+		switch tf.Direction {
+		case TrendBullish:
+			arrow = "▲"
+		case TrendBearish:
+			arrow = "▼"
+		}
+		parts = append(parts, tf.Timeframe+" "+arrow)
+	}
+	return fmt.Sprintf("%s (score %.2f, %d bullish / %d bearish / %d neutral) | %s",
+		s.Direction, s.Score, s.Bullish, s.Bearish, s.Neutral, strings.Join(parts, " "))
+}
+
+// CalculateConfluence computes confluence score from multi-timeframe market data
+// timeframes: timeframes to evaluate (empty = all available); returns nil if no timeframe has data
+func CalculateConfluence(data *market.Data, timeframes []string) *ConfluenceScore {
+	if data == nil || len(data.TimeframeData) == 0 {
+		return nil
+	}
+	if len(timeframes) == 0 {
+		for _, tf := range timeframeDisplayOrder {
+			if _, ok := data.TimeframeData[tf]; ok {
+				timeframes = append(timeframes, tf)
+			}
+		}
+	}
+
+	score := &ConfluenceScore{Symbol: data.Symbol}
+	for _, tf := range timeframes {
+		series, ok := data.TimeframeData[tf]
+		if !ok || series == nil {
+			continue
+		}
+		trend := timeframeTrend(tf, series)
+		switch trend.Direction {
+		case TrendBullish:
+			score.Bullish++
+		case TrendBearish:
+			score.Bearish++
+		default:
+			score.Neutral++
+		}
+		score.Timeframes = append(score.Timeframes, trend)
+	}
+	if score.Total() == 0 {
+		return nil
+	}
+
+	switch {
+	case score.Bullish > score.Bearish && score.Bullish >= score.Neutral:
+		score.Direction = TrendBullish
+		score.Score = float64(score.Bullish) / float64(score.Total())
+	case score.Bearish > score.Bullish && score.Bearish >= score.Neutral:
+		score.Direction = TrendBearish
+		score.Score = float64(score.Bearish) / float64(score.Total())
+	default:
+		score.Direction = TrendNeutral
+		score.Score = 0
+	}
+	return score
+}
+
+// timeframeTrend votes trend direction for one timeframe
+func timeframeTrend(tf string, series *market.TimeframeSeriesData) TimeframeTrend {
+	trend := TimeframeTrend{Timeframe: tf, Direction: TrendNeutral}
+	votes := []int{}
+
+	if n := len(series.EMA20Values); n > confluenceEMASlopeBars {
+		prev := series.EMA20Values[n-1-confluenceEMASlopeBars]
+		if prev != 0 {
+			trend.EMASlope = (series.EMA20Values[n-1] - prev) / prev * 100
+			votes = append(votes, sign(trend.EMASlope))
+		}
+	}
+	if n := len(series.MACDValues); n > 0 {
+		trend.MACD = series.MACDValues[n-1]
+		votes = append(votes, sign(trend.MACD))
+	}
+	rsiValues := series.RSI14Values
+	if len(rsiValues) == 0 {
+		rsiValues = series.RSI7Values
+	}
+	if n := len(rsiValues); n > 0 {
+		trend.RSI = rsiValues[n-1]
+		switch {
+		case trend.RSI > confluenceRSIBull:
+			votes = append(votes, 1)
+		case trend.RSI < confluenceRSIBear:
+			votes = append(votes, -1)
+		default:
+			votes = append(votes, 0)
+		}
+	}
+
+	bull, bear := 0, 0
+	for _, v := range votes {
+		if v > 0 {
+			bull++
+		} else if v < 0 {
+			bear++
+		}
+	}
+	switch {
+	case bull >= 2 && bear == 0:
+		trend.Direction = TrendBullish
+	case bear >= 2 && bull == 0:
+		trend.Direction = TrendBearish
+	}
+	return trend
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// ComputeConfluence fills ctx.ConfluenceMap for all symbols with market data (no-op unless confluence is enabled)
+func (e *StrategyEngine) ComputeConfluence(ctx *Context) {
+	indicators := e.config.Indicators
+	if !indicators.EnableConfluence || ctx == nil {
+		return
+	}
+	ctx.ConfluenceMap = make(map[string]*ConfluenceScore, len(ctx.MarketDataMap))
+	for symbol, data := range ctx.MarketDataMap {
+		if score := CalculateConfluence(data, indicators.ConfluenceTimeframes); score != nil {
+			ctx.ConfluenceMap[symbol] = score
+		}
+	}
+}
+
+// confluenceRequiredMatch minimum aligned timeframes required to open, given number evaluated
+func (e *StrategyEngine) confluenceRequiredMatch(total int) int {
+	indicators := e.config.Indicators
+	if indicators.ConfluenceRequireAll {
+		return total
+	}
+	if indicators.ConfluenceMinMatch > 0 {
+		return indicators.ConfluenceMinMatch
+	}
+	return defaultConfluenceMatch
+}
+
+// enforceConfluence converts opens without enough timeframe alignment to wait (only with ConfluenceBlockOpens)
+// Symbols without confluence data are left untouched since alignment cannot be evaluated.
+func (e *StrategyEngine) enforceConfluence(decisions []Decision, confluence map[string]*ConfluenceScore) []Decision {
+	indicators := e.config.Indicators
+	if !indicators.EnableConfluence || !indicators.ConfluenceBlockOpens {
+		return decisions
+	}
+
+	for i := range decisions {
+		d := &decisions[i]
+		var side string
+		switch d.Action {
+		case "open_long":
+			side = "long"
+		case "open_short":
+			side = "short"
+		default:
+			continue
+		}
+
+		score, ok := confluence[d.Symbol]
+		if !ok {
+			continue
+		}
+		required := e.confluenceRequiredMatch(score.Total())
+		if matches := score.Matches(side); matches < required {
+			logger.Warnf("🛡️  [Confluence] Blocked %s %s: %d/%d timeframes aligned, %d required",
+				d.Action, d.Symbol, matches, score.Total(), required)
+			d.Reasoning = fmt.Sprintf("[Confluence blocked %s: %d/%d timeframes aligned, %d required] %s",
+				d.Action, matches, score.Total(), required, d.Reasoning)
+			d.Action = "wait"
+		}
+	}
+	return decisions
+}
@@ -0,0 +1,82 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"testing"
+)
+
+// trendSeries builds timeframe series with given EMA step, MACD and RSI
+func trendSeries(tf string, emaStep, macd, rsi float64) *market.TimeframeSeriesData {
+	ema := make([]float64, 6)
+	for i := range ema {
+		ema[i] = 100 + float64(i)*emaStep
+	}
+	return &market.TimeframeSeriesData{
+		Timeframe:   tf,
+		EMA20Values: ema,
+		MACDValues:  []float64{macd},
+		RSI14Values: []float64{rsi},
+	}
+}
+
+func confluenceData() *market.Data {
+	return &market.Data{
+		Symbol:       "AAPL",
+		CurrentPrice: 100,
+		TimeframeData: map[string]*market.TimeframeSeriesData{
+			"15m": trendSeries("15m", 0.5, 0.2, 62),  // bullish
+			"1h":  trendSeries("1h", 0.3, 0.1, 50),   // bullish (RSI neutral)
+			"4h":  trendSeries("4h", -0.4, -0.3, 40), // bearish
+		},
+	}
+}
+
+func TestCalculateConfluence(t *testing.T) {
+	score := CalculateConfluence(confluenceData(), []string{"15m", "1h", "4h"})
+	if score == nil {
+		t.Fatal("expected confluence score")
+	}
+	if score.Direction != TrendBullish || score.Bullish != 2 || score.Bearish != 1 {
+		t.Errorf("unexpected score: %+v", score)
+	}
+	if score.Score < 0.66 || score.Score > 0.67 {
+		t.Errorf("expected score 2/3, got %.2f", score.Score)
+	}
+	if score.Matches("long") != 2 || score.Matches("short") != 1 {
+		t.Errorf("unexpected matches: long=%d short=%d", score.Matches("long"), score.Matches("short"))
+	}
+
+	if CalculateConfluence(&market.Data{Symbol: "AAPL"}, nil) != nil {
+		t.Error("expected nil score without timeframe data")
+	}
+}
+
+func TestEnforceConfluence(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.EnableConfluence = true
+	cfg.Indicators.ConfluenceTimeframes = []string{"15m", "1h", "4h"}
+	cfg.Indicators.ConfluenceMinMatch = 2
+	cfg.Indicators.ConfluenceBlockOpens = true
+	engine := NewStrategyEngine(&cfg)
+
+	ctx := &Context{MarketDataMap: map[string]*market.Data{"AAPL": confluenceData()}}
+	engine.ComputeConfluence(ctx)
+
+	decisions := engine.enforceConfluence([]Decision{
+		{Symbol: "AAPL", Action: "open_long"},
+		{Symbol: "AAPL", Action: "open_short"},
+		{Symbol: "TSLA", Action: "open_short"}, // no data, untouched
+	}, ctx.ConfluenceMap)
+
+	if got := decisionActions(decisions); got[0] != "AAPL:open_long" || got[1] != "AAPL:wait" || got[2] != "TSLA:open_short" {
+		t.Errorf("unexpected decisions: %v", got)
+	}
+
+	// Strict mode requires all timeframes
+	cfg.Indicators.ConfluenceRequireAll = true
+	decisions = engine.enforceConfluence([]Decision{{Symbol: "AAPL", Action: "open_long"}}, ctx.ConfluenceMap)
+	if decisions[0].Action != "wait" {
+		t.Errorf("expected open_long blocked in strict mode, got %s", decisions[0].Action)
+	}
+}
@@ -0,0 +1,164 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Context Builder Hooks
+// ============================================================================
+// Users inject their own data (proprietary signals, a desk's notes, ...) into
+// the context and user prompt without forking BuildUserPrompt. Hooks are
+// registered per trader ID (AllTraders = every trader) from Go code, or run
+// as external scripts configured in the strategy (see context_script.go):
+//   - AfterContextBuild runs once per cycle after the trader has built the
+//     context and before any AI call; it may adjust the context or add
+//     PromptBlocks
+//   - BeforePromptBuild runs every time a user prompt is built from the
+//     context (once per batch, again when the prompt budget trims it); its
+//     blocks go into that prompt only, so it must be cheap
+// PromptBlocks are rendered as "## <Title>" sections before the candidates.
+// A failing or panicking hook is logged and skipped, never fails the cycle.
+
+// AllTraders trader ID of hooks applied to every trader
+const AllTraders = "*"
+
+// maxPromptBlockChars content of a prompt block is truncated beyond this many characters
+const maxPromptBlockChars = 4000
+
+// PromptBlock custom data block appended to the user prompt
+type PromptBlock struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// ContextHook extension point of a trader's context and user prompt (either function may be nil)
+type ContextHook struct {
+	Name              string
+	AfterContextBuild func(ctx *Context) error
+	BeforePromptBuild func(ctx *Context) ([]PromptBlock, error)
+}
+
+var (
+	contextHooksMu sync.RWMutex
+	contextHooks   = make(map[string][]ContextHook) // trader ID -> hooks in registration order
+)
+
+// RegisterContextHook registers a hook for a trader (AllTraders = every trader)
+// A hook registered again under the same name for the same trader replaces the previous one.
+func RegisterContextHook(traderID string, hook ContextHook) {
+	contextHooksMu.Lock()
+	defer contextHooksMu.Unlock()
+	hooks := contextHooks[traderID]
+	for i := range hooks {
+		if hooks[i].Name == hook.Name {
+			hooks[i] = hook
+			return
+		}
+	}
+	contextHooks[traderID] = append(hooks, hook)
+}
+
+// UnregisterContextHook removes a trader's hook by name
+func UnregisterContextHook(traderID, name string) {
+	contextHooksMu.Lock()
+	defer contextHooksMu.Unlock()
+	hooks := contextHooks[traderID]
+	for i := range hooks {
+		if hooks[i].Name == name {
+			contextHooks[traderID] = append(hooks[:i:i], hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// ContextHooksFor hooks applying to a trader: AllTraders hooks first, then the trader's own
+func ContextHooksFor(traderID string) []ContextHook {
+	contextHooksMu.RLock()
+	defer contextHooksMu.RUnlock()
+	hooks := append([]ContextHook(nil), contextHooks[AllTraders]...)
+	if traderID != "" && traderID != AllTraders {
+		hooks = append(hooks, contextHooks[traderID]...)
+	}
+	return hooks
+}
+
+// RunAfterContextBuild runs the hooks' AfterContextBuild in order, returning one message per failed hook
+func RunAfterContextBuild(ctx *Context, hooks []ContextHook) []string {
+	var failures []string
+	for _, hook := range hooks {
+		if hook.AfterContextBuild == nil {
+			continue
+		}
+		if err := runContextHook(hook.Name, func() error { return hook.AfterContextBuild(ctx) }); err != nil {
+			logger.Warnf("🔌 Context hook %s failed: %v", hook.Name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", hook.Name, err))
+		}
+	}
+	return failures
+}
+
+// AddPromptBlock adds a custom data block to the user prompt (empty content is ignored)
+func (ctx *Context) AddPromptBlock(title, content string) {
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	ctx.PromptBlocks = append(ctx.PromptBlocks, PromptBlock{Title: title, Content: content})
+}
+
+// promptBlocks the context's blocks plus those of BeforePromptBuild hooks for this prompt
+func promptBlocks(ctx *Context) []PromptBlock {
+	blocks := append([]PromptBlock(nil), ctx.PromptBlocks...)
+	for _, hook := range ContextHooksFor(ctx.TraderID) {
+		if hook.BeforePromptBuild == nil {
+			continue
+		}
+		var added []PromptBlock
+		err := runContextHook(hook.Name, func() error {
+			var err error
+			added, err = hook.BeforePromptBuild(ctx)
+			return err
+		})
+		if err != nil {
+			logger.Warnf("🔌 Context hook %s failed: %v", hook.Name, err)
+			continue
+		}
+		blocks = append(blocks, added...)
+	}
+	return blocks
+}
+
+// runContextHook calls fn, turning a panic into an error
+func runContextHook(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("hook %s panicked: %v", name, r)
+		}
+	}()
+	return fn()
+}
+
+// formatPromptBlocks custom data section of the user prompt (empty without blocks)
+func formatPromptBlocks(ctx *Context) string {
+	blocks := promptBlocks(ctx)
+	var sb strings.Builder
+	for _, block := range blocks {
+		content := strings.TrimSpace(block.Content)
+		if content == "" {
+			continue
+		}
+		if runes := []rune(content); len(runes) > maxPromptBlockChars {
+			content = string(runes[:maxPromptBlockChars]) + "\n[truncated]"
+		}
+		title := strings.TrimSpace(block.Title)
+		if title == "" {
+			title = "Custom Data"
+		}
+		sb.WriteString("## " + title + "\n")
+		sb.WriteString(content + "\n\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package decision
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContextHooks(t *testing.T) {
+	defer UnregisterContextHook(AllTraders, "global")
+	defer UnregisterContextHook("trader-1", "signals")
+	defer UnregisterContextHook("trader-1", "broken")
+
+	RegisterContextHook(AllTraders, ContextHook{
+		Name: "global",
+		BeforePromptBuild: func(ctx *Context) ([]PromptBlock, error) {
+			return []PromptBlock{{Title: "Desk Notes", Content: "FOMC at 14:00"}}, nil
+		},
+	})
+	RegisterContextHook("trader-1", ContextHook{
+		Name: "signals",
+		AfterContextBuild: func(ctx *Context) error {
+			ctx.AddPromptBlock("Proprietary Signals", "NVDA: +0.8")
+			return nil
+		},
+	})
+	RegisterContextHook("trader-1", ContextHook{
+		Name:              "broken",
+		AfterContextBuild: func(ctx *Context) error { panic("boom") },
+		BeforePromptBuild: func(ctx *Context) ([]PromptBlock, error) { return nil, errors.New("feed down") },
+	})
+
+	if got := len(ContextHooksFor("trader-2")); got != 1 {
+		t.Fatalf("trader-2 should only get the global hook, got %d", got)
+	}
+
+	ctx := &Context{TraderID: "trader-1"}
+	failures := RunAfterContextBuild(ctx, ContextHooksFor("trader-1"))
+	if len(failures) != 1 || !strings.Contains(failures[0], "panicked") {
+		t.Errorf("failures = %v, want the recovered panic of broken", failures)
+	}
+
+	out := formatPromptBlocks(ctx)
+	for _, want := range []string{"## Proprietary Signals\nNVDA: +0.8", "## Desk Notes\nFOMC at 14:00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("prompt blocks missing %q:\n%s", want, out)
+		}
+	}
+	if formatPromptBlocks(&Context{TraderID: "trader-2"}) != "## Desk Notes\nFOMC at 14:00\n\n" {
+		t.Errorf("trader-2 should only see global blocks")
+	}
+}
+
+func TestParseContextScriptOutput(t *testing.T) {
+	blocks := parseContextScriptOutput("flow", `{"blocks":[{"title":"Options Flow","content":"TSLA call sweep"}]}`)
+	if len(blocks) != 1 || blocks[0].Title != "Options Flow" {
+		t.Errorf("JSON output = %+v", blocks)
+	}
+	blocks = parseContextScriptOutput("flow", "TSLA call sweep\n")
+	if len(blocks) != 1 || blocks[0].Title != "flow" || blocks[0].Content != "TSLA call sweep" {
+		t.Errorf("plain output = %+v", blocks)
+	}
+	if parseContextScriptOutput("flow", "  ") != nil {
+		t.Error("empty output should add no block")
+	}
+}
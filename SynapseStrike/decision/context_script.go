@@ -0,0 +1,114 @@
+package decision
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Context hook scripts are executables in the server's context hooks directory, so strategies (editable
+// via the API) can only name scripts an operator installed, never run arbitrary commands. The script
+// receives the cycle context as JSON on stdin and prints either {"blocks":[{"title":..,"content":..}]}
+// or plain text, which becomes one block titled with the script name.
+
+const (
+	defaultContextScriptTimeout = 10 * time.Second
+	maxContextScriptOutput      = 64 * 1024 // Bytes of stdout read from a script
+)
+
+// contextScriptInput JSON written to a context hook script's stdin
+type contextScriptInput struct {
+	TraderID string             `json:"trader_id"`
+	Context  *Context           `json:"context"`
+	Prices   map[string]float64 `json:"prices,omitempty"` // Current price per symbol with market data
+}
+
+// contextScriptOutput JSON printed by a context hook script
+type contextScriptOutput struct {
+	Blocks []PromptBlock `json:"blocks"`
+}
+
+// ScriptContextHook context hook running the executable at path once per cycle (AfterContextBuild)
+func ScriptContextHook(name, path string, timeout time.Duration) ContextHook {
+	if timeout <= 0 {
+		timeout = defaultContextScriptTimeout
+	}
+	return ContextHook{
+		Name: "script:" + name,
+		AfterContextBuild: func(ctx *Context) error {
+			blocks, err := runContextScript(name, path, timeout, ctx)
+			if err != nil {
+				return err
+			}
+			for _, block := range blocks {
+				ctx.AddPromptBlock(block.Title, block.Content)
+			}
+			return nil
+		},
+	}
+}
+
+// runContextScript executes the script with the context on stdin and parses its blocks
+func runContextScript(name, path string, timeout time.Duration, ctx *Context) ([]PromptBlock, error) {
+	input := contextScriptInput{TraderID: ctx.TraderID, Context: ctx}
+	for symbol, data := range ctx.MarketDataMap {
+		if data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		if input.Prices == nil {
+			input.Prices = make(map[string]float64, len(ctx.MarketDataMap))
+		}
+		input.Prices[symbol] = data.CurrentPrice
+	}
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode context: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, path)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if runCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, truncateScriptOutput(msg, 200))
+		}
+		return nil, err
+	}
+	if stdout.Len() > maxContextScriptOutput {
+		return nil, fmt.Errorf("output of %d bytes exceeds %d", stdout.Len(), maxContextScriptOutput)
+	}
+	return parseContextScriptOutput(name, stdout.String()), nil
+}
+
+// parseContextScriptOutput blocks of a {"blocks": [...]} object, else the whole output as one block
+func parseContextScriptOutput(name, output string) []PromptBlock {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+	if strings.HasPrefix(output, "{") {
+		var parsed contextScriptOutput
+		if err := json.Unmarshal([]byte(output), &parsed); err == nil {
+			return parsed.Blocks
+		}
+	}
+	return []PromptBlock{{Title: name, Content: output}}
+}
+
+func truncateScriptOutput(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Data Completeness Report
+// ============================================================================
+// Every feed that failed for a symbol this cycle is collected in
+// Context.DataGaps: the whole market data fetch (klines), secondary kline
+// timeframes, open interest, stock news and the quant data API, each with the
+// reason. The list is stored with the decision record and summarized in the
+// user prompt so the AI can lower its confidence where data is missing
+// instead of treating a partial picture as complete.
+
+// addDataGap reports a failed feed of a symbol
+func (ctx *Context) addDataGap(symbol, feed, reason string) {
+	ctx.DataGaps = append(ctx.DataGaps, store.DataGap{Symbol: symbol, Feed: feed, Reason: reason})
+}
+
+// addMarketDataGaps reports the optional feeds missing from fetched market data
+// News gaps only count when news is part of the prompt.
+func (ctx *Context) addMarketDataGaps(symbol string, data *market.Data, config *store.StrategyConfig) {
+	for _, gap := range data.Gaps {
+		feed := gap.Feed
+		switch feed {
+		case market.FeedKlines:
+			feed = store.DataFeedKlines
+		case market.FeedOI:
+			feed = store.DataFeedOI
+		case market.FeedNews:
+			if !config.Indicators.EnableStockNews {
+				continue
+			}
+			feed = store.DataFeedNews
+		}
+		ctx.addDataGap(symbol, feed, gap.Reason)
+	}
+}
+
+// AddQuantDataGaps reports symbols whose quant data could not be fetched
+func (ctx *Context) AddQuantDataGaps(errs map[string]error) {
+	for symbol, err := range errs {
+		ctx.addDataGap(symbol, store.DataFeedQuant, err.Error())
+	}
+}
+
+// formatDataCompleteness summarizes the data gaps of symbols shown to the AI
+// Candidates dropped by liquidity or quality filters are not in the prompt, so their gaps are left out.
+func (e *StrategyEngine) formatDataCompleteness(ctx *Context) string {
+	dropped := make(map[string]bool)
+	for _, f := range ctx.FilteredOut {
+		if f.Reason != store.FilterReasonFetchFailed {
+			dropped[f.Symbol] = true
+		}
+	}
+
+	var symbols []string
+	gapsBySymbol := make(map[string][]string)
+	for _, gap := range ctx.DataGaps {
+		if dropped[gap.Symbol] {
+			continue
+		}
+		if _, ok := gapsBySymbol[gap.Symbol]; !ok {
+			symbols = append(symbols, gap.Symbol)
+		}
+		gapsBySymbol[gap.Symbol] = append(gapsBySymbol[gap.Symbol], fmt.Sprintf("%s (%s)", gap.Feed, gap.Reason))
+	}
+	if len(symbols) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(e.t("## Data Completeness\n"))
+	sb.WriteString(fmt.Sprintf(e.t("%d symbols have incomplete data this cycle:\n"), len(symbols)))
+	for _, symbol := range symbols {
+		line := strings.Join(gapsBySymbol[symbol], "; ")
+		if _, hasData := ctx.MarketDataMap[symbol]; !hasData {
+			line = e.t("no market data") + " — " + line
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", symbol, line))
+	}
+	sb.WriteString(e.t("Factor these gaps into your confidence: lower it when a feed your analysis relies on is missing, and do not open positions on symbols without market data.\n\n"))
+	return sb.String()
+}
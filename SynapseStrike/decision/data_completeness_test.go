@@ -0,0 +1,61 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDataCompletenessReport(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.CoinSource.MinOIValueUSD = 1_000_000
+	engine := NewStrategyEngine(&cfg)
+
+	partial := &market.Data{CurrentPrice: 1, OpenInterest: &market.OIData{Latest: 5_000_000}, Gaps: []market.FeedGap{
+		{Feed: market.FeedKlines, Reason: "4h: no bars"},
+		{Feed: market.FeedNews, Reason: "news disabled"}, // News not in the prompt, ignored
+	}}
+	lowOI := &market.Data{CurrentPrice: 1, OpenInterest: &market.OIData{Latest: 1}}
+	ctx := &Context{
+		MarketDataMap: make(map[string]*market.Data),
+		CandidateStocks: []CandidateStock{
+			{Symbol: "AUSDT"}, {Symbol: "BUSDT"}, {Symbol: "CUSDT"},
+		},
+	}
+	ctx.AddQuantDataGaps(map[string]error{"CUSDT": errors.New("quant timeout")})
+	applyMarketDataFilters(ctx, &cfg, []string{"AUSDT", "BUSDT", "CUSDT"}, []marketFetchResult{
+		{data: partial},
+		{err: errors.New("klines unavailable")},
+		{data: lowOI},
+	}, nil)
+
+	want := []store.DataGap{
+		{Symbol: "CUSDT", Feed: store.DataFeedQuant, Reason: "quant timeout"},
+		{Symbol: "AUSDT", Feed: store.DataFeedKlines, Reason: "4h: no bars"},
+		{Symbol: "BUSDT", Feed: store.DataFeedKlines, Reason: "klines unavailable"},
+	}
+	if len(ctx.DataGaps) != len(want) {
+		t.Fatalf("expected gaps %+v, got %+v", want, ctx.DataGaps)
+	}
+	for i := range want {
+		if ctx.DataGaps[i] != want[i] {
+			t.Errorf("gap %d: expected %+v, got %+v", i, want[i], ctx.DataGaps[i])
+		}
+	}
+
+	// CUSDT was dropped for low OI, the AI never sees it
+	report := engine.formatDataCompleteness(ctx)
+	if !strings.Contains(report, "2 symbols have incomplete data") ||
+		!strings.Contains(report, "- AUSDT: klines (4h: no bars)") ||
+		!strings.Contains(report, "- BUSDT: no market data — klines (klines unavailable)") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+	if strings.Contains(report, "CUSDT") {
+		t.Errorf("expected filtered CUSDT left out:\n%s", report)
+	}
+	if engine.formatDataCompleteness(&Context{}) != "" {
+		t.Error("expected no section without gaps")
+	}
+}
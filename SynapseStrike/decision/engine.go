@@ -4,15 +4,18 @@ import (
 	"SynapseStrike/logger"
 	"SynapseStrike/market"
 	"SynapseStrike/mcp"
+	"SynapseStrike/numfmt"
 	"SynapseStrike/provider"
 	"SynapseStrike/security"
 	"SynapseStrike/store"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -39,18 +42,30 @@ var (
 
 // PositionInfo position information
 type PositionInfo struct {
-	Symbol           string  `json:"symbol"`
-	Side             string  `json:"side"` // "long" or "short"
-	EntryPrice       float64 `json:"entry_price"`
-	MarkPrice        float64 `json:"mark_price"`
-	Quantity         float64 `json:"quantity"`
-	Leverage         int     `json:"leverage"`
-	UnrealizedPnL    float64 `json:"unrealized_pnl"`
-	UnrealizedPnLPct float64 `json:"unrealized_pnl_pct"`
-	PeakPnLPct       float64 `json:"peak_pnl_pct"` // Historical peak profit percentage
-	LiquidationPrice float64 `json:"liquidation_price"`
-	MarginUsed       float64 `json:"margin_used"`
-	UpdateTime       int64   `json:"update_time"` // Position update timestamp (milliseconds)
+	Symbol           string        `json:"symbol"`
+	Side             string        `json:"side"` // "long" or "short"
+	EntryPrice       float64       `json:"entry_price"`
+	MarkPrice        float64       `json:"mark_price"`
+	Quantity         float64       `json:"quantity"`
+	Leverage         int           `json:"leverage"`
+	UnrealizedPnL    float64       `json:"unrealized_pnl"`
+	UnrealizedPnLPct float64       `json:"unrealized_pnl_pct"`
+	PeakPnLPct       float64       `json:"peak_pnl_pct"` // Historical peak profit percentage
+	LiquidationPrice float64       `json:"liquidation_price"`
+	MarginUsed       float64       `json:"margin_used"`
+	UpdateTime       int64         `json:"update_time"`             // Position update timestamp (milliseconds)
+	ForcedReview     bool          `json:"forced_review,omitempty"` // Max holding time reached, AI must decide to close or hold
+	Overnight        *OvernightGap `json:"overnight,omitempty"`     // Held over the last close (morning cycles only)
+}
+
+// OvernightGap gap analysis of a stock position held overnight
+type OvernightGap struct {
+	HeldSince  string  `json:"held_since"`  // Trading day the position was carried past the close (YYYY-MM-DD)
+	PrevClose  float64 `json:"prev_close"`  // Price when the end-of-day policy ran
+	Open       float64 `json:"open"`        // Today's opening price (current price if unavailable)
+	GapPct     float64 `json:"gap_pct"`     // (Open - PrevClose) / PrevClose × 100
+	StopPrice  float64 `json:"stop_price"`  // Overnight hard stop (0 = none)
+	StopGapped bool    `json:"stop_gapped"` // Open is beyond the hard stop
 }
 
 // AccountInfo account information
@@ -89,6 +104,19 @@ type TradingStats struct {
 	AvgWin         float64 `json:"avg_win"`          // Average win
 	AvgLoss        float64 `json:"avg_loss"`         // Average loss
 	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Maximum drawdown (%)
+
+	// Entry/exit quality from MFE/MAE of trades tracked by the position monitor (see trade_quality.go)
+	TrackedTrades     int     `json:"tracked_trades,omitempty"`
+	AvgMAEWinnersPct  float64 `json:"avg_mae_winners_pct,omitempty"` // Average heat winners took (% of entry price)
+	MaxMAEWinnersPct  float64 `json:"max_mae_winners_pct,omitempty"` // Deepest heat a winner recovered from
+	AvgMFELosersPct   float64 `json:"avg_mfe_losers_pct,omitempty"`  // Average open profit losers gave back
+	ExitEfficiencyPct float64 `json:"exit_efficiency_pct,omitempty"` // Share of the favorable move winners captured
+	StopEfficiencyPct float64 `json:"stop_efficiency_pct,omitempty"` // Realized loss / MAE of losers (100 = cut at the worst point)
+	AvgEntryQuality   float64 `json:"avg_entry_quality,omitempty"`   // MFE / (MFE + MAE) × 100, averaged
+
+	// Recent performance windows (see trade_quality.go), nil = not loaded
+	LastDays   *store.WindowStats `json:"last_days,omitempty"`   // Trades closed in the last RecentStatsDays days
+	LastTrades *store.WindowStats `json:"last_trades,omitempty"` // Last RecentStatsTrades closed trades
 }
 
 // RecentOrder recently completed order (for AI input)
@@ -104,43 +132,108 @@ type RecentOrder struct {
 	HoldDuration string  `json:"hold_duration"` // Hold duration, e.g. "2h30m"
 }
 
+// LimitEntryInfo limit entry order placed from an entry_price decision
+type LimitEntryInfo struct {
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"`        // long/short
+	EntryPrice float64 `json:"entry_price"` // Limit price
+	Quantity   float64 `json:"quantity"`
+	FilledQty  float64 `json:"filled_qty"`
+	Status     string  `json:"status"`     // pending | filled | expired | canceled
+	PlacedAt   string  `json:"placed_at"`  // Placement time
+	ExpiresAt  string  `json:"expires_at"` // Cancel time if still unfilled
+	Note       string  `json:"note,omitempty"`
+}
+
+// GridInfo state and P&L of one grid run by the grid engine (Local Function grid tactic)
+type GridInfo struct {
+	Symbol        string  `json:"symbol"`
+	LowerPrice    float64 `json:"lower_price"`
+	UpperPrice    float64 `json:"upper_price"`
+	Levels        int     `json:"levels"`
+	Price         float64 `json:"price"`          // Current price (0 if unavailable)
+	PendingLevels int     `json:"pending_levels"` // Level orders resting
+	OpenLegs      int     `json:"open_legs"`      // Filled levels waiting for take profit
+	OpenQuantity  float64 `json:"open_quantity"`
+	ClosedLegs    int     `json:"closed_legs"`
+	RealizedPnL   float64 `json:"realized_pnl"`   // Closed legs, before fees
+	UnrealizedPnL float64 `json:"unrealized_pnl"` // Open legs at the current price
+}
+
 // Context trading context (complete information passed to AI)
 type Context struct {
-	CurrentTime      string                             `json:"current_time"`
-	RuntimeMinutes   int                                `json:"runtime_minutes"`
-	CallCount        int                                `json:"call_count"`
-	Account          AccountInfo                        `json:"account"`
-	Positions        []PositionInfo                     `json:"positions"`
-	CandidateStocks  []CandidateStock                   `json:"candidate_stocks"`
-	PromptVariant    string                             `json:"prompt_variant,omitempty"`
-	TradingStats     *TradingStats                      `json:"trading_stats,omitempty"`
-	RecentOrders     []RecentOrder                      `json:"recent_orders,omitempty"`
-	MarketDataMap    map[string]*market.Data            `json:"-"`
-	MultiTFMarket    map[string]map[string]*market.Data `json:"-"`
-	OITopDataMap     map[string]*OITopData              `json:"-"`
-	QuantDataMap     map[string]*QuantData              `json:"-"`
-	OIRankingData    *provider.OIRankingData            `json:"-"` // Market-wide OI ranking data
-	LargeCapLeverage int                                `json:"-"`
-	SmallCapLeverage int                                `json:"-"`
-	Timeframes       []string                           `json:"-"`
-	PositionTPSLMap  map[string][2]float64              `json:"-"` // Cached TP/SL prices per position (symbol_side -> [TP, SL])
+	TraderID          string                             `json:"-"` // Trader the context is built for (selects its context hooks)
+	CurrentTime       string                             `json:"current_time"`
+	RuntimeMinutes    int                                `json:"runtime_minutes"`
+	CallCount         int                                `json:"call_count"`
+	Account           AccountInfo                        `json:"account"`
+	Positions         []PositionInfo                     `json:"positions"`
+	CandidateStocks   []CandidateStock                   `json:"candidate_stocks"`
+	PromptVariant     string                             `json:"prompt_variant,omitempty"`
+	TradingStats      *TradingStats                      `json:"trading_stats,omitempty"`
+	RecentOrders      []RecentOrder                      `json:"recent_orders,omitempty"`
+	LimitEntries      []LimitEntryInfo                   `json:"limit_entries,omitempty"` // Pending limit entries and those resolved since last cycle
+	Grids             []GridInfo                         `json:"grids,omitempty"`         // Grid engine state (Local Function grid tactic)
+	MarketDataMap     map[string]*market.Data            `json:"-"`
+	MultiTFMarket     map[string]map[string]*market.Data `json:"-"`
+	OITopDataMap      map[string]*OITopData              `json:"-"`
+	FundingDataMap    map[string]*FundingData            `json:"-"` // Funding rate annotations (funding_arb source only)
+	WebhookDataMap    map[string]*WebhookCandidate       `json:"-"` // Screener scores/notes (webhook source only)
+	SocialDataMap     map[string]*SocialData             `json:"-"` // Mentions/sentiment (social_trending source only)
+	Regime            *MarketRegime                      `json:"-"` // Index-based market regime (Regime.Enabled only)
+	EquityRisk        *EquityRiskState                   `json:"-"` // Equity curve risk multiplier (EquityRisk.Enabled only)
+	Blackouts         []Blackout                         `json:"-"` // Active and upcoming news blackout windows
+	Shock             *MarketShock                       `json:"-"` // Detected market shock (nil = normal cycle, Shock.Enabled only)
+	QuantDataMap      map[string]*QuantData              `json:"-"`
+	OIRankingData     *provider.OIRankingData            `json:"-"` // Market-wide OI ranking data
+	LargeCapLeverage  int                                `json:"-"`
+	SmallCapLeverage  int                                `json:"-"`
+	Timeframes        []string                           `json:"-"`
+	PositionTPSLMap   map[string][2]float64              `json:"-"` // Cached TP/SL prices per position (symbol_side -> [TP, SL])
+	Deadline          time.Time                          `json:"-"` // Cycle deadline for AI calls (zero = no deadline)
+	FailoverClient    mcp.AIClient                       `json:"-"` // Different-provider client for batches failing all retries (nil = none)
+	RepairClient      mcp.AIClient                       `json:"-"` // Client repairing malformed decision JSON (nil = the answering client)
+	ConfluenceMap     map[string]*ConfluenceScore        `json:"-"` // Multi-timeframe confluence per symbol (EnableConfluence only)
+	Memory            MemoryRecaller                     `json:"-"` // Decision memory for similar past setups (nil = disabled)
+	Situations        map[string]string                  `json:"-"` // Described market situation per symbol (Memory.Enabled only)
+	Lessons           map[string][]*store.DecisionMemory `json:"-"` // Similar resolved past setups per candidate
+	Annotations       []*store.TradeAnnotation           `json:"-"` // Recent human trade annotations, newest first (Annotations.Enabled only)
+	PreviousDecisions map[string]*PreviousDecision       `json:"-"` // Last decision and outcome per symbol (ChurnGuard.Enabled only)
+	SessionSummary    string                             `json:"-"` // Compressed reasoning and outcomes of the last cycles (SessionMemory.Enabled only)
+	Exchange          string                             `json:"-"` // Exchange type, used for exchange minimum order values
+	CandidateRanking  *CandidateRanking                  `json:"-"` // Opportunity scores and cut candidates (CandidateRanking.Enabled only)
+	SymbolListBlocked []string                           `json:"-"` // Candidates removed by the trader's symbol allow/deny lists
+	FilteredOut       []store.FilteredSymbol             `json:"-"` // Candidates dropped by market data filters (liquidity, data quality, fetch failures)
+	DataGaps          []store.DataGap                    `json:"-"` // Feeds that failed this cycle, per symbol (data completeness report)
+	TradeBudget       *TradeBudget                       `json:"-"` // Opens used and rejected against the trade budget (TradeGovernor.Enabled only)
+	PromptBlocks      []PromptBlock                      `json:"-"` // Custom data blocks added by context hooks
+	NativeReasoning   bool                               `json:"-"` // AI's native reasoning is captured, <reasoning> tags are optional
+	OpensPaused       string                             `json:"-"` // Operator pause reason, opens are rejected ("" = not paused)
+	VenueMaintenance  string                             `json:"-"` // Active exchange maintenance window, no orders are sent ("" = none)
 }
 
 // Decision AI trading decision
 type Decision struct {
 	Symbol string `json:"symbol"`
-	Action string `json:"action"` // "open_long", "open_short", "close_long", "close_short", "hold", "wait"
+	Action string `json:"action"` // "open_long", "open_short", "close_long", "close_short", "update_stops", "hold", "wait"
 
 	// Opening position parameters
 	Leverage        int     `json:"leverage,omitempty"`
 	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
 	StopLoss        float64 `json:"stop_loss,omitempty"`
 	TakeProfit      float64 `json:"take_profit,omitempty"`
+	EntryPrice      float64 `json:"entry_price,omitempty"` // Limit entry price (0 = market entry)
+
+	TakeProfitLevels []TakeProfitLevel `json:"take_profit_levels,omitempty"` // Scaled exits, nearest first (see take_profit_levels.go)
 
 	// Common parameters
 	Confidence int     `json:"confidence,omitempty"` // Confidence level (0-100)
 	RiskUSD    float64 `json:"risk_usd,omitempty"`   // Maximum USD risk
 	Reasoning  string  `json:"reasoning"`
+
+	// Schema v2 (see schema.go)
+	SchemaVersion int                    `json:"schema_version,omitempty"` // Output contract version (missing = v1)
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`       // Extra action parameters (trailing stop, validity window, order type)
 }
 
 // FullDecision AI's complete decision (including chain of thought)
@@ -152,6 +245,14 @@ type FullDecision struct {
 	RawResponse         string     `json:"raw_response"`
 	Timestamp           time.Time  `json:"timestamp"`
 	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+	// Per-decision validation results (dropped and repaired entries)
+	Validation *ValidationReport `json:"validation,omitempty"`
+	// Self-review second pass verdicts (nil if disabled or nothing to review)
+	SelfReview *SelfReviewResult `json:"self_review,omitempty"`
+	// Per-model outputs in ensemble mode (nil for single model)
+	Ensemble *EnsembleResult `json:"ensemble,omitempty"`
+	// What was dropped to fit user prompts into the token budget (one entry per compressed prompt)
+	PromptCompression []PromptCompression `json:"prompt_compression,omitempty"`
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -190,14 +291,24 @@ type OIDeltaData struct {
 
 // StrategyEngine strategy execution engine
 type StrategyEngine struct {
-	config *store.StrategyConfig
+	config     *store.StrategyConfig
+	strategyID string // Strategy ID (keys webhook candidate lists)
 }
 
 // NewStrategyEngine creates strategy execution engine
+// Legacy coin fields of configs not loaded through ParseConfig (API requests, backtests) are migrated in place.
 func NewStrategyEngine(config *store.StrategyConfig) *StrategyEngine {
+	if config != nil {
+		config.Migrate()
+	}
 	return &StrategyEngine{config: config}
 }
 
+// SetStrategyID sets ID of the strategy this engine runs (required by the webhook candidate source)
+func (e *StrategyEngine) SetStrategyID(id string) {
+	e.strategyID = id
+}
+
 // GetRiskControlConfig gets risk control configuration
 func (e *StrategyEngine) GetRiskControlConfig() store.RiskControlConfig {
 	return e.config.RiskControl
@@ -208,6 +319,18 @@ func (e *StrategyEngine) GetConfig() *store.StrategyConfig {
 	return e.config
 }
 
+// GetGenerationParams gets AI sampling parameters of the strategy
+func (e *StrategyEngine) GetGenerationParams() mcp.GenerationParams {
+	ap := e.config.AIParams
+	return mcp.GenerationParams{
+		Temperature:     ap.Temperature,
+		TopP:            ap.TopP,
+		MaxTokens:       ap.MaxTokens,
+		ReasoningEffort: ap.ReasoningEffort,
+		NativeReasoning: ap.NativeReasoning,
+	}
+}
+
 // ============================================================================
 // Entry Functions - Main API
 // ============================================================================
@@ -230,10 +353,18 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		engine = NewStrategyEngine(&defaultConfig)
 	}
 
+	if err := prepareDecisionContext(ctx, engine); err != nil {
+		return nil, err
+	}
+	return runDecisionPipeline(ctx, mcpClient, engine, variant)
+}
+
+// prepareDecisionContext fetches market data and computes shared context data (confluence, lessons, regime, ...)
+func prepareDecisionContext(ctx *Context, engine *StrategyEngine) error {
 	// 1. Fetch market data using strategy config
 	if len(ctx.MarketDataMap) == 0 {
 		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
-			return nil, fmt.Errorf("failed to fetch market data: %w", err)
+			return store.WithErrorCategory(store.ErrorCategoryDataMissing, fmt.Errorf("failed to fetch market data: %w", err))
 		}
 	}
 
@@ -253,7 +384,42 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		}
 	}
 
+	// Multi-timeframe confluence (computed once, shared by all batches)
+	engine.ComputeConfluence(ctx)
+
+	// Similar past setups with outcomes (decision memory)
+	engine.RecallLessons(ctx)
+
+	// Cross-exchange funding rates for funding_arb candidates
+	engine.ComputeFundingData(ctx)
+
+	// Screener scores/notes for webhook candidates
+	engine.ComputeWebhookData(ctx)
+
+	// Mentions and sentiment for social trending candidates
+	engine.ComputeSocialData(ctx)
+
+	// SPY/QQQ market regime
+	engine.ComputeRegime(ctx)
+
+	// Index gap/crash and candidate volatility spike detection
+	engine.ComputeShock(ctx)
+
+	// Earnings and macro event blackout windows
+	engine.ComputeBlackouts(ctx)
+
+	// Most promising candidates first, so they land in the earliest batches
+	engine.RankCandidates(ctx)
+	return nil
+}
+
+// runDecisionPipeline gets decision of one AI client for a prepared context
+// Only reads ctx, so several clients may run on the same context concurrently (ensemble mode).
+func runDecisionPipeline(ctx *Context, mcpClient mcp.AIClient, engine *StrategyEngine, variant string) (*FullDecision, error) {
 	riskConfig := engine.GetRiskControlConfig()
+	stopRefs := buildStopReferences(ctx, engine.GetConfig().Indicators.Klines.PrimaryTimeframe)
+	toolRegistry := engine.buildToolRegistry()
+	toolBudget := engine.toolCallingBudget()
 
 	// =========================================================================
 	// Local Function Provider: bypass AI calls entirely, use algorithmic logic
@@ -277,6 +443,9 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 			len(allCandidates), batchSize, (len(allCandidates)+batchSize-1)/batchSize)
 	}
 
+	// Models returning reasoning in a native channel (extended thinking, reasoning_content) need no <reasoning> block
+	nativeReasoning := mcp.CapturesNativeReasoning(mcpClient)
+
 	var allDecisions []Decision
 	var allCoTTraces []string
 	var allUserPrompts []string
@@ -284,6 +453,8 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 	var systemPrompt string
 	var totalAIDurationMs int64
 	var lastErr error
+	var compressions []PromptCompression
+	validation := &ValidationReport{}
 
 	// Split candidates into batches
 	for batchIdx := 0; batchIdx < len(allCandidates); batchIdx += batchSize {
@@ -295,6 +466,15 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		batchNum := batchIdx/batchSize + 1
 		totalBatches := (len(allCandidates) + batchSize - 1) / batchSize
 
+		// Cycle deadline reached: cancel remaining batches, proceed with decisions obtained so far
+		if !ctx.Deadline.IsZero() && !time.Now().Before(ctx.Deadline) {
+			skipped := totalBatches - batchNum + 1
+			logger.Warnf("⏱️  Cycle deadline reached, skipping remaining %d/%d batches", skipped, totalBatches)
+			allCoTTraces = append(allCoTTraces, fmt.Sprintf("## Batches %d-%d/%d — SKIPPED\nCycle deadline reached", batchNum, totalBatches, totalBatches))
+			lastErr = fmt.Errorf("%w before batch %d/%d", errCycleDeadline, batchNum, totalBatches)
+			break
+		}
+
 		if needsBatching {
 			symbols := make([]string, len(batchStocks))
 			for i, s := range batchStocks {
@@ -313,40 +493,89 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 			CandidateStocks: batchStocks,
 			MarketDataMap:  ctx.MarketDataMap,
 			OITopDataMap:   ctx.OITopDataMap,
+			FundingDataMap: ctx.FundingDataMap,
+			WebhookDataMap: ctx.WebhookDataMap,
+			SocialDataMap:  ctx.SocialDataMap,
+			Regime:         ctx.Regime,
+			Blackouts:      ctx.Blackouts,
+			Shock:          ctx.Shock,
 			QuantDataMap:   ctx.QuantDataMap,
+			TraderID:       ctx.TraderID,
+			TradingStats:   ctx.TradingStats,
 			RecentOrders:   ctx.RecentOrders,
+			TradeBudget:    ctx.TradeBudget,
+			PromptBlocks:   ctx.PromptBlocks,
+			LimitEntries:   ctx.LimitEntries,
+			ConfluenceMap:  ctx.ConfluenceMap,
+			Lessons:        ctx.Lessons,
+			Annotations:    ctx.Annotations,
+			SessionSummary: ctx.SessionSummary,
+			CandidateRanking: ctx.CandidateRanking,
+			NativeReasoning: nativeReasoning,
 		}
 
 		// Build prompts for this batch
-		systemPrompt = engine.BuildSystemPrompt(ctx.Account.TotalEquity, variant)
-		userPrompt := engine.BuildUserPrompt(batchCtx)
+		systemPrompt = engine.buildSystemPrompt(ctx.Account.TotalEquity, variant, nativeReasoning)
+		systemPrompt += formatEquityRisk(ctx.EquityRisk)
+		if toolRegistry != nil {
+			systemPrompt += "\n" + toolRegistry.PromptSection(toolBudget)
+		}
+		userPrompt, compression := engine.BuildUserPromptWithBudget(batchCtx)
+		if compression != nil {
+			if needsBatching {
+				compression.Batch = batchNum
+			}
+			logger.Warnf("%s", compression.String())
+			compressions = append(compressions, *compression)
+		}
 
-		// Call AI API
+		// Call AI API (retried with backoff, then failover client, see batch_retry.go)
 		aiCallStart := time.Now()
-		var aiResponse string
-		var err error
-
-		if mcpClient.GetProvider() == mcp.ProviderArchitect {
-			symbol := "BTCUSDT"
-			if len(batchStocks) > 0 {
-				symbol = batchStocks[0].Symbol
-			}
-			timeframe := engine.GetConfig().Indicators.Klines.PrimaryTimeframe
-			if timeframe == "" {
-				timeframe = "1m"
-			}
-			req, _ := mcp.NewRequestBuilder().
-				WithSystemPrompt(systemPrompt).
-				WithUserPrompt(userPrompt).
-				WithMetadataItem("market_context", batchCtx).
-				WithMetadataItem("symbol", symbol).
-				WithMetadataItem("timeframe", timeframe).
-				WithMetadataItem("question", userPrompt).
-				Build()
-			aiResponse, err = mcpClient.CallWithRequest(req)
-		} else {
-			aiResponse, err = mcpClient.CallWithMessages(systemPrompt, userPrompt)
+		var toolCalls []mcp.ToolCallRecord
+		callBatch := func(client mcp.AIClient) (string, error) {
+			if client.GetProvider() == mcp.ProviderArchitect {
+				symbol := "BTCUSDT"
+				if len(batchStocks) > 0 {
+					symbol = batchStocks[0].Symbol
+				}
+				timeframe := engine.GetConfig().Indicators.Klines.PrimaryTimeframe
+				if timeframe == "" {
+					timeframe = "1m"
+				}
+				req, _ := mcp.NewRequestBuilder().
+					WithSystemPrompt(systemPrompt).
+					WithUserPrompt(userPrompt).
+					WithMetadataItem("market_context", batchCtx).
+					WithMetadataItem("symbol", symbol).
+					WithMetadataItem("timeframe", timeframe).
+					WithMetadataItem("question", userPrompt).
+					Build()
+				return callAIWithDeadline(ctx.Deadline, func() (string, error) {
+					return client.CallWithRequest(req)
+				})
+			}
+			if toolRegistry != nil {
+				// Tool-calling mode: AI may request data before deciding
+				var loopResult *mcp.ToolLoopResult
+				response, err := callAIWithDeadline(ctx.Deadline, func() (string, error) {
+					result, loopErr := mcp.RunToolLoop(client, systemPrompt, userPrompt, toolRegistry, toolBudget)
+					loopResult = result
+					if loopErr != nil {
+						return "", loopErr
+					}
+					return result.Response, nil
+				})
+				if err == nil && loopResult != nil {
+					toolCalls = loopResult.Calls
+					logger.Infof("🔧 Tool-calling: %d tool calls in %d rounds", len(loopResult.Calls), loopResult.Rounds)
+				}
+				return response, err
+			}
+			return callAIWithDeadline(ctx.Deadline, func() (string, error) {
+				return client.CallWithMessages(systemPrompt, userPrompt)
+			})
 		}
+		aiResponse, answeredBy, err := engine.callBatchWithRetry(ctx, mcpClient, fmt.Sprintf("Batch %d/%d", batchNum, totalBatches), callBatch)
 
 		aiCallDuration := time.Since(aiCallStart)
 		totalAIDurationMs += aiCallDuration.Milliseconds()
@@ -365,6 +594,13 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		if needsBatching {
 			logger.Infof("✅ [Batch %d/%d] AI responded in %.1fs", batchNum, totalBatches, float64(aiCallDuration.Milliseconds())/1000)
 		}
+		if answeredBy != mcpClient {
+			allCoTTraces = append(allCoTTraces, fmt.Sprintf("## Batch %d/%d — answered by failover %s/%s", batchNum, totalBatches,
+				answeredBy.GetProvider(), answeredBy.GetModel()))
+		}
+
+		// Read before a repair call replaces the client's last reasoning
+		batchNativeReasoning := mcp.NativeReasoningOf(answeredBy)
 
 		// Parse this batch's response
 		batchDecision, parseErr := parseFullDecisionResponse(
@@ -374,9 +610,55 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 			riskConfig.SmallCapMaxMargin,
 			riskConfig.LargeCapMaxPositionValueRatio,
 			riskConfig.SmallCapMaxPositionValueRatio,
+			PositionLimits{Exchange: ctx.Exchange, Risk: riskConfig, StopRefs: stopRefs, Blackouts: ctx.Blackouts, Positions: ctx.Positions},
 		)
 
+		noJSON := parseErr == nil && isNoJSONFallback(batchDecision)
+		if noJSON && engine.strictParsing() {
+			parseErr = errNoDecisionJSON
+		}
+		if parseErr != nil {
+			captureLLMFixture(answeredBy.GetProvider(), answeredBy.GetModel(), ctx.Account.TotalEquity, aiResponse, parseErr)
+		}
+
+		// One repair attempt of malformed or missing decision JSON before falling back
+		if engine.GetConfig().DecisionParsing.RepairEnabled && (noJSON || repairableParseError(parseErr)) {
+			reason := noJSONFallbackReasoning
+			if parseErr != nil {
+				reason = firstLine(parseErr.Error())
+			}
+			logger.Warnf("🩹 [Batch %d/%d] Decision JSON unusable (%s), requesting repair", batchNum, totalBatches, reason)
+			repaired, repairErr := engine.repairDecisionResponse(ctx, answeredBy, aiResponse, errors.New(reason))
+			var repairedDecision *FullDecision
+			if repairErr == nil {
+				repairedDecision, repairErr = parseFullDecisionResponse(repaired, ctx.Account.TotalEquity,
+					riskConfig.LargeCapMaxMargin, riskConfig.SmallCapMaxMargin,
+					riskConfig.LargeCapMaxPositionValueRatio, riskConfig.SmallCapMaxPositionValueRatio,
+					PositionLimits{Exchange: ctx.Exchange, Risk: riskConfig, StopRefs: stopRefs, Blackouts: ctx.Blackouts, Positions: ctx.Positions})
+			}
+			if repairErr == nil && isNoJSONFallback(repairedDecision) {
+				repairErr = errNoDecisionJSON
+			}
+			if repairErr != nil {
+				logger.Warnf("⚠️  [Batch %d/%d] Decision JSON repair failed: %s", batchNum, totalBatches, firstLine(repairErr.Error()))
+				if parseErr != nil {
+					parseErr = fmt.Errorf("%w (repair failed: %s)", parseErr, firstLine(repairErr.Error()))
+				}
+			} else {
+				logger.Infof("✅ [Batch %d/%d] Decision JSON repaired: %d decisions", batchNum, totalBatches, len(repairedDecision.Decisions))
+				repairedDecision.CoTTrace = batchDecision.CoTTrace + "\n\n(Decision JSON repaired by a second AI call)"
+				batchDecision, parseErr = repairedDecision, nil
+				aiResponse += "\n\n--- Repaired decision JSON ---\n" + repaired
+			}
+		}
+		if parseErr != nil && errors.Is(parseErr, errNoDecisionJSON) && batchDecision != nil {
+			batchDecision.Decisions = nil // Strict mode: no safe wait
+		}
+
 		if batchDecision != nil {
+			validation.Merge(batchDecision.Validation)
+			batchDecision.CoTTrace = withNativeReasoning(batchNativeReasoning, batchDecision.CoTTrace)
+			batchDecision.CoTTrace += formatToolCalls(toolCalls)
 			if batchDecision.CoTTrace != "" {
 				header := fmt.Sprintf("## Batch %d/%d", batchNum, totalBatches)
 				allCoTTraces = append(allCoTTraces, header+"\n"+batchDecision.CoTTrace)
@@ -395,7 +677,14 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		allRawResponses = append(allRawResponses, aiResponse)
 
 		if parseErr != nil && !needsBatching {
-			return batchDecision, fmt.Errorf("failed to parse AI response: %w", parseErr)
+			if batchDecision != nil {
+				// Keep prompts and raw output of the failed response for the record
+				batchDecision.SystemPrompt = systemPrompt
+				batchDecision.UserPrompt = userPrompt
+				batchDecision.RawResponse = aiResponse
+				batchDecision.PromptCompression = compressions
+			}
+			return batchDecision, store.WithErrorCategory(store.ErrorCategoryAIParse, fmt.Errorf("failed to parse AI response: %w", parseErr))
 		} else if parseErr != nil {
 			logger.Warnf("⚠️  [Batch %d/%d] Parse error (non-fatal): %v", batchNum, totalBatches, parseErr)
 		}
@@ -406,6 +695,20 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		return nil, lastErr
 	}
 
+	// Second pass: AI reviews the proposed decisions, only confirmed/amended ones are executed
+	var selfReview *SelfReviewResult
+	if engine.GetConfig().EnableSelfReview {
+		limits := PositionLimits{Exchange: ctx.Exchange, Risk: riskConfig, StopRefs: stopRefs, Blackouts: ctx.Blackouts, Positions: ctx.Positions}
+		allDecisions, selfReview = engine.selfReviewDecisions(ctx, mcpClient, allDecisions, func(d *Decision) error {
+			return validateDecision(d, ctx.Account.TotalEquity, riskConfig.LargeCapMaxMargin, riskConfig.SmallCapMaxMargin,
+				riskConfig.LargeCapMaxPositionValueRatio, riskConfig.SmallCapMaxPositionValueRatio, limits)
+		})
+		if selfReview != nil {
+			totalAIDurationMs += selfReview.DurationMs
+			allCoTTraces = append(allCoTTraces, "## Self-Review\n"+extractCoTTrace(selfReview.RawResponse))
+		}
+	}
+
 	// If no decisions from any batch, add a default wait
 	if len(allDecisions) == 0 {
 		allDecisions = append(allDecisions, Decision{
@@ -415,6 +718,18 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		})
 	}
 
+	// [CODE ENFORCED] Block opens without enough timeframe alignment
+	allDecisions = engine.enforceConfluence(allDecisions, ctx.ConfluenceMap)
+
+	// [CODE ENFORCED] Block all opens in a market shock
+	allDecisions = engine.enforceShock(allDecisions, ctx)
+
+	// [CODE ENFORCED] Scale down new positions in high volatility regime
+	allDecisions = engine.enforceRegime(allDecisions, ctx)
+
+	// [CODE ENFORCED] Scale down new positions while the account is in drawdown
+	allDecisions = engine.enforceEquityRisk(allDecisions, ctx)
+
 	// Merge all batch results into a single FullDecision
 	mergedCoT := strings.Join(allCoTTraces, "\n\n---\n\n")
 	mergedPrompts := strings.Join(allUserPrompts, "\n\n===BATCH SEPARATOR===\n\n")
@@ -433,21 +748,79 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		RawResponse:         mergedRaw,
 		Timestamp:           time.Now(),
 		AIRequestDurationMs: totalAIDurationMs,
+		Validation:          validation,
+		SelfReview:          selfReview,
+		PromptCompression:   compressions,
 	}, nil
 }
 
+// errCycleDeadline returned when AI call does not complete before cycle deadline
+var errCycleDeadline = errors.New("AI cycle deadline exceeded")
+
+// IsAITimeout whether a decision error means the AI did not answer before the cycle deadline
+func IsAITimeout(err error) bool {
+	return errors.Is(err, errCycleDeadline)
+}
+
+// ErrorCategory error category of a decision error: timeout, tagged category (parse, data), else AI API
+func ErrorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	if IsAITimeout(err) {
+		return store.ErrorCategoryTimeout
+	}
+	if category := store.ErrorCategoryOf(err); category != "" {
+		return category
+	}
+	return store.ErrorCategoryAIAPI
+}
+
+// callAIWithDeadline runs AI call, giving up when deadline passes (zero deadline = wait indefinitely)
+// The abandoned call keeps running in background until the client's own request timeout hits;
+// its result is discarded.
+func callAIWithDeadline(deadline time.Time, call func() (string, error)) (string, error) {
+	if deadline.IsZero() {
+		return call()
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return "", errCycleDeadline
+	}
+
+	type result struct {
+		response string
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := call()
+		done <- result{response, err}
+	}()
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-timer.C:
+		return "", errCycleDeadline
+	}
+}
+
 // ============================================================================
 // Market Data Fetching
 // ============================================================================
 
-// fetchMarketDataWithStrategy fetches market data using strategy config (multiple timeframes)
-func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
-	config := engine.GetConfig()
-	ctx.MarketDataMap = make(map[string]*market.Data)
+// defaultMaxConcurrentFetches symbols fetched in parallel when KlineConfig.MaxConcurrentFetches is unset
+const defaultMaxConcurrentFetches = 8
 
-	timeframes := config.Indicators.Klines.SelectedTimeframes
-	primaryTimeframe := config.Indicators.Klines.PrimaryTimeframe
-	klineCount := config.Indicators.Klines.PrimaryCount
+// fetchMarketDataWithStrategy fetches market data using strategy config (multiple timeframes)
+// klineTimeframes timeframes fetched per symbol, the primary timeframe and its kline count (defaults applied)
+func klineTimeframes(config *store.StrategyConfig) (timeframes []string, primaryTimeframe string, klineCount int) {
+	timeframes = append(timeframes, config.Indicators.Klines.SelectedTimeframes...)
+	primaryTimeframe = config.Indicators.Klines.PrimaryTimeframe
+	klineCount = config.Indicators.Klines.PrimaryCount
 
 	// Compatible with old configuration
 	if len(timeframes) == 0 {
@@ -481,97 +854,155 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 	if klineCount <= 0 {
 		klineCount = 30
 	}
+	return timeframes, primaryTimeframe, klineCount
+}
 
+func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
+	config := engine.GetConfig()
+	ctx.MarketDataMap = make(map[string]*market.Data)
+
+	timeframes, primaryTimeframe, klineCount := klineTimeframes(config)
 	logger.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
 
-	// Helper function to detect if symbol is a stock (vs crypto)
-	// Stocks: TSLA, AAPL, DJT, ONDS (no USDT suffix)
-	// Crypto: BTCUSDT, ETHUSDT (has USDT suffix)
-	isStockSymbol := func(symbol string) bool {
-		symbol = strings.ToUpper(symbol)
-		// If it ends with USDT, USD, or other crypto suffixes, it's crypto
-		if strings.HasSuffix(symbol, "USDT") || strings.HasSuffix(symbol, "BUSD") ||
-			strings.HasSuffix(symbol, "USDC") || strings.HasSuffix(symbol, "BTC") ||
-			strings.HasSuffix(symbol, "ETH") {
-			return false
-		}
-		// If it's all letters (no digits) and 1-5 chars, likely a stock ticker
-		if len(symbol) <= 5 {
-			for _, r := range symbol {
-				if r < 'A' || r > 'Z' {
-					return false
-				}
-			}
-			return true
-		}
-		return false
+	maxWorkers := config.Indicators.Klines.MaxConcurrentFetches
+	if maxWorkers <= 0 {
+		maxWorkers = defaultMaxConcurrentFetches
 	}
-
-	// 1. First fetch data for position stocks (must fetch)
-	for _, pos := range ctx.Positions {
-		var data *market.Data
-		var err error
-
-		if isStockSymbol(pos.Symbol) {
-			data, err = market.GetStockDataWithTimeframes(pos.Symbol, timeframes, primaryTimeframe, klineCount)
-		} else {
-			data, err = market.GetWithTimeframes(pos.Symbol, timeframes, primaryTimeframe, klineCount)
+	fetch := func(symbol string) (*market.Data, error) {
+		if market.UsesAlpacaData(ctx.Exchange, symbol) {
+			return market.GetStockDataWithTimeframes(symbol, timeframes, primaryTimeframe, klineCount)
 		}
-
-		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
-			continue
+		data, err := market.GetWithTimeframes(symbol, timeframes, primaryTimeframe, klineCount)
+		if err == nil && config.Indicators.EnableOrderBook {
+			if book, bookErr := market.GetOrderBook(symbol, config.Indicators.OrderBookLevels); bookErr != nil {
+				logger.Infof("⚠️  %s order book unavailable: %v", symbol, bookErr)
+			} else {
+				data.OrderBook = book
+			}
 		}
-		ctx.MarketDataMap[pos.Symbol] = data
+		return data, err
 	}
 
-	// 2. Fetch data for all candidate stocks
+	// 1. Collect position stocks (must fetch) first, then candidate stocks
 	positionSymbols := make(map[string]bool)
+	var symbols []string
 	for _, pos := range ctx.Positions {
-		positionSymbols[pos.Symbol] = true
+		if !positionSymbols[pos.Symbol] {
+			positionSymbols[pos.Symbol] = true
+			symbols = append(symbols, pos.Symbol)
+		}
+	}
+	queued := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		queued[symbol] = true
 	}
-
-	const minOIThresholdMillions = 15.0 // 15M USD minimum open interest value (only for crypto)
-
 	for _, stock := range ctx.CandidateStocks {
-		if _, exists := ctx.MarketDataMap[stock.Symbol]; exists {
-			continue
+		if !queued[stock.Symbol] {
+			queued[stock.Symbol] = true
+			symbols = append(symbols, stock.Symbol)
 		}
+	}
 
-		var data *market.Data
-		var err error
+	// 2. Fetch concurrently, then apply results in input order
+	fetchStart := time.Now()
+	results := fetchMarketDataConcurrently(symbols, maxWorkers, fetch)
+	fetchErrors := applyMarketDataFilters(ctx, config, symbols, results, positionSymbols)
 
-		isStock := isStockSymbol(stock.Symbol)
-		if isStock {
-			data, err = market.GetStockDataWithTimeframes(stock.Symbol, timeframes, primaryTimeframe, klineCount)
-		} else {
-			data, err = market.GetWithTimeframes(stock.Symbol, timeframes, primaryTimeframe, klineCount)
-		}
+	if len(fetchErrors) > 0 {
+		logger.Infof("⚠️  Failed to fetch market data for %d/%d stocks: %s",
+			len(fetchErrors), len(symbols), strings.Join(fetchErrors, "; "))
+	}
+	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d stocks in %.1fs (%d workers)",
+		len(ctx.MarketDataMap), time.Since(fetchStart).Seconds(), maxWorkers)
+	return nil
+}
+
+// applyMarketDataFilters stores fetched data of symbols passing the liquidity and data quality filters in ctx.MarketDataMap
+// Dropped candidates are listed in ctx.FilteredOut; held positions are never filtered. Returns the fetch errors.
+func applyMarketDataFilters(ctx *Context, config *store.StrategyConfig, symbols []string, results []marketFetchResult, positionSymbols map[string]bool) []string {
+	// Candidate sources decide the liquidity threshold (strategy MinOIValueUSD and per-source overrides)
+	candidateSources := make(map[string][]string, len(ctx.CandidateStocks))
+	for _, stock := range ctx.CandidateStocks {
+		candidateSources[stock.Symbol] = append(candidateSources[stock.Symbol], stock.Sources...)
+	}
+	filterOut := func(symbol, reason, detail string) {
+		ctx.FilteredOut = append(ctx.FilteredOut, store.FilteredSymbol{Symbol: symbol, Reason: reason, Detail: detail})
+	}
 
+	var fetchErrors []string
+	for i, symbol := range symbols {
+		data, err := results[i].data, results[i].err
 		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for %s: %v", stock.Symbol, err)
+			fetchErrors = append(fetchErrors, fmt.Sprintf("%s (%v)", symbol, err))
+			ctx.addDataGap(symbol, store.DataFeedKlines, err.Error())
+			if !positionSymbols[symbol] {
+				filterOut(symbol, store.FilterReasonFetchFailed, err.Error())
+			}
 			continue
 		}
 
-		// Liquidity filter (only for crypto, stocks don't have OI)
-		if !isStock {
-			isExistingPosition := positionSymbols[stock.Symbol]
-			if !isExistingPosition && data.OpenInterest != nil && data.CurrentPrice > 0 {
-				oiValue := data.OpenInterest.Latest * data.CurrentPrice
-				oiValueInMillions := oiValue / 1_000_000
-				if oiValueInMillions < minOIThresholdMillions {
-					logger.Infof("⚠️  %s OI value too low (%.2fM USD < %.1fM), skipping stock",
-						stock.Symbol, oiValueInMillions, minOIThresholdMillions)
-					continue
+		// Liquidity filter (only for crypto candidates, stocks don't have OI)
+		if !market.IsStock(symbol) && !positionSymbols[symbol] && data.OpenInterest != nil && data.CurrentPrice > 0 {
+			sources := candidateSources[symbol]
+			oiValue := data.OpenInterest.Latest * data.CurrentPrice
+			if minOI := config.CoinSource.MinOIValueFor(sources); minOI > 0 && oiValue < minOI {
+				detail := fmt.Sprintf("OI %.2fM < %.2fM USD", oiValue/1_000_000, minOI/1_000_000)
+				if len(sources) > 0 {
+					detail += " (" + strings.Join(sources, "+") + ")"
 				}
+				logger.Infof("⚠️  %s OI value too low: %s, skipping stock", symbol, detail)
+				filterOut(symbol, store.FilterReasonLowOI, detail)
+				continue
 			}
 		}
 
-		ctx.MarketDataMap[stock.Symbol] = data
+		// Data quality filter (missing/duplicate/out-of-order bars), held positions are always kept
+		if minQuality := config.Indicators.Klines.MinDataQuality; minQuality > 0 && !positionSymbols[symbol] &&
+			data.Quality != nil && data.Quality.Score < minQuality {
+			logger.Infof("⚠️  %s data quality too low (%s < %.2f), skipping stock", symbol, data.Quality.Summary(), minQuality)
+			filterOut(symbol, store.FilterReasonDataQuality, fmt.Sprintf("%s < %.2f", data.Quality.Summary(), minQuality))
+			continue
+		}
+
+		ctx.MarketDataMap[symbol] = data
+		ctx.addMarketDataGaps(symbol, data, config)
 	}
+	return fetchErrors
+}
 
-	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d stocks", len(ctx.MarketDataMap))
-	return nil
+// marketFetchResult market data fetch result of one symbol
+type marketFetchResult struct {
+	data *market.Data
+	err  error
+}
+
+// fetchMarketDataConcurrently fetches symbols with at most maxWorkers requests in flight
+// Results are indexed like symbols; a failed symbol does not abort the others.
+func fetchMarketDataConcurrently(symbols []string, maxWorkers int, fetch func(string) (*market.Data, error)) []marketFetchResult {
+	results := make([]marketFetchResult, len(symbols))
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxWorkers) // Limit concurrency
+	for i, symbol := range symbols {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, symbol string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			data, err := fetch(symbol)
+			if err == nil && data == nil {
+				err = fmt.Errorf("no data returned")
+			}
+			results[i] = marketFetchResult{data: data, err: err}
+		}(i, symbol)
+	}
+	wg.Wait()
+	return results
 }
 
 // ============================================================================
@@ -585,8 +1016,8 @@ func (e *StrategyEngine) GetCandidateStocks() ([]CandidateStock, error) {
 
 	stockSource := e.config.CoinSource
 
-	if stockSource.CoinPoolAPIURL != "" {
-		provider.SetCoinPoolAPI(stockSource.CoinPoolAPIURL)
+	if stockSource.StockPoolAPIURL != "" {
+		provider.SetCoinPoolAPI(stockSource.StockPoolAPIURL)
 	}
 	if stockSource.OITopAPIURL != "" {
 		provider.SetOITopAPI(stockSource.OITopAPIURL)
@@ -594,13 +1025,9 @@ func (e *StrategyEngine) GetCandidateStocks() ([]CandidateStock, error) {
 
 	switch stockSource.SourceType {
 	case "static":
-		// Support both StaticStocks (new stock trading) and StaticCoins (legacy crypto)
-		staticSymbols := stockSource.StaticStocks
-		logger.Infof("📊 GetCandidateStocks: StaticStocks=%v, StaticCoins=%v", stockSource.StaticStocks, stockSource.StaticCoins)
-		if len(staticSymbols) == 0 {
-			staticSymbols = stockSource.StaticCoins // fallback to legacy field
-		}
-		for _, symbol := range staticSymbols {
+		// Legacy StaticCoins are moved to StaticStocks by StrategyConfig.Migrate
+		logger.Infof("📊 GetCandidateStocks: StaticStocks=%v", stockSource.StaticStocks)
+		for _, symbol := range stockSource.StaticStocks {
 			symbol = market.Normalize(symbol)
 			candidates = append(candidates, CandidateStock{
 				Symbol:  symbol,
@@ -610,7 +1037,7 @@ func (e *StrategyEngine) GetCandidateStocks() ([]CandidateStock, error) {
 		return candidates, nil
 
 	case "coinpool", "stockpool": // stockpool is the stock trading alias
-		return e.getStockPoolStocks(stockSource.CoinPoolLimit)
+		return e.getStockPoolStocks(stockSource.StockPoolLimit)
 
 	case "ai100":
 		return e.getAI100Stocks(stockSource.AI100Limit)
@@ -624,15 +1051,18 @@ func (e *StrategyEngine) GetCandidateStocks() ([]CandidateStock, error) {
 	case "top_losers":
 		return e.getTopLosersStocks(stockSource.TopLosersLimit)
 
+	case "funding_arb":
+		return e.getFundingArbStocks(stockSource.FundingArbLimit)
+
+	case "webhook":
+		return e.getWebhookStocks(stockSource.WebhookLimit)
+
+	case "social_trending":
+		return e.getSocialTrendingStocks(stockSource.SocialTrendingLimit)
+
 	case "mixed":
-		// Check both UseCoinPool (legacy) and UseStockPool (new stock trading)
-		usePool := stockSource.UseCoinPool || stockSource.UseStockPool
-		poolLimit := stockSource.CoinPoolLimit
-		if poolLimit == 0 {
-			poolLimit = stockSource.StockPoolLimit
-		}
-		if usePool {
-			poolStocks, err := e.getStockPoolStocks(poolLimit)
+		if stockSource.UseStockPool {
+			poolStocks, err := e.getStockPoolStocks(stockSource.StockPoolLimit)
 			if err != nil {
 				logger.Infof("⚠️  Failed to get AI500 pool: %v", err)
 			} else {
@@ -687,12 +1117,40 @@ func (e *StrategyEngine) GetCandidateStocks() ([]CandidateStock, error) {
 			}
 		}
 
-		// Support both StaticStocks (new stock trading) and StaticCoins (legacy crypto)
-		mixedStaticSymbols := stockSource.StaticStocks
-		if len(mixedStaticSymbols) == 0 {
-			mixedStaticSymbols = stockSource.StaticCoins // fallback to legacy field
+		if stockSource.UseFundingArb {
+			fundingStocks, err := e.getFundingArbStocks(stockSource.FundingArbLimit)
+			if err != nil {
+				logger.Infof("⚠️  Failed to get Funding Arb: %v", err)
+			} else {
+				for _, stock := range fundingStocks {
+					symbolSources[stock.Symbol] = append(symbolSources[stock.Symbol], "funding_arb")
+				}
+			}
+		}
+
+		if stockSource.UseWebhook {
+			webhookStocks, err := e.getWebhookStocks(stockSource.WebhookLimit)
+			if err != nil {
+				logger.Infof("⚠️  Failed to get Webhook candidates: %v", err)
+			} else {
+				for _, stock := range webhookStocks {
+					symbolSources[stock.Symbol] = append(symbolSources[stock.Symbol], "webhook")
+				}
+			}
 		}
-		for _, symbol := range mixedStaticSymbols {
+
+		if stockSource.UseSocialTrending {
+			socialStocks, err := e.getSocialTrendingStocks(stockSource.SocialTrendingLimit)
+			if err != nil {
+				logger.Infof("⚠️  Failed to get Social Trending: %v", err)
+			} else {
+				for _, stock := range socialStocks {
+					symbolSources[stock.Symbol] = append(symbolSources[stock.Symbol], "social_trending")
+				}
+			}
+		}
+
+		for _, symbol := range stockSource.StaticStocks {
 			symbol = market.Normalize(symbol)
 			if _, exists := symbolSources[symbol]; !exists {
 				symbolSources[symbol] = []string{"static"}
@@ -935,7 +1393,7 @@ func extractJSONPath(data interface{}, path string) interface{} {
 	return current
 }
 
-// FetchQuantData fetches quantitative data for a single stock
+// FetchQuantData fetches quantitative data for a single stock (cached, concurrent requests coalesced)
 func (e *StrategyEngine) FetchQuantData(symbol string) (*QuantData, error) {
 	if !e.config.Indicators.EnableQuantData || e.config.Indicators.QuantDataAPIURL == "" {
 		return nil, nil
@@ -944,6 +1402,17 @@ func (e *StrategyEngine) FetchQuantData(symbol string) (*QuantData, error) {
 	apiURL := e.config.Indicators.QuantDataAPIURL
 	url := strings.Replace(apiURL, "{symbol}", symbol, -1)
 
+	ttl := time.Duration(e.config.Indicators.QuantDataCacheTTLSec) * time.Second
+	if e.config.Indicators.QuantDataCacheTTLSec <= 0 {
+		ttl = defaultQuantDataCacheTTL
+	}
+	return quantCache.get(url, ttl, func() (*QuantData, error) {
+		return fetchQuantDataFromAPI(url)
+	})
+}
+
+// fetchQuantDataFromAPI requests quantitative data from the configured API
+func fetchQuantDataFromAPI(url string) (*QuantData, error) {
 	// SSRF Protection: Validate URL before making request
 	resp, err := security.SafeGet(url, 10*time.Second)
 	if err != nil {
@@ -978,24 +1447,56 @@ func (e *StrategyEngine) FetchQuantData(symbol string) (*QuantData, error) {
 
 // FetchQuantDataBatch batch fetches quantitative data
 func (e *StrategyEngine) FetchQuantDataBatch(symbols []string) map[string]*QuantData {
+	result, _ := e.FetchQuantDataBatchWithErrors(symbols)
+	return result
+}
+
+// FetchQuantDataBatchWithErrors batch fetches quantitative data, also returns the error of each failed symbol
+// At most QuantDataMaxConcurrent requests are in flight to respect the provider's rate limit.
+func (e *StrategyEngine) FetchQuantDataBatchWithErrors(symbols []string) (map[string]*QuantData, map[string]error) {
 	result := make(map[string]*QuantData)
+	errs := make(map[string]error)
 
 	if !e.config.Indicators.EnableQuantData || e.config.Indicators.QuantDataAPIURL == "" {
-		return result
+		return result, errs
 	}
 
+	maxWorkers := e.config.Indicators.QuantDataMaxConcurrent
+	if maxWorkers <= 0 {
+		maxWorkers = defaultQuantDataMaxConcurrent
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	semaphore := make(chan struct{}, maxWorkers) // Limit concurrency
 	for _, symbol := range symbols {
-		data, err := e.FetchQuantData(symbol)
-		if err != nil {
-			logger.Infof("⚠️  Failed to fetch quantitative data for %s: %v", symbol, err)
-			continue
-		}
-		if data != nil {
-			result[symbol] = data
-		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			data, err := e.FetchQuantData(symbol)
+			if err != nil {
+				logger.Infof("⚠️  Failed to fetch quantitative data for %s: %v", symbol, err)
+				mu.Lock()
+				errs[symbol] = err
+				mu.Unlock()
+				return
+			}
+			if data != nil {
+				mu.Lock()
+				result[symbol] = data
+				mu.Unlock()
+			}
+		}(symbol)
 	}
+	wg.Wait()
 
-	return result
+	return result, errs
 }
 
 // FetchOIRankingData fetches market-wide OI ranking data
@@ -1005,20 +1506,10 @@ func (e *StrategyEngine) FetchOIRankingData() *provider.OIRankingData {
 		return nil
 	}
 
-	baseURL := indicators.OIRankingAPIURL
-	if baseURL == "" {
-		baseURL = "http://172.22.189.252:30006"
-	}
-
-	// Get auth key from existing API URL or use default
-	authKey := "cm_568c67eae410d912c54c"
-	if indicators.QuantDataAPIURL != "" {
-		if idx := strings.Index(indicators.QuantDataAPIURL, "auth="); idx != -1 {
-			authKey = indicators.QuantDataAPIURL[idx+5:]
-			if ampIdx := strings.Index(authKey, "&"); ampIdx != -1 {
-				authKey = authKey[:ampIdx]
-			}
-		}
+	source, err := e.oiRankingProvider()
+	if err != nil {
+		logger.Warnf("⚠️  OI ranking source unavailable, skipping OI ranking data: %v", err)
+		return nil
 	}
 
 	duration := indicators.OIRankingDuration
@@ -1033,7 +1524,7 @@ func (e *StrategyEngine) FetchOIRankingData() *provider.OIRankingData {
 
 	logger.Infof("📊 Fetching OI ranking data (duration: %s, limit: %d)", duration, limit)
 
-	data, err := provider.GetOIRankingData(baseURL, authKey, duration, limit)
+	data, err := provider.GetOIRankingData(source, duration, limit)
 	if err != nil {
 		logger.Warnf("⚠️  Failed to fetch OI ranking data: %v", err)
 		return nil
@@ -1045,12 +1536,52 @@ func (e *StrategyEngine) FetchOIRankingData() *provider.OIRankingData {
 	return data
 }
 
+// oiRankingProvider OI ranking source of the strategy: a registered provider or the HTTP API at oi_ranking_api_url
+func (e *StrategyEngine) oiRankingProvider() (provider.OIRankingProvider, error) {
+	indicators := e.config.Indicators
+	source := indicators.OIRankingSource
+	if source.Provider != "" {
+		p, ok := provider.GetOIRankingProvider(source.Provider)
+		if !ok {
+			return nil, fmt.Errorf("OI ranking provider %q not registered", source.Provider)
+		}
+		return p, nil
+	}
+
+	apiKey := source.APIKey
+	if apiKey == "" && source.AuthScheme == "" {
+		// Strategies predating oi_ranking_source share the auth key of the quant data API
+		if idx := strings.Index(indicators.QuantDataAPIURL, "auth="); idx != -1 {
+			apiKey = indicators.QuantDataAPIURL[idx+5:]
+			if ampIdx := strings.Index(apiKey, "&"); ampIdx != -1 {
+				apiKey = apiKey[:ampIdx]
+			}
+		}
+	}
+	return provider.NewHTTPOIRankingProvider(provider.OIRankingHTTPConfig{
+		BaseURL:       indicators.OIRankingAPIURL,
+		TopPath:       source.TopPath,
+		LowPath:       source.LowPath,
+		AuthScheme:    source.AuthScheme,
+		AuthName:      source.AuthName,
+		APIKey:        apiKey,
+		PositionsPath: source.PositionsPath,
+		TimeRangePath: source.TimeRangePath,
+		FieldMap:      source.FieldMap,
+	})
+}
+
 // ============================================================================
 // Prompt Building - System Prompt
 // ============================================================================
 
 // BuildSystemPrompt builds System Prompt according to strategy configuration
 func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string) string {
+	return e.buildSystemPrompt(accountEquity, variant, false)
+}
+
+// buildSystemPrompt builds System Prompt, with an optional <reasoning> block when the model's native reasoning is captured
+func (e *StrategyEngine) buildSystemPrompt(accountEquity float64, variant string, nativeReasoning bool) string {
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
 	promptSections := e.config.PromptSections
@@ -1061,18 +1592,18 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		sb.WriteString(promptSections.RoleDefinition)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# You are a professional stock trading AI\n\n")
-		sb.WriteString("Your task is to make trading decisions based on provided market data.\n\n")
+		sb.WriteString(e.t("# You are a professional stock trading AI\n\n"))
+		sb.WriteString(e.t("Your task is to make trading decisions based on provided market data.\n\n"))
 	}
 
 	// 2. Trading mode variant
 	switch strings.ToLower(strings.TrimSpace(variant)) {
 	case "aggressive":
-		sb.WriteString("## Mode: Aggressive\n- Prioritize capturing trend breakouts, can build positions in batches when confidence ≥ 70\n- Allow higher positions, but must strictly set stop-loss and explain risk-reward ratio\n\n")
+		sb.WriteString(e.t("## Mode: Aggressive\n- Prioritize capturing trend breakouts, can build positions in batches when confidence ≥ 70\n- Allow higher positions, but must strictly set stop-loss and explain risk-reward ratio\n\n"))
 	case "conservative":
-		sb.WriteString("## Mode: Conservative\n- Only open positions when multiple signals resonate\n- Prioritize cash preservation, must pause for multiple periods after consecutive losses\n\n")
+		sb.WriteString(e.t("## Mode: Conservative\n- Only open positions when multiple signals resonate\n- Prioritize cash preservation, must pause for multiple periods after consecutive losses\n\n"))
 	case "scalping":
-		sb.WriteString("## Mode: Scalping\n- Focus on short-term momentum, smaller profit targets but require quick action\n- If price doesn't move as expected within two bars, immediately reduce position or stop-loss\n\n")
+		sb.WriteString(e.t("## Mode: Scalping\n- Focus on short-term momentum, smaller profit targets but require quick action\n- If price doesn't move as expected within two bars, immediately reduce position or stop-loss\n\n"))
 	}
 
 	// 3. Hard constraints (risk control)
@@ -1085,55 +1616,75 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		smallCapPosValueRatio = 1.0
 	}
 
-	sb.WriteString("# Hard Constraints (Risk Control)\n\n")
-	sb.WriteString("## CODE ENFORCED (Backend validation, cannot be bypassed):\n")
-	sb.WriteString(fmt.Sprintf("- Max Positions: %d stocks simultaneously\n", riskControl.MaxPositions))
-	sb.WriteString(fmt.Sprintf("- Position Value Limit (Small Caps): max %.0f USD (= equity %.0f × %.1fx)\n",
+	sb.WriteString(e.t("# Hard Constraints (Risk Control)\n\n"))
+	sb.WriteString(e.t("## CODE ENFORCED (Backend validation, cannot be bypassed):\n"))
+	sb.WriteString(fmt.Sprintf(e.t("- Max Positions: %d stocks simultaneously\n"), riskControl.MaxPositions))
+	sb.WriteString(fmt.Sprintf(e.t("- Position Value Limit (Small Caps): max %.0f USD (= equity %.0f × %.1fx)\n"),
 		accountEquity*smallCapPosValueRatio, accountEquity, smallCapPosValueRatio))
-	sb.WriteString(fmt.Sprintf("- Position Value Limit (Large Cap): max %.0f USD (= equity %.0f × %.1fx)\n",
+	sb.WriteString(fmt.Sprintf(e.t("- Position Value Limit (Large Cap): max %.0f USD (= equity %.0f × %.1fx)\n"),
 		accountEquity*largeCapPosValueRatio, accountEquity, largeCapPosValueRatio))
-	sb.WriteString(fmt.Sprintf("- Max Margin Usage: ≤%.0f%%\n", riskControl.MaxMarginUsage*100))
-	sb.WriteString(fmt.Sprintf("- Min Position Size: ≥%.0f USD\n\n", riskControl.MinPositionSize))
+	sb.WriteString(fmt.Sprintf(e.t("- Max Margin Usage: ≤%.0f%%\n"), riskControl.MaxMarginUsage*100))
+	minLimits := PositionLimits{Risk: riskControl}
+	largeCapSymbols := riskControl.LargeCapSymbols
+	if len(largeCapSymbols) == 0 {
+		largeCapSymbols = store.DefaultLargeCapSymbols
+	}
+	sb.WriteString(fmt.Sprintf(e.t("- Min Position Size: Small Caps ≥%.0f USD | Large Cap ≥%.0f USD\n"),
+		minLimits.MinPositionSize(""), minLimits.MinPositionSize(largeCapSymbols[0])))
+	sb.WriteString(fmt.Sprintf(e.t("- Large Cap symbols: %s (all others are Small Caps)\n"), strings.Join(largeCapSymbols, ", ")))
+	if riskControl.StopATRMinMultiple > 0 || riskControl.StopATRMaxMultiple > 0 {
+		sb.WriteString(fmt.Sprintf(e.t("- Stop Loss Distance: %.1f-%.1f × ATR(14) from current price (stops outside are auto-adjusted)\n"),
+			riskControl.StopATRMinMultiple, riskControl.StopATRMaxMultiple))
+	}
+	if enabled, bufferPct, _ := riskControl.LiquidationGuard(); enabled {
+		sb.WriteString(fmt.Sprintf(e.t("- Liquidation Guard (leveraged crypto): stop loss must be more than %.1f%% of entry in front of the liquidation price (≈ 1/leverage from entry), otherwise the open is rejected\n"),
+			bufferPct))
+	}
+	if overrides := formatSymbolOverrides(riskControl); overrides != "" {
+		sb.WriteString(e.t("- Per-symbol limits (replace the limits above for these symbols; opens below the confidence minimum are rejected):\n"))
+		sb.WriteString(overrides)
+	}
+	sb.WriteString("\n")
 
-	sb.WriteString("## AI GUIDED (Recommended, you should follow):\n")
-	sb.WriteString(fmt.Sprintf("- Trading Leverage: Small Caps max %dx | Large Cap max %dx\n",
+	sb.WriteString(e.t("## AI GUIDED (Recommended, you should follow):\n"))
+	sb.WriteString(fmt.Sprintf(e.t("- Trading Leverage: Small Caps max %dx | Large Cap max %dx\n"),
 		riskControl.SmallCapMaxMargin, riskControl.LargeCapMaxMargin))
-	sb.WriteString(fmt.Sprintf("- Risk-Reward Ratio: ≥1:%.1f (take_profit / stop_loss)\n", riskControl.MinRiskRewardRatio))
-	sb.WriteString(fmt.Sprintf("- Min Confidence: ≥%d to open position\n\n", riskControl.MinConfidence))
+	sb.WriteString(fmt.Sprintf(e.t("- Risk-Reward Ratio: ≥1:%.1f (take_profit / stop_loss)\n"), riskControl.MinRiskRewardRatio))
+	sb.WriteString(fmt.Sprintf(e.t("- Min Confidence: ≥%d to open position\n\n"), riskControl.MinConfidence))
 
 	// Position sizing guidance
-	sb.WriteString("## Position Sizing Guidance\n")
-	sb.WriteString("Calculate `position_size_usd` based on your confidence and the Position Value Limits above:\n")
-	sb.WriteString("- High confidence (≥85): Use 80-100%% of max position value limit\n")
-	sb.WriteString("- Medium confidence (70-84): Use 50-80%% of max position value limit\n")
-	sb.WriteString("- Low confidence (60-69): Use 30-50%% of max position value limit\n")
-	sb.WriteString(fmt.Sprintf("- Example: With equity %.0f and Large Cap ratio %.1fx, max is %.0f USD\n",
+	sb.WriteString(e.t("## Position Sizing Guidance\n"))
+	sb.WriteString(e.t("Calculate `position_size_usd` based on your confidence and the Position Value Limits above:\n"))
+	sb.WriteString(e.t("- High confidence (≥85): Use 80-100%% of max position value limit\n"))
+	sb.WriteString(e.t("- Medium confidence (70-84): Use 50-80%% of max position value limit\n"))
+	sb.WriteString(e.t("- Low confidence (60-69): Use 30-50%% of max position value limit\n"))
+	sb.WriteString(fmt.Sprintf(e.t("- Example: With equity %.0f and Large Cap ratio %.1fx, max is %.0f USD\n"),
 		accountEquity, largeCapPosValueRatio, accountEquity*largeCapPosValueRatio))
-	sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n")
+	sb.WriteString(e.t("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n"))
 
 	// 4. Trading frequency (editable)
 	if promptSections.TradingFrequency != "" {
 		sb.WriteString(promptSections.TradingFrequency)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# ⏱️ Trading Frequency Awareness\n\n")
-		sb.WriteString("- Excellent traders: 2-4 trades/day ≈ 0.1-0.2 trades/hour\n")
-		sb.WriteString("- >2 trades/hour = Overtrading\n")
-		sb.WriteString("- Single position hold time ≥ 30-60 minutes\n")
-		sb.WriteString("If you find yourself trading every period → standards too low; if closing positions < 30 minutes → too impatient.\n\n")
+		sb.WriteString(e.t("# ⏱️ Trading Frequency Awareness\n\n"))
+		sb.WriteString(e.t("- Excellent traders: 2-4 trades/day ≈ 0.1-0.2 trades/hour\n"))
+		sb.WriteString(e.t("- >2 trades/hour = Overtrading\n"))
+		sb.WriteString(e.t("- Single position hold time ≥ 30-60 minutes\n"))
+		sb.WriteString(e.t("If you find yourself trading every period → standards too low; if closing positions < 30 minutes → too impatient.\n\n"))
 	}
 
 	// 5. Entry standards (editable)
 	if promptSections.EntryStandards != "" {
 		sb.WriteString(promptSections.EntryStandards)
-		sb.WriteString("\n\nYou have the following indicator data:\n")
+		sb.WriteString(e.t("\n\nYou have the following indicator data:\n"))
 		e.writeAvailableIndicators(&sb)
-		sb.WriteString(fmt.Sprintf("\n**Confidence ≥ %d** required to open positions.\n\n", riskControl.MinConfidence))
+		sb.WriteString(fmt.Sprintf(e.t("\n**Confidence ≥ %d** required to open positions.\n\n"), riskControl.MinConfidence))
 	} else {
-		sb.WriteString("# 🎯 Entry Standards (Strict)\n\n")
-		sb.WriteString("Only open positions when multiple signals resonate. You have:\n")
+		sb.WriteString(e.t("# 🎯 Entry Standards (Strict)\n\n"))
+		sb.WriteString(e.t("Only open positions when multiple signals resonate. You have:\n"))
 		e.writeAvailableIndicators(&sb)
-		sb.WriteString(fmt.Sprintf("\nFeel free to use any effective analysis method, but **confidence ≥ %d** required to open positions; avoid low-quality behaviors such as single indicators, contradictory signals, sideways consolidation, reopening immediately after closing, etc.\n\n", riskControl.MinConfidence))
+		sb.WriteString(fmt.Sprintf(e.t("\nFeel free to use any effective analysis method, but **confidence ≥ %d** required to open positions; avoid low-quality behaviors such as single indicators, contradictory signals, sideways consolidation, reopening immediately after closing, etc.\n\n"), riskControl.MinConfidence))
 	}
 
 	// 6. Decision process (editable)
@@ -1141,30 +1692,34 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		sb.WriteString(promptSections.DecisionProcess)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# 📋 Decision Process\n\n")
-		sb.WriteString("1. Check positions → Should we take profit/stop-loss\n")
-		sb.WriteString("2. Scan candidate stocks + multi-timeframe → Are there strong signals\n")
-		sb.WriteString("3. Write chain of thought first, then output structured JSON\n\n")
+		sb.WriteString(e.t("# 📋 Decision Process\n\n"))
+		sb.WriteString(e.t("1. Check positions → Should we take profit/stop-loss\n"))
+		sb.WriteString(e.t("2. Scan candidate stocks + multi-timeframe → Are there strong signals\n"))
+		sb.WriteString(e.t("3. Write chain of thought first, then output structured JSON\n\n"))
 	}
 
 	// 7. Output format - CRITICAL: Must use exact XML tags
-	sb.WriteString("# ⚠️ OUTPUT FORMAT (CRITICAL - MUST FOLLOW EXACTLY)\n\n")
-	sb.WriteString("**YOUR RESPONSE MUST START WITH `<reasoning>` TAG AND END WITH `</decision>` TAG**\n\n")
-	sb.WriteString("## MANDATORY Structure (Copy This Exactly):\n\n")
+	sb.WriteString(e.t("# ⚠️ OUTPUT FORMAT (CRITICAL - MUST FOLLOW EXACTLY)\n\n"))
+	if nativeReasoning {
+		sb.WriteString(e.t("**Your native reasoning is recorded as the chain of thought: the `<reasoning>` block is optional (a short summary at most). YOUR RESPONSE MUST END WITH `</decision>` TAG**\n\n"))
+	} else {
+		sb.WriteString(e.t("**YOUR RESPONSE MUST START WITH `<reasoning>` TAG AND END WITH `</decision>` TAG**\n\n"))
+	}
+	sb.WriteString(e.t("## MANDATORY Structure (Copy This Exactly):\n\n"))
 	sb.WriteString("```\n")
 	sb.WriteString("<reasoning>\n")
-	sb.WriteString("## Chain of Thought Analysis\n\n")
-	sb.WriteString("### 1. Account & Risk Assessment\n")
-	sb.WriteString("- Current equity: $XXX\n")
-	sb.WriteString("- Available margin: $XXX\n")
-	sb.WriteString("- Open positions: X\n\n")
-	sb.WriteString("### 2. Stock-by-Stock Analysis\n")
-	sb.WriteString("For each candidate stock, analyze:\n")
-	sb.WriteString("- **SYMBOL**: Price action, trend direction, key levels\n")
-	sb.WriteString("- Indicators: RSI, MACD, Volume signals\n")
-	sb.WriteString("- Decision: BUY/SELL/WAIT and why\n\n")
-	sb.WriteString("### 3. Final Decision Summary\n")
-	sb.WriteString("- Selected trades and reasoning\n")
+	sb.WriteString(e.t("## Chain of Thought Analysis\n\n"))
+	sb.WriteString(e.t("### 1. Account & Risk Assessment\n"))
+	sb.WriteString(e.t("- Current equity: $XXX\n"))
+	sb.WriteString(e.t("- Available margin: $XXX\n"))
+	sb.WriteString(e.t("- Open positions: X\n\n"))
+	sb.WriteString(e.t("### 2. Stock-by-Stock Analysis\n"))
+	sb.WriteString(e.t("For each candidate stock, analyze:\n"))
+	sb.WriteString(e.t("- **SYMBOL**: Price action, trend direction, key levels\n"))
+	sb.WriteString(e.t("- Indicators: RSI, MACD, Volume signals\n"))
+	sb.WriteString(e.t("- Decision: BUY/SELL/WAIT and why\n\n"))
+	sb.WriteString(e.t("### 3. Final Decision Summary\n"))
+	sb.WriteString(e.t("- Selected trades and reasoning\n"))
 	sb.WriteString("</reasoning>\n\n")
 	sb.WriteString("<decision>\n")
 	sb.WriteString("```json\n")
@@ -1172,49 +1727,72 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	sb.WriteString("```\n")
 	sb.WriteString("</decision>\n")
 	sb.WriteString("```\n\n")
-	sb.WriteString("## ⚠️ PARSING RULES (FAILURE = REJECTED RESPONSE)\n\n")
-	sb.WriteString("1. **FIRST LINE** of your response MUST be exactly: `<reasoning>`\n")
-	sb.WriteString("2. **LAST LINES** MUST be: `</decision>` (with JSON inside)\n")
-	sb.WriteString("3. **NO TEXT** before `<reasoning>` or after `</decision>`\n")
-	sb.WriteString("4. **JSON MUST** be inside ```json code fence within `<decision>` tags\n\n")
-	sb.WriteString("## JSON Decision Array Format:\n\n")
+	sb.WriteString(e.t("## ⚠️ PARSING RULES (FAILURE = REJECTED RESPONSE)\n\n"))
+	if nativeReasoning {
+		sb.WriteString(e.t("1. `<reasoning>` is optional; if present it comes first\n"))
+		sb.WriteString(e.t("2. **LAST LINES** MUST be: `</decision>` (with JSON inside)\n"))
+		sb.WriteString(e.t("3. **NO TEXT** after `</decision>`\n"))
+	} else {
+		sb.WriteString(e.t("1. **FIRST LINE** of your response MUST be exactly: `<reasoning>`\n"))
+		sb.WriteString(e.t("2. **LAST LINES** MUST be: `</decision>` (with JSON inside)\n"))
+		sb.WriteString(e.t("3. **NO TEXT** before `<reasoning>` or after `</decision>`\n"))
+	}
+	sb.WriteString(e.t("4. **JSON MUST** be inside ```json code fence within `<decision>` tags\n\n"))
+	sb.WriteString(e.t("## JSON Decision Array Format:\n\n"))
 	sb.WriteString("```json\n[\n")
 	// Use the actual configured position value ratio for Large Cap in the example
 	examplePositionSize := accountEquity * largeCapPosValueRatio
-	sb.WriteString(fmt.Sprintf("  {\"symbol\": \"AAPL\", \"action\": \"open_short\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 97000, \"take_profit\": 91000, \"confidence\": 85, \"risk_usd\": 300},\n",
+	sb.WriteString(fmt.Sprintf("  {\"schema_version\": 2, \"symbol\": \"AAPL\", \"action\": \"open_short\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 97000, \"take_profit\": 91000, \"confidence\": 85, \"risk_usd\": 300, \"metadata\": {\"order_type\": \"market\", \"valid_for_minutes\": 15}},\n",
 		riskControl.LargeCapMaxMargin, examplePositionSize))
 	sb.WriteString("  {\"symbol\": \"MSFT\", \"action\": \"close_long\"},\n")
 	sb.WriteString("  {\"symbol\": \"GOOGL\", \"action\": \"wait\"}\n")
 	sb.WriteString("]\n```\n\n")
-	sb.WriteString("## Field Description\n\n")
-	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | hold | wait\n")
-	sb.WriteString(fmt.Sprintf("- `confidence`: 0-100 (opening recommended ≥ %d)\n", riskControl.MinConfidence))
-	sb.WriteString("- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n")
-	sb.WriteString("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n")
+	sb.WriteString(e.t("## Field Description\n\n"))
+	sb.WriteString(e.t("- `action`: open_long | open_short | close_long | close_short | update_stops | hold | wait\n"))
+	sb.WriteString(fmt.Sprintf(e.t("- `confidence`: 0-100 (opening recommended ≥ %d)\n"), riskControl.MinConfidence))
+	sb.WriteString(e.t("- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n"))
+	sb.WriteString(e.t("- `risk_usd`: loss if the stop fills = position_size_usd × stop distance %. Code enforced: a position risking more than risk_usd is resized down\n"))
+	if riskControl.MaxRiskPerTradeUSD > 0 || riskControl.MaxRiskPerTradePct > 0 {
+		sb.WriteString(fmt.Sprintf(e.t("- Max risk per trade: %s (the smaller applies)\n"), formatRiskPerTradeCaps(riskControl)))
+	}
+	sb.WriteString(e.t("- `update_stops`: move stop_loss and/or take_profit of a held position (e.g. stop to breakeven after +2R). Stop loss must stay below the current price for longs (above for shorts), take profit on the other side; omitted levels are kept\n"))
+	sb.WriteString(e.t("- `entry_price` (optional, opening only): enter with a limit order at this price instead of market (e.g. a pullback to VWAP). Unfilled orders are cancelled after the expiry window (`valid_for_minutes` if given); stop_loss and take_profit are placed once filled\n"))
+	sb.WriteString(e.t("- `take_profit_levels` (optional, opening only): scaled exits as [{\"price\", \"pct_of_position\"}], e.g. 50% at +1R and the rest at +3R. Up to 4 levels; a remainder below 100% is closed at take_profit. Each level is a separate reduce-only order, the final level is the take_profit\n"))
+	sb.WriteString(fmt.Sprintf(e.t("- `schema_version`: %d (optional)\n"), CurrentDecisionSchemaVersion))
+	sb.WriteString(fmt.Sprintf(e.t("- `metadata` (optional): %s (ATR multiple), %s, %s (market | limit), %s, %s (update_stops with both sides held)\n"),
+		MetaTrailingStopATR, MetaValidForMinutes, MetaOrderType, MetaLimitPrice, MetaPositionSide))
+	sb.WriteString(e.t("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n"))
+
+	// 7.5. Response language (non-English prompts only)
+	sb.WriteString(responseLanguageInstruction[e.config.Language])
 
 	// 8. Multi-Timeframe Confluence Instructions
 	if indicators.EnableConfluence {
-		sb.WriteString("# 🛡️ Multi-Timeframe Confluence Engine (CRITICAL)\n\n")
-		sb.WriteString("You are in **Confluence Mode**. You MUST check signals across all provided timeframes before opening or closing positions.\n")
+		sb.WriteString(e.t("# 🛡️ Multi-Timeframe Confluence Engine (CRITICAL)\n\n"))
+		sb.WriteString(e.t("You are in **Confluence Mode**. You MUST check signals across all provided timeframes before opening or closing positions.\n"))
 		if indicators.ConfluenceRequireAll {
-			sb.WriteString(fmt.Sprintf("- **STRICT REQUIREMENT**: Every single selected timeframe (%s) MUST show the same trend direction and signal resonance. If they do not align, output `wait` for that symbol.\n",
+			sb.WriteString(fmt.Sprintf(e.t("- **STRICT REQUIREMENT**: Every single selected timeframe (%s) MUST show the same trend direction and signal resonance. If they do not align, output `wait` for that symbol.\n"),
 				strings.Join(indicators.ConfluenceTimeframes, ", ")))
 		} else {
 			minMatch := indicators.ConfluenceMinMatch
 			if minMatch <= 0 {
 				minMatch = 2
 			}
-			sb.WriteString(fmt.Sprintf("- **CONFLUENCE REQUIREMENT**: At least %d out of %d timeframes (%s) MUST align. If fewer than %d timeframes agree, output `wait` for that symbol.\n",
+			sb.WriteString(fmt.Sprintf(e.t("- **CONFLUENCE REQUIREMENT**: At least %d out of %d timeframes (%s) MUST align. If fewer than %d timeframes agree, output `wait` for that symbol.\n"),
 				minMatch, len(indicators.ConfluenceTimeframes), strings.Join(indicators.ConfluenceTimeframes, ", "), minMatch))
 		}
-		sb.WriteString("- Analyze the 'trend alignment' between short-term (e.g., 5m/15m) and higher-term (e.g., 1h/4h) structures.\n")
-		sb.WriteString("- Trade only in the direction of the macro trend if confluence is present.\n\n")
+		sb.WriteString(e.t("- Each stock includes a computed `Confluence` line (per-timeframe trend from EMA20 slope, MACD sign and RSI regime). Use it as the primary alignment check.\n"))
+		if indicators.ConfluenceBlockOpens {
+			sb.WriteString(e.t("- Opens that do not meet the confluence requirement are **rejected by the system** and converted to `wait`.\n"))
+		}
+		sb.WriteString(e.t("- Analyze the 'trend alignment' between short-term (e.g., 5m/15m) and higher-term (e.g., 1h/4h) structures.\n"))
+		sb.WriteString(e.t("- Trade only in the direction of the macro trend if confluence is present.\n\n"))
 	}
 
 	// 8.5. VWAP + Slope & Stretch Algorithm (Tier 1)
 	if indicators.EnableVWAPSlopeStretch {
-		sb.WriteString("# 📈 VWAP + Slope & Stretch Algorithm (CRITICAL - Tier 1)\n\n")
-		sb.WriteString("**This algorithm is ACTIVE. You MUST apply these entry conditions before opening ANY position.**\n\n")
+		sb.WriteString(e.t("# 📈 VWAP + Slope & Stretch Algorithm (CRITICAL - Tier 1)\n\n"))
+		sb.WriteString(e.t("**This algorithm is ACTIVE. You MUST apply these entry conditions before opening ANY position.**\n\n"))
 
 		// Get entry time with default
 		entryTime := indicators.VWAPEntryTime
@@ -1226,30 +1804,30 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		ai100Client := market.GetAI100Client()
 		sellTriggers, _ := ai100Client.FetchSellTriggers()
 
-		sb.WriteString(fmt.Sprintf("## Entry Time: %s AM ET\n\n", entryTime))
+		sb.WriteString(fmt.Sprintf(e.t("## Entry Time: %s AM ET\n\n"), entryTime))
 
-		sb.WriteString("## Entry Conditions (ALL must be TRUE to open a LONG position):\n")
-		sb.WriteString(fmt.Sprintf("1. **Price @ %s AM > VWAP**: Current price must be ABOVE VWAP\n", entryTime))
-		sb.WriteString(fmt.Sprintf("2. **VWAP Slope Positive**: VWAP is trending UP (VWAP @ %s AM > VWAP @ 9:40 AM)\n", entryTime))
-		sb.WriteString("3. **Stretch Filter**: Price not overextended (Stretch < 0.5 × OR_Volatility)\n")
-		sb.WriteString("4. **Momentum Filter**: Sufficient momentum from open (Momentum > 0.25 × OR_Volatility)\n\n")
+		sb.WriteString(e.t("## Entry Conditions (ALL must be TRUE to open a LONG position):\n"))
+		sb.WriteString(fmt.Sprintf(e.t("1. **Price @ %s AM > VWAP**: Current price must be ABOVE VWAP\n"), entryTime))
+		sb.WriteString(fmt.Sprintf(e.t("2. **VWAP Slope Positive**: VWAP is trending UP (VWAP @ %s AM > VWAP @ 9:40 AM)\n"), entryTime))
+		sb.WriteString(e.t("3. **Stretch Filter**: Price not overextended (Stretch < 0.5 × OR_Volatility)\n"))
+		sb.WriteString(e.t("4. **Momentum Filter**: Sufficient momentum from open (Momentum > 0.25 × OR_Volatility)\n\n"))
 
-		sb.WriteString("## Key Metrics to Calculate:\n")
-		sb.WriteString(fmt.Sprintf("- **VWAP**: Volume Weighted Average Price from 9:30 AM to %s AM\n", entryTime))
-		sb.WriteString(fmt.Sprintf("- **VWAP Slope** = (VWAP @ %s AM - VWAP @ 9:40 AM) / VWAP @ 9:40 AM\n", entryTime))
-		sb.WriteString("- **VWAP Stretch** = (Current_Price - VWAP) / VWAP\n")
-		sb.WriteString("- **OR Volatility** = max(OR_High - VWAP, VWAP - OR_Low) / VWAP (Opening Range: 9:30-10:00 AM)\n")
-		sb.WriteString(fmt.Sprintf("- **Momentum** = (Price @ %s AM - Open) / Open\n\n", entryTime))
+		sb.WriteString(e.t("## Key Metrics to Calculate:\n"))
+		sb.WriteString(fmt.Sprintf(e.t("- **VWAP**: Volume Weighted Average Price from 9:30 AM to %s AM\n"), entryTime))
+		sb.WriteString(fmt.Sprintf(e.t("- **VWAP Slope** = (VWAP @ %s AM - VWAP @ 9:40 AM) / VWAP @ 9:40 AM\n"), entryTime))
+		sb.WriteString(e.t("- **VWAP Stretch** = (Current_Price - VWAP) / VWAP\n"))
+		sb.WriteString(e.t("- **OR Volatility** = max(OR_High - VWAP, VWAP - OR_Low) / VWAP (Opening Range: 9:30-10:00 AM)\n"))
+		sb.WriteString(fmt.Sprintf(e.t("- **Momentum** = (Price @ %s AM - Open) / Open\n\n"), entryTime))
 
-		sb.WriteString("## Exit Rules:\n")
-		sb.WriteString("- **Take Profit**: Use the stock-specific sell_trigger % from the table below\n")
-		sb.WriteString("- **Stop Loss**: Day's Open Price (protection)\n")
-		sb.WriteString("- **Time Exit**: Close position by 3:55 PM ET if neither TP nor SL hit\n\n")
+		sb.WriteString(e.t("## Exit Rules:\n"))
+		sb.WriteString(e.t("- **Take Profit**: Use the stock-specific sell_trigger % from the table below\n"))
+		sb.WriteString(e.t("- **Stop Loss**: Day's Open Price (protection)\n"))
+		sb.WriteString(e.t("- **Time Exit**: Close position by 3:55 PM ET if neither TP nor SL hit\n\n"))
 
 		// Write sell_trigger table for candidate stocks
 		if len(sellTriggers) > 0 {
-			sb.WriteString("## Stock-Specific Take Profit Targets (from AI100 optimization):\n")
-			sb.WriteString("| Symbol | Take Profit % |\n")
+			sb.WriteString(e.t("## Stock-Specific Take Profit Targets (from AI100 optimization):\n"))
+			sb.WriteString(e.t("| Symbol | Take Profit % |\n"))
 			sb.WriteString("|--------|---------------|\n")
 			for symbol, trigger := range sellTriggers {
 				sb.WriteString(fmt.Sprintf("| %s | %.2f%% |\n", symbol, trigger))
@@ -1257,18 +1835,18 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 			sb.WriteString("\n")
 		}
 
-		sb.WriteString("## IMPORTANT:\n")
-		sb.WriteString("- If ANY entry condition fails, output `wait` for that symbol\n")
-		sb.WriteString("- In your reasoning, explicitly state each condition check result\n")
-		sb.WriteString("- This is a MOMENTUM strategy - buy when trending UP above VWAP\n\n")
+		sb.WriteString(e.t("## IMPORTANT:\n"))
+		sb.WriteString(e.t("- If ANY entry condition fails, output `wait` for that symbol\n"))
+		sb.WriteString(e.t("- In your reasoning, explicitly state each condition check result\n"))
+		sb.WriteString(e.t("- This is a MOMENTUM strategy - buy when trending UP above VWAP\n\n"))
 	}
 
 	// 9. Custom Prompt
 	if e.config.CustomPrompt != "" {
-		sb.WriteString("# 📌 Personalized Trading Strategy\n\n")
+		sb.WriteString(e.t("# 📌 Personalized Trading Strategy\n\n"))
 		sb.WriteString(e.config.CustomPrompt)
 		sb.WriteString("\n\n")
-		sb.WriteString("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n")
+		sb.WriteString(e.t("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n"))
 	}
 
 	return sb.String()
@@ -1278,64 +1856,76 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 	indicators := e.config.Indicators
 	kline := indicators.Klines
 
-	sb.WriteString(fmt.Sprintf("- %s price series", kline.PrimaryTimeframe))
+	sb.WriteString(fmt.Sprintf(e.t("- %s price series"), kline.PrimaryTimeframe))
 	if kline.EnableMultiTimeframe {
-		sb.WriteString(fmt.Sprintf(" + %s K-line series\n", kline.LongerTimeframe))
+		sb.WriteString(fmt.Sprintf(e.t(" + %s K-line series\n"), kline.LongerTimeframe))
 	} else {
 		sb.WriteString("\n")
 	}
 
 	if indicators.EnableEMA {
-		sb.WriteString("- EMA indicators")
+		sb.WriteString(e.t("- EMA indicators"))
 		if len(indicators.EMAPeriods) > 0 {
-			sb.WriteString(fmt.Sprintf(" (periods: %v)", indicators.EMAPeriods))
+			sb.WriteString(fmt.Sprintf(e.t(" (periods: %v)"), indicators.EMAPeriods))
 		}
 		sb.WriteString("\n")
 	}
 
 	if indicators.EnableMACD {
-		sb.WriteString("- MACD indicators\n")
+		sb.WriteString(e.t("- MACD indicators\n"))
 	}
 
 	if indicators.EnableRSI {
-		sb.WriteString("- RSI indicators")
+		sb.WriteString(e.t("- RSI indicators"))
 		if len(indicators.RSIPeriods) > 0 {
-			sb.WriteString(fmt.Sprintf(" (periods: %v)", indicators.RSIPeriods))
+			sb.WriteString(fmt.Sprintf(e.t(" (periods: %v)"), indicators.RSIPeriods))
 		}
 		sb.WriteString("\n")
 	}
 
 	if indicators.EnableATR {
-		sb.WriteString("- ATR indicators")
+		sb.WriteString(e.t("- ATR indicators"))
 		if len(indicators.ATRPeriods) > 0 {
-			sb.WriteString(fmt.Sprintf(" (periods: %v)", indicators.ATRPeriods))
+			sb.WriteString(fmt.Sprintf(e.t(" (periods: %v)"), indicators.ATRPeriods))
 		}
 		sb.WriteString("\n")
 	}
 
 	if indicators.EnableVolume {
-		sb.WriteString("- Volume data\n")
+		sb.WriteString(e.t("- Volume data\n"))
 	}
 
 	if indicators.EnableOI {
-		sb.WriteString("- Open Interest (OI) data\n")
+		sb.WriteString(e.t("- Open Interest (OI) data\n"))
 	}
 
 	if indicators.EnableFundingRate {
-		sb.WriteString("- Funding rate\n")
+		sb.WriteString(e.t("- Funding rate\n"))
+	}
+
+	if e.config.CoinSource.UseStockPool || e.config.CoinSource.UseOITop {
+		sb.WriteString(e.t("- AI500 / OI_Top filter tags (if available)\n"))
+	}
+
+	if e.usesFundingArb() {
+		sb.WriteString(e.t("- Cross-exchange funding rates (extreme funding = crowded side, wide spread = carry opportunity)\n"))
+	}
+
+	if e.usesWebhookSource() {
+		sb.WriteString(e.t("- External screener scores and notes (screener's view, confirm with market data)\n"))
 	}
 
-	if len(e.config.CoinSource.StaticCoins) > 0 || e.config.CoinSource.UseStockPool || e.config.CoinSource.UseOITop {
-		sb.WriteString("- AI500 / OI_Top filter tags (if available)\n")
+	if e.usesSocialTrending() {
+		sb.WriteString(e.t("- Social mentions and sentiment (crowd attention, a momentum/volatility hint rather than a signal)\n"))
 	}
 
 	if indicators.EnableQuantData {
-		sb.WriteString("- Quantitative data (institutional/retail fund flow, position changes, multi-period price changes)\n")
+		sb.WriteString(e.t("- Quantitative data (institutional/retail fund flow, position changes, multi-period price changes)\n"))
 	}
 
 	// VWAP indicators
 	if indicators.EnableVWAPIndicator {
-		sb.WriteString("- VWAP (Volume Weighted Average Price) series\n")
+		sb.WriteString(e.t("- VWAP (Volume Weighted Average Price) series\n"))
 	}
 
 	// Volume Profile
@@ -1344,44 +1934,44 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		if bins <= 0 {
 			bins = 24
 		}
-		sb.WriteString(fmt.Sprintf("- Volume Profile (%d price levels)\n", bins))
+		sb.WriteString(fmt.Sprintf(e.t("- Volume Profile (%d price levels)\n"), bins))
 	}
 
 	// Stock-specific indicators
 	if indicators.EnableStockNews {
-		sb.WriteString("- Stock news & sentiment\n")
+		sb.WriteString(e.t("- Stock news & sentiment\n"))
 	}
 
 	if indicators.EnableCorporateActions {
-		sb.WriteString("- Corporate actions (dividends, splits, etc.)\n")
+		sb.WriteString(e.t("- Corporate actions (dividends, splits, etc.)\n"))
 	}
 
 	if indicators.EnableVolumeSurge {
-		sb.WriteString("- Volume surge detection (2x+ average)\n")
+		sb.WriteString(e.t("- Volume surge detection (2x+ average)\n"))
 	}
 
 	if indicators.EnableAnalystRatings {
-		sb.WriteString("- Analyst ratings & price targets\n")
+		sb.WriteString(e.t("- Analyst ratings & price targets\n"))
 	}
 
 	if indicators.EnableEarnings {
-		sb.WriteString("- Earnings calendar (upcoming earnings dates & estimates)\n")
+		sb.WriteString(e.t("- Earnings calendar (upcoming earnings dates & estimates)\n"))
 	}
 
 	if indicators.EnableShortInterest {
-		sb.WriteString("- Short interest & squeeze risk\n")
+		sb.WriteString(e.t("- Short interest & squeeze risk\n"))
 	}
 
 	if indicators.EnableZeroDTE {
-		sb.WriteString("- Zero DTE options sentiment (put/call ratio, max pain)\n")
+		sb.WriteString(e.t("- Zero DTE options sentiment (put/call ratio, max pain)\n"))
 	}
 
 	if indicators.EnableTradeFlow {
-		sb.WriteString("- Trade flow analysis (institutional buy/sell activity)\n")
+		sb.WriteString(e.t("- Trade flow analysis (institutional buy/sell activity)\n"))
 	}
 
 	if indicators.EnableAnchoredVWAP {
-		sb.WriteString("- Session-anchored VWAP (from 9:30 AM ET market open)\n")
+		sb.WriteString(e.t("- Session-anchored VWAP (from 9:30 AM ET market open)\n"))
 	}
 
 	if indicators.EnableConfluence {
@@ -1389,7 +1979,7 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		if indicators.ConfluenceRequireAll {
 			mode = "Strict"
 		}
-		sb.WriteString(fmt.Sprintf("- Multi-Timeframe Confluence Engine (%s Mode: %v)\n",
+		sb.WriteString(fmt.Sprintf(e.t("- Multi-Timeframe Confluence Engine (%s Mode: %v)\n"),
 			mode, indicators.ConfluenceTimeframes))
 	}
 
@@ -1398,7 +1988,7 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		if entryTime == "" {
 			entryTime = "10:00"
 		}
-		sb.WriteString(fmt.Sprintf("- **VWAP + Slope & Stretch Algorithm** (Entry: %s AM ET) - Tier 1 Entry Filter\n", entryTime))
+		sb.WriteString(fmt.Sprintf(e.t("- **VWAP + Slope & Stretch Algorithm** (Entry: %s AM ET) - Tier 1 Entry Filter\n"), entryTime))
 	}
 }
 
@@ -1411,34 +2001,49 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	var sb strings.Builder
 
 	// System status
-	sb.WriteString(fmt.Sprintf("Time: %s | Period: #%d | Runtime: %d minutes\n\n",
+	sb.WriteString(fmt.Sprintf(e.t("Time: %s | Period: #%d | Runtime: %d minutes\n\n"),
 		ctx.CurrentTime, ctx.CallCount, ctx.RuntimeMinutes))
 
-	// Market Reference (SPY)
-	if spyData, hasSPY := ctx.MarketDataMap["SPY"]; hasSPY {
-		sb.WriteString(fmt.Sprintf("SPY: %.2f (1h: %+.2f%%, 4h: %+.2f%%) | MACD: %.4f | RSI: %.2f\n\n",
+	// Market Reference (regime classifier when enabled, raw SPY otherwise)
+	if ctx.Regime != nil {
+		sb.WriteString(formatRegime(ctx))
+	} else if spyData, hasSPY := ctx.MarketDataMap["SPY"]; hasSPY {
+		sb.WriteString(fmt.Sprintf(e.t("SPY: %.2f (1h: %+.2f%%, 4h: %+.2f%%) | MACD: %.4f | RSI: %.2f\n\n"),
 			spyData.CurrentPrice, spyData.PriceChange1h, spyData.PriceChange4h,
 			spyData.CurrentMACD, spyData.CurrentRSI7))
 	}
+	sb.WriteString(formatBlackouts(ctx.Blackouts))
+	sb.WriteString(formatShock(ctx.Shock))
+	if ctx.OpensPaused != "" {
+		sb.WriteString(fmt.Sprintf(e.t("⏸ TRADING PAUSED by the operator (%s): new opens are rejected. Only manage open positions (close, update_stops, hold/wait).\n\n"), ctx.OpensPaused))
+	}
+	if ctx.VenueMaintenance != "" {
+		sb.WriteString(fmt.Sprintf(e.t("🛠 EXCHANGE MAINTENANCE (%s): no orders can be sent this cycle. Analyze and answer hold/wait; resting stop orders stay in place.\n\n"), ctx.VenueMaintenance))
+	}
 
 	// Account information
-	sb.WriteString(fmt.Sprintf("Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n",
+	sb.WriteString(fmt.Sprintf(e.t("Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n"),
 		ctx.Account.TotalEquity,
 		ctx.Account.AvailableBalance,
 		(ctx.Account.AvailableBalance/ctx.Account.TotalEquity)*100,
 		ctx.Account.TotalPnLPct,
 		ctx.Account.MarginUsedPct,
 		ctx.Account.PositionCount))
+	if market.IsSpotExchange(ctx.Exchange) {
+		sb.WriteString(e.t("Spot account: only open_long/close_long are possible (open_short/close_short become wait), leverage is always 1x and position size is limited by cash\n\n"))
+	} else if market.IsAlpacaExchange(ctx.Exchange) && hasCryptoSymbols(ctx) {
+		sb.WriteString(e.t("Crypto pairs on this broker trade spot: only open_long/close_long are possible for them (open_short/close_short become wait) at 1x leverage; stocks follow the normal rules\n\n"))
+	}
 
 	// Recently completed orders (placed before positions to ensure visibility)
 	if len(ctx.RecentOrders) > 0 {
-		sb.WriteString("## Recent Completed Trades\n")
+		sb.WriteString(e.t("## Recent Completed Trades\n"))
 		for i, order := range ctx.RecentOrders {
 			resultStr := "Profit"
 			if order.RealizedPnL < 0 {
 				resultStr = "Loss"
 			}
-			sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USD (%+.2f%%) | %s→%s (%s)\n",
+			sb.WriteString(fmt.Sprintf(e.t("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USD (%+.2f%%) | %s→%s (%s)\n"),
 				i+1, order.Symbol, order.Side,
 				order.EntryPrice, order.ExitPrice,
 				resultStr, order.RealizedPnL, order.PnLPct,
@@ -1447,16 +2052,39 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		sb.WriteString("\n")
 	}
 
+	// Overall performance and MFE/MAE trade quality
+	sb.WriteString(formatTradingStats(ctx.TradingStats))
+
+	// Human review of recent closed trades (trade journal)
+	sb.WriteString(formatAnnotationDigest(ctx.Annotations, e.config.Annotations.MaxItems))
+
+	// Limit entry orders (still pending, or filled / expired since the last cycle)
+	if len(ctx.LimitEntries) > 0 {
+		sb.WriteString(formatLimitEntries(ctx.LimitEntries))
+	}
+
+	// Running thesis of the last cycles (session memory)
+	sb.WriteString(formatSessionMemory(ctx))
+
+	// Previous decision and outcome per symbol (churn guard)
+	sb.WriteString(formatPreviousDecisions(ctx))
+
+	// Trade budget usage and opens rejected last cycle (trade governor)
+	sb.WriteString(formatTradeBudget(ctx.TradeBudget))
+
 	// Position information
 	if len(ctx.Positions) > 0 {
-		sb.WriteString("## Current Positions\n")
+		sb.WriteString(e.t("## Current Positions\n"))
 		for i, pos := range ctx.Positions {
 			sb.WriteString(e.formatPositionInfo(i+1, pos, ctx))
 		}
 	} else {
-		sb.WriteString("Current Positions: None\n\n")
+		sb.WriteString(e.t("Current Positions: None\n\n"))
 	}
 
+	// Custom data blocks of context hooks
+	sb.WriteString(formatPromptBlocks(ctx))
+
 	// Candidate stocks
 	stocksWithData := 0
 	stocksWithoutData := 0
@@ -1468,7 +2096,7 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("## Candidate Stocks (%d configured, %d with market data)\n\n", len(ctx.CandidateStocks), stocksWithData))
+	sb.WriteString(fmt.Sprintf(e.t("## Candidate Stocks (%d configured, %d with market data)\n\n"), len(ctx.CandidateStocks), stocksWithData))
 
 	displayedCount := 0
 	// First, show stocks WITH market data
@@ -1480,7 +2108,17 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		displayedCount++
 
 		sourceTags := e.formatStockSourceTag(stock.Sources)
+		if ctx.CandidateRanking != nil {
+			if score, ok := ctx.CandidateRanking.Scores[stock.Symbol]; ok {
+				sourceTags += fmt.Sprintf(e.t(" (opportunity score %.2f)"), score.Score)
+			}
+		}
 		sb.WriteString(fmt.Sprintf("### %d. %s%s\n\n", displayedCount, stock.Symbol, sourceTags))
+		sb.WriteString(e.formatConfluence(stock.Symbol, marketData, ctx))
+		sb.WriteString(formatFundingData(stock.Symbol, ctx))
+		sb.WriteString(formatWebhookData(stock.Symbol, ctx))
+		sb.WriteString(formatSocialData(stock.Symbol, ctx))
+		sb.WriteString(formatLessons(stock.Symbol, ctx))
 		sb.WriteString(e.formatMarketData(marketData))
 
 		if ctx.QuantDataMap != nil {
@@ -1493,33 +2131,89 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 
 	// Then, list stocks WITHOUT market data (so AI knows about them)
 	if stocksWithoutData > 0 {
-		sb.WriteString("### Stocks Pending Market Data:\n")
+		sb.WriteString(e.t("### Stocks Pending Market Data:\n"))
 		for _, stock := range ctx.CandidateStocks {
 			if _, hasData := ctx.MarketDataMap[stock.Symbol]; !hasData {
 				sourceTags := e.formatStockSourceTag(stock.Sources)
-				sb.WriteString(fmt.Sprintf("- %s%s (market data unavailable)\n", stock.Symbol, sourceTags))
+				sb.WriteString(fmt.Sprintf(e.t("- %s%s (market data unavailable)\n"), stock.Symbol, sourceTags))
 			}
 		}
 		sb.WriteString("\n")
 	}
 	sb.WriteString("\n")
 
+	// Feeds that failed this cycle, so the AI can weigh data gaps
+	sb.WriteString(e.formatDataCompleteness(ctx))
+
 	// OI Ranking data (market-wide open interest changes)
 	if ctx.OIRankingData != nil {
 		sb.WriteString(provider.FormatOIRankingForAI(ctx.OIRankingData))
 	}
 
 	sb.WriteString("---\n\n")
-	sb.WriteString("## 🚨 FINAL REMINDER - OUTPUT FORMAT\n\n")
-	sb.WriteString("Your response MUST follow this EXACT structure:\n\n")
-	sb.WriteString("1. Start with `<reasoning>` (no text before it)\n")
-	sb.WriteString("2. Write detailed Chain of Thought analysis for each stock\n")
-	sb.WriteString("3. Close with `</reasoning>`\n")
-	sb.WriteString("4. Open `<decision>` tag\n")
-	sb.WriteString("5. Write JSON array inside ```json code fence\n")
-	sb.WriteString("6. Close with `</decision>` (no text after it)\n\n")
-	sb.WriteString("**BEGIN YOUR RESPONSE WITH `<reasoning>` NOW:**\n")
+	sb.WriteString(e.t("## 🚨 FINAL REMINDER - OUTPUT FORMAT\n\n"))
+	sb.WriteString(e.t("Your response MUST follow this EXACT structure:\n\n"))
+	if ctx.NativeReasoning {
+		// Analysis happens in the model's native reasoning channel, only the decision block is required
+		sb.WriteString(e.t("1. Do your per-stock analysis in your native reasoning; a `<reasoning>` summary is optional\n"))
+		sb.WriteString(e.t("2. Open `<decision>` tag\n"))
+		sb.WriteString(e.t("3. Write JSON array inside ```json code fence\n"))
+		sb.WriteString(e.t("4. Close with `</decision>` (no text after it)\n\n"))
+		sb.WriteString(e.t("**BEGIN YOUR RESPONSE NOW:**\n"))
+		return sb.String()
+	}
+	sb.WriteString(e.t("1. Start with `<reasoning>` (no text before it)\n"))
+	sb.WriteString(e.t("2. Write detailed Chain of Thought analysis for each stock\n"))
+	sb.WriteString(e.t("3. Close with `</reasoning>`\n"))
+	sb.WriteString(e.t("4. Open `<decision>` tag\n"))
+	sb.WriteString(e.t("5. Write JSON array inside ```json code fence\n"))
+	sb.WriteString(e.t("6. Close with `</decision>` (no text after it)\n\n"))
+	sb.WriteString(e.t("**BEGIN YOUR RESPONSE WITH `<reasoning>` NOW:**\n"))
+
+	return sb.String()
+}
+
+// formatOvernightGap gap analysis note for a position held over the last close
+func formatOvernightGap(side string, gap *OvernightGap) string {
+	favorable := (side == "long" && gap.GapPct >= 0) || (side == "short" && gap.GapPct <= 0)
+	verdict := "against the position"
+	if favorable {
+		verdict = "in favor of the position"
+	}
+	note := fmt.Sprintf("🌙 **OVERNIGHT HOLD** (since %s close): prev close %.4f → open %.4f, gap %+.2f%% %s",
+		gap.HeldSince, gap.PrevClose, gap.Open, gap.GapPct, verdict)
+	if gap.StopPrice > 0 {
+		note += fmt.Sprintf(" | overnight hard stop %.4f", gap.StopPrice)
+		if gap.StopGapped {
+			note += " (gapped through: stop fills at the open, not at the stop price)"
+		}
+	}
+	return note + ". Re-assess whether the gap fills or extends before holding; reset stop loss / take profit for today's session.\n\n"
+}
 
+// formatLimitEntries formats limit entry orders so the AI neither duplicates pending entries nor misses expired ones
+func formatLimitEntries(entries []LimitEntryInfo) string {
+	var sb strings.Builder
+	sb.WriteString("## Limit Entry Orders\n")
+	for i, entry := range entries {
+		sb.WriteString(fmt.Sprintf("%d. %s %s @ %.4f | Qty %.4f | %s", i+1, entry.Symbol, strings.ToUpper(entry.Side),
+			entry.EntryPrice, entry.Quantity, strings.ToUpper(entry.Status)))
+		switch entry.Status {
+		case "pending":
+			sb.WriteString(fmt.Sprintf(" (placed %s, expires %s)", entry.PlacedAt, entry.ExpiresAt))
+		case "filled":
+			sb.WriteString(fmt.Sprintf(" (filled %.4f, now a position)", entry.FilledQty))
+		default:
+			if entry.FilledQty > 0 {
+				sb.WriteString(fmt.Sprintf(" (partially filled %.4f)", entry.FilledQty))
+			}
+		}
+		if entry.Note != "" {
+			sb.WriteString(" — " + entry.Note)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Pending entries count toward max positions; do not open the same symbol/side again while one is pending.\n\n")
 	return sb.String()
 }
 
@@ -1549,6 +2243,19 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 		pos.EntryPrice, pos.MarkPrice, pos.Quantity, positionValue, pos.UnrealizedPnLPct, pos.UnrealizedPnL, pos.PeakPnLPct,
 		pos.Leverage, pos.MarginUsed, pos.LiquidationPrice, holdingDuration))
 
+	if tpsl, ok := ctx.PositionTPSLMap[pos.Symbol+"_"+pos.Side]; ok && (tpsl[0] > 0 || tpsl[1] > 0) {
+		sb.WriteString(fmt.Sprintf("Current Stops: Stop Loss %.4f | Take Profit %.4f (move with update_stops)\n\n", tpsl[1], tpsl[0]))
+	}
+
+	if pos.ForcedReview {
+		sb.WriteString(fmt.Sprintf("⏰ **FORCED REVIEW**: max holding time (%d min) reached. Decide now: close this position or hold with a clear reason (it will be auto-closed after the grace period).\n\n",
+			e.config.RiskControl.MaxHoldMinutes))
+	}
+
+	if pos.Overnight != nil {
+		sb.WriteString(formatOvernightGap(pos.Side, pos.Overnight))
+	}
+
 	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
 		sb.WriteString(e.formatMarketData(marketData))
 
@@ -1563,6 +2270,21 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 	return sb.String()
 }
 
+// formatConfluence formats computed confluence line (empty unless confluence is enabled)
+func (e *StrategyEngine) formatConfluence(symbol string, data *market.Data, ctx *Context) string {
+	if !e.config.Indicators.EnableConfluence {
+		return ""
+	}
+	score, ok := ctx.ConfluenceMap[symbol]
+	if !ok {
+		score = CalculateConfluence(data, e.config.Indicators.ConfluenceTimeframes)
+	}
+	if score == nil {
+		return ""
+	}
+	return fmt.Sprintf("Confluence (computed): %s\n\n", score.Summary())
+}
+
 func (e *StrategyEngine) formatStockSourceTag(sources []string) string {
 	if len(sources) > 1 {
 		return " (AI500+OI_Top dual signal)"
@@ -1572,6 +2294,12 @@ func (e *StrategyEngine) formatStockSourceTag(sources []string) string {
 			return " (AI500)"
 		case "oi_top":
 			return " (OI_Top position growth)"
+		case "funding_arb":
+			return " (Funding extreme)"
+		case "webhook":
+			return " (Screener webhook)"
+		case "social_trending":
+			return " (Social trending)"
 		case "static":
 			return " (Manual selection)"
 		}
@@ -1583,18 +2311,49 @@ func (e *StrategyEngine) formatStockSourceTag(sources []string) string {
 // Market Data Formatting
 // ============================================================================
 
+// timeframeDisplayOrder timeframes from shortest to longest
+var timeframeDisplayOrder = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
+
+// orderBookPromptLevels order book levels per side listed in the prompt (spread and imbalance use all fetched levels)
+const orderBookPromptLevels = 5
+
+// formatOrderBook order book section of a symbol
+func formatOrderBook(book *market.OrderBook) string {
+	var sb strings.Builder
+	pressure := "balanced"
+	if book.ImbalanceRatio >= 1.5 {
+		pressure = "bid heavy"
+	} else if book.ImbalanceRatio > 0 && book.ImbalanceRatio <= 1/1.5 {
+		pressure = "ask heavy"
+	}
+	sb.WriteString(fmt.Sprintf("Order Book (top %d levels, %s): bid %s / ask %s | spread %.4f%% | depth bid %s vs ask %s USD | imbalance %.2f (%s)\n",
+		max(len(book.Bids), len(book.Asks)), book.Source, numfmt.Price(book.BestBid), numfmt.Price(book.BestAsk), book.SpreadPct,
+		numfmt.Compact(book.BidNotional), numfmt.Compact(book.AskNotional), book.ImbalanceRatio, pressure))
+	writeLevels := func(side string, levels []market.OrderBookLevel) {
+		parts := make([]string, 0, orderBookPromptLevels)
+		for i := 0; i < len(levels) && i < orderBookPromptLevels; i++ {
+			parts = append(parts, numfmt.Price(levels[i].Price)+"×"+numfmt.Compact(levels[i].Size))
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", side, strings.Join(parts, ", ")))
+	}
+	writeLevels("Bids", book.Bids)
+	writeLevels("Asks", book.Asks)
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 	var sb strings.Builder
 	indicators := e.config.Indicators
 
-	sb.WriteString(fmt.Sprintf("current_price = %.4f", data.CurrentPrice))
+	sb.WriteString("current_price = " + numfmt.Price(data.CurrentPrice))
 
 	if indicators.EnableEMA {
-		sb.WriteString(fmt.Sprintf(", current_ema20 = %.3f", data.CurrentEMA20))
+		sb.WriteString(", current_ema20 = " + numfmt.Price(data.CurrentEMA20))
 	}
 
 	if indicators.EnableMACD {
-		sb.WriteString(fmt.Sprintf(", current_macd = %.3f", data.CurrentMACD))
+		sb.WriteString(", current_macd = " + numfmt.Price(data.CurrentMACD))
 	}
 
 	if indicators.EnableRSI {
@@ -1603,22 +2362,29 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 
 	sb.WriteString("\n\n")
 
+	if summary := data.Quality.Summary(); summary != "" {
+		sb.WriteString(fmt.Sprintf("⚠️ Data quality: %s, treat indicators with caution\n\n", summary))
+	}
+
 	if indicators.EnableOI || indicators.EnableFundingRate {
 		sb.WriteString(fmt.Sprintf("Additional data for %s:\n\n", data.Symbol))
 
 		if indicators.EnableOI && data.OpenInterest != nil {
-			sb.WriteString(fmt.Sprintf("Open Interest: Latest: %.2f Average: %.2f\n\n",
-				data.OpenInterest.Latest, data.OpenInterest.Average))
+			sb.WriteString(fmt.Sprintf("Open Interest: Latest: %s Average: %s\n\n",
+				numfmt.Compact(data.OpenInterest.Latest), numfmt.Compact(data.OpenInterest.Average)))
 		}
 
 		if indicators.EnableFundingRate {
-			sb.WriteString(fmt.Sprintf("Funding Rate: %.2e\n\n", data.FundingRate))
+			sb.WriteString(fmt.Sprintf("Funding Rate: %s\n\n", numfmt.Percent(data.FundingRate, 4)))
 		}
 	}
 
+	if indicators.EnableOrderBook && data.OrderBook != nil {
+		sb.WriteString(formatOrderBook(data.OrderBook))
+	}
+
 	if len(data.TimeframeData) > 0 {
-		timeframeOrder := []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
-		for _, tf := range timeframeOrder {
+		for _, tf := range timeframeDisplayOrder {
 			if tfData, ok := data.TimeframeData[tf]; ok {
 				sb.WriteString(fmt.Sprintf("=== %s Timeframe (oldest → latest) ===\n\n", strings.ToUpper(tf)))
 				e.formatTimeframeSeriesData(&sb, tfData, indicators)
@@ -1631,28 +2397,28 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 			sb.WriteString(fmt.Sprintf("Intraday series (%s intervals, oldest → latest):\n\n", klineConfig.PrimaryTimeframe))
 
 			if len(data.IntradaySeries.MidPrices) > 0 {
-				sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.IntradaySeries.MidPrices)))
+				sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", numfmt.Series(data.IntradaySeries.MidPrices)))
 			}
 
 			if indicators.EnableEMA && len(data.IntradaySeries.EMA20Values) > 0 {
-				sb.WriteString(fmt.Sprintf("EMA indicators (20-period): %s\n\n", formatFloatSlice(data.IntradaySeries.EMA20Values)))
+				sb.WriteString(fmt.Sprintf("EMA indicators (20-period): %s\n\n", numfmt.Series(data.IntradaySeries.EMA20Values)))
 			}
 
 			if indicators.EnableMACD && len(data.IntradaySeries.MACDValues) > 0 {
-				sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", formatFloatSlice(data.IntradaySeries.MACDValues)))
+				sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", numfmt.Series(data.IntradaySeries.MACDValues)))
 			}
 
 			if indicators.EnableRSI {
 				if len(data.IntradaySeries.RSI7Values) > 0 {
-					sb.WriteString(fmt.Sprintf("RSI indicators (7-Period): %s\n\n", formatFloatSlice(data.IntradaySeries.RSI7Values)))
+					sb.WriteString(fmt.Sprintf("RSI indicators (7-Period): %s\n\n", numfmt.Series(data.IntradaySeries.RSI7Values)))
 				}
 				if len(data.IntradaySeries.RSI14Values) > 0 {
-					sb.WriteString(fmt.Sprintf("RSI indicators (14-Period): %s\n\n", formatFloatSlice(data.IntradaySeries.RSI14Values)))
+					sb.WriteString(fmt.Sprintf("RSI indicators (14-Period): %s\n\n", numfmt.Series(data.IntradaySeries.RSI14Values)))
 				}
 			}
 
 			if indicators.EnableVolume && len(data.IntradaySeries.Volume) > 0 {
-				sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.IntradaySeries.Volume)))
+				sb.WriteString(fmt.Sprintf("Volume: %s\n\n", numfmt.Series(data.IntradaySeries.Volume)))
 			}
 
 			if indicators.EnableATR {
@@ -1679,11 +2445,11 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 			}
 
 			if indicators.EnableMACD && len(data.LongerTermContext.MACDValues) > 0 {
-				sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", formatFloatSlice(data.LongerTermContext.MACDValues)))
+				sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", numfmt.Series(data.LongerTermContext.MACDValues)))
 			}
 
 			if indicators.EnableRSI && len(data.LongerTermContext.RSI14Values) > 0 {
-				sb.WriteString(fmt.Sprintf("RSI indicators (14-Period): %s\n\n", formatFloatSlice(data.LongerTermContext.RSI14Values)))
+				sb.WriteString(fmt.Sprintf("RSI indicators (14-Period): %s\n\n", numfmt.Series(data.LongerTermContext.RSI14Values)))
 			}
 		}
 	}
@@ -1711,31 +2477,31 @@ func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *ma
 		}
 		sb.WriteString("\n")
 	} else if len(data.MidPrices) > 0 {
-		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.MidPrices)))
+		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", numfmt.Series(data.MidPrices)))
 		if indicators.EnableVolume && len(data.Volume) > 0 {
-			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.Volume)))
+			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", numfmt.Series(data.Volume)))
 		}
 	}
 
 	if indicators.EnableEMA {
 		if len(data.EMA20Values) > 0 {
-			sb.WriteString(fmt.Sprintf("EMA20: %s\n", formatFloatSlice(data.EMA20Values)))
+			sb.WriteString(fmt.Sprintf("EMA20: %s\n", numfmt.Series(data.EMA20Values)))
 		}
 		if len(data.EMA50Values) > 0 {
-			sb.WriteString(fmt.Sprintf("EMA50: %s\n", formatFloatSlice(data.EMA50Values)))
+			sb.WriteString(fmt.Sprintf("EMA50: %s\n", numfmt.Series(data.EMA50Values)))
 		}
 	}
 
 	if indicators.EnableMACD && len(data.MACDValues) > 0 {
-		sb.WriteString(fmt.Sprintf("MACD: %s\n", formatFloatSlice(data.MACDValues)))
+		sb.WriteString(fmt.Sprintf("MACD: %s\n", numfmt.Series(data.MACDValues)))
 	}
 
 	if indicators.EnableRSI {
 		if len(data.RSI7Values) > 0 {
-			sb.WriteString(fmt.Sprintf("RSI7: %s\n", formatFloatSlice(data.RSI7Values)))
+			sb.WriteString(fmt.Sprintf("RSI7: %s\n", numfmt.Series(data.RSI7Values)))
 		}
 		if len(data.RSI14Values) > 0 {
-			sb.WriteString(fmt.Sprintf("RSI14: %s\n", formatFloatSlice(data.RSI14Values)))
+			sb.WriteString(fmt.Sprintf("RSI14: %s\n", numfmt.Series(data.RSI14Values)))
 		}
 	}
 
@@ -1749,13 +2515,13 @@ func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *ma
 			sb.WriteString(fmt.Sprintf("Current VWAP: %.4f\n", data.CurrentVWAP))
 		}
 		if len(data.VWAPValues) > 0 {
-			sb.WriteString(fmt.Sprintf("VWAP Series: %s\n", formatFloatSlice(data.VWAPValues)))
+			sb.WriteString(fmt.Sprintf("VWAP Series: %s\n", numfmt.Series(data.VWAPValues)))
 		}
 	}
 
 	// Volume Profile
 	if indicators.EnableVolumeProfile && len(data.VolumeProfile) > 0 {
-		sb.WriteString(fmt.Sprintf("Volume Profile (price levels low→high): %s\n", formatFloatSlice(data.VolumeProfile)))
+		sb.WriteString(fmt.Sprintf("Volume Profile (price levels low→high): %s\n", numfmt.Series(data.VolumeProfile)))
 	}
 
 	sb.WriteString("\n")
@@ -1881,7 +2647,7 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 				sb.WriteString("  Institutional Futures:\n")
 				for _, tf := range timeframes {
 					if v, ok := data.Netflow.Institution.Future[tf]; ok {
-						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, formatFlowValue(v)))
+						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, numfmt.Flow(v)))
 					}
 				}
 			}
@@ -1889,7 +2655,7 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 				sb.WriteString("  Institutional Spot:\n")
 				for _, tf := range timeframes {
 					if v, ok := data.Netflow.Institution.Spot[tf]; ok {
-						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, formatFlowValue(v)))
+						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, numfmt.Flow(v)))
 					}
 				}
 			}
@@ -1900,7 +2666,7 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 				sb.WriteString("  Retail Futures:\n")
 				for _, tf := range timeframes {
 					if v, ok := data.Netflow.Personal.Future[tf]; ok {
-						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, formatFlowValue(v)))
+						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, numfmt.Flow(v)))
 					}
 				}
 			}
@@ -1908,7 +2674,7 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 				sb.WriteString("  Retail Spot:\n")
 				for _, tf := range timeframes {
 					if v, ok := data.Netflow.Personal.Spot[tf]; ok {
-						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, formatFlowValue(v)))
+						sb.WriteString(fmt.Sprintf("    %s: %s\n", tf, numfmt.Flow(v)))
 					}
 				}
 			}
@@ -1921,7 +2687,7 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 				sb.WriteString(fmt.Sprintf("Open Interest (%s):\n", exchange))
 				for _, tf := range []string{"5m", "15m", "1h", "4h", "12h", "24h"} {
 					if d, ok := oiData.Delta[tf]; ok {
-						sb.WriteString(fmt.Sprintf("    %s: %+.4f%% (%s)\n", tf, d.OIDeltaPercent, formatFlowValue(d.OIDeltaValue)))
+						sb.WriteString(fmt.Sprintf("    %s: %+.4f%% (%s)\n", tf, d.OIDeltaPercent, numfmt.Flow(d.OIDeltaValue)))
 					}
 				}
 			}
@@ -1931,38 +2697,11 @@ func (e *StrategyEngine) formatQuantData(data *QuantData) string {
 	return sb.String()
 }
 
-func formatFlowValue(v float64) string {
-	sign := ""
-	if v >= 0 {
-		sign = "+"
-	}
-	absV := v
-	if absV < 0 {
-		absV = -absV
-	}
-	if absV >= 1e9 {
-		return fmt.Sprintf("%s%.2fB", sign, v/1e9)
-	} else if absV >= 1e6 {
-		return fmt.Sprintf("%s%.2fM", sign, v/1e6)
-	} else if absV >= 1e3 {
-		return fmt.Sprintf("%s%.2fK", sign, v/1e3)
-	}
-	return fmt.Sprintf("%s%.2f", sign, v)
-}
-
-func formatFloatSlice(values []float64) string {
-	strValues := make([]string, len(values))
-	for i, v := range values {
-		strValues[i] = fmt.Sprintf("%.4f", v)
-	}
-	return "[" + strings.Join(strValues, ", ") + "]"
-}
-
 // ============================================================================
 // AI Response Parsing
 // ============================================================================
 
-func parseFullDecisionResponse(aiResponse string, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64) (*FullDecision, error) {
+func parseFullDecisionResponse(aiResponse string, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64, limits PositionLimits) (*FullDecision, error) {
 	cotTrace := extractCoTTrace(aiResponse)
 
 	// Detect potentially truncated response (max_tokens reached)
@@ -1970,7 +2709,7 @@ func parseFullDecisionResponse(aiResponse string, accountEquity float64, largeCa
 	if len(trimmed) > 0 {
 		lastChar := trimmed[len(trimmed)-1]
 		if lastChar != ']' && lastChar != '}' && lastChar != '`' && !strings.HasSuffix(trimmed, "</decision>") {
-			logger.Warnf("⚠️  AI response may be truncated (last char: '%c', length: %d). Consider increasing AI_MAX_TOKENS.", lastChar, len(trimmed))
+			logger.Warnf("⚠️  AI response may be truncated (last char: '%c', length: %d). Consider increasing the strategy's ai_params.max_tokens (or AI_MAX_TOKENS).", lastChar, len(trimmed))
 		}
 	}
 
@@ -1979,20 +2718,20 @@ func parseFullDecisionResponse(aiResponse string, accountEquity float64, largeCa
 		return &FullDecision{
 			CoTTrace:  cotTrace,
 			Decisions: []Decision{},
-		}, fmt.Errorf("failed to extract decisions (response length: %d): %w", len(aiResponse), err)
+		}, fmt.Errorf("%w (response length: %d): %w", errDecisionExtraction, len(aiResponse), err)
 	}
 
-	if err := validateDecisions(decisions, accountEquity, largeCapLeverage, smallCapLeverage, largeCapPosRatio, smallCapPosRatio); err != nil {
-		return &FullDecision{
-			CoTTrace:  cotTrace,
-			Decisions: decisions,
-		}, fmt.Errorf("decision validation failed: %w", err)
+	// Invalid entries are dropped, the rest of the batch is kept
+	valid, report := validateDecisions(decisions, accountEquity, largeCapLeverage, smallCapLeverage, largeCapPosRatio, smallCapPosRatio, limits)
+	fd := &FullDecision{
+		CoTTrace:   cotTrace,
+		Decisions:  valid,
+		Validation: report,
 	}
-
-	return &FullDecision{
-		CoTTrace:  cotTrace,
-		Decisions: decisions,
-	}, nil
+	if len(valid) == 0 && report.Dropped > 0 {
+		return fd, fmt.Errorf("decision validation failed: %s", report.DroppedSummary())
+	}
+	return fd, nil
 }
 
 func extractCoTTrace(response string) string {
@@ -2015,6 +2754,18 @@ func extractCoTTrace(response string) string {
 	return strings.TrimSpace(response)
 }
 
+// withNativeReasoning puts the model's native reasoning in front of the chain of thought from its response
+func withNativeReasoning(native, cotTrace string) string {
+	if native == "" {
+		return cotTrace
+	}
+	logger.Infof("✓ Captured native reasoning (%d chars)", len(native))
+	if cotTrace == "" {
+		return "### Native Reasoning\n" + native
+	}
+	return "### Native Reasoning\n" + native + "\n\n### Response Reasoning\n" + cotTrace
+}
+
 func extractDecisions(response string) ([]Decision, error) {
 	s := removeInvisibleRunes(response)
 	s = strings.TrimSpace(s)
@@ -2031,6 +2782,15 @@ func extractDecisions(response string) ([]Decision, error) {
 
 	jsonPart = fixMissingQuotes(jsonPart)
 
+	// Schema v2 envelope: {"schema_version": N, "decisions": [...]}
+	if arrayPart, version, ok := extractDecisionEnvelope(jsonPart); ok {
+		jsonContent := fixMissingQuotes(compactArrayOpen(arrayPart))
+		if err := validateJSONFormat(jsonContent); err != nil {
+			return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
+		}
+		return decodeDecisions(jsonContent, version)
+	}
+
 	if m := reJSONFence.FindStringSubmatch(jsonPart); m != nil && len(m) > 1 {
 		jsonContent := strings.TrimSpace(m[1])
 		jsonContent = compactArrayOpen(jsonContent)
@@ -2038,11 +2798,7 @@ func extractDecisions(response string) ([]Decision, error) {
 		if err := validateJSONFormat(jsonContent); err != nil {
 			return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
 		}
-		var decisions []Decision
-		if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-			return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
-		}
-		return decisions, nil
+		return decodeDecisions(jsonContent, 0)
 	}
 
 	jsonContent := strings.TrimSpace(reJSONArray.FindString(jsonPart))
@@ -2057,7 +2813,7 @@ func extractDecisions(response string) ([]Decision, error) {
 		fallbackDecision := Decision{
 			Symbol:    "ALL",
 			Action:    "wait",
-			Reasoning: fmt.Sprintf("Model didn't output structured JSON decision, entering safe wait; summary: %s", cotSummary),
+			Reasoning: fmt.Sprintf("%s, entering safe wait; summary: %s", noJSONFallbackReasoning, cotSummary),
 		}
 
 		return []Decision{fallbackDecision}, nil
@@ -2070,12 +2826,7 @@ func extractDecisions(response string) ([]Decision, error) {
 		return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
 	}
 
-	var decisions []Decision
-	if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
-	}
-
-	return decisions, nil
+	return decodeDecisions(jsonContent, 0)
 }
 
 func fixMissingQuotes(jsonStr string) string {
@@ -2148,39 +2899,194 @@ func compactArrayOpen(s string) string {
 // Decision Validation
 // ============================================================================
 
-func validateDecisions(decisions []Decision, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64) error {
-	for i, decision := range decisions {
-		if err := validateDecision(&decision, accountEquity, largeCapLeverage, smallCapLeverage, largeCapPosRatio, smallCapPosRatio); err != nil {
-			return fmt.Errorf("decision #%d validation failed: %w", i+1, err)
+const (
+	defaultMinPositionSize         = 12.0 // Default RiskControl.MinPositionSize
+	defaultLargeCapMinPositionSize = 60.0 // Default RiskControl.LargeCapMinPositionSize
+)
+
+// PositionLimits symbol classification and minimum position sizes used by validation
+type PositionLimits struct {
+	Exchange  string                   // Exchange type for registry minimum order values ("" = any exchange)
+	Risk      store.RiskControlConfig  // Large Cap symbols, configured minimum sizes and stop ATR bounds
+	StopRefs  map[string]StopReference // Price and ATR per symbol for stop distance check (nil = skip check)
+	Blackouts []Blackout               // News blackout windows blocking opens (nil = skip check)
+	Positions []PositionInfo           // Held positions targeted by update_stops (nil = no stop updates)
+}
+
+// IsSpot whether symbol trades spot under these limits (no leverage, no short selling)
+func (l PositionLimits) IsSpot(symbol string) bool {
+	return market.IsSpotSymbol(l.Exchange, symbol)
+}
+
+// IsLargeCap checks whether symbol uses Large Cap limits
+func (l PositionLimits) IsLargeCap(symbol string) bool {
+	return l.Risk.IsLargeCap(symbol)
+}
+
+// MinPositionSize minimum position size (USD) of symbol: configured minimum, raised to exchange minimum order value
+func (l PositionLimits) MinPositionSize(symbol string) float64 {
+	minSize := l.Risk.MinPositionSize
+	if minSize <= 0 {
+		minSize = defaultMinPositionSize
+	}
+	if l.IsLargeCap(symbol) {
+		minSize = l.Risk.LargeCapMinPositionSize
+		if minSize <= 0 {
+			minSize = defaultLargeCapMinPositionSize
 		}
 	}
-	return nil
+	if exchangeMin := market.Symbols.MinNotional(l.Exchange, symbol); exchangeMin > minSize {
+		return exchangeMin
+	}
+	return minSize
+}
+
+// ValidationIssue problem found in one AI decision
+type ValidationIssue struct {
+	Index   int    `json:"index"` // 1-based position in the AI response
+	Symbol  string `json:"symbol"`
+	Action  string `json:"action"`
+	Dropped bool   `json:"dropped"` // true = removed, false = auto-repaired and kept
+	Message string `json:"message"`
+}
+
+// String formats issue as execution log line
+func (i ValidationIssue) String() string {
+	if i.Dropped {
+		return fmt.Sprintf("⚠️ Validation dropped decision #%d %s %s: %s", i.Index, i.Symbol, i.Action, i.Message)
+	}
+	return fmt.Sprintf("🔧 Validation repaired decision #%d %s %s: %s", i.Index, i.Symbol, i.Action, i.Message)
+}
+
+// ValidationReport per-decision validation results of an AI response
+type ValidationReport struct {
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Dropped int               `json:"dropped"`
+	Issues  []ValidationIssue `json:"issues,omitempty"`
 }
 
-func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64) error {
+// Merge adds results of another report (e.g. another batch)
+func (r *ValidationReport) Merge(other *ValidationReport) {
+	if other == nil {
+		return
+	}
+	r.Total += other.Total
+	r.Valid += other.Valid
+	r.Dropped += other.Dropped
+	r.Issues = append(r.Issues, other.Issues...)
+}
+
+// DroppedSummary joins reasons of dropped decisions
+func (r *ValidationReport) DroppedSummary() string {
+	var reasons []string
+	for _, issue := range r.Issues {
+		if issue.Dropped {
+			reasons = append(reasons, fmt.Sprintf("decision #%d: %s", issue.Index, issue.Message))
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// validateDecisions validates each decision, returns the valid ones and a report of dropped/repaired entries
+func validateDecisions(decisions []Decision, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64, limits PositionLimits) ([]Decision, *ValidationReport) {
+	report := &ValidationReport{Total: len(decisions)}
+	valid := make([]Decision, 0, len(decisions))
+	for i := range decisions {
+		original := decisions[i]
+		// Validate in place so auto-adjustments (leverage, size, stop distance) are kept
+		if err := validateDecision(&decisions[i], accountEquity, largeCapLeverage, smallCapLeverage, largeCapPosRatio, smallCapPosRatio, limits); err != nil {
+			logger.Warnf("⚠️  Decision #%d (%s %s) dropped: %v", i+1, original.Symbol, original.Action, err)
+			report.Dropped++
+			report.Issues = append(report.Issues, ValidationIssue{
+				Index: i + 1, Symbol: original.Symbol, Action: original.Action, Dropped: true, Message: err.Error(),
+			})
+			continue
+		}
+		if repairs := describeRepairs(original, decisions[i]); repairs != "" {
+			report.Issues = append(report.Issues, ValidationIssue{
+				Index: i + 1, Symbol: original.Symbol, Action: original.Action, Message: repairs,
+			})
+		}
+		valid = append(valid, decisions[i])
+	}
+	report.Valid = len(valid)
+	return valid, report
+}
+
+// describeRepairs lists fields auto-adjusted by validation (empty if none)
+func describeRepairs(before, after Decision) string {
+	var repairs []string
+	if before.Action != after.Action {
+		repairs = append(repairs, fmt.Sprintf("action %s → %s", before.Action, after.Action))
+	}
+	if before.Leverage != after.Leverage {
+		repairs = append(repairs, fmt.Sprintf("leverage %dx → %dx", before.Leverage, after.Leverage))
+	}
+	if before.PositionSizeUSD != after.PositionSizeUSD {
+		repairs = append(repairs, fmt.Sprintf("position size %.2f → %.2f USD", before.PositionSizeUSD, after.PositionSizeUSD))
+	}
+	if before.StopLoss != after.StopLoss {
+		repairs = append(repairs, fmt.Sprintf("stop loss %.4f → %.4f", before.StopLoss, after.StopLoss))
+	}
+	return strings.Join(repairs, ", ")
+}
+
+func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smallCapLeverage int, largeCapPosRatio, smallCapPosRatio float64, limits PositionLimits) error {
 	validActions := map[string]bool{
-		"open_long":   true,
-		"open_short":  true,
-		"close_long":  true,
-		"close_short": true,
-		"hold":        true,
-		"wait":        true,
+		"open_long":    true,
+		"open_short":   true,
+		"close_long":   true,
+		"close_short":  true,
+		"update_stops": true,
+		"hold":         true,
+		"wait":         true,
 	}
 
 	if !validActions[d.Action] {
 		return fmt.Errorf("invalid action: %s", d.Action)
 	}
+	if d.Action == "update_stops" {
+		return validateUpdateStops(d, limits)
+	}
+	if limits.IsSpot(d.Symbol) && applySpotRules(d) {
+		return nil
+	}
 
 	if d.Action == "open_long" || d.Action == "open_short" {
+		if err := checkBlackout(d, limits); err != nil {
+			return err
+		}
+
 		maxLeverage := smallCapLeverage
 		posRatio := smallCapPosRatio
 		maxPositionValue := accountEquity * posRatio
-		if d.Symbol == "AAPL" || d.Symbol == "MSFT" {
+		isLargeCap := limits.IsLargeCap(d.Symbol)
+		if isLargeCap {
 			maxLeverage = largeCapLeverage
 			posRatio = largeCapPosRatio
 			maxPositionValue = accountEquity * posRatio
 		}
+		// Per-symbol overrides: leverage replaces the cap class limit, size caps the equity ratio limit
+		override, hasOverride := limits.Risk.SymbolOverrideFor(d.Symbol)
+		if hasOverride && override.MaxLeverage > 0 {
+			maxLeverage = override.MaxLeverage
+		}
+		if maxUSD := limits.Risk.MaxPositionSizeUSDFor(d.Symbol); maxUSD > 0 && maxUSD < maxPositionValue {
+			maxPositionValue = maxUSD
+		}
+		if minConfidence := limits.Risk.MinConfidenceFor(d.Symbol); minConfidence > 0 && d.Confidence < minConfidence {
+			return fmt.Errorf("%s confidence %d below the symbol's minimum %d", d.Symbol, d.Confidence, minConfidence)
+		}
+		if limits.IsSpot(d.Symbol) {
+			// Spot buys are paid in full: no leverage, at most the whole equity
+			maxLeverage = 1
+			if maxPositionValue > accountEquity {
+				maxPositionValue = accountEquity
+			}
+		}
 
+		applyVolTarget(d, limits)
 		if d.Leverage <= 0 {
 			return fmt.Errorf("leverage must be greater than 0: %d", d.Leverage)
 		}
@@ -2193,17 +3099,8 @@ func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smal
 			return fmt.Errorf("position size must be greater than 0: %.2f", d.PositionSizeUSD)
 		}
 
-		const minPositionSizeGeneral = 12.0
-		const minPositionSizeLargeCap = 60.0
-
-		if d.Symbol == "AAPL" || d.Symbol == "MSFT" {
-			if d.PositionSizeUSD < minPositionSizeLargeCap {
-				return fmt.Errorf("%s opening amount too small (%.2f USD), must be ≥%.2f USD", d.Symbol, d.PositionSizeUSD, minPositionSizeLargeCap)
-			}
-		} else {
-			if d.PositionSizeUSD < minPositionSizeGeneral {
-				return fmt.Errorf("opening amount too small (%.2f USD), must be ≥%.2f USD", d.PositionSizeUSD, minPositionSizeGeneral)
-			}
+		if minPositionSize := limits.MinPositionSize(d.Symbol); d.PositionSizeUSD < minPositionSize {
+			return fmt.Errorf("%s opening amount too small (%.2f USD), must be ≥%.2f USD", d.Symbol, d.PositionSizeUSD, minPositionSize)
 		}
 
 		tolerance := maxPositionValue * 0.01
@@ -2211,7 +3108,7 @@ func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smal
 			// Auto-adjust position size to max allowed (like we do for leverage)
 			originalSize := d.PositionSizeUSD
 			d.PositionSizeUSD = maxPositionValue
-			if d.Symbol == "AAPL" || d.Symbol == "MSFT" {
+			if isLargeCap {
 				logger.Infof("⚠️  [Position Size Fallback] %s Large Cap position size exceeded (%.0f > %.0f USD), auto-adjusting to limit %.0f USD",
 					d.Symbol, originalSize, maxPositionValue, d.PositionSizeUSD)
 			} else {
@@ -2219,9 +3116,13 @@ func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smal
 					d.Symbol, originalSize, maxPositionValue, d.PositionSizeUSD)
 			}
 		}
+		if err := normalizeTakeProfitLevels(d); err != nil {
+			return err
+		}
 		if d.StopLoss <= 0 || d.TakeProfit <= 0 {
 			return fmt.Errorf("stop loss and take profit must be greater than 0")
 		}
+		adjustStopToATR(d, limits)
 
 		if d.Action == "open_long" {
 			if d.StopLoss >= d.TakeProfit {
@@ -2234,12 +3135,30 @@ func validateDecision(d *Decision, accountEquity float64, largeCapLeverage, smal
 		}
 
 		var entryPrice float64
-		if d.Action == "open_long" {
+		if d.EntryPrice < 0 {
+			return fmt.Errorf("entry price cannot be negative: %.4f", d.EntryPrice)
+		} else if d.EntryPrice > 0 {
+			// Limit entry: stop and target must bracket the entry price
+			if d.Action == "open_long" && (d.EntryPrice <= d.StopLoss || d.EntryPrice >= d.TakeProfit) {
+				return fmt.Errorf("for long limit entries, entry price %.4f must be between stop loss %.4f and take profit %.4f", d.EntryPrice, d.StopLoss, d.TakeProfit)
+			}
+			if d.Action == "open_short" && (d.EntryPrice >= d.StopLoss || d.EntryPrice <= d.TakeProfit) {
+				return fmt.Errorf("for short limit entries, entry price %.4f must be between take profit %.4f and stop loss %.4f", d.EntryPrice, d.TakeProfit, d.StopLoss)
+			}
+			entryPrice = d.EntryPrice
+		} else if d.Action == "open_long" {
 			entryPrice = d.StopLoss + (d.TakeProfit-d.StopLoss)*0.2
 		} else {
 			entryPrice = d.StopLoss - (d.StopLoss-d.TakeProfit)*0.2
 		}
 
+		if err := checkLiquidationDistance(d, limits); err != nil {
+			return err
+		}
+		if err := enforceRiskPerTrade(d, accountEquity, entryPrice, limits); err != nil {
+			return err
+		}
+
 		var riskPercent, rewardPercent, riskRewardRatio float64
 		if d.Action == "open_long" {
 			riskPercent = (entryPrice - d.StopLoss) / entryPrice * 100
@@ -2306,6 +3225,9 @@ func GetAlgorithmicDecision(ctx *Context, engine *StrategyEngine) (*FullDecision
 	}
 	decisions = append(decisions, safekeepingDecisions...)
 
+	// 3. No new opens in a market shock
+	decisions = engine.enforceShock(decisions, ctx)
+
 	// If no decisions, just wait/hold
 	if len(decisions) == 0 {
 		cotBuilder.WriteString("### ⏳ Final Decision\nNo algorithmic signals found across all candidates. Entering **WAIT** mode.\n")
@@ -2477,7 +3399,7 @@ func calculateVWAPSlopeStretchWithAnalysis(ctx *Context, symbol string, config *
 	analysis.WriteString("✅ **ALL CONDITIONS PASSED** → BUY SIGNAL\n\n")
 
 	posRatio := config.RiskControl.SmallCapMaxPositionValueRatio
-	if symbol == "AAPL" || symbol == "MSFT" || symbol == "TSLA" || symbol == "NVDA" || symbol == "GOOGL" || symbol == "META" || symbol == "AMZN" {
+	if config.RiskControl.IsLargeCap(symbol) {
 		posRatio = config.RiskControl.LargeCapMaxPositionValueRatio
 	}
 	if posRatio <= 0 {
@@ -0,0 +1,265 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Ensemble Decision Mode
+// ============================================================================
+// The same prepared Context is sent to 2-3 AI clients in parallel, each going
+// through the normal decision pipeline (batching, validation, self-review).
+// Their decision arrays are normalized to one decision per symbol and merged:
+//   - unanimous: an action passes only if every responding model proposes it
+//   - majority: an action passes if more than half of the responding models propose it
+//   - highest_confidence: per symbol, the decision with the highest confidence wins
+// Models that fail are left out of the vote. Each model's output is kept in
+// FullDecision.Ensemble for later comparison.
+
+const (
+	EnsembleUnanimous         = "unanimous"
+	EnsembleMajority          = "majority"
+	EnsembleHighestConfidence = "highest_confidence"
+)
+
+// EnsembleMember AI client taking part in the ensemble
+type EnsembleMember struct {
+	Name   string // Display name (AI model name)
+	Client mcp.AIClient
+}
+
+// EnsembleModelOutput decision output of one ensemble member
+type EnsembleModelOutput struct {
+	Name        string     `json:"name"`
+	Provider    string     `json:"provider"`
+	Model       string     `json:"model"`
+	Decisions   []Decision `json:"decisions,omitempty"`
+	RawResponse string     `json:"raw_response,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+}
+
+// EnsembleResult per-model outputs of an ensemble decision
+type EnsembleResult struct {
+	MergeRule string                `json:"merge_rule"`
+	Models    []EnsembleModelOutput `json:"models"`
+}
+
+// GetEnsembleDecision fans ctx out to all members and merges their decisions with rule
+func GetEnsembleDecision(ctx *Context, members []EnsembleMember, engine *StrategyEngine, variant, rule string) (*FullDecision, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("ensemble has no members")
+	}
+	if rule == "" {
+		rule = EnsembleMajority
+	}
+
+	// Market data and shared context are prepared once, members only read ctx
+	if err := prepareDecisionContext(ctx, engine); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("🎼 [Ensemble] Requesting decisions from %d models (rule: %s)", len(members), rule)
+	results := make([]*FullDecision, len(members))
+	outputs := make([]EnsembleModelOutput, len(members))
+	errs := make([]error, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m EnsembleMember) {
+			defer wg.Done()
+			start := time.Now()
+			fd, err := runDecisionPipeline(ctx, m.Client, engine, variant)
+			out := EnsembleModelOutput{
+				Name:       m.Name,
+				Provider:   m.Client.GetProvider(),
+				Model:      m.Client.GetModel(),
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if fd != nil {
+				out.RawResponse = fd.RawResponse
+			}
+			if err != nil {
+				errs[i] = err
+				out.Error = err.Error()
+				logger.Warnf("⚠️  [Ensemble] %s failed: %v", m.Name, err)
+			} else {
+				out.Decisions = fd.Decisions
+				results[i] = fd
+			}
+			outputs[i] = out
+		}(i, m)
+	}
+	wg.Wait()
+
+	var succeeded []int
+	for i, fd := range results {
+		if fd != nil {
+			succeeded = append(succeeded, i)
+		}
+	}
+	if len(succeeded) == 0 {
+		for _, err := range errs {
+			if !IsAITimeout(err) {
+				return nil, fmt.Errorf("all %d ensemble models failed: %s", len(members), outputs[0].Error)
+			}
+		}
+		return nil, fmt.Errorf("all %d ensemble models failed: %w", len(members), errCycleDeadline)
+	}
+
+	votes := make([][]Decision, 0, len(succeeded))
+	for _, i := range succeeded {
+		votes = append(votes, results[i].Decisions)
+	}
+	merged := mergeEnsembleDecisions(votes, rule)
+
+	// Combine prompts, reasoning and raw output of all members for the record
+	first := results[succeeded[0]]
+	fd := &FullDecision{
+		SystemPrompt:      first.SystemPrompt,
+		UserPrompt:        first.UserPrompt,
+		Decisions:         merged,
+		Timestamp:         time.Now(),
+		Validation:        &ValidationReport{},
+		Ensemble:          &EnsembleResult{MergeRule: rule, Models: outputs},
+		PromptCompression: first.PromptCompression,
+	}
+	var cots, raws []string
+	for i, out := range outputs {
+		header := fmt.Sprintf("## Model %s (%s/%s)", out.Name, out.Provider, out.Model)
+		if out.Error != "" {
+			cots = append(cots, header+" — FAILED\nError: "+out.Error)
+		} else {
+			cots = append(cots, header+"\n"+results[i].CoTTrace)
+			fd.Validation.Merge(results[i].Validation)
+			if results[i].AIRequestDurationMs > fd.AIRequestDurationMs {
+				fd.AIRequestDurationMs = results[i].AIRequestDurationMs
+			}
+		}
+		raws = append(raws, fmt.Sprintf("===MODEL: %s===\n%s", out.Name, out.RawResponse))
+	}
+	fd.CoTTrace = strings.Join(cots, "\n\n---\n\n")
+	fd.RawResponse = strings.Join(raws, "\n\n")
+
+	logger.Infof("🎼 [Ensemble] %d/%d models responded, %d merged decisions", len(succeeded), len(members), len(merged))
+	return fd, nil
+}
+
+// isActionable whether action changes positions (open/close)
+func isActionable(action string) bool {
+	return strings.HasPrefix(action, "open_") || strings.HasPrefix(action, "close_")
+}
+
+// normalizeEnsembleVote reduces a model's decisions to one per symbol
+// Actionable decisions take precedence over hold/wait; "ALL" placeholders are ignored.
+func normalizeEnsembleVote(decisions []Decision) map[string]Decision {
+	vote := make(map[string]Decision)
+	for _, d := range decisions {
+		d.Symbol = strings.ToUpper(strings.TrimSpace(d.Symbol))
+		d.Action = strings.ToLower(strings.TrimSpace(d.Action))
+		if d.Symbol == "" || d.Symbol == "ALL" {
+			continue
+		}
+		if existing, ok := vote[d.Symbol]; ok && (isActionable(existing.Action) || !isActionable(d.Action)) {
+			continue
+		}
+		vote[d.Symbol] = d
+	}
+	return vote
+}
+
+// mergeEnsembleDecisions merges decisions of models with rule
+func mergeEnsembleDecisions(votes [][]Decision, rule string) []Decision {
+	normalized := make([]map[string]Decision, len(votes))
+	symbolSet := make(map[string]bool)
+	for i, v := range votes {
+		normalized[i] = normalizeEnsembleVote(v)
+		for symbol := range normalized[i] {
+			symbolSet[symbol] = true
+		}
+	}
+	symbols := make([]string, 0, len(symbolSet))
+	for symbol := range symbolSet {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var merged []Decision
+	for _, symbol := range symbols {
+		// Models without a decision for the symbol count as "wait"
+		proposals := make([]Decision, len(normalized))
+		counts := make(map[string]int)
+		for i, vote := range normalized {
+			d, ok := vote[symbol]
+			if !ok {
+				d = Decision{Symbol: symbol, Action: "wait"}
+			}
+			proposals[i] = d
+			counts[d.Action]++
+		}
+
+		var winner *Decision
+		switch rule {
+		case EnsembleHighestConfidence:
+			for i := range proposals {
+				if winner == nil || proposals[i].Confidence > winner.Confidence {
+					winner = &proposals[i]
+				}
+			}
+		default:
+			required := len(proposals)/2 + 1
+			if rule == EnsembleUnanimous {
+				required = len(proposals)
+			}
+			for i := range proposals {
+				p := &proposals[i]
+				if counts[p.Action] >= required && (winner == nil || p.Confidence > winner.Confidence) {
+					winner = p
+				}
+			}
+		}
+
+		summary := ensembleVoteSummary(proposals)
+		if winner == nil || !isActionable(winner.Action) {
+			action := "wait"
+			if winner != nil {
+				action = winner.Action
+			}
+			merged = append(merged, Decision{
+				Symbol:    symbol,
+				Action:    action,
+				Reasoning: fmt.Sprintf("[Ensemble %s] no action agreed (%s)", rule, summary),
+			})
+			continue
+		}
+		d := *winner
+		d.Reasoning = fmt.Sprintf("[Ensemble %s %d/%d: %s] %s", rule, counts[d.Action], len(proposals), summary, d.Reasoning)
+		merged = append(merged, d)
+	}
+
+	if len(merged) == 0 {
+		merged = append(merged, Decision{Symbol: "ALL", Action: "wait", Reasoning: "[Ensemble] no decisions from any model"})
+	}
+	return merged
+}
+
+// ensembleVoteSummary formats proposals as "open_long 80, wait, open_long 65"
+func ensembleVoteSummary(proposals []Decision) string {
+	parts := make([]string, len(proposals))
+	for i, p := range proposals {
+		parts[i] = p.Action
+		if p.Confidence > 0 {
+			parts[i] += fmt.Sprintf(" %d", p.Confidence)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
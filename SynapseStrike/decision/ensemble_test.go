@@ -0,0 +1,64 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"strings"
+	"testing"
+)
+
+// TestMergeEnsembleDecisions tests merge rules over per-model decisions
+func TestMergeEnsembleDecisions(t *testing.T) {
+	votes := [][]Decision{
+		{{Symbol: "AAPL", Action: "open_long", Confidence: 70}, {Symbol: "MSFT", Action: "open_short", Confidence: 90}},
+		{{Symbol: "AAPL", Action: "open_long", Confidence: 80}, {Symbol: "MSFT", Action: "wait"}},
+		{{Symbol: "AAPL", Action: "wait"}, {Symbol: "MSFT", Action: "hold"}, {Symbol: "ALL", Action: "wait"}},
+	}
+
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{EnsembleUnanimous, "AAPL:wait,MSFT:wait"},
+		{EnsembleMajority, "AAPL:open_long,MSFT:wait"},
+		{EnsembleHighestConfidence, "AAPL:open_long,MSFT:open_short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			merged := mergeEnsembleDecisions(votes, tt.rule)
+			if got := strings.Join(decisionActions(merged), ","); got != tt.want {
+				t.Errorf("merged = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// Majority picks parameters of the most confident agreeing model
+	merged := mergeEnsembleDecisions(votes, EnsembleMajority)
+	if merged[0].Confidence != 80 || !strings.Contains(merged[0].Reasoning, "2/3") {
+		t.Errorf("unexpected majority decision: %+v", merged[0])
+	}
+}
+
+// TestGetEnsembleDecision tests fan-out to members with one failing model
+func TestGetEnsembleDecision(t *testing.T) {
+	ok1 := mcp.NewMockClient(waitResponse("SYM0", "close_long"))
+	ok2 := mcp.NewMockClient(waitResponse("SYM0", "close_long"))
+	bad := mcp.NewMockClient(`<decision>[{"symbol": "SYM0", "action": }]</decision>`)
+	members := []EnsembleMember{{Name: "a", Client: ok1}, {Name: "b", Client: ok2}, {Name: "c", Client: bad}}
+
+	fd, err := GetEnsembleDecision(newBatchContext(1), members, newFixtureEngine(), "balanced", EnsembleUnanimous)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); got != "SYM0:close_long" {
+		t.Errorf("merged decisions = %s, want SYM0:close_long", got)
+	}
+	if fd.Ensemble == nil || len(fd.Ensemble.Models) != 3 {
+		t.Fatalf("expected outputs of 3 models, got %+v", fd.Ensemble)
+	}
+	if fd.Ensemble.Models[2].Error == "" || fd.Ensemble.Models[2].RawResponse == "" {
+		t.Errorf("failed model should keep error and raw output: %+v", fd.Ensemble.Models[2])
+	}
+	if !strings.Contains(fd.RawResponse, "===MODEL: b===") {
+		t.Errorf("raw response should contain every model's output")
+	}
+}
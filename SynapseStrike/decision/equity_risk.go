@@ -0,0 +1,139 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================================================
+// Equity Curve Risk Scaling
+// ============================================================================
+// The trader reads its recent equity curve (store.Equity snapshots) and hands
+// it to ComputeEquityRisk. Walking the curve in order, risk is reduced once
+// equity is DrawdownPct below its running peak and restored once the drawdown
+// recovers to RecoveryPct, so the state survives restarts without being
+// stored. While reduced, new position sizes and the max positions count are
+// scaled by RiskScale. The multiplier is stated in the system prompt and
+// enforced on the merged decisions, like the regime scale.
+
+const (
+	defaultEquityRiskLookbackHours = 168
+	defaultEquityRiskDrawdownPct   = 5.0
+	defaultEquityRiskRecoveryPct   = 2.0
+	defaultEquityRiskScale         = 0.5
+)
+
+// EquityRiskState risk multiplier derived from the equity curve
+type EquityRiskState struct {
+	Multiplier   float64 `json:"multiplier"`    // Applied to new position sizes (1 = full risk)
+	MaxPositions int     `json:"max_positions"` // Scaled max positions (0 = unchanged)
+	Reduced      bool    `json:"reduced"`
+	DrawdownPct  float64 `json:"drawdown_pct"` // Current drawdown from peak equity %
+	PeakEquity   float64 `json:"peak_equity"`
+	Equity       float64 `json:"equity"`
+	ReduceAtPct  float64 `json:"reduce_at_pct"`
+	RestoreAtPct float64 `json:"restore_at_pct"`
+}
+
+// EquityRiskLookbackHours equity curve window of the config (default when unset)
+func EquityRiskLookbackHours(cfg store.EquityRiskConfig) int {
+	if cfg.LookbackHours <= 0 {
+		return defaultEquityRiskLookbackHours
+	}
+	return cfg.LookbackHours
+}
+
+// ComputeEquityRisk risk state after the equity curve (chronological, last value = current equity)
+// maxPositions is the configured max positions count scaled while risk is reduced.
+func ComputeEquityRisk(cfg store.EquityRiskConfig, equities []float64, maxPositions int) *EquityRiskState {
+	reduceAt := cfg.DrawdownPct
+	if reduceAt <= 0 {
+		reduceAt = defaultEquityRiskDrawdownPct
+	}
+	restoreAt := cfg.RecoveryPct
+	if restoreAt <= 0 || restoreAt >= reduceAt {
+		restoreAt = math.Min(defaultEquityRiskRecoveryPct, reduceAt/2)
+	}
+	scale := cfg.RiskScale
+	if scale <= 0 || scale > 1 {
+		scale = defaultEquityRiskScale
+	}
+
+	state := &EquityRiskState{Multiplier: 1, ReduceAtPct: reduceAt, RestoreAtPct: restoreAt}
+	for _, equity := range equities {
+		if equity <= 0 {
+			continue
+		}
+		state.Equity = equity
+		state.PeakEquity = math.Max(state.PeakEquity, equity)
+		state.DrawdownPct = (state.PeakEquity - equity) / state.PeakEquity * 100
+		switch {
+		case !state.Reduced && state.DrawdownPct >= reduceAt:
+			state.Reduced = true
+		case state.Reduced && state.DrawdownPct <= restoreAt:
+			state.Reduced = false
+		}
+	}
+
+	if state.Reduced {
+		state.Multiplier = scale
+		if maxPositions > 0 {
+			state.MaxPositions = max(1, int(math.Floor(float64(maxPositions)*scale)))
+		}
+	}
+	return state
+}
+
+// enforceEquityRisk scales new position sizes and caps opens while equity curve risk is reduced
+func (e *StrategyEngine) enforceEquityRisk(decisions []Decision, ctx *Context) []Decision {
+	state := ctx.EquityRisk
+	if state == nil || !state.Reduced {
+		return decisions
+	}
+
+	openSlots := state.MaxPositions - len(ctx.Positions) // Only used when MaxPositions is set
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Action != "open_long" && d.Action != "open_short" {
+			continue
+		}
+		if state.MaxPositions > 0 && openSlots <= 0 {
+			logger.Warnf("🛡️  [Equity Risk] Blocked %s %s: %.2f%% drawdown allows %d positions", d.Action, d.Symbol, state.DrawdownPct, state.MaxPositions)
+			d.Reasoning = fmt.Sprintf("[Equity risk blocked %s: %.2f%% drawdown allows %d positions] %s",
+				d.Action, state.DrawdownPct, state.MaxPositions, d.Reasoning)
+			d.Action = "wait"
+			continue
+		}
+		openSlots--
+		if d.PositionSizeUSD > 0 {
+			original := d.PositionSizeUSD
+			d.PositionSizeUSD = original * state.Multiplier
+			logger.Infof("🛡️  [Equity Risk] %s %s size scaled %.2f → %.2f (drawdown %.2f%%, ×%.2f)",
+				d.Action, d.Symbol, original, d.PositionSizeUSD, state.DrawdownPct, state.Multiplier)
+		}
+	}
+	return decisions
+}
+
+// formatEquityRisk equity risk section of the system prompt (empty if not computed)
+func formatEquityRisk(state *EquityRiskState) string {
+	if state == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("# Equity Curve Risk Scaling (CODE ENFORCED)\n\n")
+	sb.WriteString(fmt.Sprintf("- Current risk multiplier: ×%.2f (drawdown %.2f%% from peak equity %.2f; risk is reduced at %.1f%% and restored at %.1f%%)\n",
+		state.Multiplier, state.DrawdownPct, state.PeakEquity, state.ReduceAtPct, state.RestoreAtPct))
+	if state.Reduced {
+		sb.WriteString(fmt.Sprintf("- ⚠️ Account in drawdown: new position sizes are scaled to %.0f%%", state.Multiplier*100))
+		if state.MaxPositions > 0 {
+			sb.WriteString(fmt.Sprintf(", max %d positions", state.MaxPositions))
+		}
+		sb.WriteString(". Prefer only the highest-conviction setups.\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"testing"
+)
+
+func TestComputeEquityRisk(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en").EquityRisk // 5% reduce, 2% restore, ×0.5
+
+	tests := []struct {
+		name     string
+		equities []float64
+		reduced  bool
+	}{
+		{"no drawdown", []float64{1000, 1020, 1010}, false},
+		{"drawdown reached", []float64{1000, 1100, 1040}, true},
+		{"partial recovery stays reduced", []float64{1000, 1100, 1040, 1070}, true},
+		{"recovered within restore threshold", []float64{1000, 1100, 1040, 1080}, false},
+		{"new peak measured from the high", []float64{1000, 940, 990, 1200, 1150}, false},
+	}
+	for _, tt := range tests {
+		state := ComputeEquityRisk(cfg, tt.equities, 3)
+		if state.Reduced != tt.reduced {
+			t.Errorf("%s: reduced = %v (drawdown %.2f%%), want %v", tt.name, state.Reduced, state.DrawdownPct, tt.reduced)
+		}
+	}
+
+	state := ComputeEquityRisk(cfg, []float64{1000, 1100, 1040}, 3)
+	if state.Multiplier != 0.5 || state.MaxPositions != 1 {
+		t.Errorf("reduced state = %+v, want ×0.5 and 1 position", state)
+	}
+
+	engine := NewStrategyEngine(&store.StrategyConfig{})
+	ctx := &Context{EquityRisk: state}
+	decisions := engine.enforceEquityRisk([]Decision{
+		{Symbol: "MSFT", Action: "open_long", PositionSizeUSD: 1000},
+		{Symbol: "TSLA", Action: "open_short", PositionSizeUSD: 1000},
+	}, ctx)
+	if decisions[0].PositionSizeUSD != 500 || decisions[1].Action != "wait" {
+		t.Errorf("expected first open halved and second blocked, got %+v", decisions)
+	}
+}
@@ -0,0 +1,60 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"time"
+)
+
+// ============================================================================
+// Decision Explainability
+// ============================================================================
+// For every executed open, the indicator values the AI saw in its prompt are
+// snapshotted and stored with the position (trader_positions.entry_indicators).
+// Closed trades joined with their entry-time indicators are served by
+// GET /api/trade-explanations for post-hoc analysis of which setups pay off.
+
+// SnapshotIndicators indicator values of symbol as presented in ctx (nil without market data)
+// VWAP is the anchored session VWAP when available, the primary timeframe VWAP otherwise.
+func (e *StrategyEngine) SnapshotIndicators(ctx *Context, symbol string) *store.EntryIndicators {
+	if ctx == nil {
+		return nil
+	}
+	data, ok := ctx.MarketDataMap[symbol]
+	if !ok || data == nil {
+		return nil
+	}
+
+	snap := &store.EntryIndicators{
+		DecisionTime: time.Now().UTC(),
+		Price:        data.CurrentPrice,
+		RSI7:         data.CurrentRSI7,
+		MACD:         data.CurrentMACD,
+		EMA20:        data.CurrentEMA20,
+	}
+	if series := data.IntradaySeries; series != nil && len(series.RSI14Values) > 0 {
+		snap.RSI14 = series.RSI14Values[len(series.RSI14Values)-1]
+	}
+	if extra := data.StockExtraData; extra != nil {
+		snap.VWAP = extra.AnchoredVWAP
+		snap.VolumeRatio = extra.VolumeRatio
+	}
+	if tf, ok := data.TimeframeData[e.config.Indicators.Klines.PrimaryTimeframe]; ok && tf != nil {
+		if snap.VWAP == 0 {
+			snap.VWAP = tf.CurrentVWAP
+		}
+		if snap.RSI14 == 0 && len(tf.RSI14Values) > 0 {
+			snap.RSI14 = tf.RSI14Values[len(tf.RSI14Values)-1]
+		}
+	}
+	if snap.VWAP > 0 && snap.Price > 0 {
+		snap.VWAPDevPct = (snap.Price - snap.VWAP) / snap.VWAP * 100
+	}
+	if confluence := ctx.ConfluenceMap[symbol]; confluence != nil {
+		snap.Confluence = confluence.Direction
+		snap.ConfluenceScore = confluence.Score
+	}
+	if oi := ctx.OITopDataMap[symbol]; oi != nil {
+		snap.OIDeltaPct = oi.OIDeltaPercent
+	}
+	return snap
+}
@@ -0,0 +1,37 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"math"
+	"testing"
+)
+
+// TestSnapshotIndicators tests that the snapshot carries the values the AI saw for the symbol
+func TestSnapshotIndicators(t *testing.T) {
+	engine := newFixtureEngine()
+	ctx := newBatchContext(2)
+	data := ctx.MarketDataMap["SYM1"]
+	data.CurrentRSI7 = 72
+	data.CurrentMACD = 0.4
+	data.IntradaySeries = &market.IntradayData{RSI14Values: []float64{50, 64}}
+	data.StockExtraData = &market.StockExtraData{AnchoredVWAP: 98, VolumeRatio: 2.5}
+	ctx.OITopDataMap["SYM1"] = &OITopData{OIDeltaPercent: 3}
+	ctx.ConfluenceMap = map[string]*ConfluenceScore{"SYM1": {Direction: TrendBullish, Score: 0.75}}
+
+	snap := engine.SnapshotIndicators(ctx, "SYM1")
+	if snap == nil {
+		t.Fatal("expected snapshot")
+	}
+	if snap.RSI7 != 72 || snap.RSI14 != 64 || snap.MACD != 0.4 || snap.VolumeRatio != 2.5 || snap.OIDeltaPct != 3 {
+		t.Errorf("unexpected indicator values: %+v", snap)
+	}
+	if snap.VWAP != 98 || math.Abs(snap.VWAPDevPct-2.0408) > 0.001 {
+		t.Errorf("unexpected VWAP: %.2f (%.4f%%)", snap.VWAP, snap.VWAPDevPct)
+	}
+	if snap.Confluence != TrendBullish || snap.ConfluenceScore != 0.75 {
+		t.Errorf("unexpected confluence: %s %.2f", snap.Confluence, snap.ConfluenceScore)
+	}
+	if engine.SnapshotIndicators(ctx, "UNKNOWN") != nil {
+		t.Error("expected nil snapshot without market data")
+	}
+}
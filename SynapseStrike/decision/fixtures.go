@@ -0,0 +1,103 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// LLM Response Fixtures
+// ============================================================================
+// Malformed AI responses seen in production are captured to disk so they can
+// be replayed in unit tests (see testdata/llm_fixtures). Capture is enabled by
+// setting LLM_FIXTURE_DIR; captured files are reviewed, given expectations and
+// copied into testdata by hand.
+
+// LLMFixture captured AI response with expected parse outcome
+type LLMFixture struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	CapturedAt    time.Time `json:"captured_at,omitempty"`
+	Provider      string    `json:"provider,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	AccountEquity float64   `json:"account_equity"`
+	Response      string    `json:"response"`
+	ParseError    string    `json:"parse_error,omitempty"`    // Error observed at capture time
+	ExpectError   bool      `json:"expect_error"`             // Whether parsing is expected to fail
+	ExpectActions []string  `json:"expect_actions,omitempty"` // Expected "SYMBOL:action" list (in order)
+	ExpectCoT     bool      `json:"expect_cot,omitempty"`     // Whether a non-empty CoT trace is expected
+}
+
+var reFixtureNameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// captureLLMFixture saves AI response that failed to parse (no-op unless LLM_FIXTURE_DIR is set)
+func captureLLMFixture(provider, model string, accountEquity float64, response string, parseErr error) {
+	dir := os.Getenv("LLM_FIXTURE_DIR")
+	if dir == "" || parseErr == nil {
+		return
+	}
+
+	now := time.Now()
+	name := reFixtureNameUnsafe.ReplaceAllString(fmt.Sprintf("%s_%s", provider, now.Format("20060102_150405.000")), "_")
+	fixture := LLMFixture{
+		Name:          name,
+		CapturedAt:    now,
+		Provider:      provider,
+		Model:         model,
+		AccountEquity: accountEquity,
+		Response:      response,
+		ParseError:    parseErr.Error(),
+		ExpectError:   true,
+	}
+
+	if err := SaveLLMFixture(dir, &fixture); err != nil {
+		logger.Warnf("⚠️  Failed to capture LLM fixture: %v", err)
+		return
+	}
+	logger.Infof("🧪 Captured malformed AI response as fixture: %s", name)
+}
+
+// SaveLLMFixture writes fixture as <dir>/<name>.json
+func SaveLLMFixture(dir string, fixture *LLMFixture) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize fixture: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, fixture.Name+".json"), data, 0644)
+}
+
+// LoadLLMFixtures loads all *.json fixtures from directory (sorted by file name)
+func LoadLLMFixtures(dir string) ([]*LLMFixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fixtures := make([]*LLMFixture, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", file, err)
+		}
+		var fixture LLMFixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+		}
+		if fixture.Name == "" {
+			fixture.Name = strings.TrimSuffix(filepath.Base(file), ".json")
+		}
+		fixtures = append(fixtures, &fixture)
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,249 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fixtureDir = "testdata/llm_fixtures"
+
+// newFixtureEngine creates strategy engine with default risk config
+func newFixtureEngine() *StrategyEngine {
+	cfg := store.GetDefaultStrategyConfig("en")
+	return NewStrategyEngine(&cfg)
+}
+
+// decisionActions formats decisions as "SYMBOL:action" list
+func decisionActions(decisions []Decision) []string {
+	actions := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		actions = append(actions, d.Symbol+":"+d.Action)
+	}
+	return actions
+}
+
+// TestParseFullDecisionResponse_Fixtures replays captured LLM outputs through the parser
+func TestParseFullDecisionResponse_Fixtures(t *testing.T) {
+	fixtures, err := LoadLLMFixtures(fixtureDir)
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found")
+	}
+
+	risk := newFixtureEngine().GetRiskControlConfig()
+	for _, fx := range fixtures {
+		t.Run(fx.Name, func(t *testing.T) {
+			fd, err := parseFullDecisionResponse(fx.Response, fx.AccountEquity,
+				risk.LargeCapMaxMargin, risk.SmallCapMaxMargin,
+				risk.LargeCapMaxPositionValueRatio, risk.SmallCapMaxPositionValueRatio,
+				PositionLimits{Risk: risk})
+
+			if fx.ExpectError {
+				if err == nil {
+					t.Fatalf("expected error, got decisions %v", decisionActions(fd.Decisions))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := decisionActions(fd.Decisions)
+			if strings.Join(got, ",") != strings.Join(fx.ExpectActions, ",") {
+				t.Errorf("actions = %v, want %v", got, fx.ExpectActions)
+			}
+			if fx.ExpectCoT && fd.CoTTrace == "" {
+				t.Error("expected non-empty CoT trace")
+			}
+		})
+	}
+}
+
+// TestSaveAndLoadLLMFixture tests fixture round trip
+func TestSaveAndLoadLLMFixture(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("LLM_FIXTURE_DIR", dir)
+
+	captureLLMFixture("deepseek", "deepseek-chat", 500, "<decision>[{", errors.New("boom"))
+
+	fixtures, err := LoadLLMFixtures(dir)
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("expected 1 fixture, got %d", len(fixtures))
+	}
+	fx := fixtures[0]
+	if fx.Response != "<decision>[{" || fx.ParseError != "boom" || !fx.ExpectError || fx.AccountEquity != 500 {
+		t.Errorf("unexpected fixture: %+v", fx)
+	}
+}
+
+// newBatchContext creates context with n candidates and no network dependencies
+func newBatchContext(n int) *Context {
+	ctx := &Context{
+		Account:       AccountInfo{TotalEquity: 1000, AvailableBalance: 1000},
+		MarketDataMap: make(map[string]*market.Data),
+		OITopDataMap:  make(map[string]*OITopData),
+	}
+	for i := 0; i < n; i++ {
+		symbol := fmt.Sprintf("SYM%d", i)
+		ctx.CandidateStocks = append(ctx.CandidateStocks, CandidateStock{Symbol: symbol})
+		ctx.MarketDataMap[symbol] = &market.Data{Symbol: symbol, CurrentPrice: 100}
+	}
+	return ctx
+}
+
+// waitResponse builds valid AI response containing a single decision
+func waitResponse(symbol, action string) string {
+	return fmt.Sprintf(`<reasoning>%s analysis</reasoning><decision>[{"symbol":"%s","action":"%s","reasoning":"test"}]</decision>`,
+		symbol, symbol, action)
+}
+
+// TestGetFullDecisionWithStrategy_Batching tests that candidates are split into batches and merged
+func TestGetFullDecisionWithStrategy_Batching(t *testing.T) {
+	client := mcp.NewMockClient(
+		waitResponse("SYM0", "hold"),
+		waitResponse("SYM2", "close_long"),
+		waitResponse("SYM4", "hold"),
+	)
+
+	fd, err := GetFullDecisionWithStrategy(newBatchContext(5), client, newFixtureEngine(), "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.CallCount() != 3 {
+		t.Errorf("expected 3 AI calls for 5 candidates, got %d", client.CallCount())
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); got != "SYM0:hold,SYM2:close_long,SYM4:hold" {
+		t.Errorf("unexpected merged decisions: %s", got)
+	}
+	if strings.Count(fd.RawResponse, "===BATCH SEPARATOR===") != 2 {
+		t.Errorf("expected raw responses of 3 batches to be merged")
+	}
+
+	// Each batch prompt should only contain its own candidates
+	calls := client.Calls()
+	if !strings.Contains(calls[1].UserPrompt, "SYM2") || strings.Contains(calls[1].UserPrompt, "SYM0") {
+		t.Errorf("batch 2 prompt should only contain SYM2/SYM3")
+	}
+}
+
+// TestGetFullDecisionWithStrategy_BatchFailure tests that failed batches are skipped
+func TestGetFullDecisionWithStrategy_BatchFailure(t *testing.T) {
+	client := mcp.NewMockClient(waitResponse("SYM0", "hold"))
+	client.AddError(errors.New("status 429"))
+	client.AddResponse("<reasoning>truncated</reasoning><decision>[{\"symbol\":\"SYM4\",\"action\":\"buy\"}]</decision>")
+	engine := newFixtureEngine()
+	engine.GetConfig().BatchRetry.MaxRetries = 0
+
+	fd, err := GetFullDecisionWithStrategy(newBatchContext(5), client, engine, "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); !strings.HasPrefix(got, "SYM0:hold") {
+		t.Errorf("expected decision from successful batch, got %s", got)
+	}
+	if !strings.Contains(fd.CoTTrace, "FAILED") {
+		t.Error("expected failed batch to be noted in CoT trace")
+	}
+}
+
+// TestGetFullDecisionWithStrategy_AllBatchesFail tests that error is returned when every batch fails
+func TestGetFullDecisionWithStrategy_AllBatchesFail(t *testing.T) {
+	client := &mcp.MockClient{Provider: mcp.ProviderMock}
+	client.AddError(errors.New("connection refused"))
+	engine := newFixtureEngine()
+	engine.GetConfig().BatchRetry.MaxRetries = 0
+
+	_, err := GetFullDecisionWithStrategy(newBatchContext(3), client, engine, "balanced")
+	if err == nil {
+		t.Fatal("expected error when all batches fail")
+	}
+	if client.CallCount() != 2 {
+		t.Errorf("expected 2 AI calls, got %d", client.CallCount())
+	}
+	if category := ErrorCategory(err); category != store.ErrorCategoryAIAPI {
+		t.Errorf("expected ai_api error category, got %q", category)
+	}
+}
+
+// TestGetFullDecisionWithStrategy_SingleBatchParseError tests that parse errors surface without batching
+func TestGetFullDecisionWithStrategy_SingleBatchParseError(t *testing.T) {
+	client := mcp.NewMockClient(`<reasoning>x</reasoning><decision>[{"symbol":"SYM0","action":"buy"}]</decision>`)
+
+	fd, err := GetFullDecisionWithStrategy(newBatchContext(1), client, newFixtureEngine(), "balanced")
+	if err == nil {
+		t.Fatal("expected parse error")
+	}
+	if fd == nil || fd.CoTTrace != "x" {
+		t.Errorf("expected partial decision with CoT trace, got %+v", fd)
+	}
+	if category := ErrorCategory(err); category != store.ErrorCategoryAIParse {
+		t.Errorf("expected ai_parse error category, got %q", category)
+	}
+}
+
+// TestGetFullDecisionWithStrategy_CycleDeadline tests that a slow batch is abandoned and remaining batches are skipped
+func TestGetFullDecisionWithStrategy_CycleDeadline(t *testing.T) {
+	client := mcp.NewMockClient()
+	client.ResponseFunc = func(systemPrompt, userPrompt string) (string, error) {
+		if strings.Contains(userPrompt, "SYM0") {
+			return waitResponse("SYM0", "hold"), nil
+		}
+		time.Sleep(500 * time.Millisecond)
+		return waitResponse("SYM2", "hold"), nil
+	}
+
+	ctx := newBatchContext(5)
+	ctx.Deadline = time.Now().Add(100 * time.Millisecond)
+
+	start := time.Now()
+	fd, err := GetFullDecisionWithStrategy(ctx, client, newFixtureEngine(), "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("deadline not enforced, took %v", elapsed)
+	}
+	if client.CallCount() != 2 {
+		t.Errorf("expected 2 AI calls before deadline, got %d", client.CallCount())
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); got != "SYM0:hold" {
+		t.Errorf("expected decisions obtained before deadline, got %s", got)
+	}
+	if !strings.Contains(fd.CoTTrace, "SKIPPED") {
+		t.Error("expected skipped batches to be noted in CoT trace")
+	}
+}
+
+// TestGetFullDecisionWithStrategy_AITimeout tests that a cycle without any decision before the deadline reports an AI timeout
+func TestGetFullDecisionWithStrategy_AITimeout(t *testing.T) {
+	client := mcp.NewMockClient()
+	client.ResponseFunc = func(systemPrompt, userPrompt string) (string, error) {
+		time.Sleep(300 * time.Millisecond)
+		return waitResponse("SYM0", "hold"), nil
+	}
+
+	ctx := newBatchContext(1)
+	ctx.Deadline = time.Now().Add(50 * time.Millisecond)
+	_, err := GetFullDecisionWithStrategy(ctx, client, newFixtureEngine(), "balanced")
+	if !IsAITimeout(err) {
+		t.Errorf("expected AI timeout, got %v", err)
+	}
+	if category := ErrorCategory(err); category != store.ErrorCategoryTimeout {
+		t.Errorf("expected timeout error category, got %q", category)
+	}
+	if IsAITimeout(fmt.Errorf("AI API call failed: %w", errors.New("status 503"))) {
+		t.Error("expected other AI failures not to count as timeout")
+	}
+}
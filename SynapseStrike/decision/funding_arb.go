@@ -0,0 +1,134 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/provider"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Funding Rate Arbitrage Candidate Source
+// ============================================================================
+// "funding_arb" ranks crypto perpetuals by funding extremes across Binance,
+// Bybit and OKX. Extreme positive funding means crowded longs paying shorts,
+// extreme negative funding the opposite; a wide cross-exchange spread is a
+// delta-neutral carry opportunity. The scan result is annotated per candidate
+// in the prompt, in the same spirit as OI Top data.
+
+const defaultFundingArbLimit = 20
+
+// FundingData funding rate annotation of a candidate (for AI decision reference)
+type FundingData struct {
+	Rank        int                // Funding extreme ranking (1 = most extreme)
+	Rates       map[string]float64 // Exchange -> current funding rate
+	AvgRate     float64            // Average funding rate across exchanges
+	Spread      float64            // Highest - lowest rate across exchanges
+	MaxExchange string             // Exchange with highest rate (short leg of carry)
+	MinExchange string             // Exchange with lowest rate (long leg of carry)
+	NextFunding time.Time          // Earliest next settlement (zero if unknown)
+}
+
+// usesFundingArb whether the configured candidate source includes the funding scanner
+func (e *StrategyEngine) usesFundingArb() bool {
+	source := e.config.CoinSource
+	return source.SourceType == "funding_arb" || (source.SourceType == "mixed" && source.UseFundingArb)
+}
+
+// scanFundingArb runs the funding scanner with strategy settings
+func (e *StrategyEngine) scanFundingArb(limit int) ([]provider.FundingArbEntry, error) {
+	source := e.config.CoinSource
+	return provider.ScanFundingArb(source.FundingArbExchanges, source.FundingArbMinRate, limit)
+}
+
+func (e *StrategyEngine) getFundingArbStocks(limit int) ([]CandidateStock, error) {
+	if limit <= 0 {
+		limit = defaultFundingArbLimit
+	}
+
+	entries, err := e.scanFundingArb(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []CandidateStock
+	for _, entry := range entries {
+		candidates = append(candidates, CandidateStock{
+			Symbol:  market.Normalize(entry.Symbol),
+			Sources: []string{"funding_arb"},
+		})
+	}
+	return candidates, nil
+}
+
+// ComputeFundingData fills ctx.FundingDataMap from the funding scan (no-op unless funding_arb source is used)
+// Scan results are cached by the provider, so this reuses the data fetched for candidate selection.
+func (e *StrategyEngine) ComputeFundingData(ctx *Context) {
+	if ctx == nil || ctx.FundingDataMap != nil || !e.usesFundingArb() {
+		return
+	}
+	entries, err := e.scanFundingArb(0)
+	if err != nil {
+		logger.Infof("⚠️  Failed to get funding data: %v", err)
+		return
+	}
+	ctx.FundingDataMap = make(map[string]*FundingData, len(entries))
+	for _, entry := range entries {
+		data := &FundingData{
+			Rank:        entry.Rank,
+			Rates:       entry.Rates,
+			AvgRate:     entry.AvgRate,
+			Spread:      entry.Spread,
+			MaxExchange: entry.MaxExchange,
+			MinExchange: entry.MinExchange,
+		}
+		if entry.NextFundingMs > 0 {
+			data.NextFunding = time.UnixMilli(entry.NextFundingMs)
+		}
+		ctx.FundingDataMap[market.Normalize(entry.Symbol)] = data
+	}
+}
+
+// formatFundingData funding annotation line of a candidate (empty if none)
+func formatFundingData(symbol string, ctx *Context) string {
+	data, ok := ctx.FundingDataMap[symbol]
+	if !ok || data == nil {
+		return ""
+	}
+
+	exchanges := make([]string, 0, len(data.Rates))
+	for exchange := range data.Rates {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	rates := make([]string, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		rates = append(rates, fmt.Sprintf("%s %s", exchange, provider.FormatFundingRate(data.Rates[exchange])))
+	}
+
+	crowded := "balanced"
+	switch {
+	case data.AvgRate > 0:
+		crowded = "longs paying shorts"
+	case data.AvgRate < 0:
+		crowded = "shorts paying longs"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Funding (rank #%d): %s | avg %s (%s)",
+		data.Rank, strings.Join(rates, ", "), provider.FormatFundingRate(data.AvgRate), crowded))
+	if len(data.Rates) > 1 {
+		sb.WriteString(fmt.Sprintf(" | spread %s (long %s / short %s)",
+			provider.FormatFundingRate(data.Spread), data.MinExchange, data.MaxExchange))
+	}
+	if !data.NextFunding.IsZero() {
+		if until := time.Until(data.NextFunding); until > 0 {
+			sb.WriteString(fmt.Sprintf(" | next settlement in %s", until.Round(time.Minute)))
+		}
+	}
+	sb.WriteString("\n\n")
+	return sb.String()
+}
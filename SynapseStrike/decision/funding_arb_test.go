@@ -0,0 +1,30 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatFundingData(t *testing.T) {
+	ctx := &Context{FundingDataMap: map[string]*FundingData{
+		"ETHUSDT": {
+			Rank:        1,
+			Rates:       map[string]float64{"okx": -0.0009, "binance": -0.0015},
+			AvgRate:     -0.0012,
+			Spread:      0.0006,
+			MaxExchange: "okx",
+			MinExchange: "binance",
+		},
+	}}
+
+	line := formatFundingData("ETHUSDT", ctx)
+	for _, want := range []string{"rank #1", "binance -0.1500%, okx -0.0900%", "shorts paying longs", "long binance / short okx"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %q in %q", want, line)
+		}
+	}
+
+	if formatFundingData("BTCUSDT", ctx) != "" {
+		t.Error("expected empty annotation for symbol without funding data")
+	}
+}
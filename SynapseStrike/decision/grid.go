@@ -0,0 +1,56 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Grid / DCA Tactic (Local Function)
+// ============================================================================
+// With Grid.Enabled the Local Function provider runs the grid tactic. Level
+// orders and per-leg take profits are placed and cancelled by the trader's grid
+// engine before each decision (trader/grid.go), which reports every grid in
+// Context.Grids. The decision step only summarizes the grids: it opens nothing
+// itself, and position safekeeping leaves grid symbols alone so the generic
+// TP/SL fallback does not close legs the engine is managing.
+
+// gridSymbols symbols traded by the configured grids
+func gridSymbols(config *store.StrategyConfig) map[string]bool {
+	symbols := make(map[string]bool)
+	if config == nil || !config.Grid.Enabled {
+		return symbols
+	}
+	for _, grid := range config.Grid.Grids {
+		symbols[grid.Symbol] = true
+	}
+	return symbols
+}
+
+// localFuncGrid reports grid state and P&L, the grid engine places the orders
+func localFuncGrid(ctx *Context, cotBuilder *strings.Builder) []Decision {
+	cotBuilder.WriteString("### Grid / DCA Tactic\n\n")
+	if len(ctx.Grids) == 0 {
+		cotBuilder.WriteString("No grid state reported (grid engine inactive or no grids configured).\n\n")
+		return []Decision{{Symbol: "ALL", Action: "wait", Reasoning: "Local Function: grid engine reported no grids"}}
+	}
+
+	var realized, unrealized float64
+	cotBuilder.WriteString("| Symbol | Range | Price | Resting | Open legs | Closed legs | Realized | Unrealized |\n")
+	cotBuilder.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, grid := range ctx.Grids {
+		cotBuilder.WriteString(fmt.Sprintf("| %s | %.4f-%.4f (%d) | %.4f | %d | %d (%.4f) | %d | %+.2f | %+.2f |\n",
+			grid.Symbol, grid.LowerPrice, grid.UpperPrice, grid.Levels, grid.Price, grid.PendingLevels,
+			grid.OpenLegs, grid.OpenQuantity, grid.ClosedLegs, grid.RealizedPnL, grid.UnrealizedPnL))
+		realized += grid.RealizedPnL
+		unrealized += grid.UnrealizedPnL
+	}
+	cotBuilder.WriteString(fmt.Sprintf("\n**Grid P&L:** realized %+.2f, unrealized %+.2f (before fees)\n\n", realized, unrealized))
+
+	return []Decision{{
+		Symbol:    "ALL",
+		Action:    "wait",
+		Reasoning: fmt.Sprintf("Local Function: grid orders managed by the grid engine (realized %+.2f, unrealized %+.2f)", realized, unrealized),
+	}}
+}
@@ -0,0 +1,31 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+)
+
+func TestLocalFuncGrid(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Grid = store.GridConfig{Enabled: true, Grids: []store.GridSpec{{Symbol: "BTCUSDT"}}}
+	if algo := detectAlgoType(&cfg); algo != "grid" {
+		t.Fatalf("expected grid algo, got %s", algo)
+	}
+	if !gridSymbols(&cfg)["BTCUSDT"] {
+		t.Error("expected BTCUSDT to be a grid symbol")
+	}
+
+	ctx := &Context{Grids: []GridInfo{
+		{Symbol: "BTCUSDT", Levels: 5, PendingLevels: 2, OpenLegs: 1, RealizedPnL: 1.5, UnrealizedPnL: -0.5},
+		{Symbol: "ETHUSDT", ClosedLegs: 3, RealizedPnL: 2},
+	}}
+	cot := &strings.Builder{}
+	decisions := localFuncGrid(ctx, cot)
+	if len(decisions) != 1 || decisions[0].Action != "wait" {
+		t.Fatalf("expected a single wait decision, got %+v", decisions)
+	}
+	if !strings.Contains(cot.String(), "realized +3.50, unrealized -0.50") {
+		t.Errorf("expected total grid P&L in CoT, got:\n%s", cot.String())
+	}
+}
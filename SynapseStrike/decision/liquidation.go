@@ -0,0 +1,60 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"fmt"
+)
+
+// ============================================================================
+// Liquidation Distance Guard
+// ============================================================================
+// A leveraged crypto position is liquidated roughly 1/leverage (less the
+// maintenance margin) away from entry. A stop loss beyond that point never
+// fires, and one just in front of it is hit by the same wick that liquidates.
+// Opens whose stop lies within LiquidationStopBufferPct (of entry) of the
+// estimated isolated-margin liquidation price are dropped. Cross margin
+// liquidates later than the estimate, so the guard errs on the safe side.
+// Like the stop distance check, the guard needs the symbol's market reference
+// (limits.StopRefs); stocks are not checked.
+
+// DefaultMaintenanceMarginRate maintenance margin rate used for liquidation estimates (lowest tier of major futures exchanges)
+const DefaultMaintenanceMarginRate = 0.005
+
+// EstimateLiquidationPrice isolated-margin liquidation price of a position (0 when leverage <= 1: no liquidation)
+func EstimateLiquidationPrice(side string, entryPrice float64, leverage int) float64 {
+	if leverage <= 1 || entryPrice <= 0 {
+		return 0
+	}
+	distance := 1/float64(leverage) - DefaultMaintenanceMarginRate
+	if side == "short" {
+		return entryPrice * (1 + distance)
+	}
+	return entryPrice * (1 - distance)
+}
+
+// checkLiquidationDistance rejects leveraged crypto opens whose stop loss is beyond or too close to liquidation
+// Distance is measured from the expected fill: entry_price for limit entries, current price otherwise.
+func checkLiquidationDistance(d *Decision, limits PositionLimits) error {
+	enabled, bufferPct, _ := limits.Risk.LiquidationGuard()
+	ref, ok := limits.StopRefs[d.Symbol]
+	if !enabled || !ok || d.Leverage <= 1 || !market.IsCrypto(d.Symbol) {
+		return nil
+	}
+	entryPrice := ref.Price
+	if d.EntryPrice > 0 {
+		entryPrice = d.EntryPrice
+	}
+
+	side := "long"
+	if d.Action == "open_short" {
+		side = "short"
+	}
+	liqPrice := EstimateLiquidationPrice(side, entryPrice, d.Leverage)
+	buffer := entryPrice * bufferPct / 100
+
+	if (side == "long" && d.StopLoss <= liqPrice+buffer) || (side == "short" && d.StopLoss >= liqPrice-buffer) {
+		return fmt.Errorf("stop loss %.4f is beyond or within %.2f%% of estimated liquidation price %.4f at %dx (entry %.4f), lower leverage or tighten the stop",
+			d.StopLoss, bufferPct, liqPrice, d.Leverage, entryPrice)
+	}
+	return nil
+}
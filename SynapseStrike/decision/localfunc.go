@@ -40,6 +40,8 @@ func GetLocalFunctionDecision(ctx *Context, engine *StrategyEngine, modelName st
 		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount))
 
 	switch algoType {
+	case "grid":
+		decisions = localFuncGrid(ctx, cotBuilder)
 	case "genetic":
 		decisions = localFuncGenetic(ctx, engine, modelName, cotBuilder)
 	case "vwaper":
@@ -63,7 +65,14 @@ func GetLocalFunctionDecision(ctx *Context, engine *StrategyEngine, modelName st
 	}
 
 	// Handle position safekeeping (TP/SL for open positions)
-	safekeepingDecisions := HandlePositionSafekeeping(ctx, engine)
+	// Grid legs are closed by their own take profits, not by safekeeping
+	var safekeepingDecisions []Decision
+	grids := gridSymbols(config)
+	for _, d := range HandlePositionSafekeeping(ctx, engine) {
+		if !grids[d.Symbol] {
+			safekeepingDecisions = append(safekeepingDecisions, d)
+		}
+	}
 	if len(safekeepingDecisions) > 0 {
 		cotBuilder.WriteString("### Position Management\n\n")
 		for _, d := range safekeepingDecisions {
@@ -462,6 +471,11 @@ func detectAlgoType(config *store.StrategyConfig) string {
 		return "unknown"
 	}
 
+	// Grid tactic replaces the signal algos
+	if config.Grid.Enabled {
+		return "grid"
+	}
+
 	// Genetic Algorithm takes priority (most specific)
 	if config.Indicators.EnableGeneticAlgo {
 		return "genetic"
@@ -0,0 +1,79 @@
+package decision
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Manual Decisions
+// ============================================================================
+// Users can enter or close positions through the API with the same decision
+// format the AI produces. Manual decisions are validated exactly like AI
+// output (size, leverage, stop distance, blackouts) and then pass the
+// code-enforced risk rules (confluence, shock, regime, equity curve) before
+// the trader executes them and records them as a regular decision record.
+
+// maxManualDecisions max decisions accepted in one manual submission
+const maxManualDecisions = 20
+
+// PrepareManualDecisions validates user-entered decisions and applies the risk enforcement of AI decisions
+// ctx is the trader's current context; market data is fetched for the decision symbols.
+// Returns an error when nothing is left to execute.
+func PrepareManualDecisions(ctx *Context, engine *StrategyEngine, decisions []Decision, submittedBy string) (*FullDecision, error) {
+	if len(decisions) == 0 {
+		return nil, fmt.Errorf("no decisions submitted")
+	}
+	if len(decisions) > maxManualDecisions {
+		return nil, fmt.Errorf("at most %d decisions per submission", maxManualDecisions)
+	}
+
+	// Market data of the submitted symbols (plus held positions) instead of the strategy's candidates
+	seen := make(map[string]bool, len(decisions))
+	candidates := make([]CandidateStock, 0, len(decisions))
+	for i := range decisions {
+		d := &decisions[i]
+		d.Symbol = strings.ToUpper(strings.TrimSpace(d.Symbol))
+		if d.Symbol == "" || d.Action == "" {
+			return nil, fmt.Errorf("decision #%d: symbol and action are required", i+1)
+		}
+		if !seen[d.Symbol] {
+			seen[d.Symbol] = true
+			candidates = append(candidates, CandidateStock{Symbol: d.Symbol, Sources: []string{"manual"}})
+		}
+	}
+	ctx.CandidateStocks = candidates
+	ctx.MarketDataMap = nil
+	if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+		return nil, fmt.Errorf("failed to fetch market data: %w", err)
+	}
+	engine.ComputeConfluence(ctx)
+	engine.ComputeRegime(ctx)
+	engine.ComputeShock(ctx)
+	engine.ComputeBlackouts(ctx)
+
+	riskConfig := engine.GetRiskControlConfig()
+	stopRefs := buildStopReferences(ctx, engine.GetConfig().Indicators.Klines.PrimaryTimeframe)
+	valid, report := validateDecisions(decisions, ctx.Account.TotalEquity,
+		riskConfig.LargeCapMaxMargin, riskConfig.SmallCapMaxMargin,
+		riskConfig.LargeCapMaxPositionValueRatio, riskConfig.SmallCapMaxPositionValueRatio,
+		PositionLimits{Exchange: ctx.Exchange, Risk: riskConfig, StopRefs: stopRefs, Blackouts: ctx.Blackouts, Positions: ctx.Positions})
+
+	fd := &FullDecision{
+		CoTTrace:   fmt.Sprintf("Manual decisions submitted by %s", submittedBy),
+		Decisions:  valid,
+		Timestamp:  time.Now(),
+		Validation: report,
+	}
+	if len(valid) == 0 {
+		return fd, fmt.Errorf("decision validation failed: %s", report.DroppedSummary())
+	}
+
+	// [CODE ENFORCED] Same risk rules as AI decisions
+	fd.Decisions = engine.enforceConfluence(fd.Decisions, ctx.ConfluenceMap)
+	fd.Decisions = engine.enforceShock(fd.Decisions, ctx)
+	fd.Decisions = engine.enforceRegime(fd.Decisions, ctx)
+	fd.Decisions = engine.enforceEquityRisk(fd.Decisions, ctx)
+	return fd, nil
+}
@@ -0,0 +1,29 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrepareManualDecisionsRejectsMalformedInput(t *testing.T) {
+	engine := &StrategyEngine{}
+	tooMany := make([]Decision, maxManualDecisions+1)
+	for i := range tooMany {
+		tooMany[i] = Decision{Symbol: "NVDA", Action: "hold"}
+	}
+	cases := []struct {
+		name      string
+		decisions []Decision
+		want      string
+	}{
+		{"empty", nil, "no decisions"},
+		{"too many", tooMany, "at most"},
+		{"missing symbol", []Decision{{Symbol: "NVDA", Action: "hold"}, {Action: "open_long"}}, "decision #2"},
+	}
+	for _, tc := range cases {
+		_, err := PrepareManualDecisions(&Context{}, engine, tc.decisions, "user test")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchMarketDataConcurrently(t *testing.T) {
+	symbols := []string{"AAPL", "MSFT", "BAD", "NVDA", "TSLA", "AMD"}
+	var inFlight, maxInFlight int32
+
+	results := fetchMarketDataConcurrently(symbols, 2, func(symbol string) (*market.Data, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if symbol == "BAD" {
+			return nil, fmt.Errorf("not found")
+		}
+		return &market.Data{Symbol: symbol}, nil
+	})
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent fetches, got %d", maxInFlight)
+	}
+	for i, symbol := range symbols {
+		r := results[i]
+		if symbol == "BAD" {
+			if r.err == nil {
+				t.Error("expected error for BAD")
+			}
+			continue
+		}
+		if r.err != nil || r.data == nil || r.data.Symbol != symbol {
+			t.Errorf("result %d: expected %s, got %+v", i, symbol, r)
+		}
+	}
+}
+
+// TestApplyMarketDataFilters tests per-source OI thresholds and the filtered out list
+func TestApplyMarketDataFilters(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.CoinSource.SourceMinOIValueUSD = map[string]float64{"static": 5_000_000, "oi_top": 30_000_000}
+
+	// OI value in USD = Latest * CurrentPrice
+	withOI := func(millions float64) *market.Data {
+		return &market.Data{CurrentPrice: 1, OpenInterest: &market.OIData{Latest: millions * 1_000_000}}
+	}
+	symbols := []string{"AUSDT", "BUSDT", "CUSDT", "DUSDT", "EUSDT", "FUSDT"}
+	results := []marketFetchResult{
+		{data: withOI(8)},                       // static: 5M bar
+		{data: withOI(20)},                      // oi_top: 30M bar
+		{data: withOI(10)},                      // ai500: default 15M bar
+		{data: withOI(10)},                      // static + oi_top: lowest bar wins
+		{err: fmt.Errorf("klines unavailable")}, // fetch failure
+		{data: withOI(1)},                       // held position: never filtered
+	}
+	ctx := &Context{
+		MarketDataMap: make(map[string]*market.Data),
+		CandidateStocks: []CandidateStock{
+			{Symbol: "AUSDT", Sources: []string{"static"}},
+			{Symbol: "BUSDT", Sources: []string{"oi_top"}},
+			{Symbol: "CUSDT", Sources: []string{"ai500"}},
+			{Symbol: "DUSDT", Sources: []string{"static", "oi_top"}},
+			{Symbol: "EUSDT", Sources: []string{"static"}},
+		},
+	}
+
+	fetchErrors := applyMarketDataFilters(ctx, &cfg, symbols, results, map[string]bool{"FUSDT": true})
+	if len(fetchErrors) != 1 {
+		t.Errorf("expected 1 fetch error, got %v", fetchErrors)
+	}
+	for _, symbol := range []string{"AUSDT", "DUSDT", "FUSDT"} {
+		if ctx.MarketDataMap[symbol] == nil {
+			t.Errorf("expected %s kept", symbol)
+		}
+	}
+
+	reasons := make(map[string]string)
+	for _, f := range ctx.FilteredOut {
+		reasons[f.Symbol] = f.Reason
+	}
+	want := map[string]string{"BUSDT": store.FilterReasonLowOI, "CUSDT": store.FilterReasonLowOI, "EUSDT": store.FilterReasonFetchFailed}
+	if len(reasons) != len(want) {
+		t.Errorf("expected filtered out %v, got %+v", want, ctx.FilteredOut)
+	}
+	for symbol, reason := range want {
+		if reasons[symbol] != reason {
+			t.Errorf("%s: expected reason %s, got %q", symbol, reason, reasons[symbol])
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Decision Memory ("has the AI seen this setup before?")
+// ============================================================================
+// Every executed open is stored with a short description of the market
+// situation and the AI's reasoning, embedded together. Once the position is
+// closed the realized outcome is attached and the memory is re-embedded with
+// it. Before each AI call the current situation of every candidate is
+// embedded and the most similar resolved setups are shown in the user prompt
+// as lessons learned.
+
+const (
+	hashEmbeddingDim        = 256  // Dimension of local hash embeddings
+	memorySearchWindow      = 500  // Most recent resolved memories considered per search
+	memoryReasoningMaxChars = 300  // Reasoning excerpt length stored with each memory
+	defaultMemoryTopK       = 3    // Default MemoryConfig.TopK
+	defaultMemorySimilarity = 0.75 // Default MemoryConfig.MinSimilarity
+
+	memoryEmbedTimeout = 15 * time.Second // Provider embedding call bound of Record/Recall (capped by the cycle deadline)
+)
+
+// MemoryRecaller finds resolved past setups similar to current situations
+type MemoryRecaller interface {
+	// Recall returns up to topK memories per symbol with similarity >= minSimilarity
+	// Provider embedding calls give up when ctx is done (local embeddings are used instead).
+	Recall(ctx context.Context, situations map[string]string, topK int, minSimilarity float64) (map[string][]*store.DecisionMemory, error)
+}
+
+// HashEmbedder local embedder using feature hashing of situation lines
+// Used when the AI provider has no embeddings API; similarity reflects shared situation features.
+type HashEmbedder struct {
+	Dim int
+}
+
+// EmbeddingModel embedding model name (vectors of different models are never compared)
+func (h *HashEmbedder) EmbeddingModel() string {
+	return fmt.Sprintf("hash-%d", h.dim())
+}
+
+// Embed hashes each non-empty line (lower-cased) into a signed bucket, L2-normalized
+func (h *HashEmbedder) Embed(texts []string) ([][]float32, error) {
+	dim := h.dim()
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, dim)
+		for _, line := range strings.Split(strings.ToLower(text), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			hasher := fnv.New64a()
+			hasher.Write([]byte(line))
+			sum := hasher.Sum64()
+			if sum>>63 == 1 {
+				v[sum%uint64(dim)] -= 1
+			} else {
+				v[sum%uint64(dim)] += 1
+			}
+		}
+		normalize(v)
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func (h *HashEmbedder) dim() int {
+	if h.Dim > 0 {
+		return h.Dim
+	}
+	return hashEmbeddingDim
+}
+
+// MemoryBank decision memory of a single trader
+type MemoryBank struct {
+	store    *store.MemoryStore
+	traderID string
+
+	embedder mcp.Embedder // Provider embedder (nil = local only)
+	fallback mcp.Embedder
+
+	mu           sync.Mutex
+	providerDown bool // Last provider call failed (warn once per outage)
+}
+
+// NewMemoryBank creates decision memory for trader
+// embedder may be nil, in which case local hash embeddings are used.
+func NewMemoryBank(st *store.MemoryStore, traderID string, embedder mcp.Embedder) *MemoryBank {
+	return &MemoryBank{
+		store:    st,
+		traderID: traderID,
+		embedder: embedder,
+		fallback: &HashEmbedder{},
+	}
+}
+
+// embed embeds texts with provider embedder, using local embeddings for this call when the provider
+// fails or does not answer before ctx is done (the provider is tried again on the next call)
+func (m *MemoryBank) embed(ctx context.Context, texts []string) ([][]float32, string, error) {
+	if m.embedder != nil {
+		type result struct {
+			vectors [][]float32
+			err     error
+		}
+		done := make(chan result, 1)
+		go func() {
+			vectors, err := m.embedder.Embed(texts)
+			done <- result{vectors, err}
+		}()
+
+		var err error
+		select {
+		case r := <-done:
+			if r.err == nil {
+				m.setProviderDown(false, nil)
+				return r.vectors, m.embedder.EmbeddingModel(), nil
+			}
+			err = r.err
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		m.setProviderDown(true, err)
+	}
+
+	vectors, err := m.fallback.Embed(texts)
+	return vectors, m.fallback.EmbeddingModel(), err
+}
+
+// setProviderDown tracks provider embedding failures, logging when an outage starts and ends
+func (m *MemoryBank) setProviderDown(down bool, err error) {
+	m.mu.Lock()
+	changed := m.providerDown != down
+	m.providerDown = down
+	m.mu.Unlock()
+	if !changed {
+		return
+	}
+	if down {
+		logger.Warnf("⚠️  [Memory] Provider embeddings unavailable, using local hash embeddings until it recovers: %v", err)
+	} else {
+		logger.Infof("🧠 [Memory] Provider embeddings available again")
+	}
+}
+
+// memoryDocument text embedded for a memory: the situation, the AI's reasoning and, once resolved, the outcome
+// Reasoning and outcome are one line each so they add a single feature to hash embeddings.
+func memoryDocument(mem *store.DecisionMemory) string {
+	lines := []string{mem.Situation}
+	if reasoning := strings.Join(strings.Fields(mem.Reasoning), " "); reasoning != "" {
+		lines = append(lines, "reasoning: "+reasoning)
+	}
+	if mem.Resolved {
+		outcome := "loss"
+		if mem.RealizedPnL > 0 {
+			outcome = "win"
+		}
+		if mem.CloseReason != "" {
+			outcome += ", closed by " + mem.CloseReason
+		}
+		lines = append(lines, "outcome: "+outcome)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Record stores executed decision with its situation (outcome is attached once position closes)
+func (m *MemoryBank) Record(symbol, action, situation, reasoning string, positionID int64) error {
+	if situation == "" {
+		return nil
+	}
+	reasoning = strings.TrimSpace(reasoning)
+	if runes := []rune(reasoning); len(runes) > memoryReasoningMaxChars {
+		reasoning = string(runes[:memoryReasoningMaxChars]) + "..."
+	}
+	mem := &store.DecisionMemory{
+		TraderID:   m.traderID,
+		PositionID: positionID,
+		Symbol:     symbol,
+		Action:     action,
+		Situation:  situation,
+		Reasoning:  reasoning,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), memoryEmbedTimeout)
+	defer cancel()
+	vectors, model, err := m.embed(ctx, []string{memoryDocument(mem)})
+	if err != nil {
+		return fmt.Errorf("failed to embed situation: %w", err)
+	}
+	mem.Embedding = vectors[0]
+	mem.EmbeddingModel = model
+	return m.store.Save(mem)
+}
+
+// reembedResolved re-embeds newly resolved memories so their vectors include the outcome
+// Provider vectors are kept when only local embeddings are available, so the memory stays searchable.
+func (m *MemoryBank) reembedResolved(ctx context.Context, memories []*store.DecisionMemory) {
+	texts := make([]string, len(memories))
+	for i, mem := range memories {
+		texts[i] = memoryDocument(mem)
+	}
+	vectors, model, err := m.embed(ctx, texts)
+	if err != nil {
+		logger.Warnf("⚠️  [Memory] Failed to embed outcomes: %v", err)
+		return
+	}
+	for i, mem := range memories {
+		if model == m.fallback.EmbeddingModel() && mem.EmbeddingModel != model {
+			continue
+		}
+		if err := m.store.SetEmbedding(mem.ID, vectors[i], model); err != nil {
+			logger.Warnf("⚠️  [Memory] Failed to store outcome embedding of memory %d: %v", mem.ID, err)
+		}
+	}
+}
+
+// Recall implements MemoryRecaller
+func (m *MemoryBank) Recall(ctx context.Context, situations map[string]string, topK int, minSimilarity float64) (map[string][]*store.DecisionMemory, error) {
+	if len(situations) == 0 {
+		return nil, nil
+	}
+	if resolved, err := m.store.ResolveClosed(m.traderID); err != nil {
+		logger.Warnf("⚠️  [Memory] Failed to resolve closed positions: %v", err)
+	} else if len(resolved) > 0 {
+		logger.Infof("🧠 [Memory] Attached outcomes to %d past decisions", len(resolved))
+		m.reembedResolved(ctx, resolved)
+	}
+
+	symbols := make([]string, 0, len(situations))
+	for symbol := range situations {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	texts := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		texts[i] = situations[symbol]
+	}
+
+	vectors, model, err := m.embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed situations: %w", err)
+	}
+	memories, err := m.store.ListResolved(m.traderID, model, memorySearchWindow)
+	if err != nil {
+		return nil, err
+	}
+	if len(memories) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string][]*store.DecisionMemory)
+	for i, symbol := range symbols {
+		if similar := mostSimilar(vectors[i], memories, topK, minSimilarity); len(similar) > 0 {
+			result[symbol] = similar
+		}
+	}
+	return result, nil
+}
+
+// mostSimilar returns top-k memories by cosine similarity (copies with Similarity set)
+func mostSimilar(query []float32, memories []*store.DecisionMemory, topK int, minSimilarity float64) []*store.DecisionMemory {
+	var matches []*store.DecisionMemory
+	for _, mem := range memories {
+		similarity := cosineSimilarity(query, mem.Embedding)
+		if similarity < minSimilarity {
+			continue
+		}
+		match := *mem
+		match.Similarity = similarity
+		matches = append(matches, &match)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches
+}
+
+// cosineSimilarity of two vectors (0 if dimensions differ or either is zero)
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func normalize(v []float32) {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] = float32(float64(v[i]) / norm)
+	}
+}
+
+// DescribeSituation builds symbol-agnostic, bucketed description of market situation
+// One "feature: bucket" per line so that both provider and hash embeddings capture it.
+func DescribeSituation(data *market.Data, confluence *ConfluenceScore) string {
+	if data == nil {
+		return ""
+	}
+	var lines []string
+	lines = append(lines, "asset: "+string(market.Symbols.AssetClass(data.Symbol)))
+	if data.PriceChange1h != 0 {
+		lines = append(lines, "change 1h: "+bucketChange(data.PriceChange1h))
+	}
+	if data.PriceChange4h != 0 {
+		lines = append(lines, "change 4h: "+bucketChange(data.PriceChange4h))
+	}
+	if data.CurrentRSI7 != 0 {
+		lines = append(lines, "rsi7: "+bucketRSI(data.CurrentRSI7))
+	}
+	if data.CurrentMACD != 0 {
+		if data.CurrentMACD > 0 {
+			lines = append(lines, "macd: positive")
+		} else {
+			lines = append(lines, "macd: negative")
+		}
+	}
+	if data.CurrentEMA20 > 0 && data.CurrentPrice > 0 {
+		distance := (data.CurrentPrice - data.CurrentEMA20) / data.CurrentEMA20 * 100
+		switch {
+		case distance > 2:
+			lines = append(lines, "price vs ema20: extended above")
+		case distance > 0:
+			lines = append(lines, "price vs ema20: above")
+		case distance < -2:
+			lines = append(lines, "price vs ema20: extended below")
+		default:
+			lines = append(lines, "price vs ema20: below")
+		}
+	}
+	if extra := data.StockExtraData; extra != nil && extra.VolumeRatio > 0 {
+		switch {
+		case extra.VolumeRatio >= 2:
+			lines = append(lines, "volume: surge")
+		case extra.VolumeRatio >= 1.2:
+			lines = append(lines, "volume: elevated")
+		case extra.VolumeRatio < 0.7:
+			lines = append(lines, "volume: light")
+		default:
+			lines = append(lines, "volume: normal")
+		}
+	}
+	if confluence != nil {
+		lines = append(lines, "confluence: "+confluence.Direction)
+		for _, tf := range confluence.Timeframes {
+			lines = append(lines, fmt.Sprintf("trend %s: %s", tf.Timeframe, tf.Direction))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func bucketChange(pct float64) string {
+	switch {
+	case pct > 1:
+		return "strong up"
+	case pct > 0.2:
+		return "up"
+	case pct < -1:
+		return "strong down"
+	case pct < -0.2:
+		return "down"
+	}
+	return "flat"
+}
+
+func bucketRSI(rsi float64) string {
+	switch {
+	case rsi > 70:
+		return "overbought"
+	case rsi > 55:
+		return "bullish"
+	case rsi < 30:
+		return "oversold"
+	case rsi < 45:
+		return "bearish"
+	}
+	return "neutral"
+}
+
+// RecallLessons describes candidate situations and fills ctx.Situations/ctx.Lessons (no-op unless memory is enabled)
+func (e *StrategyEngine) RecallLessons(ctx *Context) {
+	if ctx == nil || !e.config.Memory.Enabled {
+		return
+	}
+	ctx.Situations = make(map[string]string, len(ctx.MarketDataMap))
+	for symbol, data := range ctx.MarketDataMap {
+		ctx.Situations[symbol] = DescribeSituation(data, ctx.ConfluenceMap[symbol])
+	}
+	if ctx.Memory == nil {
+		return
+	}
+
+	topK := e.config.Memory.TopK
+	if topK <= 0 {
+		topK = defaultMemoryTopK
+	}
+	minSimilarity := e.config.Memory.MinSimilarity
+	if minSimilarity <= 0 {
+		minSimilarity = defaultMemorySimilarity
+	}
+
+	situations := make(map[string]string, len(ctx.CandidateStocks))
+	for _, stock := range ctx.CandidateStocks {
+		if situation := ctx.Situations[stock.Symbol]; situation != "" {
+			situations[stock.Symbol] = situation
+		}
+	}
+	deadline := time.Now().Add(memoryEmbedTimeout)
+	if !ctx.Deadline.IsZero() && ctx.Deadline.Before(deadline) {
+		deadline = ctx.Deadline
+	}
+	recallCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	lessons, err := ctx.Memory.Recall(recallCtx, situations, topK, minSimilarity)
+	if err != nil {
+		logger.Warnf("⚠️  [Memory] Failed to recall similar setups: %v", err)
+		return
+	}
+	ctx.Lessons = lessons
+}
+
+// formatLessons formats similar past setups of a candidate (empty if none)
+func formatLessons(symbol string, ctx *Context) string {
+	lessons := ctx.Lessons[symbol]
+	if len(lessons) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Lessons learned (similar past setups):\n")
+	for _, l := range lessons {
+		outcome := "loss"
+		if l.RealizedPnL > 0 {
+			outcome = "win"
+		}
+		sb.WriteString(fmt.Sprintf("- %s %s %s → %s %+.2f USD (%+.2f%%)",
+			l.CreatedAt.Format("2006-01-02"), l.Symbol, l.Action, outcome, l.RealizedPnL, l.PnLPct))
+		if l.CloseReason != "" {
+			sb.WriteString(", closed by " + l.CloseReason)
+		}
+		sb.WriteString(fmt.Sprintf(" | similarity %.2f", l.Similarity))
+		if l.Reasoning != "" {
+			sb.WriteString(fmt.Sprintf(" | reasoning then: \"%s\"", strings.ReplaceAll(l.Reasoning, "\n", " ")))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
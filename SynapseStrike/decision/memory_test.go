@@ -0,0 +1,171 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHashEmbedder_Similarity(t *testing.T) {
+	bullish := DescribeSituation(&market.Data{Symbol: "AAPL", PriceChange1h: 1.5, CurrentRSI7: 65, CurrentMACD: 0.3, CurrentPrice: 101, CurrentEMA20: 100}, nil)
+	similar := DescribeSituation(&market.Data{Symbol: "MSFT", PriceChange1h: 2.1, CurrentRSI7: 62, CurrentMACD: 0.1, CurrentPrice: 101.5, CurrentEMA20: 100}, nil)
+	bearish := DescribeSituation(&market.Data{Symbol: "TSLA", PriceChange1h: -1.5, CurrentRSI7: 25, CurrentMACD: -0.3, CurrentPrice: 95, CurrentEMA20: 100}, nil)
+
+	vectors, err := (&HashEmbedder{}).Embed([]string{bullish, similar, bearish})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sim := cosineSimilarity(vectors[0], vectors[1]); sim < 0.99 {
+		t.Errorf("expected identical buckets to be similar, got %.2f", sim)
+	}
+	if sim := cosineSimilarity(vectors[0], vectors[2]); sim > 0.5 {
+		t.Errorf("expected opposite setups to be dissimilar, got %.2f", sim)
+	}
+}
+
+func TestMemoryBank_RecordAndRecall(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	pos := &store.TraderPosition{TraderID: "t1", Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 100, EntryTime: time.Now(), Leverage: 1, Status: "OPEN"}
+	if err := st.Position().Create(pos); err != nil {
+		t.Fatal(err)
+	}
+
+	situation := DescribeSituation(&market.Data{Symbol: "AAPL", PriceChange1h: 1.5, CurrentRSI7: 65, CurrentMACD: 0.3}, nil)
+	bank := NewMemoryBank(st.Memory(), "t1", nil)
+	if err := bank.Record("AAPL", "open_long", situation, "Breakout above VWAP", pos.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unresolved memories are not recalled
+	lessons, err := bank.Recall(context.Background(), map[string]string{"MSFT": situation}, 3, 0.75)
+	if err != nil || len(lessons) != 0 {
+		t.Fatalf("expected no lessons before close, got %v (err %v)", lessons, err)
+	}
+
+	if err := st.Position().ClosePosition(pos.ID, 95, "", -50, 0, "stop_loss"); err != nil {
+		t.Fatal(err)
+	}
+	lessons, err = bank.Recall(context.Background(), map[string]string{"MSFT": situation}, 3, 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons["MSFT"]) != 1 {
+		t.Fatalf("expected 1 lesson, got %v", lessons)
+	}
+	l := lessons["MSFT"][0]
+	if l.RealizedPnL != -50 || l.PnLPct != -5 || l.CloseReason != "stop_loss" {
+		t.Errorf("unexpected outcome: %+v", l)
+	}
+
+	text := formatLessons("MSFT", &Context{Lessons: lessons})
+	if !strings.Contains(text, "AAPL open_long → loss -50.00 USD (-5.00%), closed by stop_loss") {
+		t.Errorf("unexpected lessons text: %s", text)
+	}
+}
+
+// flakyEmbedder provider embedder failing or stalling on demand
+type flakyEmbedder struct {
+	calls atomic.Int32
+	fail  atomic.Bool
+	delay time.Duration
+}
+
+func (f *flakyEmbedder) EmbeddingModel() string { return "provider" }
+
+func (f *flakyEmbedder) Embed(texts []string) ([][]float32, error) {
+	f.calls.Add(1)
+	time.Sleep(f.delay)
+	if f.fail.Load() {
+		return nil, errors.New("provider unavailable")
+	}
+	return (&HashEmbedder{Dim: 64}).Embed(texts)
+}
+
+func TestMemoryBank_ProviderFallbackPerCall(t *testing.T) {
+	provider := &flakyEmbedder{}
+	bank := NewMemoryBank(nil, "t1", provider)
+
+	provider.fail.Store(true)
+	if _, model, err := bank.embed(context.Background(), []string{"rsi7: bullish"}); err != nil || model != "hash-256" {
+		t.Fatalf("failed provider call: model %q, err %v, want local fallback", model, err)
+	}
+
+	// Provider is retried on the next call instead of being dropped for the session
+	provider.fail.Store(false)
+	if _, model, err := bank.embed(context.Background(), []string{"rsi7: bullish"}); err != nil || model != "provider" {
+		t.Fatalf("recovered provider call: model %q, err %v, want provider", model, err)
+	}
+	if calls := provider.calls.Load(); calls != 2 {
+		t.Errorf("provider calls = %d, want 2", calls)
+	}
+}
+
+func TestMemoryBank_EmbedTimeout(t *testing.T) {
+	bank := NewMemoryBank(nil, "t1", &flakyEmbedder{delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, model, err := bank.embed(ctx, []string{"rsi7: bullish"})
+	if err != nil || model != "hash-256" {
+		t.Fatalf("stalled provider: model %q, err %v, want local fallback", model, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("embed waited %v for a stalled provider", elapsed)
+	}
+}
+
+func TestMemoryDocument(t *testing.T) {
+	mem := &store.DecisionMemory{Situation: "rsi7: bullish\nmacd: positive", Reasoning: "Breakout\nabove VWAP"}
+	if got, want := memoryDocument(mem), "rsi7: bullish\nmacd: positive\nreasoning: Breakout above VWAP"; got != want {
+		t.Errorf("unresolved document = %q, want %q", got, want)
+	}
+	mem.Resolved, mem.RealizedPnL, mem.CloseReason = true, -50, "stop_loss"
+	if got := memoryDocument(mem); !strings.HasSuffix(got, "\noutcome: loss, closed by stop_loss") {
+		t.Errorf("resolved document = %q, want outcome line", got)
+	}
+}
+
+func TestMemoryBank_OutcomeReembedded(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	pos := &store.TraderPosition{TraderID: "t1", Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 100, EntryTime: time.Now(), Leverage: 1, Status: "OPEN"}
+	if err := st.Position().Create(pos); err != nil {
+		t.Fatal(err)
+	}
+	situation := DescribeSituation(&market.Data{Symbol: "AAPL", PriceChange1h: 1.5, CurrentRSI7: 65, CurrentMACD: 0.3}, nil)
+	bank := NewMemoryBank(st.Memory(), "t1", nil)
+	if err := bank.Record("AAPL", "open_long", situation, "Breakout above VWAP", pos.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Position().ClosePosition(pos.ID, 110, "", 100, 0, "take_profit"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bank.Recall(context.Background(), map[string]string{"AAPL": situation}, 3, 0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	memories, err := st.Memory().ListResolved("t1", "hash-256", 10)
+	if err != nil || len(memories) != 1 {
+		t.Fatalf("expected 1 resolved memory, got %d (err %v)", len(memories), err)
+	}
+	want, _ := (&HashEmbedder{}).Embed([]string{memoryDocument(memories[0])})
+	if sim := cosineSimilarity(memories[0].Embedding, want[0]); sim < 0.999 {
+		t.Errorf("stored embedding does not include reasoning and outcome (similarity %.3f)", sim)
+	}
+}
@@ -0,0 +1,45 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"strings"
+	"testing"
+)
+
+// TestGetFullDecisionWithStrategy_NativeReasoning tests that native reasoning becomes the CoT and relaxes the tag rules
+func TestGetFullDecisionWithStrategy_NativeReasoning(t *testing.T) {
+	client := &mcp.MockClient{Provider: mcp.ProviderMock, NativeReasoning: true}
+	client.Responses = []mcp.MockResponse{{
+		Content:   `<decision>[{"symbol":"SYM0","action":"hold","reasoning":"test"}]</decision>`,
+		Reasoning: "SYM0 holds above support",
+	}}
+
+	fd, err := GetFullDecisionWithStrategy(newBatchContext(1), client, newFixtureEngine(), "balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(decisionActions(fd.Decisions), ","); got != "SYM0:hold" {
+		t.Errorf("unexpected decisions: %s", got)
+	}
+	if !strings.Contains(fd.CoTTrace, "### Native Reasoning\nSYM0 holds above support") {
+		t.Errorf("expected native reasoning in CoT trace, got %q", fd.CoTTrace)
+	}
+
+	call := client.Calls()[0]
+	if strings.Contains(call.SystemPrompt, "MUST START WITH `<reasoning>`") {
+		t.Error("system prompt should not require <reasoning> with native reasoning")
+	}
+	if strings.Contains(call.UserPrompt, "BEGIN YOUR RESPONSE WITH `<reasoning>`") {
+		t.Error("user prompt should not require <reasoning> with native reasoning")
+	}
+}
+
+func TestWithNativeReasoning(t *testing.T) {
+	if got := withNativeReasoning("", "tags"); got != "tags" {
+		t.Errorf("expected response CoT unchanged, got %q", got)
+	}
+	got := withNativeReasoning("thinking", "tags")
+	if !strings.HasPrefix(got, "### Native Reasoning\nthinking") || !strings.HasSuffix(got, "### Response Reasoning\ntags") {
+		t.Errorf("unexpected merged CoT: %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Decision JSON Repair
+// ============================================================================
+// A response whose decision JSON cannot be extracted (broken syntax, formulas
+// instead of numbers, truncated array) used to end the batch as a parse error,
+// and a response without any JSON silently became a "wait". With
+// DecisionParsing.RepairEnabled the decision block is sent back once to an AI
+// (DecisionParsing.RepairAIModelID, typically a cheap model) with a prompt to
+// rewrite it into valid schema JSON; only if that also fails does the batch
+// fall back. In "strict" mode a response without decision JSON is treated as
+// a parse failure instead of a silent wait.
+
+const (
+	maxRepairInputChars = 6000 // Tail of the malformed response sent for repair
+	repairTimeout       = 30 * time.Second
+)
+
+var (
+	// errDecisionExtraction decision JSON could not be extracted from the response (repairable)
+	errDecisionExtraction = errors.New("failed to extract decisions")
+	// errNoDecisionJSON response contains no decision JSON (strict parsing)
+	errNoDecisionJSON = errors.New("response contains no decision JSON")
+)
+
+// noJSONFallbackReasoning reasoning prefix of the safe wait produced for a response without JSON
+const noJSONFallbackReasoning = "Model didn't output structured JSON decision"
+
+// isNoJSONFallback whether a parsed decision is only the safe wait of a response without JSON
+func isNoJSONFallback(fd *FullDecision) bool {
+	return fd != nil && len(fd.Decisions) == 1 && fd.Decisions[0].Symbol == "ALL" &&
+		fd.Decisions[0].Action == "wait" && strings.HasPrefix(fd.Decisions[0].Reasoning, noJSONFallbackReasoning)
+}
+
+// strictParsing whether a response without decision JSON is a parse failure
+func (e *StrategyEngine) strictParsing() bool {
+	return e.config.DecisionParsing.Strictness == store.ParseStrictnessStrict
+}
+
+// repairableParseError whether a parse failure is a JSON problem a repair call can fix
+// Validation failures (all decisions rejected by risk rules) are not repaired.
+func repairableParseError(err error) bool {
+	return errors.Is(err, errDecisionExtraction) || errors.Is(err, errNoDecisionJSON)
+}
+
+// repairDecisionResponse asks the AI once to rewrite a malformed response into valid decision JSON
+// Returns the repaired response, to be parsed like the original.
+func (e *StrategyEngine) repairDecisionResponse(ctx *Context, client mcp.AIClient, response string, parseErr error) (string, error) {
+	if ctx.RepairClient != nil {
+		client = ctx.RepairClient
+	}
+	block := response
+	if match := reDecisionTag.FindStringSubmatch(response); len(match) > 1 {
+		block = match[1]
+	}
+	if len(block) > maxRepairInputChars {
+		block = block[len(block)-maxRepairInputChars:]
+	}
+
+	deadline := time.Now().Add(repairTimeout)
+	if !ctx.Deadline.IsZero() && ctx.Deadline.Before(deadline) {
+		deadline = ctx.Deadline
+	}
+	start := time.Now()
+	repaired, err := callAIWithDeadline(deadline, func() (string, error) {
+		return client.CallWithMessages(buildRepairSystemPrompt(), buildRepairUserPrompt(block, parseErr))
+	})
+	if err != nil {
+		return "", fmt.Errorf("repair call failed: %w", err)
+	}
+	logger.Infof("🩹 Decision JSON repair answered by %s/%s in %.1fs", client.GetProvider(), client.GetModel(), time.Since(start).Seconds())
+	return repaired, nil
+}
+
+func buildRepairSystemPrompt() string {
+	var sb strings.Builder
+	sb.WriteString("You repair malformed trading decision output into valid JSON. Do not change any decision, only fix the format.\n\n")
+	sb.WriteString("Output ONLY a JSON array inside <decision></decision> tags, no other text:\n")
+	sb.WriteString("<decision>\n```json\n[{\"symbol\": \"AAPL\", \"action\": \"open_long\", \"leverage\": 2, \"position_size_usd\": 500, \"stop_loss\": 180, \"take_profit\": 200, \"confidence\": 80, \"risk_usd\": 20, \"reasoning\": \"...\"}]\n```\n</decision>\n\n")
+	sb.WriteString("Rules:\n")
+	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | update_stops | hold | wait\n")
+	sb.WriteString("- Numbers must be plain values: compute formulas, no ranges (~), no thousand separators, no units\n")
+	sb.WriteString("- Keep symbols, actions, prices and sizes exactly as intended in the input; drop entries that cannot be recovered\n")
+	sb.WriteString("- If the input contains no decisions at all, output [{\"symbol\": \"ALL\", \"action\": \"wait\", \"reasoning\": \"no decision in input\"}]\n")
+	return sb.String()
+}
+
+func buildRepairUserPrompt(block string, parseErr error) string {
+	return fmt.Sprintf("Parser error: %s\n\nRepair this into the valid schema:\n\n%s\n", firstLine(parseErr.Error()), strings.TrimSpace(block))
+}
+
+// firstLine first line of an error text (parse errors echo the whole response)
+func firstLine(s string) string {
+	if idx := strings.Index(s, "\n"); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
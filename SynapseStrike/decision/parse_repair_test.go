@@ -0,0 +1,72 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repairStubClient AI client answering every call with a fixed response
+type repairStubClient struct {
+	response string
+	prompts  []string
+}
+
+func (c *repairStubClient) SetAPIKey(string, string, string)             {}
+func (c *repairStubClient) SetTimeout(time.Duration)                     {}
+func (c *repairStubClient) SetGenerationParams(mcp.GenerationParams)     {}
+func (c *repairStubClient) CallWithRequest(*mcp.Request) (string, error) { return c.response, nil }
+func (c *repairStubClient) GetProvider() string                          { return "stub" }
+func (c *repairStubClient) GetModel() string                             { return "repair" }
+func (c *repairStubClient) CallWithMessages(system, user string) (string, error) {
+	c.prompts = append(c.prompts, user)
+	return c.response, nil
+}
+
+func TestRepairDecisionResponse(t *testing.T) {
+	engine := &StrategyEngine{config: &store.StrategyConfig{}}
+	malformed := "<reasoning>x</reasoning><decision>[{\"symbol\": \"BTCUSDT\", \"action\": \"open_long\", \"stop_loss\": 95000~96000}]</decision>"
+	_, parseErr := parseFullDecisionResponse(malformed, 1000, 0, 0, 0, 0, PositionLimits{})
+	if !repairableParseError(parseErr) {
+		t.Fatalf("extraction failure should be repairable, got %v", parseErr)
+	}
+
+	answering := &repairStubClient{response: "unused"}
+	repairer := &repairStubClient{response: "<decision>[{\"symbol\": \"BTCUSDT\", \"action\": \"wait\", \"reasoning\": \"ok\"}]</decision>"}
+	ctx := &Context{RepairClient: repairer}
+	repaired, err := engine.repairDecisionResponse(ctx, answering, malformed, parseErr)
+	if err != nil {
+		t.Fatalf("repair failed: %v", err)
+	}
+	if len(answering.prompts) != 0 || len(repairer.prompts) != 1 {
+		t.Fatalf("repair should use the configured repair client once")
+	}
+	if !strings.Contains(repairer.prompts[0], "95000~96000") {
+		t.Errorf("malformed block not sent for repair: %s", repairer.prompts[0])
+	}
+	fd, err := parseFullDecisionResponse(repaired, 1000, 0, 0, 0, 0, PositionLimits{})
+	if err != nil || len(fd.Decisions) != 1 {
+		t.Fatalf("repaired response not parseable: %v", err)
+	}
+
+	if repairableParseError(errors.New("all decisions rejected")) {
+		t.Error("validation failures must not be repaired")
+	}
+}
+
+func TestStrictParsingNoJSON(t *testing.T) {
+	fd, err := parseFullDecisionResponse("I think the market is choppy, better to stay out.", 1000, 0, 0, 0, 0, PositionLimits{})
+	if err != nil {
+		t.Fatalf("lenient parse failed: %v", err)
+	}
+	if !isNoJSONFallback(fd) {
+		t.Fatalf("expected the safe wait fallback, got %+v", fd.Decisions)
+	}
+	engine := &StrategyEngine{config: &store.StrategyConfig{DecisionParsing: store.DecisionParsingConfig{Strictness: store.ParseStrictnessStrict}}}
+	if !engine.strictParsing() {
+		t.Error("strict strictness not detected")
+	}
+}
@@ -0,0 +1,70 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// Trade Preview
+// ============================================================================
+// Before an open is executed its expected sizing and risk are computed from
+// the validated decision and stored on the action record: margin, estimated
+// liquidation, USD at risk at the stop, USD reward at the target, risk/reward
+// and share of equity at risk. The UI shows it next to the action and
+// reviewers can audit sizing without re-deriving it from prices.
+
+// PreviewTrade expected outcome of an open decision (nil for other actions or without a price)
+// Market entries are previewed at the symbol's current price from ctx.
+func PreviewTrade(d *Decision, ctx *Context) *store.TradePreview {
+	if d.Action != "open_long" && d.Action != "open_short" || d.PositionSizeUSD <= 0 {
+		return nil
+	}
+	entry := d.EntryPrice
+	if entry <= 0 && ctx != nil {
+		if data, ok := ctx.MarketDataMap[d.Symbol]; ok && data != nil {
+			entry = data.CurrentPrice
+		}
+	}
+	if entry <= 0 {
+		return nil
+	}
+
+	side := "long"
+	if d.Action == "open_short" {
+		side = "short"
+	}
+	leverage := max(d.Leverage, 1)
+	quantity := d.PositionSizeUSD / entry
+	preview := &store.TradePreview{
+		EntryPrice:       entry,
+		Quantity:         quantity,
+		PositionValue:    d.PositionSizeUSD,
+		Margin:           d.PositionSizeUSD / float64(leverage),
+		LiquidationPrice: EstimateLiquidationPrice(side, entry, leverage),
+	}
+	if d.StopLoss > 0 {
+		preview.RiskUSD = math.Abs(entry-d.StopLoss) * quantity
+	}
+	if d.TakeProfit > 0 {
+		preview.RewardUSD = math.Abs(d.TakeProfit-entry) * quantity
+	}
+	if preview.RiskUSD > 0 {
+		preview.RiskReward = preview.RewardUSD / preview.RiskUSD
+	}
+	if ctx != nil && ctx.Account.TotalEquity > 0 {
+		preview.EquityAtRiskPct = preview.RiskUSD / ctx.Account.TotalEquity * 100
+	}
+	return preview
+}
+
+// FormatTradePreview one-line summary for the execution log
+func FormatTradePreview(symbol, action string, p *store.TradePreview) string {
+	line := fmt.Sprintf("🔎 Preview %s %s: %.4f @ %.4f (value %.2f, margin %.2f) | risk %.2f USD (%.2f%% of equity) | reward %.2f USD | R:R %.2f",
+		symbol, action, p.Quantity, p.EntryPrice, p.PositionValue, p.Margin, p.RiskUSD, p.EquityAtRiskPct, p.RewardUSD, p.RiskReward)
+	if p.LiquidationPrice > 0 {
+		line += fmt.Sprintf(" | liq ~%.4f", p.LiquidationPrice)
+	}
+	return line
+}
@@ -0,0 +1,45 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"math"
+	"testing"
+)
+
+func TestPreviewTrade(t *testing.T) {
+	ctx := &Context{
+		Account:       AccountInfo{TotalEquity: 10000},
+		MarketDataMap: map[string]*market.Data{"BTCUSDT": {CurrentPrice: 100000}},
+	}
+	d := &Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 5000, StopLoss: 98000, TakeProfit: 106000}
+
+	p := PreviewTrade(d, ctx)
+	if p == nil {
+		t.Fatal("expected a preview")
+	}
+	approx := func(name string, got, want float64) {
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s = %.6f, want %.6f", name, got, want)
+		}
+	}
+	approx("quantity", p.Quantity, 0.05)
+	approx("margin", p.Margin, 1000)
+	approx("risk", p.RiskUSD, 100)
+	approx("reward", p.RewardUSD, 300)
+	approx("risk/reward", p.RiskReward, 3)
+	approx("equity at risk", p.EquityAtRiskPct, 1)
+	approx("liquidation", p.LiquidationPrice, EstimateLiquidationPrice("long", 100000, 5))
+
+	// Limit entries are previewed at the limit price
+	d.EntryPrice = 99000
+	if p := PreviewTrade(d, ctx); p.EntryPrice != 99000 {
+		t.Errorf("limit entry previewed at %.2f", p.EntryPrice)
+	}
+
+	if PreviewTrade(&Decision{Symbol: "BTCUSDT", Action: "close_long"}, ctx) != nil {
+		t.Error("closes have no preview")
+	}
+	if PreviewTrade(&Decision{Symbol: "ETHUSDT", Action: "open_short", PositionSizeUSD: 100}, ctx) != nil {
+		t.Error("no preview without a price")
+	}
+}
@@ -0,0 +1,274 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// User Prompt Budget
+// ============================================================================
+// With many candidates and several timeframes the user prompt can exceed the
+// model context. When PromptBudget is enabled and the estimated prompt is over
+// MaxTokens, a compressed copy of the context is rendered step by step until
+// the prompt fits:
+//   1. drop older bars:      kline tables are halved, down to MinBars
+//   2. reduce series length: indicator series are halved, down to MinBars
+//   3. summarize news:       headlines only, at most promptBudgetNewsItems per stock
+// Shared market data is never modified. What was dropped is reported in
+// FullDecision.PromptCompression and logged with the decision record.
+
+const (
+	defaultPromptBudgetTokens  = 24000
+	defaultPromptBudgetMinBars = 10
+	promptBudgetNewsItems      = 3
+)
+
+// PromptCompression what was dropped to fit a user prompt into the token budget
+type PromptCompression struct {
+	Batch          int      `json:"batch,omitempty"` // Batch number in batch mode (0 = single prompt)
+	Budget         int      `json:"budget"`
+	OriginalTokens int      `json:"original_tokens"`
+	FinalTokens    int      `json:"final_tokens"`
+	Dropped        []string `json:"dropped"`
+	OverBudget     bool     `json:"over_budget,omitempty"` // Still over budget after all steps
+}
+
+func (c PromptCompression) String() string {
+	prefix := "🗜️ Prompt"
+	if c.Batch > 0 {
+		prefix = fmt.Sprintf("🗜️ Batch %d prompt", c.Batch)
+	}
+	s := fmt.Sprintf("%s compressed ~%d → ~%d tokens (budget %d): %s",
+		prefix, c.OriginalTokens, c.FinalTokens, c.Budget, strings.Join(c.Dropped, "; "))
+	if c.OverBudget {
+		s += " — still over budget"
+	}
+	return s
+}
+
+// EstimateTokens rough token count of text
+// ~4 ASCII characters per token; non-ASCII characters (emoji, CJK) count as one token each.
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < 128 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// BuildUserPromptWithBudget builds the user prompt, compressing it to fit the strategy's prompt budget
+// Returns nil compression if the budget is disabled or the prompt already fits.
+func (e *StrategyEngine) BuildUserPromptWithBudget(ctx *Context) (string, *PromptCompression) {
+	prompt := e.BuildUserPrompt(ctx)
+	cfg := e.config.PromptBudget
+	if !cfg.Enabled {
+		return prompt, nil
+	}
+	budget := cfg.MaxTokens
+	if budget <= 0 {
+		budget = defaultPromptBudgetTokens
+	}
+	tokens := EstimateTokens(prompt)
+	if tokens <= budget {
+		return prompt, nil
+	}
+	minBars := cfg.MinBars
+	if minBars <= 0 {
+		minBars = defaultPromptBudgetMinBars
+	}
+
+	compressed := *ctx
+	compressed.MarketDataMap = cloneMarketDataMap(ctx.MarketDataMap)
+	c := &promptCompressor{engine: e, ctx: &compressed, prompt: prompt, tokens: tokens, budget: budget}
+	report := &PromptCompression{Budget: budget, OriginalTokens: tokens}
+
+	if from, to := c.halve(minBars, c.longestKlines, c.trimKlines); to < from {
+		report.Dropped = append(report.Dropped, fmt.Sprintf("older kline bars (%d → %d bars per timeframe)", from, to))
+	}
+	if !c.fits() {
+		if from, to := c.halve(minBars, c.longestSeries, c.trimSeries); to < from {
+			report.Dropped = append(report.Dropped, fmt.Sprintf("indicator series shortened (%d → %d values)", from, to))
+		}
+	}
+	if !c.fits() {
+		if stocks := c.summarizeNews(); stocks > 0 {
+			c.render()
+			report.Dropped = append(report.Dropped, fmt.Sprintf("news reduced to %d headlines without summaries (%d stocks)", promptBudgetNewsItems, stocks))
+		}
+	}
+
+	report.FinalTokens = c.tokens
+	report.OverBudget = !c.fits()
+	if len(report.Dropped) == 0 {
+		report.Dropped = append(report.Dropped, "nothing left to compress")
+	}
+	return c.prompt, report
+}
+
+// promptCompressor renders a compressed copy of the context until it fits the budget
+type promptCompressor struct {
+	engine *StrategyEngine
+	ctx    *Context // Context with cloned market data, safe to trim
+	prompt string
+	tokens int
+	budget int
+}
+
+func (c *promptCompressor) fits() bool {
+	return c.tokens <= c.budget
+}
+
+func (c *promptCompressor) render() {
+	c.prompt = c.engine.BuildUserPrompt(c.ctx)
+	c.tokens = EstimateTokens(c.prompt)
+}
+
+// halve cuts lengths in half (not below minBars) until the prompt fits, returns longest length before and after
+func (c *promptCompressor) halve(minBars int, longest func() int, trim func(limit int)) (int, int) {
+	from := longest()
+	to := from
+	for !c.fits() && to > minBars {
+		to = max(to/2, minBars)
+		trim(to)
+		c.render()
+	}
+	return from, to
+}
+
+// longestKlines longest kline table in the context
+func (c *promptCompressor) longestKlines() int {
+	longest := 0
+	for _, data := range c.ctx.MarketDataMap {
+		if data == nil {
+			continue
+		}
+		for _, series := range data.TimeframeData {
+			if series != nil {
+				longest = max(longest, len(series.Klines))
+			}
+		}
+	}
+	return longest
+}
+
+// trimKlines keeps the latest limit bars of every kline table
+func (c *promptCompressor) trimKlines(limit int) {
+	for _, data := range c.ctx.MarketDataMap {
+		if data == nil {
+			continue
+		}
+		for _, series := range data.TimeframeData {
+			if series != nil && len(series.Klines) > limit {
+				series.Klines = series.Klines[len(series.Klines)-limit:]
+			}
+		}
+	}
+}
+
+// indicatorSeries pointers to all time-ordered value series of data
+func indicatorSeries(data *market.Data) []*[]float64 {
+	var series []*[]float64
+	for _, tf := range data.TimeframeData {
+		if tf != nil {
+			series = append(series, &tf.MidPrices, &tf.EMA20Values, &tf.EMA50Values, &tf.MACDValues,
+				&tf.RSI7Values, &tf.RSI14Values, &tf.Volume, &tf.VWAPValues)
+		}
+	}
+	if s := data.IntradaySeries; s != nil {
+		series = append(series, &s.MidPrices, &s.EMA20Values, &s.MACDValues, &s.RSI7Values, &s.RSI14Values, &s.Volume)
+	}
+	if l := data.LongerTermContext; l != nil {
+		series = append(series, &l.MACDValues, &l.RSI14Values)
+	}
+	return series
+}
+
+// longestSeries longest indicator series in the context
+func (c *promptCompressor) longestSeries() int {
+	longest := 0
+	for _, data := range c.ctx.MarketDataMap {
+		if data == nil {
+			continue
+		}
+		for _, values := range indicatorSeries(data) {
+			longest = max(longest, len(*values))
+		}
+	}
+	return longest
+}
+
+// trimSeries keeps the latest limit values of every indicator series
+func (c *promptCompressor) trimSeries(limit int) {
+	for _, data := range c.ctx.MarketDataMap {
+		if data == nil {
+			continue
+		}
+		for _, values := range indicatorSeries(data) {
+			if len(*values) > limit {
+				*values = (*values)[len(*values)-limit:]
+			}
+		}
+	}
+}
+
+// summarizeNews reduces news to the latest headlines without summaries, returns number of stocks changed
+func (c *promptCompressor) summarizeNews() int {
+	changed := 0
+	for _, data := range c.ctx.MarketDataMap {
+		if data == nil || data.StockExtraData == nil || len(data.StockExtraData.RecentNews) == 0 {
+			continue
+		}
+		news := data.StockExtraData.RecentNews
+		headlines := make([]market.NewsItem, 0, promptBudgetNewsItems)
+		for i := 0; i < len(news) && i < promptBudgetNewsItems; i++ {
+			item := news[i]
+			item.Summary = ""
+			headlines = append(headlines, item)
+		}
+		data.StockExtraData.RecentNews = headlines
+		changed++
+	}
+	return changed
+}
+
+// cloneMarketDataMap copies market data deep enough that series can be trimmed without touching the original
+func cloneMarketDataMap(src map[string]*market.Data) map[string]*market.Data {
+	dst := make(map[string]*market.Data, len(src))
+	for symbol, data := range src {
+		if data == nil {
+			dst[symbol] = nil
+			continue
+		}
+		clone := *data
+		if data.TimeframeData != nil {
+			clone.TimeframeData = make(map[string]*market.TimeframeSeriesData, len(data.TimeframeData))
+			for tf, series := range data.TimeframeData {
+				if series != nil {
+					s := *series
+					series = &s
+				}
+				clone.TimeframeData[tf] = series
+			}
+		}
+		if data.IntradaySeries != nil {
+			s := *data.IntradaySeries
+			clone.IntradaySeries = &s
+		}
+		if data.LongerTermContext != nil {
+			l := *data.LongerTermContext
+			clone.LongerTermContext = &l
+		}
+		if data.StockExtraData != nil {
+			x := *data.StockExtraData
+			clone.StockExtraData = &x
+		}
+		dst[symbol] = &clone
+	}
+	return dst
+}
@@ -0,0 +1,71 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"strings"
+	"testing"
+)
+
+// newLargePromptContext builds a context whose prompt is dominated by long kline tables, series and news
+func newLargePromptContext(n, bars int) *Context {
+	ctx := newBatchContext(n)
+	for _, data := range ctx.MarketDataMap {
+		series := &market.TimeframeSeriesData{Timeframe: "5m"}
+		for i := 0; i < bars; i++ {
+			series.Klines = append(series.Klines, market.KlineBar{Time: int64(i) * 300000, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000})
+			series.EMA20Values = append(series.EMA20Values, 100)
+		}
+		data.TimeframeData = map[string]*market.TimeframeSeriesData{"5m": series}
+		data.StockExtraData = &market.StockExtraData{}
+		for i := 0; i < 10; i++ {
+			data.StockExtraData.RecentNews = append(data.StockExtraData.RecentNews, market.NewsItem{
+				Headline: "Headline", Source: "wire", Summary: strings.Repeat("long summary ", 50),
+			})
+		}
+	}
+	return ctx
+}
+
+// TestBuildUserPromptWithBudget tests that older bars are dropped to fit the budget without touching shared data
+func TestBuildUserPromptWithBudget(t *testing.T) {
+	engine := newFixtureEngine()
+	engine.config.Indicators.EnableEMA = true
+	engine.config.PromptBudget.Enabled = true
+	engine.config.PromptBudget.MaxTokens = 20000
+	ctx := newLargePromptContext(4, 400)
+
+	original := EstimateTokens(engine.BuildUserPrompt(ctx))
+	if original <= 20000 {
+		t.Fatalf("test prompt too small: ~%d tokens", original)
+	}
+
+	prompt, report := engine.BuildUserPromptWithBudget(ctx)
+	if report == nil {
+		t.Fatal("expected compression report")
+	}
+	if got := EstimateTokens(prompt); got > 20000 || report.FinalTokens != got || report.OverBudget {
+		t.Errorf("prompt ~%d tokens, report %+v", got, report)
+	}
+	if !strings.Contains(report.Dropped[0], "older kline bars (400 → ") {
+		t.Errorf("unexpected dropped steps: %v", report.Dropped)
+	}
+	if len(ctx.MarketDataMap["SYM0"].TimeframeData["5m"].Klines) != 400 {
+		t.Error("shared market data must not be trimmed")
+	}
+
+	// Tight budget: every step runs, still over budget
+	engine.config.PromptBudget.MaxTokens = 1000
+	_, report = engine.BuildUserPromptWithBudget(ctx)
+	if len(report.Dropped) != 3 || !report.OverBudget {
+		t.Errorf("expected all steps and over budget, got %+v", report)
+	}
+	if news := ctx.MarketDataMap["SYM0"].StockExtraData.RecentNews; len(news) != 10 || news[0].Summary == "" {
+		t.Error("shared news must not be summarized")
+	}
+
+	// Disabled budget leaves the prompt alone
+	engine.config.PromptBudget.Enabled = false
+	if _, report := engine.BuildUserPromptWithBudget(ctx); report != nil {
+		t.Errorf("expected no compression when disabled, got %+v", report)
+	}
+}
@@ -0,0 +1,213 @@
+package decision
+
+import "SynapseStrike/store"
+
+// ============================================================================
+// Prompt Language
+// ============================================================================
+// StrategyConfig.Language switches the generated prompt text: the fixed
+// sections of the system prompt, the frame of the user prompt and the output
+// format reminder are looked up in promptTranslations with the English text
+// as key (missing entries fall back to English), and non-English prompts end
+// with an instruction to reason and explain in that language. XML tags, JSON
+// field names, actions and symbols always stay in English so parsing is
+// unaffected. Per-symbol market data blocks keep their English labels.
+
+// promptTranslations prompt text per language, keyed by the English source text
+// Format verbs must appear in the same order as in the key (see TestPromptTranslationVerbs).
+var promptTranslations = map[string]map[string]string{
+	store.PromptLanguageChinese: zhPromptText,
+}
+
+// responseLanguageInstruction asks the AI to write its reasoning in the prompt language
+var responseLanguageInstruction = map[string]string{
+	store.PromptLanguageChinese: "# 🌐 输出语言\n\n请使用简体中文撰写 `<reasoning>` 中的全部分析以及 JSON 中的 `reasoning` 字段。XML 标签、JSON 字段名、`action` 取值和股票代码保持英文原样。\n\n",
+}
+
+// t prompt text in the strategy's language (English source text when untranslated)
+func (e *StrategyEngine) t(text string) string {
+	if translated, ok := promptTranslations[e.config.Language][text]; ok {
+		return translated
+	}
+	return text
+}
+
+var zhPromptText = map[string]string{
+	// System prompt
+	"# You are a professional stock trading AI\n\n":                             "# 你是一名专业的股票交易 AI\n\n",
+	"Your task is to make trading decisions based on provided market data.\n\n": "你的任务是根据提供的市场数据做出交易决策。\n\n",
+	"## Mode: Aggressive\n- Prioritize capturing trend breakouts, can build positions in batches when confidence ≥ 70\n- Allow higher positions, but must strictly set stop-loss and explain risk-reward ratio\n\n": "## 模式：激进\n- 优先捕捉趋势突破，信心度 ≥ 70 时可分批建仓\n- 允许更高仓位，但必须严格设置止损并说明风险收益比\n\n",
+	"## Mode: Conservative\n- Only open positions when multiple signals resonate\n- Prioritize cash preservation, must pause for multiple periods after consecutive losses\n\n":                                     "## 模式：保守\n- 仅在多个信号共振时开仓\n- 优先保全资金，连续亏损后必须暂停多个周期\n\n",
+	"## Mode: Scalping\n- Focus on short-term momentum, smaller profit targets but require quick action\n- If price doesn't move as expected within two bars, immediately reduce position or stop-loss\n\n":         "## 模式：剥头皮\n- 专注短线动量，止盈目标更小但需要快速行动\n- 如果价格在两根 K 线内未按预期运行，立即减仓或止损\n\n",
+	"# Hard Constraints (Risk Control)\n\n":                                                            "# 硬性约束（风险控制）\n\n",
+	"## CODE ENFORCED (Backend validation, cannot be bypassed):\n":                                     "## 代码强制（后端校验，无法绕过）：\n",
+	"- Max Positions: %d stocks simultaneously\n":                                                      "- 最大持仓数：同时最多 %d 只股票\n",
+	"- Position Value Limit (Small Caps): max %.0f USD (= equity %.0f × %.1fx)\n":                      "- 仓位价值上限（小盘股）：最多 %.0f USD（= 权益 %.0f × %.1fx）\n",
+	"- Position Value Limit (Large Cap): max %.0f USD (= equity %.0f × %.1fx)\n":                       "- 仓位价值上限（大盘股）：最多 %.0f USD（= 权益 %.0f × %.1fx）\n",
+	"- Max Margin Usage: ≤%.0f%%\n":                                                                    "- 最大保证金使用率：≤%.0f%%\n",
+	"- Min Position Size: Small Caps ≥%.0f USD | Large Cap ≥%.0f USD\n":                                "- 最小仓位：小盘股 ≥%.0f USD | 大盘股 ≥%.0f USD\n",
+	"- Large Cap symbols: %s (all others are Small Caps)\n":                                            "- 大盘股代码：%s（其余均为小盘股）\n",
+	"- Stop Loss Distance: %.1f-%.1f × ATR(14) from current price (stops outside are auto-adjusted)\n": "- 止损距离：距当前价格 %.1f-%.1f × ATR(14)（超出范围的止损会被自动调整）\n",
+	"- Liquidation Guard (leveraged crypto): stop loss must be more than %.1f%% of entry in front of the liquidation price (≈ 1/leverage from entry), otherwise the open is rejected\n": "- 强平保护（杠杆加密货币）：止损必须位于强平价格之前，且间隔超过入场价的 %.1f%%（强平价约在距入场价 1/杠杆 处），否则开仓会被拒绝\n",
+	"- Per-symbol limits (replace the limits above for these symbols; opens below the confidence minimum are rejected):\n":                                                              "- 单个标的限制（对这些标的替代上述限制；置信度低于最低要求的开仓会被拒绝）：\n",
+	"## AI GUIDED (Recommended, you should follow):\n":                                                                      "## AI 指导（建议遵守）：\n",
+	"- Trading Leverage: Small Caps max %dx | Large Cap max %dx\n":                                                          "- 交易杠杆：小盘股最多 %dx | 大盘股最多 %dx\n",
+	"- Risk-Reward Ratio: ≥1:%.1f (take_profit / stop_loss)\n":                                                              "- 风险收益比：≥1:%.1f（take_profit / stop_loss）\n",
+	"- Min Confidence: ≥%d to open position\n\n":                                                                            "- 最低信心度：≥%d 才能开仓\n\n",
+	"## Position Sizing Guidance\n":                                                                                         "## 仓位规模指引\n",
+	"Calculate `position_size_usd` based on your confidence and the Position Value Limits above:\n":                         "根据你的信心度和上述仓位价值上限计算 `position_size_usd`：\n",
+	"- High confidence (≥85): Use 80-100%% of max position value limit\n":                                                   "- 高信心度（≥85）：使用仓位价值上限的 80-100%%\n",
+	"- Medium confidence (70-84): Use 50-80%% of max position value limit\n":                                                "- 中等信心度（70-84）：使用仓位价值上限的 50-80%%\n",
+	"- Low confidence (60-69): Use 30-50%% of max position value limit\n":                                                   "- 低信心度（60-69）：使用仓位价值上限的 30-50%%\n",
+	"- Example: With equity %.0f and Large Cap ratio %.1fx, max is %.0f USD\n":                                              "- 示例：权益 %.0f、大盘股比例 %.1fx 时，上限为 %.0f USD\n",
+	"- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n":                      "- **不要**直接把 available_balance 当作 position_size_usd，请使用仓位价值上限！\n\n",
+	"# ⏱️ Trading Frequency Awareness\n\n":                                                                                  "# ⏱️ 交易频率意识\n\n",
+	"- Excellent traders: 2-4 trades/day ≈ 0.1-0.2 trades/hour\n":                                                           "- 优秀交易者：每天 2-4 笔 ≈ 每小时 0.1-0.2 笔\n",
+	"- >2 trades/hour = Overtrading\n":                                                                                      "- 每小时 >2 笔 = 过度交易\n",
+	"- Single position hold time ≥ 30-60 minutes\n":                                                                         "- 单笔持仓时间 ≥ 30-60 分钟\n",
+	"If you find yourself trading every period → standards too low; if closing positions < 30 minutes → too impatient.\n\n": "如果你发现自己每个周期都在交易 → 标准太低；如果持仓不到 30 分钟就平仓 → 太急躁。\n\n",
+	"\n\nYou have the following indicator data:\n":                                                                          "\n\n你可以使用以下指标数据：\n",
+	"\n**Confidence ≥ %d** required to open positions.\n\n":                                                                 "\n开仓要求**信心度 ≥ %d**。\n\n",
+	"# 🎯 Entry Standards (Strict)\n\n":                                                                                      "# 🎯 入场标准（严格）\n\n",
+	"Only open positions when multiple signals resonate. You have:\n":                                                       "仅在多个信号共振时开仓。你拥有：\n",
+	"\nFeel free to use any effective analysis method, but **confidence ≥ %d** required to open positions; avoid low-quality behaviors such as single indicators, contradictory signals, sideways consolidation, reopening immediately after closing, etc.\n\n": "\n可自由使用任何有效的分析方法，但开仓要求**信心度 ≥ %d**；避免单一指标、信号矛盾、横盘震荡、平仓后立即重新开仓等低质量行为。\n\n",
+	"# 📋 Decision Process\n\n":                                                               "# 📋 决策流程\n\n",
+	"1. Check positions → Should we take profit/stop-loss\n":                                 "1. 检查持仓 → 是否需要止盈/止损\n",
+	"2. Scan candidate stocks + multi-timeframe → Are there strong signals\n":                "2. 扫描候选股票 + 多时间周期 → 是否存在强信号\n",
+	"3. Write chain of thought first, then output structured JSON\n\n":                       "3. 先写思维链，再输出结构化 JSON\n\n",
+	"# ⚠️ OUTPUT FORMAT (CRITICAL - MUST FOLLOW EXACTLY)\n\n":                                "# ⚠️ 输出格式（关键 - 必须严格遵守）\n\n",
+	"**YOUR RESPONSE MUST START WITH `<reasoning>` TAG AND END WITH `</decision>` TAG**\n\n": "**你的回复必须以 `<reasoning>` 标签开头，并以 `</decision>` 标签结尾**\n\n",
+	"## MANDATORY Structure (Copy This Exactly):\n\n":                                        "## 强制结构（请严格照此输出）：\n\n",
+	"## Chain of Thought Analysis\n\n":                                                       "## 思维链分析\n\n",
+	"### 1. Account & Risk Assessment\n":                                                     "### 1. 账户与风险评估\n",
+	"- Current equity: $XXX\n":                                                               "- 当前权益：$XXX\n",
+	"- Available margin: $XXX\n":                                                             "- 可用保证金：$XXX\n",
+	"- Open positions: X\n\n":                                                                "- 持仓数量：X\n\n",
+	"### 2. Stock-by-Stock Analysis\n":                                                       "### 2. 逐只股票分析\n",
+	"For each candidate stock, analyze:\n":                                                   "对每只候选股票分析：\n",
+	"- **SYMBOL**: Price action, trend direction, key levels\n":                              "- **代码**：价格走势、趋势方向、关键价位\n",
+	"- Indicators: RSI, MACD, Volume signals\n":                                              "- 指标：RSI、MACD、成交量信号\n",
+	"- Decision: BUY/SELL/WAIT and why\n\n":                                                  "- 决策：买入/卖出/观望及原因\n\n",
+	"### 3. Final Decision Summary\n":                                                        "### 3. 最终决策总结\n",
+	"- Selected trades and reasoning\n":                                                      "- 选定的交易及理由\n",
+	"## ⚠️ PARSING RULES (FAILURE = REJECTED RESPONSE)\n\n":                                  "## ⚠️ 解析规则（违反 = 回复被拒绝）\n\n",
+	"1. **FIRST LINE** of your response MUST be exactly: `<reasoning>`\n":                    "1. 回复的**第一行**必须恰好是：`<reasoning>`\n",
+	"2. **LAST LINES** MUST be: `</decision>` (with JSON inside)\n":                          "2. **最后几行**必须是：`</decision>`（其中包含 JSON）\n",
+	"3. **NO TEXT** before `<reasoning>` or after `</decision>`\n":                           "3. `<reasoning>` 之前和 `</decision>` 之后**不得有任何文字**\n",
+	"**Your native reasoning is recorded as the chain of thought: the `<reasoning>` block is optional (a short summary at most). YOUR RESPONSE MUST END WITH `</decision>` TAG**\n\n": "**你的原生推理会被记录为思维链：`<reasoning>` 块可选（最多写简短总结）。你的回复必须以 `</decision>` 标签结尾**\n\n",
+	"1. `<reasoning>` is optional; if present it comes first\n":                                            "1. `<reasoning>` 可选；如有则必须放在最前面\n",
+	"3. **NO TEXT** after `</decision>`\n":                                                                 "3. `</decision>` 之后**不得有任何文字**\n",
+	"4. **JSON MUST** be inside ```json code fence within `<decision>` tags\n\n":                           "4. **JSON 必须**放在 `<decision>` 标签内的 ```json 代码块中\n\n",
+	"## JSON Decision Array Format:\n\n":                                                                   "## JSON 决策数组格式：\n\n",
+	"## Field Description\n\n":                                                                             "## 字段说明\n\n",
+	"- `action`: open_long | open_short | close_long | close_short | update_stops | hold | wait\n":         "- `action`：open_long | open_short | close_long | close_short | update_stops | hold | wait\n",
+	"- `confidence`: 0-100 (opening recommended ≥ %d)\n":                                                   "- `confidence`：0-100（建议 ≥ %d 才开仓）\n",
+	"- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n": "- 开仓时必填：leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n",
+	"- `risk_usd`: loss if the stop fills = position_size_usd × stop distance %. Code enforced: a position risking more than risk_usd is resized down\n": "- `risk_usd`：止损触发时的亏损 = position_size_usd × 止损距离 %。代码强制：风险超过 risk_usd 的仓位会被自动缩小\n",
+	"- Max risk per trade: %s (the smaller applies)\n": "- 单笔最大风险：%s（取较小者）\n",
+	"- `update_stops`: move stop_loss and/or take_profit of a held position (e.g. stop to breakeven after +2R). Stop loss must stay below the current price for longs (above for shorts), take profit on the other side; omitted levels are kept\n":                                               "- `update_stops`：调整持仓的 stop_loss 和/或 take_profit（例如盈利 +2R 后将止损移至保本）。多头止损必须低于当前价格（空头高于当前价格），止盈在另一侧；未提供的价位保持不变\n",
+	"- `entry_price` (optional, opening only): enter with a limit order at this price instead of market (e.g. a pullback to VWAP). Unfilled orders are cancelled after the expiry window (`valid_for_minutes` if given); stop_loss and take_profit are placed once filled\n":                      "- `entry_price`（可选，仅开仓）：以该价格挂限价单入场而不是市价（例如回踩 VWAP）。未成交的订单在有效期结束后取消（如提供则为 `valid_for_minutes`）；成交后再设置 stop_loss 和 take_profit\n",
+	"- `take_profit_levels` (optional, opening only): scaled exits as [{\"price\", \"pct_of_position\"}], e.g. 50% at +1R and the rest at +3R. Up to 4 levels; a remainder below 100% is closed at take_profit. Each level is a separate reduce-only order, the final level is the take_profit\n": "- `take_profit_levels`（可选，仅开仓）：分批止盈，格式为 [{\"price\", \"pct_of_position\"}]，例如 +1R 平仓 50%、其余在 +3R 平仓。最多 4 档；合计不足 100% 的部分在 take_profit 平仓。每档为单独的只减仓订单，最后一档即 take_profit\n",
+	"- `schema_version`: %d (optional)\n": "- `schema_version`：%d（可选）\n",
+	"- `metadata` (optional): %s (ATR multiple), %s, %s (market | limit), %s, %s (update_stops with both sides held)\n":                                                              "- `metadata`（可选）：%s（ATR 倍数）、%s、%s（market | limit）、%s、%s（同时持有多空仓位时用于 update_stops）\n",
+	"- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n":                                             "- **重要**：所有数值必须是计算后的数字，不能是公式/表达式（例如使用 `27.76` 而不是 `3000 * 0.01`）\n\n",
+	"# 🛡️ Multi-Timeframe Confluence Engine (CRITICAL)\n\n":                                                                                                                          "# 🛡️ 多时间周期共振引擎（关键）\n\n",
+	"You are in **Confluence Mode**. You MUST check signals across all provided timeframes before opening or closing positions.\n":                                                   "你处于**共振模式**。在开仓或平仓前，必须检查所有提供的时间周期上的信号。\n",
+	"- **STRICT REQUIREMENT**: Every single selected timeframe (%s) MUST show the same trend direction and signal resonance. If they do not align, output `wait` for that symbol.\n": "- **严格要求**：每一个选定的时间周期（%s）都必须显示相同的趋势方向和信号共振。若不一致，该股票输出 `wait`。\n",
+	"- **CONFLUENCE REQUIREMENT**: At least %d out of %d timeframes (%s) MUST align. If fewer than %d timeframes agree, output `wait` for that symbol.\n":                            "- **共振要求**：至少 %d 个（共 %d 个）时间周期（%s）必须一致。若一致的时间周期少于 %d 个，该股票输出 `wait`。\n",
+	"- Each stock includes a computed `Confluence` line (per-timeframe trend from EMA20 slope, MACD sign and RSI regime). Use it as the primary alignment check.\n":                  "- 每只股票都包含计算得出的 `Confluence` 行（各时间周期的趋势，来自 EMA20 斜率、MACD 符号和 RSI 区间）。请将其作为主要的一致性检查。\n",
+	"- Opens that do not meet the confluence requirement are **rejected by the system** and converted to `wait`.\n":                                                                  "- 不满足共振要求的开仓会被**系统拒绝**并转为 `wait`。\n",
+	"- Analyze the 'trend alignment' between short-term (e.g., 5m/15m) and higher-term (e.g., 1h/4h) structures.\n":                                                                  "- 分析短周期（如 5m/15m）与更高周期（如 1h/4h）结构之间的“趋势一致性”。\n",
+	"- Trade only in the direction of the macro trend if confluence is present.\n\n":                                                                                                 "- 仅在存在共振时顺着大周期趋势方向交易。\n\n",
+	"# 📈 VWAP + Slope & Stretch Algorithm (CRITICAL - Tier 1)\n\n":                                                                                                                   "# 📈 VWAP + 斜率与偏离算法（关键 - 一级）\n\n",
+	"**This algorithm is ACTIVE. You MUST apply these entry conditions before opening ANY position.**\n\n":                                                                           "**该算法已启用。在开任何仓位之前，你必须应用以下入场条件。**\n\n",
+	"## Entry Time: %s AM ET\n\n":                                                                      "## 入场时间：美东时间上午 %s\n\n",
+	"## Entry Conditions (ALL must be TRUE to open a LONG position):\n":                                "## 入场条件（开多仓时必须全部满足）：\n",
+	"1. **Price @ %s AM > VWAP**: Current price must be ABOVE VWAP\n":                                  "1. **上午 %s 价格 > VWAP**：当前价格必须高于 VWAP\n",
+	"2. **VWAP Slope Positive**: VWAP is trending UP (VWAP @ %s AM > VWAP @ 9:40 AM)\n":                "2. **VWAP 斜率为正**：VWAP 向上（上午 %s 的 VWAP > 上午 9:40 的 VWAP）\n",
+	"3. **Stretch Filter**: Price not overextended (Stretch < 0.5 × OR_Volatility)\n":                  "3. **偏离过滤**：价格未过度延伸（Stretch < 0.5 × OR_Volatility）\n",
+	"4. **Momentum Filter**: Sufficient momentum from open (Momentum > 0.25 × OR_Volatility)\n\n":      "4. **动量过滤**：开盘以来动量充足（Momentum > 0.25 × OR_Volatility）\n\n",
+	"## Key Metrics to Calculate:\n":                                                                   "## 需要计算的关键指标：\n",
+	"- **VWAP**: Volume Weighted Average Price from 9:30 AM to %s AM\n":                                "- **VWAP**：上午 9:30 至上午 %s 的成交量加权平均价\n",
+	"- **VWAP Slope** = (VWAP @ %s AM - VWAP @ 9:40 AM) / VWAP @ 9:40 AM\n":                            "- **VWAP 斜率** = (上午 %s 的 VWAP - 上午 9:40 的 VWAP) / 上午 9:40 的 VWAP\n",
+	"- **VWAP Stretch** = (Current_Price - VWAP) / VWAP\n":                                             "- **VWAP 偏离** = (Current_Price - VWAP) / VWAP\n",
+	"- **OR Volatility** = max(OR_High - VWAP, VWAP - OR_Low) / VWAP (Opening Range: 9:30-10:00 AM)\n": "- **OR 波动率** = max(OR_High - VWAP, VWAP - OR_Low) / VWAP（开盘区间：上午 9:30-10:00）\n",
+	"- **Momentum** = (Price @ %s AM - Open) / Open\n\n":                                               "- **动量** = (上午 %s 的价格 - 开盘价) / 开盘价\n\n",
+	"## Exit Rules:\n": "## 离场规则：\n",
+	"- **Take Profit**: Use the stock-specific sell_trigger % from the table below\n":                                                  "- **止盈**：使用下表中各股票专属的 sell_trigger 百分比\n",
+	"- **Stop Loss**: Day's Open Price (protection)\n":                                                                                 "- **止损**：当日开盘价（保护）\n",
+	"- **Time Exit**: Close position by 3:55 PM ET if neither TP nor SL hit\n\n":                                                       "- **时间离场**：如果止盈和止损都未触发，在美东时间下午 3:55 前平仓\n\n",
+	"## Stock-Specific Take Profit Targets (from AI100 optimization):\n":                                                               "## 各股票专属止盈目标（来自 AI100 优化）：\n",
+	"| Symbol | Take Profit % |\n":                                                                                                     "| 代码 | 止盈 % |\n",
+	"## IMPORTANT:\n":                                                                                                                  "## 重要：\n",
+	"- If ANY entry condition fails, output `wait` for that symbol\n":                                                                  "- 如果任何入场条件不满足，该股票输出 `wait`\n",
+	"- In your reasoning, explicitly state each condition check result\n":                                                              "- 在推理中明确写出每个条件的检查结果\n",
+	"- This is a MOMENTUM strategy - buy when trending UP above VWAP\n\n":                                                              "- 这是一个动量策略 —— 在 VWAP 上方且趋势向上时买入\n\n",
+	"# 📌 Personalized Trading Strategy\n\n":                                                                                            "# 📌 个性化交易策略\n\n",
+	"Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n": "注意：以上个性化策略是对基本规则的补充，不能违反基本风控原则。\n",
+
+	// Available indicators
+	"- %s price series":                             "- %s 价格序列",
+	" + %s K-line series\n":                         " + %s K 线序列\n",
+	"- EMA indicators":                              "- EMA 指标",
+	" (periods: %v)":                                "（周期：%v）",
+	"- MACD indicators\n":                           "- MACD 指标\n",
+	"- RSI indicators":                              "- RSI 指标",
+	"- ATR indicators":                              "- ATR 指标",
+	"- Volume data\n":                               "- 成交量数据\n",
+	"- Open Interest (OI) data\n":                   "- 持仓量（OI）数据\n",
+	"- Funding rate\n":                              "- 资金费率\n",
+	"- AI500 / OI_Top filter tags (if available)\n": "- AI500 / OI_Top 筛选标签（如有）\n",
+	"- Cross-exchange funding rates (extreme funding = crowded side, wide spread = carry opportunity)\n":   "- 跨交易所资金费率（极端费率 = 拥挤的一方，价差大 = 套息机会）\n",
+	"- External screener scores and notes (screener's view, confirm with market data)\n":                   "- 外部筛选器评分与备注（筛选器的观点，需结合市场数据确认）\n",
+	"- Social mentions and sentiment (crowd attention, a momentum/volatility hint rather than a signal)\n": "- 社交媒体提及次数与情绪（市场关注度，仅作为动量/波动提示而非交易信号）\n",
+	"- Quantitative data (institutional/retail fund flow, position changes, multi-period price changes)\n": "- 量化数据（机构/散户资金流、持仓变化、多周期涨跌幅）\n",
+	"- VWAP (Volume Weighted Average Price) series\n":                                                      "- VWAP（成交量加权平均价）序列\n",
+	"- Volume Profile (%d price levels)\n":                                                                 "- 成交量分布（%d 个价格档位）\n",
+	"- Stock news & sentiment\n":                                                                           "- 股票新闻与情绪\n",
+	"- Corporate actions (dividends, splits, etc.)\n":                                                      "- 公司行为（分红、拆股等）\n",
+	"- Volume surge detection (2x+ average)\n":                                                             "- 放量检测（2 倍以上均量）\n",
+	"- Analyst ratings & price targets\n":                                                                  "- 分析师评级与目标价\n",
+	"- Earnings calendar (upcoming earnings dates & estimates)\n":                                          "- 财报日历（即将发布的财报日期与预期）\n",
+	"- Short interest & squeeze risk\n":                                                                    "- 空头持仓与轧空风险\n",
+	"- Zero DTE options sentiment (put/call ratio, max pain)\n":                                            "- 0DTE 期权情绪（看跌/看涨比率、最大痛点）\n",
+	"- Trade flow analysis (institutional buy/sell activity)\n":                                            "- 成交流向分析（机构买卖活动）\n",
+	"- Session-anchored VWAP (from 9:30 AM ET market open)\n":                                              "- 交易时段锚定 VWAP（自美东时间上午 9:30 开盘起）\n",
+	"- Multi-Timeframe Confluence Engine (%s Mode: %v)\n":                                                  "- 多时间周期共振引擎（%s 模式：%v）\n",
+	"- **VWAP + Slope & Stretch Algorithm** (Entry: %s AM ET) - Tier 1 Entry Filter\n":                     "- **VWAP + 斜率与偏离算法**（入场：美东时间上午 %s）- 一级入场过滤\n",
+
+	// User prompt
+	"Time: %s | Period: #%d | Runtime: %d minutes\n\n":                                                                                                                                "时间：%s | 周期：#%d | 运行时长：%d 分钟\n\n",
+	"SPY: %.2f (1h: %+.2f%%, 4h: %+.2f%%) | MACD: %.4f | RSI: %.2f\n\n":                                                                                                               "SPY：%.2f（1h：%+.2f%%，4h：%+.2f%%）| MACD：%.4f | RSI：%.2f\n\n",
+	"Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n":                                                                                   "账户：权益 %.2f | 可用余额 %.2f（%.1f%%）| 盈亏 %+.2f%% | 保证金 %.1f%% | 持仓 %d\n\n",
+	"Spot account: only open_long/close_long are possible (open_short/close_short become wait), leverage is always 1x and position size is limited by cash\n\n":                       "现货账户：只能 open_long/close_long（open_short/close_short 会转为 wait），杠杆固定为 1x，仓位受现金限制\n\n",
+	"Crypto pairs on this broker trade spot: only open_long/close_long are possible for them (open_short/close_short become wait) at 1x leverage; stocks follow the normal rules\n\n": "该券商的加密货币交易对为现货：只能 open_long/close_long（open_short/close_short 会转为 wait），杠杆为 1x；股票遵循常规规则\n\n",
+	"## Recent Completed Trades\n": "## 最近完成的交易\n",
+	"%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USD (%+.2f%%) | %s→%s (%s)\n": "%d. %s %s | 入场 %.4f 出场 %.4f | %s：%+.2f USD（%+.2f%%）| %s→%s（%s）\n",
+	"## Current Positions\n":                                       "## 当前持仓\n",
+	"Current Positions: None\n\n":                                  "当前持仓：无\n\n",
+	"## Candidate Stocks (%d configured, %d with market data)\n\n": "## 候选股票（已配置 %d 只，%d 只有市场数据）\n\n",
+	" (opportunity score %.2f)":                                    "（机会评分 %.2f）",
+	"### Stocks Pending Market Data:\n":                            "### 等待市场数据的股票：\n",
+	"- %s%s (market data unavailable)\n":                           "- %s%s（市场数据不可用）\n",
+	"## Data Completeness\n":                                       "## 数据完整性\n",
+	"%d symbols have incomplete data this cycle:\n":                "本周期有 %d 个代码数据不完整：\n",
+	"no market data":                                               "无市场数据",
+	"Factor these gaps into your confidence: lower it when a feed your analysis relies on is missing, and do not open positions on symbols without market data.\n\n": "请在信心度中考虑这些数据缺口：分析所依赖的数据缺失时降低信心度，且不要对没有市场数据的代码开仓。\n\n",
+	"## 🚨 FINAL REMINDER - OUTPUT FORMAT\n\n":                                                       "## 🚨 最终提醒 - 输出格式\n\n",
+	"Your response MUST follow this EXACT structure:\n\n":                                           "你的回复必须严格遵循以下结构：\n\n",
+	"1. Start with `<reasoning>` (no text before it)\n":                                             "1. 以 `<reasoning>` 开头（之前不得有任何文字）\n",
+	"2. Write detailed Chain of Thought analysis for each stock\n":                                  "2. 对每只股票写出详细的思维链分析\n",
+	"3. Close with `</reasoning>`\n":                                                                "3. 以 `</reasoning>` 结束推理\n",
+	"4. Open `<decision>` tag\n":                                                                    "4. 打开 `<decision>` 标签\n",
+	"5. Write JSON array inside ```json code fence\n":                                               "5. 在 ```json 代码块中写出 JSON 数组\n",
+	"6. Close with `</decision>` (no text after it)\n\n":                                            "6. 以 `</decision>` 结束（之后不得有任何文字）\n\n",
+	"1. Do your per-stock analysis in your native reasoning; a `<reasoning>` summary is optional\n": "1. 在原生推理中完成逐只股票分析；`<reasoning>` 总结可选\n",
+	"2. Open `<decision>` tag\n":                                                                    "2. 打开 `<decision>` 标签\n",
+	"3. Write JSON array inside ```json code fence\n":                                               "3. 在 ```json 代码块中写出 JSON 数组\n",
+	"4. Close with `</decision>` (no text after it)\n\n":                                            "4. 以 `</decision>` 结束（之后不得有任何文字）\n\n",
+	"**BEGIN YOUR RESPONSE NOW:**\n":                                                                "**现在开始你的回复：**\n",
+	"**BEGIN YOUR RESPONSE WITH `<reasoning>` NOW:**\n":                                             "**现在以 `<reasoning>` 开始你的回复：**\n",
+	"⏸ TRADING PAUSED by the operator (%s): new opens are rejected. Only manage open positions (close, update_stops, hold/wait).\n\n":     "⏸ 交易已被操作员暂停（%s）：新开仓将被拒绝。只管理现有持仓（平仓、update_stops、hold/wait）。\n\n",
+	"🛠 EXCHANGE MAINTENANCE (%s): no orders can be sent this cycle. Analyze and answer hold/wait; resting stop orders stay in place.\n\n": "🛠 交易所维护中（%s）：本周期无法下单。请照常分析并输出 hold/wait；已挂出的止损单保持不变。\n\n",
+}
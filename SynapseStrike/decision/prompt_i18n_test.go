@@ -0,0 +1,40 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var formatVerb = regexp.MustCompile(`%[+#0-]*[\d.]*[a-zA-Z%]`)
+
+func TestPromptTranslationVerbs(t *testing.T) {
+	for lang, catalog := range promptTranslations {
+		for source, translated := range catalog {
+			if !slices.Equal(formatVerb.FindAllString(source, -1), formatVerb.FindAllString(translated, -1)) {
+				t.Errorf("%s translation of %q has different format verbs: %q", lang, source, translated)
+			}
+		}
+	}
+}
+
+func TestBuildSystemPromptLanguage(t *testing.T) {
+	zhConfig := store.GetDefaultStrategyConfig(store.PromptLanguageChinese)
+	zhPrompt := NewStrategyEngine(&zhConfig).BuildSystemPrompt(10000, "")
+	for _, want := range []string{"# 硬性约束（风险控制）", "## 字段说明", "# 🌐 输出语言", "<reasoning>"} {
+		if !strings.Contains(zhPrompt, want) {
+			t.Errorf("zh system prompt should contain %q", want)
+		}
+	}
+	if strings.Contains(zhPrompt, "Hard Constraints") {
+		t.Error("zh system prompt should not contain untranslated section headers")
+	}
+
+	enConfig := store.GetDefaultStrategyConfig(store.PromptLanguageEnglish)
+	enPrompt := NewStrategyEngine(&enConfig).BuildSystemPrompt(10000, "")
+	if !strings.Contains(enPrompt, "# Hard Constraints (Risk Control)") || strings.Contains(enPrompt, "输出语言") {
+		t.Error("en system prompt should stay English without a language instruction")
+	}
+}
@@ -0,0 +1,79 @@
+package decision
+
+import (
+	"SynapseStrike/cache"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Quant Data Cache
+// ============================================================================
+// Quant data is requested per symbol every cycle, often by several traders and
+// the debate/backtest tools at the same time. Responses are cached per request
+// URL for a short TTL in the shared cache (Redis when configured, so several
+// processes share one copy), and concurrent requests for the same URL in this
+// process share one call.
+
+const (
+	defaultQuantDataMaxConcurrent = 4
+	defaultQuantDataCacheTTL      = 60 * time.Second
+)
+
+// quantCache shared quant data cache (keyed by request URL, so one symbol on different APIs never collides)
+var quantCache = newQuantDataCache()
+
+type quantCacheEntry struct {
+	Data      *QuantData `json:"data"`
+	FetchedAt time.Time  `json:"fetched_at"`
+}
+
+// quantFetchCall in-flight request that other callers of the same key wait on
+type quantFetchCall struct {
+	done chan struct{}
+	data *QuantData
+	err  error
+}
+
+type quantDataCache struct {
+	mu       sync.Mutex
+	inFlight map[string]*quantFetchCall
+}
+
+func newQuantDataCache() *quantDataCache {
+	return &quantDataCache{
+		inFlight: make(map[string]*quantFetchCall),
+	}
+}
+
+// get returns cached data younger than ttl, otherwise fetches it once for all concurrent callers
+// Errors are returned to every waiting caller but never cached.
+func (c *quantDataCache) get(key string, ttl time.Duration, fetch func() (*QuantData, error)) (*QuantData, error) {
+	cacheKey := "quant:" + cache.HashKey(key)
+	var entry quantCacheEntry
+	if cache.GetJSON(cacheKey, &entry) && entry.Data != nil && time.Since(entry.FetchedAt) < ttl {
+		return entry.Data, nil
+	}
+
+	c.mu.Lock()
+	if call, ok := c.inFlight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+	call := &quantFetchCall{done: make(chan struct{})}
+	c.inFlight[key] = call
+	c.mu.Unlock()
+
+	call.data, call.err = fetch()
+
+	if call.err == nil {
+		cache.SetJSON(cacheKey, quantCacheEntry{Data: call.data, FetchedAt: time.Now()}, ttl)
+	}
+	c.mu.Lock()
+	delete(c.inFlight, key)
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.data, call.err
+}
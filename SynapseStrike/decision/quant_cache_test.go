@@ -0,0 +1,67 @@
+package decision
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQuantDataCacheCoalescesAndExpires(t *testing.T) {
+	cache := newQuantDataCache()
+	var calls int32
+	fetch := func() (*QuantData, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return &QuantData{Symbol: "BTCUSDT"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := cache.get("btc", time.Minute, fetch)
+			if err != nil || data == nil || data.Symbol != "BTCUSDT" {
+				t.Errorf("unexpected result: %+v, %v", data, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("expected concurrent requests to share 1 fetch, got %d", calls)
+	}
+
+	// Cached within TTL
+	cache.get("btc", time.Minute, fetch)
+	if calls != 1 {
+		t.Errorf("expected cached result, got %d fetches", calls)
+	}
+
+	// Expired
+	cache.get("btc", 0, fetch)
+	if calls != 2 {
+		t.Errorf("expected refetch after TTL, got %d fetches", calls)
+	}
+}
+
+func TestQuantDataCacheDoesNotCacheErrors(t *testing.T) {
+	cache := newQuantDataCache()
+	var calls int
+	fetch := func() (*QuantData, error) {
+		calls++
+		if calls == 1 {
+			return nil, fmt.Errorf("rate limited")
+		}
+		return &QuantData{Symbol: "ETHUSDT"}, nil
+	}
+
+	if _, err := cache.get("eth", time.Minute, fetch); err == nil {
+		t.Fatal("expected error from first fetch")
+	}
+	data, err := cache.get("eth", time.Minute, fetch)
+	if err != nil || data == nil {
+		t.Fatalf("expected retry to succeed, got %+v, %v", data, err)
+	}
+}
@@ -0,0 +1,264 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================================================
+// Market Regime Classifier
+// ============================================================================
+// Index symbols (SPY/QQQ by default) are always fetched, independent of the
+// candidate list, and each is classified on one timeframe:
+//   - high_vol:      recent ATR(14) / baseline ATR >= HighVolATRRatio
+//   - choppy:        |EMA20 - EMA50| / price < ChopBandPct, or price and EMAs not stacked
+//   - trending_up:   price > EMA20 > EMA50
+//   - trending_down: price < EMA20 < EMA50
+// The market regime is high_vol if any index is high_vol, a trend if all
+// indices agree on it, and choppy otherwise.
+
+const (
+	RegimeTrendingUp   = "trending_up"
+	RegimeTrendingDown = "trending_down"
+	RegimeChoppy       = "choppy"
+	RegimeHighVol      = "high_vol"
+
+	defaultRegimeTimeframe  = "1h"
+	defaultRegimeKlineCount = 100
+	defaultHighVolATRRatio  = 1.5
+	defaultChopBandPct      = 0.15
+	defaultHighVolSizeScale = 0.5
+	regimeATRPeriod         = 14
+)
+
+var defaultRegimeIndices = []string{"SPY", "QQQ"}
+
+// IndexRegime regime classification of a single index
+type IndexRegime struct {
+	Symbol   string  `json:"symbol"`
+	Regime   string  `json:"regime"`
+	Price    float64 `json:"price"`
+	EMA20    float64 `json:"ema20"`
+	EMA50    float64 `json:"ema50"`
+	ATRPct   float64 `json:"atr_pct"`   // Recent ATR as % of price
+	ATRRatio float64 `json:"atr_ratio"` // Recent ATR / baseline ATR
+}
+
+// MarketRegime overall market regime with risk adjustments applied to new positions
+type MarketRegime struct {
+	Regime       string        `json:"regime"`
+	Timeframe    string        `json:"timeframe"`
+	Indices      []IndexRegime `json:"indices"`
+	SizeScale    float64       `json:"size_scale"`    // Multiplier applied to new position sizes (1 = unchanged)
+	MaxPositions int           `json:"max_positions"` // Max positions override (0 = unchanged)
+}
+
+// Summary one-line description for prompts and logs
+func (r *MarketRegime) Summary() string {
+	parts := make([]string, 0, len(r.Indices))
+	for _, idx := range r.Indices {
+		parts = append(parts, fmt.Sprintf("%s %.2f %s (EMA20 %.2f / EMA50 %.2f, ATR %.2f%%, ATR ratio %.2f)",
+			idx.Symbol, idx.Price, idx.Regime, idx.EMA20, idx.EMA50, idx.ATRPct, idx.ATRRatio))
+	}
+	return fmt.Sprintf("%s [%s] | %s", r.Regime, r.Timeframe, strings.Join(parts, " | "))
+}
+
+// ClassifyIndexRegime classifies one index from its timeframe series (nil if not enough data)
+func ClassifyIndexRegime(symbol string, series *market.TimeframeSeriesData, cfg store.RegimeConfig) *IndexRegime {
+	if series == nil || len(series.Klines) <= regimeATRPeriod || len(series.EMA20Values) == 0 || len(series.EMA50Values) == 0 {
+		return nil
+	}
+	highVolRatio := cfg.HighVolATRRatio
+	if highVolRatio <= 0 {
+		highVolRatio = defaultHighVolATRRatio
+	}
+	chopBand := cfg.ChopBandPct
+	if chopBand <= 0 {
+		chopBand = defaultChopBandPct
+	}
+
+	idx := &IndexRegime{
+		Symbol: symbol,
+		Price:  series.Klines[len(series.Klines)-1].Close,
+		EMA20:  series.EMA20Values[len(series.EMA20Values)-1],
+		EMA50:  series.EMA50Values[len(series.EMA50Values)-1],
+	}
+	if idx.Price <= 0 {
+		return nil
+	}
+
+	recentATR, baselineATR := regimeATR(series.Klines)
+	idx.ATRPct = recentATR / idx.Price * 100
+	if baselineATR > 0 {
+		idx.ATRRatio = recentATR / baselineATR
+	}
+
+	bandPct := math.Abs(idx.EMA20-idx.EMA50) / idx.Price * 100
+	switch {
+	case idx.ATRRatio >= highVolRatio:
+		idx.Regime = RegimeHighVol
+	case bandPct < chopBand:
+		idx.Regime = RegimeChoppy
+	case idx.Price > idx.EMA20 && idx.EMA20 > idx.EMA50:
+		idx.Regime = RegimeTrendingUp
+	case idx.Price < idx.EMA20 && idx.EMA20 < idx.EMA50:
+		idx.Regime = RegimeTrendingDown
+	default:
+		idx.Regime = RegimeChoppy
+	}
+	return idx
+}
+
+// regimeATR recent ATR (last regimeATRPeriod bars) and baseline ATR (all bars)
+func regimeATR(klines []market.KlineBar) (recent, baseline float64) {
+	trueRanges := make([]float64, 0, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		prevClose := klines[i-1].Close
+		tr := math.Max(klines[i].High-klines[i].Low,
+			math.Max(math.Abs(klines[i].High-prevClose), math.Abs(klines[i].Low-prevClose)))
+		trueRanges = append(trueRanges, tr)
+	}
+	if len(trueRanges) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, tr := range trueRanges {
+		sum += tr
+	}
+	baseline = sum / float64(len(trueRanges))
+
+	n := regimeATRPeriod
+	if n > len(trueRanges) {
+		n = len(trueRanges)
+	}
+	sum = 0
+	for _, tr := range trueRanges[len(trueRanges)-n:] {
+		sum += tr
+	}
+	return sum / float64(n), baseline
+}
+
+// combineRegimes overall regime from index regimes
+func combineRegimes(indices []IndexRegime) string {
+	if len(indices) == 0 {
+		return ""
+	}
+	for _, idx := range indices {
+		if idx.Regime == RegimeHighVol {
+			return RegimeHighVol
+		}
+	}
+	first := indices[0].Regime
+	for _, idx := range indices[1:] {
+		if idx.Regime != first {
+			return RegimeChoppy
+		}
+	}
+	return first
+}
+
+// ComputeRegime fills ctx.Regime from index data (no-op unless regime is enabled)
+// Index data already in MarketDataMap is reused when it has the regime timeframe.
+func (e *StrategyEngine) ComputeRegime(ctx *Context) {
+	cfg := e.config.Regime
+	if !cfg.Enabled || ctx == nil {
+		return
+	}
+	timeframe := cfg.Timeframe
+	if timeframe == "" {
+		timeframe = defaultRegimeTimeframe
+	}
+	symbols := cfg.IndexSymbols
+	if len(symbols) == 0 {
+		symbols = defaultRegimeIndices
+	}
+
+	var indices []IndexRegime
+	for _, symbol := range symbols {
+		data, ok := ctx.MarketDataMap[symbol]
+		if !ok || data.TimeframeData[timeframe] == nil {
+			var err error
+			data, err = market.GetStockDataWithTimeframes(symbol, []string{timeframe}, timeframe, defaultRegimeKlineCount)
+			if err != nil {
+				logger.Infof("⚠️  [Regime] Failed to fetch %s %s data: %v", symbol, timeframe, err)
+				continue
+			}
+		}
+		if idx := ClassifyIndexRegime(symbol, data.TimeframeData[timeframe], cfg); idx != nil {
+			indices = append(indices, *idx)
+		}
+	}
+	if len(indices) == 0 {
+		return
+	}
+
+	regime := &MarketRegime{
+		Regime:    combineRegimes(indices),
+		Timeframe: timeframe,
+		Indices:   indices,
+		SizeScale: 1,
+	}
+	if regime.Regime == RegimeHighVol && cfg.ScaleRiskInHighVol {
+		regime.SizeScale = cfg.HighVolSizeScale
+		if regime.SizeScale <= 0 || regime.SizeScale > 1 {
+			regime.SizeScale = defaultHighVolSizeScale
+		}
+		regime.MaxPositions = cfg.HighVolMaxPositions
+	}
+	ctx.Regime = regime
+	logger.Infof("🌡️  [Regime] %s", regime.Summary())
+}
+
+// enforceRegime scales new position sizes and caps opens in high_vol regime (only with ScaleRiskInHighVol)
+func (e *StrategyEngine) enforceRegime(decisions []Decision, ctx *Context) []Decision {
+	regime := ctx.Regime
+	if regime == nil || regime.Regime != RegimeHighVol || !e.config.Regime.ScaleRiskInHighVol {
+		return decisions
+	}
+
+	openSlots := regime.MaxPositions - len(ctx.Positions) // Only used when MaxPositions is set
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Action != "open_long" && d.Action != "open_short" {
+			continue
+		}
+		if regime.MaxPositions > 0 && openSlots <= 0 {
+			logger.Warnf("🛡️  [Regime] Blocked %s %s: high_vol regime allows %d positions", d.Action, d.Symbol, regime.MaxPositions)
+			d.Reasoning = fmt.Sprintf("[Regime blocked %s: high_vol regime allows %d positions] %s",
+				d.Action, regime.MaxPositions, d.Reasoning)
+			d.Action = "wait"
+			continue
+		}
+		openSlots--
+		if regime.SizeScale < 1 && d.PositionSizeUSD > 0 {
+			original := d.PositionSizeUSD
+			d.PositionSizeUSD = original * regime.SizeScale
+			logger.Infof("🛡️  [Regime] %s %s size scaled %.2f → %.2f (high_vol ×%.2f)",
+				d.Action, d.Symbol, original, d.PositionSizeUSD, regime.SizeScale)
+		}
+	}
+	return decisions
+}
+
+// formatRegime market regime section of the user prompt (empty if not computed)
+func formatRegime(ctx *Context) string {
+	regime := ctx.Regime
+	if regime == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Market Regime (computed): %s\n", regime.Summary()))
+	if regime.SizeScale < 1 {
+		sb.WriteString(fmt.Sprintf("⚠️ High volatility: new position sizes are scaled to %.0f%%", regime.SizeScale*100))
+		if regime.MaxPositions > 0 {
+			sb.WriteString(fmt.Sprintf(", max %d positions", regime.MaxPositions))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
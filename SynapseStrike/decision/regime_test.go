@@ -0,0 +1,91 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"testing"
+)
+
+// regimeSeries builds series with price drifting by step per bar and given bar range (last 14 bars use lastRange)
+func regimeSeries(step, barRange, lastRange, ema20, ema50 float64) *market.TimeframeSeriesData {
+	series := &market.TimeframeSeriesData{
+		Timeframe:   "1h",
+		EMA20Values: []float64{ema20},
+		EMA50Values: []float64{ema50},
+	}
+	price := 400.0
+	for i := 0; i < 60; i++ {
+		r := barRange
+		if i >= 60-regimeATRPeriod {
+			r = lastRange
+		}
+		price += step
+		series.Klines = append(series.Klines, market.KlineBar{
+			Open: price, High: price + r/2, Low: price - r/2, Close: price,
+		})
+	}
+	return series
+}
+
+func TestClassifyIndexRegime(t *testing.T) {
+	cfg := store.RegimeConfig{HighVolATRRatio: 1.5, ChopBandPct: 0.15}
+
+	tests := []struct {
+		name   string
+		series *market.TimeframeSeriesData
+		want   string
+	}{
+		{"trending up", regimeSeries(0.5, 1, 1, 425, 415), RegimeTrendingUp},
+		{"trending down", regimeSeries(-0.5, 1, 1, 375, 385), RegimeTrendingDown},
+		{"choppy band", regimeSeries(0, 1, 1, 400.1, 400), RegimeChoppy},
+		{"high vol", regimeSeries(0.5, 1, 4, 425, 415), RegimeHighVol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := ClassifyIndexRegime("SPY", tt.series, cfg)
+			if idx == nil || idx.Regime != tt.want {
+				t.Errorf("expected %s, got %+v", tt.want, idx)
+			}
+		})
+	}
+
+	if ClassifyIndexRegime("SPY", &market.TimeframeSeriesData{}, cfg) != nil {
+		t.Error("expected nil without data")
+	}
+}
+
+func TestEnforceRegime(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Regime.Enabled = true
+	cfg.Regime.ScaleRiskInHighVol = true
+	cfg.Regime.HighVolMaxPositions = 2
+	engine := NewStrategyEngine(&cfg)
+
+	ctx := &Context{
+		MarketDataMap: map[string]*market.Data{
+			"SPY": {Symbol: "SPY", TimeframeData: map[string]*market.TimeframeSeriesData{"1h": regimeSeries(0.5, 1, 4, 425, 415)}},
+			"QQQ": {Symbol: "QQQ", TimeframeData: map[string]*market.TimeframeSeriesData{"1h": regimeSeries(0.5, 1, 1, 425, 415)}},
+		},
+		Positions: []PositionInfo{{Symbol: "AAPL"}},
+	}
+	engine.ComputeRegime(ctx)
+	if ctx.Regime == nil || ctx.Regime.Regime != RegimeHighVol || ctx.Regime.SizeScale != 0.5 {
+		t.Fatalf("unexpected regime: %+v", ctx.Regime)
+	}
+
+	decisions := engine.enforceRegime([]Decision{
+		{Symbol: "MSFT", Action: "open_long", PositionSizeUSD: 1000},
+		{Symbol: "AAPL", Action: "close_long"},
+		{Symbol: "TSLA", Action: "open_short", PositionSizeUSD: 1000},
+	}, ctx)
+
+	if decisions[0].Action != "open_long" || decisions[0].PositionSizeUSD != 500 {
+		t.Errorf("expected scaled open, got %+v", decisions[0])
+	}
+	if decisions[1].Action != "close_long" {
+		t.Errorf("close should be untouched, got %s", decisions[1].Action)
+	}
+	if decisions[2].Action != "wait" {
+		t.Errorf("expected second open blocked by high_vol max positions, got %s", decisions[2].Action)
+	}
+}
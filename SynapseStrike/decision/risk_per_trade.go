@@ -0,0 +1,91 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================================================
+// Risk Per Trade
+// ============================================================================
+// The AI states risk_usd (the loss it accepts if the stop fills) next to
+// position_size_usd, but the two are chosen independently and often disagree.
+// The actual risk at the stop is position size × stop distance % (measured
+// from the expected fill like the liquidation guard: entry_price for limit
+// entries, current price otherwise). It is held to the smallest of the AI's
+// own risk_usd, RiskControl.MaxRiskPerTradeUSD and RiskControl.MaxRiskPerTradePct
+// of equity; a larger open is resized down to fit, and dropped if the
+// resized position falls below the minimum position size. risk_usd is then
+// overwritten with the actual risk so the decision record states what the
+// position really risks.
+
+// riskPerTradeTolerance relative overshoot of the allowed risk accepted without resizing (rounding in AI output)
+const riskPerTradeTolerance = 0.01
+
+// enforceRiskPerTrade resizes an open decision so its loss at the stop stays within the risk budget
+// fallbackEntry is the entry estimate of validateDecision, used when the symbol has no market reference.
+func enforceRiskPerTrade(d *Decision, accountEquity, fallbackEntry float64, limits PositionLimits) error {
+	entryPrice := fallbackEntry
+	if ref, ok := limits.StopRefs[d.Symbol]; ok && ref.Price > 0 {
+		entryPrice = ref.Price
+	}
+	if d.EntryPrice > 0 {
+		entryPrice = d.EntryPrice
+	}
+	if entryPrice <= 0 {
+		return nil
+	}
+	stopDistance := math.Abs(entryPrice-d.StopLoss) / entryPrice
+	if stopDistance <= 0 {
+		return nil
+	}
+
+	allowed, source := maxRiskPerTrade(d, accountEquity, limits)
+	actual := d.PositionSizeUSD * stopDistance
+	if allowed > 0 && actual > allowed*(1+riskPerTradeTolerance) {
+		originalSize := d.PositionSizeUSD
+		resized := allowed / stopDistance
+		if minPositionSize := limits.MinPositionSize(d.Symbol); resized < minPositionSize {
+			return fmt.Errorf("%s risk at stop %.2f USD exceeds %s %.2f USD, and the position fitting it (%.2f USD) is below the minimum of %.2f USD, tighten the stop or skip the trade",
+				d.Symbol, actual, source, allowed, resized, minPositionSize)
+		}
+		d.PositionSizeUSD = resized
+		actual = allowed
+		logger.Infof("⚠️  [Risk Per Trade] %s risk at stop %.2f USD (%.0f USD × %.2f%% stop) exceeds %s %.2f USD, auto-adjusting position size to %.0f USD",
+			d.Symbol, originalSize*stopDistance, originalSize, stopDistance*100, source, allowed, d.PositionSizeUSD)
+	}
+	d.RiskUSD = math.Round(actual*100) / 100
+	return nil
+}
+
+// maxRiskPerTrade smallest configured or stated risk limit of an open (0 = unlimited) and what it comes from
+func maxRiskPerTrade(d *Decision, accountEquity float64, limits PositionLimits) (float64, string) {
+	var allowed float64
+	var source string
+	consider := func(limit float64, name string) {
+		if limit > 0 && (allowed == 0 || limit < allowed) {
+			allowed, source = limit, name
+		}
+	}
+	consider(d.RiskUSD, "stated risk_usd")
+	consider(limits.Risk.MaxRiskPerTradeUSD, "max risk per trade")
+	if accountEquity > 0 {
+		consider(accountEquity*limits.Risk.MaxRiskPerTradePct/100, fmt.Sprintf("max risk per trade (%.2f%% of equity)", limits.Risk.MaxRiskPerTradePct))
+	}
+	return allowed, source
+}
+
+// formatRiskPerTradeCaps configured risk per trade caps for prompts, e.g. "50.00 USD / 1.00% of equity"
+func formatRiskPerTradeCaps(risk store.RiskControlConfig) string {
+	var caps []string
+	if risk.MaxRiskPerTradeUSD > 0 {
+		caps = append(caps, fmt.Sprintf("%.2f USD", risk.MaxRiskPerTradeUSD))
+	}
+	if risk.MaxRiskPerTradePct > 0 {
+		caps = append(caps, fmt.Sprintf("%.2f%% of equity", risk.MaxRiskPerTradePct))
+	}
+	return strings.Join(caps, " / ")
+}
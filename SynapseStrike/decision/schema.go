@@ -0,0 +1,103 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Decision Output Schema Versioning
+// ============================================================================
+// v1: JSON array of decisions with fixed fields (no schema_version).
+// v2: same array, each decision may carry "schema_version": 2 and a free-form
+//     "metadata" object for extra action parameters. The array may also be
+//     wrapped in an envelope: {"schema_version": 2, "decisions": [...]}.
+// New parameters go into metadata under the keys below, so older stored
+// records and older models (v1 output) keep parsing unchanged.
+
+const (
+	DecisionSchemaV1             = 1
+	DecisionSchemaV2             = 2
+	CurrentDecisionSchemaVersion = DecisionSchemaV2
+)
+
+// Known decision metadata keys (v2)
+const (
+	MetaTrailingStopATR = "trailing_stop_atr" // Trailing stop distance in ATR multiples
+	MetaValidForMinutes = "valid_for_minutes" // Decision validity window, ignored after expiry
+	MetaOrderType       = "order_type"        // "market" | "limit"
+	MetaLimitPrice      = "limit_price"       // Limit price when order_type is "limit"
+	MetaPositionSide    = "position_side"     // "long" | "short", position targeted by update_stops
+)
+
+var reDecisionEnvelope = regexp.MustCompile(`(?is)\{\s*"schema_version"\s*:\s*(\d+)\s*,\s*"decisions"\s*:\s*(\[.*\])\s*\}`)
+
+// decodeDecisions parses a decision array and normalizes schema versions
+// envelopeVersion is the version declared by an enclosing envelope (0 = none).
+func decodeDecisions(jsonContent string, envelopeVersion int) ([]Decision, error) {
+	var decisions []Decision
+	if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
+		return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
+	}
+	normalizeDecisionSchema(decisions, envelopeVersion)
+	return decisions, nil
+}
+
+// extractDecisionEnvelope finds a v2 envelope, returning its decision array and version
+func extractDecisionEnvelope(s string) (string, int, bool) {
+	m := reDecisionEnvelope.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, false
+	}
+	version, err := strconv.Atoi(m[1])
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.TrimSpace(m[2]), version, true
+}
+
+// normalizeDecisionSchema fills missing schema versions (envelope version, else v1)
+// Versions newer than CurrentDecisionSchemaVersion are parsed best-effort: unknown fields are dropped.
+func normalizeDecisionSchema(decisions []Decision, envelopeVersion int) {
+	for i := range decisions {
+		d := &decisions[i]
+		if d.SchemaVersion <= 0 {
+			d.SchemaVersion = envelopeVersion
+		}
+		if d.SchemaVersion <= 0 {
+			d.SchemaVersion = DecisionSchemaV1
+		}
+		if d.SchemaVersion > CurrentDecisionSchemaVersion {
+			logger.Warnf("⚠️  %s %s uses decision schema v%d (supported ≤ v%d), parsing known fields only",
+				d.Symbol, d.Action, d.SchemaVersion, CurrentDecisionSchemaVersion)
+		}
+		if d.SchemaVersion == DecisionSchemaV1 && len(d.Metadata) > 0 {
+			// Metadata without a version is accepted as v2
+			d.SchemaVersion = DecisionSchemaV2
+		}
+	}
+}
+
+// MetaFloat gets numeric metadata value (numbers or numeric strings)
+func (d *Decision) MetaFloat(key string) (float64, bool) {
+	switch v := d.Metadata[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// MetaString gets string metadata value
+func (d *Decision) MetaString(key string) (string, bool) {
+	v, ok := d.Metadata[key].(string)
+	return v, ok
+}
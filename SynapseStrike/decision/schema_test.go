@@ -0,0 +1,57 @@
+package decision
+
+import "testing"
+
+func TestExtractDecisionsSchemaVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		wantVersion int
+		wantOrder   string
+	}{
+		{
+			name:        "v1 array",
+			response:    "<decision>\n```json\n[{\"symbol\": \"AAPL\", \"action\": \"wait\"}]\n```\n</decision>",
+			wantVersion: DecisionSchemaV1,
+		},
+		{
+			name:        "v2 array with metadata",
+			response:    "<decision>\n```json\n[{\"schema_version\": 2, \"symbol\": \"AAPL\", \"action\": \"wait\", \"metadata\": {\"order_type\": \"limit\", \"valid_for_minutes\": 15}}]\n```\n</decision>",
+			wantVersion: DecisionSchemaV2,
+			wantOrder:   "limit",
+		},
+		{
+			name:        "v2 envelope",
+			response:    "<decision>\n```json\n{\"schema_version\": 2, \"decisions\": [{\"symbol\": \"AAPL\", \"action\": \"wait\", \"metadata\": {\"order_type\": \"market\"}}]}\n```\n</decision>",
+			wantVersion: DecisionSchemaV2,
+			wantOrder:   "market",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions, err := extractDecisions(tt.response)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(decisions) != 1 || decisions[0].Symbol != "AAPL" {
+				t.Fatalf("unexpected decisions: %+v", decisions)
+			}
+			d := decisions[0]
+			if d.SchemaVersion != tt.wantVersion {
+				t.Errorf("schema version = %d, want %d", d.SchemaVersion, tt.wantVersion)
+			}
+			orderType, _ := d.MetaString(MetaOrderType)
+			if orderType != tt.wantOrder {
+				t.Errorf("order type = %q, want %q", orderType, tt.wantOrder)
+			}
+		})
+	}
+
+	d := Decision{Metadata: map[string]interface{}{MetaValidForMinutes: 15.0, MetaLimitPrice: "101.5"}}
+	if v, ok := d.MetaFloat(MetaValidForMinutes); !ok || v != 15 {
+		t.Errorf("valid_for_minutes = %v, %v", v, ok)
+	}
+	if v, ok := d.MetaFloat(MetaLimitPrice); !ok || v != 101.5 {
+		t.Errorf("limit_price = %v, %v", v, ok)
+	}
+}
@@ -0,0 +1,259 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Self-Review Second Pass
+// ============================================================================
+// When EnableSelfReview is set, the merged decisions of a cycle are sent back
+// to the AI together with the key risk constraints and the account state. The
+// reviewer confirms, amends (leverage / size / stop / target) or rejects each
+// actionable decision; only confirmed or amended ones go on to execution.
+// Open decisions without a verdict (review failed, entry missing) are dropped,
+// close decisions are kept since closing only reduces risk.
+
+const (
+	ReviewConfirm = "confirm"
+	ReviewAmend   = "amend"
+	ReviewReject  = "reject"
+)
+
+// ReviewVerdict reviewer's verdict on one proposed decision
+type ReviewVerdict struct {
+	Index   int    `json:"index"` // 1-based position in the proposed list
+	Symbol  string `json:"symbol"`
+	Action  string `json:"action"`
+	Verdict string `json:"verdict"` // confirm | amend | reject | missing
+	Reason  string `json:"reason,omitempty"`
+
+	// Amended parameters (amend only, zero = unchanged)
+	Leverage        int     `json:"leverage,omitempty"`
+	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
+	StopLoss        float64 `json:"stop_loss,omitempty"`
+	TakeProfit      float64 `json:"take_profit,omitempty"`
+}
+
+// String formats verdict as execution log line
+func (v ReviewVerdict) String() string {
+	icon := map[string]string{ReviewConfirm: "✅", ReviewAmend: "✏️", ReviewReject: "🚫"}[v.Verdict]
+	if icon == "" {
+		icon = "❔"
+	}
+	line := fmt.Sprintf("%s Self-review %s #%d %s %s", icon, v.Verdict, v.Index, v.Symbol, v.Action)
+	if v.Reason != "" {
+		line += ": " + v.Reason
+	}
+	return line
+}
+
+// SelfReviewResult outcome of the self-review pass
+type SelfReviewResult struct {
+	Verdicts    []ReviewVerdict `json:"verdicts,omitempty"`
+	Error       string          `json:"error,omitempty"` // Review call/parse failure (unreviewed opens dropped)
+	RawResponse string          `json:"raw_response,omitempty"`
+	DurationMs  int64           `json:"duration_ms,omitempty"`
+}
+
+// needsReview whether a decision goes through self-review (hold/wait pass unchanged)
+func needsReview(d Decision) bool {
+	return strings.HasPrefix(d.Action, "open_") || strings.HasPrefix(d.Action, "close_")
+}
+
+// selfReviewDecisions runs the second pass on decisions, returns decisions allowed to execute
+// Amended decisions are checked with validate again before they are kept.
+func (e *StrategyEngine) selfReviewDecisions(ctx *Context, mcpClient mcp.AIClient, decisions []Decision, validate func(*Decision) error) ([]Decision, *SelfReviewResult) {
+	var proposed []Decision
+	for _, d := range decisions {
+		if needsReview(d) {
+			proposed = append(proposed, d)
+		}
+	}
+	if len(proposed) == 0 {
+		return decisions, nil
+	}
+
+	result := &SelfReviewResult{}
+	start := time.Now()
+	systemPrompt := buildSelfReviewSystemPrompt()
+	userPrompt := e.buildSelfReviewUserPrompt(ctx, proposed)
+	response, err := callAIWithDeadline(ctx.Deadline, func() (string, error) {
+		return mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	})
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.RawResponse = response
+
+	var verdicts map[int]ReviewVerdict
+	if err == nil {
+		verdicts, err = parseReviewVerdicts(response)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		logger.Warnf("⚠️  [Self-Review] Review failed, unreviewed opens will be dropped: %v", err)
+	}
+
+	kept := make([]Decision, 0, len(decisions))
+	index := 0
+	for _, d := range decisions {
+		if !needsReview(d) {
+			kept = append(kept, d)
+			continue
+		}
+		index++
+		verdict, ok := verdicts[index]
+		if !ok {
+			verdict = ReviewVerdict{Verdict: "missing", Reason: "no verdict from reviewer"}
+		}
+		verdict.Index, verdict.Symbol, verdict.Action = index, d.Symbol, d.Action
+
+		switch verdict.Verdict {
+		case ReviewConfirm:
+			kept = append(kept, d)
+		case ReviewAmend:
+			amended := applyReviewAmendment(d, verdict)
+			if err := validate(&amended); err != nil {
+				verdict.Verdict = ReviewReject
+				verdict.Reason = strings.TrimSpace(verdict.Reason + " (amendment invalid: " + err.Error() + ")")
+				logger.Infof("🚫 [Self-Review] Invalid amendment of %s %s dropped: %v", d.Symbol, d.Action, err)
+				break
+			}
+			kept = append(kept, amended)
+		case ReviewReject:
+			logger.Infof("🚫 [Self-Review] Rejected %s %s: %s", d.Symbol, d.Action, verdict.Reason)
+		default:
+			if strings.HasPrefix(d.Action, "close_") {
+				kept = append(kept, d)
+			} else {
+				logger.Infof("🚫 [Self-Review] Dropped unreviewed %s %s", d.Symbol, d.Action)
+			}
+		}
+		result.Verdicts = append(result.Verdicts, verdict)
+	}
+
+	logger.Infof("🔍 [Self-Review] %d proposed → %d kept in %.1fs", len(proposed), len(kept)-(len(decisions)-len(proposed)), float64(result.DurationMs)/1000)
+	return kept, result
+}
+
+// applyReviewAmendment overrides decision parameters amended by the reviewer
+func applyReviewAmendment(d Decision, v ReviewVerdict) Decision {
+	if v.Leverage > 0 {
+		d.Leverage = v.Leverage
+	}
+	if v.PositionSizeUSD > 0 {
+		d.PositionSizeUSD = v.PositionSizeUSD
+	}
+	if v.StopLoss > 0 {
+		d.StopLoss = v.StopLoss
+	}
+	if v.TakeProfit > 0 {
+		d.TakeProfit = v.TakeProfit
+	}
+	if v.Reason != "" {
+		d.Reasoning = strings.TrimSpace(d.Reasoning + " [Review: " + v.Reason + "]")
+	}
+	return d
+}
+
+// parseReviewVerdicts extracts verdict array from review response, keyed by index
+func parseReviewVerdicts(response string) (map[int]ReviewVerdict, error) {
+	s := removeInvisibleRunes(response)
+	if match := reDecisionTag.FindStringSubmatch(s); len(match) > 1 {
+		s = match[1]
+	}
+	start, end := strings.Index(s, "["), strings.LastIndex(s, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no verdict array in review response")
+	}
+
+	var list []ReviewVerdict
+	if err := json.Unmarshal([]byte(s[start:end+1]), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse review verdicts: %w", err)
+	}
+	verdicts := make(map[int]ReviewVerdict, len(list))
+	for _, v := range list {
+		v.Verdict = strings.ToLower(strings.TrimSpace(v.Verdict))
+		if v.Index <= 0 {
+			continue
+		}
+		switch v.Verdict {
+		case ReviewConfirm, ReviewAmend, ReviewReject:
+			verdicts[v.Index] = v
+		}
+	}
+	return verdicts, nil
+}
+
+// buildSelfReviewSystemPrompt reviewer role and output contract
+func buildSelfReviewSystemPrompt() string {
+	var sb strings.Builder
+	sb.WriteString("# You are a risk reviewer double-checking trading decisions\n\n")
+	sb.WriteString("Another pass of the trading AI proposed the decisions below. Check each one against the constraints and the account state: ")
+	sb.WriteString("stop/target on the correct side of price, risk/reward, position size and leverage within limits, no duplicate or conflicting positions, reasoning consistent with the action.\n\n")
+	sb.WriteString("For each decision answer one verdict:\n")
+	sb.WriteString("- confirm: execute as proposed\n")
+	sb.WriteString("- amend: execute with corrected leverage / position_size_usd / stop_loss / take_profit (only give fields you change)\n")
+	sb.WriteString("- reject: do not execute\n\n")
+	sb.WriteString("# Output format\n\n")
+	sb.WriteString("<decision>\n")
+	sb.WriteString("[{\"index\": 1, \"verdict\": \"confirm\", \"reason\": \"...\"}, {\"index\": 2, \"verdict\": \"amend\", \"stop_loss\": 98.5, \"reason\": \"...\"}]\n")
+	sb.WriteString("</decision>\n\n")
+	sb.WriteString("Give exactly one verdict per index. Decisions without a verdict are not executed.\n")
+	return sb.String()
+}
+
+// buildSelfReviewUserPrompt proposed decisions plus key constraints and account state
+func (e *StrategyEngine) buildSelfReviewUserPrompt(ctx *Context, proposed []Decision) string {
+	risk := e.GetRiskControlConfig()
+	var sb strings.Builder
+
+	sb.WriteString("## Constraints\n")
+	sb.WriteString(fmt.Sprintf("- Max positions: %d\n", risk.MaxPositions))
+	sb.WriteString(fmt.Sprintf("- Max leverage: large cap %dx, small cap %dx\n", risk.LargeCapMaxMargin, risk.SmallCapMaxMargin))
+	sb.WriteString(fmt.Sprintf("- Max position value: large cap %.1f× equity, small cap %.1f× equity\n", risk.LargeCapMaxPositionValueRatio, risk.SmallCapMaxPositionValueRatio))
+	if risk.MaxPositionSizeUSD > 0 {
+		sb.WriteString(fmt.Sprintf("- Max position size: %.2f USD\n", risk.MaxPositionSizeUSD))
+	}
+	if risk.MaxRiskPerTradeUSD > 0 || risk.MaxRiskPerTradePct > 0 {
+		sb.WriteString(fmt.Sprintf("- Max risk per trade (loss at stop): %s\n", formatRiskPerTradeCaps(risk)))
+	}
+	sb.WriteString(fmt.Sprintf("- Min risk/reward: %.1f, min confidence: %d\n", risk.MinRiskRewardRatio, risk.MinConfidence))
+	if overrides := formatSymbolOverrides(risk); overrides != "" {
+		sb.WriteString("- Per-symbol limits:\n")
+		sb.WriteString(overrides)
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Account\n")
+	sb.WriteString(fmt.Sprintf("Equity %.2f | Available %.2f | Margin used %.1f%% | Positions %d\n",
+		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.MarginUsedPct, ctx.Account.PositionCount))
+	for _, pos := range ctx.Positions {
+		sb.WriteString(fmt.Sprintf("- %s %s entry %.4f mark %.4f qty %.4f %dx PnL %+.2f%%\n",
+			pos.Symbol, pos.Side, pos.EntryPrice, pos.MarkPrice, pos.Quantity, pos.Leverage, pos.UnrealizedPnLPct))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Proposed decisions\n")
+	for i, d := range proposed {
+		sb.WriteString(fmt.Sprintf("%d. %s %s", i+1, d.Symbol, d.Action))
+		if strings.HasPrefix(d.Action, "open_") {
+			sb.WriteString(fmt.Sprintf(" | size %.2f USD %dx | SL %.4f TP %.4f", d.PositionSizeUSD, d.Leverage, d.StopLoss, d.TakeProfit))
+			if d.EntryPrice > 0 {
+				sb.WriteString(fmt.Sprintf(" | limit entry %.4f", d.EntryPrice))
+			}
+			if data, ok := ctx.MarketDataMap[d.Symbol]; ok && data != nil {
+				sb.WriteString(fmt.Sprintf(" | price %.4f", data.CurrentPrice))
+			}
+		}
+		if d.Confidence > 0 {
+			sb.WriteString(fmt.Sprintf(" | confidence %d", d.Confidence))
+		}
+		sb.WriteString("\n   Reasoning: " + d.Reasoning + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestSelfReviewDecisions tests that only confirmed or amended decisions pass the review
+func TestSelfReviewDecisions(t *testing.T) {
+	client := mcp.NewMockClient(`<reasoning>checked</reasoning><decision>[
+		{"index": 1, "verdict": "confirm"},
+		{"index": 2, "verdict": "amend", "stop_loss": 104, "reason": "stop too wide"},
+		{"index": 3, "verdict": "reject", "reason": "duplicate"}
+	]</decision>`)
+	decisions := []Decision{
+		{Symbol: "SYM0", Action: "open_long", StopLoss: 95, TakeProfit: 110},
+		{Symbol: "SYM1", Action: "open_short", StopLoss: 108, TakeProfit: 90},
+		{Symbol: "SYM2", Action: "open_long", StopLoss: 95, TakeProfit: 110},
+		{Symbol: "SYM3", Action: "hold"},
+		{Symbol: "SYM4", Action: "open_long", StopLoss: 95, TakeProfit: 110},
+		{Symbol: "SYM5", Action: "close_long"},
+	}
+
+	kept, result := newFixtureEngine().selfReviewDecisions(newBatchContext(6), client, decisions, func(*Decision) error { return nil })
+	if got := strings.Join(decisionActions(kept), ","); got != "SYM0:open_long,SYM1:open_short,SYM3:hold,SYM5:close_long" {
+		t.Fatalf("kept decisions = %s", got)
+	}
+	if kept[1].StopLoss != 104 {
+		t.Errorf("amended stop loss = %v, want 104", kept[1].StopLoss)
+	}
+	if len(result.Verdicts) != 5 || result.Verdicts[3].Verdict != "missing" {
+		t.Errorf("unexpected verdicts: %+v", result.Verdicts)
+	}
+	if !strings.Contains(client.Calls()[0].UserPrompt, "5. SYM5 close_long") {
+		t.Errorf("review prompt should list proposed decisions")
+	}
+}
+
+// TestSelfReviewDecisions_InvalidAmendment tests that amendments failing validation are dropped
+func TestSelfReviewDecisions_InvalidAmendment(t *testing.T) {
+	client := mcp.NewMockClient(`<decision>[{"index": 1, "verdict": "amend", "take_profit": 90}]</decision>`)
+	decisions := []Decision{{Symbol: "SYM0", Action: "open_long", StopLoss: 95, TakeProfit: 110}}
+
+	kept, result := newFixtureEngine().selfReviewDecisions(newBatchContext(1), client, decisions, func(d *Decision) error {
+		if d.TakeProfit <= d.StopLoss {
+			return fmt.Errorf("take profit below stop loss")
+		}
+		return nil
+	})
+	if len(kept) != 0 {
+		t.Fatalf("invalid amendment should be dropped, kept %v", decisionActions(kept))
+	}
+	if result.Verdicts[0].Verdict != ReviewReject {
+		t.Errorf("verdict = %s, want reject", result.Verdicts[0].Verdict)
+	}
+}
+
+// TestSelfReviewDecisions_ReviewFailure tests that a failed review drops opens but keeps closes
+func TestSelfReviewDecisions_ReviewFailure(t *testing.T) {
+	client := mcp.NewMockClient("I cannot review these decisions.")
+	decisions := []Decision{
+		{Symbol: "SYM0", Action: "open_long", StopLoss: 95, TakeProfit: 110},
+		{Symbol: "SYM1", Action: "close_short"},
+	}
+
+	kept, result := newFixtureEngine().selfReviewDecisions(newBatchContext(2), client, decisions, func(*Decision) error { return nil })
+	if got := strings.Join(decisionActions(kept), ","); got != "SYM1:close_short" {
+		t.Errorf("kept decisions = %s, want SYM1:close_short", got)
+	}
+	if result.Error == "" {
+		t.Error("expected review error to be recorded")
+	}
+}
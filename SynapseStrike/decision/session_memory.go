@@ -0,0 +1,187 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Session Memory (short-term conversation memory)
+// ============================================================================
+// Every cycle is a fresh, stateless AI call, so the AI re-reads the same data
+// and may argue the opposite of its own thesis from five minutes ago. With
+// SessionMemory.Enabled the trader keeps the reasoning and the executed
+// actions of the last Cycles cycles; after each cycle a cheap summarization
+// call (SessionMemory.AIModelID) compresses them into a short running thesis
+// of at most MaxChars characters, which is injected into the next prompts.
+// The summarization runs in the background so the cycle does not wait for
+// it; while one is in flight later cycles are only remembered and included
+// in the next summarization.
+// The memory lives only for the trader's run: a restart starts a new session.
+// A failed summarization keeps the previous summary.
+
+const (
+	defaultSessionMemoryCycles   = 6
+	defaultSessionMemoryMaxChars = 1200
+	sessionReasoningMaxChars     = 1500 // Tail of each cycle's chain of thought sent for summarization
+	sessionSummaryTimeout        = 30 * time.Second
+)
+
+// SessionCycle reasoning and actions of one cycle as remembered by the session memory
+type SessionCycle struct {
+	Cycle     int
+	Time      time.Time
+	Reasoning string   // Chain of thought excerpt
+	Actions   []string // "AAPL open_long executed", "TSLA close_short failed: ...", "NVDA hold"
+}
+
+// SessionMemory short-term memory of a single trader's run
+type SessionMemory struct {
+	mu          sync.Mutex
+	cycles      []SessionCycle
+	summary     string
+	session     int  // Incremented by Reset, a summary of an earlier session is discarded
+	summarizing bool // A background summarization is in flight
+}
+
+// NewSessionMemory creates an empty session memory
+func NewSessionMemory() *SessionMemory {
+	return &SessionMemory{}
+}
+
+// Add remembers a cycle, keeping only the last keep cycles (<= 0 = default)
+func (m *SessionMemory) Add(cycle SessionCycle, keep int) {
+	if keep <= 0 {
+		keep = defaultSessionMemoryCycles
+	}
+	if runes := []rune(cycle.Reasoning); len(runes) > sessionReasoningMaxChars {
+		cycle.Reasoning = "..." + string(runes[len(runes)-sessionReasoningMaxChars:])
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cycles = append(m.cycles, cycle)
+	if len(m.cycles) > keep {
+		m.cycles = append([]SessionCycle(nil), m.cycles[len(m.cycles)-keep:]...)
+	}
+}
+
+// Summary current summary ("" before the first summarization)
+func (m *SessionMemory) Summary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.summary
+}
+
+// Reset forgets all cycles and the summary (new session)
+func (m *SessionMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cycles, m.summary = nil, ""
+	m.session++
+}
+
+// SummarizeInBackground runs Summarize in a goroutine, returns false if a summarization is already running
+// onError (optional) is called with the error of a failed summarization.
+func (m *SessionMemory) SummarizeInBackground(client mcp.AIClient, maxChars int, onError func(error)) bool {
+	m.mu.Lock()
+	if m.summarizing {
+		m.mu.Unlock()
+		return false
+	}
+	m.summarizing = true
+	m.mu.Unlock()
+
+	go func() {
+		err := m.Summarize(client, maxChars)
+		m.mu.Lock()
+		m.summarizing = false
+		m.mu.Unlock()
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}()
+	return true
+}
+
+// Summarize compresses the remembered cycles into a new summary of at most maxChars (<= 0 = default)
+func (m *SessionMemory) Summarize(client mcp.AIClient, maxChars int) error {
+	if maxChars <= 0 {
+		maxChars = defaultSessionMemoryMaxChars
+	}
+	m.mu.Lock()
+	cycles := append([]SessionCycle(nil), m.cycles...)
+	session := m.session
+	m.mu.Unlock()
+	if len(cycles) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	response, err := callAIWithDeadline(start.Add(sessionSummaryTimeout), func() (string, error) {
+		return client.CallWithMessages(buildSessionSummarySystemPrompt(maxChars), buildSessionSummaryUserPrompt(cycles))
+	})
+	if err != nil {
+		return fmt.Errorf("session summary call failed: %w", err)
+	}
+	summary := strings.TrimSpace(response)
+	if summary == "" {
+		return fmt.Errorf("session summary is empty")
+	}
+	if runes := []rune(summary); len(runes) > maxChars {
+		summary = string(runes[:maxChars]) + "..."
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session != session {
+		return fmt.Errorf("session was reset during summarization")
+	}
+	m.summary = summary
+	logger.Infof("🧠 [Session Memory] Summarized %d cycles by %s/%s in %.1fs (%d chars)",
+		len(cycles), client.GetProvider(), client.GetModel(), time.Since(start).Seconds(), len([]rune(summary)))
+	return nil
+}
+
+func buildSessionSummarySystemPrompt(maxChars int) string {
+	var sb strings.Builder
+	sb.WriteString("You maintain the short-term memory of an AI trader. Compress its recent trading cycles into a running thesis it will read at the start of the next cycle.\n\n")
+	sb.WriteString("Keep:\n")
+	sb.WriteString("- the current market view and bias per symbol, and what would invalidate it\n")
+	sb.WriteString("- positions taken or closed and why, orders that failed\n")
+	sb.WriteString("- setups it is waiting for (levels, confirmations)\n")
+	sb.WriteString("- where its view changed between cycles, and the reason\n\n")
+	sb.WriteString(fmt.Sprintf("Plain text, terse bullet points, at most %d characters. No JSON, no advice, no new analysis: only what the trader itself concluded.\n", maxChars))
+	return sb.String()
+}
+
+func buildSessionSummaryUserPrompt(cycles []SessionCycle) string {
+	var sb strings.Builder
+	sb.WriteString("Recent cycles, oldest first:\n\n")
+	for _, c := range cycles {
+		sb.WriteString(fmt.Sprintf("## Cycle #%d (%s UTC)\n", c.Cycle, c.Time.UTC().Format("2006-01-02 15:04")))
+		if len(c.Actions) > 0 {
+			sb.WriteString("Actions: " + strings.Join(c.Actions, "; ") + "\n")
+		}
+		if c.Reasoning != "" {
+			sb.WriteString("Reasoning:\n" + c.Reasoning + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatSessionMemory formats the session summary for the user prompt (empty if none)
+func formatSessionMemory(ctx *Context) string {
+	if ctx.SessionSummary == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Your Recent Thesis (session memory)\n")
+	sb.WriteString(ctx.SessionSummary + "\n")
+	sb.WriteString("Stay consistent with your recent thesis unless the data has materially changed; if you change your view, say what changed.\n\n")
+	return sb.String()
+}
@@ -0,0 +1,124 @@
+package decision
+
+import (
+	"SynapseStrike/mcp"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSessionMemory tests that the last cycles are summarized and a failed summary keeps the previous one
+func TestSessionMemory(t *testing.T) {
+	mem := NewSessionMemory()
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	for i := 1; i <= 4; i++ {
+		mem.Add(SessionCycle{
+			Cycle:     i,
+			Time:      start.Add(time.Duration(i) * 5 * time.Minute),
+			Reasoning: strings.Repeat("x", sessionReasoningMaxChars) + "NVDA breakout thesis",
+			Actions:   []string{"NVDA open_long executed"},
+		}, 3)
+	}
+
+	client := mcp.NewMockClient("- NVDA: long bias above 120, thesis intact").AddError(errors.New("rate limited"))
+	if err := mem.Summarize(client, 20); err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if got := mem.Summary(); got != "- NVDA: long bias ab..." {
+		t.Errorf("summary = %q, want truncated to 20 chars", got)
+	}
+	prompt := client.Calls()[0].UserPrompt
+	if strings.Contains(prompt, "Cycle #1 ") || !strings.Contains(prompt, "Cycle #2 ") || !strings.Contains(prompt, "Cycle #4 ") {
+		t.Errorf("prompt should hold the last 3 cycles:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "...xxx") || !strings.Contains(prompt, "NVDA breakout thesis") {
+		t.Errorf("reasoning should be cut to its tail")
+	}
+
+	if err := mem.Summarize(client, 20); err == nil {
+		t.Fatal("expected error from failing summary call")
+	}
+	if mem.Summary() == "" {
+		t.Error("failed summary should keep the previous summary")
+	}
+
+	ctx := &Context{SessionSummary: mem.Summary()}
+	if got := formatSessionMemory(ctx); !strings.Contains(got, "## Your Recent Thesis") || !strings.Contains(got, "NVDA") {
+		t.Errorf("formatSessionMemory() = %q", got)
+	}
+	mem.Reset()
+	if mem.Summary() != "" || formatSessionMemory(&Context{}) != "" {
+		t.Error("reset session should have no summary")
+	}
+}
+
+// blockingSummaryClient holds summary calls until release is closed
+type blockingSummaryClient struct {
+	*mcp.MockClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingSummaryClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.MockClient.CallWithMessages(systemPrompt, userPrompt)
+}
+
+// TestSessionMemorySummarizeInBackground tests that one summarization runs at a time without blocking the caller
+func TestSessionMemorySummarizeInBackground(t *testing.T) {
+	newClient := func(response string) *blockingSummaryClient {
+		return &blockingSummaryClient{
+			MockClient: mcp.NewMockClient(response),
+			started:    make(chan struct{}, 1),
+			release:    make(chan struct{}),
+		}
+	}
+	waitSummary := func(mem *SessionMemory, want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for mem.Summary() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("summary = %q, want %q", mem.Summary(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	mem := NewSessionMemory()
+	mem.Add(SessionCycle{Cycle: 1, Time: time.Now(), Actions: []string{"NVDA open_long executed"}}, 3)
+	client := newClient("- NVDA: long")
+	if !mem.SummarizeInBackground(client, 100, nil) {
+		t.Fatal("first summarization should start")
+	}
+	<-client.started
+
+	mem.Add(SessionCycle{Cycle: 2, Time: time.Now(), Actions: []string{"NVDA hold"}}, 3)
+	if mem.SummarizeInBackground(newClient("unused"), 100, nil) {
+		t.Error("second summarization should not start while the first is running")
+	}
+	close(client.release)
+	waitSummary(mem, "- NVDA: long")
+
+	// A summary finishing after a reset belongs to the previous session
+	client = newClient("- stale")
+	failed := make(chan error, 1)
+	for !mem.SummarizeInBackground(client, 100, func(err error) { failed <- err }) {
+		time.Sleep(5 * time.Millisecond) // Previous goroutine is still clearing its flag
+	}
+	<-client.started
+	mem.Reset()
+	close(client.release)
+	select {
+	case err := <-failed:
+		if err == nil || !strings.Contains(err.Error(), "reset") {
+			t.Errorf("onError = %v, want reset error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("onError was not called for the discarded summary")
+	}
+	if mem.Summary() != "" {
+		t.Errorf("summary after reset = %q, want empty", mem.Summary())
+	}
+}
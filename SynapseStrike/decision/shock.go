@@ -0,0 +1,236 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Market Shock Detection
+// ============================================================================
+// A cycle runs in shock mode when either
+//   - an index symbol (SPY/BTCUSDT by default) moved more than MovePct within
+//     the last WindowMinutes, measured as the largest excursion of the window's
+//     bars from the close before the window (so overnight gaps count), or
+//   - the candidates' volatility spikes: the average z-score of the recent
+//     true range (% of price) against each candidate's own history reaches
+//     ATRZScore.
+// Shock mode blocks every new open of the cycle (CODE ENFORCED), the trader
+// tightens stops on open positions to StopPct and the decision record is
+// marked as a shock cycle.
+
+const (
+	defaultShockIndexMovePct  = 3.0
+	defaultShockWindowMinutes = 30
+	defaultShockStopPct       = 1.0
+	shockTimeframe            = "5m"
+	shockKlineCount           = 100
+	shockRecentBars           = 3  // Bars averaged for the recent true range
+	shockMinBaselineBars      = 20 // Min history bars for a candidate's ATR z-score
+)
+
+var defaultShockIndices = []string{"SPY", "BTCUSDT"}
+
+// IndexMove largest move of an index within the shock window
+type IndexMove struct {
+	Symbol  string  `json:"symbol"`
+	MovePct float64 `json:"move_pct"` // Signed % from the close before the window
+}
+
+// MarketShock detected market shock (nil in ctx = normal cycle)
+type MarketShock struct {
+	Reasons   []string    `json:"reasons"`
+	Moves     []IndexMove `json:"moves"`
+	ATRZScore float64     `json:"atr_z_score"` // Candidate-wide recent true range z-score
+	StopPct   float64     `json:"stop_pct"`    // Stop distance for open positions
+}
+
+// Summary one-line description for prompts, logs and the decision record
+func (s *MarketShock) Summary() string {
+	return strings.Join(s.Reasons, "; ")
+}
+
+// IndexShockMove largest signed move (%) of the bars within window before now,
+// relative to the last close before the window (ok = false without bars on both sides)
+func IndexShockMove(klines []market.KlineBar, window time.Duration, now time.Time) (float64, bool) {
+	windowStart := now.Add(-window).UnixMilli()
+	ref := -1
+	for i := len(klines) - 1; i >= 0; i-- {
+		if klines[i].Time < windowStart {
+			ref = i
+			break
+		}
+	}
+	if ref < 0 || ref == len(klines)-1 || klines[ref].Close <= 0 {
+		return 0, false
+	}
+
+	refClose := klines[ref].Close
+	move := 0.0
+	for _, k := range klines[ref+1:] {
+		for _, price := range []float64{k.High, k.Low, k.Close} {
+			if pct := (price - refClose) / refClose * 100; math.Abs(pct) > math.Abs(move) {
+				move = pct
+			}
+		}
+	}
+	return move, true
+}
+
+// ATRZScore z-score of the recent true range (% of price) against the earlier bars (ok = false if too short)
+func ATRZScore(klines []market.KlineBar) (float64, bool) {
+	if len(klines) < shockMinBaselineBars+shockRecentBars+1 {
+		return 0, false
+	}
+	ranges := make([]float64, 0, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		prevClose := klines[i-1].Close
+		if prevClose <= 0 {
+			return 0, false
+		}
+		tr := math.Max(klines[i].High-klines[i].Low,
+			math.Max(math.Abs(klines[i].High-prevClose), math.Abs(klines[i].Low-prevClose)))
+		ranges = append(ranges, tr/prevClose*100)
+	}
+
+	baseline := ranges[:len(ranges)-shockRecentBars]
+	mean := 0.0
+	for _, r := range baseline {
+		mean += r
+	}
+	mean /= float64(len(baseline))
+	variance := 0.0
+	for _, r := range baseline {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(baseline)))
+	if std == 0 {
+		return 0, false
+	}
+
+	recent := 0.0
+	for _, r := range ranges[len(ranges)-shockRecentBars:] {
+		recent += r
+	}
+	recent /= shockRecentBars
+	return (recent - mean) / std, true
+}
+
+// ComputeShock fills ctx.Shock when the market is in shock (no-op unless shock detection is enabled)
+func (e *StrategyEngine) ComputeShock(ctx *Context) {
+	cfg := e.config.Shock
+	if !cfg.Enabled || ctx == nil {
+		return
+	}
+	movePct := cfg.MovePct
+	if movePct <= 0 {
+		movePct = defaultShockIndexMovePct
+	}
+	windowMinutes := cfg.WindowMinutes
+	if windowMinutes <= 0 {
+		windowMinutes = defaultShockWindowMinutes
+	}
+	symbols := cfg.IndexSymbols
+	if len(symbols) == 0 {
+		symbols = defaultShockIndices
+	}
+
+	shock := &MarketShock{StopPct: cfg.StopPct}
+	if shock.StopPct <= 0 {
+		shock.StopPct = defaultShockStopPct
+	}
+	now := time.Now()
+	for _, symbol := range symbols {
+		series, err := fetchShockSeries(symbol)
+		if err != nil {
+			logger.Infof("⚠️  [Shock] Failed to fetch %s %s data: %v", symbol, shockTimeframe, err)
+			continue
+		}
+		move, ok := IndexShockMove(series.Klines, time.Duration(windowMinutes)*time.Minute, now)
+		if !ok {
+			continue
+		}
+		shock.Moves = append(shock.Moves, IndexMove{Symbol: symbol, MovePct: move})
+		if math.Abs(move) >= movePct {
+			shock.Reasons = append(shock.Reasons, fmt.Sprintf("%s moved %+.2f%% in %d min (limit %.2f%%)",
+				symbol, move, windowMinutes, movePct))
+		}
+	}
+
+	if cfg.ATRZScore > 0 {
+		timeframe := e.config.Indicators.Klines.PrimaryTimeframe
+		sum, count := 0.0, 0
+		for _, stock := range ctx.CandidateStocks {
+			data, ok := ctx.MarketDataMap[stock.Symbol]
+			if !ok || data.TimeframeData[timeframe] == nil {
+				continue
+			}
+			if z, ok := ATRZScore(data.TimeframeData[timeframe].Klines); ok {
+				sum += z
+				count++
+			}
+		}
+		if count > 0 {
+			shock.ATRZScore = sum / float64(count)
+			if shock.ATRZScore >= cfg.ATRZScore {
+				shock.Reasons = append(shock.Reasons, fmt.Sprintf("candidate ATR z-score %.2f across %d symbols (limit %.2f)",
+					shock.ATRZScore, count, cfg.ATRZScore))
+			}
+		}
+	}
+
+	if len(shock.Reasons) == 0 {
+		return
+	}
+	ctx.Shock = shock
+	logger.Warnf("🚨 [Shock] Shock mode: %s", shock.Summary())
+}
+
+// fetchShockSeries recent 5m bars of an index symbol (crypto pairs from the futures feed, others as stocks)
+func fetchShockSeries(symbol string) (*market.TimeframeSeriesData, error) {
+	var data *market.Data
+	var err error
+	if market.IsCrypto(symbol) {
+		data, err = market.GetWithTimeframes(symbol, []string{shockTimeframe}, shockTimeframe, shockKlineCount)
+	} else {
+		data, err = market.GetStockDataWithTimeframes(symbol, []string{shockTimeframe}, shockTimeframe, shockKlineCount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	series := data.TimeframeData[shockTimeframe]
+	if series == nil {
+		return nil, fmt.Errorf("no %s data", shockTimeframe)
+	}
+	return series, nil
+}
+
+// enforceShock turns every open into wait while the market is in shock
+func (e *StrategyEngine) enforceShock(decisions []Decision, ctx *Context) []Decision {
+	if ctx.Shock == nil {
+		return decisions
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Action != "open_long" && d.Action != "open_short" {
+			continue
+		}
+		logger.Warnf("🚨 [Shock] Blocked %s %s: %s", d.Action, d.Symbol, ctx.Shock.Summary())
+		d.Reasoning = fmt.Sprintf("[Shock mode blocked %s: %s] %s", d.Action, ctx.Shock.Summary(), d.Reasoning)
+		d.Action = "wait"
+	}
+	return decisions
+}
+
+// formatShock shock mode section of the user prompt (empty in normal cycles)
+func formatShock(shock *MarketShock) string {
+	if shock == nil {
+		return ""
+	}
+	return fmt.Sprintf("🚨 MARKET SHOCK MODE: %s\nNew opens are blocked this cycle and stops of open positions are tightened to %.2f%% from price. Focus on protecting open positions.\n\n",
+		shock.Summary(), shock.StopPct)
+}
@@ -0,0 +1,61 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"testing"
+	"time"
+)
+
+func TestIndexShockMove(t *testing.T) {
+	now := time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC)
+	bar := func(minutesAgo int, high, low, close float64) market.KlineBar {
+		return market.KlineBar{Time: now.Add(-time.Duration(minutesAgo) * time.Minute).UnixMilli(), High: high, Low: low, Close: close}
+	}
+	klines := []market.KlineBar{
+		bar(60, 101, 99, 100),
+		bar(35, 101, 99, 100), // Last close before the 30 min window
+		bar(20, 100, 96, 97),
+		bar(5, 99, 97.5, 98.5),
+	}
+	move, ok := IndexShockMove(klines, 30*time.Minute, now)
+	if !ok || move != -4 {
+		t.Errorf("move = %.2f (ok=%v), want -4%% (low of the window)", move, ok)
+	}
+
+	if _, ok := IndexShockMove(klines[:2], 30*time.Minute, now); ok {
+		t.Error("no bars inside the window should not produce a move")
+	}
+}
+
+func TestATRZScore(t *testing.T) {
+	klines := make([]market.KlineBar, 0, 40)
+	for i := 0; i < 36; i++ {
+		spread := 0.5 + float64(i%2)*0.5 // Ranges alternate 1% / 2%
+		klines = append(klines, market.KlineBar{High: 100 + spread, Low: 100 - spread, Close: 100})
+	}
+	calm, ok := ATRZScore(klines)
+	if !ok || calm > 1 {
+		t.Errorf("calm z-score = %.2f (ok=%v), want about 0", calm, ok)
+	}
+
+	for i := 0; i < shockRecentBars; i++ {
+		klines = append(klines, market.KlineBar{High: 104, Low: 96, Close: 100})
+	}
+	spike, _ := ATRZScore(klines)
+	if spike < 3 {
+		t.Errorf("spike z-score = %.2f, want >= 3", spike)
+	}
+}
+
+func TestEnforceShock(t *testing.T) {
+	engine := &StrategyEngine{}
+	decisions := []Decision{
+		{Symbol: "NVDA", Action: "open_long"},
+		{Symbol: "TSLA", Action: "close_short"},
+	}
+	ctx := &Context{Shock: &MarketShock{Reasons: []string{"SPY moved -3.10% in 30 min (limit 3.00%)"}}}
+	decisions = engine.enforceShock(decisions, ctx)
+	if decisions[0].Action != "wait" || decisions[1].Action != "close_short" {
+		t.Errorf("shock mode should block opens only, got %s / %s", decisions[0].Action, decisions[1].Action)
+	}
+}
@@ -0,0 +1,102 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/provider"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Social Trending Candidate Source
+// ============================================================================
+// "social_trending" takes the most mentioned tickers of a social sentiment
+// API (e.g. a self-hosted Reddit/X scraper, see provider/social_sentiment.go)
+// as candidates. Mention counts and sentiment are annotated per candidate in
+// the prompt, in the same spirit as OI Top tags: crowd attention is a
+// momentum and volatility hint, not a trade signal by itself.
+
+const defaultSocialTrendingLimit = 20
+
+// SocialData social annotation of a candidate (for AI decision reference)
+type SocialData struct {
+	Rank             int     // Mentions ranking (1 = most mentioned)
+	Mentions         int     // Mentions over the source's window
+	MentionChangePct float64 // Mention change vs. previous window in % (0 = not reported)
+	Sentiment        float64 // -1 (bearish) .. +1 (bullish)
+}
+
+// usesSocialTrending whether the configured candidate source includes social trending tickers
+func (e *StrategyEngine) usesSocialTrending() bool {
+	source := e.config.CoinSource
+	return source.SourceType == "social_trending" || (source.SourceType == "mixed" && source.UseSocialTrending)
+}
+
+// socialTrending fetches trending tickers with strategy settings (limit <= 0 = all)
+func (e *StrategyEngine) socialTrending(limit int) ([]provider.SocialTrend, error) {
+	source := e.config.CoinSource
+	return provider.GetSocialTrending(provider.SocialSentimentConfig{
+		APIURL:      source.SocialTrendingAPIURL,
+		APIKey:      source.SocialTrendingAPIKey,
+		MinMentions: source.SocialTrendingMinMentions,
+	}, limit)
+}
+
+func (e *StrategyEngine) getSocialTrendingStocks(limit int) ([]CandidateStock, error) {
+	if limit <= 0 {
+		limit = defaultSocialTrendingLimit
+	}
+
+	trends, err := e.socialTrending(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []CandidateStock
+	for _, trend := range trends {
+		candidates = append(candidates, CandidateStock{
+			Symbol:  market.Normalize(trend.Symbol),
+			Sources: []string{"social_trending"},
+		})
+	}
+	return candidates, nil
+}
+
+// ComputeSocialData fills ctx.SocialDataMap from the trending list (no-op unless social_trending source is used)
+// The list is cached by the provider, so this reuses the data fetched for candidate selection.
+func (e *StrategyEngine) ComputeSocialData(ctx *Context) {
+	if ctx == nil || ctx.SocialDataMap != nil || !e.usesSocialTrending() {
+		return
+	}
+	trends, err := e.socialTrending(0)
+	if err != nil {
+		logger.Infof("⚠️  Failed to get social sentiment data: %v", err)
+		return
+	}
+	ctx.SocialDataMap = make(map[string]*SocialData, len(trends))
+	for _, trend := range trends {
+		ctx.SocialDataMap[market.Normalize(trend.Symbol)] = &SocialData{
+			Rank:             trend.Rank,
+			Mentions:         trend.Mentions,
+			MentionChangePct: trend.MentionChangePct,
+			Sentiment:        trend.Sentiment,
+		}
+	}
+}
+
+// formatSocialData social annotation line of a candidate (empty if none)
+func formatSocialData(symbol string, ctx *Context) string {
+	data, ok := ctx.SocialDataMap[symbol]
+	if !ok || data == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Social (rank #%d): %d mentions", data.Rank, data.Mentions))
+	if data.MentionChangePct != 0 {
+		sb.WriteString(fmt.Sprintf(" (%+.0f%% vs previous window)", data.MentionChangePct))
+	}
+	sb.WriteString(fmt.Sprintf(" | sentiment %+.2f (%s)\n\n", data.Sentiment, provider.SentimentLabel(data.Sentiment)))
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatSocialData(t *testing.T) {
+	ctx := &Context{SocialDataMap: map[string]*SocialData{
+		"GME":  {Rank: 1, Mentions: 1200, MentionChangePct: 340, Sentiment: 0.62},
+		"TSLA": {Rank: 2, Mentions: 800, Sentiment: -0.2},
+	}}
+
+	line := formatSocialData("GME", ctx)
+	for _, want := range []string{"Social (rank #1): 1200 mentions", "+340% vs previous window", "sentiment +0.62 (very bullish)"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %q in %q", want, line)
+		}
+	}
+	if line := formatSocialData("TSLA", ctx); strings.Contains(line, "previous window") || !strings.Contains(line, "(bearish)") {
+		t.Errorf("unexpected annotation without mention change: %q", line)
+	}
+	if formatSocialData("AAPL", ctx) != "" {
+		t.Error("expected empty annotation for symbol without social data")
+	}
+}
@@ -0,0 +1,46 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"fmt"
+)
+
+// ============================================================================
+// Spot Account Rules
+// ============================================================================
+// On spot exchanges (market.IsSpotExchange) a position is a held balance of
+// the base asset: there is no short selling and no leverage. Short actions
+// are converted to "wait" instead of being dropped, so the AI's view stays in
+// the record; opens are forced to 1x and capped at equity in validateDecision.
+// The same rules apply per symbol to crypto pairs on Alpaca, whose stocks
+// keep margin and short selling (market.IsSpotSymbol).
+
+// applySpotRules adapts a decision to a spot account, true if it was converted to wait
+func applySpotRules(d *Decision) bool {
+	switch d.Action {
+	case "open_short", "close_short":
+		logger.Infof("⚠️  [Spot] %s %s not possible on a spot account, converted to wait", d.Symbol, d.Action)
+		d.Reasoning = fmt.Sprintf("[spot account: %s converted to wait] %s", d.Action, d.Reasoning)
+		d.Action = "wait"
+		return true
+	case "open_long":
+		d.Leverage = 1
+	}
+	return false
+}
+
+// hasCryptoSymbols whether any position or candidate of the cycle is a crypto pair
+func hasCryptoSymbols(ctx *Context) bool {
+	for _, pos := range ctx.Positions {
+		if market.IsCrypto(pos.Symbol) {
+			return true
+		}
+	}
+	for _, c := range ctx.CandidateStocks {
+		if market.IsCrypto(c.Symbol) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package decision
+
+import "testing"
+
+func TestValidateDecisionSpot(t *testing.T) {
+	limits := PositionLimits{Exchange: "binance-spot"}
+
+	// Shorts become wait and keep the AI's reasoning
+	short := Decision{Symbol: "ETHUSDT", Action: "open_short", Leverage: 3, PositionSizeUSD: 200, StopLoss: 3300, TakeProfit: 2700, Reasoning: "breakdown"}
+	if err := validateDecision(&short, 1000, 10, 5, 5, 1, limits); err != nil {
+		t.Fatalf("short on spot should be converted, got %v", err)
+	}
+	if short.Action != "wait" {
+		t.Errorf("action = %s, want wait", short.Action)
+	}
+
+	// Longs run at 1x and are capped at equity
+	long := Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 10, PositionSizeUSD: 4000, StopLoss: 95000, TakeProfit: 112000}
+	if err := validateDecision(&long, 1000, 10, 5, 5, 1, limits); err != nil {
+		t.Fatalf("long on spot rejected: %v", err)
+	}
+	if long.Leverage != 1 {
+		t.Errorf("leverage = %d, want 1", long.Leverage)
+	}
+	if long.PositionSizeUSD != 1000 {
+		t.Errorf("position size = %.2f, want capped at equity 1000", long.PositionSizeUSD)
+	}
+
+	// Futures accounts are unaffected
+	futuresShort := Decision{Symbol: "ETHUSDT", Action: "open_short", Leverage: 3, PositionSizeUSD: 200, StopLoss: 3300, TakeProfit: 2700}
+	if err := validateDecision(&futuresShort, 1000, 10, 5, 5, 1, PositionLimits{Exchange: "binance"}); err == nil && futuresShort.Action != "open_short" {
+		t.Errorf("futures short changed to %s", futuresShort.Action)
+	}
+}
+
+func TestValidateDecisionAlpacaCryptoSpot(t *testing.T) {
+	limits := PositionLimits{Exchange: "alpaca-paper"}
+
+	// Crypto pairs on Alpaca are spot: shorts become wait
+	cryptoShort := Decision{Symbol: "BTCUSDT", Action: "open_short", Leverage: 2, PositionSizeUSD: 200, StopLoss: 110000, TakeProfit: 95000}
+	if err := validateDecision(&cryptoShort, 1000, 10, 5, 5, 1, limits); err != nil {
+		t.Fatalf("crypto short on Alpaca should be converted, got %v", err)
+	}
+	if cryptoShort.Action != "wait" {
+		t.Errorf("crypto short action = %s, want wait", cryptoShort.Action)
+	}
+
+	// Stocks on the same account keep short selling
+	stockShort := Decision{Symbol: "TSLA", Action: "open_short", Leverage: 2, PositionSizeUSD: 200, StopLoss: 260, TakeProfit: 230}
+	validateDecision(&stockShort, 1000, 10, 5, 5, 1, limits)
+	if stockShort.Action != "open_short" {
+		t.Errorf("stock short changed to %s", stockShort.Action)
+	}
+}
@@ -0,0 +1,76 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"math"
+)
+
+// ============================================================================
+// Stop Distance Sanity Check
+// ============================================================================
+// Stop loss distance from current price is expressed in multiples of the
+// primary timeframe ATR(14). Stops closer than StopATRMinMultiple (noise
+// stop-outs) or farther than StopATRMaxMultiple (oversized risk) are moved to
+// the nearest bound, like the leverage fallback, instead of failing the
+// decision.
+
+// StopReference current price and ATR of a symbol used to measure stop distance
+type StopReference struct {
+	Price    float64
+	ATR      float64
+	DailyATR float64 // Volatility-targeted leverage (see vol_target.go)
+}
+
+// buildStopReferences collects price and primary timeframe ATR for every symbol in market data
+func buildStopReferences(ctx *Context, primaryTimeframe string) map[string]StopReference {
+	refs := make(map[string]StopReference, len(ctx.MarketDataMap))
+	for symbol, data := range ctx.MarketDataMap {
+		if data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		atr := 0.0
+		if series := data.TimeframeData[primaryTimeframe]; series != nil {
+			atr = series.ATR14
+		}
+		if atr <= 0 && data.IntradaySeries != nil {
+			atr = data.IntradaySeries.ATR14
+		}
+		if atr > 0 {
+			refs[symbol] = StopReference{Price: data.CurrentPrice, ATR: atr, DailyATR: dailyATR(symbol, data, primaryTimeframe, atr)}
+		}
+	}
+	return refs
+}
+
+// adjustStopToATR moves an opening decision's stop loss into [min, max] ATR multiples from current price
+// No-op when the symbol has no price/ATR reference or both bounds are disabled.
+func adjustStopToATR(d *Decision, limits PositionLimits) {
+	ref, ok := limits.StopRefs[d.Symbol]
+	minMultiple := limits.Risk.StopATRMinMultiple
+	maxMultiple := limits.Risk.StopATRMaxMultiple
+	if !ok || ref.ATR <= 0 || ref.Price <= 0 || (minMultiple <= 0 && maxMultiple <= 0) {
+		return
+	}
+
+	// Signed distance: positive when the stop is on the protective side of price
+	direction := 1.0
+	if d.Action == "open_short" {
+		direction = -1.0
+	}
+	multiple := (ref.Price - d.StopLoss) * direction / ref.ATR
+
+	target := multiple
+	if minMultiple > 0 && multiple < minMultiple {
+		target = minMultiple
+	} else if maxMultiple > 0 && multiple > maxMultiple {
+		target = maxMultiple
+	}
+	if target == multiple {
+		return
+	}
+
+	original := d.StopLoss
+	d.StopLoss = math.Round((ref.Price-direction*target*ref.ATR)*1e4) / 1e4
+	logger.Infof("⚠️  [Stop Distance Fallback] %s %s stop %.4f is %.2f ATR from price %.4f (allowed %.2f-%.2f), auto-adjusting to %.4f",
+		d.Symbol, d.Action, original, multiple, ref.Price, minMultiple, maxMultiple, d.StopLoss)
+}
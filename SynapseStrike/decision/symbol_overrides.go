@@ -0,0 +1,47 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Per-Symbol Sizing Overrides
+// ============================================================================
+// RiskControl.SymbolOverrides replace the global max position size, max
+// leverage and min confidence for individual symbols. validateDecision and
+// the trader's open checks resolve them through the RiskControlConfig
+// helpers (MaxLeverageFor, MaxPositionSizeUSDFor, MinConfidenceFor); this
+// file lists them among the prompt's hard constraints so the AI sizes those
+// symbols right instead of relying on the auto-adjustment.
+
+// formatSymbolOverrides one line per overridden symbol, sorted by symbol (empty if none)
+func formatSymbolOverrides(risk store.RiskControlConfig) string {
+	symbols := make([]string, 0, len(risk.SymbolOverrides))
+	for symbol := range risk.SymbolOverrides {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var sb strings.Builder
+	for _, symbol := range symbols {
+		override := risk.SymbolOverrides[symbol]
+		var parts []string
+		if override.MaxPositionUSD > 0 {
+			parts = append(parts, fmt.Sprintf("max position %.0f USD", override.MaxPositionUSD))
+		}
+		if override.MaxLeverage > 0 {
+			parts = append(parts, fmt.Sprintf("max leverage %dx", override.MaxLeverage))
+		}
+		if override.MinConfidence > 0 {
+			parts = append(parts, fmt.Sprintf("confidence ≥ %d to open", override.MinConfidence))
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", strings.ToUpper(strings.TrimSpace(symbol)), strings.Join(parts, ", ")))
+	}
+	return sb.String()
+}
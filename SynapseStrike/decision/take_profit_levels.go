@@ -0,0 +1,84 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ============================================================================
+// Scaled Take Profit (take_profit_levels)
+// ============================================================================
+// An open decision may plan scaled exits, e.g. 50% at +1R and the rest at
+// +3R: [{"price": 101, "pct_of_position": 50}, {"price": 103, "pct_of_position": 50}].
+// Validation sorts the levels from nearest to farthest and makes them cover
+// the whole position: a remainder below 100% is closed at take_profit when
+// that lies beyond the last level, otherwise it is added to the last level.
+// take_profit then always equals the final level, so risk/reward checks,
+// safekeeping and update_stops keep working on the final target. The trader
+// places the final level as the regular take profit and every earlier level
+// as a reduce-only order for its share (see trader/take_profit_levels.go).
+
+// maxTakeProfitLevels levels accepted from the AI (including the final target)
+const maxTakeProfitLevels = 4
+
+// TakeProfitLevel one scaled exit: close pct_of_position percent of the opened quantity at price
+type TakeProfitLevel struct {
+	Price         float64 `json:"price"`
+	PctOfPosition float64 `json:"pct_of_position"` // Percent of the opened quantity (0-100]
+}
+
+// normalizeTakeProfitLevels validates and completes the scaled exits of an open decision (no-op without levels)
+func normalizeTakeProfitLevels(d *Decision) error {
+	if len(d.TakeProfitLevels) == 0 {
+		return nil
+	}
+	if len(d.TakeProfitLevels) > maxTakeProfitLevels {
+		return fmt.Errorf("take_profit_levels has %d levels (max %d)", len(d.TakeProfitLevels), maxTakeProfitLevels)
+	}
+	long := d.Action == "open_long"
+	levels := append([]TakeProfitLevel(nil), d.TakeProfitLevels...)
+	sort.Slice(levels, func(i, j int) bool {
+		if long {
+			return levels[i].Price < levels[j].Price
+		}
+		return levels[i].Price > levels[j].Price
+	})
+
+	total := 0.0
+	for i, level := range levels {
+		if level.Price <= 0 {
+			return fmt.Errorf("take profit level price must be greater than 0: %.4f", level.Price)
+		}
+		if level.PctOfPosition <= 0 || level.PctOfPosition > 100 {
+			return fmt.Errorf("take profit level pct_of_position must be in (0, 100]: %.2f", level.PctOfPosition)
+		}
+		if i > 0 && level.Price == levels[i-1].Price {
+			return fmt.Errorf("duplicate take profit level price %.4f", level.Price)
+		}
+		total += level.PctOfPosition
+	}
+	if total > 100.01 {
+		return fmt.Errorf("take profit levels close %.2f%% of the position (max 100%%)", total)
+	}
+	if d.StopLoss > 0 && ((long && levels[0].Price <= d.StopLoss) || (!long && levels[0].Price >= d.StopLoss)) {
+		return fmt.Errorf("take profit level %.4f is not beyond stop loss %.4f", levels[0].Price, d.StopLoss)
+	}
+
+	// The remainder rides to take_profit if that lies beyond the last level, else the last level closes it
+	last := levels[len(levels)-1].Price
+	if remainder := 100 - total; remainder > 0.01 {
+		beyond := (long && d.TakeProfit > last) || (!long && d.TakeProfit > 0 && d.TakeProfit < last)
+		if beyond {
+			levels = append(levels, TakeProfitLevel{Price: d.TakeProfit, PctOfPosition: remainder})
+		} else {
+			levels[len(levels)-1].PctOfPosition += remainder
+		}
+	}
+	for i := range levels {
+		levels[i].PctOfPosition = math.Round(levels[i].PctOfPosition*100) / 100
+	}
+	d.TakeProfitLevels = levels
+	d.TakeProfit = levels[len(levels)-1].Price
+	return nil
+}
@@ -0,0 +1,68 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTakeProfitLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		d          Decision
+		wantLevels []TakeProfitLevel
+		wantTP     float64
+		wantErr    string
+	}{
+		{
+			name:       "remainder rides to take_profit beyond the last level",
+			d:          Decision{Action: "open_long", StopLoss: 95, TakeProfit: 115, TakeProfitLevels: []TakeProfitLevel{{110, 25}, {105, 50}}},
+			wantLevels: []TakeProfitLevel{{105, 50}, {110, 25}, {115, 25}},
+			wantTP:     115,
+		},
+		{
+			name:       "remainder added to the last level, take_profit follows it",
+			d:          Decision{Action: "open_short", StopLoss: 105, TakeProfit: 97, TakeProfitLevels: []TakeProfitLevel{{95, 30}, {90, 40}}},
+			wantLevels: []TakeProfitLevel{{95, 30}, {90, 70}},
+			wantTP:     90,
+		},
+		{
+			name:       "missing take_profit is the final level",
+			d:          Decision{Action: "open_long", StopLoss: 95, TakeProfitLevels: []TakeProfitLevel{{101, 50}, {103, 50}}},
+			wantLevels: []TakeProfitLevel{{101, 50}, {103, 50}},
+			wantTP:     103,
+		},
+		{
+			name:    "more than 100%",
+			d:       Decision{Action: "open_long", StopLoss: 95, TakeProfitLevels: []TakeProfitLevel{{101, 60}, {103, 50}}},
+			wantErr: "max 100%",
+		},
+		{
+			name:    "level on the stop side",
+			d:       Decision{Action: "open_long", StopLoss: 95, TakeProfitLevels: []TakeProfitLevel{{94, 50}, {103, 50}}},
+			wantErr: "not beyond stop loss",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.d
+			err := normalizeTakeProfitLevels(&d)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.TakeProfit != tt.wantTP || len(d.TakeProfitLevels) != len(tt.wantLevels) {
+				t.Fatalf("take profit %.2f levels %+v, want %.2f %+v", d.TakeProfit, d.TakeProfitLevels, tt.wantTP, tt.wantLevels)
+			}
+			for i, want := range tt.wantLevels {
+				if d.TakeProfitLevels[i] != want {
+					t.Errorf("level %d = %+v, want %+v", i, d.TakeProfitLevels[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/mcp"
+	"SynapseStrike/provider"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Tool-Calling Decision Mode
+// ============================================================================
+// When ToolCalling is enabled, the AI may request extra data through the tools
+// below before emitting its decision (see mcp.RunToolLoop). Each AI call has
+// its own budget of ToolCalling.MaxCalls tool executions.
+
+const (
+	ToolGetKlines = "get_klines"
+	ToolGetNews   = "get_news"
+
+	defaultToolKlineCount = 20
+	maxToolKlineCount     = 100
+	defaultToolNewsLimit  = 5
+	maxToolNewsLimit      = 10
+)
+
+// Data sources used by tools (replaced in tests)
+var (
+	toolFetchKlines = func(symbol, timeframe string, count int) (*market.Data, error) {
+		if market.IsStock(symbol) {
+			return market.GetStockDataWithTimeframes(symbol, []string{timeframe}, timeframe, count)
+		}
+		return market.GetWithTimeframes(symbol, []string{timeframe}, timeframe, count)
+	}
+	toolFetchNews = func(symbol string, limit int) ([]provider.StockNewsItem, error) {
+		news, err := provider.GetStockNews([]string{symbol}, limit)
+		if err != nil {
+			return nil, err
+		}
+		return news.News, nil
+	}
+)
+
+// toolCallingBudget tool call budget per AI call (0 = tool-calling disabled)
+func (e *StrategyEngine) toolCallingBudget() int {
+	cfg := e.config.ToolCalling
+	if !cfg.Enabled {
+		return 0
+	}
+	if cfg.MaxCalls <= 0 {
+		return mcp.DefaultToolCallBudget
+	}
+	return cfg.MaxCalls
+}
+
+// buildToolRegistry registers decision tools allowed by config (nil if tool-calling is disabled)
+func (e *StrategyEngine) buildToolRegistry() *mcp.ToolRegistry {
+	if e.toolCallingBudget() == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool)
+	for _, name := range e.config.ToolCalling.Tools {
+		allowed[strings.TrimSpace(name)] = true
+	}
+	enabled := func(name string) bool {
+		return len(allowed) == 0 || allowed[name]
+	}
+
+	registry := mcp.NewToolRegistry()
+	if enabled(ToolGetKlines) {
+		registry.Register(ToolGetKlines,
+			"OHLCV K-lines with EMA20, RSI14 and ATR14 for a symbol and timeframe (1m, 5m, 15m, 1h, 4h, 1d).",
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol":    map[string]any{"type": "string"},
+					"timeframe": map[string]any{"type": "string"},
+					"count":     map[string]any{"type": "integer", "description": fmt.Sprintf("bars, default %d, max %d", defaultToolKlineCount, maxToolKlineCount)},
+				},
+				"required": []string{"symbol", "timeframe"},
+			},
+			toolGetKlines)
+	}
+	if enabled(ToolGetNews) {
+		registry.Register(ToolGetNews,
+			"Recent news headlines with keyword sentiment for a stock symbol.",
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol": map[string]any{"type": "string"},
+					"limit":  map[string]any{"type": "integer", "description": fmt.Sprintf("articles, default %d, max %d", defaultToolNewsLimit, maxToolNewsLimit)},
+				},
+				"required": []string{"symbol"},
+			},
+			toolGetNews)
+	}
+	if registry.Len() == 0 {
+		return nil
+	}
+	return registry
+}
+
+// toolGetKlines get_klines tool handler
+func toolGetKlines(args map[string]any) (string, error) {
+	symbol := strings.ToUpper(toolStringArg(args, "symbol"))
+	if symbol == "" {
+		return "", fmt.Errorf("symbol is required")
+	}
+	timeframe, err := market.NormalizeTimeframe(toolStringArg(args, "timeframe"))
+	if err != nil {
+		return "", err
+	}
+	count := toolIntArg(args, "count", defaultToolKlineCount, maxToolKlineCount)
+
+	data, err := toolFetchKlines(symbol, timeframe, count)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s %s K-lines: %w", symbol, timeframe, err)
+	}
+	series := data.TimeframeData[timeframe]
+	if series == nil || len(series.Klines) == 0 {
+		return "", fmt.Errorf("no %s K-lines for %s", timeframe, symbol)
+	}
+
+	klines := series.Klines
+	if len(klines) > count {
+		klines = klines[len(klines)-count:]
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s, last %d bars (time UTC, open, high, low, close, volume):\n", symbol, timeframe, len(klines)))
+	for _, k := range klines {
+		sb.WriteString(fmt.Sprintf("%s %.4f %.4f %.4f %.4f %.0f\n",
+			time.UnixMilli(k.Time).UTC().Format("01-02 15:04"), k.Open, k.High, k.Low, k.Close, k.Volume))
+	}
+	if n := len(series.EMA20Values); n > 0 {
+		sb.WriteString(fmt.Sprintf("EMA20: %.4f", series.EMA20Values[n-1]))
+	}
+	if n := len(series.RSI14Values); n > 0 {
+		sb.WriteString(fmt.Sprintf(" | RSI14: %.1f", series.RSI14Values[n-1]))
+	}
+	if series.ATR14 > 0 {
+		sb.WriteString(fmt.Sprintf(" | ATR14: %.4f", series.ATR14))
+	}
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// toolGetNews get_news tool handler
+func toolGetNews(args map[string]any) (string, error) {
+	symbol := strings.ToUpper(toolStringArg(args, "symbol"))
+	if symbol == "" {
+		return "", fmt.Errorf("symbol is required")
+	}
+	limit := toolIntArg(args, "limit", defaultToolNewsLimit, maxToolNewsLimit)
+
+	news, err := toolFetchNews(symbol, limit)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s news: %w", symbol, err)
+	}
+	if len(news) == 0 {
+		return fmt.Sprintf("No recent news for %s\n", symbol), nil
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s recent news (%d):\n", symbol, len(news)))
+	for _, n := range news {
+		sb.WriteString(fmt.Sprintf("- [%s] %s (%s, %s)\n", n.CreatedAt, n.Headline, n.Source, n.Sentiment))
+	}
+	return sb.String(), nil
+}
+
+func toolStringArg(args map[string]any, key string) string {
+	v, _ := args[key].(string)
+	return strings.TrimSpace(v)
+}
+
+// toolIntArg integer argument clamped to [1, max] (def when missing)
+func toolIntArg(args map[string]any, key string, def, max int) int {
+	v, ok := args[key].(float64)
+	if !ok || v < 1 {
+		return def
+	}
+	if int(v) > max {
+		return max
+	}
+	return int(v)
+}
+
+// formatToolCalls tool call summary appended to the chain of thought
+func formatToolCalls(calls []mcp.ToolCallRecord) string {
+	if len(calls) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n\n### 🔧 Tool calls (%d)\n", len(calls)))
+	for _, c := range calls {
+		status := fmt.Sprintf("ok, %d chars", len(c.Result))
+		if c.Error != "" {
+			status = "error: " + c.Error
+		}
+		sb.WriteString(fmt.Sprintf("- %s %v → %s\n", c.Call.Name, c.Call.Arguments, status))
+	}
+	return sb.String()
+}
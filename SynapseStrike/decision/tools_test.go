@@ -0,0 +1,48 @@
+package decision
+
+import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+)
+
+func TestBuildToolRegistry(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	if NewStrategyEngine(&cfg).buildToolRegistry() != nil {
+		t.Error("expected no registry when tool-calling is disabled")
+	}
+
+	cfg.ToolCalling.Enabled = true
+	cfg.ToolCalling.Tools = []string{ToolGetKlines}
+	registry := NewStrategyEngine(&cfg).buildToolRegistry()
+	if registry == nil || registry.Len() != 1 {
+		t.Fatalf("expected only get_klines registered, got %+v", registry)
+	}
+}
+
+func TestToolGetKlines(t *testing.T) {
+	original := toolFetchKlines
+	defer func() { toolFetchKlines = original }()
+	toolFetchKlines = func(symbol, timeframe string, count int) (*market.Data, error) {
+		series := &market.TimeframeSeriesData{Timeframe: timeframe, ATR14: 1.25, RSI14Values: []float64{55}}
+		for i := 0; i < 30; i++ {
+			series.Klines = append(series.Klines, market.KlineBar{Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 1000})
+		}
+		return &market.Data{Symbol: symbol, TimeframeData: map[string]*market.TimeframeSeriesData{timeframe: series}}, nil
+	}
+
+	out, err := toolGetKlines(map[string]any{"symbol": "aapl", "timeframe": "15M", "count": 5.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"AAPL 15m, last 5 bars", "RSI14: 55.0", "ATR14: 1.2500"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if _, err := toolGetKlines(map[string]any{"symbol": "AAPL", "timeframe": "7m"}); err == nil {
+		t.Error("expected unsupported timeframe error")
+	}
+}
@@ -0,0 +1,150 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Trade Frequency Governor
+// ============================================================================
+// The system prompt asks the AI not to overtrade; with TradeGovernor.Enabled
+// the trader enforces it. Every executed open is logged, and an open that
+// would exceed MaxOpensPerHour / MaxOpensPerDay (all symbols) or
+// MaxOpensPerSymbolPerHour / MaxOpensPerSymbolPerDay is rejected before an
+// order is placed. The user prompt shows the budget used so far and, in the
+// cycle after a rejection, "you exceeded your trade budget" with the reason.
+// Windows are rolling (last 60 minutes, last 24 hours).
+
+const (
+	tradeBudgetHour = time.Hour
+	tradeBudgetDay  = 24 * time.Hour
+)
+
+// TradeOpen one executed open counted against the trade budget
+type TradeOpen struct {
+	Symbol string    `json:"symbol"`
+	At     time.Time `json:"at"`
+}
+
+// TradeBudget trade budget usage shown in the user prompt (filled by the trader)
+type TradeBudget struct {
+	OpensLastHour  int      `json:"opens_last_hour"`
+	OpensLastDay   int      `json:"opens_last_day"`
+	MaxPerHour     int      `json:"max_per_hour"` // 0 = unlimited
+	MaxPerDay      int      `json:"max_per_day"`  // 0 = unlimited
+	SymbolsAtLimit []string `json:"symbols_at_limit,omitempty"`
+	Rejected       []string `json:"rejected,omitempty"` // Opens rejected since the last cycle, with reasons
+}
+
+// CheckTradeBudget reports whether another open on symbol fits the budget, with the reason when it does not
+func CheckTradeBudget(cfg store.TradeGovernorConfig, opens []TradeOpen, symbol string, now time.Time) (bool, string) {
+	if !cfg.Enabled {
+		return true, ""
+	}
+	hour, day, symbolHour, symbolDay := countTradeOpens(opens, symbol, now)
+	switch {
+	case cfg.MaxOpensPerHour > 0 && hour >= cfg.MaxOpensPerHour:
+		return false, fmt.Sprintf("%d opens in the last hour (max %d)", hour, cfg.MaxOpensPerHour)
+	case cfg.MaxOpensPerDay > 0 && day >= cfg.MaxOpensPerDay:
+		return false, fmt.Sprintf("%d opens in the last 24h (max %d)", day, cfg.MaxOpensPerDay)
+	case cfg.MaxOpensPerSymbolPerHour > 0 && symbolHour >= cfg.MaxOpensPerSymbolPerHour:
+		return false, fmt.Sprintf("%d %s opens in the last hour (max %d per symbol)", symbolHour, symbol, cfg.MaxOpensPerSymbolPerHour)
+	case cfg.MaxOpensPerSymbolPerDay > 0 && symbolDay >= cfg.MaxOpensPerSymbolPerDay:
+		return false, fmt.Sprintf("%d %s opens in the last 24h (max %d per symbol)", symbolDay, symbol, cfg.MaxOpensPerSymbolPerDay)
+	}
+	return true, ""
+}
+
+// BuildTradeBudget summarizes budget usage for the prompt (nil when the governor is disabled)
+func BuildTradeBudget(cfg store.TradeGovernorConfig, opens []TradeOpen, now time.Time) *TradeBudget {
+	if !cfg.Enabled {
+		return nil
+	}
+	budget := &TradeBudget{MaxPerHour: cfg.MaxOpensPerHour, MaxPerDay: cfg.MaxOpensPerDay}
+	budget.OpensLastHour, budget.OpensLastDay, _, _ = countTradeOpens(opens, "", now)
+
+	seen := make(map[string]bool)
+	for _, open := range opens {
+		if seen[open.Symbol] {
+			continue
+		}
+		seen[open.Symbol] = true
+		_, _, symbolHour, symbolDay := countTradeOpens(opens, open.Symbol, now)
+		switch {
+		case cfg.MaxOpensPerSymbolPerHour > 0 && symbolHour >= cfg.MaxOpensPerSymbolPerHour:
+			budget.SymbolsAtLimit = append(budget.SymbolsAtLimit, fmt.Sprintf("%s (%d/%d this hour)", open.Symbol, symbolHour, cfg.MaxOpensPerSymbolPerHour))
+		case cfg.MaxOpensPerSymbolPerDay > 0 && symbolDay >= cfg.MaxOpensPerSymbolPerDay:
+			budget.SymbolsAtLimit = append(budget.SymbolsAtLimit, fmt.Sprintf("%s (%d/%d today)", open.Symbol, symbolDay, cfg.MaxOpensPerSymbolPerDay))
+		}
+	}
+	sort.Strings(budget.SymbolsAtLimit)
+	return budget
+}
+
+// PruneTradeOpens drops opens older than the longest budget window
+func PruneTradeOpens(opens []TradeOpen, now time.Time) []TradeOpen {
+	kept := opens[:0]
+	for _, open := range opens {
+		if now.Sub(open.At) < tradeBudgetDay {
+			kept = append(kept, open)
+		}
+	}
+	return kept
+}
+
+// countTradeOpens opens within the hour and day windows, overall and for symbol
+func countTradeOpens(opens []TradeOpen, symbol string, now time.Time) (hour, day, symbolHour, symbolDay int) {
+	for _, open := range opens {
+		age := now.Sub(open.At)
+		if age >= tradeBudgetDay {
+			continue
+		}
+		inHour := age < tradeBudgetHour
+		day++
+		if inHour {
+			hour++
+		}
+		if symbol != "" && open.Symbol == symbol {
+			symbolDay++
+			if inHour {
+				symbolHour++
+			}
+		}
+	}
+	return hour, day, symbolHour, symbolDay
+}
+
+// formatTradeBudget trade budget section of the user prompt (empty when the governor is disabled)
+func formatTradeBudget(b *TradeBudget) string {
+	if b == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Trade Budget (enforced)\n")
+	sb.WriteString(fmt.Sprintf("Opens: %s in the last hour, %s in the last 24h\n",
+		formatBudgetUsage(b.OpensLastHour, b.MaxPerHour), formatBudgetUsage(b.OpensLastDay, b.MaxPerDay)))
+	if len(b.SymbolsAtLimit) > 0 {
+		sb.WriteString("Symbols at their limit (opens rejected): " + strings.Join(b.SymbolsAtLimit, ", ") + "\n")
+	}
+	if len(b.Rejected) > 0 {
+		sb.WriteString("⚠️ You exceeded your trade budget last cycle, these opens were rejected:\n")
+		for _, reason := range b.Rejected {
+			sb.WriteString("- " + reason + "\n")
+		}
+		sb.WriteString("Only open when the setup is clearly better than waiting for budget to free up.\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatBudgetUsage "used/max" or just "used" when unlimited
+func formatBudgetUsage(used, max int) string {
+	if max <= 0 {
+		return fmt.Sprintf("%d", used)
+	}
+	return fmt.Sprintf("%d/%d", used, max)
+}
@@ -0,0 +1,61 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckTradeBudget(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cfg := store.TradeGovernorConfig{Enabled: true, MaxOpensPerHour: 3, MaxOpensPerDay: 5, MaxOpensPerSymbolPerHour: 1, MaxOpensPerSymbolPerDay: 2}
+	opens := []TradeOpen{
+		{Symbol: "NVDA", At: now.Add(-20 * time.Minute)},
+		{Symbol: "TSLA", At: now.Add(-3 * time.Hour)},
+		{Symbol: "TSLA", At: now.Add(-5 * time.Hour)},
+		{Symbol: "AAPL", At: now.Add(-25 * time.Hour)}, // Outside both windows
+	}
+
+	tests := []struct {
+		symbol string
+		ok     bool
+		reason string
+	}{
+		{"AAPL", true, ""},
+		{"NVDA", false, "1 NVDA opens in the last hour (max 1 per symbol)"},
+		{"TSLA", false, "2 TSLA opens in the last 24h (max 2 per symbol)"},
+	}
+	for _, tt := range tests {
+		ok, reason := CheckTradeBudget(cfg, opens, tt.symbol, now)
+		if ok != tt.ok || reason != tt.reason {
+			t.Errorf("CheckTradeBudget(%s) = %v %q, want %v %q", tt.symbol, ok, reason, tt.ok, tt.reason)
+		}
+	}
+
+	cfg.MaxOpensPerDay = 3
+	if ok, reason := CheckTradeBudget(cfg, opens, "AAPL", now); ok || reason != "3 opens in the last 24h (max 3)" {
+		t.Errorf("trader-wide daily cap not enforced: %v %q", ok, reason)
+	}
+	cfg.Enabled = false
+	if ok, _ := CheckTradeBudget(cfg, opens, "NVDA", now); !ok {
+		t.Error("disabled governor should allow every open")
+	}
+}
+
+func TestFormatTradeBudget(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cfg := store.TradeGovernorConfig{Enabled: true, MaxOpensPerHour: 4, MaxOpensPerSymbolPerHour: 1}
+	budget := BuildTradeBudget(cfg, []TradeOpen{{Symbol: "NVDA", At: now.Add(-10 * time.Minute)}}, now)
+	budget.Rejected = []string{"open_long NVDA: 1 NVDA opens in the last hour (max 1 per symbol)"}
+
+	out := formatTradeBudget(budget)
+	for _, want := range []string{"Opens: 1/4 in the last hour, 1 in the last 24h", "NVDA (1/1 this hour)", "You exceeded your trade budget", "- open_long NVDA"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("prompt section missing %q:\n%s", want, out)
+		}
+	}
+	if BuildTradeBudget(store.TradeGovernorConfig{}, nil, now) != nil || formatTradeBudget(nil) != "" {
+		t.Error("disabled governor should add no prompt section")
+	}
+}
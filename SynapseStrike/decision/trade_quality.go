@@ -0,0 +1,112 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Trade Quality (MFE / MAE)
+// ============================================================================
+// Win rate and PnL say whether trades worked, not how well they were timed.
+// The position monitor records every position's maximum favorable and
+// adverse excursion (see store/trade_excursion.go); the user prompt shows the
+// aggregates next to the overall stats so the AI can calibrate: stops tighter
+// than the heat winners usually take cut winners, a low exit efficiency means
+// targets or exits give back most of the move, and losers that were well in
+// profit first point at missing break-even management. The last 7 days and
+// last 30 trades are shown as separate, labeled windows so a recent change in
+// performance is not hidden by the all-time numbers.
+
+// minTrackedTradesForQuality tracked trades needed before quality metrics are shown
+const minTrackedTradesForQuality = 3
+
+// Recent performance windows shown next to the all-time stats
+const (
+	RecentStatsDays   = 7  // Trades closed in the last 7 days
+	RecentStatsTrades = 30 // Last 30 closed trades
+)
+
+// NewTradingStats trading stats for the AI from the position store's stats (excursions may be nil)
+func NewTradingStats(full *store.TraderStats, excursions *store.ExcursionStats) *TradingStats {
+	if full == nil {
+		return nil
+	}
+	stats := &TradingStats{
+		TotalTrades:    full.TotalTrades,
+		WinRate:        full.WinRate,
+		ProfitFactor:   full.ProfitFactor,
+		SharpeRatio:    full.SharpeRatio,
+		TotalPnL:       full.NetPnL,
+		AvgWin:         full.AvgWin,
+		AvgLoss:        full.AvgLoss,
+		MaxDrawdownPct: full.MaxDrawdownPct,
+	}
+	if excursions != nil {
+		stats.TrackedTrades = excursions.TrackedTrades
+		stats.AvgMAEWinnersPct = excursions.AvgMAEWinnersPct
+		stats.MaxMAEWinnersPct = excursions.MaxMAEWinnersPct
+		stats.AvgMFELosersPct = excursions.AvgMFELosersPct
+		stats.ExitEfficiencyPct = excursions.ExitEfficiencyPct
+		stats.StopEfficiencyPct = excursions.StopEfficiencyPct
+		stats.AvgEntryQuality = excursions.AvgEntryQuality
+	}
+	return stats
+}
+
+// formatTradingStats performance and trade quality section of the user prompt (empty without closed trades)
+func formatTradingStats(s *TradingStats) string {
+	if s == nil || s.TotalTrades == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Performance & Trade Quality\n")
+	sb.WriteString(fmt.Sprintf("Closed trades %d | Win rate %.1f%% | Profit factor %.2f | Net PnL %+.2f | Avg win %.2f | Avg loss %.2f | Max drawdown %.1f%%\n",
+		s.TotalTrades, s.WinRate, s.ProfitFactor, s.TotalPnL, s.AvgWin, s.AvgLoss, s.MaxDrawdownPct))
+	if s.LastDays != nil || s.LastTrades != nil {
+		sb.WriteString(formatWindowStats(fmt.Sprintf("Last %d days", RecentStatsDays), s.LastDays))
+		sb.WriteString(formatWindowStats(fmt.Sprintf("Last %d trades", RecentStatsTrades), s.LastTrades))
+		sb.WriteString("Recent windows reflect current conditions better than all-time stats: when they trail the overall numbers, trade less often and size down; when they lead, keep the current approach without escalating risk.\n")
+	}
+
+	if s.TrackedTrades >= minTrackedTradesForQuality {
+		sb.WriteString(fmt.Sprintf("Entry quality %.0f/100 over %d trades (MFE share of the total excursion, 100 = never went against the entry)\n",
+			s.AvgEntryQuality, s.TrackedTrades))
+		if s.AvgMAEWinnersPct > 0 {
+			sb.WriteString(fmt.Sprintf("- Winners took %.2f%% adverse excursion on average before working (max %.2f%%): stops tighter than this cut winners\n",
+				s.AvgMAEWinnersPct, s.MaxMAEWinnersPct))
+		}
+		if s.ExitEfficiencyPct > 0 {
+			sb.WriteString(fmt.Sprintf("- Exit efficiency %.0f%%: winners captured this share of their best unrealized move\n", s.ExitEfficiencyPct))
+		}
+		if s.StopEfficiencyPct > 0 {
+			sb.WriteString(fmt.Sprintf("- Stop efficiency %.0f%%: losers were closed at this share of their worst unrealized move (100 = cut at the worst point)\n", s.StopEfficiencyPct))
+		}
+		if s.AvgMFELosersPct > 0 {
+			sb.WriteString(fmt.Sprintf("- Losers were up %.2f%% on average before turning: consider moving stops to break-even earlier\n", s.AvgMFELosersPct))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatWindowStats one labeled recent performance line (empty if the window was not loaded)
+func formatWindowStats(label string, w *store.WindowStats) string {
+	if w == nil {
+		return ""
+	}
+	if w.Trades == 0 {
+		return fmt.Sprintf("%s: no closed trades\n", label)
+	}
+	profitFactor := fmt.Sprintf("%.2f", w.ProfitFactor)
+	if w.ProfitFactor == 0 && w.WinRate > 0 {
+		profitFactor = "n/a (no losses)"
+	}
+	line := fmt.Sprintf("%s: %d trades | Win rate %.1f%% | Profit factor %s | Net PnL %+.2f",
+		label, w.Trades, w.WinRate, profitFactor, w.NetPnL)
+	if w.RTrades > 0 {
+		line += fmt.Sprintf(" | Avg R %+.2f (%d trades with a stop)", w.AvgR, w.RTrades)
+	}
+	return line + "\n"
+}
@@ -0,0 +1,71 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"strings"
+	"testing"
+)
+
+func TestFormatTradingStats(t *testing.T) {
+	if got := formatTradingStats(nil); got != "" {
+		t.Errorf("expected empty section without stats, got %q", got)
+	}
+	if got := formatTradingStats(&TradingStats{}); got != "" {
+		t.Errorf("expected empty section without closed trades, got %q", got)
+	}
+
+	full := &store.TraderStats{TotalTrades: 12, WinRate: 50, ProfitFactor: 1.4, NetPnL: 85.5, AvgWin: 30, AvgLoss: 18}
+	excursions := &store.ExcursionStats{
+		TrackedTrades:     10,
+		AvgMAEWinnersPct:  0.8,
+		MaxMAEWinnersPct:  2.1,
+		AvgMFELosersPct:   0.5,
+		ExitEfficiencyPct: 45,
+		StopEfficiencyPct: 90,
+		AvgEntryQuality:   62,
+	}
+	section := formatTradingStats(NewTradingStats(full, excursions))
+	for _, want := range []string{
+		"## Performance & Trade Quality",
+		"Closed trades 12 | Win rate 50.0%",
+		"Net PnL +85.50",
+		"Entry quality 62/100 over 10 trades",
+		"Winners took 0.80% adverse excursion on average before working (max 2.10%)",
+		"Exit efficiency 45%",
+		"Stop efficiency 90%",
+		"Losers were up 0.50% on average",
+	} {
+		if !strings.Contains(section, want) {
+			t.Errorf("section missing %q:\n%s", want, section)
+		}
+	}
+
+	// Too few tracked trades: overall stats only
+	excursions.TrackedTrades = minTrackedTradesForQuality - 1
+	section = formatTradingStats(NewTradingStats(full, excursions))
+	if strings.Contains(section, "Entry quality") {
+		t.Errorf("quality metrics shown for %d tracked trades:\n%s", excursions.TrackedTrades, section)
+	}
+}
+
+func TestFormatTradingStatsWindows(t *testing.T) {
+	stats := NewTradingStats(&store.TraderStats{TotalTrades: 40, WinRate: 55, ProfitFactor: 1.6}, nil)
+	stats.LastDays = &store.WindowStats{}
+	stats.LastTrades = &store.WindowStats{Trades: 30, WinRate: 40, ProfitFactor: 0.8, NetPnL: -52.5, AvgR: -0.25, RTrades: 28}
+
+	section := formatTradingStats(stats)
+	for _, want := range []string{
+		"Last 7 days: no closed trades",
+		"Last 30 trades: 30 trades | Win rate 40.0% | Profit factor 0.80 | Net PnL -52.50 | Avg R -0.25 (28 trades with a stop)",
+		"trade less often and size down",
+	} {
+		if !strings.Contains(section, want) {
+			t.Errorf("section missing %q:\n%s", want, section)
+		}
+	}
+
+	stats.LastDays = &store.WindowStats{Trades: 3, WinRate: 100, NetPnL: 12}
+	if section = formatTradingStats(stats); !strings.Contains(section, "Profit factor n/a (no losses)") {
+		t.Errorf("profit factor without losses not marked:\n%s", section)
+	}
+}
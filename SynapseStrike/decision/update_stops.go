@@ -0,0 +1,90 @@
+package decision
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Stop Updates on Open Positions
+// ============================================================================
+// "update_stops" lets the AI move the stop loss and/or take profit of a
+// position it already holds (e.g. stop to breakeven after +2R, tighter trail
+// into a target). Only the non-zero prices are changed. The new levels must
+// be on the correct side of the current price — a stop above the price of a
+// long would trigger immediately — and the stop must stay inside the
+// liquidation price. The trader replaces the exchange SL/TP orders.
+
+// validateUpdateStops checks new SL/TP levels against the held position and current price
+// The position side is stored in metadata (MetaPositionSide) for execution.
+func validateUpdateStops(d *Decision, limits PositionLimits) error {
+	if d.StopLoss < 0 || d.TakeProfit < 0 {
+		return fmt.Errorf("stop loss and take profit cannot be negative")
+	}
+	if d.StopLoss == 0 && d.TakeProfit == 0 {
+		return fmt.Errorf("update_stops requires stop_loss and/or take_profit")
+	}
+
+	pos, err := findStopsPosition(d, limits.Positions)
+	if err != nil {
+		return err
+	}
+	price := pos.MarkPrice
+	if ref, ok := limits.StopRefs[d.Symbol]; ok && ref.Price > 0 {
+		price = ref.Price
+	}
+	if price <= 0 {
+		return fmt.Errorf("no current price for %s, cannot validate stop update", d.Symbol)
+	}
+
+	if pos.Side == "long" {
+		if d.StopLoss > 0 && d.StopLoss >= price {
+			return fmt.Errorf("for long positions, new stop loss %.4f must be below current price %.4f", d.StopLoss, price)
+		}
+		if d.TakeProfit > 0 && d.TakeProfit <= price {
+			return fmt.Errorf("for long positions, new take profit %.4f must be above current price %.4f", d.TakeProfit, price)
+		}
+		if d.StopLoss > 0 && pos.LiquidationPrice > 0 && d.StopLoss <= pos.LiquidationPrice {
+			return fmt.Errorf("new stop loss %.4f is beyond liquidation price %.4f", d.StopLoss, pos.LiquidationPrice)
+		}
+	} else {
+		if d.StopLoss > 0 && d.StopLoss <= price {
+			return fmt.Errorf("for short positions, new stop loss %.4f must be above current price %.4f", d.StopLoss, price)
+		}
+		if d.TakeProfit > 0 && d.TakeProfit >= price {
+			return fmt.Errorf("for short positions, new take profit %.4f must be below current price %.4f", d.TakeProfit, price)
+		}
+		if d.StopLoss > 0 && pos.LiquidationPrice > 0 && d.StopLoss >= pos.LiquidationPrice {
+			return fmt.Errorf("new stop loss %.4f is beyond liquidation price %.4f", d.StopLoss, pos.LiquidationPrice)
+		}
+	}
+
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]interface{})
+	}
+	d.Metadata[MetaPositionSide] = pos.Side
+	return nil
+}
+
+// findStopsPosition held position targeted by an update_stops decision
+// With both sides held (hedge mode) the side must be given in metadata.
+func findStopsPosition(d *Decision, positions []PositionInfo) (*PositionInfo, error) {
+	side, _ := d.MetaString(MetaPositionSide)
+	side = strings.ToLower(strings.TrimSpace(side))
+
+	var matches []*PositionInfo
+	for i := range positions {
+		pos := &positions[i]
+		if pos.Symbol == d.Symbol && (side == "" || pos.Side == side) {
+			matches = append(matches, pos)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no open %s position to update stops", d.Symbol)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("both long and short %s positions are open, set metadata %s", d.Symbol, MetaPositionSide)
+	}
+}
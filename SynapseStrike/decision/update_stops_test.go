@@ -0,0 +1,54 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateUpdateStops tests SL/TP updates against the held position and current price
+func TestValidateUpdateStops(t *testing.T) {
+	limits := PositionLimits{Positions: []PositionInfo{
+		{Symbol: "NVDA", Side: "long", EntryPrice: 100, MarkPrice: 110, LiquidationPrice: 60},
+		{Symbol: "TSLA", Side: "short", EntryPrice: 200, MarkPrice: 190},
+		{Symbol: "META", Side: "long", MarkPrice: 500},
+		{Symbol: "META", Side: "short", MarkPrice: 500},
+	}}
+
+	tests := []struct {
+		name     string
+		decision Decision
+		wantErr  string
+		wantSide string
+	}{
+		{"long stop to breakeven", Decision{Symbol: "NVDA", StopLoss: 100}, "", "long"},
+		{"long take profit only", Decision{Symbol: "NVDA", TakeProfit: 130}, "", "long"},
+		{"long stop above price", Decision{Symbol: "NVDA", StopLoss: 111}, "must be below current price", ""},
+		{"long take profit below price", Decision{Symbol: "NVDA", StopLoss: 100, TakeProfit: 105}, "must be above current price", ""},
+		{"long stop beyond liquidation", Decision{Symbol: "NVDA", StopLoss: 55}, "beyond liquidation price", ""},
+		{"short stop lowered", Decision{Symbol: "TSLA", StopLoss: 200, TakeProfit: 170}, "", "short"},
+		{"short stop below price", Decision{Symbol: "TSLA", StopLoss: 185}, "must be above current price", ""},
+		{"no levels", Decision{Symbol: "NVDA"}, "requires stop_loss and/or take_profit", ""},
+		{"no position", Decision{Symbol: "AAPL", StopLoss: 150}, "no open AAPL position", ""},
+		{"both sides held", Decision{Symbol: "META", StopLoss: 480}, "both long and short", ""},
+		{"both sides held with side", Decision{Symbol: "META", StopLoss: 520, Metadata: map[string]interface{}{MetaPositionSide: "short"}}, "", "short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.decision
+			d.Action = "update_stops"
+			err := validateDecision(&d, 1000, 10, 5, 5, 1, limits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if side, _ := d.MetaString(MetaPositionSide); side != tt.wantSide {
+				t.Errorf("position side = %q, want %q", side, tt.wantSide)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package decision
 
 import (
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -84,7 +88,7 @@ func TestLeverageFallback(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Use default position value ratios for testing (10x for BTC/ETH, 1.5x for altcoins)
-			err := validateDecision(&tt.decision, tt.accountEquity, tt.btcEthLeverage, tt.altcoinLeverage, 10.0, 1.5)
+			err := validateDecision(&tt.decision, tt.accountEquity, tt.btcEthLeverage, tt.altcoinLeverage, 10.0, 1.5, PositionLimits{})
 
 			// Check error status
 			if (err != nil) != tt.wantError {
@@ -100,7 +104,6 @@ func TestLeverageFallback(t *testing.T) {
 	}
 }
 
-
 // contains checks if string contains substring (helper function)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -115,3 +118,265 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+// TestPositionLimits_MinPositionSize tests configurable Large Cap list and exchange minimums
+func TestPositionLimits_MinPositionSize(t *testing.T) {
+	limits := PositionLimits{
+		Exchange: "hyperliquid",
+		Risk: store.RiskControlConfig{
+			MinPositionSize:         8,
+			LargeCapMinPositionSize: 50,
+			LargeCapSymbols:         []string{"AMD"},
+		},
+	}
+	if !limits.IsLargeCap("AMD") || limits.IsLargeCap("AAPL") {
+		t.Errorf("expected configured Large Cap list to replace defaults")
+	}
+	if got := limits.MinPositionSize("AMD"); got != 50 {
+		t.Errorf("Large Cap min = %v, want 50", got)
+	}
+	if got := limits.MinPositionSize("ETHUSDT"); got != 10 {
+		t.Errorf("expected hyperliquid minimum order value (10) to raise configured min, got %v", got)
+	}
+
+	d := Decision{Symbol: "AMD", Action: "open_long", Leverage: 1, PositionSizeUSD: 40, StopLoss: 90, TakeProfit: 150}
+	if err := validateDecision(&d, 1000, 5, 5, 5, 1, limits); err == nil {
+		t.Error("expected Large Cap position below minimum to fail validation")
+	}
+}
+
+// TestStopDistanceFallback tests stop loss adjustment to configured ATR multiple bounds
+func TestStopDistanceFallback(t *testing.T) {
+	limits := PositionLimits{
+		Risk:     store.RiskControlConfig{StopATRMinMultiple: 0.5, StopATRMaxMultiple: 5},
+		StopRefs: map[string]StopReference{"AAPL": {Price: 100, ATR: 2}},
+	}
+	decisions := []Decision{
+		{Symbol: "AAPL", Action: "open_long", Leverage: 1, PositionSizeUSD: 100, StopLoss: 99.9, TakeProfit: 150},
+		{Symbol: "AAPL", Action: "open_short", Leverage: 1, PositionSizeUSD: 100, StopLoss: 140, TakeProfit: 50},
+		{Symbol: "AAPL", Action: "open_long", Leverage: 1, PositionSizeUSD: 100, StopLoss: 95, TakeProfit: 150},
+		{Symbol: "MSFT", Action: "open_long", Leverage: 1, PositionSizeUSD: 100, StopLoss: 99.9, TakeProfit: 150},
+	}
+	if valid, report := validateDecisions(decisions, 1000, 5, 5, 5, 1, limits); len(valid) != len(decisions) {
+		t.Fatalf("unexpected dropped decisions: %s", report.DroppedSummary())
+	}
+
+	want := []float64{99, 110, 95, 99.9}
+	for i, w := range want {
+		if decisions[i].StopLoss != w {
+			t.Errorf("decision #%d stop loss = %v, want %v", i+1, decisions[i].StopLoss, w)
+		}
+	}
+}
+
+// TestVolTargetLeverage tests AI leverage override by target / daily ATR%, capped by max leverage
+func TestVolTargetLeverage(t *testing.T) {
+	refs := map[string]StopReference{
+		"AAPL":    {Price: 100, ATR: 0.5, DailyATR: 2},     // 2%/day → 1% target = 0.5x, at least 1x
+		"BTCUSDT": {Price: 100, ATR: 0.1, DailyATR: 0.25},  // 0.25%/day → 4x
+		"ETHUSDT": {Price: 100, ATR: 0.01, DailyATR: 0.05}, // 0.05%/day → 20x, capped at 10x
+	}
+	tests := []struct {
+		symbol string
+		risk   store.RiskControlConfig
+		want   int
+	}{
+		{"AAPL", store.RiskControlConfig{VolTargetEnabled: true}, 1},
+		{"BTCUSDT", store.RiskControlConfig{VolTargetEnabled: true}, 4},
+		{"BTCUSDT", store.RiskControlConfig{VolTargetEnabled: true, VolTargetDailyRiskPct: 2}, 8},
+		{"ETHUSDT", store.RiskControlConfig{VolTargetEnabled: true}, 10},
+		{"BTCUSDT", store.RiskControlConfig{}, 3},
+		{"SOLUSDT", store.RiskControlConfig{VolTargetEnabled: true}, 3},
+	}
+	for _, tt := range tests {
+		d := Decision{Symbol: tt.symbol, Action: "open_long", Leverage: 3, PositionSizeUSD: 100, StopLoss: 99, TakeProfit: 200}
+		if err := validateDecision(&d, 1000, 10, 10, 5, 1, PositionLimits{Risk: tt.risk, StopRefs: refs}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.symbol, err)
+		}
+		if d.Leverage != tt.want {
+			t.Errorf("%s (target %.1f%%) leverage = %dx, want %dx", tt.symbol, tt.risk.VolTargetDailyRiskPct, d.Leverage, tt.want)
+		}
+	}
+
+	// 15m crypto ATR scales by sqrt(96 bars per day)
+	if got := dailyATR("BTCUSDT", &market.Data{}, "15m", 1); math.Abs(got-math.Sqrt(96)) > 1e-9 {
+		t.Errorf("dailyATR = %v, want %v", got, math.Sqrt(96))
+	}
+}
+
+// TestLiquidationGuard tests that leveraged crypto opens with stops beyond or near liquidation are dropped
+func TestLiquidationGuard(t *testing.T) {
+	refs := map[string]StopReference{"SOLUSDT": {Price: 100, ATR: 5}, "AAPL": {Price: 100, ATR: 5}}
+	disabled := false
+	tests := []struct {
+		name      string
+		decision  Decision
+		risk      store.RiskControlConfig
+		wantError bool
+	}{
+		// 10x: long liquidation ≈ 90.5, short ≈ 109.5, default buffer 1% of entry
+		{"long stop before liquidation", Decision{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 95, TakeProfit: 200}, store.RiskControlConfig{}, false},
+		{"long stop within buffer", Decision{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 91, TakeProfit: 200}, store.RiskControlConfig{}, true},
+		{"short stop beyond liquidation", Decision{Symbol: "SOLUSDT", Action: "open_short", StopLoss: 112, TakeProfit: 20}, store.RiskControlConfig{}, true},
+		{"short stop before liquidation", Decision{Symbol: "SOLUSDT", Action: "open_short", StopLoss: 105, TakeProfit: 20}, store.RiskControlConfig{}, false},
+		{"guard disabled", Decision{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 91, TakeProfit: 200}, store.RiskControlConfig{LiquidationGuardEnabled: &disabled}, false},
+		{"stocks not checked", Decision{Symbol: "AAPL", Action: "open_long", StopLoss: 91, TakeProfit: 200}, store.RiskControlConfig{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.decision
+			d.Leverage, d.PositionSizeUSD = 10, 100
+			err := validateDecision(&d, 1000, 10, 10, 5, 1, PositionLimits{Risk: tt.risk, StopRefs: refs})
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestValidateDecisions_KeepsValidEntries tests invalid decisions are dropped without discarding the rest of the batch
+func TestValidateDecisions_KeepsValidEntries(t *testing.T) {
+	decisions := []Decision{
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 20, PositionSizeUSD: 100, StopLoss: 50, TakeProfit: 200},
+		{Symbol: "ETHUSDT", Action: "buy_everything"},
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 100, StopLoss: 200, TakeProfit: 50},
+		{Symbol: "BNBUSDT", Action: "hold"},
+	}
+
+	valid, report := validateDecisions(decisions, 100, 10, 5, 5, 1, PositionLimits{})
+	if len(valid) != 2 || valid[0].Symbol != "SOLUSDT" || valid[1].Symbol != "BNBUSDT" {
+		t.Fatalf("valid decisions = %+v, want SOLUSDT and BNBUSDT", valid)
+	}
+	if valid[0].Leverage != 5 {
+		t.Errorf("leverage = %d, want repaired to 5", valid[0].Leverage)
+	}
+	if report.Total != 4 || report.Valid != 2 || report.Dropped != 2 {
+		t.Errorf("report totals = %d/%d/%d, want 4/2/2", report.Total, report.Valid, report.Dropped)
+	}
+
+	var dropped, repaired []int
+	for _, issue := range report.Issues {
+		if issue.Dropped {
+			dropped = append(dropped, issue.Index)
+		} else {
+			repaired = append(repaired, issue.Index)
+		}
+	}
+	if len(dropped) != 2 || dropped[0] != 2 || dropped[1] != 3 {
+		t.Errorf("dropped indexes = %v, want [2 3]", dropped)
+	}
+	if len(repaired) != 1 || repaired[0] != 1 {
+		t.Errorf("repaired indexes = %v, want [1]", repaired)
+	}
+}
+
+// TestValidateDecision_EntryPrice tests limit entry bracketing and risk/reward measured from entry_price
+func TestValidateDecision_EntryPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		stopLoss   float64
+		takeProfit float64
+		entryPrice float64
+		wantError  bool
+	}{
+		{"long pullback entry", "open_long", 50, 200, 80, false},
+		{"long entry too close to target", "open_long", 50, 200, 150, true},
+		{"long entry below stop", "open_long", 50, 200, 40, true},
+		{"short rally entry", "open_short", 200, 50, 170, false},
+		{"short entry above stop", "open_short", 200, 50, 210, true},
+		{"negative entry", "open_long", 50, 200, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decision{Symbol: "SOLUSDT", Action: tt.action, Leverage: 5, PositionSizeUSD: 100,
+				StopLoss: tt.stopLoss, TakeProfit: tt.takeProfit, EntryPrice: tt.entryPrice}
+			err := validateDecision(&d, 100, 10, 5, 5, 1, PositionLimits{})
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestSymbolOverrides tests per-symbol leverage, size and confidence limits replacing the global ones
+func TestSymbolOverrides(t *testing.T) {
+	risk := store.RiskControlConfig{
+		MaxPositionSizeUSD: 500,
+		SymbolOverrides: map[string]store.SymbolOverride{
+			"btcusdt":  {MaxPositionUSD: 4000, MaxLeverage: 20},
+			"PEPEUSDT": {MaxPositionUSD: 50, MaxLeverage: 2, MinConfidence: 85},
+		},
+	}
+	tests := []struct {
+		name         string
+		decision     Decision
+		wantLeverage int
+		wantSize     float64
+		wantError    bool
+	}{
+		{"aggressive override above global cap", Decision{Symbol: "BTCUSDT", Leverage: 15, PositionSizeUSD: 3000, Confidence: 70}, 15, 3000, false},
+		{"override leverage caps", Decision{Symbol: "BTCUSDT", Leverage: 25, PositionSizeUSD: 6000, Confidence: 70}, 20, 4000, false},
+		{"tiny override", Decision{Symbol: "PEPEUSDT", Leverage: 5, PositionSizeUSD: 300, Confidence: 90}, 2, 50, false},
+		{"below symbol confidence", Decision{Symbol: "PEPEUSDT", Leverage: 2, PositionSizeUSD: 50, Confidence: 80}, 0, 0, true},
+		{"global cap without override", Decision{Symbol: "SOLUSDT", Leverage: 5, PositionSizeUSD: 900, Confidence: 50}, 5, 500, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.decision
+			d.Action, d.StopLoss, d.TakeProfit = "open_long", 50, 200
+			err := validateDecision(&d, 10000, 10, 5, 5, 1, PositionLimits{Risk: risk})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err == nil && (d.Leverage != tt.wantLeverage || d.PositionSizeUSD != tt.wantSize) {
+				t.Errorf("got %dx / %.0f USD, want %dx / %.0f USD", d.Leverage, d.PositionSizeUSD, tt.wantLeverage, tt.wantSize)
+			}
+		})
+	}
+
+	lines := formatSymbolOverrides(risk)
+	if !strings.Contains(lines, "  - BTCUSDT: max position 4000 USD, max leverage 20x\n") ||
+		!strings.Contains(lines, "PEPEUSDT: max position 50 USD, max leverage 2x, confidence ≥ 85 to open") {
+		t.Errorf("unexpected override listing:\n%s", lines)
+	}
+}
+
+// TestRiskPerTrade tests that opens risking more at the stop than risk_usd or the configured caps are resized
+func TestRiskPerTrade(t *testing.T) {
+	refs := map[string]StopReference{"PLTR": {Price: 100, ATR: 2}}
+	tests := []struct {
+		name      string
+		riskUSD   float64
+		entry     float64
+		risk      store.RiskControlConfig
+		wantSize  float64
+		wantRisk  float64
+		wantError bool
+	}{
+		// 200 USD with the stop 5% away risks 10 USD
+		{"no limits", 0, 0, store.RiskControlConfig{}, 200, 10, false},
+		{"stated risk above actual", 20, 0, store.RiskControlConfig{}, 200, 10, false},
+		{"stated risk below actual", 5, 0, store.RiskControlConfig{}, 100, 5, false},
+		{"usd cap below stated risk", 5, 0, store.RiskControlConfig{MaxRiskPerTradeUSD: 4}, 80, 4, false},
+		{"equity pct cap", 0, 0, store.RiskControlConfig{MaxRiskPerTradePct: 0.3}, 60, 3, false},
+		{"limit entry measures from entry price", 3, 98, store.RiskControlConfig{}, 98, 3, false},
+		{"resized below minimum size", 0, 0, store.RiskControlConfig{MaxRiskPerTradeUSD: 0.5}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decision{Symbol: "PLTR", Action: "open_long", Leverage: 1, PositionSizeUSD: 200,
+				StopLoss: 95, TakeProfit: 130, EntryPrice: tt.entry, RiskUSD: tt.riskUSD}
+			err := validateDecision(&d, 1000, 10, 10, 5, 1, PositionLimits{Risk: tt.risk, StopRefs: refs})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if math.Abs(d.PositionSizeUSD-tt.wantSize) > 1e-6 || math.Abs(d.RiskUSD-tt.wantRisk) > 1e-6 {
+				t.Errorf("size/risk = %.2f/%.2f, want %.2f/%.2f", d.PositionSizeUSD, d.RiskUSD, tt.wantSize, tt.wantRisk)
+			}
+		})
+	}
+}
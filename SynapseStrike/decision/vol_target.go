@@ -0,0 +1,66 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"math"
+)
+
+// ============================================================================
+// Volatility-Targeted Leverage
+// ============================================================================
+// With RiskControl.VolTargetEnabled the leverage of an opening decision is
+// not taken from the AI but computed as VolTargetDailyRiskPct / daily ATR%,
+// so every position moves its margin by about the target per day: calm
+// symbols get more leverage, volatile ones less. Daily ATR comes from the 1d
+// series when it is fetched, otherwise the primary timeframe ATR is scaled by
+// the square root of its bars per trading day (390 minutes for stocks, 24h
+// for crypto). The max leverage fallback still caps the result.
+
+const stockSessionMinutes = 390
+
+// dailyATR daily ATR of a symbol: 1d series ATR(14), else primary timeframe ATR scaled to one trading day
+func dailyATR(symbol string, data *market.Data, primaryTimeframe string, primaryATR float64) float64 {
+	if series := data.TimeframeData["1d"]; series != nil && series.ATR14 > 0 {
+		return series.ATR14
+	}
+	tfMinutes := market.TimeframeMinutes(primaryTimeframe)
+	if tfMinutes <= 0 || primaryATR <= 0 {
+		return 0
+	}
+	sessionMinutes := 24 * 60
+	if market.IsStock(symbol) {
+		sessionMinutes = stockSessionMinutes
+	}
+	if tfMinutes >= sessionMinutes {
+		return primaryATR
+	}
+	return primaryATR * math.Sqrt(float64(sessionMinutes)/float64(tfMinutes))
+}
+
+// volTargetLeverage leverage whose daily volatility (dailyATRPct × leverage) is closest to targetPct, at least 1x
+func volTargetLeverage(dailyATRPct, targetPct float64) int {
+	if dailyATRPct <= 0 {
+		return 0
+	}
+	return max(1, int(math.Round(targetPct/dailyATRPct)))
+}
+
+// applyVolTarget overrides an opening decision's leverage with the volatility-targeted leverage
+// No-op when disabled or the symbol has no daily ATR reference (AI leverage is kept).
+func applyVolTarget(d *Decision, limits PositionLimits) {
+	enabled, targetPct := limits.Risk.VolTarget()
+	ref, ok := limits.StopRefs[d.Symbol]
+	if !enabled || !ok || ref.DailyATR <= 0 || ref.Price <= 0 {
+		return
+	}
+
+	atrPct := ref.DailyATR / ref.Price * 100
+	leverage := volTargetLeverage(atrPct, targetPct)
+	if leverage == d.Leverage {
+		return
+	}
+	logger.Infof("⚠️  [Vol Target] %s daily ATR %.2f%%, target %.2f%%/day: leverage %dx → %dx",
+		d.Symbol, atrPct, targetPct, d.Leverage, leverage)
+	d.Leverage = leverage
+}
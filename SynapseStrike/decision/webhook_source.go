@@ -0,0 +1,184 @@
+package decision
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Webhook Screener Candidate Source
+// ============================================================================
+// "webhook" lets an external screener push its own candidate list: it POSTs a
+// signed payload to /api/webhooks/candidates/:strategy_id, the latest list is
+// kept per strategy for WebhookTTLMinutes, and GetCandidateStocks serves it
+// ranked by score. Scores and notes are annotated per candidate in the prompt,
+// in the same spirit as OI Top and funding data.
+
+const (
+	defaultWebhookLimit      = 20
+	defaultWebhookTTLMinutes = 60
+	maxWebhookCandidates     = 500 // Upper bound of a pushed list
+)
+
+// WebhookCandidate candidate pushed by an external screener
+type WebhookCandidate struct {
+	Symbol string  `json:"symbol"`
+	Score  float64 `json:"score,omitempty"` // Screener score (higher = stronger, ranks the list)
+	Note   string  `json:"note,omitempty"`  // Screener reasoning shown to the AI
+}
+
+// UnmarshalJSON accepts both {"symbol": ...} objects and plain symbol strings
+func (c *WebhookCandidate) UnmarshalJSON(data []byte) error {
+	var symbol string
+	if err := json.Unmarshal(data, &symbol); err == nil {
+		*c = WebhookCandidate{Symbol: symbol}
+		return nil
+	}
+	type plain WebhookCandidate
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*c = WebhookCandidate(p)
+	return nil
+}
+
+// webhookFeed latest list received for a strategy
+type webhookFeed struct {
+	candidates []WebhookCandidate
+	receivedAt time.Time
+}
+
+var (
+	webhookFeedsMu sync.RWMutex
+	webhookFeeds   = make(map[string]webhookFeed)
+)
+
+// SetWebhookCandidates replaces the candidate list of a strategy, returns number of candidates kept
+// Symbols are normalized and deduplicated (first occurrence wins), the list is ranked by score.
+func SetWebhookCandidates(strategyID string, candidates []WebhookCandidate) (int, error) {
+	if strategyID == "" {
+		return 0, fmt.Errorf("strategy ID is required")
+	}
+	if len(candidates) > maxWebhookCandidates {
+		return 0, fmt.Errorf("too many symbols: %d (max %d)", len(candidates), maxWebhookCandidates)
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	kept := make([]WebhookCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		symbol := strings.TrimSpace(c.Symbol)
+		if symbol == "" {
+			continue
+		}
+		symbol = market.Normalize(symbol)
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		c.Symbol = symbol
+		c.Note = strings.TrimSpace(c.Note)
+		kept = append(kept, c)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Score > kept[j].Score })
+
+	webhookFeedsMu.Lock()
+	webhookFeeds[strategyID] = webhookFeed{candidates: kept, receivedAt: time.Now()}
+	webhookFeedsMu.Unlock()
+	return len(kept), nil
+}
+
+// getWebhookFeed gets the latest list of a strategy if it is younger than ttl
+func getWebhookFeed(strategyID string, ttl time.Duration) ([]WebhookCandidate, error) {
+	webhookFeedsMu.RLock()
+	feed, ok := webhookFeeds[strategyID]
+	webhookFeedsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no candidate list received from webhook yet")
+	}
+	if age := time.Since(feed.receivedAt); age > ttl {
+		return nil, fmt.Errorf("webhook candidate list expired (received %s ago, TTL %s)", age.Round(time.Second), ttl)
+	}
+	return feed.candidates, nil
+}
+
+// usesWebhookSource whether the configured candidate source includes the webhook screener
+func (e *StrategyEngine) usesWebhookSource() bool {
+	source := e.config.CoinSource
+	return source.SourceType == "webhook" || (source.SourceType == "mixed" && source.UseWebhook)
+}
+
+// webhookCandidates latest unexpired list pushed for this engine's strategy
+func (e *StrategyEngine) webhookCandidates() ([]WebhookCandidate, error) {
+	if e.strategyID == "" {
+		return nil, fmt.Errorf("strategy ID not set, webhook candidates unavailable")
+	}
+	ttlMinutes := e.config.CoinSource.WebhookTTLMinutes
+	if ttlMinutes <= 0 {
+		ttlMinutes = defaultWebhookTTLMinutes
+	}
+	return getWebhookFeed(e.strategyID, time.Duration(ttlMinutes)*time.Minute)
+}
+
+func (e *StrategyEngine) getWebhookStocks(limit int) ([]CandidateStock, error) {
+	if limit <= 0 {
+		limit = defaultWebhookLimit
+	}
+
+	list, err := e.webhookCandidates()
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	candidates := make([]CandidateStock, 0, len(list))
+	for _, c := range list {
+		candidates = append(candidates, CandidateStock{
+			Symbol:  c.Symbol,
+			Sources: []string{"webhook"},
+		})
+	}
+	return candidates, nil
+}
+
+// ComputeWebhookData fills ctx.WebhookDataMap from the pushed list (no-op unless webhook source is used)
+func (e *StrategyEngine) ComputeWebhookData(ctx *Context) {
+	if ctx == nil || ctx.WebhookDataMap != nil || !e.usesWebhookSource() {
+		return
+	}
+	list, err := e.webhookCandidates()
+	if err != nil {
+		logger.Infof("⚠️  Failed to get webhook screener data: %v", err)
+		return
+	}
+	ctx.WebhookDataMap = make(map[string]*WebhookCandidate, len(list))
+	for i := range list {
+		c := list[i]
+		ctx.WebhookDataMap[c.Symbol] = &c
+	}
+}
+
+// formatWebhookData screener annotation line of a candidate (empty if none)
+func formatWebhookData(symbol string, ctx *Context) string {
+	data, ok := ctx.WebhookDataMap[symbol]
+	if !ok || data == nil || (data.Score == 0 && data.Note == "") {
+		return ""
+	}
+
+	var parts []string
+	if data.Score != 0 {
+		parts = append(parts, fmt.Sprintf("score %.2f", data.Score))
+	}
+	if data.Note != "" {
+		parts = append(parts, data.Note)
+	}
+	return fmt.Sprintf("Screener: %s\n\n", strings.Join(parts, " | "))
+}
@@ -0,0 +1,49 @@
+package decision
+
+import (
+	"SynapseStrike/store"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWebhookCandidatesRankedAndLimited(t *testing.T) {
+	var payload struct {
+		Symbols []WebhookCandidate `json:"symbols"`
+	}
+	body := `{"symbols": ["solusdt", {"symbol": "ETHUSDT", "score": 90, "note": "breakout"}, {"symbol": "BTCUSDT", "score": 70}, "SOLUSDT"]}`
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	count, err := SetWebhookCandidates("strategy-webhook-test", payload.Symbols)
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 deduplicated candidates, got %d (%v)", count, err)
+	}
+
+	engine := NewStrategyEngine(&store.StrategyConfig{CoinSource: store.CoinSourceConfig{SourceType: "webhook"}})
+	engine.SetStrategyID("strategy-webhook-test")
+
+	candidates, err := engine.getWebhookStocks(2)
+	if err != nil {
+		t.Fatalf("getWebhookStocks: %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].Symbol != "ETHUSDT" || candidates[1].Symbol != "BTCUSDT" {
+		t.Errorf("expected [ETHUSDT BTCUSDT] ranked by score, got %+v", candidates)
+	}
+
+	ctx := &Context{}
+	engine.ComputeWebhookData(ctx)
+	if line := formatWebhookData("ETHUSDT", ctx); !strings.Contains(line, "score 90.00 | breakout") {
+		t.Errorf("unexpected annotation: %q", line)
+	}
+	if formatWebhookData("SOLUSDT", ctx) != "" {
+		t.Error("expected empty annotation for candidate without score or note")
+	}
+
+	other := NewStrategyEngine(&store.StrategyConfig{CoinSource: store.CoinSourceConfig{SourceType: "webhook"}})
+	other.SetStrategyID("strategy-without-feed")
+	if _, err := other.getWebhookStocks(0); err == nil {
+		t.Error("expected error for strategy without a pushed list")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"SynapseStrike/api"
 	"SynapseStrike/auth"
 	"SynapseStrike/backtest"
+	"SynapseStrike/cache"
 	"SynapseStrike/config"
 	"SynapseStrike/crypto"
 	"SynapseStrike/logger"
 	"SynapseStrike/manager"
 	"SynapseStrike/market"
 	"SynapseStrike/mcp"
+	"SynapseStrike/notify"
 	"SynapseStrike/store"
 	"SynapseStrike/trader"
 	"os"
@@ -37,6 +39,12 @@ func main() {
 	cfg := config.Get()
 	logger.Info("✅ Configuration loaded")
 
+	// Initialize notification channels (Telegram/Discord/webhook)
+	if notifier := notify.NewFromConfig(cfg); notifier != nil {
+		notify.SetGlobal(notifier)
+		logger.Infof("🔔 Notifications enabled: %v", notifier.Channels())
+	}
+
 	// Initialize database
 	// Default path is data/data.db to work with Docker volume mount (/app/data)
 	dbPath := "data/data.db"
@@ -90,12 +98,68 @@ func main() {
 		return decrypted
 	}
 	st.SetCryptoFuncs(encryptFunc, decryptFunc)
+	// Encrypt credentials still stored in plain text (written before encryption was enabled)
+	encrypted, err := st.ReencryptCredentials(func(value string) (string, error) {
+		if cryptoService.IsEncryptedStorageValue(value) {
+			return value, nil
+		}
+		return cryptoService.EncryptForStorage(value)
+	})
+	if err != nil {
+		logger.Warnf("⚠️ Failed to encrypt plain-text credentials: %v", err)
+	} else if encrypted > 0 {
+		logger.Infof("🔐 Encrypted %d plain-text credentials at rest", encrypted)
+	}
 	logger.Info("✅ Encryption service initialized successfully")
 
 	// Set JWT secret
 	auth.SetJWTSecret(cfg.JWTSecret)
 	logger.Info("🔑 JWT secret configured")
 
+	// Shared market data cache: Redis when configured (shared across processes), in-memory otherwise
+	if cfg.RedisURL != "" {
+		if err := cache.Init(cfg.RedisURL); err != nil {
+			logger.Warnf("⚠️ Redis cache unavailable, using in-memory cache: %v", err)
+		} else {
+			logger.Info("🧊 Redis cache enabled")
+		}
+	}
+
+	// Local kline archive: fallback for market data when the live API is down
+	if cfg.KlineArchivePath != "" {
+		archive, err := market.OpenKlineArchive(cfg.KlineArchivePath)
+		if err != nil {
+			logger.Warnf("⚠️ Kline archive unavailable: %v", err)
+		} else {
+			defer archive.Close()
+			market.SetKlineArchive(archive)
+			logger.Infof("🗄️ Kline archive enabled: %s", cfg.KlineArchivePath)
+		}
+	}
+
+	// US market holiday calendar updates (years after the built-in table)
+	if n, err := market.LoadTradingCalendar(cfg.MarketCalendarPath); err != nil {
+		logger.Warnf("⚠️ Market calendar not loaded: %v", err)
+	} else if n > 0 {
+		logger.Infof("📅 Loaded %d market holidays/half days from %s", n, cfg.MarketCalendarPath)
+	}
+
+	// Quote currency reference rates (USDC/EUR account balances are reported in USD)
+	quoteRates, err := market.ParseQuoteRates(cfg.QuoteRates)
+	if err != nil {
+		logger.Warnf("⚠️ Ignoring QUOTE_RATES: %v", err)
+	}
+	market.QuoteRates.Configure(quoteRates, time.Duration(cfg.QuoteRateRefreshMinutes)*time.Minute)
+
+	// Exchange maintenance windows (static file + Bybit/OKX status pages)
+	maintenanceWindows, err := market.LoadMaintenanceWindows(cfg.MaintenanceWindowsPath)
+	if err != nil {
+		logger.Warnf("⚠️ Maintenance windows not loaded: %v", err)
+	} else if len(maintenanceWindows) > 0 {
+		logger.Infof("🛠 Loaded %d exchange maintenance windows from %s", len(maintenanceWindows), cfg.MaintenanceWindowsPath)
+	}
+	market.Maintenance.Configure(maintenanceWindows, time.Duration(cfg.MaintenancePollMinutes)*time.Minute)
+
 	// Start WebSocket market monitor FIRST (before loading traders that may need market data)
 	// This ensures WSMonitorCli is initialized before any trader tries to access it
 	go market.NewWSMonitor(150).Start(nil)
@@ -116,6 +180,11 @@ func main() {
 	positionSyncManager.Start()
 	defer positionSyncManager.Stop()
 
+	// Start equity compactor (downsamples equity snapshots older than 7 days)
+	equityCompactor := trader.NewEquityCompactor(st, 0) // 0 = use default 1h interval
+	equityCompactor.Start()
+	defer equityCompactor.Stop()
+
 	// Load all traders from database to memory (may auto-start traders with IsRunning=true)
 	if err := traderManager.LoadTradersFromStore(st); err != nil {
 		logger.Fatalf("❌ Failed to load traders: %v", err)
@@ -161,6 +230,7 @@ func main() {
 
 	// Stop all traders
 	traderManager.StopAll()
+	notify.Global().Wait() // Flush pending notifications
 	logger.Info("✅ System shut down safely")
 }
 
@@ -3,6 +3,8 @@ package manager
 import (
 	"context"
 	"fmt"
+	"SynapseStrike/config"
+	"SynapseStrike/crypto"
 	"SynapseStrike/debate"
 	"SynapseStrike/decision"
 	"SynapseStrike/logger"
@@ -305,9 +307,17 @@ func (tm *TraderManager) getConcurrentTraderData(traders []*trader.AutoTrader) [
 				account, err := trader.GetAccountInfo()
 				if err != nil {
 					errorChan <- err
-				} else {
-					accountChan <- account
+					return
 				}
+				// Worst-case equity impact of a 10% move against every open position (leverage stress)
+				if equity, ok := account["total_equity"].(float64); ok {
+					if report, err := trader.GetStressReportForEquity(equity, nil); err == nil {
+						if worst := report.WorstCase(); worst != nil {
+							account["stress_worst_case_pct"] = worst.EquityImpactPct
+						}
+					}
+				}
+				accountChan <- account
 			}()
 
 			status := trader.GetStatus()
@@ -326,6 +336,7 @@ func (tm *TraderManager) getConcurrentTraderData(traders []*trader.AutoTrader) [
 					"total_pnl_pct":          account["total_pnl_pct"],
 					"position_count":         account["position_count"],
 					"margin_used_pct":        account["margin_used_pct"],
+					"stress_worst_case_pct":  account["stress_worst_case_pct"],
 					"is_running":             status["is_running"],
 					"system_prompt_template": trader.GetSystemPromptTemplate(),
 				}
@@ -409,6 +420,41 @@ func (tm *TraderManager) GetTopTradersData() (map[string]interface{}, error) {
 }
 
 
+// StrategyReloadResult result of pushing strategy config to one running trader
+type StrategyReloadResult struct {
+	TraderID   string `json:"trader_id"`
+	TraderName string `json:"trader_name"`
+	Version    int    `json:"version,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// UpdateStrategyConfig hot-reloads strategy config into all loaded traders using strategyID
+// Running traders pick up the new config at the start of their next cycle.
+func (tm *TraderManager) UpdateStrategyConfig(strategyID string, cfg *store.StrategyConfig) []StrategyReloadResult {
+	tm.mu.RLock()
+	var targets []*trader.AutoTrader
+	for _, t := range tm.traders {
+		if t.GetStrategyID() == strategyID {
+			targets = append(targets, t)
+		}
+	}
+	tm.mu.RUnlock()
+
+	results := make([]StrategyReloadResult, 0, len(targets))
+	for _, t := range targets {
+		result := StrategyReloadResult{TraderID: t.GetID(), TraderName: t.GetName()}
+		version, err := t.UpdateStrategyConfig(cfg)
+		if err != nil {
+			logger.Warnf("⚠️  Failed to reload strategy %s for trader %s: %v", strategyID, t.GetName(), err)
+			result.Error = err.Error()
+		} else {
+			result.Version = version
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 // RemoveTrader removes a trader from memory (does not affect database)
 // Used to force reload when updating trader configuration
 func (tm *TraderManager) RemoveTrader(traderID string) {
@@ -658,12 +704,25 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 	} else {
 		return fmt.Errorf("trader %s has no strategy configured", traderCfg.Name)
 	}
+	// Reject broken configs at load instead of failing inside the first cycle
+	if err := trader.ValidateStrategyConfig(strategyConfig); err != nil {
+		return fmt.Errorf("strategy config of trader %s is invalid: %w", traderCfg.Name, err)
+	}
+
+	// Credentials are decrypted by the store; values still encrypted mean the data key doesn't match
+	if err := exchangeCfg.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil {
+		return fmt.Errorf("trader %s: %w", traderCfg.Name, err)
+	}
+	if err := aiModelCfg.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil {
+		return fmt.Errorf("trader %s: %w", traderCfg.Name, err)
+	}
 
 	// Build AutoTraderConfig (coinPoolURL/oiTopURL obtained from strategy config, used in StrategyEngine)
 	traderConfig := trader.AutoTraderConfig{
 		ID:                    traderCfg.ID,
 		Name:                  traderCfg.Name,
 		AIModel:               aiModelCfg.Provider,
+		AIModelID:             aiModelCfg.ID,
 		Exchange:              exchangeCfg.ExchangeType, // Exchange type: binance/bybit/okx/etc
 		ExchangeID:            exchangeCfg.ID,           // Exchange account UUID (for multi-account)
 		BinanceAPIKey:         "",
@@ -680,17 +739,33 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		IsCrossMargin:        traderCfg.IsCrossMargin,
 		ShowInCompetition:    traderCfg.ShowInCompetition,
 		TradeOnlyMarketHours: traderCfg.TradeOnlyMarketHours,
+		ShadowMode:           traderCfg.ShadowMode,
+		TradingSchedule:      traderCfg.TradingSchedule,
+		SymbolLists:          traderCfg.SymbolLists,
+		StrategyID:           traderCfg.StrategyID,
+		AICycleTimeout:       time.Duration(config.Get().AICycleTimeoutSeconds) * time.Second,
+		ContextHooksDir:      config.Get().ContextHooksDir,
 		StrategyConfig:       strategyConfig,
 	}
+	traderConfig.QuoteCurrency = exchangeCfg.QuoteCurrency
 
 	// Set API keys based on exchange type
 	switch exchangeCfg.ExchangeType {
 	case "binance":
 		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
 		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
+		traderConfig.BinanceTestnet = exchangeCfg.Testnet
+	case "binance-spot":
+		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
+		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
+		traderConfig.BinanceTestnet = exchangeCfg.Testnet
+	case "coinbase":
+		traderConfig.CoinbaseAPIKeyName = exchangeCfg.APIKey
+		traderConfig.CoinbasePrivateKeyPEM = exchangeCfg.SecretKey
 	case "bybit":
 		traderConfig.BybitAPIKey = exchangeCfg.APIKey
 		traderConfig.BybitSecretKey = exchangeCfg.SecretKey
+		traderConfig.BybitTestnet = exchangeCfg.Testnet
 	case "okx":
 		traderConfig.OKXAPIKey = exchangeCfg.APIKey
 		traderConfig.OKXSecretKey = exchangeCfg.SecretKey
@@ -712,6 +787,16 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		traderConfig.LighterAPIKeyPrivateKey = exchangeCfg.LighterAPIKeyPrivateKey
 		traderConfig.LighterAPIKeyIndex = exchangeCfg.LighterAPIKeyIndex
 		traderConfig.LighterTestnet = exchangeCfg.Testnet
+	case "dydx":
+		traderConfig.DydxMnemonic = exchangeCfg.DydxMnemonic
+		traderConfig.DydxSubaccount = exchangeCfg.DydxSubaccount
+		traderConfig.DydxTestnet = exchangeCfg.Testnet
+	case "ccxt":
+		traderConfig.CCXTGatewayURL = exchangeCfg.CCXTGatewayURL
+		traderConfig.CCXTExchangeID = exchangeCfg.CCXTExchangeID
+		traderConfig.CCXTAPIKey = exchangeCfg.APIKey
+		traderConfig.CCXTSecret = exchangeCfg.SecretKey
+		traderConfig.CCXTPassword = exchangeCfg.Passphrase
 	case "alpaca", "alpaca-paper", "alpaca-live":
 		// Alpaca uses standard API key/secret format, reuse Binance fields
 		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
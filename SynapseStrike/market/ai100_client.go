@@ -1,6 +1,7 @@
 package market
 
 import (
+	"SynapseStrike/cache"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,14 +26,14 @@ type AI100Response struct {
 	} `json:"data"`
 }
 
+// ai100SellTriggersKey shared cache key of the sell trigger map
+const ai100SellTriggersKey = "ai100:sell_triggers"
+
 // AI100Client fetches optimized sell_trigger values from AI100 API
 type AI100Client struct {
-	apiURL      string
-	authKey     string
-	cache       map[string]float64
-	cacheMu     sync.RWMutex
-	cacheExpiry time.Time
-	cacheTTL    time.Duration
+	apiURL   string
+	authKey  string
+	cacheTTL time.Duration
 }
 
 // Global AI100 client instance
@@ -47,7 +48,6 @@ func GetAI100Client() *AI100Client {
 		globalAI100Client = &AI100Client{
 			apiURL:   "http://24.12.59.214:8082/api/ai100/list",
 			authKey:  "pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ",
-			cache:    make(map[string]float64),
 			cacheTTL: 5 * time.Minute, // Cache for 5 minutes
 		}
 	})
@@ -57,16 +57,10 @@ func GetAI100Client() *AI100Client {
 // FetchSellTriggers fetches sell_trigger values for all stocks
 // Returns a map of symbol -> sell_trigger percentage
 func (c *AI100Client) FetchSellTriggers() (map[string]float64, error) {
-	c.cacheMu.RLock()
-	if time.Now().Before(c.cacheExpiry) && len(c.cache) > 0 {
-		result := make(map[string]float64, len(c.cache))
-		for k, v := range c.cache {
-			result[k] = v
-		}
-		c.cacheMu.RUnlock()
-		return result, nil
+	var cached map[string]float64
+	if cache.GetJSON(ai100SellTriggersKey, &cached) && len(cached) > 0 {
+		return cached, nil
 	}
-	c.cacheMu.RUnlock()
 
 	// Fetch from API
 	url := fmt.Sprintf("%s?auth=%s&sort=fin&limit=100", c.apiURL, c.authKey)
@@ -103,10 +97,7 @@ func (c *AI100Client) FetchSellTriggers() (map[string]float64, error) {
 	}
 
 	// Update cache
-	c.cacheMu.Lock()
-	c.cache = result
-	c.cacheExpiry = time.Now().Add(c.cacheTTL)
-	c.cacheMu.Unlock()
+	cache.SetJSON(ai100SellTriggersKey, result, c.cacheTTL)
 
 	return result, nil
 }
@@ -16,6 +16,8 @@ import (
 const (
 	// Alpaca API endpoints for stock market data
 	alpacaDataBaseURL = "https://data.alpaca.markets"
+	// Alpaca crypto market data (US feed), symbols as BTC/USD
+	alpacaCryptoPath = "/v1beta3/crypto/us"
 )
 
 type APIClient struct {
@@ -84,7 +86,18 @@ func mapIntervalToAlpaca(interval string) string {
 	}
 }
 
+// GetKlines gets the latest limit K-lines, served from the kline archive when the API fails
 func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, error) {
+	klines, err := c.getKlinesAlpaca(symbol, interval, limit)
+	if err != nil {
+		if archived, ok := archiveFallbackLast(symbol, interval, limit, err); ok {
+			return archived, nil
+		}
+	}
+	return klines, err
+}
+
+func (c *APIClient) getKlinesAlpaca(symbol, interval string, limit int) ([]Kline, error) {
 	// Use Alpaca stocks API
 	alpacaInterval := mapIntervalToAlpaca(interval)
 	
@@ -113,6 +126,12 @@ func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, erro
 	}
 	startTime := now.Add(-duration)
 
+	// Crypto pairs come from the crypto endpoint, keyed by the Alpaca pair (BTCUSDT -> BTC/USD)
+	cryptoPair := ""
+	if IsCrypto(symbol) {
+		cryptoPair = ToExchangeSymbol("alpaca", symbol)
+	}
+
 	url := fmt.Sprintf("%s/v2/stocks/%s/bars?timeframe=%s&start=%s&limit=%d",
 		alpacaDataBaseURL,
 		symbol,
@@ -120,6 +139,9 @@ func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, erro
 		url.QueryEscape(startTime.Format(time.RFC3339)),
 		limit,
 	)
+	if cryptoPair != "" {
+		url = alpacaCryptoBarsURL(cryptoPair, alpacaInterval, startTime, limit)
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -153,7 +175,16 @@ func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, erro
 		NextToken string      `json:"next_page_token"`
 	}
 	
-	err = json.Unmarshal(body, &alpacaResp)
+	if cryptoPair != "" {
+		// Crypto bars are grouped by pair: {"bars": {"BTC/USD": [...]}}
+		var cryptoResp struct {
+			Bars map[string][]AlpacaBar `json:"bars"`
+		}
+		err = json.Unmarshal(body, &cryptoResp)
+		alpacaResp.Bars = cryptoResp.Bars[cryptoPair]
+	} else {
+		err = json.Unmarshal(body, &alpacaResp)
+	}
 	if err != nil {
 		log.Printf("Failed to parse Alpaca response: %s", string(body))
 		return nil, err
@@ -180,6 +211,18 @@ func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, erro
 	return klines, nil
 }
 
+// alpacaCryptoBarsURL crypto bars request of an Alpaca pair
+func alpacaCryptoBarsURL(pair, alpacaInterval string, start time.Time, limit int) string {
+	return fmt.Sprintf("%s%s/bars?symbols=%s&timeframe=%s&start=%s&limit=%d",
+		alpacaDataBaseURL,
+		alpacaCryptoPath,
+		url.QueryEscape(pair),
+		alpacaInterval,
+		url.QueryEscape(start.Format(time.RFC3339)),
+		limit,
+	)
+}
+
 func getDurationFromInterval(interval string) time.Duration {
 	switch interval {
 	case "1m":
@@ -227,6 +270,10 @@ func parseKline(kr KlineResponse) (Kline, error) {
 }
 
 func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
+	if IsCrypto(symbol) {
+		return c.getCryptoPriceAlpaca(symbol)
+	}
+
 	// Use Alpaca latest trade endpoint
 	url := fmt.Sprintf("%s/v2/stocks/%s/trades/latest", alpacaDataBaseURL, symbol)
 	req, err := http.NewRequest("GET", url, nil)
@@ -262,6 +309,45 @@ func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
 	return tradeResp.Trade.Price, nil
 }
 
+// getCryptoPriceAlpaca gets the latest trade price of a crypto pair from Alpaca
+func (c *APIClient) getCryptoPriceAlpaca(symbol string) (float64, error) {
+	pair := ToExchangeSymbol("alpaca", symbol)
+	req, err := http.NewRequest("GET", alpacaDataBaseURL+alpacaCryptoPath+"/latest/trades?symbols="+url.QueryEscape(pair), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("APCA-API-KEY-ID", c.apiKey)
+	req.Header.Set("APCA-API-SECRET-KEY", c.apiSecret)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("Alpaca API returned status %d", resp.StatusCode)
+	}
+
+	var tradeResp struct {
+		Trades map[string]struct {
+			Price float64 `json:"p"`
+		} `json:"trades"`
+	}
+	if err := json.Unmarshal(body, &tradeResp); err != nil {
+		return 0, err
+	}
+	trade, ok := tradeResp.Trades[pair]
+	if !ok || trade.Price <= 0 {
+		return 0, fmt.Errorf("no latest trade for %s", pair)
+	}
+	return trade.Price, nil
+}
+
 // AlpacaNews represents a news article from Alpaca API
 type AlpacaNews struct {
 	ID        int64    `json:"id"`
@@ -0,0 +1,475 @@
+package market
+
+import (
+	"SynapseStrike/logger"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ============================================================================
+// Local Kline Archive
+// ============================================================================
+// KlineArchive keeps closed K-lines in a local SQLite database. Sync downloads
+// bars incrementally (stocks from Alpaca, crypto from Binance), Verify checks
+// stored bars for gaps and malformed OHLC values. The backtester can read the
+// archive directly (data_source "archive"), and once registered with
+// SetKlineArchive it serves as fallback when the live API is down.
+
+const (
+	archiveSyncChunkBars     = 5000 // Bars requested per download chunk
+	archiveMaxStaleIntervals = 2    // Live fallback only serves archives whose newest bar closed at most this many intervals ago
+)
+
+// KlineArchive local K-line archive
+type KlineArchive struct {
+	db    *sql.DB
+	fetch func(symbol, timeframe string, start, end time.Time) ([]Kline, error) // Download source (replaced in tests)
+}
+
+// OpenKlineArchive opens (or creates) archive database at path
+func OpenKlineArchive(path string) (*KlineArchive, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open kline archive: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`PRAGMA journal_mode=WAL`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set kline archive journal mode: %w", err)
+	}
+
+	a := &KlineArchive{db: db, fetch: fetchArchiveSource}
+	if err := a.initTables(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close closes archive database
+func (a *KlineArchive) Close() error {
+	return a.db.Close()
+}
+
+func (a *KlineArchive) initTables() error {
+	_, err := a.db.Exec(`
+		CREATE TABLE IF NOT EXISTS klines (
+			symbol TEXT NOT NULL,
+			timeframe TEXT NOT NULL,
+			open_time INTEGER NOT NULL,
+			close_time INTEGER NOT NULL,
+			open REAL NOT NULL,
+			high REAL NOT NULL,
+			low REAL NOT NULL,
+			close REAL NOT NULL,
+			volume REAL NOT NULL,
+			quote_volume REAL NOT NULL DEFAULT 0,
+			trades INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (symbol, timeframe, open_time)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create klines table: %w", err)
+	}
+	return nil
+}
+
+// archiveKey normalizes symbol and timeframe used as archive key
+func archiveKey(symbol, timeframe string) (string, string, error) {
+	tf, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.ToUpper(strings.TrimSpace(symbol)), tf, nil
+}
+
+// fetchArchiveSource downloads bars from the source matching the symbol's asset class
+func fetchArchiveSource(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+	if IsStock(symbol) {
+		return getKlinesRangeAlpaca(symbol, timeframe, start, end)
+	}
+	return GetBinanceKlinesRange(symbol, timeframe, start, end)
+}
+
+// Save upserts bars of a symbol/timeframe (a bar saved again replaces the stored one)
+func (a *KlineArchive) Save(symbol, timeframe string, klines []Kline) error {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return err
+	}
+	if len(klines) == 0 {
+		return nil
+	}
+
+	tx, err := a.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO klines (symbol, timeframe, open_time, close_time, open, high, low, close, volume, quote_volume, trades)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(symbol, timeframe, open_time) DO UPDATE SET
+			close_time = excluded.close_time,
+			open = excluded.open,
+			high = excluded.high,
+			low = excluded.low,
+			close = excluded.close,
+			volume = excluded.volume,
+			quote_volume = excluded.quote_volume,
+			trades = excluded.trades
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare kline insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, k := range klines {
+		if _, err := stmt.Exec(symbol, tf, k.OpenTime, k.CloseTime, k.Open, k.High, k.Low, k.Close, k.Volume, k.QuoteVolume, k.Trades); err != nil {
+			return fmt.Errorf("failed to save kline: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Range gets bars opened within [start, end], ordered by time
+func (a *KlineArchive) Range(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	return a.query(`
+		SELECT open_time, close_time, open, high, low, close, volume, quote_volume, trades
+		FROM klines WHERE symbol = ? AND timeframe = ? AND open_time >= ? AND open_time <= ?
+		ORDER BY open_time ASC
+	`, symbol, tf, start.UnixMilli(), end.UnixMilli())
+}
+
+// Last gets the latest limit bars, ordered by time
+func (a *KlineArchive) Last(symbol, timeframe string, limit int) ([]Kline, error) {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := a.query(`
+		SELECT open_time, close_time, open, high, low, close, volume, quote_volume, trades
+		FROM klines WHERE symbol = ? AND timeframe = ?
+		ORDER BY open_time DESC LIMIT ?
+	`, symbol, tf, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(klines)-1; i < j; i, j = i+1, j-1 {
+		klines[i], klines[j] = klines[j], klines[i]
+	}
+	return klines, nil
+}
+
+func (a *KlineArchive) query(query string, args ...any) ([]Kline, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines: %w", err)
+	}
+	defer rows.Close()
+
+	var klines []Kline
+	for rows.Next() {
+		var k Kline
+		if err := rows.Scan(&k.OpenTime, &k.CloseTime, &k.Open, &k.High, &k.Low, &k.Close, &k.Volume, &k.QuoteVolume, &k.Trades); err != nil {
+			return nil, fmt.Errorf("failed to scan kline: %w", err)
+		}
+		klines = append(klines, k)
+	}
+	return klines, rows.Err()
+}
+
+// Bounds gets open time of first and last stored bar (ok = false if nothing stored)
+func (a *KlineArchive) Bounds(symbol, timeframe string) (first, last time.Time, ok bool, err error) {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return first, last, false, err
+	}
+	var minMs, maxMs sql.NullInt64
+	err = a.db.QueryRow(`SELECT MIN(open_time), MAX(open_time) FROM klines WHERE symbol = ? AND timeframe = ?`, symbol, tf).Scan(&minMs, &maxMs)
+	if err != nil {
+		return first, last, false, fmt.Errorf("failed to query kline bounds: %w", err)
+	}
+	if !minMs.Valid {
+		return first, last, false, nil
+	}
+	return time.UnixMilli(minMs.Int64), time.UnixMilli(maxMs.Int64), true, nil
+}
+
+// ArchiveSyncResult result of a Sync call
+type ArchiveSyncResult struct {
+	Symbol    string
+	Timeframe string
+	Fetched   int // Bars downloaded
+	Saved     int // Closed bars written to archive
+}
+
+// Sync downloads bars of [start, end] missing from the archive
+// Only the parts before the first and after the last stored bar are downloaded, so repeated runs
+// are incremental. Bars that have not closed yet are skipped. Use FillGaps for holes inside the range.
+func (a *KlineArchive) Sync(symbol, timeframe string, start, end time.Time) (*ArchiveSyncResult, error) {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	result := &ArchiveSyncResult{Symbol: symbol, Timeframe: tf}
+	if !end.After(start) {
+		return result, nil
+	}
+
+	first, last, ok, err := a.Bounds(symbol, tf)
+	if err != nil {
+		return nil, err
+	}
+	type span struct{ from, to time.Time }
+	var spans []span
+	if !ok {
+		spans = append(spans, span{start, end})
+	} else {
+		if start.Before(first) {
+			spans = append(spans, span{start, first})
+		}
+		from := last
+		if from.Before(start) {
+			from = start
+		}
+		if end.After(from) {
+			spans = append(spans, span{from, end})
+		}
+	}
+
+	for _, sp := range spans {
+		if err := a.download(symbol, tf, sp.from, sp.to, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// download fetches [from, to) in chunks and saves closed bars
+func (a *KlineArchive) download(symbol, tf string, from, to time.Time, result *ArchiveSyncResult) error {
+	dur, _ := TFDuration(tf)
+	chunk := dur * archiveSyncChunkBars
+	nowMs := time.Now().UnixMilli()
+
+	for chunkStart := from; chunkStart.Before(to); chunkStart = chunkStart.Add(chunk) {
+		chunkEnd := chunkStart.Add(chunk)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+		klines, err := a.fetch(symbol, tf, chunkStart, chunkEnd)
+		if err != nil {
+			return fmt.Errorf("failed to download %s %s klines (%s - %s): %w",
+				symbol, tf, chunkStart.Format(time.RFC3339), chunkEnd.Format(time.RFC3339), err)
+		}
+		result.Fetched += len(klines)
+
+		closed := klines[:0]
+		for _, k := range klines {
+			if k.CloseTime < nowMs {
+				closed = append(closed, k)
+			}
+		}
+		if err := a.Save(symbol, tf, closed); err != nil {
+			return err
+		}
+		result.Saved += len(closed)
+	}
+	return nil
+}
+
+// KlineGap run of missing bars between two stored bars
+type KlineGap struct {
+	From    time.Time // Open time of first missing bar
+	To      time.Time // Open time of last missing bar
+	Missing int       // Number of missing bars
+}
+
+// ArchiveIntegrityReport result of Verify
+type ArchiveIntegrityReport struct {
+	Symbol      string
+	Timeframe   string
+	Bars        int
+	First       time.Time
+	Last        time.Time
+	Gaps        []KlineGap // Only checked for continuous (24/7) markets
+	InvalidBars int        // Bars with inconsistent OHLC, negative volume or close time before open time
+}
+
+// OK whether no integrity problem was found
+func (r *ArchiveIntegrityReport) OK() bool {
+	return len(r.Gaps) == 0 && r.InvalidBars == 0
+}
+
+// Verify checks stored bars for gaps and malformed values
+// Stock sessions have natural gaps (nights, weekends, holidays, illiquid minutes), so gaps
+// are only reported for continuously traded symbols.
+func (a *KlineArchive) Verify(symbol, timeframe string) (*ArchiveIntegrityReport, error) {
+	symbol, tf, err := archiveKey(symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := a.query(`
+		SELECT open_time, close_time, open, high, low, close, volume, quote_volume, trades
+		FROM klines WHERE symbol = ? AND timeframe = ?
+		ORDER BY open_time ASC
+	`, symbol, tf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ArchiveIntegrityReport{Symbol: symbol, Timeframe: tf, Bars: len(klines)}
+	if len(klines) == 0 {
+		return report, nil
+	}
+	report.First = time.UnixMilli(klines[0].OpenTime)
+	report.Last = time.UnixMilli(klines[len(klines)-1].OpenTime)
+
+	durMs := int64(0)
+	if dur, err := TFDuration(tf); err == nil {
+		durMs = dur.Milliseconds()
+	}
+	checkGaps := !IsStock(symbol) && durMs > 0
+
+	for i, k := range klines {
+		if k.High < math.Max(k.Open, k.Close) || k.Low > math.Min(k.Open, k.Close) || k.Low <= 0 ||
+			k.Volume < 0 || k.CloseTime <= k.OpenTime {
+			report.InvalidBars++
+		}
+		if checkGaps && i > 0 {
+			if missing := int((k.OpenTime-klines[i-1].OpenTime)/durMs) - 1; missing > 0 {
+				report.Gaps = append(report.Gaps, KlineGap{
+					From:    time.UnixMilli(klines[i-1].OpenTime + durMs),
+					To:      time.UnixMilli(k.OpenTime - durMs),
+					Missing: missing,
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+// FillGaps downloads bars missing inside the stored range, returns number of bars saved
+func (a *KlineArchive) FillGaps(symbol, timeframe string) (int, error) {
+	report, err := a.Verify(symbol, timeframe)
+	if err != nil {
+		return 0, err
+	}
+	dur, _ := TFDuration(report.Timeframe)
+	result := &ArchiveSyncResult{Symbol: report.Symbol, Timeframe: report.Timeframe}
+	for _, gap := range report.Gaps {
+		if err := a.download(report.Symbol, report.Timeframe, gap.From, gap.To.Add(dur), result); err != nil {
+			return result.Saved, err
+		}
+	}
+	return result.Saved, nil
+}
+
+// ============================================================================
+// Live API fallback
+// ============================================================================
+
+var (
+	archiveMu     sync.RWMutex
+	globalArchive *KlineArchive
+)
+
+// SetKlineArchive registers archive used as fallback when the live API fails (nil disables fallback)
+func SetKlineArchive(a *KlineArchive) {
+	archiveMu.Lock()
+	globalArchive = a
+	archiveMu.Unlock()
+}
+
+// GetKlineArchive gets registered archive (nil if none)
+func GetKlineArchive() *KlineArchive {
+	archiveMu.RLock()
+	defer archiveMu.RUnlock()
+	return globalArchive
+}
+
+// archiveFallbackRange serves a failed range request from the archive (ok = false unless archived bars cover the range)
+func archiveFallbackRange(symbol, timeframe string, start, end time.Time, apiErr error) ([]Kline, bool) {
+	a := GetKlineArchive()
+	if a == nil {
+		return nil, false
+	}
+	interval, _ := TFDuration(timeframe)
+	var klines []Kline
+	for _, candidate := range archiveSymbols(symbol) {
+		if klines, _ = a.Range(candidate, timeframe, start, end); archiveCovers(klines, start, end, interval) {
+			break
+		}
+	}
+	if !archiveCovers(klines, start, end, interval) {
+		return nil, false
+	}
+	logger.Warnf("⚠️  [Archive] Live API failed for %s %s (%v), serving %d archived bars", symbol, timeframe, apiErr, len(klines))
+	return klines, true
+}
+
+// archiveCovers checks that bars reach from start to end (within one interval at both edges)
+func archiveCovers(klines []Kline, start, end time.Time, interval time.Duration) bool {
+	if len(klines) == 0 {
+		return false
+	}
+	first := time.UnixMilli(klines[0].OpenTime)
+	last := time.UnixMilli(klines[len(klines)-1].OpenTime)
+	return !first.After(start.Add(interval)) && !last.Before(end.Add(-interval))
+}
+
+// archiveFallbackLast serves a failed latest-bars request from the archive
+// Live trading must not act on old bars: ok = false unless the newest archived bar
+// closed within archiveMaxStaleIntervals intervals of now, so the API error surfaces instead.
+func archiveFallbackLast(symbol, timeframe string, limit int, apiErr error) ([]Kline, bool) {
+	a := GetKlineArchive()
+	if a == nil {
+		return nil, false
+	}
+	var klines []Kline
+	for _, candidate := range archiveSymbols(symbol) {
+		if klines, _ = a.Last(candidate, timeframe, limit); len(klines) > 0 {
+			break
+		}
+	}
+	if len(klines) == 0 {
+		return nil, false
+	}
+	latest := time.UnixMilli(klines[len(klines)-1].OpenTime)
+	interval, _ := TFDuration(timeframe)
+	if interval <= 0 {
+		interval = getDurationFromInterval(timeframe)
+	}
+	if age := time.Since(time.UnixMilli(klines[len(klines)-1].CloseTime)); age > archiveMaxStaleIntervals*interval {
+		logger.Warnf("⚠️  [Archive] Live API failed for %s %s (%v), archived bars too old to serve (latest %s, %s ago)",
+			symbol, timeframe, apiErr, latest.Format(time.RFC3339), age.Truncate(time.Second))
+		return nil, false
+	}
+	logger.Warnf("⚠️  [Archive] Live API failed for %s %s (%v), serving %d archived bars (latest %s)",
+		symbol, timeframe, apiErr, len(klines), latest.Format(time.RFC3339))
+	return klines, true
+}
+
+// archiveSymbols archive keys to look up for a requested symbol (as given, then without USDT/USD suffix like the stock API)
+func archiveSymbols(symbol string) []string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	stripped := strings.TrimSuffix(strings.TrimSuffix(symbol, "USDT"), "USD")
+	if stripped == symbol || stripped == "" {
+		return []string{symbol}
+	}
+	return []string{symbol, stripped}
+}
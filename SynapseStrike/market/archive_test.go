@@ -0,0 +1,126 @@
+package market
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestArchive opens archive in temp dir with fake download source serving 1m bars
+func newTestArchive(t *testing.T) (*KlineArchive, *[][2]time.Time) {
+	t.Helper()
+	a, err := OpenKlineArchive(filepath.Join(t.TempDir(), "klines.db"))
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+
+	var requests [][2]time.Time
+	a.fetch = func(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+		requests = append(requests, [2]time.Time{start, end})
+		var klines []Kline
+		for ts := start.Truncate(time.Minute); ts.Before(end); ts = ts.Add(time.Minute) {
+			klines = append(klines, Kline{OpenTime: ts.UnixMilli(), CloseTime: ts.Add(time.Minute).UnixMilli() - 1,
+				Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10})
+		}
+		return klines, nil
+	}
+	return a, &requests
+}
+
+// TestKlineArchive_SyncIncremental tests that repeated syncs only download missing ranges
+func TestKlineArchive_SyncIncremental(t *testing.T) {
+	a, requests := newTestArchive(t)
+	base := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	result, err := a.Sync("BTCUSDT", "1m", base, base.Add(60*time.Minute))
+	if err != nil || result.Saved != 60 {
+		t.Fatalf("first sync: saved %v, err %v", result, err)
+	}
+
+	// Extend at both ends: only before-first and after-last ranges are downloaded
+	*requests = nil
+	if _, err := a.Sync("btcusdt", "1m", base.Add(-10*time.Minute), base.Add(90*time.Minute)); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(*requests) != 2 || !(*requests)[0][1].Equal(base) || !(*requests)[1][0].Equal(base.Add(59*time.Minute)) {
+		t.Errorf("unexpected download ranges: %v", *requests)
+	}
+
+	klines, err := a.Range("BTCUSDT", "1m", base.Add(-10*time.Minute), base.Add(90*time.Minute))
+	if err != nil || len(klines) != 100 {
+		t.Fatalf("expected 100 archived bars, got %d (err %v)", len(klines), err)
+	}
+	last, _ := a.Last("BTCUSDT", "1m", 3)
+	if len(last) != 3 || last[2].OpenTime != base.Add(89*time.Minute).UnixMilli() {
+		t.Errorf("unexpected latest bars: %+v", last)
+	}
+}
+
+// TestKlineArchive_VerifyAndFillGaps tests gap/invalid bar detection and gap repair
+func TestKlineArchive_VerifyAndFillGaps(t *testing.T) {
+	a, _ := newTestArchive(t)
+	base := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	bar := func(minute int, high float64) Kline {
+		ts := base.Add(time.Duration(minute) * time.Minute)
+		return Kline{OpenTime: ts.UnixMilli(), CloseTime: ts.Add(time.Minute).UnixMilli() - 1, Open: 100, High: high, Low: 99, Close: 100, Volume: 1}
+	}
+	if err := a.Save("BTCUSDT", "1m", []Kline{bar(0, 101), bar(1, 101), bar(5, 99.5), bar(6, 101)}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	report, err := a.Verify("BTCUSDT", "1m")
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if report.Bars != 4 || report.InvalidBars != 1 || len(report.Gaps) != 1 || report.Gaps[0].Missing != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	saved, err := a.FillGaps("BTCUSDT", "1m")
+	if err != nil || saved != 3 {
+		t.Fatalf("fill gaps: saved %d, err %v", saved, err)
+	}
+	if report, _ = a.Verify("BTCUSDT", "1m"); len(report.Gaps) != 0 || report.Bars != 7 {
+		t.Errorf("gaps remain after repair: %+v", report)
+	}
+}
+
+// TestArchiveFallback tests that archived bars are served when the live API fails
+func TestArchiveFallback(t *testing.T) {
+	a, _ := newTestArchive(t)
+	base := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	if _, err := a.Sync("AAPL", "1m", base, base.Add(30*time.Minute)); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	apiErr := errors.New("connection refused")
+	if _, ok := archiveFallbackRange("AAPL", "1m", base, base.Add(10*time.Minute), apiErr); ok {
+		t.Fatal("fallback should be disabled without registered archive")
+	}
+
+	SetKlineArchive(a)
+	defer SetKlineArchive(nil)
+	klines, ok := archiveFallbackRange("AAPLUSDT", "1m", base, base.Add(9*time.Minute), apiErr)
+	if !ok || len(klines) != 10 {
+		t.Errorf("expected 10 archived bars for range fallback, got %d (ok %v)", len(klines), ok)
+	}
+	if _, ok = archiveFallbackRange("AAPL", "1m", base.Add(20*time.Minute), base.Add(60*time.Minute), apiErr); ok {
+		t.Error("range only partly archived should not be served")
+	}
+	if _, ok = archiveFallbackRange("AAPL", "1m", base.Add(-time.Hour), base.Add(10*time.Minute), apiErr); ok {
+		t.Error("range starting before the archive should not be served")
+	}
+	if _, ok = archiveFallbackLast("AAPL", "1m", 5, apiErr); ok {
+		t.Error("days-old bars should not be served to live trading")
+	}
+
+	now := time.Now().Truncate(time.Minute)
+	if _, err := a.Sync("MSFT", "1m", now.Add(-30*time.Minute), now.Add(-time.Minute)); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if klines, ok = archiveFallbackLast("MSFT", "1m", 5, apiErr); !ok || len(klines) != 5 {
+		t.Errorf("expected 5 fresh archived bars for latest fallback, got %d (ok %v)", len(klines), ok)
+	}
+}
@@ -1,7 +1,9 @@
 package market
 
 import (
+	"SynapseStrike/cache"
 	"SynapseStrike/logger"
+	"SynapseStrike/numfmt"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,7 +11,6 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -21,9 +22,8 @@ type FundingRateCache struct {
 }
 
 var (
-	fundingRateMap sync.Map // map[string]*FundingRateCache
-	frCacheTTL     = 1 * time.Hour
-	httpClient     = &http.Client{Timeout: 30 * time.Second} // HTTP client for external API calls
+	frCacheTTL = 1 * time.Hour
+	httpClient = &http.Client{Timeout: 30 * time.Second} // HTTP client for external API calls
 )
 
 // Get retrieves market data for the specified token
@@ -50,6 +50,14 @@ func Get(symbol string) (*Data, error) {
 		return nil, fmt.Errorf("Failed to get 4-hour K-line: %v", err)
 	}
 
+	// Sort, de-duplicate and repair small gaps before calculating indicators
+	quality := &DataQuality{}
+	var q3m, q4h *KlineQuality
+	klines3m, q3m = RepairKlines(symbol, "3m", klines3m)
+	klines4h, q4h = RepairKlines(symbol, "4h", klines4h)
+	quality.Add(q3m)
+	quality.Add(q4h)
+
 	// Check if data is empty
 	if len(klines3m) == 0 {
 		return nil, fmt.Errorf("3-minute K-line data is empty")
@@ -111,6 +119,7 @@ func Get(symbol string) (*Data, error) {
 		FundingRate:       fundingRate,
 		IntradaySeries:    intradayData,
 		LongerTermContext: longerTermData,
+		Quality:           quality,
 	}, nil
 }
 
@@ -144,21 +153,30 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 
 	// Store data for all timeframes
 	timeframeData := make(map[string]*TimeframeSeriesData)
+	quality := &DataQuality{}
 	var primaryKlines []Kline
 
+	var gaps []FeedGap
+
 	// Get K-line data for each timeframe
 	for _, tf := range timeframes {
 		klines, err := WSMonitorCli.GetCurrentKlines(symbol, tf)
 		if err != nil {
 			logger.Infof("⚠️ Failed to get %s %s K-line: %v", symbol, tf, err)
+			gaps = append(gaps, FeedGap{Feed: FeedKlines, Reason: fmt.Sprintf("%s: %v", tf, err)})
 			continue
 		}
 
 		if len(klines) == 0 {
 			logger.Infof("⚠️ %s %s K-line data is empty", symbol, tf)
+			gaps = append(gaps, FeedGap{Feed: FeedKlines, Reason: tf + ": no bars"})
 			continue
 		}
 
+		// Sort, de-duplicate and repair small gaps before calculating indicators
+		klines, tfQuality := RepairKlines(symbol, tf, klines)
+		quality.Add(tfQuality)
+
 		// Save primary timeframe K-lines for calculating base indicators
 		if tf == primaryTimeframe {
 			primaryKlines = klines
@@ -194,6 +212,7 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	oiData, err := getOpenInterestData(symbol)
 	if err != nil {
 		oiData = &OIData{Latest: 0, Average: 0}
+		gaps = append(gaps, FeedGap{Feed: FeedOI, Reason: err.Error()})
 	}
 
 	// Get Funding Rate
@@ -210,11 +229,14 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 		OpenInterest:  oiData,
 		FundingRate:   fundingRate,
 		TimeframeData: timeframeData,
+		Quality:       quality,
+		Gaps:          gaps,
 	}, nil
 }
 
 // GetStockDataWithTimeframes retrieves stock market data using Alpaca API
-// This is the stock-specific version of GetWithTimeframes
+// This is the stock-specific version of GetWithTimeframes. Crypto pairs traded on
+// Alpaca come through here too (Alpaca crypto bars), without the stock extras.
 // timeframes: list of timeframes, e.g. ["5m", "15m", "1h", "4h"]
 // primaryTimeframe: primary timeframe (used for calculating current indicators), defaults to timeframes[0]
 // count: number of K-lines for each timeframe
@@ -251,8 +273,11 @@ func GetStockDataWithTimeframes(symbol string, timeframes []string, primaryTimef
 
 	// Store data for all timeframes
 	timeframeData := make(map[string]*TimeframeSeriesData)
+	quality := &DataQuality{}
 	var primaryKlines []Kline
 
+	var gaps []FeedGap
+
 	// Get K-line data for each timeframe via Alpaca API
 	for _, tf := range timeframes {
 		// Request more bars to have enough data for indicators
@@ -264,16 +289,22 @@ func GetStockDataWithTimeframes(symbol string, timeframes []string, primaryTimef
 		klines, err := apiClient.GetKlines(symbol, tf, requestCount)
 		if err != nil {
 			logger.Infof("⚠️ Failed to get %s %s K-line from Alpaca: %v", symbol, tf, err)
+			gaps = append(gaps, FeedGap{Feed: FeedKlines, Reason: fmt.Sprintf("%s: %v", tf, err)})
 			continue
 		}
 
 		if len(klines) == 0 {
 			logger.Infof("⚠️ %s %s K-line data is empty from Alpaca", symbol, tf)
+			gaps = append(gaps, FeedGap{Feed: FeedKlines, Reason: tf + ": no bars"})
 			continue
 		}
 
 		logger.Infof("✓ Got %d %s K-lines for %s from Alpaca", len(klines), tf, symbol)
 
+		// Sort, de-duplicate and repair small gaps before calculating indicators
+		klines, tfQuality := RepairKlines(symbol, tf, klines)
+		quality.Add(tfQuality)
+
 		// Save primary timeframe K-lines for calculating base indicators
 		if tf == primaryTimeframe {
 			primaryKlines = klines
@@ -300,7 +331,12 @@ func GetStockDataWithTimeframes(symbol string, timeframes []string, primaryTimef
 	priceChange4h := calculatePriceChangeByBars(primaryKlines, primaryTimeframe, 240) // 4 hours
 
 	// Fetch stock-specific extra data (news, corporate actions, volume surge)
-	stockExtra := fetchStockExtraData(symbol, apiClient, primaryKlines)
+	var stockExtra *StockExtraData
+	if !IsCrypto(symbol) {
+		var extraGaps []FeedGap
+		stockExtra, extraGaps = fetchStockExtraData(symbol, apiClient, primaryKlines)
+		gaps = append(gaps, extraGaps...)
+	}
 
 	// Stocks don't have OI or funding rate like crypto
 	return &Data{
@@ -315,15 +351,22 @@ func GetStockDataWithTimeframes(symbol string, timeframes []string, primaryTimef
 		FundingRate:    0,   // No funding rate for stocks
 		TimeframeData:  timeframeData,
 		StockExtraData: stockExtra,
+		Quality:        quality,
+		Gaps:           gaps,
 	}, nil
 }
 
 // fetchStockExtraData fetches news, corporate actions, and calculates volume surge
-func fetchStockExtraData(symbol string, apiClient *APIClient, klines []Kline) *StockExtraData {
+// Returns the failed feeds the prompt reports as gaps (news).
+func fetchStockExtraData(symbol string, apiClient *APIClient, klines []Kline) (*StockExtraData, []FeedGap) {
 	extra := &StockExtraData{}
+	var gaps []FeedGap
 
 	// Fetch news (last 5 articles)
 	news, err := apiClient.GetNews(symbol, 5)
+	if err != nil {
+		gaps = append(gaps, FeedGap{Feed: FeedNews, Reason: err.Error()})
+	}
 	if err == nil && len(news) > 0 {
 		for _, n := range news {
 			extra.RecentNews = append(extra.RecentNews, NewsItem{
@@ -412,7 +455,7 @@ func fetchStockExtraData(symbol string, apiClient *APIClient, klines []Kline) *S
 		extra.AnchoredVWAPDev = (currentPrice - extra.AnchoredVWAP) / extra.AnchoredVWAP * 100
 	}
 
-	return extra
+	return extra, gaps
 }
 
 // AnalystRatingData holds analyst rating info
@@ -1045,7 +1088,7 @@ func calculatePriceChangeByBars(klines []Kline, timeframe string, targetMinutes
 	}
 
 	// Parse timeframe to minutes
-	tfMinutes := parseTimeframeToMinutes(timeframe)
+	tfMinutes := TimeframeMinutes(timeframe)
 	if tfMinutes <= 0 {
 		return 0
 	}
@@ -1069,8 +1112,8 @@ func calculatePriceChangeByBars(klines []Kline, timeframe string, targetMinutes
 	return 0
 }
 
-// parseTimeframeToMinutes parses timeframe string to minutes
-func parseTimeframeToMinutes(tf string) int {
+// TimeframeMinutes parses timeframe string to minutes (0 = unknown)
+func TimeframeMinutes(tf string) int {
 	switch tf {
 	case "1m":
 		return 1
@@ -1353,12 +1396,10 @@ func getOpenInterestData(symbol string) (*OIData, error) {
 func getFundingRate(symbol string) (float64, error) {
 	// Check cache (1-hour validity)
 	// Funding Rate only updates every 8 hours, 1-hour cache is very reasonable
-	if cached, ok := fundingRateMap.Load(symbol); ok {
-		cache := cached.(*FundingRateCache)
-		if time.Since(cache.UpdatedAt) < frCacheTTL {
-			// Cache hit, return directly
-			return cache.Rate, nil
-		}
+	var cached FundingRateCache
+	if cache.GetJSON("funding_rate:"+symbol, &cached) && time.Since(cached.UpdatedAt) < frCacheTTL {
+		// Cache hit, return directly
+		return cached.Rate, nil
 	}
 
 	// Cache expired or doesn't exist, call API
@@ -1393,10 +1434,10 @@ func getFundingRate(symbol string) (float64, error) {
 	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
 
 	// Update cache
-	fundingRateMap.Store(symbol, &FundingRateCache{
+	cache.SetJSON("funding_rate:"+symbol, FundingRateCache{
 		Rate:      rate,
 		UpdatedAt: time.Now(),
-	})
+	}, frCacheTTL)
 
 	return rate, nil
 }
@@ -1406,48 +1447,52 @@ func Format(data *Data) string {
 	var sb strings.Builder
 
 	// Format price with dynamic precision
-	priceStr := formatPriceWithDynamicPrecision(data.CurrentPrice)
-	sb.WriteString(fmt.Sprintf("current_price = %s, current_ema20 = %.3f, current_macd = %.3f, current_rsi (7 period) = %.3f\n\n",
-		priceStr, data.CurrentEMA20, data.CurrentMACD, data.CurrentRSI7))
+	priceStr := numfmt.Price(data.CurrentPrice)
+	sb.WriteString(fmt.Sprintf("current_price = %s, current_ema20 = %s, current_macd = %s, current_rsi (7 period) = %.3f\n\n",
+		priceStr, numfmt.Price(data.CurrentEMA20), numfmt.Price(data.CurrentMACD), data.CurrentRSI7))
+
+	if summary := data.Quality.Summary(); summary != "" {
+		sb.WriteString(fmt.Sprintf("Data quality: %s\n\n", summary))
+	}
 
 	sb.WriteString(fmt.Sprintf("In addition, here is the latest %s open interest and funding rate for perps:\n\n",
 		data.Symbol))
 
 	if data.OpenInterest != nil {
 		// Format OI data with dynamic precision
-		oiLatestStr := formatPriceWithDynamicPrecision(data.OpenInterest.Latest)
-		oiAverageStr := formatPriceWithDynamicPrecision(data.OpenInterest.Average)
+		oiLatestStr := numfmt.Price(data.OpenInterest.Latest)
+		oiAverageStr := numfmt.Price(data.OpenInterest.Average)
 		sb.WriteString(fmt.Sprintf("Open Interest: Latest: %s Average: %s\n\n",
 			oiLatestStr, oiAverageStr))
 	}
 
-	sb.WriteString(fmt.Sprintf("Funding Rate: %.2e\n\n", data.FundingRate))
+	sb.WriteString(fmt.Sprintf("Funding Rate: %s\n\n", numfmt.Percent(data.FundingRate, 4)))
 
 	if data.IntradaySeries != nil {
 		sb.WriteString("Intraday series (3‑minute intervals, oldest → latest):\n\n")
 
 		if len(data.IntradaySeries.MidPrices) > 0 {
-			sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.IntradaySeries.MidPrices)))
+			sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", numfmt.Series(data.IntradaySeries.MidPrices)))
 		}
 
 		if len(data.IntradaySeries.EMA20Values) > 0 {
-			sb.WriteString(fmt.Sprintf("EMA indicators (20‑period): %s\n\n", formatFloatSlice(data.IntradaySeries.EMA20Values)))
+			sb.WriteString(fmt.Sprintf("EMA indicators (20‑period): %s\n\n", numfmt.Series(data.IntradaySeries.EMA20Values)))
 		}
 
 		if len(data.IntradaySeries.MACDValues) > 0 {
-			sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", formatFloatSlice(data.IntradaySeries.MACDValues)))
+			sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", numfmt.Series(data.IntradaySeries.MACDValues)))
 		}
 
 		if len(data.IntradaySeries.RSI7Values) > 0 {
-			sb.WriteString(fmt.Sprintf("RSI indicators (7‑Period): %s\n\n", formatFloatSlice(data.IntradaySeries.RSI7Values)))
+			sb.WriteString(fmt.Sprintf("RSI indicators (7‑Period): %s\n\n", numfmt.Series(data.IntradaySeries.RSI7Values)))
 		}
 
 		if len(data.IntradaySeries.RSI14Values) > 0 {
-			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", formatFloatSlice(data.IntradaySeries.RSI14Values)))
+			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", numfmt.Series(data.IntradaySeries.RSI14Values)))
 		}
 
 		if len(data.IntradaySeries.Volume) > 0 {
-			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.IntradaySeries.Volume)))
+			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", numfmt.Series(data.IntradaySeries.Volume)))
 		}
 
 		sb.WriteString(fmt.Sprintf("3m ATR (14‑period): %.3f\n\n", data.IntradaySeries.ATR14))
@@ -1466,11 +1511,11 @@ func Format(data *Data) string {
 			data.LongerTermContext.CurrentVolume, data.LongerTermContext.AverageVolume))
 
 		if len(data.LongerTermContext.MACDValues) > 0 {
-			sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", formatFloatSlice(data.LongerTermContext.MACDValues)))
+			sb.WriteString(fmt.Sprintf("MACD indicators: %s\n\n", numfmt.Series(data.LongerTermContext.MACDValues)))
 		}
 
 		if len(data.LongerTermContext.RSI14Values) > 0 {
-			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", formatFloatSlice(data.LongerTermContext.RSI14Values)))
+			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", numfmt.Series(data.LongerTermContext.RSI14Values)))
 		}
 	}
 
@@ -1557,31 +1602,31 @@ func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData) {
 		sb.WriteString("\n")
 	} else if len(data.MidPrices) > 0 {
 		// Fallback to old format for backward compatibility
-		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.MidPrices)))
+		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", numfmt.Series(data.MidPrices)))
 		if len(data.Volume) > 0 {
-			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.Volume)))
+			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", numfmt.Series(data.Volume)))
 		}
 	}
 
 	// Technical indicators
 	if len(data.EMA20Values) > 0 {
-		sb.WriteString(fmt.Sprintf("EMA20: %s\n", formatFloatSlice(data.EMA20Values)))
+		sb.WriteString(fmt.Sprintf("EMA20: %s\n", numfmt.Series(data.EMA20Values)))
 	}
 
 	if len(data.EMA50Values) > 0 {
-		sb.WriteString(fmt.Sprintf("EMA50: %s\n", formatFloatSlice(data.EMA50Values)))
+		sb.WriteString(fmt.Sprintf("EMA50: %s\n", numfmt.Series(data.EMA50Values)))
 	}
 
 	if len(data.MACDValues) > 0 {
-		sb.WriteString(fmt.Sprintf("MACD: %s\n", formatFloatSlice(data.MACDValues)))
+		sb.WriteString(fmt.Sprintf("MACD: %s\n", numfmt.Series(data.MACDValues)))
 	}
 
 	if len(data.RSI7Values) > 0 {
-		sb.WriteString(fmt.Sprintf("RSI7: %s\n", formatFloatSlice(data.RSI7Values)))
+		sb.WriteString(fmt.Sprintf("RSI7: %s\n", numfmt.Series(data.RSI7Values)))
 	}
 
 	if len(data.RSI14Values) > 0 {
-		sb.WriteString(fmt.Sprintf("RSI14: %s\n", formatFloatSlice(data.RSI14Values)))
+		sb.WriteString(fmt.Sprintf("RSI14: %s\n", numfmt.Series(data.RSI14Values)))
 	}
 
 	if data.ATR14 > 0 {
@@ -1593,60 +1638,21 @@ func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData) {
 		sb.WriteString(fmt.Sprintf("Current VWAP: %.4f\n", data.CurrentVWAP))
 	}
 	if len(data.VWAPValues) > 0 {
-		sb.WriteString(fmt.Sprintf("VWAP Series: %s\n", formatFloatSlice(data.VWAPValues)))
+		sb.WriteString(fmt.Sprintf("VWAP Series: %s\n", numfmt.Series(data.VWAPValues)))
 	}
 
 	// Volume Profile (simplified)
 	if len(data.VolumeProfile) > 0 {
-		sb.WriteString(fmt.Sprintf("Volume Profile (price levels low→high): %s\n", formatFloatSlice(data.VolumeProfile)))
+		sb.WriteString(fmt.Sprintf("Volume Profile (price levels low→high): %s\n", numfmt.Series(data.VolumeProfile)))
 	}
 
 	sb.WriteString("\n")
 }
 
-// formatPriceWithDynamicPrecision dynamically selects precision based on price range
-// This perfectly supports all coins from ultra-low price meme coins (< 0.0001) to BTC/ETH
-func formatPriceWithDynamicPrecision(price float64) string {
-	switch {
-	case price < 0.0001:
-		// Ultra-low price meme coins: 1000SATS, 1000WHY, DOGS
-		// 0.00002070 → "0.00002070" (8 decimal places)
-		return fmt.Sprintf("%.8f", price)
-	case price < 0.001:
-		// Low price meme coins: NEIRO, HMSTR, HOT, NOT
-		// 0.00015060 → "0.000151" (6 decimal places)
-		return fmt.Sprintf("%.6f", price)
-	case price < 0.01:
-		// Mid-low price coins: PEPE, SHIB, MEME
-		// 0.00556800 → "0.005568" (6 decimal places)
-		return fmt.Sprintf("%.6f", price)
-	case price < 1.0:
-		// Low price coins: ASTER, DOGE, ADA, TRX
-		// 0.9954 → "0.9954" (4 decimal places)
-		return fmt.Sprintf("%.4f", price)
-	case price < 100:
-		// Mid price coins: SOL, AVAX, LINK, MATIC
-		// 23.4567 → "23.4567" (4 decimal places)
-		return fmt.Sprintf("%.4f", price)
-	default:
-		// High price coins: BTC, ETH (save tokens)
-		// 45678.9123 → "45678.91" (2 decimal places)
-		return fmt.Sprintf("%.2f", price)
-	}
-}
-
-// formatFloatSlice formats float64 slice to string (using dynamic precision)
-func formatFloatSlice(values []float64) string {
-	strValues := make([]string, len(values))
-	for i, v := range values {
-		strValues[i] = formatPriceWithDynamicPrecision(v)
-	}
-	return "[" + strings.Join(strValues, ", ") + "]"
-}
-
-// Normalize normalizes symbol to uppercase (stock symbols don't need USDT suffix)
+// Normalize normalizes symbol to canonical form (uppercase, aliases resolved via symbol registry)
+// Stock symbols don't need USDT suffix
 func Normalize(symbol string) string {
-	return strings.ToUpper(strings.TrimSpace(symbol))
+	return Symbols.Resolve(symbol)
 }
 
 // parseFloat parses float value
@@ -0,0 +1,102 @@
+package market
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Decimal Order Math
+// ============================================================================
+// Exchanges reject orders whose quantity is not a multiple of the lot step
+// (LOT_SIZE) or whose price is not a multiple of the tick (PRICE_FILTER).
+// Rounding in float64 produces values like 0.30000000000000004 or floors
+// 0.7/0.1 to 6, so quantity and price rounding is done on decimals: the
+// float input is taken at its shortest representation, aligned to the exact
+// increment and formatted with exactly the increment's decimal places.
+// Quantities are always rounded down (never exceed the requested size or the
+// held position), prices to the nearest tick.
+
+// ParseIncrement parses a step or tick size as published by exchanges ("0.00100000")
+// Returns false for empty, invalid or non-positive values.
+func ParseIncrement(s string) (decimal.Decimal, bool) {
+	inc, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil || !inc.IsPositive() {
+		return decimal.Zero, false
+	}
+	return inc, true
+}
+
+// IncrementFromFloat step or tick size held as float64 (e.g. 0.001)
+func IncrementFromFloat(f float64) decimal.Decimal {
+	if f <= 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat(f)
+}
+
+// IncrementFromPlaces step of an exchange publishing decimal places instead of a size (3 → 0.001)
+func IncrementFromPlaces(places int) decimal.Decimal {
+	if places < 0 {
+		places = 0
+	}
+	return decimal.New(1, -int32(places))
+}
+
+// FloorToIncrement rounds value down to a multiple of increment (unchanged if increment is not positive)
+func FloorToIncrement(value float64, increment decimal.Decimal) decimal.Decimal {
+	d := decimal.NewFromFloat(value)
+	if !increment.IsPositive() {
+		return d
+	}
+	return d.Div(increment).Floor().Mul(increment)
+}
+
+// RoundToIncrement rounds value to the nearest multiple of increment (unchanged if increment is not positive)
+func RoundToIncrement(value float64, increment decimal.Decimal) decimal.Decimal {
+	d := decimal.NewFromFloat(value)
+	if !increment.IsPositive() {
+		return d
+	}
+	return d.Div(increment).Round(0).Mul(increment)
+}
+
+// FloorToUnits converts value to integer units of 10^-places, rounded down (0.29 at 4 places → 2900)
+// Used by exchanges taking integer base amounts instead of decimal strings.
+func FloorToUnits(value float64, places int) int64 {
+	return decimal.NewFromFloat(value).Shift(int32(places)).Floor().IntPart()
+}
+
+// RoundToUnits converts value to integer units of 10^-places, rounded to nearest (prices)
+func RoundToUnits(value float64, places int) int64 {
+	return decimal.NewFromFloat(value).Shift(int32(places)).Round(0).IntPart()
+}
+
+// IncrementPlaces number of decimal places of increment ("0.00100000" → 3, "5" → 0)
+func IncrementPlaces(increment decimal.Decimal) int32 {
+	s := increment.String() // Trailing zeros are trimmed
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		return int32(len(s) - idx - 1)
+	}
+	return 0
+}
+
+// FormatIncrement formats value with exactly the decimal places of increment
+// A non-positive increment formats the value as is.
+func FormatIncrement(value, increment decimal.Decimal) string {
+	if !increment.IsPositive() {
+		return value.String()
+	}
+	return value.StringFixed(IncrementPlaces(increment))
+}
+
+// FormatQuantityStep rounds quantity down to step and formats it for an order
+func FormatQuantityStep(quantity float64, step decimal.Decimal) string {
+	return FormatIncrement(FloorToIncrement(quantity, step), step)
+}
+
+// FormatPriceTick rounds price to the nearest tick and formats it for an order
+func FormatPriceTick(price float64, tick decimal.Decimal) string {
+	return FormatIncrement(RoundToIncrement(price, tick), tick)
+}
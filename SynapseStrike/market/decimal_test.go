@@ -0,0 +1,80 @@
+package market
+
+import "testing"
+
+func TestFormatQuantityStep(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		step     string
+		want     string
+	}{
+		{0.7, "0.1", "0.7"}, // float64: 0.7/0.1 = 6.9999999 would floor to 0.6
+		{0.3, "0.00100000", "0.300"},
+		{1.23456789, "0.001", "1.234"},
+		{0.0129, "0.001", "0.012"},
+		{57, "5", "55"},
+		{0.0009, "0.001", "0.000"},
+	}
+	for _, tt := range tests {
+		step, ok := ParseIncrement(tt.step)
+		if !ok {
+			t.Fatalf("ParseIncrement(%q) failed", tt.step)
+		}
+		if got := FormatQuantityStep(tt.quantity, step); got != tt.want {
+			t.Errorf("FormatQuantityStep(%v, %s) = %q, want %q", tt.quantity, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPriceTick(t *testing.T) {
+	tests := []struct {
+		price float64
+		tick  float64
+		want  string
+	}{
+		{187.236, 0.01, "187.24"},
+		{60123.45, 0.1, "60123.5"},
+		{0.000012345, 0.0000001, "0.0000123"},
+		{1.15, 0.05, "1.15"},
+	}
+	for _, tt := range tests {
+		if got := FormatPriceTick(tt.price, IncrementFromFloat(tt.tick)); got != tt.want {
+			t.Errorf("FormatPriceTick(%v, %v) = %q, want %q", tt.price, tt.tick, got, tt.want)
+		}
+	}
+}
+
+func TestParseIncrementInvalid(t *testing.T) {
+	for _, s := range []string{"", "0", "-0.1", "abc"} {
+		if _, ok := ParseIncrement(s); ok {
+			t.Errorf("ParseIncrement(%q) accepted", s)
+		}
+	}
+	if got := FormatQuantityStep(1.5, IncrementFromFloat(0)); got != "1.5" {
+		t.Errorf("FormatQuantityStep without step = %q, want 1.5", got)
+	}
+}
+
+func TestUnits(t *testing.T) {
+	if got := FloorToUnits(0.29, 4); got != 2900 {
+		t.Errorf("FloorToUnits(0.29, 4) = %d, want 2900", got)
+	}
+	if got := FloorToUnits(0.012349, 4); got != 123 {
+		t.Errorf("FloorToUnits(0.012349, 4) = %d, want 123", got)
+	}
+	if got := RoundToUnits(1.005, 2); got != 101 {
+		t.Errorf("RoundToUnits(1.005, 2) = %d, want 101", got)
+	}
+}
+
+func TestSymbolRegistry_FormatOrderValues(t *testing.T) {
+	if got := Symbols.FormatQuantity("BTCUSDT", 0.0129); got != "0.012" {
+		t.Errorf("FormatQuantity(BTCUSDT) = %q, want 0.012", got)
+	}
+	if got := Symbols.FormatPrice("ETHUSDT", 3012.345); got != "3012.35" {
+		t.Errorf("FormatPrice(ETHUSDT) = %q, want 3012.35", got)
+	}
+	if got := Symbols.FormatPrice("UNKNOWNUSDT", 0.1234567); got != "0.1234567" {
+		t.Errorf("FormatPrice(UNKNOWNUSDT) = %q, want 0.1234567", got)
+	}
+}
@@ -0,0 +1,101 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Economic Calendar
+// ============================================================================
+// Macro releases (FOMC decisions, CPI prints, ...) come from the FMP economic
+// calendar. The calendar from yesterday to a week ahead is fetched at most
+// once per economicCalendarRefresh and shared by all traders; a stale copy is
+// kept if refreshing fails.
+
+const (
+	economicCalendarRefresh   = 6 * time.Hour
+	economicCalendarDaysAhead = 7
+)
+
+// EconomicEvent one scheduled macro release
+type EconomicEvent struct {
+	Event   string    `json:"event"`
+	Country string    `json:"country"`
+	Impact  string    `json:"impact"`
+	Time    time.Time `json:"time"` // Release time (UTC)
+}
+
+var (
+	economicCalendarMu        sync.Mutex
+	economicCalendarEvents    []EconomicEvent
+	economicCalendarFetchedAt time.Time
+)
+
+// GetEconomicCalendar macro releases from yesterday to a week ahead (cached)
+func GetEconomicCalendar() ([]EconomicEvent, error) {
+	economicCalendarMu.Lock()
+	defer economicCalendarMu.Unlock()
+
+	if !economicCalendarFetchedAt.IsZero() && time.Since(economicCalendarFetchedAt) < economicCalendarRefresh {
+		return economicCalendarEvents, nil
+	}
+	events, err := fetchEconomicCalendar(time.Now().UTC())
+	if err != nil {
+		if economicCalendarEvents != nil {
+			return economicCalendarEvents, nil
+		}
+		return nil, err
+	}
+	economicCalendarEvents = events
+	economicCalendarFetchedAt = time.Now()
+	return events, nil
+}
+
+// fetchEconomicCalendar fetches the FMP economic calendar around now
+func fetchEconomicCalendar(now time.Time) ([]EconomicEvent, error) {
+	from := now.AddDate(0, 0, -1).Format("2006-01-02")
+	to := now.AddDate(0, 0, economicCalendarDaysAhead).Format("2006-01-02")
+
+	url := fmt.Sprintf("https://financialmodelingprep.com/api/v3/economic_calendar?from=%s&to=%s&apikey=JgGALumW4MUTAuCLQZRS9BgldKqLdpM6", from, to)
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("economic calendar request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return parseEconomicCalendar(body)
+}
+
+// parseEconomicCalendar parses FMP economic calendar entries, skips entries without a valid date
+func parseEconomicCalendar(body []byte) ([]EconomicEvent, error) {
+	var raw []struct {
+		Event   string `json:"event"`
+		Date    string `json:"date"` // "2006-01-02 15:04:05" in UTC
+		Country string `json:"country"`
+		Impact  string `json:"impact"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	events := make([]EconomicEvent, 0, len(raw))
+	for _, e := range raw {
+		t, err := time.Parse("2006-01-02 15:04:05", e.Date)
+		if err != nil {
+			continue
+		}
+		events = append(events, EconomicEvent{Event: e.Event, Country: e.Country, Impact: e.Impact, Time: t.UTC()})
+	}
+	return events, nil
+}
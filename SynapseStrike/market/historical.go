@@ -81,8 +81,20 @@ func mapTimeframeToAlpaca(tf string) string {
 }
 
 // GetKlinesRange fetches K-line (bar) series within specified time range using Alpaca API.
-// Returns data sorted by time in ascending order.
+// Returns data sorted by time in ascending order. When the API fails, bars are served
+// from the local kline archive if it covers the range (see archive.go).
 func GetKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kline, error) {
+	klines, err := getKlinesRangeAlpaca(symbol, timeframe, start, end)
+	if err != nil {
+		if archived, ok := archiveFallbackRange(symbol, timeframe, start, end, err); ok {
+			return archived, nil
+		}
+	}
+	return klines, err
+}
+
+// getKlinesRangeAlpaca fetches K-line series within time range from Alpaca API (no archive fallback)
+func getKlinesRangeAlpaca(symbol string, timeframe string, start, end time.Time) ([]Kline, error) {
 	// Normalize symbol - for stocks, just uppercase and remove any suffixes
 	symbol = strings.ToUpper(strings.TrimSuffix(symbol, "USDT"))
 	symbol = strings.TrimSuffix(symbol, "USD")
@@ -226,6 +238,66 @@ func getTimeframeDurationMs(tf string) int64 {
 	}
 }
 
+// ============================================================================
+// Binance Public Klines (crypto)
+// ============================================================================
+
+const (
+	binanceKlinesURL     = "https://api.binance.com/api/v3/klines"
+	binanceMaxKlineLimit = 1000
+)
+
+// GetBinanceKlinesRange fetches K-line series within time range from Binance public API (no credentials needed).
+// Returns data sorted by time in ascending order.
+func GetBinanceKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kline, error) {
+	tf, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+	symbol = strings.ToUpper(symbol)
+
+	var all []Kline
+	client := &http.Client{Timeout: 30 * time.Second}
+	from := start.UnixMilli()
+	for from < end.UnixMilli() {
+		url := fmt.Sprintf("%s?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=%d",
+			binanceKlinesURL, symbol, tf, from, end.UnixMilli(), binanceMaxKlineLimit)
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("Binance API request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Binance API returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var rows []KlineResponse
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse Binance response: %w", err)
+		}
+		for _, row := range rows {
+			kline, err := parseKline(row)
+			if err != nil {
+				continue
+			}
+			all = append(all, kline)
+		}
+
+		if len(rows) < binanceMaxKlineLimit || len(all) == 0 {
+			break
+		}
+		from = all[len(all)-1].OpenTime + 1
+	}
+	return all, nil
+}
+
 // ============================================================================
 // Widesurf / Polygon-compatible Data Fetcher
 // ============================================================================
@@ -0,0 +1,219 @@
+package market
+
+import (
+	"SynapseStrike/logger"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// OHLCV Integrity Validation
+// ============================================================================
+// Bars from the Binance WS cache or Alpaca can arrive with duplicate open
+// times (a bar re-sent by the stream), out of order, or with holes (missed
+// stream messages, API pagination glitches). Before indicators are computed
+// every series is sorted, de-duplicated and checked for gaps; gaps of up to
+// maxRepairGapBars bars are re-fetched from the source. What remains is
+// summarized as a 0-1 quality score on market.Data so the prompt and the
+// candidate filter can discount unreliable data.
+//
+// Stock sessions have natural gaps (nights, weekends, holidays), so for
+// stocks only gaps inside one New York trading day are counted.
+
+const (
+	maxRepairGapBars   = 5 // Longer gaps are reported, not re-fetched
+	maxRepairsPerCheck = 3 // Re-fetch requests per series and check
+)
+
+// klineGapFetcher source for re-fetching missing bars (replaced in tests)
+var klineGapFetcher = fetchArchiveSource
+
+// KlineQuality integrity result of one kline series
+type KlineQuality struct {
+	Timeframe  string  `json:"timeframe"`
+	Bars       int     `json:"bars"`
+	Missing    int     `json:"missing"`      // Bars still missing inside the series
+	Duplicates int     `json:"duplicates"`   // Bars with an already seen open time (dropped)
+	OutOfOrder int     `json:"out_of_order"` // Bars received after a later bar (re-sorted)
+	Invalid    int     `json:"invalid"`      // Bars with inconsistent OHLC or negative volume
+	Repaired   int     `json:"repaired"`     // Missing bars filled by re-fetching
+	Score      float64 `json:"score"`        // 1 = clean, 0 = unusable
+}
+
+// DataQuality integrity of the kline series behind a market.Data
+type DataQuality struct {
+	Score      float64                  `json:"score"` // Lowest timeframe score
+	Timeframes map[string]*KlineQuality `json:"timeframes"`
+}
+
+// Add records the quality of one timeframe
+func (q *DataQuality) Add(kq *KlineQuality) {
+	if q.Timeframes == nil {
+		q.Timeframes = make(map[string]*KlineQuality)
+		q.Score = 1
+	}
+	q.Timeframes[kq.Timeframe] = kq
+	q.Score = math.Min(q.Score, kq.Score)
+}
+
+// Summary one-line description of the problems found (empty when clean)
+func (q *DataQuality) Summary() string {
+	if q == nil || q.Score >= 1 {
+		return ""
+	}
+	timeframes := make([]string, 0, len(q.Timeframes))
+	for tf := range q.Timeframes {
+		timeframes = append(timeframes, tf)
+	}
+	sort.Strings(timeframes)
+
+	var parts []string
+	for _, tf := range timeframes {
+		kq := q.Timeframes[tf]
+		var issues []string
+		if kq.Missing > 0 {
+			issues = append(issues, fmt.Sprintf("%d missing", kq.Missing))
+		}
+		if kq.Duplicates > 0 {
+			issues = append(issues, fmt.Sprintf("%d duplicate", kq.Duplicates))
+		}
+		if kq.OutOfOrder > 0 {
+			issues = append(issues, fmt.Sprintf("%d out of order", kq.OutOfOrder))
+		}
+		if kq.Invalid > 0 {
+			issues = append(issues, fmt.Sprintf("%d invalid", kq.Invalid))
+		}
+		if len(issues) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", tf, strings.Join(issues, ", ")))
+		}
+	}
+	return fmt.Sprintf("%.2f (%s)", q.Score, strings.Join(parts, "; "))
+}
+
+// ValidateKlines sorts and de-duplicates bars and reports the gaps inside the series
+// For a duplicate open time the later received bar wins (the stream re-sends updated bars).
+func ValidateKlines(symbol, timeframe string, klines []Kline) ([]Kline, *KlineQuality, []KlineGap) {
+	quality := &KlineQuality{Timeframe: timeframe}
+	if len(klines) == 0 {
+		quality.Score = 0
+		return klines, quality, nil
+	}
+
+	latest := int64(math.MinInt64)
+	for _, k := range klines {
+		if k.OpenTime < latest {
+			quality.OutOfOrder++
+		}
+		latest = max(latest, k.OpenTime)
+	}
+
+	byOpenTime := make(map[int64]int, len(klines))
+	cleaned := make([]Kline, 0, len(klines))
+	for _, k := range klines {
+		if idx, seen := byOpenTime[k.OpenTime]; seen {
+			quality.Duplicates++
+			cleaned[idx] = k
+			continue
+		}
+		byOpenTime[k.OpenTime] = len(cleaned)
+		cleaned = append(cleaned, k)
+	}
+	sort.SliceStable(cleaned, func(i, j int) bool { return cleaned[i].OpenTime < cleaned[j].OpenTime })
+
+	var gaps []KlineGap
+	dur, err := TFDuration(timeframe)
+	durMs := dur.Milliseconds()
+	stock := IsStock(symbol)
+	for i, k := range cleaned {
+		if k.High < math.Max(k.Open, k.Close) || k.Low > math.Min(k.Open, k.Close) || k.Low <= 0 || k.Volume < 0 {
+			quality.Invalid++
+		}
+		if i == 0 || err != nil || durMs <= 0 {
+			continue
+		}
+		prev := cleaned[i-1]
+		if stock && !sameTradingDay(prev.OpenTime, k.OpenTime) {
+			continue
+		}
+		if missing := int((k.OpenTime-prev.OpenTime)/durMs) - 1; missing > 0 {
+			gaps = append(gaps, KlineGap{
+				From:    time.UnixMilli(prev.OpenTime + durMs),
+				To:      time.UnixMilli(k.OpenTime - durMs),
+				Missing: missing,
+			})
+			quality.Missing += missing
+		}
+	}
+
+	quality.Bars = len(cleaned)
+	quality.Score = klineQualityScore(quality)
+	return cleaned, quality, gaps
+}
+
+// RepairKlines validates bars and re-fetches small gaps from the source
+func RepairKlines(symbol, timeframe string, klines []Kline) ([]Kline, *KlineQuality) {
+	cleaned, quality, gaps := ValidateKlines(symbol, timeframe, klines)
+	if len(gaps) == 0 {
+		return cleaned, quality
+	}
+	dur, err := TFDuration(timeframe)
+	if err != nil {
+		return cleaned, quality
+	}
+
+	var fetched []Kline
+	attempts := 0
+	for _, gap := range gaps {
+		if gap.Missing > maxRepairGapBars || attempts >= maxRepairsPerCheck {
+			continue
+		}
+		attempts++
+		bars, err := klineGapFetcher(symbol, timeframe, gap.From, gap.To.Add(dur))
+		if err != nil {
+			logger.Infof("⚠️ %s %s gap repair failed (%d bars from %s): %v",
+				symbol, timeframe, gap.Missing, gap.From.UTC().Format(time.RFC3339), err)
+			continue
+		}
+		for _, bar := range bars {
+			if bar.OpenTime >= gap.From.UnixMilli() && bar.OpenTime <= gap.To.UnixMilli() {
+				fetched = append(fetched, bar)
+			}
+		}
+	}
+	if len(fetched) == 0 {
+		return cleaned, quality
+	}
+
+	// Re-validate with the fetched bars; duplicates/ordering of the original series are kept
+	repaired, after, _ := ValidateKlines(symbol, timeframe, append(cleaned, fetched...))
+	quality.Repaired = quality.Missing - after.Missing
+	quality.Missing = after.Missing
+	quality.Bars = after.Bars
+	quality.Invalid = after.Invalid
+	quality.Score = klineQualityScore(quality)
+	logger.Infof("🩹 %s %s repaired %d missing bars (%d still missing)", symbol, timeframe, quality.Repaired, quality.Missing)
+	return repaired, quality
+}
+
+// klineQualityScore share of expected bars without a problem
+func klineQualityScore(q *KlineQuality) float64 {
+	expected := q.Bars + q.Missing
+	if expected == 0 {
+		return 0
+	}
+	issues := q.Missing + q.Duplicates + q.OutOfOrder + q.Invalid
+	return math.Max(0, 1-float64(issues)/float64(expected))
+}
+
+// sameTradingDay whether two bar open times fall on the same New York calendar day
+func sameTradingDay(a, b int64) bool {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	ta, tb := time.UnixMilli(a).In(loc), time.UnixMilli(b).In(loc)
+	return ta.YearDay() == tb.YearDay() && ta.Year() == tb.Year()
+}
@@ -0,0 +1,107 @@
+package market
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testBar 1m bar opening at base + minute
+func testBar(base time.Time, minute int, close float64) Kline {
+	ts := base.Add(time.Duration(minute) * time.Minute)
+	return Kline{OpenTime: ts.UnixMilli(), CloseTime: ts.Add(time.Minute).UnixMilli() - 1,
+		Open: close, High: close + 1, Low: close - 1, Close: close, Volume: 10}
+}
+
+// TestValidateKlines tests sorting, de-duplication and gap detection
+func TestValidateKlines(t *testing.T) {
+	base := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	klines := []Kline{
+		testBar(base, 0, 100),
+		testBar(base, 2, 102),
+		testBar(base, 1, 101), // Out of order
+		testBar(base, 2, 103), // Re-sent with updated close
+		testBar(base, 6, 106), // Bars 3-5 missing
+	}
+
+	cleaned, quality, gaps := ValidateKlines("BTCUSDT", "1m", klines)
+	if len(cleaned) != 4 || quality.Duplicates != 1 || quality.OutOfOrder != 1 || quality.Missing != 3 {
+		t.Fatalf("quality = %+v (bars %d), want 4 bars, 1 duplicate, 1 out of order, 3 missing", quality, len(cleaned))
+	}
+	for i := 1; i < len(cleaned); i++ {
+		if cleaned[i].OpenTime <= cleaned[i-1].OpenTime {
+			t.Fatalf("bars not sorted at %d", i)
+		}
+	}
+	if cleaned[2].Close != 103 {
+		t.Errorf("duplicate should keep the later bar, close = %.0f", cleaned[2].Close)
+	}
+	if len(gaps) != 1 || gaps[0].Missing != 3 || !gaps[0].From.Equal(base.Add(3*time.Minute)) {
+		t.Errorf("gaps = %+v, want bars 3-5", gaps)
+	}
+	if quality.Score <= 0 || quality.Score >= 1 {
+		t.Errorf("score = %.2f, want between 0 and 1", quality.Score)
+	}
+}
+
+// TestValidateKlines_StockSessionGap tests that overnight stock gaps are not counted as missing
+func TestValidateKlines_StockSessionGap(t *testing.T) {
+	close1 := time.Date(2024, 1, 2, 20, 55, 0, 0, time.UTC) // 15:55 ET
+	open2 := time.Date(2024, 1, 3, 14, 30, 0, 0, time.UTC)  // 09:30 ET next day
+	klines := []Kline{testBar(close1, 0, 100), testBar(open2, 0, 101)}
+
+	_, quality, gaps := ValidateKlines("AAPL", "5m", klines)
+	if len(gaps) != 0 || quality.Score != 1 {
+		t.Errorf("overnight gap should be ignored for stocks, got %+v", quality)
+	}
+}
+
+// TestRepairKlines tests that small gaps are re-fetched and large ones only reported
+func TestRepairKlines(t *testing.T) {
+	base := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	original := klineGapFetcher
+	t.Cleanup(func() { klineGapFetcher = original })
+	requests := 0
+	klineGapFetcher = func(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+		requests++
+		var bars []Kline
+		for ts := start; ts.Before(end); ts = ts.Add(time.Minute) {
+			bars = append(bars, testBar(ts, 0, 100))
+		}
+		return bars, nil
+	}
+
+	klines := []Kline{testBar(base, 0, 100), testBar(base, 3, 100), testBar(base, 20, 100)}
+	repaired, quality := RepairKlines("BTCUSDT", "1m", klines)
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 (16-bar gap is too long to repair)", requests)
+	}
+	if quality.Repaired != 2 || quality.Missing != 16 || len(repaired) != 5 {
+		t.Errorf("quality = %+v (bars %d), want 2 repaired, 16 missing, 5 bars", quality, len(repaired))
+	}
+
+	klineGapFetcher = func(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+		return nil, errors.New("api down")
+	}
+	_, quality = RepairKlines("BTCUSDT", "1m", klines[:2])
+	if quality.Repaired != 0 || quality.Missing != 2 {
+		t.Errorf("failed repair should keep the gap, got %+v", quality)
+	}
+}
+
+// TestDataQualitySummary tests the lowest timeframe score and the problem summary
+func TestDataQualitySummary(t *testing.T) {
+	quality := &DataQuality{}
+	quality.Add(&KlineQuality{Timeframe: "5m", Bars: 30, Score: 1})
+	quality.Add(&KlineQuality{Timeframe: "1h", Bars: 18, Missing: 2, Score: 0.9})
+	if quality.Score != 0.9 {
+		t.Errorf("score = %.2f, want lowest timeframe score 0.9", quality.Score)
+	}
+	if got, want := quality.Summary(), "0.90 (1h: 2 missing)"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	var none *DataQuality
+	if none.Summary() != "" {
+		t.Error("nil quality should have an empty summary")
+	}
+}
@@ -0,0 +1,271 @@
+package market
+
+import (
+	"SynapseStrike/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Exchange Maintenance Windows
+// ============================================================================
+// Exchanges take trading offline for scheduled maintenance; orders sent in
+// that time fail with errors that look like anything but maintenance. Known
+// windows come from two sources:
+//   - static windows from a JSON file (MAINTENANCE_WINDOWS_PATH, default
+//     data/maintenance_windows.json), e.g. for venues without a status API
+//   - the public status pages of Bybit and OKX, polled lazily per exchange
+//     and cached for the refresh interval (MAINTENANCE_POLL_MINUTES, default
+//     10, 0 = static windows only)
+// Traders ask for the window active on their exchange before each cycle and
+// each order (see trader/maintenance.go).
+
+const (
+	MaintenanceSourceConfig     = "config"
+	MaintenanceSourceStatusPage = "status_page"
+
+	bybitSystemStatusURL = "https://api.bybit.com/v5/system/status"
+	okxSystemStatusURL   = "https://www.okx.com/api/v5/system/status"
+)
+
+// MaintenanceWindow scheduled downtime of an exchange
+type MaintenanceWindow struct {
+	Exchange string    `json:"exchange"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Reason   string    `json:"reason,omitempty"`
+	Source   string    `json:"source,omitempty"` // MaintenanceSource* constant
+}
+
+// Active checks whether t lies inside the window widened by buffer on both sides
+func (w MaintenanceWindow) Active(t time.Time, buffer time.Duration) bool {
+	return !t.Before(w.Start.Add(-buffer)) && !t.After(w.End.Add(buffer))
+}
+
+// String formats the window for logs and prompts
+func (w MaintenanceWindow) String() string {
+	s := fmt.Sprintf("%s maintenance %s - %s UTC", w.Exchange, w.Start.UTC().Format("2006-01-02 15:04"), w.End.UTC().Format("15:04"))
+	if w.Reason != "" {
+		s += " (" + w.Reason + ")"
+	}
+	return s
+}
+
+// MaintenanceFetcher fetches scheduled and ongoing maintenance windows of one exchange
+type MaintenanceFetcher func() ([]MaintenanceWindow, error)
+
+// MaintenanceCalendar static and polled maintenance windows per exchange
+type MaintenanceCalendar struct {
+	mu       sync.RWMutex
+	static   []MaintenanceWindow
+	polled   map[string]polledMaintenance
+	refresh  time.Duration
+	fetchers map[string]MaintenanceFetcher
+}
+
+type polledMaintenance struct {
+	windows   []MaintenanceWindow
+	fetchedAt time.Time
+}
+
+// Maintenance default maintenance calendar used by traders (status pages are polled once Configure sets a refresh interval)
+var Maintenance = NewMaintenanceCalendar(0, map[string]MaintenanceFetcher{
+	"bybit": fetchBybitMaintenance,
+	"okx":   fetchOKXMaintenance,
+})
+
+// NewMaintenanceCalendar creates a calendar polling the status pages of fetchers' exchanges
+func NewMaintenanceCalendar(refresh time.Duration, fetchers map[string]MaintenanceFetcher) *MaintenanceCalendar {
+	return &MaintenanceCalendar{
+		polled:   make(map[string]polledMaintenance),
+		refresh:  refresh,
+		fetchers: fetchers,
+	}
+}
+
+// Configure replaces static windows and the polling interval (refresh <= 0 disables polling)
+func (c *MaintenanceCalendar) Configure(static []MaintenanceWindow, refresh time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.static = static
+	c.refresh = refresh
+	c.polled = make(map[string]polledMaintenance)
+}
+
+// Windows known maintenance windows of exchange, static first
+// Status pages are polled when the cached result is older than the refresh interval;
+// if polling fails the stale result is used.
+func (c *MaintenanceCalendar) Windows(exchange string) []MaintenanceWindow {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	c.mu.RLock()
+	var windows []MaintenanceWindow
+	for _, w := range c.static {
+		if w.Exchange == exchange {
+			windows = append(windows, w)
+		}
+	}
+	cached, isCached := c.polled[exchange]
+	refresh, fetch := c.refresh, c.fetchers[exchange]
+	c.mu.RUnlock()
+
+	if refresh <= 0 || fetch == nil {
+		return windows
+	}
+	if !isCached || time.Since(cached.fetchedAt) >= refresh {
+		polled, err := fetch()
+		if err != nil {
+			// Keep the stale result, retry after the next refresh interval
+			logger.Warnf("⚠️ [Maintenance] Failed to poll %s status page: %v", exchange, err)
+			polled = cached.windows
+		}
+		cached = polledMaintenance{windows: polled, fetchedAt: time.Now()}
+		c.mu.Lock()
+		c.polled[exchange] = cached
+		c.mu.Unlock()
+	}
+	return append(windows, cached.windows...)
+}
+
+// ActiveWindow maintenance window of exchange active at t (widened by buffer), nil if none
+func (c *MaintenanceCalendar) ActiveWindow(exchange string, t time.Time, buffer time.Duration) *MaintenanceWindow {
+	for _, w := range c.Windows(exchange) {
+		if w.Active(t, buffer) {
+			return &w
+		}
+	}
+	return nil
+}
+
+// LoadMaintenanceWindows reads static maintenance windows from a JSON file
+// A missing file is not an error.
+func LoadMaintenanceWindows(path string) ([]MaintenanceWindow, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var windows []MaintenanceWindow
+	if err := json.Unmarshal(data, &windows); err != nil {
+		return nil, fmt.Errorf("invalid maintenance windows %s: %w", path, err)
+	}
+	for i := range windows {
+		w := &windows[i]
+		w.Exchange = strings.ToLower(strings.TrimSpace(w.Exchange))
+		if w.Exchange == "" {
+			return nil, fmt.Errorf("maintenance window #%d: exchange is required", i+1)
+		}
+		if w.Start.IsZero() || !w.End.After(w.Start) {
+			return nil, fmt.Errorf("maintenance window #%d (%s): end must be after start", i+1, w.Exchange)
+		}
+		w.Source = MaintenanceSourceConfig
+	}
+	return windows, nil
+}
+
+// fetchBybitMaintenance scheduled and ongoing Bybit maintenance of trading services
+func fetchBybitMaintenance() ([]MaintenanceWindow, error) {
+	var resp struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				Title        string `json:"title"`
+				State        string `json:"state"`
+				Begin        string `json:"begin"`
+				End          string `json:"end"`
+				ServiceTypes []int  `json:"serviceTypes"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := getStatusPage(bybitSystemStatusURL, &resp); err != nil {
+		return nil, err
+	}
+	if resp.RetCode != 0 {
+		return nil, fmt.Errorf("bybit system status: %s", resp.RetMsg)
+	}
+	var windows []MaintenanceWindow
+	for _, m := range resp.Result.List {
+		if m.State != "scheduled" && m.State != "ongoing" {
+			continue
+		}
+		// 1 = trading service, 2 = HTTP trading service (market data and private streams don't block orders)
+		if len(m.ServiceTypes) > 0 && !slices.Contains(m.ServiceTypes, 1) && !slices.Contains(m.ServiceTypes, 2) {
+			continue
+		}
+		if w, ok := statusPageWindow("bybit", m.Title, m.Begin, m.End); ok {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+// fetchOKXMaintenance scheduled, ongoing and pre-open OKX maintenance of trading services
+func fetchOKXMaintenance() ([]MaintenanceWindow, error) {
+	var resp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Title       string `json:"title"`
+			State       string `json:"state"`
+			Begin       string `json:"begin"`
+			End         string `json:"end"`
+			ServiceType string `json:"serviceType"`
+		} `json:"data"`
+	}
+	if err := getStatusPage(okxSystemStatusURL, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("okx system status: %s", resp.Msg)
+	}
+	// Block trading, trading bots, spread trading and copy trading don't affect API orders
+	unrelated := map[string]bool{"6": true, "7": true, "10": true, "11": true}
+	var windows []MaintenanceWindow
+	for _, m := range resp.Data {
+		if m.State != "scheduled" && m.State != "ongoing" && m.State != "pre_open" || unrelated[m.ServiceType] {
+			continue
+		}
+		if w, ok := statusPageWindow("okx", m.Title, m.Begin, m.End); ok {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+// statusPageWindow builds a window from millisecond timestamps of a status page
+func statusPageWindow(exchange, title, begin, end string) (MaintenanceWindow, bool) {
+	beginMs, err1 := strconv.ParseInt(begin, 10, 64)
+	endMs, err2 := strconv.ParseInt(end, 10, 64)
+	if err1 != nil || err2 != nil || endMs <= beginMs {
+		return MaintenanceWindow{}, false
+	}
+	return MaintenanceWindow{
+		Exchange: exchange,
+		Start:    time.UnixMilli(beginMs).UTC(),
+		End:      time.UnixMilli(endMs).UTC(),
+		Reason:   title,
+		Source:   MaintenanceSourceStatusPage,
+	}, true
+}
+
+func getStatusPage(url string, out interface{}) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,83 @@
+package market
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceCalendar_ActiveWindow(t *testing.T) {
+	now := time.Date(2026, 5, 6, 2, 0, 0, 0, time.UTC)
+	fetches := 0
+	fail := false
+	calendar := NewMaintenanceCalendar(time.Hour, map[string]MaintenanceFetcher{
+		"bybit": func() ([]MaintenanceWindow, error) {
+			fetches++
+			if fail {
+				return nil, errors.New("status page down")
+			}
+			return []MaintenanceWindow{{Exchange: "bybit", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Reason: "upgrade"}}, nil
+		},
+	})
+	calendar.Configure([]MaintenanceWindow{{Exchange: "okx", Start: now.Add(-time.Minute), End: now.Add(time.Minute)}}, time.Hour)
+
+	if w := calendar.ActiveWindow("OKX", now, 0); w == nil {
+		t.Error("static okx window should be active")
+	}
+	if w := calendar.ActiveWindow("bybit", now, 0); w != nil {
+		t.Errorf("bybit window not started yet, got %v", w)
+	}
+	if w := calendar.ActiveWindow("bybit", now.Add(55*time.Minute), 10*time.Minute); w == nil || w.Reason != "upgrade" {
+		t.Errorf("buffer should widen the polled window, got %v", w)
+	}
+	if w := calendar.ActiveWindow("binance", now, time.Hour); w != nil {
+		t.Errorf("binance has no windows, got %v", w)
+	}
+	// Status page is cached for the refresh interval
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1", fetches)
+	}
+
+	// A failed poll keeps the stale windows
+	calendar.mu.Lock()
+	calendar.polled["bybit"] = polledMaintenance{windows: calendar.polled["bybit"].windows, fetchedAt: now.Add(-2 * time.Hour)}
+	calendar.mu.Unlock()
+	fail = true
+	if got := calendar.Windows("bybit"); fetches != 2 || len(got) != 1 {
+		t.Errorf("after failed poll: %d fetches, windows %v, want stale window kept", fetches, got)
+	}
+}
+
+func TestLoadMaintenanceWindows(t *testing.T) {
+	dir := t.TempDir()
+	if windows, err := LoadMaintenanceWindows(filepath.Join(dir, "missing.json")); err != nil || windows != nil {
+		t.Errorf("missing file: %v, %v", windows, err)
+	}
+
+	path := filepath.Join(dir, "windows.json")
+	os.WriteFile(path, []byte(`[{"exchange": " Bybit ", "start": "2026-05-06T02:00:00Z", "end": "2026-05-06T03:00:00Z", "reason": "wallet upgrade"}]`), 0o644)
+	windows, err := LoadMaintenanceWindows(path)
+	if err != nil || len(windows) != 1 {
+		t.Fatalf("LoadMaintenanceWindows() = %v, %v", windows, err)
+	}
+	if windows[0].Exchange != "bybit" || windows[0].Source != MaintenanceSourceConfig {
+		t.Errorf("window = %+v, want normalized bybit config window", windows[0])
+	}
+
+	os.WriteFile(path, []byte(`[{"exchange": "okx", "start": "2026-05-06T03:00:00Z", "end": "2026-05-06T02:00:00Z"}]`), 0o644)
+	if _, err := LoadMaintenanceWindows(path); err == nil {
+		t.Error("expected error for window ending before it starts")
+	}
+}
+
+func TestStatusPageWindow(t *testing.T) {
+	w, ok := statusPageWindow("okx", "Trading system upgrade", "1778032800000", "1778036400000")
+	if !ok || !w.Start.Equal(time.UnixMilli(1778032800000)) || w.End.Sub(w.Start) != time.Hour || w.Source != MaintenanceSourceStatusPage {
+		t.Errorf("statusPageWindow() = %+v, %v", w, ok)
+	}
+	if _, ok := statusPageWindow("okx", "", "", "1778036400000"); ok {
+		t.Error("expected missing begin to be rejected")
+	}
+}
@@ -0,0 +1,186 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Order Book Snapshots
+// ============================================================================
+// Top N levels of the crypto futures order book, from the Binance depth
+// endpoint with Bybit as fallback. Besides the levels a snapshot carries the
+// spread and the bid/ask imbalance ratio (bid notional / ask notional of the
+// fetched levels: > 1 = more resting buy interest). Snapshots are cached for
+// orderBookCacheTTL so the prompt and the smart limit pricer share one fetch.
+
+const (
+	DefaultOrderBookLevels = 20
+	orderBookCacheTTL      = 5 * time.Second
+	binanceDepthURL        = "https://fapi.binance.com/fapi/v1/depth?symbol=%s&limit=%d"
+	bybitOrderBookURL      = "https://api.bybit.com/v5/market/orderbook?category=linear&symbol=%s&limit=%d"
+)
+
+// OrderBookLevel one price level of the order book
+type OrderBookLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"` // Base asset quantity
+}
+
+// OrderBook order book snapshot with spread and imbalance
+type OrderBook struct {
+	Source         string           `json:"source"` // binance/bybit
+	Bids           []OrderBookLevel `json:"bids"`   // Best bid first
+	Asks           []OrderBookLevel `json:"asks"`   // Best ask first
+	BestBid        float64          `json:"best_bid"`
+	BestAsk        float64          `json:"best_ask"`
+	SpreadPct      float64          `json:"spread_pct"` // (ask - bid) / mid × 100
+	BidNotional    float64          `json:"bid_notional"`
+	AskNotional    float64          `json:"ask_notional"`
+	ImbalanceRatio float64          `json:"imbalance_ratio"` // Bid notional / ask notional (0 = no asks)
+	Time           time.Time        `json:"time"`
+}
+
+var orderBookCache sync.Map // map[string]*OrderBook (symbol|levels)
+
+// GetOrderBook top levels of the crypto futures order book of symbol (Binance, Bybit fallback)
+func GetOrderBook(symbol string, levels int) (*OrderBook, error) {
+	if levels <= 0 {
+		levels = DefaultOrderBookLevels
+	}
+	key := fmt.Sprintf("%s|%d", symbol, levels)
+	if cached, ok := orderBookCache.Load(key); ok {
+		if book := cached.(*OrderBook); time.Since(book.Time) < orderBookCacheTTL {
+			return book, nil
+		}
+	}
+
+	book, err := fetchBinanceOrderBook(symbol, levels)
+	if err != nil {
+		var bybitErr error
+		if book, bybitErr = fetchBybitOrderBook(symbol, levels); bybitErr != nil {
+			return nil, fmt.Errorf("binance: %v, bybit: %v", err, bybitErr)
+		}
+	}
+	orderBookCache.Store(key, book)
+	return book, nil
+}
+
+// binanceDepthLimit smallest depth limit supported by Binance that covers levels
+func binanceDepthLimit(levels int) int {
+	for _, limit := range []int{5, 10, 20, 50, 100, 500, 1000} {
+		if levels <= limit {
+			return limit
+		}
+	}
+	return 1000
+}
+
+func fetchBinanceOrderBook(symbol string, levels int) (*OrderBook, error) {
+	body, err := getOrderBookBody(fmt.Sprintf(binanceDepthURL, symbol, binanceDepthLimit(levels)))
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Bids [][]string `json:"bids"`
+		Asks [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return NewOrderBook("binance", parseOrderBookLevels(result.Bids), parseOrderBookLevels(result.Asks), levels)
+}
+
+func fetchBybitOrderBook(symbol string, levels int) (*OrderBook, error) {
+	body, err := getOrderBookBody(fmt.Sprintf(bybitOrderBookURL, symbol, min(levels, 200)))
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			Bids [][]string `json:"b"`
+			Asks [][]string `json:"a"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("bybit order book error %d: %s", result.RetCode, result.RetMsg)
+	}
+	return NewOrderBook("bybit", parseOrderBookLevels(result.Result.Bids), parseOrderBookLevels(result.Result.Asks), levels)
+}
+
+// getOrderBookBody GET request for order book endpoints
+func getOrderBookBody(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("order book request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// parseOrderBookLevels parses [price, size] string pairs, skips malformed levels
+func parseOrderBookLevels(raw [][]string) []OrderBookLevel {
+	levels := make([]OrderBookLevel, 0, len(raw))
+	for _, level := range raw {
+		if len(level) < 2 {
+			continue
+		}
+		price, err1 := strconv.ParseFloat(level[0], 64)
+		size, err2 := strconv.ParseFloat(level[1], 64)
+		if err1 != nil || err2 != nil || price <= 0 {
+			continue
+		}
+		levels = append(levels, OrderBookLevel{Price: price, Size: size})
+	}
+	return levels
+}
+
+// NewOrderBook snapshot of the first levels of bids and asks (best first) with spread and imbalance
+func NewOrderBook(source string, bids, asks []OrderBookLevel, levels int) (*OrderBook, error) {
+	if len(bids) == 0 || len(asks) == 0 {
+		return nil, fmt.Errorf("empty order book")
+	}
+	if levels > 0 {
+		bids = bids[:min(levels, len(bids))]
+		asks = asks[:min(levels, len(asks))]
+	}
+
+	book := &OrderBook{
+		Source:  source,
+		Bids:    bids,
+		Asks:    asks,
+		BestBid: bids[0].Price,
+		BestAsk: asks[0].Price,
+		Time:    time.Now(),
+	}
+	if mid := (book.BestBid + book.BestAsk) / 2; mid > 0 {
+		book.SpreadPct = (book.BestAsk - book.BestBid) / mid * 100
+	}
+	for _, level := range bids {
+		book.BidNotional += level.Price * level.Size
+	}
+	for _, level := range asks {
+		book.AskNotional += level.Price * level.Size
+	}
+	if book.AskNotional > 0 {
+		book.ImbalanceRatio = book.BidNotional / book.AskNotional
+	}
+	return book, nil
+}
@@ -0,0 +1,32 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewOrderBook(t *testing.T) {
+	bids := parseOrderBookLevels([][]string{{"100.0", "3"}, {"99.5", "2"}, {"99.0", "50"}, {"bad", "1"}})
+	asks := parseOrderBookLevels([][]string{{"100.2", "1"}, {"100.5", "1"}})
+	book, err := NewOrderBook("binance", bids, asks, 2)
+	if err != nil {
+		t.Fatalf("NewOrderBook failed: %v", err)
+	}
+	if book.BestBid != 100 || book.BestAsk != 100.2 || len(book.Bids) != 2 {
+		t.Errorf("book = %+v, want best 100 / 100.2 and 2 bid levels", book)
+	}
+	if math.Abs(book.SpreadPct-0.1998) > 0.001 {
+		t.Errorf("spread = %.4f%%, want ~0.1998%%", book.SpreadPct)
+	}
+	// (300 + 199) / (100.2 + 100.5)
+	if math.Abs(book.ImbalanceRatio-499/200.7) > 1e-9 {
+		t.Errorf("imbalance = %.4f, want %.4f", book.ImbalanceRatio, 499/200.7)
+	}
+
+	if _, err := NewOrderBook("bybit", bids, nil, 5); err == nil {
+		t.Error("one-sided book should be rejected")
+	}
+	if binanceDepthLimit(7) != 10 || binanceDepthLimit(2000) != 1000 {
+		t.Error("depth limit should round up to a supported Binance limit")
+	}
+}
@@ -0,0 +1,186 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Quote Currencies
+// ============================================================================
+// Exchanges report balances in their collateral currency: USDT futures, USDC
+// on Hyperliquid/dYdX/Lighter, EUR on euro-denominated stock accounts. To keep
+// equity comparable across accounts, balances are converted into the
+// reporting currency (USD) through reference rates:
+//   - USD and USDT are the anchor (rate 1)
+//   - static rates from QUOTE_RATES (e.g. "EUR=1.08,USDC=1") always win
+//   - other currencies use the Binance spot <CUR>USDT price, cached for the
+//     refresh interval (QUOTE_RATE_REFRESH_MINUTES, default 15)
+// Prices are not converted: market data and orders stay in the instrument's
+// own quote, and position sizes decided by the AI are in USD.
+
+const (
+	ReportingCurrency         = "USD"
+	defaultQuoteRateRefresh   = 15 * time.Minute
+	binanceSpotTickerPriceURL = "https://api.binance.com/api/v3/ticker/price?symbol="
+)
+
+// QuoteRateFeed reference rates of quote currencies in the reporting currency (USD per unit)
+type QuoteRateFeed struct {
+	mu      sync.RWMutex
+	static  map[string]float64
+	live    map[string]quoteRate
+	refresh time.Duration
+	fetch   func(currency string) (float64, error)
+}
+
+type quoteRate struct {
+	rate      float64
+	fetchedAt time.Time
+}
+
+// QuoteRates default quote rate feed used by trader and API packages
+var QuoteRates = NewQuoteRateFeed(nil, defaultQuoteRateRefresh, fetchBinanceQuoteRate)
+
+// NewQuoteRateFeed creates a rate feed with static rates and a live fetcher (nil fetch = static rates only)
+func NewQuoteRateFeed(static map[string]float64, refresh time.Duration, fetch func(currency string) (float64, error)) *QuoteRateFeed {
+	f := &QuoteRateFeed{
+		static:  make(map[string]float64),
+		live:    make(map[string]quoteRate),
+		refresh: refresh,
+		fetch:   fetch,
+	}
+	f.Configure(static, refresh)
+	return f
+}
+
+// Configure replaces static rates and refresh interval (refresh <= 0 disables the live feed)
+func (f *QuoteRateFeed) Configure(static map[string]float64, refresh time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.static = make(map[string]float64, len(static))
+	for currency, rate := range static {
+		f.static[NormalizeQuoteCurrency(currency)] = rate
+	}
+	f.refresh = refresh
+	f.live = make(map[string]quoteRate)
+}
+
+// Rate USD value of one unit of currency
+// A stale live rate is still returned if refreshing it fails.
+func (f *QuoteRateFeed) Rate(currency string) (float64, error) {
+	currency = NormalizeQuoteCurrency(currency)
+	if currency == ReportingCurrency || currency == "USDT" {
+		return 1, nil
+	}
+
+	f.mu.RLock()
+	rate, isStatic := f.static[currency]
+	cached, isCached := f.live[currency]
+	refresh, fetch := f.refresh, f.fetch
+	f.mu.RUnlock()
+
+	if isStatic {
+		return rate, nil
+	}
+	if refresh <= 0 || fetch == nil {
+		return 0, fmt.Errorf("no reference rate for %s (set QUOTE_RATES)", currency)
+	}
+	if isCached && time.Since(cached.fetchedAt) < refresh {
+		return cached.rate, nil
+	}
+
+	rate, err := fetch(currency)
+	if err != nil || rate <= 0 {
+		if isCached {
+			return cached.rate, nil
+		}
+		return 0, fmt.Errorf("failed to fetch reference rate for %s: %v", currency, err)
+	}
+	f.mu.Lock()
+	f.live[currency] = quoteRate{rate: rate, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	return rate, nil
+}
+
+// ToUSD converts amount in currency into the reporting currency
+func (f *QuoteRateFeed) ToUSD(amount float64, currency string) (float64, error) {
+	rate, err := f.Rate(currency)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// NormalizeQuoteCurrency upper-cased currency code, empty = reporting currency
+func NormalizeQuoteCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return ReportingCurrency
+	}
+	return currency
+}
+
+// IsValidQuoteCurrency reports whether currency is empty (exchange default) or a 3-5 letter code
+func IsValidQuoteCurrency(currency string) bool {
+	currency = strings.TrimSpace(currency)
+	if currency == "" {
+		return true
+	}
+	if len(currency) < 3 || len(currency) > 5 {
+		return false
+	}
+	for _, r := range currency {
+		if !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseQuoteRates parses static rates in "EUR=1.08,USDC=1" format
+func ParseQuoteRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		currency, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid quote rate %q (expected CUR=rate)", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid quote rate %q: must be a positive number", pair)
+		}
+		rates[NormalizeQuoteCurrency(currency)] = rate
+	}
+	return rates, nil
+}
+
+// fetchBinanceQuoteRate fetches <currency>USDT spot price from Binance (USDT taken as USD)
+func fetchBinanceQuoteRate(currency string) (float64, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(binanceSpotTickerPriceURL + currency + "USDT")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("binance ticker %sUSDT: HTTP %d", currency, resp.StatusCode)
+	}
+
+	var ticker struct {
+		Price string `json:"price"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(ticker.Price, 64)
+}
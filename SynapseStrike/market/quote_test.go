@@ -0,0 +1,49 @@
+package market
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuoteRateFeed_Rate(t *testing.T) {
+	fetches := 0
+	feed := NewQuoteRateFeed(map[string]float64{"eur": 1.08}, time.Hour, func(currency string) (float64, error) {
+		fetches++
+		if currency == "USDC" {
+			return 0.9998, nil
+		}
+		return 0, errors.New("unknown currency")
+	})
+
+	tests := map[string]float64{"": 1, "usd": 1, "USDT": 1, "EUR": 1.08, "USDC": 0.9998}
+	for currency, want := range tests {
+		if got, err := feed.Rate(currency); err != nil || got != want {
+			t.Errorf("Rate(%q) = %v, %v, want %v", currency, got, err, want)
+		}
+	}
+	if _, err := feed.Rate("GBP"); err == nil {
+		t.Error("expected error for currency without rate")
+	}
+	// Live rate is cached for the refresh interval
+	feed.Rate("USDC")
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want 2 (USDC once, GBP once)", fetches)
+	}
+
+	if got, _ := feed.ToUSD(100, "EUR"); got != 108 {
+		t.Errorf("ToUSD(100 EUR) = %v, want 108", got)
+	}
+}
+
+func TestParseQuoteRates(t *testing.T) {
+	rates, err := ParseQuoteRates(" eur=1.08, USDC = 1 ,")
+	if err != nil || rates["EUR"] != 1.08 || rates["USDC"] != 1 || len(rates) != 2 {
+		t.Errorf("ParseQuoteRates = %v, %v", rates, err)
+	}
+	for _, invalid := range []string{"EUR", "EUR=abc", "EUR=-1"} {
+		if _, err := ParseQuoteRates(invalid); err == nil {
+			t.Errorf("ParseQuoteRates(%q) expected error", invalid)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package market
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Symbol Registry
+// ============================================================================
+// Canonical symbols are what the rest of the system (AI prompts, decisions,
+// database, market data) uses: Binance USDT-M style for crypto ("BTCUSDT",
+// "1000PEPEUSDT") and plain tickers for stocks ("AAPL"). The registry maps
+// aliases and exchange-specific symbols onto canonical ones and carries
+// per-symbol metadata (asset class, tick size, min quantity).
+
+// AssetClass asset class of a tradable symbol
+type AssetClass string
+
+const (
+	AssetCrypto AssetClass = "crypto"
+	AssetStock  AssetClass = "stock"
+	AssetOption AssetClass = "option"
+)
+
+// SymbolSpec canonical symbol metadata (not to be confused with SymbolInfo from exchange info API)
+type SymbolSpec struct {
+	Symbol          string            // Canonical symbol
+	AssetClass      AssetClass        // crypto/stock/option
+	TickSize        float64           // Minimum price increment (0 = unknown)
+	MinQty          float64           // Minimum order quantity, also used as quantity step (0 = unknown)
+	MinNotional     float64           // Minimum order value in USD, overrides exchange/asset class minimum (0 = unknown)
+	Aliases         []string          // Other names resolving to this symbol (e.g. PEPEUSDT -> 1000PEPEUSDT)
+	ExchangeSymbols map[string]string // Exchange type -> exchange symbol, overrides default conversion
+}
+
+// SymbolRegistry thread-safe registry of canonical symbols
+type SymbolRegistry struct {
+	mu      sync.RWMutex
+	symbols map[string]*SymbolSpec       // canonical -> info
+	aliases map[string]string            // upper-cased alias -> canonical
+	reverse map[string]map[string]string // exchange -> exchange symbol -> canonical
+
+	minNotional map[string]map[AssetClass]float64 // exchange ("" = any) -> asset class -> min order value (USD)
+}
+
+// Symbols default registry used by market, decision and trader packages
+var Symbols = newDefaultSymbolRegistry()
+
+// cryptoQuoteSuffixes quote currencies identifying crypto pairs
+var cryptoQuoteSuffixes = []string{"USDT", "USDC", "BUSD", "BTC", "ETH"}
+
+// reOptionSymbol OCC option symbol, e.g. AAPL240119C00150000
+var reOptionSymbol = regexp.MustCompile(`^[A-Z]{1,6}\d{6}[CP]\d{8}$`)
+
+// NewSymbolRegistry creates an empty symbol registry
+func NewSymbolRegistry() *SymbolRegistry {
+	return &SymbolRegistry{
+		symbols: make(map[string]*SymbolSpec),
+		aliases: make(map[string]string),
+		reverse: make(map[string]map[string]string),
+
+		minNotional: make(map[string]map[AssetClass]float64),
+	}
+}
+
+// Register adds or replaces symbol metadata
+func (r *SymbolRegistry) Register(info SymbolSpec) {
+	info.Symbol = strings.ToUpper(strings.TrimSpace(info.Symbol))
+	if info.Symbol == "" {
+		return
+	}
+	if info.AssetClass == "" {
+		info.AssetClass = guessAssetClass(info.Symbol)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.symbols[info.Symbol] = &info
+	for _, alias := range info.Aliases {
+		r.aliases[strings.ToUpper(alias)] = info.Symbol
+	}
+	for exchange, exchangeSymbol := range info.ExchangeSymbols {
+		if r.reverse[exchange] == nil {
+			r.reverse[exchange] = make(map[string]string)
+		}
+		r.reverse[exchange][strings.ToUpper(exchangeSymbol)] = info.Symbol
+	}
+}
+
+// Resolve returns canonical symbol (upper-cased, aliases resolved)
+func (r *SymbolRegistry) Resolve(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if canonical, ok := r.aliases[symbol]; ok {
+		return canonical
+	}
+	return symbol
+}
+
+// Lookup gets registered metadata for symbol (aliases resolved)
+func (r *SymbolRegistry) Lookup(symbol string) (*SymbolSpec, bool) {
+	canonical := r.Resolve(symbol)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.symbols[canonical]
+	return info, ok
+}
+
+// AssetClass gets asset class of symbol (registry first, then heuristics)
+func (r *SymbolRegistry) AssetClass(symbol string) AssetClass {
+	if info, ok := r.Lookup(symbol); ok {
+		return info.AssetClass
+	}
+	return guessAssetClass(r.Resolve(symbol))
+}
+
+// ToExchange converts canonical symbol to exchange-specific symbol
+func (r *SymbolRegistry) ToExchange(exchange, symbol string) string {
+	canonical := r.Resolve(symbol)
+	if info, ok := r.Lookup(canonical); ok {
+		if exchangeSymbol, ok := info.ExchangeSymbols[exchange]; ok {
+			return exchangeSymbol
+		}
+	}
+
+	switch exchange {
+	case "okx":
+		// BTCUSDT -> BTC-USDT-SWAP
+		return strings.TrimSuffix(canonical, "USDT") + "-USDT-SWAP"
+	case "hyperliquid", "lighter":
+		// BTCUSDT -> BTC
+		return trimCryptoQuote(canonical)
+	case "dydx", "coinbase":
+		// BTCUSDT -> BTC-USD
+		return trimCryptoQuote(canonical) + "-USD"
+	case "alpaca", "alpaca-live", "alpaca-paper":
+		// BTCUSDT -> BTC/USD (Alpaca crypto pair), stocks unchanged
+		if r.AssetClass(canonical) == AssetCrypto {
+			return trimCryptoQuote(canonical) + "/USD"
+		}
+		return canonical
+	case "ccxt":
+		// BTCUSDT -> BTC/USDT:USDT (CCXT unified linear perpetual), other symbols unchanged
+		for _, quote := range []string{"USDT", "USDC"} {
+			if base := strings.TrimSuffix(canonical, quote); base != canonical && base != "" {
+				return base + "/" + quote + ":" + quote
+			}
+		}
+		return canonical
+	default:
+		// binance/binance-spot/bybit/bitget/aster use canonical format
+		return canonical
+	}
+}
+
+// FromExchange converts exchange-specific symbol back to canonical symbol
+func (r *SymbolRegistry) FromExchange(exchange, exchangeSymbol string) string {
+	r.mu.RLock()
+	canonical, ok := r.reverse[exchange][strings.ToUpper(exchangeSymbol)]
+	r.mu.RUnlock()
+	if ok {
+		return canonical
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(exchangeSymbol))
+	switch exchange {
+	case "okx":
+		// BTC-USDT-SWAP -> BTCUSDT
+		parts := strings.Split(symbol, "-")
+		if len(parts) >= 2 {
+			symbol = parts[0] + parts[1]
+		}
+	case "hyperliquid", "lighter":
+		// BTC -> BTCUSDT
+		symbol = trimCryptoQuote(symbol) + "USDT"
+	case "dydx", "coinbase":
+		// BTC-USD -> BTCUSDT
+		symbol = strings.TrimSuffix(symbol, "-USD") + "USDT"
+	case "alpaca", "alpaca-live", "alpaca-paper":
+		// BTC/USD (orders) or BTCUSD (positions) -> BTCUSDT, stocks unchanged
+		if base, ok := alpacaCryptoBase(symbol); ok {
+			symbol = base + "USDT"
+		}
+	case "ccxt":
+		// BTC/USDT:USDT -> BTCUSDT
+		if i := strings.Index(symbol, ":"); i >= 0 {
+			symbol = symbol[:i]
+		}
+		symbol = strings.ReplaceAll(symbol, "/", "")
+	}
+	return r.Resolve(symbol)
+}
+
+// RoundPrice rounds price to symbol tick size (unchanged if tick size unknown)
+func (r *SymbolRegistry) RoundPrice(symbol string, price float64) float64 {
+	info, ok := r.Lookup(symbol)
+	if !ok || info.TickSize <= 0 {
+		return price
+	}
+	return RoundToIncrement(price, IncrementFromFloat(info.TickSize)).InexactFloat64()
+}
+
+// RoundQuantity rounds quantity down to symbol min quantity step (unchanged if unknown)
+func (r *SymbolRegistry) RoundQuantity(symbol string, quantity float64) float64 {
+	info, ok := r.Lookup(symbol)
+	if !ok || info.MinQty <= 0 {
+		return quantity
+	}
+	return FloorToIncrement(quantity, IncrementFromFloat(info.MinQty)).InexactFloat64()
+}
+
+// FormatPrice formats price rounded to symbol tick size for an order (shortest form if unknown)
+func (r *SymbolRegistry) FormatPrice(symbol string, price float64) string {
+	var tick float64
+	if info, ok := r.Lookup(symbol); ok {
+		tick = info.TickSize
+	}
+	return FormatPriceTick(price, IncrementFromFloat(tick))
+}
+
+// FormatQuantity formats quantity rounded down to symbol min quantity step for an order (shortest form if unknown)
+func (r *SymbolRegistry) FormatQuantity(symbol string, quantity float64) string {
+	var step float64
+	if info, ok := r.Lookup(symbol); ok {
+		step = info.MinQty
+	}
+	return FormatQuantityStep(quantity, IncrementFromFloat(step))
+}
+
+// MinQuantity gets symbol minimum order quantity (0 if unknown)
+func (r *SymbolRegistry) MinQuantity(symbol string) float64 {
+	if info, ok := r.Lookup(symbol); ok {
+		return info.MinQty
+	}
+	return 0
+}
+
+// SetMinNotional sets minimum order value (USD) for asset class on exchange ("" = all exchanges)
+func (r *SymbolRegistry) SetMinNotional(exchange string, class AssetClass, usd float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.minNotional[exchange] == nil {
+		r.minNotional[exchange] = make(map[AssetClass]float64)
+	}
+	r.minNotional[exchange][class] = usd
+}
+
+// MinNotional gets minimum order value (USD) of symbol on exchange (0 if unknown)
+// Priority: symbol metadata > exchange + asset class > any exchange + asset class
+func (r *SymbolRegistry) MinNotional(exchange, symbol string) float64 {
+	if info, ok := r.Lookup(symbol); ok && info.MinNotional > 0 {
+		return info.MinNotional
+	}
+	class := r.AssetClass(symbol)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if usd, ok := r.minNotional[exchange][class]; ok {
+		return usd
+	}
+	return r.minNotional[""][class]
+}
+
+// IsStock checks whether symbol is a stock ticker (options are not stocks)
+func IsStock(symbol string) bool {
+	return Symbols.AssetClass(symbol) == AssetStock
+}
+
+// IsCrypto checks whether symbol is a crypto pair
+func IsCrypto(symbol string) bool {
+	return Symbols.AssetClass(symbol) == AssetCrypto
+}
+
+// IsSpotExchange whether an exchange type trades spot crypto (no leverage, no short selling)
+func IsSpotExchange(exchange string) bool {
+	switch exchange {
+	case "binance-spot", "coinbase":
+		return true
+	}
+	return false
+}
+
+// IsAlpacaExchange whether an exchange type is an Alpaca account
+func IsAlpacaExchange(exchange string) bool {
+	return strings.HasPrefix(exchange, "alpaca")
+}
+
+// IsSpotSymbol whether symbol trades spot on exchange (no leverage, no short selling)
+// Alpaca trades stocks on margin but crypto pairs spot only.
+func IsSpotSymbol(exchange, symbol string) bool {
+	return IsSpotExchange(exchange) || (IsAlpacaExchange(exchange) && IsCrypto(symbol))
+}
+
+// UsesAlpacaData whether market data of symbol comes from the Alpaca data API
+// Stocks always do; crypto pairs do when the trader is on Alpaca, so bars match the broker's own market.
+func UsesAlpacaData(exchange, symbol string) bool {
+	return IsStock(symbol) || (IsAlpacaExchange(exchange) && IsCrypto(symbol))
+}
+
+// ToExchangeSymbol converts canonical symbol to exchange format using default registry
+func ToExchangeSymbol(exchange, symbol string) string {
+	return Symbols.ToExchange(exchange, symbol)
+}
+
+// FromExchangeSymbol converts exchange symbol to canonical format using default registry
+func FromExchangeSymbol(exchange, exchangeSymbol string) string {
+	return Symbols.FromExchange(exchange, exchangeSymbol)
+}
+
+// guessAssetClass classifies unregistered symbols
+// Stocks: TSLA, AAPL, BRK.B (1-5 letters); Crypto: BTCUSDT, ETHUSDT (quote suffix); Options: OCC format
+func guessAssetClass(symbol string) AssetClass {
+	if reOptionSymbol.MatchString(symbol) {
+		return AssetOption
+	}
+	for _, suffix := range cryptoQuoteSuffixes {
+		if strings.HasSuffix(symbol, suffix) {
+			return AssetCrypto
+		}
+	}
+	if len(symbol) > 0 && len(symbol) <= 5 {
+		for _, r := range symbol {
+			if (r < 'A' || r > 'Z') && r != '.' {
+				return AssetCrypto
+			}
+		}
+		return AssetStock
+	}
+	return AssetCrypto
+}
+
+// trimCryptoQuote strips quote currency and perp suffixes (BTCUSDT/BTC-PERP/BTC/USDC -> BTC)
+func trimCryptoQuote(symbol string) string {
+	s := strings.TrimSuffix(symbol, "-PERP")
+	s = strings.TrimSuffix(s, "/USDT")
+	s = strings.TrimSuffix(s, "/USDC")
+	s = strings.TrimSuffix(s, "USDT")
+	s = strings.TrimSuffix(s, "USDC")
+	return s
+}
+
+// alpacaCryptoBase base asset of an Alpaca crypto symbol (BTC/USD, BTCUSD -> BTC)
+// Stock tickers have at most 5 letters, so a longer symbol ending in USD is a crypto pair.
+func alpacaCryptoBase(symbol string) (string, bool) {
+	if i := strings.Index(symbol, "/"); i > 0 {
+		return symbol[:i], true
+	}
+	if base := strings.TrimSuffix(symbol, "USD"); base != symbol && len(symbol) > 5 {
+		return base, true
+	}
+	return "", false
+}
+
+// newDefaultSymbolRegistry creates registry with built-in symbols
+func newDefaultSymbolRegistry() *SymbolRegistry {
+	r := NewSymbolRegistry()
+
+	// Majors (Binance USDT-M filters)
+	// Bare base names (BTC, SOL) are deliberately not aliases: they collide with stock tickers
+	r.Register(SymbolSpec{Symbol: "BTCUSDT", AssetClass: AssetCrypto, TickSize: 0.1, MinQty: 0.001, Aliases: []string{"BTC-PERP"}})
+	r.Register(SymbolSpec{Symbol: "ETHUSDT", AssetClass: AssetCrypto, TickSize: 0.01, MinQty: 0.001, Aliases: []string{"ETH-PERP"}})
+	r.Register(SymbolSpec{Symbol: "SOLUSDT", AssetClass: AssetCrypto, TickSize: 0.01, MinQty: 1, Aliases: []string{"SOL-PERP"}})
+
+	// 1000x contracts: Binance/Bybit list these as 1000XXXUSDT, Hyperliquid as kXXX, OKX per unit
+	for _, base := range []string{"PEPE", "SHIB", "BONK", "FLOKI"} {
+		r.Register(SymbolSpec{
+			Symbol:     "1000" + base + "USDT",
+			AssetClass: AssetCrypto,
+			Aliases:    []string{base + "USDT", base + "-PERP"},
+			ExchangeSymbols: map[string]string{
+				"hyperliquid": "k" + base,
+				"okx":         base + "-USDT-SWAP",
+			},
+		})
+	}
+
+	// Exchange minimum order values (USD)
+	r.SetMinNotional("binance", AssetCrypto, 5)
+	r.SetMinNotional("binance-spot", AssetCrypto, 5)
+	r.SetMinNotional("coinbase", AssetCrypto, 1)
+	r.SetMinNotional("bybit", AssetCrypto, 5)
+	r.SetMinNotional("aster", AssetCrypto, 5)
+	r.SetMinNotional("hyperliquid", AssetCrypto, 10)
+	r.SetMinNotional("alpaca", AssetStock, 1)
+	r.SetMinNotional("alpaca-live", AssetStock, 1)
+	r.SetMinNotional("alpaca-paper", AssetStock, 1)
+	r.SetMinNotional("alpaca", AssetCrypto, 1)
+	r.SetMinNotional("alpaca-live", AssetCrypto, 1)
+	r.SetMinNotional("alpaca-paper", AssetCrypto, 1)
+
+	// Large-cap stocks (whole-cent tick)
+	for _, ticker := range []string{"AAPL", "MSFT", "NVDA", "TSLA", "AMZN", "GOOGL", "META", "SPY", "QQQ"} {
+		r.Register(SymbolSpec{Symbol: ticker, AssetClass: AssetStock, TickSize: 0.01})
+	}
+
+	return r
+}
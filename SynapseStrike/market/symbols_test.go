@@ -0,0 +1,131 @@
+package market
+
+import "testing"
+
+func TestSymbolRegistry_Resolve(t *testing.T) {
+	tests := map[string]string{
+		" aapl ":       "AAPL",
+		"pepeusdt":     "1000PEPEUSDT",
+		"1000PEPEUSDT": "1000PEPEUSDT",
+		"BTC-PERP":     "BTCUSDT",
+		"SOL":          "SOL", // Bare base names are not aliased (stock ticker collision)
+	}
+	for input, want := range tests {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSymbolRegistry_AssetClass(t *testing.T) {
+	tests := map[string]AssetClass{
+		"AAPL":                AssetStock,
+		"ONDS":                AssetStock,
+		"BTCUSDT":             AssetCrypto,
+		"1000PEPEUSDT":        AssetCrypto,
+		"ETHBTC":              AssetCrypto,
+		"AAPL240119C00150000": AssetOption,
+	}
+	for symbol, want := range tests {
+		if got := Symbols.AssetClass(symbol); got != want {
+			t.Errorf("AssetClass(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestSymbolRegistry_ExchangeMapping(t *testing.T) {
+	tests := []struct {
+		exchange  string
+		canonical string
+		exchanged string
+	}{
+		{"okx", "BTCUSDT", "BTC-USDT-SWAP"},
+		{"okx", "1000PEPEUSDT", "PEPE-USDT-SWAP"},
+		{"hyperliquid", "ETHUSDT", "ETH"},
+		{"hyperliquid", "1000PEPEUSDT", "kPEPE"},
+		{"dydx", "ETHUSDT", "ETH-USD"},
+		{"coinbase", "BTCUSDT", "BTC-USD"},
+		{"binance-spot", "SOLUSDT", "SOLUSDT"},
+		{"binance", "1000PEPEUSDT", "1000PEPEUSDT"},
+		{"alpaca", "TSLA", "TSLA"},
+		{"alpaca", "BTCUSDT", "BTC/USD"},
+		{"alpaca-paper", "ETHUSDT", "ETH/USD"},
+		{"ccxt", "BTCUSDT", "BTC/USDT:USDT"},
+		{"ccxt", "ETHUSDC", "ETH/USDC:USDC"},
+		{"ccxt", "TSLA", "TSLA"},
+	}
+	for _, tt := range tests {
+		if got := ToExchangeSymbol(tt.exchange, tt.canonical); got != tt.exchanged {
+			t.Errorf("ToExchangeSymbol(%s, %s) = %s, want %s", tt.exchange, tt.canonical, got, tt.exchanged)
+		}
+		if got := FromExchangeSymbol(tt.exchange, tt.exchanged); got != tt.canonical {
+			t.Errorf("FromExchangeSymbol(%s, %s) = %s, want %s", tt.exchange, tt.exchanged, got, tt.canonical)
+		}
+	}
+}
+
+func TestSymbolRegistry_AlpacaCrypto(t *testing.T) {
+	// Positions report crypto pairs without the slash
+	if got := FromExchangeSymbol("alpaca", "BTCUSD"); got != "BTCUSDT" {
+		t.Errorf("FromExchangeSymbol(alpaca, BTCUSD) = %s, want BTCUSDT", got)
+	}
+	if got := FromExchangeSymbol("alpaca", "AAPL"); got != "AAPL" {
+		t.Errorf("FromExchangeSymbol(alpaca, AAPL) = %s, want AAPL", got)
+	}
+
+	tests := []struct {
+		exchange string
+		symbol   string
+		spot     bool
+		alpaca   bool
+	}{
+		{"alpaca", "BTCUSDT", true, true},
+		{"alpaca-paper", "AAPL", false, true},
+		{"binance", "BTCUSDT", false, false},
+		{"binance", "AAPL", false, true},
+		{"coinbase", "ETHUSDT", true, false},
+	}
+	for _, tt := range tests {
+		if got := IsSpotSymbol(tt.exchange, tt.symbol); got != tt.spot {
+			t.Errorf("IsSpotSymbol(%s, %s) = %v, want %v", tt.exchange, tt.symbol, got, tt.spot)
+		}
+		if got := UsesAlpacaData(tt.exchange, tt.symbol); got != tt.alpaca {
+			t.Errorf("UsesAlpacaData(%s, %s) = %v, want %v", tt.exchange, tt.symbol, got, tt.alpaca)
+		}
+	}
+}
+
+func TestSymbolRegistry_Rounding(t *testing.T) {
+	if got := Symbols.RoundQuantity("BTCUSDT", 0.0129); got < 0.01199 || got > 0.01201 {
+		t.Errorf("RoundQuantity(BTCUSDT) = %v, want 0.012", got)
+	}
+	if got := Symbols.RoundQuantity("UNKNOWNUSDT", 1.2345); got != 1.2345 {
+		t.Errorf("unknown symbol quantity should be unchanged, got %v", got)
+	}
+	if got := Symbols.RoundPrice("AAPL", 187.236); got < 187.2399 || got > 187.2401 {
+		t.Errorf("RoundPrice(AAPL) = %v, want 187.24", got)
+	}
+}
+
+func TestSymbolRegistry_MinNotional(t *testing.T) {
+	r := NewSymbolRegistry()
+	r.SetMinNotional("", AssetCrypto, 5)
+	r.SetMinNotional("hyperliquid", AssetCrypto, 10)
+	r.Register(SymbolSpec{Symbol: "BTCUSDT", MinNotional: 100})
+
+	tests := []struct {
+		exchange string
+		symbol   string
+		want     float64
+	}{
+		{"hyperliquid", "ETHUSDT", 10},
+		{"binance", "ETHUSDT", 5},       // Falls back to any-exchange minimum
+		{"hyperliquid", "BTCUSDT", 100}, // Symbol minimum overrides exchange
+		{"alpaca", "AAPL", 0},
+	}
+	for _, tt := range tests {
+		if got := r.MinNotional(tt.exchange, tt.symbol); got != tt.want {
+			t.Errorf("MinNotional(%s, %s) = %v, want %v", tt.exchange, tt.symbol, got, tt.want)
+		}
+	}
+}
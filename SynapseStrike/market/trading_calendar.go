@@ -0,0 +1,226 @@
+package market
+
+import (
+	"SynapseStrike/logger"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// US Market Trading Calendar
+// ============================================================================
+// Regular session 9:30-16:00 ET on weekdays, except NYSE holidays (closed) and
+// half days (13:00 close). The built-in table covers the years below; later
+// years are added each year through a JSON file (MARKET_CALENDAR_PATH, see
+// LoadTradingCalendar) without a release:
+//
+//	[{"date": "2028-01-17", "name": "Martin Luther King Jr. Day"},
+//	 {"date": "2028-11-24", "name": "Day after Thanksgiving", "early_close": "13:00"}]
+//
+// For a year missing from both, weekdays are treated as regular sessions and
+// a warning is logged once.
+
+const (
+	sessionOpenMinutes    = 9*60 + 30 // 9:30 ET
+	sessionCloseMinutes   = 16 * 60   // 16:00 ET
+	halfDayCloseMinutesET = 13 * 60   // 13:00 ET
+)
+
+// MarketDay holiday or half day of the US stock market
+type MarketDay struct {
+	Date       string `json:"date"`                  // YYYY-MM-DD (ET)
+	Name       string `json:"name"`                  // Holiday name
+	EarlyClose string `json:"early_close,omitempty"` // HH:MM ET close of a half day ("" = closed all day)
+}
+
+// builtinTradingCalendar NYSE holidays and half days
+var builtinTradingCalendar = []MarketDay{
+	// 2025
+	{Date: "2025-01-01", Name: "New Year's Day"},
+	{Date: "2025-01-09", Name: "National Day of Mourning"},
+	{Date: "2025-01-20", Name: "Martin Luther King Jr. Day"},
+	{Date: "2025-02-17", Name: "Washington's Birthday"},
+	{Date: "2025-04-18", Name: "Good Friday"},
+	{Date: "2025-05-26", Name: "Memorial Day"},
+	{Date: "2025-06-19", Name: "Juneteenth"},
+	{Date: "2025-07-03", Name: "Independence Day eve", EarlyClose: "13:00"},
+	{Date: "2025-07-04", Name: "Independence Day"},
+	{Date: "2025-09-01", Name: "Labor Day"},
+	{Date: "2025-11-27", Name: "Thanksgiving Day"},
+	{Date: "2025-11-28", Name: "Day after Thanksgiving", EarlyClose: "13:00"},
+	{Date: "2025-12-24", Name: "Christmas Eve", EarlyClose: "13:00"},
+	{Date: "2025-12-25", Name: "Christmas Day"},
+	// 2026
+	{Date: "2026-01-01", Name: "New Year's Day"},
+	{Date: "2026-01-19", Name: "Martin Luther King Jr. Day"},
+	{Date: "2026-02-16", Name: "Washington's Birthday"},
+	{Date: "2026-04-03", Name: "Good Friday"},
+	{Date: "2026-05-25", Name: "Memorial Day"},
+	{Date: "2026-06-19", Name: "Juneteenth"},
+	{Date: "2026-07-03", Name: "Independence Day (observed)"},
+	{Date: "2026-09-07", Name: "Labor Day"},
+	{Date: "2026-11-26", Name: "Thanksgiving Day"},
+	{Date: "2026-11-27", Name: "Day after Thanksgiving", EarlyClose: "13:00"},
+	{Date: "2026-12-24", Name: "Christmas Eve", EarlyClose: "13:00"},
+	{Date: "2026-12-25", Name: "Christmas Day"},
+	// 2027
+	{Date: "2027-01-01", Name: "New Year's Day"},
+	{Date: "2027-01-18", Name: "Martin Luther King Jr. Day"},
+	{Date: "2027-02-15", Name: "Washington's Birthday"},
+	{Date: "2027-03-26", Name: "Good Friday"},
+	{Date: "2027-05-31", Name: "Memorial Day"},
+	{Date: "2027-06-18", Name: "Juneteenth (observed)"},
+	{Date: "2027-07-05", Name: "Independence Day (observed)"},
+	{Date: "2027-09-06", Name: "Labor Day"},
+	{Date: "2027-11-25", Name: "Thanksgiving Day"},
+	{Date: "2027-11-26", Name: "Day after Thanksgiving", EarlyClose: "13:00"},
+	{Date: "2027-12-24", Name: "Christmas Day (observed)"},
+}
+
+var (
+	tradingCalendarMu     sync.RWMutex
+	tradingCalendarDays   = indexMarketDays(builtinTradingCalendar)
+	tradingCalendarYears  = marketDayYears(builtinTradingCalendar)
+	tradingCalendarWarned = make(map[int]bool)
+)
+
+// MarketSession regular session of one ET calendar day
+type MarketSession struct {
+	Date         string // YYYY-MM-DD (ET)
+	Open         bool   // Market trades this day
+	Holiday      string // Holiday or half-day name ("" = regular day)
+	OpenMinutes  int    // Session open, minutes after midnight ET
+	CloseMinutes int    // Session close, minutes after midnight ET (13:00 on half days)
+}
+
+// HalfDay whether the session closes early
+func (s MarketSession) HalfDay() bool {
+	return s.Open && s.CloseMinutes < sessionCloseMinutes
+}
+
+// USMarketTimezone US market timezone (UTC if tzdata is unavailable)
+func USMarketTimezone() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// USMarketSession regular session of the ET day containing t
+func USMarketSession(t time.Time) MarketSession {
+	et := t.In(USMarketTimezone())
+	session := MarketSession{
+		Date:         et.Format("2006-01-02"),
+		Open:         et.Weekday() != time.Saturday && et.Weekday() != time.Sunday,
+		OpenMinutes:  sessionOpenMinutes,
+		CloseMinutes: sessionCloseMinutes,
+	}
+	if !session.Open {
+		return session
+	}
+
+	tradingCalendarMu.RLock()
+	day, special := tradingCalendarDays[session.Date]
+	covered := tradingCalendarYears[et.Year()]
+	tradingCalendarMu.RUnlock()
+	if !covered {
+		warnMissingCalendarYear(et.Year())
+	}
+	if !special {
+		return session
+	}
+
+	session.Holiday = day.Name
+	if day.EarlyClose == "" {
+		session.Open = false
+		return session
+	}
+	session.CloseMinutes = halfDayCloseMinutesET
+	var hour, minute int
+	if _, err := fmt.Sscanf(day.EarlyClose, "%d:%d", &hour, &minute); err == nil {
+		session.CloseMinutes = hour*60 + minute
+	}
+	return session
+}
+
+// IsUSMarketOpen whether t falls in a regular US stock market session
+func IsUSMarketOpen(t time.Time) bool {
+	session := USMarketSession(t)
+	if !session.Open {
+		return false
+	}
+	et := t.In(USMarketTimezone())
+	minutes := et.Hour()*60 + et.Minute()
+	return minutes >= session.OpenMinutes && minutes < session.CloseMinutes
+}
+
+// LoadTradingCalendar adds holidays and half days from a JSON file to the calendar
+// Entries replace built-in entries of the same date. A missing file is not an error.
+func LoadTradingCalendar(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var days []MarketDay
+	if err := json.Unmarshal(data, &days); err != nil {
+		return 0, fmt.Errorf("invalid trading calendar %s: %w", path, err)
+	}
+	for _, day := range days {
+		if _, err := time.Parse("2006-01-02", day.Date); err != nil {
+			return 0, fmt.Errorf("invalid trading calendar date %q: %w", day.Date, err)
+		}
+		if day.EarlyClose != "" {
+			if _, err := time.Parse("15:04", day.EarlyClose); err != nil {
+				return 0, fmt.Errorf("invalid early_close %q on %s (expected HH:MM)", day.EarlyClose, day.Date)
+			}
+		}
+	}
+
+	tradingCalendarMu.Lock()
+	defer tradingCalendarMu.Unlock()
+	for _, day := range days {
+		tradingCalendarDays[day.Date] = day
+	}
+	for year := range marketDayYears(days) {
+		tradingCalendarYears[year] = true
+	}
+	return len(days), nil
+}
+
+// warnMissingCalendarYear logs once per year that holidays are unknown
+func warnMissingCalendarYear(year int) {
+	tradingCalendarMu.Lock()
+	defer tradingCalendarMu.Unlock()
+	if tradingCalendarWarned[year] {
+		return
+	}
+	tradingCalendarWarned[year] = true
+	logger.Warnf("⚠️ No US market holiday calendar for %d, treating all weekdays as trading days (add it via MARKET_CALENDAR_PATH)", year)
+}
+
+// indexMarketDays market days by date
+func indexMarketDays(days []MarketDay) map[string]MarketDay {
+	index := make(map[string]MarketDay, len(days))
+	for _, day := range days {
+		index[day.Date] = day
+	}
+	return index
+}
+
+// marketDayYears years covered by market days
+func marketDayYears(days []MarketDay) map[int]bool {
+	years := make(map[int]bool)
+	for _, day := range days {
+		if t, err := time.Parse("2006-01-02", day.Date); err == nil {
+			years[t.Year()] = true
+		}
+	}
+	return years
+}
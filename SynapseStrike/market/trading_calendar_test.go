@@ -0,0 +1,66 @@
+package market
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// etTime time in the US market timezone
+func etTime(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, USMarketTimezone())
+}
+
+func TestIsUSMarketOpen(t *testing.T) {
+	cases := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"regular session", etTime(2026, 11, 25, 15, 30), true},
+		{"before open", etTime(2026, 11, 25, 9, 29), false},
+		{"thanksgiving", etTime(2026, 11, 26, 11, 0), false},
+		{"half day morning", etTime(2026, 11, 27, 12, 30), true},
+		{"half day after 1 PM close", etTime(2026, 11, 27, 13, 30), false},
+		{"weekend", etTime(2026, 11, 28, 11, 0), false},
+		{"observed independence day", etTime(2026, 7, 3, 11, 0), false},
+	}
+	for _, tc := range cases {
+		if got := IsUSMarketOpen(tc.at); got != tc.want {
+			t.Errorf("%s: open = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if session := USMarketSession(etTime(2026, 12, 24, 10, 0)); !session.HalfDay() || session.CloseMinutes != 13*60 {
+		t.Errorf("christmas eve session = %+v, want 13:00 half day", session)
+	}
+}
+
+func TestLoadTradingCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar.json")
+	if n, err := LoadTradingCalendar(path); n != 0 || err != nil {
+		t.Fatalf("missing file: n=%d err=%v, want ignored", n, err)
+	}
+
+	content := `[{"date": "2031-01-01", "name": "New Year's Day"}, {"date": "2031-07-03", "name": "Independence Day eve", "early_close": "13:00"}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := LoadTradingCalendar(path); n != 2 || err != nil {
+		t.Fatalf("n=%d err=%v, want 2 entries", n, err)
+	}
+	if IsUSMarketOpen(etTime(2031, 1, 1, 11, 0)) {
+		t.Error("loaded holiday should close the market")
+	}
+	if session := USMarketSession(etTime(2031, 7, 3, 11, 0)); !session.HalfDay() {
+		t.Errorf("loaded half day = %+v", session)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"date": "2031-13-01", "name": "bad"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTradingCalendar(path); err == nil {
+		t.Error("invalid date should be rejected")
+	}
+}
@@ -13,11 +13,27 @@ type Data struct {
 	CurrentRSI7       float64
 	OpenInterest      *OIData
 	FundingRate       float64
+	OrderBook         *OrderBook `json:"order_book,omitempty"` // Order book snapshot (crypto, Indicators.EnableOrderBook only)
 	IntradaySeries    *IntradayData
 	LongerTermContext *LongerTermData
 	// Multi-timeframe data (new)
 	TimeframeData  map[string]*TimeframeSeriesData `json:"timeframe_data,omitempty"`
 	StockExtraData *StockExtraData                 `json:"stock_extra_data,omitempty"` // Stock-specific data
+	Quality        *DataQuality                    `json:"quality,omitempty"`          // OHLCV integrity of the fetched series (see integrity.go)
+	Gaps           []FeedGap                       `json:"gaps,omitempty"`             // Optional feeds that failed, the data is usable without them
+}
+
+// Feeds reported in FeedGap
+const (
+	FeedKlines = "klines" // Kline series of a secondary timeframe
+	FeedOI     = "oi"     // Open interest
+	FeedNews   = "news"   // Stock news
+)
+
+// FeedGap optional feed missing from a fetch and why
+type FeedGap struct {
+	Feed   string `json:"feed"`
+	Reason string `json:"reason"`
 }
 
 // StockExtraData contains stock-specific indicators (not applicable for crypto)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -80,15 +81,32 @@ func (c *ClaudeClient) buildUrl() string {
 
 // buildMCPRequestBody Claude has different request format
 func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
+	cfg := c.cfg()
 	requestBody := map[string]any{
 		"model":      c.Model,
-		"max_tokens": c.MaxTokens,
+		"max_tokens": cfg.MaxTokens,
 		"system":     systemPrompt,
 		"messages": []map[string]string{
 			{"role": "user", "content": userPrompt},
 		},
 	}
 
+	// Extended thinking requires the default temperature and a budget below max_tokens
+	if budget, ok := claudeThinkingBudgets[cfg.ReasoningEffort]; ok {
+		if cfg.MaxTokens <= budget {
+			requestBody["max_tokens"] = budget + cfg.MaxTokens
+		}
+		requestBody["thinking"] = map[string]any{"type": "enabled", "budget_tokens": budget}
+		return requestBody
+	}
+
+	// Claude accepts either temperature or top_p, not both
+	if cfg.TopP != nil {
+		requestBody["top_p"] = *cfg.TopP
+	} else {
+		requestBody["temperature"] = cfg.Temperature
+	}
+
 	return requestBody
 }
 
@@ -96,8 +114,9 @@ func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[
 func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 	var response struct {
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type     string `json:"type"`
+			Text     string `json:"text"`
+			Thinking string `json:"thinking"` // Extended thinking block
 		} `json:"content"`
 		Error *struct {
 			Type    string `json:"type"`
@@ -117,6 +136,15 @@ func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 		return "", fmt.Errorf("Claude returned empty content, body: %s", string(body))
 	}
 
+	// Thinking blocks precede the text content
+	var thinking []string
+	for _, content := range response.Content {
+		if content.Type == "thinking" && content.Thinking != "" {
+			thinking = append(thinking, content.Thinking)
+		}
+	}
+	c.setLastReasoning(strings.Join(thinking, "\n\n"))
+
 	// Find text content
 	for _, content := range response.Content {
 		if content.Type == "text" {
@@ -126,3 +154,10 @@ func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 
 	return "", fmt.Errorf("no text content in Claude response")
 }
+
+// CapturesNativeReasoning Claude returns thinking blocks when extended thinking is enabled by the reasoning effort
+func (c *ClaudeClient) CapturesNativeReasoning() bool {
+	cfg := c.cfg()
+	_, thinking := claudeThinkingBudgets[cfg.ReasoningEffort]
+	return cfg.NativeReasoning && thinking
+}
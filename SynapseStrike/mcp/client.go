@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -57,6 +59,11 @@ type Client struct {
 	httpClient *http.Client
 	logger     Logger // Logger (replaceable)
 	config     *Config // Config object (stores all configurations)
+	baseConfig *Config // Config as created, before SetGenerationParams overrides
+
+	configMu *sync.RWMutex // Guards config swaps (SetGenerationParams) against in-flight calls, shared by copies
+
+	lastReasoning atomic.Value // string: provider-native reasoning of the most recent call (see reasoning.go)
 
 	// hooks are used to implement dynamic dispatch (polymorphism)
 	// When DeepSeekClient embeds Client, hooks point to DeepSeekClient
@@ -109,6 +116,8 @@ func NewClient(opts ...ClientOption) AIClient {
 		httpClient: cfg.HTTPClient,
 		logger:     cfg.Logger,
 		config:     cfg,
+		baseConfig: cfg,
+		configMu:   &sync.RWMutex{},
 	}
 
 	// 4. Set default Provider (if not set)
@@ -153,7 +162,7 @@ func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string,
 
 	// Fixed retry flow
 	var lastErr error
-	maxRetries := client.config.MaxRetries
+	maxRetries := client.cfg().MaxRetries
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
@@ -177,14 +186,7 @@ func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string,
 
 		// Wait before retry with exponential backoff (2s, 4s, 8s, ...)
 		if attempt < maxRetries {
-			waitTime := client.config.RetryWaitBase
-			for i := 1; i < attempt; i++ {
-				waitTime *= 2
-			}
-			// Cap at 30 seconds
-			if waitTime > 30*time.Second {
-				waitTime = 30 * time.Second
-			}
+			waitTime := client.retryWait(attempt)
 			client.logger.Infof("⏳ Waiting %v before retry (exponential backoff)...", waitTime)
 			time.Sleep(waitTime)
 		}
@@ -193,6 +195,23 @@ func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string,
 	return "", fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)
 }
 
+// retryWait returns backoff duration after given failed attempt (RetryWaitBase doubled per attempt, capped at RetryWaitMax)
+func (client *Client) retryWait(attempt int) time.Duration {
+	cfg := client.cfg()
+	maxWait := cfg.RetryWaitMax
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	waitTime := cfg.RetryWaitBase
+	for i := 1; i < attempt && waitTime < maxWait; i++ {
+		waitTime *= 2
+	}
+	if waitTime > maxWait {
+		waitTime = maxWait
+	}
+	return waitTime
+}
+
 func (client *Client) setAuthHeader(reqHeader http.Header) {
 	reqHeader.Set("Authorization", fmt.Sprintf("Bearer %s", client.APIKey))
 }
@@ -215,26 +234,21 @@ func (client *Client) buildMCPRequestBody(systemPrompt, userPrompt string) map[s
 	})
 
 	// Build request body
+	cfg := client.cfg()
 	requestBody := map[string]interface{}{
 		"model":       client.Model,
 		"messages":    messages,
-		"temperature": client.config.Temperature, // Use configured temperature
+		"temperature": cfg.Temperature, // Use configured temperature
 	}
 	// OpenAI newer models use max_completion_tokens instead of max_tokens
 	if client.Provider == ProviderOpenAI {
-		requestBody["max_completion_tokens"] = client.MaxTokens
+		requestBody["max_completion_tokens"] = cfg.MaxTokens
 	} else {
-		requestBody["max_tokens"] = client.MaxTokens
+		requestBody["max_tokens"] = cfg.MaxTokens
 	}
 
-	// Disable thinking/reasoning mode for Qwen3+ models via vLLM chat_template_kwargs
-	// This prevents the model from wasting tokens on <think> internal reasoning
-	modelLower := strings.ToLower(client.Model)
-	if strings.Contains(modelLower, "qwen3") || strings.Contains(modelLower, "qwen/qwen3") {
-		requestBody["chat_template_kwargs"] = map[string]interface{}{
-			"enable_thinking": false,
-		}
-	}
+	// top_p and reasoning effort (Qwen3+ thinking toggle) when configured
+	client.applySamplingParams(requestBody, client.Model)
 
 	return requestBody
 }
@@ -252,7 +266,9 @@ func (client *Client) parseMCPResponse(body []byte) (string, error) {
 	var result struct {
 		Choices []struct {
 			Message struct {
-				Content string `json:"content"`
+				Content          string `json:"content"`
+				ReasoningContent string `json:"reasoning_content"` // DeepSeek R1, Qwen3 thinking
+				Reasoning        string `json:"reasoning"`         // OpenRouter / vLLM variant
 			} `json:"message"`
 		} `json:"choices"`
 	}
@@ -265,7 +281,13 @@ func (client *Client) parseMCPResponse(body []byte) (string, error) {
 		return "", fmt.Errorf("API returned empty response")
 	}
 
-	return result.Choices[0].Message.Content, nil
+	message := result.Choices[0].Message
+	if message.ReasoningContent != "" {
+		client.setLastReasoning(message.ReasoningContent)
+	} else {
+		client.setLastReasoning(message.Reasoning)
+	}
+	return message.Content, nil
 }
 
 func (client *Client) buildUrl() string {
@@ -299,6 +321,8 @@ func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
 		client.logger.Debugf("[%s]   API Key: %s...%s", client.String(), client.APIKey[:4], client.APIKey[len(client.APIKey)-4:])
 	}
 
+	client.setLastReasoning("")
+
 	// Step 1: Build request body (via hooks for dynamic dispatch)
 	requestBody := client.hooks.buildMCPRequestBody(systemPrompt, userPrompt)
 
@@ -362,7 +386,7 @@ func (client *Client) String() string {
 func (client *Client) isRetryableError(err error) bool {
 	errStr := err.Error()
 	// Network errors, timeouts, EOF, etc. can be retried
-	for _, retryable := range client.config.RetryableErrors {
+	for _, retryable := range client.cfg().RetryableErrors {
 		if strings.Contains(errStr, retryable) {
 			return true
 		}
@@ -401,7 +425,7 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 
 	// Fixed retry flow
 	var lastErr error
-	maxRetries := client.config.MaxRetries
+	maxRetries := client.cfg().MaxRetries
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
@@ -425,14 +449,7 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 
 		// Wait before retry with exponential backoff (2s, 4s, 8s, ...)
 		if attempt < maxRetries {
-			waitTime := client.config.RetryWaitBase
-			for i := 1; i < attempt; i++ {
-				waitTime *= 2
-			}
-			// Cap at 30 seconds
-			if waitTime > 30*time.Second {
-				waitTime = 30 * time.Second
-			}
+			waitTime := client.retryWait(attempt)
 			client.logger.Infof("⏳ Waiting %v before retry (exponential backoff)...", waitTime)
 			time.Sleep(waitTime)
 		}
@@ -446,6 +463,7 @@ func (client *Client) callWithRequest(req *Request) (string, error) {
 	// Print current AI configuration
 	client.logger.Infof("📡 [%s] Request AI Server with Builder: BaseURL: %s", client.String(), client.BaseURL)
 	client.logger.Debugf("[%s] Messages count: %d", client.String(), len(req.Messages))
+	client.setLastReasoning("")
 
 	// Build request body (from Request object)
 	requestBody := client.buildRequestBodyFromRequest(req)
@@ -511,11 +529,12 @@ func (client *Client) buildRequestBodyFromRequest(req *Request) map[string]any {
 	}
 
 	// Add optional parameters (only add non-nil parameters)
+	cfg := client.cfg()
 	if req.Temperature != nil {
 		requestBody["temperature"] = *req.Temperature
 	} else {
 		// If not set in Request, use Client's configuration
-		requestBody["temperature"] = client.config.Temperature
+		requestBody["temperature"] = cfg.Temperature
 	}
 
 	// OpenAI newer models use max_completion_tokens instead of max_tokens
@@ -527,9 +546,10 @@ func (client *Client) buildRequestBodyFromRequest(req *Request) map[string]any {
 		requestBody[tokenKey] = *req.MaxTokens
 	} else {
 		// If not set in Request, use Client's MaxTokens
-		requestBody[tokenKey] = client.MaxTokens
+		requestBody[tokenKey] = cfg.MaxTokens
 	}
 
+	client.applySamplingParams(requestBody, req.Model)
 	if req.TopP != nil {
 		requestBody["top_p"] = *req.TopP
 	}
@@ -383,6 +383,21 @@ func TestClient_SetTimeout(t *testing.T) {
 	}
 }
 
+func TestClient_RetryWait(t *testing.T) {
+	client := NewClient(
+		WithRetryWaitBase(2*time.Second),
+		WithRetryWaitMax(10*time.Second),
+	)
+
+	c := client.(*Client)
+	expected := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, want := range expected {
+		if got := c.retryWait(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
+
 // ============================================================
 // Test String Method
 // ============================================================
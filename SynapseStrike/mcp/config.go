@@ -17,14 +17,21 @@ type Config struct {
 	BaseURL  string
 	Model    string
 
+	// Embedding configuration
+	EmbeddingModel string
+
 	// Behavior configuration
-	MaxTokens   int
-	Temperature float64
-	UseFullURL  bool
+	MaxTokens       int
+	Temperature     float64
+	TopP            *float64 // Nucleus sampling (nil = provider default)
+	ReasoningEffort string   // "low" | "medium" | "high" (empty = provider default)
+	NativeReasoning bool     // Request and capture provider-native reasoning (see reasoning.go)
+	UseFullURL      bool
 
 	// Retry configuration
 	MaxRetries     int
 	RetryWaitBase  time.Duration
+	RetryWaitMax   time.Duration // Upper bound for exponential backoff
 	RetryableErrors []string
 
 	// Timeout configuration
@@ -37,18 +44,22 @@ type Config struct {
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
+	timeout := getEnvSeconds("AI_REQUEST_TIMEOUT_SECONDS", DefaultTimeout)
+
 	return &Config{
 		// Default values
 		MaxTokens:      getEnvInt("AI_MAX_TOKENS", 2000),
 		Temperature:    MCPClientTemperature,
-		MaxRetries:     MaxRetryTimes,
-		RetryWaitBase:  2 * time.Second,
-		Timeout:        DefaultTimeout,
+		MaxRetries:     getEnvInt("AI_MAX_RETRIES", MaxRetryTimes),
+		RetryWaitBase:  getEnvSeconds("AI_RETRY_WAIT_BASE_SECONDS", 2*time.Second),
+		RetryWaitMax:   getEnvSeconds("AI_RETRY_WAIT_MAX_SECONDS", 30*time.Second),
+		Timeout:        timeout,
+		EmbeddingModel: getEnvString("AI_EMBEDDING_MODEL", DefaultEmbeddingModel),
 		RetryableErrors: retryableErrors,
 
 		// Default dependencies (use global logger)
 		Logger:     logger.NewMCPLogger(),
-		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		HTTPClient: &http.Client{Timeout: timeout},
 	}
 }
 
@@ -62,6 +73,14 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvSeconds reads duration in seconds from environment variable, returns default value if failed
+func getEnvSeconds(key string, defaultValue time.Duration) time.Duration {
+	if seconds := getEnvInt(key, 0); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultValue
+}
+
 // getEnvString reads string from environment variable, returns default value if empty
 func getEnvString(key string, defaultValue string) string {
 	if val := os.Getenv(key); val != "" {
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"net/http"
+	"strings"
 )
 
 const (
@@ -81,3 +82,9 @@ func (dsClient *DeepSeekClient) SetAPIKey(apiKey string, customURL string, custo
 func (dsClient *DeepSeekClient) setAuthHeader(reqHeaders http.Header) {
 	dsClient.Client.setAuthHeader(reqHeaders)
 }
+
+// CapturesNativeReasoning DeepSeek reasoning models (deepseek-reasoner, R1) return reasoning_content
+func (dsClient *DeepSeekClient) CapturesNativeReasoning() bool {
+	model := strings.ToLower(dsClient.Model)
+	return dsClient.cfg().NativeReasoning && (strings.Contains(model, "reasoner") || strings.Contains(model, "r1"))
+}
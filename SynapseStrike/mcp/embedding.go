@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultEmbeddingModel default OpenAI-compatible embedding model
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// Embedder converts texts to embedding vectors
+type Embedder interface {
+	Embed(texts []string) ([][]float32, error)
+	EmbeddingModel() string
+}
+
+// EmbeddingModel gets configured embedding model name
+func (client *Client) EmbeddingModel() string {
+	if cfg := client.cfg(); cfg != nil && cfg.EmbeddingModel != "" {
+		return cfg.EmbeddingModel
+	}
+	return DefaultEmbeddingModel
+}
+
+// Embed calls OpenAI-compatible /embeddings endpoint of the configured provider
+// Providers without an embeddings API return an error; callers should fall back to a local embedder.
+func (client *Client) Embed(texts []string) ([][]float32, error) {
+	if client.APIKey == "" {
+		return nil, fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	}
+	if client.UseFullURL {
+		return nil, fmt.Errorf("embeddings not supported with full URL endpoint")
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(map[string]any{
+		"model": client.EmbeddingModel(),
+		"input": texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize embedding request: %w", err)
+	}
+
+	req, err := client.hooks.buildRequest(fmt.Sprintf("%s/embeddings", client.BaseURL), jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors, expected %d", len(result.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding response index %d out of range", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
@@ -69,3 +69,35 @@ func (c *GeminiClient) SetAPIKey(apiKey string, customURL string, customModel st
 func (c *GeminiClient) setAuthHeader(reqHeaders http.Header) {
 	c.Client.setAuthHeader(reqHeaders)
 }
+
+// buildMCPRequestBody asks Gemini thinking models for thought summaries when native reasoning is enabled
+func (c *GeminiClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
+	requestBody := c.Client.buildMCPRequestBody(systemPrompt, userPrompt)
+	if c.cfg().NativeReasoning {
+		requestBody["extra_body"] = map[string]any{
+			"google": map[string]any{
+				"thinking_config": map[string]any{"include_thoughts": true},
+			},
+		}
+	}
+	return requestBody
+}
+
+// parseMCPResponse moves the <thought> summary in front of the content into the native reasoning
+func (c *GeminiClient) parseMCPResponse(body []byte) (string, error) {
+	content, err := c.Client.parseMCPResponse(body)
+	if err != nil || !c.cfg().NativeReasoning {
+		return content, err
+	}
+	thought, rest := splitGeminiThought(content)
+	if thought == "" {
+		return content, nil
+	}
+	c.setLastReasoning(thought)
+	return rest, nil
+}
+
+// CapturesNativeReasoning Gemini thinking models return thought summaries when requested
+func (c *GeminiClient) CapturesNativeReasoning() bool {
+	return c.cfg().NativeReasoning
+}
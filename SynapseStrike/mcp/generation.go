@@ -0,0 +1,113 @@
+package mcp
+
+import "strings"
+
+// Reasoning effort levels (GenerationParams.ReasoningEffort)
+const (
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
+// IsValidReasoningEffort checks a reasoning effort level ("" = provider default)
+func IsValidReasoningEffort(effort string) bool {
+	switch effort {
+	case "", ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+		return true
+	}
+	return false
+}
+
+// GenerationParams sampling parameters applied to every call of a client
+// Nil / zero fields fall back to the settings the client was created with.
+type GenerationParams struct {
+	Temperature     *float64 // Sampling temperature (0-2)
+	TopP            *float64 // Nucleus sampling (0-1)
+	MaxTokens       int      // Maximum response tokens
+	ReasoningEffort string   // "low" | "medium" | "high" (reasoning-capable models only)
+	NativeReasoning bool     // Capture reasoning returned outside the content (thinking blocks, reasoning_content)
+}
+
+// claudeThinkingBudgets extended thinking token budget per reasoning effort
+var claudeThinkingBudgets = map[string]int{
+	ReasoningEffortLow:    1024,
+	ReasoningEffortMedium: 4096,
+	ReasoningEffortHigh:   16384,
+}
+
+// reasoningEffortProviders providers accepting the OpenAI reasoning_effort field
+// (LocalAI covers vLLM-served gpt-oss models). Qwen3 models toggle thinking instead,
+// other providers ignore the setting.
+var reasoningEffortProviders = map[string]bool{
+	ProviderOpenAI:  true,
+	ProviderGrok:    true,
+	ProviderGemini:  true,
+	ProviderLocalAI: true,
+	ProviderCustom:  true,
+}
+
+// SetGenerationParams replaces sampling parameters of subsequent calls
+// The config is copied so clients cloned from this one keep their own settings, and swapped
+// under configMu so calls already running on another goroutine keep the config they started with.
+func (client *Client) SetGenerationParams(params GenerationParams) {
+	client.configMu.Lock()
+	defer client.configMu.Unlock()
+
+	base := client.baseConfig
+	if base == nil {
+		base = client.config
+	}
+	cfg := *base
+	if params.Temperature != nil {
+		cfg.Temperature = *params.Temperature
+	}
+	if params.TopP != nil {
+		topP := *params.TopP
+		cfg.TopP = &topP
+	}
+	if params.MaxTokens > 0 {
+		cfg.MaxTokens = params.MaxTokens
+	}
+	if params.ReasoningEffort != "" {
+		cfg.ReasoningEffort = params.ReasoningEffort
+	}
+	if params.NativeReasoning {
+		cfg.NativeReasoning = true
+	}
+	client.config = &cfg
+	client.MaxTokens = cfg.MaxTokens
+}
+
+// cfg current config (replaced as a whole by SetGenerationParams, never modified in place)
+func (client *Client) cfg() *Config {
+	client.configMu.RLock()
+	defer client.configMu.RUnlock()
+	return client.config
+}
+
+// isQwen3Model whether the model is a Qwen3+ model served with a thinking toggle
+func (client *Client) isQwen3Model(model string) bool {
+	modelLower := strings.ToLower(model)
+	return strings.Contains(modelLower, "qwen3") || strings.Contains(modelLower, "qwen/qwen3")
+}
+
+// applySamplingParams adds configured top_p and reasoning settings to an OpenAI-compatible request body
+func (client *Client) applySamplingParams(requestBody map[string]any, model string) {
+	cfg := client.cfg()
+	if cfg.TopP != nil {
+		requestBody["top_p"] = *cfg.TopP
+	}
+
+	effort := cfg.ReasoningEffort
+	if client.isQwen3Model(model) {
+		// Thinking stays disabled unless a reasoning effort is configured, so the model
+		// does not waste tokens on <think> internal reasoning (vLLM chat_template_kwargs)
+		requestBody["chat_template_kwargs"] = map[string]interface{}{
+			"enable_thinking": effort != "",
+		}
+		return
+	}
+	if effort != "" && reasoningEffortProviders[client.Provider] {
+		requestBody["reasoning_effort"] = effort
+	}
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import "testing"
+
+func TestClient_SetGenerationParams(t *testing.T) {
+	client := NewOpenAIClientWithOptions(WithMaxTokens(2000)).(*OpenAIClient)
+
+	temperature, topP := 0.1, 0.9
+	client.SetGenerationParams(GenerationParams{
+		Temperature:     &temperature,
+		TopP:            &topP,
+		MaxTokens:       8000,
+		ReasoningEffort: ReasoningEffortLow,
+	})
+
+	body := client.buildMCPRequestBody("system", "user")
+	if body["temperature"] != 0.1 {
+		t.Errorf("expected temperature 0.1, got %v", body["temperature"])
+	}
+	if body["top_p"] != 0.9 {
+		t.Errorf("expected top_p 0.9, got %v", body["top_p"])
+	}
+	if body["max_completion_tokens"] != 8000 {
+		t.Errorf("expected max_completion_tokens 8000, got %v", body["max_completion_tokens"])
+	}
+	if body["reasoning_effort"] != ReasoningEffortLow {
+		t.Errorf("expected reasoning_effort low, got %v", body["reasoning_effort"])
+	}
+
+	// Unset params fall back to the creation-time config
+	client.SetGenerationParams(GenerationParams{})
+	body = client.buildMCPRequestBody("system", "user")
+	if body["temperature"] != MCPClientTemperature {
+		t.Errorf("expected default temperature, got %v", body["temperature"])
+	}
+	if _, ok := body["top_p"]; ok {
+		t.Error("top_p should be omitted after reset")
+	}
+	if body["max_completion_tokens"] != 2000 {
+		t.Errorf("expected max_completion_tokens 2000, got %v", body["max_completion_tokens"])
+	}
+	if _, ok := body["reasoning_effort"]; ok {
+		t.Error("reasoning_effort should be omitted after reset")
+	}
+}
+
+func TestClient_SetGenerationParams_ReasoningByProvider(t *testing.T) {
+	params := GenerationParams{ReasoningEffort: ReasoningEffortHigh}
+
+	deepseek := NewDeepSeekClientWithOptions().(*DeepSeekClient)
+	deepseek.SetGenerationParams(params)
+	if _, ok := deepseek.buildMCPRequestBody("system", "user")["reasoning_effort"]; ok {
+		t.Error("DeepSeek should not receive reasoning_effort")
+	}
+
+	qwen3 := NewLocalAIClientWithOptions(WithModel("Qwen/Qwen3-32B")).(*LocalAIClient)
+	qwen3.SetGenerationParams(params)
+	kwargs, ok := qwen3.buildMCPRequestBody("system", "user")["chat_template_kwargs"].(map[string]interface{})
+	if !ok || kwargs["enable_thinking"] != true {
+		t.Errorf("Qwen3 thinking should be enabled, got %v", kwargs)
+	}
+
+	claude := NewClaudeClientWithOptions(WithMaxTokens(2000)).(*ClaudeClient)
+	claude.SetGenerationParams(params)
+	body := claude.buildMCPRequestBody("system", "user")
+	thinking, ok := body["thinking"].(map[string]any)
+	if !ok || thinking["budget_tokens"] != 16384 {
+		t.Fatalf("expected Claude thinking budget 16384, got %v", body["thinking"])
+	}
+	if maxTokens, _ := body["max_tokens"].(int); maxTokens <= 16384 {
+		t.Errorf("max_tokens (%d) must exceed thinking budget", maxTokens)
+	}
+	if _, ok := body["temperature"]; ok {
+		t.Error("temperature must be omitted with extended thinking")
+	}
+}
+
+func TestClient_SetGenerationParams_ClonedClient(t *testing.T) {
+	base := NewClient(WithAPIKey("test-key")).(*Client)
+	clone := *base
+
+	temperature := 1.2
+	clone.SetGenerationParams(GenerationParams{Temperature: &temperature})
+
+	if base.config.Temperature != MCPClientTemperature {
+		t.Errorf("base client temperature changed to %v", base.config.Temperature)
+	}
+	if clone.config.Temperature != 1.2 {
+		t.Errorf("expected clone temperature 1.2, got %v", clone.config.Temperature)
+	}
+}
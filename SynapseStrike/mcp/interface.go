@@ -9,6 +9,7 @@ import (
 type AIClient interface {
 	SetAPIKey(apiKey string, customURL string, customModel string)
 	SetTimeout(timeout time.Duration)
+	SetGenerationParams(params GenerationParams) // Temperature, top_p, max tokens and reasoning effort for subsequent calls
 	CallWithMessages(systemPrompt, userPrompt string) (string, error)
 	CallWithRequest(req *Request) (string, error) // Builder pattern API (supports advanced features)
 	GetProvider() string
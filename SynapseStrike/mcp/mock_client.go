@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ProviderMock provider name reported by MockClient
+const ProviderMock = "mock"
+
+// MockCall records a single call made to MockClient
+type MockCall struct {
+	SystemPrompt string
+	UserPrompt   string
+	Request      *Request // Only set for CallWithRequest
+}
+
+// MockResponse canned response (Content or Err)
+type MockResponse struct {
+	Content   string
+	Reasoning string // Native reasoning returned with the content (see NativeReasoning)
+	Err       error
+}
+
+// MockClient AIClient implementation returning canned responses (for testing decision logic without network)
+//
+// Responses are consumed in order; when exhausted, the last response is repeated.
+// Set ResponseFunc to compute responses dynamically from the prompts.
+//
+// Usage example:
+//
+//	client := mcp.NewMockClient("<reasoning>...</reasoning><decision>[...]</decision>")
+//	fd, err := decision.GetFullDecisionWithStrategy(ctx, client, engine, "balanced")
+type MockClient struct {
+	Provider        string
+	Model           string
+	Responses       []MockResponse
+	ResponseFunc    func(systemPrompt, userPrompt string) (string, error)
+	Params          GenerationParams // Last params passed to SetGenerationParams
+	NativeReasoning bool             // Report canned reasoning as provider-native reasoning
+
+	mu            sync.Mutex
+	calls         []MockCall
+	next          int
+	lastReasoning string
+}
+
+// NewMockClient creates mock client returning given responses in order
+func NewMockClient(responses ...string) *MockClient {
+	m := &MockClient{
+		Provider: ProviderMock,
+		Model:    "mock-model",
+	}
+	for _, r := range responses {
+		m.Responses = append(m.Responses, MockResponse{Content: r})
+	}
+	return m
+}
+
+// AddResponse appends a successful response
+func (m *MockClient) AddResponse(content string) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Responses = append(m.Responses, MockResponse{Content: content})
+	return m
+}
+
+// AddError appends a failing response
+func (m *MockClient) AddError(err error) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Responses = append(m.Responses, MockResponse{Err: err})
+	return m
+}
+
+func (m *MockClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	if customModel != "" {
+		m.Model = customModel
+	}
+}
+
+func (m *MockClient) SetTimeout(timeout time.Duration) {}
+
+func (m *MockClient) SetGenerationParams(params GenerationParams) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Params = params
+}
+
+func (m *MockClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return m.respond(MockCall{SystemPrompt: systemPrompt, UserPrompt: userPrompt})
+}
+
+func (m *MockClient) CallWithRequest(req *Request) (string, error) {
+	call := MockCall{Request: req}
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			call.SystemPrompt = msg.Content
+		case "user":
+			call.UserPrompt = msg.Content
+		}
+	}
+	return m.respond(call)
+}
+
+func (m *MockClient) GetProvider() string {
+	return m.Provider
+}
+
+func (m *MockClient) GetModel() string {
+	return m.Model
+}
+
+// CapturesNativeReasoning whether canned reasoning is reported as native reasoning
+func (m *MockClient) CapturesNativeReasoning() bool {
+	return m.NativeReasoning
+}
+
+// LastReasoning canned reasoning of the most recent response
+func (m *MockClient) LastReasoning() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastReasoning
+}
+
+// Calls returns all recorded calls
+func (m *MockClient) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall{}, m.calls...)
+}
+
+// CallCount returns number of calls made
+func (m *MockClient) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// respond records call and returns next canned response
+func (m *MockClient) respond(call MockCall) (string, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	fn := m.ResponseFunc
+	if fn == nil {
+		defer m.mu.Unlock()
+		if len(m.Responses) == 0 {
+			return "", fmt.Errorf("mock client has no canned responses")
+		}
+		idx := m.next
+		if idx >= len(m.Responses) {
+			idx = len(m.Responses) - 1
+		} else {
+			m.next++
+		}
+		r := m.Responses[idx]
+		m.lastReasoning = r.Reasoning
+		return r.Content, r.Err
+	}
+	m.mu.Unlock()
+	return fn(call.SystemPrompt, call.UserPrompt)
+}
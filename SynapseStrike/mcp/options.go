@@ -68,6 +68,16 @@ func WithRetryWaitBase(waitTime time.Duration) ClientOption {
 	}
 }
 
+// WithRetryWaitMax sets maximum retry wait duration (caps exponential backoff)
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithRetryWaitMax(10 * time.Second))
+func WithRetryWaitMax(waitTime time.Duration) ClientOption {
+	return func(c *Config) {
+		c.RetryWaitMax = waitTime
+	}
+}
+
 // ============================================================
 // AI Parameter Options
 // ============================================================
@@ -92,6 +102,36 @@ func WithTemperature(temperature float64) ClientOption {
 	}
 }
 
+// WithTopP sets nucleus sampling parameter
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithTopP(0.9))
+func WithTopP(topP float64) ClientOption {
+	return func(c *Config) {
+		c.TopP = &topP
+	}
+}
+
+// WithReasoningEffort sets reasoning effort of reasoning-capable models ("low", "medium", "high")
+//
+// Usage example:
+//   client := mcp.NewOpenAIClientWithOptions(mcp.WithReasoningEffort(mcp.ReasoningEffortLow))
+func WithReasoningEffort(effort string) ClientOption {
+	return func(c *Config) {
+		c.ReasoningEffort = effort
+	}
+}
+
+// WithNativeReasoning captures reasoning returned outside the response content (Claude thinking, DeepSeek R1)
+//
+// Usage example:
+//   client := mcp.NewDeepSeekClientWithOptions(mcp.WithModel("deepseek-reasoner"), mcp.WithNativeReasoning())
+func WithNativeReasoning() ClientOption {
+	return func(c *Config) {
+		c.NativeReasoning = true
+	}
+}
+
 // ============================================================
 // Provider Configuration Options
 // ============================================================
@@ -117,6 +157,13 @@ func WithModel(model string) ClientOption {
 	}
 }
 
+// WithEmbeddingModel sets embedding model name (used by Embed)
+func WithEmbeddingModel(model string) ClientOption {
+	return func(c *Config) {
+		c.EmbeddingModel = model
+	}
+}
+
 // WithProvider sets provider
 func WithProvider(provider string) ClientOption {
 	return func(c *Config) {
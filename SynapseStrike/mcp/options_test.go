@@ -82,6 +82,32 @@ func TestWithMaxRetries(t *testing.T) {
 	}
 }
 
+func TestWithRetryWaitMax(t *testing.T) {
+	cfg := DefaultConfig()
+	WithRetryWaitMax(10 * time.Second)(cfg)
+
+	if cfg.RetryWaitMax != 10*time.Second {
+		t.Errorf("expected 10s, got %v", cfg.RetryWaitMax)
+	}
+}
+
+func TestDefaultConfig_EnvOverrides(t *testing.T) {
+	t.Setenv("AI_REQUEST_TIMEOUT_SECONDS", "45")
+	t.Setenv("AI_MAX_RETRIES", "2")
+	t.Setenv("AI_RETRY_WAIT_BASE_SECONDS", "1")
+
+	cfg := DefaultConfig()
+	if cfg.Timeout != 45*time.Second || cfg.HTTPClient.Timeout != 45*time.Second {
+		t.Errorf("expected 45s timeout, got %v / %v", cfg.Timeout, cfg.HTTPClient.Timeout)
+	}
+	if cfg.MaxRetries != 2 {
+		t.Errorf("expected 2 retries, got %d", cfg.MaxRetries)
+	}
+	if cfg.RetryWaitBase != time.Second {
+		t.Errorf("expected 1s retry wait base, got %v", cfg.RetryWaitBase)
+	}
+}
+
 func TestWithTimeout(t *testing.T) {
 	cfg := DefaultConfig()
 	WithTimeout(60 * time.Second)(cfg)
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Ping checks that the AI provider is reachable and accepts the API key, without spending tokens
+// Sends GET {BaseURL}/models (the origin of BaseURL when UseFullURL is set) with the provider's auth
+// headers. Any answer other than 401/403 or a 5xx counts as reachable: a 404 only means the provider
+// has no model listing endpoint. Clients without a base URL (local function) have nothing to reach.
+func (client *Client) Ping(ctx context.Context) error {
+	if client.BaseURL == "" {
+		return nil
+	}
+
+	pingURL := strings.TrimSuffix(client.BaseURL, "/") + "/models"
+	if client.UseFullURL {
+		u, err := url.Parse(client.BaseURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q", client.BaseURL)
+		}
+		pingURL = u.Scheme + "://" + u.Host
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pingURL, nil)
+	if err != nil {
+		return fmt.Errorf("fail to build request: %w", err)
+	}
+	client.hooks.setAuthHeader(req.Header)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("API key rejected (status %d)", resp.StatusCode)
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("provider error (status %d)", resp.StatusCode)
+	}
+	return nil
+}
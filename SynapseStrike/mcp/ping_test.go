@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientPing(t *testing.T) {
+	status := http.StatusOK
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL+"/v1"), WithAPIKey("sk-test")).(*Client)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("expected reachable provider, got %v", err)
+	}
+	if gotPath != "/v1/models" || gotAuth != "Bearer sk-test" {
+		t.Errorf("expected authenticated GET /v1/models, got %s (auth %q)", gotPath, gotAuth)
+	}
+
+	status = http.StatusNotFound
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("404 means reachable without model listing, got %v", err)
+	}
+	for _, status = range []int{http.StatusUnauthorized, http.StatusBadGateway} {
+		if err := client.Ping(context.Background()); err == nil {
+			t.Errorf("expected error for status %d", status)
+		}
+	}
+
+	status = http.StatusOK
+	full := NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL+"/custom/chat"), WithUseFullURL(true)).(*Client)
+	if err := full.Ping(context.Background()); err != nil || gotPath != "/" {
+		t.Errorf("expected full URL client to ping the origin, got path %q, err %v", gotPath, err)
+	}
+}
+
+func TestClientPingWithoutBaseURL(t *testing.T) {
+	client := NewLocalFuncClient().(*LocalFuncClient)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("local function client has nothing to reach, got %v", err)
+	}
+}
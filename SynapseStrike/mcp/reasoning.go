@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Native Reasoning Capture
+// ============================================================================
+// Some models return their reasoning in a separate channel instead of the
+// <reasoning> tags the decision prompt asks for: Claude extended thinking
+// ("thinking" content blocks), DeepSeek R1 / Qwen3 thinking
+// ("reasoning_content" next to the message content) and Gemini thinking
+// models (thought summaries, requested with include_thoughts and returned as a
+// <thought> block in front of the content). With GenerationParams.NativeReasoning
+// the client keeps that reasoning of its most recent call (LastReasoning), so
+// the decision engine can store it as the chain of thought and stop insisting
+// on a <reasoning> block the model already wrote elsewhere.
+
+// ReasoningCapturer AI client exposing provider-native reasoning
+// All clients embedding Client implement it; use NativeReasoningOf for any AIClient.
+type ReasoningCapturer interface {
+	// CapturesNativeReasoning whether native reasoning is enabled and returned by the configured model
+	CapturesNativeReasoning() bool
+	// LastReasoning native reasoning of the most recent successful call ("" = none)
+	LastReasoning() string
+}
+
+var reGeminiThought = regexp.MustCompile(`(?s)^\s*<thought>(.*?)</thought>\s*`)
+
+// CapturesNativeReasoning whether the client returns provider-native reasoning
+func CapturesNativeReasoning(client AIClient) bool {
+	rc, ok := client.(ReasoningCapturer)
+	return ok && rc.CapturesNativeReasoning()
+}
+
+// NativeReasoningOf native reasoning of the client's most recent call ("" if not captured)
+func NativeReasoningOf(client AIClient) string {
+	rc, ok := client.(ReasoningCapturer)
+	if !ok || !rc.CapturesNativeReasoning() {
+		return ""
+	}
+	return rc.LastReasoning()
+}
+
+// CapturesNativeReasoning OpenAI-compatible responses carry reasoning_content for Qwen3 with thinking enabled
+// Providers with their own reasoning channel override this.
+func (client *Client) CapturesNativeReasoning() bool {
+	cfg := client.cfg()
+	return cfg.NativeReasoning && cfg.ReasoningEffort != "" && client.isQwen3Model(client.Model)
+}
+
+// LastReasoning native reasoning of the most recent successful call
+func (client *Client) LastReasoning() string {
+	reasoning, _ := client.lastReasoning.Load().(string)
+	return reasoning
+}
+
+// setLastReasoning records native reasoning of the current call
+func (client *Client) setLastReasoning(reasoning string) {
+	client.lastReasoning.Store(strings.TrimSpace(reasoning))
+}
+
+// splitGeminiThought separates a leading <thought> summary from Gemini response content
+func splitGeminiThought(content string) (thought, rest string) {
+	match := reGeminiThought.FindStringSubmatchIndex(content)
+	if match == nil {
+		return "", content
+	}
+	return strings.TrimSpace(content[match[2]:match[3]]), content[match[1]:]
+}
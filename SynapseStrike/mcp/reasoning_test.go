@@ -0,0 +1,80 @@
+package mcp
+
+import "testing"
+
+func TestClaudeClient_ParseThinking(t *testing.T) {
+	client := NewClaudeClientWithOptions(WithNativeReasoning(), WithReasoningEffort(ReasoningEffortLow)).(*ClaudeClient)
+	body := []byte(`{"content": [
+		{"type": "thinking", "thinking": "BTC is trending up"},
+		{"type": "text", "text": "<decision>[]</decision>"}
+	]}`)
+
+	content, err := client.parseMCPResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "<decision>[]</decision>" {
+		t.Errorf("unexpected content: %q", content)
+	}
+	if got := NativeReasoningOf(client); got != "BTC is trending up" {
+		t.Errorf("expected thinking as native reasoning, got %q", got)
+	}
+
+	// Without extended thinking there is no native channel
+	plain := NewClaudeClientWithOptions(WithNativeReasoning()).(*ClaudeClient)
+	if CapturesNativeReasoning(plain) {
+		t.Error("Claude without reasoning effort should not capture native reasoning")
+	}
+}
+
+func TestDeepSeekClient_ParseReasoningContent(t *testing.T) {
+	client := NewDeepSeekClientWithOptions(WithModel("deepseek-reasoner"), WithNativeReasoning()).(*DeepSeekClient)
+	body := []byte(`{"choices": [{"message": {"content": "<decision>[]</decision>", "reasoning_content": " check funding "}}]}`)
+
+	content, err := client.parseMCPResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "<decision>[]</decision>" {
+		t.Errorf("unexpected content: %q", content)
+	}
+	if got := NativeReasoningOf(client); got != "check funding" {
+		t.Errorf("expected reasoning_content as native reasoning, got %q", got)
+	}
+
+	chat := NewDeepSeekClientWithOptions(WithNativeReasoning())
+	if CapturesNativeReasoning(chat) {
+		t.Error("deepseek-chat should not capture native reasoning")
+	}
+	disabled := NewDeepSeekClientWithOptions(WithModel("deepseek-reasoner"))
+	if CapturesNativeReasoning(disabled) {
+		t.Error("native reasoning must be opt-in")
+	}
+}
+
+func TestGeminiClient_ThoughtSummary(t *testing.T) {
+	client := NewGeminiClientWithOptions(WithNativeReasoning()).(*GeminiClient)
+	if _, ok := client.buildMCPRequestBody("sys", "user")["extra_body"]; !ok {
+		t.Error("expected include_thoughts request with native reasoning")
+	}
+
+	body := []byte(`{"choices": [{"message": {"content": "<thought>Volume is thin</thought>\n<decision>[]</decision>"}}]}`)
+	content, err := client.parseMCPResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "<decision>[]</decision>" {
+		t.Errorf("expected thought removed from content, got %q", content)
+	}
+	if got := client.LastReasoning(); got != "Volume is thin" {
+		t.Errorf("expected thought as native reasoning, got %q", got)
+	}
+
+	// Later call without thought clears the previous reasoning
+	if _, err := client.parseMCPResponse([]byte(`{"choices": [{"message": {"content": "ok"}}]}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := client.LastReasoning(); got != "" {
+		t.Errorf("expected no reasoning, got %q", got)
+	}
+}
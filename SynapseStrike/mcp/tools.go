@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Tool-Calling Mode
+// ============================================================================
+// Text-protocol tool calling that works with every provider: the model emits
+//   <tool_call>{"name": "get_klines", "arguments": {"symbol": "AAPL", "timeframe": "15m"}}</tool_call>
+// and the loop executes the registered handler and feeds the result back as
+//   <tool_result name="get_klines">...</tool_result>
+// until the model answers without tool calls or the per-cycle call budget is spent.
+
+// DefaultToolCallBudget tool calls allowed per loop when budget is not set
+const DefaultToolCallBudget = 6
+
+// ToolHandler executes a tool call and returns text fed back to the model
+type ToolHandler func(args map[string]any) (string, error)
+
+// ToolCall tool invocation requested by the model
+type ToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// ToolCallRecord executed tool call with result (for logging / decision records)
+type ToolCallRecord struct {
+	Call   ToolCall `json:"call"`
+	Result string   `json:"result,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// ToolLoopResult final model response and executed tool calls
+type ToolLoopResult struct {
+	Response string           `json:"response"`
+	Calls    []ToolCallRecord `json:"calls"`
+	Rounds   int              `json:"rounds"`
+}
+
+type registeredTool struct {
+	def     FunctionDef
+	handler ToolHandler
+}
+
+// ToolRegistry named tools available to the model
+type ToolRegistry struct {
+	tools map[string]registeredTool
+}
+
+// NewToolRegistry creates empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]registeredTool)}
+}
+
+// Register adds a tool; parameters is a JSON Schema object describing arguments
+func (r *ToolRegistry) Register(name, description string, parameters map[string]any, handler ToolHandler) {
+	r.tools[name] = registeredTool{
+		def:     FunctionDef{Name: name, Description: description, Parameters: parameters},
+		handler: handler,
+	}
+}
+
+// Len number of registered tools
+func (r *ToolRegistry) Len() int {
+	return len(r.tools)
+}
+
+// Tools registered tools as API tool definitions (sorted by name)
+func (r *ToolRegistry) Tools() []Tool {
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]Tool, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, Tool{Type: "function", Function: r.tools[name].def})
+	}
+	return tools
+}
+
+// Execute runs a tool call
+func (r *ToolRegistry) Execute(call ToolCall) (string, error) {
+	tool, ok := r.tools[call.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool: %s", call.Name)
+	}
+	if call.Arguments == nil {
+		call.Arguments = map[string]any{}
+	}
+	return tool.handler(call.Arguments)
+}
+
+// PromptSection tool usage instructions appended to the system prompt
+func (r *ToolRegistry) PromptSection(budget int) string {
+	var sb strings.Builder
+	sb.WriteString("# 🔧 Tools (optional, before your decision)\n\n")
+	sb.WriteString(fmt.Sprintf("You may request additional data with up to %d tool calls in total. ", budget))
+	sb.WriteString("To call tools, respond ONLY with one or more lines of the form:\n\n")
+	sb.WriteString("<tool_call>{\"name\": \"TOOL_NAME\", \"arguments\": {...}}</tool_call>\n\n")
+	sb.WriteString("Results are returned in <tool_result> tags. When you have enough information, respond with the normal <reasoning>/<decision> output and no tool calls.\n\n")
+	sb.WriteString("Available tools:\n")
+	for _, tool := range r.Tools() {
+		params, _ := json.Marshal(tool.Function.Parameters)
+		sb.WriteString(fmt.Sprintf("- `%s`: %s Arguments: %s\n", tool.Function.Name, tool.Function.Description, string(params)))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+var reToolCall = regexp.MustCompile(`(?s)<tool_call>\s*(.*?)\s*</tool_call>`)
+
+// ParseToolCalls extracts tool calls from a model response (malformed calls are returned with empty name)
+func ParseToolCalls(response string) []ToolCall {
+	matches := reToolCall.FindAllStringSubmatch(response, -1)
+	calls := make([]ToolCall, 0, len(matches))
+	for _, m := range matches {
+		var call ToolCall
+		if err := json.Unmarshal([]byte(m[1]), &call); err != nil {
+			call = ToolCall{}
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// RunToolLoop calls the model, executing requested tools until it answers without tool calls
+// The conversation is replayed inside the user prompt so it works with CallWithMessages on every provider.
+// Once budget tool calls have been executed, further calls are refused and the model is asked to decide.
+func RunToolLoop(client AIClient, systemPrompt, userPrompt string, registry *ToolRegistry, budget int) (*ToolLoopResult, error) {
+	if budget <= 0 {
+		budget = DefaultToolCallBudget
+	}
+	result := &ToolLoopResult{}
+	transcript := userPrompt
+
+	// Each round executes at least one call, so budget+1 rounds always reach a final answer request
+	for round := 1; round <= budget+1; round++ {
+		result.Rounds = round
+		response, err := client.CallWithMessages(systemPrompt, transcript)
+		if err != nil {
+			return result, err
+		}
+
+		calls := ParseToolCalls(response)
+		if len(calls) == 0 || strings.Contains(response, "<decision>") {
+			result.Response = response
+			return result, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("\n\n---\n# Your tool calls (round %d)\n\n", round))
+		for _, m := range reToolCall.FindAllString(response, -1) {
+			sb.WriteString(m + "\n")
+		}
+		sb.WriteString("\n# Tool results\n\n")
+		for _, call := range calls {
+			record := ToolCallRecord{Call: call}
+			switch {
+			case call.Name == "":
+				record.Error = "malformed tool call, expected {\"name\": ..., \"arguments\": {...}}"
+			case len(result.Calls) >= budget:
+				record.Error = "tool call budget exhausted"
+			default:
+				output, err := registry.Execute(call)
+				if err != nil {
+					record.Error = err.Error()
+				} else {
+					record.Result = output
+				}
+			}
+			result.Calls = append(result.Calls, record)
+
+			content := record.Result
+			if record.Error != "" {
+				content = "ERROR: " + record.Error
+			}
+			sb.WriteString(fmt.Sprintf("<tool_result name=\"%s\">\n%s\n</tool_result>\n", call.Name, content))
+		}
+
+		remaining := budget - len(result.Calls)
+		if remaining <= 0 {
+			sb.WriteString("\nTool call budget exhausted. Respond now with your final <reasoning>/<decision> output and no tool calls.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\n%d tool calls remaining. Call more tools or respond with your final <reasoning>/<decision> output.\n", remaining))
+		}
+		transcript += sb.String()
+	}
+
+	return result, fmt.Errorf("model kept requesting tools after budget of %d calls", budget)
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newEchoRegistry(executed *int) *ToolRegistry {
+	registry := NewToolRegistry()
+	registry.Register("echo", "Echo text.", map[string]any{"type": "object"}, func(args map[string]any) (string, error) {
+		*executed++
+		return fmt.Sprintf("echo: %v", args["text"]), nil
+	})
+	return registry
+}
+
+func TestParseToolCalls(t *testing.T) {
+	calls := ParseToolCalls(`<tool_call>{"name": "echo", "arguments": {"text": "hi"}}</tool_call>
+<tool_call>not json</tool_call>`)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[0].Name != "echo" || calls[0].Arguments["text"] != "hi" {
+		t.Errorf("unexpected first call: %+v", calls[0])
+	}
+	if calls[1].Name != "" {
+		t.Errorf("expected malformed call with empty name, got %+v", calls[1])
+	}
+}
+
+func TestRunToolLoop(t *testing.T) {
+	executed := 0
+	client := NewMockClient(
+		`<tool_call>{"name": "echo", "arguments": {"text": "AAPL"}}</tool_call>`,
+		"<reasoning>done</reasoning><decision>[]</decision>",
+	)
+
+	result, err := RunToolLoop(client, "system", "user", newEchoRegistry(&executed), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != 1 || len(result.Calls) != 1 || result.Rounds != 2 {
+		t.Errorf("expected 1 call in 2 rounds, got %d calls / %d rounds", len(result.Calls), result.Rounds)
+	}
+	if !strings.Contains(result.Response, "<decision>") {
+		t.Errorf("unexpected final response: %s", result.Response)
+	}
+	calls := client.Calls()
+	if !strings.Contains(calls[1].UserPrompt, `<tool_result name="echo">`) || !strings.Contains(calls[1].UserPrompt, "echo: AAPL") {
+		t.Errorf("expected tool result in follow-up prompt, got: %s", calls[1].UserPrompt)
+	}
+}
+
+func TestRunToolLoopBudget(t *testing.T) {
+	executed := 0
+	client := NewMockClient(
+		`<tool_call>{"name": "echo", "arguments": {"text": "1"}}</tool_call><tool_call>{"name": "echo", "arguments": {"text": "2"}}</tool_call>`,
+		"<reasoning>done</reasoning><decision>[]</decision>",
+	)
+
+	result, err := RunToolLoop(client, "system", "user", newEchoRegistry(&executed), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != 1 {
+		t.Errorf("expected budget to allow 1 execution, got %d", executed)
+	}
+	if len(result.Calls) != 2 || result.Calls[1].Error != "tool call budget exhausted" {
+		t.Errorf("expected second call refused, got %+v", result.Calls)
+	}
+	if !strings.Contains(client.Calls()[1].UserPrompt, "budget exhausted") {
+		t.Error("expected budget exhausted notice in follow-up prompt")
+	}
+}
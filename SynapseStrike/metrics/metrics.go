@@ -288,6 +288,17 @@ var (
 		[]string{"trader_id", "ai_model"},
 	)
 
+	// DecisionErrorsTotal tracks decision record (scope "cycle") and action (scope "action") errors by category
+	DecisionErrorsTotal = promauto.With(Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "synapsestrike",
+			Subsystem: "decision",
+			Name:      "errors_total",
+			Help:      "Total number of decision errors by category",
+		},
+		[]string{"trader_id", "scope", "category"},
+	)
+
 	// ============================================
 	// System Metrics
 	// ============================================
@@ -412,6 +423,11 @@ func RecordAICall(traderID, aiModel string, durationMs int64, hasError bool) {
 	}
 }
 
+// RecordDecisionError increments the decision error counter of a category
+func RecordDecisionError(traderID, scope, category string) {
+	DecisionErrorsTotal.WithLabelValues(traderID, scope, category).Inc()
+}
+
 // RecordCycleDuration records a trading cycle duration
 func RecordCycleDuration(traderID string, durationSeconds float64) {
 	TraderCycleDuration.WithLabelValues(traderID).Observe(durationSeconds)
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpClient shared HTTP client for all channels
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// postJSON sends JSON payload and checks response status
+func postJSON(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// ============================================================================
+// Telegram
+// ============================================================================
+
+// TelegramChannel sends messages via Telegram Bot API
+type TelegramChannel struct {
+	BotToken string
+	ChatID   string
+	BaseURL  string // Default: https://api.telegram.org
+}
+
+// NewTelegramChannel creates Telegram channel
+func NewTelegramChannel(botToken, chatID string) *TelegramChannel {
+	return &TelegramChannel{
+		BotToken: botToken,
+		ChatID:   chatID,
+		BaseURL:  "https://api.telegram.org",
+	}
+}
+
+func (c *TelegramChannel) Name() string { return "telegram" }
+
+func (c *TelegramChannel) Send(ctx context.Context, event *Event) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", c.BaseURL, c.BotToken)
+	return postJSON(ctx, url, map[string]interface{}{
+		"chat_id":                  c.ChatID,
+		"text":                     event.Text(),
+		"disable_web_page_preview": true,
+	})
+}
+
+// ============================================================================
+// Discord
+// ============================================================================
+
+// DiscordChannel sends messages via Discord webhook
+type DiscordChannel struct {
+	WebhookURL string
+}
+
+// NewDiscordChannel creates Discord channel
+func NewDiscordChannel(webhookURL string) *DiscordChannel {
+	return &DiscordChannel{WebhookURL: webhookURL}
+}
+
+func (c *DiscordChannel) Name() string { return "discord" }
+
+func (c *DiscordChannel) Send(ctx context.Context, event *Event) error {
+	// Discord limits message content to 2000 characters
+	content := event.Text()
+	if len(content) > 2000 {
+		content = content[:1997] + "..."
+	}
+	return postJSON(ctx, c.WebhookURL, map[string]interface{}{
+		"content": content,
+	})
+}
+
+// ============================================================================
+// Generic Webhook
+// ============================================================================
+
+// WebhookChannel posts raw event JSON to arbitrary URL
+type WebhookChannel struct {
+	URL string
+}
+
+// NewWebhookChannel creates generic webhook channel
+func NewWebhookChannel(url string) *WebhookChannel {
+	return &WebhookChannel{URL: url}
+}
+
+func (c *WebhookChannel) Name() string { return "webhook" }
+
+func (c *WebhookChannel) Send(ctx context.Context, event *Event) error {
+	payload := struct {
+		*Event
+		Text      string `json:"text"`
+		Timestamp string `json:"timestamp"`
+	}{
+		Event:     event,
+		Text:      event.Text(),
+		Timestamp: event.Timestamp.Format(time.RFC3339),
+	}
+	return postJSON(ctx, c.URL, payload)
+}
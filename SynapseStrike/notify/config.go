@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"SynapseStrike/config"
+)
+
+// NewFromConfig creates notifier from global configuration
+// Returns nil if no channel is configured
+func NewFromConfig(cfg *config.Config) *Notifier {
+	var channels []Channel
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+		channels = append(channels, NewTelegramChannel(cfg.TelegramBotToken, cfg.TelegramChatID))
+	}
+	if cfg.DiscordWebhookURL != "" {
+		channels = append(channels, NewDiscordChannel(cfg.DiscordWebhookURL))
+	}
+	if cfg.NotifyWebhookURL != "" {
+		channels = append(channels, NewWebhookChannel(cfg.NotifyWebhookURL))
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	return New(channels, ParseEvents(cfg.NotifyEvents))
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"SynapseStrike/logger"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType notification event type
+type EventType string
+
+const (
+	EventPositionOpened  EventType = "position_opened"  // Position opened by trader
+	EventPositionClosed  EventType = "position_closed"  // Position closed (AI decision, EOD, manual, etc.)
+	EventStopLossHit     EventType = "stop_loss_hit"    // Exchange-side stop loss triggered
+	EventTakeProfitHit   EventType = "take_profit_hit"  // Exchange-side take profit triggered
+	EventCircuitBreaker  EventType = "circuit_breaker"  // Risk control circuit breaker triggered
+	EventAIFallback      EventType = "ai_fallback"      // AI decision failed, algorithmic fallback engaged
+	EventLiquidationRisk EventType = "liquidation_risk" // Mark price approaching liquidation price
+	EventDailySummary    EventType = "daily_summary"    // Daily P&L summary
+)
+
+// AllEvents all supported event types
+var AllEvents = []EventType{
+	EventPositionOpened,
+	EventPositionClosed,
+	EventStopLossHit,
+	EventTakeProfitHit,
+	EventCircuitBreaker,
+	EventAIFallback,
+	EventLiquidationRisk,
+	EventDailySummary,
+}
+
+// sendTimeout maximum time allowed for a single channel delivery
+const sendTimeout = 10 * time.Second
+
+// Event notification event
+type Event struct {
+	Type       EventType         `json:"type"`
+	TraderID   string            `json:"trader_id"`
+	TraderName string            `json:"trader_name"`
+	Symbol     string            `json:"symbol,omitempty"`
+	Title      string            `json:"title"`
+	Message    string            `json:"message"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// Text formats event as plain text (used by chat channels)
+func (e *Event) Text() string {
+	var sb strings.Builder
+	sb.WriteString(e.Title)
+	if e.TraderName != "" {
+		sb.WriteString(fmt.Sprintf(" [%s]", e.TraderName))
+	}
+	if e.Message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(e.Message)
+	}
+
+	// Sort field keys for stable output
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("\n• %s: %s", k, e.Fields[k]))
+	}
+	return sb.String()
+}
+
+// Channel notification delivery channel (Telegram, Discord, webhook, ...)
+type Channel interface {
+	// Name returns channel name (for logging)
+	Name() string
+	// Send delivers event, must respect ctx cancellation
+	Send(ctx context.Context, event *Event) error
+}
+
+// Notifier dispatches events to all configured channels
+type Notifier struct {
+	channels []Channel
+	events   map[EventType]bool // Enabled events (empty = all events)
+	wg       sync.WaitGroup
+}
+
+// New creates notifier
+// events: enabled event types, nil or empty means all events are enabled
+func New(channels []Channel, events []EventType) *Notifier {
+	n := &Notifier{
+		channels: channels,
+		events:   make(map[EventType]bool),
+	}
+	for _, e := range events {
+		n.events[e] = true
+	}
+	return n
+}
+
+// Enabled checks if event type should be delivered
+func (n *Notifier) Enabled(eventType EventType) bool {
+	if n == nil || len(n.channels) == 0 {
+		return false
+	}
+	if len(n.events) == 0 {
+		return true
+	}
+	return n.events[eventType]
+}
+
+// Channels returns configured channel names
+func (n *Notifier) Channels() []string {
+	if n == nil {
+		return nil
+	}
+	names := make([]string, 0, len(n.channels))
+	for _, c := range n.channels {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// Notify delivers event asynchronously to all channels (never blocks the trading loop)
+func (n *Notifier) Notify(event Event) {
+	if !n.Enabled(event.Type) {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	for _, ch := range n.channels {
+		n.wg.Add(1)
+		go func(ch Channel) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := ch.Send(ctx, &event); err != nil {
+				logger.Warnf("⚠️ Notification via %s failed (%s): %v", ch.Name(), event.Type, err)
+			}
+		}(ch)
+	}
+}
+
+// Wait waits for all in-flight deliveries to finish
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// ParseEvents parses comma-separated event list, unknown events are ignored
+func ParseEvents(s string) []EventType {
+	var events []EventType
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			return nil
+		}
+		for _, e := range AllEvents {
+			if string(e) == name {
+				events = append(events, e)
+				break
+			}
+		}
+	}
+	return events
+}
+
+// ============================================================================
+// Global Notifier
+// ============================================================================
+
+var (
+	global   *Notifier
+	globalMu sync.RWMutex
+)
+
+// SetGlobal sets global notifier used by Publish
+func SetGlobal(n *Notifier) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = n
+}
+
+// Global returns global notifier (may be nil)
+func Global() *Notifier {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Publish delivers event via global notifier (no-op if not configured)
+func Publish(event Event) {
+	if n := Global(); n != nil {
+		n.Notify(event)
+	}
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordingChannel records delivered events
+type recordingChannel struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+
+func (c *recordingChannel) Send(ctx context.Context, event *Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, *event)
+	return nil
+}
+
+// TestNotifier_EventFilter tests that only enabled events are delivered
+func TestNotifier_EventFilter(t *testing.T) {
+	ch := &recordingChannel{}
+	n := New([]Channel{ch}, []EventType{EventPositionOpened})
+
+	n.Notify(Event{Type: EventPositionOpened, Symbol: "AAPL"})
+	n.Notify(Event{Type: EventAIFallback})
+	n.Wait()
+
+	if len(ch.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(ch.events))
+	}
+	if ch.events[0].Symbol != "AAPL" {
+		t.Errorf("unexpected symbol: %s", ch.events[0].Symbol)
+	}
+	if ch.events[0].Timestamp.IsZero() {
+		t.Error("timestamp should be filled in")
+	}
+}
+
+// TestNotifier_NilSafe tests that nil notifier and Publish without global are no-ops
+func TestNotifier_NilSafe(t *testing.T) {
+	var n *Notifier
+	if n.Enabled(EventDailySummary) {
+		t.Error("nil notifier should not be enabled")
+	}
+	n.Notify(Event{Type: EventDailySummary})
+	n.Wait()
+
+	SetGlobal(nil)
+	Publish(Event{Type: EventDailySummary})
+}
+
+// TestParseEvents tests event list parsing
+func TestParseEvents(t *testing.T) {
+	events := ParseEvents(" position_opened, STOP_LOSS_HIT ,unknown")
+	if len(events) != 2 || events[0] != EventPositionOpened || events[1] != EventStopLossHit {
+		t.Errorf("unexpected events: %v", events)
+	}
+	if events := ParseEvents("all"); events != nil {
+		t.Errorf("'all' should enable every event, got %v", events)
+	}
+}
+
+// TestWebhookChannel tests that webhook channel posts event JSON
+func TestWebhookChannel(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New([]Channel{NewWebhookChannel(server.URL)}, nil)
+	n.Notify(Event{
+		Type:       EventPositionClosed,
+		TraderName: "scalper",
+		Symbol:     "TSLA",
+		Title:      "Closed LONG TSLA",
+		Fields:     map[string]string{"P&L": "+12.50"},
+	})
+	n.Wait()
+
+	if received["type"] != string(EventPositionClosed) {
+		t.Errorf("unexpected type: %v", received["type"])
+	}
+	if received["symbol"] != "TSLA" {
+		t.Errorf("unexpected symbol: %v", received["symbol"])
+	}
+	if text, _ := received["text"].(string); text == "" {
+		t.Error("text should not be empty")
+	}
+}
+
+// TestWebhookChannel_ErrorStatus tests that non-2xx responses are reported as errors
+func TestWebhookChannel_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewWebhookChannel(server.URL).Send(context.Background(), &Event{Type: EventDailySummary})
+	if err == nil {
+		t.Error("expected error for 400 response")
+	}
+}
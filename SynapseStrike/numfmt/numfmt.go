@@ -0,0 +1,128 @@
+package numfmt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Prompt Number Formatting
+// ============================================================================
+// Numbers written into AI prompts must be unambiguous: always a plain decimal
+// with "." as the decimal point and no thousands separators (never "1,234.5"
+// or "1.2e-05", which models misread), with enough significant digits that
+// sub-cent meme coin prices and indicators do not collapse to 0. Prices and
+// price-scale series use Price, money flows and OI changes use Flow, sizes
+// and notionals use Compact. Non-finite values render as "n/a".
+
+// compactUnits magnitude suffixes used by Compact and Flow, largest first
+var compactUnits = []struct {
+	scale  float64
+	suffix string
+}{
+	{1e12, "T"},
+	{1e9, "B"},
+	{1e6, "M"},
+	{1e3, "K"},
+}
+
+// maxDecimals upper bound of decimals for tiny values (1e-12 still shows 4 significant digits)
+const maxDecimals = 16
+
+// Decimal formats v with a fixed number of decimals, never in scientific notation
+func Decimal(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "n/a"
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if isNegativeZero(s) {
+		return s[1:]
+	}
+	return s
+}
+
+// Price formats a price (or price-scale value such as an EMA or MACD) with precision chosen by magnitude
+// >= 100: 2 decimals, >= 0.01: 4 decimals, >= 0.0001: 6 decimals, smaller: 4 significant digits.
+func Price(v float64) string {
+	return Decimal(v, priceDecimals(math.Abs(v)))
+}
+
+// priceDecimals decimals of a price of absolute value abs
+func priceDecimals(abs float64) int {
+	switch {
+	case abs >= 100:
+		return 2 // BTC, large caps: save tokens
+	case abs >= 0.01:
+		return 4 // 23.4567, 0.9954
+	case abs >= 0.0001:
+		return 6 // PEPE, SHIB: 0.005568
+	case abs == 0:
+		return 2
+	default:
+		// 1000SATS, DOGS: 0.00002070 (4 significant digits)
+		decimals := int(-math.Floor(math.Log10(abs))) + 3
+		return min(decimals, maxDecimals)
+	}
+}
+
+// Series formats values as "[a, b, c]" using Price precision per value
+func Series(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = Price(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// Compact formats a size or notional with a K/M/B/T suffix (1234567 → "1.23M"), Price precision below 1000
+func Compact(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "n/a"
+	}
+	if s, ok := withSuffix(v); ok {
+		return s
+	}
+	return Price(v)
+}
+
+// Flow formats a signed money flow or OI change with a K/M/B/T suffix ("+1.50M", "-820.00")
+func Flow(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "n/a"
+	}
+	s, ok := withSuffix(v)
+	if !ok {
+		s = Decimal(v, 2)
+	}
+	if !strings.HasPrefix(s, "-") {
+		s = "+" + s
+	}
+	return s
+}
+
+// Percent formats a ratio (0.0001) as a percentage with the given decimals ("0.0100%")
+func Percent(ratio float64, decimals int) string {
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return "n/a"
+	}
+	return Decimal(ratio*100, decimals) + "%"
+}
+
+// withSuffix formats |v| >= 1000 with 2 decimals and a magnitude suffix
+// The unit is chosen on the rounded value, so 999999 is "1.00M" rather than "1000.00K".
+func withSuffix(v float64) (string, bool) {
+	abs := math.Abs(v)
+	for _, unit := range compactUnits {
+		// Value in the next smaller unit (units are 1000 apart) as it would be printed
+		if math.Round(abs/(unit.scale/1000)*100)/100 >= 1000 {
+			return Decimal(v/unit.scale, 2) + unit.suffix, true
+		}
+	}
+	return "", false
+}
+
+// isNegativeZero whether a formatted value is "-0", "-0.00" etc.
+func isNegativeZero(s string) bool {
+	return strings.HasPrefix(s, "-") && strings.Trim(s[1:], "0.") == ""
+}
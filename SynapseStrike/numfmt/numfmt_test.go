@@ -0,0 +1,110 @@
+package numfmt
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPrice(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  string
+	}{
+		{45678.9123, "45678.91"},
+		{187.236, "187.24"},
+		{23.45678, "23.4568"},
+		{0.9954, "0.9954"},
+		{0.005568, "0.005568"},
+		{0.00015060, "0.000151"},
+		{0.00002070, "0.00002070"}, // Meme coins keep 4 significant digits
+		{0.0000000012345, "0.000000001235"},
+		{1e-20, "0.0000000000000000"}, // Capped at 16 decimals
+		{-0.00002070, "-0.00002070"},  // Negative MACD values use the same precision
+		{-12.5, "-12.5000"},
+		{0, "0.00"},
+		{-0.0000001, "-0.0000001000"},
+		{1e21, "1000000000000000000000.00"}, // %v would print 1e+21
+		{math.NaN(), "n/a"},
+		{math.Inf(1), "n/a"},
+	}
+	for _, tt := range tests {
+		if got := Price(tt.price); got != tt.want {
+			t.Errorf("Price(%v) = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		v        float64
+		decimals int
+		want     string
+	}{
+		{1234567.891, 2, "1234567.89"}, // No thousands separators
+		{-0.001, 2, "0.00"},            // No negative zero
+		{0.000012, 4, "0.0000"},
+		{1.5e-7, 9, "0.000000150"},
+	}
+	for _, tt := range tests {
+		if got := Decimal(tt.v, tt.decimals); got != tt.want {
+			t.Errorf("Decimal(%v, %d) = %q, want %q", tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestCompactAndFlow(t *testing.T) {
+	tests := []struct {
+		v       float64
+		compact string
+		flow    string
+	}{
+		{0, "0.00", "+0.00"},
+		{12.5, "12.5000", "+12.50"},
+		{-820, "-820.00", "-820.00"},
+		{999.994, "999.99", "+999.99"},
+		{999.996, "1.00K", "+1.00K"}, // Rounds into the next unit instead of "1000.00"
+		{1500, "1.50K", "+1.50K"},
+		{999999, "1.00M", "+1.00M"}, // Not "1000.00K"
+		{-2345678, "-2.35M", "-2.35M"},
+		{3.2e9, "3.20B", "+3.20B"},
+		{4.5e12, "4.50T", "+4.50T"},
+		{0.00002070, "0.00002070", "+0.00"},
+		{math.NaN(), "n/a", "n/a"},
+	}
+	for _, tt := range tests {
+		if got := Compact(tt.v); got != tt.compact {
+			t.Errorf("Compact(%v) = %q, want %q", tt.v, got, tt.compact)
+		}
+		if got := Flow(tt.v); got != tt.flow {
+			t.Errorf("Flow(%v) = %q, want %q", tt.v, got, tt.flow)
+		}
+	}
+}
+
+func TestPercentAndSeries(t *testing.T) {
+	if got := Percent(0.0001, 4); got != "0.0100%" {
+		t.Errorf("Percent(0.0001, 4) = %q, want 0.0100%%", got)
+	}
+	if got := Percent(-0.00000375, 4); got != "-0.0004%" {
+		t.Errorf("Percent(-0.00000375, 4) = %q, want -0.0004%%", got)
+	}
+	if got := Series([]float64{101.256, 0.5, 0.00001234, -0.0002}); got != "[101.26, 0.5000, 0.00001234, -0.000200]" {
+		t.Errorf("Series = %q", got)
+	}
+	if got := Series(nil); got != "[]" {
+		t.Errorf("Series(nil) = %q, want []", got)
+	}
+}
+
+// TestNoScientificNotation tests that no helper emits an exponent for any magnitude
+func TestNoScientificNotation(t *testing.T) {
+	for exp := -18; exp <= 18; exp++ {
+		v := 1.2345 * math.Pow(10, float64(exp))
+		for _, s := range []string{Price(v), Price(-v), Compact(v), Flow(-v), Percent(v, 4), Decimal(v, 2)} {
+			if strings.ContainsAny(s, "eE") {
+				t.Errorf("1.2345e%d formatted as %q", exp, s)
+			}
+		}
+	}
+}
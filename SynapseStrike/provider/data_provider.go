@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"SynapseStrike/cache"
+	"SynapseStrike/numfmt"
 	"SynapseStrike/security"
 	"encoding/json"
 	"fmt"
@@ -601,6 +603,9 @@ var oiTopConfig = struct {
 	Timeout: 30 * time.Second,
 }
 
+// oiCacheTTL OI snapshot cache duration (shared by traders and debate sessions of one cycle)
+var oiCacheTTL = 1 * time.Minute
+
 // GetOITopPositions retrieves OI Top 20 data (with retry)
 func GetOITopPositions() ([]OIPosition, error) {
 	if strings.TrimSpace(oiTopConfig.APIURL) == "" {
@@ -608,6 +613,12 @@ func GetOITopPositions() ([]OIPosition, error) {
 		return []OIPosition{}, nil
 	}
 
+	cacheKey := "oi_top:" + cache.HashKey(oiTopConfig.APIURL)
+	var cached []OIPosition
+	if cache.GetJSON(cacheKey, &cached) {
+		return cached, nil
+	}
+
 	maxRetries := 3
 	var lastErr error
 
@@ -622,6 +633,7 @@ func GetOITopPositions() ([]OIPosition, error) {
 			if attempt > 1 {
 				log.Printf("✓ Retry attempt %d succeeded", attempt)
 			}
+			cache.SetJSON(cacheKey, positions, oiCacheTTL)
 			return positions, nil
 		}
 
@@ -704,10 +716,10 @@ type OIRankingData struct {
 	FetchedAt    time.Time    `json:"fetched_at"`
 }
 
-// GetOIRankingData retrieves OI ranking data (both top increase and low decrease)
-func GetOIRankingData(baseURL, authKey string, duration string, limit int) (*OIRankingData, error) {
-	if baseURL == "" || authKey == "" {
-		return nil, fmt.Errorf("OI API URL or auth key not configured")
+// GetOIRankingData retrieves OI ranking data (both top increase and low decrease) from a source
+func GetOIRankingData(p OIRankingProvider, duration string, limit int) (*OIRankingData, error) {
+	if p == nil {
+		return nil, ErrOIRankingNotConfigured
 	}
 
 	if duration == "" {
@@ -717,64 +729,44 @@ func GetOIRankingData(baseURL, authKey string, duration string, limit int) (*OIR
 		limit = 20
 	}
 
+	cacheKey := fmt.Sprintf("oi_ranking:%s:%s:%d", cache.HashKey(p.Name()), duration, limit)
+	var cached OIRankingData
+	if cache.GetJSON(cacheKey, &cached) {
+		return &cached, nil
+	}
+
 	result := &OIRankingData{
 		Duration:  duration,
 		FetchedAt: time.Now(),
 	}
 
 	// Fetch top ranking
-	topURL := fmt.Sprintf("%s/api/oi/top-ranking?limit=%d&duration=%s&auth=%s", baseURL, limit, duration, authKey)
-	topPositions, timeRange, err := fetchOIRanking(topURL)
+	topPositions, timeRange, err := p.FetchOIRanking(OIRankTop, duration, limit)
 	if err != nil {
-		log.Printf("⚠️  Failed to fetch OI top ranking: %v", err)
+		log.Printf("⚠️  Failed to fetch OI top ranking from %s: %v", p.Name(), err)
 	} else {
 		result.TopPositions = topPositions
 		result.TimeRange = timeRange
 	}
 
 	// Fetch low ranking
-	lowURL := fmt.Sprintf("%s/api/oi/low-ranking?limit=%d&duration=%s&auth=%s", baseURL, limit, duration, authKey)
-	lowPositions, _, err := fetchOIRanking(lowURL)
+	lowPositions, _, err := p.FetchOIRanking(OIRankLow, duration, limit)
 	if err != nil {
-		log.Printf("⚠️  Failed to fetch OI low ranking: %v", err)
+		log.Printf("⚠️  Failed to fetch OI low ranking from %s: %v", p.Name(), err)
 	} else {
 		result.LowPositions = lowPositions
 	}
 
+	if result.TimeRange == "" {
+		result.TimeRange = duration
+	}
 	log.Printf("✓ Fetched OI ranking data: %d top, %d low (duration: %s)",
 		len(result.TopPositions), len(result.LowPositions), duration)
-
-	return result, nil
-}
-
-// fetchOIRanking fetches OI ranking from a single endpoint
-func fetchOIRanking(url string) ([]OIPosition, string, error) {
-	// SSRF Protection: Validate URL before making request
-	resp, err := security.SafeGet(url, 30*time.Second)
-	if err != nil {
-		return nil, "", fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	if len(result.TopPositions) > 0 || len(result.LowPositions) > 0 {
+		cache.SetJSON(cacheKey, result, oiCacheTTL)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var response OITopAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, "", fmt.Errorf("JSON parsing failed: %w", err)
-	}
-
-	if response.Code != 0 {
-		return nil, "", fmt.Errorf("API returned error code: %d", response.Code)
-	}
-
-	return response.Data.Positions, response.Data.TimeRange, nil
+	return result, nil
 }
 
 // FormatOIRankingForAI formats OI ranking data for AI consumption
@@ -796,7 +788,7 @@ func FormatOIRankingForAI(data *OIRankingData) string {
 			sb.WriteString(fmt.Sprintf("| #%d | %s | %s | %+.2f%% | %+.2f%% |\n",
 				pos.Rank,
 				pos.Symbol,
-				formatOIValue(pos.OIDeltaValue),
+				numfmt.Flow(pos.OIDeltaValue),
 				pos.OIDeltaPercent,
 				pos.PriceDeltaPercent,
 			))
@@ -814,7 +806,7 @@ func FormatOIRankingForAI(data *OIRankingData) string {
 			sb.WriteString(fmt.Sprintf("| #%d | %s | %s | %+.2f%% | %+.2f%% |\n",
 				pos.Rank,
 				pos.Symbol,
-				formatOIValue(pos.OIDeltaValue),
+				numfmt.Flow(pos.OIDeltaValue),
 				pos.OIDeltaPercent,
 				pos.PriceDeltaPercent,
 			))
@@ -826,26 +818,6 @@ func FormatOIRankingForAI(data *OIRankingData) string {
 	return sb.String()
 }
 
-// formatOIValue formats OI value for display
-func formatOIValue(v float64) string {
-	sign := ""
-	if v >= 0 {
-		sign = "+"
-	}
-	absV := v
-	if absV < 0 {
-		absV = -absV
-	}
-	if absV >= 1e9 {
-		return fmt.Sprintf("%s%.2fB", sign, v/1e9)
-	} else if absV >= 1e6 {
-		return fmt.Sprintf("%s%.2fM", sign, v/1e6)
-	} else if absV >= 1e3 {
-		return fmt.Sprintf("%s%.2fK", sign, v/1e3)
-	}
-	return fmt.Sprintf("%s%.2f", sign, v)
-}
-
 // GetMergedData retrieves merged data (AI500 + OI Top, deduplicated)
 func GetMergedData(ai500Limit int) (*MergedData, error) {
 	ai500TopSymbols, err := GetTopRatedCoins(ai500Limit)
@@ -0,0 +1,319 @@
+package provider
+
+import (
+	"SynapseStrike/cache"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Funding Rate Arbitrage Scanner
+// ============================================================================
+// Pulls perpetual funding rates from the public endpoints of the integrated
+// exchanges (Binance / Bybit / OKX), merges them per symbol and ranks symbols
+// by funding extremes: the most crowded side (largest absolute rate) or the
+// widest cross-exchange spread, whichever is larger.
+
+// Supported funding rate exchanges
+const (
+	FundingExchangeBinance = "binance"
+	FundingExchangeBybit   = "bybit"
+	FundingExchangeOKX     = "okx"
+)
+
+// DefaultFundingExchanges exchanges scanned when none are configured
+var DefaultFundingExchanges = []string{FundingExchangeBinance, FundingExchangeBybit, FundingExchangeOKX}
+
+var fundingEndpoints = map[string]string{
+	FundingExchangeBinance: "https://fapi.binance.com/fapi/v1/premiumIndex",
+	FundingExchangeBybit:   "https://api.bybit.com/v5/market/tickers?category=linear",
+	FundingExchangeOKX:     "https://www.okx.com/api/v5/public/funding-rate?instId=ANY",
+}
+
+// FundingRate funding rate of one perpetual on one exchange
+type FundingRate struct {
+	Symbol          string  // Normalized symbol (e.g.: BTCUSDT)
+	Exchange        string  // binance / bybit / okx
+	Rate            float64 // Current funding rate per interval (0.0001 = 0.01%)
+	NextFundingTime int64   // Next settlement (Unix ms)
+}
+
+// FundingArbEntry funding data of one symbol merged across exchanges
+type FundingArbEntry struct {
+	Symbol        string             // Normalized symbol
+	Rank          int                // Ranking by Score (1 = most extreme)
+	Rates         map[string]float64 // Exchange -> funding rate
+	MaxRate       float64            // Highest funding rate across exchanges
+	MinRate       float64            // Lowest funding rate across exchanges
+	MaxExchange   string             // Exchange with highest rate
+	MinExchange   string             // Exchange with lowest rate
+	AvgRate       float64            // Average funding rate
+	Spread        float64            // MaxRate - MinRate (cross-exchange arbitrage)
+	Score         float64            // max(|extreme rate|, spread) used for ranking
+	NextFundingMs int64              // Earliest next funding settlement (Unix ms)
+}
+
+var (
+	fundingCacheTTL = 5 * time.Minute // Funding settles every 1-8 hours, 5 minutes is plenty fresh
+	fundingTimeout  = 15 * time.Second
+)
+
+// GetFundingRates retrieves current funding rates of all USDT perpetuals on an exchange (cached)
+func GetFundingRates(exchange string) ([]FundingRate, error) {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	url, ok := fundingEndpoints[exchange]
+	if !ok {
+		return nil, fmt.Errorf("funding rates not supported for exchange: %s", exchange)
+	}
+
+	cacheKey := "funding_rates:" + exchange
+	var cached []FundingRate
+	if cache.GetJSON(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	body, err := fetchFundingBody(url)
+	if err != nil {
+		return nil, fmt.Errorf("%s funding request failed: %w", exchange, err)
+	}
+
+	var rates []FundingRate
+	switch exchange {
+	case FundingExchangeBinance:
+		rates, err = parseBinanceFunding(body)
+	case FundingExchangeBybit:
+		rates, err = parseBybitFunding(body)
+	case FundingExchangeOKX:
+		rates, err = parseOKXFunding(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s funding parsing failed: %w", exchange, err)
+	}
+
+	cache.SetJSON(cacheKey, rates, fundingCacheTTL)
+	return rates, nil
+}
+
+// ScanFundingArb scans funding rates across exchanges and returns symbols ranked by funding extremes
+// minAbsRate: minimum Score to keep a symbol (0 = keep all); limit: maximum results (<= 0 = all)
+// Exchanges that fail are skipped; an error is returned only if no exchange returned data.
+func ScanFundingArb(exchanges []string, minAbsRate float64, limit int) ([]FundingArbEntry, error) {
+	if len(exchanges) == 0 {
+		exchanges = DefaultFundingExchanges
+	}
+
+	var all []FundingRate
+	var lastErr error
+	for _, exchange := range exchanges {
+		rates, err := GetFundingRates(exchange)
+		if err != nil {
+			log.Printf("⚠️  Funding scan: %v", err)
+			lastErr = err
+			continue
+		}
+		all = append(all, rates...)
+	}
+	if len(all) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	entries := RankFundingArb(all, minAbsRate)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	log.Printf("✓ Funding scan: %d rates from %d exchanges, %d symbols kept", len(all), len(exchanges), len(entries))
+	return entries, nil
+}
+
+// RankFundingArb merges funding rates per symbol and ranks them by Score (descending)
+func RankFundingArb(rates []FundingRate, minAbsRate float64) []FundingArbEntry {
+	bySymbol := make(map[string]*FundingArbEntry)
+	var order []string
+	for _, r := range rates {
+		if r.Symbol == "" {
+			continue
+		}
+		entry, ok := bySymbol[r.Symbol]
+		if !ok {
+			entry = &FundingArbEntry{
+				Symbol:      r.Symbol,
+				Rates:       make(map[string]float64),
+				MaxRate:     r.Rate,
+				MinRate:     r.Rate,
+				MaxExchange: r.Exchange,
+				MinExchange: r.Exchange,
+			}
+			bySymbol[r.Symbol] = entry
+			order = append(order, r.Symbol)
+		}
+		entry.Rates[r.Exchange] = r.Rate
+		if r.Rate > entry.MaxRate {
+			entry.MaxRate, entry.MaxExchange = r.Rate, r.Exchange
+		}
+		if r.Rate < entry.MinRate {
+			entry.MinRate, entry.MinExchange = r.Rate, r.Exchange
+		}
+		if r.NextFundingTime > 0 && (entry.NextFundingMs == 0 || r.NextFundingTime < entry.NextFundingMs) {
+			entry.NextFundingMs = r.NextFundingTime
+		}
+	}
+
+	entries := make([]FundingArbEntry, 0, len(bySymbol))
+	for _, symbol := range order {
+		entry := bySymbol[symbol]
+		sum := 0.0
+		for _, rate := range entry.Rates {
+			sum += rate
+		}
+		entry.AvgRate = sum / float64(len(entry.Rates))
+		entry.Spread = entry.MaxRate - entry.MinRate
+		entry.Score = math.Max(math.Max(math.Abs(entry.MaxRate), math.Abs(entry.MinRate)), entry.Spread)
+		if entry.Score < minAbsRate {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Score > entries[j].Score
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// FormatFundingRate formats funding rate as percentage (e.g.: 0.0123%)
+func FormatFundingRate(rate float64) string {
+	return fmt.Sprintf("%.4f%%", rate*100)
+}
+
+func fetchFundingBody(url string) ([]byte, error) {
+	client := &http.Client{Timeout: fundingTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+func parseBinanceFunding(body []byte) ([]FundingRate, error) {
+	var items []struct {
+		Symbol          string `json:"symbol"`
+		LastFundingRate string `json:"lastFundingRate"`
+		NextFundingTime int64  `json:"nextFundingTime"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, err
+	}
+	rates := make([]FundingRate, 0, len(items))
+	for _, item := range items {
+		if !strings.HasSuffix(item.Symbol, "USDT") {
+			continue
+		}
+		rate, err := strconv.ParseFloat(item.LastFundingRate, 64)
+		if err != nil {
+			continue
+		}
+		rates = append(rates, FundingRate{
+			Symbol:          item.Symbol,
+			Exchange:        FundingExchangeBinance,
+			Rate:            rate,
+			NextFundingTime: item.NextFundingTime,
+		})
+	}
+	return rates, nil
+}
+
+func parseBybitFunding(body []byte) ([]FundingRate, error) {
+	var response struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				Symbol          string `json:"symbol"`
+				FundingRate     string `json:"fundingRate"`
+				NextFundingTime string `json:"nextFundingTime"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if response.RetCode != 0 {
+		return nil, fmt.Errorf("retCode %d: %s", response.RetCode, response.RetMsg)
+	}
+	rates := make([]FundingRate, 0, len(response.Result.List))
+	for _, item := range response.Result.List {
+		if !strings.HasSuffix(item.Symbol, "USDT") || item.FundingRate == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(item.FundingRate, 64)
+		if err != nil {
+			continue
+		}
+		next, _ := strconv.ParseInt(item.NextFundingTime, 10, 64)
+		rates = append(rates, FundingRate{
+			Symbol:          item.Symbol,
+			Exchange:        FundingExchangeBybit,
+			Rate:            rate,
+			NextFundingTime: next,
+		})
+	}
+	return rates, nil
+}
+
+func parseOKXFunding(body []byte) ([]FundingRate, error) {
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstID      string `json:"instId"`
+			FundingRate string `json:"fundingRate"`
+			FundingTime string `json:"fundingTime"` // Upcoming settlement time
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("code %s: %s", response.Code, response.Msg)
+	}
+	rates := make([]FundingRate, 0, len(response.Data))
+	for _, item := range response.Data {
+		// OKX instId format: BTC-USDT-SWAP
+		parts := strings.Split(item.InstID, "-")
+		if len(parts) != 3 || parts[1] != "USDT" || parts[2] != "SWAP" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(item.FundingRate, 64)
+		if err != nil {
+			continue
+		}
+		next, _ := strconv.ParseInt(item.FundingTime, 10, 64)
+		rates = append(rates, FundingRate{
+			Symbol:          parts[0] + parts[1],
+			Exchange:        FundingExchangeOKX,
+			Rate:            rate,
+			NextFundingTime: next,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,47 @@
+package provider
+
+import "testing"
+
+func TestRankFundingArb(t *testing.T) {
+	rates := []FundingRate{
+		{Symbol: "BTCUSDT", Exchange: "binance", Rate: 0.0001},
+		{Symbol: "BTCUSDT", Exchange: "bybit", Rate: 0.0001},
+		{Symbol: "ETHUSDT", Exchange: "binance", Rate: -0.0015},
+		{Symbol: "ETHUSDT", Exchange: "okx", Rate: -0.0009},
+		{Symbol: "SOLUSDT", Exchange: "binance", Rate: 0.0004},
+		{Symbol: "SOLUSDT", Exchange: "bybit", Rate: -0.0004},
+	}
+
+	entries := RankFundingArb(rates, 0.0002)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries above threshold, got %d", len(entries))
+	}
+
+	eth := entries[0]
+	if eth.Symbol != "ETHUSDT" || eth.Rank != 1 || eth.MinExchange != "binance" || eth.MaxExchange != "okx" {
+		t.Errorf("unexpected top entry: %+v", eth)
+	}
+	if eth.AvgRate > -0.00119 || eth.AvgRate < -0.00121 {
+		t.Errorf("expected avg rate -0.0012, got %f", eth.AvgRate)
+	}
+
+	// Opposite signs across exchanges: spread dominates the score
+	sol := entries[1]
+	if sol.Symbol != "SOLUSDT" || sol.Spread < 0.00079 || sol.Score != sol.Spread {
+		t.Errorf("unexpected spread entry: %+v", sol)
+	}
+}
+
+func TestParseOKXFunding(t *testing.T) {
+	body := []byte(`{"code":"0","msg":"","data":[
+		{"instId":"BTC-USDT-SWAP","fundingRate":"0.00012","fundingTime":"1700000000000"},
+		{"instId":"BTC-USD-SWAP","fundingRate":"0.0003","fundingTime":"1700000000000"}
+	]}`)
+	rates, err := parseOKXFunding(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 1 || rates[0].Symbol != "BTCUSDT" || rates[0].Rate != 0.00012 || rates[0].NextFundingTime != 1700000000000 {
+		t.Errorf("unexpected rates: %+v", rates)
+	}
+}
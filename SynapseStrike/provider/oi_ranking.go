@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"SynapseStrike/security"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// OI Ranking Providers
+// ============================================================================
+// Market-wide OI ranking (largest open interest increases and decreases) is
+// served by an OIRankingProvider. The built-in HTTPOIRankingProvider queries
+// any ranking API over HTTP: base URL, top/low paths, the way the API key is
+// sent (query parameter, bearer token or custom header) and where positions
+// sit in the response are all configurable, with defaults matching the
+// original OI ranking API. Sources that don't fit that shape are plugged in
+// from Go code with RegisterOIRankingProvider and selected by name in the
+// strategy. Without a configured source OI ranking is skipped, not failed.
+
+// OI ranking kinds
+const (
+	OIRankTop = "top" // Largest OI increases
+	OIRankLow = "low" // Largest OI decreases
+)
+
+// OI ranking API key schemes
+const (
+	OIAuthNone   = "none"   // No authentication
+	OIAuthQuery  = "query"  // ?<AuthName>=<key> (AuthName default "auth")
+	OIAuthBearer = "bearer" // Authorization: Bearer <key>
+	OIAuthHeader = "header" // <AuthName>: <key> (AuthName default "X-API-Key")
+)
+
+// Default request and response layout of the original OI ranking API
+const (
+	defaultOITopPath       = "/api/oi/top-ranking"
+	defaultOILowPath       = "/api/oi/low-ranking"
+	defaultOIPositionsPath = "data.positions"
+	defaultOITimeRangePath = "data.time_range"
+)
+
+// ErrOIRankingNotConfigured no OI ranking source is configured
+var ErrOIRankingNotConfigured = errors.New("OI ranking source not configured")
+
+// OIRankingProvider source of market-wide OI ranking
+type OIRankingProvider interface {
+	// Name identifies the source (used in logs and cache keys)
+	Name() string
+	// FetchOIRanking returns up to limit positions of the ranking kind (OIRankTop/OIRankLow) over
+	// duration (e.g. "1h") and the time range label reported by the source (may be empty)
+	FetchOIRanking(kind, duration string, limit int) ([]OIPosition, string, error)
+}
+
+var (
+	oiRankingProvidersMu sync.RWMutex
+	oiRankingProviders   = make(map[string]OIRankingProvider)
+)
+
+// RegisterOIRankingProvider registers a named OI ranking source selectable in strategies
+// A provider registered again under the same name replaces the previous one.
+func RegisterOIRankingProvider(name string, p OIRankingProvider) {
+	oiRankingProvidersMu.Lock()
+	defer oiRankingProvidersMu.Unlock()
+	oiRankingProviders[name] = p
+}
+
+// GetOIRankingProvider returns a registered OI ranking source by name
+func GetOIRankingProvider(name string) (OIRankingProvider, bool) {
+	oiRankingProvidersMu.RLock()
+	defer oiRankingProvidersMu.RUnlock()
+	p, ok := oiRankingProviders[name]
+	return p, ok
+}
+
+// OIRankingHTTPConfig request and response layout of an HTTP OI ranking API (empty fields use defaults)
+type OIRankingHTTPConfig struct {
+	BaseURL       string            // e.g. https://oi.example.com (required)
+	TopPath       string            // Path of the OI increase ranking (default /api/oi/top-ranking)
+	LowPath       string            // Path of the OI decrease ranking (default /api/oi/low-ranking)
+	AuthScheme    string            // OIAuthNone/Query/Bearer/Header (default query with an API key, else none)
+	AuthName      string            // Query parameter or header carrying the key
+	APIKey        string            // API key
+	PositionsPath string            // Dot path of the position array (default data.positions, "." = root array)
+	TimeRangePath string            // Dot path of the time range label (default data.time_range)
+	FieldMap      map[string]string // OIPosition JSON field -> field name in the response (default same name)
+	Timeout       time.Duration     // Request timeout (default 30s)
+}
+
+// HTTPOIRankingProvider OI ranking source querying an HTTP API
+type HTTPOIRankingProvider struct {
+	cfg OIRankingHTTPConfig
+}
+
+// NewHTTPOIRankingProvider creates an HTTP OI ranking source, filling in defaults
+func NewHTTPOIRankingProvider(cfg OIRankingHTTPConfig) (*HTTPOIRankingProvider, error) {
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.BaseURL == "" {
+		return nil, ErrOIRankingNotConfigured
+	}
+	if cfg.TopPath == "" {
+		cfg.TopPath = defaultOITopPath
+	}
+	if cfg.LowPath == "" {
+		cfg.LowPath = defaultOILowPath
+	}
+	if cfg.PositionsPath == "" {
+		cfg.PositionsPath = defaultOIPositionsPath
+	}
+	if cfg.TimeRangePath == "" {
+		cfg.TimeRangePath = defaultOITimeRangePath
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	cfg.AuthScheme = strings.ToLower(cfg.AuthScheme)
+	if cfg.AuthScheme == "" {
+		cfg.AuthScheme = OIAuthNone
+		if cfg.APIKey != "" {
+			cfg.AuthScheme = OIAuthQuery
+		}
+	}
+	switch cfg.AuthScheme {
+	case OIAuthNone:
+	case OIAuthQuery:
+		if cfg.AuthName == "" {
+			cfg.AuthName = "auth"
+		}
+	case OIAuthHeader:
+		if cfg.AuthName == "" {
+			cfg.AuthName = "X-API-Key"
+		}
+	case OIAuthBearer:
+		cfg.AuthName = "Authorization"
+	default:
+		return nil, fmt.Errorf("unknown OI ranking auth scheme: %s", cfg.AuthScheme)
+	}
+	if cfg.AuthScheme != OIAuthNone && cfg.APIKey == "" {
+		return nil, fmt.Errorf("OI ranking auth scheme %s requires an API key", cfg.AuthScheme)
+	}
+	return &HTTPOIRankingProvider{cfg: cfg}, nil
+}
+
+// Name identifies the source by its base URL (never the API key, names end up in logs)
+func (p *HTTPOIRankingProvider) Name() string {
+	return p.cfg.BaseURL
+}
+
+// FetchOIRanking requests one ranking and maps the response to OIPositions
+func (p *HTTPOIRankingProvider) FetchOIRanking(kind, duration string, limit int) ([]OIPosition, string, error) {
+	path := p.cfg.TopPath
+	if kind == OIRankLow {
+		path = p.cfg.LowPath
+	}
+	reqURL, err := p.requestURL(path, duration, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// SSRF Protection: Validate URL before making request
+	if err := security.ValidateURL(reqURL); err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	switch p.cfg.AuthScheme {
+	case OIAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	case OIAuthHeader:
+		req.Header.Set(p.cfg.AuthName, p.cfg.APIKey)
+	}
+
+	resp, err := security.SafeHTTPClient(p.cfg.Timeout).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, truncateOIBody(body))
+	}
+	return parseOIRankingResponse(body, p.cfg.PositionsPath, p.cfg.TimeRangePath, p.cfg.FieldMap)
+}
+
+// requestURL base URL + path with limit, duration and (query auth) the API key
+func (p *HTTPOIRankingProvider) requestURL(path, duration string, limit int) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u, err := url.Parse(p.cfg.BaseURL + path)
+	if err != nil {
+		return "", fmt.Errorf("invalid OI ranking URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("duration", duration)
+	if p.cfg.AuthScheme == OIAuthQuery {
+		q.Set(p.cfg.AuthName, p.cfg.APIKey)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// parseOIRankingResponse extracts positions and the time range label from a ranking response
+// Numbers given as strings are accepted; a missing rank is the position's index (1-based).
+func parseOIRankingResponse(body []byte, positionsPath, timeRangePath string, fieldMap map[string]string) ([]OIPosition, string, error) {
+	var root interface{}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, "", fmt.Errorf("JSON parsing failed: %w", err)
+	}
+
+	items, ok := oiJSONPath(root, positionsPath).([]interface{})
+	if !ok {
+		// The original API reports failures as a non-zero code without positions
+		if m, isMap := root.(map[string]interface{}); isMap {
+			if code, hasCode := oiNumber(m["code"]); hasCode && code != 0 {
+				return nil, "", fmt.Errorf("API returned error code: %.0f", code)
+			}
+		}
+		return nil, "", fmt.Errorf("no position list at %q", positionsPath)
+	}
+	timeRange, _ := oiJSONPath(root, timeRangePath).(string)
+
+	field := func(name string) string {
+		if mapped, ok := fieldMap[name]; ok && mapped != "" {
+			return mapped
+		}
+		return name
+	}
+	positions := make([]OIPosition, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		symbol, _ := oiJSONPath(m, field("symbol")).(string)
+		if symbol == "" {
+			continue
+		}
+		num := func(name string) float64 {
+			v, _ := oiNumber(oiJSONPath(m, field(name)))
+			return v
+		}
+		pos := OIPosition{
+			Symbol:            symbol,
+			Rank:              int(num("rank")),
+			CurrentOI:         num("current_oi"),
+			OIDelta:           num("oi_delta"),
+			OIDeltaPercent:    num("oi_delta_percent"),
+			OIDeltaValue:      num("oi_delta_value"),
+			PriceDeltaPercent: num("price_delta_percent"),
+			NetLong:           num("net_long"),
+			NetShort:          num("net_short"),
+		}
+		if pos.Rank <= 0 {
+			pos.Rank = i + 1
+		}
+		positions = append(positions, pos)
+	}
+	return positions, timeRange, nil
+}
+
+// oiJSONPath value at a dot path ("." = the value itself), nil if absent
+func oiJSONPath(data interface{}, path string) interface{} {
+	if path == "" || path == "." {
+		return data
+	}
+	current := data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// oiNumber a JSON number or numeric string
+func oiNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func truncateOIBody(body []byte) string {
+	if len(body) > 200 {
+		return string(body[:200]) + "..."
+	}
+	return string(body)
+}
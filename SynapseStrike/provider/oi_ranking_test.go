@@ -0,0 +1,87 @@
+package provider
+
+import "testing"
+
+func TestParseOIRankingResponseDefaultLayout(t *testing.T) {
+	body := []byte(`{"code":0,"data":{"time_range":"1 hour","positions":[
+		{"symbol":"BTCUSDT","rank":1,"oi_delta_value":1500000,"oi_delta_percent":2.5,"price_delta_percent":1.1},
+		{"symbol":"ETHUSDT","rank":2,"oi_delta_value":900000,"oi_delta_percent":1.8,"price_delta_percent":-0.4}
+	]}}`)
+	positions, timeRange, err := parseOIRankingResponse(body, defaultOIPositionsPath, defaultOITimeRangePath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeRange != "1 hour" || len(positions) != 2 {
+		t.Fatalf("expected 2 positions over 1 hour, got %d over %q", len(positions), timeRange)
+	}
+	if eth := positions[1]; eth.Symbol != "ETHUSDT" || eth.Rank != 2 || eth.OIDeltaValue != 900000 || eth.PriceDeltaPercent != -0.4 {
+		t.Errorf("unexpected position: %+v", eth)
+	}
+}
+
+func TestParseOIRankingResponseFieldMap(t *testing.T) {
+	body := []byte(`{"result":[
+		{"ticker":"SOLUSDT","oi":{"change_usd":"420000.5","change_pct":"3.2"}},
+		{"ticker":"DOGEUSDT","oi":{"change_usd":12000,"change_pct":0.4}},
+		{"oi":{"change_usd":1}}
+	]}`)
+	fields := map[string]string{"symbol": "ticker", "oi_delta_value": "oi.change_usd", "oi_delta_percent": "oi.change_pct"}
+	positions, timeRange, err := parseOIRankingResponse(body, "result", defaultOITimeRangePath, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeRange != "" || len(positions) != 2 {
+		t.Fatalf("expected 2 positions without time range (entry without symbol skipped), got %d, %q", len(positions), timeRange)
+	}
+	sol := positions[0]
+	if sol.Symbol != "SOLUSDT" || sol.Rank != 1 || sol.OIDeltaValue != 420000.5 || sol.OIDeltaPercent != 3.2 {
+		t.Errorf("numeric strings should be parsed and rank default to the index: %+v", sol)
+	}
+	if positions[1].Rank != 2 {
+		t.Errorf("expected rank 2, got %d", positions[1].Rank)
+	}
+}
+
+func TestParseOIRankingResponseErrorCode(t *testing.T) {
+	if _, _, err := parseOIRankingResponse([]byte(`{"code":401,"msg":"bad auth"}`), defaultOIPositionsPath, defaultOITimeRangePath, nil); err == nil {
+		t.Error("expected error for non-zero code without positions")
+	}
+	positions, _, err := parseOIRankingResponse([]byte(`[{"symbol":"BTCUSDT"}]`), ".", defaultOITimeRangePath, nil)
+	if err != nil || len(positions) != 1 {
+		t.Errorf("expected root array to parse, got %v, %v", positions, err)
+	}
+}
+
+func TestNewHTTPOIRankingProvider(t *testing.T) {
+	if _, err := NewHTTPOIRankingProvider(OIRankingHTTPConfig{}); err != ErrOIRankingNotConfigured {
+		t.Errorf("expected ErrOIRankingNotConfigured without base URL, got %v", err)
+	}
+	if _, err := NewHTTPOIRankingProvider(OIRankingHTTPConfig{BaseURL: "https://oi.example.com", AuthScheme: OIAuthBearer}); err == nil {
+		t.Error("expected error for bearer auth without API key")
+	}
+	if _, err := NewHTTPOIRankingProvider(OIRankingHTTPConfig{BaseURL: "https://oi.example.com", AuthScheme: "cookie", APIKey: "k"}); err == nil {
+		t.Error("expected error for unknown auth scheme")
+	}
+
+	p, err := NewHTTPOIRankingProvider(OIRankingHTTPConfig{BaseURL: "https://oi.example.com/", APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := p.requestURL(p.cfg.LowPath, "4h", 15)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://oi.example.com/api/oi/low-ranking?auth=secret&duration=4h&limit=15"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if p.Name() != "https://oi.example.com" {
+		t.Errorf("name should be the base URL without key, got %s", p.Name())
+	}
+
+	p, _ = NewHTTPOIRankingProvider(OIRankingHTTPConfig{BaseURL: "https://oi.example.com", AuthScheme: OIAuthHeader, APIKey: "secret"})
+	got, _ = p.requestURL("v1/oi/top", "1h", 10)
+	if got != "https://oi.example.com/v1/oi/top?duration=1h&limit=10" || p.cfg.AuthName != "X-API-Key" {
+		t.Errorf("header auth must keep the key out of the URL: %s (header %s)", got, p.cfg.AuthName)
+	}
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"SynapseStrike/cache"
+	"SynapseStrike/security"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Social Sentiment Provider
+// ============================================================================
+// Pulls trending tickers from a configurable social API, typically a
+// user-hosted Reddit/X scraper. The endpoint returns one entry per ticker
+// with its mention count and sentiment; entries may sit at the root of the
+// response or under "data", "tickers", "trending" or "results", and common
+// field name variants (ticker, mention_count, score, ...) are accepted. The
+// result is ranked by mentions and cached briefly, since candidate selection
+// and prompt annotation both read it in the same cycle.
+
+// ErrSocialNotConfigured no social sentiment API is configured
+var ErrSocialNotConfigured = errors.New("social sentiment API not configured")
+
+var (
+	socialCacheTTL = 5 * time.Minute
+	socialTimeout  = 15 * time.Second
+)
+
+// socialListKeys object keys searched for the ticker list (in order)
+var socialListKeys = []string{"data", "tickers", "trending", "results"}
+
+// SocialTrend mention and sentiment data of one trending ticker
+type SocialTrend struct {
+	Symbol           string  `json:"symbol"`
+	Rank             int     `json:"rank"`               // Ranking by mentions (1 = most mentioned)
+	Mentions         int     `json:"mentions"`           // Mentions over the source's window
+	MentionChangePct float64 `json:"mention_change_pct"` // Change vs. the previous window in % (0 if not reported)
+	Sentiment        float64 `json:"sentiment"`          // -1 (bearish) .. +1 (bullish)
+	Bullish          int     `json:"bullish,omitempty"`  // Bullish mentions (if reported)
+	Bearish          int     `json:"bearish,omitempty"`  // Bearish mentions (if reported)
+}
+
+// SocialSentimentConfig social API endpoint
+type SocialSentimentConfig struct {
+	APIURL      string // Trending endpoint (required)
+	APIKey      string // Sent as "Authorization: Bearer <key>" (optional)
+	MinMentions int    // Drop tickers with fewer mentions (0 = keep all)
+}
+
+// GetSocialTrending retrieves trending tickers ranked by mentions (cached), limit <= 0 = all
+func GetSocialTrending(cfg SocialSentimentConfig, limit int) ([]SocialTrend, error) {
+	apiURL := strings.TrimSpace(cfg.APIURL)
+	if apiURL == "" {
+		return nil, ErrSocialNotConfigured
+	}
+
+	cacheKey := "social_trending:" + cache.HashKey(apiURL+"|"+cfg.APIKey)
+	var trends []SocialTrend
+	if !cache.GetJSON(cacheKey, &trends) {
+		body, err := fetchSocialBody(apiURL, cfg.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("social sentiment request failed: %w", err)
+		}
+		trends, err = ParseSocialTrending(body)
+		if err != nil {
+			return nil, fmt.Errorf("social sentiment parsing failed: %w", err)
+		}
+		cache.SetJSON(cacheKey, trends, socialCacheTTL)
+	}
+
+	result := make([]SocialTrend, 0, len(trends))
+	for _, t := range trends {
+		if t.Mentions < cfg.MinMentions {
+			continue
+		}
+		result = append(result, t)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// ParseSocialTrending parses a trending response into entries ranked by mentions
+// Sentiment given on a 0..100 scale (percent bullish) is mapped to -1..+1; without a
+// sentiment field it is derived from bullish/bearish counts when those are present.
+func ParseSocialTrending(body []byte) ([]SocialTrend, error) {
+	var root interface{}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, fmt.Errorf("JSON parsing failed: %w", err)
+	}
+
+	items, ok := root.([]interface{})
+	if !ok {
+		m, isMap := root.(map[string]interface{})
+		if !isMap {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		for _, key := range socialListKeys {
+			if items, ok = m[key].([]interface{}); ok {
+				break
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("no ticker list in response")
+		}
+	}
+
+	seen := make(map[string]bool, len(items))
+	trends := make([]SocialTrend, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		symbol := strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(socialString(m, "symbol", "ticker")), "$"))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		t := SocialTrend{
+			Symbol:           symbol,
+			Mentions:         int(socialNumber(m, "mentions", "mention_count", "count")),
+			MentionChangePct: socialNumber(m, "mention_change_pct", "mentions_change_pct", "change_pct"),
+			Bullish:          int(socialNumber(m, "bullish", "bullish_count")),
+			Bearish:          int(socialNumber(m, "bearish", "bearish_count")),
+		}
+		if sentiment, ok := socialValue(m, "sentiment", "sentiment_score", "score"); ok {
+			if sentiment > 1 {
+				sentiment = (sentiment - 50) / 50 // 0..100 percent bullish
+			}
+			t.Sentiment = clampSentiment(sentiment)
+		} else if t.Bullish+t.Bearish > 0 {
+			t.Sentiment = float64(t.Bullish-t.Bearish) / float64(t.Bullish+t.Bearish)
+		}
+		trends = append(trends, t)
+	}
+
+	sort.SliceStable(trends, func(i, j int) bool { return trends[i].Mentions > trends[j].Mentions })
+	for i := range trends {
+		trends[i].Rank = i + 1
+	}
+	return trends, nil
+}
+
+// SentimentLabel wording of a sentiment score
+func SentimentLabel(sentiment float64) string {
+	switch {
+	case sentiment >= 0.5:
+		return "very bullish"
+	case sentiment >= 0.15:
+		return "bullish"
+	case sentiment <= -0.5:
+		return "very bearish"
+	case sentiment <= -0.15:
+		return "bearish"
+	}
+	return "neutral"
+}
+
+func clampSentiment(v float64) float64 {
+	if v > 1 {
+		return 1
+	}
+	if v < -1 {
+		return -1
+	}
+	return v
+}
+
+// socialValue first numeric value among the given keys
+func socialValue(m map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if v, ok := oiNumber(m[key]); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func socialNumber(m map[string]interface{}, keys ...string) float64 {
+	v, _ := socialValue(m, keys...)
+	return v
+}
+
+func socialString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func fetchSocialBody(apiURL, apiKey string) ([]byte, error) {
+	// SSRF Protection: Validate URL before making request
+	if err := security.ValidateURL(apiURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := security.SafeHTTPClient(socialTimeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, truncateOIBody(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSocialTrending(t *testing.T) {
+	body := []byte(`{"data":[
+		{"ticker":"$gme","mention_count":"120","sentiment":72},
+		{"symbol":"TSLA","mentions":450,"sentiment":-0.3,"mention_change_pct":85.5},
+		{"symbol":"PLTR","mentions":90,"bullish":30,"bearish":10},
+		{"symbol":"TSLA","mentions":5},
+		{"mentions":999}
+	]}`)
+	trends, err := ParseSocialTrending(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trends) != 3 {
+		t.Fatalf("expected 3 tickers (duplicate and symbol-less entries skipped), got %+v", trends)
+	}
+
+	tsla, gme, pltr := trends[0], trends[1], trends[2]
+	if tsla.Symbol != "TSLA" || tsla.Rank != 1 || tsla.Mentions != 450 || tsla.Sentiment != -0.3 || tsla.MentionChangePct != 85.5 {
+		t.Errorf("expected TSLA ranked first by mentions: %+v", tsla)
+	}
+	if gme.Symbol != "GME" || gme.Mentions != 120 || math.Abs(gme.Sentiment-0.44) > 1e-9 {
+		t.Errorf("expected $ prefix stripped and 72%% bullish mapped to +0.44: %+v", gme)
+	}
+	if pltr.Rank != 3 || math.Abs(pltr.Sentiment-0.5) > 1e-9 {
+		t.Errorf("expected sentiment derived from bullish/bearish counts: %+v", pltr)
+	}
+}
+
+func TestParseSocialTrendingRootArray(t *testing.T) {
+	trends, err := ParseSocialTrending([]byte(`[{"symbol":"AMC","mentions":10,"score":-4}]`))
+	if err != nil || len(trends) != 1 || trends[0].Sentiment != -1 {
+		t.Errorf("expected root array with clamped sentiment, got %+v, %v", trends, err)
+	}
+	if _, err := ParseSocialTrending([]byte(`{"status":"ok"}`)); err == nil {
+		t.Error("expected error without ticker list")
+	}
+}
+
+func TestGetSocialTrendingNotConfigured(t *testing.T) {
+	if _, err := GetSocialTrending(SocialSentimentConfig{}, 10); err != ErrSocialNotConfigured {
+		t.Errorf("expected ErrSocialNotConfigured, got %v", err)
+	}
+}
+
+func TestSentimentLabel(t *testing.T) {
+	for sentiment, want := range map[float64]string{0.8: "very bullish", 0.2: "bullish", 0: "neutral", -0.2: "bearish", -0.9: "very bearish"} {
+		if got := SentimentLabel(sentiment); got != want {
+			t.Errorf("SentimentLabel(%.1f) = %q, want %q", sentiment, got, want)
+		}
+	}
+}
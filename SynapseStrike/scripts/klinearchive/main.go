@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"SynapseStrike/market"
+)
+
+// Bulk-downloads klines into the local kline archive and checks its integrity.
+//
+//	go run ./scripts/klinearchive -symbols AAPL,BTCUSDT -timeframes 1m,1h -start 2024-01-01
+//	go run ./scripts/klinearchive -symbols BTCUSDT -timeframes 1m -verify -repair
+//
+// Stocks are downloaded from Alpaca (ALPACA_API_KEY / ALPACA_API_SECRET), crypto from Binance.
+// Runs are incremental: only bars outside the archived range are downloaded.
+func main() {
+	dbPath := flag.String("db", "data/klines.db", "archive database path")
+	symbolsFlag := flag.String("symbols", "", "comma-separated symbols (e.g. AAPL,BTCUSDT)")
+	timeframesFlag := flag.String("timeframes", "1m", "comma-separated timeframes")
+	startFlag := flag.String("start", "", "start date YYYY-MM-DD (default: 30 days ago)")
+	endFlag := flag.String("end", "", "end date YYYY-MM-DD (default: now)")
+	verify := flag.Bool("verify", false, "check archived bars for gaps and invalid values instead of downloading")
+	repair := flag.Bool("repair", false, "with -verify: download bars missing inside the archived range")
+	flag.Parse()
+
+	symbols := splitList(*symbolsFlag)
+	timeframes := splitList(*timeframesFlag)
+	if len(symbols) == 0 || len(timeframes) == 0 {
+		log.Fatalf("❌ -symbols and -timeframes are required")
+	}
+
+	end := time.Now()
+	if *endFlag != "" {
+		end = mustParseDate(*endFlag)
+	}
+	start := end.AddDate(0, 0, -30)
+	if *startFlag != "" {
+		start = mustParseDate(*startFlag)
+	}
+
+	archive, err := market.OpenKlineArchive(*dbPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer archive.Close()
+
+	failed := false
+	for _, symbol := range symbols {
+		for _, tf := range timeframes {
+			if *verify {
+				failed = verifySeries(archive, symbol, tf, *repair) || failed
+				continue
+			}
+			result, err := archive.Sync(symbol, tf, start, end)
+			if err != nil {
+				log.Printf("❌ %s %s: %v", symbol, tf, err)
+				failed = true
+				continue
+			}
+			log.Printf("✅ %s %s: %d bars downloaded, %d saved", result.Symbol, result.Timeframe, result.Fetched, result.Saved)
+		}
+	}
+	if failed {
+		log.Fatalf("❌ Finished with errors")
+	}
+}
+
+// verifySeries prints integrity report of a series, returns true if problems remain
+func verifySeries(archive *market.KlineArchive, symbol, tf string, repair bool) bool {
+	report, err := archive.Verify(symbol, tf)
+	if err != nil {
+		log.Printf("❌ %s %s: %v", symbol, tf, err)
+		return true
+	}
+	if report.Bars == 0 {
+		log.Printf("⚠️ %s %s: no archived bars", report.Symbol, report.Timeframe)
+		return false
+	}
+	log.Printf("📦 %s %s: %d bars %s → %s, %d gaps, %d invalid bars", report.Symbol, report.Timeframe, report.Bars,
+		report.First.UTC().Format(time.RFC3339), report.Last.UTC().Format(time.RFC3339), len(report.Gaps), report.InvalidBars)
+	for _, gap := range report.Gaps {
+		log.Printf("   gap %s → %s (%d bars)", gap.From.UTC().Format(time.RFC3339), gap.To.UTC().Format(time.RFC3339), gap.Missing)
+	}
+	if repair && len(report.Gaps) > 0 {
+		saved, err := archive.FillGaps(symbol, tf)
+		if err != nil {
+			log.Printf("❌ %s %s: gap repair failed: %v", symbol, tf, err)
+			return true
+		}
+		log.Printf("🔧 %s %s: %d bars filled", report.Symbol, report.Timeframe, saved)
+		if report, err = archive.Verify(symbol, tf); err != nil {
+			return true
+		}
+	}
+	return !report.OK()
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func mustParseDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		log.Fatalf("❌ Invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return t
+}
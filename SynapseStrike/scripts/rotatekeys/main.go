@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"SynapseStrike/crypto"
+	"SynapseStrike/store"
+
+	_ "github.com/joho/godotenv/autoload"
+	_ "modernc.org/sqlite"
+)
+
+// Re-encrypts all stored credentials with the current data encryption key.
+//
+//	DATA_ENCRYPTION_KEY=<new key> DATA_ENCRYPTION_KEY_PREVIOUS=<old key> go run ./scripts/rotatekeys -db data/data.db
+//
+// Generate the new key with `openssl rand -base64 32`, configure it as DATA_ENCRYPTION_KEY and move the
+// old key to DATA_ENCRYPTION_KEY_PREVIOUS (comma-separated if several). The server accepts both keys in
+// the meantime, so it can keep running. After this command reports success the previous key can be removed.
+// Plain-text credentials are encrypted as well. The database is backed up first (-backup=false to skip).
+func main() {
+	dbPath := flag.String("db", "data/data.db", "database path")
+	backup := flag.Bool("backup", true, "copy the database to <db>.pre_rotation_backup before rotating")
+	flag.Parse()
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		log.Fatalf("❌ Database not found: %s", *dbPath)
+	}
+	cs, err := crypto.NewStorageCryptoService()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if strings.TrimSpace(os.Getenv(crypto.EnvPreviousDataEncryptionKeys)) == "" {
+		log.Printf("⚠️  %s is not set: only plain-text credentials and values sealed with the current key can be read", crypto.EnvPreviousDataEncryptionKeys)
+	}
+
+	if *backup {
+		backupPath := fmt.Sprintf("%s.pre_rotation_backup", *dbPath)
+		if err := backupDatabase(*dbPath, backupPath); err != nil {
+			log.Fatalf("❌ Backup failed: %v", err)
+		}
+		log.Printf("📦 Backed up database to %s", backupPath)
+	}
+
+	st, err := store.New(*dbPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer st.Close()
+
+	log.Printf("🔄 Re-encrypting %s", strings.Join(store.CredentialColumns(), ", "))
+	count, err := st.ReencryptCredentials(func(value string) (string, error) {
+		return cs.ReencryptForStorage(value)
+	})
+	if err != nil {
+		log.Fatalf("❌ Rotation aborted, nothing was changed: %v", err)
+	}
+	log.Printf("✅ Re-encrypted %d credentials with the current key, %s can now be removed", count, crypto.EnvPreviousDataEncryptionKeys)
+}
+
+// backupDatabase writes a consistent copy of the database to backupPath
+// VACUUM INTO reads through SQLite, so a write by the running server or a pending journal
+// cannot leave a torn copy the way copying the file would.
+func backupDatabase(dbPath, backupPath string) error {
+	if _, err := os.Stat(backupPath); err == nil {
+		return fmt.Errorf("%s already exists, move it away or pass -backup=false", backupPath)
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if _, err := db.Exec(`PRAGMA busy_timeout = 5000`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`VACUUM INTO ?`, backupPath); err != nil {
+		return err
+	}
+	return os.Chmod(backupPath, 0600)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"sort"
+
+	"SynapseStrike/store"
+)
+
+// Migrates the database to tenant-scoped trader data and splits users into separate databases.
+//
+//	go run ./scripts/tenant -db data/data.db -audit
+//	go run ./scripts/tenant -db data/data.db -export-user <user_id> -out data/tenant-<user_id>.db
+//
+// Opening the store runs the migration: trader data rows are backfilled with their owner's user_id.
+// -audit then reports rows that cannot be attributed (traders deleted or reassigned) and exits
+// non-zero if any exist; -export-user writes a copy that only contains that user's data.
+func main() {
+	dbPath := flag.String("db", "data/data.db", "database path")
+	audit := flag.Bool("audit", false, "report trader data rows without a matching owner")
+	exportUser := flag.String("export-user", "", "user ID to export into a standalone database")
+	out := flag.String("out", "", "with -export-user: output database path")
+	flag.Parse()
+
+	if !*audit && *exportUser == "" {
+		log.Fatalf("❌ -audit or -export-user is required")
+	}
+	if *exportUser != "" && *out == "" {
+		log.Fatalf("❌ -out is required with -export-user")
+	}
+
+	st, err := store.New(*dbPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer st.Close()
+
+	failed := false
+	if *audit {
+		audits, err := st.TenantAudit()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		for _, a := range audits {
+			icon := "✅"
+			if a.Unowned > 0 || a.Mismatched > 0 {
+				icon = "⚠️"
+				failed = true
+			}
+			log.Printf("%s %s: %d rows, %d unowned, %d mismatched", icon, a.Table, a.Rows, a.Unowned, a.Mismatched)
+		}
+	}
+
+	if *exportUser != "" {
+		kept, err := st.ExportTenant(*exportUser, *out)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		tables := make([]string, 0, len(kept))
+		for table := range kept {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			log.Printf("📦 %s: %d rows", table, kept[table])
+		}
+		log.Printf("✅ Exported user %s to %s", *exportUser, *out)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
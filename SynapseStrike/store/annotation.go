@@ -0,0 +1,153 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Trade annotation labels (human verdict on a closed trade)
+const (
+	AnnotationGoodTrade = "good_trade" // Correct read, well executed
+	AnnotationBadEntry  = "bad_entry"  // Chased, early or poorly located entry
+	AnnotationPoorExit  = "poor_exit"  // Correct read, poor exit (too early, too late, stop too tight)
+	AnnotationWrongRead = "wrong_read" // Thesis was wrong
+	AnnotationOversized = "oversized"  // Position too large for the setup
+	AnnotationOther     = "other"
+)
+
+// AnnotationLabels valid trade annotation labels
+var AnnotationLabels = []string{
+	AnnotationGoodTrade, AnnotationBadEntry, AnnotationPoorExit, AnnotationWrongRead, AnnotationOversized, AnnotationOther,
+}
+
+// IsValidAnnotationLabel checks whether label is one of AnnotationLabels
+func IsValidAnnotationLabel(label string) bool {
+	for _, l := range AnnotationLabels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotationStore human annotations of closed trades (trade journal)
+type AnnotationStore struct {
+	db *sql.DB
+}
+
+// TradeAnnotation human annotation of a closed trade
+// Symbol, Side, RealizedPnL, CloseReason and ExitTime come from the annotated position.
+type TradeAnnotation struct {
+	ID          int64     `json:"id"`
+	TraderID    string    `json:"trader_id"`
+	PositionID  int64     `json:"position_id"`
+	Label       string    `json:"label"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	Symbol      string    `json:"symbol"`
+	Side        string    `json:"side"` // long/short
+	RealizedPnL float64   `json:"realized_pnl"`
+	CloseReason string    `json:"close_reason"`
+	ExitTime    time.Time `json:"exit_time"`
+}
+
+// initTables initializes trade annotation tables
+func (s *AnnotationStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS trade_annotations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			position_id INTEGER NOT NULL,
+			label TEXT NOT NULL,
+			note TEXT DEFAULT '',
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_annotations_trader ON trade_annotations(trader_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_annotations_position ON trade_annotations(position_id)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create annotates a closed position of the trader
+func (s *AnnotationStore) Create(a *TradeAnnotation) error {
+	if !IsValidAnnotationLabel(a.Label) {
+		return fmt.Errorf("invalid annotation label %q", a.Label)
+	}
+	var status string
+	err := s.db.QueryRow(`SELECT status FROM trader_positions WHERE id = ? AND trader_id = ?`, a.PositionID, a.TraderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("position %d not found", a.PositionID)
+	} else if err != nil {
+		return fmt.Errorf("failed to query position: %w", err)
+	}
+	if status != "CLOSED" {
+		return fmt.Errorf("position %d is not closed", a.PositionID)
+	}
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO trade_annotations (trader_id, position_id, label, note, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, a.TraderID, a.PositionID, a.Label, strings.TrimSpace(a.Note), a.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save trade annotation: %w", err)
+	}
+	a.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// List gets annotations of the trader created since the given time (zero = all), newest first
+func (s *AnnotationStore) List(traderID string, since time.Time, limit int) ([]*TradeAnnotation, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.trader_id, a.position_id, a.label, COALESCE(a.note, ''), a.created_at,
+			p.symbol, p.side, COALESCE(p.realized_pnl, 0), COALESCE(p.close_reason, ''), p.exit_time
+		FROM trade_annotations a
+		JOIN trader_positions p ON p.id = a.position_id
+		WHERE a.trader_id = ? AND a.created_at >= ?
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ?
+	`, traderID, since.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var annotations []*TradeAnnotation
+	for rows.Next() {
+		a := &TradeAnnotation{}
+		var createdAt string
+		var exitTime sql.NullString
+		if err := rows.Scan(&a.ID, &a.TraderID, &a.PositionID, &a.Label, &a.Note, &createdAt,
+			&a.Symbol, &a.Side, &a.RealizedPnL, &a.CloseReason, &exitTime); err != nil {
+			return nil, err
+		}
+		a.Side = strings.ToLower(a.Side)
+		a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		if exitTime.Valid {
+			a.ExitTime, _ = time.Parse(time.RFC3339, exitTime.String)
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, nil
+}
+
+// Delete deletes an annotation of the trader
+func (s *AnnotationStore) Delete(traderID string, id int64) error {
+	result, err := s.db.Exec(`DELETE FROM trade_annotations WHERE id = ? AND trader_id = ?`, id, traderID)
+	if err != nil {
+		return fmt.Errorf("failed to delete trade annotation: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("annotation %d not found", id)
+	}
+	return nil
+}
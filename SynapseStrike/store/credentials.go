@@ -0,0 +1,199 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Credential Columns
+// ============================================================================
+// Exchange secrets, AI model API keys and candidate webhook secrets of strategy
+// configs are encrypted at rest by the crypto functions set with SetCryptoFuncs
+// (AES-GCM, key from DATA_ENCRYPTION_KEY).
+// Rows written before encryption was introduced, or sealed with a retired key,
+// are brought up to date by ReencryptCredentials: at startup to encrypt any
+// plain-text leftovers, and by the key rotation command
+// (go run ./scripts/rotatekeys) after a new data key is configured.
+
+// credentialColumns encrypted columns per table
+var credentialColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"exchanges", []string{"api_key", "secret_key", "passphrase", "aster_private_key",
+		"lighter_private_key", "lighter_api_key_private_key", "dydx_mnemonic"}},
+	{"ai_models", []string{"api_key"}},
+}
+
+// configCredentialTables tables whose config JSON holds a webhook secret (stock_source.webhook_secret)
+var configCredentialTables = []string{"strategies", "tactics"}
+
+// credentialUpdate new value of one credential cell
+type credentialUpdate struct {
+	table, column string
+	rowID         int64
+	value         string
+}
+
+// ReencryptCredentials passes every non-empty credential through reencrypt and stores changed values
+// Returns the number of values updated. All updates are written in one transaction: if reencrypt fails
+// for any value nothing is changed, so a wrong key never leaves the database half rotated.
+func (s *Store) ReencryptCredentials(reencrypt func(value string) (string, error)) (int, error) {
+	var updates []credentialUpdate
+	for _, tc := range credentialColumns {
+		for _, column := range tc.columns {
+			columnUpdates, err := s.credentialUpdates(tc.table, column, reencrypt)
+			if err != nil {
+				return 0, err
+			}
+			updates = append(updates, columnUpdates...)
+		}
+	}
+	for _, table := range configCredentialTables {
+		configUpdates, err := s.credentialUpdates(table, "config", func(config string) (string, error) {
+			return mapWebhookSecret(config, reencrypt)
+		})
+		if err != nil {
+			return 0, err
+		}
+		updates = append(updates, configUpdates...)
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	for _, u := range updates {
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE rowid = ?`, u.table, u.column), u.value, u.rowID); err != nil {
+			return 0, fmt.Errorf("failed to update %s.%s: %w", u.table, u.column, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(updates), nil
+}
+
+// credentialUpdates passes every non-empty value of a column through rewrite and collects the changed ones
+func (s *Store) credentialUpdates(table, column string, rewrite func(value string) (string, error)) ([]credentialUpdate, error) {
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT rowid, %s FROM %s WHERE COALESCE(%s, '') != ''`, column, table, column))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s.%s: %w", table, column, err)
+	}
+	defer rows.Close()
+
+	var updates []credentialUpdate
+	for rows.Next() {
+		var rowID int64
+		var value string
+		if err := rows.Scan(&rowID, &value); err != nil {
+			return nil, err
+		}
+		updated, err := rewrite(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s (row %d): %w", table, column, rowID, err)
+		}
+		if updated != value {
+			updates = append(updates, credentialUpdate{table, column, rowID, updated})
+		}
+	}
+	return updates, rows.Err()
+}
+
+// mapWebhookSecret passes the webhook secret of a strategy config JSON through fn
+// Configs without a secret, or that do not parse, are returned unchanged.
+func mapWebhookSecret(configJSON string, fn func(secret string) (string, error)) (string, error) {
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return configJSON, nil
+	}
+	var source map[string]json.RawMessage
+	if err := json.Unmarshal(config["stock_source"], &source); err != nil {
+		return configJSON, nil
+	}
+	var secret string
+	if err := json.Unmarshal(source["webhook_secret"], &secret); err != nil || secret == "" {
+		return configJSON, nil
+	}
+
+	updated, err := fn(secret)
+	if err != nil || updated == secret {
+		return configJSON, err
+	}
+	if source["webhook_secret"], err = json.Marshal(updated); err != nil {
+		return configJSON, err
+	}
+	if config["stock_source"], err = json.Marshal(source); err != nil {
+		return configJSON, err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return configJSON, err
+	}
+	return string(data), nil
+}
+
+// cryptConfig encrypts or decrypts the webhook secret of a strategy config JSON (unchanged if crypt is nil)
+func cryptConfig(configJSON string, crypt func(string) string) string {
+	if crypt == nil {
+		return configJSON
+	}
+	updated, _ := mapWebhookSecret(configJSON, func(secret string) (string, error) {
+		return crypt(secret), nil
+	})
+	return updated
+}
+
+// CredentialColumns "table.column" names of the encrypted credential columns
+func CredentialColumns() []string {
+	var names []string
+	for _, tc := range credentialColumns {
+		for _, column := range tc.columns {
+			names = append(names, tc.table+"."+column)
+		}
+	}
+	for _, table := range configCredentialTables {
+		names = append(names, table+".config (stock_source.webhook_secret)")
+	}
+	return names
+}
+
+// undecryptedCredential name of the first credential still in encrypted form (empty if none)
+func undecryptedCredential(isEncrypted func(string) bool, fields map[string]string) string {
+	for name, value := range fields {
+		if value != "" && isEncrypted(value) {
+			return name
+		}
+	}
+	return ""
+}
+
+// CheckDecrypted returns an error if a credential of the exchange could not be decrypted
+// isEncrypted reports whether a value is still in encrypted storage format.
+func (e *Exchange) CheckDecrypted(isEncrypted func(string) bool) error {
+	if name := undecryptedCredential(isEncrypted, map[string]string{
+		"api_key":                     e.APIKey,
+		"secret_key":                  e.SecretKey,
+		"passphrase":                  e.Passphrase,
+		"aster_private_key":           e.AsterPrivateKey,
+		"lighter_private_key":         e.LighterPrivateKey,
+		"lighter_api_key_private_key": e.LighterAPIKeyPrivateKey,
+		"dydx_mnemonic":               e.DydxMnemonic,
+	}); name != "" {
+		return fmt.Errorf("exchange %s: %s could not be decrypted (check DATA_ENCRYPTION_KEY / DATA_ENCRYPTION_KEY_PREVIOUS)", e.ID, name)
+	}
+	return nil
+}
+
+// CheckDecrypted returns an error if the AI model's API key could not be decrypted
+func (m *AIModel) CheckDecrypted(isEncrypted func(string) bool) error {
+	if m.APIKey != "" && isEncrypted(m.APIKey) {
+		return fmt.Errorf("AI model %s: api_key could not be decrypted (check DATA_ENCRYPTION_KEY / DATA_ENCRYPTION_KEY_PREVIOUS)", m.ID)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package store
+
+import (
+	"SynapseStrike/crypto"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newCredentialTestStore opens a store holding one exchange account and one AI model of user alice
+func newCredentialTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	s, err := New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	exchangeID, err := s.Exchange().Create("alice", "binance", "main", true, "key-1", "secret-1", "pass-1", false,
+		"", "", "", "", "", "", "", 0, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+	if err := s.AIModel().Create("alice", "alice_deepseek", "DeepSeek", "deepseek", true, "model-key", ""); err != nil {
+		t.Fatalf("failed to create AI model: %v", err)
+	}
+	return s, exchangeID
+}
+
+// credentialValues stored credential values by "table.column"
+func credentialValues(t *testing.T, s *Store) map[string]string {
+	t.Helper()
+	values := make(map[string]string)
+	var apiKey, secretKey, passphrase, modelKey string
+	if err := s.db.QueryRow(`SELECT api_key, secret_key, passphrase FROM exchanges`).Scan(&apiKey, &secretKey, &passphrase); err != nil {
+		t.Fatalf("failed to read exchange: %v", err)
+	}
+	if err := s.db.QueryRow(`SELECT api_key FROM ai_models WHERE id = 'alice_deepseek'`).Scan(&modelKey); err != nil {
+		t.Fatalf("failed to read AI model: %v", err)
+	}
+	values["exchanges.api_key"] = apiKey
+	values["exchanges.secret_key"] = secretKey
+	values["exchanges.passphrase"] = passphrase
+	values["ai_models.api_key"] = modelKey
+	return values
+}
+
+func TestReencryptCredentials(t *testing.T) {
+	s, _ := newCredentialTestStore(t)
+	rotate := func(value string) (string, error) {
+		if strings.HasPrefix(value, "rotated:") {
+			return value, nil
+		}
+		return "rotated:" + value, nil
+	}
+
+	count, err := s.ReencryptCredentials(rotate)
+	if err != nil {
+		t.Fatalf("ReencryptCredentials: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("updated %d credentials, want 4", count)
+	}
+	for name, value := range credentialValues(t, s) {
+		if !strings.HasPrefix(value, "rotated:") {
+			t.Errorf("%s = %q, want rotated", name, value)
+		}
+	}
+
+	// Unchanged values are not rewritten
+	if count, err := s.ReencryptCredentials(rotate); err != nil || count != 0 {
+		t.Errorf("second run updated %d credentials (%v), want 0", count, err)
+	}
+}
+
+func TestReencryptCredentialsRollsBack(t *testing.T) {
+	t.Run("reencrypt fails", func(t *testing.T) {
+		s, _ := newCredentialTestStore(t)
+		before := credentialValues(t, s)
+
+		_, err := s.ReencryptCredentials(func(value string) (string, error) {
+			if value == "pass-1" {
+				return "", errors.New("decryption failed")
+			}
+			return "rotated:" + value, nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "exchanges.passphrase") {
+			t.Fatalf("ReencryptCredentials = %v, want error naming exchanges.passphrase", err)
+		}
+		for name, value := range credentialValues(t, s) {
+			if value != before[name] {
+				t.Errorf("%s changed to %q by an aborted rotation", name, value)
+			}
+		}
+	})
+
+	t.Run("update fails mid-transaction", func(t *testing.T) {
+		s, _ := newCredentialTestStore(t)
+		before := credentialValues(t, s)
+		// Fails the third of four updates, after api_key and secret_key were written
+		if _, err := s.db.Exec(`
+			CREATE TRIGGER fail_passphrase_update BEFORE UPDATE OF passphrase ON exchanges
+			BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END
+		`); err != nil {
+			t.Fatalf("failed to create trigger: %v", err)
+		}
+
+		if _, err := s.ReencryptCredentials(func(value string) (string, error) {
+			return "rotated:" + value, nil
+		}); err == nil {
+			t.Fatal("ReencryptCredentials should fail")
+		}
+		for name, value := range credentialValues(t, s) {
+			if value != before[name] {
+				t.Errorf("%s changed to %q, the transaction was not rolled back", name, value)
+			}
+		}
+	})
+}
+
+// cryptoFuncs storage encrypt/decrypt functions wired like the server does (undecryptable values stay encrypted)
+func cryptoFuncs(t *testing.T, cs *crypto.CryptoService) (func(string) string, func(string) string) {
+	encrypt := func(plaintext string) string {
+		encrypted, err := cs.EncryptForStorage(plaintext)
+		if err != nil {
+			t.Fatalf("EncryptForStorage: %v", err)
+		}
+		return encrypted
+	}
+	decrypt := func(encrypted string) string {
+		decrypted, err := cs.DecryptFromStorage(encrypted)
+		if err != nil {
+			return encrypted
+		}
+		return decrypted
+	}
+	return encrypt, decrypt
+}
+
+// newStorageCrypto creates a storage crypto service from the given current and previous data keys
+func newStorageCrypto(t *testing.T, current, previous string) *crypto.CryptoService {
+	t.Helper()
+	t.Setenv(crypto.EnvDataEncryptionKey, current)
+	t.Setenv(crypto.EnvPreviousDataEncryptionKeys, previous)
+	cs, err := crypto.NewStorageCryptoService()
+	if err != nil {
+		t.Fatalf("NewStorageCryptoService: %v", err)
+	}
+	return cs
+}
+
+func reencryptWith(cs *crypto.CryptoService) func(string) (string, error) {
+	return func(value string) (string, error) {
+		return cs.ReencryptForStorage(value)
+	}
+}
+
+func TestCheckDecrypted(t *testing.T) {
+	oldKey, _ := crypto.GenerateDataKey()
+	newKey, _ := crypto.GenerateDataKey()
+	s, exchangeID := newCredentialTestStore(t)
+	if _, err := s.ReencryptCredentials(reencryptWith(newStorageCrypto(t, oldKey, ""))); err != nil {
+		t.Fatalf("failed to encrypt credentials with the old key: %v", err)
+	}
+
+	load := func(cs *crypto.CryptoService) (*Exchange, *AIModel) {
+		s.SetCryptoFuncs(cryptoFuncs(t, cs))
+		exchange, err := s.Exchange().GetByID("alice", exchangeID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		model, err := s.AIModel().GetByID("alice_deepseek")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		return exchange, model
+	}
+
+	// New key without the previous one: rows sealed with the old key cannot be read
+	exchange, model := load(newStorageCrypto(t, newKey, ""))
+	if err := exchange.CheckDecrypted(crypto.IsEncryptedStorageValue); err == nil || !strings.Contains(err.Error(), "could not be decrypted") {
+		t.Errorf("exchange CheckDecrypted = %v, want undecryptable credential", err)
+	}
+	if err := model.CheckDecrypted(crypto.IsEncryptedStorageValue); err == nil {
+		t.Error("AI model CheckDecrypted should report the undecryptable api_key")
+	}
+
+	// During rotation the previous key still opens them
+	rotating := newStorageCrypto(t, newKey, oldKey)
+	exchange, model = load(rotating)
+	if err := exchange.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil || exchange.SecretKey != "secret-1" {
+		t.Errorf("exchange CheckDecrypted = %v (secret %q), want decrypted", err, exchange.SecretKey)
+	}
+	if err := model.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil || model.APIKey != "model-key" {
+		t.Errorf("AI model CheckDecrypted = %v (key %q), want decrypted", err, model.APIKey)
+	}
+
+	// After rotating the rows the new key alone is enough
+	if count, err := s.ReencryptCredentials(reencryptWith(rotating)); err != nil || count != 4 {
+		t.Fatalf("ReencryptCredentials rotated %d (%v), want 4", count, err)
+	}
+	exchange, model = load(newStorageCrypto(t, newKey, ""))
+	if err := exchange.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil {
+		t.Errorf("exchange CheckDecrypted after rotation = %v", err)
+	}
+	if err := model.CheckDecrypted(crypto.IsEncryptedStorageValue); err != nil {
+		t.Errorf("AI model CheckDecrypted after rotation = %v", err)
+	}
+}
+
+func TestStrategyWebhookSecretEncrypted(t *testing.T) {
+	oldKey, _ := crypto.GenerateDataKey()
+	newKey, _ := crypto.GenerateDataKey()
+	s, _ := newCredentialTestStore(t)
+	s.SetCryptoFuncs(cryptoFuncs(t, newStorageCrypto(t, oldKey, "")))
+
+	config := GetDefaultStrategyConfig("en")
+	config.CoinSource.SourceType = "webhook"
+	config.CoinSource.WebhookSecret = "hook-secret"
+	strategy := &Strategy{ID: "webhook-strategy", UserID: "alice", Name: "Webhook"}
+	if err := strategy.SetConfig(&config); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	if err := s.Strategy().Create(strategy); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	storedSecret := func() string {
+		t.Helper()
+		var raw string
+		if err := s.db.QueryRow(`SELECT config FROM strategies WHERE id = ?`, strategy.ID).Scan(&raw); err != nil {
+			t.Fatalf("failed to read strategy: %v", err)
+		}
+		var stored StrategyConfig
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			t.Fatalf("stored config is not valid JSON: %v", err)
+		}
+		return stored.CoinSource.WebhookSecret
+	}
+	loadedSecret := func() string {
+		t.Helper()
+		loaded, err := s.Strategy().Get("alice", strategy.ID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		parsed, err := loaded.ParseConfig()
+		if err != nil {
+			t.Fatalf("ParseConfig: %v", err)
+		}
+		return parsed.CoinSource.WebhookSecret
+	}
+
+	sealed := storedSecret()
+	if !crypto.IsEncryptedStorageValue(sealed) {
+		t.Fatalf("stored webhook_secret = %q, want encrypted", sealed)
+	}
+	if got := loadedSecret(); got != "hook-secret" {
+		t.Errorf("loaded webhook_secret = %q, want hook-secret", got)
+	}
+
+	// Key rotation re-encrypts the secret inside the config JSON as well
+	rotating := newStorageCrypto(t, newKey, oldKey)
+	if count, err := s.ReencryptCredentials(reencryptWith(rotating)); err != nil || count != 5 {
+		t.Fatalf("ReencryptCredentials rotated %d (%v), want 5", count, err)
+	}
+	if rotated := storedSecret(); rotated == sealed || !crypto.IsEncryptedStorageValue(rotated) {
+		t.Errorf("stored webhook_secret = %q, want re-encrypted", rotated)
+	}
+	s.SetCryptoFuncs(cryptoFuncs(t, newStorageCrypto(t, newKey, "")))
+	if got := loadedSecret(); got != "hook-secret" {
+		t.Errorf("webhook_secret after rotation = %q, want hook-secret", got)
+	}
+}
@@ -9,7 +9,8 @@ import (
 
 // DecisionStore decision log storage
 type DecisionStore struct {
-	db *sql.DB
+	db     *sql.DB
+	userID string // Owner reads are limited to (see ForUser), empty = all users
 }
 
 // DecisionRecord decision record
@@ -24,10 +25,16 @@ type DecisionRecord struct {
 	DecisionJSON        string             `json:"decision_json"`
 	RawResponse         string             `json:"raw_response"` // Raw AI response for debugging
 	CandidateCoins      []string           `json:"candidate_coins"`
+	FilteredOut         []FilteredSymbol   `json:"filtered_out,omitempty"` // Candidates dropped before the AI saw them, with the reason
+	DataGaps            []DataGap          `json:"data_gaps,omitempty"`    // Feeds missing for symbols the AI saw (data completeness report)
 	ExecutionLog        []string           `json:"execution_log"`
 	Success             bool               `json:"success"`
 	ErrorMessage        string             `json:"error_message"`
+	ErrorCategory       string             `json:"error_category,omitempty"` // ErrorCategory* of ErrorMessage, or of the AI failure a fallback covered
 	AIRequestDurationMs int64              `json:"ai_request_duration_ms"`
+	ShockMode           bool               `json:"shock_mode"`             // Cycle ran in market shock mode (no opens, stops tightened)
+	ShockReason         string             `json:"shock_reason,omitempty"` // What triggered shock mode
+	AITimeout           bool               `json:"ai_timeout"`             // AI decision exceeded the latency SLA (fallback used or cycle skipped)
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
@@ -55,6 +62,37 @@ type PositionSnapshot struct {
 	LiquidationPrice float64 `json:"liquidation_price"`
 }
 
+// Reasons a candidate was filtered out (FilteredSymbol.Reason)
+const (
+	FilterReasonSymbolList  = "symbol_list"  // Trader's symbol allow/deny lists
+	FilterReasonLowOI       = "low_oi"       // Open interest value below the source's liquidity threshold
+	FilterReasonDataQuality = "data_quality" // Kline data quality below the minimum
+	FilterReasonFetchFailed = "fetch_failed" // Market data could not be fetched
+	FilterReasonRankingCut  = "ranking_cut"  // Cut by candidate opportunity ranking
+)
+
+// FilteredSymbol candidate removed from the cycle before the AI decision
+type FilteredSymbol struct {
+	Symbol string `json:"symbol"`
+	Reason string `json:"reason"`           // FilterReason* constant
+	Detail string `json:"detail,omitempty"` // Human-readable specifics, e.g. "OI 8.20M < 15.00M USD (oi_top)"
+}
+
+// Data feeds of the completeness report (DataGap.Feed)
+const (
+	DataFeedKlines = "klines" // Kline series (whole fetch or a secondary timeframe)
+	DataFeedQuant  = "quant"  // Quantitative data API
+	DataFeedNews   = "news"   // Stock news
+	DataFeedOI     = "oi"     // Open interest
+)
+
+// DataGap feed that failed for a symbol in a cycle
+type DataGap struct {
+	Symbol string `json:"symbol"`
+	Feed   string `json:"feed"`   // DataFeed* constant
+	Reason string `json:"reason"` // Error or what is missing, e.g. "4h: no bars"
+}
+
 // DecisionAction decision action
 type DecisionAction struct {
 	Action     string    `json:"action"`
@@ -70,6 +108,30 @@ type DecisionAction struct {
 	Timestamp  time.Time `json:"timestamp"`
 	Success    bool      `json:"success"`
 	Error      string    `json:"error"`
+
+	ExchangeOrderID string `json:"exchange_order_id,omitempty"` // Order ID of exchanges with non-numeric IDs (OrderID is 0)
+
+	// Error taxonomy (empty on success and on records saved before it)
+	ErrorCategory string `json:"error_category,omitempty"` // ErrorCategory* constant
+
+	// Decision schema v2 (records saved before v2 have neither field)
+	SchemaVersion int                    `json:"schema_version,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"` // Extra action parameters from the AI decision
+
+	Preview *TradePreview `json:"preview,omitempty"` // Expected sizing and risk of an open, computed before execution
+}
+
+// TradePreview what an open decision is about to do, computed from the validated decision before execution
+type TradePreview struct {
+	EntryPrice       float64 `json:"entry_price"`        // Limit price, or current price for market entries
+	Quantity         float64 `json:"quantity"`           // Position size / entry price
+	PositionValue    float64 `json:"position_value"`     // Notional value (USD)
+	Margin           float64 `json:"margin"`             // Position value / leverage
+	LiquidationPrice float64 `json:"liquidation_price"`  // Estimated isolated-margin liquidation (0 = unleveraged)
+	RiskUSD          float64 `json:"risk_usd"`           // Loss if the stop loss fills
+	RewardUSD        float64 `json:"reward_usd"`         // Profit if the take profit fills
+	RiskReward       float64 `json:"risk_reward"`        // Reward / risk
+	EquityAtRiskPct  float64 `json:"equity_at_risk_pct"` // Risk USD as % of account equity
 }
 
 // Statistics statistics information
@@ -120,6 +182,22 @@ func (s *DecisionStore) initTables() error {
 	// Migration: add decisions column if not exists
 	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN decisions TEXT DEFAULT '[]'`)
 
+	// Migration: add market shock mode columns if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN shock_mode BOOLEAN DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN shock_reason TEXT DEFAULT ''`)
+
+	// Migration: add AI timeout flag if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN ai_timeout BOOLEAN DEFAULT 0`)
+
+	// Migration: add error category if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN error_category TEXT DEFAULT ''`)
+
+	// Migration: add filtered out candidates if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN filtered_out TEXT DEFAULT '[]'`)
+
+	// Migration: add data completeness gaps if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN data_gaps TEXT DEFAULT '[]'`)
+
 	return nil
 }
 
@@ -135,19 +213,24 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	candidateCoinsJSON, _ := json.Marshal(record.CandidateCoins)
 	executionLogJSON, _ := json.Marshal(record.ExecutionLog)
 	decisionsJSON, _ := json.Marshal(record.Decisions)
+	filteredOutJSON, _ := json.Marshal(record.FilteredOut)
+	dataGapsJSON, _ := json.Marshal(record.DataGaps)
 
 	// Insert decision record main table (only save AI decision related content)
 	result, err := s.db.Exec(`
 		INSERT INTO decision_records (
 			trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			cot_trace, decision_json, raw_response, candidate_coins, execution_log,
-			decisions, success, error_message, ai_request_duration_ms
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			decisions, success, error_message, ai_request_duration_ms, shock_mode, shock_reason, ai_timeout,
+			error_category, filtered_out, data_gaps
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		record.TraderID, record.CycleNumber, record.Timestamp.Format(time.RFC3339),
 		record.SystemPrompt, record.InputPrompt, record.CoTTrace, record.DecisionJSON,
 		record.RawResponse, string(candidateCoinsJSON), string(executionLogJSON),
 		string(decisionsJSON), record.Success, record.ErrorMessage, record.AIRequestDurationMs,
+		record.ShockMode, record.ShockReason, record.AITimeout, record.ErrorCategory,
+		string(filteredOutJSON), string(dataGapsJSON),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert decision record: %w", err)
@@ -162,17 +245,24 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	return nil
 }
 
+// ForUser returns a copy of the store whose reads only return userID's records
+func (s *DecisionStore) ForUser(userID string) *DecisionStore {
+	return &DecisionStore{db: s.db, userID: userID}
+}
+
 // GetLatestRecords gets the latest N records for specified trader (sorted by time in ascending order: old to new)
 func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRecord, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms
+			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms,
+			   COALESCE(shock_mode, 0), COALESCE(shock_reason, ''), COALESCE(ai_timeout, 0),
+			   COALESCE(error_category, ''), COALESCE(filtered_out, '[]'), COALESCE(data_gaps, '[]')
 		FROM decision_records
-		WHERE trader_id = ?
+		WHERE trader_id = ? AND `+tenantOwned+`
 		ORDER BY timestamp DESC
 		LIMIT ?
-	`, traderID, n)
+	`, traderID, s.userID, s.userID, n)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query decision records: %w", err)
 	}
@@ -205,7 +295,9 @@ func (s *DecisionStore) GetAllLatestRecords(n int) ([]*DecisionRecord, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms
+			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms,
+			   COALESCE(shock_mode, 0), COALESCE(shock_reason, ''), COALESCE(ai_timeout, 0),
+			   COALESCE(error_category, ''), COALESCE(filtered_out, '[]'), COALESCE(data_gaps, '[]')
 		FROM decision_records
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -239,11 +331,42 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms
+			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms,
+			   COALESCE(shock_mode, 0), COALESCE(shock_reason, ''), COALESCE(ai_timeout, 0),
+			   COALESCE(error_category, ''), COALESCE(filtered_out, '[]'), COALESCE(data_gaps, '[]')
+		FROM decision_records
+		WHERE trader_id = ? AND `+tenantOwned+` AND DATE(timestamp) = ?
+		ORDER BY timestamp ASC
+	`, traderID, s.userID, s.userID, dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*DecisionRecord
+	for rows.Next() {
+		record, err := s.scanDecisionRecord(rows)
+		if err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// GetRecordsSince gets records since the given time (zero = all), oldest first
+func (s *DecisionStore) GetRecordsSince(traderID string, since time.Time) ([]*DecisionRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
+			   cot_trace, decision_json, candidate_coins, execution_log,
+			   COALESCE(decisions, '[]'), success, error_message, ai_request_duration_ms,
+			   COALESCE(shock_mode, 0), COALESCE(shock_reason, ''), COALESCE(ai_timeout, 0),
+			   COALESCE(error_category, ''), COALESCE(filtered_out, '[]'), COALESCE(data_gaps, '[]')
 		FROM decision_records
-		WHERE trader_id = ? AND DATE(timestamp) = ?
+		WHERE trader_id = ? AND `+tenantOwned+` AND timestamp >= ?
 		ORDER BY timestamp ASC
-	`, traderID, dateStr)
+	`, traderID, s.userID, s.userID, since.UTC().Format(time.RFC3339))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query decision records: %w", err)
 	}
@@ -281,15 +404,15 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	stats := &Statistics{}
 
 	err := s.db.QueryRow(`
-		SELECT COUNT(*) FROM decision_records WHERE trader_id = ?
-	`, traderID).Scan(&stats.TotalCycles)
+		SELECT COUNT(*) FROM decision_records WHERE trader_id = ? AND `+tenantOwned+`
+	`, traderID, s.userID, s.userID).Scan(&stats.TotalCycles)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query total cycles: %w", err)
 	}
 
 	err = s.db.QueryRow(`
-		SELECT COUNT(*) FROM decision_records WHERE trader_id = ? AND success = 1
-	`, traderID).Scan(&stats.SuccessfulCycles)
+		SELECT COUNT(*) FROM decision_records WHERE trader_id = ? AND `+tenantOwned+` AND success = 1
+	`, traderID, s.userID, s.userID).Scan(&stats.SuccessfulCycles)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query successful cycles: %w", err)
 	}
@@ -298,13 +421,13 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	// Count from trader_positions table
 	s.db.QueryRow(`
 		SELECT COUNT(*) FROM trader_positions
-		WHERE trader_id = ?
-	`, traderID).Scan(&stats.TotalOpenPositions)
+		WHERE trader_id = ? AND `+tenantOwned+`
+	`, traderID, s.userID, s.userID).Scan(&stats.TotalOpenPositions)
 
 	s.db.QueryRow(`
 		SELECT COUNT(*) FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
-	`, traderID).Scan(&stats.TotalClosePositions)
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED'
+	`, traderID, s.userID, s.userID).Scan(&stats.TotalClosePositions)
 
 	return stats, nil
 }
@@ -346,13 +469,15 @@ func (s *DecisionStore) GetLastCycleNumber(traderID string) (int, error) {
 func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, error) {
 	var record DecisionRecord
 	var timestampStr string
-	var candidateCoinsJSON, executionLogJSON, decisionsJSON string
+	var candidateCoinsJSON, executionLogJSON, decisionsJSON, filteredOutJSON, dataGapsJSON string
 
 	err := rows.Scan(
 		&record.ID, &record.TraderID, &record.CycleNumber, &timestampStr,
 		&record.SystemPrompt, &record.InputPrompt, &record.CoTTrace,
 		&record.DecisionJSON, &candidateCoinsJSON, &executionLogJSON,
 		&decisionsJSON, &record.Success, &record.ErrorMessage, &record.AIRequestDurationMs,
+		&record.ShockMode, &record.ShockReason, &record.AITimeout, &record.ErrorCategory,
+		&filteredOutJSON, &dataGapsJSON,
 	)
 	if err != nil {
 		return nil, err
@@ -361,6 +486,8 @@ func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, err
 	record.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 	json.Unmarshal([]byte(candidateCoinsJSON), &record.CandidateCoins)
 	json.Unmarshal([]byte(executionLogJSON), &record.ExecutionLog)
+	json.Unmarshal([]byte(filteredOutJSON), &record.FilteredOut)
+	json.Unmarshal([]byte(dataGapsJSON), &record.DataGaps)
 	json.Unmarshal([]byte(decisionsJSON), &record.Decisions)
 
 	return &record, nil
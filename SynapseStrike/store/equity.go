@@ -8,7 +8,8 @@ import (
 
 // EquityStore account equity storage (for plotting return curves)
 type EquityStore struct {
-	db *sql.DB
+	db     *sql.DB
+	userID string // Owner reads are limited to (see ForUser), empty = all users
 }
 
 // EquitySnapshot equity snapshot
@@ -21,6 +22,39 @@ type EquitySnapshot struct {
 	UnrealizedPnL float64   `json:"unrealized_pnl"`  // Unrealized profit and loss
 	PositionCount int       `json:"position_count"`  // Position count
 	MarginUsedPct float64   `json:"margin_used_pct"` // Margin usage percentage
+
+	// Buy-and-hold benchmark at the time of the snapshot (empty when not tracked)
+	BenchmarkSymbol string  `json:"benchmark_symbol,omitempty"`
+	BenchmarkPrice  float64 `json:"benchmark_price,omitempty"`
+	BenchmarkEquity float64 `json:"benchmark_equity,omitempty"` // Initial balance held in the benchmark since tracking began
+
+	// Rollup of old snapshots (see equity_rollup.go): the bucket's last values and its equity range
+	ResolutionSec int     `json:"resolution_sec,omitempty"` // Bucket size in seconds (0 = raw snapshot)
+	EquityLow     float64 `json:"equity_low,omitempty"`     // Lowest total equity in the bucket
+	EquityHigh    float64 `json:"equity_high,omitempty"`    // Highest total equity in the bucket
+}
+
+// equitySnapshotColumns columns read by scanEquitySnapshot
+const equitySnapshotColumns = `id, trader_id, timestamp, total_equity, balance,
+		       unrealized_pnl, position_count, margin_used_pct,
+		       COALESCE(benchmark_symbol, ''), COALESCE(benchmark_price, 0), COALESCE(benchmark_equity, 0),
+		       COALESCE(resolution_sec, 0), COALESCE(equity_low, 0), COALESCE(equity_high, 0)`
+
+// scanEquitySnapshot scans a row selected with equitySnapshotColumns
+func scanEquitySnapshot(rows *sql.Rows) (*EquitySnapshot, error) {
+	snap := &EquitySnapshot{}
+	var timestampStr string
+	err := rows.Scan(
+		&snap.ID, &snap.TraderID, &timestampStr, &snap.TotalEquity,
+		&snap.Balance, &snap.UnrealizedPnL, &snap.PositionCount, &snap.MarginUsedPct,
+		&snap.BenchmarkSymbol, &snap.BenchmarkPrice, &snap.BenchmarkEquity,
+		&snap.ResolutionSec, &snap.EquityLow, &snap.EquityHigh,
+	)
+	if err != nil {
+		return nil, err
+	}
+	snap.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
+	return snap, nil
 }
 
 // initTables initializes equity tables
@@ -49,9 +83,29 @@ func (s *EquityStore) initTables() error {
 		}
 	}
 
+	// Migration: add buy-and-hold benchmark columns if not exists
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN benchmark_symbol TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN benchmark_price REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN benchmark_equity REAL DEFAULT 0`)
+
+	// Migration: add rollup columns if not exists
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN resolution_sec INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN equity_low REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_equity_snapshots ADD COLUMN equity_high REAL DEFAULT 0`)
+
 	return nil
 }
 
+// insertEquitySnapshotSQL inserts one snapshot row (raw or rolled up)
+const insertEquitySnapshotSQL = `
+		INSERT INTO trader_equity_snapshots (
+			trader_id, timestamp, total_equity, balance,
+			unrealized_pnl, position_count, margin_used_pct,
+			benchmark_symbol, benchmark_price, benchmark_equity,
+			resolution_sec, equity_low, equity_high
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
 // Save saves equity snapshot
 func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 	if snapshot.Timestamp.IsZero() {
@@ -60,12 +114,7 @@ func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 		snapshot.Timestamp = snapshot.Timestamp.UTC()
 	}
 
-	result, err := s.db.Exec(`
-		INSERT INTO trader_equity_snapshots (
-			trader_id, timestamp, total_equity, balance,
-			unrealized_pnl, position_count, margin_used_pct
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
-	`,
+	result, err := s.db.Exec(insertEquitySnapshotSQL,
 		snapshot.TraderID,
 		snapshot.Timestamp.Format(time.RFC3339),
 		snapshot.TotalEquity,
@@ -73,6 +122,12 @@ func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 		snapshot.UnrealizedPnL,
 		snapshot.PositionCount,
 		snapshot.MarginUsedPct,
+		snapshot.BenchmarkSymbol,
+		snapshot.BenchmarkPrice,
+		snapshot.BenchmarkEquity,
+		snapshot.ResolutionSec,
+		snapshot.EquityLow,
+		snapshot.EquityHigh,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save equity snapshot: %w", err)
@@ -83,16 +138,20 @@ func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 	return nil
 }
 
+// ForUser returns a copy of the store whose reads only return userID's snapshots
+func (s *EquityStore) ForUser(userID string) *EquityStore {
+	return &EquityStore{db: s.db, userID: userID}
+}
+
 // GetLatest gets the latest N equity records for specified trader (sorted in ascending chronological order: old to new)
 func (s *EquityStore) GetLatest(traderID string, limit int) ([]*EquitySnapshot, error) {
 	rows, err := s.db.Query(`
-		SELECT id, trader_id, timestamp, total_equity, balance,
-		       unrealized_pnl, position_count, margin_used_pct
+		SELECT `+equitySnapshotColumns+`
 		FROM trader_equity_snapshots
-		WHERE trader_id = ?
+		WHERE trader_id = ? AND `+tenantOwned+`
 		ORDER BY timestamp DESC
 		LIMIT ?
-	`, traderID, limit)
+	`, traderID, s.userID, s.userID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query equity records: %w", err)
 	}
@@ -100,16 +159,10 @@ func (s *EquityStore) GetLatest(traderID string, limit int) ([]*EquitySnapshot,
 
 	var snapshots []*EquitySnapshot
 	for rows.Next() {
-		snap := &EquitySnapshot{}
-		var timestampStr string
-		err := rows.Scan(
-			&snap.ID, &snap.TraderID, &timestampStr, &snap.TotalEquity,
-			&snap.Balance, &snap.UnrealizedPnL, &snap.PositionCount, &snap.MarginUsedPct,
-		)
+		snap, err := scanEquitySnapshot(rows)
 		if err != nil {
 			continue
 		}
-		snap.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 		snapshots = append(snapshots, snap)
 	}
 
@@ -124,12 +177,11 @@ func (s *EquityStore) GetLatest(traderID string, limit int) ([]*EquitySnapshot,
 // GetByTimeRange gets equity records within specified time range
 func (s *EquityStore) GetByTimeRange(traderID string, start, end time.Time) ([]*EquitySnapshot, error) {
 	rows, err := s.db.Query(`
-		SELECT id, trader_id, timestamp, total_equity, balance,
-		       unrealized_pnl, position_count, margin_used_pct
+		SELECT `+equitySnapshotColumns+`
 		FROM trader_equity_snapshots
-		WHERE trader_id = ? AND timestamp >= ? AND timestamp <= ?
+		WHERE trader_id = ? AND `+tenantOwned+` AND timestamp >= ? AND timestamp <= ?
 		ORDER BY timestamp ASC
-	`, traderID, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	`, traderID, s.userID, s.userID, start.Format(time.RFC3339), end.Format(time.RFC3339))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query equity records: %w", err)
 	}
@@ -137,16 +189,10 @@ func (s *EquityStore) GetByTimeRange(traderID string, start, end time.Time) ([]*
 
 	var snapshots []*EquitySnapshot
 	for rows.Next() {
-		snap := &EquitySnapshot{}
-		var timestampStr string
-		err := rows.Scan(
-			&snap.ID, &snap.TraderID, &timestampStr, &snap.TotalEquity,
-			&snap.Balance, &snap.UnrealizedPnL, &snap.PositionCount, &snap.MarginUsedPct,
-		)
+		snap, err := scanEquitySnapshot(rows)
 		if err != nil {
 			continue
 		}
-		snap.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 		snapshots = append(snapshots, snap)
 	}
 
@@ -157,7 +203,9 @@ func (s *EquityStore) GetByTimeRange(traderID string, start, end time.Time) ([]*
 func (s *EquityStore) GetAllTradersLatest() (map[string]*EquitySnapshot, error) {
 	rows, err := s.db.Query(`
 		SELECT e.id, e.trader_id, e.timestamp, e.total_equity, e.balance,
-		       e.unrealized_pnl, e.position_count, e.margin_used_pct
+		       e.unrealized_pnl, e.position_count, e.margin_used_pct,
+		       COALESCE(e.benchmark_symbol, ''), COALESCE(e.benchmark_price, 0), COALESCE(e.benchmark_equity, 0),
+		       COALESCE(e.resolution_sec, 0), COALESCE(e.equity_low, 0), COALESCE(e.equity_high, 0)
 		FROM trader_equity_snapshots e
 		INNER JOIN (
 			SELECT trader_id, MAX(timestamp) as max_ts
@@ -172,16 +220,10 @@ func (s *EquityStore) GetAllTradersLatest() (map[string]*EquitySnapshot, error)
 
 	result := make(map[string]*EquitySnapshot)
 	for rows.Next() {
-		snap := &EquitySnapshot{}
-		var timestampStr string
-		err := rows.Scan(
-			&snap.ID, &snap.TraderID, &timestampStr, &snap.TotalEquity,
-			&snap.Balance, &snap.UnrealizedPnL, &snap.PositionCount, &snap.MarginUsedPct,
-		)
+		snap, err := scanEquitySnapshot(rows)
 		if err != nil {
 			continue
 		}
-		snap.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 		result[snap.TraderID] = snap
 	}
 
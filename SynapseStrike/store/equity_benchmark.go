@@ -0,0 +1,70 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ============================================================================
+// Buy-and-Hold Benchmark
+// ============================================================================
+// Next to the account equity, each snapshot records what the trader's initial
+// balance would be worth had it simply been held in the benchmark (BTC, SPY,
+// ... per strategy) since tracking began. The first snapshot of a benchmark
+// symbol is its base: later snapshots scale the base equity by the price
+// change. Comparing the returns of both curves over any window gives the
+// trader's alpha.
+
+// BenchmarkPerformance return of the account and of its benchmark over a window
+type BenchmarkPerformance struct {
+	Symbol             string  `json:"symbol"`
+	ReturnPct          float64 `json:"return_pct"`           // Account equity return in %
+	BenchmarkReturnPct float64 `json:"benchmark_return_pct"` // Buy-and-hold return in %
+	AlphaPct           float64 `json:"alpha_pct"`            // ReturnPct - BenchmarkReturnPct
+}
+
+// GetBenchmarkBase gets price and equity of the trader's first snapshot tracking symbol (0, 0 if none)
+func (s *EquityStore) GetBenchmarkBase(traderID, symbol string) (price, equity float64, err error) {
+	err = s.db.QueryRow(`
+		SELECT benchmark_price, benchmark_equity FROM trader_equity_snapshots
+		WHERE trader_id = ? AND benchmark_symbol = ? AND benchmark_price > 0
+		ORDER BY timestamp ASC
+		LIMIT 1
+	`, traderID, symbol).Scan(&price, &equity)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query benchmark base: %w", err)
+	}
+	return price, equity, nil
+}
+
+// RelativePerformance compares the account with its benchmark between two snapshots
+// ok is false unless both snapshots track the same benchmark and have equity.
+func RelativePerformance(start, end *EquitySnapshot) (perf BenchmarkPerformance, ok bool) {
+	if start == nil || end == nil || start.BenchmarkSymbol == "" || start.BenchmarkSymbol != end.BenchmarkSymbol ||
+		start.TotalEquity <= 0 || start.BenchmarkEquity <= 0 {
+		return perf, false
+	}
+	perf.Symbol = start.BenchmarkSymbol
+	perf.ReturnPct = (end.TotalEquity/start.TotalEquity - 1) * 100
+	perf.BenchmarkReturnPct = (end.BenchmarkEquity/start.BenchmarkEquity - 1) * 100
+	perf.AlphaPct = perf.ReturnPct - perf.BenchmarkReturnPct
+	return perf, true
+}
+
+// WindowPerformance compares the account with its benchmark over the snapshots of a window (old to new)
+// The window starts at the first snapshot tracking the benchmark of the last snapshot.
+func WindowPerformance(snapshots []*EquitySnapshot) (BenchmarkPerformance, bool) {
+	if len(snapshots) == 0 {
+		return BenchmarkPerformance{}, false
+	}
+	end := snapshots[len(snapshots)-1]
+	for _, snap := range snapshots {
+		if snap.BenchmarkSymbol == end.BenchmarkSymbol && snap.BenchmarkEquity > 0 {
+			return RelativePerformance(snap, end)
+		}
+	}
+	return BenchmarkPerformance{}, false
+}
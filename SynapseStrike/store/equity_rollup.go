@@ -0,0 +1,165 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Equity Snapshot Rollup
+// ============================================================================
+// A snapshot is saved every cycle, which over months adds up to hundreds of
+// thousands of rows per trader while the charts only need fine detail for the
+// recent past. Old snapshots are therefore downsampled in place: raw points
+// are kept for 7 days, then merged into 15-minute buckets, and after 30 days
+// into hourly buckets. A bucket row is stamped with the bucket start, keeps
+// the values of the bucket's last snapshot (equity, balance, positions,
+// benchmark) and records the lowest and highest equity seen in the bucket, so
+// drawdowns inside a bucket are not lost. Since rollups stay in the same
+// table, every reader (equity history API, drawdown monitor, benchmark)
+// transparently gets a mixed-resolution series: coarse for old data, raw for
+// recent data. Only complete buckets older than a tier's age are compacted,
+// so compaction is idempotent and can run at any time.
+
+// Equity snapshot resolutions (EquitySnapshot.ResolutionSec)
+const (
+	EquityResolutionRaw = 0
+	EquityResolution15m = 15 * 60
+	EquityResolution1h  = 60 * 60
+)
+
+// EquityRollupTier snapshots older than Age are merged into buckets of Resolution seconds
+type EquityRollupTier struct {
+	Age        time.Duration
+	Resolution int
+}
+
+// DefaultEquityRollupTiers raw for 7 days, 15-minute buckets up to 30 days, hourly buckets beyond
+var DefaultEquityRollupTiers = []EquityRollupTier{
+	{Age: 7 * 24 * time.Hour, Resolution: EquityResolution15m},
+	{Age: 30 * 24 * time.Hour, Resolution: EquityResolution1h},
+}
+
+// EquityCompactionResult rows affected by a compaction run
+type EquityCompactionResult struct {
+	RowsRemoved int64 // Finer rows merged away
+	RowsWritten int64 // Bucket rows written
+}
+
+// equityBucket snapshots of one trader falling into one bucket
+type equityBucket struct {
+	ids  []int64
+	last *EquitySnapshot
+	low  float64
+	high float64
+}
+
+// Compact downsamples snapshots according to tiers (ordered by increasing age)
+func (s *EquityStore) Compact(now time.Time, tiers []EquityRollupTier) (EquityCompactionResult, error) {
+	var total EquityCompactionResult
+	for _, tier := range tiers {
+		result, err := s.compactTier(now, tier)
+		if err != nil {
+			return total, err
+		}
+		total.RowsRemoved += result.RowsRemoved
+		total.RowsWritten += result.RowsWritten
+	}
+	return total, nil
+}
+
+// compactTier merges all finer snapshots in complete buckets older than the tier's age
+func (s *EquityStore) compactTier(now time.Time, tier EquityRollupTier) (EquityCompactionResult, error) {
+	var result EquityCompactionResult
+	bucketSize := time.Duration(tier.Resolution) * time.Second
+	if bucketSize <= 0 {
+		return result, nil
+	}
+	// Buckets ending after the cutoff may still receive snapshots of a finer tier
+	cutoff := now.UTC().Add(-tier.Age).Truncate(bucketSize)
+
+	rows, err := s.db.Query(`
+		SELECT `+equitySnapshotColumns+`
+		FROM trader_equity_snapshots
+		WHERE timestamp < ? AND COALESCE(resolution_sec, 0) < ?
+		ORDER BY trader_id, timestamp ASC
+	`, cutoff.Format(time.RFC3339), tier.Resolution)
+	if err != nil {
+		return result, fmt.Errorf("failed to query equity snapshots to compact: %w", err)
+	}
+
+	type bucketKey struct {
+		traderID string
+		start    time.Time
+	}
+	buckets := make(map[bucketKey]*equityBucket)
+	var order []bucketKey
+	for rows.Next() {
+		snap, err := scanEquitySnapshot(rows)
+		if err != nil || snap.Timestamp.IsZero() {
+			continue
+		}
+		low, high := snap.TotalEquity, snap.TotalEquity
+		if snap.ResolutionSec > 0 {
+			low, high = snap.EquityLow, snap.EquityHigh
+		}
+
+		key := bucketKey{snap.TraderID, snap.Timestamp.UTC().Truncate(bucketSize)}
+		b, ok := buckets[key]
+		if !ok {
+			b = &equityBucket{low: low, high: high}
+			buckets[key] = b
+			order = append(order, key)
+		}
+		b.ids = append(b.ids, snap.ID)
+		b.last = snap
+		b.low = min(b.low, low)
+		b.high = max(b.high, high)
+	}
+	rows.Close()
+	if len(order) == 0 {
+		return result, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleteStmt, err := tx.Prepare(`DELETE FROM trader_equity_snapshots WHERE id = ?`)
+	if err != nil {
+		return result, fmt.Errorf("failed to prepare delete: %w", err)
+	}
+	defer deleteStmt.Close()
+	insertStmt, err := tx.Prepare(insertEquitySnapshotSQL)
+	if err != nil {
+		return result, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, key := range order {
+		b := buckets[key]
+		for _, id := range b.ids {
+			if _, err := deleteStmt.Exec(id); err != nil {
+				return result, fmt.Errorf("failed to delete equity snapshot %d: %w", id, err)
+			}
+		}
+		last := b.last
+		if _, err := insertStmt.Exec(
+			key.traderID, key.start.Format(time.RFC3339),
+			last.TotalEquity, last.Balance, last.UnrealizedPnL, last.PositionCount, last.MarginUsedPct,
+			last.BenchmarkSymbol, last.BenchmarkPrice, last.BenchmarkEquity,
+			tier.Resolution, b.low, b.high,
+		); err != nil {
+			return result, fmt.Errorf("failed to write equity rollup: %w", err)
+		}
+		result.RowsRemoved += int64(len(b.ids))
+		result.RowsWritten++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit equity compaction: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEquityCompaction tests downsampling old equity snapshots into 15-minute and hourly buckets
+func TestEquityCompaction(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// One snapshot every 10 minutes over 40 days, equity dips to 900 in the first slot of each hour
+	// Seeded in one transaction: row-by-row commits with FULL sync take half a minute
+	tx, err := st.db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	for ts := now.Add(-40 * 24 * time.Hour); ts.Before(now); ts = ts.Add(10 * time.Minute) {
+		equity := 1000.0 + float64(ts.Minute())
+		if ts.Minute() == 0 {
+			equity = 900
+		}
+		if _, err := tx.Exec(insertEquitySnapshotSQL, "t1", ts.Format(time.RFC3339), equity, 0, 0, 0, 0, "", 0, 0, EquityResolutionRaw, 0, 0); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit snapshots: %v", err)
+	}
+	before, _ := st.Equity().GetCount("t1")
+
+	result, err := st.Equity().Compact(now, DefaultEquityRollupTiers)
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	after, _ := st.Equity().GetCount("t1")
+	if result.RowsRemoved == 0 || int64(before-after) != result.RowsRemoved-result.RowsWritten {
+		t.Fatalf("unexpected compaction result %+v (rows %d -> %d)", result, before, after)
+	}
+	if again, err := st.Equity().Compact(now, DefaultEquityRollupTiers); err != nil || again.RowsRemoved != 0 {
+		t.Errorf("second pass compacted again: %+v (%v)", again, err)
+	}
+
+	snapshots, err := st.Equity().GetByTimeRange("t1", now.Add(-41*24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("failed to read series: %v", err)
+	}
+	if len(snapshots) != after {
+		t.Fatalf("expected %d points in the mixed series, got %d", after, len(snapshots))
+	}
+	counts := map[int]int{}
+	for i, snap := range snapshots {
+		counts[snap.ResolutionSec]++
+		if i > 0 && !snap.Timestamp.After(snapshots[i-1].Timestamp) {
+			t.Fatalf("series not in chronological order at %s", snap.Timestamp)
+		}
+		age := now.Sub(snap.Timestamp)
+		switch {
+		case age > 30*24*time.Hour:
+			if snap.ResolutionSec != EquityResolution1h {
+				t.Fatalf("snapshot aged %s has resolution %d, want hourly", age, snap.ResolutionSec)
+			}
+			// The hourly bucket keeps the dip and the last value of its 15-minute buckets
+			if snap.EquityLow != 900 || snap.EquityHigh != 1050 || snap.TotalEquity != 1050 {
+				t.Fatalf("hourly bucket at %s: low %.0f high %.0f close %.0f", snap.Timestamp, snap.EquityLow, snap.EquityHigh, snap.TotalEquity)
+			}
+		case age > 7*24*time.Hour:
+			if snap.ResolutionSec != EquityResolution15m {
+				t.Fatalf("snapshot aged %s has resolution %d, want 15 minutes", age, snap.ResolutionSec)
+			}
+		case age < 7*24*time.Hour:
+			if snap.ResolutionSec != EquityResolutionRaw {
+				t.Fatalf("snapshot aged %s was compacted", age)
+			}
+		}
+	}
+	// 7 days of raw 10-minute points, 23 days of 15-minute buckets, 10 days of hourly buckets
+	if counts[EquityResolutionRaw] != 7*24*6 || counts[EquityResolution15m] != 23*24*4 || counts[EquityResolution1h] != 10*24 {
+		t.Errorf("unexpected resolution counts %v", counts)
+	}
+}
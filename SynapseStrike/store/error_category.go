@@ -0,0 +1,63 @@
+package store
+
+import "errors"
+
+// ============================================================================
+// Error Taxonomy
+// ============================================================================
+// Decision records and actions used to carry errors as free-form strings only,
+// which made them impossible to aggregate. Every error stored on a record or
+// action now also gets one of a fixed set of categories. Code that knows why
+// it failed tags the error with WithErrorCategory; ErrorCategoryOf reads the
+// tag back through any amount of %w wrapping.
+
+// Error categories of decision records and actions
+const (
+	ErrorCategoryAIParse        = "ai_parse"        // AI response could not be parsed or validated
+	ErrorCategoryAIAPI          = "ai_api"          // AI provider call failed
+	ErrorCategoryExchangeReject = "exchange_reject" // Exchange rejected or failed the request
+	ErrorCategoryRiskBlock      = "risk_block"      // Blocked by risk control (limits, pauses, blocklist)
+	ErrorCategoryDataMissing    = "data_missing"    // Market or account data unavailable
+	ErrorCategoryTimeout        = "timeout"         // Deadline exceeded
+)
+
+// ErrorCategories all error categories
+var ErrorCategories = []string{
+	ErrorCategoryAIParse,
+	ErrorCategoryAIAPI,
+	ErrorCategoryExchangeReject,
+	ErrorCategoryRiskBlock,
+	ErrorCategoryDataMissing,
+	ErrorCategoryTimeout,
+}
+
+// CategorizedError error tagged with an error category
+type CategorizedError struct {
+	Category string
+	Err      error
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategorizedError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorCategory tags err with an error category (nil stays nil)
+func WithErrorCategory(category string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CategorizedError{Category: category, Err: err}
+}
+
+// ErrorCategoryOf gets the outermost category tag of err, "" if untagged
+func ErrorCategoryOf(err error) string {
+	var categorized *CategorizedError
+	if errors.As(err, &categorized) {
+		return categorized.Category
+	}
+	return ""
+}
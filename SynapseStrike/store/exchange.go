@@ -20,7 +20,7 @@ type ExchangeStore struct {
 // Exchange exchange configuration
 type Exchange struct {
 	ID                      string    `json:"id"`            // UUID
-	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName             string    `json:"account_name"`  // User-defined account name
 	UserID                  string    `json:"user_id"`
 	Name                    string    `json:"name"` // Display name (auto-generated or user-defined)
@@ -38,6 +38,11 @@ type Exchange struct {
 	LighterPrivateKey       string    `json:"lighterPrivateKey"`
 	LighterAPIKeyPrivateKey string    `json:"lighterAPIKeyPrivateKey"`
 	LighterAPIKeyIndex      int       `json:"lighterAPIKeyIndex"`
+	DydxMnemonic            string    `json:"dydxMnemonic"`
+	DydxSubaccount          int       `json:"dydxSubaccount"`
+	QuoteCurrency           string    `json:"quote_currency"`   // Collateral currency (USDT/USDC/EUR/USD, empty = exchange default)
+	CCXTGatewayURL          string    `json:"ccxt_gateway_url"` // CCXT bridge gateway base URL (exchange_type "ccxt")
+	CCXTExchangeID          string    `json:"ccxt_exchange_id"` // CCXT exchange id behind the gateway, e.g. "krakenfutures"
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 }
@@ -65,6 +70,8 @@ func (s *ExchangeStore) initTables() error {
 			lighter_private_key TEXT DEFAULT '',
 			lighter_api_key_private_key TEXT DEFAULT '',
 			lighter_api_key_index INTEGER DEFAULT 0,
+			dydx_mnemonic TEXT DEFAULT '',
+			dydx_subaccount INTEGER DEFAULT 0,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -78,6 +85,11 @@ func (s *ExchangeStore) initTables() error {
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN exchange_type TEXT NOT NULL DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN account_name TEXT NOT NULL DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN lighter_api_key_index INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN dydx_mnemonic TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN dydx_subaccount INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN quote_currency TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN ccxt_gateway_url TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN ccxt_exchange_id TEXT DEFAULT ''`)
 
 	// Run migration to multi-account if needed
 	if err := s.migrateToMultiAccount(); err != nil {
@@ -234,6 +246,11 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
 		       COALESCE(lighter_api_key_index, 0) as lighter_api_key_index,
+		       COALESCE(dydx_mnemonic, '') as dydx_mnemonic,
+		       COALESCE(dydx_subaccount, 0) as dydx_subaccount,
+		       COALESCE(quote_currency, '') as quote_currency,
+		       COALESCE(ccxt_gateway_url, '') as ccxt_gateway_url,
+		       COALESCE(ccxt_exchange_id, '') as ccxt_exchange_id,
 		       created_at, updated_at
 		FROM exchanges WHERE user_id = ? ORDER BY exchange_type, account_name
 	`, userID)
@@ -252,6 +269,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 			&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 			&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 			&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey, &e.LighterAPIKeyIndex,
+			&e.DydxMnemonic, &e.DydxSubaccount, &e.QuoteCurrency, &e.CCXTGatewayURL, &e.CCXTExchangeID,
 			&createdAt, &updatedAt,
 		)
 		if err != nil {
@@ -265,6 +283,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 		e.AsterPrivateKey = s.decrypt(e.AsterPrivateKey)
 		e.LighterPrivateKey = s.decrypt(e.LighterPrivateKey)
 		e.LighterAPIKeyPrivateKey = s.decrypt(e.LighterAPIKeyPrivateKey)
+		e.DydxMnemonic = s.decrypt(e.DydxMnemonic)
 		exchanges = append(exchanges, &e)
 	}
 	return exchanges, nil
@@ -286,6 +305,11 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
 		       COALESCE(lighter_api_key_index, 0) as lighter_api_key_index,
+		       COALESCE(dydx_mnemonic, '') as dydx_mnemonic,
+		       COALESCE(dydx_subaccount, 0) as dydx_subaccount,
+		       COALESCE(quote_currency, '') as quote_currency,
+		       COALESCE(ccxt_gateway_url, '') as ccxt_gateway_url,
+		       COALESCE(ccxt_exchange_id, '') as ccxt_exchange_id,
 		       created_at, updated_at
 		FROM exchanges WHERE id = ? AND user_id = ?
 	`, id, userID).Scan(
@@ -294,6 +318,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 		&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 		&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey, &e.LighterAPIKeyIndex,
+		&e.DydxMnemonic, &e.DydxSubaccount, &e.QuoteCurrency, &e.CCXTGatewayURL, &e.CCXTExchangeID,
 		&createdAt, &updatedAt,
 	)
 	if err != nil {
@@ -307,6 +332,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 	e.AsterPrivateKey = s.decrypt(e.AsterPrivateKey)
 	e.LighterPrivateKey = s.decrypt(e.LighterPrivateKey)
 	e.LighterAPIKeyPrivateKey = s.decrypt(e.LighterAPIKeyPrivateKey)
+	e.DydxMnemonic = s.decrypt(e.DydxMnemonic)
 	return &e, nil
 }
 
@@ -315,6 +341,10 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 	switch exchangeType {
 	case "binance":
 		return "Binance Futures", "cex"
+	case "binance-spot":
+		return "Binance Spot", "cex"
+	case "coinbase":
+		return "Coinbase", "cex"
 	case "bybit":
 		return "Bybit Futures", "cex"
 	case "okx":
@@ -327,6 +357,10 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 		return "Aster DEX", "dex"
 	case "lighter":
 		return "LIGHTER DEX", "dex"
+	case "dydx":
+		return "dYdX v4", "dex"
+	case "ccxt":
+		return "CCXT Bridge", "cex"
 	default:
 		return exchangeType + " Exchange", "cex"
 	}
@@ -336,7 +370,8 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled bool,
 	apiKey, secretKey, passphrase string, testnet bool,
 	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey,
-	lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string, lighterApiKeyIndex int) (string, error) {
+	lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string, lighterApiKeyIndex int,
+	dydxMnemonic string, dydxSubaccount int) (string, error) {
 
 	id := uuid.New().String()
 	name, typ := getExchangeNameAndType(exchangeType)
@@ -354,12 +389,14 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 		                       api_key, secret_key, passphrase, testnet,
 		                       hyperliquid_wallet_addr, aster_user, aster_signer, aster_private_key,
 		                       lighter_wallet_addr, lighter_private_key, lighter_api_key_private_key, lighter_api_key_index,
+		                       dydx_mnemonic, dydx_subaccount,
 		                       created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
 	`, id, exchangeType, accountName, userID, name, typ, enabled,
 		s.encrypt(apiKey), s.encrypt(secretKey), s.encrypt(passphrase), testnet,
 		hyperliquidWalletAddr, asterUser, asterSigner, s.encrypt(asterPrivateKey),
-		lighterWalletAddr, s.encrypt(lighterPrivateKey), s.encrypt(lighterApiKeyPrivateKey), lighterApiKeyIndex)
+		lighterWalletAddr, s.encrypt(lighterPrivateKey), s.encrypt(lighterApiKeyPrivateKey), lighterApiKeyIndex,
+		s.encrypt(dydxMnemonic), dydxSubaccount)
 
 	if err != nil {
 		return "", err
@@ -369,7 +406,8 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 
 // Update updates exchange configuration by UUID
 func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKey, passphrase string, testnet bool,
-	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string, lighterApiKeyIndex int) error {
+	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string, lighterApiKeyIndex int,
+	dydxMnemonic string, dydxSubaccount int) error {
 
 	logger.Debugf("🔧 ExchangeStore.Update: userID=%s, id=%s, enabled=%v", userID, id, enabled)
 
@@ -381,9 +419,10 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 		"aster_signer = ?",
 		"lighter_wallet_addr = ?",
 		"lighter_api_key_index = ?",
+		"dydx_subaccount = ?",
 		"updated_at = datetime('now')",
 	}
-	args := []interface{}{enabled, testnet, hyperliquidWalletAddr, asterUser, asterSigner, lighterWalletAddr, lighterApiKeyIndex}
+	args := []interface{}{enabled, testnet, hyperliquidWalletAddr, asterUser, asterSigner, lighterWalletAddr, lighterApiKeyIndex, dydxSubaccount}
 
 	if apiKey != "" {
 		setClauses = append(setClauses, "api_key = ?")
@@ -409,6 +448,10 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 		setClauses = append(setClauses, "lighter_api_key_private_key = ?")
 		args = append(args, s.encrypt(lighterApiKeyPrivateKey))
 	}
+	if dydxMnemonic != "" {
+		setClauses = append(setClauses, "dydx_mnemonic = ?")
+		args = append(args, s.encrypt(dydxMnemonic))
+	}
 
 	args = append(args, id, userID)
 	query := fmt.Sprintf(`UPDATE exchanges SET %s WHERE id = ? AND user_id = ?`, strings.Join(setClauses, ", "))
@@ -439,6 +482,34 @@ func (s *ExchangeStore) UpdateAccountName(userID, id, accountName string) error
 	return nil
 }
 
+// UpdateQuoteCurrency updates the collateral currency of an exchange (empty = exchange default)
+func (s *ExchangeStore) UpdateQuoteCurrency(userID, id, quoteCurrency string) error {
+	result, err := s.db.Exec(`UPDATE exchanges SET quote_currency = ?, updated_at = datetime('now') WHERE id = ? AND user_id = ?`,
+		strings.ToUpper(strings.TrimSpace(quoteCurrency)), id, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	return nil
+}
+
+// UpdateCCXTBridge updates the gateway URL and CCXT exchange id of a CCXT bridge account
+func (s *ExchangeStore) UpdateCCXTBridge(userID, id, gatewayURL, ccxtExchangeID string) error {
+	result, err := s.db.Exec(`UPDATE exchanges SET ccxt_gateway_url = ?, ccxt_exchange_id = ?, updated_at = datetime('now') WHERE id = ? AND user_id = ?`,
+		strings.TrimSpace(gatewayURL), strings.ToLower(strings.TrimSpace(ccxtExchangeID)), id, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	return nil
+}
+
 // Delete deletes an exchange account
 func (s *ExchangeStore) Delete(userID, id string) error {
 	result, err := s.db.Exec(`DELETE FROM exchanges WHERE id = ? AND user_id = ?`, id, userID)
@@ -459,10 +530,10 @@ func (s *ExchangeStore) CreateLegacy(userID, id, name, typ string, enabled bool,
 	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey string) error {
 
 	// Check if this is an old-style ID (exchange type as ID)
-	if id == "binance" || id == "bybit" || id == "okx" || id == "bitget" || id == "hyperliquid" || id == "aster" || id == "lighter" {
+	if id == "binance" || id == "bybit" || id == "okx" || id == "bitget" || id == "hyperliquid" || id == "aster" || id == "lighter" || id == "dydx" {
 		// Use new Create method with exchange type
 		_, err := s.Create(userID, id, "Default", enabled, apiKey, secretKey, "", testnet,
-			hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, "", "", "", 0)
+			hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, "", "", "", 0, "", 0)
 		return err
 	}
 
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Execution queue item statuses
+const (
+	ExecutionPending = "pending" // Waiting for execution (or for its next retry)
+	ExecutionRunning = "running" // Claimed by the executor (reset to pending on restart)
+	ExecutionDone    = "done"
+	ExecutionFailed  = "failed"
+	ExecutionExpired = "expired" // Not executed within the staleness window
+)
+
+// ExecutionQueueStore durable queue of parsed decisions awaiting execution
+type ExecutionQueueStore struct {
+	db *sql.DB
+}
+
+// ExecutionItem one queued decision
+type ExecutionItem struct {
+	ID          int64     `json:"id"`
+	TraderID    string    `json:"trader_id"`
+	CycleNumber int       `json:"cycle_number"`
+	Seq         int       `json:"seq"` // Execution order within the cycle
+	Symbol      string    `json:"symbol"`
+	Action      string    `json:"action"`
+	Payload     string    `json:"payload"` // Decision JSON
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// initTables initializes execution queue tables
+func (s *ExecutionQueueStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS execution_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			cycle_number INTEGER DEFAULT 0,
+			seq INTEGER DEFAULT 0,
+			symbol TEXT NOT NULL,
+			action TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER DEFAULT 0,
+			last_error TEXT DEFAULT '',
+			enqueued_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_execution_queue_trader ON execution_queue(trader_id, status, id)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Enqueue adds the decisions of one cycle in execution order (IDs are filled in)
+func (s *ExecutionQueueStore) Enqueue(items []*ExecutionItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	now := time.Now().UTC()
+	for i, item := range items {
+		item.Seq = i
+		item.Status = ExecutionPending
+		item.EnqueuedAt = now
+		item.UpdatedAt = now
+		result, err := tx.Exec(`
+			INSERT INTO execution_queue (trader_id, cycle_number, seq, symbol, action, payload, status, enqueued_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, item.TraderID, item.CycleNumber, item.Seq, item.Symbol, item.Action, item.Payload, item.Status,
+			now.Format(time.RFC3339), now.Format(time.RFC3339))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to enqueue %s %s: %w", item.Symbol, item.Action, err)
+		}
+		item.ID, _ = result.LastInsertId()
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ClaimNext marks the oldest pending item of a trader as running (nil if queue is empty)
+func (s *ExecutionQueueStore) ClaimNext(traderID string) (*ExecutionItem, error) {
+	item, err := s.scanItem(s.db.QueryRow(`
+		SELECT id, trader_id, cycle_number, seq, symbol, action, payload, status, attempts,
+			COALESCE(last_error, ''), enqueued_at, updated_at
+		FROM execution_queue WHERE trader_id = ? AND status = ?
+		ORDER BY id LIMIT 1
+	`, traderID, ExecutionPending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim execution item: %w", err)
+	}
+
+	item.Status = ExecutionRunning
+	item.Attempts++
+	item.UpdatedAt = time.Now().UTC()
+	_, err = s.db.Exec(`UPDATE execution_queue SET status = ?, attempts = ?, updated_at = ? WHERE id = ?`,
+		item.Status, item.Attempts, item.UpdatedAt.Format(time.RFC3339), item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim execution item: %w", err)
+	}
+	return item, nil
+}
+
+// Complete marks an item as executed
+func (s *ExecutionQueueStore) Complete(id int64) error {
+	return s.setStatus(id, ExecutionDone, "")
+}
+
+// Fail marks an item as failed for good
+func (s *ExecutionQueueStore) Fail(id int64, errMsg string) error {
+	return s.setStatus(id, ExecutionFailed, errMsg)
+}
+
+// Retry returns an item to pending after a transient failure (it keeps its place in the queue)
+func (s *ExecutionQueueStore) Retry(id int64, errMsg string) error {
+	return s.setStatus(id, ExecutionPending, errMsg)
+}
+
+// Recover prepares a trader's queue after a restart: items interrupted while running
+// go back to pending, pending items enqueued before staleBefore expire.
+// Returns the number of expired items.
+func (s *ExecutionQueueStore) Recover(traderID string, staleBefore time.Time) (int, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(`UPDATE execution_queue SET status = ?, updated_at = ? WHERE trader_id = ? AND status = ?`,
+		ExecutionPending, now, traderID, ExecutionRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recover running items: %w", err)
+	}
+	result, err := s.db.Exec(`
+		UPDATE execution_queue SET status = ?, last_error = 'stale: not executed before restart', updated_at = ?
+		WHERE trader_id = ? AND status = ? AND enqueued_at < ?
+	`, ExecutionExpired, now, traderID, ExecutionPending, staleBefore.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire stale items: %w", err)
+	}
+	expired, _ := result.RowsAffected()
+	return int(expired), nil
+}
+
+// ExpirePending expires all pending items of a trader (e.g. superseded by a new cycle)
+func (s *ExecutionQueueStore) ExpirePending(traderID, reason string) (int, error) {
+	result, err := s.db.Exec(`UPDATE execution_queue SET status = ?, last_error = ?, updated_at = ? WHERE trader_id = ? AND status = ?`,
+		ExecutionExpired, reason, time.Now().UTC().Format(time.RFC3339), traderID, ExecutionPending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire pending items: %w", err)
+	}
+	expired, _ := result.RowsAffected()
+	return int(expired), nil
+}
+
+// CountPending number of pending items of a trader
+func (s *ExecutionQueueStore) CountPending(traderID string) (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM execution_queue WHERE trader_id = ? AND status = ?`,
+		traderID, ExecutionPending).Scan(&count)
+	return count, err
+}
+
+// Purge deletes finished items (done/failed/expired) last updated before cutoff
+func (s *ExecutionQueueStore) Purge(cutoff time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM execution_queue WHERE status IN (?, ?, ?) AND updated_at < ?`,
+		ExecutionDone, ExecutionFailed, ExecutionExpired, cutoff.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *ExecutionQueueStore) setStatus(id int64, status, errMsg string) error {
+	_, err := s.db.Exec(`UPDATE execution_queue SET status = ?, last_error = ?, updated_at = ? WHERE id = ?`,
+		status, errMsg, time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to update execution item %d: %w", id, err)
+	}
+	return nil
+}
+
+func (s *ExecutionQueueStore) scanItem(row *sql.Row) (*ExecutionItem, error) {
+	var item ExecutionItem
+	var enqueuedAt, updatedAt string
+	err := row.Scan(&item.ID, &item.TraderID, &item.CycleNumber, &item.Seq, &item.Symbol, &item.Action,
+		&item.Payload, &item.Status, &item.Attempts, &item.LastError, &enqueuedAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	item.EnqueuedAt, _ = time.Parse(time.RFC3339, enqueuedAt)
+	item.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &item, nil
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Order types of a recorded fill
+const (
+	FillTypeMarket        = "market"         // Market order
+	FillTypeSmartLimit    = "smart_limit"    // Smart limit order filled at its limit
+	FillTypeSmartFallback = "smart_fallback" // Smart limit order unfilled, completed at market
+	FillTypeTWAP          = "twap"           // TWAP/iceberg child orders (average fill)
+)
+
+// FillStore fill quality of executed orders (slippage vs decision-time price)
+type FillStore struct {
+	db *sql.DB
+}
+
+// OrderFill one executed order and its slippage
+// Slippage is positive when the fill is worse than the reference: buying above it or selling below it.
+type OrderFill struct {
+	ID             int64     `json:"id"`
+	TraderID       string    `json:"trader_id"`
+	ExchangeType   string    `json:"exchange_type"`
+	Symbol         string    `json:"symbol"`
+	Action         string    `json:"action"` // open_long/open_short/close_long/close_short
+	OrderID        string    `json:"order_id"`
+	OrderType      string    `json:"order_type"`      // FillType*
+	ReferencePrice float64   `json:"reference_price"` // Market price when the order was decided
+	LimitPrice     float64   `json:"limit_price"`     // Smart limit target (0 = none)
+	FillPrice      float64   `json:"fill_price"`
+	Quantity       float64   `json:"quantity"`
+	SlippageBps    float64   `json:"slippage_bps"` // vs reference price
+	SlippageUSD    float64   `json:"slippage_usd"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SlippageStats aggregated fill quality of one exchange/symbol
+type SlippageStats struct {
+	ExchangeType     string  `json:"exchange_type"`
+	Symbol           string  `json:"symbol"`
+	Fills            int     `json:"fills"`
+	AvgSlippageBps   float64 `json:"avg_slippage_bps"`
+	WorstSlippageBps float64 `json:"worst_slippage_bps"`
+	SlippageUSD      float64 `json:"slippage_usd"`      // Total cost (negative = price improvement)
+	SmartOrders      int     `json:"smart_orders"`      // Smart limit attempts (filled or fallen back to market)
+	SmartLimitFills  int     `json:"smart_limit_fills"` // Smart limit orders filled at their limit
+}
+
+// LimitFillRate share of smart limit orders filled at their limit (0 if none)
+func (s SlippageStats) LimitFillRate() float64 {
+	if s.SmartOrders == 0 {
+		return 0
+	}
+	return float64(s.SmartLimitFills) / float64(s.SmartOrders)
+}
+
+// initTables initializes fill quality tables
+func (s *FillStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS order_fills (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			exchange_type TEXT NOT NULL DEFAULT '',
+			symbol TEXT NOT NULL,
+			action TEXT NOT NULL,
+			order_id TEXT DEFAULT '',
+			order_type TEXT NOT NULL,
+			reference_price REAL DEFAULT 0,
+			limit_price REAL DEFAULT 0,
+			fill_price REAL NOT NULL,
+			quantity REAL NOT NULL,
+			slippage_bps REAL DEFAULT 0,
+			slippage_usd REAL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_fills_trader_time ON order_fills(trader_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_order_fills_exchange_symbol ON order_fills(exchange_type, symbol, order_type)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create saves a fill
+func (s *FillStore) Create(fill *OrderFill) error {
+	if fill.CreatedAt.IsZero() {
+		fill.CreatedAt = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO order_fills (trader_id, exchange_type, symbol, action, order_id, order_type,
+			reference_price, limit_price, fill_price, quantity, slippage_bps, slippage_usd, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, fill.TraderID, fill.ExchangeType, fill.Symbol, fill.Action, fill.OrderID, fill.OrderType,
+		fill.ReferencePrice, fill.LimitPrice, fill.FillPrice, fill.Quantity, fill.SlippageBps, fill.SlippageUSD,
+		fill.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save order fill: %w", err)
+	}
+	fill.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// Stats gets fill quality of the trader per exchange and symbol (zero since = all time)
+func (s *FillStore) Stats(traderID string, since time.Time) ([]SlippageStats, error) {
+	rows, err := s.db.Query(`
+		SELECT exchange_type, symbol, COUNT(*), AVG(slippage_bps), MAX(slippage_bps), SUM(slippage_usd),
+			SUM(CASE WHEN order_type IN (?, ?) THEN 1 ELSE 0 END),
+			SUM(CASE WHEN order_type = ? THEN 1 ELSE 0 END)
+		FROM order_fills
+		WHERE trader_id = ? AND created_at >= ?
+		GROUP BY exchange_type, symbol
+		ORDER BY exchange_type, symbol
+	`, FillTypeSmartLimit, FillTypeSmartFallback, FillTypeSmartLimit, traderID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fill quality: %w", err)
+	}
+	defer rows.Close()
+
+	var result []SlippageStats
+	for rows.Next() {
+		var stats SlippageStats
+		if err := rows.Scan(&stats.ExchangeType, &stats.Symbol, &stats.Fills, &stats.AvgSlippageBps,
+			&stats.WorstSlippageBps, &stats.SlippageUSD, &stats.SmartOrders, &stats.SmartLimitFills); err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+	return result, rows.Err()
+}
+
+// RecentSmartOrderStats fill quality of the most recent smart limit orders (at most limit) on exchange/symbol, across all traders
+func (s *FillStore) RecentSmartOrderStats(exchangeType, symbol string, limit int) (SlippageStats, error) {
+	stats := SlippageStats{ExchangeType: exchangeType, Symbol: symbol}
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(AVG(slippage_bps), 0), COALESCE(MAX(slippage_bps), 0), COALESCE(SUM(slippage_usd), 0),
+			COALESCE(SUM(CASE WHEN order_type = ? THEN 1 ELSE 0 END), 0)
+		FROM (
+			SELECT order_type, slippage_bps, slippage_usd FROM order_fills
+			WHERE exchange_type = ? AND symbol = ? AND order_type IN (?, ?)
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		)
+	`, FillTypeSmartLimit, exchangeType, symbol, FillTypeSmartLimit, FillTypeSmartFallback, limit).Scan(
+		&stats.Fills, &stats.AvgSlippageBps, &stats.WorstSlippageBps, &stats.SlippageUSD, &stats.SmartLimitFills)
+	if err != nil {
+		return stats, fmt.Errorf("failed to query smart order fills: %w", err)
+	}
+	stats.SmartOrders = stats.Fills
+	return stats, nil
+}
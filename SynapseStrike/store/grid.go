@@ -0,0 +1,176 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Grid leg status
+const (
+	GridLegPending  = "pending"  // Level order resting on the exchange
+	GridLegOpen     = "open"     // Level order filled, waiting for the leg's take profit
+	GridLegClosed   = "closed"   // Take profit hit, P&L realized
+	GridLegCanceled = "canceled" // Level order cancelled unfilled
+)
+
+// GridStore legs of the grid/DCA tactic
+// Grid P&L is kept here, apart from the trader's position history.
+type GridStore struct {
+	db *sql.DB
+}
+
+// GridLeg one level order of a grid and its take profit
+type GridLeg struct {
+	ID              int64     `json:"id"`
+	TraderID        string    `json:"trader_id"`
+	Symbol          string    `json:"symbol"`
+	Level           int       `json:"level"`       // Level index, 0 = lowest
+	LevelPrice      float64   `json:"level_price"` // Limit price of the level order
+	Quantity        float64   `json:"quantity"`    // Ordered quantity, filled quantity once open
+	Status          string    `json:"status"`
+	EntryOrderID    string    `json:"entry_order_id"`
+	FillPrice       float64   `json:"fill_price"`
+	TakeProfitPrice float64   `json:"take_profit_price"`
+	TPOrderID       string    `json:"tp_order_id,omitempty"` // Empty when the take profit is watched each cycle
+	ExitPrice       float64   `json:"exit_price"`
+	RealizedPnL     float64   `json:"realized_pnl"` // Before fees
+	CreatedAt       time.Time `json:"created_at"`
+	FilledAt        time.Time `json:"filled_at"`
+	ClosedAt        time.Time `json:"closed_at"`
+}
+
+// GridSymbolPnL realized grid P&L of one symbol
+type GridSymbolPnL struct {
+	Symbol      string  `json:"symbol"`
+	ClosedLegs  int     `json:"closed_legs"`
+	RealizedPnL float64 `json:"realized_pnl"`
+}
+
+// initTables initializes grid tables
+func (s *GridStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS grid_legs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			level INTEGER NOT NULL,
+			level_price REAL NOT NULL,
+			quantity REAL NOT NULL,
+			status TEXT NOT NULL,
+			entry_order_id TEXT DEFAULT '',
+			fill_price REAL DEFAULT 0,
+			take_profit_price REAL DEFAULT 0,
+			tp_order_id TEXT DEFAULT '',
+			exit_price REAL DEFAULT 0,
+			realized_pnl REAL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			filled_at DATETIME,
+			closed_at DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_grid_legs_trader_status ON grid_legs(trader_id, status)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create saves a new leg
+func (s *GridStore) Create(leg *GridLeg) error {
+	if leg.CreatedAt.IsZero() {
+		leg.CreatedAt = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO grid_legs (trader_id, symbol, level, level_price, quantity, status, entry_order_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, leg.TraderID, leg.Symbol, leg.Level, leg.LevelPrice, leg.Quantity, leg.Status, leg.EntryOrderID,
+		leg.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save grid leg: %w", err)
+	}
+	leg.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// Update saves status, fill, take profit and exit of a leg
+func (s *GridStore) Update(leg *GridLeg) error {
+	_, err := s.db.Exec(`
+		UPDATE grid_legs SET quantity = ?, status = ?, fill_price = ?, take_profit_price = ?, tp_order_id = ?,
+			exit_price = ?, realized_pnl = ?, filled_at = ?, closed_at = ?
+		WHERE id = ?
+	`, leg.Quantity, leg.Status, leg.FillPrice, leg.TakeProfitPrice, leg.TPOrderID,
+		leg.ExitPrice, leg.RealizedPnL, formatGridTime(leg.FilledAt), formatGridTime(leg.ClosedAt), leg.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update grid leg %d: %w", leg.ID, err)
+	}
+	return nil
+}
+
+// ListActive gets pending and open legs of the trader, by symbol and level
+func (s *GridStore) ListActive(traderID string) ([]*GridLeg, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, symbol, level, level_price, quantity, status, COALESCE(entry_order_id, ''),
+			fill_price, take_profit_price, COALESCE(tp_order_id, ''), exit_price, realized_pnl,
+			created_at, filled_at, closed_at
+		FROM grid_legs
+		WHERE trader_id = ? AND status IN (?, ?)
+		ORDER BY symbol, level
+	`, traderID, GridLegPending, GridLegOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query grid legs: %w", err)
+	}
+	defer rows.Close()
+
+	var legs []*GridLeg
+	for rows.Next() {
+		leg := &GridLeg{}
+		var createdAt string
+		var filledAt, closedAt sql.NullString
+		if err := rows.Scan(&leg.ID, &leg.TraderID, &leg.Symbol, &leg.Level, &leg.LevelPrice, &leg.Quantity,
+			&leg.Status, &leg.EntryOrderID, &leg.FillPrice, &leg.TakeProfitPrice, &leg.TPOrderID,
+			&leg.ExitPrice, &leg.RealizedPnL, &createdAt, &filledAt, &closedAt); err != nil {
+			return nil, err
+		}
+		leg.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		leg.FilledAt = parseStateTime(filledAt)
+		leg.ClosedAt = parseStateTime(closedAt)
+		legs = append(legs, leg)
+	}
+	return legs, rows.Err()
+}
+
+// RealizedPnL gets realized grid P&L of the trader per symbol
+func (s *GridStore) RealizedPnL(traderID string) ([]GridSymbolPnL, error) {
+	rows, err := s.db.Query(`
+		SELECT symbol, COUNT(*), COALESCE(SUM(realized_pnl), 0)
+		FROM grid_legs
+		WHERE trader_id = ? AND status = ?
+		GROUP BY symbol
+		ORDER BY symbol
+	`, traderID, GridLegClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query grid P&L: %w", err)
+	}
+	defer rows.Close()
+
+	var result []GridSymbolPnL
+	for rows.Next() {
+		var pnl GridSymbolPnL
+		if err := rows.Scan(&pnl.Symbol, &pnl.ClosedLegs, &pnl.RealizedPnL); err != nil {
+			return nil, err
+		}
+		result = append(result, pnl)
+	}
+	return result, rows.Err()
+}
+
+// formatGridTime formats an optional leg time (NULL when zero)
+func formatGridTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MemoryStore decision memory storage (embedded past setups + outcomes)
+type MemoryStore struct {
+	db *sql.DB
+}
+
+// DecisionMemory past trading situation with its outcome
+type DecisionMemory struct {
+	ID             int64     `json:"id"`
+	TraderID       string    `json:"trader_id"`
+	PositionID     int64     `json:"position_id"` // trader_positions.id opened by this decision (0 = none)
+	Symbol         string    `json:"symbol"`
+	Action         string    `json:"action"`
+	Situation      string    `json:"situation"` // Market situation text that was embedded
+	Reasoning      string    `json:"reasoning"` // AI reasoning excerpt
+	Embedding      []float32 `json:"-"`
+	EmbeddingModel string    `json:"embedding_model"`
+	Resolved       bool      `json:"resolved"`
+	RealizedPnL    float64   `json:"realized_pnl"`
+	PnLPct         float64   `json:"pnl_pct"`
+	CloseReason    string    `json:"close_reason"`
+	CreatedAt      time.Time `json:"created_at"`
+	ResolvedAt     time.Time `json:"resolved_at,omitempty"`
+
+	Similarity float64 `json:"similarity,omitempty"` // Filled in by similarity search, not persisted
+}
+
+// initTables initializes decision memory tables
+func (s *MemoryStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS decision_memories (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			position_id INTEGER DEFAULT 0,
+			symbol TEXT NOT NULL,
+			action TEXT NOT NULL,
+			situation TEXT NOT NULL,
+			reasoning TEXT DEFAULT '',
+			embedding BLOB NOT NULL,
+			embedding_model TEXT NOT NULL,
+			resolved BOOLEAN DEFAULT 0,
+			realized_pnl REAL DEFAULT 0,
+			pnl_pct REAL DEFAULT 0,
+			close_reason TEXT DEFAULT '',
+			created_at DATETIME NOT NULL,
+			resolved_at DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_trader ON decision_memories(trader_id, resolved, embedding_model)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_position ON decision_memories(position_id)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Save saves decision memory
+func (s *MemoryStore) Save(m *DecisionMemory) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO decision_memories (
+			trader_id, position_id, symbol, action, situation, reasoning,
+			embedding, embedding_model, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		m.TraderID, m.PositionID, m.Symbol, m.Action, m.Situation, m.Reasoning,
+		encodeEmbedding(m.Embedding), m.EmbeddingModel, m.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save decision memory: %w", err)
+	}
+	m.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ResolveClosed attaches outcomes of closed positions to unresolved memories
+// Returns the memories resolved (with outcome, without embedding).
+func (s *MemoryStore) ResolveClosed(traderID string) ([]*DecisionMemory, error) {
+	rows, err := s.db.Query(`
+		SELECT m.id, m.trader_id, m.position_id, m.symbol, m.action, m.situation, COALESCE(m.reasoning, ''), m.embedding_model,
+			p.realized_pnl, p.entry_price, p.quantity, p.leverage, COALESCE(p.close_reason, '')
+		FROM decision_memories m
+		JOIN trader_positions p ON p.id = m.position_id
+		WHERE m.trader_id = ? AND m.resolved = 0 AND m.position_id > 0 AND p.status = 'CLOSED'
+	`, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unresolved memories: %w", err)
+	}
+
+	var memories []*DecisionMemory
+	for rows.Next() {
+		var m DecisionMemory
+		var entryPrice, quantity float64
+		var leverage int
+		if err := rows.Scan(&m.ID, &m.TraderID, &m.PositionID, &m.Symbol, &m.Action, &m.Situation, &m.Reasoning, &m.EmbeddingModel,
+			&m.RealizedPnL, &entryPrice, &quantity, &leverage, &m.CloseReason); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if leverage <= 0 {
+			leverage = 1
+		}
+		// P&L percentage relative to margin used
+		if margin := entryPrice * quantity / float64(leverage); margin > 0 {
+			m.PnLPct = m.RealizedPnL / margin * 100
+		}
+		m.Resolved = true
+		memories = append(memories, &m)
+	}
+	rows.Close()
+
+	now := time.Now().UTC()
+	for _, m := range memories {
+		if _, err := s.db.Exec(`
+			UPDATE decision_memories SET resolved = 1, realized_pnl = ?, pnl_pct = ?, close_reason = ?, resolved_at = ?
+			WHERE id = ?
+		`, m.RealizedPnL, m.PnLPct, m.CloseReason, now.Format(time.RFC3339), m.ID); err != nil {
+			return nil, fmt.Errorf("failed to resolve memory: %w", err)
+		}
+		m.ResolvedAt = now
+	}
+	return memories, nil
+}
+
+// SetEmbedding replaces the embedding of a memory
+func (s *MemoryStore) SetEmbedding(id int64, embedding []float32, embeddingModel string) error {
+	_, err := s.db.Exec(`UPDATE decision_memories SET embedding = ?, embedding_model = ? WHERE id = ?`,
+		encodeEmbedding(embedding), embeddingModel, id)
+	if err != nil {
+		return fmt.Errorf("failed to update memory embedding: %w", err)
+	}
+	return nil
+}
+
+// ListResolved gets resolved memories with embeddings from the given model (newest first)
+func (s *MemoryStore) ListResolved(traderID, embeddingModel string, limit int) ([]*DecisionMemory, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, position_id, symbol, action, situation, reasoning, embedding, embedding_model,
+			resolved, realized_pnl, pnl_pct, close_reason, created_at, resolved_at
+		FROM decision_memories
+		WHERE trader_id = ? AND embedding_model = ? AND resolved = 1
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, traderID, embeddingModel, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []*DecisionMemory
+	for rows.Next() {
+		var m DecisionMemory
+		var embedding []byte
+		var createdAt, resolvedAt sql.NullString
+		if err := rows.Scan(&m.ID, &m.TraderID, &m.PositionID, &m.Symbol, &m.Action, &m.Situation, &m.Reasoning,
+			&embedding, &m.EmbeddingModel, &m.Resolved, &m.RealizedPnL, &m.PnLPct, &m.CloseReason,
+			&createdAt, &resolvedAt); err != nil {
+			return nil, err
+		}
+		m.Embedding = decodeEmbedding(embedding)
+		if createdAt.Valid {
+			m.CreatedAt, _ = time.Parse(time.RFC3339, createdAt.String)
+		}
+		if resolvedAt.Valid {
+			m.ResolvedAt, _ = time.Parse(time.RFC3339, resolvedAt.String)
+		}
+		memories = append(memories, &m)
+	}
+	return memories, rows.Err()
+}
+
+// DeleteByTrader deletes all memories of a trader
+func (s *MemoryStore) DeleteByTrader(traderID string) error {
+	_, err := s.db.Exec(`DELETE FROM decision_memories WHERE trader_id = ?`, traderID)
+	return err
+}
+
+// encodeEmbedding serializes vector as little-endian float32 blob
+func encodeEmbedding(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+// decodeEmbedding deserializes little-endian float32 blob
+func decodeEmbedding(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return v
+}
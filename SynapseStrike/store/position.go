@@ -2,8 +2,11 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,8 +19,11 @@ type TraderStats struct {
 	WinRate        float64 `json:"win_rate"`         // Win rate (%)
 	ProfitFactor   float64 `json:"profit_factor"`    // Profit factor
 	SharpeRatio    float64 `json:"sharpe_ratio"`     // Sharpe ratio
-	TotalPnL       float64 `json:"total_pnl"`        // Total PnL
+	TotalPnL       float64 `json:"total_pnl"`        // Total PnL (gross, same as GrossPnL)
+	GrossPnL       float64 `json:"gross_pnl"`        // PnL from prices only (before fees)
+	NetPnL         float64 `json:"net_pnl"`          // GrossPnL - TotalFee
 	TotalFee       float64 `json:"total_fee"`        // Total fees
+	TotalSlippage  float64 `json:"total_slippage"`   // Total slippage cost vs expected prices (positive = cost, already in GrossPnL)
 	AvgWin         float64 `json:"avg_win"`          // Average win
 	AvgLoss        float64 `json:"avg_loss"`         // Average loss
 	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Max drawdown (%)
@@ -31,27 +37,31 @@ type TraderPosition struct {
 	ExchangeType       string     `json:"exchange_type"`        // Exchange type: binance/bybit/okx/hyperliquid/aster/lighter
 	ExchangePositionID string     `json:"exchange_position_id"` // Exchange-specific unique position ID for deduplication
 	Symbol             string     `json:"symbol"`
-	Side               string     `json:"side"`           // LONG/SHORT
-	Quantity           float64    `json:"quantity"`       // Opening quantity
-	EntryPrice         float64    `json:"entry_price"`    // Entry price
-	EntryOrderID       string     `json:"entry_order_id"` // Entry order ID
-	EntryTime          time.Time  `json:"entry_time"`     // Entry time
-	ExitPrice          float64    `json:"exit_price"`     // Exit price
-	ExitOrderID        string     `json:"exit_order_id"`  // Exit order ID
-	ExitTime           *time.Time `json:"exit_time"`      // Exit time
-	RealizedPnL        float64    `json:"realized_pnl"`   // Realized profit and loss
-	Fee                float64    `json:"fee"`            // Fee
-	Leverage           int        `json:"leverage"`       // Leverage multiplier
-	Status             string     `json:"status"`         // OPEN/CLOSED
-	CloseReason        string     `json:"close_reason"`   // Close reason: ai_decision/manual/stop_loss/take_profit
-	Source             string     `json:"source"`         // Source: system/manual/sync
+	Side               string     `json:"side"`                           // LONG/SHORT
+	Quantity           float64    `json:"quantity"`                       // Opening quantity
+	EntryPrice         float64    `json:"entry_price"`                    // Entry price
+	EntryOrderID       string     `json:"entry_order_id"`                 // Entry order ID
+	EntryTime          time.Time  `json:"entry_time"`                     // Entry time
+	ExitPrice          float64    `json:"exit_price"`                     // Exit price
+	ExitOrderID        string     `json:"exit_order_id"`                  // Exit order ID
+	ExitTime           *time.Time `json:"exit_time"`                      // Exit time
+	RealizedPnL        float64    `json:"realized_pnl"`                   // Realized profit and loss
+	Fee                float64    `json:"fee"`                            // Fee
+	Leverage           int        `json:"leverage"`                       // Leverage multiplier
+	ExpectedEntryPrice float64    `json:"expected_entry_price,omitempty"` // Market price when entry was decided (0 = unknown)
+	ExpectedExitPrice  float64    `json:"expected_exit_price,omitempty"`  // Market price when exit was decided (0 = unknown)
+	Slippage           float64    `json:"slippage"`                       // Entry + exit slippage cost in USD (positive = worse than expected)
+	Status             string     `json:"status"`                         // OPEN/CLOSED
+	CloseReason        string     `json:"close_reason"`                   // Close reason: ai_decision/manual/stop_loss/take_profit
+	Source             string     `json:"source"`                         // Source: system/manual/sync/import
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
 // PositionStore position storage
 type PositionStore struct {
-	db *sql.DB
+	db     *sql.DB
+	userID string // Owner reads are limited to (see ForUser), empty = all users
 }
 
 // NewPositionStore creates position storage instance
@@ -59,6 +69,11 @@ func NewPositionStore(db *sql.DB) *PositionStore {
 	return &PositionStore{db: db}
 }
 
+// ForUser returns a copy of the store whose reads only return userID's positions
+func (s *PositionStore) ForUser(userID string) *PositionStore {
+	return &PositionStore{db: s.db, userID: userID}
+}
+
 // InitTables initializes position tables
 func (s *PositionStore) InitTables() error {
 	_, err := s.db.Exec(`
@@ -99,6 +114,30 @@ func (s *PositionStore) InitTables() error {
 	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN exchange_position_id TEXT NOT NULL DEFAULT ''`)
 	// Migration: add source field (system/manual/sync)
 	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN source TEXT DEFAULT 'system'`)
+	// Migration: add expected prices and slippage (fill quality tracking)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN expected_entry_price REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN expected_exit_price REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN slippage REAL DEFAULT 0`)
+	// Migration: add overnight hold tag (end-of-day policy)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN overnight_date TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN overnight_close_price REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN overnight_stop REAL DEFAULT 0`)
+	// Migration: add exchange order IDs of the SL/TP orders protecting the position
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN sl_order_id TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN tp_order_id TEXT DEFAULT ''`)
+	// Migration: add reduce-only orders of scaled take profit levels (JSON)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN tp_levels TEXT DEFAULT ''`)
+	// Migration: add indicator snapshot the AI saw at entry (JSON, decision explainability)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN entry_indicators TEXT DEFAULT ''`)
+	// Migration: add maximum favorable/adverse excursion in % of entry price (see trade_excursion.go)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN mfe_pct REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN mae_pct REAL DEFAULT 0`)
+	// Migration: add SL/TP prices and peak P&L for resuming management after a restart (see position_recovery.go)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN stop_loss REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN take_profit REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN peak_pnl_pct REAL`)
+	// Migration: first stop loss of the position (R multiples, see rolling_stats.go)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN initial_stop_loss REAL DEFAULT 0`)
 
 	// Create indexes (after migration)
 	indices := []string{
@@ -134,11 +173,12 @@ func (s *PositionStore) Create(pos *TraderPosition) error {
 	result, err := s.db.Exec(`
 		INSERT INTO trader_positions (
 			trader_id, exchange_id, exchange_type, symbol, side, quantity, entry_price, entry_order_id,
-			entry_time, leverage, status, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			entry_time, leverage, fee, expected_entry_price, slippage, status, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		pos.TraderID, pos.ExchangeID, pos.ExchangeType, pos.Symbol, pos.Side, pos.Quantity, pos.EntryPrice,
 		pos.EntryOrderID, pos.EntryTime.Format(time.RFC3339), pos.Leverage,
+		pos.Fee, pos.ExpectedEntryPrice, pos.Slippage,
 		pos.Status, now.Format(time.RFC3339), now.Format(time.RFC3339),
 	)
 	if err != nil {
@@ -169,6 +209,250 @@ func (s *PositionStore) ClosePosition(id int64, exitPrice float64, exitOrderID s
 	return nil
 }
 
+// ClosePositionWithCosts closes position, adding exit fee and slippage to those recorded at entry
+// realizedPnL is gross (price difference × quantity); fees are kept separately.
+func (s *PositionStore) ClosePositionWithCosts(id int64, exitPrice, expectedExitPrice float64, exitOrderID string, realizedPnL, exitFee, exitSlippage float64, closeReason string) error {
+	now := time.Now()
+	_, err := s.db.Exec(`
+		UPDATE trader_positions SET
+			exit_price = ?, expected_exit_price = ?, exit_order_id = ?, exit_time = ?,
+			realized_pnl = ?, fee = COALESCE(fee, 0) + ?, slippage = COALESCE(slippage, 0) + ?,
+			status = 'CLOSED', close_reason = ?, updated_at = ?
+		WHERE id = ?
+	`,
+		exitPrice, expectedExitPrice, exitOrderID, now.Format(time.RFC3339),
+		realizedPnL, exitFee, exitSlippage, closeReason, now.Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update position record: %w", err)
+	}
+	return nil
+}
+
+// PartialClose splits quantity off an open position into a closed record
+// The open record keeps the remaining quantity; fees recorded at entry stay on it.
+func (s *PositionStore) PartialClose(id int64, quantity, exitPrice float64, exitOrderID string, realizedPnL, fee float64, closeReason string) error {
+	now := time.Now().Format(time.RFC3339)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO trader_positions (
+			trader_id, exchange_id, exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, leverage, expected_entry_price, exit_price, exit_order_id, exit_time,
+			realized_pnl, fee, status, close_reason, source, entry_indicators, created_at, updated_at
+		)
+		SELECT trader_id, exchange_id, exchange_type, symbol, side, ?, entry_price, entry_order_id,
+			entry_time, leverage, expected_entry_price, ?, ?, ?,
+			?, ?, 'CLOSED', ?, source, entry_indicators, created_at, ?
+		FROM trader_positions
+		WHERE id = ? AND status = 'OPEN' AND quantity > ?
+	`, quantity, exitPrice, exitOrderID, now, realizedPnL, fee, closeReason, now, id, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to create partial close record: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("position %d is not open or quantity %.8f is not below its size", id, quantity)
+	}
+	if _, err := tx.Exec(`UPDATE trader_positions SET quantity = quantity - ?, updated_at = ? WHERE id = ?`, quantity, now, id); err != nil {
+		return fmt.Errorf("failed to update position quantity: %w", err)
+	}
+	return tx.Commit()
+}
+
+// OvernightTag end-of-day tag of a position held overnight
+type OvernightTag struct {
+	Date       string  `json:"date"`        // Trading day (YYYY-MM-DD, ET) the position was held past the close
+	ClosePrice float64 `json:"close_price"` // Price when the end-of-day policy ran
+	StopPrice  float64 `json:"stop_price"`  // Hard stop placed for the overnight hold (0 = none)
+}
+
+// TagOvernight marks an open position as held overnight
+func (s *PositionStore) TagOvernight(id int64, tag OvernightTag) error {
+	_, err := s.db.Exec(`
+		UPDATE trader_positions SET overnight_date = ?, overnight_close_price = ?, overnight_stop = ?, updated_at = ?
+		WHERE id = ?
+	`, tag.Date, tag.ClosePrice, tag.StopPrice, time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to tag overnight position: %w", err)
+	}
+	return nil
+}
+
+// GetOvernightTag gets overnight tag of an open position (nil if not held overnight)
+func (s *PositionStore) GetOvernightTag(traderID, symbol, side string) (*OvernightTag, error) {
+	var tag OvernightTag
+	err := s.db.QueryRow(`
+		SELECT COALESCE(overnight_date, ''), COALESCE(overnight_close_price, 0), COALESCE(overnight_stop, 0)
+		FROM trader_positions
+		WHERE trader_id = ? AND symbol = ? AND UPPER(side) = UPPER(?) AND status = 'OPEN'
+		ORDER BY entry_time DESC LIMIT 1
+	`, traderID, symbol, side).Scan(&tag.Date, &tag.ClosePrice, &tag.StopPrice)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if tag.Date == "" {
+		return nil, nil
+	}
+	return &tag, nil
+}
+
+// ProtectiveOrders exchange order IDs of the SL/TP orders protecting an open position
+type ProtectiveOrders struct {
+	PositionID        int64                  `json:"position_id"`
+	StopLossOrderID   string                 `json:"sl_order_id"`         // Empty = no tracked stop-loss order
+	TakeProfitOrderID string                 `json:"tp_order_id"`         // Empty = no tracked take-profit order (final level of a scaled take profit)
+	TakeProfitLevels  []TakeProfitLevelOrder `json:"tp_levels,omitempty"` // Reduce-only orders of the levels before the final one
+}
+
+// TakeProfitLevelOrder reduce-only order of one scaled take profit level
+type TakeProfitLevelOrder struct {
+	Price         float64 `json:"price"`
+	PctOfPosition float64 `json:"pct_of_position"`
+	Quantity      float64 `json:"quantity"`
+	OrderID       string  `json:"order_id"`
+	Filled        bool    `json:"filled,omitempty"` // Fill recorded as a partial close of the position
+}
+
+// SetProtectiveOrders records the SL/TP order IDs of a position
+func (s *PositionStore) SetProtectiveOrders(orders ProtectiveOrders) error {
+	levels := ""
+	if len(orders.TakeProfitLevels) > 0 {
+		data, err := json.Marshal(orders.TakeProfitLevels)
+		if err != nil {
+			return fmt.Errorf("failed to encode take profit levels: %w", err)
+		}
+		levels = string(data)
+	}
+	_, err := s.db.Exec(`
+		UPDATE trader_positions SET sl_order_id = ?, tp_order_id = ?, tp_levels = ?, updated_at = ?
+		WHERE id = ?
+	`, orders.StopLossOrderID, orders.TakeProfitOrderID, levels, time.Now().Format(time.RFC3339), orders.PositionID)
+	if err != nil {
+		return fmt.Errorf("failed to update protective orders: %w", err)
+	}
+	return nil
+}
+
+// GetProtectiveOrders gets the SL/TP order IDs of a position
+func (s *PositionStore) GetProtectiveOrders(id int64) (*ProtectiveOrders, error) {
+	orders := ProtectiveOrders{PositionID: id}
+	var levels string
+	err := s.db.QueryRow(`
+		SELECT COALESCE(sl_order_id, ''), COALESCE(tp_order_id, ''), COALESCE(tp_levels, '')
+		FROM trader_positions WHERE id = ?
+	`, id).Scan(&orders.StopLossOrderID, &orders.TakeProfitOrderID, &levels)
+	if err != nil {
+		return nil, err
+	}
+	if levels != "" {
+		if err := json.Unmarshal([]byte(levels), &orders.TakeProfitLevels); err != nil {
+			return nil, fmt.Errorf("failed to decode take profit levels: %w", err)
+		}
+	}
+	return &orders, nil
+}
+
+// EntryIndicators indicator values the AI saw when it decided to open a position
+type EntryIndicators struct {
+	DecisionTime    time.Time `json:"decision_time"`
+	Price           float64   `json:"price"`
+	RSI7            float64   `json:"rsi7"`
+	RSI14           float64   `json:"rsi14"`
+	MACD            float64   `json:"macd"`
+	EMA20           float64   `json:"ema20"`
+	VWAP            float64   `json:"vwap"`
+	VWAPDevPct      float64   `json:"vwap_dev_pct"`               // Price deviation from VWAP (%)
+	Confluence      string    `json:"confluence,omitempty"`       // Dominant multi-timeframe direction (empty = confluence disabled)
+	ConfluenceScore float64   `json:"confluence_score,omitempty"` // Share of timeframes agreeing with Confluence (0-1)
+	OIDeltaPct      float64   `json:"oi_delta_pct"`               // Open interest change (%, OI Top data)
+	VolumeRatio     float64   `json:"volume_ratio"`               // Current / average volume
+}
+
+// SetEntryIndicators stores the entry indicator snapshot of a position
+func (s *PositionStore) SetEntryIndicators(id int64, indicators *EntryIndicators) error {
+	data, err := json.Marshal(indicators)
+	if err != nil {
+		return fmt.Errorf("failed to serialize entry indicators: %w", err)
+	}
+	_, err = s.db.Exec(`
+		UPDATE trader_positions SET entry_indicators = ?, updated_at = ? WHERE id = ?
+	`, string(data), time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to update entry indicators: %w", err)
+	}
+	return nil
+}
+
+// TradeExplanation closed trade joined with the indicators it was opened on
+type TradeExplanation struct {
+	PositionID  int64            `json:"position_id"`
+	Symbol      string           `json:"symbol"`
+	Side        string           `json:"side"` // long/short
+	Quantity    float64          `json:"quantity"`
+	EntryPrice  float64          `json:"entry_price"`
+	ExitPrice   float64          `json:"exit_price"`
+	RealizedPnL float64          `json:"realized_pnl"`
+	PnLPct      float64          `json:"pnl_pct"`
+	CloseReason string           `json:"close_reason"`
+	EntryTime   time.Time        `json:"entry_time"`
+	ExitTime    time.Time        `json:"exit_time"`
+	MFEPct      float64          `json:"mfe_pct"` // Maximum favorable excursion (% of entry price, 0 = not tracked)
+	MAEPct      float64          `json:"mae_pct"` // Maximum adverse excursion (% of entry price, 0 = not tracked)
+	Indicators  *EntryIndicators `json:"indicators"`
+}
+
+// GetTradeExplanations gets recent closed trades that have an entry indicator snapshot
+func (s *PositionStore) GetTradeExplanations(traderID string, limit int) ([]TradeExplanation, error) {
+	rows, err := s.db.Query(`
+		SELECT id, symbol, side, quantity, entry_price, COALESCE(exit_price, 0), COALESCE(realized_pnl, 0),
+			COALESCE(close_reason, ''), entry_time, exit_time, COALESCE(mfe_pct, 0), COALESCE(mae_pct, 0), entry_indicators
+		FROM trader_positions
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED' AND COALESCE(entry_indicators, '') != ''
+		ORDER BY exit_time DESC
+		LIMIT ?
+	`, traderID, s.userID, s.userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade explanations: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []TradeExplanation
+	for rows.Next() {
+		var t TradeExplanation
+		var entryTime, exitTime sql.NullString
+		var indicators string
+		if err := rows.Scan(&t.PositionID, &t.Symbol, &t.Side, &t.Quantity, &t.EntryPrice, &t.ExitPrice,
+			&t.RealizedPnL, &t.CloseReason, &entryTime, &exitTime, &t.MFEPct, &t.MAEPct, &indicators); err != nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(indicators), &t.Indicators); err != nil {
+			continue
+		}
+		t.Side = strings.ToLower(t.Side)
+		if t.EntryPrice > 0 {
+			t.PnLPct = (t.ExitPrice - t.EntryPrice) / t.EntryPrice * 100
+			if t.Side == "short" {
+				t.PnLPct = -t.PnLPct
+			}
+		}
+		if entryTime.Valid {
+			t.EntryTime, _ = time.Parse(time.RFC3339, entryTime.String)
+		}
+		if exitTime.Valid {
+			t.ExitTime, _ = time.Parse(time.RFC3339, exitTime.String)
+		}
+		trades = append(trades, t)
+	}
+	return trades, nil
+}
+
 // GetOpenPositions gets all open positions
 func (s *PositionStore) GetOpenPositions(traderID string) ([]*TraderPosition, error) {
 	rows, err := s.db.Query(`
@@ -176,9 +460,9 @@ func (s *PositionStore) GetOpenPositions(traderID string) ([]*TraderPosition, er
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
 			leverage, status, close_reason, created_at, updated_at
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'OPEN'
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'OPEN'
 		ORDER BY entry_time DESC
-	`, traderID)
+	`, traderID, s.userID, s.userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query open positions: %w", err)
 	}
@@ -223,10 +507,28 @@ func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*Trade
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
 			leverage, status, close_reason, created_at, updated_at
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED'
 		ORDER BY exit_time DESC
 		LIMIT ?
-	`, traderID, limit)
+	`, traderID, s.userID, s.userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed positions: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanPositions(rows)
+}
+
+// GetClosedPositionsSince gets positions closed since the given time (zero = all), oldest first
+func (s *PositionStore) GetClosedPositionsSince(traderID string, since time.Time) ([]*TraderPosition, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
+			leverage, status, close_reason, created_at, updated_at
+		FROM trader_positions
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED' AND COALESCE(exit_time, updated_at) >= ?
+		ORDER BY exit_time ASC
+	`, traderID, s.userID, s.userID, since.UTC().Format(time.RFC3339))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query closed positions: %w", err)
 	}
@@ -235,6 +537,45 @@ func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*Trade
 	return s.scanPositions(rows)
 }
 
+// ImportTrade stores an externally executed trade as a closed position with source "import"
+// Returns false for duplicates: repeated imports of the same trade ID and re-imports of exported own trades.
+// The trade must have passed Validate.
+func (s *PositionStore) ImportTrade(traderID, exchangeID string, trade ExportedTrade) (bool, error) {
+	if idStr, ok := strings.CutPrefix(trade.ID, exportTradeIDPrefix); ok {
+		if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
+			var count int
+			if err := s.db.QueryRow(`SELECT COUNT(*) FROM trader_positions WHERE id = ? AND trader_id = ?`, id, traderID).Scan(&count); err != nil {
+				return false, fmt.Errorf("failed to check exported trade: %w", err)
+			}
+			if count > 0 {
+				return false, nil // Own trade from an earlier export
+			}
+		}
+	}
+
+	exchangeType := trade.Exchange
+	if exchangeType == "" {
+		exchangeType = "external"
+	}
+	record := &ClosedPnLRecord{
+		Symbol:      trade.Symbol,
+		Side:        trade.Side,
+		EntryPrice:  trade.EntryPrice,
+		ExitPrice:   trade.ExitPrice,
+		Quantity:    trade.Quantity,
+		RealizedPnL: trade.RealizedPnL,
+		Fee:         trade.Fee,
+		Leverage:    trade.Leverage,
+		EntryTime:   trade.EntryTime,
+		ExitTime:    trade.ExitTime,
+		CloseType:   trade.CloseReason,
+	}
+	if trade.ID != "" {
+		record.ExchangeID = importTradeIDPrefix + trade.ID
+	}
+	return s.createClosedPosition(traderID, exchangeID, exchangeType, PositionSourceImport, record)
+}
+
 // GetAllOpenPositions gets all traders' open positions (for global sync)
 func (s *PositionStore) GetAllOpenPositions() ([]*TraderPosition, error) {
 	rows, err := s.db.Query(`
@@ -293,7 +634,7 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 
 	// Query all closed positions
 	rows, err := s.db.Query(`
-		SELECT realized_pnl, fee, exit_time
+		SELECT realized_pnl, fee, COALESCE(slippage, 0), exit_time
 		FROM trader_positions
 		WHERE trader_id = ? AND status = 'CLOSED'
 		ORDER BY exit_time ASC
@@ -307,15 +648,16 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 	var totalWin, totalLoss float64
 
 	for rows.Next() {
-		var pnl, fee float64
+		var pnl, fee, slippage float64
 		var exitTime sql.NullString
-		if err := rows.Scan(&pnl, &fee, &exitTime); err != nil {
+		if err := rows.Scan(&pnl, &fee, &slippage, &exitTime); err != nil {
 			continue
 		}
 
 		stats.TotalTrades++
 		stats.TotalPnL += pnl
 		stats.TotalFee += fee
+		stats.TotalSlippage += slippage
 		pnls = append(pnls, pnl)
 
 		if pnl > 0 {
@@ -327,6 +669,9 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 		}
 	}
 
+	stats.GrossPnL = stats.TotalPnL
+	stats.NetPnL = stats.GrossPnL - stats.TotalFee
+
 	// Calculate win rate
 	if stats.TotalTrades > 0 {
 		stats.WinRate = float64(stats.WinTrades) / float64(stats.TotalTrades) * 100
@@ -694,6 +1039,170 @@ func (s *PositionStore) GetDirectionStats(traderID string) ([]DirectionStats, er
 	return stats, nil
 }
 
+// PnLBucket PnL of one group of closed trades
+type PnLBucket struct {
+	Key        string  `json:"key"` // Symbol, side (long/short), close reason or hour of day ("00".."23")
+	TradeCount int     `json:"trade_count"`
+	WinTrades  int     `json:"win_trades"`
+	WinRate    float64 `json:"win_rate"`
+	TotalPnL   float64 `json:"total_pnl"`
+	AvgPnL     float64 `json:"avg_pnl"`
+	Fees       float64 `json:"fees"`
+}
+
+// PnLHeatmapCell PnL of closed trades of one symbol opened in one hour of day
+type PnLHeatmapCell struct {
+	Symbol     string  `json:"symbol"`
+	Hour       int     `json:"hour"` // Entry hour of day (0-23) in the attribution time zone
+	TradeCount int     `json:"trade_count"`
+	WinRate    float64 `json:"win_rate"`
+	TotalPnL   float64 `json:"total_pnl"`
+}
+
+// PnLAttribution realized PnL broken down by symbol, side, close reason and entry hour of day
+type PnLAttribution struct {
+	Timezone   string           `json:"timezone"`
+	TradeCount int              `json:"trade_count"`
+	TotalPnL   float64          `json:"total_pnl"`
+	BySymbol   []PnLBucket      `json:"by_symbol"` // Worst total PnL first
+	BySide     []PnLBucket      `json:"by_side"`
+	ByReason   []PnLBucket      `json:"by_reason"` // Close reasons ("unknown" if not recorded), worst total PnL first
+	ByHour     []PnLBucket      `json:"by_hour"`   // Hours with trades, in order of the day
+	Heatmap    []PnLHeatmapCell `json:"heatmap"`   // Symbol × hour cells with trades
+}
+
+// pnlAccumulator running totals of a PnL bucket
+type pnlAccumulator struct {
+	count, wins int
+	pnl, fees   float64
+}
+
+func (a *pnlAccumulator) add(pnl, fee float64) {
+	a.count++
+	if pnl > 0 {
+		a.wins++
+	}
+	a.pnl += pnl
+	a.fees += fee
+}
+
+// accumulatorFor accumulator of key, created on first use
+func accumulatorFor[K comparable](m map[K]*pnlAccumulator, key K) *pnlAccumulator {
+	if m[key] == nil {
+		m[key] = &pnlAccumulator{}
+	}
+	return m[key]
+}
+
+func (a *pnlAccumulator) winRate() float64 {
+	if a.count == 0 {
+		return 0
+	}
+	return float64(a.wins) / float64(a.count) * 100
+}
+
+func (a *pnlAccumulator) bucket(key string) PnLBucket {
+	b := PnLBucket{Key: key, TradeCount: a.count, WinTrades: a.wins, WinRate: a.winRate(), TotalPnL: a.pnl, Fees: a.fees}
+	if a.count > 0 {
+		b.AvgPnL = a.pnl / float64(a.count)
+	}
+	return b
+}
+
+// GetPnLAttribution attributes realized PnL of trades closed since the given time (zero = all)
+// to symbols, sides, close reasons and entry hours of day; hours are taken in loc (nil = UTC).
+func (s *PositionStore) GetPnLAttribution(traderID string, since time.Time, loc *time.Location) (*PnLAttribution, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	rows, err := s.db.Query(`
+		SELECT symbol, side, COALESCE(close_reason, ''), COALESCE(realized_pnl, 0), COALESCE(fee, 0), entry_time
+		FROM trader_positions
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED' AND COALESCE(exit_time, updated_at) >= ?
+	`, traderID, s.userID, s.userID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pnl attribution: %w", err)
+	}
+	defer rows.Close()
+
+	type cellKey struct {
+		symbol string
+		hour   int
+	}
+	bySymbol := make(map[string]*pnlAccumulator)
+	bySide := make(map[string]*pnlAccumulator)
+	byReason := make(map[string]*pnlAccumulator)
+	byHour := make(map[int]*pnlAccumulator)
+	cells := make(map[cellKey]*pnlAccumulator)
+
+	result := &PnLAttribution{
+		Timezone: loc.String(),
+		BySymbol: []PnLBucket{},
+		BySide:   []PnLBucket{},
+		ByReason: []PnLBucket{},
+		ByHour:   []PnLBucket{},
+		Heatmap:  []PnLHeatmapCell{},
+	}
+	for rows.Next() {
+		var symbol, side, reason string
+		var pnl, fee float64
+		var entryTime sql.NullString
+		if err := rows.Scan(&symbol, &side, &reason, &pnl, &fee, &entryTime); err != nil {
+			continue
+		}
+		if reason == "" {
+			reason = "unknown"
+		}
+		result.TradeCount++
+		result.TotalPnL += pnl
+		accumulatorFor(bySymbol, symbol).add(pnl, fee)
+		accumulatorFor(bySide, strings.ToLower(side)).add(pnl, fee)
+		accumulatorFor(byReason, reason).add(pnl, fee)
+
+		if !entryTime.Valid {
+			continue
+		}
+		entry, err := time.Parse(time.RFC3339, entryTime.String)
+		if err != nil {
+			continue
+		}
+		hour := entry.In(loc).Hour()
+		accumulatorFor(byHour, hour).add(pnl, fee)
+		accumulatorFor(cells, cellKey{symbol, hour}).add(pnl, fee)
+	}
+
+	for symbol, acc := range bySymbol {
+		result.BySymbol = append(result.BySymbol, acc.bucket(symbol))
+	}
+	sort.Slice(result.BySymbol, func(i, j int) bool { return result.BySymbol[i].TotalPnL < result.BySymbol[j].TotalPnL })
+	for _, side := range []string{"long", "short"} {
+		if acc := bySide[side]; acc != nil {
+			result.BySide = append(result.BySide, acc.bucket(side))
+		}
+	}
+	for reason, acc := range byReason {
+		result.ByReason = append(result.ByReason, acc.bucket(reason))
+	}
+	sort.Slice(result.ByReason, func(i, j int) bool { return result.ByReason[i].TotalPnL < result.ByReason[j].TotalPnL })
+	for hour := 0; hour < 24; hour++ {
+		if acc := byHour[hour]; acc != nil {
+			result.ByHour = append(result.ByHour, acc.bucket(fmt.Sprintf("%02d", hour)))
+		}
+	}
+	for key, acc := range cells {
+		result.Heatmap = append(result.Heatmap, PnLHeatmapCell{
+			Symbol: key.symbol, Hour: key.hour, TradeCount: acc.count, WinRate: acc.winRate(), TotalPnL: acc.pnl,
+		})
+	}
+	sort.Slice(result.Heatmap, func(i, j int) bool {
+		if result.Heatmap[i].Symbol != result.Heatmap[j].Symbol {
+			return result.Heatmap[i].Symbol < result.Heatmap[j].Symbol
+		}
+		return result.Heatmap[i].Hour < result.Heatmap[j].Hour
+	})
+	return result, nil
+}
+
 // HistorySummary comprehensive trading history for AI context
 type HistorySummary struct {
 	// Overall stats
@@ -910,6 +1419,11 @@ func (s *PositionStore) ExistsWithExchangePositionID(exchangeID, exchangePositio
 // This is used for syncing historical positions from exchange
 // Returns true if created, false if already exists (deduped) or invalid data
 func (s *PositionStore) CreateFromClosedPnL(traderID, exchangeID, exchangeType string, record *ClosedPnLRecord) (bool, error) {
+	return s.createClosedPosition(traderID, exchangeID, exchangeType, "sync", record)
+}
+
+// createClosedPosition inserts a closed position with the given source, deduplicated by exchange position ID
+func (s *PositionStore) createClosedPosition(traderID, exchangeID, exchangeType, source string, record *ClosedPnLRecord) (bool, error) {
 	// ==========================================================================
 	// Step 1: Validate required fields
 	// ==========================================================================
@@ -993,12 +1507,12 @@ func (s *PositionStore) CreateFromClosedPnL(traderID, exchangeID, exchangeType s
 			exit_price, exit_order_id, exit_time,
 			realized_pnl, fee, leverage, status, close_reason, source,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'CLOSED', ?, 'sync', ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'CLOSED', ?, ?, ?, ?)
 	`,
 		traderID, exchangeID, exchangeType, exchangePositionID, record.Symbol, side, record.Quantity,
 		record.EntryPrice, "", entryTime.Format(time.RFC3339),
 		record.ExitPrice, record.OrderID, exitTime.Format(time.RFC3339),
-		record.RealizedPnL, record.Fee, record.Leverage, record.CloseType,
+		record.RealizedPnL, record.Fee, record.Leverage, record.CloseType, source,
 		now.Format(time.RFC3339), now.Format(time.RFC3339),
 	)
 	if err != nil {
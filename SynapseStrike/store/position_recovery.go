@@ -0,0 +1,66 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Position Recovery State
+// ============================================================================
+// The trader keeps SL/TP prices and the peak P&L of open positions in memory
+// (drawdown rule, safekeeping TP/SL enforcement). They are mirrored to the
+// open position record so that after a crash or restart the trader can rebind
+// the positions still open on the exchange, restore the peaks and re-place
+// protective orders that no longer exist.
+
+// PositionRecovery persisted management state of an open position
+type PositionRecovery struct {
+	PositionID int64
+	StopLoss   float64 // 0 = none recorded
+	TakeProfit float64 // 0 = none recorded
+	PeakPnLPct float64 // Peak leveraged P&L in %
+	HasPeak    bool    // PeakPnLPct was recorded
+}
+
+// SetProtectivePrices records the SL/TP prices of the trader's open position (side: long/short)
+// The first stop loss is kept as the position's initial stop, the risk unit of its R multiple.
+func (s *PositionStore) SetProtectivePrices(traderID, symbol, side string, stopLoss, takeProfit float64) error {
+	_, err := s.db.Exec(`
+		UPDATE trader_positions SET stop_loss = ?, take_profit = ?, updated_at = ?,
+			initial_stop_loss = CASE WHEN COALESCE(initial_stop_loss, 0) = 0 THEN ? ELSE initial_stop_loss END
+		WHERE trader_id = ? AND symbol = ? AND UPPER(side) = UPPER(?) AND status = 'OPEN'
+	`, stopLoss, takeProfit, time.Now().Format(time.RFC3339), stopLoss, traderID, symbol, side)
+	if err != nil {
+		return fmt.Errorf("failed to update protective prices: %w", err)
+	}
+	return nil
+}
+
+// UpdatePeakPnL raises the recorded peak P&L of the trader's open position to at least pnlPct
+func (s *PositionStore) UpdatePeakPnL(traderID, symbol, side string, pnlPct float64) error {
+	_, err := s.db.Exec(`
+		UPDATE trader_positions SET peak_pnl_pct = MAX(COALESCE(peak_pnl_pct, ?), ?)
+		WHERE trader_id = ? AND symbol = ? AND UPPER(side) = UPPER(?) AND status = 'OPEN'
+	`, pnlPct, pnlPct, traderID, symbol, side)
+	if err != nil {
+		return fmt.Errorf("failed to update peak P&L: %w", err)
+	}
+	return nil
+}
+
+// GetPositionRecovery gets the persisted management state of a position
+func (s *PositionStore) GetPositionRecovery(id int64) (*PositionRecovery, error) {
+	recovery := PositionRecovery{PositionID: id}
+	var peak sql.NullFloat64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(stop_loss, 0), COALESCE(take_profit, 0), peak_pnl_pct
+		FROM trader_positions WHERE id = ?
+	`, id).Scan(&recovery.StopLoss, &recovery.TakeProfit, &peak)
+	if err != nil {
+		return nil, err
+	}
+	recovery.PeakPnLPct, recovery.HasPeak = peak.Float64, peak.Valid
+	return &recovery, nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetPnLAttribution(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+	positions := s.Position()
+
+	// 2024-03-01 is before DST: New York = UTC-5
+	day := func(hour, minute int) time.Time { return time.Date(2024, 3, 1, hour, minute, 0, 0, time.UTC) }
+	trades := []struct {
+		traderID, symbol, side, reason string
+		entry                          time.Time
+		pnl, entryFee, exitFee         float64
+		open                           bool
+	}{
+		{"t1", "AAPL", "LONG", "take_profit", day(14, 30), 100, 1, 1, false},
+		{"t1", "AAPL", "SHORT", "stop_loss", day(15, 10), -40, 0.5, 0.5, false},
+		{"t1", "TSLA", "LONG", "stop_loss", day(14, 45), -60, 1, 1, false},
+		{"t1", "TSLA", "LONG", "", day(20, 0), 10, 0, 0, false},
+		{"t1", "MSFT", "LONG", "", day(14, 30), 0, 1, 0, true},               // Still open
+		{"t2", "NVDA", "LONG", "ai_decision", day(14, 30), 500, 1, 1, false}, // Other trader
+	}
+	for _, tr := range trades {
+		pos := &TraderPosition{TraderID: tr.traderID, Symbol: tr.symbol, Side: tr.side, Quantity: 1, EntryPrice: 100, EntryTime: tr.entry, Fee: tr.entryFee}
+		if err := positions.Create(pos); err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		if tr.open {
+			continue
+		}
+		if err := positions.ClosePositionWithCosts(pos.ID, 100, 100, "exit", tr.pnl, tr.exitFee, 0, tr.reason); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	got, err := positions.GetPnLAttribution("t1", time.Now().Add(-time.Hour), ny)
+	if err != nil {
+		t.Fatalf("GetPnLAttribution: %v", err)
+	}
+	if got.Timezone != "America/New_York" || got.TradeCount != 4 || got.TotalPnL != 10 {
+		t.Errorf("totals = %s %d trades %v PnL, want America/New_York 4 trades 10 PnL", got.Timezone, got.TradeCount, got.TotalPnL)
+	}
+
+	checkBuckets := func(name string, buckets, want []PnLBucket) {
+		t.Helper()
+		if len(buckets) != len(want) {
+			t.Fatalf("%s = %+v, want %+v", name, buckets, want)
+		}
+		for i := range want {
+			if buckets[i] != want[i] {
+				t.Errorf("%s[%d] = %+v, want %+v", name, i, buckets[i], want[i])
+			}
+		}
+	}
+	checkBuckets("by symbol", got.BySymbol, []PnLBucket{
+		{Key: "TSLA", TradeCount: 2, WinTrades: 1, WinRate: 50, TotalPnL: -50, AvgPnL: -25, Fees: 2},
+		{Key: "AAPL", TradeCount: 2, WinTrades: 1, WinRate: 50, TotalPnL: 60, AvgPnL: 30, Fees: 3},
+	})
+	longWins, longTrades := 2.0, 3.0 // Runtime division, as in the store
+	checkBuckets("by side", got.BySide, []PnLBucket{
+		{Key: "long", TradeCount: 3, WinTrades: 2, WinRate: longWins / longTrades * 100, TotalPnL: 50, AvgPnL: 50 / longTrades, Fees: 4},
+		{Key: "short", TradeCount: 1, WinTrades: 0, WinRate: 0, TotalPnL: -40, AvgPnL: -40, Fees: 1},
+	})
+	checkBuckets("by reason", got.ByReason, []PnLBucket{
+		{Key: "stop_loss", TradeCount: 2, WinTrades: 0, WinRate: 0, TotalPnL: -100, AvgPnL: -50, Fees: 3},
+		{Key: "unknown", TradeCount: 1, WinTrades: 1, WinRate: 100, TotalPnL: 10, AvgPnL: 10, Fees: 0},
+		{Key: "take_profit", TradeCount: 1, WinTrades: 1, WinRate: 100, TotalPnL: 100, AvgPnL: 100, Fees: 2},
+	})
+	checkBuckets("by hour", got.ByHour, []PnLBucket{
+		{Key: "09", TradeCount: 2, WinTrades: 1, WinRate: 50, TotalPnL: 40, AvgPnL: 20, Fees: 4},
+		{Key: "10", TradeCount: 1, WinTrades: 0, WinRate: 0, TotalPnL: -40, AvgPnL: -40, Fees: 1},
+		{Key: "15", TradeCount: 1, WinTrades: 1, WinRate: 100, TotalPnL: 10, AvgPnL: 10, Fees: 0},
+	})
+
+	wantCells := []PnLHeatmapCell{
+		{Symbol: "AAPL", Hour: 9, TradeCount: 1, WinRate: 100, TotalPnL: 100},
+		{Symbol: "AAPL", Hour: 10, TradeCount: 1, WinRate: 0, TotalPnL: -40},
+		{Symbol: "TSLA", Hour: 9, TradeCount: 1, WinRate: 0, TotalPnL: -60},
+		{Symbol: "TSLA", Hour: 15, TradeCount: 1, WinRate: 100, TotalPnL: 10},
+	}
+	if len(got.Heatmap) != len(wantCells) {
+		t.Fatalf("heatmap = %+v, want %+v", got.Heatmap, wantCells)
+	}
+	for i := range wantCells {
+		if got.Heatmap[i] != wantCells[i] {
+			t.Errorf("heatmap[%d] = %+v, want %+v", i, got.Heatmap[i], wantCells[i])
+		}
+	}
+
+	// Trades closed before since are excluded
+	later, err := positions.GetPnLAttribution("t1", time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("GetPnLAttribution: %v", err)
+	}
+	if later.Timezone != "UTC" || later.TradeCount != 0 || len(later.BySymbol) != 0 || len(later.ByReason) != 0 {
+		t.Errorf("attribution after since = %+v, want empty", later)
+	}
+}
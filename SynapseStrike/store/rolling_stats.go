@@ -0,0 +1,98 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Rolling-Window Trade Stats
+// ============================================================================
+// All-time stats dilute a recent change in performance. WindowStats covers
+// the closed trades of a recent window (last N days and/or last N trades) so
+// the AI can see whether the strategy is currently working. R multiples use
+// the position's initial stop loss as the risk unit; trades without one are
+// left out of the average R.
+
+// WindowStats closed-trade stats of a recent window
+type WindowStats struct {
+	Trades       int     `json:"trades"`
+	WinRate      float64 `json:"win_rate"`      // %
+	ProfitFactor float64 `json:"profit_factor"` // 0 without losses
+	NetPnL       float64 `json:"net_pnl"`       // After fees
+	AvgR         float64 `json:"avg_r"`         // Average gross P&L in units of initial risk
+	RTrades      int     `json:"r_trades"`      // Trades with an initial stop (included in AvgR)
+}
+
+// GetWindowStats gets stats of the trader's closed trades since a time (zero = no limit), at most the lastN most recent (0 = no limit)
+func (s *PositionStore) GetWindowStats(traderID string, since time.Time, lastN int) (*WindowStats, error) {
+	query := `
+		SELECT side, quantity, entry_price, realized_pnl, COALESCE(fee, 0), COALESCE(initial_stop_loss, 0)
+		FROM trader_positions
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(exit_time, updated_at) >= ?
+		ORDER BY exit_time DESC`
+	args := []interface{}{traderID, since.UTC().Format(time.RFC3339)}
+	if lastN > 0 {
+		query += ` LIMIT ?`
+		args = append(args, lastN)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query window stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &WindowStats{}
+	var wins int
+	var totalWin, totalLoss, totalR float64
+	for rows.Next() {
+		var side string
+		var quantity, entryPrice, pnl, fee, initialStop float64
+		if err := rows.Scan(&side, &quantity, &entryPrice, &pnl, &fee, &initialStop); err != nil {
+			return nil, err
+		}
+		stats.Trades++
+		stats.NetPnL += pnl - fee
+		if pnl > 0 {
+			wins++
+			totalWin += pnl
+		} else if pnl < 0 {
+			totalLoss += -pnl
+		}
+		if r, ok := rMultiple(side, entryPrice, initialStop, quantity, pnl); ok {
+			totalR += r
+			stats.RTrades++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.Trades > 0 {
+		stats.WinRate = float64(wins) / float64(stats.Trades) * 100
+	}
+	if totalLoss > 0 {
+		stats.ProfitFactor = totalWin / totalLoss
+	}
+	if stats.RTrades > 0 {
+		stats.AvgR = totalR / float64(stats.RTrades)
+	}
+	return stats, nil
+}
+
+// rMultiple P&L of a trade in units of its initial risk, false without a stop on the losing side of the entry
+func rMultiple(side string, entryPrice, initialStop, quantity, pnl float64) (float64, bool) {
+	if initialStop <= 0 || entryPrice <= 0 || quantity <= 0 {
+		return 0, false
+	}
+	risk := entryPrice - initialStop
+	if strings.EqualFold(side, "short") {
+		risk = initialStop - entryPrice
+	}
+	if risk <= 0 {
+		return 0, false
+	}
+	return pnl / (risk * math.Abs(quantity)), true
+}
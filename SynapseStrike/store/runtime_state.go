@@ -0,0 +1,172 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Trader cycle phases persisted in runtime state
+const (
+	CyclePhaseIdle      = "idle"      // No cycle running
+	CyclePhaseDeciding  = "deciding"  // Building context / waiting for AI decision
+	CyclePhaseExecuting = "executing" // Executing decisions (PendingActions not yet executed)
+)
+
+// RuntimeStateStore trader runtime state storage (survives restarts)
+type RuntimeStateStore struct {
+	db *sql.DB
+}
+
+// TraderRuntimeState in-flight state of a trader, saved during cycles and on shutdown
+type TraderRuntimeState struct {
+	TraderID       string    `json:"trader_id"`
+	CallCount      int       `json:"call_count"`
+	DailyPnL       float64   `json:"daily_pnl"`
+	LastResetTime  time.Time `json:"last_reset_time"`
+	StopUntil      time.Time `json:"stop_until"` // Risk control pause end
+	CyclePhase     string    `json:"cycle_phase"`
+	CycleStartedAt time.Time `json:"cycle_started_at"`
+	PendingActions []string  `json:"pending_actions"` // "SYMBOL action" not yet executed in current cycle
+	CleanShutdown  bool      `json:"clean_shutdown"`  // Whether trader stopped via Stop() (false = crash / kill)
+	ShutdownPolicy string    `json:"shutdown_policy"` // Policy applied on last shutdown
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Equity floor halt (cleared only by manual reset)
+	FloorHaltedAt   time.Time `json:"floor_halted_at"`
+	FloorHaltReason string    `json:"floor_halt_reason"`
+
+	// Operator pause (opens blocked until resumed)
+	PausedAt    time.Time `json:"paused_at"`
+	PausedBy    string    `json:"paused_by"`
+	PauseReason string    `json:"pause_reason"`
+}
+
+// initTables initializes runtime state tables
+func (s *RuntimeStateStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS trader_runtime_state (
+			trader_id TEXT PRIMARY KEY,
+			call_count INTEGER DEFAULT 0,
+			daily_pnl REAL DEFAULT 0,
+			last_reset_time DATETIME,
+			stop_until DATETIME,
+			cycle_phase TEXT DEFAULT 'idle',
+			cycle_started_at DATETIME,
+			pending_actions TEXT DEFAULT '[]',
+			clean_shutdown BOOLEAN DEFAULT 0,
+			shutdown_policy TEXT DEFAULT '',
+			updated_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trader_runtime_state table: %w", err)
+	}
+
+	// Migration: add equity floor halt columns if not exists
+	s.db.Exec(`ALTER TABLE trader_runtime_state ADD COLUMN floor_halted_at DATETIME`)
+	s.db.Exec(`ALTER TABLE trader_runtime_state ADD COLUMN floor_halt_reason TEXT DEFAULT ''`)
+
+	// Migration: add operator pause columns if not exists
+	s.db.Exec(`ALTER TABLE trader_runtime_state ADD COLUMN paused_at DATETIME`)
+	s.db.Exec(`ALTER TABLE trader_runtime_state ADD COLUMN paused_by TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE trader_runtime_state ADD COLUMN pause_reason TEXT DEFAULT ''`)
+	return nil
+}
+
+// Save upserts trader runtime state
+func (s *RuntimeStateStore) Save(state *TraderRuntimeState) error {
+	pending, err := json.Marshal(state.PendingActions)
+	if err != nil {
+		return fmt.Errorf("failed to encode pending actions: %w", err)
+	}
+	state.UpdatedAt = time.Now().UTC()
+	_, err = s.db.Exec(`
+		INSERT INTO trader_runtime_state (
+			trader_id, call_count, daily_pnl, last_reset_time, stop_until, cycle_phase,
+			cycle_started_at, pending_actions, clean_shutdown, shutdown_policy, updated_at,
+			floor_halted_at, floor_halt_reason, paused_at, paused_by, pause_reason
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(trader_id) DO UPDATE SET
+			call_count = excluded.call_count,
+			daily_pnl = excluded.daily_pnl,
+			last_reset_time = excluded.last_reset_time,
+			stop_until = excluded.stop_until,
+			cycle_phase = excluded.cycle_phase,
+			cycle_started_at = excluded.cycle_started_at,
+			pending_actions = excluded.pending_actions,
+			clean_shutdown = excluded.clean_shutdown,
+			shutdown_policy = excluded.shutdown_policy,
+			updated_at = excluded.updated_at,
+			floor_halted_at = excluded.floor_halted_at,
+			floor_halt_reason = excluded.floor_halt_reason,
+			paused_at = excluded.paused_at,
+			paused_by = excluded.paused_by,
+			pause_reason = excluded.pause_reason
+	`,
+		state.TraderID, state.CallCount, state.DailyPnL, formatStateTime(state.LastResetTime),
+		formatStateTime(state.StopUntil), state.CyclePhase, formatStateTime(state.CycleStartedAt),
+		string(pending), state.CleanShutdown, state.ShutdownPolicy, state.UpdatedAt.Format(time.RFC3339),
+		formatStateTime(state.FloorHaltedAt), state.FloorHaltReason,
+		formatStateTime(state.PausedAt), state.PausedBy, state.PauseReason,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save runtime state: %w", err)
+	}
+	return nil
+}
+
+// Get gets trader runtime state (nil if none saved)
+func (s *RuntimeStateStore) Get(traderID string) (*TraderRuntimeState, error) {
+	var state TraderRuntimeState
+	var lastReset, stopUntil, cycleStarted, floorHalted, pausedAt sql.NullString
+	var pending, updatedAt string
+	err := s.db.QueryRow(`
+		SELECT trader_id, call_count, daily_pnl, last_reset_time, stop_until, cycle_phase,
+			cycle_started_at, COALESCE(pending_actions, '[]'), clean_shutdown, COALESCE(shutdown_policy, ''), updated_at,
+			floor_halted_at, COALESCE(floor_halt_reason, ''),
+			paused_at, COALESCE(paused_by, ''), COALESCE(pause_reason, '')
+		FROM trader_runtime_state WHERE trader_id = ?
+	`, traderID).Scan(
+		&state.TraderID, &state.CallCount, &state.DailyPnL, &lastReset, &stopUntil, &state.CyclePhase,
+		&cycleStarted, &pending, &state.CleanShutdown, &state.ShutdownPolicy, &updatedAt,
+		&floorHalted, &state.FloorHaltReason,
+		&pausedAt, &state.PausedBy, &state.PauseReason,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime state: %w", err)
+	}
+	state.LastResetTime = parseStateTime(lastReset)
+	state.StopUntil = parseStateTime(stopUntil)
+	state.CycleStartedAt = parseStateTime(cycleStarted)
+	state.FloorHaltedAt = parseStateTime(floorHalted)
+	state.PausedAt = parseStateTime(pausedAt)
+	state.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	json.Unmarshal([]byte(pending), &state.PendingActions)
+	return &state, nil
+}
+
+// Delete deletes trader runtime state
+func (s *RuntimeStateStore) Delete(traderID string) error {
+	_, err := s.db.Exec(`DELETE FROM trader_runtime_state WHERE trader_id = ?`, traderID)
+	return err
+}
+
+func formatStateTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseStateTime(v sql.NullString) time.Time {
+	if !v.Valid {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, v.String)
+	return t
+}
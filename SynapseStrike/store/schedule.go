@@ -0,0 +1,220 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultScheduleTimezone timezone used when a trading schedule does not specify one
+const DefaultScheduleTimezone = "America/New_York"
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// TradingSchedule time-of-day / day-of-week gating of a trader's decision cycles
+// A cycle runs only inside one of Windows (empty = any time) and outside all Blackouts.
+// Examples: Windows 10:00-11:30 and 14:00-15:30 mon-fri; Blackout fri 15:00-24:00.
+type TradingSchedule struct {
+	Enabled   bool             `json:"enabled"`
+	Timezone  string           `json:"timezone,omitempty"`  // IANA timezone (default America/New_York)
+	Windows   []ScheduleWindow `json:"windows,omitempty"`   // Allowed trading windows
+	Blackouts []ScheduleWindow `json:"blackouts,omitempty"` // Blocked windows, take precedence over Windows
+}
+
+// ScheduleWindow daily time range on selected weekdays
+// Start after End wraps past midnight (e.g. 22:00-02:00); Days refer to the day the window starts.
+type ScheduleWindow struct {
+	Label string   `json:"label,omitempty"`
+	Days  []string `json:"days,omitempty"` // "mon".."sun" (empty = every day)
+	Start string   `json:"start"`          // "HH:MM" inclusive
+	End   string   `json:"end"`            // "HH:MM" exclusive, "24:00" = end of day
+}
+
+// Validate checks timezone, weekdays and times
+func (s *TradingSchedule) Validate() error {
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+	}
+	for _, group := range [][]ScheduleWindow{s.Windows, s.Blackouts} {
+		for _, w := range group {
+			if err := w.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Check reports whether trading is allowed at the given time, with the reason when it is not
+func (s *TradingSchedule) Check(now time.Time) (bool, string) {
+	if s == nil || !s.Enabled {
+		return true, ""
+	}
+	loc, err := s.location()
+	if err != nil {
+		// Invalid timezone: fail open like market hours check, Validate rejects this on save
+		return true, ""
+	}
+	local := now.In(loc)
+
+	for _, w := range s.Blackouts {
+		if w.contains(local) {
+			return false, fmt.Sprintf("Outside trading schedule: blackout %s", w.describe(loc))
+		}
+	}
+	if len(s.Windows) == 0 {
+		return true, ""
+	}
+	for _, w := range s.Windows {
+		if w.contains(local) {
+			return true, ""
+		}
+	}
+	descriptions := make([]string, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		descriptions = append(descriptions, w.describe(loc))
+	}
+	return false, fmt.Sprintf("Outside trading schedule: allowed windows %s", strings.Join(descriptions, "; "))
+}
+
+func (s *TradingSchedule) location() (*time.Location, error) {
+	tz := s.Timezone
+	if tz == "" {
+		tz = DefaultScheduleTimezone
+	}
+	return time.LoadLocation(tz)
+}
+
+func (w ScheduleWindow) validate() error {
+	for _, day := range w.Days {
+		if _, ok := scheduleWeekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid schedule day %q (use mon..sun)", day)
+		}
+	}
+	start, err := parseScheduleClock(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseScheduleClock(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("schedule window %s-%s is empty", w.Start, w.End)
+	}
+	if start == 24*60 {
+		return fmt.Errorf("schedule window cannot start at 24:00")
+	}
+	return nil
+}
+
+// contains checks whether local time falls inside the window
+func (w ScheduleWindow) contains(local time.Time) bool {
+	start, err1 := parseScheduleClock(w.Start)
+	end, err2 := parseScheduleClock(w.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	minutes := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return w.onDay(local.Weekday()) && minutes >= start && minutes < end
+	}
+	// Overnight window: evening part belongs to today, morning part to the previous day's window
+	if minutes >= start {
+		return w.onDay(local.Weekday())
+	}
+	if minutes < end {
+		return w.onDay((local.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+func (w ScheduleWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if scheduleWeekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// describe human-readable window, e.g. "fri 15:00-24:00 America/New_York"
+func (w ScheduleWindow) describe(loc *time.Location) string {
+	days := "daily"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	desc := fmt.Sprintf("%s %s-%s %s", days, w.Start, w.End, loc)
+	if w.Label != "" {
+		desc = w.Label + " (" + desc + ")"
+	}
+	return desc
+}
+
+// parseScheduleClock parses "HH:MM" into minutes since midnight (24:00 allowed, single-digit hour accepted)
+func parseScheduleClock(clock string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(clock), ":")
+	if len(parts) != 2 || !isScheduleDigits(parts[0], 1, 2) || !isScheduleDigits(parts[1], 2, 2) {
+		return 0, fmt.Errorf("invalid schedule time %q (use HH:MM)", clock)
+	}
+	hour, err1 := strconv.Atoi(parts[0])
+	minute, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid schedule time %q (use HH:MM)", clock)
+	}
+	return hour*60 + minute, nil
+}
+
+// isScheduleDigits whether s is min..max ASCII digits (Atoi alone accepts signs like "+9")
+func isScheduleDigits(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeTradingSchedule serializes schedule for storage (empty string = none)
+func encodeTradingSchedule(s *TradingSchedule) string {
+	if s == nil {
+		return ""
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeTradingSchedule parses stored schedule (nil if empty or invalid)
+func decodeTradingSchedule(data string) *TradingSchedule {
+	if data == "" {
+		return nil
+	}
+	var s TradingSchedule
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil
+	}
+	return &s
+}
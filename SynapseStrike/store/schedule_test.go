@@ -0,0 +1,129 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// scheduleTime builds a time in the given IANA zone
+func scheduleTime(t *testing.T, tz string, year int, month time.Month, day, hour, minute int) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", tz, err)
+	}
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+func TestTradingScheduleCheck(t *testing.T) {
+	// 2024-03-15 is a Friday, 2024-03-16 a Saturday
+	overnight := &TradingSchedule{
+		Enabled:  true,
+		Timezone: "UTC",
+		Windows:  []ScheduleWindow{{Days: []string{"fri"}, Start: "22:00", End: "02:00"}},
+	}
+	weekdays := &TradingSchedule{
+		Enabled:   true,
+		Timezone:  "America/New_York",
+		Windows:   []ScheduleWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:30", End: "16:00"}},
+		Blackouts: []ScheduleWindow{{Label: "Friday close", Days: []string{"fri"}, Start: "15:00", End: "24:00"}},
+	}
+	untilMidnight := &TradingSchedule{
+		Enabled:  true,
+		Timezone: "UTC",
+		Windows:  []ScheduleWindow{{Days: []string{"fri"}, Start: "20:00", End: "24:00"}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule *TradingSchedule
+		now      time.Time
+		allowed  bool
+		reason   string
+	}{
+		{"nil schedule", nil, scheduleTime(t, "UTC", 2024, 3, 15, 3, 0), true, ""},
+		{"disabled schedule", &TradingSchedule{Windows: overnight.Windows}, scheduleTime(t, "UTC", 2024, 3, 15, 12, 0), true, ""},
+		{"enabled without windows", &TradingSchedule{Enabled: true, Timezone: "UTC"}, scheduleTime(t, "UTC", 2024, 3, 15, 12, 0), true, ""},
+
+		{"overnight evening part on start day", overnight, scheduleTime(t, "UTC", 2024, 3, 15, 23, 30), true, ""},
+		{"overnight morning part after rollover", overnight, scheduleTime(t, "UTC", 2024, 3, 16, 1, 59), true, ""},
+		{"overnight end is exclusive", overnight, scheduleTime(t, "UTC", 2024, 3, 16, 2, 0), false, "allowed windows fri 22:00-02:00 UTC"},
+		{"overnight morning part belongs to the previous day", overnight, scheduleTime(t, "UTC", 2024, 3, 15, 1, 0), false, "allowed windows"},
+		{"overnight before start", overnight, scheduleTime(t, "UTC", 2024, 3, 15, 21, 59), false, "allowed windows"},
+
+		{"24:00 end includes the last minute", untilMidnight, scheduleTime(t, "UTC", 2024, 3, 15, 23, 59), true, ""},
+		{"24:00 end stops at day rollover", untilMidnight, scheduleTime(t, "UTC", 2024, 3, 16, 0, 0), false, "allowed windows"},
+
+		{"inside window before blackout", weekdays, scheduleTime(t, "America/New_York", 2024, 3, 15, 14, 59), true, ""},
+		{"blackout takes precedence over window", weekdays, scheduleTime(t, "America/New_York", 2024, 3, 15, 15, 0), false, "blackout Friday close (fri 15:00-24:00 America/New_York)"},
+		{"blackout only on its days", weekdays, scheduleTime(t, "America/New_York", 2024, 3, 14, 15, 30), true, ""},
+		{"window start is inclusive", weekdays, scheduleTime(t, "America/New_York", 2024, 3, 14, 9, 30), true, ""},
+		{"weekend outside windows", weekdays, scheduleTime(t, "America/New_York", 2024, 3, 16, 11, 0), false, "allowed windows"},
+
+		// 14:00 UTC = 10:00 EDT (DST since 2024-03-10), 9:00 EST a week earlier
+		{"UTC time converted to schedule timezone", weekdays, scheduleTime(t, "UTC", 2024, 3, 14, 14, 0), true, ""},
+		{"UTC time converted before DST", weekdays, scheduleTime(t, "UTC", 2024, 3, 7, 14, 0), false, "allowed windows"},
+		{"UTC date rolls over before the schedule day", weekdays, scheduleTime(t, "UTC", 2024, 3, 16, 0, 30), false, "blackout"},
+		{"default timezone is New York", &TradingSchedule{Enabled: true, Windows: weekdays.Windows}, scheduleTime(t, "UTC", 2024, 3, 14, 14, 0), true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := tt.schedule.Check(tt.now)
+			if allowed != tt.allowed {
+				t.Fatalf("Check(%s) allowed = %v (%s), want %v", tt.now, allowed, reason, tt.allowed)
+			}
+			if !strings.Contains(reason, tt.reason) || (tt.reason == "") != (reason == "") {
+				t.Errorf("Check(%s) reason = %q, want it to contain %q", tt.now, reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestTradingScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  ScheduleWindow
+		wantErr string
+	}{
+		{"regular window", ScheduleWindow{Days: []string{"Mon", "fri"}, Start: "09:30", End: "16:00"}, ""},
+		{"single-digit hour", ScheduleWindow{Start: "9:30", End: "16:00"}, ""},
+		{"overnight window", ScheduleWindow{Start: "22:00", End: "02:00"}, ""},
+		{"end of day", ScheduleWindow{Start: "15:00", End: "24:00"}, ""},
+		{"missing minutes", ScheduleWindow{Start: "9", End: "16:00"}, "invalid schedule time"},
+		{"single-digit minutes", ScheduleWindow{Start: "09:5", End: "16:00"}, "invalid schedule time"},
+		{"signed hour", ScheduleWindow{Start: "+9:00", End: "16:00"}, "invalid schedule time"},
+		{"negative hour", ScheduleWindow{Start: "-1:00", End: "16:00"}, "invalid schedule time"},
+		{"hour out of range", ScheduleWindow{Start: "25:00", End: "16:00"}, "invalid schedule time"},
+		{"minute out of range", ScheduleWindow{Start: "09:60", End: "16:00"}, "invalid schedule time"},
+		{"past end of day", ScheduleWindow{Start: "09:00", End: "24:30"}, "invalid schedule time"},
+		{"seconds", ScheduleWindow{Start: "09:00:00", End: "16:00"}, "invalid schedule time"},
+		{"not a number", ScheduleWindow{Start: "ab:cd", End: "16:00"}, "invalid schedule time"},
+		{"empty", ScheduleWindow{Start: "", End: "16:00"}, "invalid schedule time"},
+		{"empty window", ScheduleWindow{Start: "10:00", End: "10:00"}, "is empty"},
+		{"start at end of day", ScheduleWindow{Start: "24:00", End: "02:00"}, "cannot start at 24:00"},
+		{"unknown day", ScheduleWindow{Days: []string{"monday"}, Start: "09:30", End: "16:00"}, "invalid schedule day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &TradingSchedule{Enabled: true, Blackouts: []ScheduleWindow{tt.window}}
+			err := schedule.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	bad := &TradingSchedule{Enabled: true, Timezone: "Mars/Olympus"}
+	if err := bad.Validate(); err == nil || !strings.Contains(err.Error(), "invalid schedule timezone") {
+		t.Errorf("Validate() with unknown timezone = %v", err)
+	}
+}
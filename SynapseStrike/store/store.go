@@ -26,6 +26,13 @@ type Store struct {
 	strategy *StrategyStore
 	tactic   *TacticStore
 	equity   *EquityStore
+	memory   *MemoryStore
+	runtime  *RuntimeStateStore
+	vwapBar  *VWAPBarStore
+	execQ    *ExecutionQueueStore
+	annot    *AnnotationStore
+	grid     *GridStore
+	fill     *FillStore
 
 	// Encryption functions
 	encryptFunc func(string) string
@@ -112,6 +119,14 @@ func (s *Store) SetCryptoFuncs(encrypt, decrypt func(string) string) {
 	if s.trader != nil {
 		s.trader.decryptFunc = decrypt
 	}
+	if s.strategy != nil {
+		s.strategy.encryptFunc = encrypt
+		s.strategy.decryptFunc = decrypt
+	}
+	if s.tactic != nil {
+		s.tactic.encryptFunc = encrypt
+		s.tactic.decryptFunc = decrypt
+	}
 }
 
 // initTables initializes all database tables
@@ -147,6 +162,30 @@ func (s *Store) initTables() error {
 	if err := s.Equity().initTables(); err != nil {
 		return fmt.Errorf("failed to initialize equity tables: %w", err)
 	}
+	if err := s.Memory().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize decision memory tables: %w", err)
+	}
+	if err := s.RuntimeState().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize runtime state tables: %w", err)
+	}
+	if err := s.VWAPBar().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize vwap bar tables: %w", err)
+	}
+	if err := s.ExecutionQueue().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize execution queue tables: %w", err)
+	}
+	if err := s.Annotation().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trade annotation tables: %w", err)
+	}
+	if err := s.Grid().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize grid tables: %w", err)
+	}
+	if err := s.Fill().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize fill quality tables: %w", err)
+	}
+	if err := s.initTenantColumns(); err != nil {
+		return fmt.Errorf("failed to initialize tenant columns: %w", err)
+	}
 	return nil
 }
 
@@ -256,7 +295,11 @@ func (s *Store) Strategy() *StrategyStore {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.strategy == nil {
-		s.strategy = &StrategyStore{db: s.db}
+		s.strategy = &StrategyStore{
+			db:          s.db,
+			encryptFunc: s.encryptFunc,
+			decryptFunc: s.decryptFunc,
+		}
 	}
 	return s.strategy
 }
@@ -276,11 +319,85 @@ func (s *Store) Tactic() *TacticStore {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.tactic == nil {
-		s.tactic = &TacticStore{db: s.db}
+		s.tactic = &TacticStore{
+			db:          s.db,
+			encryptFunc: s.encryptFunc,
+			decryptFunc: s.decryptFunc,
+		}
 	}
 	return s.tactic
 }
 
+// Memory gets decision memory storage
+func (s *Store) Memory() *MemoryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.memory == nil {
+		s.memory = &MemoryStore{db: s.db}
+	}
+	return s.memory
+}
+
+// RuntimeState gets trader runtime state storage
+func (s *Store) RuntimeState() *RuntimeStateStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runtime == nil {
+		s.runtime = &RuntimeStateStore{db: s.db}
+	}
+	return s.runtime
+}
+
+// VWAPBar gets intraday VWAP bar storage
+func (s *Store) VWAPBar() *VWAPBarStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vwapBar == nil {
+		s.vwapBar = &VWAPBarStore{db: s.db}
+	}
+	return s.vwapBar
+}
+
+// ExecutionQueue gets decision execution queue storage
+func (s *Store) ExecutionQueue() *ExecutionQueueStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.execQ == nil {
+		s.execQ = &ExecutionQueueStore{db: s.db}
+	}
+	return s.execQ
+}
+
+// Annotation gets trade annotation storage
+func (s *Store) Annotation() *AnnotationStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.annot == nil {
+		s.annot = &AnnotationStore{db: s.db}
+	}
+	return s.annot
+}
+
+// Grid gets grid/DCA leg storage
+func (s *Store) Grid() *GridStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grid == nil {
+		s.grid = &GridStore{db: s.db}
+	}
+	return s.grid
+}
+
+// Fill gets order fill quality storage
+func (s *Store) Fill() *FillStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fill == nil {
+		s.fill = &FillStore{db: s.db}
+	}
+	return s.fill
+}
+
 // Close closes database connection
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -1,16 +1,20 @@
 package store
 
 import (
+	"SynapseStrike/logger"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 )
 
 // StrategyStore strategy storage
 type StrategyStore struct {
-	db *sql.DB
+	db          *sql.DB
+	encryptFunc func(string) string
+	decryptFunc func(string) string
 }
 
 // Strategy strategy configuration
@@ -34,14 +38,69 @@ type StrategyConfig struct {
 	Indicators IndicatorConfig `json:"indicators"`
 	// custom prompt (appended at the end)
 	CustomPrompt string `json:"custom_prompt,omitempty"`
+	// prompt language (en/zh, default en): generated prompt sections and the AI's reasoning
+	Language string `json:"language,omitempty"`
 	// risk control configuration
 	RiskControl RiskControlConfig `json:"risk_control"`
 	// execution configuration (Phase 2: Smart Order Execution)
 	Execution ExecutionConfig `json:"execution"`
+	// decision memory configuration (similar past setups as lessons learned)
+	Memory MemoryConfig `json:"memory"`
+	// session memory (compressed summary of the last cycles' reasoning and outcomes, kept for the trader's run)
+	SessionMemory SessionMemoryConfig `json:"session_memory"`
+	// market regime configuration (index-based regime classification and risk scaling)
+	Regime RegimeConfig `json:"regime"`
+	// market shock detection (index gap/crash or candidate-wide volatility spike blocks opens and tightens stops)
+	Shock ShockConfig `json:"shock"`
+	// equity curve risk scaling (smaller positions while the account is in drawdown)
+	EquityRisk EquityRiskConfig `json:"equity_risk"`
+	// trade journal feedback (digest of human annotations on closed trades as coaching context)
+	Annotations AnnotationFeedbackConfig `json:"annotations"`
+	// churn guard (flag or suppress direction flips on recently opened symbols without a material change)
+	ChurnGuard ChurnGuardConfig `json:"churn_guard"`
+	// trade frequency governor (caps opens per hour/day per trader and per symbol, enforced in code)
+	TradeGovernor TradeGovernorConfig `json:"trade_governor"`
+	// context hook scripts (operator-installed executables adding custom data blocks to the user prompt)
+	ContextHooks ContextHooksConfig `json:"context_hooks"`
+	// tool-calling decision mode (AI requests extra data through tools before deciding)
+	ToolCalling ToolCallingConfig `json:"tool_calling"`
+	// self-review second pass (AI confirms, amends or rejects its proposed decisions before execution)
+	EnableSelfReview bool `json:"enable_self_review,omitempty"`
+	// ensemble decision mode (several AI models decide on the same context, decisions are merged)
+	Ensemble EnsembleConfig `json:"ensemble"`
+	// failed AI batch retries with jittered backoff and different-provider failover
+	BatchRetry BatchRetryConfig `json:"batch_retry"`
+	// decision parsing strictness and one-shot AI repair of malformed decision JSON
+	DecisionParsing DecisionParsingConfig `json:"decision_parsing"`
+	// reuse of the previous cycle's AI decision when the AI fails and the context is unchanged
+	AIResponseCache AIResponseCacheConfig `json:"ai_response_cache"`
+	// AI decision latency SLA per cycle and what the cycle does when it is exceeded
+	AILatency AILatencyConfig `json:"ai_latency"`
+	// AI sampling parameters (temperature, top_p, max tokens, reasoning effort) of the trader's AI calls
+	AIParams AIParamsConfig `json:"ai_params"`
+	// candidate ranking by opportunity score before batching
+	CandidateRanking CandidateRankingConfig `json:"candidate_ranking"`
+	// user prompt token budget (older bars, series and news are compressed to fit)
+	PromptBudget PromptBudgetConfig `json:"prompt_budget"`
+	// buy-and-hold benchmark tracked next to the equity curve (alpha in history and reports)
+	Benchmark BenchmarkConfig `json:"benchmark"`
+	// grid/DCA tactic run by the Local Function provider (resting level orders, take profit per leg)
+	Grid GridConfig `json:"grid"`
 	// editable sections of System Prompt
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
 }
 
+// Prompt languages (StrategyConfig.Language)
+const (
+	PromptLanguageEnglish = "en"
+	PromptLanguageChinese = "zh"
+)
+
+// IsValidPromptLanguage checks a prompt language ("" = English)
+func IsValidPromptLanguage(lang string) bool {
+	return lang == "" || lang == PromptLanguageEnglish || lang == PromptLanguageChinese
+}
+
 // PromptSectionsConfig editable sections of System Prompt
 type PromptSectionsConfig struct {
 	// role definition (title + description)
@@ -56,7 +115,7 @@ type PromptSectionsConfig struct {
 
 // CoinSourceConfig stock/coin source configuration
 type CoinSourceConfig struct {
-	// source type: "static" | "coinpool" | "stockpool" | "ai100" | "oi_top" | "top_winners" | "top_losers" | "mixed"
+	// source type: "static" | "coinpool" | "stockpool" | "ai100" | "oi_top" | "top_winners" | "top_losers" | "funding_arb" | "webhook" | "social_trending" | "mixed"
 	SourceType string `json:"source_type"`
 	// static coin list (used when source_type = "static") - legacy field
 	StaticCoins []string `json:"static_coins,omitempty"`
@@ -95,6 +154,63 @@ type CoinSourceConfig struct {
 	TopLosersLimit int `json:"top_losers_limit,omitempty"`
 	// Top Losers API URL (strategy-level configuration)
 	TopLosersAPIURL string `json:"top_losers_api_url,omitempty"`
+	// whether to use funding rate arbitrage scanner (crypto perpetuals)
+	UseFundingArb bool `json:"use_funding_arb"`
+	// Funding arb maximum count
+	FundingArbLimit int `json:"funding_arb_limit,omitempty"`
+	// exchanges to scan: "binance" | "bybit" | "okx" (empty = all)
+	FundingArbExchanges []string `json:"funding_arb_exchanges,omitempty"`
+	// minimum funding extreme to keep a symbol, as rate per interval (0.0005 = 0.05%)
+	FundingArbMinRate float64 `json:"funding_arb_min_rate,omitempty"`
+	// whether to use candidates pushed by an external screener (POST /api/webhooks/candidates/:strategy_id)
+	UseWebhook bool `json:"use_webhook"`
+	// Webhook maximum count
+	WebhookLimit int `json:"webhook_limit,omitempty"`
+	// HMAC-SHA256 secret the screener signs payloads with (required for webhook source)
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+	// set in API responses instead of WebhookSecret, which is never sent back to clients
+	WebhookSecretSet bool `json:"webhook_secret_set,omitempty"`
+	// minutes a pushed list stays valid (default 60)
+	WebhookTTLMinutes int `json:"webhook_ttl_minutes,omitempty"`
+	// whether to use trending tickers of a social sentiment API (e.g. a self-hosted Reddit/X scraper)
+	UseSocialTrending bool `json:"use_social_trending"`
+	// Social trending maximum count
+	SocialTrendingLimit int `json:"social_trending_limit,omitempty"`
+	// Social trending API URL (required for social_trending source)
+	SocialTrendingAPIURL string `json:"social_trending_api_url,omitempty"`
+	// API key sent as "Authorization: Bearer <key>" (optional)
+	SocialTrendingAPIKey string `json:"social_trending_api_key,omitempty"`
+	// minimum mentions to keep a ticker (0 = keep all)
+	SocialTrendingMinMentions int `json:"social_trending_min_mentions,omitempty"`
+	// liquidity filter: minimum open interest value in USD of crypto candidates (0 = DefaultMinOIValueUSD)
+	// held positions and stocks are never filtered
+	MinOIValueUSD float64 `json:"min_oi_value_usd,omitempty"`
+	// per-source minimum OI value overriding MinOIValueUSD, e.g. {"static": 5000000, "oi_top": 30000000}
+	// (0 = no OI filter for the source; candidates from several sources use the lowest threshold)
+	SourceMinOIValueUSD map[string]float64 `json:"source_min_oi_value_usd,omitempty"`
+}
+
+// DefaultMinOIValueUSD minimum open interest value of crypto candidates when none is configured
+const DefaultMinOIValueUSD = 15_000_000
+
+// MinOIValueFor minimum open interest value in USD of a candidate from the given sources (0 = no filter)
+func (c CoinSourceConfig) MinOIValueFor(sources []string) float64 {
+	threshold := c.MinOIValueUSD
+	if threshold <= 0 {
+		threshold = DefaultMinOIValueUSD
+	}
+	if len(sources) == 0 {
+		return threshold
+	}
+	lowest := math.MaxFloat64
+	for _, source := range sources {
+		value, ok := c.SourceMinOIValueUSD[source]
+		if !ok {
+			value = threshold
+		}
+		lowest = min(lowest, value)
+	}
+	return lowest
 }
 
 // IndicatorConfig indicator configuration
@@ -111,6 +227,9 @@ type IndicatorConfig struct {
 	EnableVolume      bool `json:"enable_volume"`
 	EnableOI          bool `json:"enable_oi"`           // open interest
 	EnableFundingRate bool `json:"enable_funding_rate"` // funding rate
+	// order book snapshot: top levels, spread and bid/ask imbalance (crypto only, also used by the smart limit pricer)
+	EnableOrderBook bool `json:"enable_order_book"`
+	OrderBookLevels int  `json:"order_book_levels,omitempty"` // levels per side (default 20)
 	// VWAP indicators (calculable from bar data)
 	EnableVWAPIndicator bool `json:"enable_vwap_indicator"`          // Volume Weighted Average Price
 	EnableAnchoredVWAP  bool `json:"enable_anchored_vwap"`           // Anchored VWAP from session start
@@ -126,15 +245,19 @@ type IndicatorConfig struct {
 	// external data sources
 	ExternalDataSources []ExternalDataSource `json:"external_data_sources,omitempty"`
 	// quantitative data sources (capital flow, position changes, price changes)
-	EnableQuantData    bool   `json:"enable_quant_data"`            // whether to enable quantitative data
-	QuantDataAPIURL    string `json:"quant_data_api_url,omitempty"` // quantitative data API address
-	EnableQuantOI      bool   `json:"enable_quant_oi"`              // whether to show OI data
-	EnableQuantNetflow bool   `json:"enable_quant_netflow"`         // whether to show Netflow data
+	EnableQuantData        bool   `json:"enable_quant_data"`                   // whether to enable quantitative data
+	QuantDataAPIURL        string `json:"quant_data_api_url,omitempty"`        // quantitative data API address
+	EnableQuantOI          bool   `json:"enable_quant_oi"`                     // whether to show OI data
+	EnableQuantNetflow     bool   `json:"enable_quant_netflow"`                // whether to show Netflow data
+	QuantDataMaxConcurrent int    `json:"quant_data_max_concurrent,omitempty"` // max requests in flight (default 4, respect provider rate limit)
+	QuantDataCacheTTLSec   int    `json:"quant_data_cache_ttl_sec,omitempty"`  // per-symbol cache lifetime in seconds (default 60)
 	// OI ranking data (market-wide open interest increase/decrease rankings)
 	EnableOIRanking   bool   `json:"enable_oi_ranking"`             // whether to enable OI ranking data
 	OIRankingAPIURL   string `json:"oi_ranking_api_url,omitempty"`  // OI ranking API base URL
 	OIRankingDuration string `json:"oi_ranking_duration,omitempty"` // duration: 1h, 4h, 24h
 	OIRankingLimit    int    `json:"oi_ranking_limit,omitempty"`    // number of entries (default 10)
+	// OI ranking source: registered provider or request/response layout of the API at OIRankingAPIURL
+	OIRankingSource OIRankingSourceConfig `json:"oi_ranking_source"`
 
 	// Stock Ranking Data Indicators (Alpaca Pro)
 	EnableStockNews        bool `json:"enable_stock_news"`          // Real-time news & sentiment
@@ -153,6 +276,7 @@ type IndicatorConfig struct {
 	ConfluenceTimeframes []string `json:"confluence_timeframes,omitempty"` // Timeframes to check for confluence
 	ConfluenceRequireAll bool     `json:"confluence_require_all"`          // Require ALL timeframes to align (strict)
 	ConfluenceMinMatch   int      `json:"confluence_min_match,omitempty"`  // Minimum timeframes that must align
+	ConfluenceBlockOpens bool     `json:"confluence_block_opens"`          // Hard-block opens below ConfluenceMinMatch (code enforced)
 
 	// ============================================================================
 	// Phase 1: Core Profit Engine Features
@@ -229,6 +353,10 @@ type KlineConfig struct {
 	EnableMultiTimeframe bool `json:"enable_multi_timeframe"`
 	// selected timeframe list (new: supports multi-timeframe selection)
 	SelectedTimeframes []string `json:"selected_timeframes,omitempty"`
+	// max symbols fetched in parallel per cycle (default: 8)
+	MaxConcurrentFetches int `json:"max_concurrent_fetches,omitempty"`
+	// candidates whose OHLCV quality score (0-1, missing/duplicate/out-of-order bars) is below this are skipped (0 = off)
+	MinDataQuality float64 `json:"min_data_quality,omitempty"`
 }
 
 // ExternalDataSource external data source configuration
@@ -242,6 +370,21 @@ type ExternalDataSource struct {
 	RefreshSecs int               `json:"refresh_secs,omitempty"` // refresh interval (seconds)
 }
 
+// OIRankingSourceConfig OI ranking source (see provider/oi_ranking.go)
+// Empty fields keep the layout of the original OI ranking API. Without a Provider and
+// without oi_ranking_api_url, OI ranking is skipped.
+type OIRankingSourceConfig struct {
+	Provider      string            `json:"provider,omitempty"`        // name of a provider registered in code (overrides the HTTP API)
+	TopPath       string            `json:"top_path,omitempty"`        // OI increase ranking path (default /api/oi/top-ranking)
+	LowPath       string            `json:"low_path,omitempty"`        // OI decrease ranking path (default /api/oi/low-ranking)
+	AuthScheme    string            `json:"auth_scheme,omitempty"`     // none | query | bearer | header (default query with an API key)
+	AuthName      string            `json:"auth_name,omitempty"`       // query parameter (default auth) or header name (default X-API-Key)
+	APIKey        string            `json:"api_key,omitempty"`         // API key
+	PositionsPath string            `json:"positions_path,omitempty"`  // dot path of the position array (default data.positions)
+	TimeRangePath string            `json:"time_range_path,omitempty"` // dot path of the time range label (default data.time_range)
+	FieldMap      map[string]string `json:"field_map,omitempty"`       // position field (symbol, rank, oi_delta_value, ...) -> response field
+}
+
 // RiskControlConfig risk control configuration
 // All parameters are clearly defined without ambiguity:
 //
@@ -252,6 +395,8 @@ type ExternalDataSource struct {
 //   - LargeCapMaxMargin: Large Cap max brokerage margin (AI guided)
 //   - SmallCapMaxMargin: Small Cap max brokerage margin (AI guided)
 //
+// Large Caps are symbols listed in LargeCapSymbols (default: DefaultLargeCapSymbols).
+//
 // Position Value Limits (single position notional value / account equity):
 //   - LargeCapMaxPositionValueRatio: Large Cap max = equity × ratio (CODE ENFORCED)
 //   - SmallCapMaxPositionValueRatio: Small Cap max = equity × ratio (CODE ENFORCED)
@@ -259,12 +404,16 @@ type ExternalDataSource struct {
 // Risk Controls:
 //   - MaxMarginUsage: max margin utilization percentage (CODE ENFORCED)
 //   - MinPositionSize: minimum position size in USD (CODE ENFORCED)
+//   - LargeCapMinPositionSize: minimum Large Cap position size in USD (CODE ENFORCED)
 //   - MinRiskRewardRatio: min take_profit / stop_loss ratio (AI guided)
 //   - MinConfidence: min AI confidence to open position (AI guided)
 type RiskControlConfig struct {
 	// Max number of stocks held simultaneously (CODE ENFORCED)
 	MaxPositions int `json:"max_positions"`
 
+	// Symbols treated as Large Cap (empty = DefaultLargeCapSymbols)
+	LargeCapSymbols []string `json:"large_cap_symbols,omitempty"`
+
 	// Large Cap brokerage margin for opening positions (AI guided)
 	LargeCapMaxMargin int `json:"large_cap_max_margin"`
 	// Small Cap brokerage margin for opening positions (AI guided)
@@ -279,16 +428,27 @@ type RiskControlConfig struct {
 	// This is an absolute cap regardless of equity ratio - e.g. set to 1000 for $1000 max per trade
 	MaxPositionSizeUSD float64 `json:"max_position_size_usd"`
 
+	// Max loss per trade if the stop loss fills (CODE ENFORCED, 0 = no limit)
+	// Opens are resized so that position size × stop distance stays within both caps
+	MaxRiskPerTradeUSD float64 `json:"max_risk_per_trade_usd"`
+	MaxRiskPerTradePct float64 `json:"max_risk_per_trade_pct"` // % of equity
+
 	// Max margin utilization (e.g. 0.9 = 90%) (CODE ENFORCED)
 	MaxMarginUsage float64 `json:"max_margin_usage"`
 	// Min position size in USDT (CODE ENFORCED)
 	MinPositionSize float64 `json:"min_position_size"`
+	// Min Large Cap position size in USDT (CODE ENFORCED, default: 60)
+	LargeCapMinPositionSize float64 `json:"large_cap_min_position_size"`
 
 	// Min take_profit / stop_loss ratio (AI guided)
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio"`
 	// Min AI confidence to open position (AI guided)
 	MinConfidence int `json:"min_confidence"`
 
+	// Per-symbol sizing overrides (CODE ENFORCED), e.g. aggressive on BTCUSDT but tiny on small caps.
+	// Non-zero fields replace the global limit for that symbol, see SymbolOverride.
+	SymbolOverrides map[string]SymbolOverride `json:"symbol_overrides,omitempty"`
+
 	// ============================================================================
 	// Phase 1: New Risk Management Features
 	// ============================================================================
@@ -307,11 +467,65 @@ type RiskControlConfig struct {
 	UseDailyLossLimit bool    `json:"use_daily_loss_limit"` // Enable daily loss limit
 	DailyLossLimitPct float64 `json:"daily_loss_limit_pct"` // Daily loss limit as % of equity (default: 2%)
 
+	// Equity Floor (walk-away protection, CODE ENFORCED)
+	// Once virtual equity (initial balance + realized + unrealized P&L) falls below either floor the trader
+	// stops trading. Unlike the daily loss limit the halt never lifts by itself: it survives restarts until
+	// reset via POST /api/traders/:id/equity-floor/reset.
+	EquityFloorUSD    float64 `json:"equity_floor_usd"`              // Absolute floor in USD (0 = disabled)
+	EquityFloorPct    float64 `json:"equity_floor_pct"`              // Floor in % of initial balance (0 = disabled)
+	EquityFloorAction string  `json:"equity_floor_action,omitempty"` // "freeze" (default, positions kept) or "flatten" (close all)
+
 	// Trailing Stop
 	UseTrailingStop     bool    `json:"use_trailing_stop"`     // Enable ATR-based trailing stop
 	TrailingStopATR     float64 `json:"trailing_stop_atr"`     // Trail by X ATR (default: 1.5)
 	TrailingActivationR float64 `json:"trailing_activation_r"` // Activate after X R profit (default: 1.0)
 
+	// Stop Distance Sanity Check
+	// Stop loss distance from current price is measured in primary timeframe ATR(14) multiples.
+	// Stops outside [min, max] are moved to the nearest bound instead of rejecting the decision (0 = bound disabled).
+	StopATRMinMultiple float64 `json:"stop_atr_min_multiple"` // Min stop distance in ATR (default: 0.5)
+	StopATRMaxMultiple float64 `json:"stop_atr_max_multiple"` // Max stop distance in ATR (default: 5.0)
+
+	// Volatility-Targeted Leverage (CODE ENFORCED)
+	// Opening leverage is computed as VolTargetDailyRiskPct / daily ATR%, so position volatility (ATR% × leverage)
+	// matches the target whatever the symbol; the AI's leverage is overridden and the max leverage still applies.
+	VolTargetEnabled      bool    `json:"vol_target_enabled,omitempty"` // Override AI leverage (default: false)
+	VolTargetDailyRiskPct float64 `json:"vol_target_daily_risk_pct"`    // Target daily position volatility in % (default: 1)
+
+	// Drawdown Monitor (profit giveback protection)
+	// Closes a position once its leveraged profit is above DrawdownActivationPct and it has given back
+	// DrawdownClosePct of its peak profit. Unset values fall back to the defaults (5% / 40% / 60s); an explicit 0
+	// activation watches every profitable position and an explicit 0 close percentage closes on any giveback.
+	// With DrawdownRealtime, fills and mark prices pushed by exchange websockets (Binance/Bybit user
+	// streams, Alpaca trade updates) are checked as they arrive; the interval check keeps running as backstop.
+	DrawdownMonitorEnabled   *bool                   `json:"drawdown_monitor_enabled,omitempty"` // nil = enabled (default)
+	DrawdownRealtime         bool                    `json:"drawdown_realtime,omitempty"`        // Real-time position updates via exchange websockets (default: false)
+	DrawdownActivationPct    *float64                `json:"drawdown_activation_pct,omitempty"`  // Min current profit % to close on drawdown (nil = 5)
+	DrawdownClosePct         *float64                `json:"drawdown_close_pct,omitempty"`       // Giveback % of peak profit that triggers close (nil = 40)
+	DrawdownCheckIntervalSec int                     `json:"drawdown_check_interval_sec"`        // Check interval in seconds (default: 60)
+	DrawdownOverrides        map[string]DrawdownRule `json:"drawdown_overrides,omitempty"`       // Per-symbol overrides (set fields replace global rule)
+
+	// Liquidation Guard (leveraged crypto positions)
+	// Opens whose stop loss lies beyond, or within LiquidationStopBufferPct of, the estimated liquidation price
+	// are rejected. The position monitor alerts when mark price comes within LiquidationAlertPct of liquidation.
+	LiquidationGuardEnabled  *bool   `json:"liquidation_guard_enabled,omitempty"` // nil = enabled (default)
+	LiquidationStopBufferPct float64 `json:"liquidation_stop_buffer_pct"`         // Min stop-to-liquidation distance in % of entry (default: 1)
+	LiquidationAlertPct      float64 `json:"liquidation_alert_pct"`               // Alert when mark is within this % of liquidation (default: 5)
+
+	// News Blackouts (CODE ENFORCED)
+	// No new opens within EarningsBlackoutHours of a symbol's earnings release. With MacroBlackoutEnabled, no opens
+	// on any symbol from MacroBlackoutHoursBefore until MacroBlackoutHoursAfter a matching US macro release.
+	EarningsBlackoutHours    float64  `json:"earnings_blackout_hours"`         // Hours around earnings without new opens (0 = disabled)
+	MacroBlackoutEnabled     bool     `json:"macro_blackout_enabled"`          // Enable market-wide macro event blackouts
+	MacroBlackoutEvents      []string `json:"macro_blackout_events,omitempty"` // Event name keywords (empty = DefaultMacroBlackoutEvents)
+	MacroBlackoutHoursBefore float64  `json:"macro_blackout_hours_before"`     // Blackout start before the release (default: 2)
+	MacroBlackoutHoursAfter  float64  `json:"macro_blackout_hours_after"`      // Blackout end after the release (default: 1)
+
+	// Max Holding Time (checked by the position monitor, close reason "max_hold_time")
+	MaxHoldMinutes            int    `json:"max_hold_minutes"`              // Max position holding time in minutes (0 = unlimited)
+	MaxHoldAction             string `json:"max_hold_action,omitempty"`     // "close" (default) or "review" (AI decides first, close after grace)
+	MaxHoldReviewGraceMinutes int    `json:"max_hold_review_grace_minutes"` // Review mode: minutes after deadline before forced close (default: 30)
+
 	// Partial Profit Taking
 	UsePartialProfits bool    `json:"use_partial_profits"` // Enable partial profit taking
 	PartialProfitPct  float64 `json:"partial_profit_pct"`  // % to close at first target (default: 50%)
@@ -327,6 +541,24 @@ type RiskControlConfig struct {
 	CloseAtEOD     bool   `json:"close_at_eod"`      // Auto-close all positions before market close
 	CloseAtEODTime string `json:"close_at_eod_time"` // Time to close in HH:MM ET format (default: "15:55")
 
+	// End-of-Day Policy (stock traders, applied at CloseAtEODTime or EODMinutesBeforeClose)
+	//   - "flatten":    close all positions (default)
+	//   - "reduce":     close EODReducePct of each position, hold the rest with a hard stop
+	//   - "hold-stops": hold positions with a hard stop EODStopPct from the closing price
+	// Positions held overnight are tagged so the next morning's cycle gets a gap analysis.
+	EODPolicy             string  `json:"eod_policy"`               // "flatten" | "reduce" | "hold-stops"
+	EODReducePct          float64 `json:"eod_reduce_pct"`           // % of each position to close for "reduce" (default: 50)
+	EODStopPct            float64 `json:"eod_stop_pct"`             // Hard stop distance from price in % for overnight holds (default: 2)
+	EODMinutesBeforeClose int     `json:"eod_minutes_before_close"` // Run N minutes before 16:00 ET (0 = use CloseAtEODTime)
+
+	// Shutdown Policy
+	// Applied to this trader's open positions when the trader is stopped (Stop() / SIGTERM):
+	//   - "leave":         keep positions with their existing SL/TP (default)
+	//   - "tighten-stops": move stop losses to ShutdownStopPct from current price (never loosens a stop)
+	//   - "flatten":       close all positions
+	ShutdownPolicy  string  `json:"shutdown_policy"`   // "leave" | "tighten-stops" | "flatten"
+	ShutdownStopPct float64 `json:"shutdown_stop_pct"` // Stop distance from price in % for "tighten-stops" (default: 0.5)
+
 	// Market Hours Filter
 	UseMarketHoursFilter bool   `json:"use_market_hours_filter"` // Only trade during market hours
 	MarketOpenTime       string `json:"market_open_time"`        // Market open time (default: "09:30")
@@ -341,6 +573,11 @@ type ExecutionConfig struct {
 	EnableLimitOrders   bool    `json:"enable_limit_orders"`         // Enable smart limit orders (default: false)
 	LimitOffsetATRMult  float64 `json:"limit_offset_atr_multiplier"` // ATR multiplier for limit offset (default: 0.5)
 	LimitTimeoutSeconds int     `json:"limit_timeout_seconds"`       // Timeout before switching to market order (default: 5-10s)
+	// Scale the ATR multiplier per exchange/symbol by the recorded smart limit fill rate (default: false)
+	CalibrateLimitOffset bool `json:"calibrate_limit_offset"`
+
+	// Limit Entries - AI decisions with entry_price rest as GTC limit orders
+	LimitEntryExpiryMinutes int `json:"limit_entry_expiry_minutes"` // Unfilled entries are cancelled after this (default: 60, valid_for_minutes overrides)
 
 	// TWAP (Time-Weighted Average Price) - Split large orders to reduce market impact
 	EnableTWAP          bool    `json:"enable_twap"`           // Enable TWAP for large orders (default: false)
@@ -350,6 +587,440 @@ type ExecutionConfig struct {
 
 	// Order Type Preference
 	PreferredOrderType string `json:"preferred_order_type"` // "market" | "limit" | "smart" (default: "market")
+
+	// Venue Price Guard - compare the execution venue's price with the price the AI decided on
+	// (market data may come from another exchange than the one orders are sent to)
+	PriceGuardBps    float64 `json:"price_guard_bps"`    // Max deviation in basis points before an open is guarded (0 = disabled)
+	PriceGuardAction string  `json:"price_guard_action"` // "abort" | "reprice" (default: "abort")
+
+	// Exchange Maintenance - behaviour while the exchange is in a scheduled maintenance window
+	MaintenanceAction        string `json:"maintenance_action"`         // "defer" | "monitor" (default: "defer")
+	MaintenanceBufferMinutes int    `json:"maintenance_buffer_minutes"` // Window widened by this on both sides (default: 5)
+}
+
+// Venue price guard actions (ExecutionConfig.PriceGuardAction)
+const (
+	PriceGuardAbort   = "abort"   // Reject the open
+	PriceGuardReprice = "reprice" // Shift entry, stop loss and take profits by the venue/context price ratio and execute
+)
+
+// Exchange maintenance actions (ExecutionConfig.MaintenanceAction)
+const (
+	MaintenanceDefer   = "defer"   // Skip cycles until the window is over
+	MaintenanceMonitor = "monitor" // Run cycles for analysis, place no orders
+)
+
+// MemoryConfig decision memory configuration
+// Past decisions are embedded with their outcomes; the most similar ones are injected into the prompt.
+type MemoryConfig struct {
+	Enabled       bool    `json:"enabled"`        // Enable decision memory (default: false)
+	TopK          int     `json:"top_k"`          // Number of similar past setups per candidate (default: 3)
+	MinSimilarity float64 `json:"min_similarity"` // Minimum cosine similarity to include (default: 0.75)
+}
+
+// SessionMemoryConfig short-term conversation memory configuration
+// A cheap AI call compresses the last cycles into a summary injected into the next prompts (see decision/session_memory.go).
+type SessionMemoryConfig struct {
+	Enabled   bool   `json:"enabled"`               // Enable session memory (default: false)
+	Cycles    int    `json:"cycles"`                // Last K cycles folded into the summary (default: 6)
+	MaxChars  int    `json:"max_chars"`             // Summary length limit (default: 1200)
+	AIModelID string `json:"ai_model_id,omitempty"` // AI model writing the summary, typically a cheap one (empty = trader's model)
+}
+
+// AnnotationFeedbackConfig trade journal feedback configuration
+// Annotations of the lookback window are summarized in the user prompt (see decision/annotations.go).
+type AnnotationFeedbackConfig struct {
+	Enabled      bool `json:"enabled"`       // Feed annotation digest into the user prompt (default: false)
+	MaxItems     int  `json:"max_items"`     // Max annotated trades listed with their notes (default: 10)
+	LookbackDays int  `json:"lookback_days"` // Only annotations of the last N days (default: 30)
+}
+
+// Churn guard actions (ChurnGuardConfig.Action)
+const (
+	ChurnActionFlag     = "flag"     // Record the flip in the decision log and execute it
+	ChurnActionSuppress = "suppress" // Turn the flip into hold/wait
+)
+
+// ChurnGuardConfig cycle-over-cycle decision comparator configuration
+// The previous decision and its outcome are shown per symbol in the user prompt; a close or
+// reversal of a position opened within the lookback is a flip unless price or RSI moved enough
+// since the open (see decision/churn.go).
+type ChurnGuardConfig struct {
+	Enabled         bool    `json:"enabled"`            // Enable previous decision context and flip detection (default: false)
+	LookbackCycles  int     `json:"lookback_cycles"`    // Opens of the last N cycles are protected (default: 3)
+	MinPriceMovePct float64 `json:"min_price_move_pct"` // Price move since the open that justifies a flip (default: 1.0)
+	MinRSIChange    float64 `json:"min_rsi_change"`     // RSI7 change since the open that justifies a flip (default: 15)
+	Action          string  `json:"action"`             // "flag" | "suppress" (default: "flag")
+}
+
+// TradeGovernorConfig trade frequency governor configuration
+// Opens beyond a budget are rejected at execution time and the rejection is reported in the next
+// prompt (see decision/trade_budget.go). Windows are rolling: the last 60 minutes and the last 24 hours.
+// A limit of 0 means unlimited.
+type TradeGovernorConfig struct {
+	Enabled                  bool `json:"enabled"`                       // Enforce the trade budget (default: false)
+	MaxOpensPerHour          int  `json:"max_opens_per_hour"`            // Opens across all symbols per hour (default: 4)
+	MaxOpensPerDay           int  `json:"max_opens_per_day"`             // Opens across all symbols per day (default: 20)
+	MaxOpensPerSymbolPerHour int  `json:"max_opens_per_symbol_per_hour"` // Opens of one symbol per hour (default: 1)
+	MaxOpensPerSymbolPerDay  int  `json:"max_opens_per_symbol_per_day"`  // Opens of one symbol per day (default: 4)
+}
+
+// ContextHooksConfig external context hook scripts
+// Scripts are file names of executables in the server's CONTEXT_HOOKS_DIR (default data/context_hooks).
+// Each runs once per cycle with the context as JSON on stdin and prints prompt blocks
+// (see decision/context_script.go). Go code registers hooks with decision.RegisterContextHook instead.
+type ContextHooksConfig struct {
+	Enabled        bool     `json:"enabled"`           // Run the scripts every cycle (default: false)
+	Scripts        []string `json:"scripts,omitempty"` // Script file names, run in order
+	TimeoutSeconds int      `json:"timeout_seconds"`   // Per-script timeout (default: 10)
+}
+
+// RegimeConfig market regime classifier configuration
+// Index symbols (SPY/QQQ) are always fetched and classified as trending_up / trending_down / choppy / high_vol.
+type RegimeConfig struct {
+	Enabled         bool     `json:"enabled"`            // Enable regime classification in prompt (default: false)
+	IndexSymbols    []string `json:"index_symbols"`      // Index symbols to classify (default: SPY, QQQ)
+	Timeframe       string   `json:"timeframe"`          // K-line timeframe for classification (default: 1h)
+	HighVolATRRatio float64  `json:"high_vol_atr_ratio"` // Recent ATR / baseline ATR at or above this = high_vol (default: 1.5)
+	ChopBandPct     float64  `json:"chop_band_pct"`      // |EMA20 - EMA50| / price below this % = choppy (default: 0.15)
+
+	// Risk scaling in high_vol regime (CODE ENFORCED)
+	ScaleRiskInHighVol  bool    `json:"scale_risk_in_high_vol"` // Scale down new positions in high_vol regime
+	HighVolSizeScale    float64 `json:"high_vol_size_scale"`    // Position size multiplier in high_vol (default: 0.5)
+	HighVolMaxPositions int     `json:"high_vol_max_positions"` // Max positions in high_vol (0 = unchanged)
+}
+
+// ShockConfig market shock detector configuration
+// A shock is an index moving more than MovePct within WindowMinutes, or the candidates' ATR spiking
+// beyond ATRZScore standard deviations. Shock cycles skip new opens and tighten existing stops.
+type ShockConfig struct {
+	Enabled       bool     `json:"enabled"`        // Enable shock detection (default: false)
+	IndexSymbols  []string `json:"index_symbols"`  // Symbols watched for gaps and crashes (default: SPY, BTCUSDT)
+	MovePct       float64  `json:"move_pct"`       // Index move % within the window that triggers shock mode (default: 3)
+	WindowMinutes int      `json:"window_minutes"` // Move window in minutes (default: 30)
+	ATRZScore     float64  `json:"atr_z_score"`    // Candidate-wide ATR z-score that triggers shock mode (0 = disabled, default: 3)
+	StopPct       float64  `json:"stop_pct"`       // Stops of open positions are tightened to this % from price (default: 1)
+}
+
+// EquityRiskConfig equity curve-based risk scaling
+// Drawdown is measured from the peak of the trader's equity snapshots in the lookback window.
+// Risk is reduced once the drawdown reaches DrawdownPct and restored once it recovers to RecoveryPct.
+type EquityRiskConfig struct {
+	Enabled       bool    `json:"enabled"`        // Enable equity curve risk scaling (default: false)
+	LookbackHours int     `json:"lookback_hours"` // Equity curve window for the peak (default: 168 = 7 days)
+	DrawdownPct   float64 `json:"drawdown_pct"`   // Drawdown from peak % that reduces risk (default: 5)
+	RecoveryPct   float64 `json:"recovery_pct"`   // Drawdown % at or below which full risk is restored (default: 2)
+	RiskScale     float64 `json:"risk_scale"`     // Position size and max positions multiplier while reduced (default: 0.5)
+}
+
+// ToolCallingConfig tool-calling decision mode configuration
+// The AI may call data tools (get_klines, get_news, ...) before emitting its decision, within a per-cycle budget.
+type ToolCallingConfig struct {
+	Enabled  bool     `json:"enabled"`         // Enable tool-calling mode (default: false)
+	MaxCalls int      `json:"max_calls"`       // Tool call budget per AI call (default: 6)
+	Tools    []string `json:"tools,omitempty"` // Allowed tool names (empty = all)
+}
+
+// EnsembleConfig ensemble decision mode configuration
+// The trader's own AI model plus AIModelIDs (1-2 more) decide on the same context, decisions are merged by MergeRule.
+type EnsembleConfig struct {
+	Enabled    bool     `json:"enabled"`                // Enable ensemble mode (default: false)
+	AIModelIDs []string `json:"ai_model_ids,omitempty"` // Additional AI model IDs (1-2)
+	MergeRule  string   `json:"merge_rule"`             // "unanimous" | "majority" | "highest_confidence" (default: majority)
+}
+
+// BatchRetryConfig retry configuration of failed AI batch calls
+// Each retry waits BaseDelayMs × 2^attempt (capped at MaxDelayMs) with jitter; after the last retry the batch
+// is tried once on FailoverAIModelID before it is skipped for the cycle.
+type BatchRetryConfig struct {
+	MaxRetries        int    `json:"max_retries"`                    // Retries per batch on the trader's AI model (0 = no retry, default: 2)
+	BaseDelayMs       int    `json:"base_delay_ms"`                  // First retry delay (default: 1000)
+	MaxDelayMs        int    `json:"max_delay_ms"`                   // Retry delay cap (default: 8000)
+	FailoverAIModelID string `json:"failover_ai_model_id,omitempty"` // AI model of a different provider to fail over to (empty = none)
+}
+
+// DecisionParsingConfig how AI responses without valid decision JSON are handled
+// With RepairEnabled the malformed block is sent back once to RepairAIModelID (a cheap model) with a
+// "repair this into the schema" prompt before the batch counts as a parse failure.
+type DecisionParsingConfig struct {
+	Strictness      string `json:"strictness"`                   // "lenient" (no JSON → safe wait) | "strict" (no JSON is a parse failure) (default: lenient)
+	RepairEnabled   bool   `json:"repair_enabled"`               // One AI repair attempt of malformed decision JSON (default: true)
+	RepairAIModelID string `json:"repair_ai_model_id,omitempty"` // AI model used for the repair (empty = model that answered)
+}
+
+// Decision parse strictness (DecisionParsingConfig.Strictness)
+const (
+	ParseStrictnessLenient = "lenient" // A response without decision JSON becomes a safe "wait"
+	ParseStrictnessStrict  = "strict"  // A response without decision JSON is a parse failure (repaired or fallen back like malformed JSON)
+)
+
+// AIResponseCacheConfig reuse of the last AI decision during provider outages
+// When the AI call fails and the context hash (positions, candidates, prices rounded to PriceBucketPct)
+// equals that of the last successful AI decision made within FreshnessMinutes, that decision is reused
+// instead of flipping straight to the algorithmic fallback.
+type AIResponseCacheConfig struct {
+	Enabled          bool    `json:"enabled"`           // Reuse the last AI decision for an unchanged context on AI failure (default: false)
+	FreshnessMinutes int     `json:"freshness_minutes"` // Maximum age of a reused decision (default: 15)
+	PriceBucketPct   float64 `json:"price_bucket_pct"`  // Price granularity of the context hash in percent (default: 0.5)
+}
+
+// AI timeout policies (AILatencyConfig.OnTimeout)
+const (
+	AITimeoutFallback = "fallback" // Algorithmic decision (GetAlgorithmicDecision)
+	AITimeoutSkip     = "skip"     // No trades this cycle
+)
+
+// AILatencyConfig wall-clock SLA of the cycle's AI decision
+// AI calls still running when the SLA expires are abandoned and the cycle continues per OnTimeout;
+// the decision record is flagged as AI timeout.
+type AILatencyConfig struct {
+	SLASeconds int    `json:"sla_seconds"` // Maximum AI time per cycle (0 = AI_CYCLE_TIMEOUT_SECONDS, default 80% of scan interval)
+	OnTimeout  string `json:"on_timeout"`  // "fallback" (default) | "skip"
+}
+
+// AIParamsConfig AI sampling parameters
+// Applied to every AI client the trader calls (own model, ensemble members, batch failover).
+// Unset fields keep the provider client's defaults (temperature 0.5, AI_MAX_TOKENS).
+type AIParamsConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`      // Sampling temperature 0-2 (nil = client default)
+	TopP            *float64 `json:"top_p,omitempty"`            // Nucleus sampling 0-1 (nil = provider default)
+	MaxTokens       int      `json:"max_tokens,omitempty"`       // Maximum response tokens (0 = AI_MAX_TOKENS)
+	ReasoningEffort string   `json:"reasoning_effort,omitempty"` // "low" | "medium" | "high" for reasoning-capable models (empty = provider default)
+	NativeReasoning bool     `json:"native_reasoning,omitempty"` // Use reasoning returned outside the content (Claude thinking, DeepSeek R1, Gemini thoughts) as chain of thought, <reasoning> tags become optional
+}
+
+// CandidateRankingConfig candidate pre-ranking configuration
+// Candidates are ordered by a weighted opportunity score (see decision/candidate_rank.go).
+// All weights 0 = equal weights.
+type CandidateRankingConfig struct {
+	Enabled        bool    `json:"enabled"`         // Rank candidates before batching (default: false)
+	MaxCandidates  int     `json:"max_candidates"`  // Cut low scorers beyond this count (0 = keep all, held symbols always kept)
+	MomentumWeight float64 `json:"momentum_weight"` // 1h/4h price change
+	VolumeWeight   float64 `json:"volume_weight"`   // Volume surge vs average
+	OIWeight       float64 `json:"oi_weight"`       // Open interest change
+	NewsWeight     float64 `json:"news_weight"`     // Recency of latest news
+}
+
+// PromptBudgetConfig user prompt size budget
+// When the estimated prompt exceeds MaxTokens, older kline bars are dropped, indicator
+// series shortened and news reduced to headlines until it fits (see decision/prompt_budget.go).
+type PromptBudgetConfig struct {
+	Enabled   bool `json:"enabled"`    // Enable prompt compression (default: false)
+	MaxTokens int  `json:"max_tokens"` // Estimated token budget of the user prompt (default: 24000)
+	MinBars   int  `json:"min_bars"`   // Never cut kline tables / series below this many bars (default: 10)
+}
+
+// DefaultBenchmarkSymbol benchmark held when none is configured
+const DefaultBenchmarkSymbol = "BTCUSDT"
+
+// BenchmarkConfig buy-and-hold benchmark of the trader
+// Each equity snapshot records what the initial balance would be worth held in Symbol (see store/equity_benchmark.go).
+type BenchmarkConfig struct {
+	Enabled bool   `json:"enabled"` // Track the benchmark (default: true)
+	Symbol  string `json:"symbol"`  // Held instrument, e.g. "BTCUSDT" or "SPY" (default: BTCUSDT)
+}
+
+// GridConfig grid/DCA tactic of the Local Function provider
+// Each grid rests a limit buy on every level below the market; a filled level (leg) gets its own
+// take profit and the level is re-armed once the leg is closed (see trader/grid.go).
+type GridConfig struct {
+	Enabled bool       `json:"enabled"` // Run the grid engine (default: false)
+	Grids   []GridSpec `json:"grids"`   // One grid per symbol
+}
+
+// GridSpec grid of one symbol (long only)
+type GridSpec struct {
+	Symbol        string  `json:"symbol"`
+	LowerPrice    float64 `json:"lower_price"`     // Lowest level
+	UpperPrice    float64 `json:"upper_price"`     // Highest level
+	Levels        int     `json:"levels"`          // Number of levels, evenly spaced from lower to upper (>= 2)
+	LevelSizeUSD  float64 `json:"level_size_usd"`  // Notional bought per level
+	TakeProfitPct float64 `json:"take_profit_pct"` // Take profit of each leg in % above its fill price
+	Leverage      int     `json:"leverage"`        // Leverage of the level orders (default: 1)
+}
+
+// LevelPrices level prices of the grid, lowest first
+func (g GridSpec) LevelPrices() []float64 {
+	if g.Levels < 2 || g.UpperPrice <= g.LowerPrice {
+		return nil
+	}
+	step := (g.UpperPrice - g.LowerPrice) / float64(g.Levels-1)
+	prices := make([]float64, g.Levels)
+	for i := range prices {
+		prices[i] = g.LowerPrice + step*float64(i)
+	}
+	return prices
+}
+
+// Liquidation guard defaults
+const (
+	DefaultLiquidationStopBufferPct = 1.0
+	DefaultLiquidationAlertPct      = 5.0
+)
+
+// DefaultVolTargetDailyRiskPct target daily position volatility of volatility-targeted leverage
+const DefaultVolTargetDailyRiskPct = 1.0
+
+// News blackout defaults
+const (
+	DefaultMacroBlackoutHoursBefore = 2.0
+	DefaultMacroBlackoutHoursAfter  = 1.0
+)
+
+// DefaultMacroBlackoutEvents macro events blacked out when RiskControlConfig.MacroBlackoutEvents is empty
+var DefaultMacroBlackoutEvents = []string{"FOMC", "CPI"}
+
+// Drawdown monitor defaults (behavior before drawdown rules were configurable)
+const (
+	DefaultDrawdownActivationPct    = 5.0
+	DefaultDrawdownClosePct         = 40.0
+	DefaultDrawdownCheckIntervalSec = 60
+)
+
+// DrawdownRule per-symbol drawdown monitor override
+type DrawdownRule struct {
+	Enabled       *bool    `json:"enabled,omitempty"`        // nil = inherit global flag
+	ActivationPct *float64 `json:"activation_pct,omitempty"` // nil = inherit
+	ClosePct      *float64 `json:"close_pct,omitempty"`      // nil = inherit
+}
+
+// SymbolOverride per-symbol position sizing override (zero fields inherit the global limit)
+type SymbolOverride struct {
+	MaxPositionUSD float64 `json:"max_position_usd,omitempty"` // Replaces max_position_size_usd (equity ratio limit still applies)
+	MaxLeverage    int     `json:"max_leverage,omitempty"`     // Replaces large/small cap max leverage
+	MinConfidence  int     `json:"min_confidence,omitempty"`   // Min AI confidence to open, enforced for this symbol
+}
+
+// SymbolOverrideFor override configured for symbol (keys match case-insensitively)
+func (rc *RiskControlConfig) SymbolOverrideFor(symbol string) (SymbolOverride, bool) {
+	for key, override := range rc.SymbolOverrides {
+		if strings.EqualFold(strings.TrimSpace(key), symbol) {
+			return override, true
+		}
+	}
+	return SymbolOverride{}, false
+}
+
+// MaxLeverageFor max leverage of symbol (override, else large/small cap limit)
+func (rc *RiskControlConfig) MaxLeverageFor(symbol string) int {
+	if override, ok := rc.SymbolOverrideFor(symbol); ok && override.MaxLeverage > 0 {
+		return override.MaxLeverage
+	}
+	if rc.IsLargeCap(symbol) {
+		return rc.LargeCapMaxMargin
+	}
+	return rc.SmallCapMaxMargin
+}
+
+// MaxPositionSizeUSDFor absolute position size cap of symbol in USD (0 = no cap)
+func (rc *RiskControlConfig) MaxPositionSizeUSDFor(symbol string) float64 {
+	if override, ok := rc.SymbolOverrideFor(symbol); ok && override.MaxPositionUSD > 0 {
+		return override.MaxPositionUSD
+	}
+	return rc.MaxPositionSizeUSD
+}
+
+// MinConfidenceFor enforced min confidence to open symbol (0 = not enforced, global min_confidence is AI guided)
+func (rc *RiskControlConfig) MinConfidenceFor(symbol string) int {
+	if override, ok := rc.SymbolOverrideFor(symbol); ok {
+		return override.MinConfidence
+	}
+	return 0
+}
+
+// DrawdownRuleFor resolves drawdown rule of symbol (global rule, defaults, then per-symbol override)
+func (rc *RiskControlConfig) DrawdownRuleFor(symbol string) (enabled bool, activationPct, closePct float64) {
+	enabled = rc.DrawdownMonitorEnabled == nil || *rc.DrawdownMonitorEnabled
+	activationPct = DefaultDrawdownActivationPct
+	if rc.DrawdownActivationPct != nil {
+		activationPct = *rc.DrawdownActivationPct
+	}
+	closePct = DefaultDrawdownClosePct
+	if rc.DrawdownClosePct != nil {
+		closePct = *rc.DrawdownClosePct
+	}
+
+	for key, override := range rc.DrawdownOverrides {
+		if !strings.EqualFold(strings.TrimSpace(key), symbol) {
+			continue
+		}
+		if override.Enabled != nil {
+			enabled = *override.Enabled
+		}
+		if override.ActivationPct != nil {
+			activationPct = *override.ActivationPct
+		}
+		if override.ClosePct != nil {
+			closePct = *override.ClosePct
+		}
+		break
+	}
+	return enabled, activationPct, closePct
+}
+
+// DrawdownCheckInterval drawdown monitor check interval
+func (rc *RiskControlConfig) DrawdownCheckInterval() time.Duration {
+	if rc.DrawdownCheckIntervalSec <= 0 {
+		return DefaultDrawdownCheckIntervalSec * time.Second
+	}
+	return time.Duration(rc.DrawdownCheckIntervalSec) * time.Second
+}
+
+// LiquidationGuard resolves liquidation guard settings (zero values fall back to defaults)
+func (rc *RiskControlConfig) LiquidationGuard() (enabled bool, stopBufferPct, alertPct float64) {
+	enabled = rc.LiquidationGuardEnabled == nil || *rc.LiquidationGuardEnabled
+	stopBufferPct = rc.LiquidationStopBufferPct
+	if stopBufferPct <= 0 {
+		stopBufferPct = DefaultLiquidationStopBufferPct
+	}
+	alertPct = rc.LiquidationAlertPct
+	if alertPct <= 0 {
+		alertPct = DefaultLiquidationAlertPct
+	}
+	return enabled, stopBufferPct, alertPct
+}
+
+// VolTarget resolves volatility-targeted leverage settings (zero target falls back to default)
+func (rc *RiskControlConfig) VolTarget() (enabled bool, dailyRiskPct float64) {
+	dailyRiskPct = rc.VolTargetDailyRiskPct
+	if dailyRiskPct <= 0 {
+		dailyRiskPct = DefaultVolTargetDailyRiskPct
+	}
+	return rc.VolTargetEnabled, dailyRiskPct
+}
+
+// MacroBlackout resolves macro blackout settings (zero values fall back to defaults)
+func (rc *RiskControlConfig) MacroBlackout() (enabled bool, events []string, hoursBefore, hoursAfter float64) {
+	events = rc.MacroBlackoutEvents
+	if len(events) == 0 {
+		events = DefaultMacroBlackoutEvents
+	}
+	hoursBefore = rc.MacroBlackoutHoursBefore
+	if hoursBefore <= 0 {
+		hoursBefore = DefaultMacroBlackoutHoursBefore
+	}
+	hoursAfter = rc.MacroBlackoutHoursAfter
+	if hoursAfter <= 0 {
+		hoursAfter = DefaultMacroBlackoutHoursAfter
+	}
+	return rc.MacroBlackoutEnabled, events, hoursBefore, hoursAfter
+}
+
+// DefaultLargeCapSymbols symbols treated as Large Cap when RiskControlConfig.LargeCapSymbols is empty
+var DefaultLargeCapSymbols = []string{"AAPL", "MSFT", "NVDA", "TSLA", "AMZN", "GOOGL", "META"}
+
+// IsLargeCap checks whether symbol uses Large Cap limits
+func (rc *RiskControlConfig) IsLargeCap(symbol string) bool {
+	symbols := rc.LargeCapSymbols
+	if len(symbols) == 0 {
+		symbols = DefaultLargeCapSymbols
+	}
+	for _, s := range symbols {
+		if strings.EqualFold(strings.TrimSpace(s), symbol) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *StrategyStore) initTables() error {
@@ -393,21 +1064,28 @@ func (s *StrategyStore) initDefaultData() error {
 
 // GetDefaultStrategyConfig returns the default strategy configuration for the given language
 func GetDefaultStrategyConfig(lang string) StrategyConfig {
+	drawdownActivationPct, drawdownClosePct := DefaultDrawdownActivationPct, DefaultDrawdownClosePct
 	config := StrategyConfig{
 		CoinSource: CoinSourceConfig{
-			SourceType:      "coinpool",
-			UseCoinPool:     true,
-			CoinPoolLimit:   10,
-			CoinPoolAPIURL:  "http://172.22.189.252:30006/api/ai500/list?auth=cm_568c67eae410d912c54c",
-			UseOITop:        false,
-			OITopLimit:      20,
-			OITopAPIURL:     "http://172.22.189.252:30006/api/oi/top-ranking?limit=20&duration=1h&auth=cm_568c67eae410d912c54c",
-			UseMoversTop:    false,
-			MoversTopLimit:  100,
-			MoversTopAPIURL: "https://invest-soft.com/api/winners/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ",
-			UseTopLosers:    false,
-			TopLosersLimit:  100,
-			TopLosersAPIURL: "https://invest-soft.com/api/losers/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ",
+			SourceType:          "stockpool",
+			UseStockPool:        true,
+			StockPoolLimit:      10,
+			StockPoolAPIURL:     "http://172.22.189.252:30006/api/ai500/list?auth=cm_568c67eae410d912c54c",
+			UseOITop:            false,
+			OITopLimit:          20,
+			OITopAPIURL:         "",
+			UseMoversTop:        false,
+			MoversTopLimit:      100,
+			MoversTopAPIURL:     "https://invest-soft.com/api/winners/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ",
+			UseTopLosers:        false,
+			TopLosersLimit:      100,
+			TopLosersAPIURL:     "https://invest-soft.com/api/losers/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ",
+			UseFundingArb:       false,
+			FundingArbLimit:     20,
+			FundingArbMinRate:   0.0005,
+			UseSocialTrending:   false,
+			SocialTrendingLimit: 20,
+			MinOIValueUSD:       DefaultMinOIValueUSD,
 		},
 		Indicators: IndicatorConfig{
 			Klines: KlineConfig{
@@ -417,6 +1095,7 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 				LongerCount:          10,
 				EnableMultiTimeframe: true,
 				SelectedTimeframes:   []string{"5m", "15m", "1h", "4h"},
+				MaxConcurrentFetches: 8,
 			},
 			EnableRawKlines:   true, // Required - raw OHLCV data for AI analysis
 			EnableEMA:         false,
@@ -427,18 +1106,20 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			EnableOI:          false, // Disabled - crypto exchange feature
 			EnableFundingRate: false, // Disabled - crypto exchange feature
 			// VWAP indicators - enabled by default (calculated from bar data)
-			EnableVWAPIndicator: true,
-			EnableAnchoredVWAP:  true,
-			AnchoredVWAPPeriod:  0, // 0 = session start
-			EnableVolumeProfile: true,
-			VolumeProfileBins:   24, // Default 24 price bins
-			EMAPeriods:          []int{20, 50},
-			RSIPeriods:          []int{7, 14},
-			ATRPeriods:          []int{14},
-			EnableQuantData:     false, // Disabled - crypto data source
-			QuantDataAPIURL:     "",
-			EnableQuantOI:       false,
-			EnableQuantNetflow:  false,
+			EnableVWAPIndicator:    true,
+			EnableAnchoredVWAP:     true,
+			AnchoredVWAPPeriod:     0, // 0 = session start
+			EnableVolumeProfile:    true,
+			VolumeProfileBins:      24, // Default 24 price bins
+			EMAPeriods:             []int{20, 50},
+			RSIPeriods:             []int{7, 14},
+			ATRPeriods:             []int{14},
+			EnableQuantData:        false, // Disabled - crypto data source
+			QuantDataAPIURL:        "",
+			EnableQuantOI:          false,
+			EnableQuantNetflow:     false,
+			QuantDataMaxConcurrent: 4,
+			QuantDataCacheTTLSec:   60,
 			// OI ranking data - disabled for stock trading
 			EnableOIRanking:   false,
 			OIRankingAPIURL:   "",
@@ -485,6 +1166,7 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			SmallCapMaxPositionValueRatio: 1.0, // Small Cap: max position = 1x equity (CODE ENFORCED)
 			MaxMarginUsage:                0.9, // Max 90% margin usage (CODE ENFORCED)
 			MinPositionSize:               12,  // Min 12 USD per position (CODE ENFORCED)
+			LargeCapMinPositionSize:       60,  // Min 60 USD per Large Cap position (CODE ENFORCED)
 			MinRiskRewardRatio:            3.0, // Min 3:1 profit/loss ratio (AI guided)
 			MinConfidence:                 75,  // Min 75% confidence (AI guided)
 
@@ -504,12 +1186,40 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			TrailingStopATR:     1.5,   // Trail by 1.5 ATR when enabled
 			TrailingActivationR: 1.0,   // Activate after 1R profit
 
+			StopATRMinMultiple: 0.5, // Stops closer than 0.5 ATR are widened
+			StopATRMaxMultiple: 5.0, // Stops farther than 5 ATR are tightened
+
+			VolTargetEnabled:      false,                        // AI chooses leverage by default
+			VolTargetDailyRiskPct: DefaultVolTargetDailyRiskPct, // 1% daily volatility when enabled
+
+			DrawdownActivationPct:    &drawdownActivationPct,          // Only positions in >5% profit
+			DrawdownClosePct:         &drawdownClosePct,               // Close after giving back 40% of peak profit
+			DrawdownCheckIntervalSec: DefaultDrawdownCheckIntervalSec, // Check every minute
+
+			LiquidationStopBufferPct: DefaultLiquidationStopBufferPct, // Stop at least 1% of entry away from liquidation
+			LiquidationAlertPct:      DefaultLiquidationAlertPct,      // Alert when mark is within 5% of liquidation
+
+			EarningsBlackoutHours:    24,                              // No opens within a day of earnings
+			MacroBlackoutEnabled:     false,                           // Macro blackouts are opt-in
+			MacroBlackoutEvents:      DefaultMacroBlackoutEvents,      // FOMC decisions and CPI prints
+			MacroBlackoutHoursBefore: DefaultMacroBlackoutHoursBefore, // From 2h before the release
+			MacroBlackoutHoursAfter:  DefaultMacroBlackoutHoursAfter,  // Until 1h after it
+
+			MaxHoldMinutes:            0,       // No holding time limit by default
+			MaxHoldAction:             "close", // Close immediately at the deadline
+			MaxHoldReviewGraceMinutes: 30,      // Review mode: close 30 min after deadline
+
 			UsePartialProfits: false, // Partial profits disabled by default
 			PartialProfitPct:  0.50,  // Take 50% at first target
 			PartialProfitR:    2.0,   // First target at 2R
 
-			CloseAtEOD:           true,    // Auto-close positions before market close (default: on for day-trade)
-			CloseAtEODTime:       "15:55", // 3:55 PM ET (5 min before close)
+			CloseAtEOD:      true,      // Auto-close positions before market close (default: on for day-trade)
+			CloseAtEODTime:  "15:55",   // 3:55 PM ET (5 min before close)
+			EODPolicy:       "flatten", // Close everything at EOD
+			EODReducePct:    50,        // "reduce": close half, hold half overnight
+			EODStopPct:      2,         // Overnight hard stop 2% from price
+			ShutdownPolicy:  "leave",   // Keep positions and their SL/TP on shutdown
+			ShutdownStopPct: 0.5,       // Tighten stops to 0.5% from price when policy is tighten-stops
 
 			UseMarketHoursFilter: true, // Market hours filter enabled
 			MarketOpenTime:       "09:30",
@@ -522,16 +1232,152 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			LimitOffsetATRMult:  0.5,   // 0.5 ATR offset from VWAP
 			LimitTimeoutSeconds: 5,     // 5 second timeout before market order
 
+			CalibrateLimitOffset: false, // Fixed ATR multiplier until fill statistics are trusted
+
+			LimitEntryExpiryMinutes: 60, // Cancel unfilled limit entries after 1 hour
+
 			EnableTWAP:          false, // Disabled by default (for large accounts)
 			TWAPDurationSeconds: 60,    // Spread over 60 seconds
 			TWAPMinSize:         50000, // Only for $50k+ orders
 			TWAPSliceCount:      6,     // 6 slices
 
 			PreferredOrderType: "market", // Market orders by default
+
+			PriceGuardBps:    0,               // Disabled by default (enable when data and execution venues differ)
+			PriceGuardAction: PriceGuardAbort, // Reject opens on a deviating venue
+
+			MaintenanceAction:        MaintenanceDefer, // No cycles during exchange maintenance
+			MaintenanceBufferMinutes: 5,
 		},
+		Memory: MemoryConfig{
+			Enabled:       false, // Disabled by default (needs closed trades to be useful)
+			TopK:          3,     // 3 similar setups per candidate
+			MinSimilarity: 0.75,  // Only reasonably similar setups
+		},
+		SessionMemory: SessionMemoryConfig{
+			Enabled:  false, // Disabled by default (one extra AI call per cycle)
+			Cycles:   6,
+			MaxChars: 1200,
+		},
+		Regime: RegimeConfig{
+			Enabled:             false,
+			IndexSymbols:        []string{"SPY", "QQQ"},
+			Timeframe:           "1h",
+			HighVolATRRatio:     1.5,  // Volatility 50% above its baseline
+			ChopBandPct:         0.15, // EMAs within 0.15% of price = no trend
+			ScaleRiskInHighVol:  false,
+			HighVolSizeScale:    0.5, // Half size in high volatility
+			HighVolMaxPositions: 0,
+		},
+		Shock: ShockConfig{
+			Enabled:       false,
+			IndexSymbols:  []string{"SPY", "BTCUSDT"},
+			MovePct:       3,  // 3% index move...
+			WindowMinutes: 30, // ...within 30 minutes
+			ATRZScore:     3,  // Or candidate volatility 3σ above normal
+			StopPct:       1,  // Stops pulled to 1% from price
+		},
+		EquityRisk: EquityRiskConfig{
+			Enabled:       false,
+			LookbackHours: 168, // Peak of the last 7 days
+			DrawdownPct:   5,   // Halve risk after a 5% drawdown
+			RecoveryPct:   2,   // Full risk again within 2% of the peak
+			RiskScale:     0.5,
+		},
+		Annotations: AnnotationFeedbackConfig{
+			Enabled:      false,
+			MaxItems:     10, // Ten most recent annotated trades
+			LookbackDays: 30,
+		},
+		ChurnGuard: ChurnGuardConfig{
+			Enabled:         false,
+			LookbackCycles:  3,
+			MinPriceMovePct: 1.0,
+			MinRSIChange:    15,
+			Action:          ChurnActionFlag,
+		},
+		TradeGovernor: TradeGovernorConfig{
+			Enabled:                  false,
+			MaxOpensPerHour:          4,
+			MaxOpensPerDay:           20,
+			MaxOpensPerSymbolPerHour: 1,
+			MaxOpensPerSymbolPerDay:  4,
+		},
+		ContextHooks: ContextHooksConfig{
+			Enabled:        false,
+			TimeoutSeconds: 10,
+		},
+		ToolCalling: ToolCallingConfig{
+			Enabled:  false,
+			MaxCalls: 6,
+		},
+		Ensemble: EnsembleConfig{
+			Enabled:   false,
+			MergeRule: "majority",
+		},
+		BatchRetry: BatchRetryConfig{
+			MaxRetries:  2,
+			BaseDelayMs: 1000,
+			MaxDelayMs:  8000,
+		},
+		DecisionParsing: DecisionParsingConfig{
+			Strictness:    ParseStrictnessLenient,
+			RepairEnabled: true,
+		},
+		AIResponseCache: AIResponseCacheConfig{
+			Enabled:          false,
+			FreshnessMinutes: 15,
+			PriceBucketPct:   0.5,
+		},
+		AILatency: AILatencyConfig{
+			SLASeconds: 0,
+			OnTimeout:  AITimeoutFallback,
+		},
+		CandidateRanking: CandidateRankingConfig{
+			Enabled:        false,
+			MaxCandidates:  0,
+			MomentumWeight: 1,
+			VolumeWeight:   1,
+			OIWeight:       1,
+			NewsWeight:     1,
+		},
+		PromptBudget: PromptBudgetConfig{
+			Enabled:   false,
+			MaxTokens: 24000, // Leaves room for system prompt and response in 32k-context models
+			MinBars:   10,
+		},
+		Benchmark: BenchmarkConfig{
+			Enabled: true,
+			Symbol:  DefaultBenchmarkSymbol,
+		},
+	}
+
+	if lang == PromptLanguageChinese {
+		config.Language = PromptLanguageChinese
+		config.PromptSections = PromptSectionsConfig{
+			RoleDefinition: `# 你是一名专业的股票交易 AI
+
+你的任务是根据提供的市场数据做出交易决策。你是一名经验丰富的量化交易员，擅长技术分析和风险管理。`,
+			TradingFrequency: `# ⏱️ 交易频率意识
+
+- 优秀交易者：每天 2-4 笔 ≈ 每小时 0.1-0.2 笔
+- 每小时 >2 笔 = 过度交易
+- 单笔持仓时间 ≥ 30-60 分钟
+如果你发现自己每个周期都在交易 → 标准太低；如果持仓不到 30 分钟就平仓 → 太急躁。`,
+			EntryStandards: `# 🎯 入场标准（严格）
+
+仅在多个信号共振时开仓。可自由使用任何有效的分析方法，避免单一指标、信号矛盾、横盘震荡、平仓后立即重新开仓等低质量行为。`,
+			DecisionProcess: `# 📋 决策流程
+
+1. 检查持仓 → 是否需要止盈/止损
+2. 扫描候选股票 + 多时间周期 → 是否存在强信号
+3. 先写思维链，再输出结构化 JSON`,
+		}
+		return config
 	}
 
-	// Use English stock trading prompts for all languages
+	// English stock trading prompts
+	config.Language = PromptLanguageEnglish
 	config.PromptSections = PromptSectionsConfig{
 		RoleDefinition: `# You are a professional stock trading AI
 
@@ -560,7 +1406,7 @@ func (s *StrategyStore) Create(strategy *Strategy) error {
 	_, err := s.db.Exec(`
 		INSERT INTO strategies (id, user_id, name, description, is_active, is_default, config)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, strategy.ID, strategy.UserID, strategy.Name, strategy.Description, strategy.IsActive, strategy.IsDefault, strategy.Config)
+	`, strategy.ID, strategy.UserID, strategy.Name, strategy.Description, strategy.IsActive, strategy.IsDefault, cryptConfig(strategy.Config, s.encryptFunc))
 	return err
 }
 
@@ -570,7 +1416,7 @@ func (s *StrategyStore) Update(strategy *Strategy) error {
 		UPDATE strategies SET
 			name = ?, description = ?, config = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ? AND user_id = ?
-	`, strategy.Name, strategy.Description, strategy.Config, strategy.ID, strategy.UserID)
+	`, strategy.Name, strategy.Description, cryptConfig(strategy.Config, s.encryptFunc), strategy.ID, strategy.UserID)
 	return err
 }
 
@@ -615,6 +1461,7 @@ func (s *StrategyStore) List(userID string) ([]*Strategy, error) {
 		}
 		st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 		st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		st.Config = cryptConfig(st.Config, s.decryptFunc)
 		strategies = append(strategies, &st)
 	}
 	return strategies, nil
@@ -638,6 +1485,29 @@ func (s *StrategyStore) Get(userID, id string) (*Strategy, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
+	return &st, nil
+}
+
+// GetByID get a single strategy regardless of owner (for unauthenticated callers such as webhooks)
+func (s *StrategyStore) GetByID(id string) (*Strategy, error) {
+	var st Strategy
+	var createdAt, updatedAt string
+	err := s.db.QueryRow(`
+		SELECT id, user_id, name, description, is_active, is_default, config, created_at, updated_at
+		FROM strategies
+		WHERE id = ?
+	`, id).Scan(
+		&st.ID, &st.UserID, &st.Name, &st.Description,
+		&st.IsActive, &st.IsDefault, &st.Config,
+		&createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -663,6 +1533,7 @@ func (s *StrategyStore) GetActive(userID string) (*Strategy, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -685,6 +1556,7 @@ func (s *StrategyStore) GetDefault() (*Strategy, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -766,6 +1638,9 @@ func (s *Strategy) ParseConfig() (*StrategyConfig, error) {
 	if err := json.Unmarshal([]byte(s.Config), &config); err != nil {
 		return nil, fmt.Errorf("failed to parse strategy configuration: %w", err)
 	}
+	if applied := config.Migrate(); len(applied) > 0 {
+		logger.Infof("🔄 Strategy %s config migrated: %s", s.Name, strings.Join(applied, ", "))
+	}
 	return &config, nil
 }
 
@@ -778,3 +1653,18 @@ func (s *Strategy) SetConfig(config *StrategyConfig) error {
 	s.Config = string(data)
 	return nil
 }
+
+// RedactSecrets replaces the webhook secret with a set/unset flag for API responses
+func (c *StrategyConfig) RedactSecrets() {
+	c.CoinSource.WebhookSecretSet = c.CoinSource.WebhookSecret != ""
+	c.CoinSource.WebhookSecret = ""
+}
+
+// RestoreSecrets keeps the stored webhook secret when a client submits a redacted config
+// existing is the stored config (nil on create); the response-only flag is cleared either way.
+func (c *StrategyConfig) RestoreSecrets(existing *StrategyConfig) {
+	if c.CoinSource.WebhookSecret == "" && existing != nil {
+		c.CoinSource.WebhookSecret = existing.CoinSource.WebhookSecret
+	}
+	c.CoinSource.WebhookSecretSet = false
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDrawdownRuleFor(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	off := false
+	on := true
+
+	tests := []struct {
+		name           string
+		rc             RiskControlConfig
+		symbol         string
+		wantEnabled    bool
+		wantActivation float64
+		wantClose      float64
+	}{
+		{"unset uses defaults", RiskControlConfig{}, "AAPL", true, DefaultDrawdownActivationPct, DefaultDrawdownClosePct},
+		{"global values", RiskControlConfig{DrawdownActivationPct: pct(8), DrawdownClosePct: pct(25)}, "AAPL", true, 8, 25},
+		{"explicit global zero", RiskControlConfig{DrawdownActivationPct: pct(0), DrawdownClosePct: pct(0)}, "AAPL", true, 0, 0},
+		{"global disabled", RiskControlConfig{DrawdownMonitorEnabled: &off}, "AAPL", false, DefaultDrawdownActivationPct, DefaultDrawdownClosePct},
+		{
+			"override replaces set fields only",
+			RiskControlConfig{DrawdownActivationPct: pct(8), DrawdownClosePct: pct(25), DrawdownOverrides: map[string]DrawdownRule{"TSLA": {ClosePct: pct(60)}}},
+			"TSLA", true, 8, 60,
+		},
+		{
+			"override explicit zero",
+			RiskControlConfig{DrawdownOverrides: map[string]DrawdownRule{"TSLA": {ActivationPct: pct(0), ClosePct: pct(0)}}},
+			"TSLA", true, 0, 0,
+		},
+		{
+			"override key matches case-insensitively and trimmed",
+			RiskControlConfig{DrawdownOverrides: map[string]DrawdownRule{" tsla ": {ActivationPct: pct(2)}}},
+			"TSLA", true, 2, DefaultDrawdownClosePct,
+		},
+		{
+			"override of another symbol ignored",
+			RiskControlConfig{DrawdownClosePct: pct(25), DrawdownOverrides: map[string]DrawdownRule{"TSLA": {ClosePct: pct(60), Enabled: &off}}},
+			"AAPL", true, DefaultDrawdownActivationPct, 25,
+		},
+		{
+			"override disables symbol",
+			RiskControlConfig{DrawdownOverrides: map[string]DrawdownRule{"TSLA": {Enabled: &off}}},
+			"TSLA", false, DefaultDrawdownActivationPct, DefaultDrawdownClosePct,
+		},
+		{
+			"override enables symbol when globally disabled",
+			RiskControlConfig{DrawdownMonitorEnabled: &off, DrawdownOverrides: map[string]DrawdownRule{"TSLA": {Enabled: &on, ClosePct: pct(30)}}},
+			"TSLA", true, DefaultDrawdownActivationPct, 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabled, activation, closePct := tt.rc.DrawdownRuleFor(tt.symbol)
+			if enabled != tt.wantEnabled || activation != tt.wantActivation || closePct != tt.wantClose {
+				t.Errorf("DrawdownRuleFor(%s) = %v, %v, %v, want %v, %v, %v",
+					tt.symbol, enabled, activation, closePct, tt.wantEnabled, tt.wantActivation, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestDrawdownRuleExplicitZeroSurvivesJSON(t *testing.T) {
+	raw := `{"drawdown_close_pct":0,"drawdown_overrides":{"TSLA":{"activation_pct":0}}}`
+	var rc RiskControlConfig
+	if err := json.Unmarshal([]byte(raw), &rc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, activation, closePct := rc.DrawdownRuleFor("TSLA"); activation != 0 || closePct != 0 {
+		t.Fatalf("explicit zeros resolved to %v / %v", activation, closePct)
+	}
+
+	data, err := json.Marshal(rc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back RiskControlConfig
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal round trip: %v", err)
+	}
+	if back.DrawdownClosePct == nil || back.DrawdownActivationPct != nil || back.DrawdownOverrides["TSLA"].ActivationPct == nil {
+		t.Errorf("round trip lost explicit zeros or invented values: %s", data)
+	}
+}
+
+func TestStrategyConfigSecretRedaction(t *testing.T) {
+	stored := StrategyConfig{CoinSource: CoinSourceConfig{SourceType: "webhook", WebhookSecret: "hook-secret"}}
+
+	redacted := stored
+	redacted.RedactSecrets()
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "hook-secret") || !redacted.CoinSource.WebhookSecretSet {
+		t.Fatalf("redacted config leaks the secret or lost the set flag: %s", data)
+	}
+
+	// Saving the redacted config back keeps the stored secret and drops the response-only flag
+	redacted.RestoreSecrets(&stored)
+	if redacted.CoinSource.WebhookSecret != "hook-secret" || redacted.CoinSource.WebhookSecretSet {
+		t.Errorf("RestoreSecrets = %+v, want stored secret without flag", redacted.CoinSource)
+	}
+
+	// A new secret replaces the stored one
+	changed := StrategyConfig{CoinSource: CoinSourceConfig{WebhookSecret: "new-secret"}}
+	changed.RestoreSecrets(&stored)
+	if changed.CoinSource.WebhookSecret != "new-secret" {
+		t.Errorf("RestoreSecrets overwrote a submitted secret with %q", changed.CoinSource.WebhookSecret)
+	}
+
+	// On create there is nothing to restore and the flag alone does not count as a secret
+	created := StrategyConfig{CoinSource: CoinSourceConfig{WebhookSecretSet: true}}
+	created.RestoreSecrets(nil)
+	if created.CoinSource.WebhookSecret != "" || created.CoinSource.WebhookSecretSet {
+		t.Errorf("RestoreSecrets(nil) = %+v, want empty secret without flag", created.CoinSource)
+	}
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Strategy Config Validation & Migration
+// ============================================================================
+// Validate rejects configurations that would otherwise only fail deep inside
+// a trading cycle (a "0m" timeframe, negative ratios, a static source without
+// symbols) and reports every problem as a *ConfigError naming the JSON field.
+// Checks that depend on trader behaviour (EOD and shutdown policies, grid
+// limits, supported exchange timeframes) stay in the trader, which runs
+// Validate first. Migrate rewrites the legacy crypto-era stock source fields
+// to their stock equivalents when a config is loaded (ParseConfig), so the
+// rest of the code reads one set of names.
+
+// ErrInvalidStrategyConfig matched by errors.Is for every strategy config validation error
+var ErrInvalidStrategyConfig = errors.New("invalid strategy config")
+
+// ConfigError invalid value of one strategy config field
+type ConfigError struct {
+	Field  string `json:"field"` // JSON path, e.g. "risk_control.max_margin_usage"
+	Reason string `json:"reason"`
+}
+
+func (e *ConfigError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+func (e *ConfigError) Unwrap() error {
+	return ErrInvalidStrategyConfig
+}
+
+// ConfigErrors all validation errors of a strategy config
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs ConfigErrors) Unwrap() []error {
+	wrapped := make([]error, len(errs))
+	for i, err := range errs {
+		wrapped[i] = err
+	}
+	return wrapped
+}
+
+// AsConfigErrors field errors of a strategy config validation error (other errors are reported on "config")
+func AsConfigErrors(err error) []*ConfigError {
+	var configErrs ConfigErrors
+	if errors.As(err, &configErrs) {
+		return configErrs
+	}
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		return []*ConfigError{configErr}
+	}
+	return []*ConfigError{{Field: "config", Reason: err.Error()}}
+}
+
+// Stock source types (CoinSourceConfig.SourceType)
+var validSourceTypes = map[string]bool{
+	"static": true, "coinpool": true, "stockpool": true, "ai100": true, "oi_top": true, "movers_top": true,
+	"top_winners": true, "top_losers": true, "funding_arb": true, "webhook": true, "social_trending": true, "mixed": true,
+}
+
+// reTimeframe kline timeframe format ("5m", "1h", "1d"), exchange support is checked by the trader
+var reTimeframe = regexp.MustCompile(`^([0-9]+)[mhdw]$`)
+
+// configValidator collects field errors
+type configValidator struct {
+	errs ConfigErrors
+}
+
+func (v *configValidator) fail(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ConfigError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *configValidator) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.fail(field, "cannot be negative (got %g)", value)
+	}
+}
+
+func (v *configValidator) between(field string, value, lo, hi float64) {
+	if value < lo || value > hi {
+		v.fail(field, "must be between %g and %g (got %g)", lo, hi, value)
+	}
+}
+
+// timeframe checks an optional timeframe ("" = default)
+func (v *configValidator) timeframe(field, tf string) {
+	if tf == "" {
+		return
+	}
+	match := reTimeframe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(tf)))
+	if match == nil {
+		v.fail(field, "must be a timeframe like 5m, 1h or 1d (got %q)", tf)
+	} else if strings.TrimLeft(match[1], "0") == "" {
+		v.fail(field, "cannot be a zero-length timeframe (got %q)", tf)
+	}
+}
+
+// Validate checks the config for values that break the trading loop
+// Returns ConfigErrors listing every invalid field, nil if the config is valid.
+func (c *StrategyConfig) Validate() error {
+	v := &configValidator{}
+
+	k := c.Indicators.Klines
+	v.timeframe("indicators.klines.primary_timeframe", k.PrimaryTimeframe)
+	v.timeframe("indicators.klines.longer_timeframe", k.LongerTimeframe)
+	for i, tf := range k.SelectedTimeframes {
+		if tf == "" {
+			v.fail(fmt.Sprintf("indicators.klines.selected_timeframes[%d]", i), "cannot be empty")
+		}
+		v.timeframe(fmt.Sprintf("indicators.klines.selected_timeframes[%d]", i), tf)
+	}
+	for i, tf := range c.Indicators.ConfluenceTimeframes {
+		v.timeframe(fmt.Sprintf("indicators.confluence_timeframes[%d]", i), tf)
+	}
+	v.nonNegative("indicators.klines.primary_count", float64(k.PrimaryCount))
+	v.nonNegative("indicators.klines.longer_count", float64(k.LongerCount))
+	v.nonNegative("indicators.klines.max_concurrent_fetches", float64(k.MaxConcurrentFetches))
+	v.between("indicators.klines.min_data_quality", k.MinDataQuality, 0, 1)
+
+	src := c.CoinSource
+	if src.SourceType != "" && !validSourceTypes[src.SourceType] {
+		v.fail("stock_source.source_type", "is not a known source type (got %q)", src.SourceType)
+	}
+	if src.SourceType == "static" && len(src.StaticStocks) == 0 && len(src.StaticCoins) == 0 {
+		v.fail("stock_source.static_stocks", "cannot be empty for the static source")
+	}
+	v.nonNegative("stock_source.coin_pool_limit", float64(src.CoinPoolLimit))
+	v.nonNegative("stock_source.stock_pool_limit", float64(src.StockPoolLimit))
+	v.nonNegative("stock_source.ai100_limit", float64(src.AI100Limit))
+	v.nonNegative("stock_source.oi_top_limit", float64(src.OITopLimit))
+	v.nonNegative("stock_source.movers_top_limit", float64(src.MoversTopLimit))
+	v.nonNegative("stock_source.top_losers_limit", float64(src.TopLosersLimit))
+	v.nonNegative("stock_source.funding_arb_limit", float64(src.FundingArbLimit))
+
+	rc := c.RiskControl
+	v.nonNegative("risk_control.max_positions", float64(rc.MaxPositions))
+	v.nonNegative("risk_control.large_cap_max_margin", float64(rc.LargeCapMaxMargin))
+	v.nonNegative("risk_control.small_cap_max_margin", float64(rc.SmallCapMaxMargin))
+	v.nonNegative("risk_control.large_cap_max_position_value_ratio", rc.LargeCapMaxPositionValueRatio)
+	v.nonNegative("risk_control.small_cap_max_position_value_ratio", rc.SmallCapMaxPositionValueRatio)
+	v.nonNegative("risk_control.max_position_size_usd", rc.MaxPositionSizeUSD)
+	v.nonNegative("risk_control.max_risk_per_trade_usd", rc.MaxRiskPerTradeUSD)
+	v.between("risk_control.max_risk_per_trade_pct", rc.MaxRiskPerTradePct, 0, 100)
+	v.nonNegative("risk_control.min_position_size", rc.MinPositionSize)
+	v.nonNegative("risk_control.large_cap_min_position_size", rc.LargeCapMinPositionSize)
+	if rc.MaxPositionSizeUSD > 0 && rc.MinPositionSize > rc.MaxPositionSizeUSD {
+		v.fail("risk_control.min_position_size", "(%.2f) exceeds max_position_size_usd (%.2f)", rc.MinPositionSize, rc.MaxPositionSizeUSD)
+	}
+	v.between("risk_control.max_margin_usage", rc.MaxMarginUsage, 0, 1)
+	v.between("risk_control.min_confidence", float64(rc.MinConfidence), 0, 100)
+	v.nonNegative("risk_control.min_risk_reward_ratio", rc.MinRiskRewardRatio)
+
+	v.nonNegative("execution.price_guard_bps", c.Execution.PriceGuardBps)
+	v.nonNegative("execution.maintenance_buffer_minutes", float64(c.Execution.MaintenanceBufferMinutes))
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Migrate moves legacy coin fields of the stock source to their stock equivalents
+// The legacy value wins where the old code read it (pool limit and API URL), the stock value elsewhere.
+// Returns one description per applied migration (empty if the config was current).
+func (c *StrategyConfig) Migrate() []string {
+	src := &c.CoinSource
+	var applied []string
+	if len(src.StaticCoins) > 0 {
+		if len(src.StaticStocks) == 0 {
+			src.StaticStocks = src.StaticCoins
+		}
+		src.StaticCoins = nil
+		applied = append(applied, "static_coins → static_stocks")
+	}
+	if src.UseCoinPool {
+		src.UseStockPool, src.UseCoinPool = true, false
+		applied = append(applied, "use_coin_pool → use_stock_pool")
+	}
+	if src.CoinPoolLimit != 0 {
+		src.StockPoolLimit, src.CoinPoolLimit = src.CoinPoolLimit, 0
+		applied = append(applied, "coin_pool_limit → stock_pool_limit")
+	}
+	if src.CoinPoolAPIURL != "" {
+		src.StockPoolAPIURL, src.CoinPoolAPIURL = src.CoinPoolAPIURL, ""
+		applied = append(applied, "coin_pool_api_url → stock_pool_api_url")
+	}
+	if src.SourceType == "coinpool" {
+		src.SourceType = "stockpool"
+		applied = append(applied, "source_type coinpool → stockpool")
+	}
+	return applied
+}
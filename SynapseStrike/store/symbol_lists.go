@@ -0,0 +1,149 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// maxSymbolListEntries upper bound per list, keeps the per-candidate check cheap
+const maxSymbolListEntries = 500
+
+// SymbolLists per-trader allow/deny lists of tradable symbols
+// Entries are symbols ("BTCUSDT") or glob patterns ("*PEPE*", "1000*"); matching is case-insensitive
+// and also compares base assets, so "BTC" covers BTCUSDT, BTCUSDC, BTC-PERP and BTC/USDT.
+// Deny takes precedence over Allow; an empty Allow permits every symbol not denied.
+// Lists only gate new entries: existing positions can always be closed or have their stops moved.
+type SymbolLists struct {
+	Allow []string `json:"allow,omitempty"` // Curated watchlist (empty = any symbol)
+	Deny  []string `json:"deny,omitempty"`  // Never trade these
+}
+
+// Normalize uppercases, trims and deduplicates entries in place
+func (l *SymbolLists) Normalize() {
+	if l == nil {
+		return
+	}
+	l.Allow = normalizeSymbolEntries(l.Allow)
+	l.Deny = normalizeSymbolEntries(l.Deny)
+}
+
+// Validate checks list sizes and glob syntax
+func (l *SymbolLists) Validate() error {
+	if l == nil {
+		return nil
+	}
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{{"allow", l.Allow}, {"deny", l.Deny}} {
+		name, entries := list.name, list.entries
+		if len(entries) > maxSymbolListEntries {
+			return fmt.Errorf("symbol %s list has %d entries (max %d)", name, len(entries), maxSymbolListEntries)
+		}
+		for _, entry := range entries {
+			if strings.TrimSpace(entry) == "" {
+				return fmt.Errorf("symbol %s list contains an empty entry", name)
+			}
+			if _, err := path.Match(strings.ToUpper(entry), ""); err != nil {
+				return fmt.Errorf("invalid symbol %s pattern %q: %w", name, entry, err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsEmpty whether the lists impose no restriction
+func (l *SymbolLists) IsEmpty() bool {
+	return l == nil || (len(l.Allow) == 0 && len(l.Deny) == 0)
+}
+
+// Check reports whether the symbol may be traded, with the reason when it may not
+func (l *SymbolLists) Check(symbol string) (bool, string) {
+	if l.IsEmpty() {
+		return true, ""
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if entry, ok := matchSymbolEntry(l.Deny, symbol); ok {
+		return false, fmt.Sprintf("%s is on the deny list (%s)", symbol, entry)
+	}
+	if len(l.Allow) > 0 {
+		if _, ok := matchSymbolEntry(l.Allow, symbol); !ok {
+			return false, fmt.Sprintf("%s is not on the allow list", symbol)
+		}
+	}
+	return true, ""
+}
+
+// matchSymbolEntry returns the first entry matching symbol or its base asset
+func matchSymbolEntry(entries []string, symbol string) (string, bool) {
+	base := symbolListBase(symbol)
+	for _, entry := range entries {
+		pattern := strings.ToUpper(strings.TrimSpace(entry))
+		if pattern == symbol || symbolListBase(pattern) == base {
+			return entry, true
+		}
+		for _, name := range []string{symbol, base} {
+			if ok, err := path.Match(pattern, name); err == nil && ok {
+				return entry, true
+			}
+		}
+	}
+	return "", false
+}
+
+// symbolListQuoteSuffixes quote/contract suffixes stripped to get the base asset (longest first)
+var symbolListQuoteSuffixes = []string{"/USDT", "/USDC", "-PERP", "USDT", "USDC"}
+
+// symbolListBase base asset of an uppercased symbol ("BTCUSDT", "BTC-PERP", "BTC/USDT" -> "BTC")
+// Mirrors the quote stripping of the market symbol registry (store cannot import market); stock tickers are unchanged.
+func symbolListBase(symbol string) string {
+	for _, suffix := range symbolListQuoteSuffixes {
+		if base := strings.TrimSuffix(symbol, suffix); base != symbol && base != "" {
+			return base
+		}
+	}
+	return symbol
+}
+
+func normalizeSymbolEntries(entries []string) []string {
+	if len(entries) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(entries))
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToUpper(strings.TrimSpace(entry))
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		result = append(result, entry)
+	}
+	return result
+}
+
+// encodeSymbolLists serializes lists for storage (empty string = none)
+func encodeSymbolLists(l *SymbolLists) string {
+	if l.IsEmpty() {
+		return ""
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeSymbolLists parses stored lists (nil if empty or invalid)
+func decodeSymbolLists(data string) *SymbolLists {
+	if data == "" {
+		return nil
+	}
+	var l SymbolLists
+	if err := json.Unmarshal([]byte(data), &l); err != nil {
+		return nil
+	}
+	return &l
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSymbolListsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		lists   *SymbolLists
+		symbol  string
+		allowed bool
+		reason  string
+	}{
+		{"nil lists allow all", nil, "BTCUSDT", true, ""},
+		{"empty allow list means all", &SymbolLists{Deny: []string{"DOGEUSDT"}}, "BTCUSDT", true, ""},
+		{"empty allow list still applies deny", &SymbolLists{Deny: []string{"DOGEUSDT"}}, "DOGEUSDT", false, "deny list (DOGEUSDT)"},
+		{"allow list", &SymbolLists{Allow: []string{"BTCUSDT", "ETHUSDT"}}, "ETHUSDT", true, ""},
+		{"not on allow list", &SymbolLists{Allow: []string{"BTCUSDT", "ETHUSDT"}}, "SOLUSDT", false, "not on the allow list"},
+		{"deny beats allow", &SymbolLists{Allow: []string{"BTCUSDT", "ETHUSDT"}, Deny: []string{"ETHUSDT"}}, "ETHUSDT", false, "deny list"},
+		{"deny pattern beats allow pattern", &SymbolLists{Allow: []string{"*USDT"}, Deny: []string{"*PEPE*"}}, "1000PEPEUSDT", false, "deny list (*PEPE*)"},
+		{"allow pattern", &SymbolLists{Allow: []string{"*USDT"}, Deny: []string{"*PEPE*"}}, "SOLUSDT", true, ""},
+
+		{"case-insensitive and trimmed", &SymbolLists{Allow: []string{" btcusdt "}}, "  BtcUsdt", true, ""},
+		{"base entry covers USDT pair", &SymbolLists{Deny: []string{"BTC"}}, "BTCUSDT", false, "deny list (BTC)"},
+		{"base entry covers USDC pair", &SymbolLists{Allow: []string{"BTC"}}, "BTCUSDC", true, ""},
+		{"base entry covers perp", &SymbolLists{Allow: []string{"BTC"}}, "BTC-PERP", true, ""},
+		{"base entry covers slash pair", &SymbolLists{Deny: []string{"btc"}}, "btc/usdt", false, "deny list"},
+		{"pair entry covers base symbol", &SymbolLists{Allow: []string{"BTCUSDT"}}, "BTC", true, ""},
+		{"pair entry covers other quote", &SymbolLists{Deny: []string{"ETHUSDT"}}, "ETH/USDC", false, "deny list (ETHUSDT)"},
+		{"pattern matches base symbol", &SymbolLists{Allow: []string{"BTC*"}}, "BTC", true, ""},
+		{"base does not match longer ticker", &SymbolLists{Deny: []string{"BTC"}}, "WBTCUSDT", true, ""},
+		{"stock tickers compare as is", &SymbolLists{Allow: []string{"AAPL"}}, "AAP", false, "not on the allow list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := tt.lists.Check(tt.symbol)
+			if allowed != tt.allowed {
+				t.Fatalf("Check(%q) allowed = %v (%s), want %v", tt.symbol, allowed, reason, tt.allowed)
+			}
+			if !strings.Contains(reason, tt.reason) || (tt.reason == "") != (reason == "") {
+				t.Errorf("Check(%q) reason = %q, want it to contain %q", tt.symbol, reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestSymbolListsNormalizeAndValidate(t *testing.T) {
+	lists := &SymbolLists{Allow: []string{" btcusdt", "BTCUSDT", "", "eth*"}, Deny: []string{"doge "}}
+	lists.Normalize()
+	if got := strings.Join(lists.Allow, ","); got != "BTCUSDT,ETH*" {
+		t.Errorf("normalized allow = %s", got)
+	}
+	if got := strings.Join(lists.Deny, ","); got != "DOGE" {
+		t.Errorf("normalized deny = %s", got)
+	}
+	if err := lists.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	if !(&SymbolLists{}).IsEmpty() || (&SymbolLists{Deny: []string{"X"}}).IsEmpty() {
+		t.Error("IsEmpty mismatch")
+	}
+	if err := (&SymbolLists{Deny: []string{"[BTC"}}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid symbol deny pattern") {
+		t.Errorf("Validate() with bad pattern = %v", err)
+	}
+	if err := (&SymbolLists{Allow: []string{" "}}).Validate(); err == nil || !strings.Contains(err.Error(), "empty entry") {
+		t.Errorf("Validate() with empty entry = %v", err)
+	}
+}
@@ -1,14 +1,18 @@
 package store
 
 import (
+	"SynapseStrike/logger"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
 type TacticStore struct {
-	db *sql.DB
+	db          *sql.DB
+	encryptFunc func(string) string
+	decryptFunc func(string) string
 }
 
 type Tactic struct {
@@ -76,7 +80,7 @@ func (s *TacticStore) Create(tactic *Tactic) error {
 	_, err := s.db.Exec(`
 		INSERT INTO tactics (id, user_id, name, description, strategy_type, is_active, is_default, config)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, tactic.ID, tactic.UserID, tactic.Name, tactic.Description, strategyType, tactic.IsActive, tactic.IsDefault, tactic.Config)
+	`, tactic.ID, tactic.UserID, tactic.Name, tactic.Description, strategyType, tactic.IsActive, tactic.IsDefault, cryptConfig(tactic.Config, s.encryptFunc))
 	return err
 }
 
@@ -85,7 +89,7 @@ func (s *TacticStore) Update(tactic *Tactic) error {
 		UPDATE tactics SET
 			name = ?, description = ?, strategy_type = ?, config = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ? AND user_id = ?
-	`, tactic.Name, tactic.Description, tactic.StrategyType, tactic.Config, tactic.ID, tactic.UserID)
+	`, tactic.Name, tactic.Description, tactic.StrategyType, cryptConfig(tactic.Config, s.encryptFunc), tactic.ID, tactic.UserID)
 	return err
 }
 
@@ -120,6 +124,7 @@ func (s *TacticStore) List(userID string) ([]*Tactic, error) {
 		}
 		st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 		st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		st.Config = cryptConfig(st.Config, s.decryptFunc)
 		tactics = append(tactics, &st)
 	}
 	return tactics, nil
@@ -138,6 +143,25 @@ func (s *TacticStore) Get(userID, id string) (*Tactic, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
+	return &st, nil
+}
+
+// GetByID get a single tactic regardless of owner (for unauthenticated callers such as webhooks)
+func (s *TacticStore) GetByID(id string) (*Tactic, error) {
+	var st Tactic
+	var createdAt, updatedAt string
+	err := s.db.QueryRow(`
+		SELECT id, user_id, name, description, COALESCE(strategy_type, 'sonnet'), is_active, is_default, config, created_at, updated_at
+		FROM tactics
+		WHERE id = ?
+	`, id).Scan(&st.ID, &st.UserID, &st.Name, &st.Description, &st.StrategyType, &st.IsActive, &st.IsDefault, &st.Config, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -157,6 +181,7 @@ func (s *TacticStore) GetActive(userID string) (*Tactic, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -174,6 +199,7 @@ func (s *TacticStore) GetDefault() (*Tactic, error) {
 	}
 	st.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	st.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	st.Config = cryptConfig(st.Config, s.decryptFunc)
 	return &st, nil
 }
 
@@ -225,6 +251,9 @@ func (s *Tactic) ParseConfig() (*TacticConfig, error) {
 	if err := json.Unmarshal([]byte(s.Config), &config); err != nil {
 		return nil, fmt.Errorf("failed to parse tactic configuration: %w", err)
 	}
+	if applied := config.Migrate(); len(applied) > 0 {
+		logger.Infof("🔄 Tactic %s config migrated: %s", s.Name, strings.Join(applied, ", "))
+	}
 	return &config, nil
 }
 
@@ -0,0 +1,221 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ============================================================================
+// Tenant Isolation
+// ============================================================================
+// Configuration tables (ai_models, exchanges, traders, strategies, ...) carry
+// user_id; trader data tables are keyed by trader_id. The high-volume trader
+// tables below also carry the owning user_id, stamped from the traders table
+// by an insert trigger (so writers stay unchanged) and backfilled for rows
+// written before the column existed. Handlers resolve trader IDs through
+// AuthorizeTrader and read through ForUser-scoped decision, position and
+// equity stores, whose queries also filter on user_id, so no user can read
+// another user's decisions, positions or equity. ExportTenant splits one user into a standalone database file for
+// per-tenant deployments; TenantAudit reports rows that cannot be attributed.
+
+// ErrTraderNotOwned trader does not exist or belongs to another user
+var ErrTraderNotOwned = errors.New("trader does not exist")
+
+// tenantDataTables trader data tables stamped with the owning user_id
+var tenantDataTables = []string{
+	"decision_records", "trader_positions", "trader_equity_snapshots",
+	"decision_memories", "execution_queue", "grid_legs", "order_fills", "trade_annotations",
+}
+
+// tenantOwned WHERE predicate of scoped reads, bound to the store's userID twice
+// (an empty userID, used by the trader runtime and public competition reads, matches every row)
+const tenantOwned = `(? = '' OR user_id = ?)`
+
+// TenantTableAudit ownership audit result of one table
+type TenantTableAudit struct {
+	Table      string `json:"table"`
+	Rows       int64  `json:"rows"`
+	Unowned    int64  `json:"unowned"`    // No user_id (trader deleted)
+	Mismatched int64  `json:"mismatched"` // user_id differs from the trader's current owner
+}
+
+// initTenantColumns adds, backfills and auto-stamps user_id on trader data tables
+func (s *Store) initTenantColumns() error {
+	for _, table := range tenantDataTables {
+		// Ignore error (column may already exist)
+		s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN user_id TEXT NOT NULL DEFAULT ''`, table))
+
+		if _, err := s.db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_user ON %s(user_id, trader_id)`, table, table)); err != nil {
+			return fmt.Errorf("failed to create %s user index: %w", table, err)
+		}
+
+		_, err := s.db.Exec(fmt.Sprintf(`
+			CREATE TRIGGER IF NOT EXISTS %s_stamp_user AFTER INSERT ON %s
+			WHEN NEW.user_id = ''
+			BEGIN
+				UPDATE %s SET user_id = COALESCE((SELECT user_id FROM traders WHERE id = NEW.trader_id), '')
+				WHERE id = NEW.id;
+			END
+		`, table, table, table))
+		if err != nil {
+			return fmt.Errorf("failed to create %s user trigger: %w", table, err)
+		}
+
+		_, err = s.db.Exec(fmt.Sprintf(`
+			UPDATE %s SET user_id = (SELECT user_id FROM traders WHERE traders.id = %s.trader_id)
+			WHERE user_id = '' AND trader_id IN (SELECT id FROM traders)
+		`, table, table))
+		if err != nil {
+			return fmt.Errorf("failed to backfill %s user_id: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// AuthorizeTrader returns ErrTraderNotOwned unless traderID belongs to userID
+func (s *Store) AuthorizeTrader(userID, traderID string) error {
+	if userID == "" || traderID == "" {
+		return ErrTraderNotOwned
+	}
+	var owner string
+	err := s.db.QueryRow(`SELECT user_id FROM traders WHERE id = ?`, traderID).Scan(&owner)
+	if err == sql.ErrNoRows || (err == nil && owner != userID) {
+		return ErrTraderNotOwned
+	}
+	return err
+}
+
+// TenantAudit counts rows of the trader data tables that cannot be attributed to their trader's owner
+func (s *Store) TenantAudit() ([]TenantTableAudit, error) {
+	audits := make([]TenantTableAudit, 0, len(tenantDataTables))
+	for _, table := range tenantDataTables {
+		a := TenantTableAudit{Table: table}
+		err := s.db.QueryRow(fmt.Sprintf(`
+			SELECT COUNT(*),
+			       COALESCE(SUM(CASE WHEN t.user_id = '' THEN 1 ELSE 0 END), 0),
+			       COALESCE(SUM(CASE WHEN t.user_id != '' AND tr.user_id IS NOT NULL AND t.user_id != tr.user_id THEN 1 ELSE 0 END), 0)
+			FROM %s t LEFT JOIN traders tr ON tr.id = t.trader_id
+		`, table)).Scan(&a.Rows, &a.Unowned, &a.Mismatched)
+		if err != nil {
+			return nil, fmt.Errorf("failed to audit %s: %w", table, err)
+		}
+		audits = append(audits, a)
+	}
+	return audits, nil
+}
+
+// ExportTenant writes a copy of the database containing only userID's data to path,
+// returning the rows kept per table. Shared market data tables are copied as is.
+func (s *Store) ExportTenant(userID, path string) (map[string]int64, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	if _, err := s.db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return nil, fmt.Errorf("failed to copy database: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open copy: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	tables, err := tenantTableColumns(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parents first: rows owned by user_id, then children keyed by trader, run and session
+	for table, columns := range tables {
+		var query string
+		switch {
+		case table == "users":
+			query = `DELETE FROM users WHERE id != ?`
+		case columns["user_id"]:
+			query = fmt.Sprintf(`DELETE FROM %s WHERE user_id != ?`, table)
+		default:
+			continue
+		}
+		if _, err := db.Exec(query, userID); err != nil {
+			return nil, fmt.Errorf("failed to filter %s: %w", table, err)
+		}
+	}
+	children := []struct{ column, parent, parentKey string }{
+		{"trader_id", "traders", "id"},
+		{"run_id", "backtest_runs", "run_id"},
+		{"session_id", "debate_sessions", "id"},
+	}
+	for _, child := range children {
+		if tables[child.parent] == nil {
+			continue
+		}
+		for table, columns := range tables {
+			if table == child.parent || columns["user_id"] || !columns[child.column] {
+				continue
+			}
+			query := fmt.Sprintf(`DELETE FROM %s WHERE %s NOT IN (SELECT %s FROM %s)`, table, child.column, child.parentKey, child.parent)
+			if _, err := db.Exec(query); err != nil {
+				return nil, fmt.Errorf("failed to filter %s: %w", table, err)
+			}
+		}
+	}
+
+	// Rewrite the file so deleted rows of other tenants do not linger in free pages
+	if _, err := db.Exec(`VACUUM`); err != nil {
+		return nil, fmt.Errorf("failed to compact copy: %w", err)
+	}
+
+	kept := make(map[string]int64, len(tables))
+	for table := range tables {
+		var n int64
+		if err := db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, table)).Scan(&n); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		kept[table] = n
+	}
+	return kept, nil
+}
+
+// tenantTableColumns column names of every user table
+func tenantTableColumns(db *sql.DB) (map[string]map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	tables := make(map[string]map[string]bool, len(names))
+	for _, name := range names {
+		cols, err := db.Query(fmt.Sprintf(`SELECT name FROM pragma_table_info('%s')`, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s columns: %w", name, err)
+		}
+		columns := make(map[string]bool)
+		for cols.Next() {
+			var col string
+			if err := cols.Scan(&col); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			columns[col] = true
+		}
+		cols.Close()
+		tables[name] = columns
+	}
+	return tables, nil
+}
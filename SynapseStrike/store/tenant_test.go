@@ -0,0 +1,213 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTenantTestStore opens a store with users alice and bob owning traders t-alice and t-bob
+func newTenantTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	for _, user := range []string{"alice", "bob"} {
+		if err := s.User().Create(&User{ID: user, Email: user + "@example.com"}); err != nil {
+			t.Fatalf("failed to create user %s: %v", user, err)
+		}
+		if err := s.Trader().Create(&Trader{ID: "t-" + user, UserID: user, Name: user}); err != nil {
+			t.Fatalf("failed to create trader of %s: %v", user, err)
+		}
+	}
+	return s
+}
+
+// ownerOf user_id stamped on the newest row of a trader in table
+func ownerOf(t *testing.T, s *Store, table, traderID string) string {
+	t.Helper()
+	var owner string
+	err := s.db.QueryRow(`SELECT user_id FROM `+table+` WHERE trader_id = ? ORDER BY id DESC LIMIT 1`, traderID).Scan(&owner)
+	if err != nil {
+		t.Fatalf("failed to read %s owner: %v", table, err)
+	}
+	return owner
+}
+
+func TestTenantInsertTriggerStampsOwner(t *testing.T) {
+	s := newTenantTestStore(t)
+
+	for _, traderID := range []string{"t-alice", "t-bob"} {
+		if err := s.Decision().LogDecision(&DecisionRecord{TraderID: traderID, CycleNumber: 1, Success: true}); err != nil {
+			t.Fatalf("LogDecision: %v", err)
+		}
+		if err := s.Position().Create(&TraderPosition{TraderID: traderID, Symbol: "AAPL", Side: "LONG", Quantity: 1, EntryPrice: 100, Status: "OPEN"}); err != nil {
+			t.Fatalf("Create position: %v", err)
+		}
+		if err := s.Equity().Save(&EquitySnapshot{TraderID: traderID, Timestamp: time.Now(), TotalEquity: 1000}); err != nil {
+			t.Fatalf("Save equity: %v", err)
+		}
+		if err := s.Memory().Save(&DecisionMemory{TraderID: traderID, Symbol: "AAPL", Action: "open_long"}); err != nil {
+			t.Fatalf("Save memory: %v", err)
+		}
+	}
+
+	for _, table := range []string{"decision_records", "trader_positions", "trader_equity_snapshots", "decision_memories"} {
+		if got := ownerOf(t, s, table, "t-alice"); got != "alice" {
+			t.Errorf("%s owner of t-alice = %q, want alice", table, got)
+		}
+		if got := ownerOf(t, s, table, "t-bob"); got != "bob" {
+			t.Errorf("%s owner of t-bob = %q, want bob", table, got)
+		}
+	}
+
+	// Scoped reads filter on user_id as well as trader_id
+	tests := []struct {
+		user string
+		want int
+	}{
+		{"", 1},      // Unscoped (trader runtime)
+		{"alice", 1}, // Owner
+		{"bob", 0},   // Other tenant
+	}
+	for _, tt := range tests {
+		records, err := s.Decision().ForUser(tt.user).GetLatestRecords("t-alice", 10)
+		if err != nil || len(records) != tt.want {
+			t.Errorf("decisions of t-alice for %q = %d (%v), want %d", tt.user, len(records), err, tt.want)
+		}
+		positions, err := s.Position().ForUser(tt.user).GetOpenPositions("t-alice")
+		if err != nil || len(positions) != tt.want {
+			t.Errorf("positions of t-alice for %q = %d (%v), want %d", tt.user, len(positions), err, tt.want)
+		}
+		snapshots, err := s.Equity().ForUser(tt.user).GetLatest("t-alice", 10)
+		if err != nil || len(snapshots) != tt.want {
+			t.Errorf("equity of t-alice for %q = %d (%v), want %d", tt.user, len(snapshots), err, tt.want)
+		}
+	}
+}
+
+func TestTenantBackfill(t *testing.T) {
+	s := newTenantTestStore(t)
+
+	// Written before its trader row exists, like data of traders predating the user_id column
+	if err := s.Equity().Save(&EquitySnapshot{TraderID: "t-carol", Timestamp: time.Now(), TotalEquity: 1000}); err != nil {
+		t.Fatalf("Save equity: %v", err)
+	}
+	if got := ownerOf(t, s, "trader_equity_snapshots", "t-carol"); got != "" {
+		t.Fatalf("owner before trader exists = %q, want empty", got)
+	}
+	audits, err := s.TenantAudit()
+	if err != nil {
+		t.Fatalf("TenantAudit: %v", err)
+	}
+	for _, a := range audits {
+		if a.Table == "trader_equity_snapshots" && a.Unowned != 1 {
+			t.Errorf("unowned equity rows = %d, want 1", a.Unowned)
+		}
+	}
+
+	if err := s.Trader().Create(&Trader{ID: "t-carol", UserID: "carol", Name: "carol"}); err != nil {
+		t.Fatalf("failed to create trader: %v", err)
+	}
+	if err := s.initTenantColumns(); err != nil {
+		t.Fatalf("initTenantColumns: %v", err)
+	}
+	if got := ownerOf(t, s, "trader_equity_snapshots", "t-carol"); got != "carol" {
+		t.Errorf("owner after backfill = %q, want carol", got)
+	}
+	audits, err = s.TenantAudit()
+	if err != nil {
+		t.Fatalf("TenantAudit: %v", err)
+	}
+	for _, a := range audits {
+		if a.Unowned != 0 || a.Mismatched != 0 {
+			t.Errorf("%s after backfill: unowned %d, mismatched %d", a.Table, a.Unowned, a.Mismatched)
+		}
+	}
+}
+
+func TestAuthorizeTrader(t *testing.T) {
+	s := newTenantTestStore(t)
+
+	tests := []struct {
+		name     string
+		userID   string
+		traderID string
+		wantErr  bool
+	}{
+		{"owner", "alice", "t-alice", false},
+		{"other user", "bob", "t-alice", true},
+		{"unknown trader", "alice", "t-nobody", true},
+		{"anonymous", "", "t-alice", true},
+		{"no trader", "alice", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.AuthorizeTrader(tt.userID, tt.traderID)
+			if tt.wantErr && !errors.Is(err, ErrTraderNotOwned) {
+				t.Errorf("AuthorizeTrader(%q, %q) = %v, want ErrTraderNotOwned", tt.userID, tt.traderID, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("AuthorizeTrader(%q, %q) = %v, want nil", tt.userID, tt.traderID, err)
+			}
+		})
+	}
+}
+
+func TestExportTenant(t *testing.T) {
+	s := newTenantTestStore(t)
+	for _, traderID := range []string{"t-alice", "t-bob"} {
+		if err := s.Decision().LogDecision(&DecisionRecord{TraderID: traderID, CycleNumber: 1}); err != nil {
+			t.Fatalf("LogDecision: %v", err)
+		}
+		if err := s.Equity().Save(&EquitySnapshot{TraderID: traderID, Timestamp: time.Now(), TotalEquity: 1000}); err != nil {
+			t.Fatalf("Save equity: %v", err)
+		}
+		if err := s.RuntimeState().Save(&TraderRuntimeState{TraderID: traderID}); err != nil {
+			t.Fatalf("Save runtime state: %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "alice.db")
+	kept, err := s.ExportTenant("alice", path)
+	if err != nil {
+		t.Fatalf("ExportTenant: %v", err)
+	}
+	for table, want := range map[string]int64{
+		"users": 1, "traders": 1, "decision_records": 1, "trader_equity_snapshots": 1, "trader_runtime_state": 1,
+	} {
+		if kept[table] != want {
+			t.Errorf("kept %s = %d, want %d", table, kept[table], want)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open export: %v", err)
+	}
+	defer db.Close()
+	var foreign int
+	err = db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM traders WHERE id != 't-alice')
+		     + (SELECT COUNT(*) FROM decision_records WHERE trader_id != 't-alice')
+		     + (SELECT COUNT(*) FROM trader_runtime_state WHERE trader_id != 't-alice')
+	`).Scan(&foreign)
+	if err != nil {
+		t.Fatalf("failed to query export: %v", err)
+	}
+	if foreign != 0 {
+		t.Errorf("export contains %d rows of other tenants", foreign)
+	}
+
+	if _, err := s.ExportTenant("alice", path); err == nil {
+		t.Error("ExportTenant should refuse to overwrite an existing file")
+	}
+	if _, err := s.ExportTenant("", filepath.Join(t.TempDir(), "all.db")); err == nil {
+		t.Error("ExportTenant should require a user id")
+	}
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Trade Excursions (MFE / MAE)
+// ============================================================================
+// While a position is open the trader's position monitor records its maximum
+// favorable excursion (MFE, best unrealized price move) and maximum adverse
+// excursion (MAE, worst unrealized price move) in percent of the entry price,
+// unleveraged, in the mfe_pct / mae_pct columns. Closed trades keep them, and
+// ExcursionStats turns them into post-hoc entry quality metrics: how much heat
+// winners took before working, how much of the favorable move exits captured
+// and whether losers were cut at their worst point. Trades closed before
+// tracking started (imports, exchange sync) have no excursions and are
+// excluded.
+
+// ExcursionStats entry/exit quality aggregates of closed trades with tracked excursions
+type ExcursionStats struct {
+	TrackedTrades     int     `json:"tracked_trades"`      // Closed trades with MFE/MAE
+	AvgMFEPct         float64 `json:"avg_mfe_pct"`         // Average maximum favorable excursion (% of entry price)
+	AvgMAEPct         float64 `json:"avg_mae_pct"`         // Average maximum adverse excursion (% of entry price, positive)
+	AvgMAEWinnersPct  float64 `json:"avg_mae_winners_pct"` // Average heat winners took before working
+	MaxMAEWinnersPct  float64 `json:"max_mae_winners_pct"` // Deepest heat a winner recovered from
+	AvgMFELosersPct   float64 `json:"avg_mfe_losers_pct"`  // Average open profit losers gave back
+	ExitEfficiencyPct float64 `json:"exit_efficiency_pct"` // Winners: realized move / MFE (share of the best move captured)
+	StopEfficiencyPct float64 `json:"stop_efficiency_pct"` // Losers: realized loss / MAE (100 = cut at the worst point)
+	AvgEntryQuality   float64 `json:"avg_entry_quality"`   // Average MFE / (MFE + MAE) × 100 (100 = never went against the entry)
+}
+
+// tradeExcursion price excursions of one closed trade
+type tradeExcursion struct {
+	Side       string // long/short
+	EntryPrice float64
+	ExitPrice  float64
+	PnL        float64 // Realized PnL (sign decides winner/loser)
+	MFEPct     float64
+	MAEPct     float64
+}
+
+// UpdateExcursion raises the recorded MFE/MAE of an open position to at least the given values
+// Returns false if the position is no longer open.
+func (s *PositionStore) UpdateExcursion(id int64, mfePct, maePct float64) (bool, error) {
+	result, err := s.db.Exec(`
+		UPDATE trader_positions
+		SET mfe_pct = MAX(COALESCE(mfe_pct, 0), ?), mae_pct = MAX(COALESCE(mae_pct, 0), ?)
+		WHERE id = ? AND status = 'OPEN'
+	`, mfePct, maePct, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update excursion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// GetExcursion gets the recorded MFE/MAE of a position (0, 0 if never tracked)
+func (s *PositionStore) GetExcursion(id int64) (mfePct, maePct float64, err error) {
+	err = s.db.QueryRow(`
+		SELECT COALESCE(mfe_pct, 0), COALESCE(mae_pct, 0) FROM trader_positions WHERE id = ?
+	`, id).Scan(&mfePct, &maePct)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	return mfePct, maePct, err
+}
+
+// GetExcursionStats gets entry/exit quality of the trader's closed trades (since zero = all)
+func (s *PositionStore) GetExcursionStats(traderID string, since time.Time) (*ExcursionStats, error) {
+	rows, err := s.db.Query(`
+		SELECT side, entry_price, COALESCE(exit_price, 0), COALESCE(realized_pnl, 0),
+			COALESCE(mfe_pct, 0), COALESCE(mae_pct, 0), exit_time
+		FROM trader_positions
+		WHERE trader_id = ? AND `+tenantOwned+` AND status = 'CLOSED' AND (COALESCE(mfe_pct, 0) > 0 OR COALESCE(mae_pct, 0) > 0)
+	`, traderID, s.userID, s.userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade excursions: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []tradeExcursion
+	for rows.Next() {
+		var t tradeExcursion
+		var exitTime sql.NullString
+		if err := rows.Scan(&t.Side, &t.EntryPrice, &t.ExitPrice, &t.PnL, &t.MFEPct, &t.MAEPct, &exitTime); err != nil {
+			continue
+		}
+		if !since.IsZero() && exitTime.Valid {
+			if exit, err := time.Parse(time.RFC3339, exitTime.String); err == nil && exit.Before(since) {
+				continue
+			}
+		}
+		t.Side = strings.ToLower(t.Side)
+		trades = append(trades, t)
+	}
+	return calculateExcursionStats(trades), nil
+}
+
+// calculateExcursionStats aggregates trade excursions
+// The exit itself is an excursion point: a fill beyond the extremes seen by the monitor widens them.
+func calculateExcursionStats(trades []tradeExcursion) *ExcursionStats {
+	stats := &ExcursionStats{}
+	var mfeSum, maeSum, qualitySum float64
+	var winners, losers int
+	var maeWinSum, mfeLoseSum, exitEffSum, stopEffSum float64
+	var exitEffCount, stopEffCount int
+
+	for _, t := range trades {
+		if t.EntryPrice <= 0 {
+			continue
+		}
+		move := 0.0
+		if t.ExitPrice > 0 {
+			move = (t.ExitPrice - t.EntryPrice) / t.EntryPrice * 100
+			if t.Side == "short" {
+				move = -move
+			}
+		}
+		mfe, mae := t.MFEPct, t.MAEPct
+		if move > mfe {
+			mfe = move
+		}
+		if -move > mae {
+			mae = -move
+		}
+		if mfe+mae <= 0 {
+			continue
+		}
+
+		stats.TrackedTrades++
+		mfeSum += mfe
+		maeSum += mae
+		qualitySum += mfe / (mfe + mae) * 100
+
+		if t.PnL > 0 {
+			winners++
+			maeWinSum += mae
+			if mae > stats.MaxMAEWinnersPct {
+				stats.MaxMAEWinnersPct = mae
+			}
+			if mfe > 0 && move > 0 {
+				exitEffSum += move / mfe * 100
+				exitEffCount++
+			}
+		} else if t.PnL < 0 {
+			losers++
+			mfeLoseSum += mfe
+			if mae > 0 && move < 0 {
+				stopEffSum += -move / mae * 100
+				stopEffCount++
+			}
+		}
+	}
+
+	if stats.TrackedTrades == 0 {
+		return stats
+	}
+	n := float64(stats.TrackedTrades)
+	stats.AvgMFEPct = mfeSum / n
+	stats.AvgMAEPct = maeSum / n
+	stats.AvgEntryQuality = qualitySum / n
+	if winners > 0 {
+		stats.AvgMAEWinnersPct = maeWinSum / float64(winners)
+	}
+	if losers > 0 {
+		stats.AvgMFELosersPct = mfeLoseSum / float64(losers)
+	}
+	if exitEffCount > 0 {
+		stats.ExitEfficiencyPct = exitEffSum / float64(exitEffCount)
+	}
+	if stopEffCount > 0 {
+		stats.StopEfficiencyPct = stopEffSum / float64(stopEffCount)
+	}
+	return stats
+}
@@ -0,0 +1,299 @@
+package store
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Trade Import / Export
+// ============================================================================
+// Closed trades are exported as CSV or as a broker-agnostic JSON document
+// (TradeExportSchema) to reconcile with tax software and spreadsheets. The
+// same format, JSON or CSV with the same columns, imports externally executed
+// trades: they are stored as closed positions with source "import" and count
+// in all performance stats like the trader's own trades. Decision records are
+// exported the same way for auditing.
+
+const (
+	TradeExportSchema    = "synapsestrike.trades.v1"
+	DecisionExportSchema = "synapsestrike.decisions.v1"
+
+	PositionSourceImport = "import"
+
+	exportTradeIDPrefix = "synapse-" // ID prefix of exported own trades (skipped on re-import)
+	importTradeIDPrefix = "import_"  // exchange_position_id prefix of imported trades
+)
+
+// ExportedTrade broker-agnostic closed trade
+type ExportedTrade struct {
+	ID          string    `json:"id"` // Unique trade ID, deduplicates repeated imports
+	Symbol      string    `json:"symbol"`
+	Side        string    `json:"side"` // long/short
+	Quantity    float64   `json:"quantity"`
+	EntryPrice  float64   `json:"entry_price"`
+	ExitPrice   float64   `json:"exit_price"`
+	EntryTime   time.Time `json:"entry_time"`
+	ExitTime    time.Time `json:"exit_time"`
+	Fee         float64   `json:"fee"`
+	RealizedPnL float64   `json:"realized_pnl"`
+	Leverage    int       `json:"leverage,omitempty"`
+	CloseReason string    `json:"close_reason,omitempty"`
+	Exchange    string    `json:"exchange,omitempty"`
+}
+
+// TradeExport trade export document
+type TradeExport struct {
+	Schema     string          `json:"schema"`
+	TraderID   string          `json:"trader_id,omitempty"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Trades     []ExportedTrade `json:"trades"`
+}
+
+// DecisionExport decision record export document
+type DecisionExport struct {
+	Schema     string            `json:"schema"`
+	TraderID   string            `json:"trader_id"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Records    []*DecisionRecord `json:"records"`
+}
+
+// TradeCSVColumns CSV columns of exported trades (same names as the JSON fields)
+var TradeCSVColumns = []string{
+	"id", "symbol", "side", "quantity", "entry_price", "exit_price", "entry_time", "exit_time",
+	"fee", "realized_pnl", "leverage", "close_reason", "exchange",
+}
+
+// requiredTradeCSVColumns columns an imported CSV must have
+var requiredTradeCSVColumns = []string{"symbol", "side", "quantity", "entry_price", "exit_price", "exit_time"}
+
+// DecisionCSVColumns CSV columns of exported decision records
+var DecisionCSVColumns = []string{
+	"cycle_number", "timestamp", "success", "shock_mode", "candidates", "decisions",
+	"execution_log", "error_message", "ai_request_duration_ms", "ai_timeout",
+	"error_category",
+}
+
+// NewExportedTrade converts a closed position to an exported trade
+func NewExportedTrade(pos *TraderPosition) ExportedTrade {
+	trade := ExportedTrade{
+		ID:          fmt.Sprintf("%s%d", exportTradeIDPrefix, pos.ID),
+		Symbol:      pos.Symbol,
+		Side:        strings.ToLower(pos.Side),
+		Quantity:    pos.Quantity,
+		EntryPrice:  pos.EntryPrice,
+		ExitPrice:   pos.ExitPrice,
+		EntryTime:   pos.EntryTime.UTC(),
+		Fee:         pos.Fee,
+		RealizedPnL: pos.RealizedPnL,
+		Leverage:    pos.Leverage,
+		CloseReason: pos.CloseReason,
+		Exchange:    pos.ExchangeType,
+	}
+	if pos.ExitTime != nil {
+		trade.ExitTime = pos.ExitTime.UTC()
+	}
+	return trade
+}
+
+// Validate checks an imported trade and normalizes side (buy/sell accepted) and entry time
+func (t *ExportedTrade) Validate() error {
+	t.Symbol = strings.ToUpper(strings.TrimSpace(t.Symbol))
+	if t.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	switch strings.ToLower(strings.TrimSpace(t.Side)) {
+	case "long", "buy":
+		t.Side = "long"
+	case "short", "sell":
+		t.Side = "short"
+	default:
+		return fmt.Errorf("invalid side %q (long/short)", t.Side)
+	}
+	if t.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if t.EntryPrice <= 0 || t.ExitPrice <= 0 {
+		return fmt.Errorf("entry_price and exit_price must be positive")
+	}
+	if t.ExitTime.IsZero() {
+		return fmt.Errorf("exit_time is required")
+	}
+	if t.ExitTime.After(time.Now().Add(time.Hour)) {
+		return fmt.Errorf("exit_time is in the future")
+	}
+	if t.EntryTime.IsZero() || t.EntryTime.After(t.ExitTime) {
+		t.EntryTime = t.ExitTime
+	}
+	return nil
+}
+
+// WriteTradesCSV writes trades as CSV with a TradeCSVColumns header
+func WriteTradesCSV(w io.Writer, trades []ExportedTrade) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(TradeCSVColumns); err != nil {
+		return err
+	}
+	for _, t := range trades {
+		row := []string{
+			csvText(t.ID), csvText(t.Symbol), csvText(t.Side), formatCSVFloat(t.Quantity), formatCSVFloat(t.EntryPrice), formatCSVFloat(t.ExitPrice),
+			formatCSVTime(t.EntryTime), formatCSVTime(t.ExitTime), formatCSVFloat(t.Fee), formatCSVFloat(t.RealizedPnL),
+			strconv.Itoa(t.Leverage), csvText(t.CloseReason), csvText(t.Exchange),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ParseTradesCSV parses trades from CSV with a header row
+// Columns are matched by name (any order, unknown columns ignored); times are RFC3339 or "2006-01-02 15:04:05" UTC.
+func ParseTradesCSV(r io.Reader) ([]ExportedTrade, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range requiredTradeCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", name)
+		}
+	}
+
+	var trades []ExportedTrade
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return parseCSVText(strings.TrimSpace(row[i]))
+			}
+			return ""
+		}
+
+		trade := ExportedTrade{
+			ID:          field("id"),
+			Symbol:      field("symbol"),
+			Side:        field("side"),
+			CloseReason: field("close_reason"),
+			Exchange:    field("exchange"),
+		}
+		numbers := []struct {
+			name string
+			dst  *float64
+		}{
+			{"quantity", &trade.Quantity}, {"entry_price", &trade.EntryPrice}, {"exit_price", &trade.ExitPrice},
+			{"fee", &trade.Fee}, {"realized_pnl", &trade.RealizedPnL},
+		}
+		for _, n := range numbers {
+			if value := field(n.name); value != "" {
+				if *n.dst, err = strconv.ParseFloat(value, 64); err != nil {
+					return nil, fmt.Errorf("line %d: invalid %s %q", line, n.name, value)
+				}
+			}
+		}
+		if value := field("leverage"); value != "" {
+			if trade.Leverage, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid leverage %q", line, value)
+			}
+		}
+		if trade.EntryTime, err = parseCSVTime(field("entry_time")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid entry_time: %w", line, err)
+		}
+		if trade.ExitTime, err = parseCSVTime(field("exit_time")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid exit_time: %w", line, err)
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// WriteDecisionsCSV writes decision records as CSV with a DecisionCSVColumns header (one row per cycle)
+func WriteDecisionsCSV(w io.Writer, records []*DecisionRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(DecisionCSVColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		actions := make([]string, 0, len(r.Decisions))
+		for _, d := range r.Decisions {
+			action := fmt.Sprintf("%s %s", d.Action, d.Symbol)
+			if d.Error != "" {
+				action += " (failed: " + d.Error + ")"
+			}
+			actions = append(actions, action)
+		}
+		row := []string{
+			strconv.Itoa(r.CycleNumber), formatCSVTime(r.Timestamp), strconv.FormatBool(r.Success),
+			strconv.FormatBool(r.ShockMode), csvText(strings.Join(r.CandidateCoins, ";")), csvText(strings.Join(actions, "; ")),
+			csvText(strings.Join(r.ExecutionLog, " | ")), csvText(r.ErrorMessage), strconv.FormatInt(r.AIRequestDurationMs, 10),
+			strconv.FormatBool(r.AITimeout), csvText(r.ErrorCategory),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvFormulaPrefixes leading characters that make spreadsheets evaluate a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvText escapes a text cell against formula injection (CSV export is opened in spreadsheets)
+// Cells starting with a formula character get a leading apostrophe, which spreadsheets show as text.
+// Only text cells are escaped: numbers are written by formatCSVFloat and stay numeric.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// parseCSVText reverses csvText so exported trades re-import with their original values
+func parseCSVText(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseCSVTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time format %q", value)
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTradesCSV(t *testing.T) {
+	t.Run("columns by name", func(t *testing.T) {
+		input := "\ufeffExit_Time, side ,symbol,quantity,entry_price,exit_price,fee,leverage,notes,id\n" +
+			"2024-03-01 15:30:00,buy,aapl,10,100,110,1.5,2,ignored,T-1\n" +
+			"2024-03-02T10:00:00Z,SHORT,TSLA,5,200,190,,,,\n"
+		trades, err := ParseTradesCSV(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ParseTradesCSV: %v", err)
+		}
+		if len(trades) != 2 {
+			t.Fatalf("parsed %d trades, want 2", len(trades))
+		}
+		first := trades[0]
+		if first.ID != "T-1" || first.Symbol != "aapl" || first.Side != "buy" || first.Quantity != 10 ||
+			first.EntryPrice != 100 || first.ExitPrice != 110 || first.Fee != 1.5 || first.Leverage != 2 {
+			t.Errorf("first trade = %+v", first)
+		}
+		if want := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC); !first.ExitTime.Equal(want) {
+			t.Errorf("exit time = %v, want %v", first.ExitTime, want)
+		}
+		if !trades[1].EntryTime.IsZero() || trades[1].Leverage != 0 {
+			t.Errorf("empty optional columns should stay zero: %+v", trades[1])
+		}
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "", "header"},
+		{"missing column", "symbol,side,quantity,entry_price,exit_price\nAAPL,long,1,1,1\n", `"exit_time"`},
+		{"invalid number", "symbol,side,quantity,entry_price,exit_price,exit_time\nAAPL,long,ten,1,1,2024-03-01\n", "line 2: invalid quantity"},
+		{"invalid leverage", "symbol,side,quantity,entry_price,exit_price,exit_time,leverage\nAAPL,long,1,1,1,2024-03-01,2x\n", "invalid leverage"},
+		{"invalid time", "symbol,side,quantity,entry_price,exit_price,exit_time\nAAPL,long,1,1,1,03/01/2024\n", "invalid exit_time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTradesCSV(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTradesCSV error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportedTradeValidate(t *testing.T) {
+	exit := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	valid := func() ExportedTrade {
+		return ExportedTrade{Symbol: " aapl ", Side: "Buy", Quantity: 10, EntryPrice: 100, ExitPrice: 110, ExitTime: exit}
+	}
+
+	trade := valid()
+	if err := trade.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if trade.Symbol != "AAPL" || trade.Side != "long" || !trade.EntryTime.Equal(exit) {
+		t.Errorf("normalized trade = %+v, want AAPL long entered at exit time", trade)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(tr *ExportedTrade)
+		want   string // Expected error substring, empty = valid
+	}{
+		{"sell is short", func(tr *ExportedTrade) { tr.Side = "SELL" }, ""},
+		{"entry after exit is clamped", func(tr *ExportedTrade) { tr.EntryTime = exit.Add(time.Hour) }, ""},
+		{"no symbol", func(tr *ExportedTrade) { tr.Symbol = "  " }, "symbol"},
+		{"unknown side", func(tr *ExportedTrade) { tr.Side = "flat" }, "invalid side"},
+		{"zero quantity", func(tr *ExportedTrade) { tr.Quantity = 0 }, "quantity"},
+		{"negative price", func(tr *ExportedTrade) { tr.ExitPrice = -1 }, "exit_price"},
+		{"no exit time", func(tr *ExportedTrade) { tr.ExitTime = time.Time{} }, "exit_time is required"},
+		{"future exit", func(tr *ExportedTrade) { tr.ExitTime = time.Now().Add(2 * time.Hour) }, "future"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade := valid()
+			tt.mutate(&trade)
+			err := trade.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate = %v, want valid", err)
+				}
+				if trade.EntryTime.After(trade.ExitTime) {
+					t.Errorf("entry time %v after exit time %v", trade.EntryTime, trade.ExitTime)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTradesCSVEscapesFormulas(t *testing.T) {
+	exit := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	trades := []ExportedTrade{{
+		ID: "=HYPERLINK(\"http://evil\")", Symbol: "AAPL", Side: "long", Quantity: 10, EntryPrice: 100, ExitPrice: 90,
+		EntryTime: exit, ExitTime: exit, RealizedPnL: -100, CloseReason: "@SUM(A1)", Exchange: "+cmd",
+	}}
+	var buf bytes.Buffer
+	if err := WriteTradesCSV(&buf, trades); err != nil {
+		t.Fatalf("WriteTradesCSV: %v", err)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("exported CSV has %d rows (%v), want 2", len(rows), err)
+	}
+	row := rows[1]
+	for i, want := range map[int]string{0: "'=HYPERLINK(\"http://evil\")", 9: "-100", 11: "'@SUM(A1)", 12: "'+cmd"} {
+		if row[i] != want {
+			t.Errorf("%s = %q, want %q", TradeCSVColumns[i], row[i], want)
+		}
+	}
+
+	// Escaped cells re-import with their original values
+	parsed, err := ParseTradesCSV(bytes.NewReader(buf.Bytes()))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("re-import parsed %d trades (%v), want 1", len(parsed), err)
+	}
+	if parsed[0].ID != trades[0].ID || parsed[0].CloseReason != "@SUM(A1)" || parsed[0].Exchange != "+cmd" || parsed[0].RealizedPnL != -100 {
+		t.Errorf("re-imported trade = %+v", parsed[0])
+	}
+}
+
+func TestImportTradeDeduplicates(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	exit := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	trade := ExportedTrade{ID: "broker-42", Symbol: "AAPL", Side: "long", Quantity: 10, EntryPrice: 100, ExitPrice: 110,
+		EntryTime: exit.Add(-time.Hour), ExitTime: exit, RealizedPnL: 100}
+
+	if created, err := s.Position().ImportTrade("t1", "ex1", trade); err != nil || !created {
+		t.Fatalf("first import created=%v (%v), want created", created, err)
+	}
+	if created, err := s.Position().ImportTrade("t1", "ex1", trade); err != nil || created {
+		t.Errorf("repeated import created=%v (%v), want duplicate", created, err)
+	}
+
+	// Without an ID the trade is identified by symbol, side, exit time and P&L
+	anonymous := trade
+	anonymous.ID = ""
+	anonymous.ExitTime = exit.Add(time.Minute)
+	if created, err := s.Position().ImportTrade("t1", "ex1", anonymous); err != nil || !created {
+		t.Fatalf("anonymous import created=%v (%v), want created", created, err)
+	}
+	if created, err := s.Position().ImportTrade("t1", "ex1", anonymous); err != nil || created {
+		t.Errorf("repeated anonymous import created=%v (%v), want duplicate", created, err)
+	}
+
+	// Re-importing the trader's own export skips its trades
+	closed, err := s.Position().GetClosedPositions("t1", 10)
+	if err != nil || len(closed) != 2 {
+		t.Fatalf("closed positions = %d (%v), want 2", len(closed), err)
+	}
+	for _, pos := range closed {
+		if created, err := s.Position().ImportTrade("t1", "ex1", NewExportedTrade(pos)); err != nil || created {
+			t.Errorf("re-import of own trade %d created=%v (%v), want skipped", pos.ID, created, err)
+		}
+	}
+	if closed, _ := s.Position().GetClosedPositions("t1", 10); len(closed) != 2 {
+		t.Errorf("closed positions after re-import = %d, want 2", len(closed))
+	}
+}
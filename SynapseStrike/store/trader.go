@@ -27,6 +27,9 @@ type Trader struct {
 	IsCrossMargin        bool      `json:"is_cross_margin"`
 	ShowInCompetition    bool      `json:"show_in_competition"`   // Whether to show in competition page
 	TradeOnlyMarketHours bool      `json:"trade_only_market_hours"` // Only trade during stock market hours (9:30 AM - 4:00 PM ET)
+	ShadowMode           bool      `json:"shadow_mode"`             // Run full pipeline but execute on virtual ledger instead of exchange
+	TradingSchedule      *TradingSchedule `json:"trading_schedule,omitempty"` // Time-of-day / day-of-week trading windows (nil = no schedule)
+	SymbolLists          *SymbolLists     `json:"symbol_lists,omitempty"`     // Symbol allow/deny lists (nil = no restriction)
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 
@@ -103,6 +106,9 @@ func (s *TraderStore) initTables() error {
 		`ALTER TABLE traders ADD COLUMN strategy_id TEXT DEFAULT ''`,
 		`ALTER TABLE traders ADD COLUMN show_in_competition BOOLEAN DEFAULT 1`,
 		`ALTER TABLE traders ADD COLUMN trade_only_market_hours BOOLEAN DEFAULT 0`,
+		`ALTER TABLE traders ADD COLUMN shadow_mode BOOLEAN DEFAULT 0`,
+		`ALTER TABLE traders ADD COLUMN trading_schedule TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN symbol_lists TEXT DEFAULT ''`,
 	}
 	for _, q := range alterQueries {
 		s.db.Exec(q)
@@ -203,12 +209,14 @@ func (s *TraderStore) Create(trader *Trader) error {
 		INSERT INTO traders (id, user_id, name, ai_model_id, exchange_id, strategy_id, initial_balance,
 		                     scan_interval_minutes, is_running, is_cross_margin, show_in_competition,
 		                     large_cap_leverage, small_cap_leverage, trading_symbols, use_coin_pool,
-		                     use_oi_top, custom_prompt, override_base_prompt, system_prompt_template, trade_only_market_hours)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		                     use_oi_top, custom_prompt, override_base_prompt, system_prompt_template, trade_only_market_hours,
+		                     shadow_mode, trading_schedule, symbol_lists)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, trader.ID, trader.UserID, trader.Name, trader.AIModelID, trader.ExchangeID, trader.StrategyID,
 		trader.InitialBalance, trader.ScanIntervalMinutes, trader.IsRunning, trader.IsCrossMargin, trader.ShowInCompetition,
 		trader.LargeCapLeverage, trader.SmallCapLeverage, trader.TradingSymbols, trader.UseCoinPool,
-		trader.UseOITop, trader.CustomPrompt, trader.OverrideBasePrompt, trader.SystemPromptTemplate, trader.TradeOnlyMarketHours)
+		trader.UseOITop, trader.CustomPrompt, trader.OverrideBasePrompt, trader.SystemPromptTemplate, trader.TradeOnlyMarketHours,
+		trader.ShadowMode, encodeTradingSchedule(trader.TradingSchedule), encodeSymbolLists(trader.SymbolLists))
 	return err
 }
 
@@ -217,7 +225,8 @@ func (s *TraderStore) List(userID string) ([]*Trader, error) {
 	rows, err := s.db.Query(`
 		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
 		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
-		       COALESCE(show_in_competition, 1), COALESCE(trade_only_market_hours, 0),
+		       COALESCE(show_in_competition, 1), COALESCE(trade_only_market_hours, 0), COALESCE(shadow_mode, 0),
+		       COALESCE(trading_schedule, ''), COALESCE(symbol_lists, ''),
 		       COALESCE(large_cap_leverage, 5), COALESCE(small_cap_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
 		       COALESCE(override_base_prompt, 0), COALESCE(system_prompt_template, 'default'),
@@ -232,11 +241,11 @@ func (s *TraderStore) List(userID string) ([]*Trader, error) {
 	var traders []*Trader
 	for rows.Next() {
 		var t Trader
-		var createdAt, updatedAt string
+		var createdAt, updatedAt, schedule, symbolLists string
 		err := rows.Scan(
 			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
 			&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
-			&t.ShowInCompetition, &t.TradeOnlyMarketHours,
+			&t.ShowInCompetition, &t.TradeOnlyMarketHours, &t.ShadowMode, &schedule, &symbolLists,
 			&t.LargeCapLeverage, &t.SmallCapLeverage, &t.TradingSymbols,
 			&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
 			&t.SystemPromptTemplate, &createdAt, &updatedAt,
@@ -246,6 +255,8 @@ func (s *TraderStore) List(userID string) ([]*Trader, error) {
 		}
 		t.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 		t.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		t.TradingSchedule = decodeTradingSchedule(schedule)
+		t.SymbolLists = decodeSymbolLists(symbolLists)
 		traders = append(traders, &t)
 	}
 	return traders, nil
@@ -263,6 +274,19 @@ func (s *TraderStore) UpdateShowInCompetition(userID, id string, showInCompetiti
 	return err
 }
 
+// UpdateSymbolLists updates trader symbol allow/deny lists (nil clears them)
+func (s *TraderStore) UpdateSymbolLists(userID, id string, lists *SymbolLists) error {
+	result, err := s.db.Exec(`UPDATE traders SET symbol_lists = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ?`,
+		encodeSymbolLists(lists), id, userID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("trader not found")
+	}
+	return nil
+}
+
 // Update updates trader configuration
 func (s *TraderStore) Update(trader *Trader) error {
 	fmt.Printf("📝 TraderStore.Update: ID=%s, Name=%s, AIModelID=%s, StrategyID=%s\n",
@@ -278,12 +302,16 @@ func (s *TraderStore) Update(trader *Trader) error {
 			is_cross_margin = ?,
 			show_in_competition = ?,
 			trade_only_market_hours = ?,
+			shadow_mode = ?,
+			trading_schedule = ?,
+			symbol_lists = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ? AND user_id = ?
 	`, trader.Name, trader.AIModelID, trader.ExchangeID, trader.StrategyID,
 		trader.InitialBalance, trader.InitialBalance,
 		trader.ScanIntervalMinutes, trader.ScanIntervalMinutes,
 		trader.IsCrossMargin, trader.ShowInCompetition, trader.TradeOnlyMarketHours,
+		trader.ShadowMode, encodeTradingSchedule(trader.TradingSchedule), encodeSymbolLists(trader.SymbolLists),
 		trader.ID, trader.UserID)
 	return err
 }
@@ -316,7 +344,7 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 	var trader Trader
 	var aiModel AIModel
 	var exchange Exchange
-	var traderCreatedAt, traderUpdatedAt string
+	var traderCreatedAt, traderUpdatedAt, traderSchedule, traderSymbolLists string
 	var aiModelCreatedAt, aiModelUpdatedAt string
 	var exchangeCreatedAt, exchangeUpdatedAt string
 
@@ -327,15 +355,16 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 			COALESCE(t.large_cap_leverage, 5), COALESCE(t.small_cap_leverage, 5), COALESCE(t.trading_symbols, ''),
 			COALESCE(t.use_coin_pool, 0), COALESCE(t.use_oi_top, 0), COALESCE(t.custom_prompt, ''),
 			COALESCE(t.override_base_prompt, 0), COALESCE(t.system_prompt_template, 'default'),
-			COALESCE(t.trade_only_market_hours, 0),
-			t.created_at, t.updated_at,
+			COALESCE(t.trade_only_market_hours, 0), COALESCE(t.shadow_mode, 0), COALESCE(t.trading_schedule, ''),
+			COALESCE(t.symbol_lists, ''), t.created_at, t.updated_at,
 			a.id, a.user_id, a.name, a.provider, a.enabled, a.api_key,
 			COALESCE(a.custom_api_url, ''), COALESCE(a.custom_model_name, ''), a.created_at, a.updated_at,
 			e.id, COALESCE(e.exchange_type, '') as exchange_type, COALESCE(e.account_name, '') as account_name,
 			e.user_id, e.name, e.type, e.enabled, e.api_key, e.secret_key, COALESCE(e.passphrase, ''), e.testnet,
 			COALESCE(e.hyperliquid_wallet_addr, ''), COALESCE(e.aster_user, ''), COALESCE(e.aster_signer, ''),
 			COALESCE(e.aster_private_key, ''), COALESCE(e.lighter_wallet_addr, ''), COALESCE(e.lighter_private_key, ''),
-			COALESCE(e.lighter_api_key_private_key, ''), COALESCE(e.lighter_api_key_index, 0), e.created_at, e.updated_at
+			COALESCE(e.lighter_api_key_private_key, ''), COALESCE(e.lighter_api_key_index, 0),
+			COALESCE(e.dydx_mnemonic, ''), COALESCE(e.dydx_subaccount, 0), e.created_at, e.updated_at
 		FROM traders t
 		JOIN ai_models a ON t.ai_model_id = a.id AND t.user_id = a.user_id
 		JOIN exchanges e ON t.exchange_id = e.id AND t.user_id = e.user_id
@@ -345,7 +374,8 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 		&trader.InitialBalance, &trader.ScanIntervalMinutes, &trader.IsRunning, &trader.IsCrossMargin,
 		&trader.LargeCapLeverage, &trader.SmallCapLeverage, &trader.TradingSymbols,
 		&trader.UseCoinPool, &trader.UseOITop, &trader.CustomPrompt, &trader.OverrideBasePrompt,
-		&trader.SystemPromptTemplate, &trader.TradeOnlyMarketHours, &traderCreatedAt, &traderUpdatedAt,
+		&trader.SystemPromptTemplate, &trader.TradeOnlyMarketHours, &trader.ShadowMode, &traderSchedule,
+		&traderSymbolLists, &traderCreatedAt, &traderUpdatedAt,
 		&aiModel.ID, &aiModel.UserID, &aiModel.Name, &aiModel.Provider, &aiModel.Enabled, &aiModel.APIKey,
 		&aiModel.CustomAPIURL, &aiModel.CustomModelName, &aiModelCreatedAt, &aiModelUpdatedAt,
 		&exchange.ID, &exchange.ExchangeType, &exchange.AccountName,
@@ -353,6 +383,7 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 		&exchange.APIKey, &exchange.SecretKey, &exchange.Passphrase, &exchange.Testnet, &exchange.HyperliquidWalletAddr,
 		&exchange.AsterUser, &exchange.AsterSigner, &exchange.AsterPrivateKey,
 		&exchange.LighterWalletAddr, &exchange.LighterPrivateKey, &exchange.LighterAPIKeyPrivateKey, &exchange.LighterAPIKeyIndex,
+		&exchange.DydxMnemonic, &exchange.DydxSubaccount,
 		&exchangeCreatedAt, &exchangeUpdatedAt,
 	)
 	if err != nil {
@@ -361,6 +392,8 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 
 	trader.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", traderCreatedAt)
 	trader.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", traderUpdatedAt)
+	trader.TradingSchedule = decodeTradingSchedule(traderSchedule)
+	trader.SymbolLists = decodeSymbolLists(traderSymbolLists)
 	aiModel.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", aiModelCreatedAt)
 	aiModel.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", aiModelUpdatedAt)
 	exchange.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", exchangeCreatedAt)
@@ -374,6 +407,7 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 	exchange.AsterPrivateKey = s.decrypt(exchange.AsterPrivateKey)
 	exchange.LighterPrivateKey = s.decrypt(exchange.LighterPrivateKey)
 	exchange.LighterAPIKeyPrivateKey = s.decrypt(exchange.LighterAPIKeyPrivateKey)
+	exchange.DydxMnemonic = s.decrypt(exchange.DydxMnemonic)
 
 	// Load associated strategy
 	var strategy *Strategy
@@ -451,28 +485,30 @@ func (s *TraderStore) getActiveOrDefaultStrategy(userID string) (*Strategy, erro
 // GetByID gets a trader by ID without requiring userID (for public APIs)
 func (s *TraderStore) GetByID(traderID string) (*Trader, error) {
 	var t Trader
-	var createdAt, updatedAt string
+	var createdAt, updatedAt, schedule, symbolLists string
 	err := s.db.QueryRow(`
 		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
 		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
 		       COALESCE(large_cap_leverage, 5), COALESCE(small_cap_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
 		       COALESCE(override_base_prompt, 0), COALESCE(system_prompt_template, 'default'),
-		       COALESCE(trade_only_market_hours, 0),
-		       created_at, updated_at
+		       COALESCE(trade_only_market_hours, 0), COALESCE(shadow_mode, 0), COALESCE(trading_schedule, ''),
+		       COALESCE(symbol_lists, ''), created_at, updated_at
 		FROM traders t WHERE t.id = ?
 	`, traderID).Scan(
 		&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
 		&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
 		&t.LargeCapLeverage, &t.SmallCapLeverage, &t.TradingSymbols,
 		&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
-		&t.SystemPromptTemplate, &t.TradeOnlyMarketHours, &createdAt, &updatedAt,
+		&t.SystemPromptTemplate, &t.TradeOnlyMarketHours, &t.ShadowMode, &schedule, &symbolLists, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	t.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 	t.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	t.TradingSchedule = decodeTradingSchedule(schedule)
+	t.SymbolLists = decodeSymbolLists(symbolLists)
 	return &t, nil
 }
 
@@ -480,7 +516,8 @@ func (s *TraderStore) ListAll() ([]*Trader, error) {
 	rows, err := s.db.Query(`
 		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
 		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
-		       COALESCE(show_in_competition, 1), COALESCE(trade_only_market_hours, 0),
+		       COALESCE(show_in_competition, 1), COALESCE(trade_only_market_hours, 0), COALESCE(shadow_mode, 0),
+		       COALESCE(trading_schedule, ''), COALESCE(symbol_lists, ''),
 		       COALESCE(large_cap_leverage, 5), COALESCE(small_cap_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
 		       COALESCE(override_base_prompt, 0), COALESCE(system_prompt_template, 'default'),
@@ -495,11 +532,11 @@ func (s *TraderStore) ListAll() ([]*Trader, error) {
 	var traders []*Trader
 	for rows.Next() {
 		var t Trader
-		var createdAt, updatedAt string
+		var createdAt, updatedAt, schedule, symbolLists string
 		err := rows.Scan(
 			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
 			&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
-			&t.ShowInCompetition, &t.TradeOnlyMarketHours,
+			&t.ShowInCompetition, &t.TradeOnlyMarketHours, &t.ShadowMode, &schedule, &symbolLists,
 			&t.LargeCapLeverage, &t.SmallCapLeverage, &t.TradingSymbols,
 			&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
 			&t.SystemPromptTemplate, &createdAt, &updatedAt,
@@ -509,6 +546,8 @@ func (s *TraderStore) ListAll() ([]*Trader, error) {
 		}
 		t.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 		t.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		t.TradingSchedule = decodeTradingSchedule(schedule)
+		t.SymbolLists = decodeSymbolLists(symbolLists)
 		traders = append(traders, &t)
 	}
 	return traders, nil
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// VWAPBarStore intraday 1-minute bar storage for VWAP collectors (survives restarts)
+// Bars are market data, so they are keyed by symbol and shared by all traders.
+type VWAPBarStore struct {
+	db *sql.DB
+}
+
+// VWAPBarRecord persisted 1-minute bar
+type VWAPBarRecord struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// initTables initializes VWAP bar tables
+func (s *VWAPBarStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS vwap_bars (
+			symbol TEXT NOT NULL,
+			trade_date TEXT NOT NULL,
+			bar_time INTEGER NOT NULL,
+			open REAL NOT NULL,
+			high REAL NOT NULL,
+			low REAL NOT NULL,
+			close REAL NOT NULL,
+			volume REAL NOT NULL,
+			PRIMARY KEY (symbol, bar_time)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create vwap_bars table: %w", err)
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_vwap_bars_symbol_date ON vwap_bars(symbol, trade_date)`); err != nil {
+		return fmt.Errorf("failed to create vwap_bars index: %w", err)
+	}
+	return nil
+}
+
+// Save upserts bars of a symbol's trading day (tradeDate is YYYY-MM-DD in exchange time)
+// A bar saved again with the same time replaces the earlier, possibly incomplete, one.
+func (s *VWAPBarStore) Save(symbol, tradeDate string, bars []VWAPBarRecord) error {
+	if len(bars) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO vwap_bars (symbol, trade_date, bar_time, open, high, low, close, volume)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(symbol, bar_time) DO UPDATE SET
+			trade_date = excluded.trade_date,
+			open = excluded.open,
+			high = excluded.high,
+			low = excluded.low,
+			close = excluded.close,
+			volume = excluded.volume
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare vwap bar insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, bar := range bars {
+		if _, err := stmt.Exec(symbol, tradeDate, bar.Time.UnixMilli(), bar.Open, bar.High, bar.Low, bar.Close, bar.Volume); err != nil {
+			return fmt.Errorf("failed to save vwap bar: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// List gets bars of a symbol's trading day, ordered by time
+func (s *VWAPBarStore) List(symbol, tradeDate string) ([]VWAPBarRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT bar_time, open, high, low, close, volume
+		FROM vwap_bars WHERE symbol = ? AND trade_date = ?
+		ORDER BY bar_time ASC
+	`, symbol, tradeDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vwap bars: %w", err)
+	}
+	defer rows.Close()
+
+	var bars []VWAPBarRecord
+	for rows.Next() {
+		var bar VWAPBarRecord
+		var barTime int64
+		if err := rows.Scan(&barTime, &bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume); err != nil {
+			return nil, fmt.Errorf("failed to scan vwap bar: %w", err)
+		}
+		bar.Time = time.UnixMilli(barTime)
+		bars = append(bars, bar)
+	}
+	return bars, rows.Err()
+}
+
+// DeleteBefore deletes bars of trading days before tradeDate, returns number of deleted bars
+func (s *VWAPBarStore) DeleteBefore(tradeDate string) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM vwap_bars WHERE trade_date < ?`, tradeDate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete vwap bars: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,49 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/notify"
+	"SynapseStrike/store"
+	"fmt"
+)
+
+// ============================================================================
+// AI Decision Latency SLA
+// ============================================================================
+// The cycle's AI calls share one deadline (AILatency.SLASeconds, else
+// AI_CYCLE_TIMEOUT_SECONDS, else 80% of the scan interval). Calls still
+// running when it passes are abandoned. The record of such a cycle is flagged
+// ai_timeout, and the strategy decides what happens instead: the algorithmic
+// fallback (default, same as any other AI failure) or no trades at all this
+// cycle.
+
+// aiLatencyConfig AI latency settings of the strategy (defaults without strategy engine)
+func (at *AutoTrader) aiLatencyConfig() store.AILatencyConfig {
+	engine := at.engine()
+	if engine == nil {
+		return store.AILatencyConfig{OnTimeout: store.AITimeoutFallback}
+	}
+	cfg := engine.GetConfig().AILatency
+	if cfg.OnTimeout == "" {
+		cfg.OnTimeout = store.AITimeoutFallback
+	}
+	return cfg
+}
+
+// recordAITimeout flags the cycle as AI timeout, returns true if the cycle should be skipped
+func (at *AutoTrader) recordAITimeout(record *store.DecisionRecord, err error) bool {
+	cfg := at.aiLatencyConfig()
+	record.AITimeout = true
+	record.ErrorCategory = store.ErrorCategoryTimeout
+	note := fmt.Sprintf("⏱️ AI timeout: no decision within the %s SLA (%v)", at.aiCycleTimeout(), err)
+	logger.Warnf("%s, policy: %s", note, cfg.OnTimeout)
+	record.ExecutionLog = append(record.ExecutionLog, note)
+
+	if cfg.OnTimeout != store.AITimeoutSkip {
+		return false
+	}
+	record.Success = false
+	record.ErrorMessage = fmt.Sprintf("AI timeout: no decision within %s, cycle skipped", at.aiCycleTimeout())
+	at.publishEvent(notify.EventAIFallback, "", "⏱️ AI timeout, cycle skipped", err.Error(), nil)
+	return true
+}
@@ -0,0 +1,52 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAICycleTimeout(t *testing.T) {
+	at := &AutoTrader{config: AutoTraderConfig{ScanInterval: 5 * time.Minute}}
+	if got := at.aiCycleTimeout(); got != 4*time.Minute {
+		t.Errorf("expected 80%% of scan interval, got %s", got)
+	}
+	at.config.AICycleTimeout = 2 * time.Minute
+	if got := at.aiCycleTimeout(); got != 2*time.Minute {
+		t.Errorf("expected AI_CYCLE_TIMEOUT_SECONDS, got %s", got)
+	}
+
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.AILatency.SLASeconds = 90
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	if got := at.aiCycleTimeout(); got != 90*time.Second {
+		t.Errorf("expected strategy SLA to take precedence, got %s", got)
+	}
+}
+
+func TestRecordAITimeout(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.AILatency.SLASeconds = 90
+	at := &AutoTrader{id: "t1", name: "test", strategyEngine: decision.NewStrategyEngine(&cfg)}
+	timeout := errors.New("AI cycle deadline exceeded")
+
+	record := &store.DecisionRecord{Success: true}
+	if at.recordAITimeout(record, timeout) {
+		t.Error("expected fallback policy by default")
+	}
+	if !record.AITimeout || !record.Success || len(record.ExecutionLog) != 1 {
+		t.Errorf("expected flagged record left to the fallback, got %+v", record)
+	}
+
+	cfg.AILatency.OnTimeout = store.AITimeoutSkip
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	record = &store.DecisionRecord{Success: true}
+	if !at.recordAITimeout(record, timeout) {
+		t.Fatal("expected skip policy to skip the cycle")
+	}
+	if !record.AITimeout || record.Success || record.ErrorMessage != "AI timeout: no decision within 1m30s, cycle skipped" {
+		t.Errorf("unexpected skipped record: %+v", record)
+	}
+}
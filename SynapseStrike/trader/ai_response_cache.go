@@ -0,0 +1,119 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// AI Response Cache
+// ============================================================================
+// While an AI provider is flapping, every failed call used to flip the cycle
+// straight to the algorithmic fallback, even when nothing had changed since
+// the last good answer. With AIResponseCache.Enabled the last successful AI
+// decision is kept together with a hash of the context it was made for
+// (strategy version, positions, candidates and prices rounded to
+// PriceBucketPct). If the AI fails and the current context hashes the same
+// within FreshnessMinutes, that decision is reused; otherwise the algorithmic
+// fallback runs as before. Reused and fallback decisions are never cached.
+
+// defaultPriceBucketPct price granularity of the context hash when unset
+const defaultPriceBucketPct = 0.5
+
+// cachedAIResponse last successful AI decision and the context it was made for
+type cachedAIResponse struct {
+	hash     string
+	decision *decision.FullDecision
+	at       time.Time
+}
+
+// rememberAIResponse caches a successful AI decision (AIResponseCache.Enabled only)
+func (at *AutoTrader) rememberAIResponse(ctx *decision.Context, fd *decision.FullDecision) {
+	cfg := at.engine().GetConfig().AIResponseCache
+	if !cfg.Enabled || fd == nil {
+		return
+	}
+	at.aiCacheMu.Lock()
+	defer at.aiCacheMu.Unlock()
+	at.aiCache = &cachedAIResponse{
+		hash:     at.contextHash(ctx, cfg.PriceBucketPct),
+		decision: fd,
+		at:       time.Now(),
+	}
+}
+
+// reuseAIResponse returns the cached decision if the context is unchanged and the entry is fresh (nil otherwise)
+// failed is the partial result of the failed AI call, its prompts are kept for the record.
+func (at *AutoTrader) reuseAIResponse(ctx *decision.Context, failed *decision.FullDecision, aiErr error) *decision.FullDecision {
+	cfg := at.engine().GetConfig().AIResponseCache
+	if !cfg.Enabled {
+		return nil
+	}
+	at.aiCacheMu.Lock()
+	cached := at.aiCache
+	at.aiCacheMu.Unlock()
+	if cached == nil {
+		return nil
+	}
+
+	freshness := time.Duration(cfg.FreshnessMinutes) * time.Minute
+	if age := time.Since(cached.at); freshness <= 0 || age > freshness {
+		logger.Infof("♻️ [%s] Cached AI decision is %.0f min old (freshness %d min), not reused", at.name, age.Minutes(), cfg.FreshnessMinutes)
+		return nil
+	}
+	if hash := at.contextHash(ctx, cfg.PriceBucketPct); hash != cached.hash {
+		logger.Infof("♻️ [%s] Context changed since cached AI decision, not reused", at.name)
+		return nil
+	}
+
+	reused := &decision.FullDecision{
+		Decisions: append([]decision.Decision(nil), cached.decision.Decisions...),
+		Timestamp: time.Now(),
+		CoTTrace: fmt.Sprintf("AI Error: %s\n---\nReused AI decision from %s (context unchanged)\n---\n%s",
+			aiErr, cached.at.Format("15:04:05"), cached.decision.CoTTrace),
+	}
+	if failed != nil {
+		reused.SystemPrompt = failed.SystemPrompt
+		reused.UserPrompt = failed.UserPrompt
+		reused.RawResponse = failed.RawResponse
+	}
+	return reused
+}
+
+// contextHash fingerprint of what the AI decides on: strategy version, positions, candidates and coarse prices
+func (at *AutoTrader) contextHash(ctx *decision.Context, bucketPct float64) string {
+	if bucketPct <= 0 {
+		bucketPct = defaultPriceBucketPct
+	}
+	lines := []string{fmt.Sprintf("v|%d", at.GetStrategyVersion())}
+	for _, pos := range ctx.Positions {
+		lines = append(lines, fmt.Sprintf("p|%s|%s|%.6g|%d|%d", pos.Symbol, pos.Side, pos.Quantity, pos.Leverage, priceBucket(pos.MarkPrice, bucketPct)))
+	}
+	for _, stock := range ctx.CandidateStocks {
+		lines = append(lines, "c|"+stock.Symbol)
+	}
+	for symbol, data := range ctx.MarketDataMap {
+		if data != nil {
+			lines = append(lines, fmt.Sprintf("m|%s|%d", symbol, priceBucket(data.CurrentPrice, bucketPct)))
+		}
+	}
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// priceBucket index of the logarithmic price bucket of width bucketPct percent
+func priceBucket(price, bucketPct float64) int64 {
+	if price <= 0 {
+		return 0
+	}
+	return int64(math.Floor(math.Log(price) / math.Log1p(bucketPct/100)))
+}
@@ -0,0 +1,186 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPriceBucket(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      float64
+		bucketPct float64
+		same      bool
+	}{
+		{"identical", 100, 100, 0.5, true},
+		{"within bucket", 100.01, 100.2, 0.5, true},
+		{"one percent apart", 100, 101, 0.5, false},
+		{"wider bucket absorbs one percent", 100.01, 101, 5, true},
+		// Buckets are 0.5% wide at any price level: 0.39% apart inside one bucket, 0.9% apart across
+		{"same bucket at high prices", 49915.04, 50109.68, 0.5, true},
+		{"same bucket at low prices", 0.0100178, 0.0100567, 0.5, true},
+		{"across buckets at low prices", 0.0100001, 0.0101, 0.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := priceBucket(tt.a, tt.bucketPct), priceBucket(tt.b, tt.bucketPct)
+			if (a == b) != tt.same {
+				t.Errorf("buckets of %v and %v = %d, %d, want same=%v", tt.a, tt.b, a, b, tt.same)
+			}
+		})
+	}
+
+	if priceBucket(0, 0.5) != 0 || priceBucket(-1, 0.5) != 0 {
+		t.Error("non-positive prices should map to bucket 0")
+	}
+	prev := priceBucket(1, 0.5)
+	for price := 1.01; price < 1000; price *= 1.01 {
+		bucket := priceBucket(price, 0.5)
+		if bucket <= prev {
+			t.Fatalf("bucket of %v = %d, not above %d (1%% moves must change a 0.5%% bucket)", price, bucket, prev)
+		}
+		prev = bucket
+	}
+}
+
+// newCacheTestTrader creates a trader with the AI response cache enabled
+func newCacheTestTrader(freshnessMinutes int) *AutoTrader {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.AIResponseCache = store.AIResponseCacheConfig{Enabled: true, FreshnessMinutes: freshnessMinutes, PriceBucketPct: 0.5}
+	return &AutoTrader{
+		name:                  "test",
+		strategyEngine:        decision.NewStrategyEngine(&cfg),
+		strategyVersion:       1,
+		activeStrategyVersion: 1,
+	}
+}
+
+// newCacheTestContext decision context with one position, two candidates and their prices
+func newCacheTestContext() *decision.Context {
+	return &decision.Context{
+		Positions: []decision.PositionInfo{
+			{Symbol: "AAPL", Side: "long", Quantity: 10, Leverage: 1, MarkPrice: 100},
+		},
+		CandidateStocks: []decision.CandidateStock{{Symbol: "TSLA"}, {Symbol: "NVDA"}},
+		MarketDataMap: map[string]*market.Data{
+			"AAPL": {Symbol: "AAPL", CurrentPrice: 100},
+			"TSLA": {Symbol: "TSLA", CurrentPrice: 200},
+		},
+	}
+}
+
+func TestContextHash(t *testing.T) {
+	at := newCacheTestTrader(15)
+	base := at.contextHash(newCacheTestContext(), 0.5)
+
+	tests := []struct {
+		name   string
+		mutate func(ctx *decision.Context)
+		same   bool
+	}{
+		{"unchanged", func(ctx *decision.Context) {}, true},
+		{"candidate order", func(ctx *decision.Context) {
+			ctx.CandidateStocks[0], ctx.CandidateStocks[1] = ctx.CandidateStocks[1], ctx.CandidateStocks[0]
+		}, true},
+		{"price within bucket", func(ctx *decision.Context) { ctx.MarketDataMap["TSLA"].CurrentPrice = 200.2 }, true},
+		{"unrealized P&L", func(ctx *decision.Context) { ctx.Positions[0].UnrealizedPnL = 12 }, true},
+		{"price across bucket", func(ctx *decision.Context) { ctx.MarketDataMap["TSLA"].CurrentPrice = 203 }, false},
+		{"mark price across bucket", func(ctx *decision.Context) { ctx.Positions[0].MarkPrice = 98 }, false},
+		{"position quantity", func(ctx *decision.Context) { ctx.Positions[0].Quantity = 5 }, false},
+		{"position side", func(ctx *decision.Context) { ctx.Positions[0].Side = "short" }, false},
+		{"position closed", func(ctx *decision.Context) { ctx.Positions = nil }, false},
+		{"new candidate", func(ctx *decision.Context) {
+			ctx.CandidateStocks = append(ctx.CandidateStocks, decision.CandidateStock{Symbol: "AMD"})
+		}, false},
+		{"new market data", func(ctx *decision.Context) {
+			ctx.MarketDataMap["NVDA"] = &market.Data{Symbol: "NVDA", CurrentPrice: 500}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newCacheTestContext()
+			tt.mutate(ctx)
+			if same := at.contextHash(ctx, 0.5) == base; same != tt.same {
+				t.Errorf("hash unchanged = %v, want %v", same, tt.same)
+			}
+		})
+	}
+
+	// The default bucket applies when unset
+	if at.contextHash(newCacheTestContext(), 0) != at.contextHash(newCacheTestContext(), defaultPriceBucketPct) {
+		t.Error("unset bucket should hash like the default bucket")
+	}
+	// A new strategy version invalidates the cached context
+	at.activeStrategyVersion = 2
+	if at.contextHash(newCacheTestContext(), 0.5) == base {
+		t.Error("hash should change with the strategy version")
+	}
+}
+
+func TestReuseAIResponse(t *testing.T) {
+	aiErr := errors.New("provider timeout")
+	good := &decision.FullDecision{
+		Decisions: []decision.Decision{{Symbol: "TSLA", Action: "open_long"}},
+		CoTTrace:  "TSLA breakout",
+	}
+	failed := &decision.FullDecision{SystemPrompt: "system", UserPrompt: "user", RawResponse: "partial"}
+
+	t.Run("fresh and unchanged", func(t *testing.T) {
+		at := newCacheTestTrader(15)
+		at.rememberAIResponse(newCacheTestContext(), good)
+		reused := at.reuseAIResponse(newCacheTestContext(), failed, aiErr)
+		if reused == nil {
+			t.Fatal("fresh decision for an unchanged context should be reused")
+		}
+		if len(reused.Decisions) != 1 || reused.Decisions[0].Symbol != "TSLA" {
+			t.Errorf("reused decisions = %+v", reused.Decisions)
+		}
+		if reused.UserPrompt != "user" || reused.RawResponse != "partial" {
+			t.Errorf("reused decision should keep the failed call's prompts: %+v", reused)
+		}
+		reused.Decisions[0].Action = "hold"
+		if good.Decisions[0].Action != "open_long" {
+			t.Error("reused decisions alias the cached ones")
+		}
+	})
+
+	tests := []struct {
+		name   string
+		setup  func(at *AutoTrader)
+		mutate func(ctx *decision.Context)
+	}{
+		{"stale", func(at *AutoTrader) { at.aiCache.at = time.Now().Add(-16 * time.Minute) }, nil},
+		{"context changed", nil, func(ctx *decision.Context) { ctx.Positions = nil }},
+		{"strategy reloaded", func(at *AutoTrader) { at.activeStrategyVersion = 2 }, nil},
+		{"no freshness window", func(at *AutoTrader) { at.engine().GetConfig().AIResponseCache.FreshnessMinutes = 0 }, nil},
+		{"cache disabled", func(at *AutoTrader) { at.engine().GetConfig().AIResponseCache.Enabled = false }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := newCacheTestTrader(15)
+			at.rememberAIResponse(newCacheTestContext(), good)
+			if tt.setup != nil {
+				tt.setup(at)
+			}
+			ctx := newCacheTestContext()
+			if tt.mutate != nil {
+				tt.mutate(ctx)
+			}
+			if reused := at.reuseAIResponse(ctx, failed, aiErr); reused != nil {
+				t.Errorf("decision reused: %+v", reused)
+			}
+		})
+	}
+
+	t.Run("nothing cached", func(t *testing.T) {
+		at := newCacheTestTrader(15)
+		at.rememberAIResponse(newCacheTestContext(), nil)
+		if reused := at.reuseAIResponse(newCacheTestContext(), failed, aiErr); reused != nil {
+			t.Errorf("decision reused without a cached one: %+v", reused)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// alpacaStreamMessage trade updates stream message
+type alpacaStreamMessage struct {
+	Stream string `json:"stream"` // authorization/listening/trade_updates
+	Data   struct {
+		Status      string `json:"status"` // authorization: authorized/unauthorized
+		Event       string `json:"event"`  // trade_updates: new/fill/partial_fill/canceled/...
+		Price       string `json:"price"`
+		Qty         string `json:"qty"`
+		PositionQty string `json:"position_qty"` // Signed position size after the fill
+		Order       struct {
+			Symbol string `json:"symbol"`
+			Side   string `json:"side"` // buy/sell
+		} `json:"order"`
+	} `json:"data"`
+}
+
+// StreamPositions real-time positions from the trade_updates stream (fills)
+// The fill price doubles as mark price; positions without fills are checked by the interval poll.
+func (t *AlpacaTrader) StreamPositions(stop <-chan struct{}, updates chan<- PositionUpdate) error {
+	conn, err := dialStreamConn(strings.Replace(t.baseURL, "https://", "wss://", 1) + "/stream")
+	if err != nil {
+		return fmt.Errorf("failed to connect trade updates stream: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.writeJSON(map[string]any{"action": "auth", "key": t.apiKey, "secret": t.secretKey}); err != nil {
+		return err
+	}
+	if err := conn.writeJSON(map[string]any{"action": "listen", "data": map[string]any{"streams": []string{"trade_updates"}}}); err != nil {
+		return err
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		for {
+			raw, err := conn.read()
+			if err != nil {
+				errC <- fmt.Errorf("trade updates stream: %w", err)
+				return
+			}
+			var msg alpacaStreamMessage
+			if json.Unmarshal(raw, &msg) != nil {
+				continue
+			}
+			switch msg.Stream {
+			case "authorization":
+				if msg.Data.Status != "authorized" {
+					errC <- fmt.Errorf("trade updates stream authorization failed: %s", msg.Data.Status)
+					return
+				}
+				logger.Infof("📡 [Alpaca] Trade updates stream connected")
+			case "trade_updates":
+				if msg.Data.Event == "fill" || msg.Data.Event == "partial_fill" {
+					pushPositionUpdate(updates, stop, alpacaFillUpdate(msg))
+				}
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-errC:
+			return err
+		case <-ping.C:
+			if err := conn.ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// alpacaFillUpdate position snapshot after a fill
+func alpacaFillUpdate(msg alpacaStreamMessage) PositionUpdate {
+	price, _ := strconv.ParseFloat(msg.Data.Price, 64)
+	qty, _ := strconv.ParseFloat(msg.Data.Qty, 64)
+	positionQty, _ := strconv.ParseFloat(msg.Data.PositionQty, 64)
+
+	side := "long"
+	switch {
+	case positionQty < 0:
+		side = "short"
+	case positionQty == 0 && strings.EqualFold(msg.Data.Order.Side, "buy"):
+		side = "short" // Buy to cover closed a short
+	}
+	u := PositionUpdate{
+		Symbol:    market.FromExchangeSymbol("alpaca", msg.Data.Order.Symbol), // BTC/USD -> BTCUSDT
+		Side:      side,
+		MarkPrice: price,
+		Quantity:  math.Abs(positionQty),
+		Snapshot:  true,
+	}
+	if math.Abs(positionQty) == qty {
+		u.EntryPrice = price // Fill opened the position
+	}
+	return u
+}
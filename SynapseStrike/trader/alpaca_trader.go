@@ -10,13 +10,19 @@ import (
 	"io"
 	"math"
 	"net/http"
+	neturl "net/url"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// AlpacaTrader implements Trader interface for Alpaca Markets (stocks)
+// AlpacaTrader implements Trader interface for Alpaca Markets (stocks and spot crypto)
+// Symbols stay canonical (AAPL, BTCUSDT); crypto pairs are routed to Alpaca's
+// pair format (BTC/USD) through the symbol registry. Crypto is cash-only:
+// no short selling, no leverage, GTC orders around the clock.
 type AlpacaTrader struct {
 	apiKey    string
 	secretKey string
@@ -141,7 +147,7 @@ func (t *AlpacaTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions returns all open positions
-func (t *AlpacaTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *AlpacaTrader) GetPositions() ([]Position, error) {
 	resp, err := t.doRequest("GET", "/v2/positions", nil)
 	if err != nil {
 		return nil, err
@@ -153,9 +159,11 @@ func (t *AlpacaTrader) GetPositions() ([]map[string]interface{}, error) {
 	}
 
 	// Convert to our standard format
-	result := make([]map[string]interface{}, 0, len(positions))
+	result := make([]Position, 0, len(positions))
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
+		// Crypto positions are reported as BTCUSD
+		rawSymbol, _ := pos["symbol"].(string)
+		symbol := market.FromExchangeSymbol("alpaca", rawSymbol)
 		
 		// Parse quantity
 		qty := 0.0
@@ -211,15 +219,15 @@ func (t *AlpacaTrader) GetPositions() ([]map[string]interface{}, error) {
 			qty = -qty
 		}
 
-		result = append(result, map[string]interface{}{
-			"symbol":           symbol,
-			"side":             side,
-			"positionAmt":      qty,
-			"entryPrice":       entryPrice,
-			"markPrice":        currentPrice,
-			"unRealizedProfit": unrealizedPnL,
-			"liquidationPrice": 0.0, // Stocks don't have liquidation
-			"leverage":         1.0, // No leverage for stocks (or margin account)
+		result = append(result, Position{
+			Symbol:        symbol,
+			Side:          side,
+			Qty:           qty,
+			EntryPrice:    entryPrice,
+			MarkPrice:     currentPrice,
+			UnrealizedPnL: unrealizedPnL,
+			LiqPrice:      0, // Stocks don't have liquidation
+			Leverage:      1, // No leverage for stocks (or margin account)
 		})
 	}
 
@@ -230,12 +238,12 @@ func (t *AlpacaTrader) GetPositions() ([]map[string]interface{}, error) {
 // PlaceLimitOrder places a limit order at specified price (Phase 2: Smart Order Execution)
 func (t *AlpacaTrader) PlaceLimitOrder(symbol, side string, quantity float64, limitPrice float64) (map[string]interface{}, error) {
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
 		"side":          side, // "buy" or "sell"
 		"type":          "limit",
-		"time_in_force": "day",
-		"limit_price":   strconv.FormatFloat(limitPrice, 'f', 2, 64),
+		"time_in_force": alpacaTimeInForce(symbol),
+		"limit_price":   alpacaPrice(symbol, limitPrice),
 	}
 
 	resp, err := t.doRequest("POST", "/v2/orders", order)
@@ -293,6 +301,60 @@ func (t *AlpacaTrader) CancelOrder(orderID string) error {
 	return nil
 }
 
+// PlaceLimitEntry places a GTC limit order opening a position (implements LimitEntryTrader)
+// GTC stock orders cannot be fractional, quantity is rounded down to whole shares; crypto keeps fractions.
+func (t *AlpacaTrader) PlaceLimitEntry(symbol, side string, quantity, price float64, leverage int) (map[string]interface{}, error) {
+	crypto := market.IsCrypto(symbol)
+	if crypto && side == "short" {
+		return nil, errAlpacaCryptoShort(symbol)
+	}
+	shares := wholeShares(quantity)
+	qty := strconv.FormatFloat(shares, 'f', 0, 64)
+	if crypto {
+		shares = market.FloorToIncrement(quantity, market.IncrementFromPlaces(alpacaQtyDecimals)).InexactFloat64()
+		qty = alpacaQty(quantity)
+		if shares <= 0 {
+			return nil, fmt.Errorf("limit entry quantity too small (requested %.10f)", quantity)
+		}
+	} else if shares < 1 {
+		return nil, fmt.Errorf("limit entry needs at least 1 whole share (requested %.4f)", quantity)
+	}
+	orderSide := "buy"
+	if side == "short" {
+		orderSide = "sell"
+	}
+	order := map[string]interface{}{
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           qty,
+		"side":          orderSide,
+		"type":          "limit",
+		"time_in_force": "gtc",
+		"limit_price":   alpacaPrice(symbol, price),
+	}
+
+	resp, err := t.doRequest("POST", "/v2/orders", order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place limit entry: %w", err)
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(resp, &result)
+	orderID, _ := result["id"].(string)
+	if orderID == "" {
+		return nil, fmt.Errorf("limit entry placed without order ID")
+	}
+	result["orderId"] = orderID
+	result["quantity"] = shares
+
+	logger.Infof("📌 [Alpaca] Placed GTC limit entry: %s %s at $%.2f, qty=%s", orderSide, symbol, price, qty)
+	return result, nil
+}
+
+// CancelLimitEntry cancels a resting limit entry order (implements LimitEntryTrader)
+func (t *AlpacaTrader) CancelLimitEntry(symbol, orderID string) error {
+	return t.CancelOrder(orderID)
+}
+
 // OpenLong opens a long position (buy)
 func (t *AlpacaTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Get current market price for margin validation
@@ -324,13 +386,13 @@ func (t *AlpacaTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		}
 	}
 
-	// For stocks, we just buy shares
+	// For stocks, we just buy shares (crypto: units of the base asset)
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
 		"side":          "buy",
 		"type":          "market",
-		"time_in_force": "day",
+		"time_in_force": alpacaTimeInForce(symbol),
 	}
 
 	resp, err := t.doRequest("POST", "/v2/orders", order)
@@ -347,9 +409,13 @@ func (t *AlpacaTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 // OpenShort opens a short position (sell short)
 func (t *AlpacaTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if market.IsCrypto(symbol) {
+		return nil, errAlpacaCryptoShort(symbol)
+	}
+
 	// IMPORTANT: Alpaca does NOT allow fractional shares for short selling
 	// We must round to whole shares
-	wholeQty := math.Floor(quantity)
+	wholeQty := wholeShares(quantity)
 	if wholeQty < 1 {
 		return nil, fmt.Errorf("cannot short less than 1 share (requested: %.4f shares)", quantity)
 	}
@@ -425,7 +491,7 @@ func (t *AlpacaTrader) OpenShort(symbol string, quantity float64, leverage int)
 func (t *AlpacaTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	// If quantity is 0, close entire position
 	if quantity == 0 {
-		resp, err := t.doRequest("DELETE", "/v2/positions/"+symbol, nil)
+		resp, err := t.doRequest("DELETE", "/v2/positions/"+alpacaPositionSymbol(symbol), nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to close position: %w", err)
 		}
@@ -437,11 +503,11 @@ func (t *AlpacaTrader) CloseLong(symbol string, quantity float64) (map[string]in
 
 	// Sell specific quantity
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
 		"side":          "sell",
 		"type":          "market",
-		"time_in_force": "day",
+		"time_in_force": alpacaTimeInForce(symbol),
 	}
 
 	resp, err := t.doRequest("POST", "/v2/orders", order)
@@ -459,7 +525,7 @@ func (t *AlpacaTrader) CloseLong(symbol string, quantity float64) (map[string]in
 func (t *AlpacaTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
 	// If quantity is 0, close entire position
 	if quantity == 0 {
-		resp, err := t.doRequest("DELETE", "/v2/positions/"+symbol, nil)
+		resp, err := t.doRequest("DELETE", "/v2/positions/"+alpacaPositionSymbol(symbol), nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to close position: %w", err)
 		}
@@ -471,11 +537,11 @@ func (t *AlpacaTrader) CloseShort(symbol string, quantity float64) (map[string]i
 
 	// Buy to cover
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
 		"side":          "buy",
 		"type":          "market",
-		"time_in_force": "day",
+		"time_in_force": alpacaTimeInForce(symbol),
 	}
 
 	resp, err := t.doRequest("POST", "/v2/orders", order)
@@ -505,6 +571,9 @@ func (t *AlpacaTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 func (t *AlpacaTrader) GetMarketPrice(symbol string) (float64, error) {
 	// Use the latest trade endpoint
 	url := fmt.Sprintf("%s/v2/stocks/%s/trades/latest", t.dataURL, symbol)
+	if market.IsCrypto(symbol) {
+		url = fmt.Sprintf("%s/v1beta3/crypto/us/latest/trades?symbols=%s", t.dataURL, neturl.QueryEscape(alpacaSymbol(symbol)))
+	}
 	
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -528,7 +597,11 @@ func (t *AlpacaTrader) GetMarketPrice(symbol string) (float64, error) {
 		return 0, err
 	}
 	
-	if trade, ok := result["trade"].(map[string]interface{}); ok {
+	trade, ok := result["trade"].(map[string]interface{})
+	if trades, isCrypto := result["trades"].(map[string]interface{}); isCrypto {
+		trade, ok = trades[alpacaSymbol(symbol)].(map[string]interface{})
+	}
+	if ok {
 		if price, ok := trade["p"].(float64); ok {
 			return price, nil
 		}
@@ -539,55 +612,96 @@ func (t *AlpacaTrader) GetMarketPrice(symbol string) (float64, error) {
 
 // SetStopLoss sets a stop-loss order
 func (t *AlpacaTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	_, err := t.PlaceStopLoss(symbol, positionSide, quantity, stopPrice)
+	return err
+}
+
+// SetTakeProfit sets a take-profit order
+func (t *AlpacaTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	_, err := t.PlaceTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+	return err
+}
+
+// PlaceStopLoss places a GTC stop order closing the position, returns its order ID (implements ProtectiveOrderTrader)
+func (t *AlpacaTrader) PlaceStopLoss(symbol string, positionSide string, quantity, stopPrice float64) (string, error) {
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
-		"side":          "sell", // Sell to close long
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
+		"side":          closingSide(positionSide),
 		"type":          "stop",
-		"stop_price":    strconv.FormatFloat(stopPrice, 'f', 2, 64),
+		"stop_price":    alpacaPrice(symbol, stopPrice),
 		"time_in_force": "gtc",
 	}
-	
-	if positionSide == "short" {
-		order["side"] = "buy" // Buy to cover short
+	if market.IsCrypto(symbol) {
+		// Crypto has no plain stop orders: stop-limit with room for the fill below the trigger
+		order["type"] = "stop_limit"
+		order["limit_price"] = alpacaPrice(symbol, stopPrice*(1-alpacaCryptoStopLimitOffset))
 	}
 
-	_, err := t.doRequest("POST", "/v2/orders", order)
+	orderID, err := t.placeOrderForID(order)
 	if err != nil {
-		return fmt.Errorf("failed to set stop loss: %w", err)
+		return "", fmt.Errorf("failed to set stop loss: %w", err)
 	}
-	
-	logger.Infof("🛑 [Alpaca] Stop loss set for %s at $%.2f", symbol, stopPrice)
-	return nil
+
+	logger.Infof("🛑 [Alpaca] Stop loss set for %s at $%.2f (order %s)", symbol, stopPrice, orderID)
+	return orderID, nil
 }
 
-// SetTakeProfit sets a take-profit order
-func (t *AlpacaTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+// PlaceTakeProfit places a GTC limit order closing the position, returns its order ID (implements ProtectiveOrderTrader)
+func (t *AlpacaTrader) PlaceTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error) {
 	order := map[string]interface{}{
-		"symbol":        symbol,
-		"qty":           strconv.FormatFloat(quantity, 'f', -1, 64),
-		"side":          "sell", // Sell to close long
+		"symbol":        alpacaSymbol(symbol),
+		"qty":           alpacaQty(quantity),
+		"side":          closingSide(positionSide),
 		"type":          "limit",
-		"limit_price":   strconv.FormatFloat(takeProfitPrice, 'f', 2, 64),
+		"limit_price":   alpacaPrice(symbol, takeProfitPrice),
 		"time_in_force": "gtc",
 	}
-	
-	if positionSide == "short" {
-		order["side"] = "buy" // Buy to cover short
+
+	orderID, err := t.placeOrderForID(order)
+	if err != nil {
+		return "", fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	_, err := t.doRequest("POST", "/v2/orders", order)
+	logger.Infof("🎯 [Alpaca] Take profit set for %s at $%.2f (order %s)", symbol, takeProfitPrice, orderID)
+	return orderID, nil
+}
+
+// PlacePartialTakeProfit places a GTC limit order closing quantity of the position (implements ScaledTakeProfitTrader)
+// Take profits are quantity-based limit orders already, so this is PlaceTakeProfit.
+func (t *AlpacaTrader) PlacePartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	return t.PlaceTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// CancelProtectiveOrder cancels a stop-loss/take-profit order by ID (implements ProtectiveOrderTrader)
+func (t *AlpacaTrader) CancelProtectiveOrder(symbol, orderID string) error {
+	return t.CancelOrder(orderID)
+}
+
+// placeOrderForID posts an order and returns its order ID
+func (t *AlpacaTrader) placeOrderForID(order map[string]interface{}) (string, error) {
+	resp, err := t.doRequest("POST", "/v2/orders", order)
 	if err != nil {
-		return fmt.Errorf("failed to set take profit: %w", err)
+		return "", err
 	}
-	
-	logger.Infof("🎯 [Alpaca] Take profit set for %s at $%.2f", symbol, takeProfitPrice)
-	return nil
+
+	var result map[string]interface{}
+	json.Unmarshal(resp, &result)
+	orderID, _ := result["id"].(string)
+	return orderID, nil
 }
 
-// CancelStopLossOrders cancels stop loss orders
+// closingSide order side closing a position (positionSide: LONG/SHORT, case-insensitive)
+func closingSide(positionSide string) string {
+	if strings.EqualFold(positionSide, "short") {
+		return "buy" // Buy to cover short
+	}
+	return "sell" // Sell to close long
+}
+
+// CancelStopLossOrders cancels stop loss orders (stop-limit for crypto)
 func (t *AlpacaTrader) CancelStopLossOrders(symbol string) error {
-	return t.cancelOrdersByType(symbol, "stop")
+	return t.cancelOrdersByType(symbol, "stop", "stop_limit")
 }
 
 // CancelTakeProfitOrders cancels take profit orders
@@ -597,7 +711,7 @@ func (t *AlpacaTrader) CancelTakeProfitOrders(symbol string) error {
 
 // CancelAllOrders cancels all orders for a symbol
 func (t *AlpacaTrader) CancelAllOrders(symbol string) error {
-	_, err := t.doRequest("DELETE", "/v2/orders?symbols="+symbol, nil)
+	_, err := t.doRequest("DELETE", "/v2/orders?symbols="+neturl.QueryEscape(alpacaSymbol(symbol)), nil)
 	return err
 }
 
@@ -606,10 +720,10 @@ func (t *AlpacaTrader) CancelStopOrders(symbol string) error {
 	return t.CancelAllOrders(symbol)
 }
 
-// cancelOrdersByType cancels orders of a specific type
-func (t *AlpacaTrader) cancelOrdersByType(symbol string, orderType string) error {
+// cancelOrdersByType cancels orders of the given types
+func (t *AlpacaTrader) cancelOrdersByType(symbol string, orderTypes ...string) error {
 	// Get all open orders
-	resp, err := t.doRequest("GET", "/v2/orders?status=open&symbols="+symbol, nil)
+	resp, err := t.doRequest("GET", "/v2/orders?status=open&symbols="+neturl.QueryEscape(alpacaSymbol(symbol)), nil)
 	if err != nil {
 		return err
 	}
@@ -618,7 +732,7 @@ func (t *AlpacaTrader) cancelOrdersByType(symbol string, orderType string) error
 	json.Unmarshal(resp, &orders)
 	
 	for _, order := range orders {
-		if order["type"] == orderType {
+		if orderType, _ := order["type"].(string); slices.Contains(orderTypes, orderType) {
 			orderId := order["id"].(string)
 			_, err := t.doRequest("DELETE", "/v2/orders/"+orderId, nil)
 			if err != nil {
@@ -633,7 +747,54 @@ func (t *AlpacaTrader) cancelOrdersByType(symbol string, orderType string) error
 // FormatQuantity formats quantity (stocks use whole shares typically)
 func (t *AlpacaTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	// Alpaca supports fractional shares
-	return strconv.FormatFloat(quantity, 'f', 6, 64), nil
+	return market.FormatQuantityStep(quantity, market.IncrementFromPlaces(6)), nil
+}
+
+// alpacaCryptoStopLimitOffset limit price distance below a crypto stop trigger (crypto positions are long only)
+const alpacaCryptoStopLimitOffset = 0.01
+
+// alpacaSymbol Alpaca order symbol of a canonical symbol (BTCUSDT -> BTC/USD, stocks unchanged)
+func alpacaSymbol(symbol string) string {
+	return market.ToExchangeSymbol("alpaca", symbol)
+}
+
+// alpacaPositionSymbol symbol in position URLs, which take crypto pairs without the slash (BTCUSD)
+func alpacaPositionSymbol(symbol string) string {
+	return strings.ReplaceAll(alpacaSymbol(symbol), "/", "")
+}
+
+// alpacaTimeInForce day orders for stocks; crypto trades around the clock and only accepts gtc/ioc
+func alpacaTimeInForce(symbol string) string {
+	if market.IsCrypto(symbol) {
+		return "gtc"
+	}
+	return "day"
+}
+
+// alpacaPrice order price: whole cents for stocks, registry tick size for crypto pairs
+func alpacaPrice(symbol string, price float64) string {
+	if market.IsCrypto(symbol) {
+		return market.Symbols.FormatPrice(symbol, price)
+	}
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+// errAlpacaCryptoShort short selling is not possible for Alpaca crypto
+func errAlpacaCryptoShort(symbol string) error {
+	return fmt.Errorf("Alpaca crypto is spot only, short selling %s is not supported", symbol)
+}
+
+// alpacaQtyDecimals maximum decimals of a fractional order quantity
+const alpacaQtyDecimals = 9
+
+// alpacaQty order quantity rounded down to Alpaca's fractional precision
+func alpacaQty(quantity float64) string {
+	return market.FloorToIncrement(quantity, market.IncrementFromPlaces(alpacaQtyDecimals)).String()
+}
+
+// wholeShares quantity rounded down to whole shares
+func wholeShares(quantity float64) float64 {
+	return market.FloorToIncrement(quantity, market.IncrementFromPlaces(0)).InexactFloat64()
 }
 
 // GetOrderStatus gets the status of an order
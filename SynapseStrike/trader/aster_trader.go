@@ -9,7 +9,7 @@ import (
 	"fmt"
 	"io"
 	"SynapseStrike/logger"
-	"math"
+	"SynapseStrike/market"
 	"math/big"
 	"net/http"
 	"net/url"
@@ -150,19 +150,6 @@ func (t *AsterTrader) getPrecision(symbol string) (SymbolPrecision, error) {
 	return SymbolPrecision{}, fmt.Errorf("precision information not found for symbol %s", symbol)
 }
 
-// roundToTickSize Round price/quantity to the nearest multiple of tick size/step size
-func roundToTickSize(value float64, tickSize float64) float64 {
-	if tickSize <= 0 {
-		return value
-	}
-	// Calculate how many tick sizes
-	steps := value / tickSize
-	// Round to the nearest integer
-	roundedSteps := math.Round(steps)
-	// Multiply back by tick size
-	return roundedSteps * tickSize
-}
-
 // formatPrice Format price to correct precision and tick size
 func (t *AsterTrader) formatPrice(symbol string, price float64) (float64, error) {
 	prec, err := t.getPrecision(symbol)
@@ -171,16 +158,15 @@ func (t *AsterTrader) formatPrice(symbol string, price float64) (float64, error)
 	}
 
 	// Prioritize tick size to ensure price is a multiple of tick size
-	if prec.TickSize > 0 {
-		return roundToTickSize(price, prec.TickSize), nil
+	tick := market.IncrementFromFloat(prec.TickSize)
+	if prec.TickSize <= 0 {
+		// If no tick size, round by precision
+		tick = market.IncrementFromPlaces(prec.PricePrecision)
 	}
-
-	// If no tick size, round by precision
-	multiplier := math.Pow10(prec.PricePrecision)
-	return math.Round(price*multiplier) / multiplier, nil
+	return market.RoundToIncrement(price, tick).InexactFloat64(), nil
 }
 
-// formatQuantity Format quantity to correct precision and step size
+// formatQuantity Format quantity to correct precision and step size (rounded down)
 func (t *AsterTrader) formatQuantity(symbol string, quantity float64) (float64, error) {
 	prec, err := t.getPrecision(symbol)
 	if err != nil {
@@ -188,13 +174,12 @@ func (t *AsterTrader) formatQuantity(symbol string, quantity float64) (float64,
 	}
 
 	// Prioritize step size to ensure quantity is a multiple of step size
-	if prec.StepSize > 0 {
-		return roundToTickSize(quantity, prec.StepSize), nil
+	step := market.IncrementFromFloat(prec.StepSize)
+	if prec.StepSize <= 0 {
+		// If no step size, round by precision
+		step = market.IncrementFromPlaces(prec.QuantityPrecision)
 	}
-
-	// If no step size, round by precision
-	multiplier := math.Pow10(prec.QuantityPrecision)
-	return math.Round(quantity*multiplier) / multiplier, nil
+	return market.FloorToIncrement(quantity, step).InexactFloat64(), nil
 }
 
 // formatFloatWithPrecision Format float to string with specified precision (remove trailing zeros)
@@ -202,9 +187,11 @@ func (t *AsterTrader) formatFloatWithPrecision(value float64, precision int) str
 	// Format with specified precision
 	formatted := strconv.FormatFloat(value, 'f', precision, 64)
 
-	// Remove trailing zeros and decimal point (if any)
-	formatted = strings.TrimRight(formatted, "0")
-	formatted = strings.TrimRight(formatted, ".")
+	// Remove trailing zeros and decimal point of the fraction only ("100" stays "100")
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(formatted, "0")
+		formatted = strings.TrimRight(formatted, ".")
+	}
 
 	return formatted
 }
@@ -489,17 +476,14 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 	totalMarginUsed := 0.0
 	realUnrealizedPnl := 0.0
 	for _, pos := range positions {
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
+		markPrice := pos.MarkPrice
+		quantity := pos.Qty
+		unrealizedPnl := pos.UnrealizedPnL
 		realUnrealizedPnl += unrealizedPnl
 
 		leverage := 10
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
 		marginUsed := (quantity * markPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
@@ -520,7 +504,7 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions Get position information
-func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *AsterTrader) GetPositions() ([]Position, error) {
 	params := make(map[string]interface{})
 	body, err := t.request("GET", "/fapi/v3/positionRisk", params)
 	if err != nil {
@@ -532,7 +516,7 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	result := []map[string]interface{}{}
+	result := []Position{}
 	for _, pos := range positions {
 		posAmtStr, ok := pos["positionAmt"].(string)
 		if !ok {
@@ -544,11 +528,12 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 			continue // Skip empty positions
 		}
 
-		entryPrice, _ := strconv.ParseFloat(pos["entryPrice"].(string), 64)
-		markPrice, _ := strconv.ParseFloat(pos["markPrice"].(string), 64)
-		unRealizedProfit, _ := strconv.ParseFloat(pos["unRealizedProfit"].(string), 64)
-		leverageVal, _ := strconv.ParseFloat(pos["leverage"].(string), 64)
-		liquidationPrice, _ := strconv.ParseFloat(pos["liquidationPrice"].(string), 64)
+		entryPrice, _ := SafeFloat64(pos, "entryPrice")
+		markPrice, _ := SafeFloat64(pos, "markPrice")
+		unRealizedProfit, _ := SafeFloat64(pos, "unRealizedProfit")
+		leverageVal, _ := SafeFloat64(pos, "leverage")
+		liquidationPrice, _ := SafeFloat64(pos, "liquidationPrice")
+		symbol, _ := SafeString(pos, "symbol")
 
 		// Determine direction (consistent with Binance)
 		side := "long"
@@ -557,16 +542,15 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 			posAmt = -posAmt
 		}
 
-		// Return same field names as Binance
-		result = append(result, map[string]interface{}{
-			"symbol":           pos["symbol"],
-			"side":             side,
-			"positionAmt":      posAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": unRealizedProfit,
-			"leverage":         leverageVal,
-			"liquidationPrice": liquidationPrice,
+		result = append(result, Position{
+			Symbol:        symbol,
+			Side:          side,
+			Qty:           posAmt,
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: unRealizedProfit,
+			Leverage:      leverageVal,
+			LiqPrice:      liquidationPrice,
 		})
 	}
 
@@ -729,8 +713,8 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -811,9 +795,9 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
+			if pos.Symbol == symbol && pos.Side == "short" {
 				// Aster's GetPositions has already converted short position quantity to positive, use directly
-				quantity = pos["positionAmt"].(float64)
+				quantity = pos.Qty
 				break
 			}
 		}
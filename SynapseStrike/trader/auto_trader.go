@@ -5,32 +5,41 @@ import (
 	"SynapseStrike/logger"
 	"SynapseStrike/market"
 	"SynapseStrike/mcp"
+	"SynapseStrike/notify"
 	"SynapseStrike/store"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // AutoTraderConfig auto trading configuration (simplified version - AI makes all decisions)
 type AutoTraderConfig struct {
 	// Trader identification
-	ID      string // Trader unique identifier (for log directory, etc.)
-	Name    string // Trader display name
-	AIModel string // AI model: "qwen" or "deepseek"
+	ID        string // Trader unique identifier (for log directory, etc.)
+	Name      string // Trader display name
+	AIModel   string // AI model: "qwen" or "deepseek"
+	AIModelID string // AI model config ID (skipped when resolving ensemble members)
 
 	// Trading platform selection
-	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "hyperliquid", "aster" or "lighter"
+	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "hyperliquid", "aster", "lighter", "dydx", "ccxt", or spot "binance-spot"/"coinbase"
 	ExchangeID string // Exchange account UUID (for multi-account support)
 
 	// Binance API configuration
 	BinanceAPIKey    string
 	BinanceSecretKey string
+	BinanceTestnet   bool // Whether to use testnet
+
+	// Coinbase Advanced Trade configuration (spot)
+	CoinbaseAPIKeyName    string // CDP API key name ("organizations/.../apiKeys/...")
+	CoinbasePrivateKeyPEM string // CDP API key EC private key
 
 	// Bybit API configuration
 	BybitAPIKey    string
 	BybitSecretKey string
+	BybitTestnet   bool // Whether to use testnet
 
 	// OKX API configuration
 	OKXAPIKey     string
@@ -59,6 +68,21 @@ type AutoTraderConfig struct {
 	LighterAPIKeyIndex      int    // LIGHTER API Key index (0-255)
 	LighterTestnet          bool   // Whether to use testnet
 
+	// dYdX configuration
+	DydxMnemonic   string // dYdX wallet mnemonic (for transaction signing)
+	DydxSubaccount int    // dYdX subaccount number
+	DydxTestnet    bool   // Whether to use testnet
+
+	// CCXT bridge configuration (exchanges reached through a CCXT-compatible gateway)
+	CCXTGatewayURL string // Gateway base URL
+	CCXTExchangeID string // CCXT exchange id, e.g. "krakenfutures", "gate", "mexc"
+	CCXTAPIKey     string
+	CCXTSecret     string
+	CCXTPassword   string // Passphrase for exchanges that require one
+
+	// Collateral currency of the exchange account (USDT/USDC/EUR/USD, empty = exchange default)
+	QuoteCurrency string
+
 	// AI configuration
 	UseQwen     bool
 	DeepSeekKey string
@@ -89,7 +113,23 @@ type AutoTraderConfig struct {
 	// Market hours trading restriction
 	TradeOnlyMarketHours bool // If true, only trade during stock market hours (9:30 AM - 4:00 PM ET)
 
+	// Shadow mode (run full pipeline, execute on virtual ledger instead of exchange)
+	ShadowMode bool
+
+	// Trading schedule: time-of-day / day-of-week windows gating decision cycles (nil = always)
+	TradingSchedule *store.TradingSchedule
+
+	// Symbol allow/deny lists gating new entries (nil = any symbol), editable at runtime
+	SymbolLists *store.SymbolLists
+
+	// AI latency budget: total time allowed for AI calls in one cycle (0 = 80% of scan interval)
+	AICycleTimeout time.Duration
+
+	// Directory of the executables strategies may run as context hook scripts
+	ContextHooksDir string
+
 	// Strategy configuration (use complete strategy config)
+	StrategyID     string                // Strategy ID (used to route hot-reloads from strategy edits)
 	StrategyConfig *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
 }
 
@@ -100,6 +140,7 @@ type AutoTrader struct {
 	aiModel               string // AI model name
 	exchange              string // Trading platform type (binance/bybit/etc)
 	exchangeID            string // Exchange account UUID
+	quoteCurrency         string // Currency of exchange balances (converted to USD by getBalance)
 	showInCompetition     bool   // Whether to show in competition page
 	config                AutoTraderConfig
 	trader                Trader // Use Trader interface (supports multiple platforms)
@@ -113,7 +154,7 @@ type AutoTrader struct {
 	overrideBasePrompt    bool   // Whether to override base prompt
 	lastResetTime         time.Time
 	stopUntil             time.Time
-	isRunning             bool
+	isRunning             atomic.Bool        // Read by the API, monitors and the execution loop
 	startTime             time.Time          // System start time
 	callCount             int                // AI call count
 	positionFirstSeenTime map[string]int64   // Position first seen time (symbol_side -> timestamp in milliseconds)
@@ -132,6 +173,103 @@ type AutoTrader struct {
 	// ATR-based TP/SL price cache (from Genetic/VWAPer algo decisions)
 	positionTPSL      map[string][2]float64 // symbol_side -> [TakeProfit, StopLoss] prices
 	positionTPSLMutex sync.RWMutex          // Mutex for positionTPSL map
+
+	// Strategy hot-reload (see strategy_reload.go)
+	strategyMu            sync.RWMutex          // Protects pending strategy and versions
+	pendingStrategy       *store.StrategyConfig // Staged config, swapped in at next cycle start
+	strategyVersion       int                   // Latest accepted config version
+	activeStrategyVersion int                   // Config version currently in use
+	strategyUpdatedAt     time.Time             // When active config was applied
+
+	// Decision memory: similar past setups with outcomes (see decision_memory.go)
+	memory *decision.MemoryBank
+
+	// Session memory: running thesis of the last cycles (see session_memory.go)
+	sessionMemory *decision.SessionMemory
+
+	// Shutdown policy / runtime state persistence (see shutdown.go)
+	resumeNote     string    // Note about interrupted cycle from previous run, logged with next decision record
+	cycleStartedAt time.Time // Start time of the in-flight cycle
+
+	// Max holding time tracking (see max_hold.go)
+	maxHoldMu        sync.Mutex
+	maxHoldReviews   map[string]time.Time // symbol_side -> forced AI review requested at
+	maxHoldFirstSeen map[string]time.Time // symbol_side -> first seen by monitor (no entry time available)
+
+	// Liquidation proximity alerts (see liquidation_guard.go)
+	liquidationMu      sync.Mutex
+	liquidationAlerted map[string]bool // symbol_side -> alert sent for current approach
+
+	// Equity curve risk scaling (see equity_risk.go)
+	equityRiskMu sync.Mutex
+	equityRisk   *decision.EquityRiskState // State of the last cycle (nil = disabled)
+
+	// End-of-day policy (see eod_policy.go)
+	eodMu        sync.Mutex
+	eodHandled   map[string]string  // symbol_side -> ET date the EOD policy ran
+	sessionOpens map[string]float64 // symbol_date -> 9:30 ET opening price
+
+	// Real-time position updates from exchange websockets (see position_stream.go)
+	drawdownMu      sync.Mutex                 // Serializes drawdown checks of the interval monitor and the stream
+	streamMu        sync.Mutex                 // Guards streamPositions
+	streamPositions map[string]*streamPosition // symbol_side -> last known position state
+
+	// Ensemble decision mode and batch failover model (see ensemble.go)
+	ensembleClients map[string]*ensembleClient // AI model ID -> cached client
+
+	// Limit entries from entry_price decisions (see limit_entry.go)
+	limitEntriesMu       sync.Mutex
+	limitEntries         []*pendingLimitEntry      // Resting entry orders
+	resolvedLimitEntries []decision.LimitEntryInfo // Filled/expired since last cycle, reported once in the next context
+
+	// Manual decisions are not executed while a cycle runs (see manual_decision.go)
+	cycleMu sync.Mutex
+
+	// Cycle-over-cycle decision comparator (see churn_guard.go)
+	churnMu      sync.Mutex
+	churnHistory map[string]*decision.PreviousDecision // symbol -> last decision and last open
+
+	// Symbol allow/deny lists (see symbol_lists.go)
+	symbolListsMu sync.RWMutex
+	symbolLists   *store.SymbolLists
+
+	// Last successful AI decision, reused on AI failure for an unchanged context (see ai_response_cache.go)
+	aiCacheMu sync.Mutex
+	aiCache   *cachedAIResponse
+
+	// Trade frequency governor (see trade_governor.go)
+	governorMu       sync.Mutex
+	governorSeeded   bool                 // governorOpens loaded from the position store
+	governorOpens    []decision.TradeOpen // Executed opens of the last 24h
+	governorRejected []string             // Opens rejected since the last cycle, reported in the next prompt
+
+	// Maximum favorable/adverse excursion of open positions (see trade_excursion.go)
+	excursionMu sync.Mutex
+	excursions  map[string]*positionExcursion // symbol_side -> running MFE/MAE
+
+	// Dependency health probes for /readyz (see health.go)
+	healthMu              sync.Mutex
+	lastHealth            *TraderHealth // Cached probe result
+	lastMarketDataAt      time.Time     // When the last cycle's market data was fetched
+	lastMarketDataSymbols int           // Number of symbols fetched by the last cycle
+
+	// Buy-and-hold benchmark base (see benchmark.go), loaded with the first snapshot of a symbol
+	benchmarkBase benchmarkBase
+
+	// Equity floor halt (see equity_floor.go), cleared only by ResetEquityFloor
+	floorMu         sync.Mutex
+	floorHaltedAt   time.Time
+	floorHaltReason string
+
+	// Operator pause (see pause.go): opens are rejected until Resume
+	pauseMu     sync.Mutex
+	pausedAt    time.Time
+	pausedBy    string
+	pauseReason string
+
+	// Per-symbol data of the last cycle's context (see symbol_view.go)
+	symbolViewMu sync.RWMutex
+	symbolView   *SymbolSnapshot
 }
 
 // NewAutoTrader creates an automatic trader
@@ -242,10 +380,21 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	switch config.Exchange {
 	case "binance":
 		logger.Infof("🏦 [%s] Using Binance Futures trading", config.Name)
-		trader = NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, userID)
+		trader = NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, userID, config.BinanceTestnet)
+	case "binance-spot":
+		logger.Infof("🏦 [%s] Using Binance Spot trading", config.Name)
+		trader = NewBinanceSpotTrader(config.BinanceAPIKey, config.BinanceSecretKey,
+			ResolveQuoteCurrency(config.Exchange, config.QuoteCurrency), config.BinanceTestnet)
+	case "coinbase":
+		logger.Infof("🏦 [%s] Using Coinbase Spot trading", config.Name)
+		trader, err = NewCoinbaseSpotTrader(config.CoinbaseAPIKeyName, config.CoinbasePrivateKeyPEM,
+			ResolveQuoteCurrency(config.Exchange, config.QuoteCurrency))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Coinbase trader: %w", err)
+		}
 	case "bybit":
 		logger.Infof("🏦 [%s] Using Bybit Futures trading", config.Name)
-		trader = NewBybitTrader(config.BybitAPIKey, config.BybitSecretKey)
+		trader = NewBybitTrader(config.BybitAPIKey, config.BybitSecretKey, config.BybitTestnet)
 	case "okx":
 		logger.Infof("🏦 [%s] Using OKX Futures trading", config.Name)
 		trader = NewOKXTrader(config.OKXAPIKey, config.OKXSecretKey, config.OKXPassphrase)
@@ -282,6 +431,26 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 			return nil, fmt.Errorf("failed to initialize LIGHTER trader: %w", err)
 		}
 		logger.Infof("✓ LIGHTER trader initialized successfully")
+	case "dydx":
+		logger.Infof("🏦 [%s] Using dYdX v4 trading", config.Name)
+
+		if config.DydxMnemonic == "" {
+			return nil, fmt.Errorf("dYdX requires wallet mnemonic")
+		}
+
+		dydxTrader, err := NewDydxTrader(config.DydxMnemonic, config.DydxSubaccount, config.DydxTestnet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize dYdX trader: %w", err)
+		}
+		trader = dydxTrader
+		logger.Infof("✓ dYdX trader initialized successfully (address: %s, subaccount: %d)", dydxTrader.Address(), config.DydxSubaccount)
+	case "ccxt":
+		logger.Infof("🏦 [%s] Using %s via CCXT bridge (%s)", config.Name, config.CCXTExchangeID, config.CCXTGatewayURL)
+		trader, err = NewCCXTBridgeTrader(config.CCXTGatewayURL, config.CCXTExchangeID, config.CCXTAPIKey, config.CCXTSecret, config.CCXTPassword,
+			ResolveQuoteCurrency(config.Exchange, config.QuoteCurrency))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize CCXT bridge trader: %w", err)
+		}
 	case "alpaca", "alpaca-live":
 		logger.Infof("🏦 [%s] Using Alpaca (Live) stock trading", config.Name)
 		trader = NewAlpacaTrader(config.BinanceAPIKey, config.BinanceSecretKey, false)
@@ -292,10 +461,18 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("unsupported trading platform: %s", config.Exchange)
 	}
 
+	quoteCurrency := ResolveQuoteCurrency(config.Exchange, config.QuoteCurrency)
+	if quoteCurrency != market.ReportingCurrency {
+		logger.Infof("💱 [%s] Account balances in %s are reported in %s", config.Name, quoteCurrency, market.ReportingCurrency)
+	}
+
 	// Validate initial balance configuration, auto-fetch from exchange if 0
 	if config.InitialBalance <= 0 {
 		logger.Infof("📊 [%s] Initial balance not set, attempting to fetch current balance from exchange...", config.Name)
 		account, err := trader.GetBalance()
+		if err == nil {
+			account, err = ConvertBalanceToUSD(account, quoteCurrency)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("initial balance not set and unable to fetch balance from exchange: %w", err)
 		}
@@ -310,7 +487,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		}
 		if foundBalance > 0 {
 			config.InitialBalance = foundBalance
-			logger.Infof("✓ [%s] Auto-fetched initial balance: %.2f USDT", config.Name, foundBalance)
+			logger.Infof("✓ [%s] Auto-fetched initial balance: %.2f USD", config.Name, foundBalance)
 			// Save to database so it persists across restarts
 			if st != nil {
 				if err := st.Trader().UpdateInitialBalance(userID, config.ID, foundBalance); err != nil {
@@ -324,6 +501,25 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		}
 	}
 
+	// Shadow mode: wrap exchange trader with virtual position ledger
+	// Market data still comes from the real exchange, orders never reach it
+	var shadowTrader *ShadowTrader
+	if config.ShadowMode {
+		var startingCash float64
+		shadowTrader, startingCash = restoreShadowTrader(trader, st, config.ID, config.InitialBalance)
+		if st != nil {
+			traderID := config.ID
+			shadowTrader.SetProtectiveHandler(func(symbol, side string, stopLoss, takeProfit float64) {
+				if err := st.Position().SetProtectivePrices(traderID, symbol, side, stopLoss, takeProfit); err != nil {
+					logger.Warnf("⚠️ [Shadow] Failed to store SL/TP prices of %s %s: %v", symbol, side, err)
+				}
+			})
+		}
+		trader = shadowTrader
+		quoteCurrency = market.ReportingCurrency // Virtual ledger is kept in USD
+		logger.Infof("👻 [%s] Shadow mode enabled: decisions run on virtual ledger (cash: %.2f)", config.Name, startingCash)
+	}
+
 	// Get last cycle number (for recovery)
 	var cycleNumber int
 	if st != nil {
@@ -336,14 +532,17 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("[%s] strategy not configured", config.Name)
 	}
 	strategyEngine := decision.NewStrategyEngine(config.StrategyConfig)
+	strategyEngine.SetStrategyID(config.StrategyID)
+	mcpClient.SetGenerationParams(strategyEngine.GetGenerationParams())
 	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
 
-	return &AutoTrader{
+	at := &AutoTrader{
 		id:                    config.ID,
 		name:                  config.Name,
 		aiModel:               config.AIModel,
 		exchange:              config.Exchange,
 		exchangeID:            config.ExchangeID,
+		quoteCurrency:         quoteCurrency,
 		showInCompetition:     config.ShowInCompetition,
 		config:                config,
 		trader:                trader,
@@ -355,7 +554,6 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		lastResetTime:         time.Now(),
 		startTime:             time.Now(),
 		callCount:             0,
-		isRunning:             false,
 		positionFirstSeenTime: make(map[string]int64),
 		stopMonitorCh:         make(chan struct{}),
 		monitorWg:             sync.WaitGroup{},
@@ -365,14 +563,30 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		userID:                userID,
 		positionTPSL:          make(map[string][2]float64),
 		positionTPSLMutex:     sync.RWMutex{},
-	}, nil
+		strategyVersion:       1,
+		activeStrategyVersion: 1,
+		strategyUpdatedAt:     time.Now(),
+		memory:                newDecisionMemory(st, config.ID, mcpClient),
+		sessionMemory:         decision.NewSessionMemory(),
+		symbolLists:           config.SymbolLists,
+	}
+
+	if shadowTrader != nil {
+		shadowTrader.SetAutoCloseHandler(at.recordShadowAutoClose)
+	}
+
+	return at, nil
 }
 
 // Run runs the automatic trading main loop
 func (at *AutoTrader) Run() error {
-	at.isRunning = true
+	at.isRunning.Store(true)
 	at.stopMonitorCh = make(chan struct{})
 	at.startTime = time.Now()
+	at.sessionMemory.Reset()
+	at.restoreRuntimeState()
+	at.recoverOpenPositions()
+	at.resumeExecutionQueue()
 
 	logger.Info("🚀 AI-driven automatic trading system started")
 	logger.Infof("💰 Initial balance: %.2f USDT", at.initialBalance)
@@ -383,8 +597,8 @@ func (at *AutoTrader) Run() error {
 
 	// Check if VWAP algorithm is enabled
 	vwapEnabled := false
-	if at.strategyEngine != nil {
-		config := at.strategyEngine.GetConfig()
+	if at.engine() != nil {
+		config := at.engine().GetConfig()
 		if config != nil && config.Indicators.EnableVWAPSlopeStretch {
 			vwapEnabled = true
 			logger.Info("📊 VWAP + Slope & Stretch Algorithm enabled - will use 1-min intervals during pre-entry phase")
@@ -397,6 +611,7 @@ func (at *AutoTrader) Run() error {
 
 	// Start drawdown monitoring
 	at.startDrawdownMonitor()
+	at.startPositionStream()
 
 	// VWAP: Clean up any stale positions from previous days at startup
 	// This handles positions that should have been sold at 3:55 PM but weren't
@@ -414,8 +629,8 @@ func (at *AutoTrader) Run() error {
 		logger.Infof("📊 [VWAP] Pre-entry phase active - using 1-minute intervals until entry time")
 		logger.Infof("📊 [VWAP] Collecting initial VWAP data, no trading until entry time")
 		// Get candidate symbols from strategy engine
-		if at.strategyEngine != nil {
-			candidates, _ := at.strategyEngine.GetCandidateStocks()
+		if at.engine() != nil {
+			candidates, _ := at.engine().GetCandidateStocks()
 			var symbols []string
 			for _, c := range candidates {
 				symbols = append(symbols, c.Symbol)
@@ -439,7 +654,7 @@ func (at *AutoTrader) Run() error {
 		}
 	}
 
-	for at.isRunning {
+	for at.isRunning.Load() {
 		select {
 		case <-ticker.C:
 			// Check market hours if enabled
@@ -449,86 +664,22 @@ func (at *AutoTrader) Run() error {
 			}
 
 			// PER-ALGO MARKET CLOSE CHECK
-			// Only auto-close positions before market close if the strategy has CloseAtEOD enabled.
+			// Only apply the end-of-day policy before market close if the strategy has CloseAtEOD enabled.
 			// Behavior per algo type (configurable in Strategy Studio > Risk Control > "Close at EOD"):
 			//   - VWAPer:       CloseAtEOD = true  (day-trade strategy, no overnight holds)
 			//   - Scalper:      CloseAtEOD = true  (intraday scalping, no overnight risk)
 			//   - Swing/Custom: CloseAtEOD = false (positions may be held overnight)
-			// When disabled, positions are NOT closed at market close and carry overnight.
-			shouldCloseAtEOD := true       // default: close (backward compatible)
-			eodCloseTime := "15:55"        // default: 3:55 PM ET
-			if at.strategyEngine != nil {
-				cfg := at.strategyEngine.GetConfig()
-				if cfg != nil {
-					shouldCloseAtEOD = cfg.RiskControl.CloseAtEOD
-					if cfg.RiskControl.CloseAtEODTime != "" {
-						eodCloseTime = cfg.RiskControl.CloseAtEODTime
+			// The EOD policy (flatten / reduce / hold-stops) decides what happens in the window, see eod_policy.go.
+			// When disabled, positions are NOT closed at market close and carry overnight (tagged for gap analysis).
+			if at.config.TradeOnlyMarketHours && isMarketOpen() {
+				eod := at.eodPolicy()
+				if timeToClose, inWindow := eod.window(); inWindow {
+					if eod.closeAtEOD {
+						at.applyEODPolicy(eod, timeToClose)
+						// Skip normal trading cycle during market close window
+						continue
 					}
-				}
-			}
-			if shouldCloseAtEOD && at.config.TradeOnlyMarketHours && isMarketOpen() {
-				loc, _ := time.LoadLocation("America/New_York")
-				now := time.Now().In(loc)
-				currentMinutes := now.Hour()*60 + now.Minute()
-
-				// Parse configurable close time (HH:MM format)
-				var eodHour, eodMin int
-				fmt.Sscanf(eodCloseTime, "%d:%d", &eodHour, &eodMin)
-				if eodHour == 0 && eodMin == 0 {
-					eodHour, eodMin = 15, 55 // fallback default
-				}
-				marketCloseMinutes := eodHour*60 + eodMin
-				timeToClose := 16*60 - currentMinutes
-				
-				if currentMinutes >= marketCloseMinutes && currentMinutes < 16*60 {
-					logger.Infof("🔔 [AUTO-CLOSE] Market closing in %d minutes - checking for positions to close", timeToClose)
-					
-					// Get all current positions
-					positions, err := at.trader.GetPositions()
-					if err != nil {
-						logger.Infof("⚠️ [AUTO-CLOSE] Failed to get positions: %v", err)
-					} else if len(positions) > 0 {
-						logger.Infof("🔔 [AUTO-CLOSE] Found %d open positions - closing all before market close", len(positions))
-						
-						for _, pos := range positions {
-							symbol := pos["symbol"].(string)
-							side := pos["side"].(string)
-							
-							// Calculate PnL for logging
-							entryPrice := 0.0
-							markPrice := 0.0
-							if ep, ok := pos["entryPrice"].(float64); ok {
-								entryPrice = ep
-							}
-							if mp, ok := pos["markPrice"].(float64); ok {
-								markPrice = mp
-							}
-							
-							pnlPct := 0.0
-							if entryPrice > 0 && markPrice > 0 {
-								if side == "long" || side == "buy" {
-									pnlPct = ((markPrice - entryPrice) / entryPrice) * 100
-								} else {
-									pnlPct = ((entryPrice - markPrice) / entryPrice) * 100
-								}
-							}
-							
-							logger.Infof("🔔 [AUTO-CLOSE] Closing %s %s at %.2f%% PnL (market closes in %d min)", 
-								symbol, side, pnlPct, timeToClose)
-							
-							reasoning := fmt.Sprintf("Auto-close before market close at 4:00 PM ET (closes in %d min) | PnL: %.2f%%", timeToClose, pnlPct)
-							if err := at.closePositionWithReason(symbol, side, "market_close", reasoning); err != nil {
-								logger.Infof("❌ [AUTO-CLOSE] Failed to close %s: %v", symbol, err)
-							} else {
-								logger.Infof("✅ [AUTO-CLOSE] Successfully closed %s before market close", symbol)
-							}
-						}
-					} else {
-						logger.Infof("📊 [AUTO-CLOSE] No positions to close (%d min to market close)", timeToClose)
-					}
-					
-					// Skip normal trading cycle during market close window
-					continue
+					at.tagOvernightHoldings()
 				}
 			}
 
@@ -556,8 +707,8 @@ func (at *AutoTrader) Run() error {
 
 					logger.Infof("📊 [VWAP] Pre-entry phase - collecting data, skipping trading until entry time")
 					// Get candidate symbols from strategy engine
-					if at.strategyEngine != nil {
-						candidates, _ := at.strategyEngine.GetCandidateStocks()
+					if at.engine() != nil {
+						candidates, _ := at.engine().GetCandidateStocks()
 						var symbols []string
 						for _, c := range candidates {
 							symbols = append(symbols, c.Symbol)
@@ -592,18 +743,36 @@ func (at *AutoTrader) Run() error {
 
 // Stop stops the automatic trading
 func (at *AutoTrader) Stop() {
-	if !at.isRunning {
+	if !at.isRunning.CompareAndSwap(true, false) {
 		return
 	}
-	at.isRunning = false
 	close(at.stopMonitorCh) // Notify monitoring goroutine to stop
 	at.monitorWg.Wait()     // Wait for monitoring goroutine to finish
+
+	// Wait for an in-flight cycle or manual decision, nothing may open positions while the policy runs
+	at.cycleMu.Lock()
+	defer at.cycleMu.Unlock()
+
+	// Resting limit entries are not tracked across restarts, cancel them
+	at.cancelLimitEntries("trader stopped")
+
+	// Apply shutdown policy once the in-flight cycle has finished
+	policy := at.applyShutdownPolicy()
+	at.saveRuntimeStateWith(&store.TraderRuntimeState{
+		CyclePhase:     store.CyclePhaseIdle,
+		CleanShutdown:  true,
+		ShutdownPolicy: policy,
+	})
 	logger.Info("⏹ Automatic trading system stopped")
 }
 
 // runCycle runs one trading cycle (using AI full decision-making)
 func (at *AutoTrader) runCycle() error {
+	at.cycleMu.Lock()
+	defer at.cycleMu.Unlock()
 	at.callCount++
+	cycleStart := time.Now()
+	at.cycleStartedAt = cycleStart
 
 	logger.Info("\n" + strings.Repeat("=", 70) + "\n")
 	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
@@ -614,13 +783,32 @@ func (at *AutoTrader) runCycle() error {
 		ExecutionLog: []string{},
 		Success:      true,
 	}
+	if at.resumeNote != "" {
+		record.ExecutionLog = append(record.ExecutionLog, at.resumeNote)
+		at.resumeNote = ""
+	}
+
+	// 0. Apply strategy config staged by hot-reload (between cycles only, before the gates below can skip the cycle)
+	if version := at.applyPendingStrategy(); version > 0 {
+		logger.Infof("🔄 [%s] Strategy config v%d applied", at.name, version)
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🔄 Strategy config v%d applied", version))
+	}
 
 	// 1. Check if trading needs to be stopped
+	if reason := at.equityFloorHalt(); reason != "" {
+		logger.Infof("🧱 [%s] Equity floor: trading halted until manual reset", at.name)
+		record.Success = false
+		record.ErrorMessage = "Equity floor halt, manual reset required: " + reason
+		record.ErrorCategory = store.ErrorCategoryRiskBlock
+		at.saveDecision(record)
+		return nil
+	}
 	if time.Now().Before(at.stopUntil) {
 		remaining := at.stopUntil.Sub(time.Now())
 		logger.Infof("⏸ Risk control: Trading paused, remaining %.0f minutes", remaining.Minutes())
 		record.Success = false
 		record.ErrorMessage = fmt.Sprintf("Risk control paused, remaining %.0f minutes", remaining.Minutes())
+		record.ErrorCategory = store.ErrorCategoryRiskBlock
 		at.saveDecision(record)
 		return nil
 	}
@@ -636,24 +824,75 @@ func (at *AutoTrader) runCycle() error {
 		}
 	}
 
+	// 1.55. Check trading schedule windows
+	if allowed, reason := at.config.TradingSchedule.Check(time.Now()); !allowed {
+		logger.Infof("🗓️ %s. Skipping trading cycle.", reason)
+		record.Success = false
+		record.ErrorMessage = reason
+		at.saveDecision(record)
+		return nil
+	}
+
+	// 1.57. Exchange maintenance window (defer action skips the cycle)
+	if at.deferForMaintenance(record) {
+		at.saveDecision(record)
+		return nil
+	}
+
 	// 2. Reset daily P&L (reset every day)
 	if time.Since(at.lastResetTime) > 24*time.Hour {
+		at.notifyDailySummary(at.lastResetTime)
 		at.dailyPnL = 0
 		at.lastResetTime = time.Now()
 		logger.Info("📅 Daily P&L reset")
 	}
 
-	// 4. Collect trading context
+	// Persist in-flight cycle state (phase returns to idle when the cycle ends)
+	at.saveRuntimeState(store.CyclePhaseDeciding, nil)
+	defer at.saveRuntimeState(store.CyclePhaseIdle, nil)
+
+	// 4. Resolve limit entries (fills get SL/TP, expired ones are cancelled), then collect trading context
+	at.checkLimitEntries()
 	ctx, err := at.buildTradingContext()
 	if err != nil {
 		record.Success = false
 		record.ErrorMessage = fmt.Sprintf("Failed to build trading context: %v", err)
+		record.ErrorCategory = cycleErrorCategory(err)
+		if IsRateLimitError(err) {
+			record.ErrorMessage = fmt.Sprintf("Exchange rate limited, cycle skipped: %v", err)
+		}
 		at.saveDecision(record)
 		return fmt.Errorf("failed to build trading context: %w", err)
 	}
 
 	// Save equity snapshot independently (decoupled from AI decision, used for drawing profit curve)
 	at.saveEquitySnapshot(ctx)
+	at.updateEquityRisk(ctx)
+	if at.checkEquityFloor(ctx.Account.TotalEquity, record) {
+		at.saveDecision(record)
+		return nil
+	}
+
+	ctx.LimitEntries = at.limitEntryContext()
+	for _, entry := range ctx.LimitEntries {
+		if entry.Status != limitEntryPending {
+			record.ExecutionLog = append(record.ExecutionLog, formatLimitEntryUpdate(entry))
+		}
+	}
+
+	// Operator pause: the cycle still manages positions, opens are rejected at execution
+	if reason := at.pauseReasonIfPaused(); reason != "" {
+		ctx.OpensPaused = reason
+		record.ExecutionLog = append(record.ExecutionLog, "⏸ Trader paused ("+reason+"), new opens blocked")
+	}
+
+	// Exchange maintenance (monitor action): the cycle runs, orders are rejected at execution
+	at.maintenanceContext(ctx, record)
+
+	// Grid tactic: level orders and leg take profits are managed before the Local Function decision
+	var gridLog []string
+	ctx.Grids, gridLog = at.runGridEngine()
+	record.ExecutionLog = append(record.ExecutionLog, gridLog...)
 
 	logger.Info(strings.Repeat("=", 70))
 	for _, stock := range ctx.CandidateStocks {
@@ -665,16 +904,64 @@ func (at *AutoTrader) runCycle() error {
 
 	// 5. Use strategy engine to call AI for decision
 	logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
-	aiDecision, err := decision.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	ctx.Deadline = cycleStart.Add(at.aiCycleTimeout())
+	if at.decisionMemoryEnabled() {
+		ctx.Memory = at.memory
+	}
+	ctx.FailoverClient = at.failoverClient()
+	ctx.RepairClient = at.repairClient()
+	at.loadAnnotations(ctx)
+	at.previousDecisionContext(ctx)
+	at.sessionMemoryContext(ctx)
+	at.tradeBudgetContext(ctx)
+	for _, failure := range at.runContextHooks(ctx) {
+		record.ExecutionLog = append(record.ExecutionLog, "🔌 Context hook failed: "+failure)
+	}
+	at.snapshotSymbols(ctx)
+	aiDecision, err := at.getAIDecision(ctx)
+	at.recordMarketDataFetch(len(ctx.MarketDataMap))
+	skipOnTimeout := false
+	if err != nil {
+		// Categorized even when the cache or the fallback covers the failure
+		record.ErrorCategory = decision.ErrorCategory(err)
+	}
+	if err != nil && decision.IsAITimeout(err) {
+		skipOnTimeout = at.recordAITimeout(record, err)
+	}
+	if err == nil {
+		at.rememberAIResponse(ctx, aiDecision)
+	} else if reused := at.reuseAIResponse(ctx, aiDecision, err); reused != nil {
+		// Provider flapping but nothing changed: reuse the last AI decision instead of the algorithm
+		logger.Infof("♻️ AI decision failed (%v), reusing cached decision for unchanged context", err)
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("♻️ AI cache: reused previous AI decision for unchanged context (AI failure: %v)", err))
+		at.publishEvent(notify.EventAIFallback, "", "♻️ AI decision failed, previous decision reused for unchanged context", err.Error(), nil)
+		aiDecision = reused
+		err = nil
+	}
+
+	// AI timeout with skip policy: no trades this cycle (decision record flagged ai_timeout)
+	if err != nil && skipOnTimeout {
+		if aiDecision != nil {
+			record.SystemPrompt = aiDecision.SystemPrompt
+			record.InputPrompt = aiDecision.UserPrompt
+			record.CoTTrace = aiDecision.CoTTrace
+		}
+		at.saveDecision(record)
+		return nil
+	}
 
 	// [Bulletproof] Trigger Algorithmic Fallback if AI decision fails for ANY reason
 	// This covers: API errors (429, 5xx), network failures, parse errors, quota exhaustion, etc.
 	if err != nil {
 		aiErrMsg := err.Error()
+		failure := "AI failure"
+		if record.AITimeout {
+			failure = "AI timeout"
+		}
 		logger.Warnf("⚠️ AI Decision Failure detected: %v", err)
 		logger.Infof("🛡️ [Bulletproof] Triggering Algorithmic Fallback...")
 
-		fallbackDecision, fallbackErr := decision.GetAlgorithmicDecision(ctx, at.strategyEngine)
+		fallbackDecision, fallbackErr := decision.GetAlgorithmicDecision(ctx, at.engine())
 		if fallbackErr != nil {
 			logger.Errorf("❌ Fallback failed with error: %v", fallbackErr)
 		} else if fallbackDecision == nil {
@@ -689,15 +976,23 @@ func (at *AutoTrader) runCycle() error {
 				fallbackDecision.SystemPrompt = aiDecision.SystemPrompt
 				fallbackDecision.UserPrompt = aiDecision.UserPrompt
 				fallbackDecision.RawResponse = aiDecision.RawResponse
+				fallbackDecision.Validation = aiDecision.Validation
+				fallbackDecision.SelfReview = aiDecision.SelfReview
+				fallbackDecision.Ensemble = aiDecision.Ensemble
+				fallbackDecision.PromptCompression = aiDecision.PromptCompression
 			} else {
 				fallbackDecision.CoTTrace = fmt.Sprintf("AI Error: %s", aiErrMsg)
 			}
 			aiDecision = fallbackDecision
 			err = nil // Clear error as we have a fallback decision
-			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("Fallback: Triggered technical algorithm due to AI failure (%s)", aiErrMsg))
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("Fallback: Triggered technical algorithm due to %s (%s)", failure, aiErrMsg))
+			at.publishEvent(notify.EventAIFallback, "", "🛡️ AI decision failed, algorithmic fallback engaged", aiErrMsg, nil)
 		}
 	}
 
+	// Market shock: mark the cycle and tighten stops (opens were already blocked)
+	at.applyShockMode(ctx, record)
+
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
 		logger.Infof("⏱️ AI call duration: %.2f seconds", float64(record.AIRequestDurationMs)/1000)
@@ -705,6 +1000,60 @@ func (at *AutoTrader) runCycle() error {
 			fmt.Sprintf("AI call duration: %d ms", record.AIRequestDurationMs))
 	}
 
+	// Record candidates removed by the symbol allow/deny lists
+	if len(ctx.SymbolListBlocked) > 0 {
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("📃 Symbol lists blocked %d candidates: %s",
+			len(ctx.SymbolListBlocked), strings.Join(ctx.SymbolListBlocked, ", ")))
+	}
+
+	// Record candidates cut by opportunity ranking
+	if ctx.CandidateRanking != nil && len(ctx.CandidateRanking.Cut) > 0 {
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✂️ Candidate ranking cut %d low scorers: %s",
+			len(ctx.CandidateRanking.Cut), strings.Join(ctx.CandidateRanking.Cut, ", ")))
+	}
+
+	// Structured list of candidates that vanished before the AI decision, with the reason
+	record.FilteredOut = filteredCandidates(ctx)
+
+	// Feeds that failed this cycle (data completeness report, also summarized in the prompt)
+	record.DataGaps = ctx.DataGaps
+
+	// Record what was dropped to fit the prompt into the token budget
+	if aiDecision != nil {
+		for _, compression := range aiDecision.PromptCompression {
+			record.ExecutionLog = append(record.ExecutionLog, compression.String())
+		}
+	}
+
+	// Record dropped/repaired decisions from validation
+	if aiDecision != nil && aiDecision.Validation != nil {
+		for _, issue := range aiDecision.Validation.Issues {
+			record.ExecutionLog = append(record.ExecutionLog, issue.String())
+		}
+	}
+
+	// Record each ensemble model's output for comparison
+	if aiDecision != nil && aiDecision.Ensemble != nil {
+		for _, out := range aiDecision.Ensemble.Models {
+			record.ExecutionLog = append(record.ExecutionLog, formatEnsembleOutput(out))
+		}
+	}
+
+	// Record self-review verdicts
+	if aiDecision != nil && aiDecision.SelfReview != nil {
+		if aiDecision.SelfReview.Error != "" {
+			record.ExecutionLog = append(record.ExecutionLog, "⚠️ Self-review failed: "+aiDecision.SelfReview.Error)
+		}
+		for _, verdict := range aiDecision.SelfReview.Verdicts {
+			record.ExecutionLog = append(record.ExecutionLog, verdict.String())
+		}
+	}
+
+	// Flag or suppress direction flips on symbols opened within the last cycles
+	if aiDecision != nil && err == nil {
+		at.applyChurnGuard(ctx, aiDecision, record)
+	}
+
 	// Save chain of thought, decisions, and input prompt even if there's an error (for debugging)
 	if aiDecision != nil {
 		record.SystemPrompt = aiDecision.SystemPrompt // Save system prompt
@@ -751,11 +1100,11 @@ func (at *AutoTrader) runCycle() error {
 
 	// 6. Print AI chain of thought
 	if aiDecision.CoTTrace != "" {
-		logger.Infof("\n" + strings.Repeat("-", 70))
+		logger.Info("\n" + strings.Repeat("-", 70))
 		logger.Info("💭 AI chain of thought analysis:")
 		logger.Info(strings.Repeat("-", 70))
 		logger.Info(aiDecision.CoTTrace)
-		logger.Infof(strings.Repeat("-", 70) + "\n")
+		logger.Info(strings.Repeat("-", 70) + "\n")
 	}
 
 	// 7. Print AI decisions
@@ -776,40 +1125,19 @@ func (at *AutoTrader) runCycle() error {
 	sortedDecisions := sortDecisionsByPriority(aiDecision.Decisions)
 
 	logger.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
+	if at.config.ShadowMode {
+		logger.Info("👻 Shadow mode: decisions will be executed on virtual ledger")
+		record.ExecutionLog = append(record.ExecutionLog, "Shadow mode: decisions executed on virtual ledger (no exchange orders)")
+	}
 	for i, d := range sortedDecisions {
 		logger.Infof("  [%d] %s %s", i+1, d.Symbol, d.Action)
 	}
 	logger.Info()
 
-	// Execute decisions and record results
-	for _, d := range sortedDecisions {
-		actionRecord := store.DecisionAction{
-			Action:     d.Action,
-			Symbol:     d.Symbol,
-			Quantity:   0,
-			Leverage:   d.Leverage,
-			Price:      0,
-			StopLoss:   d.StopLoss,
-			TakeProfit: d.TakeProfit,
-			Confidence: d.Confidence,
-			Reasoning:  d.Reasoning,
-			Timestamp:  time.Now(),
-			Success:    false,
-		}
-
-		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
-			logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
-			actionRecord.Error = err.Error()
-			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
-		} else {
-			actionRecord.Success = true
-			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s succeeded — %s", d.Symbol, d.Action, d.Reasoning))
-			// Brief delay after successful execution
-			time.Sleep(1 * time.Second)
-		}
-
-		record.Decisions = append(record.Decisions, actionRecord)
-	}
+	// Execute decisions through the durable execution queue and record results
+	at.executeDecisions(ctx, sortedDecisions, record)
+	at.recordCycleDecisions(ctx, sortedDecisions, record)
+	at.updateSessionMemory(ctx, aiDecision, record)
 
 	// 9. Save decision record
 	if err := at.saveDecision(record); err != nil {
@@ -821,8 +1149,9 @@ func (at *AutoTrader) runCycle() error {
 
 // buildTradingContext builds trading context
 func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
+	engine := at.engine()
 	// 1. Get account information (account-wide)
-	balance, err := at.trader.GetBalance()
+	balance, err := at.getBalance()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -849,8 +1178,8 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	currentPositionKeys := make(map[string]bool)
 
 	for _, pos := range exchangePositions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
+		symbol := pos.Symbol
+		side := pos.Side
 
 		// Check if this position belongs to the current trader in our database
 		if at.store != nil {
@@ -860,26 +1189,23 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 			}
 		}
 
-		markPrice := pos["markPrice"].(float64)
-		entryPrice := pos["entryPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity // Short position quantity is negative, convert to positive
-		}
+		markPrice := pos.MarkPrice
+		entryPrice := pos.EntryPrice
+		quantity := pos.Qty
 
 		// Skip closed positions (quantity = 0), prevent "ghost positions" from being passed to AI
 		if quantity == 0 {
 			continue
 		}
 
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
+		unrealizedPnl := pos.UnrealizedPnL
 		totalUnrealizedPnL += unrealizedPnl
-		liquidationPrice := pos["liquidationPrice"].(float64)
+		liquidationPrice := pos.LiqPrice
 
 		// Calculate margin used (estimated)
 		leverage := 10 // Default value, should actually be fetched from position info
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
 		marginUsed := (quantity * markPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
@@ -902,8 +1228,8 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 		}
 		// Priority 2: Get from exchange API (Bybit: createdTime, OKX: createdTime)
 		if updateTime == 0 {
-			if createdTime, ok := pos["createdTime"].(int64); ok && createdTime > 0 {
-				updateTime = createdTime
+			if !pos.CreatedAt.IsZero() {
+				updateTime = pos.CreatedAt.UnixMilli()
 			}
 		}
 		// Priority 3: Fallback to local tracking
@@ -932,6 +1258,8 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 			LiquidationPrice: liquidationPrice,
 			MarginUsed:       marginUsed,
 			UpdateTime:       updateTime,
+			ForcedReview:     at.maxHoldReviewPending(symbol, side),
+			Overnight:        at.overnightGap(symbol, side, markPrice),
 		})
 	}
 
@@ -943,20 +1271,21 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	}
 
 	// 3. Use strategy engine to get candidate coins (must have strategy engine)
-	if at.strategyEngine == nil {
+	if engine == nil {
 		return nil, fmt.Errorf("trader has no strategy engine configured")
 	}
-	candidateStocks, err := at.strategyEngine.GetCandidateStocks()
+	candidateStocks, err := engine.GetCandidateStocks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get candidate stocks: %w", err)
 	}
 	logger.Infof("📋 [%s] Strategy engine fetched candidate stocks: %d", at.name, len(candidateStocks))
+	candidateStocks, symbolListBlocked := at.filterCandidatesBySymbolLists(candidateStocks)
 
 	// 4. Get Realized PnL from historical closed positions in DB
 	realizedPnL := 0.0
 	if at.store != nil {
 		if stats, err := at.store.Position().GetFullStats(at.id); err == nil && stats != nil {
-			realizedPnL = stats.TotalPnL
+			realizedPnL = stats.NetPnL // Net of fees
 		}
 	}
 
@@ -976,18 +1305,20 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	}
 
 	// 5. Get leverage from strategy config
-	strategyConfig := at.strategyEngine.GetConfig()
+	strategyConfig := engine.GetConfig()
 	btcEthLeverage := strategyConfig.RiskControl.LargeCapMaxMargin
 	altcoinLeverage := strategyConfig.RiskControl.SmallCapMaxMargin
 	logger.Infof("📋 [%s] Strategy leverage config: BTC/ETH=%dx, Altcoin=%dx", at.name, btcEthLeverage, altcoinLeverage)
 
 	// 6. Build context
 	ctx := &decision.Context{
+		TraderID:         at.id,
 		CurrentTime:      time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
 		RuntimeMinutes:   int(time.Since(at.startTime).Minutes()),
 		CallCount:        at.callCount,
 		LargeCapLeverage: btcEthLeverage,
 		SmallCapLeverage: altcoinLeverage,
+		Exchange:         at.exchange,
 		Account: decision.AccountInfo{
 			TotalEquity:      totalEquity,
 			AvailableBalance: availableBalance,
@@ -998,8 +1329,9 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 			MarginUsedPct:    marginUsedPct,
 			PositionCount:    len(positionInfos),
 		},
-		Positions:       positionInfos,
-		CandidateStocks: candidateStocks,
+		Positions:         positionInfos,
+		CandidateStocks:   candidateStocks,
+		SymbolListBlocked: symbolListBlocked,
 	}
 
 	// Populate TP/SL cache into context for safekeeping enforcement
@@ -1034,6 +1366,16 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 				})
 			}
 		}
+
+		// Overall performance with MFE/MAE trade quality
+		if stats, err := at.store.Position().GetFullStats(at.id); err == nil {
+			excursions, err := at.store.Position().GetExcursionStats(at.id, time.Time{})
+			if err != nil {
+				logger.Infof("⚠️ [%s] Failed to get trade excursion stats: %v", at.name, err)
+			}
+			ctx.TradingStats = decision.NewTradingStats(stats, excursions)
+			at.addRecentTradingStats(ctx.TradingStats)
+		}
 	} else {
 		logger.Infof("⚠️ [%s] Store is nil, cannot get recent trades", at.name)
 	}
@@ -1055,14 +1397,16 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 		}
 
 		logger.Infof("📊 [%s] Fetching quantitative data for %d symbols...", at.name, len(symbols))
-		ctx.QuantDataMap = at.strategyEngine.FetchQuantDataBatch(symbols)
+		var quantErrors map[string]error
+		ctx.QuantDataMap, quantErrors = engine.FetchQuantDataBatchWithErrors(symbols)
+		ctx.AddQuantDataGaps(quantErrors)
 		logger.Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(ctx.QuantDataMap))
 	}
 
 	// 9. Get OI ranking data (market-wide position changes)
 	if strategyConfig.Indicators.EnableOIRanking {
 		logger.Infof("📊 [%s] Fetching OI ranking data...", at.name)
-		ctx.OIRankingData = at.strategyEngine.FetchOIRankingData()
+		ctx.OIRankingData = engine.FetchOIRankingData()
 		if ctx.OIRankingData != nil {
 			logger.Infof("📊 [%s] OI ranking data ready: %d top, %d low positions",
 				at.name, len(ctx.OIRankingData.TopPositions), len(ctx.OIRankingData.LowPositions))
@@ -1072,17 +1416,49 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	return ctx, nil
 }
 
+// addRecentTradingStats loads the recent performance windows (last days, last trades) into the trading stats
+func (at *AutoTrader) addRecentTradingStats(stats *decision.TradingStats) {
+	if stats == nil || at.store == nil {
+		return
+	}
+	since := time.Now().AddDate(0, 0, -decision.RecentStatsDays)
+	if lastDays, err := at.store.Position().GetWindowStats(at.id, since, 0); err != nil {
+		logger.Infof("⚠️ [%s] Failed to get last %d days trade stats: %v", at.name, decision.RecentStatsDays, err)
+	} else {
+		stats.LastDays = lastDays
+	}
+	if lastTrades, err := at.store.Position().GetWindowStats(at.id, time.Time{}, decision.RecentStatsTrades); err != nil {
+		logger.Infof("⚠️ [%s] Failed to get last %d trades stats: %v", at.name, decision.RecentStatsTrades, err)
+	} else {
+		stats.LastTrades = lastTrades
+	}
+}
+
 // executeDecisionWithRecord executes AI decision and records detailed information
 func (at *AutoTrader) executeDecisionWithRecord(decision *decision.Decision, actionRecord *store.DecisionAction) error {
+	if err := at.checkPaused(decision); err != nil {
+		return err
+	}
+	if err := at.checkMaintenance(decision); err != nil {
+		return err
+	}
+	if err := at.checkSymbolLists(decision); err != nil {
+		return err
+	}
+	if err := at.checkTradeGovernor(decision); err != nil {
+		return err
+	}
 	switch decision.Action {
 	case "open_long":
-		return at.executeOpenLongWithRecord(decision, actionRecord)
+		return at.countTradeOpen(decision, at.executeOpenLongWithRecord(decision, actionRecord))
 	case "open_short":
-		return at.executeOpenShortWithRecord(decision, actionRecord)
+		return at.countTradeOpen(decision, at.executeOpenShortWithRecord(decision, actionRecord))
 	case "close_long":
 		return at.executeCloseLongWithRecord(decision, actionRecord)
 	case "close_short":
 		return at.executeCloseShortWithRecord(decision, actionRecord)
+	case "update_stops":
+		return at.executeUpdateStopsWithRecord(decision, actionRecord)
 	case "hold", "wait":
 		// No execution needed, just record
 		return nil
@@ -1171,13 +1547,41 @@ func (at *AutoTrader) calculateSmartLimitPrice(symbol string, side string, atrMu
 			vwap, atr, atrMultiplier, limitPrice)
 	}
 
-	return limitPrice, nil
+	return at.clampLimitPriceToBook(symbol, side, limitPrice), nil
+}
+
+// clampLimitPriceToBook keeps a smart limit price passive: a buy never above the best bid, a sell never below the best ask
+// Only for crypto with Indicators.EnableOrderBook; the price is returned unchanged if the book is unavailable.
+func (at *AutoTrader) clampLimitPriceToBook(symbol, side string, limitPrice float64) float64 {
+	engine := at.engine()
+	if engine == nil || !engine.GetConfig().Indicators.EnableOrderBook || market.IsStock(symbol) {
+		return limitPrice
+	}
+	book, err := market.GetOrderBook(symbol, engine.GetConfig().Indicators.OrderBookLevels)
+	if err != nil {
+		logger.Infof("⚠️ Order book unavailable for %s, limit price not checked against the book: %v", symbol, err)
+		return limitPrice
+	}
+	if side == "buy" && limitPrice > book.BestBid {
+		logger.Infof("📖 Smart BUY limit $%.4f would cross the spread, joining best bid $%.4f", limitPrice, book.BestBid)
+		return book.BestBid
+	}
+	if side != "buy" && limitPrice < book.BestAsk {
+		logger.Infof("📖 Smart SELL limit $%.4f would cross the spread, joining best ask $%.4f", limitPrice, book.BestAsk)
+		return book.BestAsk
+	}
+	return limitPrice
 }
 
 // executeWithSmartOrders wraps order execution with smart limit order logic (Phase 2)
 func (at *AutoTrader) executeWithSmartOrders(symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Check if smart limit orders are enabled
-	execConfig := at.config.StrategyConfig.Execution
+	execConfig := at.strategyConfig().Execution
+
+	// Large orders: TWAP/iceberg child orders (per-slice limit pricing when limit orders are enabled)
+	if at.shouldUseTWAP(execConfig, symbol, quantity) {
+		return at.executeTWAP(symbol, side, quantity, leverage, execConfig)
+	}
 
 	if !execConfig.EnableLimitOrders {
 		// Default: use market orders
@@ -1193,7 +1597,7 @@ func (at *AutoTrader) executeWithSmartOrders(symbol, side string, quantity float
 	logger.Infof("  🎯 Using smart limit order (VWAP ± ATR)")
 
 	// Calculate optimal limit price
-	limitPrice, err := at.calculateSmartLimitPrice(symbol, side, execConfig.LimitOffsetATRMult)
+	limitPrice, err := at.calculateSmartLimitPrice(symbol, side, at.limitOffsetATRMult(symbol, execConfig))
 	if err != nil {
 		logger.Infof("  ⚠️ Failed to calculate limit price, falling back to market: %v", err)
 		if side == "buy" {
@@ -1251,15 +1655,22 @@ func (at *AutoTrader) executeWithSmartOrders(symbol, side string, quantity float
 		logger.Infof("  ⏱️ Limit order not filled within %ds, canceling and using market order", timeout)
 		alpacaTrader.CancelOrder(orderID)
 
+		var marketOrder map[string]interface{}
 		if side == "buy" {
-			return at.trader.OpenLong(symbol, quantity, leverage)
+			marketOrder, err = at.trader.OpenLong(symbol, quantity, leverage)
 		} else {
-			return at.trader.OpenShort(symbol, quantity, leverage)
+			marketOrder, err = at.trader.OpenShort(symbol, quantity, leverage)
+		}
+		if marketOrder != nil {
+			marketOrder[smartLimitPriceKey] = limitPrice // Fallback fill, counts against the limit fill rate
 		}
+		return marketOrder, err
 	}
 
 	// Success: limit order filled
 	logger.Infof("  ✅ Limit order filled at $%.2f (saved slippage!)", limitPrice)
+	order[smartLimitPriceKey] = limitPrice
+	order[smartLimitFilledKey] = true
 	return order, nil
 }
 
@@ -1270,31 +1681,31 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
 	if err != nil {
-		return fmt.Errorf("failed to get positions: %w", err)
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, fmt.Errorf("failed to get positions: %w", err))
 	}
 
 	// [CODE ENFORCED] Check max positions limit
-	if err := at.enforceMaxPositions(len(positions)); err != nil {
+	if err := at.enforceMaxPositions(len(positions) + at.pendingLimitEntryCount()); err != nil {
 		return err
 	}
 
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
-		if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
-			return fmt.Errorf("❌ %s already has long position, close it first", decision.Symbol)
+		if pos.Symbol == decision.Symbol && pos.Side == "long" {
+			return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ %s already has long position, close it first", decision.Symbol))
 		}
 	}
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
 	if err != nil {
-		return err
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, err)
 	}
 
 	// Get balance (needed for multiple checks)
-	balance, err := at.trader.GetBalance()
+	balance, err := at.getBalance()
 	if err != nil {
-		return fmt.Errorf("failed to get account balance: %w", err)
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, fmt.Errorf("failed to get account balance: %w", err))
 	}
 	availableBalance := 0.0
 	if avail, ok := balance["availableBalance"].(float64); ok {
@@ -1311,37 +1722,41 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Per-symbol min confidence and max leverage
+	if err := at.enforceSymbolOverride(decision); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
+	// [CODE ENFORCED] Simulate post-trade margin usage across all positions, resize or reject
+	actualPositionSize, err := at.enforceMarginImpact(decision.Symbol, decision.PositionSizeUSD, decision.Leverage,
+		positions, equity, availableBalance)
+	if err != nil {
+		return err
 	}
+	decision.PositionSizeUSD = actualPositionSize
 
 	// [CODE ENFORCED] Minimum position size check
-	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
+	if err := at.enforceMinPositionSize(decision.Symbol, decision.PositionSizeUSD); err != nil {
 		return err
 	}
 
-	// Calculate quantity with adjusted position size
-	quantity := actualPositionSize / marketData.CurrentPrice
+	// Calculate quantity with adjusted position size (rounded to symbol lot size when known)
+	entryPrice := marketData.CurrentPrice
+	if decision.EntryPrice > 0 {
+		entryPrice = decision.EntryPrice
+	}
+	quantity, err := symbolQuantity(decision.Symbol, actualPositionSize/entryPrice)
+	if err != nil {
+		return err
+	}
 	actionRecord.Quantity = quantity
-	actionRecord.Price = marketData.CurrentPrice
+	actionRecord.Price = entryPrice
 
 	// Set margin mode
 	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
@@ -1349,33 +1764,43 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		// Continue execution, doesn't affect trading
 	}
 
+	// Limit entry: rest a GTC order at entry_price, SL/TP are placed once it fills (see limit_entry.go)
+	if decision.EntryPrice > 0 {
+		return at.placeLimitEntry(decision, "long", quantity, actionRecord)
+	}
+
 	// Open position (Phase 2: Smart Order Execution if enabled)
 	order, err := at.executeWithSmartOrders(decision.Symbol, "buy", quantity, decision.Leverage)
 	if err != nil {
 		return err
 	}
 
-	// Record order ID
-	if orderID, ok := order["orderId"].(int64); ok {
-		actionRecord.OrderID = orderID
+	// TWAP may stop after a partial fill: protect and record the filled quantity only
+	if filledQty, ok := order["executedQty"].(float64); ok && order["twap"] == true && filledQty > 0 {
+		quantity = filledQty
+		actionRecord.Quantity = quantity
 	}
 
+	// Record order ID
+	setActionOrderID(actionRecord, order)
+
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0, "")
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
 	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
+	if err := at.setStopLoss(decision.Symbol, "long", quantity, decision.StopLoss, false); err != nil {
 		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
+	if err := at.setTakeProfit(decision.Symbol, "long", quantity, decision.TakeProfit, false); err != nil {
 		logger.Infof("  ⚠ Failed to set take profit: %v", err)
 	}
+	at.placeTakeProfitLevels(decision.Symbol, "long", quantity, decision.TakeProfitLevels)
 
 	// Cache TP/SL prices for safekeeping enforcement (works even if exchange doesn't support server-side TP/SL)
 	if decision.TakeProfit > 0 || decision.StopLoss > 0 {
@@ -1393,31 +1818,31 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
 	if err != nil {
-		return fmt.Errorf("failed to get positions: %w", err)
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, fmt.Errorf("failed to get positions: %w", err))
 	}
 
 	// [CODE ENFORCED] Check max positions limit
-	if err := at.enforceMaxPositions(len(positions)); err != nil {
+	if err := at.enforceMaxPositions(len(positions) + at.pendingLimitEntryCount()); err != nil {
 		return err
 	}
 
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
-		if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
-			return fmt.Errorf("❌ %s already has short position, close it first", decision.Symbol)
+		if pos.Symbol == decision.Symbol && pos.Side == "short" {
+			return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ %s already has short position, close it first", decision.Symbol))
 		}
 	}
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
 	if err != nil {
-		return err
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, err)
 	}
 
 	// Get balance (needed for multiple checks)
-	balance, err := at.trader.GetBalance()
+	balance, err := at.getBalance()
 	if err != nil {
-		return fmt.Errorf("failed to get account balance: %w", err)
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, fmt.Errorf("failed to get account balance: %w", err))
 	}
 	availableBalance := 0.0
 	if avail, ok := balance["availableBalance"].(float64); ok {
@@ -1434,37 +1859,41 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Per-symbol min confidence and max leverage
+	if err := at.enforceSymbolOverride(decision); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
+	// [CODE ENFORCED] Simulate post-trade margin usage across all positions, resize or reject
+	actualPositionSize, err := at.enforceMarginImpact(decision.Symbol, decision.PositionSizeUSD, decision.Leverage,
+		positions, equity, availableBalance)
+	if err != nil {
+		return err
 	}
+	decision.PositionSizeUSD = actualPositionSize
 
 	// [CODE ENFORCED] Minimum position size check
-	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
+	if err := at.enforceMinPositionSize(decision.Symbol, decision.PositionSizeUSD); err != nil {
 		return err
 	}
 
-	// Calculate quantity with adjusted position size
-	quantity := actualPositionSize / marketData.CurrentPrice
+	// Calculate quantity with adjusted position size (rounded to symbol lot size when known)
+	entryPrice := marketData.CurrentPrice
+	if decision.EntryPrice > 0 {
+		entryPrice = decision.EntryPrice
+	}
+	quantity, err := symbolQuantity(decision.Symbol, actualPositionSize/entryPrice)
+	if err != nil {
+		return err
+	}
 	actionRecord.Quantity = quantity
-	actionRecord.Price = marketData.CurrentPrice
+	actionRecord.Price = entryPrice
 
 	// Set margin mode
 	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
@@ -1472,33 +1901,43 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		// Continue execution, doesn't affect trading
 	}
 
+	// Limit entry: rest a GTC order at entry_price, SL/TP are placed once it fills (see limit_entry.go)
+	if decision.EntryPrice > 0 {
+		return at.placeLimitEntry(decision, "short", quantity, actionRecord)
+	}
+
 	// Open short position (Phase 2: Smart Order Execution if enabled)
 	order, err := at.executeWithSmartOrders(decision.Symbol, "sell", quantity, decision.Leverage)
 	if err != nil {
 		return err
 	}
 
-	// Record order ID
-	if orderID, ok := order["orderId"].(int64); ok {
-		actionRecord.OrderID = orderID
+	// TWAP may stop after a partial fill: protect and record the filled quantity only
+	if filledQty, ok := order["executedQty"].(float64); ok && order["twap"] == true && filledQty > 0 {
+		quantity = filledQty
+		actionRecord.Quantity = quantity
 	}
 
+	// Record order ID
+	setActionOrderID(actionRecord, order)
+
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0, "")
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
 	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
+	if err := at.setStopLoss(decision.Symbol, "short", quantity, decision.StopLoss, false); err != nil {
 		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
+	if err := at.setTakeProfit(decision.Symbol, "short", quantity, decision.TakeProfit, false); err != nil {
 		logger.Infof("  ⚠ Failed to set take profit: %v", err)
 	}
+	at.placeTakeProfitLevels(decision.Symbol, "short", quantity, decision.TakeProfitLevels)
 
 	// Cache TP/SL prices for safekeeping enforcement (works even if exchange doesn't support server-side TP/SL)
 	if decision.TakeProfit > 0 || decision.StopLoss > 0 {
@@ -1525,7 +1964,7 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
 	if err != nil {
-		return err
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, err)
 	}
 	actionRecord.Price = marketData.CurrentPrice
 
@@ -1535,31 +1974,31 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 	positions, err := at.trader.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
-				if ep, ok := pos["entryPrice"].(float64); ok {
-					entryPrice = ep
-				}
-				if amt, ok := pos["positionAmt"].(float64); ok && amt > 0 {
-					quantity = amt
-				}
+			if pos.Symbol == decision.Symbol && pos.Side == "long" {
+				entryPrice = pos.EntryPrice
+				quantity = pos.Qty
 				break
 			}
 		}
 	}
 
+	// Cancel tracked SL/TP orders first, they would otherwise stay live (or hold the shares) after the close
+	cancelled := at.cancelProtectiveOrders(decision.Symbol, "long")
+
 	// Close position
 	order, err := at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
 	if err != nil {
+		if cancelled {
+			at.restoreProtectiveOrders(decision.Symbol, "long", quantity)
+		}
 		return err
 	}
 
 	// Record order ID
-	if orderID, ok := order["orderId"].(int64); ok {
-		actionRecord.OrderID = orderID
-	}
+	setActionOrderID(actionRecord, order)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice, "ai_decision")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1581,7 +2020,7 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
 	if err != nil {
-		return err
+		return store.WithErrorCategory(store.ErrorCategoryDataMissing, err)
 	}
 	actionRecord.Price = marketData.CurrentPrice
 
@@ -1591,31 +2030,31 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 	positions, err := at.trader.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
-				if ep, ok := pos["entryPrice"].(float64); ok {
-					entryPrice = ep
-				}
-				if amt, ok := pos["positionAmt"].(float64); ok {
-					quantity = -amt // positionAmt is negative for short
-				}
+			if pos.Symbol == decision.Symbol && pos.Side == "short" {
+				entryPrice = pos.EntryPrice
+				quantity = pos.Qty
 				break
 			}
 		}
 	}
 
+	// Cancel tracked SL/TP orders first, they would otherwise stay live (or hold the shares) after the close
+	cancelled := at.cancelProtectiveOrders(decision.Symbol, "short")
+
 	// Close position
 	order, err := at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
 	if err != nil {
+		if cancelled {
+			at.restoreProtectiveOrders(decision.Symbol, "short", quantity)
+		}
 		return err
 	}
 
 	// Record order ID
-	if orderID, ok := order["orderId"].(int64); ok {
-		actionRecord.OrderID = orderID
-	}
+	setActionOrderID(actionRecord, order)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice, "ai_decision")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1668,8 +2107,9 @@ func (at *AutoTrader) SetOverrideBasePrompt(override bool) {
 
 // GetSystemPromptTemplate gets current system prompt template name (from strategy config)
 func (at *AutoTrader) GetSystemPromptTemplate() string {
-	if at.strategyEngine != nil {
-		config := at.strategyEngine.GetConfig()
+	engine := at.engine()
+	if engine != nil {
+		config := engine.GetConfig()
 		if config.CustomPrompt != "" {
 			return "custom"
 		}
@@ -1692,6 +2132,7 @@ func (at *AutoTrader) saveEquitySnapshot(ctx *decision.Context) {
 		PositionCount: ctx.Account.PositionCount,
 		MarginUsedPct: ctx.Account.MarginUsedPct,
 	}
+	at.applyBenchmark(snapshot)
 
 	if err := at.store.Equity().Save(snapshot); err != nil {
 		logger.Infof("⚠️ Failed to save equity snapshot: %v", err)
@@ -1717,10 +2158,52 @@ func (at *AutoTrader) saveDecision(record *store.DecisionRecord) error {
 		return err
 	}
 
+	at.recordDecisionErrors(record)
 	logger.Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
 	return nil
 }
 
+// filteredCandidates candidates removed by symbol lists, market data filters and ranking, in that order
+func filteredCandidates(ctx *decision.Context) []store.FilteredSymbol {
+	var filtered []store.FilteredSymbol
+	for _, symbol := range ctx.SymbolListBlocked {
+		filtered = append(filtered, store.FilteredSymbol{Symbol: symbol, Reason: store.FilterReasonSymbolList})
+	}
+	filtered = append(filtered, ctx.FilteredOut...)
+	if ctx.CandidateRanking != nil {
+		for _, symbol := range ctx.CandidateRanking.Cut {
+			entry := store.FilteredSymbol{Symbol: symbol, Reason: store.FilterReasonRankingCut}
+			if score := ctx.CandidateRanking.Scores[symbol]; score != nil {
+				entry.Detail = fmt.Sprintf("opportunity score %.2f", score.Score)
+			}
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// symbolQuantity rounds order quantity down to the symbol's lot size from the symbol registry
+// Returns error if the rounded quantity is below the symbol's minimum order quantity.
+func symbolQuantity(symbol string, quantity float64) (float64, error) {
+	rounded := market.Symbols.RoundQuantity(symbol, quantity)
+	if minQty := market.Symbols.MinQuantity(symbol); minQty > 0 && rounded < minQty {
+		return 0, fmt.Errorf("quantity %.8f below minimum order quantity %.8f for %s", quantity, minQty, symbol)
+	}
+	return rounded, nil
+}
+
+// aiCycleTimeout gets latency budget for AI calls in one cycle
+// Defaults to 80% of scan interval so a slow provider cannot push the cycle into the next one.
+func (at *AutoTrader) aiCycleTimeout() time.Duration {
+	if sla := at.aiLatencyConfig().SLASeconds; sla > 0 {
+		return time.Duration(sla) * time.Second // Strategy AI latency SLA (see ai_latency.go)
+	}
+	if at.config.AICycleTimeout > 0 {
+		return at.config.AICycleTimeout
+	}
+	return at.config.ScanInterval * 8 / 10
+}
+
 // GetStore gets data store (for external access to decision records, etc.)
 func (at *AutoTrader) GetStore() *store.Store {
 	return at.store
@@ -1733,26 +2216,41 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		aiProvider = "Qwen"
 	}
 
-	return map[string]interface{}{
-		"trader_id":       at.id,
-		"trader_name":     at.name,
-		"ai_model":        at.aiModel,
-		"exchange":        at.exchange,
-		"is_running":      at.isRunning,
-		"start_time":      at.startTime.Format(time.RFC3339),
-		"runtime_minutes": int(time.Since(at.startTime).Minutes()),
-		"call_count":      at.callCount,
-		"initial_balance": at.initialBalance,
-		"scan_interval":   at.config.ScanInterval.String(),
-		"stop_until":      at.stopUntil.Format(time.RFC3339),
-		"last_reset_time": at.lastResetTime.Format(time.RFC3339),
-		"ai_provider":     aiProvider,
-	}
+	status := map[string]interface{}{
+		"trader_id":        at.id,
+		"trader_name":      at.name,
+		"ai_model":         at.aiModel,
+		"exchange":         at.exchange,
+		"is_running":       at.isRunning.Load(),
+		"start_time":       at.startTime.Format(time.RFC3339),
+		"runtime_minutes":  int(time.Since(at.startTime).Minutes()),
+		"call_count":       at.callCount,
+		"initial_balance":  at.initialBalance,
+		"scan_interval":    at.config.ScanInterval.String(),
+		"stop_until":       at.stopUntil.Format(time.RFC3339),
+		"last_reset_time":  at.lastResetTime.Format(time.RFC3339),
+		"ai_provider":      aiProvider,
+		"shadow_mode":      at.config.ShadowMode,
+		"trading_schedule": at.config.TradingSchedule,
+		"symbol_lists":     at.GetSymbolLists(),
+		"strategy_id":      at.config.StrategyID,
+		"strategy_version": at.GetStrategyVersion(),
+	}
+	if reporter, ok := at.trader.(interface{ RateLimitStatus() RateLimitStatus }); ok {
+		status["rate_limit"] = reporter.RateLimitStatus()
+	}
+	if state := at.currentEquityRisk(); state != nil {
+		status["equity_risk"] = state
+	}
+	status["equity_floor"] = at.GetEquityFloorStatus()
+	status["pause"] = at.GetPauseStatus()
+	status["maintenance"] = at.activeMaintenance()
+	return status
 }
 
 // GetAccountInfo gets account information (for API)
 func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
-	balance, err := at.trader.GetBalance()
+	balance, err := at.getBalance()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
@@ -1781,8 +2279,8 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	positionCount := 0
 
 	for _, pos := range exchangePositions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
+		symbol := pos.Symbol
+		side := pos.Side
 
 		// Check if this position belongs to the current trader in our database
 		if at.store != nil {
@@ -1793,18 +2291,15 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		}
 
 		// Calculate stats for this trader's position
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
+		markPrice := pos.MarkPrice
+		quantity := pos.Qty
+		unrealizedPnl := pos.UnrealizedPnL
 		totalUnrealizedPnL += unrealizedPnl
 		positionCount++
 
 		leverage := 10
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
 		marginUsed := (quantity * markPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
@@ -1814,7 +2309,7 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	realizedPnL := 0.0
 	if at.store != nil {
 		if stats, err := at.store.Position().GetFullStats(at.id); err == nil && stats != nil {
-			realizedPnL = stats.TotalPnL
+			realizedPnL = stats.NetPnL // Net of fees
 		}
 	}
 
@@ -1854,54 +2349,79 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	}, nil
 }
 
+// GetExchangePositions gets the exchange's open positions (all positions of the account, not only this trader's)
+func (at *AutoTrader) GetExchangePositions() ([]Position, error) {
+	return at.trader.GetPositions()
+}
+
 // GetPositions gets position list (for API)
-func (at *AutoTrader) GetPositions() ([]map[string]interface{}, error) {
+// Positions without exchange-reported leverage use the leverage they were opened with from the position store.
+func (at *AutoTrader) GetPositions() ([]PositionSummary, error) {
 	positions, err := at.trader.GetPositions()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	var result []map[string]interface{}
+	result := make([]PositionSummary, 0, len(positions))
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		entryPrice := pos["entryPrice"].(float64)
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
-		liquidationPrice := pos["liquidationPrice"].(float64)
-
-		leverage := 10
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		leverage := pos.Leverage
+		if leverage <= 0 && at.store != nil {
+			// Exchange doesn't report leverage: use the leverage the position was opened with
+			if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, pos.Symbol, strings.ToUpper(pos.Side)); err == nil && dbPos != nil {
+				leverage = float64(dbPos.Leverage)
+			}
 		}
-
-		// Calculate margin used
-		marginUsed := (quantity * markPrice) / float64(leverage)
-
-		// Calculate P&L percentage (based on margin)
-		pnlPct := calculatePnLPercentage(unrealizedPnl, marginUsed)
-
-		result = append(result, map[string]interface{}{
-			"symbol":             symbol,
-			"side":               side,
-			"entry_price":        entryPrice,
-			"mark_price":         markPrice,
-			"quantity":           quantity,
-			"leverage":           leverage,
-			"unrealized_pnl":     unrealizedPnl,
-			"unrealized_pnl_pct": pnlPct,
-			"liquidation_price":  liquidationPrice,
-			"margin_used":        marginUsed,
-		})
+		result = append(result, SummarizePosition(pos, leverage))
 	}
-
 	return result, nil
 }
 
+// PositionSummary open position as served by the API
+type PositionSummary struct {
+	Symbol           string   `json:"symbol"`
+	Side             string   `json:"side"` // "long" or "short"
+	EntryPrice       float64  `json:"entry_price"`
+	MarkPrice        float64  `json:"mark_price"`
+	Quantity         float64  `json:"quantity"`
+	Leverage         float64  `json:"leverage,omitempty"` // 0 = unknown
+	UnrealizedPnL    float64  `json:"unrealized_pnl"`
+	UnrealizedPnLPct float64  `json:"unrealized_pnl_pct"`          // Unrealized return (%)
+	LiquidationPrice *float64 `json:"liquidation_price,omitempty"` // nil = not reported by the exchange
+	MarginUsed       float64  `json:"margin_used"`                 // 0 when neither margin nor leverage is known
+	EntryTime        string   `json:"entry_time,omitempty"`        // YYYY-MM-DD HH:MM
+}
+
+// SummarizePosition builds the API view of an exchange position (leverage <= 0 = unknown)
+// Margin is taken from the exchange when reported, otherwise derived from notional and leverage.
+func SummarizePosition(pos Position, leverage float64) PositionSummary {
+	if leverage < 0 {
+		leverage = 0
+	}
+	marginUsed := pos.MarginUsed
+	if marginUsed <= 0 && leverage > 0 {
+		marginUsed = pos.Qty * pos.MarkPrice / leverage
+	}
+	summary := PositionSummary{
+		Symbol:           pos.Symbol,
+		Side:             strings.ToLower(pos.Side),
+		EntryPrice:       pos.EntryPrice,
+		MarkPrice:        pos.MarkPrice,
+		Quantity:         pos.Qty,
+		Leverage:         leverage,
+		UnrealizedPnL:    pos.UnrealizedPnL,
+		UnrealizedPnLPct: calculatePnLPercentage(pos.UnrealizedPnL, marginUsed),
+		MarginUsed:       marginUsed,
+	}
+	if pos.LiqPrice > 0 {
+		liq := pos.LiqPrice
+		summary.LiquidationPrice = &liq
+	}
+	if !pos.CreatedAt.IsZero() {
+		summary.EntryTime = pos.CreatedAt.Format("2006-01-02 15:04")
+	}
+	return summary
+}
+
 // calculatePnLPercentage calculates P&L percentage (based on margin, automatically considers leverage)
 // Return rate = Unrealized P&L / Margin × 100%
 func calculatePnLPercentage(unrealizedPnl, marginUsed float64) float64 {
@@ -1911,7 +2431,7 @@ func calculatePnLPercentage(unrealizedPnl, marginUsed float64) float64 {
 	return 0.0
 }
 
-// sortDecisionsByPriority sorts decisions: close positions first, then open positions, finally hold/wait
+// sortDecisionsByPriority sorts decisions: close positions first, then stop updates, then open positions, finally hold/wait
 // This avoids position stacking overflow when changing positions
 func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision {
 	if len(decisions) <= 1 {
@@ -1923,10 +2443,12 @@ func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision
 		switch action {
 		case "close_long", "close_short":
 			return 1 // Highest priority: close positions first
+		case "update_stops":
+			return 2 // Protect held positions before adding new ones
 		case "open_long", "open_short":
-			return 2 // Second priority: open positions later
+			return 3 // Open positions later
 		case "hold", "wait":
-			return 3 // Lowest priority: wait
+			return 4 // Lowest priority: wait
 		default:
 			return 999 // Unknown actions at the end
 		}
@@ -1954,15 +2476,22 @@ func (at *AutoTrader) startDrawdownMonitor() {
 	go func() {
 		defer at.monitorWg.Done()
 
-		ticker := time.NewTicker(1 * time.Minute) // Check every minute
+		interval := at.drawdownRiskConfig().DrawdownCheckInterval()
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		logger.Info("📊 Started position drawdown monitoring (check every minute)")
+		logger.Infof("📊 Started position drawdown monitoring (check every %v)", interval)
 
 		for {
 			select {
 			case <-ticker.C:
 				at.checkPositionDrawdown()
+				// Pick up interval changes from strategy hot-reload
+				if next := at.drawdownRiskConfig().DrawdownCheckInterval(); next != interval {
+					interval = next
+					ticker.Reset(interval)
+					logger.Infof("📊 Drawdown monitoring interval changed to %v", interval)
+				}
 			case <-at.stopMonitorCh:
 				logger.Info("⏹ Stopped position drawdown monitoring")
 				return
@@ -1971,8 +2500,20 @@ func (at *AutoTrader) startDrawdownMonitor() {
 	}()
 }
 
+// drawdownRiskConfig risk control config used by the drawdown monitor (defaults without strategy engine)
+func (at *AutoTrader) drawdownRiskConfig() *store.RiskControlConfig {
+	engine := at.engine()
+	if engine == nil || engine.GetConfig() == nil {
+		return &store.RiskControlConfig{}
+	}
+	return &engine.GetConfig().RiskControl
+}
+
 // checkPositionDrawdown checks position drawdown situation
+// Rule per symbol (RiskControl.DrawdownRuleFor): close when profit > activation % and drawdown from peak >= close %.
 func (at *AutoTrader) checkPositionDrawdown() {
+	riskConfig := at.drawdownRiskConfig()
+
 	// Get current positions
 	positions, err := at.trader.GetPositions()
 	if err != nil {
@@ -1980,70 +2521,103 @@ func (at *AutoTrader) checkPositionDrawdown() {
 		return
 	}
 
+	openKeys := make(map[string]bool)
+	defer at.pruneMaxHoldState(openKeys)
+	defer at.pruneLiquidationAlerts(openKeys)
+	defer at.pruneStreamPositions(openKeys)
+	defer at.pruneExcursions(openKeys)
+
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		entryPrice := pos["entryPrice"].(float64)
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity // Short position quantity is negative, convert to positive
+		symbol := pos.Symbol
+		side := pos.Side
+		entryPrice := pos.EntryPrice
+		markPrice := pos.MarkPrice
+		quantity := pos.Qty
+		openKeys[symbol+"_"+side] = true
+		at.trackExcursion(symbol, side, entryPrice, markPrice)
+
+		// Max holding time takes precedence over drawdown rule
+		if at.checkMaxHoldTime(symbol, side, pos) {
+			continue
 		}
+		at.checkLiquidationProximity(symbol, side, pos, riskConfig)
 
-		// Calculate current P&L percentage
 		leverage := 10 // Default value
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
+		at.trackStreamPosition(symbol, side, entryPrice, quantity, leverage)
+		at.evaluateDrawdown(symbol, side, entryPrice, markPrice, leverage, riskConfig, true)
+	}
+}
 
-		var currentPnLPct float64
-		if side == "long" {
-			currentPnLPct = ((markPrice - entryPrice) / entryPrice) * float64(leverage) * 100
-		} else {
-			currentPnLPct = ((entryPrice - markPrice) / entryPrice) * float64(leverage) * 100
-		}
+// evaluateDrawdown updates the peak P&L of a position and closes it when its drawdown rule triggers
+// Called by the interval check and by real-time position stream updates (see position_stream.go).
+// logProgress logs positions above activation that did not trigger (interval check only, the stream updates every second).
+func (at *AutoTrader) evaluateDrawdown(symbol, side string, entryPrice, markPrice float64, leverage int, riskConfig *store.RiskControlConfig, logProgress bool) {
+	at.drawdownMu.Lock()
+	defer at.drawdownMu.Unlock()
+	if entryPrice <= 0 || markPrice <= 0 {
+		return
+	}
 
-		// Construct unique position identifier (distinguish long/short)
-		posKey := symbol + "_" + side
+	// Calculate current P&L percentage
+	var currentPnLPct float64
+	if side == "long" {
+		currentPnLPct = ((markPrice - entryPrice) / entryPrice) * float64(leverage) * 100
+	} else {
+		currentPnLPct = ((entryPrice - markPrice) / entryPrice) * float64(leverage) * 100
+	}
 
-		// Get historical peak profit for this position
-		at.peakPnLCacheMutex.RLock()
-		peakPnLPct, exists := at.peakPnLCache[posKey]
-		at.peakPnLCacheMutex.RUnlock()
+	// Construct unique position identifier (distinguish long/short)
+	posKey := symbol + "_" + side
 
-		if !exists {
-			// If no historical peak record, use current P&L as initial value
-			peakPnLPct = currentPnLPct
-			at.UpdatePeakPnL(symbol, side, currentPnLPct)
-		} else {
-			// Update peak cache
-			at.UpdatePeakPnL(symbol, side, currentPnLPct)
-		}
+	// Get historical peak profit for this position
+	at.peakPnLCacheMutex.RLock()
+	peakPnLPct, exists := at.peakPnLCache[posKey]
+	at.peakPnLCacheMutex.RUnlock()
 
-		// Calculate drawdown (magnitude of decline from peak)
-		var drawdownPct float64
-		if peakPnLPct > 0 && currentPnLPct < peakPnLPct {
-			drawdownPct = ((peakPnLPct - currentPnLPct) / peakPnLPct) * 100
-		}
+	if !exists {
+		// If no historical peak record, use current P&L as initial value
+		peakPnLPct = currentPnLPct
+		at.UpdatePeakPnL(symbol, side, currentPnLPct)
+	} else {
+		// Update peak cache
+		at.UpdatePeakPnL(symbol, side, currentPnLPct)
+	}
+
+	// Calculate drawdown (magnitude of decline from peak)
+	var drawdownPct float64
+	if peakPnLPct > 0 && currentPnLPct < peakPnLPct {
+		drawdownPct = ((peakPnLPct - currentPnLPct) / peakPnLPct) * 100
+	}
 
-		// Check close position condition: profit > 5% and drawdown >= 40%
-		if currentPnLPct > 5.0 && drawdownPct >= 40.0 {
-			logger.Infof("🚨 Drawdown close position condition triggered: %s %s | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
-				symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
+	// Check close position condition: profit > activation % and drawdown >= close %
+	enabled, activationPct, closePct := riskConfig.DrawdownRuleFor(symbol)
+	if !enabled {
+		return // Peak still tracked above for prompts
+	}
+	if currentPnLPct > activationPct && drawdownPct >= closePct {
+		logger.Infof("🚨 Drawdown close position condition triggered: %s %s | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
+			symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
 
-			// Execute close position
-			if err := at.emergencyClosePosition(symbol, side); err != nil {
-				logger.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
-			} else {
-				logger.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
-				// Clear cache for this position after closing
-				at.ClearPeakPnLCache(symbol, side)
-			}
-		} else if currentPnLPct > 5.0 {
-			// Record situations close to close position condition (for debugging)
-			logger.Infof("📊 Drawdown monitoring: %s %s | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
-				symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
+		at.publishEvent(notify.EventCircuitBreaker, symbol,
+			fmt.Sprintf("🚨 Drawdown circuit breaker: %s %s", symbol, strings.ToUpper(side)),
+			fmt.Sprintf("Profit %.2f%% | Peak %.2f%% | Drawdown %.2f%%", currentPnLPct, peakPnLPct, drawdownPct), nil)
+
+		// Execute close position
+		if err := at.emergencyClosePosition(symbol, side); err != nil {
+			logger.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
+		} else {
+			logger.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
+			// Clear cache for this position after closing
+			at.ClearPeakPnLCache(symbol, side)
+			at.untrackStreamPosition(symbol, side)
 		}
+	} else if logProgress && currentPnLPct > activationPct {
+		// Record situations close to close position condition (for debugging)
+		logger.Infof("📊 Drawdown monitoring: %s %s | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
+			symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
 	}
 }
 
@@ -2080,23 +2654,19 @@ func (at *AutoTrader) closePositionWithReason(symbol, side, reason, reasoning st
 	var quantity float64
 	positions, _ := at.trader.GetPositions()
 	for _, pos := range positions {
-		if pos["symbol"] == symbol {
-			posSide := strings.ToLower(pos["side"].(string))
+		if pos.Symbol == symbol {
+			posSide := strings.ToLower(pos.Side)
 			if posSide == side || (posSide == "long" && side == "buy") || (posSide == "short" && side == "sell") {
-				if ep, ok := pos["entryPrice"].(float64); ok {
-					entryPrice = ep
-				}
-				if amt, ok := pos["positionAmt"].(float64); ok {
-					quantity = amt
-					if quantity < 0 {
-						quantity = -quantity
-					}
-				}
+				entryPrice = pos.EntryPrice
+				quantity = pos.Qty
 				break
 			}
 		}
 	}
 
+	// Cancel tracked SL/TP orders first, they would otherwise stay live (or hold the shares) after the close
+	cancelled := at.cancelProtectiveOrders(symbol, side)
+
 	// Execute the close
 	var order map[string]interface{}
 	var err error
@@ -2107,6 +2677,9 @@ func (at *AutoTrader) closePositionWithReason(symbol, side, reason, reasoning st
 		order, err = at.trader.CloseLong(symbol, 0) // 0 = close all
 		action = "close_long"
 		if err != nil {
+			if cancelled {
+				at.restoreProtectiveOrders(symbol, side, quantity)
+			}
 			return err
 		}
 		logger.Infof("✅ Close long position succeeded (%s), order ID: %v", reason, order["orderId"])
@@ -2114,6 +2687,9 @@ func (at *AutoTrader) closePositionWithReason(symbol, side, reason, reasoning st
 		order, err = at.trader.CloseShort(symbol, 0) // 0 = close all
 		action = "close_short"
 		if err != nil {
+			if cancelled {
+				at.restoreProtectiveOrders(symbol, side, quantity)
+			}
 			return err
 		}
 		logger.Infof("✅ Close short position succeeded (%s), order ID: %v", reason, order["orderId"])
@@ -2122,7 +2698,7 @@ func (at *AutoTrader) closePositionWithReason(symbol, side, reason, reasoning st
 	}
 
 	// Record the position closure in database
-	at.recordAndConfirmOrder(order, symbol, action, quantity, currentPrice, 0, entryPrice)
+	at.recordAndConfirmOrder(order, symbol, action, quantity, currentPrice, 0, entryPrice, reason)
 
 	// Create and save a decision record so it shows in the UI
 	at.saveVWAPSellDecision(symbol, side, action, reason, reasoning, currentPrice, entryPrice, quantity)
@@ -2193,19 +2769,26 @@ func (at *AutoTrader) GetPeakPnLCache() map[string]float64 {
 }
 
 // UpdatePeakPnL updates peak profit cache
+// New peaks are mirrored to the open position record so a restart can restore them (see position_recovery.go).
 func (at *AutoTrader) UpdatePeakPnL(symbol, side string, currentPnLPct float64) {
 	at.peakPnLCacheMutex.Lock()
-	defer at.peakPnLCacheMutex.Unlock()
-
 	posKey := symbol + "_" + side
+	changed := false
 	if peak, exists := at.peakPnLCache[posKey]; exists {
 		// Update peak (if long, take larger value; if short, currentPnLPct is negative, also compare)
 		if currentPnLPct > peak {
 			at.peakPnLCache[posKey] = currentPnLPct
+			changed = true
 		}
 	} else {
 		// First time recording
 		at.peakPnLCache[posKey] = currentPnLPct
+		changed = true
+	}
+	at.peakPnLCacheMutex.Unlock()
+
+	if changed {
+		at.persistPeakPnL(symbol, side, currentPnLPct)
 	}
 }
 
@@ -2218,13 +2801,14 @@ func (at *AutoTrader) ClearPeakPnLCache(symbol, side string) {
 	delete(at.peakPnLCache, posKey)
 }
 
-// SetPositionTPSL caches ATR-based TP/SL prices for a position
+// SetPositionTPSL caches ATR-based TP/SL prices for a position (also stored on the open position record)
 func (at *AutoTrader) SetPositionTPSL(symbol, side string, takeProfit, stopLoss float64) {
 	at.positionTPSLMutex.Lock()
-	defer at.positionTPSLMutex.Unlock()
-
 	posKey := symbol + "_" + side
 	at.positionTPSL[posKey] = [2]float64{takeProfit, stopLoss}
+	at.positionTPSLMutex.Unlock()
+
+	at.persistPositionTPSL(symbol, side, takeProfit, stopLoss)
 }
 
 // GetPositionTPSL returns cached TP/SL prices for a position (tp, sl, exists)
@@ -2251,7 +2835,8 @@ func (at *AutoTrader) ClearPositionTPSL(symbol, side string) {
 // recordAndConfirmOrder polls order status for actual fill data and records position
 // action: open_long, open_short, close_long, close_short
 // entryPrice: entry price when closing (0 when opening)
-func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
+// closeReason: close reason stored on the position record when closing (empty = "ai_decision")
+func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64, closeReason string) {
 	if at.store == nil {
 		return
 	}
@@ -2288,6 +2873,22 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	var actualQty = quantity // fallback to requested quantity
 	var fee float64
 
+	// TWAP orders already carry aggregate fill data of all child orders (average fill price)
+	if isTWAP, _ := orderResult["twap"].(bool); isTWAP {
+		if avgPrice, ok := orderResult["avgPrice"].(float64); ok && avgPrice > 0 {
+			actualPrice = avgPrice
+		}
+		if execQty, ok := orderResult["executedQty"].(float64); ok && execQty > 0 {
+			actualQty = execQty
+		}
+		fee, _ = orderResult["commission"].(float64)
+		logger.Infof("  📝 Recording TWAP position (last child ID: %s, action: %s, avg price: %.6f, qty: %.6f, fee: %.4f)",
+			orderID, action, actualPrice, actualQty, fee)
+		at.recordFill(orderResult, orderID, symbol, action, price, actualPrice, actualQty)
+		at.recordPositionChange(orderID, symbol, positionSide, action, actualQty, actualPrice, price, leverage, entryPrice, fee, closeReason)
+		return
+	}
+
 	// Wait for order to be filled and get actual fill data
 	time.Sleep(500 * time.Millisecond)
 	for i := 0; i < 5; i++ {
@@ -2321,14 +2922,28 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 		orderID, action, actualPrice, actualQty, fee)
 
 	// Record position change with actual fill data
-	at.recordPositionChange(orderID, symbol, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
+	at.recordFill(orderResult, orderID, symbol, action, price, actualPrice, actualQty)
+	at.recordPositionChange(orderID, symbol, positionSide, action, actualQty, actualPrice, price, leverage, entryPrice, fee, closeReason)
 }
 
 // recordPositionChange records position change (create record on open, update record on close)
-func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string, quantity, price float64, leverage int, entryPrice float64, fee float64) {
+// price is the actual fill price, expectedPrice the market price when the order was decided (for slippage).
+// Fees not reported by the exchange are estimated from the exchange taker rate.
+func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string, quantity, price, expectedPrice float64, leverage int, entryPrice float64, fee float64, closeReason string) {
 	if at.store == nil {
 		return
 	}
+	if closeReason == "" {
+		closeReason = "ai_decision"
+	}
+
+	if fee <= 0 {
+		fee = EstimateFee(at.exchange, quantity*price, false)
+	}
+	slippage := slippageCost(action, expectedPrice, price, quantity)
+	if slippage != 0 {
+		logger.Infof("  📐 Fill vs expected: %.6f vs %.6f (slippage %+.4f USD)", price, expectedPrice, slippage)
+	}
 
 	switch action {
 	case "open_long", "open_short":
@@ -2345,11 +2960,16 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			EntryTime:    time.Now(),
 			Leverage:     leverage,
 			Status:       "OPEN",
+			Fee:          fee,
+			Slippage:     slippage,
+
+			ExpectedEntryPrice: expectedPrice,
 		}
 		if err := at.store.Position().Create(pos); err != nil {
 			logger.Infof("  ⚠️ Failed to record position: %v", err)
 		} else {
 			logger.Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
+			at.notifyPositionOpened(symbol, side, quantity, price, leverage)
 		}
 
 	case "close_long", "close_short":
@@ -2360,7 +2980,7 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			return
 		}
 
-		// Calculate P&L
+		// Calculate gross P&L (fees are stored separately, see TraderStats.NetPnL)
 		var realizedPnL float64
 		if side == "LONG" {
 			realizedPnL = (price - openPos.EntryPrice) * openPos.Quantity
@@ -2369,19 +2989,22 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 		}
 
 		// Update position record
-		err = at.store.Position().ClosePosition(
+		err = at.store.Position().ClosePositionWithCosts(
 			openPos.ID,
-			price,   // exitPrice
-			orderID, // exitOrderID
+			price,         // exitPrice
+			expectedPrice, // expectedExitPrice
+			orderID,       // exitOrderID
 			realizedPnL,
-			fee, // fee from exchange API
-			"ai_decision",
+			fee,      // exit fee (added to entry fee)
+			slippage, // exit slippage (added to entry slippage)
+			closeReason,
 		)
 		if err != nil {
 			logger.Infof("  ⚠️ Failed to update position: %v", err)
 		} else {
-			logger.Infof("  📊 Position closed [%s] %s %s @ %.4f → %.4f, P&L: %.2f, Fee: %.4f",
-				at.id[:8], symbol, side, openPos.EntryPrice, price, realizedPnL, fee)
+			logger.Infof("  📊 Position closed [%s] %s %s @ %.4f → %.4f, P&L: %.2f (net %.2f), Fee: %.4f",
+				at.id[:8], symbol, side, openPos.EntryPrice, price, realizedPnL, realizedPnL-openPos.Fee-fee, fee)
+			at.notifyPositionClosed(symbol, side, closeReason, openPos.EntryPrice, price, realizedPnL)
 		}
 	}
 }
@@ -2402,19 +3025,20 @@ func isBTCETH(symbol string) bool {
 // equity: the account equity
 // symbol: the trading symbol
 func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity float64, symbol string) (float64, bool) {
-	if at.config.StrategyConfig == nil {
+	strategyConfig := at.strategyConfig()
+	if strategyConfig == nil {
 		return positionSizeUSD, false
 	}
 
-	riskControl := at.config.StrategyConfig.RiskControl
+	riskControl := strategyConfig.RiskControl
 	wasCapped := false
 
-	// FIRST: Check absolute max position size (if set)
+	// FIRST: Check absolute max position size (if set, per-symbol override first)
 	// This is the hard cap that applies regardless of equity ratio
-	if riskControl.MaxPositionSizeUSD > 0 && positionSizeUSD > riskControl.MaxPositionSizeUSD {
-		logger.Infof("  ⚠️ [RISK CONTROL] Position $%.2f exceeds max_position_size_usd ($%.2f), capping",
-			positionSizeUSD, riskControl.MaxPositionSizeUSD)
-		positionSizeUSD = riskControl.MaxPositionSizeUSD
+	if maxUSD := riskControl.MaxPositionSizeUSDFor(symbol); maxUSD > 0 && positionSizeUSD > maxUSD {
+		logger.Infof("  ⚠️ [RISK CONTROL] Position $%.2f exceeds max position size of %s ($%.2f), capping",
+			positionSizeUSD, symbol, maxUSD)
+		positionSizeUSD = maxUSD
 		wasCapped = true
 	}
 
@@ -2445,43 +3069,69 @@ func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity
 	return positionSizeUSD, wasCapped
 }
 
-// enforceMinPositionSize checks minimum position size (CODE ENFORCED)
-func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
-	if at.config.StrategyConfig == nil {
+// enforceSymbolOverride applies the per-symbol override of RiskControl.SymbolOverrides (CODE ENFORCED)
+// Rejects opens below the symbol's min confidence and caps leverage at the symbol's max leverage.
+func (at *AutoTrader) enforceSymbolOverride(d *decision.Decision) error {
+	strategyConfig := at.strategyConfig()
+	if strategyConfig == nil {
 		return nil
 	}
+	override, ok := strategyConfig.RiskControl.SymbolOverrideFor(d.Symbol)
+	if !ok {
+		return nil
+	}
+	if override.MinConfidence > 0 && d.Confidence < override.MinConfidence {
+		return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ [RISK CONTROL] %s confidence %d below symbol minimum %d", d.Symbol, d.Confidence, override.MinConfidence))
+	}
+	if override.MaxLeverage > 0 && d.Leverage > override.MaxLeverage {
+		logger.Infof("  ⚠️ [RISK CONTROL] %s leverage %dx exceeds symbol max %dx, capping", d.Symbol, d.Leverage, override.MaxLeverage)
+		d.Leverage = override.MaxLeverage
+	}
+	return nil
+}
 
-	minSize := at.config.StrategyConfig.RiskControl.MinPositionSize
-	if minSize <= 0 {
-		minSize = 12 // Default: 12 USDT
+// enforceMinPositionSize checks minimum position size (CODE ENFORCED)
+// Minimum depends on symbol (Large Cap / Small Cap) and exchange minimum order value
+func (at *AutoTrader) enforceMinPositionSize(symbol string, positionSizeUSD float64) error {
+	strategyConfig := at.strategyConfig()
+	if strategyConfig == nil {
+		return nil
 	}
 
+	limits := decision.PositionLimits{Exchange: at.exchange, Risk: strategyConfig.RiskControl}
+	minSize := limits.MinPositionSize(symbol)
+
 	if positionSizeUSD < minSize {
-		return fmt.Errorf("❌ [RISK CONTROL] Position %.2f USDT below minimum (%.2f USDT)", positionSizeUSD, minSize)
+		return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ [RISK CONTROL] Position %.2f USDT below minimum (%.2f USDT)", positionSizeUSD, minSize))
 	}
 	return nil
 }
 
 // enforceMaxPositions checks maximum positions count (CODE ENFORCED)
 func (at *AutoTrader) enforceMaxPositions(currentPositionCount int) error {
+	engine := at.engine()
 	// Prefer strategy engine's live config (updated via Strategy Studio)
 	maxPositions := 0
-	if at.strategyEngine != nil {
-		cfg := at.strategyEngine.GetConfig()
+	if engine != nil {
+		cfg := engine.GetConfig()
 		if cfg != nil {
 			maxPositions = cfg.RiskControl.MaxPositions
 		}
 	}
 	// Fallback to trader's static config
-	if maxPositions <= 0 && at.config.StrategyConfig != nil {
-		maxPositions = at.config.StrategyConfig.RiskControl.MaxPositions
+	if strategyConfig := at.strategyConfig(); maxPositions <= 0 && strategyConfig != nil {
+		maxPositions = strategyConfig.RiskControl.MaxPositions
 	}
 	if maxPositions <= 0 {
 		maxPositions = 3 // Default: 3 positions
 	}
+	// Scaled down while the account is in drawdown (equity_risk.go)
+	if state := at.currentEquityRisk(); state != nil && state.MaxPositions > 0 && state.MaxPositions < maxPositions {
+		maxPositions = state.MaxPositions
+	}
 
 	if currentPositionCount >= maxPositions {
-		return fmt.Errorf("❌ [RISK CONTROL] Already at max positions (%d/%d)", currentPositionCount, maxPositions)
+		return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ [RISK CONTROL] Already at max positions (%d/%d)", currentPositionCount, maxPositions))
 	}
 	return nil
 }
@@ -2492,34 +3142,11 @@ func IsMarketOpen() bool {
 	return isMarketOpen()
 }
 
-// isMarketOpen checks if US stock market is currently open (9:30 AM - 4:00 PM ET, Monday-Friday)
+// isMarketOpen checks if US stock market is currently open (9:30 AM - 4:00 PM ET on trading days,
+// closed on market holidays, 1:00 PM close on half days, see market/trading_calendar.go)
 // Used to enforce TradeOnlyMarketHours setting for stock trading
 func isMarketOpen() bool {
-	// Load Eastern Time location
-	loc, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		// Fallback: assume market is open if timezone fails
-		logger.Infof("⚠️ Failed to load America/New_York timezone: %v, assuming market is open", err)
-		return true
-	}
-
-	now := time.Now().In(loc)
-
-	// Check if weekend (Saturday = 6, Sunday = 0)
-	weekday := now.Weekday()
-	if weekday == time.Saturday || weekday == time.Sunday {
-		return false
-	}
-
-	// Market hours: 9:30 AM to 4:00 PM ET
-	hour := now.Hour()
-	minute := now.Minute()
-	currentMinutes := hour*60 + minute
-
-	marketOpenMinutes := 9*60 + 30 // 9:30 AM = 570 minutes
-	marketCloseMinutes := 16 * 60  // 4:00 PM = 960 minutes
-
-	return currentMinutes >= marketOpenMinutes && currentMinutes < marketCloseMinutes
+	return market.IsUSMarketOpen(time.Now())
 }
 
 // ============================================================================
@@ -2528,11 +3155,12 @@ func isMarketOpen() bool {
 
 // isVWAPPreEntryTime checks if current time is between 9:30 AM and entry time (e.g., 10:00 AM)
 func (at *AutoTrader) isVWAPPreEntryTime() bool {
-	if at.strategyEngine == nil {
+	engine := at.engine()
+	if engine == nil {
 		return false
 	}
 
-	config := at.strategyEngine.GetConfig()
+	config := engine.GetConfig()
 	if config == nil || !config.Indicators.EnableVWAPSlopeStretch {
 		return false
 	}
@@ -2543,9 +3171,8 @@ func (at *AutoTrader) isVWAPPreEntryTime() bool {
 	}
 	now := time.Now().In(loc)
 
-	// Check if weekend
-	weekday := now.Weekday()
-	if weekday == time.Saturday || weekday == time.Sunday {
+	// Check if weekend or market holiday
+	if !market.USMarketSession(now).Open {
 		return false
 	}
 
@@ -2569,11 +3196,12 @@ func (at *AutoTrader) isVWAPPreEntryTime() bool {
 // The window spans from the configured entry time to entry time + 5 minutes,
 // ensuring the scan interval (typically 1-3 min) never skips the entry.
 func (at *AutoTrader) isVWAPEntryTime() bool {
-	if at.strategyEngine == nil {
+	engine := at.engine()
+	if engine == nil {
 		return false
 	}
 
-	config := at.strategyEngine.GetConfig()
+	config := engine.GetConfig()
 	if config == nil || !config.Indicators.EnableVWAPSlopeStretch {
 		return false
 	}
@@ -2583,6 +3211,9 @@ func (at *AutoTrader) isVWAPEntryTime() bool {
 		return false
 	}
 	now := time.Now().In(loc)
+	if !market.USMarketSession(now).Open {
+		return false
+	}
 	currentMinutes := now.Hour()*60 + now.Minute()
 
 	// Parse entry time
@@ -2602,11 +3233,12 @@ func (at *AutoTrader) isVWAPEntryTime() bool {
 
 // isVWAPPostEntryTime checks if we're past the entry time (no new buys allowed, only manage positions)
 func (at *AutoTrader) isVWAPPostEntryTime() bool {
-	if at.strategyEngine == nil {
+	engine := at.engine()
+	if engine == nil {
 		return false
 	}
 
-	config := at.strategyEngine.GetConfig()
+	config := engine.GetConfig()
 	if config == nil || !config.Indicators.EnableVWAPSlopeStretch {
 		return false
 	}
@@ -2616,6 +3248,9 @@ func (at *AutoTrader) isVWAPPostEntryTime() bool {
 		return false
 	}
 	now := time.Now().In(loc)
+	if !market.USMarketSession(now).Open {
+		return false
+	}
 
 	// Parse entry time
 	entryTime := config.Indicators.VWAPEntryTime
@@ -2635,7 +3270,7 @@ func (at *AutoTrader) isVWAPPostEntryTime() bool {
 // runVWAPPositionManagement manages existing positions during post-entry phase
 // Continuously monitors for:
 // 1. Sell trigger hit (from AI100 API or default 5%)
-// 2. End-of-day exit (5 minutes before market close at 3:55 PM ET)
+// 2. End-of-day exit (5 minutes before market close: 3:55 PM ET, 12:55 PM on half days)
 func (at *AutoTrader) runVWAPPositionManagement() {
 	// Get current positions
 	positions, err := at.trader.GetPositions()
@@ -2645,14 +3280,11 @@ func (at *AutoTrader) runVWAPPositionManagement() {
 	}
 
 	// Filter to only this trader's positions (if using shared account)
-	var traderPositions []map[string]interface{}
+	var traderPositions []Position
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
+		symbol := pos.Symbol
+		side := pos.Side
+		quantity := pos.Qty
 		// Skip empty positions
 		if quantity == 0 {
 			continue
@@ -2675,22 +3307,23 @@ func (at *AutoTrader) runVWAPPositionManagement() {
 	loc, _ := time.LoadLocation("America/New_York")
 	now := time.Now().In(loc)
 	currentMinutes := now.Hour()*60 + now.Minute()
-	marketCloseMinutes := 16*60 - 5 // 3:55 PM (5 min before 4 PM close)
+	sessionClose := market.USMarketSession(now).CloseMinutes // 4 PM, 1 PM on half days
+	marketCloseMinutes := sessionClose - 5                   // 5 min before the close
 
 	// Check if we're near market close
 	isNearMarketClose := currentMinutes >= marketCloseMinutes
-	timeToClose := (16*60 - currentMinutes)
+	timeToClose := (sessionClose - currentMinutes)
 
 	logger.Infof("📊 [VWAP] Position check at %s ET | %d positions | Market closes in %d min",
 		now.Format("15:04"), len(traderPositions), timeToClose)
 
 	for _, pos := range traderPositions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
+		symbol := pos.Symbol
+		side := pos.Side
 
 		// Get price data
-		entryPrice := pos["entryPrice"].(float64)
-		markPrice := pos["markPrice"].(float64)
+		entryPrice := pos.EntryPrice
+		markPrice := pos.MarkPrice
 
 		// Calculate PnL percentage
 		pnlPct := 0.0
@@ -2759,12 +3392,9 @@ func (at *AutoTrader) cleanupStaleVWAPPositions() {
 
 	var staleCount int
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
+		symbol := pos.Symbol
+		side := pos.Side
+		quantity := pos.Qty
 		if quantity == 0 {
 			continue
 		}
@@ -2780,8 +3410,8 @@ func (at *AutoTrader) cleanupStaleVWAPPositions() {
 			posOpenDate := dbPos.EntryTime.In(loc).Format("2006-01-02")
 			if posOpenDate < today {
 				staleCount++
-				entryPrice := pos["entryPrice"].(float64)
-				markPrice := pos["markPrice"].(float64)
+				entryPrice := pos.EntryPrice
+				markPrice := pos.MarkPrice
 				pnlPct := 0.0
 				if entryPrice > 0 && markPrice > 0 {
 					if side == "long" || side == "buy" {
@@ -2815,11 +3445,12 @@ func (at *AutoTrader) cleanupStaleVWAPPositions() {
 // Returns 1 minute during entire trading day when VWAP mode is enabled
 // This ensures sell triggers and market close exits are checked frequently
 func (at *AutoTrader) getVWAPAwareInterval() time.Duration {
-	if at.strategyEngine == nil {
+	engine := at.engine()
+	if engine == nil {
 		return at.config.ScanInterval
 	}
 
-	config := at.strategyEngine.GetConfig()
+	config := engine.GetConfig()
 	if config == nil || !config.Indicators.EnableVWAPSlopeStretch {
 		return at.config.ScanInterval
 	}
@@ -2835,8 +3466,9 @@ func (at *AutoTrader) getVWAPAwareInterval() time.Duration {
 	return at.config.ScanInterval
 }
 
-// initVWAPCollector initializes or resets VWAP collector for a symbol
-func (at *AutoTrader) initVWAPCollector(symbol string) *VWAPCollector {
+// initVWAPCollector initializes or resets VWAP collector for a symbol, reports whether it was newly created
+func (at *AutoTrader) initVWAPCollector(symbol string) (*VWAPCollector, bool) {
+	engine := at.engine()
 	at.vwapCollectorsMu.Lock()
 	defer at.vwapCollectorsMu.Unlock()
 
@@ -2845,8 +3477,8 @@ func (at *AutoTrader) initVWAPCollector(symbol string) *VWAPCollector {
 	}
 
 	entryTime := "10:00"
-	if at.strategyEngine != nil {
-		config := at.strategyEngine.GetConfig()
+	if engine != nil {
+		config := engine.GetConfig()
 		if config != nil && config.Indicators.VWAPEntryTime != "" {
 			entryTime = config.Indicators.VWAPEntryTime
 		}
@@ -2861,13 +3493,13 @@ func (at *AutoTrader) initVWAPCollector(symbol string) *VWAPCollector {
 			collector.Reset()
 			logger.Infof("📊 [VWAP] Reset collector for %s at market open", symbol)
 		}
-		return collector
+		return collector, false
 	}
 
 	collector := NewVWAPCollector(entryTime)
 	at.vwapCollectors[symbol] = collector
 	logger.Infof("📊 [VWAP] Initialized collector for %s (entry time: %s AM ET)", symbol, entryTime)
-	return collector
+	return collector, true
 }
 
 // getVWAPCollector gets or creates a VWAP collector for a symbol
@@ -2880,7 +3512,13 @@ func (at *AutoTrader) getVWAPCollector(symbol string) *VWAPCollector {
 		}
 	}
 	at.vwapCollectorsMu.RUnlock()
-	return at.initVWAPCollector(symbol)
+
+	collector, created := at.initVWAPCollector(symbol)
+	if created {
+		// Reload today's session after a restart (outside the lock, may hit the network)
+		at.restoreVWAPSession(symbol, collector)
+	}
+	return collector
 }
 
 // collectVWAPBars fetches latest 1-min bars for all candidate stocks
@@ -2888,6 +3526,12 @@ func (at *AutoTrader) collectVWAPBars(symbols []string) {
 	for _, symbol := range symbols {
 		collector := at.getVWAPCollector(symbol)
 
+		// Drop bars left over from a previous session
+		if first := collector.FirstBarTime(); !first.IsZero() && vwapTradeDate(first) != vwapTradeDate(time.Now()) {
+			collector.Reset()
+			logger.Infof("📊 [VWAP] Reset collector for %s (new trading day)", symbol)
+		}
+
 		// Fetch latest 1-minute bar from Alpaca
 		bar, err := market.GetLatest1MinBar(symbol)
 		if err != nil {
@@ -2904,7 +3548,9 @@ func (at *AutoTrader) collectVWAPBars(symbols []string) {
 				Close:  bar.Close,
 				Volume: bar.Volume,
 			}
-			collector.AddBar(vwapBar)
+			if collector.AddBar(vwapBar) {
+				at.persistVWAPBars(symbol, []VWAPBar{vwapBar})
+			}
 			logger.Infof("📊 [VWAP] Collected bar for %s: Close=%.4f, Vol=%.0f, Bars=%d",
 				symbol, bar.Close, bar.Volume, collector.GetBarCount())
 		}
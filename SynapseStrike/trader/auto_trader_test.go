@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -9,7 +10,6 @@ import (
 
 	"SynapseStrike/decision"
 	"SynapseStrike/market"
-	"SynapseStrike/provider"
 	"SynapseStrike/store"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -61,27 +61,31 @@ func (s *AutoTraderTestSuite) SetupTest() {
 			"availableBalance":      8000.0,
 			"totalUnrealizedProfit": 100.0,
 		},
-		positions: []map[string]interface{}{},
+		positions: []Position{},
 	}
 
-
 	// Create temporary store (using nil means no actual store needed in test)
 	s.mockStore = nil
 
 	// Set default configuration
 	s.config = AutoTraderConfig{
-		ID:                   "test_trader",
-		Name:                 "Test Trader",
-		AIModel:              "deepseek",
-		Exchange:             "binance",
-		InitialBalance:       10000.0,
-		ScanInterval:         3 * time.Minute,
-		SystemPromptTemplate: "adaptive",
-		BTCETHLeverage:       10,
-		AltcoinLeverage:      5,
-		IsCrossMargin:        true,
+		ID:             "test_trader",
+		Name:           "Test Trader",
+		AIModel:        "deepseek",
+		Exchange:       "binance",
+		InitialBalance: 10000.0,
+		ScanInterval:   3 * time.Minute,
+		IsCrossMargin:  true,
 	}
 
+	// Static candidates and fixed leverage limits, no external coin pool
+	strategyConfig := store.GetDefaultStrategyConfig("en")
+	strategyConfig.CoinSource.SourceType = "static"
+	strategyConfig.CoinSource.StaticStocks = []string{"BTCUSDT", "ETHUSDT"}
+	strategyConfig.RiskControl.LargeCapMaxMargin = 10
+	strategyConfig.RiskControl.SmallCapMaxMargin = 5
+	s.config.StrategyConfig = &strategyConfig
+
 	// Create AutoTrader instance (direct construction, don't call NewAutoTrader to avoid external dependencies)
 	s.autoTrader = &AutoTrader{
 		id:                    s.config.ID,
@@ -93,13 +97,10 @@ func (s *AutoTraderTestSuite) SetupTest() {
 		mcpClient:             nil, // No actual MCP Client needed in tests
 		store:                 s.mockStore,
 		initialBalance:        s.config.InitialBalance,
-		systemPromptTemplate:  s.config.SystemPromptTemplate,
-		defaultCoins:          []string{"BTC", "ETH"},
-		tradingCoins:          []string{},
+		strategyEngine:        decision.NewStrategyEngine(&strategyConfig),
 		lastResetTime:         time.Now(),
 		startTime:             time.Now(),
 		callCount:             0,
-		isRunning:             false,
 		positionFirstSeenTime: make(map[string]int64),
 		stopMonitorCh:         make(chan struct{}),
 		peakPnLCache:          make(map[string]float64),
@@ -172,10 +173,12 @@ func (s *AutoTraderTestSuite) TestNormalizeSymbol() {
 		input    string
 		expected string
 	}{
-		{"Already standard format", "BTCUSDT", "BTCUSDT"},
-		{"Lowercase to uppercase", "btcusdt", "BTCUSDT"},
-		{"Coin name only - add USDT", "BTC", "BTCUSDT"},
-		{"With spaces - remove spaces", " BTC ", "BTCUSDT"},
+		{"Strip USDT quote", "BTCUSDT", "BTC"},
+		{"Strip perp suffix", "BTC-PERP", "BTC"},
+		{"Lowercase to uppercase", "btc", "BTC"},
+		{"Coin name only - unchanged", "BTC", "BTC"},
+		{"Lowercase pair", "btcusdt", "BTC"},            // Registry upper-cases before stripping the quote
+		{"With spaces - remove spaces", " BTC ", "BTC"}, // Registry trims input
 	}
 
 	for _, tt := range tests {
@@ -199,9 +202,8 @@ func (s *AutoTraderTestSuite) TestGettersAndSetters() {
 		s.Equal("Test Trader", s.autoTrader.GetName())
 	})
 
-	s.Run("SetSystemPromptTemplate", func() {
-		s.autoTrader.SetSystemPromptTemplate("aggressive")
-		s.Equal("aggressive", s.autoTrader.GetSystemPromptTemplate())
+	s.Run("GetSystemPromptTemplate", func() {
+		s.Equal("strategy", s.autoTrader.GetSystemPromptTemplate())
 	})
 
 	s.Run("SetCustomPrompt", func() {
@@ -246,7 +248,7 @@ func (s *AutoTraderTestSuite) TestPeakPnLCache() {
 // ============================================================
 
 func (s *AutoTraderTestSuite) TestGetStatus() {
-	s.autoTrader.isRunning = true
+	s.autoTrader.isRunning.Store(true)
 	s.autoTrader.callCount = 15
 
 	status := s.autoTrader.GetStatus()
@@ -265,12 +267,15 @@ func (s *AutoTraderTestSuite) TestGetStatus() {
 // ============================================================
 
 func (s *AutoTraderTestSuite) TestGetAccountInfo() {
+	s.mockTrader.positions = []Position{{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, MarkPrice: 51000, UnrealizedPnL: 100, Leverage: 10}}
+	defer func() { s.mockTrader.positions = []Position{} }()
+
 	accountInfo, err := s.autoTrader.GetAccountInfo()
 
 	s.NoError(err)
 	s.NotNil(accountInfo)
 
-	// Verify core fields and values
+	// Verify core fields and values (virtual equity = initial balance + realized + unrealized)
 	s.Equal(10100.0, accountInfo["total_equity"]) // 10000 + 100
 	s.Equal(8000.0, accountInfo["available_balance"])
 	s.Equal(100.0, accountInfo["total_pnl"]) // 10100 - 10000
@@ -293,16 +298,16 @@ func (s *AutoTraderTestSuite) TestGetPositions() {
 
 	s.Run("Has positions", func() {
 		// Set mock positions
-		s.mockTrader.positions = []map[string]interface{}{
+		s.mockTrader.positions = []Position{
 			{
-				"symbol":           "BTCUSDT",
-				"side":             "long",
-				"entryPrice":       50000.0,
-				"markPrice":        51000.0,
-				"positionAmt":      0.1,
-				"unRealizedProfit": 100.0,
-				"liquidationPrice": 45000.0,
-				"leverage":         10.0,
+				Symbol:        "BTCUSDT",
+				Side:          "long",
+				EntryPrice:    50000,
+				MarkPrice:     51000,
+				Qty:           0.1,
+				UnrealizedPnL: 100,
+				LiqPrice:      45000,
+				Leverage:      10,
 			},
 		}
 
@@ -312,63 +317,49 @@ func (s *AutoTraderTestSuite) TestGetPositions() {
 		s.Equal(1, len(positions))
 
 		pos := positions[0]
-		s.Equal("BTCUSDT", pos["symbol"])
-		s.Equal("long", pos["side"])
-		s.Equal(0.1, pos["quantity"])
-		s.Equal(50000.0, pos["entry_price"])
+		s.Equal("BTCUSDT", pos.Symbol)
+		s.Equal("long", pos.Side)
+		s.Equal(0.1, pos.Quantity)
+		s.Equal(50000.0, pos.EntryPrice)
+		s.Equal(10.0, pos.Leverage)
+		s.InDelta(510.0, pos.MarginUsed, 1e-9) // 0.1 × 51000 / 10
+		s.InDelta(19.6078, pos.UnrealizedPnLPct, 1e-4)
+		s.Require().NotNil(pos.LiquidationPrice)
+		s.Equal(45000.0, *pos.LiquidationPrice)
 	})
-}
-
-// ============================================================
-// Level 7: getCandidateCoins tests
-// ============================================================
 
-func (s *AutoTraderTestSuite) TestGetCandidateCoins() {
-	s.Run("Use database default coins", func() {
-		s.autoTrader.defaultCoins = []string{"BTC", "ETH", "BNB"}
-		s.autoTrader.tradingCoins = []string{} // Empty custom coins
+	s.Run("Unknown leverage and liquidation price", func() {
+		s.mockTrader.positions = []Position{{Symbol: "AAPL", Side: "long", EntryPrice: 100, MarkPrice: 110, Qty: 5, UnrealizedPnL: 50}}
+		defer func() { s.mockTrader.positions = []Position{} }()
 
-		coins, err := s.autoTrader.getCandidateCoins()
+		positions, err := s.autoTrader.GetPositions()
 
 		s.NoError(err)
-		s.Equal(3, len(coins))
-		s.Equal("BTCUSDT", coins[0].Symbol)
-		s.Equal("ETHUSDT", coins[1].Symbol)
-		s.Equal("BNBUSDT", coins[2].Symbol)
-		s.Contains(coins[0].Sources, "default")
-	})
-
-	s.Run("Use custom coins", func() {
-		s.autoTrader.tradingCoins = []string{"SOL", "AVAX"}
-
-		coins, err := s.autoTrader.getCandidateCoins()
+		s.Require().Equal(1, len(positions))
+		pos := positions[0]
+		s.Nil(pos.LiquidationPrice, "liquidation price not reported should be omitted")
+		s.Equal(0.0, pos.Leverage)
+		s.Equal(0.0, pos.MarginUsed)
+		s.Equal(0.0, pos.UnrealizedPnLPct)
 
-		s.NoError(err)
-		s.Equal(2, len(coins))
-		s.Equal("SOLUSDT", coins[0].Symbol)
-		s.Equal("AVAXUSDT", coins[1].Symbol)
-		s.Contains(coins[0].Sources, "custom")
+		data, _ := json.Marshal(pos)
+		s.NotContains(string(data), "liquidation_price")
 	})
+}
 
-	s.Run("Use AI500+OI as fallback", func() {
-		s.autoTrader.defaultCoins = []string{} // Empty default coins
-		s.autoTrader.tradingCoins = []string{} // Empty custom coins
-
-		// Mock provider.GetMergedCoinPool
-		s.patches.ApplyFunc(provider.GetMergedCoinPool, func(ai500Limit int) (*provider.MergedCoinPool, error) {
-			return &provider.MergedCoinPool{
-				AllSymbols: []string{"BTCUSDT", "ETHUSDT"},
-				SymbolSources: map[string][]string{
-					"BTCUSDT": {"ai500", "oi_top"},
-					"ETHUSDT": {"ai500"},
-				},
-			}, nil
-		})
+// ============================================================
+// Level 7: GetCandidateStocks tests
+// ============================================================
 
-		coins, err := s.autoTrader.getCandidateCoins()
+func (s *AutoTraderTestSuite) TestGetCandidateStocks() {
+	s.Run("Use static stocks of the strategy", func() {
+		stocks, err := s.autoTrader.strategyEngine.GetCandidateStocks()
 
 		s.NoError(err)
-		s.Equal(2, len(coins))
+		s.Equal(2, len(stocks))
+		s.Equal("BTCUSDT", stocks[0].Symbol)
+		s.Equal("ETHUSDT", stocks[1].Symbol)
+		s.Contains(stocks[0].Sources, "static")
 	})
 }
 
@@ -388,10 +379,10 @@ func (s *AutoTraderTestSuite) TestBuildTradingContext() {
 	s.NotNil(ctx)
 
 	// Verify core fields
-	s.Equal(10100.0, ctx.Account.TotalEquity) // 10000 + 100
+	s.Equal(10000.0, ctx.Account.TotalEquity) // Initial balance, no positions
 	s.Equal(8000.0, ctx.Account.AvailableBalance)
-	s.Equal(10, ctx.BTCETHLeverage)
-	s.Equal(5, ctx.AltcoinLeverage)
+	s.Equal(10, ctx.LargeCapLeverage)
+	s.Equal(5, ctx.SmallCapLeverage)
 }
 
 // ============================================================
@@ -431,7 +422,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			name:         "Long - insufficient margin",
 			action:       "open_long",
 			availBalance: 0.0,
-			expectedErr:  "Insufficient margin",
+			expectedErr:  "No margin left",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenLongWithRecord(d, a)
 			},
@@ -440,7 +431,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			name:         "Short - insufficient margin",
 			action:       "open_short",
 			availBalance: 0.0,
-			expectedErr:  "Insufficient margin",
+			expectedErr:  "No margin left",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenShortWithRecord(d, a)
 			},
@@ -450,7 +441,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			action:       "open_long",
 			existingSide: "long",
 			availBalance: 8000.0,
-			expectedErr:  "Already has long position",
+			expectedErr:  "already has long position",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenLongWithRecord(d, a)
 			},
@@ -460,7 +451,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			action:       "open_short",
 			existingSide: "short",
 			availBalance: 8000.0,
-			expectedErr:  "Already has short position",
+			expectedErr:  "already has short position",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenShortWithRecord(d, a)
 			},
@@ -476,9 +467,9 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 
 			s.mockTrader.balance["availableBalance"] = tt.availBalance
 			if tt.existingSide != "" {
-				s.mockTrader.positions = []map[string]interface{}{{"symbol": "BTCUSDT", "side": tt.existingSide}}
+				s.mockTrader.positions = []Position{{Symbol: "BTCUSDT", Side: tt.existingSide}}
 			} else {
-				s.mockTrader.positions = []map[string]interface{}{}
+				s.mockTrader.positions = []Position{}
 			}
 
 			decision := &decision.Decision{Action: tt.action, Symbol: "BTCUSDT", PositionSizeUSD: 1000.0, Leverage: 10}
@@ -498,7 +489,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 
 			// Restore default state
 			s.mockTrader.balance["availableBalance"] = 8000.0
-			s.mockTrader.positions = []map[string]interface{}{}
+			s.mockTrader.positions = []Position{}
 		})
 	}
 }
@@ -608,7 +599,7 @@ func (s *AutoTraderTestSuite) TestExecuteDecisionWithRecord() {
 
 		err := s.autoTrader.executeDecisionWithRecord(decision, actionRecord)
 		s.Error(err)
-		s.Contains(err.Error(), "Unknown action")
+		s.Contains(err.Error(), "unknown action")
 	})
 }
 
@@ -631,14 +622,14 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		},
 		{
 			name:           "No positions - no panic",
-			setupPositions: func() { s.mockTrader.positions = []map[string]interface{}{} },
+			setupPositions: func() { s.mockTrader.positions = []Position{} },
 			skipCacheCheck: true,
 		},
 		{
 			name: "Profit less than 5% - no close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50150.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, EntryPrice: 50000, MarkPrice: 50150, Leverage: 10},
 				}
 			},
 			setupPeakPnL:   func() { s.autoTrader.ClearPeakPnLCache("BTCUSDT", "long") },
@@ -647,8 +638,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Drawdown less than 40% - no close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50400.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, EntryPrice: 50000, MarkPrice: 50400, Leverage: 10},
 				}
 			},
 			setupPeakPnL:   func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -657,8 +648,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Long - trigger drawdown close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50300.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, EntryPrice: 50000, MarkPrice: 50300, Leverage: 10},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -668,8 +659,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Short - trigger drawdown close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "ETHUSDT", "side": "short", "positionAmt": -0.5, "entryPrice": 3000.0, "markPrice": 2982.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "ETHUSDT", Side: "short", Qty: 0.5, EntryPrice: 3000, MarkPrice: 2982, Leverage: 10},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("ETHUSDT", "short", 10.0) },
@@ -679,8 +670,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Long - close failed - keep cache",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50300.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, EntryPrice: 50000, MarkPrice: 50300, Leverage: 10},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -692,8 +683,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Short - close failed - keep cache",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "ETHUSDT", "side": "short", "positionAmt": -0.5, "entryPrice": 3000.0, "markPrice": 2982.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "ETHUSDT", Side: "short", Qty: 0.5, EntryPrice: 3000, MarkPrice: 2982, Leverage: 10},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("ETHUSDT", "short", 10.0) },
@@ -704,6 +695,11 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		},
 	}
 
+	// Mock market.Get (closing a position reads the current price)
+	s.patches.ApplyFunc(market.Get, func(symbol string) (*market.Data, error) {
+		return &market.Data{Symbol: symbol, CurrentPrice: 50000.0}, nil
+	})
+
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
 			if tt.setupPositions != nil {
@@ -732,7 +728,7 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 			}
 
 			// Clean up state
-			s.mockTrader.positions = []map[string]interface{}{}
+			s.mockTrader.positions = []Position{}
 		})
 	}
 }
@@ -756,7 +752,7 @@ func (m *MockDatabase) UpdateTraderInitialBalance(userID, traderID string, newBa
 // MockTrader Enhanced version (with error control)
 type MockTrader struct {
 	balance              map[string]interface{}
-	positions            []map[string]interface{}
+	positions            []Position
 	shouldFailBalance    bool
 	shouldFailPositions  bool
 	shouldFailOpenLong   bool
@@ -778,12 +774,12 @@ func (m *MockTrader) GetBalance() (map[string]interface{}, error) {
 	return m.balance, nil
 }
 
-func (m *MockTrader) GetPositions() ([]map[string]interface{}, error) {
+func (m *MockTrader) GetPositions() ([]Position, error) {
 	if m.shouldFailPositions {
 		return nil, errors.New("failed to get positions")
 	}
 	if m.positions == nil {
-		return []map[string]interface{}{}, nil
+		return []Position{}, nil
 	}
 	return m.positions, nil
 }
@@ -865,6 +861,14 @@ func (m *MockTrader) FormatQuantity(symbol string, quantity float64) (string, er
 	return fmt.Sprintf("%.4f", quantity), nil
 }
 
+func (m *MockTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "FILLED"}, nil
+}
+
+func (m *MockTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return nil, nil
+}
+
 // ============================================================
 // Test suite entry point
 // ============================================================
@@ -0,0 +1,98 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Buy-and-Hold Benchmark
+// ============================================================================
+// Every equity snapshot also records the benchmark of the strategy (BTCUSDT by
+// default, SPY for stock traders, ...): its price and the value of the initial
+// balance had it been held there since tracking began. The price comes from
+// the trader's exchange, or from market data when the exchange does not list
+// the symbol. The equity history API and the daily summary compare both curves
+// (alpha).
+
+// benchmarkBase first tracked snapshot of the benchmark symbol
+type benchmarkBase struct {
+	Symbol string
+	Price  float64
+	Equity float64
+}
+
+// benchmarkConfig benchmark settings of the strategy (defaults without strategy engine)
+func (at *AutoTrader) benchmarkConfig() store.BenchmarkConfig {
+	engine := at.engine()
+	if engine == nil {
+		return store.BenchmarkConfig{Enabled: true, Symbol: store.DefaultBenchmarkSymbol}
+	}
+	cfg := engine.GetConfig().Benchmark
+	if cfg.Symbol == "" {
+		cfg.Symbol = store.DefaultBenchmarkSymbol
+	}
+	return cfg
+}
+
+// applyBenchmark fills the benchmark fields of an equity snapshot (left empty if the price is unavailable)
+func (at *AutoTrader) applyBenchmark(snapshot *store.EquitySnapshot) {
+	cfg := at.benchmarkConfig()
+	if !cfg.Enabled || at.initialBalance <= 0 {
+		return
+	}
+	price, err := at.benchmarkPrice(cfg.Symbol)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Benchmark %s not tracked this cycle: %v", at.name, cfg.Symbol, err)
+		return
+	}
+
+	if at.benchmarkBase.Symbol != cfg.Symbol {
+		basePrice, baseEquity, err := at.store.Equity().GetBenchmarkBase(at.id, cfg.Symbol)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Benchmark %s not tracked this cycle: %v", at.name, cfg.Symbol, err)
+			return
+		}
+		if basePrice <= 0 {
+			// First snapshot of this benchmark: the initial balance is bought in at the current price
+			basePrice, baseEquity = price, at.initialBalance
+			logger.Infof("📏 [%s] Benchmark tracking started: %.2f held in %s at %.4f", at.name, baseEquity, cfg.Symbol, price)
+		}
+		at.benchmarkBase = benchmarkBase{Symbol: cfg.Symbol, Price: basePrice, Equity: baseEquity}
+	}
+
+	snapshot.BenchmarkSymbol = cfg.Symbol
+	snapshot.BenchmarkPrice = price
+	snapshot.BenchmarkEquity = at.benchmarkBase.Equity * price / at.benchmarkBase.Price
+}
+
+// benchmarkPrice current price of the benchmark: exchange first, market data as fallback
+func (at *AutoTrader) benchmarkPrice(symbol string) (float64, error) {
+	if price, err := at.trader.GetMarketPrice(symbol); err == nil && price > 0 {
+		return price, nil
+	}
+	data, err := market.Get(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if data.CurrentPrice <= 0 {
+		return 0, fmt.Errorf("no price for %s", symbol)
+	}
+	return data.CurrentPrice, nil
+}
+
+// benchmarkPerformance account vs benchmark return since a point in time (false if not tracked)
+func (at *AutoTrader) benchmarkPerformance(since time.Time) (store.BenchmarkPerformance, bool) {
+	if at.store == nil {
+		return store.BenchmarkPerformance{}, false
+	}
+	snapshots, err := at.store.Equity().GetByTimeRange(at.id, since, time.Now())
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load equity for benchmark comparison: %v", at.name, err)
+		return store.BenchmarkPerformance{}, false
+	}
+	return store.WindowPerformance(snapshots)
+}
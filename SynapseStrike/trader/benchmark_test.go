@@ -0,0 +1,70 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// benchmarkPriceMock exchange quoting a settable price
+type benchmarkPriceMock struct {
+	Trader
+	price float64
+}
+
+func (m *benchmarkPriceMock) GetMarketPrice(symbol string) (float64, error) {
+	return m.price, nil
+}
+
+// TestApplyBenchmark tests that the initial balance is bought in at the first snapshot and tracked afterwards
+func TestApplyBenchmark(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	mock := &benchmarkPriceMock{price: 50000}
+	at := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st, initialBalance: 1000}
+
+	start := time.Now().Add(-time.Hour).UTC()
+	first := &store.EquitySnapshot{TraderID: "trader-1", Timestamp: start, TotalEquity: 1000}
+	at.applyBenchmark(first)
+	if first.BenchmarkSymbol != store.DefaultBenchmarkSymbol || first.BenchmarkEquity != 1000 {
+		t.Fatalf("expected initial balance held in %s, got %+v", store.DefaultBenchmarkSymbol, first)
+	}
+	if err := st.Equity().Save(first); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+
+	// Restarted trader: the base is loaded from the first tracked snapshot
+	mock.price = 55000
+	at = &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st, initialBalance: 1000}
+	last := &store.EquitySnapshot{TraderID: "trader-1", Timestamp: start.Add(30 * time.Minute), TotalEquity: 1150}
+	at.applyBenchmark(last)
+	if math.Abs(last.BenchmarkEquity-1100) > 1e-9 {
+		t.Fatalf("expected benchmark equity 1100 after +10%%, got %.4f", last.BenchmarkEquity)
+	}
+	if err := st.Equity().Save(last); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+
+	perf, ok := at.benchmarkPerformance(start.Add(-time.Minute))
+	if !ok {
+		t.Fatal("expected benchmark performance")
+	}
+	if math.Abs(perf.ReturnPct-15) > 1e-9 || math.Abs(perf.BenchmarkReturnPct-10) > 1e-9 || math.Abs(perf.AlphaPct-5) > 1e-9 {
+		t.Errorf("expected return 15%%, benchmark 10%%, alpha 5%%, got %+v", perf)
+	}
+
+	// Disabled in the strategy: snapshot left untouched
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Benchmark.Enabled = false
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	untracked := &store.EquitySnapshot{TraderID: "trader-1", TotalEquity: 1200}
+	at.applyBenchmark(untracked)
+	if untracked.BenchmarkSymbol != "" || untracked.BenchmarkEquity != 0 {
+		t.Errorf("expected no benchmark when disabled, got %+v", untracked)
+	}
+}
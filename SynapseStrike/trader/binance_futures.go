@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"SynapseStrike/hook"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"math"
 	"strconv"
 	"strings"
 	"sync"
@@ -45,7 +47,9 @@ func getBrOrderID() string {
 
 // FuturesTrader Binance futures trader
 type FuturesTrader struct {
-	client *futures.Client
+	client  *futures.Client
+	limiter *RateLimiter // Shared with other traders on the same API key
+	testnet bool         // testnet.binancefuture.com REST and websocket endpoints
 
 	// Balance cache
 	cachedBalance     map[string]interface{}
@@ -53,7 +57,7 @@ type FuturesTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Position cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
@@ -61,21 +65,33 @@ type FuturesTrader struct {
 	cacheDuration time.Duration
 }
 
-// NewFuturesTrader creates futures trader
-func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
+// NewFuturesTrader creates futures trader (testnet: Binance futures testnet, requires testnet API keys)
+func NewFuturesTrader(apiKey, secretKey string, userId string, testnet bool) *FuturesTrader {
 	client := futures.NewClient(apiKey, secretKey)
+	if testnet {
+		client.BaseURL = futures.BaseApiTestnetUrl
+	}
 
 	hookRes := hook.HookExec[hook.NewBinanceTraderResult](hook.NEW_BINANCE_TRADER, userId, client)
 	if hookRes != nil && hookRes.GetResult() != nil {
 		client = hookRes.GetResult()
 	}
 
+	// Requests of all traders on this API key share one weight budget
+	limiter := getRateLimiter("binance", apiKey)
+	client.HTTPClient = withRateLimiter(client.HTTPClient, limiter)
+
 	// Sync time to avoid "Timestamp ahead" error
 	syncBinanceServerTime(client)
 	trader := &FuturesTrader{
 		client:        client,
+		limiter:       limiter,
+		testnet:       testnet,
 		cacheDuration: 15 * time.Second, // 15-second cache
 	}
+	if testnet {
+		logger.Infof("🧪 [Binance] Using futures testnet")
+	}
 
 	// Set dual-side position mode (Hedge Mode)
 	// This is required because the code uses PositionSide (LONG/SHORT)
@@ -86,6 +102,11 @@ func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
 	return trader
 }
 
+// RateLimitStatus returns state of the API key's shared rate limiter
+func (t *FuturesTrader) RateLimitStatus() RateLimitStatus {
+	return t.limiter.Status()
+}
+
 // setDualSidePosition sets dual-side position mode (called during initialization)
 func (t *FuturesTrader) setDualSidePosition() error {
 	// Try to set dual-side position mode
@@ -162,7 +183,7 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions (with cache)
-func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *FuturesTrader) GetPositions() ([]Position, error) {
 	// First check if cache is valid
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -180,31 +201,29 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 	for _, pos := range positions {
 		posAmt, _ := strconv.ParseFloat(pos.PositionAmt, 64)
 		if posAmt == 0 {
 			continue // Skip positions with zero amount
 		}
 
-		posMap := make(map[string]interface{})
-		posMap["symbol"] = pos.Symbol
-		posMap["positionAmt"], _ = strconv.ParseFloat(pos.PositionAmt, 64)
-		posMap["entryPrice"], _ = strconv.ParseFloat(pos.EntryPrice, 64)
-		posMap["markPrice"], _ = strconv.ParseFloat(pos.MarkPrice, 64)
-		posMap["unRealizedProfit"], _ = strconv.ParseFloat(pos.UnRealizedProfit, 64)
-		posMap["leverage"], _ = strconv.ParseFloat(pos.Leverage, 64)
-		posMap["liquidationPrice"], _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
+		p := Position{Symbol: pos.Symbol, Qty: math.Abs(posAmt)}
+		p.EntryPrice, _ = strconv.ParseFloat(pos.EntryPrice, 64)
+		p.MarkPrice, _ = strconv.ParseFloat(pos.MarkPrice, 64)
+		p.UnrealizedPnL, _ = strconv.ParseFloat(pos.UnRealizedProfit, 64)
+		p.Leverage, _ = strconv.ParseFloat(pos.Leverage, 64)
+		p.LiqPrice, _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
 		// Note: Binance SDK doesn't expose updateTime field, will fallback to local tracking
 
 		// Determine direction
 		if posAmt > 0 {
-			posMap["side"] = "long"
+			p.Side = "long"
 		} else {
-			posMap["side"] = "short"
+			p.Side = "short"
 		}
 
-		result = append(result, posMap)
+		result = append(result, p)
 	}
 
 	// Update cache
@@ -274,11 +293,9 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 	positions, err := t.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == symbol {
-				if lev, ok := pos["leverage"].(float64); ok {
-					currentLeverage = int(lev)
-					break
-				}
+			if pos.Symbol == symbol {
+				currentLeverage = int(pos.Leverage)
+				break
 			}
 		}
 	}
@@ -423,6 +440,71 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 	return result, nil
 }
 
+// PlaceLimitEntry places a GTC limit order opening a position (implements LimitEntryTrader)
+// Unlike OpenLong/OpenShort, existing orders of the symbol are left in place.
+func (t *FuturesTrader) PlaceLimitEntry(symbol, side string, quantity, price float64, leverage int) (map[string]interface{}, error) {
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	quantityFloat, parseErr := strconv.ParseFloat(quantityStr, 64)
+	if parseErr != nil || quantityFloat <= 0 {
+		return nil, fmt.Errorf("position size too small, rounded to 0 (original: %.8f → formatted: %s)", quantity, quantityStr)
+	}
+	if err := t.CheckMinNotional(symbol, quantityFloat); err != nil {
+		return nil, err
+	}
+	priceStr := t.FormatPrice(symbol, price)
+
+	orderSide, positionSide := futures.SideTypeBuy, futures.PositionSideTypeLong
+	if side == "short" {
+		orderSide, positionSide = futures.SideTypeSell, futures.PositionSideTypeShort
+	}
+
+	order, err := t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(orderSide).
+		PositionSide(positionSide).
+		Type(futures.OrderTypeLimit).
+		TimeInForce(futures.TimeInForceTypeGTC).
+		Quantity(quantityStr).
+		Price(priceStr).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to place limit entry: %w", err)
+	}
+
+	logger.Infof("📌 Placed GTC limit entry: %s %s quantity: %s price: %s, order ID: %d", symbol, side, quantityStr, priceStr, order.OrderID)
+
+	result := make(map[string]interface{})
+	result["orderId"] = order.OrderID
+	result["symbol"] = order.Symbol
+	result["status"] = order.Status
+	result["quantity"] = quantityFloat
+	return result, nil
+}
+
+// CancelLimitEntry cancels a resting limit entry order (implements LimitEntryTrader)
+func (t *FuturesTrader) CancelLimitEntry(symbol, orderID string) error {
+	orderIDInt, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %s", orderID)
+	}
+	_, err = t.client.NewCancelOrderService().
+		Symbol(symbol).
+		OrderID(orderIDInt).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to cancel limit entry: %w", err)
+	}
+	return nil
+}
+
 // CloseLong closes a long position
 func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
@@ -433,8 +515,8 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -488,8 +570,8 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = -pos["positionAmt"].(float64) // Short position quantity is negative, take absolute value
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -806,40 +888,87 @@ func (t *FuturesTrader) CalculatePositionSize(balance, riskPercent, price float6
 // SetStopLoss sets stop-loss order using new Algo Order API
 // Binance has migrated stop orders to Algo Order system (error -4120 STOP_ORDER_SWITCH_ALGO)
 func (t *FuturesTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
-	var side futures.SideType
-	var posSide futures.PositionSideType
+	_, err := t.PlaceStopLoss(symbol, positionSide, quantity, stopPrice)
+	return err
+}
 
-	if positionSide == "LONG" {
-		side = futures.SideTypeSell
-		posSide = futures.PositionSideTypeLong
-	} else {
-		side = futures.SideTypeBuy
-		posSide = futures.PositionSideTypeShort
+// SetTakeProfit sets take-profit order using new Algo Order API
+// Binance has migrated stop orders to Algo Order system (error -4120 STOP_ORDER_SWITCH_ALGO)
+func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	_, err := t.PlaceTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+	return err
+}
+
+// PlaceStopLoss places a stop-loss Algo order, returns its Algo ID (implements ProtectiveOrderTrader)
+func (t *FuturesTrader) PlaceStopLoss(symbol string, positionSide string, quantity, stopPrice float64) (string, error) {
+	algoID, err := t.placeAlgoCloseOrder(symbol, positionSide, futures.AlgoOrderTypeStopMarket, stopPrice)
+	if err != nil {
+		return "", fmt.Errorf("failed to set stop-loss: %w", err)
 	}
 
-	// Use new Algo Order API
-	_, err := t.client.NewCreateAlgoOrderService().
+	logger.Infof("  Stop-loss price set (Algo Order %s): %.4f", algoID, stopPrice)
+	return algoID, nil
+}
+
+// PlaceTakeProfit places a take-profit Algo order, returns its Algo ID (implements ProtectiveOrderTrader)
+func (t *FuturesTrader) PlaceTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	algoID, err := t.placeAlgoCloseOrder(symbol, positionSide, futures.AlgoOrderTypeTakeProfitMarket, takeProfitPrice)
+	if err != nil {
+		return "", fmt.Errorf("failed to set take-profit: %w", err)
+	}
+
+	logger.Infof("  Take-profit price set (Algo Order %s): %.4f", algoID, takeProfitPrice)
+	return algoID, nil
+}
+
+// PlacePartialTakeProfit places a take-profit Algo order for quantity, returns its Algo ID (implements ScaledTakeProfitTrader)
+// Unlike PlaceTakeProfit it does not close the whole position; in hedge mode the position side makes it reduce-only.
+func (t *FuturesTrader) PlacePartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return "", err
+	}
+	side, posSide := futures.SideTypeBuy, futures.PositionSideTypeShort
+	if positionSide == "LONG" {
+		side, posSide = futures.SideTypeSell, futures.PositionSideTypeLong
+	}
+	resp, err := t.client.NewCreateAlgoOrderService().
 		Symbol(symbol).
 		Side(side).
 		PositionSide(posSide).
-		Type(futures.AlgoOrderTypeStopMarket).
-		TriggerPrice(fmt.Sprintf("%.8f", stopPrice)).
+		Type(futures.AlgoOrderTypeTakeProfitMarket).
+		TriggerPrice(t.FormatPrice(symbol, takeProfitPrice)).
 		WorkingType(futures.WorkingTypeContractPrice).
-		ClosePosition(true).
+		Quantity(quantityStr).
 		ClientAlgoId(getBrOrderID()).
 		Do(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to set partial take-profit: %w", err)
+	}
+
+	algoID := strconv.FormatInt(resp.AlgoId, 10)
+	logger.Infof("  Partial take-profit set (Algo Order %s): %s @ %.4f", algoID, quantityStr, takeProfitPrice)
+	return algoID, nil
+}
 
+// CancelProtectiveOrder cancels a stop-loss/take-profit Algo order by Algo ID (implements ProtectiveOrderTrader)
+func (t *FuturesTrader) CancelProtectiveOrder(symbol, orderID string) error {
+	algoID, err := strconv.ParseInt(orderID, 10, 64)
 	if err != nil {
-		return fmt.Errorf("failed to set stop-loss: %w", err)
+		return fmt.Errorf("invalid Algo ID %q: %w", orderID, err)
+	}
+	if _, err := t.client.NewCancelAlgoOrderService().
+		AlgoID(algoID).
+		Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel Algo order %d: %w", algoID, err)
 	}
 
-	logger.Infof("  Stop-loss price set (Algo Order): %.4f", stopPrice)
+	logger.Infof("  ✓ Canceled Algo order %d (%s)", algoID, symbol)
 	return nil
 }
 
-// SetTakeProfit sets take-profit order using new Algo Order API
-// Binance has migrated stop orders to Algo Order system (error -4120 STOP_ORDER_SWITCH_ALGO)
-func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+// placeAlgoCloseOrder places a close-position trigger order, returns its Algo ID
+func (t *FuturesTrader) placeAlgoCloseOrder(symbol, positionSide string, orderType futures.AlgoOrderType, triggerPrice float64) (string, error) {
 	var side futures.SideType
 	var posSide futures.PositionSideType
 
@@ -852,23 +981,20 @@ func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quanti
 	}
 
 	// Use new Algo Order API
-	_, err := t.client.NewCreateAlgoOrderService().
+	resp, err := t.client.NewCreateAlgoOrderService().
 		Symbol(symbol).
 		Side(side).
 		PositionSide(posSide).
-		Type(futures.AlgoOrderTypeTakeProfitMarket).
-		TriggerPrice(fmt.Sprintf("%.8f", takeProfitPrice)).
+		Type(orderType).
+		TriggerPrice(fmt.Sprintf("%.8f", triggerPrice)).
 		WorkingType(futures.WorkingTypeContractPrice).
 		ClosePosition(true).
 		ClientAlgoId(getBrOrderID()).
 		Do(context.Background())
-
 	if err != nil {
-		return fmt.Errorf("failed to set take-profit: %w", err)
+		return "", err
 	}
-
-	logger.Infof("  Take-profit price set (Algo Order): %.4f", takeProfitPrice)
-	return nil
+	return strconv.FormatInt(resp.AlgoId, 10), nil
 }
 
 // GetMinNotional gets minimum notional value (Binance requirement)
@@ -922,6 +1048,34 @@ func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
 	return 3, nil // Default precision is 3
 }
 
+// FormatPrice rounds price to the symbol's tick size (PRICE_FILTER)
+func (t *FuturesTrader) FormatPrice(symbol string, price float64) string {
+	if tick, ok := market.ParseIncrement(t.symbolFilterValue(symbol, "PRICE_FILTER", "tickSize")); ok {
+		return market.FormatPriceTick(price, tick)
+	}
+	return market.Symbols.FormatPrice(symbol, price)
+}
+
+// symbolFilterValue gets a value of a symbol filter from exchange info ("" if unavailable)
+func (t *FuturesTrader) symbolFilterValue(symbol, filterType, key string) string {
+	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		return ""
+	}
+	for _, s := range exchangeInfo.Symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+		for _, filter := range s.Filters {
+			if filter["filterType"] == filterType {
+				value, _ := filter[key].(string)
+				return value
+			}
+		}
+	}
+	return ""
+}
+
 // calculatePrecision calculates precision from stepSize
 func calculatePrecision(stepSize string) int {
 	// Remove trailing zeros
@@ -965,16 +1119,16 @@ func trimTrailingZeros(s string) string {
 	return s
 }
 
-// FormatQuantity formats quantity to correct precision
+// FormatQuantity rounds quantity down to the symbol's step size (LOT_SIZE)
 func (t *FuturesTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	precision, err := t.GetSymbolPrecision(symbol)
-	if err != nil {
-		// If retrieval fails, use default format
-		return fmt.Sprintf("%.3f", quantity), nil
+	if step, ok := market.ParseIncrement(t.symbolFilterValue(symbol, "LOT_SIZE", "stepSize")); ok {
+		return market.FormatQuantityStep(quantity, step), nil
 	}
-
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, quantity), nil
+	// Exchange info unavailable: registry step, else the default precision of 3 decimals
+	if market.Symbols.MinQuantity(symbol) > 0 {
+		return market.Symbols.FormatQuantity(symbol, quantity), nil
+	}
+	return market.FormatQuantityStep(quantity, market.IncrementFromPlaces(3)), nil
 }
 
 // Helper functions
@@ -0,0 +1,166 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+const binanceListenKeyKeepalive = 30 * time.Minute // Listen keys expire after 60 minutes without keepalive
+
+// StreamPositions real-time positions from the user data stream (account updates, fills)
+// and the all-market mark price stream (1s)
+func (t *FuturesTrader) StreamPositions(stop <-chan struct{}, updates chan<- PositionUpdate) error {
+	listenKey, err := t.client.NewStartUserStreamService().Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to start user data stream: %w", err)
+	}
+
+	errC := make(chan error, 1)
+	errHandler := func(err error) {
+		select {
+		case errC <- err:
+		default:
+		}
+	}
+
+	onUserData := func(event *futures.WsUserDataEvent) {
+		switch event.Event {
+		case futures.UserDataEventTypeAccountUpdate:
+			for _, p := range event.AccountUpdate.Positions {
+				amount, _ := strconv.ParseFloat(p.Amount, 64)
+				entryPrice, _ := strconv.ParseFloat(p.EntryPrice, 64)
+				side := strings.ToLower(string(p.Side))
+				if p.Side == futures.PositionSideTypeBoth {
+					if amount == 0 {
+						continue // One-way mode close: side unknown, the interval poll prunes it
+					}
+					side = "long"
+					if amount < 0 {
+						side = "short"
+					}
+				}
+				pushPositionUpdate(updates, stop, PositionUpdate{
+					Symbol:     p.Symbol,
+					Side:       side,
+					EntryPrice: entryPrice,
+					Quantity:   math.Abs(amount),
+					Snapshot:   true,
+				})
+			}
+		case futures.UserDataEventTypeOrderTradeUpdate:
+			trade := event.OrderTradeUpdate
+			if trade.ExecutionType != futures.OrderExecutionTypeTrade {
+				return
+			}
+			if price, _ := strconv.ParseFloat(trade.LastFilledPrice, 64); price > 0 {
+				pushPositionUpdate(updates, stop, PositionUpdate{Symbol: trade.Symbol, MarkPrice: price})
+			}
+		case futures.UserDataEventTypeListenKeyExpired:
+			errHandler(fmt.Errorf("listen key expired"))
+		}
+	}
+	onMarkPrices := func(event futures.WsAllMarkPriceEvent) {
+		for _, mark := range event {
+			if price, _ := strconv.ParseFloat(mark.MarkPrice, 64); price > 0 {
+				pushPositionUpdate(updates, stop, PositionUpdate{Symbol: mark.Symbol, MarkPrice: price})
+			}
+		}
+	}
+
+	var userDone, userStop, markDone, markStop chan struct{}
+	if t.testnet {
+		userDone, userStop, err = binanceTestnetServe(listenKey, onUserData, errHandler)
+	} else {
+		userDone, userStop, err = futures.WsUserDataServe(listenKey, onUserData, errHandler)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect user data stream: %w", err)
+	}
+	defer close(userStop)
+
+	if t.testnet {
+		markDone, markStop, err = binanceTestnetServe("!markPrice@arr@1s", onMarkPrices, errHandler)
+	} else {
+		markDone, markStop, err = futures.WsAllMarkPriceServeWithRate(time.Second, onMarkPrices, errHandler)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect mark price stream: %w", err)
+	}
+	defer close(markStop)
+
+	keepalive := time.NewTicker(binanceListenKeyKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-errC:
+			return err
+		case <-userDone:
+			return fmt.Errorf("user data stream closed")
+		case <-markDone:
+			return fmt.Errorf("mark price stream closed")
+		case <-keepalive.C:
+			if err := t.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(context.Background()); err != nil {
+				return fmt.Errorf("failed to keep user data stream alive: %w", err)
+			}
+		}
+	}
+}
+
+// binanceTestnetServe testnet counterpart of the futures.WsXxxServe functions: decodes messages
+// of stream path (listen key or stream name) as T until stopC is closed
+// go-binance picks websocket endpoints by the process-wide futures.UseTestnet flag, so testnet
+// traders connect directly and can run next to production traders.
+func binanceTestnetServe[T any](path string, handler func(T), errHandler futures.ErrHandler) (doneC, stopC chan struct{}, err error) {
+	conn, err := dialStreamConn(futures.BaseWsTestnetUrl + "/" + path)
+	if err != nil {
+		return nil, nil, err
+	}
+	doneC = make(chan struct{})
+	stopC = make(chan struct{})
+
+	go func() {
+		// The user data stream can be silent for minutes; pongs extend the read deadline
+		ping := time.NewTicker(streamPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case <-stopC:
+				conn.Close()
+				return
+			case <-ping.C:
+				conn.ping()
+			}
+		}
+	}()
+
+	go func() {
+		defer close(doneC)
+		for {
+			message, err := conn.read()
+			if err != nil {
+				select {
+				case <-stopC:
+				default:
+					errHandler(err)
+				}
+				return
+			}
+			var event T
+			if err := json.Unmarshal(message, &event); err != nil {
+				errHandler(err)
+				continue
+			}
+			handler(event)
+		}
+	}()
+	return doneC, stopC, nil
+}
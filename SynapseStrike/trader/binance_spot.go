@@ -0,0 +1,286 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// binanceSpotVenue Binance spot market access for SpotTrader
+type binanceSpotVenue struct {
+	client *binance.Client
+	quote  string // Quote asset of traded pairs (USDT/USDC)
+
+	// Exchange info cache (lot and tick sizes)
+	symbolsMu     sync.Mutex
+	symbols       map[string]binance.Symbol
+	symbolsLoaded time.Time
+}
+
+// binanceSpotInfoTTL refresh interval of cached exchange info
+const binanceSpotInfoTTL = time.Hour
+
+// NewBinanceSpotTrader creates Binance spot trader (testnet: testnet.binance.vision, requires testnet API keys)
+func NewBinanceSpotTrader(apiKey, secretKey, quoteCurrency string, testnet bool) *SpotTrader {
+	client := binance.NewClient(apiKey, secretKey)
+	if testnet {
+		client.BaseURL = binance.BaseAPITestnetURL
+		logger.Infof("🧪 [Binance Spot] Using spot testnet")
+	}
+	// Spot has its own weight budget, separate from futures on the same key
+	client.HTTPClient = withRateLimiter(client.HTTPClient, getRateLimiter("binance-spot", apiKey))
+
+	if serverTime, err := client.NewServerTimeService().Do(context.Background()); err == nil {
+		client.TimeOffset = time.Now().UnixMilli() - serverTime
+	} else {
+		logger.Infof("⚠️ Failed to sync Binance spot server time: %v", err)
+	}
+
+	quote := strings.ToUpper(strings.TrimSpace(quoteCurrency))
+	if quote == "" || quote == "USD" {
+		quote = "USDT"
+	}
+	return newSpotTrader(&binanceSpotVenue{client: client, quote: quote})
+}
+
+func (v *binanceSpotVenue) Name() string       { return "binance-spot" }
+func (v *binanceSpotVenue) QuoteAsset() string { return v.quote }
+
+// pair exchange symbol of a canonical symbol in the account's quote asset
+func (v *binanceSpotVenue) pair(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range []string{"USDT", "USDC"} {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base + v.quote
+		}
+	}
+	return symbol
+}
+
+func (v *binanceSpotVenue) Balances() (map[string]spotBalance, error) {
+	account, err := v.client.NewGetAccountService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	result := make(map[string]spotBalance)
+	for _, b := range account.Balances {
+		free, _ := strconv.ParseFloat(b.Free, 64)
+		locked, _ := strconv.ParseFloat(b.Locked, 64)
+		if free+locked > 0 {
+			result[b.Asset] = spotBalance{Free: free, Locked: locked}
+		}
+	}
+	return result, nil
+}
+
+func (v *binanceSpotVenue) Price(symbol string) (float64, error) {
+	prices, err := v.client.NewListPricesService().Symbol(v.pair(symbol)).Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("price not found")
+	}
+	return strconv.ParseFloat(prices[0].Price, 64)
+}
+
+func (v *binanceSpotVenue) MarketOrder(symbol, side string, quantity float64) (*spotFill, error) {
+	quantityStr, err := v.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if q, _ := strconv.ParseFloat(quantityStr, 64); q <= 0 {
+		return nil, fmt.Errorf("quantity too small, rounded to 0 (original: %.8f)", quantity)
+	}
+
+	order, err := v.client.NewCreateOrderService().
+		Symbol(v.pair(symbol)).
+		Side(binance.SideType(side)).
+		Type(binance.OrderTypeMarket).
+		Quantity(quantityStr).
+		NewOrderRespType(binance.NewOrderRespTypeFULL).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	executed, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
+	quoteQty, _ := strconv.ParseFloat(order.CummulativeQuoteQuantity, 64)
+	fill := &spotFill{OrderID: strconv.FormatInt(order.OrderID, 10), Quantity: executed}
+	if executed > 0 {
+		fill.AvgPrice = quoteQty / executed
+	}
+	for _, f := range order.Fills {
+		commission, _ := strconv.ParseFloat(f.Commission, 64)
+		switch f.CommissionAsset {
+		case v.quote:
+			fill.Fee += commission
+		case strings.TrimSuffix(v.pair(symbol), v.quote):
+			fill.Fee += commission * fill.AvgPrice
+		}
+	}
+	return fill, nil
+}
+
+// PlaceExitOrder places an OCO (stop + target), a stop-limit or a limit sell; OCO IDs are prefixed "oco:"
+func (v *binanceSpotVenue) PlaceExitOrder(symbol string, quantity, stopPrice, targetPrice float64) (string, error) {
+	pair := v.pair(symbol)
+	quantityStr, err := v.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return "", err
+	}
+	stopLimit := stopPrice * (1 - spotStopLimitSlippage)
+
+	switch {
+	case stopPrice > 0 && targetPrice > 0:
+		resp, err := v.client.NewCreateOCOService().
+			Symbol(pair).
+			Side(binance.SideTypeSell).
+			Quantity(quantityStr).
+			Price(v.formatPrice(symbol, targetPrice)).
+			StopPrice(v.formatPrice(symbol, stopPrice)).
+			StopLimitPrice(v.formatPrice(symbol, stopLimit)).
+			StopLimitTimeInForce(binance.TimeInForceTypeGTC).
+			Do(context.Background())
+		if err != nil {
+			return "", err
+		}
+		return "oco:" + strconv.FormatInt(resp.OrderListID, 10), nil
+	case stopPrice > 0:
+		order, err := v.client.NewCreateOrderService().
+			Symbol(pair).
+			Side(binance.SideTypeSell).
+			Type(binance.OrderTypeStopLossLimit).
+			TimeInForce(binance.TimeInForceTypeGTC).
+			Quantity(quantityStr).
+			StopPrice(v.formatPrice(symbol, stopPrice)).
+			Price(v.formatPrice(symbol, stopLimit)).
+			Do(context.Background())
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(order.OrderID, 10), nil
+	default:
+		order, err := v.client.NewCreateOrderService().
+			Symbol(pair).
+			Side(binance.SideTypeSell).
+			Type(binance.OrderTypeLimit).
+			TimeInForce(binance.TimeInForceTypeGTC).
+			Quantity(quantityStr).
+			Price(v.formatPrice(symbol, targetPrice)).
+			Do(context.Background())
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(order.OrderID, 10), nil
+	}
+}
+
+func (v *binanceSpotVenue) CancelOrder(symbol, orderID string) error {
+	if listID, ok := strings.CutPrefix(orderID, "oco:"); ok {
+		id, err := strconv.ParseInt(listID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OCO list ID %q: %w", orderID, err)
+		}
+		_, err = v.client.NewCancelOCOService().Symbol(v.pair(symbol)).OrderListID(id).Do(context.Background())
+		return err
+	}
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+	_, err = v.client.NewCancelOrderService().Symbol(v.pair(symbol)).OrderID(id).Do(context.Background())
+	return err
+}
+
+func (v *binanceSpotVenue) CancelAll(symbol string) error {
+	_, err := v.client.NewCancelOpenOrdersService().Symbol(v.pair(symbol)).Do(context.Background())
+	if err != nil && !strings.Contains(err.Error(), "Unknown order") {
+		return fmt.Errorf("failed to cancel open orders: %w", err)
+	}
+	return nil
+}
+
+// symbolInfo exchange info of a pair (cached)
+func (v *binanceSpotVenue) symbolInfo(symbol string) (*binance.Symbol, error) {
+	v.symbolsMu.Lock()
+	defer v.symbolsMu.Unlock()
+	if v.symbols == nil || time.Since(v.symbolsLoaded) > binanceSpotInfoTTL {
+		info, err := v.client.NewExchangeInfoService().Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trading rules: %w", err)
+		}
+		v.symbols = make(map[string]binance.Symbol, len(info.Symbols))
+		for _, s := range info.Symbols {
+			v.symbols[s.Symbol] = s
+		}
+		v.symbolsLoaded = time.Now()
+	}
+	s, ok := v.symbols[v.pair(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("unknown spot symbol %s", v.pair(symbol))
+	}
+	return &s, nil
+}
+
+func (v *binanceSpotVenue) FormatQuantity(symbol string, quantity float64) (string, error) {
+	info, err := v.symbolInfo(symbol)
+	if err != nil {
+		return "", err
+	}
+	if lot := info.LotSizeFilter(); lot != nil {
+		if step, ok := market.ParseIncrement(lot.StepSize); ok {
+			return market.FormatQuantityStep(quantity, step), nil
+		}
+	}
+	return market.Symbols.FormatQuantity(symbol, quantity), nil
+}
+
+// formatPrice rounds price to the pair's tick size
+func (v *binanceSpotVenue) formatPrice(symbol string, price float64) string {
+	if info, err := v.symbolInfo(symbol); err == nil {
+		if pf := info.PriceFilter(); pf != nil {
+			if tick, ok := market.ParseIncrement(pf.TickSize); ok {
+				return market.FormatPriceTick(price, tick)
+			}
+		}
+	}
+	return market.Symbols.FormatPrice(symbol, price)
+}
+
+func (v *binanceSpotVenue) OrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %s", orderID)
+	}
+	order, err := v.client.NewGetOrderService().Symbol(v.pair(symbol)).OrderID(id).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+
+	executedQty, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
+	quoteQty, _ := strconv.ParseFloat(order.CummulativeQuoteQuantity, 64)
+	avgPrice := 0.0
+	if executedQty > 0 {
+		avgPrice = quoteQty / executedQty
+	}
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      symbol,
+		"status":      string(order.Status),
+		"avgPrice":    avgPrice,
+		"executedQty": executedQty,
+		"side":        string(order.Side),
+		"type":        string(order.Type),
+		"time":        order.Time,
+		"updateTime":  order.UpdateTime,
+		"commission":  0.0,
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
 	"strings"
 	"sync"
@@ -47,7 +48,7 @@ type BitgetTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Positions cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
@@ -213,8 +214,8 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 // convertSymbol converts generic symbol to Bitget format
 // e.g., BTCUSDT -> BTCUSDT
 func (t *BitgetTrader) convertSymbol(symbol string) string {
-	// Bitget uses same format as input, just ensure uppercase
-	return strings.ToUpper(symbol)
+	// Bitget uses same format as canonical symbol
+	return market.ToExchangeSymbol("bitget", symbol)
 }
 
 // GetBalance gets account balance
@@ -276,7 +277,7 @@ func (t *BitgetTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions
-func (t *BitgetTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *BitgetTrader) GetPositions() ([]Position, error) {
 	// Check cache
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -314,7 +315,7 @@ func (t *BitgetTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to parse position data: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 	for _, pos := range positions {
 		total, _ := strconv.ParseFloat(pos.Total, 64)
 		if total == 0 {
@@ -335,19 +336,18 @@ func (t *BitgetTrader) GetPositions() ([]map[string]interface{}, error) {
 			side = "short"
 		}
 
-		posMap := map[string]interface{}{
-			"symbol":           pos.Symbol,
-			"positionAmt":      total,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": unrealizedPnL,
-			"leverage":         leverage,
-			"liquidationPrice": liqPrice,
-			"side":             side,
-			"createdTime":      cTime,
-			"updatedTime":      uTime,
-		}
-		result = append(result, posMap)
+		result = append(result, Position{
+			Symbol:        pos.Symbol,
+			Side:          side,
+			Qty:           total,
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: unrealizedPnL,
+			Leverage:      leverage,
+			LiqPrice:      liqPrice,
+			CreatedAt:     timeFromMillis(cTime),
+			UpdatedAt:     timeFromMillis(uTime),
+		})
 	}
 
 	// Update cache
@@ -604,8 +604,8 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -667,8 +667,8 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -778,7 +778,7 @@ func (t *BitgetTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		"productType":  "USDT-FUTURES",
 		"marginMode":   "crossed",
 		"marginCoin":   "USDT",
-		"triggerPrice": fmt.Sprintf("%.8f", stopPrice),
+		"triggerPrice": t.formatPrice(symbol, stopPrice),
 		"triggerType":  "mark_price",
 		"side":         side,
 		"tradeSide":    "close",
@@ -817,7 +817,7 @@ func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantit
 		"productType":  "USDT-FUTURES",
 		"marginMode":   "crossed",
 		"marginCoin":   "USDT",
-		"triggerPrice": fmt.Sprintf("%.8f", takeProfitPrice),
+		"triggerPrice": t.formatPrice(symbol, takeProfitPrice),
 		"triggerType":  "mark_price",
 		"side":         side,
 		"tradeSide":    "close",
@@ -936,16 +936,24 @@ func (t *BitgetTrader) CancelStopOrders(symbol string) error {
 	return nil
 }
 
-// FormatQuantity formats quantity
+// FormatQuantity formats quantity (rounded down to volume precision)
 func (t *BitgetTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	contract, err := t.getContract(symbol)
 	if err != nil {
-		return fmt.Sprintf("%.4f", quantity), nil
+		return market.FormatQuantityStep(quantity, market.IncrementFromPlaces(4)), nil
 	}
 
 	// Format according to volume precision
-	format := fmt.Sprintf("%%.%df", contract.VolumePlace)
-	return fmt.Sprintf(format, quantity), nil
+	return market.FormatQuantityStep(quantity, market.IncrementFromPlaces(contract.VolumePlace)), nil
+}
+
+// formatPrice formats price rounded to the contract's price precision
+func (t *BitgetTrader) formatPrice(symbol string, price float64) string {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return market.Symbols.FormatPrice(symbol, price)
+	}
+	return market.FormatPriceTick(price, market.IncrementFromPlaces(contract.PricePlace))
 }
 
 // GetOrderStatus gets order status
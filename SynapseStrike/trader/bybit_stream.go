@@ -0,0 +1,219 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bybitPrivateStreamURL        = "wss://stream.bybit.com/v5/private"
+	bybitPublicStreamURL         = "wss://stream.bybit.com/v5/public/linear"
+	bybitTestnetPrivateStreamURL = "wss://stream-testnet.bybit.com/v5/private"
+	bybitTestnetPublicStreamURL  = "wss://stream-testnet.bybit.com/v5/public/linear"
+)
+
+// bybitStreamMessage private/public stream message (topic data, or op response)
+type bybitStreamMessage struct {
+	Op      string          `json:"op"`
+	Success *bool           `json:"success"`
+	RetMsg  string          `json:"ret_msg"`
+	Topic   string          `json:"topic"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// bybitStreamPosition entry of the private position topic
+type bybitStreamPosition struct {
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"` // Buy/Sell ("" = closed)
+	Size        string `json:"size"`
+	EntryPrice  string `json:"entryPrice"`
+	AvgPrice    string `json:"avgPrice"`
+	MarkPrice   string `json:"markPrice"`
+	PositionIdx int    `json:"positionIdx"` // 0 one-way, 1 hedge long, 2 hedge short
+}
+
+// StreamPositions real-time positions from the private position/execution topics
+// and mark prices from the public ticker topic of held symbols
+func (t *BybitTrader) StreamPositions(stop <-chan struct{}, updates chan<- PositionUpdate) error {
+	privateURL, publicURL := bybitPrivateStreamURL, bybitPublicStreamURL
+	if t.testnet {
+		privateURL, publicURL = bybitTestnetPrivateStreamURL, bybitTestnetPublicStreamURL
+	}
+	private, err := dialStreamConn(privateURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect private stream: %w", err)
+	}
+	defer private.Close()
+
+	expires := time.Now().Add(10 * time.Second).UnixMilli()
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(fmt.Sprintf("GET/realtime%d", expires)))
+	if err := private.writeJSON(map[string]any{"op": "auth", "args": []any{t.apiKey, expires, hex.EncodeToString(mac.Sum(nil))}}); err != nil {
+		return err
+	}
+	if err := private.writeJSON(map[string]any{"op": "subscribe", "args": []string{"position.linear", "execution.linear"}}); err != nil {
+		return err
+	}
+
+	public, err := dialStreamConn(publicURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect public stream: %w", err)
+	}
+	defer public.Close()
+
+	// Ticker subscriptions: held symbols now, new ones as positions open
+	var subMu sync.Mutex
+	subscribed := make(map[string]bool)
+	subscribeTicker := func(symbol string) error {
+		subMu.Lock()
+		defer subMu.Unlock()
+		if subscribed[symbol] {
+			return nil
+		}
+		subscribed[symbol] = true
+		return public.writeJSON(map[string]any{"op": "subscribe", "args": []string{"tickers." + symbol}})
+	}
+	if positions, err := t.GetPositions(); err == nil {
+		for _, pos := range positions {
+			if err := subscribeTicker(pos.Symbol); err != nil {
+				return err
+			}
+		}
+	}
+
+	errC := make(chan error, 2)
+	fail := func(err error) {
+		select {
+		case errC <- err:
+		default:
+		}
+	}
+
+	go func() {
+		for {
+			raw, err := private.read()
+			if err != nil {
+				fail(fmt.Errorf("private stream: %w", err))
+				return
+			}
+			var msg bybitStreamMessage
+			if json.Unmarshal(raw, &msg) != nil {
+				continue
+			}
+			if msg.Success != nil && !*msg.Success {
+				fail(fmt.Errorf("private stream %s failed: %s", msg.Op, msg.RetMsg))
+				return
+			}
+			switch msg.Topic {
+			case "position", "position.linear":
+				var positions []bybitStreamPosition
+				if json.Unmarshal(msg.Data, &positions) != nil {
+					continue
+				}
+				for _, p := range positions {
+					t.pushStreamPosition(p, updates, stop)
+					if size, _ := strconv.ParseFloat(p.Size, 64); size > 0 {
+						if err := subscribeTicker(p.Symbol); err != nil {
+							fail(err)
+							return
+						}
+					}
+				}
+			case "execution", "execution.linear":
+				var executions []struct {
+					Symbol    string `json:"symbol"`
+					ExecPrice string `json:"execPrice"`
+				}
+				if json.Unmarshal(msg.Data, &executions) != nil {
+					continue
+				}
+				for _, e := range executions {
+					if price, _ := strconv.ParseFloat(e.ExecPrice, 64); price > 0 {
+						pushPositionUpdate(updates, stop, PositionUpdate{Symbol: e.Symbol, MarkPrice: price})
+					}
+				}
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			raw, err := public.read()
+			if err != nil {
+				fail(fmt.Errorf("public stream: %w", err))
+				return
+			}
+			var msg bybitStreamMessage
+			if json.Unmarshal(raw, &msg) != nil || !strings.HasPrefix(msg.Topic, "tickers.") {
+				continue
+			}
+			var ticker struct {
+				Symbol    string `json:"symbol"`
+				MarkPrice string `json:"markPrice"` // Omitted in deltas without a mark change
+			}
+			if json.Unmarshal(msg.Data, &ticker) != nil {
+				continue
+			}
+			if price, _ := strconv.ParseFloat(ticker.MarkPrice, 64); price > 0 {
+				pushPositionUpdate(updates, stop, PositionUpdate{Symbol: ticker.Symbol, MarkPrice: price})
+			}
+		}
+	}()
+
+	logger.Infof("📡 [Bybit] Position stream connected")
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-errC:
+			return err
+		case <-ping.C:
+			// Bybit expects an application-level ping; the pong reply extends the read deadline
+			for _, conn := range []*streamConn{private, public} {
+				if err := conn.writeJSON(map[string]string{"op": "ping"}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// pushStreamPosition converts a position topic entry to snapshot updates
+func (t *BybitTrader) pushStreamPosition(p bybitStreamPosition, updates chan<- PositionUpdate, stop <-chan struct{}) {
+	size, _ := strconv.ParseFloat(p.Size, 64)
+	entryPrice, _ := strconv.ParseFloat(p.EntryPrice, 64)
+	if entryPrice <= 0 {
+		entryPrice, _ = strconv.ParseFloat(p.AvgPrice, 64)
+	}
+	markPrice, _ := strconv.ParseFloat(p.MarkPrice, 64)
+
+	var sides []string
+	switch {
+	case p.PositionIdx == 1 || strings.EqualFold(p.Side, "Buy"):
+		sides = []string{"long"}
+	case p.PositionIdx == 2 || strings.EqualFold(p.Side, "Sell"):
+		sides = []string{"short"}
+	case size == 0:
+		sides = []string{"long", "short"} // One-way mode close carries no side
+	}
+	for _, side := range sides {
+		pushPositionUpdate(updates, stop, PositionUpdate{
+			Symbol:     p.Symbol,
+			Side:       side,
+			MarkPrice:  markPrice,
+			EntryPrice: entryPrice,
+			Quantity:   size,
+			Snapshot:   true,
+		})
+	}
+}
@@ -8,9 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,6 +24,8 @@ type BybitTrader struct {
 	client    *bybit.Client
 	apiKey    string
 	secretKey string
+	testnet   bool         // api-testnet.bybit.com REST and stream-testnet websocket endpoints
+	limiter   *RateLimiter // Shared with other traders on the same API key
 
 	// Balance cache
 	cachedBalance     map[string]interface{}
@@ -31,7 +33,7 @@ type BybitTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Position cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
@@ -43,21 +45,22 @@ type BybitTrader struct {
 	cacheDuration time.Duration
 }
 
-// NewBybitTrader creates a Bybit trader
-func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
+// NewBybitTrader creates a Bybit trader (testnet: Bybit testnet, requires testnet API keys)
+func NewBybitTrader(apiKey, secretKey string, testnet bool) *BybitTrader {
 	const src = "Up000938"
 
-	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(bybit.MAINNET))
-
-	// Set HTTP transport
-	if client != nil && client.HTTPClient != nil {
-		defaultTransport := client.HTTPClient.Transport
-		if defaultTransport == nil {
-			defaultTransport = http.DefaultTransport
-		}
+	baseURL := bybit.MAINNET
+	if testnet {
+		baseURL = bybit.TESTNET
+	}
+	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(baseURL))
 
+	// Set HTTP transport (shared per-key rate limiter, then custom headers)
+	limiter := getRateLimiter("bybit", apiKey)
+	if client != nil {
+		client.HTTPClient = withRateLimiter(client.HTTPClient, limiter)
 		client.HTTPClient.Transport = &headerRoundTripper{
-			base:      defaultTransport,
+			base:      client.HTTPClient.Transport,
 			refererID: src,
 		}
 	}
@@ -66,11 +69,17 @@ func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
 		client:        client,
 		apiKey:        apiKey,
 		secretKey:     secretKey,
+		testnet:       testnet,
+		limiter:       limiter,
 		cacheDuration: 15 * time.Second,
 		qtyStepCache:  make(map[string]float64),
 	}
 
-	logger.Infof("🔵 [Bybit] Trader initialized")
+	if testnet {
+		logger.Infof("🔵 [Bybit] Trader initialized (testnet)")
+	} else {
+		logger.Infof("🔵 [Bybit] Trader initialized")
+	}
 
 	return trader
 }
@@ -86,6 +95,11 @@ func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	return h.base.RoundTrip(req)
 }
 
+// RateLimitStatus returns state of the API key's shared rate limiter
+func (t *BybitTrader) RateLimitStatus() RateLimitStatus {
+	return t.limiter.Status()
+}
+
 // GetBalance retrieves account balance
 func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 	// Check cache
@@ -162,7 +176,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions retrieves all positions
-func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *BybitTrader) GetPositions() ([]Position, error) {
 	// Check cache
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -194,7 +208,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 
 	list, _ := resultData["list"].([]interface{})
 
-	var positions []map[string]interface{}
+	var positions []Position
 
 	for _, item := range list {
 		pos, ok := item.(map[string]interface{})
@@ -241,27 +255,25 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 		// Convert to unified format (use lowercase for consistency with other exchanges)
 		// Bybit returns "Buy" for long, "Sell" for short
 		side := "long"
-		positionAmt := size
 		positionSideLower := strings.ToLower(positionSide)
 		if positionSideLower == "sell" {
 			side = "short"
-			positionAmt = -size
 		}
 
 		logger.Infof("[Bybit] GetPositions converted: symbol=%v, rawSide=%s -> side=%s", pos["symbol"], positionSide, side)
 
-		position := map[string]interface{}{
-			"symbol":           pos["symbol"],
-			"side":             side,
-			"positionAmt":      positionAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": unrealisedPnl,
-			"unrealizedPnL":    unrealisedPnl,
-			"liquidationPrice": liqPrice,
-			"leverage":         leverage,
-			"createdTime":      createdTime, // Position open time (ms)
-			"updatedTime":      updatedTime, // Position last update time (ms)
+		symbol, _ := pos["symbol"].(string)
+		position := Position{
+			Symbol:        symbol,
+			Side:          side,
+			Qty:           size,
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: unrealisedPnl,
+			LiqPrice:      liqPrice,
+			Leverage:      leverage,
+			CreatedAt:     timeFromMillis(createdTime),
+			UpdatedAt:     timeFromMillis(updatedTime),
 		}
 
 		positions = append(positions, position)
@@ -371,9 +383,8 @@ func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]int
 			return nil, err
 		}
 		for _, pos := range positions {
-			side, _ := pos["side"].(string)
-			if pos["symbol"] == symbol && strings.ToLower(side) == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -416,9 +427,8 @@ func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]in
 			return nil, err
 		}
 		for _, pos := range positions {
-			side, _ := pos["side"].(string)
-			if pos["symbol"] == symbol && strings.ToLower(side) == "short" {
-				quantity = -pos["positionAmt"].(float64) // Short position is negative
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -569,7 +579,7 @@ func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		"side":             side,
 		"orderType":        "Market",
 		"qty":              qtyStr,
-		"triggerPrice":     fmt.Sprintf("%v", stopPrice),
+		"triggerPrice":     market.Symbols.FormatPrice(symbol, stopPrice),
 		"triggerDirection": triggerDirection,
 		"triggerBy":        "LastPrice",
 		"reduceOnly":       true,
@@ -615,7 +625,7 @@ func (t *BybitTrader) SetTakeProfit(symbol string, positionSide string, quantity
 		"side":             side,
 		"orderType":        "Market",
 		"qty":              qtyStr,
-		"triggerPrice":     fmt.Sprintf("%v", takeProfitPrice),
+		"triggerPrice":     market.Symbols.FormatPrice(symbol, takeProfitPrice),
 		"triggerDirection": triggerDirection,
 		"triggerBy":        "LastPrice",
 		"reduceOnly":       true,
@@ -682,7 +692,7 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 
 	// Call public API directly to get contract information
 	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
-	resp, err := http.Get(url)
+	resp, err := t.client.HTTPClient.Get(url)
 	if err != nil {
 		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
 		return 1 // Default to integer
@@ -730,26 +740,8 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 
 // FormatQuantity formats quantity
 func (t *BybitTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	// Get qtyStep for this symbol
-	qtyStep := t.getQtyStep(symbol)
-
 	// Align quantity according to qtyStep (round down to nearest step)
-	alignedQty := math.Floor(quantity/qtyStep) * qtyStep
-
-	// Calculate required decimal places
-	decimals := 0
-	if qtyStep < 1 {
-		stepStr := strconv.FormatFloat(qtyStep, 'f', -1, 64)
-		if idx := strings.Index(stepStr, "."); idx >= 0 {
-			decimals = len(stepStr) - idx - 1
-		}
-	}
-
-	// Format
-	format := fmt.Sprintf("%%.%df", decimals)
-	formatted := fmt.Sprintf(format, alignedQty)
-
-	return formatted, nil
+	return market.FormatQuantityStep(quantity, market.IncrementFromFloat(t.getQtyStep(symbol))), nil
 }
 
 // Helper methods
@@ -937,8 +929,8 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]Clo
 	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use http.DefaultClient for the request
-	resp, err := http.DefaultClient.Do(req)
+	// Use the SDK's HTTP client so the request counts against the rate limiter
+	resp, err := t.client.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
 	}
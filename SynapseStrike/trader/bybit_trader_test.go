@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	bybit "github.com/bybit-exchange/bybit.go.api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,7 +67,7 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 	}))
 
 	// Create real Bybit trader (for interface compliance testing)
-	trader := NewBybitTrader("test_api_key", "test_secret_key")
+	trader := NewBybitTrader("test_api_key", "test_secret_key", false)
 
 	// Create base suite
 	baseSuite := NewTraderTestSuite(t, trader)
@@ -128,7 +129,7 @@ func TestNewBybitTrader(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trader := NewBybitTrader(tt.apiKey, tt.secretKey)
+			trader := NewBybitTrader(tt.apiKey, tt.secretKey, false)
 
 			if tt.wantNil {
 				assert.Nil(t, trader)
@@ -140,6 +141,14 @@ func TestNewBybitTrader(t *testing.T) {
 	}
 }
 
+func TestNewBybitTrader_Testnet(t *testing.T) {
+	assert.Equal(t, bybit.MAINNET, NewBybitTrader("test", "test", false).client.BaseURL)
+
+	trader := NewBybitTrader("test", "test", true)
+	assert.Equal(t, bybit.TESTNET, trader.client.BaseURL)
+	assert.True(t, trader.testnet)
+}
+
 // TestBybitTrader_SymbolFormat Test symbol format
 func TestBybitTrader_SymbolFormat(t *testing.T) {
 	// Bybit uses uppercase symbol format (e.g. BTCUSDT)
@@ -176,7 +185,11 @@ func TestBybitTrader_SymbolFormat(t *testing.T) {
 
 // TestBybitTrader_FormatQuantity Test quantity formatting
 func TestBybitTrader_FormatQuantity(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
+	// Seed the instrument qty steps instead of querying the Bybit API
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		trader.qtyStepCache[symbol] = 0.001
+	}
 
 	tests := []struct {
 		name     string
@@ -189,7 +202,7 @@ func TestBybitTrader_FormatQuantity(t *testing.T) {
 			name:     "BTC quantity formatting",
 			symbol:   "BTCUSDT",
 			quantity: 0.12345,
-			expected: "0.123", // Bybit defaults to 3 decimal places
+			expected: "0.123", // Rounded down to the 0.001 qty step
 			hasError: false,
 		},
 		{
@@ -335,7 +348,7 @@ func convertBybitSide(side string) string {
 // TestBybitTrader_CategoryLinear Test using only linear category
 func TestBybitTrader_CategoryLinear(t *testing.T) {
 	// Bybit trader should only use linear category (USDT perpetual contracts)
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 	assert.NotNil(t, trader)
 
 	// Verify default configuration
@@ -344,7 +357,7 @@ func TestBybitTrader_CategoryLinear(t *testing.T) {
 
 // TestBybitTrader_CacheDuration Test cache duration
 func TestBybitTrader_CacheDuration(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 
 	// Verify default cache time is 15 seconds
 	assert.Equal(t, 15*time.Second, trader.cacheDuration)
@@ -0,0 +1,596 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// CCXT Bridge Trader
+// ============================================================================
+// Trades exchanges without a native trader (Kraken Futures, Gate, MEXC, ...)
+// through a user-run gateway that exposes CCXT unified methods over HTTP:
+//
+//	POST {gateway}/exchanges/{exchange_id}/{method}
+//	{"credentials": {"apiKey": "...", "secret": "...", "password": "..."}, "args": [...]}
+//
+// The response body is the JSON result of the CCXT call. Failures are returned
+// with a non-2xx status and {"error": "..."}; HTTP 429 is reported as a
+// *RateLimitError. Feature parity is reduced to market orders, positions,
+// balances, leverage and SL/TP through the unified stopLossPrice/takeProfitPrice
+// order params (where the exchange supports them). Closed PnL history is not
+// available: externally closed positions are reconciled from position snapshots.
+// Amounts are sent in contracts (quantity / contractSize) and market precision
+// is read in CCXT's TICK_SIZE mode.
+
+// ccxtExchangeIDPattern CCXT exchange ids are lowercase identifiers (krakenfutures, gate, mexc)
+var ccxtExchangeIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidateCCXTBridgeConfig checks the gateway URL and CCXT exchange id of a bridge account
+func ValidateCCXTBridgeConfig(gatewayURL, exchangeID string) error {
+	u, err := url.Parse(strings.TrimSpace(gatewayURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid CCXT gateway URL: %q", gatewayURL)
+	}
+	if !ccxtExchangeIDPattern.MatchString(exchangeID) {
+		return fmt.Errorf("invalid CCXT exchange id: %q", exchangeID)
+	}
+	return nil
+}
+
+// CCXTBridgeTrader implements Trader through a CCXT-compatible REST gateway
+type CCXTBridgeTrader struct {
+	gatewayURL     string
+	exchangeID     string // CCXT exchange id, e.g. "krakenfutures"
+	settleCurrency string // Collateral currency reported by GetBalance
+	credentials    map[string]string
+	client         *http.Client
+
+	marketsMu sync.RWMutex
+	markets   map[string]*ccxtMarket
+}
+
+// ccxtMarket contract specification of a CCXT market
+type ccxtMarket struct {
+	ContractSize float64 // Base asset per contract
+	AmountStep   float64 // Order amount step in contracts
+	MinAmount    float64 // Minimum order amount in contracts
+}
+
+// ccxtOrder CCXT unified order structure (fields used by the bridge)
+type ccxtOrder struct {
+	ID              string  `json:"id"`
+	Symbol          string  `json:"symbol"`
+	Type            string  `json:"type"`
+	Side            string  `json:"side"`
+	Status          string  `json:"status"` // open, closed, canceled, expired, rejected
+	Average         float64 `json:"average"`
+	Price           float64 `json:"price"`
+	Filled          float64 `json:"filled"`
+	TriggerPrice    float64 `json:"triggerPrice"`
+	StopPrice       float64 `json:"stopPrice"`
+	StopLossPrice   float64 `json:"stopLossPrice"`
+	TakeProfitPrice float64 `json:"takeProfitPrice"`
+	Fee             *struct {
+		Cost float64 `json:"cost"`
+	} `json:"fee"`
+}
+
+// ccxtPosition CCXT unified position structure (fields used by the bridge)
+type ccxtPosition struct {
+	Symbol           string  `json:"symbol"`
+	Side             string  `json:"side"` // long, short
+	Contracts        float64 `json:"contracts"`
+	ContractSize     float64 `json:"contractSize"`
+	EntryPrice       float64 `json:"entryPrice"`
+	MarkPrice        float64 `json:"markPrice"`
+	UnrealizedPnl    float64 `json:"unrealizedPnl"`
+	LiquidationPrice float64 `json:"liquidationPrice"`
+	Leverage         float64 `json:"leverage"`
+}
+
+// NewCCXTBridgeTrader creates a trader for exchangeID behind the gateway at gatewayURL
+func NewCCXTBridgeTrader(gatewayURL, exchangeID, apiKey, secret, password, settleCurrency string) (*CCXTBridgeTrader, error) {
+	if err := ValidateCCXTBridgeConfig(gatewayURL, exchangeID); err != nil {
+		return nil, err
+	}
+	if settleCurrency == "" {
+		settleCurrency = DefaultQuoteCurrency("ccxt")
+	}
+	return &CCXTBridgeTrader{
+		gatewayURL:     strings.TrimRight(strings.TrimSpace(gatewayURL), "/"),
+		exchangeID:     exchangeID,
+		settleCurrency: settleCurrency,
+		credentials: map[string]string{
+			"apiKey":   apiKey,
+			"secret":   secret,
+			"password": password,
+		},
+		client:  &http.Client{Timeout: 30 * time.Second},
+		markets: make(map[string]*ccxtMarket),
+	}, nil
+}
+
+// call invokes a CCXT unified method on the gateway and decodes its result into result (may be nil)
+func (t *CCXTBridgeTrader) call(method string, result interface{}, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"credentials": t.credentials,
+		"args":        args,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/exchanges/%s/%s", t.gatewayURL, t.exchangeID, method)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ccxt %s.%s request failed: %w", t.exchangeID, method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := time.Minute
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return &RateLimitError{Exchange: "ccxt:" + t.exchangeID, StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+	}
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		msg := string(respBody)
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			msg = apiErr.Error
+		}
+		return fmt.Errorf("ccxt %s.%s failed (status %d): %s", t.exchangeID, method, resp.StatusCode, msg)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", method, err)
+	}
+	return nil
+}
+
+// convertSymbol converts canonical symbol to CCXT unified symbol (BTCUSDT -> BTC/USDT:USDT)
+func (t *CCXTBridgeTrader) convertSymbol(symbol string) string {
+	return market.ToExchangeSymbol("ccxt", symbol)
+}
+
+// getMarket gets contract specification of a symbol (cached, contract size 1 when unavailable)
+func (t *CCXTBridgeTrader) getMarket(symbol string) *ccxtMarket {
+	t.marketsMu.RLock()
+	m, ok := t.markets[symbol]
+	t.marketsMu.RUnlock()
+	if ok {
+		return m
+	}
+
+	var info struct {
+		ContractSize float64 `json:"contractSize"`
+		Precision    struct {
+			Amount float64 `json:"amount"`
+		} `json:"precision"`
+		Limits struct {
+			Amount struct {
+				Min float64 `json:"min"`
+			} `json:"amount"`
+		} `json:"limits"`
+	}
+	if err := t.call("market", &info, t.convertSymbol(symbol)); err != nil {
+		logger.Infof("  ⚠️ [CCXT] Failed to get market info for %s, assuming 1 contract = 1 unit: %v", symbol, err)
+		return &ccxtMarket{ContractSize: 1}
+	}
+
+	m = &ccxtMarket{
+		ContractSize: info.ContractSize,
+		AmountStep:   info.Precision.Amount,
+		MinAmount:    info.Limits.Amount.Min,
+	}
+	if m.ContractSize <= 0 {
+		m.ContractSize = 1
+	}
+	t.marketsMu.Lock()
+	t.markets[symbol] = m
+	t.marketsMu.Unlock()
+	return m
+}
+
+// toContracts converts base asset quantity to an order amount in contracts, rounded down to the amount step
+func (m *ccxtMarket) toContracts(quantity float64) float64 {
+	contracts := decimal.NewFromFloat(quantity).Div(decimal.NewFromFloat(m.ContractSize))
+	return market.FloorToIncrement(contracts.InexactFloat64(), market.IncrementFromFloat(m.AmountStep)).InexactFloat64()
+}
+
+// formatAmount formats a contract amount with the decimals of the amount step
+func (m *ccxtMarket) formatAmount(contracts float64) string {
+	if m.AmountStep <= 0 {
+		return strconv.FormatFloat(contracts, 'f', -1, 64)
+	}
+	step := market.IncrementFromFloat(m.AmountStep)
+	return market.FormatIncrement(decimal.NewFromFloat(contracts), step)
+}
+
+// GetBalance gets balance of the settlement currency
+// CCXT "total" is treated as wallet balance, unrealized PnL is summed from open positions.
+func (t *CCXTBridgeTrader) GetBalance() (map[string]interface{}, error) {
+	var balance struct {
+		Total map[string]float64 `json:"total"`
+		Free  map[string]float64 `json:"free"`
+	}
+	if err := t.call("fetchBalance", &balance); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	wallet := balance.Total[t.settleCurrency]
+	unrealized := 0.0
+	if positions, err := t.GetPositions(); err == nil {
+		for _, pos := range positions {
+			pnl := pos.UnrealizedPnL
+			unrealized += pnl
+		}
+	}
+
+	return map[string]interface{}{
+		"totalWalletBalance":    wallet,
+		"availableBalance":      balance.Free[t.settleCurrency],
+		"totalUnrealizedProfit": unrealized,
+		"total_equity":          wallet + unrealized,
+	}, nil
+}
+
+// GetPositions gets all open positions
+func (t *CCXTBridgeTrader) GetPositions() ([]Position, error) {
+	var positions []ccxtPosition
+	if err := t.call("fetchPositions", &positions); err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	result := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		if pos.Contracts == 0 {
+			continue
+		}
+		contractSize := pos.ContractSize
+		if contractSize <= 0 {
+			contractSize = 1
+		}
+		side := strings.ToLower(pos.Side)
+		if side != "short" {
+			side = "long"
+		}
+		leverage := pos.Leverage
+		if leverage <= 0 {
+			leverage = 1
+		}
+		result = append(result, Position{
+			Symbol:        market.FromExchangeSymbol("ccxt", pos.Symbol),
+			Side:          side,
+			Qty:           math.Abs(pos.Contracts) * contractSize,
+			EntryPrice:    pos.EntryPrice,
+			MarkPrice:     pos.MarkPrice,
+			UnrealizedPnL: pos.UnrealizedPnl,
+			LiqPrice:      pos.LiquidationPrice,
+			Leverage:      leverage,
+		})
+	}
+	return result, nil
+}
+
+// OpenLong opens long position
+func (t *CCXTBridgeTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "buy", quantity, leverage)
+}
+
+// OpenShort opens short position
+func (t *CCXTBridgeTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "sell", quantity, leverage)
+}
+
+func (t *CCXTBridgeTrader) openPosition(symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders
+	t.CancelAllOrders(symbol)
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	m := t.getMarket(symbol)
+	contracts := m.toContracts(quantity)
+	if contracts <= 0 || contracts < m.MinAmount {
+		return nil, fmt.Errorf("order amount %.8f contracts is below the minimum of %s (%.8f)", contracts, symbol, m.MinAmount)
+	}
+
+	var order ccxtOrder
+	if err := t.call("createOrder", &order, t.convertSymbol(symbol), "market", side, contracts, nil, map[string]interface{}{}); err != nil {
+		return nil, fmt.Errorf("failed to open position: %w", err)
+	}
+
+	logger.Infof("✓ [CCXT %s] Opened %s %s: %s contracts (order %s)", t.exchangeID, side, symbol, m.formatAmount(contracts), order.ID)
+	return map[string]interface{}{
+		"orderId": order.ID,
+		"symbol":  symbol,
+		"status":  "FILLED",
+	}, nil
+}
+
+// CloseLong closes long position (quantity=0 means close all)
+func (t *CCXTBridgeTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "long", quantity)
+}
+
+// CloseShort closes short position (quantity=0 means close all)
+func (t *CCXTBridgeTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "short", quantity)
+}
+
+func (t *CCXTBridgeTrader) closePosition(symbol, positionSide string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		positions, err := t.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range positions {
+			if pos.Symbol == symbol && pos.Side == positionSide {
+				quantity = pos.Qty
+				break
+			}
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("%s position not found for %s", positionSide, symbol)
+		}
+	}
+
+	m := t.getMarket(symbol)
+	contracts := m.toContracts(quantity)
+	params := map[string]interface{}{"reduceOnly": true}
+
+	var order ccxtOrder
+	if err := t.call("createOrder", &order, t.convertSymbol(symbol), "market", closingSide(positionSide), contracts, nil, params); err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", positionSide, err)
+	}
+
+	logger.Infof("✓ [CCXT %s] Closed %s %s: %s contracts (order %s)", t.exchangeID, positionSide, symbol, m.formatAmount(contracts), order.ID)
+
+	// Cancel pending orders after closing position
+	t.CancelAllOrders(symbol)
+
+	return map[string]interface{}{
+		"orderId": order.ID,
+		"symbol":  symbol,
+		"status":  "FILLED",
+	}, nil
+}
+
+// SetLeverage sets leverage
+func (t *CCXTBridgeTrader) SetLeverage(symbol string, leverage int) error {
+	return t.call("setLeverage", nil, leverage, t.convertSymbol(symbol))
+}
+
+// SetMarginMode sets margin mode (true=cross margin, false=isolated margin)
+func (t *CCXTBridgeTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	marginMode := "isolated"
+	if isCrossMargin {
+		marginMode = "cross"
+	}
+	return t.call("setMarginMode", nil, marginMode, t.convertSymbol(symbol))
+}
+
+// GetMarketPrice gets last traded price
+func (t *CCXTBridgeTrader) GetMarketPrice(symbol string) (float64, error) {
+	var ticker struct {
+		Last  float64 `json:"last"`
+		Close float64 `json:"close"`
+	}
+	if err := t.call("fetchTicker", &ticker, t.convertSymbol(symbol)); err != nil {
+		return 0, fmt.Errorf("failed to get market price: %w", err)
+	}
+	if ticker.Last > 0 {
+		return ticker.Last, nil
+	}
+	if ticker.Close > 0 {
+		return ticker.Close, nil
+	}
+	return 0, fmt.Errorf("no price in %s ticker", symbol)
+}
+
+// SetStopLoss sets stop-loss order
+func (t *CCXTBridgeTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	_, err := t.PlaceStopLoss(symbol, positionSide, quantity, stopPrice)
+	return err
+}
+
+// SetTakeProfit sets take-profit order
+func (t *CCXTBridgeTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	_, err := t.PlaceTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+	return err
+}
+
+// PlaceStopLoss places a reduce-only stop-loss order, returns its order ID
+func (t *CCXTBridgeTrader) PlaceStopLoss(symbol string, positionSide string, quantity, stopPrice float64) (string, error) {
+	return t.placeProtectiveOrder(symbol, positionSide, quantity, "stopLossPrice", stopPrice)
+}
+
+// PlaceTakeProfit places a reduce-only take-profit order, returns its order ID
+func (t *CCXTBridgeTrader) PlaceTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	return t.placeProtectiveOrder(symbol, positionSide, quantity, "takeProfitPrice", takeProfitPrice)
+}
+
+func (t *CCXTBridgeTrader) placeProtectiveOrder(symbol, positionSide string, quantity float64, priceParam string, triggerPrice float64) (string, error) {
+	m := t.getMarket(symbol)
+	params := map[string]interface{}{
+		priceParam:   triggerPrice,
+		"reduceOnly": true,
+	}
+	var order ccxtOrder
+	err := t.call("createOrder", &order, t.convertSymbol(symbol), "market", closingSide(strings.ToLower(positionSide)), m.toContracts(quantity), nil, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to place %s order: %w", priceParam, err)
+	}
+	return order.ID, nil
+}
+
+// CancelProtectiveOrder cancels a stop-loss/take-profit order by ID
+func (t *CCXTBridgeTrader) CancelProtectiveOrder(symbol, orderID string) error {
+	ccxtSymbol := t.convertSymbol(symbol)
+	if err := t.call("cancelOrder", nil, orderID, ccxtSymbol); err != nil {
+		// Some exchanges keep trigger orders in a separate book
+		return t.call("cancelOrder", nil, orderID, ccxtSymbol, map[string]interface{}{"trigger": true})
+	}
+	return nil
+}
+
+// CancelStopLossOrders cancels only stop-loss orders
+func (t *CCXTBridgeTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelProtectiveOrders(symbol, "stop_loss")
+}
+
+// CancelTakeProfitOrders cancels only take-profit orders
+func (t *CCXTBridgeTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelProtectiveOrders(symbol, "take_profit")
+}
+
+// CancelStopOrders cancels stop-loss and take-profit orders
+func (t *CCXTBridgeTrader) CancelStopOrders(symbol string) error {
+	return t.cancelProtectiveOrders(symbol, "")
+}
+
+// CancelAllOrders cancels all pending orders of the symbol, including trigger orders
+func (t *CCXTBridgeTrader) CancelAllOrders(symbol string) error {
+	ccxtSymbol := t.convertSymbol(symbol)
+	err := t.call("cancelAllOrders", nil, ccxtSymbol)
+	t.call("cancelAllOrders", nil, ccxtSymbol, map[string]interface{}{"trigger": true})
+	return err
+}
+
+// cancelProtectiveOrders cancels open protective orders of a kind ("stop_loss", "take_profit", "" = both)
+func (t *CCXTBridgeTrader) cancelProtectiveOrders(symbol, kind string) error {
+	orders, err := t.fetchOpenOrders(symbol)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		orderKind := order.protectiveKind()
+		if orderKind == "" || (kind != "" && orderKind != kind) {
+			continue
+		}
+		if err := t.CancelProtectiveOrder(symbol, order.ID); err != nil {
+			logger.Infof("  ⚠️ [CCXT] Failed to cancel %s order %s: %v", orderKind, order.ID, err)
+		}
+	}
+	return nil
+}
+
+// fetchOpenOrders lists regular and trigger open orders of a symbol (deduplicated by ID)
+func (t *CCXTBridgeTrader) fetchOpenOrders(symbol string) ([]ccxtOrder, error) {
+	ccxtSymbol := t.convertSymbol(symbol)
+	var orders []ccxtOrder
+	if err := t.call("fetchOpenOrders", &orders, ccxtSymbol); err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+	var triggerOrders []ccxtOrder
+	if err := t.call("fetchOpenOrders", &triggerOrders, ccxtSymbol, nil, nil, map[string]interface{}{"trigger": true}); err == nil {
+		seen := make(map[string]bool, len(orders))
+		for _, order := range orders {
+			seen[order.ID] = true
+		}
+		for _, order := range triggerOrders {
+			if !seen[order.ID] {
+				orders = append(orders, order)
+			}
+		}
+	}
+	return orders, nil
+}
+
+// protectiveKind classifies an open order as "stop_loss", "take_profit" or "" (not a trigger order)
+func (o *ccxtOrder) protectiveKind() string {
+	orderType := strings.ToLower(o.Type)
+	switch {
+	case o.TakeProfitPrice > 0 || strings.Contains(orderType, "take_profit") || strings.Contains(orderType, "takeprofit"):
+		return "take_profit"
+	case o.StopLossPrice > 0 || o.TriggerPrice > 0 || o.StopPrice > 0 || strings.Contains(orderType, "stop"):
+		return "stop_loss"
+	}
+	return ""
+}
+
+// FormatQuantity formats quantity as contract amount with the market's precision
+func (t *CCXTBridgeTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	m := t.getMarket(symbol)
+	return m.formatAmount(m.toContracts(quantity)), nil
+}
+
+// GetOrderStatus gets order status
+func (t *CCXTBridgeTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	var order ccxtOrder
+	if err := t.call("fetchOrder", &order, orderID, t.convertSymbol(symbol)); err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+
+	statusMap := map[string]string{
+		"closed":   "FILLED",
+		"open":     "NEW",
+		"canceled": "CANCELED",
+		"expired":  "EXPIRED",
+		"rejected": "REJECTED",
+	}
+	status := statusMap[order.Status]
+	if status == "" {
+		status = strings.ToUpper(order.Status)
+	}
+	if status == "NEW" && order.Filled > 0 {
+		status = "PARTIALLY_FILLED"
+	}
+
+	commission := 0.0
+	if order.Fee != nil {
+		commission = order.Fee.Cost
+	}
+	return map[string]interface{}{
+		"orderId":     order.ID,
+		"symbol":      symbol,
+		"status":      status,
+		"avgPrice":    order.Average,
+		"executedQty": order.Filled * t.getMarket(symbol).ContractSize,
+		"side":        order.Side,
+		"type":        order.Type,
+		"commission":  commission,
+	}, nil
+}
+
+// GetClosedPnL is not available through the bridge (CCXT has no unified closed PnL method)
+func (t *CCXTBridgeTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return []ClosedPnLRecord{}, nil
+}
@@ -0,0 +1,97 @@
+package trader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestCCXTGateway fake gateway answering CCXT methods of "gate", records the requests it receives
+func newTestCCXTGateway(t *testing.T, calls map[string][]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := strings.TrimPrefix(r.URL.Path, "/exchanges/gate/")
+		var req struct {
+			Credentials map[string]string `json:"credentials"`
+			Args        []interface{}     `json:"args"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Credentials["apiKey"] != "key" {
+			t.Errorf("%s: missing credentials", method)
+		}
+		calls[method] = req.Args
+
+		var resp interface{}
+		switch method {
+		case "market":
+			resp = map[string]interface{}{"contractSize": 0.01, "precision": map[string]interface{}{"amount": 1}, "limits": map[string]interface{}{"amount": map[string]interface{}{"min": 1}}}
+		case "createOrder":
+			resp = map[string]interface{}{"id": "42", "status": "open"}
+		case "fetchOrder":
+			resp = map[string]interface{}{"id": "42", "status": "closed", "average": 50000, "filled": 5, "fee": map[string]interface{}{"cost": 0.12}}
+		case "fetchPositions":
+			resp = []map[string]interface{}{
+				{"symbol": "BTC/USDT:USDT", "side": "short", "contracts": 5, "contractSize": 0.01, "entryPrice": 50000, "markPrice": 49000, "unrealizedPnl": 50, "leverage": 3},
+				{"symbol": "ETH/USDT:USDT", "side": "long", "contracts": 0},
+			}
+		case "fetchTicker":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		default:
+			resp = map[string]interface{}{}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestCCXTBridgeTrader(t *testing.T) {
+	calls := make(map[string][]interface{})
+	gateway := newTestCCXTGateway(t, calls)
+	defer gateway.Close()
+
+	ct, err := NewCCXTBridgeTrader(gateway.URL, "gate", "key", "secret", "", "")
+	if err != nil {
+		t.Fatalf("failed to create trader: %v", err)
+	}
+
+	// 0.0567 BTC = 5.67 contracts of 0.01 BTC, rounded down to whole contracts
+	order, err := ct.OpenLong("BTCUSDT", 0.0567, 3)
+	if err != nil || order["orderId"] != "42" {
+		t.Fatalf("OpenLong = %v, %v", order, err)
+	}
+	if args := calls["createOrder"]; args[0] != "BTC/USDT:USDT" || args[2] != "buy" || args[3] != 5.0 {
+		t.Errorf("createOrder args = %v, want BTC/USDT:USDT market buy 5", args)
+	}
+
+	// Closing all looks up the position size and sends a reduce-only order
+	if _, err := ct.CloseShort("BTCUSDT", 0); err != nil {
+		t.Fatalf("CloseShort failed: %v", err)
+	}
+	args := calls["createOrder"]
+	params, _ := args[5].(map[string]interface{})
+	if args[2] != "buy" || args[3] != 5.0 || params["reduceOnly"] != true {
+		t.Errorf("close createOrder args = %v, want reduce-only buy 5", args)
+	}
+
+	// Positions come back typed: canonical symbol, base-asset quantity, empty positions skipped
+	positions, err := ct.GetPositions()
+	want := Position{Symbol: "BTCUSDT", Side: "short", Qty: 0.05, EntryPrice: 50000, MarkPrice: 49000, UnrealizedPnL: 50, Leverage: 3}
+	if err != nil || len(positions) != 1 || positions[0] != want {
+		t.Errorf("GetPositions = %+v, %v, want [%+v]", positions, err, want)
+	}
+
+	status, err := ct.GetOrderStatus("BTCUSDT", "42")
+	if err != nil || status["status"] != "FILLED" || status["executedQty"] != 0.05 || status["commission"] != 0.12 {
+		t.Errorf("GetOrderStatus = %v, %v", status, err)
+	}
+
+	if _, err := ct.GetMarketPrice("BTCUSDT"); !IsRateLimitError(err) || executionRetryDelay(err, 1).Seconds() != 7 {
+		t.Errorf("HTTP 429 should be a rate limit error with Retry-After, got %v", err)
+	}
+
+	if _, err := NewCCXTBridgeTrader("ftp://gateway", "gate", "", "", "", ""); err == nil {
+		t.Error("non-HTTP gateway URL should be rejected")
+	}
+}
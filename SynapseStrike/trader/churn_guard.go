@@ -0,0 +1,122 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// churnHistoryCycles symbols without a decision for this many cycles are forgotten
+const churnHistoryCycles = 50
+
+// previousDecisionContext fills ctx.PreviousDecisions for candidate and held symbols (ChurnGuard.Enabled only)
+func (at *AutoTrader) previousDecisionContext(ctx *decision.Context) {
+	if !at.engine().GetConfig().ChurnGuard.Enabled {
+		return
+	}
+	held := make(map[string]decision.PositionInfo, len(ctx.Positions))
+	for _, pos := range ctx.Positions {
+		held[pos.Symbol] = pos
+	}
+	symbols := make([]string, 0, len(ctx.CandidateStocks)+len(ctx.Positions))
+	for _, stock := range ctx.CandidateStocks {
+		symbols = append(symbols, stock.Symbol)
+	}
+	for _, pos := range ctx.Positions {
+		symbols = append(symbols, pos.Symbol)
+	}
+
+	at.churnMu.Lock()
+	defer at.churnMu.Unlock()
+	for _, symbol := range symbols {
+		last, ok := at.churnHistory[symbol]
+		if !ok {
+			continue
+		}
+		if ctx.PreviousDecisions == nil {
+			ctx.PreviousDecisions = make(map[string]*decision.PreviousDecision)
+		}
+		prev := *last
+		prev.CyclesAgo = ctx.CallCount - prev.Cycle
+		pos, isHeld := held[symbol]
+		prev.Outcome = previousDecisionOutcome(&prev, pos, isHeld)
+		ctx.PreviousDecisions[symbol] = &prev
+	}
+}
+
+// previousDecisionOutcome describes what happened to the symbol since the previous decision
+func previousDecisionOutcome(prev *decision.PreviousDecision, pos decision.PositionInfo, held bool) string {
+	isOrder := strings.HasPrefix(prev.Action, "open_") || strings.HasPrefix(prev.Action, "close_")
+	switch {
+	case isOrder && !prev.Executed:
+		return "not executed"
+	case held:
+		return fmt.Sprintf("%s position open, unrealized %+.2f%%", pos.Side, pos.UnrealizedPnLPct)
+	case strings.HasPrefix(prev.Action, "open_") || prev.Action == "hold":
+		return "position since closed"
+	case strings.HasPrefix(prev.Action, "close_"):
+		return "closed, no position"
+	}
+	return "no position"
+}
+
+// applyChurnGuard flags or suppresses direction flips on recently opened symbols and logs them
+func (at *AutoTrader) applyChurnGuard(ctx *decision.Context, fullDecision *decision.FullDecision, record *store.DecisionRecord) {
+	cfg := at.engine().GetConfig().ChurnGuard
+	decisions, flags := decision.CheckChurn(fullDecision.Decisions, ctx, cfg)
+	fullDecision.Decisions = decisions
+	for _, flag := range flags {
+		record.ExecutionLog = append(record.ExecutionLog, flag.String())
+	}
+}
+
+// recordCycleDecisions remembers this cycle's decision per symbol for the next cycles' context and flip checks
+func (at *AutoTrader) recordCycleDecisions(ctx *decision.Context, decisions []decision.Decision, record *store.DecisionRecord) {
+	if !at.engine().GetConfig().ChurnGuard.Enabled {
+		return
+	}
+	executed := make(map[string]bool, len(record.Decisions))
+	for _, action := range record.Decisions {
+		if action.Success {
+			executed[action.Symbol+"_"+action.Action] = true
+		}
+	}
+
+	at.churnMu.Lock()
+	defer at.churnMu.Unlock()
+	if at.churnHistory == nil {
+		at.churnHistory = make(map[string]*decision.PreviousDecision)
+	}
+	for _, d := range decisions {
+		if d.Symbol == "" || d.Symbol == "ALL" {
+			continue
+		}
+		entry, ok := at.churnHistory[d.Symbol]
+		if !ok {
+			entry = &decision.PreviousDecision{Symbol: d.Symbol}
+			at.churnHistory[d.Symbol] = entry
+		}
+		var price, rsi float64
+		if data := ctx.MarketDataMap[d.Symbol]; data != nil {
+			price, rsi = data.CurrentPrice, data.CurrentRSI7
+		}
+		entry.Action = d.Action
+		entry.Cycle = ctx.CallCount
+		entry.Price = price
+		entry.Confidence = d.Confidence
+		entry.Executed = executed[d.Symbol+"_"+d.Action]
+		// The open stays remembered after a close, so a reversal right after it is a flip as well
+		if entry.Executed && (d.Action == "open_long" || d.Action == "open_short") {
+			entry.OpenSide = strings.TrimPrefix(d.Action, "open_")
+			entry.OpenCycle = ctx.CallCount
+			entry.OpenPrice = price
+			entry.OpenRSI = rsi
+		}
+	}
+	for symbol, entry := range at.churnHistory {
+		if ctx.CallCount-entry.Cycle > churnHistoryCycles {
+			delete(at.churnHistory, symbol)
+		}
+	}
+}
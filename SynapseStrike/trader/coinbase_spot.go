@@ -0,0 +1,426 @@
+package trader
+
+import (
+	"SynapseStrike/market"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Coinbase Advanced Trade API endpoint
+const (
+	coinbaseHost     = "api.coinbase.com"
+	coinbaseBasePath = "/api/v3/brokerage"
+)
+
+// coinbaseSpotVenue Coinbase Advanced Trade spot access for SpotTrader
+// Authenticates with a CDP API key: key name plus EC private key (PEM), each request carries a short-lived ES256 JWT.
+type coinbaseSpotVenue struct {
+	keyName    string
+	privateKey *ecdsa.PrivateKey
+	quote      string // USD or USDC
+	httpClient *http.Client
+
+	productsMu sync.Mutex
+	products   map[string]*coinbaseProduct // product ID -> metadata
+}
+
+// coinbaseProduct product metadata used for rounding
+type coinbaseProduct struct {
+	ProductID      string `json:"product_id"`
+	Price          string `json:"price"`
+	BaseIncrement  string `json:"base_increment"`
+	QuoteIncrement string `json:"quote_increment"`
+	PriceIncrement string `json:"price_increment"`
+}
+
+// coinbaseOrder order as returned by /orders/historical
+type coinbaseOrder struct {
+	OrderID            string `json:"order_id"`
+	ProductID          string `json:"product_id"`
+	Side               string `json:"side"`
+	Status             string `json:"status"`
+	OrderType          string `json:"order_type"`
+	FilledSize         string `json:"filled_size"`
+	AverageFilledPrice string `json:"average_filled_price"`
+	TotalFees          string `json:"total_fees"`
+	CreatedTime        string `json:"created_time"`
+}
+
+// NewCoinbaseSpotTrader creates Coinbase Advanced Trade spot trader
+// apiKeyName: "organizations/{org}/apiKeys/{key}", privateKeyPEM: EC private key ("\n" escapes allowed)
+func NewCoinbaseSpotTrader(apiKeyName, privateKeyPEM, quoteCurrency string) (*SpotTrader, error) {
+	pemData := strings.ReplaceAll(strings.TrimSpace(privateKeyPEM), `\n`, "\n")
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(pemData))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Coinbase API private key: %w", err)
+	}
+	quote := strings.ToUpper(strings.TrimSpace(quoteCurrency))
+	if quote != "USDC" {
+		quote = "USD"
+	}
+	return newSpotTrader(&coinbaseSpotVenue{
+		keyName:    strings.TrimSpace(apiKeyName),
+		privateKey: key,
+		quote:      quote,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		products:   make(map[string]*coinbaseProduct),
+	}), nil
+}
+
+func (v *coinbaseSpotVenue) Name() string       { return "coinbase" }
+func (v *coinbaseSpotVenue) QuoteAsset() string { return v.quote }
+
+// productID Coinbase product of a canonical symbol (BTCUSDT -> BTC-USD)
+func (v *coinbaseSpotVenue) productID(symbol string) string {
+	product := market.ToExchangeSymbol("coinbase", symbol)
+	if v.quote != "USD" {
+		product = strings.TrimSuffix(product, "-USD") + "-" + v.quote
+	}
+	return product
+}
+
+// token signs the JWT authorizing one request
+func (v *coinbaseSpotVenue) token(method, path string) (string, error) {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": v.keyName,
+		"iss": "cdp",
+		"nbf": now.Unix(),
+		"exp": now.Add(2 * time.Minute).Unix(),
+		"uri": method + " " + coinbaseHost + path,
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tok.Header["kid"] = v.keyName
+	tok.Header["nonce"] = hex.EncodeToString(nonce)
+	return tok.SignedString(v.privateKey)
+}
+
+// doRequest calls the Advanced Trade API and decodes the JSON response into out
+func (v *coinbaseSpotVenue) doRequest(method, path, query string, body, out interface{}) error {
+	fullPath := coinbaseBasePath + path
+	token, err := v.token(method, fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	url := "https://" + coinbaseHost + fullPath
+	if query != "" {
+		url += "?" + query
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("coinbase API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+func (v *coinbaseSpotVenue) Balances() (map[string]spotBalance, error) {
+	var resp struct {
+		Accounts []struct {
+			Currency         string `json:"currency"`
+			AvailableBalance struct {
+				Value string `json:"value"`
+			} `json:"available_balance"`
+			Hold struct {
+				Value string `json:"value"`
+			} `json:"hold"`
+		} `json:"accounts"`
+	}
+	if err := v.doRequest(http.MethodGet, "/accounts", "limit=250", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	result := make(map[string]spotBalance)
+	for _, a := range resp.Accounts {
+		free, _ := strconv.ParseFloat(a.AvailableBalance.Value, 64)
+		hold, _ := strconv.ParseFloat(a.Hold.Value, 64)
+		if free+hold > 0 {
+			result[strings.ToUpper(a.Currency)] = spotBalance{Free: free, Locked: hold}
+		}
+	}
+	return result, nil
+}
+
+// product fetches product metadata (increments are cached, price is always fresh)
+func (v *coinbaseSpotVenue) product(symbol string) (*coinbaseProduct, error) {
+	var p coinbaseProduct
+	if err := v.doRequest(http.MethodGet, "/products/"+v.productID(symbol), "", nil, &p); err != nil {
+		return nil, fmt.Errorf("failed to get product %s: %w", v.productID(symbol), err)
+	}
+	v.productsMu.Lock()
+	v.products[p.ProductID] = &p
+	v.productsMu.Unlock()
+	return &p, nil
+}
+
+// cachedProduct product metadata for rounding, fetched once
+func (v *coinbaseSpotVenue) cachedProduct(symbol string) (*coinbaseProduct, error) {
+	v.productsMu.Lock()
+	p, ok := v.products[v.productID(symbol)]
+	v.productsMu.Unlock()
+	if ok {
+		return p, nil
+	}
+	return v.product(symbol)
+}
+
+func (v *coinbaseSpotVenue) Price(symbol string) (float64, error) {
+	p, err := v.product(symbol)
+	if err != nil {
+		return 0, err
+	}
+	price, err := strconv.ParseFloat(p.Price, 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("price not found for %s", p.ProductID)
+	}
+	return price, nil
+}
+
+// placeOrder posts an order and returns its ID
+func (v *coinbaseSpotVenue) placeOrder(symbol, side string, configuration map[string]interface{}) (string, error) {
+	body := map[string]interface{}{
+		"client_order_id":     uuid.NewString(),
+		"product_id":          v.productID(symbol),
+		"side":                side,
+		"order_configuration": configuration,
+	}
+	var resp struct {
+		Success         bool `json:"success"`
+		SuccessResponse struct {
+			OrderID string `json:"order_id"`
+		} `json:"success_response"`
+		ErrorResponse struct {
+			Error                string `json:"error"`
+			Message              string `json:"message"`
+			PreviewFailureReason string `json:"preview_failure_reason"`
+		} `json:"error_response"`
+	}
+	if err := v.doRequest(http.MethodPost, "/orders", "", body, &resp); err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("order rejected: %s %s %s", resp.ErrorResponse.Error, resp.ErrorResponse.Message, resp.ErrorResponse.PreviewFailureReason)
+	}
+	return resp.SuccessResponse.OrderID, nil
+}
+
+func (v *coinbaseSpotVenue) getOrder(orderID string) (*coinbaseOrder, error) {
+	var resp struct {
+		Order coinbaseOrder `json:"order"`
+	}
+	if err := v.doRequest(http.MethodGet, "/orders/historical/"+orderID, "", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Order, nil
+}
+
+func (v *coinbaseSpotVenue) MarketOrder(symbol, side string, quantity float64) (*spotFill, error) {
+	baseSize, err := v.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if q, _ := strconv.ParseFloat(baseSize, 64); q <= 0 {
+		return nil, fmt.Errorf("quantity too small, rounded to 0 (original: %.8f)", quantity)
+	}
+	orderID, err := v.placeOrder(symbol, side, map[string]interface{}{
+		"market_market_ioc": map[string]string{"base_size": baseSize},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// IOC orders settle almost immediately, poll briefly for the fill report
+	fill := &spotFill{OrderID: orderID}
+	for i := 0; i < 10; i++ {
+		order, err := v.getOrder(orderID)
+		if err == nil && order.Status != "PENDING" && order.Status != "OPEN" {
+			fill.Quantity, _ = strconv.ParseFloat(order.FilledSize, 64)
+			fill.AvgPrice, _ = strconv.ParseFloat(order.AverageFilledPrice, 64)
+			fill.Fee, _ = strconv.ParseFloat(order.TotalFees, 64)
+			if fill.Quantity <= 0 {
+				return nil, fmt.Errorf("market order %s not filled (status %s)", orderID, order.Status)
+			}
+			return fill, nil
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+	// Fill report not available yet, assume the requested size at the current price
+	fill.Quantity, _ = strconv.ParseFloat(baseSize, 64)
+	fill.AvgPrice, _ = v.Price(symbol)
+	return fill, nil
+}
+
+// PlaceExitOrder places a bracket (stop + target), a stop-limit or a limit sell
+func (v *coinbaseSpotVenue) PlaceExitOrder(symbol string, quantity, stopPrice, targetPrice float64) (string, error) {
+	baseSize, err := v.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return "", err
+	}
+
+	var configuration map[string]interface{}
+	switch {
+	case stopPrice > 0 && targetPrice > 0:
+		configuration = map[string]interface{}{"trigger_bracket_gtc": map[string]string{
+			"base_size":          baseSize,
+			"limit_price":        v.formatPrice(symbol, targetPrice),
+			"stop_trigger_price": v.formatPrice(symbol, stopPrice),
+		}}
+	case stopPrice > 0:
+		configuration = map[string]interface{}{"stop_limit_stop_limit_gtc": map[string]string{
+			"base_size":      baseSize,
+			"stop_price":     v.formatPrice(symbol, stopPrice),
+			"limit_price":    v.formatPrice(symbol, stopPrice*(1-spotStopLimitSlippage)),
+			"stop_direction": "STOP_DIRECTION_STOP_DOWN",
+		}}
+	default:
+		configuration = map[string]interface{}{"limit_limit_gtc": map[string]interface{}{
+			"base_size":   baseSize,
+			"limit_price": v.formatPrice(symbol, targetPrice),
+			"post_only":   false,
+		}}
+	}
+	return v.placeOrder(symbol, "SELL", configuration)
+}
+
+// cancelOrders cancels orders by ID
+func (v *coinbaseSpotVenue) cancelOrders(orderIDs []string) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	var resp struct {
+		Results []struct {
+			Success       bool   `json:"success"`
+			FailureReason string `json:"failure_reason"`
+			OrderID       string `json:"order_id"`
+		} `json:"results"`
+	}
+	if err := v.doRequest(http.MethodPost, "/orders/batch_cancel", "", map[string][]string{"order_ids": orderIDs}, &resp); err != nil {
+		return err
+	}
+	for _, r := range resp.Results {
+		if !r.Success {
+			return fmt.Errorf("failed to cancel order %s: %s", r.OrderID, r.FailureReason)
+		}
+	}
+	return nil
+}
+
+func (v *coinbaseSpotVenue) CancelOrder(symbol, orderID string) error {
+	return v.cancelOrders([]string{orderID})
+}
+
+func (v *coinbaseSpotVenue) CancelAll(symbol string) error {
+	var resp struct {
+		Orders []coinbaseOrder `json:"orders"`
+	}
+	query := "order_status=OPEN&product_ids=" + v.productID(symbol)
+	if err := v.doRequest(http.MethodGet, "/orders/historical/batch", query, nil, &resp); err != nil {
+		return fmt.Errorf("failed to list open orders: %w", err)
+	}
+	ids := make([]string, 0, len(resp.Orders))
+	for _, o := range resp.Orders {
+		ids = append(ids, o.OrderID)
+	}
+	return v.cancelOrders(ids)
+}
+
+// roundToIncrement rounds value to an increment string ("0.00000001"), down for sizes
+func roundToIncrement(value float64, increment string, down bool) string {
+	inc, ok := market.ParseIncrement(increment)
+	if !ok {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	if down {
+		return market.FormatQuantityStep(value, inc)
+	}
+	return market.FormatPriceTick(value, inc)
+}
+
+func (v *coinbaseSpotVenue) FormatQuantity(symbol string, quantity float64) (string, error) {
+	p, err := v.cachedProduct(symbol)
+	if err != nil {
+		return "", err
+	}
+	return roundToIncrement(quantity, p.BaseIncrement, true), nil
+}
+
+// formatPrice rounds price to the product's price increment
+func (v *coinbaseSpotVenue) formatPrice(symbol string, price float64) string {
+	p, err := v.cachedProduct(symbol)
+	if err != nil {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	increment := p.PriceIncrement
+	if increment == "" {
+		increment = p.QuoteIncrement
+	}
+	return roundToIncrement(price, increment, false)
+}
+
+func (v *coinbaseSpotVenue) OrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	order, err := v.getOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	filled, _ := strconv.ParseFloat(order.FilledSize, 64)
+	avgPrice, _ := strconv.ParseFloat(order.AverageFilledPrice, 64)
+	fees, _ := strconv.ParseFloat(order.TotalFees, 64)
+
+	status := order.Status
+	switch status {
+	case "OPEN", "PENDING", "QUEUED":
+		status = "NEW"
+	case "CANCELLED", "EXPIRED", "FAILED":
+		status = "CANCELED"
+	}
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      symbol,
+		"status":      status,
+		"avgPrice":    avgPrice,
+		"executedQty": filled,
+		"side":        order.Side,
+		"type":        order.OrderType,
+		"commission":  fees,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"path/filepath"
+	"time"
+)
+
+// runContextHooks runs the trader's registered context hooks and strategy hook scripts on the built context
+// Returns one message per failed hook; failures never stop the cycle.
+func (at *AutoTrader) runContextHooks(ctx *decision.Context) []string {
+	hooks := decision.ContextHooksFor(at.id)
+	cfg := at.engine().GetConfig().ContextHooks
+	if cfg.Enabled && at.config.ContextHooksDir != "" {
+		timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+		for _, script := range cfg.Scripts {
+			// Validation rejects separators; Base keeps a hand-edited config inside the directory too
+			path := filepath.Join(at.config.ContextHooksDir, filepath.Base(script))
+			hooks = append(hooks, decision.ScriptContextHook(script, path, timeout))
+		}
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	return decision.RunAfterContextBuild(ctx, hooks)
+}
@@ -0,0 +1,58 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+)
+
+// newDecisionMemory creates decision memory for trader (nil without database)
+// Uses the AI provider's embeddings API when available, local hash embeddings otherwise.
+func newDecisionMemory(st *store.Store, traderID string, mcpClient mcp.AIClient) *decision.MemoryBank {
+	if st == nil {
+		return nil
+	}
+	var embedder mcp.Embedder
+	if e, ok := mcpClient.(mcp.Embedder); ok && mcpClient.GetProvider() != mcp.ProviderLocalFunc {
+		embedder = e
+	}
+	return decision.NewMemoryBank(st.Memory(), traderID, embedder)
+}
+
+// decisionMemoryEnabled whether decision memory is enabled in current strategy
+func (at *AutoTrader) decisionMemoryEnabled() bool {
+	return at.memory != nil && at.engine().GetConfig().Memory.Enabled
+}
+
+// rememberDecision stores executed open with its market situation for future similarity search
+func (at *AutoTrader) rememberDecision(ctx *decision.Context, d *decision.Decision) {
+	if !at.decisionMemoryEnabled() || ctx == nil {
+		return
+	}
+	var side string
+	switch d.Action {
+	case "open_long":
+		side = "LONG"
+	case "open_short":
+		side = "SHORT"
+	default:
+		return
+	}
+	situation := ctx.Situations[d.Symbol]
+	if situation == "" {
+		return
+	}
+
+	// Link memory to DB position so the outcome can be attached once it closes
+	var positionID int64
+	if pos, err := at.store.Position().GetOpenPositionBySymbol(at.id, d.Symbol, side); err == nil && pos != nil {
+		positionID = pos.ID
+	} else {
+		logger.Warnf("⚠️  [Memory] No open position record for %s %s, outcome will not be tracked", d.Symbol, side)
+	}
+
+	if err := at.memory.Record(d.Symbol, d.Action, situation, d.Reasoning, positionID); err != nil {
+		logger.Warnf("⚠️  [Memory] Failed to record decision %s %s: %v", d.Symbol, d.Action, err)
+	}
+}
@@ -0,0 +1,457 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// dYdX v4 network endpoints
+const (
+	dydxMainnetIndexerURL   = "https://indexer.dydx.trade/v4"
+	dydxMainnetValidatorURL = "https://dydx-rest.publicnode.com"
+	dydxMainnetChainID      = "dydx-mainnet-1"
+	dydxTestnetIndexerURL   = "https://indexer.v4testnet.dydx.exchange/v4"
+	dydxTestnetValidatorURL = "https://dydx-testnet-rest.publicnode.com"
+	dydxTestnetChainID      = "dydx-testnet-4"
+
+	dydxQuoteAtomicResolution = -6 // USDC quantums
+	dydxMarketCacheTTL        = 10 * time.Minute
+)
+
+// dydxMarket perpetual market parameters from the indexer
+type dydxMarket struct {
+	Ticker                    string  `json:"ticker"`
+	ClobPairID                string  `json:"clobPairId"`
+	Status                    string  `json:"status"`
+	OraclePrice               string  `json:"oraclePrice"`
+	InitialMarginFraction     string  `json:"initialMarginFraction"`
+	AtomicResolution          int     `json:"atomicResolution"`
+	QuantumConversionExponent int     `json:"quantumConversionExponent"`
+	StepBaseQuantums          float64 `json:"stepBaseQuantums"`
+	SubticksPerTick           float64 `json:"subticksPerTick"`
+	StepSize                  string  `json:"stepSize"`
+	TickSize                  string  `json:"tickSize"`
+}
+
+// toQuantums converts size in base asset to quantums (rounded down to step size)
+func (m *dydxMarket) toQuantums(size float64) uint64 {
+	raw := decimal.NewFromFloat(size).Shift(int32(-m.AtomicResolution))
+	step := decimal.NewFromFloat(m.StepBaseQuantums)
+	if !step.IsPositive() {
+		step = decimal.NewFromInt(1)
+	}
+	quantums := raw.Div(step).Floor().Mul(step)
+	if !quantums.IsPositive() {
+		return 0
+	}
+	return quantums.BigInt().Uint64()
+}
+
+// toSubticks converts USD price to subticks (rounded to tick, at least one tick)
+func (m *dydxMarket) toSubticks(price float64) uint64 {
+	exponent := m.AtomicResolution - m.QuantumConversionExponent - dydxQuoteAtomicResolution
+	raw := decimal.NewFromFloat(price).Shift(int32(exponent))
+	tick := decimal.NewFromFloat(m.SubticksPerTick)
+	if !tick.IsPositive() {
+		tick = decimal.NewFromInt(1)
+	}
+	subticks := raw.Div(tick).Round(0).Mul(tick)
+	if subticks.LessThan(tick) {
+		subticks = tick
+	}
+	return subticks.BigInt().Uint64()
+}
+
+func (m *dydxMarket) clobPairID() uint32 {
+	id, _ := strconv.ParseUint(m.ClobPairID, 10, 32)
+	return uint32(id)
+}
+
+// DydxTrader dYdX v4 perpetuals trader
+type DydxTrader struct {
+	wallet       *dydxWallet
+	subaccount   uint32
+	testnet      bool
+	indexerURL   string
+	validatorURL string
+	chainID      string
+	client       *http.Client
+
+	// Market parameter cache (ticker -> market)
+	markets        map[string]*dydxMarket
+	marketsUpdated time.Time
+	marketsMu      sync.RWMutex
+
+	txMu sync.Mutex // Serializes transaction broadcasts (account sequence)
+}
+
+// NewDydxTrader creates dYdX v4 trader
+// Parameters:
+//   - mnemonic: BIP-39 mnemonic of the dYdX wallet (12-24 words)
+//   - subaccount: subaccount number (0 = default cross-margin subaccount)
+//   - testnet: whether to use dYdX testnet
+func NewDydxTrader(mnemonic string, subaccount int, testnet bool) (*DydxTrader, error) {
+	if strings.TrimSpace(mnemonic) == "" {
+		return nil, fmt.Errorf("dYdX mnemonic is required")
+	}
+	if subaccount < 0 {
+		return nil, fmt.Errorf("invalid dYdX subaccount number: %d", subaccount)
+	}
+
+	wallet, err := newDydxWallet(mnemonic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive dYdX wallet: %w", err)
+	}
+
+	t := &DydxTrader{
+		wallet:       wallet,
+		subaccount:   uint32(subaccount),
+		testnet:      testnet,
+		indexerURL:   dydxMainnetIndexerURL,
+		validatorURL: dydxMainnetValidatorURL,
+		chainID:      dydxMainnetChainID,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		markets:      make(map[string]*dydxMarket),
+	}
+	if testnet {
+		t.indexerURL = dydxTestnetIndexerURL
+		t.validatorURL = dydxTestnetValidatorURL
+		t.chainID = dydxTestnetChainID
+	}
+
+	logger.Infof("✓ dYdX trader initialized: %s/%d (testnet=%v)", wallet.address, subaccount, testnet)
+	return t, nil
+}
+
+// Address dYdX wallet address
+func (t *DydxTrader) Address() string {
+	return t.wallet.address
+}
+
+// getJSON performs GET request and decodes JSON response
+func (t *DydxTrader) getJSON(endpoint string, out interface{}) error {
+	resp, err := t.client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// subaccountQuery address/subaccount query parameters for indexer endpoints
+func (t *DydxTrader) subaccountQuery() url.Values {
+	q := url.Values{}
+	q.Set("address", t.wallet.address)
+	q.Set("subaccountNumber", strconv.FormatUint(uint64(t.subaccount), 10))
+	return q
+}
+
+// convertSymbolToDydx converts standard symbol to dYdX ticker
+// Example: "BTCUSDT" -> "BTC-USD"
+func convertSymbolToDydx(symbol string) string {
+	return market.ToExchangeSymbol("dydx", symbol)
+}
+
+// ============================================================================
+// Markets
+// ============================================================================
+
+// loadMarkets fetches all perpetual markets from the indexer
+func (t *DydxTrader) loadMarkets() (map[string]*dydxMarket, error) {
+	var resp struct {
+		Markets map[string]*dydxMarket `json:"markets"`
+	}
+	if err := t.getJSON(t.indexerURL+"/perpetualMarkets", &resp); err != nil {
+		return nil, fmt.Errorf("failed to get markets: %w", err)
+	}
+
+	t.marketsMu.Lock()
+	t.markets = resp.Markets
+	t.marketsUpdated = time.Now()
+	t.marketsMu.Unlock()
+	return resp.Markets, nil
+}
+
+// getMarket gets market parameters of symbol (fresh=true also refreshes oracle price)
+func (t *DydxTrader) getMarket(symbol string, fresh bool) (*dydxMarket, error) {
+	ticker := convertSymbolToDydx(symbol)
+
+	if fresh {
+		var resp struct {
+			Markets map[string]*dydxMarket `json:"markets"`
+		}
+		if err := t.getJSON(t.indexerURL+"/perpetualMarkets?ticker="+url.QueryEscape(ticker), &resp); err != nil {
+			return nil, fmt.Errorf("failed to get market %s: %w", ticker, err)
+		}
+		m, ok := resp.Markets[ticker]
+		if !ok {
+			return nil, fmt.Errorf("market not found: %s", ticker)
+		}
+		t.marketsMu.Lock()
+		t.markets[ticker] = m
+		t.marketsMu.Unlock()
+		return m, nil
+	}
+
+	t.marketsMu.RLock()
+	m, ok := t.markets[ticker]
+	stale := time.Since(t.marketsUpdated) > dydxMarketCacheTTL
+	t.marketsMu.RUnlock()
+	if ok && !stale {
+		return m, nil
+	}
+
+	markets, err := t.loadMarkets()
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := markets[ticker]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("market not found: %s", ticker)
+}
+
+// getBlockHeight gets latest block height from the indexer
+func (t *DydxTrader) getBlockHeight() (uint32, error) {
+	var resp struct {
+		Height string `json:"height"`
+	}
+	if err := t.getJSON(t.indexerURL+"/height", &resp); err != nil {
+		return 0, fmt.Errorf("failed to get block height: %w", err)
+	}
+	height, err := strconv.ParseUint(resp.Height, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block height %q", resp.Height)
+	}
+	return uint32(height), nil
+}
+
+// GetMarketPrice gets oracle price
+func (t *DydxTrader) GetMarketPrice(symbol string) (float64, error) {
+	m, err := t.getMarket(symbol, true)
+	if err != nil {
+		return 0, err
+	}
+	price, _ := strconv.ParseFloat(m.OraclePrice, 64)
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price for %s: %s", m.Ticker, m.OraclePrice)
+	}
+	return price, nil
+}
+
+// FormatQuantity formats quantity to market step size
+func (t *DydxTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	m, err := t.getMarket(symbol, false)
+	if err != nil {
+		return "", err
+	}
+	step, ok := market.ParseIncrement(m.StepSize)
+	if !ok {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+	return market.FormatQuantityStep(quantity, step), nil
+}
+
+// ============================================================================
+// Account
+// ============================================================================
+
+// dydxPerpetualPosition position from the indexer
+type dydxPerpetualPosition struct {
+	Market        string `json:"market"`
+	Status        string `json:"status"`
+	Side          string `json:"side"` // LONG / SHORT
+	Size          string `json:"size"` // Signed size (negative for short)
+	EntryPrice    string `json:"entryPrice"`
+	ExitPrice     string `json:"exitPrice"`
+	RealizedPnl   string `json:"realizedPnl"`
+	UnrealizedPnl string `json:"unrealizedPnl"`
+	SumClose      string `json:"sumClose"`
+	CreatedAt     string `json:"createdAt"`
+	ClosedAt      string `json:"closedAt"`
+}
+
+// dydxSubaccount subaccount state from the indexer
+type dydxSubaccount struct {
+	Equity                 string                           `json:"equity"`
+	FreeCollateral         string                           `json:"freeCollateral"`
+	OpenPerpetualPositions map[string]dydxPerpetualPosition `json:"openPerpetualPositions"`
+}
+
+// getSubaccount gets subaccount equity and open positions
+func (t *DydxTrader) getSubaccount() (*dydxSubaccount, error) {
+	var resp struct {
+		Subaccount dydxSubaccount `json:"subaccount"`
+	}
+	endpoint := fmt.Sprintf("%s/addresses/%s/subaccountNumber/%d", t.indexerURL, t.wallet.address, t.subaccount)
+	if err := t.getJSON(endpoint, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get subaccount: %w", err)
+	}
+	return &resp.Subaccount, nil
+}
+
+// GetBalance gets account balance
+func (t *DydxTrader) GetBalance() (map[string]interface{}, error) {
+	sub, err := t.getSubaccount()
+	if err != nil {
+		return nil, err
+	}
+
+	equity, _ := strconv.ParseFloat(sub.Equity, 64)
+	freeCollateral, _ := strconv.ParseFloat(sub.FreeCollateral, 64)
+	var unrealizedPnL float64
+	for _, pos := range sub.OpenPerpetualPositions {
+		pnl, _ := strconv.ParseFloat(pos.UnrealizedPnl, 64)
+		unrealizedPnL += pnl
+	}
+
+	logger.Infof("✓ dYdX balance: equity=%.2f, free collateral=%.2f", equity, freeCollateral)
+
+	// Return in standard format compatible with auto_trader.go
+	return map[string]interface{}{
+		"totalWalletBalance":    equity - unrealizedPnL,
+		"totalUnrealizedProfit": unrealizedPnL,
+		"availableBalance":      freeCollateral,
+		"total_equity":          equity,
+	}, nil
+}
+
+// GetPositions gets all open positions
+func (t *DydxTrader) GetPositions() ([]Position, error) {
+	sub, err := t.getSubaccount()
+	if err != nil {
+		return nil, err
+	}
+	if len(sub.OpenPerpetualPositions) == 0 {
+		return []Position{}, nil
+	}
+
+	markets, err := t.loadMarkets()
+	if err != nil {
+		return nil, err
+	}
+	equity, _ := strconv.ParseFloat(sub.Equity, 64)
+
+	result := make([]Position, 0, len(sub.OpenPerpetualPositions))
+	for ticker, pos := range sub.OpenPerpetualPositions {
+		size, _ := strconv.ParseFloat(pos.Size, 64)
+		if size == 0 {
+			continue
+		}
+		entryPrice, _ := strconv.ParseFloat(pos.EntryPrice, 64)
+		unrealizedPnl, _ := strconv.ParseFloat(pos.UnrealizedPnl, 64)
+
+		markPrice := entryPrice
+		if m, ok := markets[ticker]; ok {
+			if oracle, _ := strconv.ParseFloat(m.OraclePrice, 64); oracle > 0 {
+				markPrice = oracle
+			}
+		}
+
+		// Cross margin: effective leverage = notional / subaccount equity
+		leverage := 1.0
+		if equity > 0 {
+			leverage = math.Max(1, math.Abs(size)*markPrice/equity)
+		}
+
+		side := "long"
+		if strings.EqualFold(pos.Side, "SHORT") || size < 0 {
+			side = "short"
+		}
+
+		p := Position{
+			Symbol:        market.FromExchangeSymbol("dydx", ticker),
+			Side:          side,
+			Qty:           math.Abs(size),
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: unrealizedPnl,
+			Leverage:      leverage,
+			LiqPrice:      0, // Not provided by the indexer
+		}
+		if createdAt, err := time.Parse(time.RFC3339, pos.CreatedAt); err == nil {
+			p.CreatedAt = createdAt
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// SetLeverage dYdX v4 has no per-market leverage setting (margin is shared by the subaccount)
+func (t *DydxTrader) SetLeverage(symbol string, leverage int) error {
+	logger.Infof("  ℹ dYdX uses subaccount cross margin, leverage %dx applied through position size", leverage)
+	return nil
+}
+
+// SetMarginMode dYdX v4 isolated margin requires a separate subaccount, mode is fixed per subaccount
+func (t *DydxTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		logger.Infof("  ⚠️ dYdX isolated margin requires a dedicated subaccount, using subaccount %d as configured", t.subaccount)
+	}
+	return nil
+}
+
+// GetClosedPnL gets closed positions from the indexer
+func (t *DydxTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	q := t.subaccountQuery()
+	q.Set("status", "CLOSED")
+	q.Set("limit", strconv.Itoa(limit))
+
+	var resp struct {
+		Positions []dydxPerpetualPosition `json:"positions"`
+	}
+	if err := t.getJSON(t.indexerURL+"/perpetualPositions?"+q.Encode(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+
+	var records []ClosedPnLRecord
+	for _, pos := range resp.Positions {
+		closedAt, err := time.Parse(time.RFC3339, pos.ClosedAt)
+		if err != nil || closedAt.Before(startTime) {
+			continue
+		}
+		createdAt, _ := time.Parse(time.RFC3339, pos.CreatedAt)
+		entryPrice, _ := strconv.ParseFloat(pos.EntryPrice, 64)
+		exitPrice, _ := strconv.ParseFloat(pos.ExitPrice, 64)
+		realizedPnL, _ := strconv.ParseFloat(pos.RealizedPnl, 64)
+		quantity, _ := strconv.ParseFloat(pos.SumClose, 64)
+
+		records = append(records, ClosedPnLRecord{
+			Symbol:      market.FromExchangeSymbol("dydx", pos.Market),
+			Side:        strings.ToLower(pos.Side),
+			EntryPrice:  entryPrice,
+			ExitPrice:   exitPrice,
+			Quantity:    quantity,
+			RealizedPnL: realizedPnL,
+			EntryTime:   createdAt,
+			ExitTime:    closedAt,
+			ExchangeID:  pos.Market + "_" + pos.CreatedAt,
+			CloseType:   "unknown",
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,418 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dYdX v4 order constants (dydxprotocol.clob)
+const (
+	dydxSideBuy  = 1
+	dydxSideSell = 2
+
+	dydxOrderFlagShortTerm   = 0
+	dydxOrderFlagConditional = 32
+
+	dydxTimeInForceIOC = 1
+
+	dydxConditionStopLoss   = 1
+	dydxConditionTakeProfit = 2
+
+	dydxShortTermBlocks     = 10                  // Short-term order validity (max 20 blocks)
+	dydxConditionalOrderTTL = 28 * 24 * time.Hour // SL/TP order validity
+	dydxMarketSlippage      = 0.02                // Worst price of IOC market orders vs oracle
+)
+
+// dydxIndexerOrder order from the indexer
+type dydxIndexerOrder struct {
+	ID               string `json:"id"`
+	ClientID         string `json:"clientId"`
+	ClobPairID       string `json:"clobPairId"`
+	Side             string `json:"side"`
+	Size             string `json:"size"`
+	TotalFilled      string `json:"totalFilled"`
+	Type             string `json:"type"`   // LIMIT, MARKET, STOP_MARKET, TAKE_PROFIT_MARKET, ...
+	Status           string `json:"status"` // OPEN, FILLED, CANCELED, BEST_EFFORT_CANCELED, UNTRIGGERED, ...
+	OrderFlags       string `json:"orderFlags"`
+	GoodTilBlockTime string `json:"goodTilBlockTime"`
+	Ticker           string `json:"ticker"`
+}
+
+// isOpen whether order is resting or awaiting trigger
+func (o dydxIndexerOrder) isOpen() bool {
+	switch o.Status {
+	case "OPEN", "BEST_EFFORT_OPENED", "UNTRIGGERED":
+		return true
+	}
+	return false
+}
+
+// placeMarketOrder places IOC short-term order at oracle price +/- slippage
+func (t *DydxTrader) placeMarketOrder(symbol string, side uint64, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	m, err := t.getMarket(symbol, true)
+	if err != nil {
+		return nil, err
+	}
+	price, _ := strconv.ParseFloat(m.OraclePrice, 64)
+	if price <= 0 {
+		return nil, fmt.Errorf("invalid oracle price for %s: %s", m.Ticker, m.OraclePrice)
+	}
+	worstPrice := price * (1 + dydxMarketSlippage)
+	if side == dydxSideSell {
+		worstPrice = price * (1 - dydxMarketSlippage)
+	}
+
+	height, err := t.getBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	order := dydxOrder{
+		ClientID:     rand.Uint32(),
+		OrderFlags:   dydxOrderFlagShortTerm,
+		ClobPairID:   m.clobPairID(),
+		Side:         side,
+		Quantums:     m.toQuantums(quantity),
+		Subticks:     m.toSubticks(worstPrice),
+		GoodTilBlock: height + dydxShortTermBlocks,
+		TimeInForce:  dydxTimeInForceIOC,
+		ReduceOnly:   reduceOnly,
+	}
+	if order.Quantums == 0 {
+		return nil, fmt.Errorf("quantity %.8f below %s step size %s", quantity, m.Ticker, m.StepSize)
+	}
+
+	txHash, err := t.broadcastMsg(dydxMsgPlaceOrderType, encodeDydxPlaceOrder(t.wallet.address, t.subaccount, order))
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("  📤 dYdX order submitted: %s %s qty=%.6f worst=%.4f (client ID %d, tx %s)",
+		m.Ticker, map[uint64]string{dydxSideBuy: "BUY", dydxSideSell: "SELL"}[side], quantity, worstPrice, order.ClientID, txHash)
+
+	return map[string]interface{}{
+		"orderId": strconv.FormatUint(uint64(order.ClientID), 10),
+		"symbol":  symbol,
+		"status":  "NEW",
+		"txHash":  txHash,
+	}, nil
+}
+
+// OpenLong opens a long position
+func (t *DydxTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// First cancel all pending orders for this market
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, dydxSideBuy, quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open long position: %w", err)
+	}
+	logger.Infof("✓ Long position opened successfully: %s quantity: %.4f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort opens a short position
+func (t *DydxTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// First cancel all pending orders for this market
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, dydxSideSell, quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open short position: %w", err)
+	}
+	logger.Infof("✓ Short position opened successfully: %s quantity: %.4f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong closes a long position (quantity=0 closes all)
+func (t *DydxTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "long", quantity)
+}
+
+// CloseShort closes a short position (quantity=0 closes all)
+func (t *DydxTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "short", quantity)
+}
+
+func (t *DydxTrader) closePosition(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		positions, err := t.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range positions {
+			if pos.Symbol == symbol && pos.Side == side {
+				quantity = pos.Qty
+				break
+			}
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("no %s position found for %s", side, symbol)
+		}
+	}
+
+	orderSide := uint64(dydxSideSell)
+	if side == "short" {
+		orderSide = dydxSideBuy
+	}
+	result, err := t.placeMarketOrder(symbol, orderSide, quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", side, err)
+	}
+	logger.Infof("✓ Position closed successfully: %s %s quantity: %.4f", symbol, side, quantity)
+
+	// Cancel remaining SL/TP orders for this market after closing position
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+	}
+	return result, nil
+}
+
+// placeConditionalOrder places reduce-only conditional market order (SL/TP)
+func (t *DydxTrader) placeConditionalOrder(symbol, positionSide string, quantity, triggerPrice float64, condition uint64) error {
+	m, err := t.getMarket(symbol, false)
+	if err != nil {
+		return err
+	}
+
+	// Long position is closed by selling, short position by buying
+	side := uint64(dydxSideSell)
+	worstPrice := triggerPrice * (1 - dydxMarketSlippage)
+	if strings.EqualFold(positionSide, "SHORT") {
+		side = dydxSideBuy
+		worstPrice = triggerPrice * (1 + dydxMarketSlippage)
+	}
+
+	order := dydxOrder{
+		ClientID:         rand.Uint32(),
+		OrderFlags:       dydxOrderFlagConditional,
+		ClobPairID:       m.clobPairID(),
+		Side:             side,
+		Quantums:         m.toQuantums(quantity),
+		Subticks:         m.toSubticks(worstPrice),
+		GoodTilBlockTime: uint32(time.Now().Add(dydxConditionalOrderTTL).Unix()),
+		TimeInForce:      dydxTimeInForceIOC,
+		ReduceOnly:       true,
+		ConditionType:    condition,
+		TriggerSubticks:  m.toSubticks(triggerPrice),
+	}
+	if order.Quantums == 0 {
+		return fmt.Errorf("quantity %.8f below %s step size %s", quantity, m.Ticker, m.StepSize)
+	}
+
+	_, err = t.broadcastMsg(dydxMsgPlaceOrderType, encodeDydxPlaceOrder(t.wallet.address, t.subaccount, order))
+	return err
+}
+
+// SetStopLoss sets stop loss conditional order
+func (t *DydxTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeConditionalOrder(symbol, positionSide, quantity, stopPrice, dydxConditionStopLoss); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit conditional order
+func (t *DydxTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeConditionalOrder(symbol, positionSide, quantity, takeProfitPrice, dydxConditionTakeProfit); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// getOrders gets recent orders of symbol from the indexer
+func (t *DydxTrader) getOrders(symbol string) ([]dydxIndexerOrder, error) {
+	q := t.subaccountQuery()
+	q.Set("ticker", convertSymbolToDydx(symbol))
+	q.Set("limit", "100")
+
+	var orders []dydxIndexerOrder
+	if err := t.getJSON(t.indexerURL+"/orders?"+q.Encode(), &orders); err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	return orders, nil
+}
+
+// cancelOrders cancels open orders of symbol matching filter, returns number cancelled
+func (t *DydxTrader) cancelOrders(symbol string, match func(o dydxIndexerOrder) bool) (int, error) {
+	orders, err := t.getOrders(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	var height uint32
+	cancelled := 0
+	for _, o := range orders {
+		if !o.isOpen() || !match(o) {
+			continue
+		}
+
+		clientID, _ := strconv.ParseUint(o.ClientID, 10, 32)
+		clobPairID, _ := strconv.ParseUint(o.ClobPairID, 10, 32)
+		flags, _ := strconv.ParseUint(o.OrderFlags, 10, 32)
+		cancel := dydxOrder{ClientID: uint32(clientID), OrderFlags: uint32(flags), ClobPairID: uint32(clobPairID)}
+
+		if flags == dydxOrderFlagShortTerm {
+			if height == 0 {
+				if height, err = t.getBlockHeight(); err != nil {
+					return cancelled, err
+				}
+			}
+			cancel.GoodTilBlock = height + dydxShortTermBlocks
+		} else if gtbt, err := time.Parse(time.RFC3339, o.GoodTilBlockTime); err == nil && gtbt.After(time.Now()) {
+			cancel.GoodTilBlockTime = uint32(gtbt.Unix())
+		} else {
+			cancel.GoodTilBlockTime = uint32(time.Now().Add(dydxConditionalOrderTTL).Unix())
+		}
+
+		if _, err := t.broadcastMsg(dydxMsgCancelOrderType, encodeDydxCancelOrder(t.wallet.address, t.subaccount, cancel)); err != nil {
+			logger.Infof("  ⚠ Failed to cancel order (client ID %s): %v", o.ClientID, err)
+			continue
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+func isDydxStopLossOrder(o dydxIndexerOrder) bool {
+	return strings.HasPrefix(o.Type, "STOP_")
+}
+
+func isDydxTakeProfitOrder(o dydxIndexerOrder) bool {
+	return strings.HasPrefix(o.Type, "TAKE_PROFIT")
+}
+
+// CancelStopLossOrders cancels only stop loss orders
+func (t *DydxTrader) CancelStopLossOrders(symbol string) error {
+	n, err := t.cancelOrders(symbol, isDydxStopLossOrder)
+	if err != nil {
+		return err
+	}
+	logger.Infof("  ✓ Cancelled %d stop loss orders for %s", n, symbol)
+	return nil
+}
+
+// CancelTakeProfitOrders cancels only take profit orders
+func (t *DydxTrader) CancelTakeProfitOrders(symbol string) error {
+	n, err := t.cancelOrders(symbol, isDydxTakeProfitOrder)
+	if err != nil {
+		return err
+	}
+	logger.Infof("  ✓ Cancelled %d take profit orders for %s", n, symbol)
+	return nil
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *DydxTrader) CancelStopOrders(symbol string) error {
+	n, err := t.cancelOrders(symbol, func(o dydxIndexerOrder) bool {
+		return isDydxStopLossOrder(o) || isDydxTakeProfitOrder(o)
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		logger.Infof("  ℹ No pending orders to cancel for %s", symbol)
+	} else {
+		logger.Infof("  ✓ Cancelled %d TP/SL orders for %s", n, symbol)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels all open orders for symbol
+func (t *DydxTrader) CancelAllOrders(symbol string) error {
+	if _, err := t.cancelOrders(symbol, func(dydxIndexerOrder) bool { return true }); err != nil {
+		return err
+	}
+	logger.Infof("  ✓ Cancelled all pending orders for %s", symbol)
+	return nil
+}
+
+// GetOrderStatus gets order status by client ID (as returned in orderId)
+// Short-term orders reach the indexer only once matched, unknown orders are reported as NEW
+func (t *DydxTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	result := map[string]interface{}{
+		"orderId":     orderID,
+		"status":      "NEW",
+		"avgPrice":    0.0,
+		"executedQty": 0.0,
+		"commission":  0.0,
+	}
+
+	orders, err := t.getOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var order *dydxIndexerOrder
+	for i := range orders {
+		if orders[i].ClientID == orderID {
+			order = &orders[i]
+			break
+		}
+	}
+	if order == nil {
+		return result, nil
+	}
+
+	filled, _ := strconv.ParseFloat(order.TotalFilled, 64)
+	switch {
+	case order.Status == "FILLED" || (!order.isOpen() && filled > 0):
+		result["status"] = "FILLED" // IOC partially filled then cancelled counts as filled
+	case order.isOpen():
+		result["status"] = "NEW"
+	default:
+		result["status"] = "CANCELED"
+		return result, nil
+	}
+
+	// Aggregate fills of this order for average price and fee
+	q := t.subaccountQuery()
+	q.Set("market", order.Ticker)
+	q.Set("marketType", "PERPETUAL")
+	q.Set("limit", "100")
+	var resp struct {
+		Fills []struct {
+			OrderID string `json:"orderId"`
+			Price   string `json:"price"`
+			Size    string `json:"size"`
+			Fee     string `json:"fee"`
+		} `json:"fills"`
+	}
+	if err := t.getJSON(t.indexerURL+"/fills?"+q.Encode(), &resp); err != nil {
+		logger.Infof("  ⚠ Failed to get dYdX fills: %v", err)
+		return result, nil
+	}
+
+	var qty, notional, fee float64
+	for _, f := range resp.Fills {
+		if f.OrderID != order.ID {
+			continue
+		}
+		price, _ := strconv.ParseFloat(f.Price, 64)
+		size, _ := strconv.ParseFloat(f.Size, 64)
+		fillFee, _ := strconv.ParseFloat(f.Fee, 64)
+		qty += size
+		notional += price * size
+		fee += fillFee
+	}
+	if qty > 0 {
+		result["avgPrice"] = notional / qty
+		result["executedQty"] = qty
+		result["commission"] = fee
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+func TestDydxWalletDerivation(t *testing.T) {
+	wallet, err := newDydxWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Same key as the Cosmos reference vector for m/44'/118'/0'/0/0
+	sha := sha256.Sum256(wallet.pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	cosmosAddr, _ := bech32Encode("cosmos", hasher.Sum(nil))
+	if cosmosAddr != "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4" {
+		t.Errorf("unexpected cosmos address: %s", cosmosAddr)
+	}
+	if wallet.address != "dydx19rl4cm2hmr8afy4kldpxz3fka4jguq0a4erelz" {
+		t.Errorf("unexpected dydx address: %s", wallet.address)
+	}
+
+	// Case and spacing are normalized
+	same, err := newDydxWallet("  Abandon abandon abandon abandon abandon abandon\nabandon abandon abandon abandon abandon ABOUT ")
+	if err != nil || same.address != wallet.address {
+		t.Errorf("normalized mnemonic derived %v (%v), want %s", same, err, wallet.address)
+	}
+}
+
+func TestDydxMnemonicValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  string
+	}{
+		{"too short", "abandon about", "12, 15, 18, 21 or 24 words"},
+		{"13 words", strings.Repeat("abandon ", 12) + "about", "12, 15, 18, 21 or 24 words"},
+		{"unknown word", strings.Repeat("abandon ", 11) + "abuot", "word 12"},
+		{"bad checksum", strings.Repeat("abandon ", 12), "checksum"},
+		{"swapped words", "about" + strings.Repeat(" abandon", 11), "checksum"},
+		{"24 word bad checksum", strings.Repeat("zoo ", 24), "checksum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newDydxWallet(tt.mnemonic)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newDydxWallet error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Valid 24-word mnemonic (BIP-39 reference vector)
+	if _, err := newDydxWallet(strings.Repeat("zoo ", 23) + "vote"); err != nil {
+		t.Errorf("valid 24 word mnemonic rejected: %v", err)
+	}
+}
+
+// TestDeriveBIP32Key tests key derivation against the BIP-32 reference vectors
+func TestDeriveBIP32Key(t *testing.T) {
+	tests := []struct {
+		name string
+		seed string
+		path []uint32
+		want string
+	}{
+		{"vector 1 m/0H", "000102030405060708090a0b0c0d0e0f",
+			[]uint32{0 | bip32Hardened}, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"vector 1 m/0H/1/2H/2/1000000000", "000102030405060708090a0b0c0d0e0f",
+			[]uint32{0 | bip32Hardened, 1, 2 | bip32Hardened, 2, 1000000000}, "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		{"vector 2 m/0", "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			[]uint32{0}, "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e"},
+		{"vector 2 m/0/2147483647H/1/2147483646H/2", "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			[]uint32{0, 2147483647 | bip32Hardened, 1, 2147483646 | bip32Hardened, 2}, "bb7d39bdb83ecf58f2fd82b6d918341cbef428661ef01ab97c28a4842125ac23"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, _ := hex.DecodeString(tt.seed)
+			key, err := deriveBIP32Key(seed, tt.path)
+			if err != nil {
+				t.Fatalf("deriveBIP32Key: %v", err)
+			}
+			if got := hex.EncodeToString(key); got != tt.want {
+				t.Errorf("key = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBech32Encode tests encoding against the BIP-173 reference vector
+func TestBech32Encode(t *testing.T) {
+	if got, _ := bech32Encode("a", nil); got != "a12uel5l" {
+		t.Errorf("bech32Encode(a, empty) = %s, want a12uel5l", got)
+	}
+}
+
+func TestDydxMarketConversions(t *testing.T) {
+	// BTC-USD parameters: step 0.0001 BTC, tick 1 USD
+	m := &dydxMarket{AtomicResolution: -10, QuantumConversionExponent: -9, StepBaseQuantums: 1000000, SubticksPerTick: 100000}
+
+	if got := m.toQuantums(0.01239); got != 123000000 {
+		t.Errorf("toQuantums = %d, want 123000000 (rounded down to step)", got)
+	}
+	if got := m.toSubticks(60123.4); got != 6012300000 {
+		t.Errorf("toSubticks = %d, want 6012300000", got)
+	}
+	if got := m.toSubticks(0.1); got != 100000 {
+		t.Errorf("toSubticks below one tick = %d, want 100000", got)
+	}
+}
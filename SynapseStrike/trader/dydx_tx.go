@@ -0,0 +1,409 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/go-bip39"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ============================================================================
+// dYdX v4 Wallet & Transactions
+// ============================================================================
+// dYdX v4 is a Cosmos SDK chain. The account key is derived from the BIP-39
+// mnemonic on the Cosmos path m/44'/118'/0'/0/0, orders are MsgPlaceOrder /
+// MsgCancelOrder transactions signed in SIGN_MODE_DIRECT and broadcast via a
+// validator REST endpoint. Messages are protobuf-encoded with protowire to
+// avoid depending on the Cosmos SDK.
+
+const (
+	dydxAddressPrefix = "dydx"
+	bip32Hardened     = 0x80000000
+
+	dydxMsgPlaceOrderType  = "/dydxprotocol.clob.MsgPlaceOrder"
+	dydxMsgCancelOrderType = "/dydxprotocol.clob.MsgCancelOrder"
+	cosmosPubKeyType       = "/cosmos.crypto.secp256k1.PubKey"
+	cosmosSignModeDirect   = 1
+
+	// Order placement/cancellation is gasless on dYdX v4 (no fee coins required)
+	dydxTxGasLimit = 0
+)
+
+// dydxHDPath Cosmos HD path m/44'/118'/0'/0/0 (same as Keplr / dYdX web wallet)
+var dydxHDPath = []uint32{44 | bip32Hardened, 118 | bip32Hardened, 0 | bip32Hardened, 0, 0}
+
+// dydxWallet signing key and address derived from mnemonic
+type dydxWallet struct {
+	privateKey *ecdsa.PrivateKey
+	pubKey     []byte // 33-byte compressed public key
+	address    string // dydx1... bech32 address
+}
+
+// newDydxWallet derives dYdX wallet from BIP-39 mnemonic
+// Words and checksum are validated: a mistyped word would otherwise derive a different (empty) account.
+func newDydxWallet(mnemonic string) (*dydxWallet, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("mnemonic must have 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+	for i, word := range words {
+		if _, ok := bip39.ReverseWordMap[word]; !ok {
+			return nil, fmt.Errorf("mnemonic word %d is not in the BIP-39 English word list", i+1)
+		}
+	}
+
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(words, " "), "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic checksum, check the words and their order")
+	}
+	key, err := deriveBIP32Key(seed, dydxHDPath)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := crypto.ToECDSA(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derived key: %w", err)
+	}
+	pubKey := crypto.CompressPubkey(&privateKey.PublicKey)
+
+	sha := sha256.Sum256(pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	address, err := bech32Encode(dydxAddressPrefix, hasher.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dydxWallet{privateKey: privateKey, pubKey: pubKey, address: address}, nil
+}
+
+// deriveBIP32Key derives secp256k1 private key of path from BIP-32 seed
+func deriveBIP32Key(seed []byte, path []uint32) ([]byte, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	n := crypto.S256().Params().N
+
+	for _, index := range path {
+		var data []byte
+		if index >= bip32Hardened {
+			data = append([]byte{0}, key...)
+		} else {
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parent key: %w", err)
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		child := il.Add(il, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		key = child.FillBytes(make([]byte, 32))
+		chainCode = sum[32:]
+	}
+	return key, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Encode encodes data as bech32 string with human-readable prefix
+func bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	checksumInput := append(bech32HRPExpand(hrp), values...)
+	checksumInput = append(checksumInput, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(checksumInput) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups bits of data from fromBits-wide to toBits-wide groups
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte((acc>>bits)&maxValue))
+		}
+	}
+	if pad && bits > 0 {
+		out = append(out, byte((acc<<(toBits-bits))&maxValue))
+	} else if !pad && (bits >= fromBits || (acc<<(toBits-bits))&maxValue != 0) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return out, nil
+}
+
+// ============================================================================
+// Protobuf Encoding
+// ============================================================================
+
+// dydxOrder dydxprotocol.clob.Order fields used by this trader
+type dydxOrder struct {
+	ClientID         uint32
+	OrderFlags       uint32
+	ClobPairID       uint32
+	Side             uint64 // dydxSideBuy / dydxSideSell
+	Quantums         uint64
+	Subticks         uint64
+	GoodTilBlock     uint32 // Short-term orders
+	GoodTilBlockTime uint32 // Stateful orders (unix seconds)
+	TimeInForce      uint64
+	ReduceOnly       bool
+	ConditionType    uint64
+	TriggerSubticks  uint64
+}
+
+func pbVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func pbFixed32(b []byte, num protowire.Number, v uint32) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, v)
+}
+
+func pbBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func pbString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	return pbBytes(b, num, []byte(v))
+}
+
+// encodeDydxOrderID encodes dydxprotocol.clob.OrderId
+func encodeDydxOrderID(owner string, subaccount uint32, order dydxOrder) []byte {
+	var subaccountID []byte
+	subaccountID = pbString(subaccountID, 1, owner)
+	subaccountID = pbVarint(subaccountID, 2, uint64(subaccount))
+
+	var b []byte
+	b = pbBytes(b, 1, subaccountID)
+	b = pbFixed32(b, 2, order.ClientID)
+	b = pbVarint(b, 3, uint64(order.OrderFlags))
+	b = pbVarint(b, 4, uint64(order.ClobPairID))
+	return b
+}
+
+// encodeDydxPlaceOrder encodes dydxprotocol.clob.MsgPlaceOrder
+func encodeDydxPlaceOrder(owner string, subaccount uint32, order dydxOrder) []byte {
+	var o []byte
+	o = pbBytes(o, 1, encodeDydxOrderID(owner, subaccount, order))
+	o = pbVarint(o, 2, order.Side)
+	o = pbVarint(o, 3, order.Quantums)
+	o = pbVarint(o, 4, order.Subticks)
+	o = pbVarint(o, 5, uint64(order.GoodTilBlock))
+	o = pbFixed32(o, 6, order.GoodTilBlockTime)
+	o = pbVarint(o, 7, order.TimeInForce)
+	if order.ReduceOnly {
+		o = pbVarint(o, 8, 1)
+	}
+	o = pbVarint(o, 10, order.ConditionType)
+	o = pbVarint(o, 11, order.TriggerSubticks)
+
+	return pbBytes(nil, 1, o)
+}
+
+// encodeDydxCancelOrder encodes dydxprotocol.clob.MsgCancelOrder
+func encodeDydxCancelOrder(owner string, subaccount uint32, order dydxOrder) []byte {
+	var b []byte
+	b = pbBytes(b, 1, encodeDydxOrderID(owner, subaccount, order))
+	b = pbVarint(b, 2, uint64(order.GoodTilBlock))
+	b = pbFixed32(b, 3, order.GoodTilBlockTime)
+	return b
+}
+
+// signDydxTx builds signed TxRaw bytes for a single message
+func signDydxTx(wallet *dydxWallet, chainID string, accountNumber, sequence uint64, typeURL string, msg []byte) ([]byte, error) {
+	var anyMsg []byte
+	anyMsg = pbString(anyMsg, 1, typeURL)
+	anyMsg = pbBytes(anyMsg, 2, msg)
+	bodyBytes := pbBytes(nil, 1, anyMsg)
+
+	var pubKeyAny []byte
+	pubKeyAny = pbString(pubKeyAny, 1, cosmosPubKeyType)
+	pubKeyAny = pbBytes(pubKeyAny, 2, pbBytes(nil, 1, wallet.pubKey))
+	modeInfo := pbBytes(nil, 1, pbVarint(nil, 1, cosmosSignModeDirect))
+
+	var signerInfo []byte
+	signerInfo = pbBytes(signerInfo, 1, pubKeyAny)
+	signerInfo = pbBytes(signerInfo, 2, modeInfo)
+	signerInfo = pbVarint(signerInfo, 3, sequence)
+
+	var authInfoBytes []byte
+	authInfoBytes = pbBytes(authInfoBytes, 1, signerInfo)
+	authInfoBytes = pbBytes(authInfoBytes, 2, pbVarint(nil, 2, dydxTxGasLimit))
+
+	var signDoc []byte
+	signDoc = pbBytes(signDoc, 1, bodyBytes)
+	signDoc = pbBytes(signDoc, 2, authInfoBytes)
+	signDoc = pbString(signDoc, 3, chainID)
+	signDoc = pbVarint(signDoc, 4, accountNumber)
+
+	hash := sha256.Sum256(signDoc)
+	signature, err := crypto.Sign(hash[:], wallet.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	var txRaw []byte
+	txRaw = pbBytes(txRaw, 1, bodyBytes)
+	txRaw = pbBytes(txRaw, 2, authInfoBytes)
+	txRaw = pbBytes(txRaw, 3, signature[:64]) // Cosmos expects R||S without recovery byte
+	return txRaw, nil
+}
+
+// ============================================================================
+// Broadcasting
+// ============================================================================
+
+// getChainAccount gets account number and sequence from validator REST endpoint
+func (t *DydxTrader) getChainAccount() (accountNumber, sequence uint64, err error) {
+	var resp struct {
+		Account struct {
+			AccountNumber string `json:"account_number"`
+			Sequence      string `json:"sequence"`
+		} `json:"account"`
+	}
+	endpoint := fmt.Sprintf("%s/cosmos/auth/v1beta1/accounts/%s", t.validatorURL, t.wallet.address)
+	if err := t.getJSON(endpoint, &resp); err != nil {
+		return 0, 0, fmt.Errorf("failed to get chain account (has %s been funded?): %w", t.wallet.address, err)
+	}
+	accountNumber, _ = strconv.ParseUint(resp.Account.AccountNumber, 10, 64)
+	sequence, _ = strconv.ParseUint(resp.Account.Sequence, 10, 64)
+	return accountNumber, sequence, nil
+}
+
+// broadcastMsg signs and broadcasts a single message transaction, returns tx hash
+func (t *DydxTrader) broadcastMsg(typeURL string, msg []byte) (string, error) {
+	// Serialize broadcasts so stateful orders use consecutive sequence numbers
+	t.txMu.Lock()
+	defer t.txMu.Unlock()
+
+	accountNumber, sequence, err := t.getChainAccount()
+	if err != nil {
+		return "", err
+	}
+	txBytes, err := signDydxTx(t.wallet, t.chainID, accountNumber, sequence, typeURL, msg)
+	if err != nil {
+		return "", err
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+		"mode":     "BROADCAST_MODE_SYNC",
+	})
+	resp, err := t.client.Post(t.validatorURL+"/cosmos/tx/v1beta1/txs", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("broadcast failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		TxResponse struct {
+			TxHash string `json:"txhash"`
+			Code   int    `json:"code"`
+			RawLog string `json:"raw_log"`
+		} `json:"tx_response"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse broadcast response: %w", err)
+	}
+	if result.TxResponse.Code != 0 {
+		return "", fmt.Errorf("transaction rejected (code %d): %s", result.TxResponse.Code, result.TxResponse.RawLog)
+	}
+	return result.TxResponse.TxHash, nil
+}
@@ -0,0 +1,166 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Ensemble Decision Mode
+// ============================================================================
+// When Ensemble is enabled in the strategy, the trader's own AI client and the
+// clients of Ensemble.AIModelIDs decide on the same context and their decisions
+// are merged (see decision.GetEnsembleDecision). Clients are built from the
+// user's AI model configs and rebuilt when a model config changes. The same
+// cache serves the failover model of failed batches (BatchRetry).
+
+// ensembleClient cached AI client of an ensemble member
+type ensembleClient struct {
+	name      string
+	client    mcp.AIClient
+	updatedAt time.Time
+}
+
+// newAIClientForModel creates AI client for a stored AI model config
+func newAIClientForModel(model *store.AIModel) mcp.AIClient {
+	var client mcp.AIClient
+	switch model.Provider {
+	case "deepseek":
+		client = mcp.NewDeepSeekClient()
+	case "qwen":
+		client = mcp.NewQwenClient()
+	case "openai":
+		client = mcp.NewOpenAIClient()
+	case "claude":
+		client = mcp.NewClaudeClient()
+	case "gemini":
+		client = mcp.NewGeminiClient()
+	case "grok":
+		client = mcp.NewGrokClient()
+	case "kimi":
+		client = mcp.NewKimiClient()
+	case "localai":
+		client = mcp.NewLocalAIClient()
+	case "localfunc":
+		client = mcp.NewLocalFuncClient()
+	default:
+		client = mcp.New()
+	}
+	client.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
+	return client
+}
+
+// ensembleMembers resolves ensemble members: own AI client first, then configured models
+// Models that are missing, disabled or duplicates of another member are skipped.
+func (at *AutoTrader) ensembleMembers(cfg store.EnsembleConfig) []decision.EnsembleMember {
+	members := []decision.EnsembleMember{{Name: at.aiModel, Client: at.mcpClient}}
+	if at.store == nil {
+		return members
+	}
+
+	seen := map[string]bool{at.config.AIModelID: true}
+	for _, id := range cfg.AIModelIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		cached, err := at.cachedAIClient(id)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Ensemble %v, skipping", at.name, err)
+			continue
+		}
+		members = append(members, decision.EnsembleMember{Name: cached.name, Client: cached.client})
+	}
+	return members
+}
+
+// cachedAIClient AI client of a stored AI model config, rebuilt when the config changes
+func (at *AutoTrader) cachedAIClient(id string) (*ensembleClient, error) {
+	model, err := at.store.AIModel().Get(at.userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("AI model %s not found: %w", id, err)
+	}
+	if !model.Enabled {
+		return nil, fmt.Errorf("AI model %s is disabled", model.Name)
+	}
+	if at.ensembleClients == nil {
+		at.ensembleClients = make(map[string]*ensembleClient)
+	}
+
+	cached, ok := at.ensembleClients[id]
+	if !ok || !cached.updatedAt.Equal(model.UpdatedAt) {
+		cached = &ensembleClient{name: model.Name, client: newAIClientForModel(model), updatedAt: model.UpdatedAt}
+		at.ensembleClients[id] = cached
+		logger.Infof("🤖 [%s] AI client ready: %s (%s)", at.name, model.Name, model.Provider)
+	}
+	cached.client.SetGenerationParams(at.engine().GetGenerationParams())
+	return cached, nil
+}
+
+// failoverClient AI client failed batches fail over to (nil = none configured or same as the trader's model)
+func (at *AutoTrader) failoverClient() mcp.AIClient {
+	cfg := at.engine().GetConfig()
+	if cfg == nil || at.store == nil {
+		return nil
+	}
+	id := strings.TrimSpace(cfg.BatchRetry.FailoverAIModelID)
+	if id == "" || id == at.config.AIModelID {
+		return nil
+	}
+	cached, err := at.cachedAIClient(id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Batch failover %v", at.name, err)
+		return nil
+	}
+	return cached.client
+}
+
+// repairClient AI client repairing malformed decision JSON (nil = the client that answered)
+func (at *AutoTrader) repairClient() mcp.AIClient {
+	cfg := at.engine().GetConfig()
+	if cfg == nil || at.store == nil || !cfg.DecisionParsing.RepairEnabled {
+		return nil
+	}
+	id := strings.TrimSpace(cfg.DecisionParsing.RepairAIModelID)
+	if id == "" || id == at.config.AIModelID {
+		return nil
+	}
+	cached, err := at.cachedAIClient(id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Decision repair %v", at.name, err)
+		return nil
+	}
+	return cached.client
+}
+
+// getAIDecision gets decision from the AI client, or from the ensemble when enabled
+func (at *AutoTrader) getAIDecision(ctx *decision.Context) (*decision.FullDecision, error) {
+	engine := at.engine()
+	if cfg := engine.GetConfig(); cfg != nil && cfg.Ensemble.Enabled {
+		members := at.ensembleMembers(cfg.Ensemble)
+		if len(members) > 1 {
+			return decision.GetEnsembleDecision(ctx, members, engine, "balanced", cfg.Ensemble.MergeRule)
+		}
+		logger.Warnf("⚠️ [%s] Ensemble enabled but no additional AI model available, using single model", at.name)
+	}
+	return decision.GetFullDecisionWithStrategy(ctx, at.mcpClient, engine, "balanced")
+}
+
+// formatEnsembleOutput execution log line summarizing one model's output
+func formatEnsembleOutput(out decision.EnsembleModelOutput) string {
+	if out.Error != "" {
+		return fmt.Sprintf("🎼 Ensemble %s (%s) failed: %s", out.Name, out.Provider, out.Error)
+	}
+	actions := make([]string, 0, len(out.Decisions))
+	for _, d := range out.Decisions {
+		actions = append(actions, d.Symbol+" "+d.Action)
+	}
+	return fmt.Sprintf("🎼 Ensemble %s (%s, %d ms): %s", out.Name, out.Provider, out.DurationMs, strings.Join(actions, ", "))
+}
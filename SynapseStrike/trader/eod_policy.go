@@ -0,0 +1,410 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// End-of-Day Policy & Overnight Gap Protection
+// ============================================================================
+// With RiskControl.CloseAtEOD enabled, stock traders apply the EOD policy from
+// CloseAtEODTime (or EODMinutesBeforeClose before 16:00 ET) until the close
+// (on half days the window moves forward with the 13:00 close):
+//   - flatten:    close all positions on every tick of the window (default)
+//   - reduce:     close EODReducePct of each position once, hold the rest
+//                 with a hard stop EODStopPct from price
+//   - hold-stops: hold positions with a hard stop EODStopPct from price
+// Positions held past the close are tagged in the position store with the
+// closing price and hard stop (CloseAtEOD disabled: tagged as they are). The
+// next morning's cycles (until overnightGapReviewUntil ET) get a gap analysis
+// of tagged positions in the prompt.
+
+// End-of-day policies
+const (
+	EODFlatten   = "flatten"
+	EODReduce    = "reduce"
+	EODHoldStops = "hold-stops"
+
+	defaultEODReducePct     = 50
+	defaultEODStopPct       = 2.0
+	defaultEODCloseMinutes  = 15*60 + 55 // 3:55 PM ET
+	marketCloseMinutesET    = 16 * 60
+	overnightGapReviewUntil = 12 * 60 // Gap analysis in cycles before 12:00 ET
+	closeReasonEODReduce    = "eod_reduce"
+)
+
+// eodSettings end-of-day policy from strategy config
+type eodSettings struct {
+	closeAtEOD   bool
+	policy       string
+	reducePct    float64
+	stopPct      float64
+	startMinutes int // Minutes after midnight ET when the policy window starts
+}
+
+// eodPolicy gets end-of-day policy from strategy config
+func (at *AutoTrader) eodPolicy() eodSettings {
+	engine := at.engine()
+	eod := eodSettings{
+		closeAtEOD:   true, // default: close (backward compatible)
+		policy:       EODFlatten,
+		reducePct:    defaultEODReducePct,
+		stopPct:      defaultEODStopPct,
+		startMinutes: defaultEODCloseMinutes,
+	}
+	if engine == nil {
+		return eod
+	}
+	cfg := engine.GetConfig()
+	if cfg == nil {
+		return eod
+	}
+	rc := cfg.RiskControl
+	eod.closeAtEOD = rc.CloseAtEOD
+	if rc.EODPolicy != "" {
+		eod.policy = rc.EODPolicy
+	}
+	if rc.EODReducePct > 0 {
+		eod.reducePct = rc.EODReducePct
+	}
+	if rc.EODStopPct > 0 {
+		eod.stopPct = rc.EODStopPct
+	}
+	if rc.EODMinutesBeforeClose > 0 {
+		eod.startMinutes = marketCloseMinutesET - rc.EODMinutesBeforeClose
+	} else if rc.CloseAtEODTime != "" {
+		// Parse configurable close time (HH:MM format)
+		var eodHour, eodMin int
+		fmt.Sscanf(rc.CloseAtEODTime, "%d:%d", &eodHour, &eodMin)
+		if eodHour != 0 || eodMin != 0 {
+			eod.startMinutes = eodHour*60 + eodMin
+		}
+	}
+	return eod
+}
+
+// window returns minutes to market close and whether now is inside the EOD window
+// On half days the window moves forward with the early close; no window on market holidays.
+func (eod eodSettings) window() (int, bool) {
+	return eod.windowAt(time.Now())
+}
+
+// windowAt window of the session containing t
+func (eod eodSettings) windowAt(t time.Time) (int, bool) {
+	session := market.USMarketSession(t)
+	if !session.Open {
+		return 0, false
+	}
+	now := t.In(easternTime())
+	currentMinutes := now.Hour()*60 + now.Minute()
+	start := eod.startMinutes - (marketCloseMinutesET - session.CloseMinutes)
+	return session.CloseMinutes - currentMinutes, currentMinutes >= start && currentMinutes < session.CloseMinutes
+}
+
+// easternTime US market timezone (UTC if tzdata is unavailable)
+func easternTime() *time.Location {
+	return market.USMarketTimezone()
+}
+
+// applyEODPolicy applies the end-of-day policy to all open positions
+func (at *AutoTrader) applyEODPolicy(eod eodSettings, timeToClose int) {
+	logger.Infof("🔔 [AUTO-CLOSE] Market closing in %d minutes - applying end-of-day policy: %s", timeToClose, eod.policy)
+
+	// Get all current positions
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("⚠️ [AUTO-CLOSE] Failed to get positions: %v", err)
+		return
+	}
+	if len(positions) == 0 {
+		logger.Infof("📊 [AUTO-CLOSE] No positions to close (%d min to market close)", timeToClose)
+		return
+	}
+	if eod.policy == EODFlatten {
+		logger.Infof("🔔 [AUTO-CLOSE] Found %d open positions - closing all before market close", len(positions))
+	}
+
+	for _, pos := range positions {
+		switch eod.policy {
+		case EODReduce, EODHoldStops:
+			at.holdOvernight(pos, eod)
+		default:
+			at.closeAtEOD(pos, timeToClose)
+		}
+	}
+}
+
+// closeAtEOD closes a position before market close (flatten policy)
+func (at *AutoTrader) closeAtEOD(pos Position, timeToClose int) {
+	symbol, side := pos.Symbol, pos.Side
+
+	// Calculate PnL for logging
+	entryPrice, markPrice := pos.EntryPrice, pos.MarkPrice
+	pnlPct := 0.0
+	if entryPrice > 0 && markPrice > 0 {
+		if side == "long" || side == "buy" {
+			pnlPct = ((markPrice - entryPrice) / entryPrice) * 100
+		} else {
+			pnlPct = ((entryPrice - markPrice) / entryPrice) * 100
+		}
+	}
+
+	logger.Infof("🔔 [AUTO-CLOSE] Closing %s %s at %.2f%% PnL (market closes in %d min)",
+		symbol, side, pnlPct, timeToClose)
+
+	reasoning := fmt.Sprintf("Auto-close before market close at 4:00 PM ET (closes in %d min) | PnL: %.2f%%", timeToClose, pnlPct)
+	if err := at.closePositionWithReason(symbol, side, "market_close", reasoning); err != nil {
+		logger.Infof("❌ [AUTO-CLOSE] Failed to close %s: %v", symbol, err)
+	} else {
+		logger.Infof("✅ [AUTO-CLOSE] Successfully closed %s before market close", symbol)
+	}
+}
+
+// holdOvernight reduces (reduce policy) and protects a position held over the close, once per day
+func (at *AutoTrader) holdOvernight(pos Position, eod eodSettings) {
+	symbol, quantity := pos.Symbol, pos.Qty
+	side := strings.ToLower(pos.Side)
+	if symbol == "" || quantity == 0 || !at.markEODHandled(symbol+"_"+side) {
+		return
+	}
+
+	var dbPos *store.TraderPosition
+	if at.store != nil {
+		var err error
+		if dbPos, err = at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err != nil || dbPos == nil {
+			return // Not owned by this trader
+		}
+	}
+
+	remaining := quantity
+	if eod.policy == EODReduce {
+		reasoning := fmt.Sprintf("End-of-day policy: reduce %.0f%% before market close, hold the rest overnight with a %.2f%% hard stop", eod.reducePct, eod.stopPct)
+		closeQty, err := symbolQuantity(symbol, quantity*eod.reducePct/100)
+		switch {
+		case eod.reducePct >= 100 || (err == nil && closeQty >= quantity):
+			if err := at.closePositionWithReason(symbol, side, "market_close", reasoning); err != nil {
+				logger.Infof("❌ [AUTO-CLOSE] Failed to close %s: %v", symbol, err)
+			}
+			return
+		case err != nil:
+			logger.Infof("⚠️ [AUTO-CLOSE] EOD reduce %s skipped (%v), holding full position", symbol, err)
+		default:
+			if err := at.reducePosition(symbol, side, closeQty, dbPos, reasoning); err != nil {
+				logger.Infof("❌ [AUTO-CLOSE] EOD reduce %s %s failed: %v", symbol, side, err)
+			} else {
+				remaining -= closeQty
+			}
+		}
+	}
+
+	stopPrice := at.placeOvernightStop(symbol, side, remaining, eod.stopPct, remaining != quantity)
+	at.tagOvernight(dbPos, symbol, side, stopPrice)
+}
+
+// tagOvernightHoldings tags positions carried overnight without an EOD policy (CloseAtEOD disabled)
+func (at *AutoTrader) tagOvernightHoldings() {
+	if at.store == nil {
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return
+	}
+	for _, pos := range positions {
+		symbol := pos.Symbol
+		side := strings.ToLower(pos.Side)
+		if symbol == "" || !at.markEODHandled(symbol+"_"+side) {
+			continue
+		}
+		dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+		if err != nil || dbPos == nil {
+			continue
+		}
+		_, stopLoss, _ := at.GetPositionTPSL(symbol, side)
+		at.tagOvernight(dbPos, symbol, side, stopLoss)
+	}
+}
+
+// markEODHandled records that the EOD policy ran for a position today, returns false if it already did
+func (at *AutoTrader) markEODHandled(posKey string) bool {
+	today := time.Now().In(easternTime()).Format("2006-01-02")
+	at.eodMu.Lock()
+	defer at.eodMu.Unlock()
+	if at.eodHandled == nil {
+		at.eodHandled = make(map[string]string)
+	}
+	if at.eodHandled[posKey] == today {
+		return false
+	}
+	at.eodHandled[posKey] = today
+	return true
+}
+
+// reducePosition closes part of a position and splits it off the position record
+func (at *AutoTrader) reducePosition(symbol, side string, quantity float64, dbPos *store.TraderPosition, reasoning string) error {
+	price, _ := at.trader.GetMarketPrice(symbol)
+
+	// SL/TP orders cover the full quantity: cancel them, the caller re-places them for the reduced size
+	cancelled := at.cancelProtectiveOrders(symbol, side)
+
+	var order map[string]interface{}
+	var err error
+	action := "close_" + side
+	if side == "long" {
+		order, err = at.trader.CloseLong(symbol, quantity)
+	} else {
+		order, err = at.trader.CloseShort(symbol, quantity)
+	}
+	if err != nil {
+		if cancelled && dbPos != nil {
+			at.restoreProtectiveOrders(symbol, side, dbPos.Quantity)
+		}
+		return err
+	}
+	if avgPrice, ok := order["avgPrice"].(float64); ok && avgPrice > 0 {
+		price = avgPrice
+	}
+	logger.Infof("✅ [AUTO-CLOSE] EOD reduce: closed %.4f of %s %s @ %.4f, order ID: %v", quantity, symbol, side, price, order["orderId"])
+
+	entryPrice := 0.0
+	if dbPos != nil {
+		entryPrice = dbPos.EntryPrice
+		realizedPnL := (price - entryPrice) * quantity
+		if side == "short" {
+			realizedPnL = -realizedPnL
+		}
+		fee := EstimateFee(at.exchange, quantity*price, false)
+		if err := at.store.Position().PartialClose(dbPos.ID, quantity, price, fmt.Sprintf("%v", order["orderId"]), realizedPnL, fee, closeReasonEODReduce); err != nil {
+			logger.Infof("  ⚠️ Failed to record partial close: %v", err)
+		}
+	}
+	at.saveVWAPSellDecision(symbol, side, action, closeReasonEODReduce, reasoning, price, entryPrice, quantity)
+	return nil
+}
+
+// placeOvernightStop places a hard stop stopPct from price for the overnight hold, returns the stop in effect
+// An existing tighter stop is kept; resized re-places stop and take profit for the reduced quantity.
+func (at *AutoTrader) placeOvernightStop(symbol, side string, quantity, stopPct float64, resized bool) float64 {
+	takeProfit, currentStop, exists := at.GetPositionTPSL(symbol, side)
+	price, err := at.trader.GetMarketPrice(symbol)
+	if err != nil || price <= 0 {
+		logger.Errorf("❌ [%s] EOD hard stop %s: failed to get price: %v", at.name, symbol, err)
+		return currentStop
+	}
+
+	stopPrice := price * (1 + stopPct/100)
+	if side == "long" {
+		stopPrice = price * (1 - stopPct/100)
+	}
+	if exists && currentStop > 0 && ((side == "long" && currentStop >= stopPrice) || (side == "short" && currentStop <= stopPrice)) {
+		if !resized {
+			logger.Infof("🌙 [%s] EOD hard stop %s %s: existing stop %.4f already tighter than %.4f", at.name, symbol, side, currentStop, stopPrice)
+			return currentStop
+		}
+		stopPrice = currentStop
+	}
+
+	if err := at.setStopLoss(symbol, side, quantity, stopPrice, true); err != nil {
+		logger.Errorf("❌ [%s] EOD hard stop %s %s failed: %v", at.name, symbol, side, err)
+		return currentStop
+	}
+	if resized && takeProfit > 0 {
+		if err := at.setTakeProfit(symbol, side, quantity, takeProfit, true); err != nil {
+			logger.Warnf("⚠️ [%s] EOD hard stop %s: failed to re-place take profit: %v", at.name, symbol, err)
+		}
+	}
+	at.SetPositionTPSL(symbol, side, takeProfit, stopPrice)
+	logger.Infof("🌙 [%s] EOD hard stop: %s %s %.4f held overnight, stop → %.4f (%.2f%% from %.4f)",
+		at.name, symbol, side, quantity, stopPrice, stopPct, price)
+	return stopPrice
+}
+
+// tagOvernight tags a position record as held over today's close
+func (at *AutoTrader) tagOvernight(dbPos *store.TraderPosition, symbol, side string, stopPrice float64) {
+	if at.store == nil || dbPos == nil {
+		return
+	}
+	closePrice, err := at.trader.GetMarketPrice(symbol)
+	if err != nil || closePrice <= 0 {
+		logger.Warnf("⚠️ [%s] Failed to get price for overnight tag of %s: %v", at.name, symbol, err)
+		return
+	}
+	tag := store.OvernightTag{
+		Date:       time.Now().In(easternTime()).Format("2006-01-02"),
+		ClosePrice: closePrice,
+		StopPrice:  stopPrice,
+	}
+	if err := at.store.Position().TagOvernight(dbPos.ID, tag); err != nil {
+		logger.Warnf("⚠️ [%s] %v", at.name, err)
+		return
+	}
+	logger.Infof("🌙 [%s] %s %s tagged as overnight hold (close %.4f, stop %.4f)", at.name, symbol, side, closePrice, stopPrice)
+}
+
+// overnightGap gap analysis of a position held over the last close (morning cycles only, nil otherwise)
+func (at *AutoTrader) overnightGap(symbol, side string, markPrice float64) *decision.OvernightGap {
+	if at.store == nil || !market.IsStock(symbol) {
+		return nil
+	}
+	now := time.Now().In(easternTime())
+	today := now.Format("2006-01-02")
+	if now.Hour()*60+now.Minute() >= overnightGapReviewUntil {
+		return nil
+	}
+	tag, err := at.store.Position().GetOvernightTag(at.id, symbol, side)
+	if err != nil || tag == nil || tag.Date >= today {
+		return nil
+	}
+
+	gap := &decision.OvernightGap{
+		HeldSince: tag.Date,
+		PrevClose: tag.ClosePrice,
+		Open:      at.sessionOpenPrice(symbol, now),
+		StopPrice: tag.StopPrice,
+	}
+	if gap.Open <= 0 {
+		gap.Open = markPrice
+	}
+	if tag.ClosePrice > 0 {
+		gap.GapPct = (gap.Open - tag.ClosePrice) / tag.ClosePrice * 100
+	}
+	if tag.StopPrice > 0 {
+		gap.StopGapped = (side == "long" && gap.Open <= tag.StopPrice) || (side == "short" && gap.Open >= tag.StopPrice)
+	}
+	return gap
+}
+
+// sessionOpenPrice today's 9:30 ET opening price (0 if unavailable), cached per symbol and day
+func (at *AutoTrader) sessionOpenPrice(symbol string, now time.Time) float64 {
+	key := symbol + "_" + now.Format("2006-01-02")
+	at.eodMu.Lock()
+	open, ok := at.sessionOpens[key]
+	at.eodMu.Unlock()
+	if ok {
+		return open
+	}
+
+	sessionOpen := time.Date(now.Year(), now.Month(), now.Day(), 9, 30, 0, 0, now.Location())
+	if now.Before(sessionOpen) {
+		return 0
+	}
+	klines, err := market.GetKlinesRange(symbol, "1m", sessionOpen, sessionOpen.Add(5*time.Minute))
+	if err != nil || len(klines) == 0 {
+		return 0
+	}
+	open = klines[0].Open
+
+	at.eodMu.Lock()
+	if at.sessionOpens == nil {
+		at.sessionOpens = make(map[string]float64)
+	}
+	at.sessionOpens[key] = open
+	at.eodMu.Unlock()
+	return open
+}
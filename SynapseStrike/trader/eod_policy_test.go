@@ -0,0 +1,189 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+)
+
+func TestEODPolicySettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(rc *store.RiskControlConfig)
+		wantStart     int
+		wantPolicy    string
+		wantReducePct float64
+		wantStopPct   float64
+	}{
+		{"defaults", func(rc *store.RiskControlConfig) {}, defaultEODCloseMinutes, EODFlatten, defaultEODReducePct, defaultEODStopPct},
+		{"close time", func(rc *store.RiskControlConfig) { rc.CloseAtEODTime = "15:30" }, 15*60 + 30, EODFlatten, defaultEODReducePct, defaultEODStopPct},
+		{"minutes before close win over close time", func(rc *store.RiskControlConfig) {
+			rc.CloseAtEODTime = "15:30"
+			rc.EODMinutesBeforeClose = 10
+		}, 15*60 + 50, EODFlatten, defaultEODReducePct, defaultEODStopPct},
+		{"unparsable close time keeps default", func(rc *store.RiskControlConfig) { rc.CloseAtEODTime = "late" }, defaultEODCloseMinutes, EODFlatten, defaultEODReducePct, defaultEODStopPct},
+		{"reduce settings", func(rc *store.RiskControlConfig) {
+			rc.EODPolicy = EODReduce
+			rc.EODReducePct = 25
+			rc.EODStopPct = 1.5
+		}, defaultEODCloseMinutes, EODReduce, 25, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := store.GetDefaultStrategyConfig("en")
+			cfg.RiskControl.CloseAtEODTime = ""
+			cfg.RiskControl.EODMinutesBeforeClose = 0
+			tt.mutate(&cfg.RiskControl)
+			at := &AutoTrader{strategyEngine: decision.NewStrategyEngine(&cfg)}
+
+			eod := at.eodPolicy()
+			if eod.startMinutes != tt.wantStart || eod.policy != tt.wantPolicy || eod.reducePct != tt.wantReducePct || eod.stopPct != tt.wantStopPct {
+				t.Errorf("eodPolicy = %+v, want start %d, policy %s, reduce %v%%, stop %v%%",
+					eod, tt.wantStart, tt.wantPolicy, tt.wantReducePct, tt.wantStopPct)
+			}
+		})
+	}
+}
+
+func TestEODWindow(t *testing.T) {
+	et := market.USMarketTimezone()
+	if et == time.UTC {
+		t.Skip("America/New_York timezone data unavailable")
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("Asia/Tokyo timezone data unavailable")
+	}
+	at := func(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+	standard := eodSettings{startMinutes: defaultEODCloseMinutes} // 15:55 ET
+	early := eodSettings{startMinutes: 15*60 + 30}                // 15:30 ET
+
+	tests := []struct {
+		name       string
+		eod        eodSettings
+		at         time.Time
+		wantInside bool
+		wantToGo   int // Minutes to close (checked inside the window)
+	}{
+		{"before window", standard, at(2026, 11, 25, 15, 54, et), false, 0},
+		{"window start", standard, at(2026, 11, 25, 15, 55, et), true, 5},
+		{"last minute", standard, at(2026, 11, 25, 15, 59, et), true, 1},
+		{"at close", standard, at(2026, 11, 25, 16, 0, et), false, 0},
+		{"after hours", standard, at(2026, 11, 25, 17, 0, et), false, 0},
+		{"custom close time", early, at(2026, 11, 25, 15, 30, et), true, 30},
+		{"half day moves window forward", standard, at(2026, 11, 27, 12, 55, et), true, 5},
+		{"half day before window", standard, at(2026, 11, 27, 12, 54, et), false, 0},
+		{"half day regular window is after close", standard, at(2026, 11, 27, 15, 56, et), false, 0},
+		{"half day custom close time", early, at(2026, 11, 27, 12, 30, et), true, 30},
+		{"holiday", standard, at(2026, 11, 26, 15, 56, et), false, 0},
+		{"weekend", standard, at(2026, 11, 28, 15, 56, et), false, 0},
+		{"UTC in standard time", standard, at(2026, 11, 25, 20, 56, time.UTC), true, 4},
+		{"UTC in daylight time", standard, at(2026, 7, 15, 19, 56, time.UTC), true, 4},
+		{"UTC in daylight time after close", standard, at(2026, 7, 15, 20, 56, time.UTC), false, 0},
+		{"first day of daylight time", standard, at(2026, 3, 9, 19, 57, time.UTC), true, 3},
+		// Already Thanksgiving in Tokyo, still the regular Wednesday session in New York
+		{"Tokyo clock on the previous ET session", standard, at(2026, 11, 26, 5, 56, tokyo), true, 4},
+		{"Tokyo clock on ET holiday", standard, at(2026, 11, 27, 5, 56, tokyo), false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toGo, inside := tt.eod.windowAt(tt.at)
+			if inside != tt.wantInside {
+				t.Fatalf("windowAt(%v) inside = %v, want %v", tt.at, inside, tt.wantInside)
+			}
+			if inside && toGo != tt.wantToGo {
+				t.Errorf("windowAt(%v) minutes to close = %d, want %d", tt.at, toGo, tt.wantToGo)
+			}
+		})
+	}
+}
+
+// eodTrader records the quantities closed by the EOD policy
+type eodTrader struct {
+	*shutdownTrader
+	closedQty map[string]float64
+}
+
+// recordClose adds a closed quantity (0 = the whole position)
+func (t *eodTrader) recordClose(symbol, side string, quantity float64) {
+	if quantity == 0 {
+		for _, pos := range t.positions {
+			if pos.Symbol == symbol && pos.Side == side {
+				quantity = pos.Qty
+			}
+		}
+	}
+	t.closedQty[symbol+"_"+side] += quantity
+}
+
+func (t *eodTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	t.recordClose(symbol, "long", quantity)
+	return t.shutdownTrader.CloseLong(symbol, quantity)
+}
+
+func (t *eodTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	t.recordClose(symbol, "short", quantity)
+	return t.shutdownTrader.CloseShort(symbol, quantity)
+}
+
+func TestHoldOvernight(t *testing.T) {
+	patches := gomonkey.ApplyFunc(market.Get, func(symbol string) (*market.Data, error) {
+		return &market.Data{Symbol: symbol, CurrentPrice: 100}, nil
+	})
+	defer patches.Reset()
+
+	tests := []struct {
+		name       string
+		eod        eodSettings
+		pos        Position
+		stopLoss   float64 // Existing stop (0 = none)
+		wantClosed float64 // Quantity closed (0 = full hold)
+		wantStop   float64 // Stop sent to the exchange (0 = none)
+	}{
+		{"hold-stops long", eodSettings{policy: EODHoldStops, stopPct: 2},
+			Position{Symbol: "AAPL", Side: "long", Qty: 10}, 0, 0, 98},
+		{"hold-stops short", eodSettings{policy: EODHoldStops, stopPct: 2},
+			Position{Symbol: "TSLA", Side: "short", Qty: 5}, 0, 0, 102},
+		{"hold-stops keeps tighter stop", eodSettings{policy: EODHoldStops, stopPct: 2},
+			Position{Symbol: "AAPL", Side: "long", Qty: 10}, 99, 0, 0},
+		{"reduce half", eodSettings{policy: EODReduce, reducePct: 50, stopPct: 2},
+			Position{Symbol: "AAPL", Side: "long", Qty: 10}, 0, 5, 98},
+		{"reduce keeps tighter stop for the rest", eodSettings{policy: EODReduce, reducePct: 50, stopPct: 2},
+			Position{Symbol: "AAPL", Side: "long", Qty: 10}, 99, 5, 99},
+		{"reduce everything closes", eodSettings{policy: EODReduce, reducePct: 100, stopPct: 2},
+			Position{Symbol: "AAPL", Side: "long", Qty: 10}, 0, 10, 0},
+		{"reduce below lot size holds all", eodSettings{policy: EODReduce, reducePct: 50, stopPct: 2},
+			Position{Symbol: "SOLUSDT", Side: "long", Qty: 1}, 0, 0, 98},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, exchange := newShutdownTestTrader(ShutdownLeave)
+			exchange.positions = []Position{tt.pos}
+			recorder := &eodTrader{shutdownTrader: exchange, closedQty: make(map[string]float64)}
+			at.trader = recorder
+			if tt.stopLoss > 0 {
+				at.positionTPSL[tt.pos.Symbol+"_"+tt.pos.Side] = [2]float64{0, tt.stopLoss}
+			}
+
+			at.holdOvernight(tt.pos, tt.eod)
+			if got := recorder.closedQty[tt.pos.Symbol+"_"+tt.pos.Side]; got != tt.wantClosed {
+				t.Errorf("closed %v, want %v", got, tt.wantClosed)
+			}
+			if got := exchange.stops[tt.pos.Symbol]; got != tt.wantStop {
+				t.Errorf("stop sent = %v, want %v", got, tt.wantStop)
+			}
+
+			// Once per position and day
+			at.holdOvernight(tt.pos, tt.eod)
+			if got := recorder.closedQty[tt.pos.Symbol+"_"+tt.pos.Side]; got != tt.wantClosed {
+				t.Errorf("second run closed %v in total, want %v", got, tt.wantClosed)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"sync"
+	"time"
+)
+
+// EquityCompactor Equity snapshot compaction service
+// Periodically downsamples old equity snapshots into 15-minute and hourly buckets (see store/equity_rollup.go)
+type EquityCompactor struct {
+	store    *store.Store
+	interval time.Duration
+	tiers    []store.EquityRollupTier
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewEquityCompactor Create equity snapshot compaction service
+func NewEquityCompactor(st *store.Store, interval time.Duration) *EquityCompactor {
+	if interval == 0 {
+		interval = time.Hour
+	}
+	return &EquityCompactor{
+		store:    st,
+		interval: interval,
+		tiers:    store.DefaultEquityRollupTiers,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start Start equity snapshot compaction service
+func (c *EquityCompactor) Start() {
+	c.wg.Add(1)
+	go c.run()
+	logger.Info("🗜️ Equity compactor started")
+}
+
+// Stop Stop equity snapshot compaction service
+func (c *EquityCompactor) Stop() {
+	close(c.stopCh)
+	c.wg.Wait()
+	logger.Info("🗜️ Equity compactor stopped")
+}
+
+// run Main loop
+func (c *EquityCompactor) run() {
+	defer c.wg.Done()
+
+	// Execute immediately on startup
+	c.compact(time.Now())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.compact(time.Now())
+		}
+	}
+}
+
+// compact Run one compaction pass over all traders
+func (c *EquityCompactor) compact(now time.Time) store.EquityCompactionResult {
+	result, err := c.store.Equity().Compact(now, c.tiers)
+	if err != nil {
+		logger.Warnf("⚠️ Equity compaction failed: %v", err)
+		return result
+	}
+	if result.RowsRemoved > 0 {
+		logger.Infof("🗜️ Equity compaction: %d snapshots merged into %d buckets", result.RowsRemoved, result.RowsWritten)
+	}
+	return result
+}
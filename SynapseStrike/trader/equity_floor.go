@@ -0,0 +1,147 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/notify"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Equity Floor (Walk-Away Protection)
+// ============================================================================
+// An absolute floor under the trader's virtual equity (initial balance +
+// realized + unrealized P&L): RiskControl.EquityFloorUSD and/or
+// EquityFloorPct of the initial balance, the higher one wins. Once equity falls
+// below it the trader halts: "freeze" keeps open positions with their
+// protective orders, "flatten" closes them first; resting grid level orders are
+// cancelled either way. No cycle runs until the halt is reset by hand
+// (ResetEquityFloor, POST /api/traders/:id/equity-floor/reset). The halt is
+// kept in the runtime state, so restarts do not lift it. Resetting re-arms the floor: if equity is still
+// below it, the next cycle halts again, so lower or disable the floor first.
+
+// Equity floor actions (RiskControl.EquityFloorAction)
+const (
+	EquityFloorFreeze  = "freeze"  // Stop trading, keep positions
+	EquityFloorFlatten = "flatten" // Close all positions, then stop trading
+)
+
+// EquityFloorStatus equity floor state of a trader
+type EquityFloorStatus struct {
+	FloorUSD float64    `json:"floor_usd"` // Effective floor (0 = disabled)
+	Action   string     `json:"action"`
+	Halted   bool       `json:"halted"`
+	HaltedAt *time.Time `json:"halted_at,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// equityFloor effective equity floor in USD and action of the strategy (0 = disabled)
+func (at *AutoTrader) equityFloor() (float64, string) {
+	engine := at.engine()
+	if engine == nil {
+		return 0, EquityFloorFreeze
+	}
+	rc := engine.GetRiskControlConfig()
+	floor := rc.EquityFloorUSD
+	if rc.EquityFloorPct > 0 && at.initialBalance > 0 {
+		floor = max(floor, at.initialBalance*rc.EquityFloorPct/100)
+	}
+	action := rc.EquityFloorAction
+	if action == "" {
+		action = EquityFloorFreeze
+	}
+	return floor, action
+}
+
+// equityFloorHalt halt reason, "" if trading is not halted by the equity floor
+func (at *AutoTrader) equityFloorHalt() string {
+	at.floorMu.Lock()
+	defer at.floorMu.Unlock()
+	if at.floorHaltedAt.IsZero() {
+		return ""
+	}
+	return at.floorHaltReason
+}
+
+// checkEquityFloor halts trading when equity is below the floor, returns true if the cycle must stop
+func (at *AutoTrader) checkEquityFloor(equity float64, record *store.DecisionRecord) bool {
+	floor, action := at.equityFloor()
+	if floor <= 0 || equity >= floor {
+		return false
+	}
+
+	reason := fmt.Sprintf("Equity %.2f fell below the %.2f floor (%s)", equity, floor, action)
+	at.floorMu.Lock()
+	at.floorHaltedAt = time.Now().UTC()
+	at.floorHaltReason = reason
+	at.floorMu.Unlock() // Persisted with the runtime state when the cycle ends
+
+	logger.Errorf("🧱 [%s] EQUITY FLOOR: %s, trading halted until manual reset", at.name, reason)
+	record.Success = false
+	record.ErrorMessage = "Equity floor breached: " + reason
+	record.ErrorCategory = store.ErrorCategoryRiskBlock
+	record.ExecutionLog = append(record.ExecutionLog, "🧱 "+reason+", trading halted until manual reset")
+	at.cancelGridOrders("equity floor")
+	if action == EquityFloorFlatten {
+		record.ExecutionLog = append(record.ExecutionLog, at.flattenForEquityFloor()...)
+	}
+	at.publishEvent(notify.EventCircuitBreaker, "", "🧱 Equity floor breached, trading halted",
+		reason+". Reset via POST /api/traders/"+at.id+"/equity-floor/reset", nil)
+	return true
+}
+
+// flattenForEquityFloor closes all positions of the trader, returns one log line per position
+func (at *AutoTrader) flattenForEquityFloor() []string {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Errorf("❌ [%s] Equity floor flatten: failed to get positions: %v", at.name, err)
+		return []string{fmt.Sprintf("❌ Equity floor flatten failed: %v", err)}
+	}
+	var lines []string
+	for _, pos := range positions {
+		symbol := pos.Symbol
+		side := pos.Side
+		side = strings.ToLower(side)
+		if quantity := pos.Qty; symbol == "" || quantity == 0 {
+			continue
+		}
+		if err := at.closePositionWithReason(symbol, side, "equity_floor", "Equity floor: flatten"); err != nil {
+			logger.Errorf("❌ [%s] Equity floor flatten %s %s failed: %v", at.name, symbol, side, err)
+			lines = append(lines, fmt.Sprintf("❌ Equity floor flatten %s %s failed: %v", symbol, side, err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("✓ Equity floor flatten: closed %s %s", symbol, side))
+	}
+	return lines
+}
+
+// GetEquityFloorStatus gets the equity floor and halt state
+func (at *AutoTrader) GetEquityFloorStatus() EquityFloorStatus {
+	floor, action := at.equityFloor()
+	status := EquityFloorStatus{FloorUSD: floor, Action: action}
+	at.floorMu.Lock()
+	defer at.floorMu.Unlock()
+	if !at.floorHaltedAt.IsZero() {
+		haltedAt := at.floorHaltedAt
+		status.Halted, status.HaltedAt, status.Reason = true, &haltedAt, at.floorHaltReason
+	}
+	return status
+}
+
+// ResetEquityFloor lifts an equity floor halt (manual reset), returns false if trading was not halted
+func (at *AutoTrader) ResetEquityFloor(resetBy string) bool {
+	at.floorMu.Lock()
+	if at.floorHaltedAt.IsZero() {
+		at.floorMu.Unlock()
+		return false
+	}
+	reason := at.floorHaltReason
+	at.floorHaltedAt, at.floorHaltReason = time.Time{}, ""
+	at.floorMu.Unlock()
+	at.saveRuntimeState(store.CyclePhaseIdle, nil)
+
+	logger.Infof("🧱 [%s] Equity floor halt reset by %s (was: %s)", at.name, resetBy, reason)
+	return true
+}
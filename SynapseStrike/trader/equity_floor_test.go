@@ -0,0 +1,58 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"path/filepath"
+	"testing"
+)
+
+// TestEquityFloorHalt tests that a breach halts trading across restarts until reset
+func TestEquityFloorHalt(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.EquityFloorUSD = 700
+	cfg.RiskControl.EquityFloorPct = 80
+	newTrader := func() *AutoTrader {
+		return &AutoTrader{id: "trader-1", name: "test", store: st, initialBalance: 1000,
+			strategyEngine: decision.NewStrategyEngine(&cfg)}
+	}
+	at := newTrader()
+
+	if floor, action := at.equityFloor(); floor != 800 || action != EquityFloorFreeze {
+		t.Fatalf("expected the higher floor 800 with freeze, got %.2f %s", floor, action)
+	}
+	record := &store.DecisionRecord{Success: true}
+	if at.checkEquityFloor(850, record) || !record.Success {
+		t.Fatal("expected no halt above the floor")
+	}
+	if !at.checkEquityFloor(790, record) {
+		t.Fatal("expected halt below the floor")
+	}
+	if record.Success || record.ErrorCategory != store.ErrorCategoryRiskBlock {
+		t.Errorf("expected failed risk_block record, got %+v", record)
+	}
+	at.saveRuntimeState(store.CyclePhaseIdle, nil)
+
+	// Restarted trader: the halt is restored even though equity recovered
+	at = newTrader()
+	at.restoreRuntimeState()
+	if status := at.GetEquityFloorStatus(); !status.Halted || status.Reason == "" {
+		t.Fatalf("expected halt restored, got %+v", status)
+	}
+
+	if !at.ResetEquityFloor("test") {
+		t.Fatal("expected reset to lift the halt")
+	}
+	if at.ResetEquityFloor("test") {
+		t.Error("expected second reset to report no halt")
+	}
+	at = newTrader()
+	at.restoreRuntimeState()
+	if at.equityFloorHalt() != "" {
+		t.Error("expected reset to be persisted")
+	}
+}
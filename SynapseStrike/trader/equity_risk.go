@@ -0,0 +1,68 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"time"
+)
+
+// ============================================================================
+// Equity Curve Risk Scaling
+// ============================================================================
+// Each cycle the equity snapshots of the lookback window (plus the equity of
+// this cycle) are turned into a risk multiplier by decision.ComputeEquityRisk.
+// The engine scales new position sizes with it, enforceMaxPositions applies
+// the scaled max positions count, and the state is exposed in GetStatus.
+
+// updateEquityRisk computes the equity curve risk state of this cycle into ctx.EquityRisk
+func (at *AutoTrader) updateEquityRisk(ctx *decision.Context) {
+	cfg := at.engine().GetConfig()
+	if !cfg.EquityRisk.Enabled || at.store == nil {
+		at.setEquityRisk(nil)
+		return
+	}
+
+	now := time.Now().UTC()
+	lookback := time.Duration(decision.EquityRiskLookbackHours(cfg.EquityRisk)) * time.Hour
+	snapshots, err := at.store.Equity().GetByTimeRange(at.id, now.Add(-lookback), now)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load equity curve for risk scaling: %v", at.name, err)
+		return
+	}
+	equities := make([]float64, 0, len(snapshots)+1)
+	for _, snap := range snapshots {
+		equities = append(equities, snap.TotalEquity)
+	}
+	equities = append(equities, ctx.Account.TotalEquity)
+
+	maxPositions := cfg.RiskControl.MaxPositions
+	if maxPositions <= 0 {
+		maxPositions = 3 // Same default as enforceMaxPositions
+	}
+	state := decision.ComputeEquityRisk(cfg.EquityRisk, equities, maxPositions)
+	if prev := at.setEquityRisk(state); prev == nil || prev.Reduced != state.Reduced {
+		if state.Reduced {
+			logger.Warnf("📉 [%s] Equity %.2f is %.2f%% below peak %.2f, risk scaled to ×%.2f (max positions %d)",
+				at.name, state.Equity, state.DrawdownPct, state.PeakEquity, state.Multiplier, state.MaxPositions)
+		} else if prev != nil {
+			logger.Infof("📈 [%s] Equity recovered to %.2f%% below peak, full risk restored", at.name, state.DrawdownPct)
+		}
+	}
+	ctx.EquityRisk = state
+}
+
+// setEquityRisk replaces the current equity risk state, returns the previous one
+func (at *AutoTrader) setEquityRisk(state *decision.EquityRiskState) *decision.EquityRiskState {
+	at.equityRiskMu.Lock()
+	defer at.equityRiskMu.Unlock()
+	prev := at.equityRisk
+	at.equityRisk = state
+	return prev
+}
+
+// currentEquityRisk equity risk state of the last cycle (nil when disabled)
+func (at *AutoTrader) currentEquityRisk() *decision.EquityRiskState {
+	at.equityRiskMu.Lock()
+	defer at.equityRiskMu.Unlock()
+	return at.equityRisk
+}
@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/metrics"
+	"SynapseStrike/store"
+	"errors"
+)
+
+// ============================================================================
+// Decision Error Categories
+// ============================================================================
+// Errors on decision records and actions carry a category (store.ErrorCategory*)
+// next to the message, so dashboards can count them. Errors tagged at the
+// source keep their tag. Known untagged errors (rate limits, symbol lists, trade
+// budget, AI deadline) are mapped here. Anything else that fails an action
+// comes from the exchange. Every category saved with a record is counted in
+// synapsestrike_decision_errors_total.
+
+// actionErrorCategory error category of a failed decision action
+func actionErrorCategory(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case decision.IsAITimeout(err):
+		return store.ErrorCategoryTimeout
+	case errors.Is(err, ErrSymbolBlocked), errors.Is(err, ErrTradeBudgetExceeded):
+		return store.ErrorCategoryRiskBlock
+	}
+	if category := store.ErrorCategoryOf(err); category != "" {
+		return category
+	}
+	return store.ErrorCategoryExchangeReject
+}
+
+// cycleErrorCategory error category of a cycle that could not build its trading context
+func cycleErrorCategory(err error) string {
+	if IsRateLimitError(err) {
+		return store.ErrorCategoryExchangeReject
+	}
+	if category := store.ErrorCategoryOf(err); category != "" {
+		return category
+	}
+	return store.ErrorCategoryDataMissing
+}
+
+// recordDecisionErrors counts the error categories of a saved decision record
+func (at *AutoTrader) recordDecisionErrors(record *store.DecisionRecord) {
+	if record.ErrorCategory != "" {
+		metrics.RecordDecisionError(at.id, "cycle", record.ErrorCategory)
+	}
+	for _, action := range record.Decisions {
+		if action.ErrorCategory != "" {
+			metrics.RecordDecisionError(at.id, "action", action.ErrorCategory)
+		}
+	}
+}
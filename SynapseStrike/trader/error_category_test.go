@@ -0,0 +1,38 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestActionErrorCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"symbol list", fmt.Errorf("BTCUSDT %w", ErrSymbolBlocked), store.ErrorCategoryRiskBlock},
+		{"trade budget", fmt.Errorf("%w: 5/5 opens today", ErrTradeBudgetExceeded), store.ErrorCategoryRiskBlock},
+		{"tagged risk", fmt.Errorf("open failed: %w", store.WithErrorCategory(store.ErrorCategoryRiskBlock, errors.New("❌ [RISK CONTROL] max positions"))), store.ErrorCategoryRiskBlock},
+		{"tagged data", store.WithErrorCategory(store.ErrorCategoryDataMissing, errors.New("no klines")), store.ErrorCategoryDataMissing},
+		{"rate limit", errors.New("code=-1003 too many requests"), store.ErrorCategoryExchangeReject},
+		{"exchange", errors.New("insufficient margin"), store.ErrorCategoryExchangeReject},
+	}
+	for _, tt := range tests {
+		if got := actionErrorCategory(tt.err); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCycleErrorCategory(t *testing.T) {
+	if got := cycleErrorCategory(errors.New("failed to get balance: timeout")); got != store.ErrorCategoryDataMissing {
+		t.Errorf("expected data_missing, got %q", got)
+	}
+	if got := cycleErrorCategory(errors.New("429 Too Many Requests")); got != store.ErrorCategoryExchangeReject {
+		t.Errorf("expected exchange_reject for rate limit, got %q", got)
+	}
+}
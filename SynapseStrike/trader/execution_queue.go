@@ -0,0 +1,268 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Durable Execution Queue
+// ============================================================================
+// Parsed decisions are written to the execution_queue table before anything
+// is sent to the exchange, then executed one by one in priority order (closes
+// before opens). Items are marked done/failed as they finish, so a process
+// that dies mid-batch leaves the rest pending: on restart they are replayed
+// before the first cycle unless older than one scan interval (expired: the
+// market has moved on and the next cycle decides afresh).
+// Transient failures (rate limits, connection refused before the request was
+// sent) are retried with backoff. Ambiguous failures such as timeouts are not
+// retried, the order may have reached the exchange.
+
+const (
+	executionMaxAttempts    = 3
+	executionRetryBackoff   = 2 * time.Second
+	executionRetryMaxWait   = 30 * time.Second
+	executionQueueRetention = 7 * 24 * time.Hour
+)
+
+// executeDecisions executes the sorted decisions of a cycle through the execution queue
+func (at *AutoTrader) executeDecisions(ctx *decision.Context, decisions []decision.Decision, record *store.DecisionRecord) {
+	pending := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		pending = append(pending, d.Symbol+" "+d.Action)
+	}
+	at.saveRuntimeState(store.CyclePhaseExecuting, pending)
+
+	if at.store == nil {
+		at.executeInline(ctx, decisions, record, pending)
+		return
+	}
+
+	queue := at.store.ExecutionQueue()
+	if expired, err := queue.ExpirePending(at.id, fmt.Sprintf("superseded by cycle #%d", at.callCount)); err == nil && expired > 0 {
+		logger.Infof("🗑 [%s] Expired %d queued decisions superseded by this cycle", at.name, expired)
+	}
+
+	items := make([]*store.ExecutionItem, 0, len(decisions))
+	for _, d := range decisions {
+		payload, err := json.Marshal(d)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Failed to encode decision %s %s: %v", at.name, d.Symbol, d.Action, err)
+			continue
+		}
+		items = append(items, &store.ExecutionItem{
+			TraderID:    at.id,
+			CycleNumber: at.callCount,
+			Symbol:      d.Symbol,
+			Action:      d.Action,
+			Payload:     string(payload),
+		})
+	}
+	if err := queue.Enqueue(items); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to enqueue decisions, executing without queue: %v", at.name, err)
+		at.executeInline(ctx, decisions, record, pending)
+		return
+	}
+	at.drainExecutionQueue(ctx, record, pending)
+}
+
+// executeInline executes decisions without persistence (no store, or enqueue failed)
+func (at *AutoTrader) executeInline(ctx *decision.Context, decisions []decision.Decision, record *store.DecisionRecord, pending []string) {
+	for _, d := range decisions {
+		if at.stopRequested(record, pending) {
+			return
+		}
+		at.executeQueuedDecision(ctx, &d, record)
+		pending = pending[1:]
+		at.saveRuntimeState(store.CyclePhaseExecuting, pending)
+	}
+}
+
+// drainExecutionQueue executes pending queue items of this trader until the queue is empty or stop is requested
+// ctx is nil when replaying items of a previous run (no decision memory / indicator snapshot).
+func (at *AutoTrader) drainExecutionQueue(ctx *decision.Context, record *store.DecisionRecord, pending []string) {
+	queue := at.store.ExecutionQueue()
+	for {
+		if at.stopRequested(record, pending) {
+			return
+		}
+		item, err := queue.ClaimNext(at.id)
+		if err != nil {
+			logger.Errorf("❌ [%s] %v", at.name, err)
+			return
+		}
+		if item == nil {
+			return
+		}
+
+		var d decision.Decision
+		if err := json.Unmarshal([]byte(item.Payload), &d); err != nil {
+			queue.Fail(item.ID, fmt.Sprintf("invalid payload: %v", err))
+			logger.Errorf("❌ [%s] Dropping queued decision %d (%s %s): invalid payload: %v", at.name, item.ID, item.Symbol, item.Action, err)
+			continue
+		}
+
+		err = at.executeQueuedDecision(ctx, &d, record)
+		switch {
+		case err == nil:
+			queue.Complete(item.ID)
+		case isRetryableExecutionError(err) && item.Attempts < executionMaxAttempts:
+			queue.Retry(item.ID, err.Error())
+			wait := executionRetryDelay(err, item.Attempts)
+			logger.Infof("🔁 [%s] Retrying %s %s in %s (attempt %d/%d)", at.name, d.Symbol, d.Action, wait, item.Attempts+1, executionMaxAttempts)
+			time.Sleep(wait)
+			continue
+		default:
+			queue.Fail(item.ID, err.Error())
+		}
+
+		if len(pending) > 0 {
+			pending = pending[1:]
+			at.saveRuntimeState(store.CyclePhaseExecuting, pending)
+		}
+	}
+}
+
+// executeQueuedDecision executes one decision and appends its action record and log line to record
+func (at *AutoTrader) executeQueuedDecision(ctx *decision.Context, d *decision.Decision, record *store.DecisionRecord) error {
+	actionRecord := store.DecisionAction{
+		Action:        d.Action,
+		Symbol:        d.Symbol,
+		Quantity:      0,
+		Leverage:      d.Leverage,
+		Price:         0,
+		StopLoss:      d.StopLoss,
+		TakeProfit:    d.TakeProfit,
+		Confidence:    d.Confidence,
+		Reasoning:     d.Reasoning,
+		Timestamp:     time.Now(),
+		Success:       false,
+		SchemaVersion: d.SchemaVersion,
+		Metadata:      d.Metadata,
+		Preview:       decision.PreviewTrade(d, ctx),
+	}
+	if actionRecord.Preview != nil {
+		record.ExecutionLog = append(record.ExecutionLog, decision.FormatTradePreview(d.Symbol, d.Action, actionRecord.Preview))
+	}
+
+	// Venue price guard runs first: repricing changes the stop loss and take profit that get recorded
+	err := at.checkVenuePrice(ctx, d, record)
+	if err == nil {
+		actionRecord.StopLoss, actionRecord.TakeProfit = d.StopLoss, d.TakeProfit
+		err = at.executeDecisionWithRecord(d, &actionRecord)
+	}
+	if err != nil {
+		logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
+		actionRecord.Error = err.Error()
+		actionRecord.ErrorCategory = actionErrorCategory(err)
+		if IsRateLimitError(err) {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🚦 %s %s rate limited: %v", d.Symbol, d.Action, err))
+		} else if errors.Is(err, ErrSymbolBlocked) {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("📃 %s %s %v", d.Symbol, d.Action, err))
+		} else if errors.Is(err, ErrTradeBudgetExceeded) {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🧮 %s %s rejected: %v", d.Symbol, d.Action, err))
+		} else if errors.Is(err, ErrTraderPaused) {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⏸ %s %s rejected: %v", d.Symbol, d.Action, err))
+		} else if errors.Is(err, ErrVenuePriceDeviation) {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⚖️ %s %s rejected: %v", d.Symbol, d.Action, err))
+		} else {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
+		}
+	} else {
+		actionRecord.Success = true
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s succeeded — %s", d.Symbol, d.Action, d.Reasoning))
+		at.rememberDecision(ctx, d)
+		at.recordEntryIndicators(ctx, d)
+		// Brief delay after successful execution
+		time.Sleep(1 * time.Second)
+	}
+
+	record.Decisions = append(record.Decisions, actionRecord)
+	return err
+}
+
+// stopRequested reports a mid-cycle stop; remaining queued decisions stay pending for the next run
+func (at *AutoTrader) stopRequested(record *store.DecisionRecord, pending []string) bool {
+	if at.isRunning.Load() {
+		return false
+	}
+	logger.Infof("⏹ [%s] Stop requested, skipping %d remaining decisions: %s",
+		at.name, len(pending), strings.Join(pending, ", "))
+	record.ExecutionLog = append(record.ExecutionLog,
+		fmt.Sprintf("⏹ Stop requested, skipped: %s", strings.Join(pending, ", ")))
+	return true
+}
+
+// resumeExecutionQueue replays decisions left pending by the previous run (called once on Run)
+func (at *AutoTrader) resumeExecutionQueue() {
+	if at.store == nil {
+		return
+	}
+	queue := at.store.ExecutionQueue()
+	if purged, err := queue.Purge(time.Now().Add(-executionQueueRetention)); err == nil && purged > 0 {
+		logger.Infof("🧹 Purged %d finished execution queue items", purged)
+	}
+
+	staleness := at.executionStaleness()
+	expired, err := queue.Recover(at.id, time.Now().Add(-staleness))
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to recover execution queue: %v", at.name, err)
+		return
+	}
+	if expired > 0 {
+		logger.Infof("🗑 [%s] Expired %d queued decisions older than %s", at.name, expired, staleness)
+	}
+
+	count, err := queue.CountPending(at.id)
+	if err != nil || count == 0 {
+		return
+	}
+	if at.config.TradeOnlyMarketHours && !isMarketOpen() {
+		queue.ExpirePending(at.id, "market closed at restart")
+		logger.Infof("🗑 [%s] Market closed, expired %d queued decisions from previous run", at.name, count)
+		return
+	}
+
+	logger.Infof("♻️ [%s] Replaying %d queued decisions from previous run", at.name, count)
+	record := &store.DecisionRecord{
+		ExecutionLog: []string{fmt.Sprintf("♻️ Replayed %d queued decisions interrupted by restart", count)},
+		Success:      true,
+	}
+	at.drainExecutionQueue(nil, record, nil)
+	at.saveDecision(record)
+	at.saveRuntimeState(store.CyclePhaseIdle, nil)
+}
+
+// executionStaleness age after which queued decisions are no longer executed (one scan interval, at least 1 minute)
+func (at *AutoTrader) executionStaleness() time.Duration {
+	return max(at.config.ScanInterval, time.Minute)
+}
+
+// isRetryableExecutionError whether the request certainly did not execute and may be retried
+func isRetryableExecutionError(err error) bool {
+	if IsRateLimitError(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"connection refused", "no such host", "network is unreachable"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// executionRetryDelay wait before the next attempt: exchange Retry-After if given, exponential backoff otherwise
+func executionRetryDelay(err error, attempts int) time.Duration {
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) && rlErr.RetryAfter > 0 {
+		return min(rlErr.RetryAfter, executionRetryMaxWait)
+	}
+	return min(executionRetryBackoff<<(attempts-1), executionRetryMaxWait)
+}
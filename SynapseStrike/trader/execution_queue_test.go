@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestExecutionQueueResume tests that decisions left pending by a crash are replayed unless stale
+func TestExecutionQueueResume(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	queue := st.ExecutionQueue()
+	items := []*store.ExecutionItem{
+		{TraderID: "trader-1", Symbol: "OLD", Action: "hold", Payload: `{"symbol":"OLD","action":"hold"}`},
+		{TraderID: "trader-1", Symbol: "AAPL", Action: "hold", Payload: `{"symbol":"AAPL","action":"hold"}`},
+		{TraderID: "trader-1", Symbol: "MSFT", Action: "bogus", Payload: `{"symbol":"MSFT","action":"bogus"}`},
+	}
+	if err := queue.Enqueue(items); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	// First item was enqueued long ago, second was running when the process died
+	st.DB().Exec(`UPDATE execution_queue SET enqueued_at = ? WHERE id = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), items[0].ID)
+	st.DB().Exec(`UPDATE execution_queue SET status = ? WHERE id = ?`, store.ExecutionRunning, items[1].ID)
+
+	at := &AutoTrader{id: "trader-1", name: "test", store: st, config: AutoTraderConfig{ScanInterval: 5 * time.Minute}}
+	at.isRunning.Store(true)
+	at.resumeExecutionQueue()
+
+	want := map[int64]string{items[0].ID: store.ExecutionExpired, items[1].ID: store.ExecutionDone, items[2].ID: store.ExecutionFailed}
+	for id, status := range want {
+		var got string
+		st.DB().QueryRow(`SELECT status FROM execution_queue WHERE id = ?`, id).Scan(&got)
+		if got != status {
+			t.Errorf("item %d status = %s, want %s", id, got, status)
+		}
+	}
+	if count, _ := queue.CountPending("trader-1"); count != 0 {
+		t.Errorf("pending after resume = %d, want 0", count)
+	}
+}
+
+func TestExecutionRetryPolicy(t *testing.T) {
+	rateLimited := &RateLimitError{Exchange: "binance", RetryAfter: 5 * time.Second}
+	if !isRetryableExecutionError(rateLimited) || executionRetryDelay(rateLimited, 1) != 5*time.Second {
+		t.Error("rate limit error should be retried after Retry-After")
+	}
+	refused := errors.New("dial tcp: connection refused")
+	if !isRetryableExecutionError(refused) || executionRetryDelay(refused, 2) != 4*time.Second {
+		t.Error("connection refused should be retried with exponential backoff")
+	}
+	if isRetryableExecutionError(errors.New("context deadline exceeded (Client.Timeout exceeded)")) {
+		t.Error("timeouts are ambiguous and must not be retried")
+	}
+}
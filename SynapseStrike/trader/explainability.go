@@ -0,0 +1,56 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+)
+
+// recordEntryIndicators stores the indicators the AI saw with the position opened by d (see decision/explain.go)
+// Limit entries keep the snapshot on the pending entry until the order fills.
+func (at *AutoTrader) recordEntryIndicators(ctx *decision.Context, d *decision.Decision) {
+	engine := at.engine()
+	if at.store == nil || engine == nil {
+		return
+	}
+	var side string
+	switch d.Action {
+	case "open_long":
+		side = "long"
+	case "open_short":
+		side = "short"
+	default:
+		return
+	}
+	snap := engine.SnapshotIndicators(ctx, d.Symbol)
+	if snap == nil {
+		return
+	}
+
+	if d.EntryPrice > 0 {
+		at.limitEntriesMu.Lock()
+		for _, entry := range at.limitEntries {
+			if entry.Symbol == d.Symbol && entry.Side == side {
+				entry.Indicators = snap
+			}
+		}
+		at.limitEntriesMu.Unlock()
+		return
+	}
+	at.storeEntryIndicators(d.Symbol, side, snap)
+}
+
+// storeEntryIndicators attaches an indicator snapshot to this trader's open position record
+func (at *AutoTrader) storeEntryIndicators(symbol, side string, snap *store.EntryIndicators) {
+	if at.store == nil || snap == nil {
+		return
+	}
+	pos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+	if err != nil || pos == nil {
+		logger.Warnf("⚠️  [Explain] No open position record for %s %s, entry indicators not stored", symbol, side)
+		return
+	}
+	if err := at.store.Position().SetEntryIndicators(pos.ID, snap); err != nil {
+		logger.Warnf("⚠️  [Explain] Failed to store entry indicators of %s %s: %v", symbol, side, err)
+	}
+}
@@ -0,0 +1,57 @@
+package trader
+
+import "strings"
+
+// FeeSchedule maker/taker fee rates of an exchange (fraction of notional, e.g. 0.0005 = 0.05%)
+type FeeSchedule struct {
+	Maker float64
+	Taker float64
+}
+
+// feeSchedules default VIP0 fee rates per exchange type (read-only)
+// Used to estimate fees when the exchange does not report commission for a fill.
+var feeSchedules = map[string]FeeSchedule{
+	"binance":      {Maker: 0.0002, Taker: 0.0005},
+	"binance-spot": {Maker: 0.001, Taker: 0.001},
+	"coinbase":     {Maker: 0.004, Taker: 0.006}, // Advanced Trade base tier
+	"bybit":        {Maker: 0.0002, Taker: 0.00055},
+	"okx":          {Maker: 0.0002, Taker: 0.0005},
+	"bitget":       {Maker: 0.0002, Taker: 0.0006},
+	"hyperliquid":  {Maker: 0.00015, Taker: 0.00045},
+	"aster":        {Maker: 0.0001, Taker: 0.00035},
+	"lighter":      {Maker: 0, Taker: 0},
+	"dydx":         {Maker: 0.0001, Taker: 0.0005},
+	"alpaca":       {Maker: 0, Taker: 0}, // Commission-free stock trading
+}
+
+// GetFeeSchedule gets fee schedule of exchange type (zero schedule if unknown)
+func GetFeeSchedule(exchange string) FeeSchedule {
+	if strings.HasPrefix(exchange, "alpaca") {
+		exchange = "alpaca"
+	}
+	return feeSchedules[exchange]
+}
+
+// EstimateFee estimates fee of fill with given notional value
+func EstimateFee(exchange string, notional float64, maker bool) float64 {
+	schedule := GetFeeSchedule(exchange)
+	if maker {
+		return notional * schedule.Maker
+	}
+	return notional * schedule.Taker
+}
+
+// slippageCost cost of filling at actualPrice instead of expectedPrice (positive = worse than expected)
+// Buying (open long / close short) above expected price and selling below it are costs.
+func slippageCost(action string, expectedPrice, actualPrice, quantity float64) float64 {
+	if expectedPrice <= 0 || actualPrice <= 0 {
+		return 0
+	}
+	switch action {
+	case "open_long", "close_short":
+		return (actualPrice - expectedPrice) * quantity
+	case "open_short", "close_long":
+		return (expectedPrice - actualPrice) * quantity
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange string
+		notional float64
+		maker    bool
+		want     float64
+	}{
+		{"binance taker", "binance", 10000, false, 5},
+		{"binance maker", "binance", 10000, true, 2},
+		{"bybit taker", "bybit", 20000, false, 11},
+		{"coinbase maker", "coinbase", 1000, true, 4},
+		{"alpaca variants are commission-free", "alpaca-paper", 50000, false, 0},
+		{"unknown exchange", "unknown", 10000, false, 0},
+		{"zero notional", "okx", 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateFee(tt.exchange, tt.notional, tt.maker); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EstimateFee(%s, %v, %v) = %v, want %v", tt.exchange, tt.notional, tt.maker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlippageCost(t *testing.T) {
+	tests := []struct {
+		action              string
+		expected, actual, q float64
+		want                float64
+	}{
+		{"open_long", 100, 101, 10, 10},   // Bought higher: cost
+		{"open_long", 100, 99, 10, -10},   // Bought lower: improvement
+		{"close_short", 100, 101, 10, 10}, // Buy to cover higher: cost
+		{"open_short", 100, 99, 10, 10},   // Sold lower: cost
+		{"close_long", 100, 101, 10, -10}, // Sold higher: improvement
+		{"close_long", 0, 101, 10, 0},     // Unknown expected price
+		{"open_long", 100, 0, 10, 0},      // Unknown fill price
+		{"hold", 100, 101, 10, 0},         // Not an order action
+	}
+	for _, tt := range tests {
+		if got := slippageCost(tt.action, tt.expected, tt.actual, tt.q); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("slippageCost(%s, %v, %v, %v) = %v, want %v", tt.action, tt.expected, tt.actual, tt.q, got, tt.want)
+		}
+	}
+}
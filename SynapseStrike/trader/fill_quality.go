@@ -0,0 +1,119 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"time"
+)
+
+// ============================================================================
+// Fill Quality (Slippage Analytics)
+// ============================================================================
+// Every confirmed fill is stored with its slippage in basis points against
+// the market price the order was decided at, plus the smart limit target when
+// the smart order pricer was used (store.FillStore). Stats are aggregated per
+// exchange/symbol for GET /api/traders/:id/fill-quality. With
+// Execution.CalibrateLimitOffset the recorded smart limit fill rate of an
+// exchange/symbol scales its VWAP ± ATR offset: a limit that rarely fills
+// before the timeout only adds delay before a market order, so it is pulled
+// closer; one that nearly always fills is leaving price improvement unasked.
+
+const (
+	// Smart order keys set on an order result by executeWithSmartOrders
+	smartLimitPriceKey  = "smartLimitPrice"
+	smartLimitFilledKey = "smartLimitFilled"
+
+	limitCalibrationWindow     = 30  // Most recent smart orders per exchange/symbol used for calibration
+	limitCalibrationMinSamples = 10  // Below this the configured multiplier is used
+	targetLimitFillRate        = 0.7 // Fill rate the calibrated offset aims for
+	minLimitOffsetScale        = 0.25
+	maxLimitOffsetScale        = 2.0
+)
+
+// slippageBps slippage of a fill in basis points of the reference price (positive = worse than expected)
+func slippageBps(action string, referencePrice, fillPrice float64) float64 {
+	if referencePrice <= 0 {
+		return 0
+	}
+	return slippageCost(action, referencePrice, fillPrice, 1) / referencePrice * 10000
+}
+
+// fillOrderType fill type of an order result (smart limit keys, TWAP flag)
+func fillOrderType(orderResult map[string]interface{}) (string, float64) {
+	if isTWAP, _ := orderResult["twap"].(bool); isTWAP {
+		return store.FillTypeTWAP, 0
+	}
+	limitPrice, ok := orderResult[smartLimitPriceKey].(float64)
+	if !ok || limitPrice <= 0 {
+		return store.FillTypeMarket, 0
+	}
+	if filled, _ := orderResult[smartLimitFilledKey].(bool); filled {
+		return store.FillTypeSmartLimit, limitPrice
+	}
+	return store.FillTypeSmartFallback, limitPrice
+}
+
+// recordFill stores fill quality of a confirmed order
+func (at *AutoTrader) recordFill(orderResult map[string]interface{}, orderID, symbol, action string, referencePrice, fillPrice, quantity float64) {
+	if at.store == nil || fillPrice <= 0 || quantity <= 0 {
+		return
+	}
+	orderType, limitPrice := fillOrderType(orderResult)
+	fill := &store.OrderFill{
+		TraderID:       at.id,
+		ExchangeType:   at.exchange,
+		Symbol:         symbol,
+		Action:         action,
+		OrderID:        orderID,
+		OrderType:      orderType,
+		ReferencePrice: referencePrice,
+		LimitPrice:     limitPrice,
+		FillPrice:      fillPrice,
+		Quantity:       quantity,
+		SlippageBps:    slippageBps(action, referencePrice, fillPrice),
+		SlippageUSD:    slippageCost(action, referencePrice, fillPrice, quantity),
+	}
+	if err := at.store.Fill().Create(fill); err != nil {
+		logger.Infof("  ⚠️ Failed to record fill quality: %v", err)
+		return
+	}
+	logger.Infof("  📐 Fill quality %s %s (%s): %+.1f bps vs reference %.6f", symbol, action, orderType, fill.SlippageBps, referencePrice)
+}
+
+// GetFillQuality gets slippage stats of the trader per exchange/symbol (zero since = all time)
+func (at *AutoTrader) GetFillQuality(since time.Time) ([]store.SlippageStats, error) {
+	if at.store == nil {
+		return nil, nil
+	}
+	return at.store.Fill().Stats(at.id, since)
+}
+
+// limitOffsetATRMult ATR multiplier of the smart order pricer, calibrated by recorded fill rate when enabled
+func (at *AutoTrader) limitOffsetATRMult(symbol string, execConfig store.ExecutionConfig) float64 {
+	configured := execConfig.LimitOffsetATRMult
+	if !execConfig.CalibrateLimitOffset || at.store == nil {
+		return configured
+	}
+	stats, err := at.store.Fill().RecentSmartOrderStats(at.exchange, symbol, limitCalibrationWindow)
+	if err != nil {
+		logger.Infof("⚠️ Limit offset calibration unavailable for %s: %v", symbol, err)
+		return configured
+	}
+	calibrated := calibrateLimitOffset(configured, stats)
+	if calibrated != configured {
+		logger.Infof("📐 Limit offset %s calibrated: %.2f → %.2f ATR (limit fill rate %.0f%% over %d orders)",
+			symbol, configured, calibrated, stats.LimitFillRate()*100, stats.SmartOrders)
+	}
+	return calibrated
+}
+
+// calibrateLimitOffset scales the configured ATR multiplier by fill rate / target fill rate
+// Bounded to 0.25-2x of the configured multiplier, unchanged with too few samples.
+func calibrateLimitOffset(configured float64, stats store.SlippageStats) float64 {
+	if configured <= 0 || stats.SmartOrders < limitCalibrationMinSamples {
+		return configured
+	}
+	scale := stats.LimitFillRate() / targetLimitFillRate
+	scale = min(max(scale, minLimitOffsetScale), maxLimitOffsetScale)
+	return configured * scale
+}
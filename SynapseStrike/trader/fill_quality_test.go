@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"math"
+	"testing"
+)
+
+func TestSlippageBps(t *testing.T) {
+	tests := []struct {
+		action string
+		fill   float64
+		want   float64
+	}{
+		{"open_long", 100.1, 10},   // Bought above reference
+		{"close_short", 99.9, -10}, // Bought below reference: improvement
+		{"open_short", 99.8, 20},   // Sold below reference
+		{"close_long", 100.05, -5}, // Sold above reference: improvement
+	}
+	for _, tt := range tests {
+		if got := slippageBps(tt.action, 100, tt.fill); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("slippageBps(%s, 100, %.2f) = %.4f, want %.4f", tt.action, tt.fill, got, tt.want)
+		}
+	}
+	if got := slippageBps("open_long", 0, 100); got != 0 {
+		t.Errorf("slippage without reference = %v, want 0", got)
+	}
+}
+
+func TestFillOrderType(t *testing.T) {
+	if typ, _ := fillOrderType(map[string]interface{}{"orderId": "1"}); typ != store.FillTypeMarket {
+		t.Errorf("plain order type = %s, want market", typ)
+	}
+	if typ, _ := fillOrderType(map[string]interface{}{"twap": true}); typ != store.FillTypeTWAP {
+		t.Errorf("TWAP order type = %s, want twap", typ)
+	}
+	typ, limit := fillOrderType(map[string]interface{}{smartLimitPriceKey: 99.5, smartLimitFilledKey: true})
+	if typ != store.FillTypeSmartLimit || limit != 99.5 {
+		t.Errorf("filled smart limit = %s @ %v, want smart_limit @ 99.5", typ, limit)
+	}
+	if typ, _ := fillOrderType(map[string]interface{}{smartLimitPriceKey: 99.5}); typ != store.FillTypeSmartFallback {
+		t.Errorf("unfilled smart limit = %s, want smart_fallback", typ)
+	}
+}
+
+func TestCalibrateLimitOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders int
+		filled int
+		want   float64
+	}{
+		{"too few samples", 5, 0, 0.5},
+		{"on target", 10, 7, 0.5},
+		{"rarely fills: tighter", 20, 7, 0.25},
+		{"never fills: floor", 20, 0, 0.125},
+		{"always fills: wider", 14, 14, 0.5 / 0.7},
+	}
+	for _, tt := range tests {
+		stats := store.SlippageStats{SmartOrders: tt.orders, SmartLimitFills: tt.filled}
+		if got := calibrateLimitOffset(0.5, stats); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: calibrateLimitOffset = %.4f, want %.4f", tt.name, got, tt.want)
+		}
+	}
+}
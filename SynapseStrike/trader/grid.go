@@ -0,0 +1,402 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Grid / DCA Engine (Local Function provider)
+// ============================================================================
+// Runs the grids of strategy Grid config before each Local Function decision.
+// Every level below the market gets a resting GTC limit buy (a leg, stored in
+// grid_legs). A filled leg gets a reduce-only take profit TakeProfitPct above
+// its fill price where the exchange supports partial take profits, otherwise
+// the take profit is watched each cycle and the leg closed at market. Once a
+// leg is closed its level is re-armed. Level orders no longer in the config
+// (grid removed, range or level count changed) are cancelled; open legs are
+// still run to their take profit. Legs survive restarts, and an equity floor
+// halt cancels the resting level orders.
+//
+// Grid P&L is tracked per leg and reported apart from the trader's positions
+// (Context.Grids, GET /api/traders/:id/grid). Grid symbols should not be traded
+// by anything else on the same account: legs share the exchange position.
+
+const maxGridLevels = 100
+
+// gridEngineEnabled whether the trader runs the grid engine (Local Function provider only)
+func (at *AutoTrader) gridEngineEnabled() bool {
+	return at.store != nil && at.mcpClient != nil && at.mcpClient.GetProvider() == mcp.ProviderLocalFunc
+}
+
+// gridSpecs configured grids by symbol (empty when the grid tactic is disabled)
+func (at *AutoTrader) gridSpecs() map[string]store.GridSpec {
+	engine := at.engine()
+	specs := make(map[string]store.GridSpec)
+	if engine == nil {
+		return specs
+	}
+	cfg := engine.GetConfig().Grid
+	if !cfg.Enabled {
+		return specs
+	}
+	for _, grid := range cfg.Grids {
+		specs[grid.Symbol] = grid
+	}
+	return specs
+}
+
+// runGridEngine updates the legs and places level orders, returns grid state and execution log lines
+func (at *AutoTrader) runGridEngine() ([]decision.GridInfo, []string) {
+	if !at.gridEngineEnabled() {
+		return nil, nil
+	}
+	specs := at.gridSpecs()
+	legs, err := at.store.Grid().ListActive(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Grid engine skipped: %v", at.name, err)
+		return nil, []string{fmt.Sprintf("⚠️ Grid engine skipped: %v", err)}
+	}
+	if len(specs) == 0 && len(legs) == 0 {
+		return nil, nil
+	}
+
+	prices := make(map[string]float64)
+	priceOf := func(symbol string) float64 {
+		if price, ok := prices[symbol]; ok {
+			return price
+		}
+		price, err := at.trader.GetMarketPrice(symbol)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Grid: failed to get %s price: %v", at.name, symbol, err)
+			price = 0
+		}
+		prices[symbol] = price
+		return price
+	}
+
+	// 1. Resolve fills, take profits and stale level orders
+	var lines []string
+	armed := make(map[string]bool) // symbol/level with an active leg
+	for _, leg := range legs {
+		var line string
+		switch leg.Status {
+		case store.GridLegPending:
+			spec, ok := specs[leg.Symbol]
+			line = at.updatePendingGridLeg(leg, !ok || !gridLevelMatches(spec, leg))
+		case store.GridLegOpen:
+			line = at.updateOpenGridLeg(leg, priceOf(leg.Symbol))
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if leg.Status == store.GridLegPending || leg.Status == store.GridLegOpen {
+			armed[gridLevelKey(leg.Symbol, leg.Level)] = true
+		}
+	}
+
+	// 2. Arm free levels below the market (none while paused, fills and take profits above still run)
+	if reason := at.pauseReasonIfPaused(); reason != "" {
+		return at.gridReport(specs, priceOf), append(lines, "⏸ Grid: paused ("+reason+"), no new level orders")
+	}
+	symbols := make([]string, 0, len(specs))
+	for symbol := range specs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		lines = append(lines, at.armGridLevels(specs[symbol], priceOf(symbol), armed)...)
+	}
+
+	return at.gridReport(specs, priceOf), lines
+}
+
+// armGridLevels places level orders on the free levels below price
+func (at *AutoTrader) armGridLevels(spec store.GridSpec, price float64, armed map[string]bool) []string {
+	if price <= 0 {
+		return []string{fmt.Sprintf("⚠️ Grid %s: no price, level orders not placed", spec.Symbol)}
+	}
+	lt, ok := at.trader.(LimitEntryTrader)
+	if !ok {
+		return []string{fmt.Sprintf("⚠️ Grid %s: level orders are not supported on %s", spec.Symbol, at.exchange)}
+	}
+	leverage := spec.Leverage
+	if leverage <= 0 {
+		leverage = 1
+	}
+
+	var lines []string
+	for level, levelPrice := range spec.LevelPrices() {
+		if levelPrice >= price || armed[gridLevelKey(spec.Symbol, level)] {
+			continue
+		}
+		order, err := lt.PlaceLimitEntry(spec.Symbol, "long", spec.LevelSizeUSD/levelPrice, levelPrice, leverage)
+		if err != nil {
+			// Same cause (margin, min notional) would fail the remaining levels too
+			logger.Warnf("⚠️ [%s] Grid %s level %d @ %.4f not placed: %v", at.name, spec.Symbol, level, levelPrice, err)
+			return append(lines, fmt.Sprintf("❌ Grid %s level %d @ %.4f not placed: %v", spec.Symbol, level, levelPrice, err))
+		}
+		leg := &store.GridLeg{
+			TraderID:        at.id,
+			Symbol:          spec.Symbol,
+			Level:           level,
+			LevelPrice:      levelPrice,
+			Quantity:        spec.LevelSizeUSD / levelPrice,
+			Status:          store.GridLegPending,
+			EntryOrderID:    fmt.Sprintf("%v", order["orderId"]),
+			TakeProfitPrice: levelPrice * (1 + spec.TakeProfitPct/100),
+		}
+		if placedQty, ok := order["quantity"].(float64); ok && placedQty > 0 {
+			leg.Quantity = placedQty
+		}
+		if err := at.store.Grid().Create(leg); err != nil {
+			// Untracked order would never get a take profit, take it back
+			logger.Errorf("❌ [%s] Grid %s level %d: %v", at.name, spec.Symbol, level, err)
+			if cancelErr := lt.CancelLimitEntry(spec.Symbol, leg.EntryOrderID); cancelErr != nil {
+				logger.Errorf("❌ [%s] Grid %s: failed to cancel untracked order %s: %v", at.name, spec.Symbol, leg.EntryOrderID, cancelErr)
+			}
+			return append(lines, fmt.Sprintf("❌ Grid %s level %d: %v", spec.Symbol, level, err))
+		}
+		armed[gridLevelKey(spec.Symbol, level)] = true
+		logger.Infof("🕸️ [%s] Grid %s level %d: buy %.4f @ %.4f (order %s)", at.name, spec.Symbol, level, leg.Quantity, levelPrice, leg.EntryOrderID)
+		lines = append(lines, fmt.Sprintf("🕸️ Grid %s level %d: buy %.4f @ %.4f placed", spec.Symbol, level, leg.Quantity, levelPrice))
+	}
+	return lines
+}
+
+// updatePendingGridLeg opens a filled leg and cancels a stale one, returns an execution log line
+func (at *AutoTrader) updatePendingGridLeg(leg *store.GridLeg, stale bool) string {
+	status, filledQty, avgPrice, err := at.gridOrderStatus(leg.Symbol, leg.EntryOrderID, leg.LevelPrice)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Grid %s level %d: failed to check order %s: %v", at.name, leg.Symbol, leg.Level, leg.EntryOrderID, err)
+		return ""
+	}
+
+	switch {
+	case status == "FILLED":
+	case status == "CANCELED" || status == "EXPIRED" || status == "REJECTED":
+	case stale:
+		if lt, ok := at.trader.(LimitEntryTrader); ok {
+			if err := lt.CancelLimitEntry(leg.Symbol, leg.EntryOrderID); err != nil {
+				logger.Warnf("⚠️ [%s] Grid %s level %d: failed to cancel stale order: %v", at.name, leg.Symbol, leg.Level, err)
+				return ""
+			}
+		}
+		// Fills may have happened between the status check and the cancel
+		if _, qty, price, err := at.gridOrderStatus(leg.Symbol, leg.EntryOrderID, leg.LevelPrice); err == nil && qty > filledQty {
+			filledQty, avgPrice = qty, price
+		}
+	default:
+		return ""
+	}
+
+	if filledQty <= 0 {
+		leg.Status = store.GridLegCanceled
+		leg.ClosedAt = time.Now().UTC()
+		at.saveGridLeg(leg)
+		return fmt.Sprintf("🕸️ Grid %s level %d @ %.4f cancelled unfilled", leg.Symbol, leg.Level, leg.LevelPrice)
+	}
+	return at.openGridLeg(leg, filledQty, avgPrice)
+}
+
+// openGridLeg records the fill of a level order and places the leg's take profit
+func (at *AutoTrader) openGridLeg(leg *store.GridLeg, quantity, avgPrice float64) string {
+	// Same distance above the fill as configured above the level
+	leg.TakeProfitPrice *= avgPrice / leg.LevelPrice
+	leg.Status = store.GridLegOpen
+	leg.Quantity = quantity
+	leg.FillPrice = avgPrice
+	leg.FilledAt = time.Now().UTC()
+
+	tpNote := "watched each cycle"
+	if stp, ok := at.trader.(ScaledTakeProfitTrader); ok {
+		orderID, err := stp.PlacePartialTakeProfit(leg.Symbol, "LONG", quantity, leg.TakeProfitPrice)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Grid %s level %d: take profit order failed, watched each cycle: %v", at.name, leg.Symbol, leg.Level, err)
+		} else {
+			leg.TPOrderID = orderID
+			tpNote = "order " + orderID
+		}
+	}
+	at.saveGridLeg(leg)
+	logger.Infof("🕸️ [%s] Grid %s level %d filled: %.4f @ %.4f, take profit %.4f (%s)",
+		at.name, leg.Symbol, leg.Level, quantity, avgPrice, leg.TakeProfitPrice, tpNote)
+	return fmt.Sprintf("🕸️ Grid %s level %d filled: %.4f @ %.4f, take profit %.4f", leg.Symbol, leg.Level, quantity, avgPrice, leg.TakeProfitPrice)
+}
+
+// updateOpenGridLeg closes a leg whose take profit was hit, returns an execution log line
+func (at *AutoTrader) updateOpenGridLeg(leg *store.GridLeg, price float64) string {
+	if leg.TPOrderID != "" {
+		status, _, avgPrice, err := at.gridOrderStatus(leg.Symbol, leg.TPOrderID, leg.TakeProfitPrice)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Grid %s level %d: failed to check take profit order: %v", at.name, leg.Symbol, leg.Level, err)
+			return ""
+		}
+		switch status {
+		case "FILLED":
+			return at.closeGridLeg(leg, avgPrice)
+		case "CANCELED", "EXPIRED", "REJECTED":
+			leg.TPOrderID = ""
+			at.saveGridLeg(leg)
+			logger.Warnf("⚠️ [%s] Grid %s level %d: take profit order %s, watched each cycle from now on",
+				at.name, leg.Symbol, leg.Level, strings.ToLower(status))
+		default:
+			return ""
+		}
+	}
+
+	if price <= 0 || price < leg.TakeProfitPrice {
+		return ""
+	}
+	if _, err := at.trader.CloseLong(leg.Symbol, leg.Quantity); err != nil {
+		logger.Warnf("⚠️ [%s] Grid %s level %d: take profit close failed: %v", at.name, leg.Symbol, leg.Level, err)
+		return fmt.Sprintf("❌ Grid %s level %d take profit close failed: %v", leg.Symbol, leg.Level, err)
+	}
+	return at.closeGridLeg(leg, price)
+}
+
+// closeGridLeg realizes the P&L of a leg, its level is re-armed on the next pass
+func (at *AutoTrader) closeGridLeg(leg *store.GridLeg, exitPrice float64) string {
+	leg.Status = store.GridLegClosed
+	leg.ExitPrice = exitPrice
+	leg.RealizedPnL = (exitPrice - leg.FillPrice) * leg.Quantity
+	leg.ClosedAt = time.Now().UTC()
+	at.saveGridLeg(leg)
+	logger.Infof("🕸️ [%s] Grid %s level %d take profit: %.4f @ %.4f, P&L %+.2f",
+		at.name, leg.Symbol, leg.Level, leg.Quantity, exitPrice, leg.RealizedPnL)
+	return fmt.Sprintf("🕸️ Grid %s level %d take profit @ %.4f, P&L %+.2f", leg.Symbol, leg.Level, exitPrice, leg.RealizedPnL)
+}
+
+// cancelGridOrders cancels all resting level orders (open legs keep their take profits)
+func (at *AutoTrader) cancelGridOrders(reason string) {
+	if at.store == nil {
+		return
+	}
+	legs, err := at.store.Grid().ListActive(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load grid legs (%s): %v", at.name, reason, err)
+		return
+	}
+	for _, leg := range legs {
+		if leg.Status == store.GridLegPending {
+			at.updatePendingGridLeg(leg, true)
+		}
+	}
+	logger.Infof("🕸️ [%s] Grid level orders cancelled (%s)", at.name, reason)
+}
+
+// gridOrderStatus reads status, executed quantity and average price of a grid order
+func (at *AutoTrader) gridOrderStatus(symbol, orderID string, fallbackPrice float64) (string, float64, float64, error) {
+	order, err := at.trader.GetOrderStatus(symbol, orderID)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	status, _ := order["status"].(string)
+	filledQty, _ := order["executedQty"].(float64)
+	avgPrice, _ := order["avgPrice"].(float64)
+	if avgPrice <= 0 {
+		avgPrice = fallbackPrice
+	}
+	return strings.ToUpper(status), filledQty, avgPrice, nil
+}
+
+// saveGridLeg persists a leg update
+func (at *AutoTrader) saveGridLeg(leg *store.GridLeg) {
+	if err := at.store.Grid().Update(leg); err != nil {
+		logger.Errorf("❌ [%s] %v", at.name, err)
+	}
+}
+
+// gridReport state and P&L of configured grids and of grids with legs left
+func (at *AutoTrader) gridReport(specs map[string]store.GridSpec, priceOf func(string) float64) []decision.GridInfo {
+	legs, err := at.store.Grid().ListActive(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load grid legs: %v", at.name, err)
+	}
+	realized, err := at.store.Grid().RealizedPnL(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load grid P&L: %v", at.name, err)
+	}
+
+	grids := make(map[string]*decision.GridInfo)
+	gridOf := func(symbol string) *decision.GridInfo {
+		if grid, ok := grids[symbol]; ok {
+			return grid
+		}
+		grid := &decision.GridInfo{Symbol: symbol}
+		if spec, ok := specs[symbol]; ok {
+			grid.LowerPrice, grid.UpperPrice, grid.Levels = spec.LowerPrice, spec.UpperPrice, spec.Levels
+		}
+		grids[symbol] = grid
+		return grid
+	}
+	for symbol := range specs {
+		gridOf(symbol)
+	}
+	for _, leg := range legs {
+		grid := gridOf(leg.Symbol)
+		if leg.Status == store.GridLegPending {
+			grid.PendingLevels++
+			continue
+		}
+		grid.OpenLegs++
+		grid.OpenQuantity += leg.Quantity
+	}
+	for _, pnl := range realized {
+		grid := gridOf(pnl.Symbol)
+		grid.ClosedLegs, grid.RealizedPnL = pnl.ClosedLegs, pnl.RealizedPnL
+	}
+
+	report := make([]decision.GridInfo, 0, len(grids))
+	for symbol, grid := range grids {
+		// Only grids still running or holding legs are priced
+		if _, ok := specs[symbol]; ok || grid.PendingLevels > 0 || grid.OpenLegs > 0 {
+			grid.Price = priceOf(symbol)
+		}
+		if grid.Price > 0 {
+			for _, leg := range legs {
+				if leg.Symbol == symbol && leg.Status == store.GridLegOpen {
+					grid.UnrealizedPnL += (grid.Price - leg.FillPrice) * leg.Quantity
+				}
+			}
+		}
+		report = append(report, *grid)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Symbol < report[j].Symbol })
+	return report
+}
+
+// GetGridReport gets state and P&L of the trader's grids (for API)
+func (at *AutoTrader) GetGridReport() []decision.GridInfo {
+	if at.store == nil {
+		return nil
+	}
+	return at.gridReport(at.gridSpecs(), func(symbol string) float64 {
+		price, err := at.trader.GetMarketPrice(symbol)
+		if err != nil {
+			return 0
+		}
+		return price
+	})
+}
+
+// gridLevelMatches whether a leg's level is still a level of the grid
+func gridLevelMatches(spec store.GridSpec, leg *store.GridLeg) bool {
+	prices := spec.LevelPrices()
+	if leg.Level >= len(prices) {
+		return false
+	}
+	return math.Abs(prices[leg.Level]-leg.LevelPrice) <= prices[leg.Level]*1e-9
+}
+
+// gridLevelKey key of a grid level
+func gridLevelKey(symbol string, level int) string {
+	return fmt.Sprintf("%s#%d", symbol, level)
+}
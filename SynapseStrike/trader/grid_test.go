@@ -0,0 +1,104 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// gridExchangeMock exchange resting limit orders that fill on demand
+type gridExchangeMock struct {
+	Trader
+	price    float64
+	nextID   int
+	orders   map[string]map[string]interface{}
+	closed   float64
+	canceled []string
+}
+
+func (m *gridExchangeMock) GetMarketPrice(symbol string) (float64, error) {
+	return m.price, nil
+}
+
+func (m *gridExchangeMock) PlaceLimitEntry(symbol, side string, quantity, price float64, leverage int) (map[string]interface{}, error) {
+	m.nextID++
+	id := fmt.Sprintf("%d", m.nextID)
+	m.orders[id] = map[string]interface{}{"status": "NEW", "price": price, "quantity": quantity}
+	return map[string]interface{}{"orderId": id, "quantity": quantity}, nil
+}
+
+func (m *gridExchangeMock) CancelLimitEntry(symbol, orderID string) error {
+	m.orders[orderID]["status"] = "CANCELED"
+	m.canceled = append(m.canceled, orderID)
+	return nil
+}
+
+func (m *gridExchangeMock) GetOrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	return m.orders[orderID], nil
+}
+
+func (m *gridExchangeMock) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	m.closed += quantity
+	return map[string]interface{}{}, nil
+}
+
+// fill fills the resting order at price
+func (m *gridExchangeMock) fill(price float64) {
+	for _, order := range m.orders {
+		if order["status"] == "NEW" && order["price"] == price {
+			order["status"], order["executedQty"], order["avgPrice"] = "FILLED", order["quantity"], price
+		}
+	}
+}
+
+func TestGridEngine(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Grid = store.GridConfig{Enabled: true, Grids: []store.GridSpec{{
+		Symbol: "BTCUSDT", LowerPrice: 90, UpperPrice: 110, Levels: 5, LevelSizeUSD: 10, TakeProfitPct: 2,
+	}}}
+	mock := &gridExchangeMock{price: 102, orders: make(map[string]map[string]interface{})}
+	at := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st,
+		mcpClient: mcp.NewLocalFuncClient(), strategyEngine: decision.NewStrategyEngine(&cfg)}
+
+	// Levels 90, 95 and 100 are below the market
+	grids, _ := at.runGridEngine()
+	if len(grids) != 1 || grids[0].PendingLevels != 3 {
+		t.Fatalf("expected 3 resting levels, got %+v", grids)
+	}
+
+	// Level 100 fills, its take profit is watched at 102
+	mock.fill(100)
+	grids, _ = at.runGridEngine()
+	if grids[0].OpenLegs != 1 || grids[0].PendingLevels != 2 {
+		t.Fatalf("expected 1 open leg and 2 resting levels, got %+v", grids[0])
+	}
+
+	// Take profit hit: leg closed at market, level re-armed
+	mock.price = 103
+	grids, _ = at.runGridEngine()
+	if math.Abs(mock.closed-0.1) > 1e-9 {
+		t.Errorf("expected 0.1 closed at market, got %.4f", mock.closed)
+	}
+	if grids[0].ClosedLegs != 1 || math.Abs(grids[0].RealizedPnL-0.3) > 1e-9 || grids[0].PendingLevels != 3 {
+		t.Fatalf("expected closed leg with P&L 0.3 and level re-armed, got %+v", grids[0])
+	}
+
+	// Grid disabled: resting level orders cancelled, P&L still reported
+	cfg.Grid.Enabled = false
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	grids, _ = at.runGridEngine()
+	if len(mock.canceled) != 3 {
+		t.Errorf("expected 3 level orders cancelled, got %v", mock.canceled)
+	}
+	if len(grids) != 1 || grids[0].PendingLevels != 0 || grids[0].ClosedLegs != 1 {
+		t.Errorf("expected grid P&L without resting levels, got %+v", grids)
+	}
+}
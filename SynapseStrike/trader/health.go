@@ -0,0 +1,187 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Dependency Health Probes
+// ============================================================================
+// /readyz reports per-dependency status of every running trader: the exchange
+// (a balance call, which fails on revoked or wrong API keys), the AI provider
+// (an authenticated ping that spends no tokens, see mcp.Client.Ping) and the
+// freshness of the market data used by the last decision cycle. Results are
+// cached for healthCacheTTL so orchestrators polling every few seconds do not
+// eat into exchange rate limits.
+
+const (
+	ProbeOK      = "ok"
+	ProbeFail    = "fail"
+	ProbeSkipped = "skipped"
+
+	healthProbeTimeout = 10 * time.Second
+	healthCacheTTL     = 30 * time.Second
+
+	// Market data is stale after this many scan intervals without a successful fetch
+	marketDataStaleIntervals = 3
+	minMarketDataStaleAfter  = 5 * time.Minute
+)
+
+// ProbeResult outcome of one dependency probe
+type ProbeResult struct {
+	Status    string `json:"status"` // ok / fail / skipped
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// TraderHealth dependency status of one trader
+type TraderHealth struct {
+	TraderID  string                 `json:"trader_id"`
+	Name      string                 `json:"name"`
+	Running   bool                   `json:"running"`
+	Status    string                 `json:"status"` // fail if any check failed
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]ProbeResult `json:"checks"` // exchange / ai_provider / market_data
+}
+
+// Healthy whether no check failed
+func (h *TraderHealth) Healthy() bool {
+	return h.Status != ProbeFail
+}
+
+// ProbeHealth probes the trader's dependencies (cached for healthCacheTTL)
+// Stopped traders are reported with skipped checks: their credentials are not in use.
+func (at *AutoTrader) ProbeHealth() *TraderHealth {
+	at.healthMu.Lock()
+	if at.lastHealth != nil && time.Since(at.lastHealth.CheckedAt) < healthCacheTTL && at.lastHealth.Running == at.isRunning.Load() {
+		cached := at.lastHealth
+		at.healthMu.Unlock()
+		return cached
+	}
+	at.healthMu.Unlock()
+
+	health := &TraderHealth{
+		TraderID:  at.id,
+		Name:      at.name,
+		Running:   at.isRunning.Load(),
+		Status:    ProbeOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]ProbeResult, 3),
+	}
+	if !health.Running {
+		skipped := ProbeResult{Status: ProbeSkipped, Message: "trader not running"}
+		health.Checks["exchange"], health.Checks["ai_provider"], health.Checks["market_data"] = skipped, skipped, skipped
+	} else {
+		exchange := make(chan ProbeResult, 1)
+		go func() { exchange <- at.probeExchange() }()
+		health.Checks["ai_provider"] = at.probeAIProvider()
+		health.Checks["exchange"] = <-exchange
+		health.Checks["market_data"] = at.probeMarketData()
+	}
+	for _, check := range health.Checks {
+		if check.Status == ProbeFail {
+			health.Status = ProbeFail
+		}
+	}
+
+	at.healthMu.Lock()
+	at.lastHealth = health
+	at.healthMu.Unlock()
+	return health
+}
+
+// probeExchange balance call, which also validates the API keys
+func (at *AutoTrader) probeExchange() ProbeResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		_, err := at.trader.GetBalance()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		result := ProbeResult{Status: ProbeOK, LatencyMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			result.Status = ProbeFail
+			result.Message = err.Error()
+		}
+		return result
+	case <-time.After(healthProbeTimeout):
+		return ProbeResult{Status: ProbeFail, LatencyMs: healthProbeTimeout.Milliseconds(),
+			Message: fmt.Sprintf("balance call timed out after %s", healthProbeTimeout)}
+	}
+}
+
+// probeAIProvider authenticated ping of the AI provider (skipped if the client cannot ping)
+func (at *AutoTrader) probeAIProvider() ProbeResult {
+	pinger, ok := at.mcpClient.(interface{ Ping(context.Context) error })
+	if !ok {
+		return ProbeResult{Status: ProbeSkipped, Message: "AI client does not support ping"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	result := ProbeResult{Status: ProbeOK}
+	if err := pinger.Ping(ctx); err != nil {
+		result.Status = ProbeFail
+		result.Message = err.Error()
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// probeMarketData age of the market data fetched by the last cycle
+// Skipped while cycles are not expected to run (risk pause, closed market, schedule window).
+func (at *AutoTrader) probeMarketData() ProbeResult {
+	now := time.Now()
+	if now.Before(at.stopUntil) {
+		return ProbeResult{Status: ProbeSkipped, Message: "trading paused by risk control"}
+	}
+	if at.equityFloorHalt() != "" {
+		return ProbeResult{Status: ProbeSkipped, Message: "trading halted by equity floor"}
+	}
+	if at.config.TradeOnlyMarketHours && !isMarketOpen() {
+		return ProbeResult{Status: ProbeSkipped, Message: "market closed"}
+	}
+	if allowed, reason := at.config.TradingSchedule.Check(now); !allowed {
+		return ProbeResult{Status: ProbeSkipped, Message: reason}
+	}
+
+	staleAfter := time.Duration(marketDataStaleIntervals) * at.config.ScanInterval
+	if staleAfter < minMarketDataStaleAfter {
+		staleAfter = minMarketDataStaleAfter
+	}
+
+	at.healthMu.Lock()
+	lastFetch, symbols := at.lastMarketDataAt, at.lastMarketDataSymbols
+	at.healthMu.Unlock()
+
+	if lastFetch.IsZero() {
+		if now.Sub(at.startTime) < staleAfter {
+			return ProbeResult{Status: ProbeOK, Message: "waiting for first decision cycle"}
+		}
+		return ProbeResult{Status: ProbeFail, Message: fmt.Sprintf("no market data fetched since start (%s ago)", now.Sub(at.startTime).Round(time.Second))}
+	}
+
+	age := now.Sub(lastFetch)
+	message := fmt.Sprintf("%d symbols, fetched %s ago", symbols, age.Round(time.Second))
+	if age > staleAfter {
+		return ProbeResult{Status: ProbeFail, Message: fmt.Sprintf("stale: %s (limit %s)", message, staleAfter)}
+	}
+	return ProbeResult{Status: ProbeOK, Message: message}
+}
+
+// recordMarketDataFetch remembers when the cycle's market data was fetched (for the freshness probe)
+func (at *AutoTrader) recordMarketDataFetch(symbols int) {
+	if symbols == 0 {
+		return
+	}
+	at.healthMu.Lock()
+	at.lastMarketDataAt = time.Now()
+	at.lastMarketDataSymbols = symbols
+	at.healthMu.Unlock()
+}
@@ -0,0 +1,51 @@
+package trader
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeMarketData(t *testing.T) {
+	at := &AutoTrader{config: AutoTraderConfig{ScanInterval: 3 * time.Minute}, startTime: time.Now()}
+	if result := at.probeMarketData(); result.Status != ProbeOK {
+		t.Errorf("expected ok while waiting for the first cycle, got %+v", result)
+	}
+
+	at.startTime = time.Now().Add(-time.Hour)
+	if result := at.probeMarketData(); result.Status != ProbeFail {
+		t.Errorf("expected fail without any fetch an hour after start, got %+v", result)
+	}
+
+	at.recordMarketDataFetch(12)
+	if result := at.probeMarketData(); result.Status != ProbeOK || !strings.HasPrefix(result.Message, "12 symbols") {
+		t.Errorf("expected fresh data, got %+v", result)
+	}
+
+	// 3 scan intervals = 9 minutes
+	at.lastMarketDataAt = time.Now().Add(-10 * time.Minute)
+	if result := at.probeMarketData(); result.Status != ProbeFail {
+		t.Errorf("expected stale data after 10 minutes, got %+v", result)
+	}
+
+	at.stopUntil = time.Now().Add(time.Hour)
+	if result := at.probeMarketData(); result.Status != ProbeSkipped {
+		t.Errorf("expected skipped during risk pause, got %+v", result)
+	}
+}
+
+func TestProbeHealthStoppedTrader(t *testing.T) {
+	at := &AutoTrader{id: "t1", name: "Trader 1"}
+	health := at.ProbeHealth()
+	if !health.Healthy() || health.Running || len(health.Checks) != 3 {
+		t.Fatalf("expected healthy stopped trader with 3 checks, got %+v", health)
+	}
+	for name, check := range health.Checks {
+		if check.Status != ProbeSkipped {
+			t.Errorf("expected %s skipped for stopped trader, got %+v", name, check)
+		}
+	}
+	if at.ProbeHealth() != health {
+		t.Error("expected cached result within TTL")
+	}
+}
@@ -3,6 +3,7 @@ package trader
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // SafeFloat64 Safely extract float64 value from map
@@ -74,3 +75,11 @@ func SafeInt(data map[string]interface{}, key string) (int, error) {
 		return 0, fmt.Errorf("value for key '%s' is not an integer (type: %T)", key, v)
 	}
 }
+
+// timeFromMillis converts an exchange millisecond timestamp, 0 means not reported (zero time)
+func timeFromMillis(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
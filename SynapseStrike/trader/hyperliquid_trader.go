@@ -5,13 +5,16 @@ import (
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
+	"math"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
 	"github.com/sonirico/go-hyperliquid"
 )
 
@@ -244,14 +247,14 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions
-func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *HyperliquidTrader) GetPositions() ([]Position, error) {
 	// Get account status
 	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 
 	// Iterate through all positions
 	for _, assetPos := range accountState.AssetPositions {
@@ -264,19 +267,16 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 			continue // Skip positions with zero amount
 		}
 
-		posMap := make(map[string]interface{})
-
-		// Normalize symbol format (Hyperliquid uses "BTC", we convert to "BTCUSDT")
-		symbol := position.Coin + "USDT"
-		posMap["symbol"] = symbol
+		// Normalize symbol format (Hyperliquid uses "BTC"/"kPEPE", we convert to "BTCUSDT"/"1000PEPEUSDT")
+		p := Position{Symbol: market.FromExchangeSymbol("hyperliquid", position.Coin)}
 
 		// Position amount and direction
 		if posAmt > 0 {
-			posMap["side"] = "long"
-			posMap["positionAmt"] = posAmt
+			p.Side = "long"
+			p.Qty = posAmt
 		} else {
-			posMap["side"] = "short"
-			posMap["positionAmt"] = -posAmt // Convert to positive number
+			p.Side = "short"
+			p.Qty = -posAmt // Convert to positive number
 		}
 
 		// Price information (EntryPx and LiquidationPx are pointer types)
@@ -297,13 +297,13 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 			markPrice = positionValue / absFloat(posAmt)
 		}
 
-		posMap["entryPrice"] = entryPrice
-		posMap["markPrice"] = markPrice
-		posMap["unRealizedProfit"] = unrealizedPnl
-		posMap["leverage"] = float64(position.Leverage.Value)
-		posMap["liquidationPrice"] = liquidationPx
+		p.EntryPrice = entryPrice
+		p.MarkPrice = markPrice
+		p.UnrealizedPnL = unrealizedPnl
+		p.Leverage = float64(position.Leverage.Value)
+		p.LiqPrice = liquidationPx
 
-		result = append(result, posMap)
+		result = append(result, p)
 	}
 
 	return result, nil
@@ -498,8 +498,8 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -570,8 +570,8 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Qty
 				break
 			}
 		}
@@ -802,14 +802,11 @@ func (t *HyperliquidTrader) SetTakeProfit(symbol string, positionSide string, qu
 	return nil
 }
 
-// FormatQuantity formats quantity to correct precision
+// FormatQuantity formats quantity to correct precision (rounded down, never above the requested size)
 func (t *HyperliquidTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	coin := convertSymbolToHyperliquid(symbol)
-	szDecimals := t.getSzDecimals(coin)
-
-	// Format quantity using szDecimals
-	formatStr := fmt.Sprintf("%%.%df", szDecimals)
-	return fmt.Sprintf(formatStr, quantity), nil
+	step := market.IncrementFromPlaces(t.getSzDecimals(coin))
+	return market.FormatIncrement(market.FloorToIncrement(quantity, step), step), nil
 }
 
 // getSzDecimals gets quantity precision for coin
@@ -834,18 +831,10 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 	return 4 // Default precision
 }
 
-// roundToSzDecimals rounds quantity to correct precision
+// roundToSzDecimals rounds quantity down to correct precision (a close never exceeds the position)
 func (t *HyperliquidTrader) roundToSzDecimals(coin string, quantity float64) float64 {
-	szDecimals := t.getSzDecimals(coin)
-
-	// Calculate multiplier (10^szDecimals)
-	multiplier := 1.0
-	for i := 0; i < szDecimals; i++ {
-		multiplier *= 10.0
-	}
-
-	// Round
-	return float64(int(quantity*multiplier+0.5)) / multiplier
+	step := market.IncrementFromPlaces(t.getSzDecimals(coin))
+	return market.FloorToIncrement(quantity, step).InexactFloat64()
 }
 
 // roundPriceToSigfigs rounds price to 5 significant figures
@@ -857,43 +846,15 @@ func (t *HyperliquidTrader) roundPriceToSigfigs(price float64) float64 {
 
 	const sigfigs = 5 // Hyperliquid standard: 5 significant figures
 
-	// Calculate price magnitude
-	var magnitude float64
-	if price < 0 {
-		magnitude = -price
-	} else {
-		magnitude = price
-	}
-
-	// Calculate required multiplier
-	multiplier := 1.0
-	for magnitude >= 10 {
-		magnitude /= 10
-		multiplier /= 10
-	}
-	for magnitude < 1 {
-		magnitude *= 10
-		multiplier *= 10
-	}
-
-	// Apply significant figures precision
-	for i := 0; i < sigfigs-1; i++ {
-		multiplier *= 10
-	}
-
-	// Round
-	rounded := float64(int(price*multiplier+0.5)) / multiplier
-	return rounded
+	// Decimal places keeping 5 significant figures (negative rounds integer digits, e.g. 123456 -> 123460)
+	magnitude := int32(math.Floor(math.Log10(math.Abs(price))))
+	return decimal.NewFromFloat(price).Round(sigfigs - 1 - magnitude).InexactFloat64()
 }
 
 // convertSymbolToHyperliquid converts standard symbol to Hyperliquid format
-// Example: "BTCUSDT" -> "BTC"
+// Example: "BTCUSDT" -> "BTC", "1000PEPEUSDT" -> "kPEPE"
 func convertSymbolToHyperliquid(symbol string) string {
-	// Remove USDT suffix
-	if len(symbol) > 4 && symbol[len(symbol)-4:] == "USDT" {
-		return symbol[:len(symbol)-4]
-	}
-	return symbol
+	return market.ToExchangeSymbol("hyperliquid", symbol)
 }
 
 // GetOrderStatus gets order status
@@ -281,7 +281,7 @@ func TestNewHyperliquidTrader(t *testing.T) {
 			walletAddr:    "0x1234567890123456789012345678901234567890",
 			testnet:       true,
 			wantError:     true,
-			errorContains: "Failed to parse private key",
+			errorContains: "failed to parse private key",
 		},
 		{
 			name:          "Empty wallet address",
@@ -463,10 +463,10 @@ func TestHyperliquidTrader_RoundToSzDecimals(t *testing.T) {
 		expected float64
 	}{
 		{
-			name:     "BTC - round to 4 decimals",
+			name:     "BTC - round down to 4 decimals",
 			coin:     "BTC",
 			quantity: 1.23456789,
-			expected: 1.2346,
+			expected: 1.2345,
 		},
 		{
 			name:     "ETH - round to 3 decimals",
@@ -478,14 +478,51 @@ func TestHyperliquidTrader_RoundToSzDecimals(t *testing.T) {
 			name:     "Unknown coin - use default 4 decimals",
 			coin:     "UNKNOWN",
 			quantity: 1.23456789,
-			expected: 1.2346,
+			expected: 1.2345,
+		},
+		{
+			name:     "ETH - nearest would exceed the position",
+			coin:     "ETH",
+			quantity: 0.1239,
+			expected: 0.123,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := trader.roundToSzDecimals(tt.coin, tt.quantity)
-			assert.InDelta(t, tt.expected, result, 0.0001)
+			assert.Equal(t, tt.expected, result)
+			assert.LessOrEqual(t, result, tt.quantity)
+		})
+	}
+}
+
+// TestHyperliquidTrader_FormatQuantity Test that order quantities are rounded down to the coin precision
+func TestHyperliquidTrader_FormatQuantity(t *testing.T) {
+	trader := &HyperliquidTrader{
+		meta: &hyperliquid.Meta{
+			Universe: []hyperliquid.AssetInfo{
+				{Name: "BTC", SzDecimals: 4},
+				{Name: "ETH", SzDecimals: 3},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		symbol   string
+		quantity float64
+		expected string
+	}{
+		{name: "BTC position of 0.01239 closes 0.0123, not 0.0124", symbol: "BTCUSDT", quantity: 0.01239, expected: "0.0123"},
+		{name: "ETH exact step", symbol: "ETHUSDT", quantity: 1.5, expected: "1.500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := trader.FormatQuantity(tt.symbol, tt.quantity)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
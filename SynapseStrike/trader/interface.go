@@ -33,6 +33,22 @@ type TradeRecord struct {
 	Time         time.Time // Trade execution time
 }
 
+// Position represents an open position as reported by an exchange
+// Adapters convert their native payloads so consumers never type-assert exchange fields.
+type Position struct {
+	Symbol        string    // Trading pair (e.g., "BTCUSDT")
+	Side          string    // "long" or "short"
+	EntryPrice    float64   // Average entry price
+	MarkPrice     float64   // Current mark price
+	Qty           float64   // Position size, always positive (direction is Side)
+	Leverage      float64   // Leverage (1 for spot and stocks, fractional for effective cross leverage)
+	LiqPrice      float64   // Liquidation price, 0 if none or not reported
+	UnrealizedPnL float64   // Unrealized profit/loss
+	MarginUsed    float64   // Margin held by the position, 0 if not reported
+	CreatedAt     time.Time // Position open time, zero if not reported
+	UpdatedAt     time.Time // Last position update time, zero if not reported
+}
+
 // Trader Unified trader interface
 // Supports multiple trading platforms (Binance, Hyperliquid, etc.)
 type Trader interface {
@@ -40,7 +56,7 @@ type Trader interface {
 	GetBalance() (map[string]interface{}, error)
 
 	// GetPositions Get all positions
-	GetPositions() ([]map[string]interface{}, error)
+	GetPositions() ([]Position, error)
 
 	// OpenLong Open long position
 	OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error)
@@ -94,3 +110,36 @@ type Trader interface {
 	// Returns accurate exit price, fees, and close reason for positions closed externally
 	GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error)
 }
+
+// LimitEntryTrader optional interface for exchanges that can rest GTC limit entry orders
+// Used for decisions with entry_price; SL/TP are placed by the caller once the entry fills.
+type LimitEntryTrader interface {
+	// PlaceLimitEntry places a GTC limit order opening a position (side: "long" / "short")
+	// Returns order info containing "orderId"
+	PlaceLimitEntry(symbol, side string, quantity, price float64, leverage int) (map[string]interface{}, error)
+
+	// CancelLimitEntry cancels a resting limit entry order
+	CancelLimitEntry(symbol, orderID string) error
+}
+
+// ProtectiveOrderTrader optional interface for exchanges whose SL/TP orders can be tracked individually
+// The returned order IDs are stored with the position so the orders can be cancelled by ID
+// on close, partial close or SL/TP modification (see protective_orders.go).
+type ProtectiveOrderTrader interface {
+	// PlaceStopLoss places a stop-loss order, returns its order ID
+	PlaceStopLoss(symbol string, positionSide string, quantity, stopPrice float64) (string, error)
+
+	// PlaceTakeProfit places a take-profit order, returns its order ID
+	PlaceTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error)
+
+	// CancelProtectiveOrder cancels a stop-loss/take-profit order by ID
+	CancelProtectiveOrder(symbol, orderID string) error
+}
+
+// ScaledTakeProfitTrader optional interface for exchanges placing take-profit orders for part of a position
+// Used for the levels of a scaled take profit before the final one (see take_profit_levels.go);
+// cancelled by ID through ProtectiveOrderTrader, which implementers must also implement.
+type ScaledTakeProfitTrader interface {
+	// PlacePartialTakeProfit places a reduce-only take-profit order for quantity, returns its order ID
+	PlacePartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) (string, error)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
 	"strings"
 )
@@ -129,29 +130,8 @@ func (t *LighterTraderV2) GetAccountBalance() (*AccountBalance, error) {
 }
 
 // GetPositions Get all positions (implements Trader interface)
-func (t *LighterTraderV2) GetPositions() ([]map[string]interface{}, error) {
-	positions, err := t.GetPositionsRaw("")
-	if err != nil {
-		return nil, err
-	}
-
-	result := make([]map[string]interface{}, 0, len(positions))
-	for _, pos := range positions {
-		// Return in standard format compatible with auto_trader.go
-		result = append(result, map[string]interface{}{
-			"symbol":           pos.Symbol,
-			"side":             pos.Side,
-			"positionAmt":      pos.Size,             // Standard field name
-			"entryPrice":       pos.EntryPrice,       // Standard field name
-			"markPrice":        pos.MarkPrice,        // Standard field name
-			"liquidationPrice": pos.LiquidationPrice, // Standard field name
-			"unRealizedProfit": pos.UnrealizedPnL,    // Standard field name
-			"leverage":         pos.Leverage,
-			"marginUsed":       pos.MarginUsed,
-		})
-	}
-
-	return result, nil
+func (t *LighterTraderV2) GetPositions() ([]Position, error) {
+	return t.GetPositionsRaw("")
 }
 
 // GetPositionsRaw Get all positions (returns raw type)
@@ -215,15 +195,15 @@ func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
 		}
 
 		pos := Position{
-			Symbol:           lPos.Symbol,
-			Side:             side,
-			Size:             size,
-			EntryPrice:       entryPrice,
-			MarkPrice:        markPrice,
-			LiquidationPrice: liqPrice,
-			UnrealizedPnL:    pnl,
-			Leverage:         leverage,
-			MarginUsed:       marginUsed,
+			Symbol:        lPos.Symbol,
+			Side:          side,
+			Qty:           size,
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			LiqPrice:      liqPrice,
+			UnrealizedPnL: pnl,
+			Leverage:      leverage,
+			MarginUsed:    marginUsed,
 		}
 		positions = append(positions, pos)
 
@@ -244,7 +224,7 @@ func (t *LighterTraderV2) GetPosition(symbol string) (*Position, error) {
 
 	normalizedSymbol := normalizeSymbol(symbol)
 	for _, pos := range positions {
-		if strings.EqualFold(pos.Symbol, normalizedSymbol) && pos.Size > 0 {
+		if strings.EqualFold(pos.Symbol, normalizedSymbol) && pos.Qty > 0 {
 			return &pos, nil
 		}
 	}
@@ -323,7 +303,21 @@ func (t *LighterTraderV2) GetMarketPrice(symbol string) (float64, error) {
 
 // FormatQuantity Format quantity to correct precision (implements Trader interface)
 func (t *LighterTraderV2) FormatQuantity(symbol string, quantity float64) (string, error) {
-	// TODO: Get symbol precision from API
-	// Using default precision for now
-	return fmt.Sprintf("%.4f", quantity), nil
+	return market.FormatQuantityStep(quantity, market.IncrementFromPlaces(lighterSizeDecimals(symbol))), nil
+}
+
+// lighterSizeDecimals supported size decimals of a market
+// TODO: Get symbol precision from API
+// - ETH: supported_size_decimals=4, min=0.0050
+// - BTC: supported_size_decimals=5, min=0.00020
+// - SOL: supported_size_decimals=3, min=0.050
+func lighterSizeDecimals(symbol string) int {
+	switch normalizeSymbol(symbol) {
+	case "BTC":
+		return 5
+	case "SOL":
+		return 3
+	default:
+		return 4 // Default for ETH
+	}
 }
@@ -8,8 +8,8 @@ import (
 	"mime/multipart"
 	"net/http"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/elliottech/lighter-go/types"
@@ -109,13 +109,13 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 		if err != nil {
 			return nil, fmt.Errorf("failed to get position: %w", err)
 		}
-		if pos == nil || pos.Size == 0 {
+		if pos == nil || pos.Qty == 0 {
 			return map[string]interface{}{
 				"symbol": symbol,
 				"status": "NO_POSITION",
 			}, nil
 		}
-		quantity = pos.Size
+		quantity = pos.Qty
 	}
 
 	logger.Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
@@ -152,13 +152,13 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 		if err != nil {
 			return nil, fmt.Errorf("failed to get position: %w", err)
 		}
-		if pos == nil || pos.Size == 0 {
+		if pos == nil || pos.Qty == 0 {
 			return map[string]interface{}{
 				"symbol": symbol,
 				"status": "NO_POSITION",
 			}, nil
 		}
-		quantity = pos.Size
+		quantity = pos.Qty
 	}
 
 	logger.Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
@@ -205,28 +205,14 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 		orderTypeValue = 1
 	}
 
-	// Convert quantity to LIGHTER base_amount format
-	// Different markets have different size_decimals:
-	// - ETH: supported_size_decimals=4, min=0.0050
-	// - BTC: supported_size_decimals=5, min=0.00020
-	// - SOL: supported_size_decimals=3, min=0.050
-	sizeDecimals := 4 // Default for ETH
-	normalizedSymbol := normalizeSymbol(symbol)
-	switch normalizedSymbol {
-	case "BTC":
-		sizeDecimals = 5
-	case "SOL":
-		sizeDecimals = 3
-	case "ETH":
-		sizeDecimals = 4
-	}
-	baseAmount := int64(quantity * float64(pow10(sizeDecimals)))
+	// Convert quantity to LIGHTER base_amount format (rounded down to size decimals)
+	baseAmount := market.FloorToUnits(quantity, lighterSizeDecimals(symbol))
 
 	// For market orders, we need to set a price protection value
 	// Buy orders: set high price (current * 1.05), Sell orders: set low price (current * 0.95)
 	priceValue := uint32(0)
 	if orderType == "limit" {
-		priceValue = uint32(price * 1e2) // Price precision (2 decimals)
+		priceValue = uint32(market.RoundToUnits(price, 2)) // Price precision (2 decimals)
 	} else {
 		// Market order - get current price for protection
 		marketPrice, err := t.GetMarketPrice(symbol)
@@ -235,10 +221,10 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 		}
 		if isAsk {
 			// Sell order - set minimum price (95% of current)
-			priceValue = uint32(marketPrice * 0.95 * 1e2)
+			priceValue = uint32(market.RoundToUnits(marketPrice*0.95, 2))
 		} else {
 			// Buy order - set maximum price (105% of current)
-			priceValue = uint32(marketPrice * 1.05 * 1e2)
+			priceValue = uint32(market.RoundToUnits(marketPrice*1.05, 2))
 		}
 	}
 
@@ -411,13 +397,7 @@ func (t *LighterTraderV2) submitOrder(txType int, txInfo string) (map[string]int
 // normalizeSymbol Convert SynapseStrike symbol format to Lighter format
 // SynapseStrike uses "BTC-PERP", "BTCUSDT", etc. Lighter uses "BTC", "ETH", etc.
 func normalizeSymbol(symbol string) string {
-	// Remove common suffixes
-	s := strings.TrimSuffix(symbol, "-PERP")
-	s = strings.TrimSuffix(s, "USDT")
-	s = strings.TrimSuffix(s, "USDC")
-	s = strings.TrimSuffix(s, "/USDT")
-	s = strings.TrimSuffix(s, "/USDC")
-	return strings.ToUpper(s)
+	return market.ToExchangeSymbol("lighter", symbol)
 }
 
 // getMarketIndex Get market index (convert from symbol) - dynamically fetch from API
@@ -597,20 +577,10 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 	}
 
 	// Convert quantity to base amount
-	sizeDecimals := 4
-	normalizedSymbol := normalizeSymbol(symbol)
-	switch normalizedSymbol {
-	case "BTC":
-		sizeDecimals = 5
-	case "SOL":
-		sizeDecimals = 3
-	case "ETH":
-		sizeDecimals = 4
-	}
-	baseAmount := int64(quantity * float64(pow10(sizeDecimals)))
+	baseAmount := market.FloorToUnits(quantity, lighterSizeDecimals(symbol))
 
 	// TriggerPrice: price precision is 2 decimals (multiply by 100)
-	triggerPriceValue := uint32(triggerPrice * 1e2)
+	triggerPriceValue := uint32(market.RoundToUnits(triggerPrice, 2))
 
 	// For stop orders, Price should be set to a reasonable execution price
 	// Stop-loss sell: price slightly below trigger (95% of trigger)
@@ -620,10 +590,10 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 	var priceValue uint32
 	if isAsk {
 		// Sell order - set price at 95% of trigger to ensure execution
-		priceValue = uint32(triggerPrice * 0.95 * 1e2)
+		priceValue = uint32(market.RoundToUnits(triggerPrice*0.95, 2))
 	} else {
 		// Buy order - set price at 105% of trigger to ensure execution
-		priceValue = uint32(triggerPrice * 1.05 * 1e2)
+		priceValue = uint32(market.RoundToUnits(triggerPrice*1.05, 2))
 	}
 
 	// Stop orders MUST use ImmediateOrCancel (0) with expiry set
@@ -682,12 +652,3 @@ func boolToUint8(b bool) uint8 {
 	}
 	return 0
 }
-
-// pow10 returns 10^n as int64
-func pow10(n int) int64 {
-	result := int64(1)
-	for i := 0; i < n; i++ {
-		result *= 10
-	}
-	return result
-}
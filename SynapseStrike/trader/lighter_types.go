@@ -16,19 +16,6 @@ type AccountBalance struct {
 	MaintenanceMargin float64 `json:"maintenance_margin"` // Maintenance margin
 }
 
-// Position Position information (Lighter)
-type Position struct {
-	Symbol           string  `json:"symbol"`            // Trading pair
-	Side             string  `json:"side"`              // "long" or "short"
-	Size             float64 `json:"size"`              // Position size
-	EntryPrice       float64 `json:"entry_price"`       // Average entry price
-	MarkPrice        float64 `json:"mark_price"`        // Mark price
-	LiquidationPrice float64 `json:"liquidation_price"` // Liquidation price
-	UnrealizedPnL    float64 `json:"unrealized_pnl"`    // Unrealized PnL
-	Leverage         float64 `json:"leverage"`          // Leverage multiplier
-	MarginUsed       float64 `json:"margin_used"`       // Used margin
-}
-
 // CreateOrderRequest Create order request (Lighter)
 type CreateOrderRequest struct {
 	Symbol      string  `json:"symbol"`        // Trading pair
@@ -0,0 +1,276 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Limit Entries ("entry at price" decisions)
+// ============================================================================
+// An open decision with entry_price is placed as a GTC limit order instead of
+// a market order. Each cycle the resting orders are checked before the trading
+// context is built:
+//   - filled: stop loss / take profit are placed and the position is recorded
+//   - expired (past valid_for_minutes or Execution.LimitEntryExpiryMinutes):
+//     the order is cancelled, any partial fill is protected like a fill
+//   - cancelled on the exchange: reported, partial fill protected
+// Resolved entries are reported once in the next cycle's context. Entries
+// live in memory only, so Stop() cancels whatever is still resting.
+
+const (
+	limitEntryPending  = "pending"
+	limitEntryFilled   = "filled"
+	limitEntryExpired  = "expired"
+	limitEntryCanceled = "canceled"
+
+	defaultLimitEntryExpiryMinutes = 60
+)
+
+// pendingLimitEntry resting limit entry order
+type pendingLimitEntry struct {
+	Symbol     string
+	Side       string // long/short
+	OrderID    string
+	Price      float64
+	Quantity   float64
+	Leverage   int
+	StopLoss   float64
+	TakeProfit float64
+	TPLevels   []decision.TakeProfitLevel // Scaled take profit, placed with the take profit once filled
+	PlacedAt   time.Time
+	ExpiresAt  time.Time
+	Indicators *store.EntryIndicators // Indicators the AI saw, stored with the position once filled
+}
+
+// info converts entry to its context representation
+func (e *pendingLimitEntry) info(status string, filledQty float64, note string) decision.LimitEntryInfo {
+	return decision.LimitEntryInfo{
+		Symbol:     e.Symbol,
+		Side:       e.Side,
+		EntryPrice: e.Price,
+		Quantity:   e.Quantity,
+		FilledQty:  filledQty,
+		Status:     status,
+		PlacedAt:   e.PlacedAt.UTC().Format("2006-01-02 15:04 UTC"),
+		ExpiresAt:  e.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"),
+		Note:       note,
+	}
+}
+
+// limitEntryExpiry expiry window of a limit entry decision
+func (at *AutoTrader) limitEntryExpiry(d *decision.Decision) time.Duration {
+	engine := at.engine()
+	if minutes, ok := d.MetaFloat(decision.MetaValidForMinutes); ok && minutes > 0 {
+		return time.Duration(minutes * float64(time.Minute))
+	}
+	minutes := defaultLimitEntryExpiryMinutes
+	if engine != nil {
+		if configured := engine.GetConfig().Execution.LimitEntryExpiryMinutes; configured > 0 {
+			minutes = configured
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// placeLimitEntry places the GTC limit order of an entry_price decision and tracks it
+func (at *AutoTrader) placeLimitEntry(d *decision.Decision, side string, quantity float64, actionRecord *store.DecisionAction) error {
+	lt, ok := at.trader.(LimitEntryTrader)
+	if !ok {
+		return fmt.Errorf("limit entries (entry_price) are not supported on %s", at.exchange)
+	}
+
+	at.limitEntriesMu.Lock()
+	for _, entry := range at.limitEntries {
+		if entry.Symbol == d.Symbol && entry.Side == side {
+			at.limitEntriesMu.Unlock()
+			return fmt.Errorf("%s already has a pending %s limit entry at %.4f", d.Symbol, side, entry.Price)
+		}
+	}
+	at.limitEntriesMu.Unlock()
+
+	order, err := lt.PlaceLimitEntry(d.Symbol, side, quantity, d.EntryPrice, d.Leverage)
+	if err != nil {
+		return err
+	}
+	if placedQty, ok := order["quantity"].(float64); ok && placedQty > 0 {
+		quantity = placedQty
+	}
+	if orderID, ok := order["orderId"].(int64); ok {
+		actionRecord.OrderID = orderID
+	}
+
+	now := time.Now()
+	entry := &pendingLimitEntry{
+		Symbol:     d.Symbol,
+		Side:       side,
+		OrderID:    fmt.Sprintf("%v", order["orderId"]),
+		Price:      d.EntryPrice,
+		Quantity:   quantity,
+		Leverage:   d.Leverage,
+		StopLoss:   d.StopLoss,
+		TakeProfit: d.TakeProfit,
+		TPLevels:   d.TakeProfitLevels,
+		PlacedAt:   now,
+		ExpiresAt:  now.Add(at.limitEntryExpiry(d)),
+	}
+	at.limitEntriesMu.Lock()
+	at.limitEntries = append(at.limitEntries, entry)
+	at.limitEntriesMu.Unlock()
+
+	actionRecord.Quantity = quantity
+	actionRecord.Price = d.EntryPrice
+	logger.Infof("  📌 [%s] %s %s limit entry @ %.4f resting until %s (order %s)",
+		at.name, d.Symbol, side, d.EntryPrice, entry.ExpiresAt.Format("15:04"), entry.OrderID)
+	return nil
+}
+
+// pendingLimitEntryCount number of resting limit entries (count toward max positions)
+func (at *AutoTrader) pendingLimitEntryCount() int {
+	at.limitEntriesMu.Lock()
+	defer at.limitEntriesMu.Unlock()
+	return len(at.limitEntries)
+}
+
+// checkLimitEntries resolves filled, cancelled and expired limit entries
+func (at *AutoTrader) checkLimitEntries() {
+	at.limitEntriesMu.Lock()
+	entries := at.limitEntries
+	at.limitEntriesMu.Unlock()
+	if len(entries) == 0 {
+		return
+	}
+
+	var remaining []*pendingLimitEntry
+	var resolved []decision.LimitEntryInfo
+	for _, entry := range entries {
+		status, filledQty, avgPrice, err := at.limitEntryStatus(entry)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Failed to check limit entry %s %s (order %s): %v", at.name, entry.Symbol, entry.Side, entry.OrderID, err)
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		switch {
+		case status == "FILLED":
+			at.onLimitEntryFilled(entry, filledQty, avgPrice)
+			resolved = append(resolved, entry.info(limitEntryFilled, filledQty, ""))
+		case status == "CANCELED" || status == "EXPIRED" || status == "REJECTED":
+			note := "order " + strings.ToLower(status) + " on exchange"
+			if filledQty > 0 {
+				at.onLimitEntryFilled(entry, filledQty, avgPrice)
+			}
+			resolved = append(resolved, entry.info(limitEntryCanceled, filledQty, note))
+		case time.Now().After(entry.ExpiresAt):
+			if lt, ok := at.trader.(LimitEntryTrader); ok {
+				if err := lt.CancelLimitEntry(entry.Symbol, entry.OrderID); err != nil {
+					logger.Warnf("⚠️ [%s] Failed to cancel expired limit entry %s %s: %v", at.name, entry.Symbol, entry.Side, err)
+					remaining = append(remaining, entry)
+					continue
+				}
+			}
+			// Fills may have happened between the status check and the cancel
+			if _, qty, price, err := at.limitEntryStatus(entry); err == nil && qty > filledQty {
+				filledQty, avgPrice = qty, price
+			}
+			if filledQty > 0 {
+				at.onLimitEntryFilled(entry, filledQty, avgPrice)
+			}
+			resolved = append(resolved, entry.info(limitEntryExpired, filledQty, "unfilled order cancelled after expiry"))
+			logger.Infof("⌛ [%s] Limit entry %s %s @ %.4f expired and cancelled", at.name, entry.Symbol, entry.Side, entry.Price)
+		default:
+			remaining = append(remaining, entry)
+		}
+	}
+
+	at.limitEntriesMu.Lock()
+	// Keep entries placed while the check was running
+	at.limitEntries = append(remaining, at.limitEntries[len(entries):]...)
+	at.resolvedLimitEntries = append(at.resolvedLimitEntries, resolved...)
+	at.limitEntriesMu.Unlock()
+}
+
+// limitEntryStatus reads order status, executed quantity and average price from the exchange
+func (at *AutoTrader) limitEntryStatus(entry *pendingLimitEntry) (string, float64, float64, error) {
+	order, err := at.trader.GetOrderStatus(entry.Symbol, entry.OrderID)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	status, _ := order["status"].(string)
+	filledQty, _ := order["executedQty"].(float64)
+	avgPrice, _ := order["avgPrice"].(float64)
+	if avgPrice <= 0 {
+		avgPrice = entry.Price
+	}
+	return strings.ToUpper(status), filledQty, avgPrice, nil
+}
+
+// onLimitEntryFilled protects and records the filled quantity of a limit entry
+func (at *AutoTrader) onLimitEntryFilled(entry *pendingLimitEntry, quantity, avgPrice float64) {
+	positionSide := strings.ToUpper(entry.Side)
+	logger.Infof("✅ [%s] Limit entry %s %s filled: %.4f @ %.4f", at.name, entry.Symbol, entry.Side, quantity, avgPrice)
+
+	// Record first so the SL/TP order IDs can be tracked on the position record
+	at.recordPositionChange(entry.OrderID, entry.Symbol, positionSide, "open_"+entry.Side,
+		quantity, avgPrice, entry.Price, entry.Leverage, 0, 0, "")
+	at.storeEntryIndicators(entry.Symbol, entry.Side, entry.Indicators)
+
+	if err := at.setStopLoss(entry.Symbol, entry.Side, quantity, entry.StopLoss, false); err != nil {
+		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	}
+	if err := at.setTakeProfit(entry.Symbol, entry.Side, quantity, entry.TakeProfit, false); err != nil {
+		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	}
+	at.placeTakeProfitLevels(entry.Symbol, entry.Side, quantity, entry.TPLevels)
+	at.SetPositionTPSL(entry.Symbol, entry.Side, entry.TakeProfit, entry.StopLoss)
+	at.positionFirstSeenTime[entry.Symbol+"_"+entry.Side] = time.Now().UnixMilli()
+}
+
+// limitEntryContext pending entries plus entries resolved since the last call
+func (at *AutoTrader) limitEntryContext() []decision.LimitEntryInfo {
+	at.limitEntriesMu.Lock()
+	defer at.limitEntriesMu.Unlock()
+
+	infos := at.resolvedLimitEntries
+	at.resolvedLimitEntries = nil
+	for _, entry := range at.limitEntries {
+		infos = append(infos, entry.info(limitEntryPending, 0, ""))
+	}
+	return infos
+}
+
+// cancelLimitEntries cancels all resting limit entries
+func (at *AutoTrader) cancelLimitEntries(reason string) {
+	at.limitEntriesMu.Lock()
+	entries := at.limitEntries
+	at.limitEntries = nil
+	at.limitEntriesMu.Unlock()
+
+	lt, ok := at.trader.(LimitEntryTrader)
+	if !ok {
+		return
+	}
+	for _, entry := range entries {
+		if err := lt.CancelLimitEntry(entry.Symbol, entry.OrderID); err != nil {
+			logger.Warnf("⚠️ [%s] Failed to cancel limit entry %s %s (%s): %v", at.name, entry.Symbol, entry.Side, reason, err)
+			continue
+		}
+		logger.Infof("🚫 [%s] Cancelled limit entry %s %s @ %.4f (%s)", at.name, entry.Symbol, entry.Side, entry.Price, reason)
+	}
+}
+
+// formatLimitEntryUpdate execution log line for a resolved limit entry
+func formatLimitEntryUpdate(entry decision.LimitEntryInfo) string {
+	line := fmt.Sprintf("📌 Limit entry %s %s @ %.4f %s", entry.Symbol, entry.Side, entry.EntryPrice, entry.Status)
+	if entry.FilledQty > 0 && entry.Status != limitEntryFilled {
+		line += fmt.Sprintf(" (partially filled %.4f)", entry.FilledQty)
+	}
+	if entry.Note != "" {
+		line += ": " + entry.Note
+	}
+	return line
+}
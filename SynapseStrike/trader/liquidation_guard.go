@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/notify"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================================================
+// Liquidation Proximity Monitor
+// ============================================================================
+// Opens with a stop near liquidation are rejected at validation (see
+// decision/liquidation.go). Positions can still drift toward liquidation when
+// a stop fails to place or margin is drained by other positions, so the
+// position monitor measures the distance from mark price to the exchange's
+// liquidation price (estimated from entry and leverage when not reported) and
+// sends a liquidation_risk alert once it falls within LiquidationAlertPct.
+// The alert re-arms when the distance recovers to twice the threshold.
+
+// liquidationDistancePct distance from mark price to liquidation in % of mark (ok=false without liquidation price)
+func liquidationDistancePct(side string, pos Position) (distancePct, liqPrice float64, ok bool) {
+	markPrice := pos.MarkPrice
+	liqPrice = pos.LiqPrice
+	if liqPrice <= 0 {
+		liqPrice = decision.EstimateLiquidationPrice(side, pos.EntryPrice, int(pos.Leverage))
+	}
+	if markPrice <= 0 || liqPrice <= 0 {
+		return 0, 0, false
+	}
+	return math.Abs(markPrice-liqPrice) / markPrice * 100, liqPrice, true
+}
+
+// checkLiquidationProximity alerts once when a leveraged position's mark price approaches liquidation
+func (at *AutoTrader) checkLiquidationProximity(symbol, side string, pos Position, rc *store.RiskControlConfig) {
+	enabled, _, alertPct := rc.LiquidationGuard()
+	if !enabled {
+		return
+	}
+	distancePct, liqPrice, ok := liquidationDistancePct(side, pos)
+	if !ok {
+		return
+	}
+
+	posKey := symbol + "_" + side
+	at.liquidationMu.Lock()
+	if at.liquidationAlerted == nil {
+		at.liquidationAlerted = make(map[string]bool)
+	}
+	alerted := at.liquidationAlerted[posKey]
+	switch {
+	case distancePct <= alertPct && !alerted:
+		at.liquidationAlerted[posKey] = true
+	case distancePct > 2*alertPct && alerted:
+		delete(at.liquidationAlerted, posKey)
+		alerted = true // Re-armed, nothing to send
+	}
+	at.liquidationMu.Unlock()
+	if alerted || distancePct > alertPct {
+		return
+	}
+
+	logger.Warnf("🚨 [%s] %s %s mark %.4f is %.2f%% from liquidation price %.4f (alert at %.2f%%)",
+		at.name, symbol, side, pos.MarkPrice, distancePct, liqPrice, alertPct)
+	at.publishEvent(notify.EventLiquidationRisk, symbol,
+		fmt.Sprintf("🚨 Liquidation risk: %s %s", symbol, strings.ToUpper(side)),
+		fmt.Sprintf("Mark price is %.2f%% from liquidation", distancePct),
+		map[string]string{
+			"Mark":        fmt.Sprintf("%.4f", pos.MarkPrice),
+			"Liquidation": fmt.Sprintf("%.4f", liqPrice),
+			"Distance":    fmt.Sprintf("%.2f%%", distancePct),
+		})
+}
+
+// pruneLiquidationAlerts drops alert state of positions that are no longer open
+func (at *AutoTrader) pruneLiquidationAlerts(openKeys map[string]bool) {
+	at.liquidationMu.Lock()
+	defer at.liquidationMu.Unlock()
+	for key := range at.liquidationAlerted {
+		if !openKeys[key] {
+			delete(at.liquidationAlerted, key)
+		}
+	}
+}
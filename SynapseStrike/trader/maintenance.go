@@ -0,0 +1,80 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Exchange Maintenance
+// ============================================================================
+// During a scheduled maintenance window of the trader's exchange (static
+// windows and Bybit/OKX status pages, see market/maintenance.go) no orders
+// are sent: they would only fail with confusing errors. With
+// Execution.MaintenanceAction "defer" the cycle is skipped and recorded as
+// deferred; with "monitor" the cycle runs for analysis, the AI is told that
+// orders are on hold and every order decision is rejected. Manual decisions
+// and queue replays are rejected the same way. The window is widened by
+// MaintenanceBufferMinutes on both sides. Shadow traders never send orders
+// and are not affected.
+
+// ErrExchangeMaintenance an order was not placed because the exchange is in a maintenance window
+var ErrExchangeMaintenance = errors.New("exchange maintenance")
+
+// activeMaintenance maintenance window of the trader's exchange active now, nil if none
+func (at *AutoTrader) activeMaintenance() *market.MaintenanceWindow {
+	engine := at.engine()
+	if at.config.ShadowMode {
+		return nil
+	}
+	var buffer time.Duration
+	if engine != nil {
+		buffer = time.Duration(engine.GetConfig().Execution.MaintenanceBufferMinutes) * time.Minute
+	}
+	return market.Maintenance.ActiveWindow(at.exchange, time.Now(), buffer)
+}
+
+// deferForMaintenance skips the cycle during an active maintenance window ("defer" action), returns true if deferred
+func (at *AutoTrader) deferForMaintenance(record *store.DecisionRecord) bool {
+	if at.engine().GetConfig().Execution.MaintenanceAction == store.MaintenanceMonitor {
+		return false
+	}
+	window := at.activeMaintenance()
+	if window == nil {
+		return false
+	}
+	logger.Infof("🛠 [%s] %s. Deferring trading cycle.", at.name, window)
+	record.Success = false
+	record.ErrorMessage = "Cycle deferred: " + window.String()
+	return true
+}
+
+// maintenanceContext tells the AI that orders are on hold during an active maintenance window ("monitor" action)
+func (at *AutoTrader) maintenanceContext(ctx *decision.Context, record *store.DecisionRecord) {
+	window := at.activeMaintenance()
+	if window == nil {
+		return
+	}
+	logger.Infof("🛠 [%s] %s: monitoring only, no orders this cycle", at.name, window)
+	ctx.VenueMaintenance = window.String()
+	record.ExecutionLog = append(record.ExecutionLog, "🛠 "+window.String()+": monitoring only, orders on hold")
+}
+
+// checkMaintenance rejects order decisions while the exchange is in a maintenance window
+func (at *AutoTrader) checkMaintenance(d *decision.Decision) error {
+	if !strings.HasPrefix(d.Action, "open_") && !strings.HasPrefix(d.Action, "close_") && d.Action != "update_stops" {
+		return nil
+	}
+	window := at.activeMaintenance()
+	if window == nil {
+		return nil
+	}
+	logger.Infof("🛠 [%s] Exchange maintenance, rejected %s %s", at.name, d.Action, d.Symbol)
+	return store.WithErrorCategory(store.ErrorCategoryExchangeReject, fmt.Errorf("%w: %s, order not sent", ErrExchangeMaintenance, window))
+}
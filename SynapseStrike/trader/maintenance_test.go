@@ -0,0 +1,54 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestExchangeMaintenance tests deferring cycles and rejecting orders during a maintenance window
+func TestExchangeMaintenance(t *testing.T) {
+	now := time.Now()
+	market.Maintenance.Configure([]market.MaintenanceWindow{{Exchange: "bybit", Start: now.Add(-time.Minute), End: now.Add(time.Hour)}}, 0)
+	defer market.Maintenance.Configure(nil, 0)
+
+	cfg := store.GetDefaultStrategyConfig("en")
+	at := &AutoTrader{name: "test", exchange: "bybit", strategyEngine: decision.NewStrategyEngine(&cfg)}
+
+	record := &store.DecisionRecord{Success: true}
+	if !at.deferForMaintenance(record) || record.Success {
+		t.Error("defer action should skip the cycle")
+	}
+	err := at.checkMaintenance(&decision.Decision{Symbol: "BTCUSDT", Action: "close_long"})
+	if !errors.Is(err, ErrExchangeMaintenance) || store.ErrorCategoryOf(err) != store.ErrorCategoryExchangeReject {
+		t.Errorf("expected ErrExchangeMaintenance, got %v", err)
+	}
+	if err := at.checkMaintenance(&decision.Decision{Symbol: "BTCUSDT", Action: "hold"}); err != nil {
+		t.Errorf("hold rejected: %v", err)
+	}
+
+	// Monitor action: the cycle runs with the window in the context
+	cfg.Execution.MaintenanceAction = store.MaintenanceMonitor
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	ctx := &decision.Context{}
+	if at.deferForMaintenance(&store.DecisionRecord{}) {
+		t.Error("monitor action should not defer the cycle")
+	}
+	at.maintenanceContext(ctx, &store.DecisionRecord{})
+	if ctx.VenueMaintenance == "" {
+		t.Error("monitor action should tell the AI about the window")
+	}
+
+	// Other exchanges and shadow traders are not affected
+	at.exchange = "binance"
+	if err := at.checkMaintenance(&decision.Decision{Symbol: "BTCUSDT", Action: "open_long"}); err != nil {
+		t.Errorf("binance order rejected: %v", err)
+	}
+	at.exchange, at.config.ShadowMode = "bybit", true
+	if err := at.checkMaintenance(&decision.Decision{Symbol: "BTCUSDT", Action: "open_long"}); err != nil {
+		t.Errorf("shadow order rejected: %v", err)
+	}
+}
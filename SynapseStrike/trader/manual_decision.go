@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCycleInProgress manual decisions are rejected instead of waiting for a running cycle
+var ErrCycleInProgress = errors.New("a decision cycle is in progress, retry shortly")
+
+// SubmitManualDecisions validates user-entered decisions like AI output, applies the risk enforcement
+// and executes them; the batch is saved as a decision record of its own.
+// The returned record is nil only when no record could be built.
+func (at *AutoTrader) SubmitManualDecisions(decisions []decision.Decision, submittedBy string) (*store.DecisionRecord, error) {
+	if !at.cycleMu.TryLock() {
+		return nil, ErrCycleInProgress
+	}
+	defer at.cycleMu.Unlock()
+
+	logger.Infof("✋ [%s] %d manual decisions submitted by %s", at.name, len(decisions), submittedBy)
+	record := &store.DecisionRecord{
+		ExecutionLog: []string{fmt.Sprintf("✋ Manual decisions submitted by %s", submittedBy)},
+		Success:      true,
+	}
+
+	ctx, err := at.buildTradingContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trading context: %w", err)
+	}
+	ctx.EquityRisk = at.currentEquityRisk()
+
+	fullDecision, err := decision.PrepareManualDecisions(ctx, at.engine(), decisions, submittedBy)
+	if fullDecision != nil {
+		record.CoTTrace = fullDecision.CoTTrace
+		if fullDecision.Validation != nil {
+			for _, issue := range fullDecision.Validation.Issues {
+				record.ExecutionLog = append(record.ExecutionLog, issue.String())
+			}
+		}
+		if len(fullDecision.Decisions) > 0 {
+			decisionJSON, _ := json.MarshalIndent(fullDecision.Decisions, "", "  ")
+			record.DecisionJSON = string(decisionJSON)
+		}
+	}
+	if err != nil {
+		record.Success = false
+		record.ErrorMessage = fmt.Sprintf("Manual decisions rejected: %v", err)
+		record.ErrorCategory = store.ErrorCategoryRiskBlock
+		at.saveDecision(record)
+		return record, err
+	}
+
+	// Close positions first, like AI decisions
+	for _, d := range sortDecisionsByPriority(fullDecision.Decisions) {
+		at.executeQueuedDecision(ctx, &d, record)
+	}
+	if err := at.saveDecision(record); err != nil {
+		logger.Infof("⚠ Failed to save manual decision record: %v", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,132 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================================================
+// Pre-trade Margin Impact Simulation
+// ============================================================================
+// Before an open order is placed, the margin of all existing positions plus the
+// new order (per exchange rules) is simulated against account equity. Orders
+// that would push margin usage above RiskControl.MaxMarginUsage, or require more
+// than the available balance, are resized to fit or rejected.
+
+const (
+	defaultMaxMarginUsage = 0.9  // Used when RiskControl.MaxMarginUsage is unset
+	marginOpenLossBuffer  = 0.01 // Extra initial margin reserved for adverse fill / open loss
+	marginSizeBuffer      = 0.98 // Resize to 98% of the max to leave room for price moves
+	regTInitialMarginRate = 0.5  // US stocks: Reg-T initial margin is 50% of position value
+)
+
+// marginRules margin model of an exchange type
+type marginRules struct {
+	minInitialMarginRate float64 // Floor of initial margin rate regardless of leverage (0 = 1/leverage only)
+	takerFeeRate         float64 // Fee reserved from available balance when opening
+}
+
+// getMarginRules gets margin rules of exchange type
+func getMarginRules(exchange string) marginRules {
+	rules := marginRules{takerFeeRate: GetFeeSchedule(exchange).Taker}
+	if strings.HasPrefix(exchange, "alpaca") {
+		rules.minInitialMarginRate = regTInitialMarginRate
+	}
+	if market.IsSpotExchange(exchange) {
+		rules.minInitialMarginRate = 1 // Spot orders are paid in full
+	}
+	return rules
+}
+
+// initialMarginRate margin per unit of notional at given leverage
+func (r marginRules) initialMarginRate(leverage float64) float64 {
+	if leverage < 1 {
+		leverage = 1
+	}
+	return math.Max(1/leverage, r.minInitialMarginRate)
+}
+
+// marginImpact result of simulating an open order against the account
+type marginImpact struct {
+	Equity          float64 // Account equity
+	ExistingMargin  float64 // Margin held by current positions
+	OrderMargin     float64 // Margin + fee required by the order at requested size
+	PostTradeUsage  float64 // (ExistingMargin + OrderMargin) / Equity
+	MaxOrderSizeUSD float64 // Largest order notional within usage limit and available balance
+}
+
+// simulateMarginImpact simulates margin usage after opening sizeUSD at leverage on top of positions
+func simulateMarginImpact(rules marginRules, positions []Position, equity, available, sizeUSD float64, leverage int, maxUsage float64) marginImpact {
+	impact := marginImpact{Equity: equity}
+
+	for _, pos := range positions {
+		// Prefer margin reported by the exchange
+		if pos.MarginUsed > 0 {
+			impact.ExistingMargin += pos.MarginUsed
+			continue
+		}
+		price := pos.MarkPrice
+		if price <= 0 {
+			price = pos.EntryPrice
+		}
+		impact.ExistingMargin += pos.Qty * price * rules.initialMarginRate(pos.Leverage)
+	}
+
+	// Margin per USD of new notional: initial margin + open loss buffer + taker fee
+	perUSD := rules.initialMarginRate(float64(leverage))*(1+marginOpenLossBuffer) + rules.takerFeeRate
+	impact.OrderMargin = sizeUSD * perUSD
+	if equity > 0 {
+		impact.PostTradeUsage = (impact.ExistingMargin + impact.OrderMargin) / equity
+	}
+
+	byUsage := (maxUsage*equity - impact.ExistingMargin) / perUSD
+	byBalance := available / perUSD
+	impact.MaxOrderSizeUSD = math.Max(0, math.Min(byUsage, byBalance))
+	return impact
+}
+
+// maxMarginUsage gets RiskControl.MaxMarginUsage from live strategy config
+func (at *AutoTrader) maxMarginUsage() float64 {
+	engine := at.engine()
+	maxUsage := 0.0
+	if engine != nil {
+		if cfg := engine.GetConfig(); cfg != nil {
+			maxUsage = cfg.RiskControl.MaxMarginUsage
+		}
+	}
+	if strategyConfig := at.strategyConfig(); maxUsage <= 0 && strategyConfig != nil {
+		maxUsage = strategyConfig.RiskControl.MaxMarginUsage
+	}
+	if maxUsage <= 0 {
+		maxUsage = defaultMaxMarginUsage
+	}
+	return maxUsage
+}
+
+// enforceMarginImpact simulates post-trade margin usage and returns the position size allowed (CODE ENFORCED)
+// The order is resized if it would exceed MaxMarginUsage or available balance, and rejected if nothing fits.
+func (at *AutoTrader) enforceMarginImpact(symbol string, positionSizeUSD float64, leverage int,
+	positions []Position, equity, availableBalance float64) (float64, error) {
+	maxUsage := at.maxMarginUsage()
+	impact := simulateMarginImpact(getMarginRules(at.exchange), positions, equity, availableBalance, positionSizeUSD, leverage, maxUsage)
+
+	logger.Infof("  🧮 Margin impact %s: existing %.2f + order %.2f on equity %.2f → %.1f%% (limit %.0f%%)",
+		symbol, impact.ExistingMargin, impact.OrderMargin, equity, impact.PostTradeUsage*100, maxUsage*100)
+
+	if positionSizeUSD <= impact.MaxOrderSizeUSD {
+		return positionSizeUSD, nil
+	}
+
+	adjustedSize := impact.MaxOrderSizeUSD * marginSizeBuffer
+	if adjustedSize <= 0 {
+		return 0, store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("❌ [RISK CONTROL] No margin left for %s: usage %.1f%% of equity already at limit %.0f%%",
+			symbol, impact.ExistingMargin/math.Max(equity, 1e-9)*100, maxUsage*100))
+	}
+	logger.Infof("  ⚠️ [RISK CONTROL] Position %.2f would bring margin usage to %.1f%% (limit %.0f%%, available %.2f), resizing to %.2f",
+		positionSizeUSD, impact.PostTradeUsage*100, maxUsage*100, availableBalance, adjustedSize)
+	return adjustedSize, nil
+}
@@ -0,0 +1,49 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulateMarginImpact(t *testing.T) {
+	rules := marginRules{takerFeeRate: 0.0005}
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, MarkPrice: 60000, Leverage: 10}, // 600 margin
+		{Symbol: "ETHUSDT", Side: "short", Qty: 2, MarkPrice: 3000, MarginUsed: 1200},
+	}
+
+	// Equity 3000, limit 90% → 2700 margin allowed, 1800 already used
+	impact := simulateMarginImpact(rules, positions, 3000, 5000, 5000, 5, 0.9)
+	if math.Abs(impact.ExistingMargin-1800) > 1e-6 {
+		t.Errorf("expected existing margin 1800, got %.2f", impact.ExistingMargin)
+	}
+	perUSD := 0.2*1.01 + 0.0005
+	if want := 900 / perUSD; math.Abs(impact.MaxOrderSizeUSD-want) > 1e-6 {
+		t.Errorf("expected max order %.2f, got %.2f", want, impact.MaxOrderSizeUSD)
+	}
+	if impact.PostTradeUsage <= 0.9 {
+		t.Errorf("expected requested order to exceed limit, usage %.3f", impact.PostTradeUsage)
+	}
+
+	// Available balance is the tighter constraint
+	impact = simulateMarginImpact(rules, positions, 3000, 100, 5000, 5, 0.9)
+	if want := 100 / perUSD; math.Abs(impact.MaxOrderSizeUSD-want) > 1e-6 {
+		t.Errorf("expected balance-bound max order %.2f, got %.2f", want, impact.MaxOrderSizeUSD)
+	}
+
+	// Already over the limit: nothing fits
+	impact = simulateMarginImpact(rules, positions, 1900, 5000, 100, 5, 0.9)
+	if impact.MaxOrderSizeUSD != 0 {
+		t.Errorf("expected no room, got %.2f", impact.MaxOrderSizeUSD)
+	}
+}
+
+func TestMarginRulesRegT(t *testing.T) {
+	rules := getMarginRules("alpaca-paper")
+	if rate := rules.initialMarginRate(4); rate != 0.5 {
+		t.Errorf("expected Reg-T 50%% floor for stocks, got %.2f", rate)
+	}
+	if rate := getMarginRules("binance").initialMarginRate(4); rate != 0.25 {
+		t.Errorf("expected 1/leverage for perps, got %.2f", rate)
+	}
+}
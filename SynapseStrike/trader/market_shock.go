@@ -0,0 +1,29 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"math"
+)
+
+// applyShockMode marks a shock cycle in the record and tightens stops of open positions (no-op without shock)
+// New opens of the cycle are already turned into wait by the decision engine.
+func (at *AutoTrader) applyShockMode(ctx *decision.Context, record *store.DecisionRecord) {
+	shock := ctx.Shock
+	if shock == nil {
+		return
+	}
+	record.ShockMode = true
+	record.ShockReason = shock.Summary()
+	record.ExecutionLog = append(record.ExecutionLog, "🚨 Shock mode: "+shock.Summary())
+	logger.Warnf("🚨 [%s] Shock mode: %s, tightening stops of %d positions to %.2f%%",
+		at.name, shock.Summary(), len(ctx.Positions), shock.StopPct)
+
+	for _, pos := range ctx.Positions {
+		if pos.Quantity == 0 {
+			continue
+		}
+		at.tightenStop("Shock mode", pos.Symbol, pos.Side, math.Abs(pos.Quantity), shock.StopPct)
+	}
+}
@@ -0,0 +1,143 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/notify"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Max Holding Time
+// ============================================================================
+// The position monitor (see startDrawdownMonitor) closes positions held longer
+// than RiskControl.MaxHoldMinutes with close reason "max_hold_time":
+//   - close:  close as soon as the deadline is reached (default)
+//   - review: flag the position for a forced AI review in the next cycle and
+//             close it if it is still open MaxHoldReviewGraceMinutes later
+
+// Max hold actions
+const (
+	MaxHoldActionClose  = "close"
+	MaxHoldActionReview = "review"
+
+	closeReasonMaxHoldTime           = "max_hold_time"
+	defaultMaxHoldReviewGraceMinutes = 30
+)
+
+// maxHoldPolicy gets holding time limit (0 = unlimited), action and review grace period
+func (at *AutoTrader) maxHoldPolicy() (time.Duration, string, time.Duration) {
+	rc := at.drawdownRiskConfig()
+	action := rc.MaxHoldAction
+	if action == "" {
+		action = MaxHoldActionClose
+	}
+	grace := rc.MaxHoldReviewGraceMinutes
+	if grace <= 0 {
+		grace = defaultMaxHoldReviewGraceMinutes
+	}
+	return time.Duration(rc.MaxHoldMinutes) * time.Minute, action, time.Duration(grace) * time.Minute
+}
+
+// positionEntryTime gets position open time (database, then exchange, then first seen by the monitor)
+func (at *AutoTrader) positionEntryTime(symbol, side string, pos Position) time.Time {
+	if at.store != nil {
+		if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err == nil && dbPos != nil && !dbPos.EntryTime.IsZero() {
+			return dbPos.EntryTime
+		}
+	}
+	if !pos.CreatedAt.IsZero() {
+		return pos.CreatedAt
+	}
+
+	posKey := symbol + "_" + side
+	at.maxHoldMu.Lock()
+	defer at.maxHoldMu.Unlock()
+	if at.maxHoldFirstSeen == nil {
+		at.maxHoldFirstSeen = make(map[string]time.Time)
+	}
+	if _, exists := at.maxHoldFirstSeen[posKey]; !exists {
+		at.maxHoldFirstSeen[posKey] = time.Now()
+	}
+	return at.maxHoldFirstSeen[posKey]
+}
+
+// checkMaxHoldTime enforces max holding time for a position, returns true if it was closed
+func (at *AutoTrader) checkMaxHoldTime(symbol, side string, pos Position) bool {
+	limit, action, grace := at.maxHoldPolicy()
+	if limit <= 0 {
+		return false
+	}
+	held := time.Since(at.positionEntryTime(symbol, side, pos))
+	if held < limit {
+		return false
+	}
+
+	posKey := symbol + "_" + side
+	if action == MaxHoldActionReview {
+		at.maxHoldMu.Lock()
+		if at.maxHoldReviews == nil {
+			at.maxHoldReviews = make(map[string]time.Time)
+		}
+		requestedAt, requested := at.maxHoldReviews[posKey]
+		if !requested {
+			at.maxHoldReviews[posKey] = time.Now()
+		}
+		at.maxHoldMu.Unlock()
+
+		if !requested {
+			logger.Infof("⏰ Max holding time reached: %s %s held %v (limit %v), requesting AI review (auto-close in %v)",
+				symbol, side, held.Round(time.Minute), limit, grace)
+			return false
+		}
+		if time.Since(requestedAt) < grace {
+			return false
+		}
+	}
+
+	logger.Infof("⏰ Max holding time close triggered: %s %s held %v (limit %v)", symbol, side, held.Round(time.Minute), limit)
+	at.publishEvent(notify.EventCircuitBreaker, symbol,
+		fmt.Sprintf("⏰ Max holding time: %s %s", symbol, strings.ToUpper(side)),
+		fmt.Sprintf("Held %v | Limit %v", held.Round(time.Minute), limit), nil)
+
+	reasoning := fmt.Sprintf("Max holding time reached (held %v, limit %v)", held.Round(time.Minute), limit)
+	if err := at.closePositionWithReason(symbol, side, closeReasonMaxHoldTime, reasoning); err != nil {
+		logger.Infof("❌ Max holding time close failed (%s %s): %v", symbol, side, err)
+		return false
+	}
+	at.ClearPeakPnLCache(symbol, side)
+	at.clearMaxHoldState(posKey)
+	return true
+}
+
+// maxHoldReviewPending reports whether a position awaits forced AI review
+func (at *AutoTrader) maxHoldReviewPending(symbol, side string) bool {
+	at.maxHoldMu.Lock()
+	defer at.maxHoldMu.Unlock()
+	_, pending := at.maxHoldReviews[symbol+"_"+side]
+	return pending
+}
+
+// pruneMaxHoldState drops tracking of positions that are no longer open
+func (at *AutoTrader) pruneMaxHoldState(openKeys map[string]bool) {
+	at.maxHoldMu.Lock()
+	defer at.maxHoldMu.Unlock()
+	for key := range at.maxHoldReviews {
+		if !openKeys[key] {
+			delete(at.maxHoldReviews, key)
+		}
+	}
+	for key := range at.maxHoldFirstSeen {
+		if !openKeys[key] {
+			delete(at.maxHoldFirstSeen, key)
+		}
+	}
+}
+
+func (at *AutoTrader) clearMaxHoldState(posKey string) {
+	at.maxHoldMu.Lock()
+	defer at.maxHoldMu.Unlock()
+	delete(at.maxHoldReviews, posKey)
+	delete(at.maxHoldFirstSeen, posKey)
+}
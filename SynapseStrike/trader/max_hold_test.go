@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+)
+
+// newMaxHoldTestTrader creates a trader with a 60 minute holding limit and the given action
+func newMaxHoldTestTrader(action string) (*AutoTrader, *shutdownTrader) {
+	at, exchange := newShutdownTestTrader("leave")
+	cfg := at.engine().GetConfig()
+	cfg.RiskControl.MaxHoldMinutes = 60
+	cfg.RiskControl.MaxHoldAction = action
+	cfg.RiskControl.MaxHoldReviewGraceMinutes = 30
+	return at, exchange
+}
+
+// TestCheckMaxHoldTime tests the close and AI review branches of the holding time limit
+func TestCheckMaxHoldTime(t *testing.T) {
+	patches := gomonkey.ApplyFunc(market.Get, func(symbol string) (*market.Data, error) {
+		return &market.Data{Symbol: symbol, CurrentPrice: 100}, nil
+	})
+	defer patches.Reset()
+
+	fresh := Position{Symbol: "AAPL", Side: "long", Qty: 10, CreatedAt: time.Now().Add(-59 * time.Minute)}
+	expired := Position{Symbol: "AAPL", Side: "long", Qty: 10, CreatedAt: time.Now().Add(-61 * time.Minute)}
+
+	t.Run("unlimited", func(t *testing.T) {
+		at, exchange := newMaxHoldTestTrader(MaxHoldActionClose)
+		at.engine().GetConfig().RiskControl.MaxHoldMinutes = 0
+		if at.checkMaxHoldTime("AAPL", "long", expired) || exchange.closedCount() != 0 {
+			t.Error("position closed without a holding limit")
+		}
+	})
+
+	t.Run("close before deadline", func(t *testing.T) {
+		at, exchange := newMaxHoldTestTrader(MaxHoldActionClose)
+		if at.checkMaxHoldTime("AAPL", "long", fresh) || exchange.closedCount() != 0 {
+			t.Error("position closed before the deadline")
+		}
+	})
+
+	t.Run("close at deadline", func(t *testing.T) {
+		at, exchange := newMaxHoldTestTrader("")
+		at.peakPnLCache["AAPL_long"] = 5
+		if !at.checkMaxHoldTime("AAPL", "long", expired) {
+			t.Fatal("expected the default action to close the position")
+		}
+		if got := strings.Join(exchange.closed, ","); got != "AAPL_long" {
+			t.Errorf("closed = %s", got)
+		}
+		if _, exists := at.GetPeakPnLCache()["AAPL_long"]; exists {
+			t.Error("peak PnL cache not cleared after close")
+		}
+	})
+
+	t.Run("close failure keeps the position", func(t *testing.T) {
+		at, exchange := newMaxHoldTestTrader(MaxHoldActionClose)
+		exchange.shouldFailCloseShort = true
+		short := Position{Symbol: "TSLA", Side: "short", Qty: 5, CreatedAt: expired.CreatedAt}
+		if at.checkMaxHoldTime("TSLA", "short", short) {
+			t.Error("failed close reported as closed")
+		}
+	})
+
+	t.Run("review then close after grace", func(t *testing.T) {
+		at, exchange := newMaxHoldTestTrader(MaxHoldActionReview)
+
+		// Deadline reached: the next cycle reviews the position, nothing is closed yet
+		if at.checkMaxHoldTime("AAPL", "long", expired) || exchange.closedCount() != 0 {
+			t.Fatal("review mode closed the position at the deadline")
+		}
+		if !at.maxHoldReviewPending("AAPL", "long") {
+			t.Fatal("AI review not requested at the deadline")
+		}
+		if at.maxHoldReviewPending("TSLA", "short") {
+			t.Error("AI review requested for another position")
+		}
+
+		// Within the grace period the AI decides
+		if at.checkMaxHoldTime("AAPL", "long", expired) || exchange.closedCount() != 0 {
+			t.Fatal("position closed within the grace period")
+		}
+
+		// Still open after the grace period: forced close
+		at.maxHoldMu.Lock()
+		at.maxHoldReviews["AAPL_long"] = time.Now().Add(-31 * time.Minute)
+		at.maxHoldMu.Unlock()
+		if !at.checkMaxHoldTime("AAPL", "long", expired) {
+			t.Fatal("position not closed after the grace period")
+		}
+		if got := strings.Join(exchange.closed, ","); got != "AAPL_long" {
+			t.Errorf("closed = %s", got)
+		}
+		if at.maxHoldReviewPending("AAPL", "long") {
+			t.Error("review still pending after close")
+		}
+	})
+
+	t.Run("review state pruned when the position is gone", func(t *testing.T) {
+		at, _ := newMaxHoldTestTrader(MaxHoldActionReview)
+		at.checkMaxHoldTime("AAPL", "long", expired)
+		at.pruneMaxHoldState(map[string]bool{"TSLA_short": true})
+		if at.maxHoldReviewPending("AAPL", "long") {
+			t.Error("review of a closed position still pending")
+		}
+	})
+}
+
+// TestPositionEntryTime tests the entry time fallbacks: database, then exchange, then first seen
+func TestPositionEntryTime(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	dbEntry := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	if err := st.Position().Create(&store.TraderPosition{TraderID: "t1", Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 95, EntryTime: dbEntry}); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	cfg := store.GetDefaultStrategyConfig("en")
+	at := &AutoTrader{id: "t1", name: "test", store: st, strategyEngine: decision.NewStrategyEngine(&cfg)}
+	exchangeCreated := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+
+	// Database record wins over the exchange timestamp
+	if got := at.positionEntryTime("AAPL", "long", Position{CreatedAt: exchangeCreated}); !got.Equal(dbEntry) {
+		t.Errorf("entry time with database record = %v, want %v", got, dbEntry)
+	}
+
+	// No database record: exchange timestamp
+	if got := at.positionEntryTime("TSLA", "short", Position{CreatedAt: exchangeCreated}); !got.Equal(exchangeCreated) {
+		t.Errorf("entry time from exchange = %v, want %v", got, exchangeCreated)
+	}
+
+	// Neither: first time the monitor saw the position, stable across checks
+	before := time.Now()
+	first := at.positionEntryTime("NVDA", "long", Position{})
+	if first.Before(before) || first.After(time.Now()) {
+		t.Errorf("first seen = %v, want about now", first)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if again := at.positionEntryTime("NVDA", "long", Position{}); !again.Equal(first) {
+		t.Errorf("first seen moved from %v to %v", first, again)
+	}
+
+	// Pruned once the position is gone, a new position starts its own clock
+	at.pruneMaxHoldState(map[string]bool{})
+	if next := at.positionEntryTime("NVDA", "long", Position{}); !next.After(first) {
+		t.Errorf("first seen after prune = %v, want after %v", next, first)
+	}
+}
@@ -0,0 +1,126 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/notify"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// publishEvent sends notification event tagged with this trader's identity
+func (at *AutoTrader) publishEvent(eventType notify.EventType, symbol, title, message string, fields map[string]string) {
+	notify.Publish(notify.Event{
+		Type:       eventType,
+		TraderID:   at.id,
+		TraderName: at.name,
+		Symbol:     symbol,
+		Title:      title,
+		Message:    message,
+		Fields:     fields,
+	})
+}
+
+// notifyPositionOpened notifies about a newly opened position
+func (at *AutoTrader) notifyPositionOpened(symbol, side string, quantity, price float64, leverage int) {
+	at.publishEvent(notify.EventPositionOpened, symbol,
+		fmt.Sprintf("📈 Opened %s %s", strings.ToUpper(side), symbol),
+		"",
+		map[string]string{
+			"Quantity": fmt.Sprintf("%.4f", quantity),
+			"Price":    fmt.Sprintf("%.4f", price),
+			"Leverage": fmt.Sprintf("%dx", leverage),
+			"Notional": fmt.Sprintf("%.2f", quantity*price),
+		})
+}
+
+// notifyPositionClosed notifies about a closed position
+// reason: close reason (ai_decision/manual/stop_loss/take_profit/...)
+func (at *AutoTrader) notifyPositionClosed(symbol, side, reason string, entryPrice, exitPrice, realizedPnL float64) {
+	at.publishEvent(closeEventType(reason), symbol,
+		fmt.Sprintf("%s Closed %s %s", pnlEmoji(realizedPnL), strings.ToUpper(side), symbol),
+		"",
+		map[string]string{
+			"Entry":  fmt.Sprintf("%.4f", entryPrice),
+			"Exit":   fmt.Sprintf("%.4f", exitPrice),
+			"P&L":    fmt.Sprintf("%+.2f", realizedPnL),
+			"Reason": reason,
+		})
+}
+
+// notifyDailySummary sends summary of positions closed since last daily reset
+func (at *AutoTrader) notifyDailySummary(since time.Time) {
+	if at.store == nil || !notify.Global().Enabled(notify.EventDailySummary) {
+		return
+	}
+
+	closed, err := at.store.Position().GetClosedPositions(at.id, 500)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to build daily summary: %v", err)
+		return
+	}
+
+	var trades, wins int
+	var realizedPnL, fees float64
+	for _, pos := range closed {
+		if pos.ExitTime == nil || pos.ExitTime.Before(since) {
+			continue
+		}
+		trades++
+		realizedPnL += pos.RealizedPnL
+		fees += pos.Fee
+		if pos.RealizedPnL > 0 {
+			wins++
+		}
+	}
+
+	winRate := 0.0
+	if trades > 0 {
+		winRate = float64(wins) / float64(trades) * 100
+	}
+
+	fields := map[string]string{
+		"Trades":       fmt.Sprintf("%d", trades),
+		"Win rate":     fmt.Sprintf("%.1f%%", winRate),
+		"Realized P&L": fmt.Sprintf("%+.2f", realizedPnL),
+		"Fees":         fmt.Sprintf("%.2f", fees),
+	}
+	// Entry/exit quality of the day's trades with tracked MFE/MAE (see trade_excursion.go)
+	if quality, err := at.store.Position().GetExcursionStats(at.id, since); err == nil && quality.TrackedTrades > 0 {
+		fields["Entry quality"] = fmt.Sprintf("%.0f/100", quality.AvgEntryQuality)
+		fields["Avg MAE before winners"] = fmt.Sprintf("%.2f%%", quality.AvgMAEWinnersPct)
+		fields["Stop efficiency"] = fmt.Sprintf("%.0f%%", quality.StopEfficiencyPct)
+		fields["Exit efficiency"] = fmt.Sprintf("%.0f%%", quality.ExitEfficiencyPct)
+	}
+	// Return of the day against buy-and-hold (see benchmark.go)
+	if perf, ok := at.benchmarkPerformance(since); ok {
+		fields["Return"] = fmt.Sprintf("%+.2f%%", perf.ReturnPct)
+		fields["Benchmark "+perf.Symbol] = fmt.Sprintf("%+.2f%%", perf.BenchmarkReturnPct)
+		fields["Alpha"] = fmt.Sprintf("%+.2f%%", perf.AlphaPct)
+	}
+
+	at.publishEvent(notify.EventDailySummary, "",
+		fmt.Sprintf("📅 Daily summary %s", time.Now().Format("2006-01-02")),
+		"",
+		fields)
+}
+
+// closeEventType maps close reason to notification event type
+func closeEventType(reason string) notify.EventType {
+	switch strings.ToLower(reason) {
+	case "stop_loss":
+		return notify.EventStopLossHit
+	case "take_profit":
+		return notify.EventTakeProfitHit
+	default:
+		return notify.EventPositionClosed
+	}
+}
+
+// pnlEmoji returns emoji for P&L sign
+func pnlEmoji(pnl float64) string {
+	if pnl >= 0 {
+		return "✅"
+	}
+	return "🔻"
+}
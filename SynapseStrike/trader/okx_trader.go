@@ -12,10 +12,13 @@ import (
 	"io"
 	"net/http"
 	"SynapseStrike/logger"
+	"SynapseStrike/market"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // OKX API endpoints
@@ -53,7 +56,7 @@ type OKXTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Positions cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
@@ -209,19 +212,13 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 // convertSymbol converts generic symbol to OKX format
 // e.g. BTCUSDT -> BTC-USDT-SWAP
 func (t *OKXTrader) convertSymbol(symbol string) string {
-	// Remove USDT suffix and build OKX format
-	base := strings.TrimSuffix(symbol, "USDT")
-	return fmt.Sprintf("%s-USDT-SWAP", base)
+	return market.ToExchangeSymbol("okx", symbol)
 }
 
 // convertSymbolBack converts OKX format back to generic symbol
 // e.g. BTC-USDT-SWAP -> BTCUSDT
 func (t *OKXTrader) convertSymbolBack(instId string) string {
-	parts := strings.Split(instId, "-")
-	if len(parts) >= 2 {
-		return parts[0] + parts[1]
-	}
-	return instId
+	return market.FromExchangeSymbol("okx", instId)
 }
 
 // GetBalance gets account balance
@@ -295,7 +292,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions
-func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *OKXTrader) GetPositions() ([]Position, error) {
 	// Check cache
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -329,7 +326,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to parse position data: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 	for _, pos := range positions {
 		contractCount, _ := strconv.ParseFloat(pos.Pos, 64)
 		if contractCount == 0 {
@@ -368,19 +365,18 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		cTime, _ := strconv.ParseInt(pos.CTime, 10, 64)
 		uTime, _ := strconv.ParseInt(pos.UTime, 10, 64)
 
-		posMap := map[string]interface{}{
-			"symbol":           symbol,
-			"positionAmt":      posAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": upl,
-			"leverage":         leverage,
-			"liquidationPrice": liqPrice,
-			"side":             side,
-			"createdTime":      cTime, // Position open time (ms)
-			"updatedTime":      uTime, // Position last update time (ms)
-		}
-		result = append(result, posMap)
+		result = append(result, Position{
+			Symbol:        symbol,
+			Side:          side,
+			Qty:           posAmt,
+			EntryPrice:    entryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: upl,
+			Leverage:      leverage,
+			LiqPrice:      liqPrice,
+			CreatedAt:     timeFromMillis(cTime),
+			UpdatedAt:     timeFromMillis(uTime),
+		})
 	}
 
 	// Update cache
@@ -538,7 +534,7 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 
 	// OKX uses contract count, need to convert quantity (in base asset) to contract count
 	// sz = quantity / ctVal (number of contracts = asset amount / asset per contract)
-	sz := quantity / inst.CtVal
+	sz := inst.contracts(quantity)
 	szStr := t.formatSize(sz, inst)
 
 	logger.Infof("  📊 OKX OpenLong: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
@@ -615,7 +611,7 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 
 	// OKX uses contract count, need to convert quantity (in base asset) to contract count
 	// sz = quantity / ctVal (number of contracts = asset amount / asset per contract)
-	sz := quantity / inst.CtVal
+	sz := inst.contracts(quantity)
 	szStr := t.formatSize(sz, inst)
 
 	logger.Infof("  📊 OKX OpenShort: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
@@ -689,8 +685,8 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64) // This is in base asset (BTC)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Qty // This is in base asset (BTC)
 				break
 			}
 		}
@@ -701,7 +697,7 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 
 	// Convert quantity (base asset) to contract count
 	// contracts = quantity / ctVal
-	contracts := quantity / inst.CtVal
+	contracts := inst.contracts(quantity)
 	szStr := t.formatSize(contracts, inst)
 
 	logger.Infof("🔻 OKX close long: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s",
@@ -772,9 +768,9 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		logger.Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
 		for _, pos := range positions {
 			logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v",
-				pos["symbol"], pos["side"], pos["positionAmt"])
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = pos["positionAmt"].(float64) // This is in base asset (BTC)
+				pos.Symbol, pos.Side, pos.Qty)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Qty // This is in base asset (BTC)
 				logger.Infof("🔍 OKX found short position: quantity=%f (base asset)", quantity)
 				break
 			}
@@ -791,7 +787,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 
 	// Convert quantity (base asset) to contract count
 	// contracts = quantity / ctVal
-	contracts := quantity / inst.CtVal
+	contracts := inst.contracts(quantity)
 	szStr := t.formatSize(contracts, inst)
 
 	logger.Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s",
@@ -887,7 +883,7 @@ func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, st
 	}
 
 	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
+	sz := inst.contracts(quantity)
 	szStr := t.formatSize(sz, inst)
 
 	// Determine direction
@@ -905,7 +901,7 @@ func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, st
 		"posSide":     posSide,
 		"ordType":     "conditional",
 		"sz":          szStr,
-		"slTriggerPx": fmt.Sprintf("%.8f", stopPrice),
+		"slTriggerPx": t.formatPrice(stopPrice, inst),
 		"slOrdPx":     "-1", // Market price
 		"tag":         okxTag,
 	}
@@ -930,7 +926,7 @@ func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 	}
 
 	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
+	sz := inst.contracts(quantity)
 	szStr := t.formatSize(sz, inst)
 
 	// Determine direction
@@ -948,7 +944,7 @@ func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 		"posSide":     posSide,
 		"ordType":     "conditional",
 		"sz":          szStr,
-		"tpTriggerPx": fmt.Sprintf("%.8f", takeProfitPrice),
+		"tpTriggerPx": t.formatPrice(takeProfitPrice, inst),
 		"tpOrdPx":     "-1", // Market price
 		"tag":         okxTag,
 	}
@@ -1068,30 +1064,26 @@ func (t *OKXTrader) FormatQuantity(symbol string, quantity float64) (string, err
 	}
 
 	// OKX uses contract count: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
+	sz := inst.contracts(quantity)
 	return t.formatSize(sz, inst), nil
 }
 
-// formatSize formats contract size
+// formatSize formats contract size (rounded down to lotSz)
 func (t *OKXTrader) formatSize(sz float64, inst *OKXInstrument) string {
-	// Determine precision based on lotSz
-	if inst.LotSz >= 1 {
-		return fmt.Sprintf("%.0f", sz)
-	}
-
-	// Calculate decimal places
-	lotSzStr := fmt.Sprintf("%f", inst.LotSz)
-	dotIndex := strings.Index(lotSzStr, ".")
-	if dotIndex == -1 {
-		return fmt.Sprintf("%.0f", sz)
-	}
+	return market.FormatQuantityStep(sz, market.IncrementFromFloat(inst.LotSz))
+}
 
-	// Remove trailing zeros
-	lotSzStr = strings.TrimRight(lotSzStr, "0")
-	precision := len(lotSzStr) - dotIndex - 1
+// formatPrice formats price rounded to tickSz
+func (t *OKXTrader) formatPrice(price float64, inst *OKXInstrument) string {
+	return market.FormatPriceTick(price, market.IncrementFromFloat(inst.TickSz))
+}
 
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, sz)
+// contracts converts quantity in base asset to contract count (quantity / ctVal, exact decimal division)
+func (inst *OKXInstrument) contracts(quantity float64) float64 {
+	if inst.CtVal <= 0 {
+		return quantity
+	}
+	return decimal.NewFromFloat(quantity).Div(decimal.NewFromFloat(inst.CtVal)).InexactFloat64()
 }
 
 // GetOrderStatus gets order status
@@ -0,0 +1,111 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Operator Pause
+// ============================================================================
+// Pause stops new exposure without stopping the trader: cycles keep running,
+// so positions are still monitored, closed and their stops updated by the AI,
+// limit entry fills still get their SL/TP and the drawdown monitor keeps
+// watching. Only opens are rejected (AI, debate and manual decisions alike)
+// and the grid engine arms no new levels; resting orders placed before the
+// pause are left alone. The AI is told about the pause so it does not waste
+// the cycle on opens. Unlike Stop() the process and the trader loop stay up,
+// and unlike the risk control pause it lasts until Resume. The state is kept
+// in the runtime state, so restarts do not lift it.
+// (POST /api/traders/:id/pause, POST /api/traders/:id/resume)
+
+// ErrTraderPaused an open was rejected because the trader is paused
+var ErrTraderPaused = errors.New("trader is paused")
+
+// defaultPauseReason reason recorded when the operator gives none
+const defaultPauseReason = "paused by operator"
+
+// PauseStatus operator pause state of a trader
+type PauseStatus struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	PausedBy string     `json:"paused_by,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// Pause blocks new opens until Resume, returns false if the trader is already paused
+func (at *AutoTrader) Pause(pausedBy, reason string) bool {
+	if reason == "" {
+		reason = defaultPauseReason
+	}
+	at.pauseMu.Lock()
+	if !at.pausedAt.IsZero() {
+		at.pauseMu.Unlock()
+		return false
+	}
+	at.pausedAt, at.pausedBy, at.pauseReason = time.Now().UTC(), pausedBy, reason
+	at.pauseMu.Unlock()
+	at.saveRuntimeState(store.CyclePhaseIdle, nil)
+
+	logger.Infof("⏸ [%s] Paused by %s (%s): new opens blocked, positions still managed", at.name, pausedBy, reason)
+	return true
+}
+
+// Resume lifts an operator pause, returns false if the trader was not paused
+func (at *AutoTrader) Resume(resumedBy string) bool {
+	at.pauseMu.Lock()
+	if at.pausedAt.IsZero() {
+		at.pauseMu.Unlock()
+		return false
+	}
+	pausedFor := time.Since(at.pausedAt)
+	at.pausedAt, at.pausedBy, at.pauseReason = time.Time{}, "", ""
+	at.pauseMu.Unlock()
+	at.saveRuntimeState(store.CyclePhaseIdle, nil)
+
+	logger.Infof("▶️ [%s] Resumed by %s after %s", at.name, resumedBy, pausedFor.Round(time.Second))
+	return true
+}
+
+// pauseReasonIfPaused pause reason, "" if the trader is not paused
+func (at *AutoTrader) pauseReasonIfPaused() string {
+	at.pauseMu.Lock()
+	defer at.pauseMu.Unlock()
+	if at.pausedAt.IsZero() {
+		return ""
+	}
+	return at.pauseReason
+}
+
+// IsPaused whether new opens are blocked by an operator pause
+func (at *AutoTrader) IsPaused() bool {
+	return at.pauseReasonIfPaused() != ""
+}
+
+// GetPauseStatus gets the operator pause state
+func (at *AutoTrader) GetPauseStatus() PauseStatus {
+	at.pauseMu.Lock()
+	defer at.pauseMu.Unlock()
+	if at.pausedAt.IsZero() {
+		return PauseStatus{}
+	}
+	pausedAt := at.pausedAt
+	return PauseStatus{Paused: true, PausedAt: &pausedAt, PausedBy: at.pausedBy, Reason: at.pauseReason}
+}
+
+// checkPaused rejects open decisions while the trader is paused
+func (at *AutoTrader) checkPaused(d *decision.Decision) error {
+	if d.Action != "open_long" && d.Action != "open_short" {
+		return nil
+	}
+	reason := at.pauseReasonIfPaused()
+	if reason == "" {
+		return nil
+	}
+	logger.Infof("⏸ [%s] Paused, rejected %s %s", at.name, d.Action, d.Symbol)
+	return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("%w (%s), no new opens", ErrTraderPaused, reason))
+}
@@ -0,0 +1,62 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestPauseResume tests that a pause blocks opens only and survives restarts until resumed
+func TestPauseResume(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	newTrader := func() *AutoTrader {
+		return &AutoTrader{id: "trader-1", name: "test", store: st}
+	}
+	at := newTrader()
+	open := &decision.Decision{Symbol: "AAPL", Action: "open_long"}
+	closing := &decision.Decision{Symbol: "AAPL", Action: "close_long"}
+
+	if err := at.checkPaused(open); err != nil {
+		t.Fatalf("open rejected before pause: %v", err)
+	}
+	if !at.Pause("test", "") {
+		t.Fatal("expected pause")
+	}
+	if at.Pause("test", "again") {
+		t.Error("expected second pause to report already paused")
+	}
+	err = at.checkPaused(open)
+	if !errors.Is(err, ErrTraderPaused) || store.ErrorCategoryOf(err) != store.ErrorCategoryRiskBlock {
+		t.Fatalf("expected risk-blocked ErrTraderPaused, got %v", err)
+	}
+	if err := at.checkPaused(closing); err != nil {
+		t.Errorf("close rejected while paused: %v", err)
+	}
+	if status := at.GetStatus()["pause"].(PauseStatus); !status.Paused || status.Reason != defaultPauseReason || status.PausedBy != "test" {
+		t.Errorf("unexpected pause status %+v", status)
+	}
+
+	// Restarted trader: the pause is restored
+	at = newTrader()
+	at.restoreRuntimeState()
+	if !at.IsPaused() {
+		t.Fatal("expected pause restored")
+	}
+
+	if !at.Resume("test") {
+		t.Fatal("expected resume")
+	}
+	if at.Resume("test") {
+		t.Error("expected second resume to report not paused")
+	}
+	at = newTrader()
+	at.restoreRuntimeState()
+	if at.IsPaused() || at.checkPaused(open) != nil {
+		t.Error("expected resume to be persisted")
+	}
+}
@@ -0,0 +1,176 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// In-Flight Position Recovery
+// ============================================================================
+// Peaks, holding times and SL/TP prices of open positions live in memory, so a
+// crash or restart used to forget them: the drawdown rule restarted from the
+// current P&L and protective orders cancelled or lost in the meantime stayed
+// missing. On startup the trader now reads its positions from the exchange,
+// rebinds those with an open position record (entry time, peak P&L and SL/TP
+// prices stored by SetPositionTPSL / UpdatePeakPnL) and re-places protective
+// orders that no longer exist. Tracked orders are checked by ID; exchanges
+// without order IDs get their SL/TP re-asserted (symbol-wide replace), unless
+// both sides of the symbol are open: the replace would cancel the other
+// side's orders, so those rely on the cached prices (safekeeping enforcement).
+
+// inactiveOrderStatuses order statuses of protective orders that no longer protect the position
+var inactiveOrderStatuses = map[string]bool{
+	"CANCELED":  true,
+	"CANCELLED": true,
+	"EXPIRED":   true,
+	"REJECTED":  true,
+	"FILLED":    true,
+}
+
+// recoverOpenPositions rebinds open exchange positions to their records and restores protection
+func (at *AutoTrader) recoverOpenPositions() {
+	if at.store == nil || at.trader == nil {
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Position recovery skipped: failed to get positions: %v", at.name, err)
+		return
+	}
+
+	sidesOpen := make(map[string]int)
+	for _, pos := range positions {
+		symbol := pos.Symbol
+		if quantity := pos.Qty; quantity != 0 {
+			sidesOpen[symbol]++
+		}
+	}
+
+	var recovered, replaced int
+	for _, pos := range positions {
+		symbol := pos.Symbol
+		side := pos.Side
+		side = strings.ToLower(side)
+		quantity := pos.Qty
+		if symbol == "" || quantity == 0 {
+			continue
+		}
+
+		record, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+		if err != nil || record == nil {
+			continue // Not owned by this trader (position sync picks it up)
+		}
+		state, err := at.store.Position().GetPositionRecovery(record.ID)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Failed to load recovery state of %s %s: %v", at.name, symbol, side, err)
+			continue
+		}
+		replaced += at.recoverPosition(symbol, side, quantity, sidesOpen[symbol] > 1, record, state)
+		recovered++
+	}
+
+	if recovered > 0 {
+		note := fmt.Sprintf("♻️ Recovered %d open positions after restart, %d protective orders re-placed", recovered, replaced)
+		logger.Infof("♻️ [%s] Recovered %d open positions, %d protective orders re-placed", at.name, recovered, replaced)
+		if at.resumeNote != "" {
+			note = at.resumeNote + "; " + note
+		}
+		at.resumeNote = note
+	}
+}
+
+// recoverPosition restores the in-memory state of one position and re-places missing SL/TP orders
+// hedged: the other side of the symbol is open as well. Returns the number of protective orders placed.
+func (at *AutoTrader) recoverPosition(symbol, side string, quantity float64, hedged bool, record *store.TraderPosition, state *store.PositionRecovery) int {
+	posKey := symbol + "_" + side
+	if !record.EntryTime.IsZero() {
+		at.positionFirstSeenTime[posKey] = record.EntryTime.UnixMilli()
+	}
+	if state.HasPeak {
+		at.peakPnLCacheMutex.Lock()
+		at.peakPnLCache[posKey] = state.PeakPnLPct
+		at.peakPnLCacheMutex.Unlock()
+	}
+	if state.StopLoss <= 0 && state.TakeProfit <= 0 {
+		logger.Warnf("⚠️ [%s] %s %s recovered without stored SL/TP prices, protective orders left unchanged", at.name, symbol, side)
+		return 0
+	}
+	at.positionTPSLMutex.Lock()
+	at.positionTPSL[posKey] = [2]float64{state.TakeProfit, state.StopLoss}
+	at.positionTPSLMutex.Unlock()
+
+	_, trackable := at.trader.(ProtectiveOrderTrader)
+	if !trackable && hedged {
+		logger.Warnf("⚠️ [%s] %s %s: SL/TP not re-asserted (both sides open, exchange has no order IDs), cached prices are enforced", at.name, symbol, side)
+		return 0
+	}
+	tracked := at.protectiveOrders(symbol, side)
+	placed := 0
+	for _, kind := range []string{protectiveStopLoss, protectiveTakeProfit} {
+		price := state.StopLoss
+		if kind == protectiveTakeProfit {
+			price = state.TakeProfit
+		}
+		if price <= 0 {
+			continue
+		}
+
+		orderID := ""
+		if tracked != nil {
+			orderID = *trackedOrderID(tracked, kind)
+		}
+		if trackable && orderID != "" && at.protectiveOrderActive(symbol, orderID) {
+			continue
+		}
+
+		// Without order IDs the existing order is unknown: replace symbol-wide instead of duplicating it
+		var err error
+		if kind == protectiveStopLoss {
+			err = at.setStopLoss(symbol, side, quantity, price, !trackable)
+		} else {
+			err = at.setTakeProfit(symbol, side, quantity, price, !trackable)
+		}
+		if err != nil {
+			logger.Errorf("❌ [%s] Failed to re-place %s of %s %s at %.4f: %v", at.name, kind, symbol, side, price, err)
+			continue
+		}
+		logger.Infof("🛡️ [%s] Re-placed %s of %s %s at %.4f", at.name, kind, symbol, side, price)
+		placed++
+	}
+	return placed
+}
+
+// protectiveOrderActive whether a tracked protective order is still live on the exchange
+// Unknown status (query failed) counts as active: re-placing could duplicate the order.
+func (at *AutoTrader) protectiveOrderActive(symbol, orderID string) bool {
+	status, err := at.trader.GetOrderStatus(symbol, orderID)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Could not verify protective order %s of %s, assuming it is live: %v", at.name, orderID, symbol, err)
+		return true
+	}
+	s, _ := status["status"].(string)
+	return !inactiveOrderStatuses[strings.ToUpper(s)]
+}
+
+// persistPositionTPSL stores SL/TP prices on the open position record (no-op without store)
+func (at *AutoTrader) persistPositionTPSL(symbol, side string, takeProfit, stopLoss float64) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.Position().SetProtectivePrices(at.id, symbol, side, stopLoss, takeProfit); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to store SL/TP prices of %s %s: %v", at.name, symbol, side, err)
+	}
+}
+
+// persistPeakPnL stores a new peak P&L on the open position record (no-op without store)
+func (at *AutoTrader) persistPeakPnL(symbol, side string, pnlPct float64) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.Position().UpdatePeakPnL(at.id, symbol, side, pnlPct); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to store peak P&L of %s %s: %v", at.name, symbol, side, err)
+	}
+}
@@ -0,0 +1,75 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recoveryMock exchange with open positions and order statuses
+type recoveryMock struct {
+	protectiveOrderMock
+	positions []Position
+	statuses  map[string]string // order ID -> status
+}
+
+func (m *recoveryMock) GetPositions() ([]Position, error) {
+	return m.positions, nil
+}
+
+func (m *recoveryMock) GetOrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": m.statuses[orderID]}, nil
+}
+
+// TestRecoverOpenPositions tests that peaks, entry time and SL/TP survive a restart and missing orders are re-placed
+func TestRecoverOpenPositions(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	entryTime := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	pos := &store.TraderPosition{TraderID: "trader-1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: entryTime}
+	if err := st.Position().Create(pos); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// First run: SL/TP placed and a peak recorded
+	mock := &recoveryMock{statuses: map[string]string{}}
+	before := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st,
+		positionTPSL: make(map[string][2]float64), peakPnLCache: make(map[string]float64)}
+	before.setStopLoss("BTCUSDT", "long", 1, 95, false)
+	before.setTakeProfit("BTCUSDT", "long", 1, 120, false)
+	before.SetPositionTPSL("BTCUSDT", "long", 120, 95)
+	before.UpdatePeakPnL("BTCUSDT", "long", 8)
+	before.UpdatePeakPnL("BTCUSDT", "long", 3)
+
+	// Restart: the stop loss was cancelled meanwhile, the take profit is still live
+	mock.statuses["sl-1"] = "CANCELED"
+	mock.statuses["tp-2"] = "NEW"
+	mock.positions = []Position{{Symbol: "BTCUSDT", Side: "long", Qty: 1}}
+	at := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st, positionTPSL: make(map[string][2]float64),
+		peakPnLCache: make(map[string]float64), positionFirstSeenTime: make(map[string]int64)}
+	at.recoverOpenPositions()
+
+	if peak := at.GetPeakPnLCache()["BTCUSDT_long"]; peak != 8 {
+		t.Errorf("expected peak 8 restored, got %.2f", peak)
+	}
+	if first := at.positionFirstSeenTime["BTCUSDT_long"]; first != entryTime.UnixMilli() {
+		t.Errorf("expected first seen time from entry time, got %d", first)
+	}
+	if tp, sl, ok := at.GetPositionTPSL("BTCUSDT", "long"); !ok || tp != 120 || sl != 95 {
+		t.Errorf("expected cached TP/SL 120/95, got %.2f/%.2f (%v)", tp, sl, ok)
+	}
+	if got := strings.Join(mock.placed, ","); got != "sl-1,tp-2,sl-3" {
+		t.Errorf("expected only the cancelled stop loss re-placed, placed %s", got)
+	}
+	orders, _ := st.Position().GetProtectiveOrders(pos.ID)
+	if orders.StopLossOrderID != "sl-3" || orders.TakeProfitOrderID != "tp-2" {
+		t.Errorf("tracked orders = %+v", orders)
+	}
+	if !strings.Contains(at.resumeNote, "1 protective orders re-placed") {
+		t.Errorf("expected recovery note, got %q", at.resumeNote)
+	}
+}
@@ -0,0 +1,273 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ============================================================================
+// Real-time Position Stream
+// ============================================================================
+// The drawdown monitor polls GetPositions every DrawdownCheckInterval, which
+// misses fast moves. With RiskControl.DrawdownRealtime, traders implementing
+// PositionStreamer (Binance and Bybit user streams, Alpaca trade updates)
+// push fills, position changes and mark prices over the exchange websocket;
+// each update refreshes the peak P&L cache and runs the drawdown rule of the
+// position at most once per positionStreamCheckInterval. Leverage comes from
+// the interval poll, so a position is checked in real time once the poll has
+// seen it. The stream reconnects with backoff and the poll keeps running as
+// backstop.
+
+const (
+	positionStreamBuffer        = 256
+	positionStreamCheckInterval = time.Second      // Min time between drawdown checks of one position
+	positionStreamMinBackoff    = time.Second      // First reconnect delay
+	positionStreamMaxBackoff    = time.Minute      // Reconnect delay cap
+	positionStreamConfigCheck   = 30 * time.Second // DrawdownRealtime hot-reload check
+	streamPingInterval          = 20 * time.Second
+	streamReadTimeout           = 90 * time.Second // Connection considered dead without any message or pong
+)
+
+// PositionUpdate real-time position change pushed by an exchange stream
+type PositionUpdate struct {
+	Symbol     string
+	Side       string  // long/short ("" = mark price of Symbol for both sides)
+	MarkPrice  float64 // Mark or last fill price (0 = not included)
+	EntryPrice float64 // Snapshot only (0 = unchanged)
+	Quantity   float64 // Snapshot only, absolute size (0 = position closed)
+	Snapshot   bool    // Position changed (fill, account update): Side and Quantity are set
+}
+
+// PositionStreamer optional trader capability: real-time position updates over the exchange websocket
+type PositionStreamer interface {
+	// StreamPositions pushes updates until stop is closed (returns nil) or the connection fails
+	StreamPositions(stop <-chan struct{}, updates chan<- PositionUpdate) error
+}
+
+// streamPosition last known state of an open position
+type streamPosition struct {
+	entryPrice float64
+	quantity   float64
+	leverage   int // 0 = not yet seen by the interval poll
+	markPrice  float64
+	checkedAt  time.Time
+}
+
+// pushPositionUpdate sends update to the runner; mark prices are dropped when the runner is behind
+func pushPositionUpdate(updates chan<- PositionUpdate, stop <-chan struct{}, u PositionUpdate) {
+	if !u.Snapshot {
+		select {
+		case updates <- u:
+		default:
+		}
+		return
+	}
+	select {
+	case updates <- u:
+	case <-stop:
+	}
+}
+
+// startPositionStream runs the exchange position stream while DrawdownRealtime is enabled
+func (at *AutoTrader) startPositionStream() {
+	streamer, ok := at.trader.(PositionStreamer)
+	if !ok {
+		return
+	}
+	at.monitorWg.Add(1)
+	go func() {
+		defer at.monitorWg.Done()
+		backoff := positionStreamMinBackoff
+		for {
+			wait := positionStreamConfigCheck
+			if at.drawdownRiskConfig().DrawdownRealtime {
+				logger.Infof("📡 [%s] Starting real-time position stream", at.name)
+				started := time.Now()
+				stopped, err := at.runPositionStream(streamer)
+				if stopped {
+					return
+				}
+				if err != nil {
+					if time.Since(started) > positionStreamMaxBackoff {
+						backoff = positionStreamMinBackoff // Was connected for a while, reconnect quickly
+					}
+					logger.Warnf("⚠️ [%s] Position stream disconnected: %v — reconnecting in %v", at.name, err, backoff)
+					wait = backoff
+					backoff = min(backoff*2, positionStreamMaxBackoff)
+				}
+			}
+			select {
+			case <-time.After(wait):
+			case <-at.stopMonitorCh:
+				return
+			}
+		}
+	}()
+}
+
+// runPositionStream applies stream updates until the trader stops (stopped = true),
+// DrawdownRealtime is disabled (nil error) or the connection fails
+func (at *AutoTrader) runPositionStream(streamer PositionStreamer) (stopped bool, err error) {
+	stop := make(chan struct{})
+	defer close(stop)
+	updates := make(chan PositionUpdate, positionStreamBuffer)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- streamer.StreamPositions(stop, updates)
+	}()
+
+	configTicker := time.NewTicker(positionStreamConfigCheck)
+	defer configTicker.Stop()
+	for {
+		select {
+		case u := <-updates:
+			at.applyPositionUpdate(u)
+		case err := <-errC:
+			return false, err
+		case <-configTicker.C:
+			if !at.drawdownRiskConfig().DrawdownRealtime {
+				logger.Infof("⏹ [%s] Real-time position stream disabled", at.name)
+				return false, nil
+			}
+		case <-at.stopMonitorCh:
+			return true, nil
+		}
+	}
+}
+
+// applyPositionUpdate updates the tracked position(s) and runs their drawdown check
+func (at *AutoTrader) applyPositionUpdate(u PositionUpdate) {
+	if u.Snapshot {
+		if u.Quantity <= 0 {
+			if at.untrackStreamPosition(u.Symbol, u.Side) {
+				logger.Infof("📡 [%s] %s %s closed (stream)", at.name, u.Symbol, u.Side)
+				at.ClearPeakPnLCache(u.Symbol, u.Side)
+			}
+			return
+		}
+		at.trackStreamPosition(u.Symbol, u.Side, u.EntryPrice, u.Quantity, 0)
+	}
+
+	sides := []string{u.Side}
+	if u.Side == "" {
+		sides = []string{"long", "short"}
+	}
+	riskConfig := at.drawdownRiskConfig()
+	for _, side := range sides {
+		at.streamMu.Lock()
+		pos := at.streamPositions[u.Symbol+"_"+side]
+		if pos == nil {
+			at.streamMu.Unlock()
+			continue
+		}
+		if u.MarkPrice > 0 {
+			pos.markPrice = u.MarkPrice
+		}
+		due := pos.leverage > 0 && pos.markPrice > 0 && time.Since(pos.checkedAt) >= positionStreamCheckInterval
+		if due {
+			pos.checkedAt = time.Now()
+		}
+		entryPrice, markPrice, leverage := pos.entryPrice, pos.markPrice, pos.leverage
+		at.streamMu.Unlock()
+
+		if due {
+			at.trackExcursion(u.Symbol, side, entryPrice, markPrice)
+			at.evaluateDrawdown(u.Symbol, side, entryPrice, markPrice, leverage, riskConfig, false)
+		}
+	}
+}
+
+// trackStreamPosition records an open position (zero entry price / leverage = keep known value)
+func (at *AutoTrader) trackStreamPosition(symbol, side string, entryPrice, quantity float64, leverage int) {
+	at.streamMu.Lock()
+	defer at.streamMu.Unlock()
+	if at.streamPositions == nil {
+		at.streamPositions = make(map[string]*streamPosition)
+	}
+	key := symbol + "_" + side
+	pos := at.streamPositions[key]
+	if pos == nil {
+		pos = &streamPosition{}
+		at.streamPositions[key] = pos
+	}
+	if entryPrice > 0 {
+		pos.entryPrice = entryPrice
+	}
+	if leverage > 0 {
+		pos.leverage = leverage
+	}
+	pos.quantity = quantity
+}
+
+// untrackStreamPosition forgets a closed position (false = was not tracked)
+func (at *AutoTrader) untrackStreamPosition(symbol, side string) bool {
+	at.streamMu.Lock()
+	defer at.streamMu.Unlock()
+	key := symbol + "_" + side
+	if _, ok := at.streamPositions[key]; !ok {
+		return false
+	}
+	delete(at.streamPositions, key)
+	return true
+}
+
+// pruneStreamPositions forgets positions the interval poll no longer reports
+func (at *AutoTrader) pruneStreamPositions(openKeys map[string]bool) {
+	at.streamMu.Lock()
+	defer at.streamMu.Unlock()
+	for key := range at.streamPositions {
+		if !openKeys[key] {
+			delete(at.streamPositions, key)
+		}
+	}
+}
+
+// streamConn websocket connection of a position stream with serialized writes and a read deadline
+type streamConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex // gorilla/websocket allows one concurrent writer
+}
+
+// dialStreamConn connects to url; any message or pong extends the read deadline
+func dialStreamConn(url string) (*streamConn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	})
+	return &streamConn{conn: conn}, nil
+}
+
+// writeJSON sends a JSON message
+func (c *streamConn) writeJSON(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+// ping sends a websocket ping frame
+func (c *streamConn) ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+}
+
+// read next message, extending the read deadline
+func (c *streamConn) read() ([]byte, error) {
+	_, message, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	c.conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	return message, nil
+}
+
+func (c *streamConn) Close() {
+	c.conn.Close()
+}
@@ -0,0 +1,62 @@
+package trader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlpacaFillUpdate(t *testing.T) {
+	var msg alpacaStreamMessage
+	msg.Data.Event = "fill"
+	msg.Data.Order.Symbol = "AAPL"
+	msg.Data.Order.Side = "buy"
+	msg.Data.Price = "190.5"
+	msg.Data.Qty = "10"
+	msg.Data.PositionQty = "10"
+
+	u := alpacaFillUpdate(msg)
+	if u.Side != "long" || u.Quantity != 10 || u.EntryPrice != 190.5 || u.MarkPrice != 190.5 || !u.Snapshot {
+		t.Errorf("opening fill = %+v", u)
+	}
+
+	// Buy to cover closes a short
+	msg.Data.PositionQty = "0"
+	if u := alpacaFillUpdate(msg); u.Side != "short" || u.Quantity != 0 || u.EntryPrice != 0 {
+		t.Errorf("closing fill = %+v", u)
+	}
+}
+
+func TestApplyPositionUpdate(t *testing.T) {
+	at := &AutoTrader{peakPnLCache: make(map[string]float64)}
+
+	// Not yet polled (leverage unknown): tracked but not checked
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100, Quantity: 1, Snapshot: true})
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", MarkPrice: 110})
+	if _, ok := at.GetPeakPnLCache()["BTCUSDT_long"]; ok {
+		t.Fatal("position without leverage should not be checked")
+	}
+
+	// Poll supplies leverage, next mark price updates the peak
+	at.trackStreamPosition("BTCUSDT", "long", 100, 1, 2)
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", MarkPrice: 101})
+	if peak := at.GetPeakPnLCache()["BTCUSDT_long"]; peak != 2 {
+		t.Errorf("peak = %.2f%%, want 2%% (1%% × 2x)", peak)
+	}
+
+	// Updates within the check interval only refresh the mark
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", MarkPrice: 102})
+	if peak := at.GetPeakPnLCache()["BTCUSDT_long"]; peak != 2 {
+		t.Errorf("peak = %.2f%%, want unchanged within %v", peak, positionStreamCheckInterval)
+	}
+	at.streamPositions["BTCUSDT_long"].checkedAt = time.Now().Add(-positionStreamCheckInterval)
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", MarkPrice: 102})
+	if peak := at.GetPeakPnLCache()["BTCUSDT_long"]; peak != 4 {
+		t.Errorf("peak = %.2f%%, want 4%%", peak)
+	}
+
+	// Close snapshot forgets the position and its peak
+	at.applyPositionUpdate(PositionUpdate{Symbol: "BTCUSDT", Side: "long", Snapshot: true})
+	if _, ok := at.GetPeakPnLCache()["BTCUSDT_long"]; ok || len(at.streamPositions) != 0 {
+		t.Error("closed position should be untracked")
+	}
+}
@@ -2,6 +2,7 @@ package trader
 
 import (
 	"SynapseStrike/logger"
+	"SynapseStrike/notify"
 	"SynapseStrike/store"
 	"fmt"
 	"strings"
@@ -120,6 +121,9 @@ func (m *PositionSyncManager) syncTraderPositions(traderID string, localPosition
 
 	// Get exchange info for history sync
 	config, _ := m.getTraderConfig(traderID)
+	if config != nil && config.Trader != nil && config.Trader.ShadowMode {
+		return // Shadow traders use virtual ledger, exchange positions are not theirs
+	}
 	exchangeID := ""
 	exchangeType := ""
 	if config != nil {
@@ -141,10 +145,10 @@ func (m *PositionSyncManager) syncTraderPositions(traderID string, localPosition
 
 	// Build exchange position map: symbol_side -> position
 	// Note: Exchange returns side as "long"/"short" (lowercase), database stores "LONG"/"SHORT" (uppercase)
-	exchangeMap := make(map[string]map[string]interface{})
+	exchangeMap := make(map[string]Position)
 	for _, pos := range exchangePositions {
-		symbol, _ := pos["symbol"].(string)
-		side, _ := pos["side"].(string) // Note: use "side" not "positionSide"
+		symbol := pos.Symbol
+		side := pos.Side // Note: use "side" not "positionSide"
 		if symbol == "" || side == "" {
 			continue
 		}
@@ -166,14 +170,14 @@ func (m *PositionSyncManager) syncTraderPositions(traderID string, localPosition
 		}
 
 		// Check if quantity is 0 or very small
-		qty := getFloatFromMap(exchangePos, "positionAmt")
-		if qty < 0 {
-			qty = -qty // Short position quantity is negative
-		}
+		qty := exchangePos.Qty
 
 		if qty < 0.0000001 {
 			// Quantity is 0, position closed
 			m.closeLocalPosition(localPos, trader, "manual")
+		} else if qty < localPos.Quantity*0.999 {
+			// Reduced on the exchange: scaled take profit levels may have filled
+			m.recordTakeProfitLevelFills(localPos, qty, exchangeType)
 		}
 	}
 }
@@ -213,6 +217,13 @@ func (m *PositionSyncManager) closeLocalPosition(pos *store.TraderPosition, trad
 		logger.Infof("⚠️  Using market price for closure (no exchange data): %s %s", pos.Symbol, pos.Side)
 	}
 
+	// Cancel the still-live sibling of a triggered SL/TP (e.g. the take profit after the stop loss filled)
+	if pot, ok := trader.(ProtectiveOrderTrader); ok {
+		if orders, err := m.store.Position().GetProtectiveOrders(pos.ID); err == nil {
+			cancelTrackedOrders(pot, pos.Symbol, orders)
+		}
+	}
+
 	// Update database
 	err := m.store.Position().ClosePosition(
 		pos.ID,
@@ -228,9 +239,31 @@ func (m *PositionSyncManager) closeLocalPosition(pos *store.TraderPosition, trad
 	} else {
 		logger.Infof("📊 Position closed [%s] %s %s @ %.4f → %.4f, PnL: %.2f, Fee: %.4f (%s)",
 			pos.TraderID[:8], pos.Symbol, pos.Side, pos.EntryPrice, exitPrice, realizedPnL, fee, closeReason)
+		m.notifyExternalClose(pos, closeReason, exitPrice, realizedPnL)
 	}
 }
 
+// notifyExternalClose Notify about position closed outside the trading loop (SL/TP trigger, manual close)
+func (m *PositionSyncManager) notifyExternalClose(pos *store.TraderPosition, closeReason string, exitPrice, realizedPnL float64) {
+	traderName := pos.TraderID
+	if config, err := m.getTraderConfig(pos.TraderID); err == nil && config.Trader != nil {
+		traderName = config.Trader.Name
+	}
+	notify.Publish(notify.Event{
+		Type:       closeEventType(closeReason),
+		TraderID:   pos.TraderID,
+		TraderName: traderName,
+		Symbol:     pos.Symbol,
+		Title:      fmt.Sprintf("%s Closed %s %s", pnlEmoji(realizedPnL), pos.Side, pos.Symbol),
+		Fields: map[string]string{
+			"Entry":  fmt.Sprintf("%.4f", pos.EntryPrice),
+			"Exit":   fmt.Sprintf("%.4f", exitPrice),
+			"P&L":    fmt.Sprintf("%+.2f", realizedPnL),
+			"Reason": closeReason,
+		},
+	})
+}
+
 // findClosedPnLRecord Try to find matching ClosedPnL record from exchange
 // For Binance, directly query trades for the specific symbol (more reliable than Income API)
 func (m *PositionSyncManager) findClosedPnLRecord(trader Trader, pos *store.TraderPosition) *ClosedPnLRecord {
@@ -492,10 +525,17 @@ func (m *PositionSyncManager) createTrader(config *store.TraderFullConfig) (Trad
 	// Use exchange.ExchangeType to determine specific exchange, not exchange.ID (UUID) or exchange.Type (cex/dex)
 	switch exchange.ExchangeType {
 	case "binance":
-		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, config.Trader.UserID), nil
+		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, config.Trader.UserID, exchange.Testnet), nil
+
+	case "binance-spot":
+		return NewBinanceSpotTrader(exchange.APIKey, exchange.SecretKey,
+			ResolveQuoteCurrency(exchange.ExchangeType, exchange.QuoteCurrency), exchange.Testnet), nil
+
+	case "coinbase":
+		return NewCoinbaseSpotTrader(exchange.APIKey, exchange.SecretKey, ResolveQuoteCurrency(exchange.ExchangeType, exchange.QuoteCurrency))
 
 	case "bybit":
-		return NewBybitTrader(exchange.APIKey, exchange.SecretKey), nil
+		return NewBybitTrader(exchange.APIKey, exchange.SecretKey, exchange.Testnet), nil
 
 	case "okx":
 		return NewOKXTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase), nil
@@ -521,6 +561,16 @@ func (m *PositionSyncManager) createTrader(config *store.TraderFullConfig) (Trad
 			false, // Always use mainnet for Lighter
 		)
 
+	case "dydx":
+		if exchange.DydxMnemonic == "" {
+			return nil, fmt.Errorf("dYdX requires wallet mnemonic")
+		}
+		return NewDydxTrader(exchange.DydxMnemonic, exchange.DydxSubaccount, exchange.Testnet)
+
+	case "ccxt":
+		return NewCCXTBridgeTrader(exchange.CCXTGatewayURL, exchange.CCXTExchangeID, exchange.APIKey, exchange.SecretKey, exchange.Passphrase,
+			ResolveQuoteCurrency(exchange.ExchangeType, exchange.QuoteCurrency))
+
 	case "alpaca", "alpaca-live":
 		return NewAlpacaTrader(exchange.APIKey, exchange.SecretKey, false), nil
 
@@ -541,25 +591,6 @@ func (m *PositionSyncManager) InvalidateCache(traderID string) {
 	delete(m.configCache, traderID)
 }
 
-// getFloatFromMap Get float64 value from map
-func getFloatFromMap(m map[string]interface{}, key string) float64 {
-	if v, ok := m[key]; ok {
-		switch val := v.(type) {
-		case float64:
-			return val
-		case int64:
-			return float64(val)
-		case int:
-			return float64(val)
-		case string:
-			var f float64
-			fmt.Sscanf(val, "%f", &f)
-			return f
-		}
-	}
-	return 0
-}
-
 // =============================================================================
 // Startup and History Sync Methods
 // =============================================================================
@@ -582,6 +613,9 @@ func (m *PositionSyncManager) startupSync() {
 
 	for _, traderInfo := range traders {
 		traderID := traderInfo.ID
+		if traderInfo.ShadowMode {
+			continue // Shadow traders have no exchange state to sync
+		}
 
 		// Get trader instance
 		trader, err := m.getOrCreateTrader(traderID)
@@ -657,8 +691,8 @@ func (m *PositionSyncManager) syncExternalPositions(traderID, exchangeID, exchan
 
 	// Find positions that exist on exchange but not locally
 	for _, pos := range exchangePositions {
-		symbol, _ := pos["symbol"].(string)
-		side, _ := pos["side"].(string)
+		symbol := pos.Symbol
+		side := pos.Side
 		if symbol == "" || side == "" {
 			continue
 		}
@@ -685,26 +719,20 @@ func (m *PositionSyncManager) syncExternalPositions(traderID, exchangeID, exchan
 		}
 
 		// This is an external position - create local record
-		qty := getFloatFromMap(pos, "positionAmt")
-		if qty < 0 {
-			qty = -qty
-		}
+		qty := pos.Qty
 		if qty < 0.0000001 {
 			continue // No actual position
 		}
 
-		entryPrice := getFloatFromMap(pos, "entryPrice")
-		leverage := int(getFloatFromMap(pos, "leverage"))
+		entryPrice := pos.EntryPrice
+		leverage := int(pos.Leverage)
 		if leverage == 0 {
 			leverage = 1
 		}
 
 		// Get entry time if available
-		createdTime := getFloatFromMap(pos, "createdTime")
-		var entryTime time.Time
-		if createdTime > 0 {
-			entryTime = time.UnixMilli(int64(createdTime))
-		} else {
+		entryTime := pos.CreatedAt
+		if entryTime.IsZero() {
 			entryTime = time.Now() // Use current time as fallback
 		}
 
@@ -0,0 +1,77 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// Venue Price Guard
+// ============================================================================
+// The AI decides on market data of the data source (Binance klines, Alpaca
+// bars) while orders may go to another venue (Bybit, Hyperliquid, a DEX)
+// whose price can differ, or the data may simply have aged during a long AI
+// call. Before an open is executed the execution venue's current price is
+// compared to the price of the decision context; beyond
+// Execution.PriceGuardBps the open is either rejected or repriced: entry,
+// stop loss and take profit levels are shifted by the venue/context price
+// ratio so the distances the AI chose are kept on the venue's price scale.
+
+// ErrVenuePriceDeviation an open was rejected because the execution venue's price deviates from the decision context
+var ErrVenuePriceDeviation = errors.New("venue price deviates from decision price")
+
+// checkVenuePrice guards an open decision against a deviating execution venue price
+// ctx is nil for decisions replayed from the execution queue, which are not checked (no context price).
+func (at *AutoTrader) checkVenuePrice(ctx *decision.Context, d *decision.Decision, record *store.DecisionRecord) error {
+	if d.Action != "open_long" && d.Action != "open_short" || ctx == nil {
+		return nil
+	}
+	cfg := at.engine().GetConfig().Execution
+	if cfg.PriceGuardBps <= 0 {
+		return nil
+	}
+	data := ctx.MarketDataMap[d.Symbol]
+	if data == nil || data.CurrentPrice <= 0 {
+		return nil
+	}
+	venuePrice, err := at.trader.GetMarketPrice(d.Symbol)
+	if err != nil || venuePrice <= 0 {
+		logger.Warnf("⚠️ [%s] Price guard skipped for %s: venue price unavailable: %v", at.name, d.Symbol, err)
+		return nil
+	}
+
+	deviation := priceDeviationBps(data.CurrentPrice, venuePrice)
+	if math.Abs(deviation) <= cfg.PriceGuardBps {
+		return nil
+	}
+	detail := fmt.Sprintf("%s venue price %.6g vs decision price %.6g (%+.1f bps, limit %.1f bps)",
+		d.Symbol, venuePrice, data.CurrentPrice, deviation, cfg.PriceGuardBps)
+
+	if cfg.PriceGuardAction != store.PriceGuardReprice {
+		logger.Warnf("⚖️ [%s] Price guard rejected %s: %s", at.name, d.Action, detail)
+		return store.WithErrorCategory(store.ErrorCategoryRiskBlock, fmt.Errorf("%w: %s", ErrVenuePriceDeviation, detail))
+	}
+	repriceDecision(d, venuePrice/data.CurrentPrice)
+	logger.Infof("⚖️ [%s] Price guard repriced %s: %s → SL %.6g, TP %.6g", at.name, d.Action, detail, d.StopLoss, d.TakeProfit)
+	record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⚖️ %s %s repriced to the venue: %s", d.Symbol, d.Action, detail))
+	return nil
+}
+
+// priceDeviationBps deviation of the venue price from the reference price in basis points
+func priceDeviationBps(reference, venue float64) float64 {
+	return (venue - reference) / reference * 10000
+}
+
+// repriceDecision scales the price levels of an open decision by ratio (venue price / decision price)
+func repriceDecision(d *decision.Decision, ratio float64) {
+	d.EntryPrice *= ratio
+	d.StopLoss *= ratio
+	d.TakeProfit *= ratio
+	for i := range d.TakeProfitLevels {
+		d.TakeProfitLevels[i].Price *= ratio
+	}
+}
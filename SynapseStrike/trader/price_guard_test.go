@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestCheckVenuePrice tests rejecting and repricing opens on a venue price deviating from the decision context
+func TestCheckVenuePrice(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Execution.PriceGuardBps = 50
+	mock := &benchmarkPriceMock{price: 100.3}
+	at := &AutoTrader{id: "t1", name: "test", trader: mock, strategyEngine: decision.NewStrategyEngine(&cfg)}
+	ctx := &decision.Context{MarketDataMap: map[string]*market.Data{"AAPL": {Symbol: "AAPL", CurrentPrice: 100}}}
+	record := &store.DecisionRecord{}
+	newOpen := func() *decision.Decision {
+		return &decision.Decision{Symbol: "AAPL", Action: "open_long", StopLoss: 95, TakeProfit: 110,
+			TakeProfitLevels: []decision.TakeProfitLevel{{Price: 105, PctOfPosition: 50}}}
+	}
+
+	// 30 bps is within the limit
+	if err := at.checkVenuePrice(ctx, newOpen(), record); err != nil {
+		t.Fatalf("deviation within limit rejected: %v", err)
+	}
+
+	// 100 bps is rejected
+	mock.price = 101
+	err := at.checkVenuePrice(ctx, newOpen(), record)
+	if !errors.Is(err, ErrVenuePriceDeviation) || store.ErrorCategoryOf(err) != store.ErrorCategoryRiskBlock {
+		t.Fatalf("expected risk-blocked ErrVenuePriceDeviation, got %v", err)
+	}
+
+	// Closes and replayed decisions (no context) are never guarded
+	if err := at.checkVenuePrice(ctx, &decision.Decision{Symbol: "AAPL", Action: "close_long"}, record); err != nil {
+		t.Errorf("close guarded: %v", err)
+	}
+	if err := at.checkVenuePrice(nil, newOpen(), record); err != nil {
+		t.Errorf("replayed decision guarded: %v", err)
+	}
+
+	// Reprice shifts all price levels by the venue/context ratio
+	cfg.Execution.PriceGuardAction = store.PriceGuardReprice
+	at.strategyEngine = decision.NewStrategyEngine(&cfg)
+	d := newOpen()
+	if err := at.checkVenuePrice(ctx, d, record); err != nil {
+		t.Fatalf("reprice returned error: %v", err)
+	}
+	for name, got := range map[string][2]float64{
+		"stop_loss":   {d.StopLoss, 95.95},
+		"take_profit": {d.TakeProfit, 111.1},
+		"tp_level":    {d.TakeProfitLevels[0].Price, 106.05},
+		"entry_price": {d.EntryPrice, 0}, // Market entries stay market entries
+	} {
+		if math.Abs(got[0]-got[1]) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got[0], got[1])
+		}
+	}
+	if len(record.ExecutionLog) != 1 {
+		t.Errorf("expected one reprice log line, got %v", record.ExecutionLog)
+	}
+}
@@ -0,0 +1,164 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"strings"
+)
+
+// ============================================================================
+// Protective Order Tracking
+// ============================================================================
+// SL/TP orders placed for a position are tracked on its position record when
+// the exchange returns their order IDs (ProtectiveOrderTrader). Tracked orders
+// are cancelled by ID when the position is closed, partially closed or its
+// SL/TP is modified, and when the position sync finds the position closed on
+// the exchange (the sibling of a triggered SL/TP would otherwise stay live).
+// Exchanges without order IDs keep the symbol-wide cancel.
+
+const (
+	protectiveStopLoss   = "stop loss"
+	protectiveTakeProfit = "take profit"
+)
+
+// setStopLoss places the stop loss of a position (side: long/short), tracking its order ID
+// replace: the existing stop loss is being modified; without a tracked order ID it is cancelled symbol-wide.
+func (at *AutoTrader) setStopLoss(symbol, side string, quantity, stopPrice float64, replace bool) error {
+	return at.setProtectiveOrder(protectiveStopLoss, symbol, side, quantity, stopPrice, replace)
+}
+
+// setTakeProfit places the take profit of a position (side: long/short), tracking its order ID
+// replace: the existing take profit is being modified; without a tracked order ID it is cancelled symbol-wide.
+func (at *AutoTrader) setTakeProfit(symbol, side string, quantity, takeProfitPrice float64, replace bool) error {
+	return at.setProtectiveOrder(protectiveTakeProfit, symbol, side, quantity, takeProfitPrice, replace)
+}
+
+func (at *AutoTrader) setProtectiveOrder(kind, symbol, side string, quantity, price float64, replace bool) error {
+	positionSide := strings.ToUpper(side)
+	pot, trackable := at.trader.(ProtectiveOrderTrader)
+	tracked := at.protectiveOrders(symbol, side)
+
+	// Cancel the order being replaced
+	oldID := ""
+	if tracked != nil {
+		oldID = *trackedOrderID(tracked, kind)
+	}
+	// A new take profit replaces a whole scaled take profit, including its level orders
+	if trackable && replace && kind == protectiveTakeProfit && tracked != nil {
+		cancelTakeProfitLevels(pot, symbol, tracked)
+	}
+	if trackable && oldID != "" {
+		if err := pot.CancelProtectiveOrder(symbol, oldID); err != nil {
+			logger.Warnf("⚠️ [%s] Failed to cancel old %s order %s of %s %s: %v", at.name, kind, oldID, symbol, side, err)
+		}
+	} else if replace {
+		var err error
+		if kind == protectiveStopLoss {
+			err = at.trader.CancelStopLossOrders(symbol)
+		} else {
+			err = at.trader.CancelTakeProfitOrders(symbol)
+		}
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Failed to cancel old %s of %s: %v", at.name, kind, symbol, err)
+		}
+	}
+
+	if !trackable {
+		if kind == protectiveStopLoss {
+			return at.trader.SetStopLoss(symbol, positionSide, quantity, price)
+		}
+		return at.trader.SetTakeProfit(symbol, positionSide, quantity, price)
+	}
+
+	var orderID string
+	var err error
+	if kind == protectiveStopLoss {
+		orderID, err = pot.PlaceStopLoss(symbol, positionSide, quantity, price)
+	} else {
+		orderID, err = pot.PlaceTakeProfit(symbol, positionSide, quantity, price)
+	}
+	// Record the new order ID (empty if placing failed: the old order is cancelled either way)
+	if tracked != nil {
+		*trackedOrderID(tracked, kind) = orderID
+		if storeErr := at.store.Position().SetProtectiveOrders(*tracked); storeErr != nil {
+			logger.Warnf("⚠️ [%s] Failed to track %s order of %s %s: %v", at.name, kind, symbol, side, storeErr)
+		}
+	}
+	return err
+}
+
+// cancelProtectiveOrders cancels the tracked SL/TP orders of a position before it is closed or reduced
+// Returns true if tracked orders were cancelled (restore them with restoreProtectiveOrders if the close fails).
+func (at *AutoTrader) cancelProtectiveOrders(symbol, side string) bool {
+	pot, ok := at.trader.(ProtectiveOrderTrader)
+	if !ok {
+		return false
+	}
+	tracked := at.protectiveOrders(symbol, side)
+	if tracked == nil || (tracked.StopLossOrderID == "" && tracked.TakeProfitOrderID == "" && len(tracked.TakeProfitLevels) == 0) {
+		return false
+	}
+
+	cancelTrackedOrders(pot, symbol, tracked)
+	if err := at.store.Position().SetProtectiveOrders(store.ProtectiveOrders{PositionID: tracked.PositionID}); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to clear protective orders of %s %s: %v", at.name, symbol, side, err)
+	}
+	logger.Infof("  🧹 Canceled tracked SL/TP orders of %s %s", symbol, side)
+	return true
+}
+
+// restoreProtectiveOrders re-places cached SL/TP of a position whose close failed after its orders were cancelled
+func (at *AutoTrader) restoreProtectiveOrders(symbol, side string, quantity float64) {
+	takeProfit, stopLoss, exists := at.GetPositionTPSL(symbol, side)
+	if !exists || quantity <= 0 {
+		logger.Warnf("⚠️ [%s] %s %s left without SL/TP orders (no cached prices to restore)", at.name, symbol, side)
+		return
+	}
+	if stopLoss > 0 {
+		if err := at.setStopLoss(symbol, side, quantity, stopLoss, false); err != nil {
+			logger.Errorf("❌ [%s] Failed to restore stop loss of %s %s: %v", at.name, symbol, side, err)
+		}
+	}
+	if takeProfit > 0 {
+		if err := at.setTakeProfit(symbol, side, quantity, takeProfit, false); err != nil {
+			logger.Errorf("❌ [%s] Failed to restore take profit of %s %s: %v", at.name, symbol, side, err)
+		}
+	}
+}
+
+// protectiveOrders tracked SL/TP orders of this trader's open position (nil without a position record)
+func (at *AutoTrader) protectiveOrders(symbol, side string) *store.ProtectiveOrders {
+	if at.store == nil {
+		return nil
+	}
+	pos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+	if err != nil || pos == nil {
+		return nil
+	}
+	orders, err := at.store.Position().GetProtectiveOrders(pos.ID)
+	if err != nil {
+		return &store.ProtectiveOrders{PositionID: pos.ID}
+	}
+	return orders
+}
+
+// cancelTrackedOrders cancels tracked SL/TP orders by ID (failures are logged, the order may be filled already)
+func cancelTrackedOrders(pot ProtectiveOrderTrader, symbol string, orders *store.ProtectiveOrders) {
+	for _, orderID := range []string{orders.StopLossOrderID, orders.TakeProfitOrderID} {
+		if orderID == "" {
+			continue
+		}
+		if err := pot.CancelProtectiveOrder(symbol, orderID); err != nil {
+			logger.Warnf("⚠️ Failed to cancel protective order %s of %s (may be filled already): %v", orderID, symbol, err)
+		}
+	}
+	cancelTakeProfitLevels(pot, symbol, orders)
+}
+
+// trackedOrderID field holding the order ID of kind
+func trackedOrderID(orders *store.ProtectiveOrders, kind string) *string {
+	if kind == protectiveStopLoss {
+		return &orders.StopLossOrderID
+	}
+	return &orders.TakeProfitOrderID
+}
@@ -0,0 +1,70 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// protectiveOrderMock exchange returning SL/TP order IDs (other Trader methods are not used)
+type protectiveOrderMock struct {
+	Trader
+	placed    []string
+	cancelled []string
+}
+
+func (m *protectiveOrderMock) PlaceStopLoss(symbol, positionSide string, quantity, stopPrice float64) (string, error) {
+	m.placed = append(m.placed, fmt.Sprintf("sl-%d", len(m.placed)+1))
+	return m.placed[len(m.placed)-1], nil
+}
+
+func (m *protectiveOrderMock) PlaceTakeProfit(symbol, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	m.placed = append(m.placed, fmt.Sprintf("tp-%d", len(m.placed)+1))
+	return m.placed[len(m.placed)-1], nil
+}
+
+func (m *protectiveOrderMock) CancelProtectiveOrder(symbol, orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	return nil
+}
+
+// TestProtectiveOrderTracking tests that SL/TP order IDs are tracked and cancelled on modification and close
+func TestProtectiveOrderTracking(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	pos := &store.TraderPosition{TraderID: "trader-1", Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 100, EntryTime: time.Now()}
+	if err := st.Position().Create(pos); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mock := &protectiveOrderMock{}
+	at := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st, positionTPSL: make(map[string][2]float64)}
+
+	at.setStopLoss("AAPL", "long", 10, 95, false)
+	at.setTakeProfit("AAPL", "long", 10, 110, false)
+	// SL modification cancels only the tracked stop loss
+	at.setStopLoss("AAPL", "long", 10, 98, true)
+	if got := strings.Join(mock.cancelled, ","); got != "sl-1" {
+		t.Errorf("cancelled on modification = %s", got)
+	}
+	orders, _ := st.Position().GetProtectiveOrders(pos.ID)
+	if orders.StopLossOrderID != "sl-3" || orders.TakeProfitOrderID != "tp-2" {
+		t.Errorf("tracked orders = %+v", orders)
+	}
+
+	// Close cancels both and clears tracking
+	if !at.cancelProtectiveOrders("AAPL", "long") {
+		t.Fatal("expected tracked orders to be cancelled")
+	}
+	if got := strings.Join(mock.cancelled, ","); got != "sl-1,sl-3,tp-2" {
+		t.Errorf("cancelled on close = %s", got)
+	}
+	if at.cancelProtectiveOrders("AAPL", "long") {
+		t.Error("expected no tracked orders after close")
+	}
+}
@@ -0,0 +1,75 @@
+package trader
+
+import (
+	"SynapseStrike/market"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Quote Currency Conversion
+// ============================================================================
+// Each exchange account has a collateral (quote) currency: USDT for CEX
+// futures, USDC for Hyperliquid/dYdX/Lighter, USD or EUR for stock brokers.
+// Balances are converted into USD through market.QuoteRates right where they
+// are read, so equity, P&L and position sizing are consistent across accounts.
+// Shadow mode keeps its virtual ledger in USD and is never converted.
+
+// balanceAmountKeys balance fields holding money amounts (other fields pass through unchanged)
+var balanceAmountKeys = []string{
+	"totalWalletBalance", "wallet_balance", "availableBalance", "available_balance",
+	"totalUnrealizedProfit", "totalEquity", "total_equity", "totalEq", "balance", "spotBalance",
+}
+
+// DefaultQuoteCurrency collateral currency of an exchange when the account does not set one
+func DefaultQuoteCurrency(exchangeType string) string {
+	switch exchangeType {
+	case "hyperliquid", "dydx", "lighter":
+		return "USDC"
+	case "binance", "binance-spot", "bybit", "okx", "bitget", "aster", "ccxt":
+		return "USDT"
+	default:
+		return market.ReportingCurrency
+	}
+}
+
+// ResolveQuoteCurrency configured quote currency of an account, falling back to the exchange default
+func ResolveQuoteCurrency(exchangeType, configured string) string {
+	if strings.TrimSpace(configured) != "" {
+		return market.NormalizeQuoteCurrency(configured)
+	}
+	return DefaultQuoteCurrency(exchangeType)
+}
+
+// ConvertBalanceToUSD converts the amounts of an exchange balance from currency into USD
+// The result is a copy with quote_currency/quote_rate added; the input map is not modified.
+func ConvertBalanceToUSD(balance map[string]interface{}, currency string) (map[string]interface{}, error) {
+	rate, err := market.QuoteRates.Rate(currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s balance to %s: %w", currency, market.ReportingCurrency, err)
+	}
+
+	converted := make(map[string]interface{}, len(balance)+2)
+	for k, v := range balance {
+		converted[k] = v
+	}
+	if rate != 1 {
+		for _, key := range balanceAmountKeys {
+			if amount, ok := converted[key].(float64); ok {
+				converted[key] = amount * rate
+			}
+		}
+	}
+	converted["quote_currency"] = market.NormalizeQuoteCurrency(currency)
+	converted["quote_rate"] = rate
+	return converted, nil
+}
+
+// getBalance account balance converted into USD
+func (at *AutoTrader) getBalance() (map[string]interface{}, error) {
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	return ConvertBalanceToUSD(balance, at.quoteCurrency)
+}
@@ -0,0 +1,342 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Exchange Rate Limiter
+// ============================================================================
+// Traders sharing an exchange account share one limiter per exchange + API key,
+// so their combined request weight stays under the exchange budget. Requests
+// are weighted per endpoint and delayed until the current window has room.
+// The weight reported by the exchange (Binance X-MBX-USED-WEIGHT-1M, Bybit
+// X-Bapi-Limit-Status) corrects the local count, which also covers requests
+// made by other processes on the same key. A 429/418 response pauses the key
+// until Retry-After; calls that would have to wait longer than rateLimitMaxWait
+// fail fast with *RateLimitError instead of stalling the trading cycle.
+
+const rateLimitMaxWait = 60 * time.Second
+
+// RateLimitError returned when a request is refused by the limiter or the exchange
+type RateLimitError struct {
+	Exchange   string
+	StatusCode int // HTTP status from exchange, 0 if refused locally
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s rate limited (HTTP %d), retry after %s", e.Exchange, e.StatusCode, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("%s rate limited, retry after %s", e.Exchange, e.RetryAfter.Round(time.Second))
+}
+
+// IsRateLimitError whether err is a rate limit refusal (limiter or exchange error code)
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"code=-1003", "too many requests", "too many visits", "way too much request weight"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitStatus limiter state exposed in trader status
+type RateLimitStatus struct {
+	Exchange    string    `json:"exchange"`
+	Used        int       `json:"used"`
+	Limit       int       `json:"limit"`
+	Window      string    `json:"window"`
+	Limited     bool      `json:"limited"`
+	PausedUntil time.Time `json:"paused_until,omitempty"`
+	Delayed     int64     `json:"delayed"`  // Requests that waited for window capacity
+	Rejected    int64     `json:"rejected"` // Requests refused with RateLimitError
+	LastLimited time.Time `json:"last_limited,omitempty"`
+}
+
+// RateLimiter request weight budget of one exchange account
+type RateLimiter struct {
+	exchange string
+	limit    int
+	window   time.Duration
+	weigh    func(req *http.Request) int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	used        int
+	pausedUntil time.Time
+	delayed     int64
+	rejected    int64
+	lastLimited time.Time
+}
+
+var (
+	rateLimiters   = make(map[string]*RateLimiter)
+	rateLimitersMu sync.Mutex
+)
+
+// getRateLimiter returns the shared limiter of exchange + apiKey, creating it on first use
+func getRateLimiter(exchange, apiKey string) *RateLimiter {
+	sum := sha256.Sum256([]byte(apiKey))
+	key := exchange + ":" + hex.EncodeToString(sum[:8])
+
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	if rl, ok := rateLimiters[key]; ok {
+		return rl
+	}
+	var rl *RateLimiter
+	switch exchange {
+	case "bybit":
+		rl = newRateLimiter(exchange, 100, 5*time.Second, bybitRequestWeight)
+	default:
+		// Binance futures allows 2400/min per IP, leave headroom for market data and other clients
+		rl = newRateLimiter(exchange, 2000, time.Minute, binanceRequestWeight)
+	}
+	rateLimiters[key] = rl
+	return rl
+}
+
+func newRateLimiter(exchange string, limit int, window time.Duration, weigh func(*http.Request) int) *RateLimiter {
+	return &RateLimiter{exchange: exchange, limit: limit, window: window, weigh: weigh}
+}
+
+// Transport wraps base so every request goes through the limiter
+func (rl *RateLimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitRoundTripper{base: base, limiter: rl}
+}
+
+// Wait blocks until weight fits into the current window
+// Returns *RateLimitError if the wait would exceed rateLimitMaxWait or done fires first.
+func (rl *RateLimiter) Wait(done <-chan struct{}, weight int) error {
+	waited := false
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+		var until time.Time
+		if now.Before(rl.pausedUntil) {
+			until = rl.pausedUntil
+		} else {
+			rl.rollWindow(now)
+			// A request heavier than the whole budget still passes into an empty window
+			if rl.used+weight <= rl.limit || rl.used == 0 {
+				rl.used += weight
+				if waited {
+					rl.delayed++
+				}
+				rl.mu.Unlock()
+				return nil
+			}
+			until = rl.windowStart.Add(rl.window)
+		}
+		wait := until.Sub(now)
+		if wait > rateLimitMaxWait {
+			rl.rejected++
+			rl.lastLimited = now
+			rl.mu.Unlock()
+			return &RateLimitError{Exchange: rl.exchange, RetryAfter: wait}
+		}
+		rl.mu.Unlock()
+
+		if !waited {
+			logger.Infof("⏳ [%s] Rate limit budget exhausted, delaying request %s", rl.exchange, wait.Round(time.Millisecond))
+		}
+		waited = true
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-done:
+			timer.Stop()
+			rl.mu.Lock()
+			rl.rejected++
+			rl.lastLimited = time.Now()
+			rl.mu.Unlock()
+			return &RateLimitError{Exchange: rl.exchange, RetryAfter: wait}
+		}
+	}
+}
+
+// rollWindow starts a new fixed window when the current one expired (caller holds mu)
+func (rl *RateLimiter) rollWindow(now time.Time) {
+	if start := now.Truncate(rl.window); !start.Equal(rl.windowStart) {
+		rl.windowStart = start
+		rl.used = 0
+	}
+}
+
+// observeUsed raises the local count to the weight reported by the exchange
+func (rl *RateLimiter) observeUsed(used int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rollWindow(time.Now())
+	if used > rl.used {
+		rl.used = used
+	}
+}
+
+// pause blocks all requests of the key until now+d
+func (rl *RateLimiter) pause(d time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	if until := now.Add(d); until.After(rl.pausedUntil) {
+		rl.pausedUntil = until
+	}
+	rl.lastLimited = now
+}
+
+// Status snapshot of the limiter (zero status for a nil limiter)
+func (rl *RateLimiter) Status() RateLimitStatus {
+	if rl == nil {
+		return RateLimitStatus{}
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	rl.rollWindow(now)
+	status := RateLimitStatus{
+		Exchange:    rl.exchange,
+		Used:        rl.used,
+		Limit:       rl.limit,
+		Window:      rl.window.String(),
+		Limited:     now.Before(rl.pausedUntil) || rl.used >= rl.limit,
+		Delayed:     rl.delayed,
+		Rejected:    rl.rejected,
+		LastLimited: rl.lastLimited,
+	}
+	if now.Before(rl.pausedUntil) {
+		status.PausedUntil = rl.pausedUntil
+	}
+	return status
+}
+
+// rateLimitRoundTripper HTTP RoundTripper applying the limiter and reading weight headers
+type rateLimitRoundTripper struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rl := t.limiter
+	if err := rl.Wait(req.Context().Done(), rl.weigh(req)); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	switch rl.exchange {
+	case "bybit":
+		// Remaining requests of the endpoint window; pause the key until reset when exhausted
+		if remaining, err := strconv.Atoi(resp.Header.Get("X-Bapi-Limit-Status")); err == nil && remaining <= 0 {
+			if resetMs, err := strconv.ParseInt(resp.Header.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64); err == nil {
+				rl.pause(time.Until(time.UnixMilli(resetMs)))
+			}
+		}
+	default:
+		if used, err := strconv.Atoi(resp.Header.Get("X-Mbx-Used-Weight-1m")); err == nil {
+			rl.observeUsed(used)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot ||
+		(rl.exchange == "bybit" && resp.StatusCode == http.StatusForbidden) {
+		retryAfter := time.Minute
+		if resp.StatusCode != http.StatusTooManyRequests {
+			// 418 (Binance IP ban) / 403 (Bybit IP ban) last longer than a plain 429
+			retryAfter = 10 * time.Minute
+		}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		rl.pause(retryAfter)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		logger.Warnf("🚦 [%s] Rate limited by exchange (HTTP %d), pausing requests for %s", rl.exchange, resp.StatusCode, retryAfter)
+		return nil, &RateLimitError{Exchange: rl.exchange, StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+	}
+	return resp, nil
+}
+
+// binanceRequestWeight request weight of a Binance futures endpoint
+func binanceRequestWeight(req *http.Request) int {
+	path := req.URL.Path
+	query := req.URL.Query()
+	hasSymbol := query.Get("symbol") != ""
+	switch {
+	case strings.HasSuffix(path, "/account"), strings.HasSuffix(path, "/balance"),
+		strings.HasSuffix(path, "/positionRisk"), strings.HasSuffix(path, "/allOrders"),
+		strings.HasSuffix(path, "/userTrades"), strings.HasSuffix(path, "/batchOrders"):
+		return 5
+	case strings.HasSuffix(path, "/income"):
+		return 30
+	case strings.HasSuffix(path, "/openOrders"):
+		if hasSymbol {
+			return 1
+		}
+		return 40
+	case strings.HasSuffix(path, "/ticker/price"), strings.HasSuffix(path, "/premiumIndex"):
+		if hasSymbol {
+			return 1
+		}
+		return 2
+	case strings.HasSuffix(path, "/ticker/24hr"):
+		if hasSymbol {
+			return 1
+		}
+		return 40
+	case strings.HasSuffix(path, "/klines"):
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		switch {
+		case limit > 0 && limit < 100:
+			return 1
+		case limit > 0 && limit < 500:
+			return 2
+		case limit > 1000:
+			return 10
+		}
+		return 5
+	case strings.HasSuffix(path, "/depth"):
+		return 5
+	}
+	return 1
+}
+
+// bybitRequestWeight Bybit limits by request count, every call costs 1
+func bybitRequestWeight(*http.Request) int {
+	return 1
+}
+
+// withRateLimiter returns a copy of c whose transport goes through rl
+// c itself is left untouched since SDKs default to the shared http.DefaultClient.
+func withRateLimiter(c *http.Client, rl *RateLimiter) *http.Client {
+	limited := &http.Client{}
+	if c != nil {
+		*limited = *c
+	}
+	limited.Transport = rl.Transport(limited.Transport)
+	return limited
+}
@@ -0,0 +1,117 @@
+package trader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+func TestRateLimiterSharedPerKey(t *testing.T) {
+	a := getRateLimiter("binance", "key-shared-test")
+	b := getRateLimiter("binance", "key-shared-test")
+	if a != b {
+		t.Fatal("expected traders on the same key to share one limiter")
+	}
+	if getRateLimiter("bybit", "key-shared-test") == a || getRateLimiter("binance", "other-key-test") == a {
+		t.Fatal("expected separate limiters per exchange and key")
+	}
+}
+
+func TestRateLimiterWaitAndPause(t *testing.T) {
+	rl := newRateLimiter("binance", 10, 200*time.Millisecond, binanceRequestWeight)
+
+	// Wait for a window boundary so both requests land in the same window
+	time.Sleep(time.Until(time.Now().Truncate(rl.window).Add(rl.window)))
+	if err := rl.Wait(nil, 8); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	if err := rl.Wait(nil, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(start) < 100*time.Millisecond {
+		t.Errorf("expected request exceeding the budget to wait for the next window")
+	}
+	if status := rl.Status(); status.Delayed != 1 || status.Used != 5 {
+		t.Errorf("expected 1 delayed request and 5 used, got %+v", status)
+	}
+
+	// Paused longer than the max wait → fail fast with RateLimitError
+	rl.pause(5 * time.Minute)
+	err := rl.Wait(nil, 1)
+	if !IsRateLimitError(err) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if status := rl.Status(); !status.Limited || status.Rejected != 1 {
+		t.Errorf("expected limited status with 1 rejection, got %+v", status)
+	}
+}
+
+func TestRateLimitTransportBinance(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("X-MBX-USED-WEIGHT-1M", "1500")
+			w.Write([]byte(`{"serverTime": 1}`))
+			return
+		}
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"code": -1003, "msg": "Too many requests"}`))
+	}))
+	defer server.Close()
+
+	rl := newRateLimiter("binance", 2000, time.Minute, binanceRequestWeight)
+	client := futures.NewClient("", "")
+	client.BaseURL = server.URL
+	client.HTTPClient = withRateLimiter(client.HTTPClient, rl)
+	if http.DefaultClient.Transport != nil {
+		t.Fatal("shared http.DefaultClient must not be modified")
+	}
+
+	if _, err := client.NewServerTimeService().Do(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used := rl.Status().Used; used < 1500 {
+		t.Errorf("expected server reported weight to be applied, used %d", used)
+	}
+
+	_, err := client.NewServerTimeService().Do(t.Context())
+	if !IsRateLimitError(err) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	status := rl.Status()
+	if !status.Limited || time.Until(status.PausedUntil) < time.Minute {
+		t.Errorf("expected key paused per Retry-After, got %+v", status)
+	}
+
+	// Further calls are refused locally without reaching the exchange
+	if _, err := client.NewServerTimeService().Do(t.Context()); !IsRateLimitError(err) {
+		t.Fatalf("expected local refusal, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls to reach the server, got %d", calls)
+	}
+}
+
+func TestBinanceRequestWeight(t *testing.T) {
+	cases := map[string]int{
+		"/fapi/v2/account":                   5,
+		"/fapi/v1/openOrders":                40,
+		"/fapi/v1/openOrders?symbol=BTCUSDT": 1,
+		"/fapi/v1/klines?limit=50":           1,
+		"/fapi/v1/klines":                    5,
+		"/fapi/v1/income":                    30,
+		"/fapi/v1/order":                     1,
+	}
+	for target, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if got := binanceRequestWeight(req); got != want {
+			t.Errorf("%s: expected weight %d, got %d", target, want, got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"strings"
+	"time"
+)
+
+// sessionMemoryContext fills ctx.SessionSummary with the running thesis of the last cycles (SessionMemory.Enabled only)
+func (at *AutoTrader) sessionMemoryContext(ctx *decision.Context) {
+	if at.sessionMemory == nil || !at.engine().GetConfig().SessionMemory.Enabled {
+		return
+	}
+	ctx.SessionSummary = at.sessionMemory.Summary()
+}
+
+// updateSessionMemory remembers this cycle's reasoning and actions and refreshes the session summary
+// The summary call (up to 30s) runs in the background: runCycle holds cycleMu and must not wait for it.
+func (at *AutoTrader) updateSessionMemory(ctx *decision.Context, fullDecision *decision.FullDecision, record *store.DecisionRecord) {
+	cfg := at.engine().GetConfig().SessionMemory
+	if at.sessionMemory == nil || !cfg.Enabled || fullDecision == nil {
+		return
+	}
+	at.sessionMemory.Add(decision.SessionCycle{
+		Cycle:     ctx.CallCount,
+		Time:      time.Now(),
+		Reasoning: fullDecision.CoTTrace,
+		Actions:   sessionCycleActions(fullDecision.Decisions, record.Decisions),
+	}, cfg.Cycles)
+
+	started := at.sessionMemory.SummarizeInBackground(at.sessionMemoryClient(cfg), cfg.MaxChars, func(err error) {
+		logger.Warnf("⚠️ [%s] Session memory not updated, keeping previous summary: %v", at.name, err)
+	})
+	if !started {
+		record.ExecutionLog = append(record.ExecutionLog, "ℹ️ Session memory: previous summary still running, this cycle is summarized with the next one")
+	}
+}
+
+// sessionMemoryClient AI client writing the session summary (the trader's own client unless a model is configured)
+func (at *AutoTrader) sessionMemoryClient(cfg store.SessionMemoryConfig) mcp.AIClient {
+	id := strings.TrimSpace(cfg.AIModelID)
+	if id == "" || id == at.config.AIModelID || at.store == nil {
+		return at.mcpClient
+	}
+	cached, err := at.cachedAIClient(id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Session memory %v, using the trader's model", at.name, err)
+		return at.mcpClient
+	}
+	return cached.client
+}
+
+// sessionCycleActions describes each decision with its execution outcome
+// Opens and closes are matched with the executed actions of the record; hold/wait are listed as decided.
+func sessionCycleActions(decisions []decision.Decision, executed []store.DecisionAction) []string {
+	outcomes := make(map[string]store.DecisionAction, len(executed))
+	for _, action := range executed {
+		outcomes[action.Symbol+"_"+action.Action] = action
+	}
+	actions := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		line := d.Symbol + " " + d.Action
+		if action, ok := outcomes[d.Symbol+"_"+d.Action]; ok {
+			if action.Success {
+				line += " executed"
+			} else {
+				line += " failed: " + action.Error
+			}
+		} else if strings.HasPrefix(d.Action, "open_") || strings.HasPrefix(d.Action, "close_") {
+			line += " not executed"
+		}
+		actions = append(actions, line)
+	}
+	return actions
+}
@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSessionCycleActions tests that decisions are described with their execution outcome
+func TestSessionCycleActions(t *testing.T) {
+	decisions := []decision.Decision{
+		{Symbol: "AAPL", Action: "open_long"},
+		{Symbol: "TSLA", Action: "close_short"},
+		{Symbol: "NVDA", Action: "open_short"},
+		{Symbol: "MSFT", Action: "hold"},
+	}
+	executed := []store.DecisionAction{
+		{Symbol: "AAPL", Action: "open_long", Success: true},
+		{Symbol: "TSLA", Action: "close_short", Error: "no position"},
+	}
+	got := strings.Join(sessionCycleActions(decisions, executed), "; ")
+	want := "AAPL open_long executed; TSLA close_short failed: no position; NVDA open_short not executed; MSFT hold"
+	if got != want {
+		t.Errorf("sessionCycleActions() = %q, want %q", got, want)
+	}
+}
+
+// TestSessionMemorySummaryDuringStrategyReload tests that background summary calls and the strategy reloads
+// of the next cycles can use the trader's AI client at the same time (run with -race)
+func TestSessionMemorySummaryDuringStrategyReload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		io.WriteString(w, `{"choices":[{"message":{"content":"Long AAPL thesis intact"}}]}`)
+	}))
+	defer server.Close()
+
+	at := newReloadTestTrader(true)
+	at.mcpClient = mcp.NewClient(mcp.WithProvider(mcp.ProviderCustom), mcp.WithAPIKey("test-key"), mcp.WithBaseURL(server.URL), mcp.WithModel("test-model"), mcp.WithMaxRetries(1))
+	at.sessionMemory = decision.NewSessionMemory()
+	at.engine().GetConfig().SessionMemory.Enabled = true
+
+	// Next cycles stage and apply new sampling parameters on the same client while summaries run
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			next := store.GetDefaultStrategyConfig("en")
+			next.SessionMemory.Enabled = true
+			topP := 0.5 + float64(i)/100
+			next.AIParams.TopP = &topP
+			if _, err := at.UpdateStrategyConfig(&next); err != nil {
+				t.Errorf("UpdateStrategyConfig: %v", err)
+				return
+			}
+			at.applyPendingStrategy()
+		}
+	}()
+
+	fullDecision := &decision.FullDecision{CoTTrace: "AAPL breaking out", Decisions: []decision.Decision{{Symbol: "AAPL", Action: "open_long"}}}
+	for cycle := 1; ; cycle++ {
+		at.updateSessionMemory(&decision.Context{CallCount: cycle}, fullDecision, &store.DecisionRecord{})
+		select {
+		case <-done:
+		default:
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		break
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for at.sessionMemory.Summary() == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("background summary did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := at.sessionMemory.Summary(); !strings.Contains(got, "AAPL") {
+		t.Errorf("summary = %q", got)
+	}
+}
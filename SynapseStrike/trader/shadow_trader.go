@@ -0,0 +1,518 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// shadowTakerFeeRate simulated taker fee applied on every virtual fill
+const shadowTakerFeeRate = 0.0005
+
+// shadowPosition virtual position held in shadow ledger
+type shadowPosition struct {
+	Symbol     string
+	Side       string // "long" or "short"
+	Quantity   float64
+	EntryPrice float64
+	Leverage   int
+	StopLoss   float64
+	TakeProfit float64
+	EntryTime  time.Time
+}
+
+// ShadowTrader virtual position ledger (shadow mode)
+// Implements Trader interface: market data is read from the real exchange,
+// but orders are filled virtually at market price and tracked in memory,
+// so the full decision pipeline can run without touching the exchange.
+type ShadowTrader struct {
+	real        Trader // Real exchange trader (used for market prices only)
+	cash        float64
+	positions   map[string]*shadowPosition // symbol_side -> position
+	closed      []ClosedPnLRecord
+	orders      map[string]map[string]interface{} // orderID -> order status
+	nextOrderID int64
+	onAutoClose func(rec ClosedPnLRecord)                               // Called when virtual SL/TP triggers
+	onProtect   func(symbol, side string, stopLoss, takeProfit float64) // Called when virtual SL/TP prices change
+	mu          sync.Mutex
+}
+
+// NewShadowTrader creates shadow trader with given starting cash
+func NewShadowTrader(real Trader, startingCash float64) *ShadowTrader {
+	return &ShadowTrader{
+		real:        real,
+		cash:        startingCash,
+		positions:   make(map[string]*shadowPosition),
+		orders:      make(map[string]map[string]interface{}),
+		nextOrderID: time.Now().UnixMilli(),
+	}
+}
+
+// restoreShadowTrader creates the virtual ledger of a trader, resuming its stored trades after a restart
+// Cash is the initial balance plus net PnL of closed trades, minus entry fees of open positions (paid
+// from cash when they were opened, but only counted in NetPnL once closed). Returns the starting cash.
+func restoreShadowTrader(real Trader, st *store.Store, traderID string, initialBalance float64) (*ShadowTrader, float64) {
+	if st == nil {
+		return NewShadowTrader(real, initialBalance), initialBalance
+	}
+	startingCash := initialBalance
+	if stats, err := st.Position().GetFullStats(traderID); err == nil {
+		startingCash += stats.NetPnL
+	}
+	openPositions, err := st.Position().GetOpenPositions(traderID)
+	if err != nil {
+		logger.Warnf("⚠️ [Shadow] Failed to load open positions, starting without them: %v", err)
+	}
+	for _, p := range openPositions {
+		startingCash -= p.Fee
+	}
+	shadow := NewShadowTrader(real, startingCash)
+	shadow.RestorePositions(openPositions, st.Position().GetPositionRecovery)
+	return shadow, startingCash
+}
+
+// SetAutoCloseHandler sets callback for positions closed by virtual SL/TP
+func (t *ShadowTrader) SetAutoCloseHandler(fn func(rec ClosedPnLRecord)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onAutoClose = fn
+}
+
+// SetProtectiveHandler sets callback persisting virtual SL/TP prices (restored by RestorePositions)
+func (t *ShadowTrader) SetProtectiveHandler(fn func(symbol, side string, stopLoss, takeProfit float64)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onProtect = fn
+}
+
+// RestorePositions restores open virtual positions from database (after restart)
+// recovery loads the SL/TP prices stored on a position record (nil = restore without SL/TP)
+func (t *ShadowTrader) RestorePositions(positions []*store.TraderPosition, recovery func(id int64) (*store.PositionRecovery, error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range positions {
+		side := strings.ToLower(p.Side)
+		leverage := p.Leverage
+		if leverage <= 0 {
+			leverage = 1
+		}
+		pos := &shadowPosition{
+			Symbol:     p.Symbol,
+			Side:       side,
+			Quantity:   p.Quantity,
+			EntryPrice: p.EntryPrice,
+			Leverage:   leverage,
+			EntryTime:  p.EntryTime,
+		}
+		if recovery != nil {
+			if state, err := recovery(p.ID); err == nil {
+				pos.StopLoss, pos.TakeProfit = state.StopLoss, state.TakeProfit
+			} else {
+				logger.Warnf("⚠️ [Shadow] Restored %s %s without SL/TP: %v", p.Symbol, side, err)
+			}
+		}
+		t.positions[shadowKey(p.Symbol, side)] = pos
+	}
+}
+
+func shadowKey(symbol, side string) string {
+	return symbol + "_" + strings.ToLower(side)
+}
+
+// normalizeSide converts LONG/SHORT/buy/sell to long/short
+func normalizeSide(side string) string {
+	switch strings.ToLower(side) {
+	case "long", "buy":
+		return "long"
+	case "short", "sell":
+		return "short"
+	}
+	return strings.ToLower(side)
+}
+
+// unrealizedPnL calculates unrealized P&L at given price
+func (p *shadowPosition) unrealizedPnL(price float64) float64 {
+	if p.Side == "long" {
+		return (price - p.EntryPrice) * p.Quantity
+	}
+	return (p.EntryPrice - price) * p.Quantity
+}
+
+// margin returns margin locked by position
+func (p *shadowPosition) margin() float64 {
+	return p.Quantity * p.EntryPrice / float64(p.Leverage)
+}
+
+// newOrderLocked creates filled virtual order (caller holds lock)
+func (t *ShadowTrader) newOrderLocked(symbol string, price, quantity, fee float64) map[string]interface{} {
+	t.nextOrderID++
+	orderID := fmt.Sprintf("shadow-%d", t.nextOrderID)
+	t.orders[orderID] = map[string]interface{}{
+		"status":      "FILLED",
+		"avgPrice":    price,
+		"executedQty": quantity,
+		"commission":  fee,
+	}
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"status":  "FILLED",
+	}
+}
+
+// closeLocked closes position and books realized P&L (caller holds lock)
+func (t *ShadowTrader) closeLocked(pos *shadowPosition, price float64, closeType string) (ClosedPnLRecord, map[string]interface{}) {
+	fee := pos.Quantity * price * shadowTakerFeeRate
+	pnl := pos.unrealizedPnL(price)
+	t.cash += pnl - fee
+	delete(t.positions, shadowKey(pos.Symbol, pos.Side))
+
+	order := t.newOrderLocked(pos.Symbol, price, pos.Quantity, fee)
+	rec := ClosedPnLRecord{
+		Symbol:      pos.Symbol,
+		Side:        pos.Side,
+		EntryPrice:  pos.EntryPrice,
+		ExitPrice:   price,
+		Quantity:    pos.Quantity,
+		RealizedPnL: pnl,
+		Fee:         fee,
+		Leverage:    pos.Leverage,
+		EntryTime:   pos.EntryTime,
+		ExitTime:    time.Now(),
+		OrderID:     order["orderId"].(string),
+		CloseType:   closeType,
+	}
+	t.closed = append(t.closed, rec)
+	// Keep closed history bounded
+	if len(t.closed) > 500 {
+		t.closed = t.closed[len(t.closed)-500:]
+	}
+	return rec, order
+}
+
+// marketPrices fetches current prices of the open position symbols
+// The lock is only held to list the symbols: price requests go to the real
+// exchange and must not block order fills and status queries meanwhile.
+func (t *ShadowTrader) marketPrices() map[string]float64 {
+	t.mu.Lock()
+	symbols := make(map[string]bool, len(t.positions))
+	for _, pos := range t.positions {
+		symbols[pos.Symbol] = true
+	}
+	t.mu.Unlock()
+
+	prices := make(map[string]float64, len(symbols))
+	for symbol := range symbols {
+		if price, err := t.real.GetMarketPrice(symbol); err == nil && price > 0 {
+			prices[symbol] = price
+		}
+	}
+	return prices
+}
+
+// checkStops triggers virtual SL/TP at given market prices
+func (t *ShadowTrader) checkStops(prices map[string]float64) {
+	var triggered []ClosedPnLRecord
+
+	t.mu.Lock()
+	for _, pos := range t.positions {
+		if pos.StopLoss <= 0 && pos.TakeProfit <= 0 {
+			continue
+		}
+		price, ok := prices[pos.Symbol]
+		if !ok {
+			continue
+		}
+
+		closeType := ""
+		if pos.Side == "long" {
+			if pos.StopLoss > 0 && price <= pos.StopLoss {
+				closeType = "stop_loss"
+			} else if pos.TakeProfit > 0 && price >= pos.TakeProfit {
+				closeType = "take_profit"
+			}
+		} else {
+			if pos.StopLoss > 0 && price >= pos.StopLoss {
+				closeType = "stop_loss"
+			} else if pos.TakeProfit > 0 && price <= pos.TakeProfit {
+				closeType = "take_profit"
+			}
+		}
+		if closeType == "" {
+			continue
+		}
+
+		rec, _ := t.closeLocked(pos, price, closeType)
+		logger.Infof("👻 [Shadow] %s triggered: %s %s @ %.4f, P&L: %.2f", closeType, rec.Symbol, rec.Side, price, rec.RealizedPnL)
+		triggered = append(triggered, rec)
+	}
+	handler := t.onAutoClose
+	t.mu.Unlock()
+
+	if handler != nil {
+		for _, rec := range triggered {
+			handler(rec)
+		}
+	}
+}
+
+// GetBalance returns virtual account balance
+func (t *ShadowTrader) GetBalance() (map[string]interface{}, error) {
+	prices := t.marketPrices()
+	t.checkStops(prices)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var unrealized, margin float64
+	for _, pos := range t.positions {
+		margin += pos.margin()
+		if price, ok := prices[pos.Symbol]; ok {
+			unrealized += pos.unrealizedPnL(price)
+		}
+	}
+
+	equity := t.cash + unrealized
+	return map[string]interface{}{
+		"totalWalletBalance":    t.cash,
+		"wallet_balance":        t.cash,
+		"availableBalance":      equity - margin,
+		"totalUnrealizedProfit": unrealized,
+		"totalEquity":           equity,
+		"total_equity":          equity,
+	}, nil
+}
+
+// GetPositions returns virtual positions (same format as exchange traders)
+func (t *ShadowTrader) GetPositions() ([]Position, error) {
+	prices := t.marketPrices()
+	t.checkStops(prices)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]Position, 0, len(t.positions))
+	for _, pos := range t.positions {
+		markPrice := pos.EntryPrice
+		if price, ok := prices[pos.Symbol]; ok {
+			markPrice = price
+		}
+
+		liquidationPrice := pos.EntryPrice * (1 - 1/float64(pos.Leverage))
+		if pos.Side == "short" {
+			liquidationPrice = pos.EntryPrice * (1 + 1/float64(pos.Leverage))
+		}
+
+		result = append(result, Position{
+			Symbol:        pos.Symbol,
+			Side:          pos.Side,
+			Qty:           pos.Quantity,
+			EntryPrice:    pos.EntryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: pos.unrealizedPnL(markPrice),
+			LiqPrice:      liquidationPrice,
+			Leverage:      float64(pos.Leverage),
+		})
+	}
+	return result, nil
+}
+
+// open opens virtual position at current market price
+func (t *ShadowTrader) open(symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("invalid quantity: %.8f", quantity)
+	}
+	if leverage <= 0 {
+		leverage = 1
+	}
+	price, err := t.real.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market price: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := shadowKey(symbol, side)
+	if _, exists := t.positions[key]; exists {
+		return nil, fmt.Errorf("shadow %s %s position already exists", symbol, side)
+	}
+
+	fee := quantity * price * shadowTakerFeeRate
+	t.cash -= fee
+	t.positions[key] = &shadowPosition{
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   quantity,
+		EntryPrice: price,
+		Leverage:   leverage,
+		EntryTime:  time.Now(),
+	}
+	logger.Infof("👻 [Shadow] Opened %s %s: qty=%.4f @ %.4f (%dx)", symbol, side, quantity, price, leverage)
+	return t.newOrderLocked(symbol, price, quantity, fee), nil
+}
+
+// close closes virtual position at current market price (quantity=0 means close all)
+func (t *ShadowTrader) close(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	price, err := t.real.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market price: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pos, exists := t.positions[shadowKey(symbol, side)]
+	if !exists {
+		return nil, fmt.Errorf("no shadow %s position for %s", side, symbol)
+	}
+
+	// Partial close: split position and close the requested part
+	if quantity > 0 && quantity < pos.Quantity {
+		part := *pos
+		part.Quantity = quantity
+		pos.Quantity -= quantity
+		fee := quantity * price * shadowTakerFeeRate
+		pnl := part.unrealizedPnL(price)
+		t.cash += pnl - fee
+		logger.Infof("👻 [Shadow] Partially closed %s %s: qty=%.4f @ %.4f, P&L: %.2f", symbol, side, quantity, price, pnl)
+		return t.newOrderLocked(symbol, price, quantity, fee), nil
+	}
+
+	rec, order := t.closeLocked(pos, price, "manual")
+	logger.Infof("👻 [Shadow] Closed %s %s @ %.4f, P&L: %.2f", symbol, side, price, rec.RealizedPnL)
+	return order, nil
+}
+
+func (t *ShadowTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, "long", quantity, leverage)
+}
+
+func (t *ShadowTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, "short", quantity, leverage)
+}
+
+func (t *ShadowTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "long", quantity)
+}
+
+func (t *ShadowTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "short", quantity)
+}
+
+func (t *ShadowTrader) SetLeverage(symbol string, leverage int) error {
+	return nil
+}
+
+func (t *ShadowTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return nil
+}
+
+func (t *ShadowTrader) GetMarketPrice(symbol string) (float64, error) {
+	return t.real.GetMarketPrice(symbol)
+}
+
+// updateProtective applies an SL/TP change to the matching positions and persists the new prices
+func (t *ShadowTrader) updateProtective(match func(pos *shadowPosition) bool, apply func(pos *shadowPosition)) {
+	var changed []shadowPosition
+	t.mu.Lock()
+	for _, pos := range t.positions {
+		if match(pos) {
+			apply(pos)
+			changed = append(changed, *pos)
+		}
+	}
+	handler := t.onProtect
+	t.mu.Unlock()
+
+	if handler != nil {
+		for _, pos := range changed {
+			handler(pos.Symbol, pos.Side, pos.StopLoss, pos.TakeProfit)
+		}
+	}
+}
+
+func (t *ShadowTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	side := normalizeSide(positionSide)
+	t.updateProtective(func(pos *shadowPosition) bool { return pos.Symbol == symbol && pos.Side == side },
+		func(pos *shadowPosition) { pos.StopLoss = stopPrice })
+	return nil
+}
+
+func (t *ShadowTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	side := normalizeSide(positionSide)
+	t.updateProtective(func(pos *shadowPosition) bool { return pos.Symbol == symbol && pos.Side == side },
+		func(pos *shadowPosition) { pos.TakeProfit = takeProfitPrice })
+	return nil
+}
+
+func (t *ShadowTrader) CancelStopLossOrders(symbol string) error {
+	t.updateProtective(func(pos *shadowPosition) bool { return pos.Symbol == symbol },
+		func(pos *shadowPosition) { pos.StopLoss = 0 })
+	return nil
+}
+
+func (t *ShadowTrader) CancelTakeProfitOrders(symbol string) error {
+	t.updateProtective(func(pos *shadowPosition) bool { return pos.Symbol == symbol },
+		func(pos *shadowPosition) { pos.TakeProfit = 0 })
+	return nil
+}
+
+func (t *ShadowTrader) CancelAllOrders(symbol string) error {
+	return t.CancelStopOrders(symbol)
+}
+
+func (t *ShadowTrader) CancelStopOrders(symbol string) error {
+	t.CancelStopLossOrders(symbol)
+	return t.CancelTakeProfitOrders(symbol)
+}
+
+func (t *ShadowTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return t.real.FormatQuantity(symbol, quantity)
+}
+
+func (t *ShadowTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if status, exists := t.orders[orderID]; exists {
+		return status, nil
+	}
+	return nil, fmt.Errorf("shadow order not found: %s", orderID)
+}
+
+func (t *ShadowTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var result []ClosedPnLRecord
+	for i := len(t.closed) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		if t.closed[i].ExitTime.After(startTime) {
+			result = append(result, t.closed[i])
+		}
+	}
+	return result, nil
+}
+
+// recordShadowAutoClose closes database position record when virtual SL/TP triggers
+// (shadow traders are skipped by PositionSyncManager, so closures are recorded here)
+func (at *AutoTrader) recordShadowAutoClose(rec ClosedPnLRecord) {
+	at.ClearPositionTPSL(rec.Symbol, rec.Side)
+	at.ClearPeakPnLCache(rec.Symbol, rec.Side)
+
+	if at.store == nil {
+		return
+	}
+	side := strings.ToUpper(rec.Side)
+	openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, rec.Symbol, side)
+	if err != nil || openPos == nil {
+		logger.Infof("  ⚠️ [Shadow] Cannot find open position record (%s %s)", rec.Symbol, side)
+		return
+	}
+	if err := at.store.Position().ClosePosition(openPos.ID, rec.ExitPrice, rec.OrderID, rec.RealizedPnL, rec.Fee, rec.CloseType); err != nil {
+		logger.Infof("  ⚠️ [Shadow] Failed to close position record: %v", err)
+		return
+	}
+	at.notifyPositionClosed(rec.Symbol, side, rec.CloseType, rec.EntryPrice, rec.ExitPrice, rec.RealizedPnL)
+}
@@ -0,0 +1,269 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"errors"
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// priceTrader real exchange serving settable prices, optionally blocking price requests
+type priceTrader struct {
+	*MockTrader
+	mu      sync.Mutex
+	prices  map[string]float64
+	blocked chan struct{} // Non-nil: price requests wait until closed
+}
+
+func (t *priceTrader) GetMarketPrice(symbol string) (float64, error) {
+	t.mu.Lock()
+	blocked := t.blocked
+	t.mu.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if price, ok := t.prices[symbol]; ok {
+		return price, nil
+	}
+	return 0, errors.New("no price")
+}
+
+func (t *priceTrader) setPrice(symbol string, price float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prices[symbol] = price
+}
+
+func newPriceTrader() *priceTrader {
+	return &priceTrader{MockTrader: &MockTrader{}, prices: map[string]float64{"AAPL": 100, "TSLA": 200}}
+}
+
+func TestShadowTraderStops(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       string
+		stopLoss   float64
+		takeProfit float64
+		price      float64
+		wantClose  string // Empty = position stays open
+	}{
+		{"long stop loss", "long", 95, 110, 94, "stop_loss"},
+		{"long take profit", "long", 95, 110, 111, "take_profit"},
+		{"long inside range", "long", 95, 110, 100, ""},
+		{"short stop loss", "short", 105, 90, 106, "stop_loss"},
+		{"short take profit", "short", 105, 90, 89, "take_profit"},
+		{"short inside range", "short", 105, 90, 100, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchange := newPriceTrader()
+			shadow := NewShadowTrader(exchange, 10000)
+			var closed []ClosedPnLRecord
+			shadow.SetAutoCloseHandler(func(rec ClosedPnLRecord) { closed = append(closed, rec) })
+
+			if _, err := shadow.open("AAPL", tt.side, 10, 1); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			shadow.SetStopLoss("AAPL", tt.side, 10, tt.stopLoss)
+			shadow.SetTakeProfit("AAPL", tt.side, 10, tt.takeProfit)
+			exchange.setPrice("AAPL", tt.price)
+
+			positions, err := shadow.GetPositions()
+			if err != nil {
+				t.Fatalf("GetPositions: %v", err)
+			}
+			if tt.wantClose == "" {
+				if len(positions) != 1 || len(closed) != 0 {
+					t.Fatalf("position should stay open: positions %d, closed %v", len(positions), closed)
+				}
+				if positions[0].MarkPrice != tt.price {
+					t.Errorf("mark price = %v, want %v", positions[0].MarkPrice, tt.price)
+				}
+				return
+			}
+			if len(positions) != 0 || len(closed) != 1 {
+				t.Fatalf("position should be closed: positions %d, closed %v", len(positions), closed)
+			}
+			if closed[0].CloseType != tt.wantClose || closed[0].ExitPrice != tt.price {
+				t.Errorf("closed by %s @ %v, want %s @ %v", closed[0].CloseType, closed[0].ExitPrice, tt.wantClose, tt.price)
+			}
+		})
+	}
+}
+
+func TestShadowTraderPersistsProtectivePrices(t *testing.T) {
+	shadow := NewShadowTrader(newPriceTrader(), 10000)
+	stored := make(map[string][2]float64)
+	shadow.SetProtectiveHandler(func(symbol, side string, stopLoss, takeProfit float64) {
+		stored[symbol+"_"+side] = [2]float64{stopLoss, takeProfit}
+	})
+
+	if _, err := shadow.OpenLong("AAPL", 10, 1); err != nil {
+		t.Fatalf("OpenLong: %v", err)
+	}
+	shadow.SetStopLoss("AAPL", "LONG", 10, 95)
+	shadow.SetTakeProfit("AAPL", "LONG", 10, 110)
+	if got := stored["AAPL_long"]; got != [2]float64{95, 110} {
+		t.Errorf("stored SL/TP = %v, want [95 110]", got)
+	}
+	shadow.CancelStopLossOrders("AAPL")
+	if got := stored["AAPL_long"]; got != [2]float64{0, 110} {
+		t.Errorf("stored SL/TP after cancel = %v, want [0 110]", got)
+	}
+	shadow.SetStopLoss("TSLA", "SHORT", 10, 210) // No position: nothing to persist
+	if _, exists := stored["TSLA_short"]; exists {
+		t.Error("SL/TP persisted for a position that does not exist")
+	}
+}
+
+func TestShadowTraderRestorePositions(t *testing.T) {
+	exchange := newPriceTrader()
+	shadow := NewShadowTrader(exchange, 10000)
+	recovery := map[int64]*store.PositionRecovery{
+		1: {PositionID: 1, StopLoss: 95, TakeProfit: 110},
+	}
+	shadow.RestorePositions([]*store.TraderPosition{
+		{ID: 1, Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 100, Leverage: 2},
+		{ID: 2, Symbol: "TSLA", Side: "SHORT", Quantity: 5, EntryPrice: 200},
+	}, func(id int64) (*store.PositionRecovery, error) {
+		if state, ok := recovery[id]; ok {
+			return state, nil
+		}
+		return nil, errors.New("not found")
+	})
+
+	positions, err := shadow.GetPositions()
+	if err != nil || len(positions) != 2 {
+		t.Fatalf("restored positions = %d (%v), want 2", len(positions), err)
+	}
+
+	// Restored stop loss still protects the position
+	var closed []ClosedPnLRecord
+	shadow.SetAutoCloseHandler(func(rec ClosedPnLRecord) { closed = append(closed, rec) })
+	exchange.setPrice("AAPL", 94)
+	exchange.setPrice("TSLA", 300) // No SL/TP restored: stays open
+	positions, _ = shadow.GetPositions()
+	if len(closed) != 1 || closed[0].Symbol != "AAPL" || closed[0].CloseType != "stop_loss" {
+		t.Fatalf("closed %v, want AAPL by its restored stop loss", closed)
+	}
+	if closed[0].Leverage != 2 {
+		t.Errorf("restored leverage = %d, want 2", closed[0].Leverage)
+	}
+	if len(positions) != 1 || positions[0].Symbol != "TSLA" || positions[0].Leverage != 1 {
+		t.Errorf("positions %v, want only TSLA at default leverage 1", positions)
+	}
+}
+
+// TestRestoreShadowTraderCash tests that a restarted shadow ledger resumes with the cash it had before the restart
+func TestRestoreShadowTraderCash(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer st.Close()
+	exchange := newPriceTrader()
+	shadow := NewShadowTrader(exchange, 10000)
+
+	// Record fills the way the trader does: entry fee on the open record, exit fee added on close
+	fill := func(symbol string, order map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		status, err := shadow.GetOrderStatus(symbol, order["orderId"].(string))
+		if err != nil {
+			t.Fatalf("GetOrderStatus: %v", err)
+		}
+		return status
+	}
+	record := func(symbol, side string, order map[string]interface{}) *store.TraderPosition {
+		t.Helper()
+		status := fill(symbol, order)
+		pos := &store.TraderPosition{TraderID: "t1", Symbol: symbol, Side: side, Quantity: status["executedQty"].(float64),
+			EntryPrice: status["avgPrice"].(float64), Fee: status["commission"].(float64), Leverage: 1, EntryTime: time.Now()}
+		if err := st.Position().Create(pos); err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		return pos
+	}
+	order, err := shadow.open("AAPL", "long", 10, 1)
+	if err != nil {
+		t.Fatalf("open AAPL: %v", err)
+	}
+	aapl := record("AAPL", "LONG", order)
+	if order, err = shadow.open("TSLA", "short", 5, 1); err != nil {
+		t.Fatalf("open TSLA: %v", err)
+	}
+	record("TSLA", "SHORT", order)
+
+	exchange.setPrice("AAPL", 110)
+	closeOrder, err := shadow.CloseLong("AAPL", 0)
+	if err != nil {
+		t.Fatalf("close AAPL: %v", err)
+	}
+	if err := st.Position().ClosePositionWithCosts(aapl.ID, 110, 110, "exit", 100, fill("AAPL", closeOrder)["commission"].(float64), 0, "manual"); err != nil {
+		t.Fatalf("failed to close position: %v", err)
+	}
+
+	before, _ := shadow.GetBalance()
+	restored, cash := restoreShadowTrader(exchange, st, "t1", 10000)
+	after, _ := restored.GetBalance()
+	// 10000 + AAPL (100 - 0.5 - 0.55) - TSLA entry fee 0.5
+	if math.Abs(cash-10098.45) > 1e-9 {
+		t.Errorf("starting cash = %.4f, want 10098.45", cash)
+	}
+	for _, key := range []string{"totalWalletBalance", "totalEquity", "availableBalance"} {
+		if math.Abs(after[key].(float64)-before[key].(float64)) > 1e-9 {
+			t.Errorf("%s after restart = %.4f, before = %.4f", key, after[key], before[key])
+		}
+	}
+	positions, _ := restored.GetPositions()
+	if len(positions) != 1 || positions[0].Symbol != "TSLA" || positions[0].Side != "short" {
+		t.Errorf("restored positions = %+v, want the open TSLA short", positions)
+	}
+}
+
+// TestShadowTraderPriceFetchOutsideLock tests that a slow price request does not block order status queries
+func TestShadowTraderPriceFetchOutsideLock(t *testing.T) {
+	exchange := newPriceTrader()
+	shadow := NewShadowTrader(exchange, 10000)
+	order, err := shadow.OpenLong("AAPL", 10, 1)
+	if err != nil {
+		t.Fatalf("OpenLong: %v", err)
+	}
+	shadow.SetStopLoss("AAPL", "long", 10, 95)
+
+	release := make(chan struct{})
+	exchange.mu.Lock()
+	exchange.blocked = release
+	exchange.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		shadow.GetBalance()
+		close(done)
+	}()
+
+	queried := make(chan error, 1)
+	go func() {
+		_, err := shadow.GetOrderStatus("AAPL", order["orderId"].(string))
+		queried <- err
+	}()
+	select {
+	case err := <-queried:
+		if err != nil {
+			t.Errorf("GetOrderStatus: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetOrderStatus blocked behind a pending price request")
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetBalance did not finish after the price request returned")
+	}
+}
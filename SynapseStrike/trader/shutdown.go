@@ -0,0 +1,199 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Graceful Shutdown & Runtime State
+// ============================================================================
+// On Stop() (API stop/delete or SIGTERM via StopAll) the trader waits for the
+// in-flight cycle, then applies the strategy's shutdown policy to the
+// positions it owns:
+//   - leave:         keep positions and their existing SL/TP (default)
+//   - tighten-stops: move stop losses to ShutdownStopPct from current price
+//   - flatten:       close all positions
+// Cycle state (phase, pending actions, call count, daily P&L, risk pause) is
+// persisted during every cycle so a restart after a crash can report what was
+// interrupted and continue with the same counters.
+
+// Shutdown policies
+const (
+	ShutdownLeave        = "leave"
+	ShutdownTightenStops = "tighten-stops"
+	ShutdownFlatten      = "flatten"
+
+	defaultShutdownStopPct = 0.5
+)
+
+// shutdownPolicy gets shutdown policy and stop distance from strategy config
+func (at *AutoTrader) shutdownPolicy() (string, float64) {
+	engine := at.engine()
+	if engine == nil {
+		return ShutdownLeave, defaultShutdownStopPct
+	}
+	rc := engine.GetConfig().RiskControl
+	policy := rc.ShutdownPolicy
+	if policy == "" {
+		policy = ShutdownLeave
+	}
+	stopPct := rc.ShutdownStopPct
+	if stopPct <= 0 {
+		stopPct = defaultShutdownStopPct
+	}
+	return policy, stopPct
+}
+
+// applyShutdownPolicy applies the shutdown policy to positions owned by this trader
+func (at *AutoTrader) applyShutdownPolicy() string {
+	policy, stopPct := at.shutdownPolicy()
+	if policy == ShutdownLeave || at.trader == nil {
+		return policy
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Errorf("❌ [%s] Shutdown policy %s: failed to get positions: %v", at.name, policy, err)
+		return policy
+	}
+
+	logger.Infof("🛑 [%s] Applying shutdown policy: %s", at.name, policy)
+	for _, pos := range positions {
+		symbol := pos.Symbol
+		side := pos.Side
+		side = strings.ToLower(side)
+		quantity := pos.Qty
+		if symbol == "" || quantity == 0 {
+			continue
+		}
+		if at.store != nil {
+			if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err != nil || dbPos == nil {
+				continue // Not owned by this trader
+			}
+		}
+
+		switch policy {
+		case ShutdownFlatten:
+			if err := at.closePositionWithReason(symbol, side, "shutdown", "Shutdown policy: flatten"); err != nil {
+				logger.Errorf("❌ [%s] Shutdown flatten %s %s failed: %v", at.name, symbol, side, err)
+			} else {
+				logger.Infof("✓ [%s] Shutdown flatten: closed %s %s", at.name, symbol, side)
+			}
+		case ShutdownTightenStops:
+			at.tightenStop("Shutdown tighten-stops", symbol, side, quantity, stopPct)
+		}
+	}
+	return policy
+}
+
+// tightenStop moves stop loss to stopPct from current price, never loosening an existing stop
+// label names the caller in logs (shutdown policy, shock mode).
+func (at *AutoTrader) tightenStop(label, symbol, side string, quantity, stopPct float64) {
+	price, err := at.trader.GetMarketPrice(symbol)
+	if err != nil || price <= 0 {
+		logger.Errorf("❌ [%s] %s %s: failed to get price: %v", at.name, label, symbol, err)
+		return
+	}
+
+	stopPrice := price * (1 + stopPct/100)
+	if side == "long" {
+		stopPrice = price * (1 - stopPct/100)
+	}
+
+	takeProfit, currentStop, exists := at.GetPositionTPSL(symbol, side)
+	if exists && currentStop > 0 {
+		if (side == "long" && currentStop >= stopPrice) || (side == "short" && currentStop <= stopPrice) {
+			logger.Infof("🛑 [%s] %s %s %s: existing stop %.4f already tighter than %.4f",
+				at.name, label, symbol, side, currentStop, stopPrice)
+			return
+		}
+	}
+
+	if err := at.setStopLoss(symbol, side, quantity, stopPrice, true); err != nil {
+		logger.Errorf("❌ [%s] %s %s %s failed: %v", at.name, label, symbol, side, err)
+		return
+	}
+	at.SetPositionTPSL(symbol, side, takeProfit, stopPrice)
+	logger.Infof("✓ [%s] %s: %s %s stop → %.4f (%.2f%% from %.4f)",
+		at.name, label, symbol, side, stopPrice, stopPct, price)
+}
+
+// saveRuntimeState persists cycle phase and counters (no-op without store)
+func (at *AutoTrader) saveRuntimeState(phase string, pending []string) {
+	at.saveRuntimeStateWith(&store.TraderRuntimeState{CyclePhase: phase, PendingActions: pending})
+}
+
+func (at *AutoTrader) saveRuntimeStateWith(state *store.TraderRuntimeState) {
+	if at.store == nil {
+		return
+	}
+	state.TraderID = at.id
+	state.CallCount = at.callCount
+	state.DailyPnL = at.dailyPnL
+	state.LastResetTime = at.lastResetTime
+	state.StopUntil = at.stopUntil
+	at.floorMu.Lock()
+	state.FloorHaltedAt, state.FloorHaltReason = at.floorHaltedAt, at.floorHaltReason
+	at.floorMu.Unlock()
+	at.pauseMu.Lock()
+	state.PausedAt, state.PausedBy, state.PauseReason = at.pausedAt, at.pausedBy, at.pauseReason
+	at.pauseMu.Unlock()
+	if state.CyclePhase != store.CyclePhaseIdle {
+		state.CycleStartedAt = at.cycleStartedAt
+	}
+	if err := at.store.RuntimeState().Save(state); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to save runtime state: %v", at.name, err)
+	}
+}
+
+// restoreRuntimeState restores counters from the previous run and detects an interrupted cycle
+func (at *AutoTrader) restoreRuntimeState() {
+	if at.store == nil {
+		return
+	}
+	state, err := at.store.RuntimeState().Get(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load runtime state: %v", at.name, err)
+		return
+	}
+	if state == nil {
+		return
+	}
+
+	at.callCount = state.CallCount
+	if time.Now().Before(state.StopUntil) {
+		at.stopUntil = state.StopUntil
+		logger.Infof("⏸ [%s] Restored risk control pause until %s", at.name, state.StopUntil.Format(time.RFC3339))
+	}
+	if !state.FloorHaltedAt.IsZero() {
+		at.floorHaltedAt, at.floorHaltReason = state.FloorHaltedAt, state.FloorHaltReason
+		logger.Warnf("🧱 [%s] Restored equity floor halt from %s (%s), trading stays halted until manual reset",
+			at.name, state.FloorHaltedAt.Format(time.RFC3339), state.FloorHaltReason)
+	}
+	if !state.PausedAt.IsZero() {
+		at.pausedAt, at.pausedBy, at.pauseReason = state.PausedAt, state.PausedBy, state.PauseReason
+		logger.Infof("⏸ [%s] Restored operator pause from %s (%s), new opens stay blocked until resumed",
+			at.name, state.PausedAt.Format(time.RFC3339), state.PauseReason)
+	}
+	if !state.LastResetTime.IsZero() && time.Since(state.LastResetTime) <= 24*time.Hour {
+		at.dailyPnL = state.DailyPnL
+		at.lastResetTime = state.LastResetTime
+	}
+
+	if !state.CleanShutdown && state.CyclePhase != store.CyclePhaseIdle && state.CyclePhase != "" {
+		note := fmt.Sprintf("Previous run was interrupted during %s phase of cycle #%d (started %s)",
+			state.CyclePhase, state.CallCount, state.CycleStartedAt.Format(time.RFC3339))
+		if len(state.PendingActions) > 0 {
+			note += fmt.Sprintf("; not executed: %s", strings.Join(state.PendingActions, ", "))
+		}
+		at.resumeNote = "⚠️ " + note
+		logger.Warnf("⚠️ [%s] %s. Positions will be re-read from exchange, pending actions are replayed from the execution queue unless stale", at.name, note)
+	} else {
+		logger.Infof("♻️ [%s] Restored runtime state: cycle #%d, last shutdown policy: %s",
+			at.name, state.CallCount, state.ShutdownPolicy)
+	}
+}
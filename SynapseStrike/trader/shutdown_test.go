@@ -0,0 +1,157 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+)
+
+// shutdownTrader records the orders sent by a shutdown policy
+type shutdownTrader struct {
+	*MockTrader
+	mu     sync.Mutex
+	closed []string
+	stops  map[string]float64
+}
+
+func (t *shutdownTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	t.mu.Lock()
+	t.closed = append(t.closed, symbol+"_long")
+	t.mu.Unlock()
+	return t.MockTrader.CloseLong(symbol, quantity)
+}
+
+func (t *shutdownTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	t.mu.Lock()
+	t.closed = append(t.closed, symbol+"_short")
+	t.mu.Unlock()
+	return t.MockTrader.CloseShort(symbol, quantity)
+}
+
+func (t *shutdownTrader) GetMarketPrice(symbol string) (float64, error) {
+	return 100, nil
+}
+
+func (t *shutdownTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	t.mu.Lock()
+	t.stops[symbol] = stopPrice
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *shutdownTrader) closedCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.closed)
+}
+
+// newShutdownTestTrader creates a running trader holding an AAPL long and a TSLA short
+func newShutdownTestTrader(policy string) (*AutoTrader, *shutdownTrader) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.ShutdownPolicy = policy
+	cfg.RiskControl.ShutdownStopPct = 1
+	exchange := &shutdownTrader{
+		MockTrader: &MockTrader{positions: []Position{
+			{Symbol: "AAPL", Side: "long", Qty: 10, EntryPrice: 95, MarkPrice: 100},
+			{Symbol: "TSLA", Side: "short", Qty: 5, EntryPrice: 105, MarkPrice: 100},
+		}},
+		stops: make(map[string]float64),
+	}
+	at := &AutoTrader{
+		id:             "t1",
+		name:           "test",
+		trader:         exchange,
+		strategyEngine: decision.NewStrategyEngine(&cfg),
+		stopMonitorCh:  make(chan struct{}),
+		positionTPSL:   make(map[string][2]float64),
+		peakPnLCache:   make(map[string]float64),
+	}
+	at.isRunning.Store(true)
+	return at, exchange
+}
+
+// TestStopShutdownPolicies tests that Stop applies the leave, flatten and tighten-stops policies
+func TestStopShutdownPolicies(t *testing.T) {
+	patches := gomonkey.ApplyFunc(market.Get, func(symbol string) (*market.Data, error) {
+		return &market.Data{Symbol: symbol, CurrentPrice: 100}, nil
+	})
+	defer patches.Reset()
+
+	t.Run("leave", func(t *testing.T) {
+		at, exchange := newShutdownTestTrader(ShutdownLeave)
+		at.Stop()
+		if exchange.closedCount() != 0 || len(exchange.stops) != 0 {
+			t.Errorf("leave should not touch positions: closed %v, stops %v", exchange.closed, exchange.stops)
+		}
+		if at.isRunning.Load() {
+			t.Error("trader should be stopped")
+		}
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		at, exchange := newShutdownTestTrader(ShutdownFlatten)
+		at.Stop()
+		if len(exchange.closed) != 2 || exchange.closed[0] != "AAPL_long" || exchange.closed[1] != "TSLA_short" {
+			t.Errorf("flatten closed %v, want both positions", exchange.closed)
+		}
+		at.Stop() // Second stop is a no-op
+		if exchange.closedCount() != 2 {
+			t.Errorf("second Stop should not apply the policy again, closed %v", exchange.closed)
+		}
+	})
+
+	t.Run("tighten-stops", func(t *testing.T) {
+		at, exchange := newShutdownTestTrader(ShutdownTightenStops)
+		at.SetPositionTPSL("TSLA", "short", 90, 100.5) // Already tighter than 1% from price
+		at.Stop()
+		if exchange.closedCount() != 0 {
+			t.Errorf("tighten-stops should not close positions, closed %v", exchange.closed)
+		}
+		if got := exchange.stops["AAPL"]; got != 99 {
+			t.Errorf("AAPL long stop = %v, want 99 (1%% below price)", got)
+		}
+		if _, moved := exchange.stops["TSLA"]; moved {
+			t.Errorf("TSLA stop should be kept, it is already tighter")
+		}
+	})
+}
+
+// TestStopWaitsForCycle tests that the shutdown policy runs only after the in-flight cycle releases the cycle lock
+func TestStopWaitsForCycle(t *testing.T) {
+	patches := gomonkey.ApplyFunc(market.Get, func(symbol string) (*market.Data, error) {
+		return &market.Data{Symbol: symbol, CurrentPrice: 100}, nil
+	})
+	defer patches.Reset()
+
+	at, exchange := newShutdownTestTrader(ShutdownFlatten)
+	at.cycleMu.Lock() // A cycle is running
+
+	stopped := make(chan struct{})
+	go func() {
+		at.Stop()
+		close(stopped)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if exchange.closedCount() != 0 {
+		t.Fatal("shutdown policy ran while a cycle was in flight")
+	}
+	if at.isRunning.Load() {
+		t.Error("trader should be marked stopped immediately so the cycle stops executing")
+	}
+
+	at.cycleMu.Unlock()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not finish after the cycle ended")
+	}
+	if exchange.closedCount() != 2 {
+		t.Errorf("flatten closed %v after the cycle, want both positions", exchange.closed)
+	}
+}
@@ -0,0 +1,496 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Spot Trading
+// ============================================================================
+// Spot exchanges (Binance spot, Coinbase) have no positions, leverage or
+// short selling: a "long position" is simply a non-zero balance of the base
+// asset. SpotTrader maps the futures-style Trader interface onto that model:
+// open_long buys the base asset, close_long sells it, shorts are rejected and
+// leverage/margin calls are no-ops. Positions are derived from balances, with
+// the average entry price tracked from the trader's own fills (holdings bought
+// elsewhere are valued at the current price). Stop-loss and take-profit share
+// the held balance, so both are placed as one exchange order (OCO/bracket);
+// changing one of them re-places the pair.
+
+// spotDustUSD holdings worth less than this are not reported as positions
+const spotDustUSD = 1.0
+
+// spotStopLimitSlippage stop-limit orders sell up to this fraction below the stop price
+const spotStopLimitSlippage = 0.005
+
+// spotBalance free and locked (in open orders) amount of an asset
+type spotBalance struct {
+	Free   float64
+	Locked float64
+}
+
+// spotFill executed market order
+type spotFill struct {
+	OrderID  string
+	Quantity float64 // Executed base quantity
+	AvgPrice float64
+	Fee      float64 // Fee in quote currency (0 if paid in another asset)
+}
+
+// spotVenue exchange-specific part of a spot trader
+// Symbols passed in are canonical ("BTCUSDT"); venues convert them to their own format.
+type spotVenue interface {
+	// Name exchange type ("binance-spot", "coinbase")
+	Name() string
+	// QuoteAsset currency the account trades against ("USDT", "USD")
+	QuoteAsset() string
+	// Balances all non-zero asset balances
+	Balances() (map[string]spotBalance, error)
+	// Price last traded price
+	Price(symbol string) (float64, error)
+	// MarketOrder buys or sells base quantity at market (side: "BUY"/"SELL")
+	MarketOrder(symbol, side string, quantity float64) (*spotFill, error)
+	// PlaceExitOrder places a resting sell order protecting quantity (stop or target may be 0, not both)
+	PlaceExitOrder(symbol string, quantity, stopPrice, targetPrice float64) (string, error)
+	// CancelOrder cancels an order returned by PlaceExitOrder
+	CancelOrder(symbol, orderID string) error
+	// CancelAll cancels all open orders of the symbol
+	CancelAll(symbol string) error
+	// FormatQuantity rounds quantity down to the symbol's lot size
+	FormatQuantity(symbol string, quantity float64) (string, error)
+	// OrderStatus order status in the GetOrderStatus format
+	OrderStatus(symbol, orderID string) (map[string]interface{}, error)
+}
+
+// spotHolding average entry of the base quantity bought by this trader
+type spotHolding struct {
+	Quantity   float64
+	EntryPrice float64
+	OpenedAt   time.Time
+}
+
+// spotExit desired stop-loss/take-profit of a holding and the exchange order carrying them
+type spotExit struct {
+	Quantity    float64
+	StopPrice   float64
+	TargetPrice float64
+	OrderID     string
+}
+
+// SpotTrader Trader implementation for spot exchanges
+type SpotTrader struct {
+	venue spotVenue
+
+	mu       sync.Mutex
+	holdings map[string]*spotHolding // symbol -> tracked entry
+	exits    map[string]*spotExit    // symbol -> protective exit order
+	closed   []ClosedPnLRecord       // closes executed by this trader
+}
+
+// newSpotTrader wraps a spot venue into a Trader
+func newSpotTrader(venue spotVenue) *SpotTrader {
+	return &SpotTrader{
+		venue:    venue,
+		holdings: make(map[string]*spotHolding),
+		exits:    make(map[string]*spotExit),
+	}
+}
+
+// errSpotShort short selling is not possible on spot accounts
+func (t *SpotTrader) errSpotShort(symbol string) error {
+	return fmt.Errorf("%s is a spot exchange, short selling %s is not supported", t.venue.Name(), symbol)
+}
+
+// baseAsset base asset of a canonical symbol (BTCUSDT -> BTC)
+func (t *SpotTrader) baseAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range []string{"USDT", "USDC", t.venue.QuoteAsset(), "USD"} {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base
+		}
+	}
+	return symbol
+}
+
+// canonicalSymbol canonical symbol of a held base asset
+func (t *SpotTrader) canonicalSymbol(asset string) string {
+	return strings.ToUpper(asset) + "USDT"
+}
+
+// heldQuantity total base quantity held (free + locked)
+func (t *SpotTrader) heldQuantity(symbol string) (float64, error) {
+	balances, err := t.venue.Balances()
+	if err != nil {
+		return 0, err
+	}
+	b := balances[t.baseAsset(symbol)]
+	return b.Free + b.Locked, nil
+}
+
+// GetBalance returns quote balance plus the value of all held assets
+func (t *SpotTrader) GetBalance() (map[string]interface{}, error) {
+	balances, err := t.venue.Balances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot balances: %w", err)
+	}
+	quote := balances[t.venue.QuoteAsset()]
+	equity := quote.Free + quote.Locked
+	unrealized := 0.0
+	for asset, b := range balances {
+		if asset == t.venue.QuoteAsset() || b.Free+b.Locked <= 0 {
+			continue
+		}
+		symbol := t.canonicalSymbol(asset)
+		price, err := t.venue.Price(symbol)
+		if err != nil {
+			continue // Assets without a quote market (e.g. fee tokens on other pairs) are ignored
+		}
+		qty := b.Free + b.Locked
+		equity += qty * price
+		t.mu.Lock()
+		if h, ok := t.holdings[symbol]; ok && h.EntryPrice > 0 {
+			unrealized += (price - h.EntryPrice) * math.Min(qty, h.Quantity)
+		}
+		t.mu.Unlock()
+	}
+
+	return map[string]interface{}{
+		"totalWalletBalance":    equity - unrealized,
+		"availableBalance":      quote.Free,
+		"totalUnrealizedProfit": unrealized,
+		"totalEquity":           equity,
+	}, nil
+}
+
+// GetPositions returns held base assets as long positions
+func (t *SpotTrader) GetPositions() ([]Position, error) {
+	balances, err := t.venue.Balances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot balances: %w", err)
+	}
+
+	assets := make([]string, 0, len(balances))
+	for asset := range balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	var result []Position
+	for _, asset := range assets {
+		b := balances[asset]
+		qty := b.Free + b.Locked
+		if asset == t.venue.QuoteAsset() || qty <= 0 {
+			continue
+		}
+		symbol := t.canonicalSymbol(asset)
+		price, err := t.venue.Price(symbol)
+		if err != nil || qty*price < spotDustUSD {
+			continue
+		}
+
+		entry := price
+		t.mu.Lock()
+		if h, ok := t.holdings[symbol]; ok && h.EntryPrice > 0 {
+			entry = h.EntryPrice
+		}
+		t.mu.Unlock()
+
+		result = append(result, Position{
+			Symbol:        symbol,
+			Side:          "long",
+			Qty:           qty,
+			EntryPrice:    entry,
+			MarkPrice:     price,
+			UnrealizedPnL: (price - entry) * qty,
+			Leverage:      1,
+		})
+	}
+	return result, nil
+}
+
+// OpenLong buys the base asset (leverage is ignored)
+func (t *SpotTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if leverage > 1 {
+		logger.Infof("  ℹ %s is a spot exchange, ignoring %dx leverage", t.venue.Name(), leverage)
+	}
+	// Release exit orders first, new SL/TP are set for the whole holding after the fill
+	if err := t.clearExit(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old exit order: %v", err)
+	}
+
+	fill, err := t.venue.MarketOrder(symbol, "BUY", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to buy %s: %w", symbol, err)
+	}
+
+	t.mu.Lock()
+	h, ok := t.holdings[symbol]
+	if !ok {
+		h = &spotHolding{OpenedAt: time.Now()}
+		t.holdings[symbol] = h
+	}
+	total := h.Quantity + fill.Quantity
+	if total > 0 {
+		h.EntryPrice = (h.EntryPrice*h.Quantity + fill.AvgPrice*fill.Quantity) / total
+	}
+	h.Quantity = total
+	t.mu.Unlock()
+
+	logger.Infof("✓ Bought %s: %.8f @ %.4f (order %s)", symbol, fill.Quantity, fill.AvgPrice, fill.OrderID)
+	return map[string]interface{}{
+		"orderId":     fill.OrderID,
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    fill.AvgPrice,
+		"executedQty": fill.Quantity,
+	}, nil
+}
+
+// OpenShort is not supported on spot exchanges
+func (t *SpotTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return nil, t.errSpotShort(symbol)
+}
+
+// CloseLong sells the base asset (quantity=0 sells the whole holding)
+func (t *SpotTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// Balance locked by exit orders must be released before selling
+	t.mu.Lock()
+	exit := t.exits[symbol]
+	t.mu.Unlock()
+	if err := t.clearExit(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel exit order: %v", err)
+	}
+
+	held, err := t.heldQuantity(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 || quantity > held {
+		quantity = held
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("no %s holding found for %s", t.baseAsset(symbol), symbol)
+	}
+
+	fill, err := t.venue.MarketOrder(symbol, "SELL", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sell %s: %w", symbol, err)
+	}
+	logger.Infof("✓ Sold %s: %.8f @ %.4f (order %s)", symbol, fill.Quantity, fill.AvgPrice, fill.OrderID)
+	t.recordClose(symbol, fill)
+
+	// Keep the remaining holding protected
+	if exit != nil && held-fill.Quantity > 0 {
+		if _, err := t.placeExit(symbol, held-fill.Quantity, exit.StopPrice, exit.TargetPrice); err != nil {
+			logger.Warnf("⚠️ Failed to re-place exit order for remaining %s: %v", symbol, err)
+		}
+	}
+
+	return map[string]interface{}{
+		"orderId":     fill.OrderID,
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    fill.AvgPrice,
+		"executedQty": fill.Quantity,
+	}, nil
+}
+
+// CloseShort is not supported on spot exchanges
+func (t *SpotTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return nil, t.errSpotShort(symbol)
+}
+
+// recordClose books realized PnL of a sell against the tracked entry
+func (t *SpotTrader) recordClose(symbol string, fill *spotFill) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := ClosedPnLRecord{
+		Symbol:     symbol,
+		Side:       "long",
+		ExitPrice:  fill.AvgPrice,
+		Quantity:   fill.Quantity,
+		Fee:        fill.Fee,
+		Leverage:   1,
+		ExitTime:   time.Now(),
+		OrderID:    fill.OrderID,
+		ExchangeID: fill.OrderID,
+		CloseType:  "manual",
+	}
+	if h, ok := t.holdings[symbol]; ok {
+		record.EntryPrice = h.EntryPrice
+		record.EntryTime = h.OpenedAt
+		record.RealizedPnL = (fill.AvgPrice-h.EntryPrice)*fill.Quantity - fill.Fee
+		h.Quantity -= fill.Quantity
+		if h.Quantity <= 0 {
+			delete(t.holdings, symbol)
+		}
+	}
+	t.closed = append(t.closed, record)
+	if len(t.closed) > 500 {
+		t.closed = t.closed[len(t.closed)-500:]
+	}
+}
+
+// SetLeverage is a no-op on spot exchanges
+func (t *SpotTrader) SetLeverage(symbol string, leverage int) error {
+	return nil
+}
+
+// SetMarginMode is a no-op on spot exchanges
+func (t *SpotTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return nil
+}
+
+// GetMarketPrice returns last traded price
+func (t *SpotTrader) GetMarketPrice(symbol string) (float64, error) {
+	return t.venue.Price(symbol)
+}
+
+// SetStopLoss sets the stop of the holding's exit order
+func (t *SpotTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if strings.EqualFold(positionSide, "SHORT") {
+		return t.errSpotShort(symbol)
+	}
+	t.mu.Lock()
+	target := 0.0
+	if exit, ok := t.exits[symbol]; ok {
+		target = exit.TargetPrice
+	}
+	t.mu.Unlock()
+	if _, err := t.placeExit(symbol, quantity, stopPrice, target); err != nil {
+		return fmt.Errorf("failed to set stop-loss: %w", err)
+	}
+	logger.Infof("  Stop-loss price set: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets the target of the holding's exit order
+func (t *SpotTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if strings.EqualFold(positionSide, "SHORT") {
+		return t.errSpotShort(symbol)
+	}
+	t.mu.Lock()
+	stop := 0.0
+	if exit, ok := t.exits[symbol]; ok {
+		stop = exit.StopPrice
+	}
+	t.mu.Unlock()
+	if _, err := t.placeExit(symbol, quantity, stop, takeProfitPrice); err != nil {
+		return fmt.Errorf("failed to set take-profit: %w", err)
+	}
+	logger.Infof("  Take-profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// placeExit replaces the symbol's exit order with one carrying stop and target (both 0 = cancel only)
+// The quantity is capped to the held balance, buy fees paid in the base asset reduce it.
+func (t *SpotTrader) placeExit(symbol string, quantity, stopPrice, targetPrice float64) (string, error) {
+	if err := t.clearExit(symbol); err != nil {
+		return "", err
+	}
+	if stopPrice <= 0 && targetPrice <= 0 {
+		return "", nil
+	}
+	if held, err := t.heldQuantity(symbol); err == nil && (quantity <= 0 || quantity > held) {
+		quantity = held
+	}
+	if quantity <= 0 {
+		return "", fmt.Errorf("no %s holding to protect", t.baseAsset(symbol))
+	}
+
+	orderID, err := t.venue.PlaceExitOrder(symbol, quantity, stopPrice, targetPrice)
+	if err != nil {
+		return "", err
+	}
+	t.mu.Lock()
+	t.exits[symbol] = &spotExit{Quantity: quantity, StopPrice: stopPrice, TargetPrice: targetPrice, OrderID: orderID}
+	t.mu.Unlock()
+	return orderID, nil
+}
+
+// clearExit cancels the symbol's exit order and forgets it
+func (t *SpotTrader) clearExit(symbol string) error {
+	t.mu.Lock()
+	exit, ok := t.exits[symbol]
+	delete(t.exits, symbol)
+	t.mu.Unlock()
+	if !ok || exit.OrderID == "" {
+		return nil
+	}
+	if err := t.venue.CancelOrder(symbol, exit.OrderID); err != nil {
+		// Already filled or cancelled on the exchange, nothing left to release
+		logger.Infof("  ⚠ Failed to cancel exit order %s: %v", exit.OrderID, err)
+	}
+	return nil
+}
+
+// CancelStopLossOrders removes the stop, keeping the target
+func (t *SpotTrader) CancelStopLossOrders(symbol string) error {
+	t.mu.Lock()
+	exit, ok := t.exits[symbol]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	_, err := t.placeExit(symbol, exit.Quantity, 0, exit.TargetPrice)
+	return err
+}
+
+// CancelTakeProfitOrders removes the target, keeping the stop
+func (t *SpotTrader) CancelTakeProfitOrders(symbol string) error {
+	t.mu.Lock()
+	exit, ok := t.exits[symbol]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	_, err := t.placeExit(symbol, exit.Quantity, exit.StopPrice, 0)
+	return err
+}
+
+// CancelAllOrders cancels all open orders of the symbol
+func (t *SpotTrader) CancelAllOrders(symbol string) error {
+	t.mu.Lock()
+	delete(t.exits, symbol)
+	t.mu.Unlock()
+	return t.venue.CancelAll(symbol)
+}
+
+// CancelStopOrders cancels the exit order (stop and target)
+func (t *SpotTrader) CancelStopOrders(symbol string) error {
+	return t.clearExit(symbol)
+}
+
+// FormatQuantity rounds quantity to the symbol's lot size
+func (t *SpotTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return t.venue.FormatQuantity(symbol, quantity)
+}
+
+// GetOrderStatus gets order status
+func (t *SpotTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.venue.OrderStatus(symbol, orderID)
+}
+
+// GetClosedPnL returns sells executed by this trader since startTime
+// Spot exchanges report no realized PnL; exits filled by exit orders are detected by position sync.
+func (t *SpotTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var records []ClosedPnLRecord
+	for _, r := range t.closed {
+		if !r.ExitTime.Before(startTime) {
+			records = append(records, r)
+		}
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
@@ -0,0 +1,426 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Strategy Hot-Reload
+// ============================================================================
+// Strategy edits made from the UI are pushed to running traders through
+// UpdateStrategyConfig. The new config is validated and staged immediately,
+// but only swapped in at the start of the next cycle so a cycle never runs
+// with half-old / half-new indicators, risk limits or prompts.
+
+// UpdateStrategyConfig validates and stages new strategy configuration
+// Returns the version number the config will run as. If the trader is not running,
+// the config is applied immediately; otherwise it takes effect on the next cycle.
+func (at *AutoTrader) UpdateStrategyConfig(cfg *store.StrategyConfig) (int, error) {
+	if err := ValidateStrategyConfig(cfg); err != nil {
+		return 0, fmt.Errorf("invalid strategy config: %w", err)
+	}
+
+	// Deep copy so later edits by the caller cannot leak into a running cycle
+	snapshot, err := cloneStrategyConfig(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	at.strategyMu.Lock()
+	defer at.strategyMu.Unlock()
+
+	at.strategyVersion++
+	at.pendingStrategy = snapshot
+	version := at.strategyVersion
+
+	if !at.isRunning.Load() {
+		at.applyPendingStrategyLocked()
+		logger.Infof("🔄 [%s] Strategy config v%d applied", at.name, version)
+	} else {
+		logger.Infof("🔄 [%s] Strategy config v%d staged, will take effect on next cycle", at.name, version)
+	}
+	return version, nil
+}
+
+// GetStrategyVersion gets version of the strategy config currently in use
+// Version starts at 1 (config loaded at startup) and increments on every accepted update.
+func (at *AutoTrader) GetStrategyVersion() int {
+	at.strategyMu.RLock()
+	defer at.strategyMu.RUnlock()
+	return at.activeStrategyVersion
+}
+
+// engine gets the strategy engine in use
+// The engine is swapped at cycle start, so code outside the cycle (monitors, API) must not read at.strategyEngine directly.
+func (at *AutoTrader) engine() *decision.StrategyEngine {
+	at.strategyMu.RLock()
+	defer at.strategyMu.RUnlock()
+	return at.strategyEngine
+}
+
+// strategyConfig gets the strategy config in use (swapped together with the engine)
+func (at *AutoTrader) strategyConfig() *store.StrategyConfig {
+	at.strategyMu.RLock()
+	defer at.strategyMu.RUnlock()
+	return at.config.StrategyConfig
+}
+
+// GetStrategyID gets ID of the strategy this trader runs
+func (at *AutoTrader) GetStrategyID() string {
+	return at.config.StrategyID
+}
+
+// applyPendingStrategy swaps in staged strategy config (called at cycle start)
+// Returns applied version, or 0 if nothing was pending.
+func (at *AutoTrader) applyPendingStrategy() int {
+	at.strategyMu.Lock()
+	defer at.strategyMu.Unlock()
+	return at.applyPendingStrategyLocked()
+}
+
+// applyPendingStrategyLocked swaps strategy engine, caller must hold strategyMu
+func (at *AutoTrader) applyPendingStrategyLocked() int {
+	if at.pendingStrategy == nil {
+		return 0
+	}
+	at.strategyEngine = decision.NewStrategyEngine(at.pendingStrategy)
+	at.strategyEngine.SetStrategyID(at.config.StrategyID)
+	at.mcpClient.SetGenerationParams(at.strategyEngine.GetGenerationParams())
+	at.config.StrategyConfig = at.pendingStrategy
+	at.pendingStrategy = nil
+	at.activeStrategyVersion = at.strategyVersion
+	at.strategyUpdatedAt = time.Now()
+	return at.activeStrategyVersion
+}
+
+// ValidateStrategyConfig rejects configurations that would break the trading loop
+func ValidateStrategyConfig(cfg *store.StrategyConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := validateTimeframes(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Indicators.QuantDataMaxConcurrent < 0 {
+		return fmt.Errorf("quant_data_max_concurrent cannot be negative")
+	}
+	if cfg.Indicators.QuantDataCacheTTLSec < 0 {
+		return fmt.Errorf("quant_data_cache_ttl_sec cannot be negative")
+	}
+	switch cfg.Indicators.OIRankingSource.AuthScheme {
+	case "", "none", "query", "bearer", "header":
+	default:
+		return fmt.Errorf("oi_ranking_source.auth_scheme must be none, query, bearer or header")
+	}
+	if cfg.Execution.LimitEntryExpiryMinutes < 0 {
+		return fmt.Errorf("limit_entry_expiry_minutes cannot be negative")
+	}
+	if cfg.ToolCalling.MaxCalls < 0 {
+		return fmt.Errorf("tool_calling.max_calls cannot be negative")
+	}
+	if cfg.Ensemble.Enabled && (len(cfg.Ensemble.AIModelIDs) < 1 || len(cfg.Ensemble.AIModelIDs) > 2) {
+		return fmt.Errorf("ensemble.ai_model_ids must list 1-2 additional AI models")
+	}
+	switch cfg.Ensemble.MergeRule {
+	case "", "unanimous", "majority", "highest_confidence":
+	default:
+		return fmt.Errorf("invalid ensemble.merge_rule: %s", cfg.Ensemble.MergeRule)
+	}
+	br := cfg.BatchRetry
+	if br.MaxRetries < 0 || br.BaseDelayMs < 0 || br.MaxDelayMs < 0 {
+		return fmt.Errorf("batch_retry settings cannot be negative")
+	}
+	if br.MaxRetries > 5 {
+		return fmt.Errorf("batch_retry.max_retries must be at most 5")
+	}
+	if br.MaxDelayMs > 0 && br.BaseDelayMs > br.MaxDelayMs {
+		return fmt.Errorf("batch_retry.base_delay_ms (%d) cannot exceed max_delay_ms (%d)", br.BaseDelayMs, br.MaxDelayMs)
+	}
+	switch cfg.DecisionParsing.Strictness {
+	case "", store.ParseStrictnessLenient, store.ParseStrictnessStrict:
+	default:
+		return fmt.Errorf("invalid decision_parsing.strictness: %s", cfg.DecisionParsing.Strictness)
+	}
+	if cfg.AIResponseCache.FreshnessMinutes < 0 || cfg.AIResponseCache.PriceBucketPct < 0 {
+		return fmt.Errorf("ai_response_cache settings cannot be negative")
+	}
+	if cfg.AIResponseCache.PriceBucketPct > 10 {
+		return fmt.Errorf("ai_response_cache.price_bucket_pct must be at most 10")
+	}
+	if cfg.AILatency.SLASeconds < 0 {
+		return fmt.Errorf("ai_latency.sla_seconds cannot be negative")
+	}
+	switch cfg.AILatency.OnTimeout {
+	case "", store.AITimeoutFallback, store.AITimeoutSkip:
+	default:
+		return fmt.Errorf("invalid ai_latency.on_timeout: %s", cfg.AILatency.OnTimeout)
+	}
+	ap := cfg.AIParams
+	if ap.Temperature != nil && (*ap.Temperature < 0 || *ap.Temperature > 2) {
+		return fmt.Errorf("ai_params.temperature must be between 0 and 2")
+	}
+	if ap.TopP != nil && (*ap.TopP <= 0 || *ap.TopP > 1) {
+		return fmt.Errorf("ai_params.top_p must be greater than 0 and at most 1")
+	}
+	if ap.MaxTokens < 0 {
+		return fmt.Errorf("ai_params.max_tokens cannot be negative")
+	}
+	if !mcp.IsValidReasoningEffort(ap.ReasoningEffort) {
+		return fmt.Errorf("ai_params.reasoning_effort must be %q, %q or %q, got %q", mcp.ReasoningEffortLow, mcp.ReasoningEffortMedium, mcp.ReasoningEffortHigh, ap.ReasoningEffort)
+	}
+	eq := cfg.EquityRisk
+	if eq.LookbackHours < 0 || eq.DrawdownPct < 0 || eq.RecoveryPct < 0 || eq.RiskScale < 0 {
+		return fmt.Errorf("equity_risk settings cannot be negative")
+	}
+	if eq.RiskScale > 1 {
+		return fmt.Errorf("equity_risk.risk_scale must be between 0 and 1")
+	}
+	if eq.DrawdownPct > 0 && eq.RecoveryPct >= eq.DrawdownPct {
+		return fmt.Errorf("equity_risk.recovery_pct (%.2f) must be below drawdown_pct (%.2f)", eq.RecoveryPct, eq.DrawdownPct)
+	}
+	if !store.IsValidPromptLanguage(cfg.Language) {
+		return fmt.Errorf("invalid language: %s (supported: %s, %s)", cfg.Language, store.PromptLanguageEnglish, store.PromptLanguageChinese)
+	}
+	shock := cfg.Shock
+	if shock.MovePct < 0 || shock.WindowMinutes < 0 || shock.ATRZScore < 0 || shock.StopPct < 0 {
+		return fmt.Errorf("shock settings cannot be negative")
+	}
+	if cfg.Annotations.MaxItems < 0 || cfg.Annotations.LookbackDays < 0 {
+		return fmt.Errorf("annotations.max_items and annotations.lookback_days cannot be negative")
+	}
+	if cfg.SessionMemory.Cycles < 0 || cfg.SessionMemory.MaxChars < 0 {
+		return fmt.Errorf("session_memory.cycles and session_memory.max_chars cannot be negative")
+	}
+	churn := cfg.ChurnGuard
+	if churn.LookbackCycles < 0 || churn.MinPriceMovePct < 0 || churn.MinRSIChange < 0 {
+		return fmt.Errorf("churn_guard settings cannot be negative")
+	}
+	if churn.Action != "" && churn.Action != store.ChurnActionFlag && churn.Action != store.ChurnActionSuppress {
+		return fmt.Errorf("invalid churn_guard.action: %s (supported: %s, %s)", churn.Action, store.ChurnActionFlag, store.ChurnActionSuppress)
+	}
+	switch cfg.Execution.PriceGuardAction {
+	case "", store.PriceGuardAbort, store.PriceGuardReprice:
+	default:
+		return fmt.Errorf("invalid execution.price_guard_action: %s (supported: %s, %s)", cfg.Execution.PriceGuardAction, store.PriceGuardAbort, store.PriceGuardReprice)
+	}
+	switch cfg.Execution.MaintenanceAction {
+	case "", store.MaintenanceDefer, store.MaintenanceMonitor:
+	default:
+		return fmt.Errorf("invalid execution.maintenance_action: %s (supported: %s, %s)", cfg.Execution.MaintenanceAction, store.MaintenanceDefer, store.MaintenanceMonitor)
+	}
+	gov := cfg.TradeGovernor
+	if gov.MaxOpensPerHour < 0 || gov.MaxOpensPerDay < 0 || gov.MaxOpensPerSymbolPerHour < 0 || gov.MaxOpensPerSymbolPerDay < 0 {
+		return fmt.Errorf("trade_governor limits cannot be negative")
+	}
+	hooks := cfg.ContextHooks
+	if hooks.TimeoutSeconds < 0 || hooks.TimeoutSeconds > 60 {
+		return fmt.Errorf("context_hooks.timeout_seconds must be between 0 and 60")
+	}
+	if len(hooks.Scripts) > 5 {
+		return fmt.Errorf("context_hooks.scripts has %d entries (max 5)", len(hooks.Scripts))
+	}
+	for _, script := range hooks.Scripts {
+		if script == "" || script == "." || script == ".." || strings.ContainsAny(script, `/\`) {
+			return fmt.Errorf("invalid context_hooks script %q: must be a file name in the context hooks directory", script)
+		}
+	}
+	rank := cfg.CandidateRanking
+	if rank.MaxCandidates < 0 || rank.MomentumWeight < 0 || rank.VolumeWeight < 0 || rank.OIWeight < 0 || rank.NewsWeight < 0 {
+		return fmt.Errorf("candidate_ranking.max_candidates and weights cannot be negative")
+	}
+	if cfg.PromptBudget.MaxTokens < 0 || cfg.PromptBudget.MinBars < 0 {
+		return fmt.Errorf("prompt_budget.max_tokens and prompt_budget.min_bars cannot be negative")
+	}
+	if cfg.PromptBudget.Enabled && cfg.PromptBudget.MaxTokens > 0 && cfg.PromptBudget.MaxTokens < 1000 {
+		return fmt.Errorf("prompt_budget.max_tokens must be at least 1000")
+	}
+	if strings.ContainsAny(cfg.Benchmark.Symbol, " /") {
+		return fmt.Errorf("invalid benchmark.symbol: %q", cfg.Benchmark.Symbol)
+	}
+	gridSymbols := make(map[string]bool)
+	for _, grid := range cfg.Grid.Grids {
+		if grid.Symbol == "" || strings.ContainsAny(grid.Symbol, " /") || gridSymbols[grid.Symbol] {
+			return fmt.Errorf("invalid or duplicate grid symbol %q", grid.Symbol)
+		}
+		gridSymbols[grid.Symbol] = true
+		if grid.LowerPrice <= 0 || grid.UpperPrice <= grid.LowerPrice {
+			return fmt.Errorf("grid %s: upper_price must be above lower_price > 0", grid.Symbol)
+		}
+		if grid.Levels < 2 || grid.Levels > maxGridLevels {
+			return fmt.Errorf("grid %s: levels must be between 2 and %d", grid.Symbol, maxGridLevels)
+		}
+		if grid.LevelSizeUSD <= 0 || grid.TakeProfitPct <= 0 || grid.Leverage < 0 {
+			return fmt.Errorf("grid %s: level_size_usd and take_profit_pct must be positive, leverage cannot be negative", grid.Symbol)
+		}
+	}
+
+	source := cfg.CoinSource
+	if source.WebhookLimit < 0 || source.WebhookTTLMinutes < 0 {
+		return fmt.Errorf("webhook_limit and webhook_ttl_minutes cannot be negative")
+	}
+	// A redacted config from the API (dry run) only carries webhook_secret_set
+	if (source.SourceType == "webhook" || (source.SourceType == "mixed" && source.UseWebhook)) && source.WebhookSecret == "" && !source.WebhookSecretSet {
+		return fmt.Errorf("webhook_secret is required for the webhook candidate source")
+	}
+	if source.SocialTrendingLimit < 0 || source.SocialTrendingMinMentions < 0 {
+		return fmt.Errorf("social_trending_limit and social_trending_min_mentions cannot be negative")
+	}
+	if (source.SourceType == "social_trending" || (source.SourceType == "mixed" && source.UseSocialTrending)) && strings.TrimSpace(source.SocialTrendingAPIURL) == "" {
+		return fmt.Errorf("social_trending_api_url is required for the social_trending candidate source")
+	}
+	if source.MinOIValueUSD < 0 {
+		return fmt.Errorf("min_oi_value_usd cannot be negative")
+	}
+	for name, value := range source.SourceMinOIValueUSD {
+		if value < 0 {
+			return fmt.Errorf("source_min_oi_value_usd of %s cannot be negative", name)
+		}
+	}
+
+	rc := cfg.RiskControl
+	for symbol, override := range rc.SymbolOverrides {
+		if strings.TrimSpace(symbol) == "" {
+			return fmt.Errorf("symbol_overrides cannot contain an empty symbol")
+		}
+		if override.MaxPositionUSD < 0 || override.MaxLeverage < 0 {
+			return fmt.Errorf("symbol override for %s cannot be negative", symbol)
+		}
+		if override.MinConfidence < 0 || override.MinConfidence > 100 {
+			return fmt.Errorf("symbol override min_confidence for %s must be between 0 and 100", symbol)
+		}
+		if override.MaxPositionUSD > 0 && rc.MinPositionSize > override.MaxPositionUSD {
+			return fmt.Errorf("min_position_size (%.2f) exceeds max_position_usd of %s (%.2f)", rc.MinPositionSize, symbol, override.MaxPositionUSD)
+		}
+	}
+	if rc.CloseAtEOD && rc.CloseAtEODTime != "" {
+		if _, err := time.Parse("15:04", rc.CloseAtEODTime); err != nil {
+			return fmt.Errorf("close_at_eod_time must be HH:MM, got %q", rc.CloseAtEODTime)
+		}
+	}
+	switch rc.EODPolicy {
+	case "", EODFlatten, EODReduce, EODHoldStops:
+	default:
+		return fmt.Errorf("eod_policy must be %q, %q or %q, got %q", EODFlatten, EODReduce, EODHoldStops, rc.EODPolicy)
+	}
+	if rc.EODReducePct < 0 || rc.EODReducePct > 100 {
+		return fmt.Errorf("eod_reduce_pct must be between 0 and 100")
+	}
+	if rc.EODStopPct < 0 {
+		return fmt.Errorf("eod_stop_pct cannot be negative")
+	}
+	if rc.EODMinutesBeforeClose < 0 || rc.EODMinutesBeforeClose > 390 {
+		return fmt.Errorf("eod_minutes_before_close must be between 0 and 390")
+	}
+	switch rc.ShutdownPolicy {
+	case "", ShutdownLeave, ShutdownTightenStops, ShutdownFlatten:
+	default:
+		return fmt.Errorf("shutdown_policy must be %q, %q or %q, got %q", ShutdownLeave, ShutdownTightenStops, ShutdownFlatten, rc.ShutdownPolicy)
+	}
+	if rc.ShutdownStopPct < 0 {
+		return fmt.Errorf("shutdown_stop_pct cannot be negative")
+	}
+	if rc.StopATRMinMultiple < 0 || rc.StopATRMaxMultiple < 0 {
+		return fmt.Errorf("stop ATR multiples cannot be negative")
+	}
+	if rc.StopATRMaxMultiple > 0 && rc.StopATRMinMultiple > rc.StopATRMaxMultiple {
+		return fmt.Errorf("stop_atr_min_multiple (%.2f) exceeds stop_atr_max_multiple (%.2f)", rc.StopATRMinMultiple, rc.StopATRMaxMultiple)
+	}
+	if rc.VolTargetDailyRiskPct < 0 || rc.VolTargetDailyRiskPct > 100 {
+		return fmt.Errorf("vol_target_daily_risk_pct must be between 0 and 100")
+	}
+	if rc.LiquidationStopBufferPct < 0 || rc.LiquidationAlertPct < 0 {
+		return fmt.Errorf("liquidation guard settings cannot be negative")
+	}
+	if rc.LiquidationStopBufferPct >= 100 || rc.LiquidationAlertPct >= 100 {
+		return fmt.Errorf("liquidation guard percentages must be below 100")
+	}
+	if rc.EarningsBlackoutHours < 0 || rc.MacroBlackoutHoursBefore < 0 || rc.MacroBlackoutHoursAfter < 0 {
+		return fmt.Errorf("blackout hours cannot be negative")
+	}
+	for _, event := range rc.MacroBlackoutEvents {
+		if strings.TrimSpace(event) == "" {
+			return fmt.Errorf("macro_blackout_events cannot contain empty names")
+		}
+	}
+	if isNegativePct(rc.DrawdownActivationPct) || isNegativePct(rc.DrawdownClosePct) || rc.DrawdownCheckIntervalSec < 0 {
+		return fmt.Errorf("drawdown monitor settings cannot be negative")
+	}
+	for symbol, rule := range rc.DrawdownOverrides {
+		if isNegativePct(rule.ActivationPct) || isNegativePct(rule.ClosePct) {
+			return fmt.Errorf("drawdown override for %s cannot be negative", symbol)
+		}
+	}
+	if rc.MaxHoldMinutes < 0 || rc.MaxHoldReviewGraceMinutes < 0 {
+		return fmt.Errorf("max hold minutes cannot be negative")
+	}
+	switch rc.MaxHoldAction {
+	case "", MaxHoldActionClose, MaxHoldActionReview:
+	default:
+		return fmt.Errorf("max_hold_action must be %q or %q, got %q", MaxHoldActionClose, MaxHoldActionReview, rc.MaxHoldAction)
+	}
+	if rc.EquityFloorUSD < 0 || rc.EquityFloorPct < 0 || rc.EquityFloorPct >= 100 {
+		return fmt.Errorf("equity_floor_usd cannot be negative and equity_floor_pct must be between 0 and 100")
+	}
+	switch rc.EquityFloorAction {
+	case "", EquityFloorFreeze, EquityFloorFlatten:
+	default:
+		return fmt.Errorf("equity_floor_action must be %q or %q, got %q", EquityFloorFreeze, EquityFloorFlatten, rc.EquityFloorAction)
+	}
+	return nil
+}
+
+// isNegativePct whether an optional percentage is set below zero (nil = default)
+func isNegativePct(pct *float64) bool {
+	return pct != nil && *pct < 0
+}
+
+// validateTimeframes rejects kline timeframes the market data layer does not support
+func validateTimeframes(cfg *store.StrategyConfig) error {
+	var errs store.ConfigErrors
+	check := func(field, tf string) {
+		if tf == "" {
+			return
+		}
+		if _, err := market.NormalizeTimeframe(tf); err != nil {
+			errs = append(errs, &store.ConfigError{Field: field, Reason: fmt.Sprintf("%q is not supported (supported: %s)", tf, strings.Join(market.SupportedTimeframes(), ", "))})
+		}
+	}
+	k := cfg.Indicators.Klines
+	check("indicators.klines.primary_timeframe", k.PrimaryTimeframe)
+	check("indicators.klines.longer_timeframe", k.LongerTimeframe)
+	for i, tf := range k.SelectedTimeframes {
+		check(fmt.Sprintf("indicators.klines.selected_timeframes[%d]", i), tf)
+	}
+	for i, tf := range cfg.Indicators.ConfluenceTimeframes {
+		check(fmt.Sprintf("indicators.confluence_timeframes[%d]", i), tf)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// cloneStrategyConfig deep copies strategy config via JSON round trip
+func cloneStrategyConfig(cfg *store.StrategyConfig) (*store.StrategyConfig, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize strategy config: %w", err)
+	}
+	var clone store.StrategyConfig
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to copy strategy config: %w", err)
+	}
+	return &clone, nil
+}
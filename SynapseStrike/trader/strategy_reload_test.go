@@ -0,0 +1,153 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/mcp"
+	"SynapseStrike/store"
+	"sync"
+	"testing"
+)
+
+// newReloadTestTrader creates a trader running the default strategy as version 1
+func newReloadTestTrader(running bool) *AutoTrader {
+	cfg := store.GetDefaultStrategyConfig("en")
+	at := &AutoTrader{
+		name:                  "test",
+		config:                AutoTraderConfig{StrategyConfig: &cfg},
+		strategyEngine:        decision.NewStrategyEngine(&cfg),
+		mcpClient:             mcp.NewMockClient(),
+		strategyVersion:       1,
+		activeStrategyVersion: 1,
+	}
+	at.isRunning.Store(running)
+	return at
+}
+
+func TestUpdateStrategyConfigAppliesAtCycleBoundary(t *testing.T) {
+	at := newReloadTestTrader(true)
+
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.MaxPositions = 7
+	version, err := at.UpdateStrategyConfig(&cfg)
+	if err != nil {
+		t.Fatalf("UpdateStrategyConfig: %v", err)
+	}
+	if version != 2 {
+		t.Errorf("staged version = %d, want 2", version)
+	}
+	cfg.RiskControl.MaxPositions = 9 // Caller edits after the update must not leak in
+
+	// Running cycle keeps the old config until the next cycle starts
+	if got := at.GetStrategyVersion(); got != 1 {
+		t.Errorf("active version before cycle = %d, want 1", got)
+	}
+	if got := at.engine().GetConfig().RiskControl.MaxPositions; got == 7 {
+		t.Error("staged config applied before the cycle boundary")
+	}
+
+	if applied := at.applyPendingStrategy(); applied != 2 {
+		t.Errorf("applyPendingStrategy = %d, want 2", applied)
+	}
+	if got := at.engine().GetConfig().RiskControl.MaxPositions; got != 7 {
+		t.Errorf("engine max positions = %d, want 7", got)
+	}
+	if got := at.strategyConfig().RiskControl.MaxPositions; got != 7 {
+		t.Errorf("config max positions = %d, want 7", got)
+	}
+	if applied := at.applyPendingStrategy(); applied != 0 {
+		t.Errorf("second applyPendingStrategy = %d, want 0 (nothing pending)", applied)
+	}
+}
+
+func TestUpdateStrategyConfigStoppedTraderAppliesImmediately(t *testing.T) {
+	at := newReloadTestTrader(false)
+
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.MaxPositions = 4
+	if _, err := at.UpdateStrategyConfig(&cfg); err != nil {
+		t.Fatalf("UpdateStrategyConfig: %v", err)
+	}
+	if got := at.GetStrategyVersion(); got != 2 {
+		t.Errorf("active version = %d, want 2", got)
+	}
+	if got := at.engine().GetConfig().RiskControl.MaxPositions; got != 4 {
+		t.Errorf("engine max positions = %d, want 4", got)
+	}
+}
+
+func TestUpdateStrategyConfigRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cfg *store.StrategyConfig)
+	}{
+		{"unknown shutdown policy", func(cfg *store.StrategyConfig) { cfg.RiskControl.ShutdownPolicy = "panic" }},
+		{"unsupported timeframe", func(cfg *store.StrategyConfig) { cfg.Indicators.Klines.PrimaryTimeframe = "7m" }},
+		{"negative limit entry expiry", func(cfg *store.StrategyConfig) { cfg.Execution.LimitEntryExpiryMinutes = -1 }},
+		{"too many batch retries", func(cfg *store.StrategyConfig) { cfg.BatchRetry.MaxRetries = 6 }},
+		{"negative drawdown close pct", func(cfg *store.StrategyConfig) {
+			closePct := -10.0
+			cfg.RiskControl.DrawdownClosePct = &closePct
+		}},
+		{"negative drawdown override", func(cfg *store.StrategyConfig) {
+			activationPct := -1.0
+			cfg.RiskControl.DrawdownOverrides = map[string]store.DrawdownRule{"AAPL": {ActivationPct: &activationPct}}
+		}},
+		{"invalid grid range", func(cfg *store.StrategyConfig) {
+			cfg.Grid.Grids = []store.GridSpec{{Symbol: "AAPL", LowerPrice: 200, UpperPrice: 100, Levels: 5, LevelSizeUSD: 100, TakeProfitPct: 1}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := newReloadTestTrader(true)
+			cfg := store.GetDefaultStrategyConfig("en")
+			tt.mutate(&cfg)
+			if _, err := at.UpdateStrategyConfig(&cfg); err == nil {
+				t.Fatal("UpdateStrategyConfig accepted an invalid config")
+			}
+			if at.pendingStrategy != nil || at.strategyVersion != 1 {
+				t.Errorf("rejected config was staged (version %d)", at.strategyVersion)
+			}
+		})
+	}
+
+	if _, err := newReloadTestTrader(true).UpdateStrategyConfig(nil); err == nil {
+		t.Error("UpdateStrategyConfig accepted a nil config")
+	}
+}
+
+// TestStrategyConfigReadsDuringReload readers outside the cycle race with the swap (run with -race)
+func TestStrategyConfigReadsDuringReload(t *testing.T) {
+	at := newReloadTestTrader(true)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				at.maxMarginUsage()
+				at.shutdownPolicy()
+				_ = at.strategyConfig().Execution
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		cfg := store.GetDefaultStrategyConfig("en")
+		cfg.RiskControl.MaxPositions = i + 1
+		if _, err := at.UpdateStrategyConfig(&cfg); err != nil {
+			t.Fatalf("UpdateStrategyConfig: %v", err)
+		}
+		at.applyPendingStrategy()
+	}
+	close(stop)
+	wg.Wait()
+
+	if got := at.GetStrategyVersion(); got != 21 {
+		t.Errorf("active version = %d, want 21", got)
+	}
+}
@@ -0,0 +1,84 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestValidateStrategyConfig tests typed field errors of invalid strategy configs
+func TestValidateStrategyConfig(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	if err := ValidateStrategyConfig(&cfg); err != nil {
+		t.Fatalf("default config rejected: %v", err)
+	}
+
+	cfg.Indicators.Klines.PrimaryTimeframe = "0m"
+	cfg.Indicators.Klines.SelectedTimeframes = []string{"5m", ""}
+	cfg.RiskControl.MaxMarginUsage = 1.5
+	cfg.RiskControl.LargeCapMaxPositionValueRatio = -1
+	cfg.CoinSource.SourceType = "static"
+	cfg.CoinSource.StaticStocks = nil
+
+	err := ValidateStrategyConfig(&cfg)
+	if !errors.Is(err, store.ErrInvalidStrategyConfig) {
+		t.Fatalf("expected ErrInvalidStrategyConfig, got %v", err)
+	}
+	var configErrs store.ConfigErrors
+	if !errors.As(err, &configErrs) {
+		t.Fatalf("expected ConfigErrors, got %T", err)
+	}
+	fields := make(map[string]bool)
+	for _, e := range configErrs {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{
+		"indicators.klines.primary_timeframe",
+		"indicators.klines.selected_timeframes[1]",
+		"risk_control.max_margin_usage",
+		"risk_control.large_cap_max_position_value_ratio",
+		"stock_source.static_stocks",
+	} {
+		if !fields[field] {
+			t.Errorf("missing error for %s (got %v)", field, err)
+		}
+	}
+
+	// Well-formed but unsupported timeframes are rejected by the trader
+	cfg = store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.Klines.LongerTimeframe = "7m"
+	errs := store.AsConfigErrors(ValidateStrategyConfig(&cfg))
+	if len(errs) != 1 || errs[0].Field != "indicators.klines.longer_timeframe" {
+		t.Errorf("expected longer_timeframe error, got %v", errs)
+	}
+}
+
+// TestStrategyConfigMigration tests migration of legacy coin fields on load
+func TestStrategyConfigMigration(t *testing.T) {
+	raw, _ := json.Marshal(map[string]interface{}{
+		"stock_source": map[string]interface{}{
+			"source_type":       "coinpool",
+			"static_coins":      []string{"AAPL", "MSFT"},
+			"use_coin_pool":     true,
+			"coin_pool_limit":   15,
+			"coin_pool_api_url": "https://pool.example/api",
+		},
+	})
+	strategy := &store.Strategy{ID: "s-1", Config: string(raw)}
+	cfg, err := strategy.ParseConfig()
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+
+	src := cfg.CoinSource
+	if src.SourceType != "stockpool" || !src.UseStockPool || src.StockPoolLimit != 15 || src.StockPoolAPIURL != "https://pool.example/api" {
+		t.Errorf("legacy pool fields not migrated: %+v", src)
+	}
+	if len(src.StaticStocks) != 2 || src.StaticCoins != nil || src.UseCoinPool || src.CoinPoolLimit != 0 || src.CoinPoolAPIURL != "" {
+		t.Errorf("legacy static fields not migrated: %+v", src)
+	}
+	if applied := cfg.Migrate(); len(applied) != 0 {
+		t.Errorf("migration is not idempotent: %v", applied)
+	}
+}
@@ -0,0 +1,199 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Leverage Stress Report
+// ============================================================================
+// "What if" view of the open positions at their current leverage: for each
+// move size (default 2%, 5%, 10%) the equity impact of
+//   - each position moving against itself
+//   - all positions moving against themselves at once (worst case)
+//   - the whole market moving down and up together (longs and shorts offset)
+// Positions whose liquidation price lies within a scenario's move are listed,
+// since their loss ends in liquidation (isolated margin: at most the margin,
+// cross margin: the account's equity absorbs it). Served by the status API
+// (GET /api/stress-report) and summarized on the competition page.
+
+// DefaultStressMoves adverse price moves in percent evaluated when none are requested
+var DefaultStressMoves = []float64{2, 5, 10}
+
+// maxStressMovePct largest move accepted (a 100% adverse move takes a long to zero)
+const maxStressMovePct = 100
+
+// StressScenario equity impact of one price move
+type StressScenario struct {
+	MovePct         float64  `json:"move_pct"`          // Price move in percent (negative = down)
+	PnL             float64  `json:"pnl"`               // Change in unrealized PnL
+	EquityAfter     float64  `json:"equity_after"`      // Equity after the move
+	EquityImpactPct float64  `json:"equity_impact_pct"` // PnL in % of current equity
+	Liquidated      []string `json:"liquidated,omitempty"`
+}
+
+// PositionStress adverse move scenarios of one open position
+type PositionStress struct {
+	Symbol           string           `json:"symbol"`
+	Side             string           `json:"side"` // long/short
+	Quantity         float64          `json:"quantity"`
+	MarkPrice        float64          `json:"mark_price"`
+	Notional         float64          `json:"notional"` // Quantity × mark price
+	Leverage         int              `json:"leverage"`
+	LiquidationPrice float64          `json:"liquidation_price,omitempty"`
+	LiquidationPct   float64          `json:"liquidation_pct,omitempty"` // Adverse move to liquidation in %
+	Scenarios        []StressScenario `json:"scenarios"`                 // Adverse move per size
+}
+
+// StressReport leverage stress test of a trader's open positions
+type StressReport struct {
+	TraderID          string           `json:"trader_id"`
+	GeneratedAt       time.Time        `json:"generated_at"`
+	TotalEquity       float64          `json:"total_equity"`
+	GrossExposure     float64          `json:"gross_exposure"`     // Sum of notionals
+	NetExposure       float64          `json:"net_exposure"`       // Long notional - short notional
+	EffectiveLeverage float64          `json:"effective_leverage"` // Gross exposure / equity
+	Positions         []PositionStress `json:"positions"`
+	Combined          []StressScenario `json:"combined"`    // Every position moves against itself
+	MarketDown        []StressScenario `json:"market_down"` // All prices fall by the move
+	MarketUp          []StressScenario `json:"market_up"`   // All prices rise by the move
+}
+
+// WorstCase combined scenario of the largest move (nil without scenarios)
+func (r *StressReport) WorstCase() *StressScenario {
+	if len(r.Combined) == 0 {
+		return nil
+	}
+	return &r.Combined[len(r.Combined)-1]
+}
+
+// ParseStressMoves parses a comma-separated list of move sizes in percent (empty = DefaultStressMoves)
+func ParseStressMoves(s string) ([]float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultStressMoves, nil
+	}
+	var moves []float64
+	for _, part := range strings.Split(s, ",") {
+		move, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid move %q", part)
+		}
+		move = math.Abs(move)
+		if move == 0 || move > maxStressMovePct {
+			return nil, fmt.Errorf("move must be between 0 and %d%%: %q", maxStressMovePct, part)
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// GetStressReport stress test of the trader's open positions at the given move sizes (nil = defaults)
+func (at *AutoTrader) GetStressReport(moves []float64) (*StressReport, error) {
+	account, err := at.GetAccountInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	equity, _ := account["total_equity"].(float64)
+	return at.GetStressReportForEquity(equity, moves)
+}
+
+// GetStressReportForEquity stress test of the open positions against an already known equity
+func (at *AutoTrader) GetStressReportForEquity(equity float64, moves []float64) (*StressReport, error) {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	report := BuildStressReport(equity, positions, moves)
+	report.TraderID = at.id
+	return report, nil
+}
+
+// BuildStressReport stress test of exchange positions (GetPositions format) against equity
+func BuildStressReport(equity float64, positions []Position, moves []float64) *StressReport {
+	if len(moves) == 0 {
+		moves = DefaultStressMoves
+	}
+	moves = append([]float64(nil), moves...)
+	sort.Float64s(moves)
+
+	report := &StressReport{GeneratedAt: time.Now(), TotalEquity: equity, Positions: []PositionStress{}}
+	for _, pos := range positions {
+		symbol, side := pos.Symbol, pos.Side
+		markPrice, quantity := pos.MarkPrice, pos.Qty
+		if markPrice <= 0 || quantity <= 0 {
+			continue
+		}
+		leverage := 1
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
+		}
+		ps := PositionStress{
+			Symbol:    symbol,
+			Side:      side,
+			Quantity:  quantity,
+			MarkPrice: markPrice,
+			Notional:  quantity * markPrice,
+			Leverage:  leverage,
+		}
+		if distance, liqPrice, ok := liquidationDistancePct(side, pos); ok {
+			ps.LiquidationPrice = liqPrice
+			ps.LiquidationPct = distance
+		}
+		for _, move := range moves {
+			ps.Scenarios = append(ps.Scenarios, stressScenario(equity, move, []PositionStress{ps}, true))
+		}
+		report.Positions = append(report.Positions, ps)
+
+		report.GrossExposure += ps.Notional
+		if side == "short" {
+			report.NetExposure -= ps.Notional
+		} else {
+			report.NetExposure += ps.Notional
+		}
+	}
+	if equity > 0 {
+		report.EffectiveLeverage = report.GrossExposure / equity
+	}
+
+	for _, move := range moves {
+		report.Combined = append(report.Combined, stressScenario(equity, move, report.Positions, true))
+		report.MarketDown = append(report.MarketDown, stressScenario(equity, -move, report.Positions, false))
+		report.MarketUp = append(report.MarketUp, stressScenario(equity, move, report.Positions, false))
+	}
+	return report
+}
+
+// stressScenario equity impact of a move of movePct percent
+// adverse: every position moves against itself by |movePct| (MovePct is reported as the adverse size);
+// otherwise all prices move by movePct.
+func stressScenario(equity, movePct float64, positions []PositionStress, adverse bool) StressScenario {
+	sc := StressScenario{MovePct: movePct}
+	for _, ps := range positions {
+		priceMove := movePct
+		if adverse {
+			priceMove = -math.Abs(movePct)
+			if ps.Side == "short" {
+				priceMove = math.Abs(movePct)
+			}
+		}
+		pnl := ps.Notional * priceMove / 100
+		if ps.Side == "short" {
+			pnl = -pnl
+		}
+		sc.PnL += pnl
+		// Liquidated if the move against the position reaches its liquidation distance
+		if pnl < 0 && ps.LiquidationPct > 0 && math.Abs(priceMove) >= ps.LiquidationPct {
+			sc.Liquidated = append(sc.Liquidated, ps.Symbol+" "+ps.Side)
+		}
+	}
+	sc.EquityAfter = equity + sc.PnL
+	if equity > 0 {
+		sc.EquityImpactPct = sc.PnL / equity * 100
+	}
+	return sc
+}
@@ -0,0 +1,66 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuildStressReport(t *testing.T) {
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Qty: 0.1, EntryPrice: 60000, MarkPrice: 60000, Leverage: 10, LiqPrice: 54600},
+		{Symbol: "ETHUSDT", Side: "short", Qty: 1, EntryPrice: 3000, MarkPrice: 3000, Leverage: 2, LiqPrice: 4400},
+		{Symbol: "DUST", Side: "long", Qty: 0, MarkPrice: 1}, // Closed, skipped
+	}
+	report := BuildStressReport(10000, positions, []float64{10, 2, 5})
+
+	if len(report.Positions) != 2 {
+		t.Fatalf("expected 2 positions, got %d", len(report.Positions))
+	}
+	if report.GrossExposure != 9000 || report.NetExposure != 3000 || math.Abs(report.EffectiveLeverage-0.9) > 1e-9 {
+		t.Errorf("unexpected exposure: gross %.2f net %.2f leverage %.2f", report.GrossExposure, report.NetExposure, report.EffectiveLeverage)
+	}
+	if len(report.Combined) != 3 || report.Combined[0].MovePct != 2 {
+		t.Fatalf("expected scenarios sorted by move size, got %+v", report.Combined)
+	}
+
+	worst := report.WorstCase()
+	if math.Abs(worst.PnL+900) > 1e-6 || math.Abs(worst.EquityImpactPct+9) > 1e-6 || math.Abs(worst.EquityAfter-9100) > 1e-6 {
+		t.Errorf("unexpected worst case: %+v", worst)
+	}
+	if len(worst.Liquidated) != 1 || worst.Liquidated[0] != "BTCUSDT long" {
+		t.Errorf("expected BTC long liquidated at 10%% (liquidation 9%% away), got %v", worst.Liquidated)
+	}
+	if len(report.Combined[1].Liquidated) != 0 {
+		t.Errorf("expected no liquidation at 5%%, got %v", report.Combined[1].Liquidated)
+	}
+
+	// Market-wide moves: the short offsets part of the long
+	down, up := report.MarketDown[2], report.MarketUp[1]
+	if down.MovePct != -10 || math.Abs(down.PnL+300) > 1e-6 {
+		t.Errorf("expected -300 at -10%%, got %+v", down)
+	}
+	if up.MovePct != 5 || math.Abs(up.PnL-150) > 1e-6 || len(up.Liquidated) != 0 {
+		t.Errorf("expected +150 at +5%%, got %+v", up)
+	}
+
+	eth := report.Positions[1]
+	if eth.Notional != 3000 || math.Abs(eth.Scenarios[0].PnL+60) > 1e-6 {
+		t.Errorf("expected ETH short to lose 60 on a 2%% rise, got %+v", eth)
+	}
+}
+
+func TestParseStressMoves(t *testing.T) {
+	moves, err := ParseStressMoves("")
+	if err != nil || len(moves) != len(DefaultStressMoves) {
+		t.Errorf("expected default moves, got %v, %v", moves, err)
+	}
+	moves, err = ParseStressMoves("1, -3,7.5")
+	if err != nil || len(moves) != 3 || moves[1] != 3 || moves[2] != 7.5 {
+		t.Errorf("unexpected moves %v, %v", moves, err)
+	}
+	for _, bad := range []string{"abc", "0", "150"} {
+		if _, err := ParseStressMoves(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Symbol Allow/Deny Lists
+// ============================================================================
+// Per-trader lists of symbols the trader may (allow) or must never (deny)
+// open positions in, e.g. a curated watchlist or "no meme coins". They are
+// enforced twice: candidates are filtered before the AI sees them, and open
+// decisions are rejected at execution time (the AI may still propose a held
+// or hallucinated symbol, and manual/external decisions skip the candidate
+// step). Closing and moving stops of existing positions is never blocked.
+// The lists are edited at runtime via the API without restarting the trader.
+
+// ErrSymbolBlocked an open decision was rejected by the trader's symbol lists
+var ErrSymbolBlocked = errors.New("blocked by symbol allow/deny list")
+
+// SetSymbolLists replaces the trader's symbol lists (nil = no restriction)
+func (at *AutoTrader) SetSymbolLists(lists *store.SymbolLists) {
+	at.symbolListsMu.Lock()
+	defer at.symbolListsMu.Unlock()
+	at.symbolLists = lists
+	if lists.IsEmpty() {
+		logger.Infof("📃 [%s] Symbol lists cleared", at.name)
+		return
+	}
+	logger.Infof("📃 [%s] Symbol lists updated: allow=%v deny=%v", at.name, lists.Allow, lists.Deny)
+}
+
+// GetSymbolLists returns the trader's current symbol lists (nil = no restriction)
+func (at *AutoTrader) GetSymbolLists() *store.SymbolLists {
+	at.symbolListsMu.RLock()
+	defer at.symbolListsMu.RUnlock()
+	return at.symbolLists
+}
+
+// filterCandidatesBySymbolLists drops candidates the lists do not allow, returning the kept ones and the blocked symbols
+func (at *AutoTrader) filterCandidatesBySymbolLists(candidates []decision.CandidateStock) ([]decision.CandidateStock, []string) {
+	lists := at.GetSymbolLists()
+	if lists.IsEmpty() {
+		return candidates, nil
+	}
+	kept := make([]decision.CandidateStock, 0, len(candidates))
+	var blocked []string
+	for _, c := range candidates {
+		if allowed, _ := lists.Check(c.Symbol); !allowed {
+			blocked = append(blocked, c.Symbol)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(blocked) > 0 {
+		logger.Infof("📃 [%s] Symbol lists removed %d candidates: %s", at.name, len(blocked), strings.Join(blocked, ", "))
+	}
+	return kept, blocked
+}
+
+// checkSymbolLists rejects open decisions for symbols the lists do not allow
+func (at *AutoTrader) checkSymbolLists(d *decision.Decision) error {
+	if d.Action != "open_long" && d.Action != "open_short" {
+		return nil
+	}
+	if allowed, reason := at.GetSymbolLists().Check(d.Symbol); !allowed {
+		return fmt.Errorf("%w: %s", ErrSymbolBlocked, reason)
+	}
+	return nil
+}
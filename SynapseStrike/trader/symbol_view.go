@@ -0,0 +1,112 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"time"
+)
+
+// ============================================================================
+// Symbol View
+// ============================================================================
+// The dashboard shows the same per-symbol data the AI saw instead of
+// re-fetching it from external APIs. Right before the AI call each cycle
+// snapshots every symbol it considered: candidates in batching order, held
+// symbols that are not candidates, then candidates removed before the
+// decision with the filter reason (see filteredCandidates). The last snapshot
+// is served by GET /api/traders/:id/symbols.
+
+// Symbol statuses of SymbolView.Status
+const (
+	SymbolStatusCandidate = "candidate" // Sent to the AI as a candidate
+	SymbolStatusPosition  = "position"  // Held but not a candidate, sent for position management
+	SymbolStatusFiltered  = "filtered"  // Removed before the AI decision (FilterReason)
+)
+
+// SymbolView engine's view of one symbol in a cycle
+type SymbolView struct {
+	Symbol           string                 `json:"symbol"`
+	Status           string                 `json:"status"`                  // SymbolStatus* constant
+	FilterReason     string                 `json:"filter_reason,omitempty"` // store.FilterReason* constant (filtered only)
+	FilterDetail     string                 `json:"filter_detail,omitempty"`
+	Sources          []string               `json:"sources,omitempty"` // Candidate sources, e.g. "ai500", "oi_top"
+	Held             bool                   `json:"held"`
+	Indicators       *store.EntryIndicators `json:"indicators,omitempty"` // nil without market data
+	PriceChange1h    float64                `json:"price_change_1h"`
+	PriceChange4h    float64                `json:"price_change_4h"`
+	NewsCount        int                    `json:"news_count"`
+	OpportunityScore *float64               `json:"opportunity_score,omitempty"` // CandidateRanking.Enabled only
+	DataGaps         []store.DataGap        `json:"data_gaps,omitempty"`
+}
+
+// SymbolSnapshot symbols of the last cycle as presented to the AI
+type SymbolSnapshot struct {
+	Cycle   int          `json:"cycle"`
+	Time    time.Time    `json:"time"`
+	Symbols []SymbolView `json:"symbols"`
+}
+
+// snapshotSymbols keeps the symbol view of ctx for GetSymbolSnapshot
+func (at *AutoTrader) snapshotSymbols(ctx *decision.Context) {
+	snapshot := buildSymbolSnapshot(at.engine(), ctx)
+	snapshot.Cycle = at.cycleNumber
+	at.symbolViewMu.Lock()
+	at.symbolView = snapshot
+	at.symbolViewMu.Unlock()
+}
+
+// GetSymbolSnapshot symbol view of the last cycle (nil before the first cycle built its context)
+func (at *AutoTrader) GetSymbolSnapshot() *SymbolSnapshot {
+	at.symbolViewMu.RLock()
+	defer at.symbolViewMu.RUnlock()
+	return at.symbolView
+}
+
+func buildSymbolSnapshot(engine *decision.StrategyEngine, ctx *decision.Context) *SymbolSnapshot {
+	held := make(map[string]bool, len(ctx.Positions))
+	for _, pos := range ctx.Positions {
+		held[pos.Symbol] = true
+	}
+	gaps := make(map[string][]store.DataGap)
+	for _, gap := range ctx.DataGaps {
+		gaps[gap.Symbol] = append(gaps[gap.Symbol], gap)
+	}
+
+	snapshot := &SymbolSnapshot{Time: time.Now().UTC(), Symbols: []SymbolView{}}
+	seen := make(map[string]bool)
+	add := func(view SymbolView) {
+		if seen[view.Symbol] {
+			return
+		}
+		seen[view.Symbol] = true
+		view.Held = held[view.Symbol]
+		view.DataGaps = gaps[view.Symbol]
+		if engine != nil {
+			view.Indicators = engine.SnapshotIndicators(ctx, view.Symbol)
+		}
+		if data := ctx.MarketDataMap[view.Symbol]; data != nil {
+			view.PriceChange1h = data.PriceChange1h
+			view.PriceChange4h = data.PriceChange4h
+			if data.StockExtraData != nil {
+				view.NewsCount = len(data.StockExtraData.RecentNews)
+			}
+		}
+		if ctx.CandidateRanking != nil {
+			if score := ctx.CandidateRanking.Scores[view.Symbol]; score != nil {
+				view.OpportunityScore = &score.Score
+			}
+		}
+		snapshot.Symbols = append(snapshot.Symbols, view)
+	}
+
+	for _, stock := range ctx.CandidateStocks {
+		add(SymbolView{Symbol: stock.Symbol, Status: SymbolStatusCandidate, Sources: stock.Sources})
+	}
+	for _, pos := range ctx.Positions {
+		add(SymbolView{Symbol: pos.Symbol, Status: SymbolStatusPosition})
+	}
+	for _, f := range filteredCandidates(ctx) {
+		add(SymbolView{Symbol: f.Symbol, Status: SymbolStatusFiltered, FilterReason: f.Reason, FilterDetail: f.Detail})
+	}
+	return snapshot
+}
@@ -0,0 +1,75 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"testing"
+)
+
+// TestSymbolSnapshot tests that candidates, held symbols and filtered candidates are snapshotted with their data
+func TestSymbolSnapshot(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	at := &AutoTrader{id: "t1", name: "test", strategyEngine: decision.NewStrategyEngine(&cfg), cycleNumber: 7}
+	if at.GetSymbolSnapshot() != nil {
+		t.Fatal("snapshot before the first cycle should be nil")
+	}
+
+	ctx := &decision.Context{
+		CandidateStocks: []decision.CandidateStock{{Symbol: "NVDA", Sources: []string{"ai500"}}, {Symbol: "AAPL"}},
+		Positions:       []decision.PositionInfo{{Symbol: "AAPL"}, {Symbol: "TSLA"}},
+		MarketDataMap: map[string]*market.Data{
+			"NVDA": {CurrentPrice: 120, PriceChange1h: 1.5, StockExtraData: &market.StockExtraData{RecentNews: make([]market.NewsItem, 3)}},
+			"TSLA": {CurrentPrice: 250},
+		},
+		ConfluenceMap:     map[string]*decision.ConfluenceScore{"NVDA": {Direction: "bullish", Score: 0.75}},
+		SymbolListBlocked: []string{"GME"},
+		FilteredOut:       []store.FilteredSymbol{{Symbol: "AMC", Reason: store.FilterReasonLowOI, Detail: "OI too low"}},
+		CandidateRanking: &decision.CandidateRanking{
+			Scores: map[string]*decision.CandidateScore{"NVDA": {Score: 0.8}, "MSFT": {Score: 0.1}},
+			Cut:    []string{"MSFT"},
+		},
+		DataGaps: []store.DataGap{{Symbol: "AAPL", Feed: store.DataFeedNews, Reason: "timeout"}},
+	}
+	at.snapshotSymbols(ctx)
+
+	snapshot := at.GetSymbolSnapshot()
+	if snapshot == nil || snapshot.Cycle != 7 {
+		t.Fatalf("snapshot = %+v, want cycle 7", snapshot)
+	}
+	want := []struct{ symbol, status, reason string }{
+		{"NVDA", SymbolStatusCandidate, ""},
+		{"AAPL", SymbolStatusCandidate, ""},
+		{"TSLA", SymbolStatusPosition, ""},
+		{"GME", SymbolStatusFiltered, store.FilterReasonSymbolList},
+		{"AMC", SymbolStatusFiltered, store.FilterReasonLowOI},
+		{"MSFT", SymbolStatusFiltered, store.FilterReasonRankingCut},
+	}
+	if len(snapshot.Symbols) != len(want) {
+		t.Fatalf("got %d symbols, want %d: %+v", len(snapshot.Symbols), len(want), snapshot.Symbols)
+	}
+	for i, w := range want {
+		got := snapshot.Symbols[i]
+		if got.Symbol != w.symbol || got.Status != w.status || got.FilterReason != w.reason {
+			t.Errorf("symbol #%d = %s/%s/%s, want %s/%s/%s", i, got.Symbol, got.Status, got.FilterReason, w.symbol, w.status, w.reason)
+		}
+	}
+
+	nvda := snapshot.Symbols[0]
+	if nvda.Indicators == nil || nvda.Indicators.Price != 120 || nvda.Indicators.ConfluenceScore != 0.75 {
+		t.Errorf("NVDA indicators = %+v", nvda.Indicators)
+	}
+	if nvda.NewsCount != 3 || nvda.PriceChange1h != 1.5 || nvda.Held {
+		t.Errorf("NVDA view = %+v", nvda)
+	}
+	if nvda.OpportunityScore == nil || *nvda.OpportunityScore != 0.8 {
+		t.Errorf("NVDA opportunity score = %v, want 0.8", nvda.OpportunityScore)
+	}
+	aapl := snapshot.Symbols[1]
+	if !aapl.Held || aapl.Indicators != nil || len(aapl.DataGaps) != 1 {
+		t.Errorf("AAPL view = %+v, want held without market data and one data gap", aapl)
+	}
+	if msft := snapshot.Symbols[5]; msft.FilterDetail != "opportunity score 0.10" {
+		t.Errorf("MSFT filter detail = %q", msft.FilterDetail)
+	}
+}
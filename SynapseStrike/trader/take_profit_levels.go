@@ -0,0 +1,118 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+	"strings"
+)
+
+// closeReasonTakeProfitLevel close reason of a partial close by scaled take profit level n (1-based)
+const closeReasonTakeProfitLevel = "take_profit_%d"
+
+// placeTakeProfitLevels places reduce-only orders for the levels before the final one of a scaled take profit
+// The final level is the regular take profit, placed by the caller for the whole quantity. Levels need an
+// exchange supporting partial take profits and a position record to track the orders on; otherwise only the
+// final take profit protects the position. A level below the minimum order quantity rides to the next level.
+func (at *AutoTrader) placeTakeProfitLevels(symbol, side string, quantity float64, levels []decision.TakeProfitLevel) {
+	if len(levels) < 2 {
+		return
+	}
+	stp, ok := at.trader.(ScaledTakeProfitTrader)
+	if !ok {
+		logger.Infof("  ⚠ %s does not support partial take profits, only the final level %.4f is placed", at.exchange, levels[len(levels)-1].Price)
+		return
+	}
+	tracked := at.protectiveOrders(symbol, side)
+	if tracked == nil {
+		logger.Infof("  ⚠ No position record for %s %s, scaled take profit levels not placed", symbol, side)
+		return
+	}
+
+	positionSide := strings.ToUpper(side)
+	carry := 0.0 // Percent of levels too small to place, added to the next level
+	for _, level := range levels[:len(levels)-1] {
+		pct := level.PctOfPosition + carry
+		levelQty, err := symbolQuantity(symbol, quantity*pct/100)
+		if err != nil || levelQty <= 0 {
+			carry = pct
+			continue
+		}
+		carry = 0
+		orderID, err := stp.PlacePartialTakeProfit(symbol, positionSide, levelQty, level.Price)
+		if err != nil {
+			logger.Infof("  ⚠ Failed to set take profit level %.4f (%.0f%%): %v", level.Price, pct, err)
+			continue
+		}
+		tracked.TakeProfitLevels = append(tracked.TakeProfitLevels, store.TakeProfitLevelOrder{
+			Price:         level.Price,
+			PctOfPosition: pct,
+			Quantity:      levelQty,
+			OrderID:       orderID,
+		})
+		logger.Infof("  🎯 Take profit level %d: %.4f of %s @ %.4f (%.0f%%)", len(tracked.TakeProfitLevels), levelQty, symbol, level.Price, pct)
+	}
+	if len(tracked.TakeProfitLevels) == 0 {
+		return
+	}
+	if err := at.store.Position().SetProtectiveOrders(*tracked); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to track take profit levels of %s %s: %v", at.name, symbol, side, err)
+	}
+}
+
+// cancelTakeProfitLevels cancels the unfilled level orders of a scaled take profit and forgets them
+func cancelTakeProfitLevels(pot ProtectiveOrderTrader, symbol string, orders *store.ProtectiveOrders) {
+	for _, level := range orders.TakeProfitLevels {
+		if level.Filled || level.OrderID == "" {
+			continue
+		}
+		if err := pot.CancelProtectiveOrder(symbol, level.OrderID); err != nil {
+			logger.Warnf("⚠️ Failed to cancel take profit level order %s of %s (may be filled already): %v", level.OrderID, symbol, err)
+		}
+	}
+	orders.TakeProfitLevels = nil
+}
+
+// recordTakeProfitLevelFills splits filled take profit levels off a position the exchange reports reduced
+// Levels fill nearest first, so the unfilled levels whose quantities fit into the reduction are the filled ones.
+// Returns true if fills were recorded.
+func (m *PositionSyncManager) recordTakeProfitLevelFills(pos *store.TraderPosition, exchangeQty float64, exchangeType string) bool {
+	orders, err := m.store.Position().GetProtectiveOrders(pos.ID)
+	if err != nil || len(orders.TakeProfitLevels) == 0 {
+		return false
+	}
+	reduced := pos.Quantity - exchangeQty
+	recorded := false
+	for i := range orders.TakeProfitLevels {
+		level := &orders.TakeProfitLevels[i]
+		if level.Filled {
+			continue
+		}
+		if level.Quantity > reduced*1.001 {
+			break
+		}
+		realizedPnL := (level.Price - pos.EntryPrice) * level.Quantity
+		if pos.Side == "SHORT" {
+			realizedPnL = -realizedPnL
+		}
+		fee := EstimateFee(exchangeType, level.Quantity*level.Price, false)
+		reason := fmt.Sprintf(closeReasonTakeProfitLevel, i+1)
+		if err := m.store.Position().PartialClose(pos.ID, level.Quantity, level.Price, level.OrderID, realizedPnL, fee, reason); err != nil {
+			logger.Infof("⚠️  Failed to record take profit level %d of %s %s: %v", i+1, pos.Symbol, pos.Side, err)
+			break
+		}
+		level.Filled = true
+		reduced -= level.Quantity
+		pos.Quantity -= level.Quantity
+		recorded = true
+		logger.Infof("🎯 Take profit level %d filled [%s] %s %s: %.4f @ %.4f, PnL: %.2f",
+			i+1, pos.TraderID[:8], pos.Symbol, pos.Side, level.Quantity, level.Price, realizedPnL)
+	}
+	if recorded {
+		if err := m.store.Position().SetProtectiveOrders(*orders); err != nil {
+			logger.Infof("⚠️  Failed to update take profit levels of %s %s: %v", pos.Symbol, pos.Side, err)
+		}
+	}
+	return recorded
+}
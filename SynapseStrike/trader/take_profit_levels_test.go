@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/store"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scaledTakeProfitMock exchange placing partial take profits
+type scaledTakeProfitMock struct {
+	protectiveOrderMock
+}
+
+func (m *scaledTakeProfitMock) PlacePartialTakeProfit(symbol, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	m.placed = append(m.placed, fmt.Sprintf("tpl-%g@%g", quantity, takeProfitPrice))
+	return m.placed[len(m.placed)-1], nil
+}
+
+// TestTakeProfitLevels tests placing, fill recording and cancellation of scaled take profit levels
+func TestTakeProfitLevels(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	pos := &store.TraderPosition{TraderID: "trader-1", Symbol: "AAPL", Side: "LONG", Quantity: 10, EntryPrice: 100, EntryTime: time.Now()}
+	if err := st.Position().Create(pos); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mock := &scaledTakeProfitMock{}
+	at := &AutoTrader{id: "trader-1", name: "test", trader: mock, store: st, positionTPSL: make(map[string][2]float64)}
+	levels := []decision.TakeProfitLevel{{Price: 105, PctOfPosition: 50}, {Price: 110, PctOfPosition: 30}, {Price: 115, PctOfPosition: 20}}
+	at.setTakeProfit("AAPL", "long", 10, 115, false)
+	at.placeTakeProfitLevels("AAPL", "long", 10, levels)
+
+	orders, _ := st.Position().GetProtectiveOrders(pos.ID)
+	if orders.TakeProfitOrderID != "tp-1" || len(orders.TakeProfitLevels) != 2 || orders.TakeProfitLevels[0].OrderID != "tpl-5@105" || orders.TakeProfitLevels[1].Quantity != 3 {
+		t.Fatalf("tracked orders = %+v", orders)
+	}
+
+	// Exchange reports 5 left: the first level filled and is split off the position record
+	m := NewPositionSyncManager(st, 0)
+	if !m.recordTakeProfitLevelFills(pos, 5, "alpaca") {
+		t.Fatal("expected the first level fill to be recorded")
+	}
+	open, _ := st.Position().GetOpenPositionBySymbol("trader-1", "AAPL", "LONG")
+	closed, _ := st.Position().GetClosedPositions("trader-1", 10)
+	if open == nil || open.Quantity != 5 || len(closed) != 1 || closed[0].CloseReason != "take_profit_1" || closed[0].RealizedPnL != 25 {
+		t.Fatalf("after level fill: open %+v closed %+v", open, closed)
+	}
+
+	// Closing cancels the final take profit and the unfilled level only
+	at.cancelProtectiveOrders("AAPL", "long")
+	if got := strings.Join(mock.cancelled, ","); got != "tp-1,tpl-3@110" {
+		t.Errorf("cancelled = %s", got)
+	}
+	orders, _ = st.Position().GetProtectiveOrders(pos.ID)
+	if len(orders.TakeProfitLevels) != 0 {
+		t.Errorf("levels still tracked after close: %+v", orders.TakeProfitLevels)
+	}
+}
@@ -0,0 +1,25 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"time"
+)
+
+// maxDigestAnnotations max annotations counted in the trade journal digest of one cycle
+const maxDigestAnnotations = 100
+
+// loadAnnotations loads recent trade journal annotations into ctx.Annotations (Annotations.Enabled only)
+func (at *AutoTrader) loadAnnotations(ctx *decision.Context) {
+	cfg := at.engine().GetConfig().Annotations
+	if !cfg.Enabled || at.store == nil {
+		return
+	}
+	since := time.Now().UTC().AddDate(0, 0, -decision.AnnotationLookbackDays(cfg))
+	annotations, err := at.store.Annotation().List(at.id, since, maxDigestAnnotations)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load trade annotations: %v", at.name, err)
+		return
+	}
+	ctx.Annotations = annotations
+}
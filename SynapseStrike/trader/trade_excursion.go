@@ -0,0 +1,93 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"math"
+	"time"
+)
+
+// excursionLookupRetry interval between position record lookups of a position without one (e.g. opened manually)
+const excursionLookupRetry = time.Minute
+
+// positionExcursion running MFE/MAE of an open position, in % of entry price
+type positionExcursion struct {
+	positionID int64 // 0 = no open position record (yet)
+	entryPrice float64
+	mfePct     float64
+	maePct     float64
+	lookedUpAt time.Time
+}
+
+// trackExcursion updates the MFE/MAE of an open position from a mark price of the position monitor
+// New extremes are written to the position record (see store/trade_excursion.go), so closed trades keep them.
+func (at *AutoTrader) trackExcursion(symbol, side string, entryPrice, markPrice float64) {
+	if at.store == nil || entryPrice <= 0 || markPrice <= 0 {
+		return
+	}
+	move := (markPrice - entryPrice) / entryPrice * 100
+	if side == "short" {
+		move = -move
+	}
+
+	at.excursionMu.Lock()
+	defer at.excursionMu.Unlock()
+	key := symbol + "_" + side
+	exc := at.excursions[key]
+	if exc == nil || exc.entryPrice != entryPrice || (exc.positionID == 0 && time.Since(exc.lookedUpAt) >= excursionLookupRetry) {
+		// New position, or the entry moved (added to): pick up the record and what it tracked so far
+		exc = at.loadExcursion(symbol, side, entryPrice, exc)
+		if at.excursions == nil {
+			at.excursions = make(map[string]*positionExcursion)
+		}
+		at.excursions[key] = exc
+	}
+	if exc.positionID == 0 {
+		return
+	}
+
+	if move <= exc.mfePct && -move <= exc.maePct {
+		return
+	}
+	exc.mfePct = math.Max(exc.mfePct, move)
+	exc.maePct = math.Max(exc.maePct, -move)
+	open, err := at.store.Position().UpdateExcursion(exc.positionID, exc.mfePct, exc.maePct)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to record excursion of %s %s: %v", at.name, symbol, side, err)
+		return
+	}
+	if !open {
+		// Record closed (e.g. reopened since the last update): look it up again next time
+		delete(at.excursions, key)
+	}
+}
+
+// loadExcursion excursion state of the open position record of symbol/side (positionID 0 = none)
+// prev keeps the extremes when the record is unchanged. Caller must hold excursionMu.
+func (at *AutoTrader) loadExcursion(symbol, side string, entryPrice float64, prev *positionExcursion) *positionExcursion {
+	exc := &positionExcursion{entryPrice: entryPrice, lookedUpAt: time.Now()}
+	pos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+	if err != nil || pos == nil {
+		return exc
+	}
+	exc.positionID = pos.ID
+	exc.mfePct, exc.maePct, err = at.store.Position().GetExcursion(pos.ID)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load excursion of %s %s: %v", at.name, symbol, side, err)
+	}
+	if prev != nil && prev.positionID == pos.ID {
+		exc.mfePct = math.Max(exc.mfePct, prev.mfePct)
+		exc.maePct = math.Max(exc.maePct, prev.maePct)
+	}
+	return exc
+}
+
+// pruneExcursions forgets positions the interval poll no longer reports
+func (at *AutoTrader) pruneExcursions(openKeys map[string]bool) {
+	at.excursionMu.Lock()
+	defer at.excursionMu.Unlock()
+	for key := range at.excursions {
+		if !openKeys[key] {
+			delete(at.excursions, key)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTradeBudgetExceeded an open decision was rejected by the trade frequency governor
+var ErrTradeBudgetExceeded = errors.New("trade budget exceeded")
+
+// checkTradeGovernor rejects open decisions beyond the trade budget (TradeGovernor.Enabled only)
+// The rejection is remembered and reported in the next cycle's prompt.
+func (at *AutoTrader) checkTradeGovernor(d *decision.Decision) error {
+	if d.Action != "open_long" && d.Action != "open_short" {
+		return nil
+	}
+	cfg := at.engine().GetConfig().TradeGovernor
+	at.governorMu.Lock()
+	defer at.governorMu.Unlock()
+	if !cfg.Enabled {
+		// Re-seed from the store when the governor is enabled again
+		at.governorSeeded = false
+		return nil
+	}
+	at.seedTradeOpensLocked()
+
+	ok, reason := decision.CheckTradeBudget(cfg, at.governorOpens, d.Symbol, time.Now())
+	if ok {
+		return nil
+	}
+	at.governorRejected = append(at.governorRejected, fmt.Sprintf("%s %s: %s", d.Action, d.Symbol, reason))
+	logger.Warnf("🧮 [%s] Trade governor rejected %s %s: %s", at.name, d.Action, d.Symbol, reason)
+	return fmt.Errorf("%w: %s", ErrTradeBudgetExceeded, reason)
+}
+
+// countTradeOpen records a successful open against the trade budget and passes err through
+func (at *AutoTrader) countTradeOpen(d *decision.Decision, err error) error {
+	if err != nil || !at.engine().GetConfig().TradeGovernor.Enabled {
+		return err
+	}
+	at.governorMu.Lock()
+	defer at.governorMu.Unlock()
+	now := time.Now()
+	at.governorOpens = append(decision.PruneTradeOpens(at.governorOpens, now), decision.TradeOpen{Symbol: d.Symbol, At: now})
+	return nil
+}
+
+// tradeBudgetContext fills ctx.TradeBudget and hands over the opens rejected since the last cycle
+func (at *AutoTrader) tradeBudgetContext(ctx *decision.Context) {
+	cfg := at.engine().GetConfig().TradeGovernor
+	if !cfg.Enabled {
+		return
+	}
+	at.governorMu.Lock()
+	defer at.governorMu.Unlock()
+	at.seedTradeOpensLocked()
+	now := time.Now()
+	at.governorOpens = decision.PruneTradeOpens(at.governorOpens, now)
+	ctx.TradeBudget = decision.BuildTradeBudget(cfg, at.governorOpens, now)
+	ctx.TradeBudget.Rejected = at.governorRejected
+	at.governorRejected = nil
+}
+
+// seedTradeOpensLocked loads the last 24h of opens from the position store once, so restarts keep the budget
+// Caller must hold governorMu.
+func (at *AutoTrader) seedTradeOpensLocked() {
+	if at.governorSeeded {
+		return
+	}
+	at.governorSeeded = true
+	at.governorOpens = nil
+	if at.store == nil {
+		return
+	}
+	since := time.Now().Add(-24 * time.Hour)
+	positions, err := at.store.Position().GetOpenPositions(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Trade governor: failed to load open positions: %v", at.name, err)
+	}
+	closed, err := at.store.Position().GetClosedPositionsSince(at.id, since)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Trade governor: failed to load closed positions: %v", at.name, err)
+	}
+	for _, pos := range append(positions, closed...) {
+		if pos.EntryTime.After(since) {
+			at.governorOpens = append(at.governorOpens, decision.TradeOpen{Symbol: pos.Symbol, At: pos.EntryTime})
+		}
+	}
+	if len(at.governorOpens) > 0 {
+		logger.Infof("🧮 [%s] Trade governor: %d opens in the last 24h loaded", at.name, len(at.governorOpens))
+	}
+}
@@ -0,0 +1,301 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// TWAP / Iceberg Execution (Phase 2: Smart Order Execution)
+// ============================================================================
+// Large orders are split into child orders spread evenly over a time window.
+// Only one child order is working at a time (iceberg), each priced at
+// VWAP ± ATR when the exchange supports limit orders, falling back to a market
+// order for the slice when the limit does not fill before the next slice.
+// Fills are aggregated into a single volume-weighted average entry price.
+
+const (
+	defaultTWAPSliceCount      = 6
+	defaultTWAPDurationSeconds = 60
+)
+
+// limitOrderTrader traders supporting limit orders with fill polling (currently Alpaca)
+type limitOrderTrader interface {
+	PlaceLimitOrder(symbol, side string, quantity float64, limitPrice float64) (map[string]interface{}, error)
+	WaitForFill(orderID string, timeoutSeconds int) (bool, error)
+	CancelOrder(orderID string) error
+}
+
+// twapFill aggregate fill of TWAP child orders
+type twapFill struct {
+	quantity float64 // Total filled quantity
+	notional float64 // Sum of fill price × quantity
+	fee      float64
+	children int
+	lastID   string
+}
+
+func (f *twapFill) add(orderID string, quantity, price, fee float64) {
+	f.quantity += quantity
+	f.notional += quantity * price
+	f.fee += fee
+	f.children++
+	f.lastID = orderID
+}
+
+// avgPrice volume-weighted average fill price
+func (f *twapFill) avgPrice() float64 {
+	if f.quantity <= 0 {
+		return 0
+	}
+	return f.notional / f.quantity
+}
+
+// shouldUseTWAP checks whether order notional reaches TWAP threshold
+func (at *AutoTrader) shouldUseTWAP(execConfig store.ExecutionConfig, symbol string, quantity float64) bool {
+	if !execConfig.EnableTWAP {
+		return false
+	}
+	price, err := at.trader.GetMarketPrice(symbol)
+	if err != nil || price <= 0 {
+		logger.Infof("  ⚠️ Failed to get price for TWAP check, executing as single order: %v", err)
+		return false
+	}
+	return quantity*price >= execConfig.TWAPMinSize
+}
+
+// splitTWAPQuantity splits quantity into equal child slices respecting lot size
+// Whole-unit orders without a fractional lot step (e.g. shares) stay whole; remainder goes to the last slice.
+func splitTWAPQuantity(symbol string, quantity float64, slices int) []float64 {
+	if slices < 1 {
+		slices = 1
+	}
+	lot := market.Symbols.MinQuantity(symbol)
+	wholeUnits := quantity == math.Floor(quantity) && (lot <= 0 || lot >= 1)
+
+	for ; slices > 1; slices-- {
+		share := decimal.NewFromFloat(quantity).Div(decimal.NewFromInt(int64(slices)))
+		slice := market.Symbols.RoundQuantity(symbol, share.InexactFloat64())
+		if wholeUnits {
+			slice = math.Floor(slice)
+		}
+		if slice > 0 && slice >= market.Symbols.MinQuantity(symbol) {
+			result := make([]float64, slices)
+			for i := 0; i < slices-1; i++ {
+				result[i] = slice
+			}
+			filled := decimal.NewFromFloat(slice).Mul(decimal.NewFromInt(int64(slices - 1)))
+			result[slices-1] = lotRemainder(symbol, quantity, filled.InexactFloat64())
+			return result
+		}
+	}
+	return []float64{quantity}
+}
+
+// lotRemainder quantity left after subtracting filled lots, computed in decimal and rounded to the symbol's lot step
+// Float subtraction would leave noise (1 - 0.7 = 0.29999999999999993) that rounding down turns into a lost lot.
+func lotRemainder(symbol string, quantity, filled float64) float64 {
+	remainder := decimal.NewFromFloat(quantity).Sub(decimal.NewFromFloat(filled))
+	return market.Symbols.RoundQuantity(symbol, remainder.InexactFloat64())
+}
+
+// executeTWAP executes order as time-sliced child orders and returns aggregate fill
+// Returned map carries orderId/avgPrice/executedQty/commission so the position is recorded at the average fill.
+func (at *AutoTrader) executeTWAP(symbol, side string, quantity float64, leverage int, execConfig store.ExecutionConfig) (map[string]interface{}, error) {
+	sliceCount := execConfig.TWAPSliceCount
+	if sliceCount <= 0 {
+		sliceCount = defaultTWAPSliceCount
+	}
+	duration := execConfig.TWAPDurationSeconds
+	if duration <= 0 {
+		duration = defaultTWAPDurationSeconds
+	}
+
+	slices := splitTWAPQuantity(symbol, quantity, sliceCount)
+	interval := time.Duration(duration) * time.Second / time.Duration(len(slices))
+	logger.Infof("  🧊 TWAP %s %s: %.4f split into %d slices over %ds (every %s)",
+		side, symbol, quantity, len(slices), duration, interval)
+
+	limitTrader, supportsLimit := at.trader.(limitOrderTrader)
+	if execConfig.EnableLimitOrders && !supportsLimit {
+		logger.Infof("  ⚠️ Limit orders not supported on %s, TWAP slices use market orders", at.exchange)
+	}
+
+	fill := &twapFill{}
+	var lastErr error
+	for i, sliceQty := range slices {
+		sliceStart := time.Now()
+
+		var err error
+		if execConfig.EnableLimitOrders && supportsLimit {
+			err = at.executeTWAPLimitSlice(limitTrader, fill, symbol, side, sliceQty, leverage, execConfig, interval)
+		} else {
+			err = at.executeTWAPMarketSlice(fill, symbol, side, sliceQty, leverage)
+		}
+		if err != nil {
+			// Stop slicing: keep what has been filled, never re-enter remaining size blindly
+			logger.Infof("  ❌ TWAP slice %d/%d failed: %v", i+1, len(slices), err)
+			lastErr = err
+			break
+		}
+		logger.Infof("  ✓ TWAP slice %d/%d: filled %.4f, running avg $%.4f", i+1, len(slices), fill.quantity, fill.avgPrice())
+
+		if i < len(slices)-1 {
+			if wait := interval - time.Since(sliceStart); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+	}
+
+	if fill.children == 0 {
+		return nil, fmt.Errorf("TWAP execution failed: %w", lastErr)
+	}
+	if lastErr != nil {
+		logger.Warnf("⚠️  TWAP %s %s partially filled: %.4f/%.4f", side, symbol, fill.quantity, quantity)
+	}
+	logger.Infof("  ✅ TWAP complete: %d child orders, %.4f filled at avg $%.4f", fill.children, fill.quantity, fill.avgPrice())
+
+	return map[string]interface{}{
+		"orderId":     fill.lastID,
+		"status":      "FILLED",
+		"avgPrice":    fill.avgPrice(),
+		"executedQty": fill.quantity,
+		"commission":  fill.fee,
+		"twap":        true,
+		"childOrders": fill.children,
+	}, nil
+}
+
+// executeTWAPMarketSlice places one market child order and records its fill
+func (at *AutoTrader) executeTWAPMarketSlice(fill *twapFill, symbol, side string, quantity float64, leverage int) error {
+	var order map[string]interface{}
+	var err error
+	if side == "buy" {
+		order, err = at.trader.OpenLong(symbol, quantity, leverage)
+	} else {
+		order, err = at.trader.OpenShort(symbol, quantity, leverage)
+	}
+	if err != nil {
+		return err
+	}
+	at.recordTWAPChildFill(fill, symbol, childOrderID(order), quantity)
+	return nil
+}
+
+// executeTWAPLimitSlice places one VWAP ± ATR limit child order, falling back to market if unfilled within interval
+func (at *AutoTrader) executeTWAPLimitSlice(lt limitOrderTrader, fill *twapFill, symbol, side string, quantity float64, leverage int, execConfig store.ExecutionConfig, interval time.Duration) error {
+	limitPrice, err := at.calculateSmartLimitPrice(symbol, side, at.limitOffsetATRMult(symbol, execConfig))
+	if err != nil {
+		logger.Infof("  ⚠️ Failed to calculate slice limit price, using market: %v", err)
+		return at.executeTWAPMarketSlice(fill, symbol, side, quantity, leverage)
+	}
+
+	order, err := lt.PlaceLimitOrder(symbol, side, quantity, limitPrice)
+	if err != nil {
+		logger.Infof("  ⚠️ Failed to place slice limit order, using market: %v", err)
+		return at.executeTWAPMarketSlice(fill, symbol, side, quantity, leverage)
+	}
+	orderID := childOrderID(order)
+	if orderID == "" {
+		return at.executeTWAPMarketSlice(fill, symbol, side, quantity, leverage)
+	}
+
+	// Give the limit order most of the slice interval to fill
+	timeout := int(interval.Seconds() * 0.8)
+	if timeout < 1 {
+		timeout = 1
+	}
+	filled, err := lt.WaitForFill(orderID, timeout)
+	if err != nil {
+		logger.Infof("  ⚠️ Error waiting for slice fill: %v", err)
+	}
+	if filled {
+		at.recordTWAPChildFill(fill, symbol, orderID, quantity)
+		return nil
+	}
+
+	// Unfilled: cancel, keep any partial fill, market the remainder
+	lt.CancelOrder(orderID)
+	partial := at.recordTWAPChildPartial(fill, symbol, orderID)
+	remaining := lotRemainder(symbol, quantity, partial)
+	if remaining <= 0 {
+		return nil
+	}
+	logger.Infof("  ⏱️ Slice limit not filled within %ds, market order for remaining %.4f", timeout, remaining)
+	return at.executeTWAPMarketSlice(fill, symbol, side, remaining, leverage)
+}
+
+// recordTWAPChildFill adds child order fill, using exchange fill data when available
+func (at *AutoTrader) recordTWAPChildFill(fill *twapFill, symbol, orderID string, requestedQty float64) {
+	price, qty, fee := at.childFillData(symbol, orderID)
+	if qty <= 0 {
+		qty = requestedQty
+	}
+	if price <= 0 {
+		if p, err := at.trader.GetMarketPrice(symbol); err == nil {
+			price = p
+		}
+	}
+	fill.add(orderID, qty, price, fee)
+}
+
+// recordTWAPChildPartial adds partially filled quantity of canceled child order, returns filled quantity
+func (at *AutoTrader) recordTWAPChildPartial(fill *twapFill, symbol, orderID string) float64 {
+	price, qty, fee := at.childFillData(symbol, orderID)
+	if qty <= 0 || price <= 0 {
+		return 0
+	}
+	fill.add(orderID, qty, price, fee)
+	return qty
+}
+
+// childFillData gets actual fill price, quantity and fee of child order (zeros if unavailable)
+func (at *AutoTrader) childFillData(symbol, orderID string) (price, quantity, fee float64) {
+	if orderID == "" {
+		return 0, 0, 0
+	}
+	status, err := at.trader.GetOrderStatus(symbol, orderID)
+	if err != nil {
+		return 0, 0, 0
+	}
+	price, _ = status["avgPrice"].(float64)
+	quantity, _ = status["executedQty"].(float64)
+	fee, _ = status["commission"].(float64)
+	return price, quantity, fee
+}
+
+// setActionOrderID records order ID of exchange order response on the decision action
+// Numeric IDs (including numeric strings, e.g. TWAP child orders) go to OrderID, others to ExchangeOrderID.
+func setActionOrderID(action *store.DecisionAction, order map[string]interface{}) {
+	id := childOrderID(order)
+	if id == "" {
+		return
+	}
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+		action.OrderID = n
+		return
+	}
+	action.ExchangeOrderID = id
+}
+
+// childOrderID extracts order ID from exchange order response ("orderId" or Alpaca "id")
+func childOrderID(order map[string]interface{}) string {
+	for _, key := range []string{"orderId", "id"} {
+		switch v := order[key].(type) {
+		case int64:
+			return fmt.Sprintf("%d", v)
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		case string:
+			return v
+		}
+	}
+	return ""
+}
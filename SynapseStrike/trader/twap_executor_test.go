@@ -0,0 +1,112 @@
+package trader
+
+import (
+	"SynapseStrike/store"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSplitTWAPQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbol   string
+		quantity float64
+		slices   int
+		want     []float64
+	}{
+		{"shares split evenly", "AAPL", 600, 6, []float64{100, 100, 100, 100, 100, 100}},
+		{"shares remainder to last slice", "AAPL", 100, 3, []float64{33, 33, 34}},
+		{"shares too few to split", "AAPL", 2, 6, []float64{1, 1}},
+		{"single share", "AAPL", 1, 6, []float64{1}},
+		{"fractional lot step", "BTCUSDT", 1, 3, []float64{0.333, 0.333, 0.334}},
+		{"float noise in remainder", "BTCUSDT", 0.3, 3, []float64{0.1, 0.1, 0.1}},
+		{"below min lot", "BTCUSDT", 0.002, 6, []float64{0.001, 0.001}},
+		{"whole lot step", "SOLUSDT", 10, 4, []float64{2, 2, 2, 4}},
+		{"no slicing", "AAPL", 50, 0, []float64{50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitTWAPQuantity(tt.symbol, tt.quantity, tt.slices)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitTWAPQuantity(%s, %v, %d) = %v, want %v", tt.symbol, tt.quantity, tt.slices, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLotRemainder(t *testing.T) {
+	tests := []struct {
+		symbol           string
+		quantity, filled float64
+		want             float64
+	}{
+		{"BTCUSDT", 1, 0.7, 0.3}, // 1 - 0.7 = 0.29999999999999993 in float
+		{"BTCUSDT", 0.5, 0.1234, 0.376},
+		{"BTCUSDT", 0.5, 0.5, 0},
+		{"AAPL", 10, 2.5, 7.5}, // Unknown lot step: decimal difference as is
+	}
+	for _, tt := range tests {
+		if got := lotRemainder(tt.symbol, tt.quantity, tt.filled); got != tt.want {
+			t.Errorf("lotRemainder(%s, %v, %v) = %v, want %v", tt.symbol, tt.quantity, tt.filled, got, tt.want)
+		}
+	}
+}
+
+func TestTWAPFill(t *testing.T) {
+	tests := []struct {
+		name      string
+		fills     [][3]float64 // quantity, price, fee
+		wantQty   float64
+		wantAvg   float64
+		wantFee   float64
+		wantChild int
+	}{
+		{"no fills", nil, 0, 0, 0, 0},
+		{"single fill", [][3]float64{{10, 100, 0.5}}, 10, 100, 0.5, 1},
+		{"volume weighted", [][3]float64{{10, 100, 0.5}, {30, 104, 1.5}}, 40, 103, 2, 2},
+		{"partial slice", [][3]float64{{5, 200, 0}, {5, 202, 0}, {2, 205, 0.1}}, 12, 201.66666666666666, 0.1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill := &twapFill{}
+			for i, f := range tt.fills {
+				fill.add(string(rune('a'+i)), f[0], f[1], f[2])
+			}
+			if fill.quantity != tt.wantQty || fill.children != tt.wantChild || math.Abs(fill.fee-tt.wantFee) > 1e-9 {
+				t.Errorf("fill = %+v, want qty %v, fee %v, children %d", fill, tt.wantQty, tt.wantFee, tt.wantChild)
+			}
+			if avg := fill.avgPrice(); math.Abs(avg-tt.wantAvg) > 1e-9 {
+				t.Errorf("avgPrice = %v, want %v", avg, tt.wantAvg)
+			}
+			if len(tt.fills) > 0 && fill.lastID != string(rune('a'+len(tt.fills)-1)) {
+				t.Errorf("lastID = %q, want last child", fill.lastID)
+			}
+		})
+	}
+}
+
+func TestSetActionOrderID(t *testing.T) {
+	tests := []struct {
+		name       string
+		order      map[string]interface{}
+		wantID     int64
+		wantExchID string
+	}{
+		{"int64", map[string]interface{}{"orderId": int64(123)}, 123, ""},
+		{"float64", map[string]interface{}{"orderId": float64(456)}, 456, ""},
+		{"numeric string from TWAP", map[string]interface{}{"orderId": "789", "twap": true}, 789, ""},
+		{"uuid", map[string]interface{}{"orderId": "b0b6dd9d-8b9b-48a9-ba46-b9d54906e415"}, 0, "b0b6dd9d-8b9b-48a9-ba46-b9d54906e415"},
+		{"alpaca id key", map[string]interface{}{"id": "abc"}, 0, "abc"},
+		{"missing", map[string]interface{}{}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var action store.DecisionAction
+			setActionOrderID(&action, tt.order)
+			if action.OrderID != tt.wantID || action.ExchangeOrderID != tt.wantExchID {
+				t.Errorf("OrderID %d, ExchangeOrderID %q, want %d, %q", action.OrderID, action.ExchangeOrderID, tt.wantID, tt.wantExchID)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package trader
+
+import (
+	"SynapseStrike/decision"
+	"SynapseStrike/logger"
+	"SynapseStrike/store"
+	"fmt"
+)
+
+// executeUpdateStopsWithRecord replaces the SL/TP orders of an open position with the decision's levels
+// Zero levels are kept. The levels are re-checked against the live price, which may have moved since validation.
+func (at *AutoTrader) executeUpdateStopsWithRecord(d *decision.Decision, actionRecord *store.DecisionAction) error {
+	side, _ := d.MetaString(decision.MetaPositionSide)
+	logger.Infof("  🎯 Update stops: %s %s (SL %.4f, TP %.4f)", d.Symbol, side, d.StopLoss, d.TakeProfit)
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	var quantity float64
+	for _, pos := range positions {
+		posSide := pos.Side
+		if pos.Symbol != d.Symbol || (side != "" && posSide != side) {
+			continue
+		}
+		side = posSide
+		quantity = pos.Qty
+		break
+	}
+	if quantity <= 0 {
+		return fmt.Errorf("no open %s position to update stops", d.Symbol)
+	}
+
+	// Ownership guard: verify this position belongs to the current trader
+	if at.store != nil {
+		dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, d.Symbol, side)
+		if err != nil || dbPos == nil {
+			logger.Warnf("🚫 [%s] Blocked update_stops %s: position not owned by this trader", at.config.Name, d.Symbol)
+			return fmt.Errorf("position %s %s not owned by trader %s", d.Symbol, side, at.config.Name)
+		}
+	}
+
+	price, err := at.trader.GetMarketPrice(d.Symbol)
+	if err != nil || price <= 0 {
+		return fmt.Errorf("failed to get price of %s: %v", d.Symbol, err)
+	}
+	actionRecord.Price = price
+	if side == "long" && ((d.StopLoss > 0 && d.StopLoss >= price) || (d.TakeProfit > 0 && d.TakeProfit <= price)) ||
+		side == "short" && ((d.StopLoss > 0 && d.StopLoss <= price) || (d.TakeProfit > 0 && d.TakeProfit >= price)) {
+		return fmt.Errorf("new stops SL %.4f / TP %.4f are on the wrong side of current price %.4f for %s %s",
+			d.StopLoss, d.TakeProfit, price, d.Symbol, side)
+	}
+
+	takeProfit, stopLoss, _ := at.GetPositionTPSL(d.Symbol, side)
+	if d.StopLoss > 0 {
+		if err := at.setStopLoss(d.Symbol, side, quantity, d.StopLoss, true); err != nil {
+			return fmt.Errorf("failed to replace stop loss: %w", err)
+		}
+		stopLoss = d.StopLoss
+	}
+	if d.TakeProfit > 0 {
+		if err := at.setTakeProfit(d.Symbol, side, quantity, d.TakeProfit, true); err != nil {
+			// Stop loss is already moved, keep it cached even though the take profit failed
+			at.SetPositionTPSL(d.Symbol, side, takeProfit, stopLoss)
+			return fmt.Errorf("failed to replace take profit: %w", err)
+		}
+		takeProfit = d.TakeProfit
+	}
+	at.SetPositionTPSL(d.Symbol, side, takeProfit, stopLoss)
+
+	logger.Infof("  ✓ %s %s stops updated: SL %.4f, TP %.4f (price %.4f)", d.Symbol, side, stopLoss, takeProfit, price)
+	return nil
+}
@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"sort"
 	"sync"
 	"time"
 )
@@ -37,8 +38,9 @@ func NewVWAPCollector(entryTime string) *VWAPCollector {
 	}
 }
 
-// AddBar adds a new 1-minute bar to the collection
-func (c *VWAPCollector) AddBar(bar VWAPBar) {
+// AddBar adds a new 1-minute bar to the collection, returns false if the bar is older than the last one
+// A bar with the same time as the last one replaces it (the latest bar may have been incomplete).
+func (c *VWAPCollector) AddBar(bar VWAPBar) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -47,12 +49,75 @@ func (c *VWAPCollector) AddBar(bar VWAPBar) {
 		bar.TypPrice = (bar.High + bar.Low + bar.Close) / 3
 	}
 
+	if n := len(c.bars); n > 0 {
+		last := c.bars[n-1].Time
+		if bar.Time.Equal(last) {
+			c.bars[n-1] = bar
+			if n == 1 {
+				c.openPrice = bar.Open
+			}
+			return true
+		}
+		if bar.Time.Before(last) {
+			return false
+		}
+	}
+
 	// Store first bar's open as day's open
 	if len(c.bars) == 0 {
 		c.openPrice = bar.Open
 	}
 
 	c.bars = append(c.bars, bar)
+	return true
+}
+
+// MergeBars merges restored or backfilled bars into the collection (sorted by time, incoming bar wins on same time)
+func (c *VWAPCollector) MergeBars(bars []VWAPBar) {
+	if len(bars) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byTime := make(map[int64]VWAPBar, len(c.bars)+len(bars))
+	for _, bar := range c.bars {
+		byTime[bar.Time.UnixMilli()] = bar
+	}
+	for _, bar := range bars {
+		if bar.TypPrice == 0 {
+			bar.TypPrice = (bar.High + bar.Low + bar.Close) / 3
+		}
+		byTime[bar.Time.UnixMilli()] = bar
+	}
+
+	merged := make([]VWAPBar, 0, len(byTime))
+	for _, bar := range byTime {
+		merged = append(merged, bar)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+
+	c.bars = merged
+	c.openPrice = merged[0].Open
+}
+
+// GetBars returns a copy of the collected bars
+func (c *VWAPCollector) GetBars() []VWAPBar {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	bars := make([]VWAPBar, len(c.bars))
+	copy(bars, c.bars)
+	return bars
+}
+
+// FirstBarTime returns time of the first collected bar (zero if none)
+func (c *VWAPCollector) FirstBarTime() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.bars) == 0 {
+		return time.Time{}
+	}
+	return c.bars[0].Time
 }
 
 // CalculateVWAP computes the Volume Weighted Average Price
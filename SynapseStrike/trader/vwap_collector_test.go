@@ -0,0 +1,52 @@
+package trader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVWAPCollectorMergeRestoredBars(t *testing.T) {
+	open := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC) // 9:30 ET
+	bar := func(minute int, price float64) VWAPBar {
+		return VWAPBar{Time: open.Add(time.Duration(minute) * time.Minute), Open: price, High: price, Low: price, Close: price, Volume: 100}
+	}
+
+	c := NewVWAPCollector("10:00")
+
+	// Live bar collected right after restart, before the session is restored
+	if !c.AddBar(bar(20, 105)) {
+		t.Fatal("expected first bar to be added")
+	}
+	if c.AddBar(bar(19, 104)) {
+		t.Error("expected older bar to be rejected")
+	}
+	if !c.AddBar(bar(20, 106)) || c.GetBarCount() != 1 {
+		t.Error("expected same-minute bar to replace the last one")
+	}
+
+	// Restored/backfilled session since the open
+	var restored []VWAPBar
+	for m := 0; m < 20; m++ {
+		restored = append(restored, bar(m, 100+float64(m)/4))
+	}
+	c.MergeBars(restored)
+
+	bars := c.GetBars()
+	if len(bars) != 21 {
+		t.Fatalf("expected 21 bars, got %d", len(bars))
+	}
+	for i := 1; i < len(bars); i++ {
+		if !bars[i].Time.After(bars[i-1].Time) {
+			t.Fatalf("bars not sorted at %d", i)
+		}
+	}
+	if c.GetOpenPrice() != 100 {
+		t.Errorf("expected open price from 9:30 bar, got %.2f", c.GetOpenPrice())
+	}
+	if bars[20].Close != 106 {
+		t.Errorf("expected live bar to be kept, got close %.2f", bars[20].Close)
+	}
+	if c.CalculateSlope() <= 0 {
+		t.Errorf("expected positive slope over rising session, got %.4f", c.CalculateSlope())
+	}
+}
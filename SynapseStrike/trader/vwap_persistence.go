@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"SynapseStrike/logger"
+	"SynapseStrike/market"
+	"SynapseStrike/store"
+	"time"
+)
+
+// ============================================================================
+// VWAP Session Persistence
+// ============================================================================
+// Collected 1-minute bars are saved per symbol and trading day, so a restart
+// during the pre-entry phase (e.g. at 9:50) keeps the session since 9:30.
+// On restore, gaps are backfilled from Alpaca 1-minute history.
+
+// vwapTradeDate trading day (YYYY-MM-DD in ET) a time belongs to
+func vwapTradeDate(t time.Time) string {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		t = t.In(loc)
+	}
+	return t.Format("2006-01-02")
+}
+
+// vwapSessionOpen gets today's 9:30 ET session open, false on weekends or before the open
+func vwapSessionOpen(now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.Time{}, false
+	}
+	now = now.In(loc)
+	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+		return time.Time{}, false
+	}
+	open := time.Date(now.Year(), now.Month(), now.Day(), 9, 30, 0, 0, loc)
+	return open, !now.Before(open)
+}
+
+// persistVWAPBars saves collected bars of the current session
+func (at *AutoTrader) persistVWAPBars(symbol string, bars []VWAPBar) {
+	if at.store == nil || len(bars) == 0 {
+		return
+	}
+	records := make([]store.VWAPBarRecord, 0, len(bars))
+	for _, bar := range bars {
+		records = append(records, store.VWAPBarRecord{
+			Time:   bar.Time,
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+			Close:  bar.Close,
+			Volume: bar.Volume,
+		})
+	}
+	if err := at.store.VWAPBar().Save(symbol, vwapTradeDate(bars[0].Time), records); err != nil {
+		logger.Infof("⚠️ [VWAP] Failed to persist bars for %s: %v", symbol, err)
+	}
+}
+
+// restoreVWAPSession reloads today's bars from the store and backfills missing minutes from Alpaca
+func (at *AutoTrader) restoreVWAPSession(symbol string, collector *VWAPCollector) {
+	now := time.Now()
+	open, ok := vwapSessionOpen(now)
+	if !ok {
+		return
+	}
+	tradeDate := vwapTradeDate(now)
+
+	restored := 0
+	if at.store != nil {
+		// Previous sessions are no longer needed
+		at.store.VWAPBar().DeleteBefore(tradeDate)
+
+		records, err := at.store.VWAPBar().List(symbol, tradeDate)
+		if err != nil {
+			logger.Infof("⚠️ [VWAP] Failed to load persisted bars for %s: %v", symbol, err)
+		}
+		bars := make([]VWAPBar, 0, len(records))
+		for _, r := range records {
+			if r.Time.Before(open) {
+				continue
+			}
+			bars = append(bars, VWAPBar{Time: r.Time, Open: r.Open, High: r.High, Low: r.Low, Close: r.Close, Volume: r.Volume})
+		}
+		collector.MergeBars(bars)
+		restored = len(bars)
+	}
+
+	// Backfill if any complete minute since the open is missing (current minute is still forming)
+	lastComplete := now.Truncate(time.Minute)
+	expected := int(lastComplete.Sub(open) / time.Minute)
+	if expected <= 0 || collector.GetBarCount() >= expected {
+		if restored > 0 {
+			logger.Infof("📊 [VWAP] Restored %d bars for %s from store", restored, symbol)
+		}
+		return
+	}
+
+	klines, err := market.GetKlinesRange(symbol, "1m", open, lastComplete)
+	if err != nil {
+		logger.Infof("⚠️ [VWAP] Failed to backfill 1-min bars for %s: %v (restored %d/%d bars)", symbol, err, restored, expected)
+		return
+	}
+	backfill := make([]VWAPBar, 0, len(klines))
+	for _, k := range klines {
+		barTime := time.UnixMilli(k.OpenTime)
+		if barTime.Before(open) || !barTime.Before(lastComplete) {
+			continue
+		}
+		backfill = append(backfill, VWAPBar{Time: barTime, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume})
+	}
+	collector.MergeBars(backfill)
+	at.persistVWAPBars(symbol, backfill)
+	logger.Infof("📊 [VWAP] Restored %d bars for %s from store, backfilled %d from Alpaca (%d bars in session)",
+		restored, symbol, len(backfill), collector.GetBarCount())
+}
@@ -1221,7 +1221,7 @@ function TraderDetailsPage({
                           className="px-1 py-3 font-mono whitespace-nowrap text-center"
                           style={{ color: 'var(--primary)' }}
                         >
-                          {pos.margin}x
+                          {pos.leverage ? `${pos.leverage}x` : '-'}
                         </td>
                         <td className="px-1 py-3 font-mono whitespace-nowrap text-right">
                           <span
@@ -1239,7 +1239,7 @@ function TraderDetailsPage({
                           className="px-1 py-3 font-mono whitespace-nowrap text-right"
                           style={{ color: '#9CA3AF' }}
                         >
-                          {pos.liquidation_price.toFixed(4)}
+                          {pos.liquidation_price ? pos.liquidation_price.toFixed(4) : '-'}
                         </td>
                       </tr>
                     ))}
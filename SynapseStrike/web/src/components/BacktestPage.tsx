@@ -781,8 +781,8 @@ export function BacktestPage() {
                         <button type="button"
                           className="flex-1 py-1.5 text-xs font-medium transition-colors"
                           style={{
-                            background: (formState as any).dataSource !== 'polygon' ? 'var(--primary)' : 'var(--bg-secondary)',
-                            color: (formState as any).dataSource !== 'polygon' ? '#000' : '#9CA3AF',
+                            background: !['polygon', 'archive'].includes((formState as any).dataSource) ? 'var(--primary)' : 'var(--bg-secondary)',
+                            color: !['polygon', 'archive'].includes((formState as any).dataSource) ? '#000' : '#9CA3AF',
                           }}
                           onClick={() => handleFormChange('dataSource', 'alpaca')}
                         >
@@ -798,6 +798,17 @@ export function BacktestPage() {
                         >
                           Widesurf
                         </button>
+                        <button type="button"
+                          className="flex-1 py-1.5 text-xs font-medium transition-colors"
+                          style={{
+                            background: (formState as any).dataSource === 'archive' ? '#22C55E' : 'var(--bg-secondary)',
+                            color: (formState as any).dataSource === 'archive' ? '#000' : '#9CA3AF',
+                          }}
+                          onClick={() => handleFormChange('dataSource', 'archive')}
+                          title="Local kline archive (download with scripts/klinearchive)"
+                        >
+                          Archive
+                        </button>
                       </div>
                       {(formState as any).dataSource === 'polygon' && (
                         <div className="mt-2 space-y-2">
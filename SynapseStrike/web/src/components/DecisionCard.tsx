@@ -13,6 +13,7 @@ const ACTION_CONFIG: Record<string, { color: string; bg: string; icon: string; l
   open_short: { color: '#F6465D', bg: 'rgba(246, 70, 93, 0.15)', icon: '📉', label: 'SHORT' },
   close_long: { color: 'var(--primary)', bg: 'var(--primary-bg, 0.15)', icon: '💰', label: 'CLOSE' },
   close_short: { color: 'var(--primary)', bg: 'var(--primary-bg, 0.15)', icon: '💰', label: 'CLOSE' },
+  update_stops: { color: '#F0B90B', bg: 'rgba(240, 185, 11, 0.15)', icon: '🎯', label: 'STOPS' },
   hold: { color: '#9CA3AF', bg: 'rgba(132, 142, 156, 0.15)', icon: '⏸️', label: 'HOLD' },
   wait: { color: '#9CA3AF', bg: 'rgba(132, 142, 156, 0.15)', icon: '⏳', label: 'WAIT' },
 }
@@ -47,6 +48,16 @@ function ActionCard({ action }: { action: DecisionAction; language: Language })
           style={{ background: action.success ? 'var(--primary)' : '#F6465D' }}
         />
       </div>
+      {/* Trade preview (opens) */}
+      {action.preview && (
+        <div className="text-xs font-mono pl-6" style={{ color: '#D1D5DB' }}>
+          {action.preview.quantity.toFixed(4)} @ {action.preview.entry_price.toFixed(4)} · margin $
+          {action.preview.margin.toFixed(2)} · risk ${action.preview.risk_usd.toFixed(2)} (
+          {action.preview.equity_at_risk_pct.toFixed(2)}%) · reward ${action.preview.reward_usd.toFixed(2)} · R:R{' '}
+          {action.preview.risk_reward.toFixed(2)}
+          {action.preview.liquidation_price > 0 && ` · liq ~${action.preview.liquidation_price.toFixed(4)}`}
+        </div>
+      )}
       {/* Reasoning */}
       {action.reasoning && (
         <div className="text-xs leading-relaxed pl-6" style={{ color: '#9CA3AF' }}>
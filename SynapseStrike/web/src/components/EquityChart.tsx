@@ -29,6 +29,13 @@ interface EquityPoint {
   pnl: number
   pnl_pct: number
   cycle_number: number
+  benchmark_symbol?: string   // Buy-and-hold benchmark (omitted where not tracked)
+  benchmark_equity?: number
+  benchmark_pnl_pct?: number  // Benchmark return since the window's first tracked point
+  alpha_pct?: number          // Equity return minus benchmark return
+  resolution_sec?: number     // Rolled-up point older than 7 days: bucket size in seconds (omitted for raw points)
+  equity_low?: number         // Lowest equity inside the bucket
+  equity_high?: number        // Highest equity inside the bucket
 }
 
 interface EquityChartProps {
@@ -1,10 +1,6 @@
 import { Clock, Activity, TrendingUp, BarChart2, Info, Lock, LineChart } from 'lucide-react'
 import type { IndicatorConfig } from '../../types'
 
-
-// Default API base URL for OI ranking data
-const DEFAULT_OI_RANKING_API_URL = 'http://172.22.189.252:30006'
-
 interface IndicatorEditorProps {
   config: IndicatorConfig
   onChange: (config: IndicatorConfig) => void
@@ -373,6 +369,21 @@ export function IndicatorEditor({
                 </div>
               )}
 
+              {/* Hard Block Toggle */}
+              <div className="flex items-center justify-between">
+                <div>
+                  <span className="text-xs font-medium block" style={{ color: '#F9FAFB' }}>Block Misaligned Opens</span>
+                  <span className="text-[10px]" style={{ color: '#6B7280' }}>Reject AI opens below the requirement (computed from EMA slope, MACD, RSI)</span>
+                </div>
+                <input
+                  type="checkbox"
+                  checked={config.confluence_block_opens || false}
+                  onChange={(e) => !disabled && onChange({ ...config, confluence_block_opens: e.target.checked })}
+                  disabled={disabled}
+                  className="w-4 h-4 rounded accent-yellow-500"
+                />
+              </div>
+
               {/* Expected Impact */}
               <div className="flex items-start gap-2 p-2 rounded" style={{ background: 'rgba(14, 203, 129, 0.08)' }}>
                 <Info className="w-3.5 h-3.5 mt-0.5 flex-shrink-0" style={{ color: 'var(--primary)' }} />
@@ -887,7 +898,6 @@ export function IndicatorEditor({
                 ...config,
                 enable_oi_ranking: e.target.checked,
                 // Set defaults when enabling
-                ...(e.target.checked && !config.oi_ranking_api_url ? { oi_ranking_api_url: DEFAULT_OI_RANKING_API_URL } : {}),
                 ...(e.target.checked && !config.oi_ranking_duration ? { oi_ranking_duration: '1h' } : {}),
                 ...(e.target.checked && !config.oi_ranking_limit ? { oi_ranking_limit: 10 } : {}),
               })}
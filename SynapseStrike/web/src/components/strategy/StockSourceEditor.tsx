@@ -4,7 +4,6 @@ import type { StockSourceConfig } from '../../types'
 
 // Default API URLs for data sources
 const DEFAULT_AI100_API_URL = 'http://24.12.59.214:8082/api/ai100/list?auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ&sort=fin&limit=100'
-const DEFAULT_OI_TOP_API_URL = 'http://172.22.189.252:30006/api/oi/top-ranking?limit=20&duration=1h&auth=cm_568c67eae410d912c54c'
 const DEFAULT_TOP_WINNERS_API_URL = 'https://invest-soft.com/api/winners/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ'
 const DEFAULT_TOP_LOSERS_API_URL = 'https://invest-soft.com/api/losers/list?sort=des&limit=100&auth=pluq8P0XTgucCN6kyxey5EPTof36R54lQc3rfgQsoNQ'
 
@@ -363,16 +362,6 @@ export function StockSourceEditor({
                 <label className="text-sm" style={{ color: '#9CA3AF' }}>
                   {t('oiTopApiUrl')}
                 </label>
-                {!disabled && !config.oi_top_api_url && (
-                  <button
-                    type="button"
-                    onClick={() => onChange({ ...config, oi_top_api_url: DEFAULT_OI_TOP_API_URL })}
-                    className="text-xs px-2 py-1 rounded"
-                    style={{ background: 'var(--primary)20', color: 'var(--primary)' }}
-                  >
-                    {t('fillDefault')}
-                  </button>
-                )}
               </div>
               <input
                 type="url"
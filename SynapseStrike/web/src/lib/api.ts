@@ -4,6 +4,13 @@ import type {
   Position,
   DecisionRecord,
   Statistics,
+  TradeExplanation,
+  TradeAnnotation,
+  PnLAttribution,
+  TradeQuality,
+  StressReport,
+  AnnotationLabel,
+  TradeImportResult,
   TraderInfo,
   TraderConfigData,
   AIModel,
@@ -360,6 +367,19 @@ export const api = {
     return result.data!
   },
 
+  // Get equity impact of adverse moves on open positions (moves: sizes in percent, default 2,5,10)
+  async getStressReport(traderId?: string, moves?: number[]): Promise<StressReport> {
+    const params = new URLSearchParams()
+    if (traderId) params.append('trader_id', traderId)
+    if (moves && moves.length > 0) params.append('moves', moves.join(','))
+    const url = params.toString()
+      ? `${API_BASE}/stress-report?${params}`
+      : `${API_BASE}/stress-report`
+    const result = await httpClient.get<StressReport>(url)
+    if (!result.success) throw new Error('Failed to get stress report')
+    return result.data!
+  },
+
   // Get decision logs (supports trader_id)
   async getDecisions(traderId?: string): Promise<DecisionRecord[]> {
     const url = traderId
@@ -398,6 +418,106 @@ export const api = {
     return result.data!
   },
 
+  // Get closed trades with the indicators the AI saw at entry (supports trader_id)
+  async getTradeExplanations(traderId?: string, limit?: number): Promise<TradeExplanation[]> {
+    const params = new URLSearchParams()
+    if (traderId) params.append('trader_id', traderId)
+    if (limit && limit > 0) params.append('limit', String(limit))
+    const url = params.toString()
+      ? `${API_BASE}/trade-explanations?${params}`
+      : `${API_BASE}/trade-explanations`
+    const result = await httpClient.get<TradeExplanation[]>(url)
+    if (!result.success) throw new Error('Failed to get trade explanations')
+    return result.data!
+  },
+
+  // Get realized PnL by symbol, side and entry hour (days: closed in the last N days, 0 = all)
+  async getPnLAttribution(traderId?: string, days?: number, tz?: string): Promise<PnLAttribution> {
+    const params = new URLSearchParams()
+    if (traderId) params.append('trader_id', traderId)
+    if (days && days > 0) params.append('days', String(days))
+    if (tz) params.append('tz', tz)
+    const url = params.toString()
+      ? `${API_BASE}/pnl-attribution?${params}`
+      : `${API_BASE}/pnl-attribution`
+    const result = await httpClient.get<PnLAttribution>(url)
+    if (!result.success) throw new Error('Failed to get PnL attribution')
+    return result.data!
+  },
+
+  // Get entry/exit quality from MFE/MAE of closed trades (days: closed in the last N days, 0 = all)
+  async getTradeQuality(traderId?: string, days?: number): Promise<TradeQuality> {
+    const params = new URLSearchParams()
+    if (traderId) params.append('trader_id', traderId)
+    if (days && days > 0) params.append('days', String(days))
+    const url = params.toString()
+      ? `${API_BASE}/trade-quality?${params}`
+      : `${API_BASE}/trade-quality`
+    const result = await httpClient.get<TradeQuality>(url)
+    if (!result.success) throw new Error('Failed to get trade quality')
+    return result.data!
+  },
+
+  // Get trade journal annotations of a trader
+  async getAnnotations(traderId: string): Promise<TradeAnnotation[]> {
+    const result = await httpClient.get<{ annotations: TradeAnnotation[] }>(
+      `${API_BASE}/traders/${traderId}/annotations`
+    )
+    if (!result.success) throw new Error('Failed to get annotations')
+    return result.data!.annotations
+  },
+
+  // Annotate a closed trade ("bad entry", "correct read, poor exit", ...)
+  async createAnnotation(traderId: string, positionId: number, label: AnnotationLabel, note?: string): Promise<TradeAnnotation> {
+    const result = await httpClient.post<TradeAnnotation>(
+      `${API_BASE}/traders/${traderId}/annotations`,
+      { position_id: positionId, label, note: note || '' }
+    )
+    if (!result.success) throw new Error('Failed to save annotation')
+    return result.data!
+  },
+
+  // Delete a trade journal annotation
+  async deleteAnnotation(traderId: string, annotationId: number): Promise<void> {
+    const result = await httpClient.delete(`${API_BASE}/traders/${traderId}/annotations/${annotationId}`)
+    if (!result.success) throw new Error('Failed to delete annotation')
+  },
+
+  // Export closed trades or decision records as CSV or JSON (days: 0/undefined = all)
+  async exportTraderData(
+    traderId: string,
+    kind: 'trades' | 'decisions',
+    format: 'csv' | 'json' = 'csv',
+    days?: number
+  ): Promise<Blob> {
+    const params = new URLSearchParams({ format })
+    if (days && days > 0) params.append('days', String(days))
+    const res = await fetch(`${API_BASE}/traders/${traderId}/export/${kind}?${params}`, {
+      headers: getAuthHeaders(),
+    })
+    if (!res.ok) {
+      const data = await res.json().catch(() => null)
+      throw new Error(data?.error || 'Export failed, please try again')
+    }
+    return res.blob()
+  },
+
+  // Import externally executed trades from a CSV or JSON export file
+  async importTrades(traderId: string, file: File): Promise<TradeImportResult> {
+    const isCSV = file.name.toLowerCase().endsWith('.csv')
+    const res = await fetch(`${API_BASE}/traders/${traderId}/import/trades`, {
+      method: 'POST',
+      headers: {
+        ...getAuthHeaders(),
+        'Content-Type': isCSV ? 'text/csv' : 'application/json',
+      },
+      body: await file.text(),
+    })
+    const data = await res.json().catch(() => null)
+    if (!res.ok) throw new Error(data?.error || 'Import failed, please try again')
+    return data as TradeImportResult
+  },
+
   // Get equity history (supports trader_id and optional hours parameter for time filtering)
   // hours: 24=1D, 120=5D, 720=1M, 4320=6M, 0=all data (YTD)
   async getEquityHistory(traderId?: string, hours?: number): Promise<any[]> {
@@ -11,6 +11,40 @@ export interface SystemStatus {
   stop_until: string
   last_reset_time: string
   ai_provider: string
+  rate_limit?: RateLimitStatus // Binance/Bybit only
+  equity_risk?: EquityRiskState // Equity curve risk scaling enabled only
+  pause?: PauseStatus
+}
+
+// Operator pause: new opens are blocked, positions are still managed
+export interface PauseStatus {
+  paused: boolean
+  paused_at?: string
+  paused_by?: string
+  reason?: string
+}
+
+export interface EquityRiskState {
+  multiplier: number     // Applied to new position sizes (1 = full risk)
+  max_positions: number  // Scaled max positions (0 = unchanged)
+  reduced: boolean
+  drawdown_pct: number   // Drawdown from peak equity %
+  peak_equity: number
+  equity: number
+  reduce_at_pct: number
+  restore_at_pct: number
+}
+
+export interface RateLimitStatus {
+  exchange: string
+  used: number
+  limit: number
+  window: string
+  limited: boolean
+  paused_until?: string
+  delayed: number
+  rejected: number
+  last_limited?: string
 }
 
 export interface AccountInfo {
@@ -33,10 +67,10 @@ export interface Position {
   entry_price: number
   mark_price: number
   quantity: number
-  margin: number
+  leverage?: number           // Missing when unknown
   unrealized_pnl: number
   unrealized_pnl_pct: number
-  liquidation_price: number
+  liquidation_price?: number  // Missing when not reported by the exchange
   margin_used: number
   entry_time?: string  // When position was opened (YYYY-MM-DD HH:MM)
 }
@@ -52,9 +86,26 @@ export interface DecisionAction {
   confidence?: number     // AI confidence (0-100)
   reasoning?: string      // Brief reasoning
   order_id: number
+  exchange_order_id?: string // Non-numeric exchange order ID (order_id is 0)
   timestamp: string
   success: boolean
   error?: string
+  error_category?: ErrorCategory
+  schema_version?: number // Decision schema version (missing = v1)
+  metadata?: Record<string, unknown> // v2 extra action parameters (trailing_stop_atr, valid_for_minutes, order_type, limit_price)
+  preview?: TradePreview  // Expected sizing and risk of an open, computed before execution
+}
+
+export interface TradePreview {
+  entry_price: number
+  quantity: number
+  position_value: number
+  margin: number
+  liquidation_price: number // 0 = unleveraged
+  risk_usd: number          // Loss if the stop loss fills
+  reward_usd: number        // Profit if the take profit fills
+  risk_reward: number
+  equity_at_risk_pct: number
 }
 
 export interface AccountSnapshot {
@@ -74,12 +125,32 @@ export interface DecisionRecord {
   account_state: AccountSnapshot
   positions: any[]
   candidate_stocks: string[]
+  filtered_out?: FilteredSymbol[]    // Candidates dropped before the AI decision
+  data_gaps?: DataGap[]              // Feeds that failed this cycle (data completeness report)
   decisions: DecisionAction[]
   execution_log: string[]
   success: boolean
   error_message?: string
+  shock_mode?: boolean
+  shock_reason?: string
+  ai_timeout?: boolean
+  error_category?: ErrorCategory // Category of error_message, or of the AI failure a fallback covered
+}
+
+export interface FilteredSymbol {
+  symbol: string
+  reason: 'symbol_list' | 'low_oi' | 'data_quality' | 'fetch_failed' | 'ranking_cut'
+  detail?: string
 }
 
+export interface DataGap {
+  symbol: string
+  feed: 'klines' | 'quant' | 'news' | 'oi'
+  reason: string
+}
+
+export type ErrorCategory = 'ai_parse' | 'ai_api' | 'exchange_reject' | 'risk_block' | 'data_missing' | 'timeout'
+
 export interface Statistics {
   total_cycles: number
   successful_cycles: number
@@ -88,6 +159,148 @@ export interface Statistics {
   total_close_positions: number
 }
 
+// Indicator values the AI saw when it opened a position
+export interface EntryIndicators {
+  decision_time: string
+  price: number
+  rsi7: number
+  rsi14: number
+  macd: number
+  ema20: number
+  vwap: number
+  vwap_dev_pct: number
+  confluence?: string
+  confluence_score?: number
+  oi_delta_pct: number
+  volume_ratio: number
+}
+
+// One symbol of the last cycle as the AI saw it
+export interface SymbolView {
+  symbol: string
+  status: 'candidate' | 'position' | 'filtered'
+  filter_reason?: string // FilteredSymbol reason (filtered only)
+  filter_detail?: string
+  sources?: string[]
+  held: boolean
+  indicators?: EntryIndicators // Missing without market data
+  price_change_1h: number
+  price_change_4h: number
+  news_count: number
+  opportunity_score?: number // Candidate ranking enabled only
+  data_gaps?: DataGap[]
+}
+
+// GET /api/traders/:id/symbols
+export interface SymbolSnapshot {
+  cycle: number
+  time: string
+  symbols: SymbolView[]
+}
+
+// Closed trade joined with its entry-time indicators
+export interface TradeExplanation {
+  position_id: number
+  symbol: string
+  side: 'long' | 'short'
+  quantity: number
+  entry_price: number
+  exit_price: number
+  realized_pnl: number
+  pnl_pct: number
+  close_reason: string
+  entry_time: string
+  exit_time: string
+  mfe_pct: number // Maximum favorable excursion (% of entry price, 0 = not tracked)
+  mae_pct: number // Maximum adverse excursion (% of entry price, 0 = not tracked)
+  indicators: EntryIndicators
+}
+
+// Realized PnL of one group of closed trades
+export interface PnLBucket {
+  key: string // symbol, side (long/short), close reason or entry hour ("00".."23")
+  trade_count: number
+  win_trades: number
+  win_rate: number
+  total_pnl: number
+  avg_pnl: number
+  fees: number
+}
+
+export interface PnLHeatmapCell {
+  symbol: string
+  hour: number // entry hour of day in the attribution time zone
+  trade_count: number
+  win_rate: number
+  total_pnl: number
+}
+
+// Realized PnL by symbol, side, close reason and entry hour of day
+export interface PnLAttribution {
+  timezone: string
+  trade_count: number
+  total_pnl: number
+  by_symbol: PnLBucket[] // worst total PnL first
+  by_side: PnLBucket[]
+  by_reason: PnLBucket[] // close reason ("unknown" if not recorded), worst total PnL first
+  by_hour: PnLBucket[]
+  heatmap: PnLHeatmapCell[]
+}
+
+// Entry/exit quality of closed trades from their MFE/MAE (percentages of entry price)
+export interface TradeQuality {
+  tracked_trades: number
+  avg_mfe_pct: number
+  avg_mae_pct: number
+  avg_mae_winners_pct: number // Heat winners took before working
+  max_mae_winners_pct: number
+  avg_mfe_losers_pct: number // Open profit losers gave back
+  exit_efficiency_pct: number // Share of the best move winners captured
+  stop_efficiency_pct: number // Realized loss / MAE of losers (100 = cut at the worst point)
+  avg_entry_quality: number // MFE / (MFE + MAE) × 100
+}
+
+// Human verdict on a closed trade (trade journal)
+export type AnnotationLabel = 'good_trade' | 'bad_entry' | 'poor_exit' | 'wrong_read' | 'oversized' | 'other'
+
+export interface TradeAnnotation {
+  id: number
+  trader_id: string
+  position_id: number
+  label: AnnotationLabel
+  note: string
+  created_at: string
+  symbol: string
+  side: 'long' | 'short'
+  realized_pnl: number
+  close_reason: string
+  exit_time: string
+}
+
+// Broker-agnostic closed trade (export schema synapsestrike.trades.v1)
+export interface ExportedTrade {
+  id: string
+  symbol: string
+  side: 'long' | 'short'
+  quantity: number
+  entry_price: number
+  exit_price: number
+  entry_time: string
+  exit_time: string
+  fee: number
+  realized_pnl: number
+  leverage?: number
+  close_reason?: string
+  exchange?: string
+}
+
+export interface TradeImportResult {
+  imported: number
+  duplicates: number
+  invalid: number
+  errors: string[]
+}
+
 // AI Tradingrelated types
 export interface TraderInfo {
   trader_id: string
@@ -116,21 +329,48 @@ export interface AIModel {
 
 export interface Brokerage {
   id: string                     // UUID (empty for supported brokerage templates)
-  brokerage_type: string          // "alpaca", "alpaca-paper", "ibkr", "simplefx", "oanda"
+  brokerage_type: string          // "alpaca", "alpaca-paper", "ibkr", "simplefx", "oanda", "ccxt", "binance-spot", "coinbase"
   account_name: string           // User-defined account name
   name: string                   // Display name
   type: 'broker' | 'forex'
   enabled: boolean
   apiKey?: string
   secretKey?: string
+  quote_currency?: string        // Collateral currency (USD/EUR/USDC/USDT, empty = brokerage default)
+  ccxt_gateway_url?: string      // CCXT bridge gateway URL ("ccxt" only)
+  ccxt_exchange_id?: string      // CCXT exchange id behind the gateway, e.g. "krakenfutures"
 }
 
 export interface CreateBrokerageRequest {
-  exchange_type: string          // "alpaca", "alpaca-paper", "ibkr", "simplefx", "oanda"
+  exchange_type: string          // "alpaca", "alpaca-paper", "ibkr", "simplefx", "oanda", "ccxt", "binance-spot", "coinbase"
   account_name: string           // User-defined account name
   enabled: boolean
   api_key?: string
   secret_key?: string
+  quote_currency?: string        // Collateral currency, balances are reported in USD
+  ccxt_gateway_url?: string      // Required for "ccxt"
+  ccxt_exchange_id?: string      // Required for "ccxt"
+}
+
+// Trading schedule window: "HH:MM" range on selected weekdays ("mon".."sun", empty = every day)
+export interface ScheduleWindow {
+  label?: string
+  days?: string[]
+  start: string
+  end: string // exclusive, "24:00" = end of day
+}
+
+export interface TradingSchedule {
+  enabled: boolean
+  timezone?: string // IANA timezone, default America/New_York
+  windows?: ScheduleWindow[] // allowed windows (empty = any time)
+  blackouts?: ScheduleWindow[] // blocked windows, take precedence
+}
+
+// Symbol allow/deny lists; entries are symbols or glob patterns ("*PEPE*")
+export interface SymbolLists {
+  allow?: string[] // curated watchlist (empty = any symbol)
+  deny?: string[] // never open, takes precedence over allow
 }
 
 export interface CreateTraderRequest {
@@ -143,6 +383,8 @@ export interface CreateTraderRequest {
   is_cross_margin?: boolean
   show_in_competition?: boolean // Show in competition
   trade_only_market_hours?: boolean // Only trade during market hours
+  trading_schedule?: TradingSchedule // Time-of-day / day-of-week trading windows
+  symbol_lists?: SymbolLists // Symbol allow/deny lists
   // withfields forbackward compatiblekeep，new versionUseStrategyconfig
   large_cap_margin?: number
   small_cap_margin?: number
@@ -184,10 +426,52 @@ export interface UpdateBrokerageConfigRequest {
       lighter_private_key?: string
       lighter_api_key_private_key?: string
       lighter_api_key_index?: number
+      // dYdX specific fields
+      dydx_mnemonic?: string
+      dydx_subaccount?: number
+      quote_currency?: string
+      // CCXT bridge specific fields
+      ccxt_gateway_url?: string
+      ccxt_exchange_id?: string
     }
   }
 }
 
+// Equity impact of one price move (stress report)
+export interface StressScenario {
+  move_pct: number // negative = down
+  pnl: number
+  equity_after: number
+  equity_impact_pct: number
+  liquidated?: string[] // "SYMBOL side" of positions liquidated by the move
+}
+
+export interface PositionStress {
+  symbol: string
+  side: 'long' | 'short'
+  quantity: number
+  mark_price: number
+  notional: number
+  leverage: number
+  liquidation_price?: number
+  liquidation_pct?: number // Adverse move to liquidation in %
+  scenarios: StressScenario[] // Adverse move per size
+}
+
+// What-if equity impact of adverse moves on open positions at current leverage
+export interface StressReport {
+  trader_id: string
+  generated_at: string
+  total_equity: number
+  gross_exposure: number
+  net_exposure: number
+  effective_leverage: number
+  positions: PositionStress[]
+  combined: StressScenario[] // Every position moves against itself
+  market_down: StressScenario[]
+  market_up: StressScenario[]
+}
+
 // Competition related types
 export interface CompetitionTraderData {
   trader_id: string
@@ -199,6 +483,7 @@ export interface CompetitionTraderData {
   total_pnl_pct: number
   position_count: number
   margin_used_pct: number
+  stress_worst_case_pct?: number // Equity impact of a 10% move against every open position
   is_running: boolean
 }
 
@@ -218,6 +503,8 @@ export interface TraderConfigData {
   is_cross_margin: boolean
   show_in_competition: boolean  // Show in competition
   trade_only_market_hours?: boolean  // Only trade during market hours
+  trading_schedule?: TradingSchedule  // Time-of-day / day-of-week trading windows
+  symbol_lists?: SymbolLists  // Symbol allow/deny lists
   scan_interval_minutes: number
   initial_balance: number
   is_running: boolean
@@ -377,6 +664,26 @@ export interface Strategy {
   updated_at: string;
 }
 
+// Strategy config validation (400 config_errors on save, POST /strategies/dry-run)
+export interface StrategyConfigError {
+  field: string;  // JSON path, e.g. "risk_control.max_margin_usage"
+  reason: string;
+}
+
+export interface StrategyConfigCheck {
+  name: 'candidates' | 'klines' | 'quant_data' | 'oi_ranking';
+  symbol?: string;
+  ok: boolean;
+  detail: string;
+}
+
+export interface StrategyDryRunReport {
+  valid: boolean;
+  errors?: StrategyConfigError[];
+  migrations?: string[];  // Legacy fields rewritten before validation
+  checks: StrategyConfigCheck[];
+}
+
 export interface PromptSectionsConfig {
   role_definition?: string;
   trading_frequency?: string;
@@ -388,13 +695,35 @@ export interface StrategyConfig {
   stock_source: StockSourceConfig;
   indicators: IndicatorConfig;
   custom_prompt?: string;
+  language?: 'en' | 'zh';  // Prompt language: generated prompt sections and AI reasoning (default: en)
   risk_control: RiskControlConfig;
   execution: ExecutionConfig;
+  memory?: MemoryConfig;
+  session_memory?: SessionMemoryConfig;
+  regime?: RegimeConfig;
+  shock?: ShockConfig;
+  equity_risk?: EquityRiskConfig;
+  annotations?: AnnotationFeedbackConfig;
+  churn_guard?: ChurnGuardConfig;
+  trade_governor?: TradeGovernorConfig;
+  context_hooks?: ContextHooksConfig;
+  tool_calling?: ToolCallingConfig;
+  enable_self_review?: boolean;      // Second AI pass confirms/amends/rejects decisions before execution
+  ensemble?: EnsembleConfig;
+  batch_retry?: BatchRetryConfig;
+  decision_parsing?: DecisionParsingConfig;
+  ai_response_cache?: AIResponseCacheConfig;
+  ai_latency?: AILatencyConfig;
+  ai_params?: AIParamsConfig;
+  candidate_ranking?: CandidateRankingConfig;
+  prompt_budget?: PromptBudgetConfig;
+  benchmark?: BenchmarkConfig;
+  grid?: GridConfig;
   prompt_sections?: PromptSectionsConfig;
 }
 
 export interface StockSourceConfig {
-  source_type: 'static' | 'coinpool' | 'stockpool' | 'ai100' | 'oi_top' | 'top_winners' | 'top_losers' | 'mixed';
+  source_type: 'static' | 'coinpool' | 'stockpool' | 'ai100' | 'oi_top' | 'top_winners' | 'top_losers' | 'funding_arb' | 'webhook' | 'social_trending' | 'mixed';
   static_stocks?: string[];
   use_stock_pool: boolean;
   stock_pool_limit?: number;
@@ -411,6 +740,22 @@ export interface StockSourceConfig {
   use_top_losers?: boolean;
   top_losers_limit?: number;
   top_losers_api_url?: string;
+  use_funding_arb?: boolean;
+  funding_arb_limit?: number;
+  funding_arb_exchanges?: string[];
+  funding_arb_min_rate?: number;
+  use_webhook?: boolean;
+  webhook_limit?: number;
+  webhook_secret?: string;       // HMAC-SHA256 secret for POST /api/webhooks/candidates/:strategy_id (write-only, leave empty to keep)
+  webhook_secret_set?: boolean;  // Returned instead of webhook_secret: whether a secret is stored
+  webhook_ttl_minutes?: number;  // Minutes a pushed list stays valid (default: 60)
+  use_social_trending?: boolean;
+  social_trending_limit?: number;
+  social_trending_api_url?: string;      // Social sentiment API returning trending tickers (e.g. Reddit/X scraper)
+  social_trending_api_key?: string;      // Sent as Authorization: Bearer <key>
+  social_trending_min_mentions?: number; // Drop tickers with fewer mentions (default: 0)
+  min_oi_value_usd?: number;         // Minimum open interest value of crypto candidates in USD (default: 15000000)
+  source_min_oi_value_usd?: Record<string, number>; // Per-source override, e.g. { static: 5000000, oi_top: 30000000 } (0 = no filter)
 }
 
 export interface IndicatorConfig {
@@ -425,6 +770,9 @@ export interface IndicatorConfig {
   enable_volume: boolean;
   enable_oi: boolean;
   enable_funding_rate: boolean;
+  // Order book snapshot (crypto only, also keeps smart limit prices passive)
+  enable_order_book?: boolean;       // Top levels, spread and bid/ask imbalance
+  order_book_levels?: number;        // Levels per side (default: 20)
   // VWAP indicators (calculated from bar data)
   enable_vwap_indicator?: boolean;   // Volume Weighted Average Price
   enable_anchored_vwap?: boolean;    // Anchored VWAP from session start
@@ -440,11 +788,14 @@ export interface IndicatorConfig {
   quant_data_api_url?: string;
   enable_quant_oi?: boolean;
   enable_quant_netflow?: boolean;
+  quant_data_max_concurrent?: number; // Max quant data requests in flight (default: 4)
+  quant_data_cache_ttl_sec?: number;  // Per-symbol quant data cache lifetime (default: 60)
   // Stock Features (market-wide OI ranking)
   enable_oi_ranking?: boolean;
   oi_ranking_api_url?: string;
   oi_ranking_duration?: string;  // "1h", "4h", "24h"
   oi_ranking_limit?: number;
+  oi_ranking_source?: OIRankingSourceConfig;
   // Stock Ranking Data Indicators
   enable_stock_news?: boolean;      // Real-time news & sentiment
   enable_trade_flow?: boolean;      // Trade flow analysis
@@ -461,6 +812,7 @@ export interface IndicatorConfig {
   confluence_timeframes?: string[];  // Timeframes to check for confluence (e.g., ['15m', '1h', '4h'])
   confluence_require_all?: boolean;  // Require ALL timeframes to align (strict mode)
   confluence_min_match?: number;     // Minimum number of timeframes that must align (2 of 3, etc.)
+  confluence_block_opens?: boolean;  // Hard-block opens below minimum match (code enforced)
 
   // ============================================================================
   // Phase 1: Core Profit Engine Features
@@ -515,6 +867,8 @@ export interface KlineConfig {
   enable_multi_timeframe: boolean;
   // add new：supportSelectmultiplewheninterval
   selected_timeframes?: string[];
+  max_concurrent_fetches?: number; // Symbols fetched in parallel per cycle (default: 8)
+  min_data_quality?: number;       // Skip candidates whose OHLCV quality score (0-1) is below this (0 = off)
 }
 
 export interface ExternalDataSource {
@@ -527,10 +881,40 @@ export interface ExternalDataSource {
   refresh_secs?: number;
 }
 
+// OI ranking source; empty fields keep the original OI ranking API layout
+export interface OIRankingSourceConfig {
+  provider?: string;        // Provider registered in code (overrides oi_ranking_api_url)
+  top_path?: string;        // Default: /api/oi/top-ranking
+  low_path?: string;        // Default: /api/oi/low-ranking
+  auth_scheme?: 'none' | 'query' | 'bearer' | 'header';
+  auth_name?: string;       // Query parameter (default: auth) or header name (default: X-API-Key)
+  api_key?: string;
+  positions_path?: string;  // Default: data.positions
+  time_range_path?: string; // Default: data.time_range
+  field_map?: Record<string, string>; // Position field -> response field
+}
+
+// Per-symbol drawdown monitor override (unset fields use global values, 0 is a real value)
+export interface DrawdownRule {
+  enabled?: boolean;
+  activation_pct?: number;
+  close_pct?: number;
+}
+
+// Per-symbol position sizing override (unset fields use global values, CODE ENFORCED)
+export interface SymbolOverride {
+  max_position_usd?: number;  // Replaces max_position_size_usd (equity ratio limit still applies)
+  max_leverage?: number;      // Replaces large/small cap max leverage
+  min_confidence?: number;    // Min AI confidence to open this symbol
+}
+
 export interface RiskControlConfig {
   // Max number of stocks held simultaneously (CODE ENFORCED)
   max_positions: number;
 
+  // Symbols treated as Large Cap (empty = AAPL, MSFT, NVDA, TSLA, AMZN, GOOGL, META)
+  large_cap_symbols?: string[];
+
   // Trading Margin - brokerage margin for opening positions (AI guided)
   large_cap_max_margin: number;    // Large Cap max brokerage margin
   small_cap_max_margin: number;    // Small Cap max brokerage margin
@@ -543,11 +927,17 @@ export interface RiskControlConfig {
   // Max Amount per Trade - absolute cap on position size in USD (CODE ENFORCED)
   max_position_size_usd?: number;                  // 0 = no limit, e.g. 1000 for $1000 max per trade
 
+  // Max Risk per Trade - loss if the stop fills, opens are resized to fit (CODE ENFORCED)
+  max_risk_per_trade_usd?: number;                 // 0 = no limit
+  max_risk_per_trade_pct?: number;                 // % of equity, 0 = no limit
+
   // Risk Parameters
   max_margin_usage: number;        // Max margin utilization, e.g. 0.9 = 90% (CODE ENFORCED)
   min_position_size: number;       // Min position size in  (CODE ENFORCED)
+  large_cap_min_position_size?: number; // Min Large Cap position size in USD, default: 60 (CODE ENFORCED)
   min_risk_reward_ratio: number;   // Min take_profit / stop_loss ratio (AI guided)
   min_confidence: number;          // Min AI confidence to open position (AI guided)
+  symbol_overrides?: Record<string, SymbolOverride>; // Per-symbol sizing overrides, e.g. { BTCUSDT: { max_leverage: 20 } }
 
   // ============================================================================
   // Phase 1: Risk Management Features
@@ -567,11 +957,49 @@ export interface RiskControlConfig {
   use_daily_loss_limit?: boolean;   // Enable daily loss limit
   daily_loss_limit_pct?: number;    // Daily loss limit as % of equity (default: 2%)
 
+  // Equity Floor (halts trading until manual reset via POST /api/traders/:id/equity-floor/reset)
+  equity_floor_usd?: number;        // Absolute equity floor in USD (0 = disabled)
+  equity_floor_pct?: number;        // Floor as % of initial balance (0 = disabled)
+  equity_floor_action?: 'freeze' | 'flatten'; // freeze keeps positions (default), flatten closes all
+
   // Trailing Stop
   use_trailing_stop?: boolean;      // Enable ATR-based trailing stop
   trailing_stop_atr?: number;       // Trail by X ATR (default: 1.5)
   trailing_activation_r?: number;   // Activate after X R profit (default: 1.0)
 
+  // Stop Distance Sanity Check (ATR multiples of primary timeframe, 0 = disabled)
+  stop_atr_min_multiple?: number;   // Min stop distance in ATR (default: 0.5)
+  stop_atr_max_multiple?: number;   // Max stop distance in ATR (default: 5.0)
+
+  // Volatility-Targeted Leverage (leverage = target / daily ATR%, overrides AI leverage)
+  vol_target_enabled?: boolean;        // Default: false
+  vol_target_daily_risk_pct?: number;  // Target daily position volatility in % (default: 1)
+
+  // Position Drawdown Monitor (close when profit > activation % and drawdown from peak >= close %)
+  drawdown_monitor_enabled?: boolean;   // Enable auto-close (default: true)
+  drawdown_activation_pct?: number;     // Min profit % before monitoring (unset: 5, 0 = any profit)
+  drawdown_close_pct?: number;          // Drawdown % from peak profit to close (unset: 40, 0 = any giveback)
+  drawdown_check_interval_sec?: number; // Check interval in seconds (default: 60)
+  drawdown_realtime?: boolean;          // Check websocket fills/mark prices as they arrive (Binance, Bybit, Alpaca; default: false)
+  drawdown_overrides?: Record<string, DrawdownRule>; // Per-symbol overrides
+
+  // Liquidation Guard (leveraged crypto: reject stops near liquidation, alert when mark approaches it)
+  liquidation_guard_enabled?: boolean;   // Enable guard (default: true)
+  liquidation_stop_buffer_pct?: number;  // Min stop-to-liquidation distance in % of entry (default: 1)
+  liquidation_alert_pct?: number;        // Alert when mark is within this % of liquidation (default: 5)
+
+  // News Blackouts (no opens around earnings; optional market-wide blackouts around macro releases)
+  earnings_blackout_hours?: number;       // Hours around a symbol's earnings without new opens (0 = disabled)
+  macro_blackout_enabled?: boolean;       // Enable macro event blackouts (default: false)
+  macro_blackout_events?: string[];       // Event name keywords (default: FOMC, CPI)
+  macro_blackout_hours_before?: number;   // Blackout start before the release (default: 2)
+  macro_blackout_hours_after?: number;    // Blackout end after the release (default: 1)
+
+  // Max Holding Time (close reason "max_hold_time")
+  max_hold_minutes?: number;              // Max holding time in minutes (0 = unlimited)
+  max_hold_action?: 'close' | 'review';   // Close at deadline, or AI review first (default: close)
+  max_hold_review_grace_minutes?: number; // Review mode: minutes before forced close (default: 30)
+
   // Partial Profit Taking
   use_partial_profits?: boolean;    // Enable partial profit taking
   partial_profit_pct?: number;      // % to close at first target (default: 50%)
@@ -580,6 +1008,14 @@ export interface RiskControlConfig {
   // End-of-Day Position Close
   close_at_eod?: boolean;            // Auto-close all positions before market close
   close_at_eod_time?: string;        // Time to close positions (default: "15:55" = 3:55 PM ET)
+  eod_policy?: 'flatten' | 'reduce' | 'hold-stops'; // End-of-day action (default: "flatten")
+  eod_reduce_pct?: number;           // % of each position closed for "reduce" (default: 50)
+  eod_stop_pct?: number;             // Overnight hard stop distance from price in % (default: 2)
+  eod_minutes_before_close?: number; // Run N minutes before 16:00 ET (0 = use close_at_eod_time)
+
+  // Shutdown Policy
+  shutdown_policy?: 'leave' | 'tighten-stops' | 'flatten'; // Action on trader stop (default: "leave")
+  shutdown_stop_pct?: number;        // Stop distance from price in % for "tighten-stops" (default: 0.5)
 
   // Market Hours Filter
   use_market_hours_filter?: boolean; // Only trade during market hours
@@ -594,6 +1030,10 @@ export interface ExecutionConfig {
   enable_limit_orders?: boolean;        // Enable smart limit orders (default: false)
   limit_offset_atr_multiplier?: number; // ATR multiplier for limit offset (default: 0.5)
   limit_timeout_seconds?: number;       // Timeout before switching to market order (default: 5-10s)
+  calibrate_limit_offset?: boolean;     // Scale the ATR multiplier by the recorded smart limit fill rate (default: false)
+
+  // Limit Entries - AI decisions with entry_price rest as GTC limit orders
+  limit_entry_expiry_minutes?: number;  // Unfilled entries are cancelled after this (default: 60)
 
   // TWAP (Time-Weighted Average Price) - Split large orders to reduce market impact
   enable_twap?: boolean;                // Enable TWAP for large orders (default: false)
@@ -603,6 +1043,191 @@ export interface ExecutionConfig {
 
   // Order Type Preference
   preferred_order_type?: string;        // "market" | "limit" | "smart" (default: "market")
+
+  // Venue Price Guard - compare the execution venue's price with the price the AI decided on
+  price_guard_bps?: number;             // Max deviation in basis points before an open is guarded (0 = disabled)
+  price_guard_action?: 'abort' | 'reprice'; // Reject the open or shift entry/SL/TP by the price ratio (default: abort)
+  maintenance_action?: 'defer' | 'monitor'; // During exchange maintenance: skip cycles or run them without orders (default: defer)
+  maintenance_buffer_minutes?: number;      // Maintenance window widened by this on both sides (default: 5)
+}
+
+// Decision memory: similar past setups with outcomes injected as lessons learned
+export interface MemoryConfig {
+  enabled: boolean;         // Enable decision memory (default: false)
+  top_k?: number;           // Similar past setups per candidate (default: 3)
+  min_similarity?: number;  // Minimum cosine similarity (default: 0.75)
+}
+
+// Session memory: running thesis of the last cycles, summarized by a cheap AI call each cycle
+export interface SessionMemoryConfig {
+  enabled: boolean;        // Enable session memory (default: false)
+  cycles?: number;         // Last K cycles folded into the summary (default: 6)
+  max_chars?: number;      // Summary length limit (default: 1200)
+  ai_model_id?: string;    // AI model writing the summary (empty = trader's model)
+}
+
+export interface RegimeConfig {
+  enabled: boolean;                  // Classify SPY/QQQ market regime in prompt (default: false)
+  index_symbols?: string[];          // Index symbols (default: SPY, QQQ)
+  timeframe?: string;                // Classification timeframe (default: 1h)
+  high_vol_atr_ratio?: number;       // Recent/baseline ATR ratio for high_vol (default: 1.5)
+  chop_band_pct?: number;            // EMA20/EMA50 band % below which regime is choppy (default: 0.15)
+  scale_risk_in_high_vol?: boolean;  // Scale down new positions in high_vol regime
+  high_vol_size_scale?: number;      // Position size multiplier in high_vol (default: 0.5)
+  high_vol_max_positions?: number;   // Max positions in high_vol (0 = unchanged)
+}
+
+export interface ShockConfig {
+  enabled: boolean;                  // Skip opens and tighten stops in market shocks (default: false)
+  index_symbols?: string[];          // Symbols watched for gaps and crashes (default: SPY, BTCUSDT)
+  move_pct?: number;                 // Index move % within the window that triggers shock mode (default: 3)
+  window_minutes?: number;           // Move window in minutes (default: 30)
+  atr_z_score?: number;              // Candidate-wide ATR z-score that triggers shock mode (0 = disabled, default: 3)
+  stop_pct?: number;                 // Stops of open positions are tightened to this % from price (default: 1)
+}
+
+export interface EquityRiskConfig {
+  enabled: boolean;                  // Scale risk down while the account is in drawdown (default: false)
+  lookback_hours?: number;           // Equity curve window for the peak (default: 168)
+  drawdown_pct?: number;             // Drawdown from peak % that reduces risk (default: 5)
+  recovery_pct?: number;             // Drawdown % at or below which full risk is restored (default: 2)
+  risk_scale?: number;               // Position size and max positions multiplier while reduced (default: 0.5)
+}
+
+export interface AnnotationFeedbackConfig {
+  enabled: boolean;                  // Feed a digest of trade journal annotations into the prompt (default: false)
+  max_items?: number;                // Max annotated trades listed with notes (default: 10)
+  lookback_days?: number;            // Only annotations of the last N days (default: 30)
+}
+
+export interface ChurnGuardConfig {
+  enabled: boolean;                  // Previous decision per symbol in the prompt + flip detection (default: false)
+  lookback_cycles?: number;          // Opens of the last N cycles are protected (default: 3)
+  min_price_move_pct?: number;       // Price move since the open that justifies a flip (default: 1.0)
+  min_rsi_change?: number;           // RSI7 change since the open that justifies a flip (default: 15)
+  action?: 'flag' | 'suppress';      // Flag the flip in the log or turn it into hold/wait (default: flag)
+}
+
+export interface TradeGovernorConfig {
+  enabled: boolean;                  // Reject opens beyond the trade budget, reported in the next prompt (default: false)
+  max_opens_per_hour: number;        // Opens across all symbols in the last 60 min (0 = unlimited, default: 4)
+  max_opens_per_day: number;         // Opens across all symbols in the last 24 h (0 = unlimited, default: 20)
+  max_opens_per_symbol_per_hour: number; // Opens of one symbol in the last 60 min (0 = unlimited, default: 1)
+  max_opens_per_symbol_per_day: number;  // Opens of one symbol in the last 24 h (0 = unlimited, default: 4)
+}
+
+export interface ContextHooksConfig {
+  enabled: boolean;                  // Run context hook scripts every cycle (default: false)
+  scripts?: string[];                // Executable file names in the server's CONTEXT_HOOKS_DIR, run in order (max 5)
+  timeout_seconds: number;           // Per-script timeout (0-60, default: 10)
+}
+
+export interface ToolCallingConfig {
+  enabled: boolean;                  // AI may call data tools before deciding (default: false)
+  max_calls?: number;                // Tool call budget per AI call (default: 6)
+  tools?: string[];                  // Allowed tools: get_klines, get_news (empty = all)
+}
+
+export interface EnsembleConfig {
+  enabled: boolean;                  // Several AI models decide, decisions are merged (default: false)
+  ai_model_ids?: string[];           // Additional AI model IDs besides the trader's own (1-2)
+  merge_rule?: 'unanimous' | 'majority' | 'highest_confidence'; // default: majority
+}
+
+export interface BatchRetryConfig {
+  max_retries: number;               // Retries of a failed AI batch (0-5, default: 2)
+  base_delay_ms: number;             // First retry delay, doubled per retry with jitter (default: 1000)
+  max_delay_ms: number;              // Retry delay cap (default: 8000)
+  failover_ai_model_id?: string;     // AI model of a different provider tried after the last retry
+}
+
+export interface DecisionParsingConfig {
+  strictness: 'lenient' | 'strict';  // lenient: no decision JSON → safe wait; strict: parse failure (default: lenient)
+  repair_enabled: boolean;           // Send malformed decision JSON back to an AI once for repair (default: true)
+  repair_ai_model_id?: string;       // Cheap AI model used for the repair (unset = model that answered)
+}
+
+export interface AIResponseCacheConfig {
+  enabled: boolean;                  // Reuse the last AI decision when the AI fails and the context is unchanged (default: false)
+  freshness_minutes: number;         // Maximum age of a reused decision (default: 15)
+  price_bucket_pct: number;          // Price granularity of the context hash in percent (default: 0.5)
+}
+
+export interface AILatencyConfig {
+  sla_seconds: number;               // Maximum AI time per cycle (0 = AI_CYCLE_TIMEOUT_SECONDS, default 80% of scan interval)
+  on_timeout: 'fallback' | 'skip';   // When exceeded: algorithmic decision or no trades this cycle (default: fallback)
+}
+
+export interface AIParamsConfig {
+  temperature?: number;              // Sampling temperature 0-2 (unset = client default 0.5)
+  top_p?: number;                    // Nucleus sampling 0-1 (unset = provider default)
+  max_tokens?: number;               // Maximum response tokens (unset = AI_MAX_TOKENS)
+  reasoning_effort?: 'low' | 'medium' | 'high'; // Reasoning-capable models only (unset = provider default)
+  native_reasoning?: boolean;        // Use provider-native reasoning (Claude thinking, DeepSeek R1, Gemini thoughts) as chain of thought (default: false)
+}
+
+export interface CandidateRankingConfig {
+  enabled: boolean;                  // Order candidates by opportunity score before batching (default: false)
+  max_candidates?: number;           // Cut low scorers beyond this count (0 = keep all)
+  momentum_weight?: number;          // Weight of 1h/4h price change (all 0 = equal weights)
+  volume_weight?: number;            // Weight of volume surge
+  oi_weight?: number;                // Weight of open interest change
+  news_weight?: number;              // Weight of news recency
+}
+
+export interface PromptBudgetConfig {
+  enabled: boolean;                  // Compress user prompt to fit the token budget (default: false)
+  max_tokens?: number;               // Estimated user prompt token budget (default: 24000)
+  min_bars?: number;                 // Minimum bars kept per kline table / series (default: 10)
+}
+
+export interface BenchmarkConfig {
+  enabled: boolean;                  // Track buy-and-hold of the initial balance next to equity (default: true)
+  symbol: string;                    // Held instrument, e.g. BTCUSDT or SPY (default: BTCUSDT)
+}
+
+// Grid/DCA tactic (Local Function provider): limit buys on each level below the market, take profit per leg
+export interface GridConfig {
+  enabled: boolean;
+  grids: GridSpec[];                 // One grid per symbol
+}
+
+export interface GridSpec {
+  symbol: string;
+  lower_price: number;               // Lowest level
+  upper_price: number;               // Highest level
+  levels: number;                    // Evenly spaced levels (2-100)
+  level_size_usd: number;            // Notional bought per level
+  take_profit_pct: number;           // Take profit of each leg above its fill price
+  leverage?: number;                 // Default: 1
+}
+
+// GET /api/traders/:id/grid
+export interface GridInfo {
+  symbol: string;
+  lower_price: number;
+  upper_price: number;
+  levels: number;
+  price: number;
+  pending_levels: number;
+  open_legs: number;
+  open_quantity: number;
+  closed_legs: number;
+  realized_pnl: number;              // Before fees
+  unrealized_pnl: number;
+}
+
+// GET /api/traders/:id/fill-quality (slippage vs decision-time price, positive = worse)
+export interface SlippageStats {
+  exchange_type: string;
+  symbol: string;
+  fills: number;
+  avg_slippage_bps: number;
+  worst_slippage_bps: number;
+  slippage_usd: number;              // Total cost, negative = price improvement
+  smart_orders: number;              // Smart limit attempts
+  smart_limit_fills: number;         // Smart limit orders filled at their limit
+  limit_fill_rate: number;
 }
 
 